@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var flagAcceptEditSessionKey string
+
+func init() {
+	acceptEditCmd.Flags().StringVarP(&flagAcceptEditSessionKey, "session-key", "k", "", "session HMAC key (default: SLB_SESSION_KEY)")
+
+	rootCmd.AddCommand(acceptEditCmd)
+}
+
+var acceptEditCmd = &cobra.Command{
+	Use:   "accept-edit <edit-id>",
+	Short: "Accept a reviewer's corrected command for your request",
+	Long: `Accept a corrected command a reviewer proposed with 'slb approve --edit'.
+
+This requests the corrected command with your original justification,
+records the proposing reviewer's approval of it, and cancels the original
+request. Both requests and the edit stay linked in their timelines, so the
+audit trail shows where the approved command came from.
+
+If the reviewer's approval cannot be recorded (for example, their session
+ended), the new request waits for review like any other. If the corrected
+command needs no approval at all, no new request is created.
+
+Only the requestor of the original request can accept its edits. Use
+--session-id/-s to specify your session and --session-key/-k its key if
+not using environment.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		project, err := projectPath()
+		if err != nil {
+			return err
+		}
+		cfg, err := config.Load(config.LoadOptions{ProjectDir: project, ConfigPath: flagConfig})
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+
+		dbConn, err := db.OpenAndMigrate(GetDB())
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer dbConn.Close()
+
+		sessionID, err := resolveReviewerSessionID(dbConn, project, flagSessionID)
+		if err != nil {
+			return err
+		}
+		sessionKey, err := resolveSessionKey(flagAcceptEditSessionKey, sessionID)
+		if err != nil {
+			return err
+		}
+		if _, err := loadCustomPatternsIntoDefaultEngine(); err != nil {
+			return fmt.Errorf("loading custom patterns: %w", err)
+		}
+
+		creator := core.NewRequestCreator(dbConn, core.NewRateLimiter(dbConn, toRateLimitConfig(cfg)), nil, toRequestCreatorConfig(cfg))
+		reviewSvc := core.NewReviewService(dbConn, core.ReviewConfigFromConfig(cfg))
+		reviewSvc.SetNotifier(buildAgentMailNotifier(project))
+		result, err := reviewSvc.AcceptEdit(cmd.Context(), creator, core.AcceptEditOptions{
+			SessionID:  sessionID,
+			SessionKey: sessionKey,
+			EditID:     args[0],
+			Environ:    os.Environ(),
+		})
+		if err != nil {
+			return fmt.Errorf("accepting edit: %w", err)
+		}
+
+		resp := map[string]any{
+			"edit_id":             result.Edit.ID,
+			"original_request_id": result.Original.ID,
+			"original_status":     string(result.Original.Status),
+			"command":             result.Edit.Command,
+			"reviewer_agent":      result.Edit.ReviewerAgent,
+		}
+		created := result.Created.Request
+		if created == nil {
+			resp["skipped"] = true
+			resp["skip_reason"] = result.Created.SkipReason
+		} else {
+			resp["request_id"] = created.ID
+			resp["risk_tier"] = string(created.RiskTier)
+			resp["status"] = string(created.Status)
+		}
+		if result.PreApproval != nil {
+			resp["pre_approved"] = true
+			resp["approvals"] = result.PreApproval.Approvals
+			if result.PreApproval.RequestStatusChanged {
+				resp["status"] = string(result.PreApproval.NewRequestStatus)
+			}
+		} else if result.PreApprovalErr != nil {
+			resp["pre_approved"] = false
+			resp["pre_approval_error"] = result.PreApprovalErr.Error()
+		}
+
+		if isJSONOutput() {
+			return output.New(output.Format(GetOutput())).Write(resp)
+		}
+
+		output.Notef(os.Stdout, "Accepted edit %s from %s; request %s cancelled\n", result.Edit.ID, result.Edit.ReviewerAgent, result.Original.ID)
+		switch {
+		case created == nil:
+			output.Notef(os.Stdout, "The corrected command needs no approval (%s): %s\n", result.Created.SkipReason, result.Edit.Command)
+		case result.PreApproval != nil:
+			output.Notef(os.Stdout, "Request %s created and approved by %s: %s\n", created.ID, result.Edit.ReviewerAgent, result.Edit.Command)
+			output.Notef(os.Stdout, "Status: %s\n", resp["status"])
+		default:
+			output.Notef(os.Stdout, "Request %s created: %s\n", created.ID, result.Edit.Command)
+			if result.PreApprovalErr != nil {
+				fmt.Printf("Warning: %s's approval could not be recorded (%v); it needs review\n", result.Edit.ReviewerAgent, result.PreApprovalErr)
+			}
+		}
+		return nil
+	},
+}
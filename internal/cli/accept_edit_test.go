@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+	"github.com/spf13/cobra"
+)
+
+// newTestAcceptEditCmd creates a fresh accept-edit command for testing.
+func newTestAcceptEditCmd(dbPath string) *cobra.Command {
+	root := &cobra.Command{
+		Use:           "slb",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	root.PersistentFlags().StringVar(&flagDB, "db", dbPath, "database path")
+	root.PersistentFlags().StringVarP(&flagOutput, "output", "o", "text", "output format")
+	root.PersistentFlags().BoolVarP(&flagJSON, "json", "j", false, "json output")
+	root.PersistentFlags().StringVarP(&flagProject, "project", "C", "", "project directory")
+	root.PersistentFlags().StringVarP(&flagConfig, "config", "c", "", "config file")
+	root.PersistentFlags().StringVarP(&flagSessionID, "session-id", "s", "", "session ID")
+
+	accept := &cobra.Command{
+		Use:  "accept-edit <edit-id>",
+		Args: acceptEditCmd.Args,
+		RunE: acceptEditCmd.RunE,
+	}
+	accept.Flags().StringVarP(&flagAcceptEditSessionKey, "session-key", "k", "", "session key")
+	root.AddCommand(accept)
+	return root
+}
+
+func resetAcceptEditFlags() {
+	flagDB = ""
+	flagOutput = "text"
+	flagJSON = false
+	flagProject = ""
+	flagConfig = ""
+	flagSessionID = ""
+	flagAcceptEditSessionKey = ""
+}
+
+func TestApproveWithEdit_AcceptEdit(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetApproveFlags()
+
+	requestorSess := testutil.MakeSession(t, h.DB,
+		testutil.WithProject(h.ProjectDir),
+		testutil.WithAgent("Requestor"),
+		testutil.WithModel("model-a"),
+	)
+	reviewerSess := testutil.MakeSession(t, h.DB,
+		testutil.WithProject(h.ProjectDir),
+		testutil.WithAgent("Reviewer"),
+		testutil.WithModel("model-b"),
+	)
+	req := testutil.MakeRequest(t, h.DB, requestorSess,
+		testutil.WithCommand("rm -rf ./build", h.ProjectDir, true),
+		testutil.WithRisk(db.RiskTierDangerous),
+	)
+
+	cmd := newTestApproveCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "approve", req.ID,
+		"--session-id", reviewerSess.ID,
+		"-k", reviewerSess.SessionKey,
+		"--edit", "rm -rf ./build/cache",
+		"-m", "only the cache",
+		"-C", h.ProjectDir,
+		"-j",
+	)
+	testutil.RequireNoError(t, err, "approve --edit")
+	var proposed map[string]any
+	if err := json.Unmarshal([]byte(stdout), &proposed); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	editID, _ := proposed["edit_id"].(string)
+	if editID == "" || proposed["command"] != "rm -rf ./build/cache" || proposed["status"] != "proposed" {
+		t.Fatalf("unexpected proposal: %v", proposed)
+	}
+	if got, _ := h.DB.GetRequest(req.ID); got.Status != db.StatusPending {
+		t.Fatalf("proposing an edit changed the request to %s", got.Status)
+	}
+
+	// Only the requestor can accept.
+	resetAcceptEditFlags()
+	cmd = newTestAcceptEditCmd(h.DBPath)
+	_, err = executeCommandCapture(t, cmd, "accept-edit", editID, "-s", reviewerSess.ID, "-k", reviewerSess.SessionKey, "-C", h.ProjectDir, "-j")
+	if err == nil || !strings.Contains(err.Error(), core.ErrNotRequestor.Error()) {
+		t.Fatalf("expected ErrNotRequestor, got %v", err)
+	}
+
+	// The requestor's session ID alone does not prove who is accepting.
+	resetAcceptEditFlags()
+	cmd = newTestAcceptEditCmd(h.DBPath)
+	_, err = executeCommandCapture(t, cmd, "accept-edit", editID, "-s", requestorSess.ID, "-k", reviewerSess.SessionKey, "-C", h.ProjectDir, "-j")
+	if err == nil || !strings.Contains(err.Error(), core.ErrSessionKeyMismatch.Error()) {
+		t.Fatalf("expected ErrSessionKeyMismatch, got %v", err)
+	}
+
+	resetAcceptEditFlags()
+	cmd = newTestAcceptEditCmd(h.DBPath)
+	stdout, err = executeCommandCapture(t, cmd, "accept-edit", editID, "-s", requestorSess.ID, "-k", requestorSess.SessionKey, "-C", h.ProjectDir, "-j")
+	testutil.RequireNoError(t, err, "accept-edit")
+	var accepted map[string]any
+	if err := json.Unmarshal([]byte(stdout), &accepted); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	newID, _ := accepted["request_id"].(string)
+	if newID == "" || accepted["pre_approved"] != true || accepted["status"] != string(db.StatusApproved) || accepted["original_status"] != string(db.StatusCancelled) {
+		t.Fatalf("unexpected acceptance: %v", accepted)
+	}
+
+	resetReviewFlags()
+	cmd = newTestReviewCmd(h.DBPath)
+	stdout, err = executeCommandCapture(t, cmd, "review", "show", newID)
+	testutil.RequireNoError(t, err, "review show")
+	for _, want := range []string{"Edited:  from request " + req.ID + " by Reviewer", "APPROVE by Reviewer", "approved with edit " + editID} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in new request:\n%s", want, stdout)
+		}
+	}
+
+	resetReviewFlags()
+	cmd = newTestReviewCmd(h.DBPath)
+	stdout, err = executeCommandCapture(t, cmd, "review", "show", req.ID)
+	testutil.RequireNoError(t, err, "review show original")
+	for _, want := range []string{"CANCELLED", "Proposed edits", editID + " by Reviewer [accepted]", "Became request " + newID} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in original request:\n%s", want, stdout)
+		}
+	}
+}
@@ -2,14 +2,17 @@ package cli
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
-	"time"
+	"strings"
 
 	"github.com/Dicklesworthstone/slb/internal/config"
 	"github.com/Dicklesworthstone/slb/internal/core"
 	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/i18n"
 	"github.com/Dicklesworthstone/slb/internal/integrations"
 	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
 	"github.com/spf13/cobra"
 )
 
@@ -18,6 +21,10 @@ var (
 	flagApproveSessionKey    string
 	flagApproveComments      string
 	flagApproveTargetProject string
+	flagApproveLatest        bool
+	flagApproveVersion       int
+	flagApproveEdit          string
+	flagApproveAckVars       []string
 
 	// Structured response flags
 	flagApproveReasonResponse string
@@ -30,10 +37,15 @@ func init() {
 	// -s is owned by the root persistent --session-id; don't reclaim the
 	// shorthand here (it collides/shadows the persistent flag). Pass the
 	// session via the long --session-id flag.
-	approveCmd.Flags().StringVar(&flagApproveSessionID, "session-id", "", "reviewer session ID (required)")
-	approveCmd.Flags().StringVarP(&flagApproveSessionKey, "session-key", "k", "", "session HMAC key for signing (required)")
+	approveCmd.Flags().StringVar(&flagApproveSessionID, "session-id", "", "reviewer session ID (default: SLB_SESSION_ID, then your active session)")
+	approveCmd.Flags().StringVarP(&flagApproveSessionKey, "session-key", "k", "", "session HMAC key for signing (default: SLB_SESSION_KEY)")
 	approveCmd.Flags().StringVarP(&flagApproveComments, "comments", "m", "", "additional comments")
+	approveCmd.Flags().StringVar(&flagApproveComments, "comment", "", "alias for --comments")
 	approveCmd.Flags().StringVar(&flagApproveTargetProject, "target-project", "", "target project path for cross-project approvals")
+	approveCmd.Flags().IntVar(&flagApproveVersion, "expected-version", 0, "fail if the request changed since this version (from 'slb show --json')")
+	approveCmd.Flags().BoolVar(&flagApproveLatest, "latest", false, "approve the newest pending request you have not reviewed")
+	approveCmd.Flags().StringVar(&flagApproveEdit, "edit", "", "approve only this corrected command; the requestor accepts it with 'slb accept-edit'")
+	approveCmd.Flags().StringSliceVar(&flagApproveAckVars, "ack-vars", nil, "acknowledge the command's unexpanded template variables (all of them, by name)")
 
 	// Structured response flags for justification fields
 	approveCmd.Flags().StringVar(&flagApproveReasonResponse, "reason-response", "", "response to the reason justification")
@@ -45,7 +57,7 @@ func init() {
 }
 
 var approveCmd = &cobra.Command{
-	Use:   "approve <request-id>",
+	Use:   "approve [request-id]",
 	Short: "Approve a pending request",
 	Long: `Approve a command request, allowing it to proceed.
 
@@ -53,26 +65,43 @@ The approval is cryptographically signed with your session key to ensure
 authenticity. Your session must be active, and you cannot approve your own
 requests (unless you are a trusted self-approve agent).
 
+The reviewer is the session given by --session-id, else SLB_SESSION_ID, else
+your active session in this project (matched by --actor / SLB_ACTOR). The key
+may come from SLB_SESSION_KEY instead of --session-key, or from the keyring
+where 'slb session start' stored it (see 'slb keyring').
+
+Use --latest instead of a request ID to approve the newest pending request
+you did not submit and have not reviewed yet. JSON output includes the
+request's updated quorum state.
+
+Pass --edit with a corrected command to approve that instead ("approve if
+you change --force to --force-with-lease"). The request stays pending until
+its requestor runs 'slb accept-edit <edit-id>', which requests the corrected
+command already approved by you and cancels the original.
+
+A command with unexpanded template variables ($TARGET, {{env}}) cannot be
+approved as is: the requestor should resubmit it with concrete values, or
+you can propose the expanded command with --edit. To approve it anyway,
+name every variable with --ack-vars (e.g. --ack-vars TARGET,env); 'slb
+review' lists them.
+
+Pass --expected-version with the version you reviewed (the "version" field
+of 'slb show --json' or 'slb pending --json') to refuse the decision if
+another reviewer changed the request in the meantime; refresh and retry.
+
 For cross-project reviews, use --target-project to specify which project's
 database contains the request you want to approve.
 
 	Examples:
+	  slb approve --latest --comment "Verified the target path"
 	  slb approve abc123 --session-id $SESSION_ID -k $SESSION_KEY
 	  slb approve abc123 --session-id $SESSION_ID -k $SESSION_KEY -m "Looks safe"
+	  slb approve abc123 --edit "git push --force-with-lease origin main" -m "Keep others' commits"
+	  slb approve abc123 --ack-vars TARGET -m "TARGET is set by the deploy job"
 	  slb approve abc123 --session-id $SESSION_ID -k $SESSION_KEY --reason-response "Valid use case"
 	  slb approve abc123 --session-id $SESSION_ID -k $SESSION_KEY --target-project /path/to/other/project`,
-	Args: cobra.ExactArgs(1),
+	Args: requestIDOrLatest(&flagApproveLatest),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		requestID := args[0]
-
-		// Validate required flags
-		if flagApproveSessionID == "" {
-			return fmt.Errorf("--session-id is required")
-		}
-		if flagApproveSessionKey == "" {
-			return fmt.Errorf("--session-key is required")
-		}
-
 		// Determine project and database path
 		project, err := projectPath()
 		if err != nil && flagApproveTargetProject == "" {
@@ -93,10 +122,54 @@ database contains the request you want to approve.
 		}
 		defer dbConn.Close()
 
+		reviewerID, err := resolveReviewerSessionID(dbConn, project, flagApproveSessionID)
+		if err != nil {
+			return err
+		}
+		sessionKey, err := resolveSessionKey(flagApproveSessionKey, reviewerID)
+		if err != nil {
+			return err
+		}
+
+		var requestID string
+		if flagApproveLatest {
+			if requestID, err = latestReviewableRequest(dbConn, project, reviewerID); err != nil {
+				return err
+			}
+		} else {
+			requestID = args[0]
+		}
+
+		cfg, err := config.Load(config.LoadOptions{ProjectDir: project, ConfigPath: flagConfig})
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		req, err := dbConn.GetRequest(requestID)
+		if err != nil {
+			return fmt.Errorf("getting request: %w", err)
+		}
+		freshAuth, err := freshAuthFor(cfg, req.RiskTier)
+		if err != nil {
+			return fmt.Errorf("sudo mode: %w", err)
+		}
+
+		reviewSvc := core.NewReviewService(dbConn, core.ReviewConfigFromConfig(cfg))
+		if flagApproveEdit != "" {
+			return proposeEdit(reviewSvc, req, core.ProposeEditOptions{
+				SessionID:        reviewerID,
+				SessionKey:       sessionKey,
+				RequestID:        requestID,
+				Command:          flagApproveEdit,
+				Comments:         flagApproveComments,
+				FreshAuth:        freshAuth,
+				AcknowledgedVars: flagApproveAckVars,
+			})
+		}
+
 		// Build review options
 		opts := core.ReviewOptions{
-			SessionID:  flagApproveSessionID,
-			SessionKey: flagApproveSessionKey,
+			SessionID:  reviewerID,
+			SessionKey: sessionKey,
 			RequestID:  requestID,
 			Decision:   db.DecisionApprove,
 			Responses: db.ReviewResponse{
@@ -105,27 +178,38 @@ database contains the request you want to approve.
 				GoalResponse:   flagApproveGoalResponse,
 				SafetyResponse: flagApproveSafetyResponse,
 			},
-			Comments: flagApproveComments,
+			Comments:         flagApproveComments,
+			ExpectedVersion:  flagApproveVersion,
+			FreshAuth:        freshAuth,
+			AcknowledgedVars: flagApproveAckVars,
 		}
 
-		// Create review service and submit
-		reviewSvc := core.NewReviewService(dbConn, core.DefaultReviewConfig())
+		// Submit the review
 		reviewSvc.SetNotifier(buildAgentMailNotifier(project))
 		result, err := reviewSvc.SubmitReview(opts)
 		if err != nil {
 			return fmt.Errorf("submitting approval: %w", err)
 		}
 
+		quorum, err := buildQuorumState(dbConn, requestID, result)
+		if err != nil {
+			return err
+		}
+
 		// Build output
 		type approvalResult struct {
-			ReviewID             string `json:"review_id"`
-			RequestID            string `json:"request_id"`
-			Decision             string `json:"decision"`
-			Approvals            int    `json:"approvals"`
-			Rejections           int    `json:"rejections"`
-			RequestStatusChanged bool   `json:"request_status_changed"`
-			NewRequestStatus     string `json:"new_request_status,omitempty"`
-			CreatedAt            string `json:"created_at"`
+			ReviewID             string      `json:"review_id"`
+			RequestID            string      `json:"request_id"`
+			Decision             string      `json:"decision"`
+			Approvals            int         `json:"approvals"`
+			DelegatedApprovals   int         `json:"delegated_approvals,omitempty"`
+			DelegatedFrom        []string    `json:"delegated_from,omitempty"`
+			Rejections           int         `json:"rejections"`
+			RequestStatusChanged bool        `json:"request_status_changed"`
+			NewRequestStatus     string      `json:"new_request_status,omitempty"`
+			Quorum               quorumState `json:"quorum"`
+			Version              int         `json:"version"`
+			CreatedAt            string      `json:"created_at"`
 		}
 
 		resp := approvalResult{
@@ -133,9 +217,13 @@ database contains the request you want to approve.
 			RequestID:            requestID,
 			Decision:             string(result.Review.Decision),
 			Approvals:            result.Approvals,
+			DelegatedApprovals:   result.DelegatedApprovals,
+			DelegatedFrom:        result.DelegatedFrom,
 			Rejections:           result.Rejections,
 			RequestStatusChanged: result.RequestStatusChanged,
-			CreatedAt:            result.Review.CreatedAt.Format(time.RFC3339),
+			Quorum:               quorum,
+			Version:              result.Version,
+			CreatedAt:            timefmt.Format(result.Review.CreatedAt),
 		}
 
 		if result.RequestStatusChanged {
@@ -143,19 +231,23 @@ database contains the request you want to approve.
 		}
 
 		out := output.New(output.Format(GetOutput()))
-		if GetOutput() == "json" {
+		if isJSONOutput() {
 			return out.Write(resp)
 		}
 
 		// Human-readable output
-		fmt.Printf("Approved request %s\n", requestID)
-		fmt.Printf("Review ID: %s\n", resp.ReviewID)
-		fmt.Printf("Approvals: %d, Rejections: %d\n", resp.Approvals, resp.Rejections)
+		output.Notef(os.Stdout, "%s\n", i18n.T("approve.done", requestID))
+		output.Notef(os.Stdout, "%s\n", i18n.T("review.id", resp.ReviewID))
+		output.Notef(os.Stdout, "%s\n", i18n.T("review.counts", resp.Approvals, resp.Rejections))
+		if resp.DelegatedApprovals > 0 {
+			output.Notef(os.Stdout, "%s\n", i18n.T("approve.delegated",
+				resp.DelegatedApprovals, strings.Join(resp.DelegatedFrom, ", ")))
+		}
 
 		if result.RequestStatusChanged {
-			fmt.Printf("Request status changed to: %s\n", resp.NewRequestStatus)
+			output.Notef(os.Stdout, "%s\n", i18n.T("review.status_changed", i18n.Status(resp.NewRequestStatus)))
 			if result.NewRequestStatus == db.StatusApproved {
-				fmt.Println("Request is now approved and ready for execution!")
+				output.Notef(os.Stdout, "%s\n", i18n.T("approve.ready"))
 			}
 		}
 
@@ -163,6 +255,31 @@ database contains the request you want to approve.
 	},
 }
 
+// proposeEdit records a corrected command the reviewer would approve in
+// place of req's and tells them how its requestor accepts it.
+func proposeEdit(reviewSvc *core.ReviewService, req *db.Request, opts core.ProposeEditOptions) error {
+	edit, err := reviewSvc.ProposeEdit(opts)
+	if err != nil {
+		return fmt.Errorf("proposing edit: %w", err)
+	}
+
+	out := output.New(output.Format(GetOutput()))
+	if isJSONOutput() {
+		return out.Write(map[string]any{
+			"edit_id":         edit.ID,
+			"request_id":      edit.RequestID,
+			"command":         edit.Command,
+			"reviewer_agent":  edit.ReviewerAgent,
+			"requestor_agent": req.RequestorAgent,
+			"status":          string(edit.Status),
+			"created_at":      timefmt.Format(edit.CreatedAt),
+		})
+	}
+	output.Notef(os.Stdout, "%s\n", i18n.T("approve.edit_proposed", edit.ID, edit.RequestID, edit.Command))
+	output.Notef(os.Stdout, "%s\n", i18n.T("approve.edit_accept", req.RequestorAgent, edit.ID))
+	return nil
+}
+
 // buildAgentMailNotifier constructs a notifier from config; falls back to no-op on errors/disabled.
 func buildAgentMailNotifier(project string) integrations.RequestNotifier {
 	cfg, err := config.Load(config.LoadOptions{
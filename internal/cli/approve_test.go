@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/i18n"
 	"github.com/Dicklesworthstone/slb/internal/integrations"
 	"github.com/Dicklesworthstone/slb/internal/testutil"
 	"github.com/spf13/cobra"
@@ -30,19 +31,24 @@ func newTestApproveCmd(dbPath string) *cobra.Command {
 	// production: no -s local shorthand (-s is owned by the persistent
 	// --session-id); the session is passed via the long --session-id flag.
 	approve := &cobra.Command{
-		Use:   "approve <request-id>",
+		Use:   "approve [request-id]",
 		Short: "Approve a pending request",
-		Args:  cobra.ExactArgs(1),
+		Args:  approveCmd.Args,
 		RunE:  approveCmd.RunE,
 	}
 	approve.Flags().StringVar(&flagApproveSessionID, "session-id", "", "reviewer session ID (required)")
 	approve.Flags().StringVarP(&flagApproveSessionKey, "session-key", "k", "", "session HMAC key for signing (required)")
 	approve.Flags().StringVarP(&flagApproveComments, "comments", "m", "", "additional comments")
+	approve.Flags().StringVar(&flagApproveComments, "comment", "", "alias for --comments")
+	approve.Flags().BoolVar(&flagApproveLatest, "latest", false, "approve the newest pending request you have not reviewed")
 	approve.Flags().StringVar(&flagApproveTargetProject, "target-project", "", "target project path for cross-project approvals")
 	approve.Flags().StringVar(&flagApproveReasonResponse, "reason-response", "", "response to the reason justification")
 	approve.Flags().StringVar(&flagApproveEffectResponse, "effect-response", "", "response to the expected effect")
 	approve.Flags().StringVar(&flagApproveGoalResponse, "goal-response", "", "response to the goal")
 	approve.Flags().StringVar(&flagApproveSafetyResponse, "safety-response", "", "response to the safety argument")
+	approve.Flags().IntVar(&flagApproveVersion, "expected-version", 0, "fail if the request changed since this version (from 'slb show --json')")
+	approve.Flags().StringVar(&flagApproveEdit, "edit", "", "approve only this corrected command")
+	approve.Flags().StringSliceVar(&flagApproveAckVars, "ack-vars", nil, "acknowledge template variables")
 
 	root.AddCommand(approve)
 
@@ -59,10 +65,14 @@ func resetApproveFlags() {
 	flagApproveSessionKey = ""
 	flagApproveComments = ""
 	flagApproveTargetProject = ""
+	flagApproveLatest = false
 	flagApproveReasonResponse = ""
 	flagApproveEffectResponse = ""
 	flagApproveGoalResponse = ""
 	flagApproveSafetyResponse = ""
+	flagApproveVersion = 0
+	flagApproveEdit = ""
+	flagApproveAckVars = nil
 }
 
 func TestApproveCommand_RequiresRequestID(t *testing.T) {
@@ -95,6 +105,21 @@ func TestApproveCommand_RequiresSessionID(t *testing.T) {
 	}
 }
 
+func TestApproveCommand_LocalizedError(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetApproveFlags()
+	prev := i18n.Locale()
+	i18n.SetLocale("es")
+	t.Cleanup(func() { i18n.SetLocale(prev) })
+
+	cmd := newTestApproveCmd(h.DBPath)
+	_, _, err := executeCommand(cmd, "approve", "some-request-id")
+
+	if err == nil || !strings.Contains(err.Error(), "se requiere --session-id") {
+		t.Errorf("expected Spanish error, got %v", err)
+	}
+}
+
 func TestApproveCommand_RequiresSessionKey(t *testing.T) {
 	h := testutil.NewHarness(t)
 	resetApproveFlags()
@@ -215,6 +240,94 @@ func TestApproveCommand_WithComments(t *testing.T) {
 	}
 }
 
+func TestApproveCommand_LatestResolvesActiveSession(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetApproveFlags()
+	origActor := flagActor
+	t.Cleanup(func() { flagActor = origActor })
+
+	requestorSess := testutil.MakeSession(t, h.DB,
+		testutil.WithProject(h.ProjectDir),
+		testutil.WithAgent("Requestor"),
+	)
+	reviewerSess := testutil.MakeSession(t, h.DB,
+		testutil.WithProject(h.ProjectDir),
+		testutil.WithAgent("Reviewer"),
+	)
+
+	older := testutil.MakeRequest(t, h.DB, requestorSess)
+	newer := testutil.MakeRequest(t, h.DB, requestorSess)
+	own := testutil.MakeRequest(t, h.DB, reviewerSess)
+	h.DB.Exec(`UPDATE requests SET created_at = ? WHERE id = ?`, "2020-01-01T00:00:00Z", older.ID)
+	h.DB.Exec(`UPDATE requests SET created_at = ? WHERE id = ?`, "2020-01-02T00:00:00Z", newer.ID)
+	h.DB.Exec(`UPDATE requests SET created_at = ? WHERE id = ?`, "2020-01-03T00:00:00Z", own.ID)
+	h.DB.Exec(`UPDATE requests SET min_approvals = 2, require_different_model = false`)
+
+	// No --session-id: the reviewer is the actor's active session.
+	flagActor = "Reviewer"
+	cmd := newTestApproveCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "approve", "--latest",
+		"-k", reviewerSess.SessionKey,
+		"--comment", "checked",
+		"-C", h.ProjectDir,
+		"-j",
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var result struct {
+		RequestID string      `json:"request_id"`
+		Quorum    quorumState `json:"quorum"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if result.RequestID != newer.ID {
+		t.Errorf("--latest should pick the newest request not submitted by the reviewer, got %s", result.RequestID)
+	}
+	want := quorumState{Status: "pending", MinApprovals: 2, Approvals: 1, Remaining: 1}
+	if result.Quorum != want {
+		t.Errorf("quorum = %+v, want %+v", result.Quorum, want)
+	}
+
+	reviews, _ := h.DB.ListReviewsForRequest(newer.ID)
+	if len(reviews) != 1 || reviews[0].ReviewerSessionID != reviewerSess.ID || reviews[0].Comments != "checked" {
+		t.Fatalf("expected one review by the resolved session with comment, got %+v", reviews)
+	}
+
+	// The reviewed request is skipped next time.
+	resetApproveFlags()
+	cmd = newTestApproveCmd(h.DBPath)
+	stdout, err = executeCommandCapture(t, cmd, "approve", "--latest",
+		"-k", reviewerSess.SessionKey, "-C", h.ProjectDir, "-j")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, older.ID) {
+		t.Errorf("expected second --latest to pick %s, got %s", older.ID, stdout)
+	}
+
+	resetApproveFlags()
+	cmd = newTestApproveCmd(h.DBPath)
+	_, err = executeCommandCapture(t, cmd, "approve", "--latest",
+		"-k", reviewerSess.SessionKey, "-C", h.ProjectDir)
+	if err == nil || !strings.Contains(err.Error(), "no pending request") {
+		t.Errorf("expected no reviewable request error, got %v", err)
+	}
+}
+
+func TestApproveCommand_LatestConflictsWithRequestID(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetApproveFlags()
+
+	cmd := newTestApproveCmd(h.DBPath)
+	_, _, err := executeCommand(cmd, "approve", "req-1", "--latest")
+	if err == nil || !strings.Contains(err.Error(), "--latest") {
+		t.Errorf("expected conflict error, got %v", err)
+	}
+}
+
 func TestApproveCommand_SelfReviewPrevented(t *testing.T) {
 	h := testutil.NewHarness(t)
 	resetApproveFlags()
@@ -244,6 +357,110 @@ func TestApproveCommand_SelfReviewPrevented(t *testing.T) {
 	}
 }
 
+func TestApproveCommand_ExpectedVersion(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetApproveFlags()
+
+	requestorSess := testutil.MakeSession(t, h.DB,
+		testutil.WithProject(h.ProjectDir),
+		testutil.WithAgent("Requestor"),
+	)
+	reviewerSess := testutil.MakeSession(t, h.DB,
+		testutil.WithProject(h.ProjectDir),
+		testutil.WithAgent("Reviewer"),
+	)
+	req := testutil.MakeRequest(t, h.DB, requestorSess)
+	h.DB.Exec(`UPDATE requests SET min_approvals = 1, require_different_model = false WHERE id = ?`, req.ID)
+
+	cmd := newTestApproveCmd(h.DBPath)
+	_, err := executeCommandCapture(t, cmd, "approve", req.ID,
+		"--session-id", reviewerSess.ID,
+		"-k", reviewerSess.SessionKey,
+		"-C", h.ProjectDir,
+		"--expected-version", "5",
+		"-j",
+	)
+	if err == nil {
+		t.Fatal("expected error for a stale version")
+	}
+	if !strings.Contains(err.Error(), "changed since it was read") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	resetApproveFlags()
+	cmd = newTestApproveCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "approve", req.ID,
+		"--session-id", reviewerSess.ID,
+		"-k", reviewerSess.SessionKey,
+		"-C", h.ProjectDir,
+		"--expected-version", "1",
+		"-j",
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if result["new_request_status"] != string(db.StatusApproved) {
+		t.Errorf("expected new_request_status=approved, got %v", result["new_request_status"])
+	}
+	if v, _ := result["version"].(float64); v <= 1 {
+		t.Errorf("expected version to advance past 1, got %v", result["version"])
+	}
+}
+
+func TestApproveCommand_AckVars(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetApproveFlags()
+
+	requestorSess := testutil.MakeSession(t, h.DB,
+		testutil.WithProject(h.ProjectDir),
+		testutil.WithAgent("Requestor"),
+	)
+	reviewerSess := testutil.MakeSession(t, h.DB,
+		testutil.WithProject(h.ProjectDir),
+		testutil.WithAgent("Reviewer"),
+	)
+	req := testutil.MakeRequest(t, h.DB, requestorSess,
+		testutil.WithCommand("kubectl delete ns $NAMESPACE --context {{env}}", h.ProjectDir, true),
+	)
+	h.DB.Exec(`UPDATE requests SET min_approvals = 1, require_different_model = false WHERE id = ?`, req.ID)
+
+	approve := func(extra ...string) (string, error) {
+		resetApproveFlags()
+		args := append([]string{"approve", req.ID,
+			"--session-id", reviewerSess.ID,
+			"-k", reviewerSess.SessionKey,
+			"-C", h.ProjectDir,
+			"-j",
+		}, extra...)
+		return executeCommandCapture(t, newTestApproveCmd(h.DBPath), args...)
+	}
+
+	_, err := approve()
+	if err == nil || !strings.Contains(err.Error(), "--ack-vars NAMESPACE,env") {
+		t.Fatalf("expected an unexpanded-variables error naming --ack-vars, got %v", err)
+	}
+	if _, err := approve("--ack-vars", "NAMESPACE"); err == nil || !strings.Contains(err.Error(), "{{env}}") {
+		t.Fatalf("expected {{env}} to be reported unacknowledged, got %v", err)
+	}
+
+	stdout, err := approve("--ack-vars", "NAMESPACE,env")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var result map[string]any
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if result["new_request_status"] != string(db.StatusApproved) {
+		t.Errorf("expected new_request_status=approved, got %v", result["new_request_status"])
+	}
+}
+
 func TestApproveCommand_InvalidSessionKey(t *testing.T) {
 	h := testutil.NewHarness(t)
 	resetApproveFlags()
@@ -14,6 +14,8 @@ type AttachmentFlags struct {
 	Files       []string
 	Contexts    []string
 	Screenshots []string
+	// Transcripts are agent transcript files attached as capped, redacted snippets.
+	Transcripts []string
 }
 
 // CollectAttachments loads and processes attachments from CLI flags.
@@ -49,5 +51,14 @@ func CollectAttachments(ctx context.Context, flags AttachmentFlags) ([]db.Attach
 		attachments = append(attachments, *attachment)
 	}
 
+	// Process transcript snippets
+	for _, path := range flags.Transcripts {
+		attachment, err := core.LoadTranscriptSnippet(path, &config)
+		if err != nil {
+			return nil, fmt.Errorf("loading transcript %q: %w", path, err)
+		}
+		attachments = append(attachments, *attachment)
+	}
+
 	return attachments, nil
 }
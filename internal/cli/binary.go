@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// formatBinary describes the executable a request would run: its path,
+// the symlink target, what kind of file it is and a short digest.
+func formatBinary(b *db.RequestBinary) string {
+	s := b.Path
+	if b.RealPath != "" {
+		s += " -> " + b.RealPath
+	}
+	var notes []string
+	if b.IsScript() {
+		notes = append(notes, "script "+b.Interpreter)
+	}
+	if b.SHA256 != "" {
+		notes = append(notes, "sha256:"+shortHash(b.SHA256))
+	}
+	if len(notes) > 0 {
+		s += " (" + strings.Join(notes, ", ") + ")"
+	}
+	return s
+}
+
+// binaryNotice warns reviewers when a command runs something from inside
+// the project instead of an installed tool. It is empty otherwise.
+func binaryNotice(b *db.RequestBinary) string {
+	if !b.InProject {
+		return ""
+	}
+	if b.IsScript() {
+		return fmt.Sprintf("%s is a script inside the project, not an installed tool", b.Name)
+	}
+	return fmt.Sprintf("%s resolves to an executable inside the project", b.Name)
+}
+
+func shortHash(sum string) string {
+	if len(sum) > 12 {
+		return sum[:12]
+	}
+	return sum
+}
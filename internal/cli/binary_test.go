@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestFormatBinary(t *testing.T) {
+	b := &db.RequestBinary{
+		Name:        "terraform",
+		Path:        "/repo/bin/terraform",
+		RealPath:    "/repo/tools/tf.sh",
+		SHA256:      "0123456789abcdef0123",
+		InProject:   true,
+		Interpreter: "#!/bin/bash",
+	}
+	got := formatBinary(b)
+	want := "/repo/bin/terraform -> /repo/tools/tf.sh (script #!/bin/bash, sha256:0123456789ab)"
+	if got != want {
+		t.Fatalf("formatBinary() = %q, want %q", got, want)
+	}
+	if notice := binaryNotice(b); !strings.Contains(notice, "terraform is a script inside the project") {
+		t.Fatalf("unexpected notice %q", notice)
+	}
+
+	b.Interpreter = ""
+	if notice := binaryNotice(b); !strings.Contains(notice, "inside the project") {
+		t.Fatalf("unexpected notice %q", notice)
+	}
+
+	installed := &db.RequestBinary{Name: "terraform", Path: "/usr/bin/terraform"}
+	if got := formatBinary(installed); got != "/usr/bin/terraform" {
+		t.Fatalf("formatBinary() = %q", got)
+	}
+	if notice := binaryNotice(installed); notice != "" {
+		t.Fatalf("expected no notice for an installed tool, got %q", notice)
+	}
+}
+
+func TestReviewShowCommand_IncludesBinary(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetReviewFlags()
+
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	req := testutil.MakeRequest(t, h.DB, sess, testutil.WithCommand("terraform destroy", h.ProjectDir, false))
+	testutil.RequireNoError(t, h.DB.SetRequestBinary(&db.RequestBinary{
+		RequestID: req.ID, Name: "terraform", Path: h.ProjectDir + "/bin/terraform",
+		InProject: true, Interpreter: "#!/bin/sh",
+	}), "set binary")
+
+	cmd := newTestReviewCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "review", "show", req.ID, "-j")
+	testutil.RequireNoError(t, err, "review show")
+
+	var result struct {
+		Binary *db.RequestBinary `json:"binary"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if result.Binary == nil || result.Binary.Name != "terraform" || !result.Binary.InProject {
+		t.Fatalf("expected the recorded binary, got %+v", result.Binary)
+	}
+}
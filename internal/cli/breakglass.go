@@ -0,0 +1,356 @@
+package cli
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/integrations"
+	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagBreakglassReason     string
+	flagBreakglassTimeout    int
+	flagBreakglassLogDir     string
+	flagBreakglassPostmortem string
+	flagBreakglassAll        bool
+)
+
+func init() {
+	breakglassCmd.Flags().StringVarP(&flagBreakglassReason, "reason", "r", "", "why approval must be bypassed (required)")
+	// No -t shorthand: -t (--toon) is owned by the root persistent flags.
+	breakglassCmd.Flags().IntVar(&flagBreakglassTimeout, "timeout", 300, "execution timeout in seconds")
+	breakglassCmd.Flags().StringVar(&flagBreakglassLogDir, "log-dir", ".slb/logs", "directory for execution logs")
+
+	breakglassAckCmd.Flags().StringVar(&flagBreakglassPostmortem, "postmortem", "", "postmortem note explaining the incident (required)")
+	breakglassListCmd.Flags().BoolVar(&flagBreakglassAll, "all", false, "include acknowledged incidents")
+
+	breakglassCmd.AddCommand(breakglassAckCmd)
+	breakglassCmd.AddCommand(breakglassListCmd)
+
+	rootCmd.AddCommand(breakglassCmd)
+}
+
+var breakglassCmd = &cobra.Command{
+	Use:   "breakglass \"<command>\"",
+	Short: "Execute immediately without approval and open an incident",
+	Long: `Execute a command immediately, bypassing approval, for genuine emergencies.
+
+Every break-glass execution:
+- Must be confirmed at the controlling terminal: the human enters their
+  name and types BREAKGLASS, so an agent cannot bypass approval on its own
+- Records a high-visibility incident under that name and the OS account
+  that ran it (see 'slb breakglass list')
+- Notifies all reviewers
+- Requires a postmortem via 'slb breakglass ack' within
+  general.breakglass_ack_hours (default 24h), confirmed at the terminal
+  under a different name and from a different OS account than the
+  break-glass. Names are self-declared and only compared ignoring case and
+  spacing; the OS account is what keeps one person from acknowledging
+  their own incident, so give each human their own account
+- Is rate limited to once per general.breakglass_cooldown_hours per project
+
+The command runs directly on the host as the invoking user: [isolation]
+settings do not apply to break-glass executions.
+
+While an incident is overdue for its postmortem, further break-glass
+executions in the project are refused.
+
+Examples:
+  slb breakglass "systemctl restart api" -r "API down, on-call paged"
+  slb breakglass ack <incident-id> --postmortem "Restarted after OOM; fix in #123"
+  slb breakglass list`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		command := args[0]
+		if strings.TrimSpace(flagBreakglassReason) == "" {
+			return fmt.Errorf("--reason is required for break-glass execution")
+		}
+
+		project, err := projectPath()
+		if err != nil {
+			return err
+		}
+		cfg, err := config.Load(config.LoadOptions{
+			ProjectDir: project,
+			ConfigPath: flagConfig,
+		})
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+
+		dbConn, err := db.OpenAndMigrate(GetDB())
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer dbConn.Close()
+
+		inc, err := openBreakglassIncident(cfg, dbConn, project, command, flagBreakglassReason)
+		if err != nil {
+			return err
+		}
+		now := inc.ExecutedAt
+
+		if err := os.MkdirAll(flagBreakglassLogDir, 0700); err != nil {
+			return fmt.Errorf("creating log dir: %w", err)
+		}
+		logPath := filepath.Join(flagBreakglassLogDir,
+			fmt.Sprintf("breakglass_%s_%s.log", now.Format("20060102-150405"), inc.ID[:8]))
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(flagBreakglassTimeout)*time.Second)
+		defer cancel()
+
+		var stream io.Writer
+		if !isJSONOutput() {
+			stream = os.Stdout
+		}
+		spec := &db.CommandSpec{Raw: command, Cwd: inc.Cwd, Shell: true, Hash: inc.CommandHash}
+		result, runErr := core.RunCommand(ctx, spec, logPath, stream)
+		secrets := core.ScanCommandOutput(result, logPath, nil)
+
+		var exitCode *int
+		if result != nil {
+			code := result.ExitCode
+			exitCode = &code
+		}
+		if err := dbConn.UpdateBreakglassResult(inc.ID, exitCode, logPath); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to record break-glass result: %v\n", err)
+		}
+		inc.ExitCode = exitCode
+		inc.LogPath = logPath
+
+		out := output.New(output.Format(GetOutput()))
+		if isJSONOutput() {
+			view := breakglassView(inc, time.Now())
+			if runErr != nil {
+				view["error"] = runErr.Error()
+			}
+			if len(secrets) > 0 {
+				view["secrets_found"] = secrets
+			}
+			if err := out.Write(view); err != nil {
+				return err
+			}
+			return runErr
+		}
+
+		output.Notef(os.Stdout, "\n")
+		if runErr != nil {
+			fmt.Printf("Break-glass execution failed: %s\n", runErr)
+		} else {
+			output.Notef(os.Stdout, "Break-glass execution completed (exit code %d)\n", result.ExitCode)
+		}
+		warnBreakglassSecrets(secrets)
+		output.Notef(os.Stdout, "Incident: %s\n", inc.ID)
+		output.Notef(os.Stdout, "Log:      %s\n", logPath)
+		output.Notef(os.Stdout, "\n")
+		output.Notef(os.Stdout, "All reviewers have been notified. A postmortem is due by %s:\n",
+			inc.AckDueAt.Local().Format("Mon Jan 2 15:04 MST"))
+		output.Notef(os.Stdout, "  slb breakglass ack %s --postmortem \"...\"\n", inc.ID)
+		return runErr
+	},
+}
+
+var breakglassAckCmd = &cobra.Command{
+	Use:   "ack <incident-id>",
+	Short: "Record the postmortem for a break-glass incident",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if strings.TrimSpace(flagBreakglassPostmortem) == "" {
+			return fmt.Errorf("--postmortem is required")
+		}
+
+		dbConn, err := db.OpenAndMigrate(GetDB())
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer dbConn.Close()
+
+		inc, err := dbConn.GetBreakglassIncident(args[0])
+		if err != nil {
+			return err
+		}
+		// The postmortem is recorded under the name given at the terminal and
+		// the OS account running slb; neither may match the break-glass.
+		by, err := confirmHuman("break-glass postmortem", []string{
+			"Postmortem for break-glass incident " + inc.ID + ":",
+			"  command:    " + core.ApplyRedaction(inc.Command, nil),
+			"  broken by:  " + inc.Actor,
+			"  postmortem: " + flagBreakglassPostmortem,
+		}, "ACK")
+		if err != nil {
+			return err
+		}
+		if err := dbConn.AcknowledgeBreakglass(inc.ID, by, currentOSUser(), flagBreakglassPostmortem); err != nil {
+			return err
+		}
+		if inc, err = dbConn.GetBreakglassIncident(inc.ID); err != nil {
+			return err
+		}
+
+		out := output.New(output.Format(GetOutput()))
+		if isJSONOutput() {
+			return out.Write(breakglassView(inc, time.Now()))
+		}
+		late := ""
+		if inc.AcknowledgedAt != nil && inc.AcknowledgedAt.After(inc.AckDueAt) {
+			late = " (late)"
+		}
+		output.Notef(os.Stdout, "Postmortem recorded for incident %s%s\n", inc.ID, late)
+		return nil
+	},
+}
+
+var breakglassListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List break-glass incidents for this project",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		project, err := projectPath()
+		if err != nil {
+			return err
+		}
+
+		dbConn, err := db.OpenAndMigrate(GetDB())
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer dbConn.Close()
+
+		incidents, err := dbConn.ListBreakglassIncidents(project, !flagBreakglassAll)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		out := output.New(output.Format(GetOutput()))
+		if isJSONOutput() {
+			views := make([]map[string]any, 0, len(incidents))
+			for _, inc := range incidents {
+				views = append(views, breakglassView(inc, now))
+			}
+			return out.Write(views)
+		}
+
+		if len(incidents) == 0 {
+			fmt.Println("No open break-glass incidents.")
+			return nil
+		}
+		for _, inc := range incidents {
+			state := "awaiting postmortem"
+			switch {
+			case inc.AcknowledgedAt != nil:
+				state = "acknowledged"
+			case inc.IsOverdue(now):
+				state = "OVERDUE"
+			}
+			fmt.Printf("%s  %s  by %s  [%s]\n", inc.ID, inc.ExecutedAt.Local().Format("2006-01-02 15:04"), inc.Actor, state)
+			fmt.Printf("    command: %s\n", core.ApplyRedaction(inc.Command, nil))
+			fmt.Printf("    reason:  %s\n", inc.Reason)
+			if inc.Postmortem != "" {
+				fmt.Printf("    postmortem: %s\n", inc.Postmortem)
+			}
+		}
+		return nil
+	},
+}
+
+// openBreakglassIncident asks the human at the terminal to confirm running
+// command without approval, records the incident under their name if the
+// project's cooldown and open postmortems allow it, and notifies reviewers.
+// The check and the insert are one transaction, so concurrent calls cannot
+// both get through.
+func openBreakglassIncident(cfg config.Config, dbConn *db.DB, project, command, reason string) (*db.BreakglassIncident, error) {
+	hash := sha256.Sum256([]byte(command))
+	commandHash := hex.EncodeToString(hash[:])
+	actor, err := confirmHuman("break-glass execution", []string{
+		"Break-glass execution bypasses approval and opens an incident:",
+		"  command: " + core.ApplyRedaction(command, nil),
+		"  reason:  " + reason,
+		"  hash:    " + commandHash,
+	}, "BREAKGLASS")
+	if err != nil {
+		return nil, err
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		cwd = "."
+	}
+	now := time.Now().UTC()
+	policy := core.BreakglassPolicy{
+		Cooldown:  time.Duration(cfg.General.BreakglassCooldownHours) * time.Hour,
+		AckWindow: time.Duration(cfg.General.BreakglassAckHours) * time.Hour,
+	}
+	// Record the incident before running so a crash still leaves a trace.
+	inc := &db.BreakglassIncident{
+		ProjectPath: project,
+		Actor:       actor,
+		ActorOSUser: currentOSUser(),
+		Command:     command,
+		CommandHash: commandHash,
+		Cwd:         cwd,
+		Reason:      reason,
+		ExecutedAt:  now,
+		AckDueAt:    now.Add(policy.AckWindow),
+	}
+	allow := func(previous []*db.BreakglassIncident) error {
+		return core.CheckBreakglassAllowed(policy, previous, now)
+	}
+	if err := dbConn.CreateBreakglassIncidentIfAllowed(inc, allow); err != nil {
+		return nil, err
+	}
+	notifyBreakglass(cfg, project, inc)
+	return inc, nil
+}
+
+// notifyBreakglass broadcasts the incident to reviewers (best effort, redacted).
+func notifyBreakglass(cfg config.Config, project string, inc *db.BreakglassIncident) {
+	if !cfg.Integrations.AgentMailEnabled {
+		return
+	}
+	redacted := *inc
+	redacted.Command = core.ApplyRedaction(inc.Command, nil)
+	client := integrations.NewAgentMailClient(project, cfg.Integrations.AgentMailThread, "")
+	if err := client.NotifyBreakglass(&redacted); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to notify reviewers: %v\n", err)
+	}
+}
+
+func breakglassView(inc *db.BreakglassIncident, now time.Time) map[string]any {
+	view := map[string]any{
+		"id":           inc.ID,
+		"project_path": inc.ProjectPath,
+		"actor":        inc.Actor,
+		"command":      core.ApplyRedaction(inc.Command, nil),
+		"command_hash": inc.CommandHash,
+		"reason":       inc.Reason,
+		"executed_at":  timefmt.Format(inc.ExecutedAt),
+		"ack_due_at":   timefmt.Format(inc.AckDueAt),
+		"overdue":      inc.IsOverdue(now),
+	}
+	if inc.ExitCode != nil {
+		view["exit_code"] = *inc.ExitCode
+	}
+	if inc.LogPath != "" {
+		view["log_path"] = inc.LogPath
+	}
+	if inc.AcknowledgedAt != nil {
+		view["acknowledged_at"] = timefmt.Format(*inc.AcknowledgedAt)
+		view["acknowledged_by"] = inc.AcknowledgedBy
+		view["postmortem"] = inc.Postmortem
+	}
+	return view
+}
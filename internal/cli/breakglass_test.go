@@ -0,0 +1,176 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+	"github.com/spf13/cobra"
+)
+
+// newTestBreakglassCmd creates a fresh breakglass command tree for testing.
+func newTestBreakglassCmd(dbPath string) *cobra.Command {
+	root := &cobra.Command{
+		Use:           "slb",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	root.PersistentFlags().StringVarP(&flagConfig, "config", "c", "", "config file path")
+	root.PersistentFlags().StringVarP(&flagOutput, "output", "o", "text", "output format")
+	root.PersistentFlags().BoolVarP(&flagJSON, "json", "j", false, "shorthand for --output=json")
+	root.PersistentFlags().StringVar(&flagDB, "db", dbPath, "database path")
+	root.PersistentFlags().StringVar(&flagActor, "actor", "", "actor identifier")
+	root.PersistentFlags().StringVarP(&flagProject, "project", "C", "", "project directory")
+
+	root.AddCommand(breakglassCmd)
+
+	return root
+}
+
+// resetBreakglassFlags resets all breakglass-related flags to defaults.
+func resetBreakglassFlags() {
+	flagConfig = ""
+	flagOutput = "text"
+	flagJSON = false
+	flagDB = ""
+	flagActor = ""
+	flagProject = ""
+	flagBreakglassReason = ""
+	flagBreakglassTimeout = 300
+	flagBreakglassLogDir = ".slb/logs"
+	flagBreakglassPostmortem = ""
+	flagBreakglassAll = false
+}
+
+func TestBreakglass_RequiresReason(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetBreakglassFlags()
+
+	cmd := newTestBreakglassCmd(h.DBPath)
+	_, _, err := executeCommand(cmd, "breakglass", "true", "-C", h.ProjectDir)
+	if err == nil || !strings.Contains(err.Error(), "--reason is required") {
+		t.Fatalf("expected --reason error, got %v", err)
+	}
+}
+
+func TestBreakglass_ExecuteRateLimitAndAck(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell execution test uses /bin/sh or $SHELL")
+	}
+	h := testutil.NewHarness(t)
+	resetBreakglassFlags()
+
+	// The human at the terminal: who answers, and whether they confirm.
+	var summaries []string
+	human, answer := "oncall", errors.New("break-glass execution not confirmed")
+	orig := confirmHuman
+	confirmHuman = func(action string, summary []string, _ string) (string, error) {
+		summaries = append(summaries, action+": "+strings.Join(summary, "\n"))
+		return human, answer
+	}
+	t.Cleanup(func() { confirmHuman = orig })
+	// The OS account slb runs as; acks must come from another one.
+	osUser := "alice"
+	origOSUser := currentOSUser
+	currentOSUser = func() string { return osUser }
+	t.Cleanup(func() { currentOSUser = origOSUser })
+
+	logDir := t.TempDir()
+	cmd := newTestBreakglassCmd(h.DBPath)
+	if _, err := executeCommandCapture(t, cmd, "breakglass", "true",
+		"-r", "api down", "--log-dir", logDir, "--actor", "agent", "-C", h.ProjectDir, "-j"); !errors.Is(err, answer) {
+		t.Fatalf("expected the unconfirmed break-glass to be refused, got %v", err)
+	}
+	hash := sha256.Sum256([]byte("true"))
+	if len(summaries) != 1 || !strings.Contains(summaries[0], hex.EncodeToString(hash[:])) {
+		t.Fatalf("expected one confirmation showing the command hash, got %v", summaries)
+	}
+	if incidents, _ := h.DB.ListBreakglassIncidents(h.ProjectDir, false); len(incidents) != 0 {
+		t.Fatalf("an unconfirmed break-glass must not open an incident, got %v", incidents)
+	}
+
+	answer = nil
+	resetBreakglassFlags()
+	cmd = newTestBreakglassCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "breakglass", "true",
+		"-r", "api down", "--log-dir", logDir, "--actor", "agent", "-C", h.ProjectDir, "-j")
+	if err != nil {
+		t.Fatalf("breakglass: %v", err)
+	}
+
+	var created map[string]any
+	if err := json.Unmarshal([]byte(stdout), &created); err != nil {
+		t.Fatalf("parse JSON: %v\n%s", err, stdout)
+	}
+	id, _ := created["id"].(string)
+	if id == "" || created["actor"] != "oncall" || created["exit_code"] != float64(0) {
+		t.Fatalf("unexpected incident: %v", created)
+	}
+
+	// A second break-glass inside the cooldown window is refused.
+	resetBreakglassFlags()
+	cmd = newTestBreakglassCmd(h.DBPath)
+	_, err = executeCommandCapture(t, cmd, "breakglass", "true",
+		"-r", "again", "--log-dir", logDir, "-C", h.ProjectDir, "-j")
+	if !errors.Is(err, core.ErrBreakglassCooldown) {
+		t.Fatalf("expected cooldown error, got %v", err)
+	}
+
+	resetBreakglassFlags()
+	cmd = newTestBreakglassCmd(h.DBPath)
+	stdout, err = executeCommandCapture(t, cmd, "breakglass", "list", "-C", h.ProjectDir, "-j")
+	if err != nil {
+		t.Fatalf("breakglass list: %v", err)
+	}
+	var open []map[string]any
+	if err := json.Unmarshal([]byte(stdout), &open); err != nil {
+		t.Fatalf("parse JSON: %v\n%s", err, stdout)
+	}
+	if len(open) != 1 || open[0]["id"] != id {
+		t.Fatalf("expected open incident %s, got %v", id, open)
+	}
+
+	resetBreakglassFlags()
+	cmd = newTestBreakglassCmd(h.DBPath)
+	if _, _, err := executeCommand(cmd, "breakglass", "ack", id); err == nil || !strings.Contains(err.Error(), "--postmortem is required") {
+		t.Fatalf("expected --postmortem error, got %v", err)
+	}
+
+	resetBreakglassFlags()
+	cmd = newTestBreakglassCmd(h.DBPath)
+	// --actor does not decide who acknowledges: the human at the terminal does.
+	if _, err := executeCommandCapture(t, cmd, "breakglass", "ack", id, "--postmortem", "all good", "--actor", "lead", "-j"); !errors.Is(err, db.ErrBreakglassSelfAck) {
+		t.Fatalf("expected the breaker's own ack to be refused, got %v", err)
+	}
+
+	// A different name from the same OS account is still the same human.
+	human = "lead"
+	resetBreakglassFlags()
+	cmd = newTestBreakglassCmd(h.DBPath)
+	if _, err := executeCommandCapture(t, cmd, "breakglass", "ack", id, "--postmortem", "all good", "-j"); !errors.Is(err, db.ErrBreakglassSelfAck) {
+		t.Fatalf("expected an ack from the breaker's OS account to be refused, got %v", err)
+	}
+
+	osUser = "bob"
+	resetBreakglassFlags()
+	cmd = newTestBreakglassCmd(h.DBPath)
+	if _, err := executeCommandCapture(t, cmd, "breakglass", "ack", id, "--postmortem", "restarted after OOM", "--actor", "oncall", "-j"); err != nil {
+		t.Fatalf("breakglass ack: %v", err)
+	}
+
+	inc, err := h.DB.GetBreakglassIncident(id)
+	if err != nil {
+		t.Fatalf("GetBreakglassIncident: %v", err)
+	}
+	if inc.AcknowledgedAt == nil || inc.AcknowledgedBy != "lead" || inc.AcknowledgedByOSUser != "bob" || inc.Postmortem != "restarted after OOM" {
+		t.Fatalf("expected acknowledged incident, got %+v", inc)
+	}
+}
@@ -5,9 +5,10 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/core/statemachine"
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
 	"github.com/spf13/cobra"
 )
 
@@ -48,12 +49,13 @@ Use --session-id/-s to specify your session if not using environment.`,
 		}
 
 		// Verify the request can be cancelled (pending or approved, but not yet executing)
-		if !core.CanCancel(request.Status) {
+		if !statemachine.CanCancel(request.Status) {
 			return fmt.Errorf("cannot cancel request: status is %s (must be pending or approved)", request.Status)
 		}
 
 		// Cancel the request
-		if err := dbConn.UpdateRequestStatus(requestID, db.StatusCancelled); err != nil {
+		in := statemachine.Input{Authority: statemachine.AuthoritySystem, Actor: request.RequestorAgent, Reason: "cancelled by requestor"}
+		if _, err := statemachine.New(dbConn).Commit(requestID, db.StatusCancelled, in); err != nil {
 			return fmt.Errorf("cancelling request: %w", err)
 		}
 
@@ -61,7 +63,7 @@ Use --session-id/-s to specify your session if not using environment.`,
 		return out.Write(map[string]any{
 			"request_id":   requestID,
 			"status":       "cancelled",
-			"cancelled_at": time.Now().UTC().Format(time.RFC3339),
+			"cancelled_at": timefmt.Format(time.Now()),
 		})
 	},
 }
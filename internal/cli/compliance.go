@@ -0,0 +1,226 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagCompliancePeriod      string
+	flagComplianceOutputFile  string
+	flagComplianceAllProjects bool
+)
+
+func init() {
+	complianceExportCmd.Flags().StringVar(&flagCompliancePeriod, "period", "", "reporting period: YYYY-Qn, YYYY-MM or YYYY (required)")
+	// Named --output-file: the persistent --output/-o is the output format.
+	complianceExportCmd.Flags().StringVar(&flagComplianceOutputFile, "output-file", "", "bundle path (default: slb-compliance-<period>.zip)")
+	complianceExportCmd.Flags().BoolVar(&flagComplianceAllProjects, "all-projects", false, "export every project instead of the current one")
+
+	complianceCmd.AddCommand(complianceExportCmd)
+	complianceCmd.AddCommand(complianceVerifyCmd)
+	rootCmd.AddCommand(complianceCmd)
+}
+
+var complianceCmd = &cobra.Command{
+	Use:   "compliance",
+	Short: "Export and verify change-management evidence",
+}
+
+var complianceExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write a zip bundle of audit evidence for a period",
+	Long: `Write a zip bundle of change-management evidence for a reporting period.
+
+The bundle contains:
+  audit.jsonl        Requests created in the period with their reviews,
+                     executions and resolutions, plus break-glass incidents
+                     and pattern changes, as a SHA-256 hash chain
+  patterns.json      The pattern set in effect and its change history
+  policies.json      The approval policies in effect at export time
+  verification.json  Audit chain and review signature check results
+  manifest.json      Period, chain head and a digest of every file
+
+Examples:
+  slb compliance export --period 2025-Q1
+  slb compliance export --period 2025-03 --output-file march.zip
+  slb compliance verify slb-compliance-2025-Q1.zip`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagCompliancePeriod == "" {
+			return fmt.Errorf("--period is required (e.g. 2025-Q1)")
+		}
+		since, until, err := core.ParseCompliancePeriod(flagCompliancePeriod)
+		if err != nil {
+			return err
+		}
+
+		project, err := projectPath()
+		if err != nil {
+			return err
+		}
+		cfg, err := config.Load(config.LoadOptions{ProjectDir: project, ConfigPath: flagConfig})
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		if flagComplianceAllProjects {
+			project = ""
+		}
+
+		if _, err := loadCustomPatternsIntoDefaultEngine(); err != nil {
+			return err
+		}
+
+		dbConn, err := db.Open(GetDB())
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer dbConn.Close()
+
+		var bundle bytes.Buffer
+		manifest, verification, err := core.ExportCompliance(&bundle, dbConn, core.ComplianceOptions{
+			Period:      flagCompliancePeriod,
+			Since:       since,
+			Until:       until,
+			ProjectPath: project,
+			Policies:    compliancePolicies(cfg),
+			Patterns:    core.GetDefaultEngine().Export(),
+		})
+		if err != nil {
+			return fmt.Errorf("building bundle: %w", err)
+		}
+
+		path := flagComplianceOutputFile
+		if path == "" {
+			path = fmt.Sprintf("slb-compliance-%s.zip", flagCompliancePeriod)
+		}
+		if err := os.WriteFile(path, bundle.Bytes(), 0o600); err != nil {
+			return fmt.Errorf("writing bundle: %w", err)
+		}
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+
+		return writeComplianceResult(path, manifest, verification)
+	},
+}
+
+var complianceVerifyCmd = &cobra.Command{
+	Use:   "verify <bundle.zip>",
+	Short: "Check a compliance bundle's digests and audit chain",
+	Long: `Check that no file in a compliance bundle was changed since export: every
+file digest must match the manifest and the audit log's hash chain must end at
+the manifest's chain head. Review signature results are those recorded at
+export time. Exits non-zero when a check fails.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("opening bundle: %w", err)
+		}
+		defer f.Close()
+		info, err := f.Stat()
+		if err != nil {
+			return fmt.Errorf("opening bundle: %w", err)
+		}
+
+		manifest, verification, err := core.VerifyComplianceBundle(f, info.Size())
+		if err != nil {
+			return err
+		}
+		if err := writeComplianceResult(args[0], manifest, verification); err != nil {
+			return err
+		}
+		if !verification.OK() {
+			return fmt.Errorf("compliance bundle failed verification")
+		}
+		return nil
+	},
+}
+
+func writeComplianceResult(path string, manifest *core.ComplianceManifest, verification *core.ComplianceVerification) error {
+	if GetOutput() != "text" {
+		return output.New(output.Format(GetOutput())).Write(map[string]any{
+			"bundle":       path,
+			"manifest":     manifest,
+			"verification": verification,
+		})
+	}
+
+	fmt.Printf("Bundle:     %s\n", path)
+	fmt.Printf("Period:     %s (%s to %s)\n", manifest.Period,
+		manifest.Since.Format("2006-01-02"), manifest.Until.Format("2006-01-02"))
+	fmt.Printf("Records:    %d\n", manifest.Records)
+	chain := "valid"
+	if !verification.Chain.Valid {
+		chain = "BROKEN: " + verification.Chain.Error
+	}
+	fmt.Printf("Chain:      %s (head %s)\n", chain, shortHash(manifest.ChainHead))
+	sigs := verification.Signatures
+	fmt.Printf("Signatures: %d/%d valid", sigs.Valid, sigs.Checked)
+	if len(sigs.Invalid) > 0 {
+		fmt.Printf(", %d INVALID", len(sigs.Invalid))
+	}
+	if len(sigs.Unverifiable) > 0 {
+		fmt.Printf(", %d unverifiable (session gone)", len(sigs.Unverifiable))
+	}
+	fmt.Println()
+	for _, name := range verification.FileMismatches {
+		fmt.Printf("MODIFIED:   %s\n", name)
+	}
+	return nil
+}
+
+// compliancePolicy is the approval policy recorded in a compliance bundle.
+// Notification endpoints and integration settings are left out.
+type compliancePolicy struct {
+	General          config.GeneralConfig            `json:"general"`
+	Tiers            map[string]compliancePolicyTier `json:"tiers"`
+	PatternPacks     []string                        `json:"pattern_packs,omitempty"`
+	RateLimits       config.RateLimitConfig          `json:"rate_limits"`
+	Agents           config.AgentsConfig             `json:"agents"`
+	ExecutionWindows config.ExecutionWindowsConfig   `json:"execution_windows"`
+}
+
+type compliancePolicyTier struct {
+	MinApprovals            int  `json:"min_approvals"`
+	DynamicQuorum           bool `json:"dynamic_quorum"`
+	DynamicQuorumFloor      int  `json:"dynamic_quorum_floor,omitempty"`
+	AutoApprove             bool `json:"auto_approve"`
+	AutoApproveDelaySeconds int  `json:"auto_approve_delay_seconds,omitempty"`
+	AutoApproveMaxScore     int  `json:"auto_approve_max_score,omitempty"`
+}
+
+func compliancePolicies(cfg config.Config) compliancePolicy {
+	tier := func(t config.PatternTierConfig) compliancePolicyTier {
+		return compliancePolicyTier{
+			MinApprovals:            t.MinApprovals,
+			DynamicQuorum:           t.DynamicQuorum,
+			DynamicQuorumFloor:      t.DynamicQuorumFloor,
+			AutoApprove:             t.AutoApprove,
+			AutoApproveDelaySeconds: t.AutoApproveDelaySeconds,
+			AutoApproveMaxScore:     t.AutoApproveMaxScore,
+		}
+	}
+	return compliancePolicy{
+		General: cfg.General,
+		Tiers: map[string]compliancePolicyTier{
+			"critical":  tier(cfg.Patterns.Critical),
+			"dangerous": tier(cfg.Patterns.Dangerous),
+			"caution":   tier(cfg.Patterns.Caution),
+			"safe":      tier(cfg.Patterns.Safe),
+		},
+		PatternPacks:     cfg.Patterns.Packs,
+		RateLimits:       cfg.RateLimits,
+		Agents:           cfg.Agents,
+		ExecutionWindows: cfg.ExecutionWindows,
+	}
+}
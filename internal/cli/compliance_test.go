@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+	"github.com/spf13/cobra"
+)
+
+func newTestComplianceCmd(dbPath string) *cobra.Command {
+	root := &cobra.Command{
+		Use:           "slb",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	root.PersistentFlags().StringVar(&flagDB, "db", dbPath, "database path")
+	root.PersistentFlags().StringVarP(&flagOutput, "output", "o", "text", "output format")
+	root.PersistentFlags().BoolVarP(&flagJSON, "json", "j", false, "json output")
+	root.PersistentFlags().StringVarP(&flagProject, "project", "C", "", "project directory")
+	root.PersistentFlags().StringVarP(&flagConfig, "config", "c", "", "config file")
+
+	compCmd := &cobra.Command{Use: "compliance"}
+	exportCmd := &cobra.Command{
+		Use:  "export",
+		Args: cobra.NoArgs,
+		RunE: complianceExportCmd.RunE,
+	}
+	exportCmd.Flags().StringVar(&flagCompliancePeriod, "period", "", "")
+	exportCmd.Flags().StringVar(&flagComplianceOutputFile, "output-file", "", "")
+	exportCmd.Flags().BoolVar(&flagComplianceAllProjects, "all-projects", false, "")
+	verifyCmd := &cobra.Command{
+		Use:  "verify <bundle.zip>",
+		Args: cobra.ExactArgs(1),
+		RunE: complianceVerifyCmd.RunE,
+	}
+	compCmd.AddCommand(exportCmd, verifyCmd)
+	root.AddCommand(compCmd)
+	return root
+}
+
+func resetComplianceFlags() {
+	flagOutput = "text"
+	flagJSON = false
+	flagProject = ""
+	flagConfig = ""
+	flagCompliancePeriod = ""
+	flagComplianceOutputFile = ""
+	flagComplianceAllProjects = false
+}
+
+func TestComplianceExportAndVerify(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetComplianceFlags()
+	t.Cleanup(resetComplianceFlags)
+
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	testutil.MakeRequest(t, h.DB, sess, testutil.WithCommand("terraform destroy", h.ProjectDir, true))
+
+	period := time.Now().UTC().Format("2006-01")
+	bundle := filepath.Join(t.TempDir(), "evidence.zip")
+	stdout, err := executeCommandCapture(t, newTestComplianceCmd(h.DBPath),
+		"compliance", "export", "-C", h.ProjectDir, "--period", period, "--output-file", bundle, "-j")
+	testutil.RequireNoError(t, err, "compliance export")
+
+	var result struct {
+		Manifest struct {
+			Records int `json:"records"`
+		} `json:"manifest"`
+		Verification struct {
+			Chain struct {
+				Valid bool `json:"valid"`
+			} `json:"audit_chain"`
+		} `json:"verification"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	testutil.RequireEqual(t, 1, result.Manifest.Records, "records")
+	if !result.Verification.Chain.Valid {
+		t.Fatal("expected a valid chain")
+	}
+
+	resetComplianceFlags()
+	stdout, err = executeCommandCapture(t, newTestComplianceCmd(h.DBPath), "compliance", "verify", bundle)
+	testutil.RequireNoError(t, err, "compliance verify")
+	if !strings.Contains(stdout, "Chain:      valid") {
+		t.Fatalf("unexpected verify output:\n%s", stdout)
+	}
+
+	// A bundle that is not a zip is an error.
+	bogus := filepath.Join(t.TempDir(), "bogus.zip")
+	if err := os.WriteFile(bogus, []byte("not a zip"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	resetComplianceFlags()
+	if _, err := executeCommandCapture(t, newTestComplianceCmd(h.DBPath), "compliance", "verify", bogus); err == nil {
+		t.Fatal("expected an error for a non-zip bundle")
+	}
+}
+
+func TestComplianceExport_RequiresValidPeriod(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetComplianceFlags()
+	t.Cleanup(resetComplianceFlags)
+
+	_, err := executeCommandCapture(t, newTestComplianceCmd(h.DBPath), "compliance", "export", "-C", h.ProjectDir)
+	if err == nil || !strings.Contains(err.Error(), "--period is required") {
+		t.Fatalf("expected a missing --period error, got %v", err)
+	}
+
+	resetComplianceFlags()
+	_, err = executeCommandCapture(t, newTestComplianceCmd(h.DBPath), "compliance", "export", "-C", h.ProjectDir, "--period", "2025-Q9")
+	if err == nil || !strings.Contains(err.Error(), "invalid period") {
+		t.Fatalf("expected an invalid period error, got %v", err)
+	}
+}
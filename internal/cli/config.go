@@ -77,18 +77,18 @@ var configGetCmd = &cobra.Command{
 
 var configSetCmd = &cobra.Command{
 	Use:   "set <key> <value>",
-	Short: "Set a configuration value in the project (or --global) config file",
-	Args:  cobra.ExactArgs(2),
+	Short: "Set a configuration value in the user (--global) config file",
+	Long: `Set a configuration value in the user config (~/.slb/config.toml).
+
+The project config (.slb/config.toml) is not written directly: it decides
+who needs approval for what, so it changes only through an approved request.
+Edit a copy and run 'slb config apply <file>' instead.`,
+	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		project, err := projectPath()
+		target, err := directConfigTarget()
 		if err != nil {
 			return err
 		}
-		userPath, projectPath := config.ConfigPaths(project, flagConfig)
-		target := projectPath
-		if flagConfigGlobal {
-			target = userPath
-		}
 
 		value, err := config.ParseValue(args[0], args[1])
 		if err != nil {
@@ -109,17 +109,17 @@ var configSetCmd = &cobra.Command{
 
 var configEditCmd = &cobra.Command{
 	Use:   "edit",
-	Short: "Open the config file in $EDITOR (default: vi)",
+	Short: "Open the user (--global) config file in $EDITOR (default: vi)",
+	Long: `Open the user config (~/.slb/config.toml) in $EDITOR (default: vi).
+
+The project config (.slb/config.toml) is not edited in place: copy it, edit
+the copy and run 'slb config apply <file>' to route the change through an
+approved request.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		project, err := projectPath()
+		target, err := directConfigTarget()
 		if err != nil {
 			return err
 		}
-		userPath, projectPath := config.ConfigPaths(project, flagConfig)
-		target := projectPath
-		if flagConfigGlobal {
-			target = userPath
-		}
 
 		// Ensure the file exists with at least defaults for convenience.
 		if _, err := os.Stat(target); errors.Is(err, os.ErrNotExist) {
@@ -141,3 +141,18 @@ var configEditCmd = &cobra.Command{
 		return editCmd.Run()
 	},
 }
+
+// directConfigTarget returns the config file that set and edit may write.
+// Only the user config qualifies: project config changes go through
+// 'slb config apply' so they need the same quorum as a critical command.
+func directConfigTarget() (string, error) {
+	project, err := projectPath()
+	if err != nil {
+		return "", err
+	}
+	userPath, projectPath := config.ConfigPaths(project, flagConfig)
+	if !flagConfigGlobal {
+		return "", fmt.Errorf("%s is changed only through an approved request: edit a copy and run 'slb config apply <file>' (use --global for the user config)", projectPath)
+	}
+	return userPath, nil
+}
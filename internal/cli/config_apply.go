@@ -0,0 +1,343 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagConfigApplyReason  string
+	flagConfigHistoryLimit int
+)
+
+func init() {
+	configApplyCmd.Flags().StringVarP(&flagConfigApplyReason, "reason", "r", "", "why the config should change")
+	configHistoryCmd.Flags().IntVar(&flagConfigHistoryLimit, "limit", 20, "maximum number of changes to show (0 for all)")
+
+	configCmd.AddCommand(configApplyCmd)
+	configCmd.AddCommand(configActivateCmd)
+	configCmd.AddCommand(configHistoryCmd)
+}
+
+var configApplyCmd = &cobra.Command{
+	Use:   "apply <staged-file>",
+	Short: "Request approval to replace the project config with a staged file",
+	Long: `Stage a new project config and route it through SLB like any other
+critical command.
+
+The staged file is validated and diffed setting by setting against the
+current .slb/config.toml, then a CRITICAL request is created for
+'slb config activate <change-id>' with the diff attached. Nothing is written
+until that request is approved and run with 'slb execute', so loosening the
+policy needs the same quorum as the commands it governs. The staged content is
+stored with the request: editing the staged file afterwards has no effect.
+
+Use --session-id/-s to specify your session if not using environment.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		project, err := projectPath()
+		if err != nil {
+			return err
+		}
+		_, target := config.ConfigPaths(project, flagConfig)
+
+		staged, err := os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("reading staged config: %w", err)
+		}
+		current, err := readConfigFile(target)
+		if err != nil {
+			return err
+		}
+		baseHash := ""
+		if current != nil {
+			baseHash = config.Hash(current)
+		}
+		configHash := config.Hash(staged)
+		if configHash == baseHash {
+			return fmt.Errorf("%s is identical to %s; nothing to apply", args[0], target)
+		}
+
+		if _, err := config.Load(config.LoadOptions{ProjectDir: project, ConfigPath: args[0]}); err != nil {
+			return fmt.Errorf("validating staged config: %w", err)
+		}
+		entries, err := config.Diff(current, staged)
+		if err != nil {
+			return err
+		}
+		diff := config.FormatDiff(entries)
+		if diff == "" {
+			diff = "(formatting only; no settings change)\n"
+		}
+
+		cfg, err := config.Load(config.LoadOptions{ProjectDir: project, ConfigPath: flagConfig})
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		dbConn, err := db.OpenAndMigrate(GetDB())
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer dbConn.Close()
+
+		sessionID, err := resolveReviewerSessionID(dbConn, project, flagSessionID)
+		if err != nil {
+			return err
+		}
+		if _, err := loadCustomPatternsIntoDefaultEngine(); err != nil {
+			return fmt.Errorf("loading custom patterns: %w", err)
+		}
+
+		reason := flagConfigApplyReason
+		if reason == "" {
+			reason = fmt.Sprintf("apply config change to %s", target)
+		}
+		changeID := uuid.New().String()
+		creator := core.NewRequestCreator(dbConn, core.NewRateLimiter(dbConn, toRateLimitConfig(cfg)), nil, toRequestCreatorConfig(cfg))
+		result, err := creator.CreateRequest(cmd.Context(), core.CreateRequestOptions{
+			SessionID: sessionID,
+			Command:   "slb config activate " + changeID,
+			Cwd:       project,
+			Environ:   os.Environ(),
+			Justification: core.Justification{
+				Reason:         reason,
+				ExpectedEffect: fmt.Sprintf("replaces %s with the staged config (%d setting(s) change)", target, len(entries)),
+			},
+			Attachments: []db.Attachment{*core.CreateDiffAttachment(diff, target)},
+			ProjectPath: project,
+			MinTier:     core.RiskTierCritical,
+		})
+		if err != nil {
+			return fmt.Errorf("creating request: %w", err)
+		}
+		request := result.Request
+
+		change := &db.ConfigChange{
+			ID:          changeID,
+			RequestID:   request.ID,
+			ProjectPath: project,
+			TargetPath:  target,
+			BaseHash:    baseHash,
+			ConfigHash:  configHash,
+			Content:     string(staged),
+			Diff:        diff,
+			CreatedBy:   request.RequestorAgent,
+		}
+		if err := dbConn.CreateConfigChange(change); err != nil {
+			return err
+		}
+
+		if isJSONOutput() {
+			return output.New(output.Format(GetOutput())).Write(map[string]any{
+				"change_id":     change.ID,
+				"request_id":    request.ID,
+				"status":        string(request.Status),
+				"tier":          string(request.RiskTier),
+				"min_approvals": request.MinApprovals,
+				"target":        target,
+				"base_hash":     baseHash,
+				"config_hash":   configHash,
+				"diff":          entries,
+			})
+		}
+
+		output.Notef(os.Stdout, "Staged config change %s for %s\n", change.ID, target)
+		fmt.Print(diff)
+		output.Notef(os.Stdout, "Request %s created (%s, %d approval(s) required)\n", request.ID, request.RiskTier, request.MinApprovals)
+		output.Notef(os.Stdout, "Once approved, activate it with: slb execute %s\n", request.ID)
+		return nil
+	},
+}
+
+var configActivateCmd = &cobra.Command{
+	Use:   "activate <change-id>",
+	Short: "Write an approved config change (run by 'slb execute')",
+	Long: `Write a config change staged with 'slb config apply'.
+
+This is the command an approved config change request runs; it refuses to
+run on its own, so use 'slb execute <request-id>' after approval. The change
+is also refused if the config file was modified after it was staged.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dbConn, err := db.OpenAndMigrate(GetDB())
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer dbConn.Close()
+
+		change, err := dbConn.GetConfigChange(args[0])
+		if err != nil {
+			return err
+		}
+		if change.Status != db.ConfigChangeStaged {
+			return fmt.Errorf("config change %s was already %s", change.ID, change.Status)
+		}
+		request, err := dbConn.GetRequest(change.RequestID)
+		if err != nil {
+			return fmt.Errorf("getting request: %w", err)
+		}
+		if request.Status != db.StatusExecuting {
+			return fmt.Errorf("config change %s is not being executed (request %s is %s); apply it with slb execute %s after approval",
+				change.ID, request.ID, request.Status, request.ID)
+		}
+
+		current, err := readConfigFile(change.TargetPath)
+		if err != nil {
+			return err
+		}
+		currentHash := ""
+		if current != nil {
+			currentHash = config.Hash(current)
+		}
+		if currentHash != change.BaseHash {
+			return fmt.Errorf("%s changed since config change %s was staged; stage it again with slb config apply", change.TargetPath, change.ID)
+		}
+
+		if err := writeConfigFile(change.TargetPath, []byte(change.Content)); err != nil {
+			return err
+		}
+		appliedBy := GetActor()
+		if request.Execution != nil && request.Execution.ExecutedByAgent != "" {
+			appliedBy = request.Execution.ExecutedByAgent
+		}
+		applied, err := dbConn.MarkConfigChangeApplied(change.ID, appliedBy)
+		if err != nil {
+			return err
+		}
+		if !applied {
+			return fmt.Errorf("config change %s was applied concurrently", change.ID)
+		}
+
+		if isJSONOutput() {
+			return output.New(output.Format(GetOutput())).Write(map[string]any{
+				"change_id":   change.ID,
+				"request_id":  request.ID,
+				"target":      change.TargetPath,
+				"config_hash": change.ConfigHash,
+				"applied_by":  appliedBy,
+			})
+		}
+		output.Notef(os.Stdout, "Applied config change %s to %s (%s)\n", change.ID, change.TargetPath, configHashLabel(change.ConfigHash))
+		return nil
+	},
+}
+
+var configHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show the config changes applied with 'slb config apply'",
+	Long: `List the config changes applied through 'slb config apply', newest first,
+with the hash of each config and the request that approved it.
+
+A warning is shown if the current config file does not match the last
+applied change, i.e. it was edited outside 'slb config apply'.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		project, err := projectPath()
+		if err != nil {
+			return err
+		}
+		_, target := config.ConfigPaths(project, flagConfig)
+
+		dbConn, err := db.OpenAndMigrate(GetDB())
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer dbConn.Close()
+
+		history, err := dbConn.ListConfigHistory(project, flagConfigHistoryLimit)
+		if err != nil {
+			return err
+		}
+		current, err := readConfigFile(target)
+		if err != nil {
+			return err
+		}
+		currentHash := ""
+		if current != nil {
+			currentHash = config.Hash(current)
+		}
+		modified := len(history) > 0 && history[0].ConfigHash != currentHash
+
+		if isJSONOutput() {
+			if history == nil {
+				history = []*db.ConfigChange{}
+			}
+			return output.New(output.Format(GetOutput())).Write(map[string]any{
+				"target":       target,
+				"current_hash": currentHash,
+				"modified":     modified,
+				"changes":      history,
+			})
+		}
+
+		if len(history) == 0 {
+			fmt.Printf("No config changes have been applied to %s with slb config apply\n", target)
+			return nil
+		}
+		fmt.Printf("Config history for %s\n", target)
+		for _, c := range history {
+			fmt.Printf("%s  %s  request %s  by %s\n", c.AppliedAt.Local().Format(time.DateTime), configHashLabel(c.ConfigHash), c.RequestID, c.AppliedBy)
+		}
+		if modified {
+			fmt.Printf("Warning: %s (%s) was modified outside slb config apply\n", target, configHashLabel(currentHash))
+		}
+		return nil
+	},
+}
+
+// readConfigFile returns a config file's content, or nil if it does not
+// exist yet.
+func readConfigFile(path string) ([]byte, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return content, nil
+}
+
+// writeConfigFile replaces a config file via a temp file and rename, so a
+// failed write never leaves a half-written config behind.
+func writeConfigFile(path string, content []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("mkdir %s: %w", dir, err)
+	}
+	tmp, err := os.CreateTemp(dir, ".config-*.toml")
+	if err != nil {
+		return fmt.Errorf("creating temp config: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing temp config: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing temp config: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("replacing %s: %w", path, err)
+	}
+	return nil
+}
+
+// configHashLabel abbreviates a config hash for display; an empty hash
+// means the file does not exist.
+func configHashLabel(hash string) string {
+	if hash == "" {
+		return "none"
+	}
+	return shortHash(hash)
+}
@@ -0,0 +1,255 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+	"github.com/spf13/cobra"
+)
+
+// newTestConfigApplyCmd creates a fresh config apply/activate/history
+// command tree for testing.
+func newTestConfigApplyCmd(dbPath string) *cobra.Command {
+	root := &cobra.Command{
+		Use:           "slb",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	root.PersistentFlags().StringVar(&flagDB, "db", dbPath, "database path")
+	root.PersistentFlags().StringVarP(&flagOutput, "output", "o", "text", "output format")
+	root.PersistentFlags().BoolVarP(&flagJSON, "json", "j", false, "json output")
+	root.PersistentFlags().StringVarP(&flagProject, "project", "C", "", "project directory")
+	root.PersistentFlags().StringVarP(&flagConfig, "config", "c", "", "config file")
+	root.PersistentFlags().StringVarP(&flagSessionID, "session-id", "s", "", "session ID")
+
+	cfgCmd := &cobra.Command{Use: "config"}
+	applyCmd := &cobra.Command{
+		Use:  "apply <staged-file>",
+		Args: configApplyCmd.Args,
+		RunE: configApplyCmd.RunE,
+	}
+	applyCmd.Flags().StringVarP(&flagConfigApplyReason, "reason", "r", "", "reason")
+	activateCmd := &cobra.Command{
+		Use:  "activate <change-id>",
+		Args: configActivateCmd.Args,
+		RunE: configActivateCmd.RunE,
+	}
+	historyCmd := &cobra.Command{
+		Use:  "history",
+		Args: configHistoryCmd.Args,
+		RunE: configHistoryCmd.RunE,
+	}
+	historyCmd.Flags().IntVar(&flagConfigHistoryLimit, "limit", 20, "limit")
+
+	cfgCmd.AddCommand(applyCmd, activateCmd, historyCmd)
+	root.AddCommand(cfgCmd)
+	return root
+}
+
+func resetConfigApplyFlags() {
+	flagDB = ""
+	flagOutput = "text"
+	flagJSON = false
+	flagProject = ""
+	flagConfig = ""
+	flagSessionID = ""
+	flagConfigApplyReason = ""
+	flagConfigHistoryLimit = 20
+}
+
+type configApplyResponse struct {
+	ChangeID     string `json:"change_id"`
+	RequestID    string `json:"request_id"`
+	Tier         string `json:"tier"`
+	MinApprovals int    `json:"min_approvals"`
+	BaseHash     string `json:"base_hash"`
+	ConfigHash   string `json:"config_hash"`
+	Diff         []struct {
+		Key string `json:"key"`
+		Old string `json:"old"`
+		New string `json:"new"`
+	} `json:"diff"`
+}
+
+func stageConfigChange(t *testing.T, h *testutil.Harness, sessionID, content string) configApplyResponse {
+	t.Helper()
+	resetConfigApplyFlags()
+	staged := filepath.Join(t.TempDir(), "staged.toml")
+	if err := os.WriteFile(staged, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	stdout, err := executeCommandCapture(t, newTestConfigApplyCmd(h.DBPath), "config", "apply", staged,
+		"-C", h.ProjectDir, "-s", sessionID, "-r", "require three approvals", "-j")
+	testutil.RequireNoError(t, err, "config apply")
+
+	var resp configApplyResponse
+	if err := json.Unmarshal([]byte(stdout), &resp); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	return resp
+}
+
+// startExecuting moves a request to executing the way 'slb execute' does.
+func startExecuting(t *testing.T, h *testutil.Harness, requestID string) {
+	t.Helper()
+	if err := h.DB.UpdateRequestStatus(requestID, db.StatusApproved); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.DB.UpdateRequestStatus(requestID, db.StatusExecuting); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().UTC()
+	if err := h.DB.UpdateRequestExecution(requestID, &db.Execution{ExecutedAt: &now, ExecutedByAgent: "Executor"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConfigApply_ActivatesAfterApproval(t *testing.T) {
+	h := testutil.NewHarness(t)
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir), testutil.WithAgent("Requestor"))
+	target := h.WriteFile(".slb/config.toml", []byte("[general]\nmin_approvals = 2\n"), 0o600)
+
+	resp := stageConfigChange(t, h, sess.ID, "[general]\nmin_approvals = 3\n")
+	if resp.ChangeID == "" || resp.RequestID == "" || resp.Tier != "critical" || resp.BaseHash == "" || resp.ConfigHash == resp.BaseHash {
+		t.Fatalf("unexpected apply response: %+v", resp)
+	}
+	if len(resp.Diff) != 1 || resp.Diff[0].Key != "general.min_approvals" || resp.Diff[0].Old != "2" || resp.Diff[0].New != "3" {
+		t.Errorf("diff = %+v", resp.Diff)
+	}
+
+	req, err := h.DB.GetRequest(resp.RequestID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Command.Raw != "slb config activate "+resp.ChangeID || req.Status != db.StatusPending || len(req.Attachments) != 1 {
+		t.Errorf("request = %+v", req)
+	}
+	if !strings.Contains(req.Attachments[0].Content, "~ general.min_approvals = 2 -> 3") {
+		t.Errorf("attachment = %q", req.Attachments[0].Content)
+	}
+
+	// Nothing is written before the request executes.
+	resetConfigApplyFlags()
+	_, err = executeCommandCapture(t, newTestConfigApplyCmd(h.DBPath), "config", "activate", resp.ChangeID, "-C", h.ProjectDir)
+	if err == nil || !strings.Contains(err.Error(), "slb execute "+resp.RequestID) {
+		t.Fatalf("expected activation to require execution, got %v", err)
+	}
+	if content, _ := os.ReadFile(target); string(content) != "[general]\nmin_approvals = 2\n" {
+		t.Fatalf("config written before approval: %q", content)
+	}
+
+	startExecuting(t, h, resp.RequestID)
+	resetConfigApplyFlags()
+	stdout, err := executeCommandCapture(t, newTestConfigApplyCmd(h.DBPath), "config", "activate", resp.ChangeID, "-C", h.ProjectDir)
+	testutil.RequireNoError(t, err, "config activate")
+	if !strings.Contains(stdout, "Applied config change "+resp.ChangeID) {
+		t.Errorf("activate output = %q", stdout)
+	}
+	if content, _ := os.ReadFile(target); string(content) != "[general]\nmin_approvals = 3\n" {
+		t.Fatalf("config after activation = %q", content)
+	}
+
+	resetConfigApplyFlags()
+	_, err = executeCommandCapture(t, newTestConfigApplyCmd(h.DBPath), "config", "activate", resp.ChangeID, "-C", h.ProjectDir)
+	if err == nil || !strings.Contains(err.Error(), "already applied") {
+		t.Errorf("expected a second activation to fail, got %v", err)
+	}
+
+	resetConfigApplyFlags()
+	stdout, err = executeCommandCapture(t, newTestConfigApplyCmd(h.DBPath), "config", "history", "-C", h.ProjectDir, "-j")
+	testutil.RequireNoError(t, err, "config history")
+	var history struct {
+		CurrentHash string `json:"current_hash"`
+		Modified    bool   `json:"modified"`
+		Changes     []struct {
+			ID         string `json:"id"`
+			RequestID  string `json:"request_id"`
+			BaseHash   string `json:"base_hash"`
+			ConfigHash string `json:"config_hash"`
+			AppliedBy  string `json:"applied_by"`
+			CreatedBy  string `json:"created_by"`
+		} `json:"changes"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &history); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if history.Modified || history.CurrentHash != resp.ConfigHash || len(history.Changes) != 1 {
+		t.Fatalf("history = %s", stdout)
+	}
+	if c := history.Changes[0]; c.ID != resp.ChangeID || c.RequestID != resp.RequestID || c.BaseHash != resp.BaseHash || c.AppliedBy != "Executor" || c.CreatedBy != "Requestor" {
+		t.Errorf("history entry = %+v", c)
+	}
+
+	// Editing the file by hand shows up as drift.
+	if err := os.WriteFile(target, []byte("[general]\nmin_approvals = 1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	resetConfigApplyFlags()
+	stdout, err = executeCommandCapture(t, newTestConfigApplyCmd(h.DBPath), "config", "history", "-C", h.ProjectDir)
+	testutil.RequireNoError(t, err, "config history")
+	if !strings.Contains(stdout, "request "+resp.RequestID+"  by Executor") || !strings.Contains(stdout, "was modified outside slb config apply") {
+		t.Errorf("history output = %q", stdout)
+	}
+}
+
+func TestConfigApply_RefusesStaleBase(t *testing.T) {
+	h := testutil.NewHarness(t)
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir), testutil.WithAgent("Requestor"))
+
+	// Staging against a missing config records an empty base hash.
+	resp := stageConfigChange(t, h, sess.ID, "[general]\nmin_approvals = 3\n")
+	if resp.BaseHash != "" || len(resp.Diff) != 1 || resp.Diff[0].Old != "" {
+		t.Fatalf("unexpected apply response: %+v", resp)
+	}
+
+	target := h.WriteFile(".slb/config.toml", []byte("[general]\nmin_approvals = 1\n"), 0o600)
+	startExecuting(t, h, resp.RequestID)
+	resetConfigApplyFlags()
+	_, err := executeCommandCapture(t, newTestConfigApplyCmd(h.DBPath), "config", "activate", resp.ChangeID, "-C", h.ProjectDir)
+	if err == nil || !strings.Contains(err.Error(), "changed since config change") {
+		t.Fatalf("expected a stale base to be refused, got %v", err)
+	}
+	if content, _ := os.ReadFile(target); string(content) != "[general]\nmin_approvals = 1\n" {
+		t.Errorf("config overwritten: %q", content)
+	}
+}
+
+func TestConfigApply_RejectsInvalidStagedFiles(t *testing.T) {
+	h := testutil.NewHarness(t)
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir), testutil.WithAgent("Requestor"))
+	h.WriteFile(".slb/config.toml", []byte("[general]\nmin_approvals = 2\n"), 0o600)
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"unchanged", "[general]\nmin_approvals = 2\n", "nothing to apply"},
+		{"invalid toml", "[general\n", "validating staged config"},
+		{"invalid value", "[general]\nmin_approvals = 0\n", "validating staged config"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetConfigApplyFlags()
+			staged := filepath.Join(t.TempDir(), "staged.toml")
+			if err := os.WriteFile(staged, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := executeCommandCapture(t, newTestConfigApplyCmd(h.DBPath), "config", "apply", staged, "-C", h.ProjectDir, "-s", sess.ID)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if history, err := h.DB.ListConfigHistory(h.ProjectDir, 0); err != nil || len(history) != 0 {
+		t.Errorf("history = %+v, %v", history, err)
+	}
+}
@@ -2,6 +2,8 @@ package cli
 
 import (
 	"encoding/json"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -161,9 +163,11 @@ func TestConfigSetCommand_RequiresKeyAndValue(t *testing.T) {
 func TestConfigSetCommand_SetsValue(t *testing.T) {
 	h := testutil.NewHarness(t)
 	resetConfigFlags()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
 
 	cmd := newTestConfigCmd(h.DBPath)
-	stdout, err := executeCommandCapture(t, cmd, "config", "set", "general.min_approvals", "3", "-C", h.ProjectDir, "-j")
+	stdout, err := executeCommandCapture(t, cmd, "config", "set", "general.min_approvals", "3", "--global", "-C", h.ProjectDir, "-j")
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -181,6 +185,30 @@ func TestConfigSetCommand_SetsValue(t *testing.T) {
 	if result["value"] == nil {
 		t.Error("expected value to be set")
 	}
+	if path, _ := result["path"].(string); path != filepath.Join(home, ".slb", "config.toml") {
+		t.Errorf("expected the user config to be written, got %v", result["path"])
+	}
+}
+
+func TestConfigSetCommand_RefusesProjectConfig(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetConfigFlags()
+
+	projectConfig := filepath.Join(h.ProjectDir, ".slb", "config.toml")
+	before, _ := os.ReadFile(projectConfig)
+
+	cmd := newTestConfigCmd(h.DBPath)
+	_, err := executeCommandCapture(t, cmd, "config", "set", "general.min_approvals", "1", "-C", h.ProjectDir, "-j")
+	if err == nil {
+		t.Fatal("expected config set on the project config to be refused")
+	}
+	if !strings.Contains(err.Error(), "slb config apply") {
+		t.Errorf("expected error to point to slb config apply, got: %v", err)
+	}
+	after, _ := os.ReadFile(projectConfig)
+	if string(after) != string(before) {
+		t.Errorf("project config was modified:\n%s", after)
+	}
 }
 
 func TestConfigCommand_Help(t *testing.T) {
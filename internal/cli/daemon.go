@@ -8,11 +8,15 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/Dicklesworthstone/slb/internal/daemon"
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
+	"github.com/Dicklesworthstone/slb/internal/utils"
+	"github.com/charmbracelet/log"
 	"github.com/spf13/cobra"
 )
 
@@ -21,21 +25,42 @@ var (
 	flagDaemonStopTimeoutSecs int
 	flagDaemonLogsFollow      bool
 	flagDaemonLogsLines       int
+	flagDaemonRunForeground   bool
+	flagDaemonRunLogLevel     string
+	flagDaemonStartChaos      string
+	flagDaemonRunChaos        string
+
+	flagDaemonHealthTimeoutSecs  int
+	flagDaemonHealthWatchdog     bool
+	flagDaemonHealthFailures     int
+	flagDaemonHealthIntervalSecs int
 )
 
 func init() {
 	daemonCmd.AddCommand(daemonStartCmd)
+	daemonCmd.AddCommand(daemonRunCmd)
 	daemonCmd.AddCommand(daemonStopCmd)
 	daemonCmd.AddCommand(daemonStatusCmd)
 	daemonCmd.AddCommand(daemonLogsCmd)
+	daemonCmd.AddCommand(daemonHealthCmd)
 
 	daemonStartCmd.Flags().BoolVar(&flagDaemonStartForeground, "foreground", false, "run the daemon in the current process (do not fork)")
+	addDaemonChaosFlag(daemonStartCmd, &flagDaemonStartChaos)
+
+	daemonRunCmd.Flags().BoolVar(&flagDaemonRunForeground, "foreground", false, "log to stderr and read admin commands from stdin")
+	daemonRunCmd.Flags().StringVar(&flagDaemonRunLogLevel, "log-level", "", "minimum log level with --foreground: debug, info, warn, error (default $SLB_LOG_LEVEL or info)")
+	addDaemonChaosFlag(daemonRunCmd, &flagDaemonRunChaos)
 
 	daemonStopCmd.Flags().IntVar(&flagDaemonStopTimeoutSecs, "timeout", 10, "seconds to wait for graceful shutdown")
 
 	daemonLogsCmd.Flags().BoolVarP(&flagDaemonLogsFollow, "follow", "f", false, "follow the log output (tail -f)")
 	daemonLogsCmd.Flags().IntVarP(&flagDaemonLogsLines, "lines", "n", 200, "number of lines to show")
 
+	daemonHealthCmd.Flags().IntVar(&flagDaemonHealthTimeoutSecs, "timeout", 5, "seconds each probe may take")
+	daemonHealthCmd.Flags().BoolVar(&flagDaemonHealthWatchdog, "watchdog", false, "restart the daemon if it stays unhealthy")
+	daemonHealthCmd.Flags().IntVar(&flagDaemonHealthFailures, "failures", 3, "consecutive failed checks before a watchdog restart")
+	daemonHealthCmd.Flags().IntVar(&flagDaemonHealthIntervalSecs, "interval", 5, "seconds between watchdog checks")
+
 	rootCmd.AddCommand(daemonCmd)
 }
 
@@ -48,6 +73,10 @@ var daemonStartCmd = &cobra.Command{
 	Use:   "start",
 	Short: "Start the daemon",
 	RunE: func(cmd *cobra.Command, args []string) error {
+		chaos, err := daemonChaos(cmd, flagDaemonStartChaos)
+		if err != nil {
+			return err
+		}
 		project, err := daemonProjectPath()
 		if err != nil {
 			return err
@@ -56,35 +85,125 @@ var daemonStartCmd = &cobra.Command{
 			return fmt.Errorf("chdir to project: %w", err)
 		}
 
-		startedAt := time.Now().UTC().Format(time.RFC3339)
-		socketPath := daemon.DefaultSocketPath()
+		startedAt := timefmt.Format(time.Now())
+		opts := daemon.DefaultServerOptions()
+		opts.Chaos = chaos
 
 		if flagDaemonStartForeground {
 			out := output.New(output.Format(GetOutput()))
-			_ = out.Write(map[string]any{
+			result := map[string]any{
 				"pid":         os.Getpid(),
-				"socket_path": socketPath,
+				"socket_path": opts.SocketPath,
 				"started_at":  startedAt,
 				"foreground":  true,
-			})
-			return daemon.RunDaemon(context.Background(), daemon.DefaultServerOptions())
+			}
+			if chaos != nil {
+				result["chaos"] = chaos.String()
+			}
+			_ = out.Write(result)
+			return daemon.RunDaemon(context.Background(), opts)
 		}
 
-		if err := daemon.StartDaemon(); err != nil {
+		if err := daemon.StartDaemonWithOptions(context.Background(), opts); err != nil {
 			return err
 		}
 
 		info := daemon.NewClient().GetStatusInfo()
 		out := output.New(output.Format(GetOutput()))
-		return out.Write(map[string]any{
+		result := map[string]any{
 			"pid":         info.PID,
 			"socket_path": info.SocketPath,
 			"started_at":  startedAt,
 			"foreground":  false,
-		})
+		}
+		if chaos != nil {
+			result["chaos"] = chaos.String()
+		}
+		return out.Write(result)
 	},
 }
 
+var daemonRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Run the daemon in this process",
+	Long: `Run the daemon in the current process until it is interrupted, as a
+service manager would.
+
+With --foreground the daemon logs to stderr instead of ~/.slb/daemon.log and
+reads admin commands from stdin, for debugging a stuck approval pipeline:
+
+  clients              list connected clients and their subscriptions
+  drop <subscription>  end an event subscription (the client reconnects)
+  reload               merge newly added custom patterns, drop the cache
+  cache                show read model stats, pending requests and sessions
+  quit                 stop the daemon`,
+	Example: `  slb daemon run --foreground
+  slb daemon run --foreground --log-level debug
+  SLB_ALLOW_CHAOS=1 slb daemon run --foreground --chaos=drop=0.2,slow-db=500ms`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		chaos, err := daemonChaos(cmd, flagDaemonRunChaos)
+		if err != nil {
+			return err
+		}
+		project, err := daemonProjectPath()
+		if err != nil {
+			return err
+		}
+		if err := os.Chdir(project); err != nil {
+			return fmt.Errorf("chdir to project: %w", err)
+		}
+		if daemon.NewClient().IsDaemonRunning() {
+			return fmt.Errorf("a daemon is already serving %s; stop it with 'slb daemon stop'", project)
+		}
+
+		opts := daemon.DefaultServerOptions()
+		opts.ProjectPath = project
+		opts.Chaos = chaos
+		if flagDaemonRunForeground {
+			opts.Logger = foregroundDaemonLogger(cmd.ErrOrStderr(), flagDaemonRunLogLevel)
+			opts.AdminIn = cmd.InOrStdin()
+			opts.AdminOut = cmd.OutOrStdout()
+			output.Notef(cmd.OutOrStdout(), "slb daemon (pid %d) serving %s on %s; type 'help' for admin commands\n",
+				os.Getpid(), project, opts.SocketPath)
+		}
+		return daemon.RunDaemon(context.Background(), opts)
+	},
+}
+
+// addDaemonChaosFlag adds --chaos, which injects failures so integrators
+// can check how their agents and hooks handle an unavailable daemon.
+func addDaemonChaosFlag(cmd *cobra.Command, spec *string) {
+	cmd.Flags().StringVar(spec, "chaos", "", "inject failures for integration testing, e.g. drop=0.1,reset=0.05,slow-db=200ms,seed=1 (requires "+daemon.ChaosEnv+"=1)")
+	cmd.Flags().Lookup("chaos").NoOptDefVal = daemon.DefaultChaosSpec
+}
+
+// daemonChaos parses --chaos when it was given, refusing it unless
+// SLB_ALLOW_CHAOS=1.
+func daemonChaos(cmd *cobra.Command, spec string) (*daemon.Chaos, error) {
+	if !cmd.Flags().Changed("chaos") {
+		return nil, nil
+	}
+	if !daemon.ChaosAllowed() {
+		return nil, fmt.Errorf("--chaos injects failures into the daemon; set %s=1 to allow it", daemon.ChaosEnv)
+	}
+	return daemon.ParseChaos(spec)
+}
+
+// foregroundDaemonLogger logs to w at level, else $SLB_LOG_LEVEL, else info.
+func foregroundDaemonLogger(w io.Writer, level string) *log.Logger {
+	opts := utils.DefaultLoggerOptions()
+	opts.Output = w
+	opts.Prefix = "daemon"
+	opts.TimeFormat = time.TimeOnly
+	if env := os.Getenv("SLB_LOG_LEVEL"); env != "" {
+		opts.Level = env
+	}
+	if level != "" {
+		opts.Level = level
+	}
+	return utils.InitLogger(opts)
+}
+
 var daemonStopCmd = &cobra.Command{
 	Use:   "stop",
 	Short: "Stop the daemon",
@@ -100,7 +219,7 @@ var daemonStopCmd = &cobra.Command{
 
 		out := output.New(output.Format(GetOutput()))
 		return out.Write(map[string]any{
-			"stopped_at": time.Now().UTC().Format(time.RFC3339),
+			"stopped_at": timefmt.Format(time.Now()),
 		})
 	},
 }
@@ -133,8 +252,7 @@ var daemonStatusCmd = &cobra.Command{
 
 		pendingCount, activeSessions := daemonProjectStats(project)
 
-		out := output.New(output.Format(GetOutput()))
-		return out.Write(map[string]any{
+		result := map[string]any{
 			"running":         info.Status == daemon.DaemonRunning,
 			"status":          info.Status.String(),
 			"pid":             info.PID,
@@ -146,7 +264,29 @@ var daemonStatusCmd = &cobra.Command{
 			"socket_path":     info.SocketPath,
 			"socket_alive":    info.SocketAlive,
 			"message":         info.Message,
-		})
+		}
+		if info.SocketAlive {
+			if status := daemonRPCStatus(info.SocketPath); status != nil {
+				if status.Cache != nil {
+					result["cache"] = status.Cache
+				}
+				if status.Writer != nil {
+					result["writer"] = status.Writer
+				}
+				if status.Chaos != nil {
+					result["chaos"] = status.Chaos
+				}
+				if status.Quotas != nil {
+					result["quotas"] = status.Quotas
+				}
+				if status.Broadcast != nil {
+					result["broadcast"] = status.Broadcast
+				}
+			}
+		}
+
+		out := output.New(output.Format(GetOutput()))
+		return out.Write(result)
 	},
 }
 
@@ -188,6 +328,98 @@ var daemonLogsCmd = &cobra.Command{
 	},
 }
 
+var daemonHealthCmd = &cobra.Command{
+	Use:   "health",
+	Short: "Probe the daemon end to end",
+	Long: `Probe the running daemon end to end and report each step's latency:
+
+  ping       the daemon answers RPCs
+  db         the daemon can write and read back its state database
+  classify   the daemon classifies a command
+  broadcast  an event published to the daemon reaches a subscriber
+
+The command fails when any probe fails. With --watchdog it is meant for
+cron or a systemd timer: the check is repeated up to --failures times,
+--interval seconds apart, and if every check fails the daemon is
+restarted (killed if it does not stop) and checked once more.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		project, err := daemonProjectPath()
+		if err != nil {
+			return err
+		}
+		if err := os.Chdir(project); err != nil {
+			return fmt.Errorf("chdir to project: %w", err)
+		}
+
+		timeout := time.Duration(flagDaemonHealthTimeoutSecs) * time.Second
+		socketPath := daemon.DefaultSocketPath()
+		check := func() *daemon.HealthReport {
+			return daemon.CheckHealth(cmd.Context(), socketPath, timeout)
+		}
+
+		report := check()
+		checks := 1
+		result := map[string]any{}
+		if flagDaemonHealthWatchdog {
+			interval := time.Duration(flagDaemonHealthIntervalSecs) * time.Second
+			for !report.Healthy && checks < flagDaemonHealthFailures {
+				time.Sleep(interval)
+				report = check()
+				checks++
+			}
+			result["restarted"] = false
+			if !report.Healthy {
+				result["restarted"] = true
+				result["failed_before_restart"] = report.Failed()
+				if err := restartDaemon(project, timeout); err != nil {
+					result["restart_error"] = err.Error()
+				} else {
+					waitForDaemon(cmd.Context(), socketPath, 10*time.Second)
+				}
+				report = check()
+				checks++
+			}
+		}
+
+		result["healthy"] = report.Healthy
+		result["socket_path"] = report.SocketPath
+		result["checked_at"] = timefmt.Format(report.CheckedAt)
+		result["probes"] = report.Probes
+		result["checks"] = checks
+
+		out := output.New(output.Format(GetOutput()))
+		if err := out.Write(result); err != nil {
+			return err
+		}
+		if !report.Healthy {
+			return withExitCode(exitDaemonUnreachable, fmt.Errorf("daemon unhealthy: %s failed", strings.Join(report.Failed(), ", ")))
+		}
+		return nil
+	},
+}
+
+// restartDaemon replaces the project's daemon; tests swap it out because
+// they cannot fork the CLI binary.
+var restartDaemon = func(project string, timeout time.Duration) error {
+	return daemon.RestartDaemon(daemon.DefaultServerOptions(), timeout, "daemon", "start", "-C", project)
+}
+
+// waitForDaemon polls until a daemon answers on socketPath or wait elapses.
+func waitForDaemon(ctx context.Context, socketPath string, wait time.Duration) {
+	deadline := time.Now().Add(wait)
+	for time.Now().Before(deadline) {
+		pingCtx, cancel := context.WithTimeout(ctx, 500*time.Millisecond)
+		client := daemon.NewIPCClient(socketPath)
+		err := client.Ping(pingCtx)
+		_ = client.Close()
+		cancel()
+		if err == nil {
+			return
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
+
 func daemonProjectPath() (string, error) {
 	if flagProject != "" {
 		return flagProject, nil
@@ -284,3 +516,18 @@ func followFile(path string, w io.Writer) error {
 		return fmt.Errorf("tail %s: %w", path, err)
 	}
 }
+
+// daemonRPCStatus fetches the status RPC (cache and writer counters) from a
+// running daemon, or nil if it does not answer.
+func daemonRPCStatus(socketPath string) *daemon.DaemonStatusInfo {
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	client := daemon.NewIPCClient(socketPath)
+	defer client.Close()
+	status, err := client.Status(ctx)
+	if err != nil {
+		return nil
+	}
+	return status
+}
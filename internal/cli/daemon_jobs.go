@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/numfmt"
+	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	daemonJobsCmd.AddCommand(daemonJobsListCmd)
+	daemonCmd.AddCommand(daemonJobsCmd)
+}
+
+var daemonJobsCmd = &cobra.Command{
+	Use:   "jobs",
+	Short: "Inspect the daemon's recurring jobs",
+}
+
+var daemonJobsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the daemon's recurring jobs and how they last ran",
+	Long: `List the daemon's recurring jobs: the expiry sweep, review reminders,
+retention pruning and the weekly digest. Each shows its schedule, whether
+it is enabled, when it last ran and how that went, and when it runs next.
+
+Jobs are enabled and scheduled under [jobs] in the config and registered
+when the daemon starts; restart the daemon after changing them. A failed
+job is retried after 30s, then after twice as long each time, until its
+next scheduled run.
+
+Examples:
+  slb daemon jobs list
+  slb daemon jobs list --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dbConn, err := db.OpenAndMigrate(GetDB())
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer dbConn.Close()
+
+		jobs, err := dbConn.ListDaemonJobs()
+		if err != nil {
+			return err
+		}
+
+		if isJSONOutput() {
+			if jobs == nil {
+				jobs = []*db.DaemonJob{}
+			}
+			return output.New(output.Format(GetOutput())).Write(map[string]any{"jobs": jobs})
+		}
+
+		if len(jobs) == 0 {
+			fmt.Println("No daemon jobs recorded yet; they are registered when the daemon starts.")
+			return nil
+		}
+		now := time.Now()
+		show := func(t *time.Time) string {
+			if t == nil {
+				return "-"
+			}
+			return timefmt.Current().Show(*t, now)
+		}
+		fmt.Printf("%-18s %-8s %-12s %-10s %-10s %s\n", "JOB", "ENABLED", "SCHEDULE", "LAST RUN", "NEXT RUN", "RESULT")
+		for _, j := range jobs {
+			enabled := "no"
+			if j.Enabled {
+				enabled = "yes"
+			}
+			result := "-"
+			switch {
+			case j.LastError != "":
+				result = fmt.Sprintf("failed (%d in a row): %s", j.Failures, j.LastError)
+			case j.LastRunAt != nil:
+				result = "ok in " + numfmt.Millis(j.LastDurationMS)
+			}
+			fmt.Printf("%-18s %-8s %-12s %-10s %-10s %s\n", j.Name, enabled, j.Schedule, show(j.LastRunAt), show(j.NextRunAt), result)
+		}
+		return nil
+	},
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+	"github.com/Dicklesworthstone/slb/internal/testutil/daemontest"
+	"github.com/spf13/cobra"
+)
+
+func newTestDaemonJobsCmd(dbPath string) *cobra.Command {
+	root := &cobra.Command{Use: "slb", SilenceUsage: true, SilenceErrors: true}
+	root.PersistentFlags().StringVar(&flagDB, "db", dbPath, "database path")
+	root.PersistentFlags().StringVarP(&flagOutput, "output", "o", "text", "output format")
+	root.PersistentFlags().BoolVarP(&flagJSON, "json", "j", false, "json output")
+	jobs := &cobra.Command{Use: "jobs"}
+	jobs.AddCommand(&cobra.Command{Use: "list", Args: cobra.NoArgs, RunE: daemonJobsListCmd.RunE})
+	daemon := &cobra.Command{Use: "daemon"}
+	daemon.AddCommand(jobs)
+	root.AddCommand(daemon)
+	return root
+}
+
+func TestDaemonJobsList(t *testing.T) {
+	resetDaemonFlags()
+	t.Cleanup(resetDaemonFlags)
+	h := testutil.NewHarness(t)
+
+	stdout, err := executeCommandCapture(t, newTestDaemonJobsCmd(h.DBPath), "daemon", "jobs", "list")
+	testutil.RequireNoError(t, err, "daemon jobs list")
+	if !strings.Contains(stdout, "No daemon jobs recorded yet") {
+		t.Errorf("expected no jobs before the daemon ran:\n%s", stdout)
+	}
+
+	d := daemontest.Start(t, daemontest.WithHarness(h))
+	dbPath := filepath.Join(d.ProjectDir, ".slb", "state.db")
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		job, err := d.DB.GetDaemonJob("expiry_sweep")
+		if err == nil && job.Runs > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expiry_sweep never ran: %+v %v", job, err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	stdout, err = executeCommandCapture(t, newTestDaemonJobsCmd(dbPath), "daemon", "jobs", "list", "-j")
+	testutil.RequireNoError(t, err, "daemon jobs list -j")
+	var result struct {
+		Jobs []db.DaemonJob `json:"jobs"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	byName := make(map[string]db.DaemonJob)
+	for _, j := range result.Jobs {
+		byName[j.Name] = j
+	}
+	if len(byName) != 4 || !byName["expiry_sweep"].Enabled || byName["weekly_digest"].Enabled || byName["weekly_digest"].Schedule != "0 9 * * 1" {
+		t.Errorf("unexpected jobs:\n%s", stdout)
+	}
+
+	stdout, err = executeCommandCapture(t, newTestDaemonJobsCmd(dbPath), "daemon", "jobs", "list")
+	testutil.RequireNoError(t, err, "daemon jobs list")
+	if !strings.Contains(stdout, "expiry_sweep") || !strings.Contains(stdout, "ok in ") {
+		t.Errorf("unexpected text output:\n%s", stdout)
+	}
+}
@@ -1,12 +1,17 @@
 package cli
 
 import (
+	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/Dicklesworthstone/slb/internal/daemon"
 	"github.com/Dicklesworthstone/slb/internal/testutil"
+	"github.com/Dicklesworthstone/slb/internal/testutil/daemontest"
 	"github.com/spf13/cobra"
 )
 
@@ -19,6 +24,45 @@ func resetDaemonFlags() {
 	flagDaemonStopTimeoutSecs = 10
 	flagDaemonLogsFollow = false
 	flagDaemonLogsLines = 200
+	flagDaemonHealthTimeoutSecs = 5
+	flagDaemonHealthWatchdog = false
+	flagDaemonHealthFailures = 3
+	flagDaemonHealthIntervalSecs = 5
+	flagDaemonStartChaos = ""
+	flagDaemonRunChaos = ""
+}
+
+func TestDaemonChaos(t *testing.T) {
+	parse := func(args ...string) (*daemon.Chaos, error) {
+		var spec string
+		cmd := &cobra.Command{Use: "run"}
+		addDaemonChaosFlag(cmd, &spec)
+		if err := cmd.ParseFlags(args); err != nil {
+			t.Fatalf("ParseFlags: %v", err)
+		}
+		return daemonChaos(cmd, spec)
+	}
+
+	t.Setenv(daemon.ChaosEnv, "")
+	if c, err := parse(); c != nil || err != nil {
+		t.Errorf("without --chaos: got %v, %v", c, err)
+	}
+	if _, err := parse("--chaos"); err == nil || !strings.Contains(err.Error(), daemon.ChaosEnv) {
+		t.Errorf("--chaos without %s: got %v", daemon.ChaosEnv, err)
+	}
+
+	t.Setenv(daemon.ChaosEnv, "1")
+	c, err := parse("--chaos")
+	if err != nil || c.String() != daemon.DefaultChaosSpec {
+		t.Errorf("--chaos: got %v, %v", c, err)
+	}
+	c, err = parse("--chaos=drop=0.5,seed=3")
+	if err != nil || c.DropRate != 0.5 || c.Seed != 3 {
+		t.Errorf("--chaos=drop=0.5,seed=3: got %+v, %v", c, err)
+	}
+	if _, err := parse("--chaos=drop=lots"); err == nil {
+		t.Error("invalid spec should fail")
+	}
 }
 
 func TestDaemonProjectPath_FromFlag(t *testing.T) {
@@ -527,3 +571,131 @@ func TestDaemonLogPath_HomeUnset(t *testing.T) {
 		}
 	}
 }
+
+func TestDaemonStatusCommand_RunningDaemon(t *testing.T) {
+	resetDaemonFlags()
+	t.Cleanup(resetDaemonFlags)
+	wd, err := os.Getwd()
+	testutil.RequireNoError(t, err, "getwd")
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	d := daemontest.Start(t, daemontest.WithProjectSocket())
+	sess := testutil.MakeSession(t, d.DB, testutil.WithProject(d.ProjectDir))
+	testutil.MakeRequest(t, d.DB, sess, testutil.WithCommand("rm -rf ./build", d.ProjectDir, true))
+
+	root := &cobra.Command{Use: "slb", SilenceUsage: true, SilenceErrors: true}
+	root.PersistentFlags().StringVarP(&flagOutput, "output", "o", "text", "output format")
+	root.PersistentFlags().BoolVarP(&flagJSON, "json", "j", false, "json output")
+	root.PersistentFlags().StringVarP(&flagProject, "project", "C", "", "project directory")
+	daemon := &cobra.Command{Use: "daemon"}
+	daemon.AddCommand(daemonStatusCmd)
+	root.AddCommand(daemon)
+
+	stdout, err := executeCommandCapture(t, root, "daemon", "status", "-C", d.ProjectDir, "-j")
+	testutil.RequireNoError(t, err, "daemon status")
+
+	var result map[string]any
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	if result["running"] != true || result["socket_alive"] != true {
+		t.Fatalf("expected a running daemon, got %v", result)
+	}
+	if result["pending_count"] != float64(1) {
+		t.Errorf("pending_count = %v, want 1", result["pending_count"])
+	}
+	if _, ok := result["writer"]; !ok {
+		t.Errorf("expected writer stats from the status RPC, got %v", result)
+	}
+}
+
+func newTestDaemonHealthRoot() *cobra.Command {
+	root := &cobra.Command{Use: "slb", SilenceUsage: true, SilenceErrors: true}
+	root.PersistentFlags().StringVarP(&flagOutput, "output", "o", "text", "output format")
+	root.PersistentFlags().BoolVarP(&flagJSON, "json", "j", false, "json output")
+	root.PersistentFlags().StringVarP(&flagProject, "project", "C", "", "project directory")
+	daemon := &cobra.Command{Use: "daemon"}
+	daemon.AddCommand(daemonHealthCmd)
+	root.AddCommand(daemon)
+	return root
+}
+
+func TestDaemonHealthCommand_RunningDaemon(t *testing.T) {
+	resetDaemonFlags()
+	t.Cleanup(resetDaemonFlags)
+	wd, err := os.Getwd()
+	testutil.RequireNoError(t, err, "getwd")
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	d := daemontest.Start(t, daemontest.WithProjectSocket())
+
+	stdout, err := executeCommandCapture(t, newTestDaemonHealthRoot(), "daemon", "health", "-C", d.ProjectDir, "-j")
+	testutil.RequireNoError(t, err, "daemon health")
+
+	var result struct {
+		Healthy bool             `json:"healthy"`
+		Checks  int              `json:"checks"`
+		Probes  []map[string]any `json:"probes"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	if !result.Healthy || result.Checks != 1 || len(result.Probes) != 4 {
+		t.Fatalf("unexpected health result: %+v", result)
+	}
+}
+
+func TestDaemonHealthCommand_WatchdogRestarts(t *testing.T) {
+	resetDaemonFlags()
+	t.Cleanup(resetDaemonFlags)
+	wd, err := os.Getwd()
+	testutil.RequireNoError(t, err, "getwd")
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	var restarts []string
+	orig := restartDaemon
+	restartDaemon = func(project string, timeout time.Duration) error {
+		restarts = append(restarts, project)
+		return errors.New("restart refused in test")
+	}
+	t.Cleanup(func() { restartDaemon = orig })
+
+	project := t.TempDir()
+	stdout, err := executeCommandCapture(t, newTestDaemonHealthRoot(), "daemon", "health", "-C", project, "-j",
+		"--watchdog", "--failures", "2", "--interval", "0", "--timeout", "1")
+	if err == nil || !strings.Contains(err.Error(), "daemon unhealthy") {
+		t.Fatalf("expected an unhealthy daemon error, got %v", err)
+	}
+	if len(restarts) != 1 || restarts[0] != project {
+		t.Fatalf("expected one restart of %s, got %v", project, restarts)
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	if result["restarted"] != true || result["restart_error"] != "restart refused in test" {
+		t.Fatalf("expected a failed restart in the result, got %v", result)
+	}
+	if result["checks"] != float64(3) {
+		t.Errorf("checks = %v, want 3 (two failures and one after the restart)", result["checks"])
+	}
+}
+
+func TestForegroundDaemonLogger_Level(t *testing.T) {
+	t.Setenv("SLB_LOG_LEVEL", "warn")
+	var buf strings.Builder
+
+	logger := foregroundDaemonLogger(&buf, "")
+	logger.Info("hidden")
+	logger.Warn("shown")
+	if strings.Contains(buf.String(), "hidden") || !strings.Contains(buf.String(), "shown") {
+		t.Fatalf("expected $SLB_LOG_LEVEL to apply, got %q", buf.String())
+	}
+
+	buf.Reset()
+	foregroundDaemonLogger(&buf, "debug").Debug("details", "client", 3)
+	if !strings.Contains(buf.String(), "details") || !strings.Contains(buf.String(), "client=3") {
+		t.Fatalf("expected the flag to override the env, got %q", buf.String())
+	}
+}
@@ -0,0 +1,242 @@
+// Package cli implements the delegate command for approval delegation.
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagDelegateTo         string
+	flagDelegateUntil      string
+	flagDelegateReason     string
+	flagDelegateSessionKey string
+	flagDelegateAll        bool
+)
+
+func init() {
+	delegateCmd.Flags().StringVar(&flagDelegateTo, "to", "", "reviewer receiving your approval authority (required)")
+	delegateCmd.Flags().StringVar(&flagDelegateUntil, "until", "", "end of the delegation window: fri, tomorrow, 3d, 8h, 2006-01-02 (required)")
+	delegateCmd.Flags().StringVar(&flagDelegateReason, "reason", "", "why the delegation exists (e.g. vacation)")
+	delegateCmd.PersistentFlags().StringVarP(&flagDelegateSessionKey, "session-key", "k", "", "session HMAC key for signing (default: SLB_SESSION_KEY)")
+
+	delegateListCmd.Flags().BoolVar(&flagDelegateAll, "all", false, "include expired and revoked delegations")
+
+	delegateCmd.AddCommand(delegateListCmd)
+	delegateCmd.AddCommand(delegateRevokeCmd)
+
+	rootCmd.AddCommand(delegateCmd)
+}
+
+var delegateCmd = &cobra.Command{
+	Use:   "delegate",
+	Short: "Delegate your approval authority to another reviewer",
+	Long: `Delegate your approval authority to another reviewer for a time window.
+
+While a delegation is active, an approval from the delegate also counts
+toward quorum on your behalf, so absences don't block reviews. You delegate
+as your own reviewer session (--session-id, else SLB_SESSION_ID, else your
+active session), and the delegation is signed with its key, like a review.
+
+A delegation counts only while your session is active in the project, and
+only where you could approve yourself: never on your own requests, not once
+you review a request yourself, and not on requests that need a different
+model than yours. Delegations are not transitive and add at most one
+approval to a request; on critical requests they count only once two
+reviewers approved directly.
+
+Only you or your delegate can revoke a delegation.
+
+Examples:
+  slb delegate --to Bob --until fri --reason "out of office"
+  slb delegate --to Bob --until 3d --session-id $SESSION_ID -k $SESSION_KEY
+  slb delegate list
+  slb delegate revoke <delegation-id>`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if strings.TrimSpace(flagDelegateTo) == "" {
+			return fmt.Errorf("--to is required")
+		}
+		if strings.TrimSpace(flagDelegateUntil) == "" {
+			return fmt.Errorf("--until is required")
+		}
+
+		project, err := projectPath()
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		until, err := core.ParseDelegationUntil(flagDelegateUntil, now)
+		if err != nil {
+			return err
+		}
+		if !until.After(now) {
+			return fmt.Errorf("--until must be in the future")
+		}
+
+		dbConn, err := db.OpenAndMigrate(GetDB())
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer dbConn.Close()
+
+		sessionID, sessionKey, err := delegateSession(dbConn, project)
+		if err != nil {
+			return err
+		}
+		d, err := core.CreateDelegation(dbConn, core.DelegationOptions{
+			SessionID:   sessionID,
+			SessionKey:  sessionKey,
+			ProjectPath: project,
+			ToAgent:     flagDelegateTo,
+			Reason:      flagDelegateReason,
+			ExpiresAt:   until,
+		})
+		if err != nil {
+			return err
+		}
+
+		out := output.New(output.Format(GetOutput()))
+		if isJSONOutput() {
+			return out.Write(delegationView(d))
+		}
+		output.Notef(os.Stdout, "Delegated approval authority from %s to %s until %s\n",
+			d.FromAgent, d.ToAgent, until.Format("Mon Jan 2 15:04 MST"))
+		output.Notef(os.Stdout, "Delegation ID: %s\n", d.ID)
+		return nil
+	},
+}
+
+var delegateListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List approval delegations for this project",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		project, err := projectPath()
+		if err != nil {
+			return err
+		}
+
+		dbConn, err := db.OpenAndMigrate(GetDB())
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer dbConn.Close()
+
+		delegations, err := dbConn.ListDelegations(project, flagDelegateAll)
+		if err != nil {
+			return err
+		}
+
+		out := output.New(output.Format(GetOutput()))
+		if isJSONOutput() {
+			views := make([]map[string]any, 0, len(delegations))
+			for _, d := range delegations {
+				views = append(views, delegationView(d))
+			}
+			return out.Write(views)
+		}
+
+		if len(delegations) == 0 {
+			fmt.Println("No active delegations.")
+			return nil
+		}
+		now := time.Now()
+		for _, d := range delegations {
+			state := "active"
+			switch {
+			case d.RevokedAt != nil:
+				state = "revoked"
+			case !d.IsActiveAt(now):
+				state = "expired"
+			}
+			fmt.Printf("%s  %s -> %s  until %s  [%s]\n",
+				d.ID, d.FromAgent, d.ToAgent, d.ExpiresAt.Local().Format("Mon Jan 2 15:04"), state)
+			if d.Reason != "" {
+				fmt.Printf("    reason: %s\n", d.Reason)
+			}
+		}
+		return nil
+	},
+}
+
+var delegateRevokeCmd = &cobra.Command{
+	Use:   "revoke <delegation-id>",
+	Short: "Revoke an approval delegation early",
+	Long: `Revoke an approval delegation early.
+
+Only the delegator or the delegate can revoke a delegation, as their
+session in this project (--session-id, else SLB_SESSION_ID, else your
+active session).`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		project, err := projectPath()
+		if err != nil {
+			return err
+		}
+
+		dbConn, err := db.OpenAndMigrate(GetDB())
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer dbConn.Close()
+
+		sessionID, sessionKey, err := delegateSession(dbConn, project)
+		if err != nil {
+			return err
+		}
+		if err := core.RevokeDelegation(dbConn, sessionID, sessionKey, project, args[0]); err != nil {
+			return fmt.Errorf("revoking delegation %s: %w", args[0], err)
+		}
+
+		out := output.New(output.Format(GetOutput()))
+		if isJSONOutput() {
+			return out.Write(map[string]any{"id": args[0], "revoked": true})
+		}
+		output.Notef(os.Stdout, "Revoked delegation %s\n", args[0])
+		return nil
+	},
+}
+
+// delegateSession resolves the caller's reviewer session and its key, the
+// way approve does.
+func delegateSession(dbConn *db.DB, project string) (string, string, error) {
+	sessionID, err := resolveReviewerSessionID(dbConn, project, flagSessionID)
+	if err != nil {
+		return "", "", err
+	}
+	sessionKey, err := resolveSessionKey(flagDelegateSessionKey, sessionID)
+	if err != nil {
+		return "", "", err
+	}
+	return sessionID, sessionKey, nil
+}
+
+func delegationView(d *db.Delegation) map[string]any {
+	view := map[string]any{
+		"id":           d.ID,
+		"project_path": d.ProjectPath,
+		"from_agent":   d.FromAgent,
+		"to_agent":     d.ToAgent,
+		"starts_at":    timefmt.Format(d.StartsAt),
+		"expires_at":   timefmt.Format(d.ExpiresAt),
+		"created_at":   timefmt.Format(d.CreatedAt),
+		"active":       d.IsActiveAt(time.Now()),
+	}
+	if d.Reason != "" {
+		view["reason"] = d.Reason
+	}
+	if d.RevokedAt != nil {
+		view["revoked_at"] = timefmt.Format(*d.RevokedAt)
+	}
+	return view
+}
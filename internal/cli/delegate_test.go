@@ -0,0 +1,176 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+	"github.com/spf13/cobra"
+)
+
+// newTestDelegateCmd creates a fresh delegate command tree for testing.
+func newTestDelegateCmd(dbPath string) *cobra.Command {
+	root := &cobra.Command{
+		Use:           "slb",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	root.PersistentFlags().StringVarP(&flagOutput, "output", "o", "text", "output format")
+	root.PersistentFlags().BoolVarP(&flagJSON, "json", "j", false, "shorthand for --output=json")
+	root.PersistentFlags().StringVar(&flagDB, "db", dbPath, "database path")
+	root.PersistentFlags().StringVar(&flagActor, "actor", "", "actor identifier")
+	root.PersistentFlags().StringVarP(&flagSessionID, "session-id", "s", "", "session ID")
+	root.PersistentFlags().StringVarP(&flagProject, "project", "C", "", "project directory")
+
+	root.AddCommand(delegateCmd)
+
+	return root
+}
+
+// resetDelegateFlags resets all delegate-related flags to defaults.
+func resetDelegateFlags() {
+	flagOutput = "text"
+	flagJSON = false
+	flagDB = ""
+	flagActor = ""
+	flagSessionID = ""
+	flagProject = ""
+	flagDelegateTo = ""
+	flagDelegateUntil = ""
+	flagDelegateReason = ""
+	flagDelegateSessionKey = ""
+	flagDelegateAll = false
+}
+
+func TestDelegate_RequiresTo(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetDelegateFlags()
+
+	cmd := newTestDelegateCmd(h.DBPath)
+	_, _, err := executeCommand(cmd, "delegate", "--until", "3d", "-C", h.ProjectDir)
+	if err == nil || !strings.Contains(err.Error(), "--to is required") {
+		t.Fatalf("expected --to is required error, got %v", err)
+	}
+}
+
+func TestDelegate_InvalidUntil(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetDelegateFlags()
+
+	cmd := newTestDelegateCmd(h.DBPath)
+	_, _, err := executeCommand(cmd, "delegate", "--to", "Bob", "--until", "someday", "-C", h.ProjectDir)
+	if err == nil || !strings.Contains(err.Error(), "unrecognized time") {
+		t.Fatalf("expected unrecognized time error, got %v", err)
+	}
+}
+
+func TestDelegate_CreateListRevoke(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetDelegateFlags()
+	t.Cleanup(resetDelegateFlags)
+	alice := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir), testutil.WithAgent("Alice"))
+	bob := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir), testutil.WithAgent("Bob"))
+	carol := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir), testutil.WithAgent("Carol"))
+
+	cmd := newTestDelegateCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "delegate",
+		"--to", "Bob", "--until", "3d", "--reason", "vacation",
+		"-s", alice.ID, "-k", alice.SessionKey, "-C", h.ProjectDir, "-j")
+	if err != nil {
+		t.Fatalf("delegate: %v", err)
+	}
+
+	var created map[string]any
+	if err := json.Unmarshal([]byte(stdout), &created); err != nil {
+		t.Fatalf("parse JSON: %v\n%s", err, stdout)
+	}
+	if created["from_agent"] != "Alice" || created["to_agent"] != "Bob" || created["active"] != true {
+		t.Fatalf("unexpected delegation: %v", created)
+	}
+	id, _ := created["id"].(string)
+	if id == "" {
+		t.Fatal("expected delegation id")
+	}
+
+	resetDelegateFlags()
+	cmd = newTestDelegateCmd(h.DBPath)
+	stdout, err = executeCommandCapture(t, cmd, "delegate", "list", "-C", h.ProjectDir, "-j")
+	if err != nil {
+		t.Fatalf("delegate list: %v", err)
+	}
+	var listed []map[string]any
+	if err := json.Unmarshal([]byte(stdout), &listed); err != nil {
+		t.Fatalf("parse JSON: %v\n%s", err, stdout)
+	}
+	if len(listed) != 1 || listed[0]["id"] != id {
+		t.Fatalf("expected delegation %s listed, got %v", id, listed)
+	}
+
+	resetDelegateFlags()
+	cmd = newTestDelegateCmd(h.DBPath)
+	if _, err := executeCommandCapture(t, cmd, "delegate", "revoke", id,
+		"-s", carol.ID, "-k", carol.SessionKey, "-C", h.ProjectDir); err == nil {
+		t.Fatal("a reviewer outside the delegation should not revoke it")
+	}
+
+	resetDelegateFlags()
+	cmd = newTestDelegateCmd(h.DBPath)
+	if _, err := executeCommandCapture(t, cmd, "delegate", "revoke", id,
+		"-s", bob.ID, "-k", bob.SessionKey, "-C", h.ProjectDir, "-j"); err != nil {
+		t.Fatalf("delegate revoke: %v", err)
+	}
+
+	resetDelegateFlags()
+	cmd = newTestDelegateCmd(h.DBPath)
+	stdout, err = executeCommandCapture(t, cmd, "delegate", "list", "-C", h.ProjectDir, "-j")
+	if err != nil {
+		t.Fatalf("delegate list: %v", err)
+	}
+	listed = nil
+	if err := json.Unmarshal([]byte(stdout), &listed); err != nil {
+		t.Fatalf("parse JSON: %v\n%s", err, stdout)
+	}
+	if len(listed) != 0 {
+		t.Fatalf("expected no active delegations after revoke, got %v", listed)
+	}
+
+	resetDelegateFlags()
+	cmd = newTestDelegateCmd(h.DBPath)
+	if _, err := executeCommandCapture(t, cmd, "delegate", "revoke", id,
+		"-s", alice.ID, "-k", alice.SessionKey, "-C", h.ProjectDir); err == nil {
+		t.Fatal("expected error revoking an already revoked delegation")
+	}
+}
+
+func TestDelegate_RejectsForgedDelegator(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetDelegateFlags()
+	t.Cleanup(resetDelegateFlags)
+	alice := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir), testutil.WithAgent("Alice"))
+	mallory := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir), testutil.WithAgent("Mallory"))
+
+	// Alice's session with Mallory's key.
+	cmd := newTestDelegateCmd(h.DBPath)
+	if _, err := executeCommandCapture(t, cmd, "delegate", "--to", "Mallory", "--until", "3d",
+		"-s", alice.ID, "-k", mallory.SessionKey, "-C", h.ProjectDir); err == nil {
+		t.Fatal("delegating from another reviewer's session should fail")
+	}
+
+	// A delegator named on the command line no longer exists as an option.
+	resetDelegateFlags()
+	cmd = newTestDelegateCmd(h.DBPath)
+	if _, err := executeCommandCapture(t, cmd, "delegate", "--to", "Mallory", "--until", "3d", "--from", "ghost1",
+		"-s", mallory.ID, "-k", mallory.SessionKey, "-C", h.ProjectDir); err == nil {
+		t.Fatal("--from should be rejected")
+	}
+
+	delegations, err := h.DB.ListDelegations(h.ProjectDir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(delegations) != 0 {
+		t.Fatalf("forged delegations were recorded: %+v", delegations)
+	}
+}
@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// diffValueLimit caps how much of a changed value the text output prints;
+// JSON output always carries the full values.
+const diffValueLimit = 400
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <request-id> <request-id>",
+	Short: "Compare two requests",
+	Long: `Compare two requests: their commands word by word, then the
+justification, context (working directory, tier, dry run, attachments)
+and outcome (status, reviews, execution) fields that differ.
+
+Useful when an agent resubmits a revised request after a rejection: diff
+the rejected request against the new one to see exactly what changed.
+Sensitive commands are compared in redacted form.
+
+Text output marks removed words [-like this-] and added words {+like this+}.
+
+Examples:
+  slb diff <rejected-id> <resubmitted-id>
+  slb diff <rejected-id> <resubmitted-id> -j`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dbConn, err := db.Open(GetDB())
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer dbConn.Close()
+
+		from, err := loadDiffSnapshot(dbConn, args[0])
+		if err != nil {
+			return err
+		}
+		to, err := loadDiffSnapshot(dbConn, args[1])
+		if err != nil {
+			return err
+		}
+		if err := dbConn.LoadRequestLabels([]*db.Request{from.Request, to.Request}); err != nil {
+			return fmt.Errorf("loading labels: %w", err)
+		}
+
+		diff := core.DiffRequests(from, to)
+		if GetOutput() != "text" {
+			return output.New(output.Format(GetOutput())).Write(diff)
+		}
+		printRequestDiff(diff)
+		return nil
+	},
+}
+
+// loadDiffSnapshot loads a request with its reviews and recorded outcome.
+func loadDiffSnapshot(dbConn *db.DB, id string) (core.RequestSnapshot, error) {
+	request, reviews, err := dbConn.GetRequestWithReviews(id)
+	if err != nil {
+		return core.RequestSnapshot{}, fmt.Errorf("getting request %s: %w", id, err)
+	}
+	snap := core.RequestSnapshot{Request: request, Reviews: reviews}
+	outcome, err := dbConn.GetOutcomeForRequest(id)
+	switch {
+	case err == nil:
+		snap.Outcome = outcome
+	case !errors.Is(err, db.ErrOutcomeNotFound):
+		return core.RequestSnapshot{}, fmt.Errorf("getting outcome for %s: %w", id, err)
+	}
+	return snap, nil
+}
+
+func printRequestDiff(d *core.RequestDiff) {
+	fmt.Printf("--- %s\n+++ %s\n\n", d.From, d.To)
+	if d.Identical() {
+		fmt.Println("No differences.")
+		return
+	}
+
+	fmt.Println("Command:")
+	if d.CommandChanged {
+		fmt.Printf("  %s\n", core.FormatWordDiff(d.Command))
+	} else {
+		fmt.Println("  (unchanged)")
+	}
+
+	for _, section := range []struct {
+		title   string
+		changes []core.FieldChange
+	}{
+		{"Justification", d.Justification},
+		{"Context", d.Context},
+		{"Outcome", d.Outcome},
+	} {
+		if len(section.changes) == 0 {
+			continue
+		}
+		fmt.Printf("\n%s:\n", section.title)
+		for _, c := range section.changes {
+			fmt.Printf("  %s\n", c.Field)
+			printDiffValue("-", c.Old)
+			printDiffValue("+", c.New)
+		}
+	}
+}
+
+// printDiffValue prints one side of a changed field, a line at a time,
+// cut to diffValueLimit runes.
+func printDiffValue(marker, value string) {
+	if value == "" {
+		return
+	}
+	if runes := []rune(value); len(runes) > diffValueLimit {
+		value = fmt.Sprintf("%s... (%d more characters; use -j for all)", string(runes[:diffValueLimit]), len(runes)-diffValueLimit)
+	}
+	for _, line := range strings.Split(value, "\n") {
+		fmt.Printf("    %s %s\n", marker, line)
+	}
+}
@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+	"github.com/spf13/cobra"
+)
+
+func newTestDiffCmd(dbPath string) *cobra.Command {
+	root := &cobra.Command{
+		Use:           "slb",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	root.PersistentFlags().StringVar(&flagDB, "db", dbPath, "database path")
+	root.PersistentFlags().StringVarP(&flagOutput, "output", "o", "text", "output format")
+	root.PersistentFlags().BoolVarP(&flagJSON, "json", "j", false, "json output")
+	root.PersistentFlags().StringVarP(&flagProject, "project", "C", "", "project directory")
+
+	root.AddCommand(diffCmd)
+
+	return root
+}
+
+func resetDiffFlags() {
+	flagDB = ""
+	flagOutput = "text"
+	flagJSON = false
+	flagProject = ""
+}
+
+func TestDiffCommand_ComparesResubmission(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetDiffFlags()
+
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	rejected := testutil.MakeRequest(t, h.DB, sess,
+		testutil.WithCommand("rm -rf ./build", h.ProjectDir, true),
+		testutil.WithJustification("clean build", "", "", ""),
+		testutil.WithStatus(db.StatusRejected),
+	)
+	revised := testutil.MakeRequest(t, h.DB, sess,
+		testutil.WithCommand("rm -rf ./build/cache", h.ProjectDir, true),
+		testutil.WithJustification("clear the stale cache only", "", "", ""),
+	)
+
+	stdout, err := executeCommandCapture(t, newTestDiffCmd(h.DBPath), "diff", rejected.ID, revised.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"rm -rf [-./build-] {+./build/cache+}",
+		"Justification:",
+		"- clean build",
+		"+ clear the stale cache only",
+		"- rejected",
+		"+ pending",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in output:\n%s", want, stdout)
+		}
+	}
+
+	resetDiffFlags()
+	stdout, err = executeCommandCapture(t, newTestDiffCmd(h.DBPath), "diff", rejected.ID, revised.ID, "-j")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var diff core.RequestDiff
+	if err := json.Unmarshal([]byte(stdout), &diff); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if diff.From != rejected.ID || diff.To != revised.ID || !diff.CommandChanged || len(diff.Justification) != 1 {
+		t.Errorf("unexpected diff: %+v", diff)
+	}
+}
+
+func TestDiffCommand_Errors(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetDiffFlags()
+
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	req := testutil.MakeRequest(t, h.DB, sess)
+
+	if _, err := executeCommandCapture(t, newTestDiffCmd(h.DBPath), "diff", req.ID); err == nil {
+		t.Error("expected error with one request id")
+	}
+	resetDiffFlags()
+	if _, err := executeCommandCapture(t, newTestDiffCmd(h.DBPath), "diff", req.ID, "missing"); err == nil {
+		t.Error("expected error for an unknown request")
+	}
+	resetDiffFlags()
+	stdout, err := executeCommandCapture(t, newTestDiffCmd(h.DBPath), "diff", req.ID, req.ID)
+	if err != nil || !strings.Contains(stdout, "No differences.") {
+		t.Errorf("expected no differences, got %v:\n%s", err, stdout)
+	}
+}
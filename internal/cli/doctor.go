@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/daemon"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/integrations"
+	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// Doctor check results.
+const (
+	doctorOK   = "ok"
+	doctorWarn = "warn"
+	doctorFail = "fail"
+)
+
+// doctorCheck is one line of `slb doctor` output.
+type doctorCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check config, database, daemon and telemetry",
+	Long: `Check that the config loads, the database opens at the current schema
+version and the daemon is running, and show whether telemetry is enabled.
+Exits non-zero when a check fails; warnings do not fail.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		checks := runDoctorChecks()
+
+		failed := 0
+		for _, c := range checks {
+			if c.Status == doctorFail {
+				failed++
+			}
+		}
+
+		if GetOutput() != "text" {
+			if err := output.New(output.Format(GetOutput())).Write(map[string]any{
+				"ok":     failed == 0,
+				"checks": checks,
+			}); err != nil {
+				return err
+			}
+		} else {
+			for _, c := range checks {
+				fmt.Printf("[%-4s] %-10s %s\n", c.Status, c.Name, c.Detail)
+			}
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d doctor check(s) failed", failed)
+		}
+		return nil
+	},
+}
+
+func runDoctorChecks() []doctorCheck {
+	var checks []doctorCheck
+
+	cfg := config.DefaultConfig()
+	project, err := projectPath()
+	if err == nil {
+		cfg, err = config.Load(config.LoadOptions{ProjectDir: project, ConfigPath: flagConfig})
+	}
+	if err != nil {
+		checks = append(checks, doctorCheck{Name: "config", Status: doctorFail, Detail: err.Error()})
+	} else {
+		checks = append(checks, doctorCheck{Name: "config", Status: doctorOK, Detail: "loaded and valid"})
+	}
+
+	dbPath := GetDB()
+	var dbConn *db.DB
+	if _, err := os.Stat(dbPath); errors.Is(err, os.ErrNotExist) {
+		checks = append(checks, doctorCheck{Name: "database", Status: doctorWarn, Detail: dbPath + " does not exist (run 'slb init')"})
+	} else if dbConn, err = db.OpenWithOptions(dbPath, db.OpenOptions{ReadOnly: true}); err != nil {
+		checks = append(checks, doctorCheck{Name: "database", Status: doctorFail, Detail: err.Error()})
+	} else {
+		defer dbConn.Close()
+		checks = append(checks, doctorDatabaseCheck(dbConn, dbPath))
+	}
+
+	info := daemon.NewClient().GetStatusInfo()
+	if info.Status == daemon.DaemonRunning {
+		checks = append(checks, doctorCheck{Name: "daemon", Status: doctorOK, Detail: fmt.Sprintf("running (pid %d)", info.PID)})
+	} else {
+		detail := info.Status.String()
+		if msg := strings.TrimSpace(info.Message); msg != "" {
+			detail = msg
+		}
+		checks = append(checks, doctorCheck{Name: "daemon", Status: doctorWarn, Detail: detail})
+	}
+
+	status := &integrations.TelemetryStatus{Enabled: cfg.Telemetry.Enabled, Endpoint: cfg.Telemetry.Endpoint}
+	if dbConn != nil {
+		if s, err := integrations.GetTelemetryStatus(dbConn, cfg.Telemetry.Enabled, cfg.Telemetry.Endpoint); err == nil {
+			status = s
+		}
+	}
+	telemetry := doctorCheck{Name: "telemetry", Status: doctorOK, Detail: telemetryStatusLine(status)}
+	if status.Enabled && status.LastAttempt != nil && status.LastAttempt.Error != "" {
+		telemetry.Status = doctorWarn
+		telemetry.Detail += ": " + status.LastAttempt.Error
+	}
+	checks = append(checks, telemetry)
+
+	return checks
+}
+
+func doctorDatabaseCheck(dbConn *db.DB, dbPath string) doctorCheck {
+	version, err := dbConn.GetSchemaVersion()
+	switch {
+	case err != nil:
+		return doctorCheck{Name: "database", Status: doctorFail, Detail: err.Error()}
+	case version < db.SchemaVersion:
+		return doctorCheck{Name: "database", Status: doctorWarn,
+			Detail: fmt.Sprintf("%s at schema v%d, v%d pending (applied on next write)", dbPath, version, db.SchemaVersion)}
+	case version > db.SchemaVersion:
+		return doctorCheck{Name: "database", Status: doctorFail,
+			Detail: fmt.Sprintf("%s at schema v%d, newer than this slb (v%d)", dbPath, version, db.SchemaVersion)}
+	default:
+		return doctorCheck{Name: "database", Status: doctorOK, Detail: fmt.Sprintf("%s at schema v%d", dbPath, version)}
+	}
+}
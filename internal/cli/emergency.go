@@ -1,10 +1,7 @@
 package cli
 
 import (
-	"bufio"
 	"context"
-	"crypto/sha256"
-	"encoding/hex"
 	"fmt"
 	"os"
 	"strings"
@@ -13,15 +10,15 @@ import (
 	"github.com/Dicklesworthstone/slb/internal/config"
 	"github.com/Dicklesworthstone/slb/internal/core"
 	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/numfmt"
 	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
 	"github.com/google/uuid"
 	"github.com/spf13/cobra"
 )
 
 var (
 	flagEmergencyReason  string
-	flagEmergencyYes     bool
-	flagEmergencyAck     string
 	flagEmergencyCapture bool
 	flagEmergencyTimeout int
 	flagEmergencyLogDir  string
@@ -29,8 +26,6 @@ var (
 
 func init() {
 	emergencyCmd.Flags().StringVarP(&flagEmergencyReason, "reason", "r", "", "reason for emergency execution (required)")
-	emergencyCmd.Flags().BoolVarP(&flagEmergencyYes, "yes", "y", false, "skip interactive confirmation")
-	emergencyCmd.Flags().StringVar(&flagEmergencyAck, "ack", "", "command hash acknowledgment (required with --yes)")
 	emergencyCmd.Flags().BoolVar(&flagEmergencyCapture, "capture-rollback", false, "capture state for rollback")
 	// No -t shorthand: -t (--toon) is owned by the root persistent flags.
 	emergencyCmd.Flags().IntVar(&flagEmergencyTimeout, "timeout", 300, "execution timeout in seconds")
@@ -44,91 +39,61 @@ var emergencyCmd = &cobra.Command{
 	Short: "Execute a command without approval (human override)",
 	Long: `Execute a command bypassing the normal approval process.
 
-This is a HUMAN OVERRIDE for emergency situations. It requires:
+This is a HUMAN OVERRIDE for emergency situations and a break-glass
+execution: it follows the same rules as 'slb breakglass'. It requires:
 - A mandatory reason explaining why the bypass is necessary
-- Interactive confirmation OR --yes with --ack containing the command hash
+- Confirmation at the controlling terminal: the human enters their name and
+  types BREAKGLASS, so an agent cannot run it on its own
+- No break-glass in the project within general.breakglass_cooldown_hours,
+  and no overdue postmortem
 
-The command is extensively logged for audit purposes.
+The command runs directly on the host as the invoking user: [isolation]
+settings do not apply to break-glass executions.
+
+It opens a break-glass incident that needs a postmortem via
+'slb breakglass ack', and can capture rollback state first.
 
 Examples:
   slb emergency-execute "rm -rf /tmp/broken" -r "System emergency"
-  slb emergency-execute "kubectl delete pod stuck-pod" -r "Pod restart needed" --yes --ack abc123
-
-To get the command hash for --ack, run:
-  echo -n "your command" | sha256sum | cut -d' ' -f1`,
+  slb emergency-execute "kubectl delete pod stuck-pod" -r "Pod restart needed" --capture-rollback`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		command := args[0]
 
 		// Validate required flags
-		if flagEmergencyReason == "" {
+		if strings.TrimSpace(flagEmergencyReason) == "" {
 			return fmt.Errorf("--reason is required for emergency execution")
 		}
 
-		// Compute command hash
-		hash := sha256.Sum256([]byte(command))
-		commandHash := hex.EncodeToString(hash[:])
+		project, err := projectPath()
+		if err != nil {
+			return err
+		}
 
-		// Validate confirmation
-		if flagEmergencyYes {
-			// Non-interactive mode requires --ack
-			if flagEmergencyAck == "" {
-				return fmt.Errorf("--ack is required when using --yes")
-			}
-			// Verify hash (allow first 8+ chars)
-			if len(flagEmergencyAck) < 8 {
-				return fmt.Errorf("--ack must be at least 8 characters of the command hash")
-			}
-			if !strings.HasPrefix(commandHash, flagEmergencyAck) {
-				return fmt.Errorf("--ack hash does not match command (expected prefix: %s)", commandHash[:8])
-			}
-		} else {
-			// Interactive confirmation
-			fmt.Println("=== EMERGENCY EXECUTION ===")
-			fmt.Printf("Command: %s\n", command)
-			fmt.Printf("Reason:  %s\n", flagEmergencyReason)
-			fmt.Printf("Hash:    %s\n", commandHash)
-			fmt.Println()
-			fmt.Println("This bypasses the approval process. Are you sure?")
-			fmt.Print("Type 'EXECUTE' to confirm: ")
-
-			reader := bufio.NewReader(os.Stdin)
-			input, err := reader.ReadString('\n')
-			if err != nil {
-				return fmt.Errorf("reading confirmation: %w", err)
-			}
+		cfg, err := config.Load(config.LoadOptions{
+			ProjectDir: project,
+			ConfigPath: flagConfig,
+		})
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
 
-			input = strings.TrimSpace(input)
-			if input != "EXECUTE" {
-				return fmt.Errorf("execution cancelled")
-			}
+		if flagEmergencyCapture && !cfg.General.EnableRollbackCapture {
+			return fmt.Errorf("rollback capture is disabled by config (general.enable_rollback_capture=false)")
 		}
 
-		// Open database for logging
 		dbConn, err := db.OpenAndMigrate(GetDB())
 		if err != nil {
 			return fmt.Errorf("opening database: %w", err)
 		}
 		defer dbConn.Close()
 
-		// Get current working directory
-		cwd, err := os.Getwd()
-		if err != nil {
-			cwd = "."
-		}
-
-		project, err := projectPath()
+		inc, err := openBreakglassIncident(cfg, dbConn, project, command, flagEmergencyReason)
 		if err != nil {
 			return err
 		}
-
-		cfg, err := config.Load(config.LoadOptions{
-			ProjectDir: project,
-			ConfigPath: flagConfig,
-		})
-		if err != nil {
-			return fmt.Errorf("loading config: %w", err)
-		}
+		cwd := inc.Cwd
+		commandHash := inc.CommandHash
 
 		// Build command spec
 		cmdSpec := &db.CommandSpec{
@@ -140,9 +105,6 @@ To get the command hash for --ack, run:
 
 		var rollbackPath string
 		if flagEmergencyCapture {
-			if !cfg.General.EnableRollbackCapture {
-				return fmt.Errorf("rollback capture is disabled by config (general.enable_rollback_capture=false)")
-			}
 			rollbackReq := &db.Request{
 				ID:          uuid.NewString(),
 				ProjectPath: project,
@@ -178,7 +140,8 @@ To get the command hash for --ack, run:
 
 		fmt.Fprintf(logFile, "=== EMERGENCY EXECUTION ===\n")
 		fmt.Fprintf(logFile, "Time:    %s\n", time.Now().Format(time.RFC3339))
-		fmt.Fprintf(logFile, "Actor:   %s\n", GetActor())
+		fmt.Fprintf(logFile, "Actor:   %s\n", inc.Actor)
+		fmt.Fprintf(logFile, "Incident: %s\n", inc.ID)
 		fmt.Fprintf(logFile, "Command: %s\n", command)
 		fmt.Fprintf(logFile, "Hash:    %s\n", commandHash)
 		fmt.Fprintf(logFile, "Reason:  %s\n", flagEmergencyReason)
@@ -190,23 +153,35 @@ To get the command hash for --ack, run:
 		defer cancel()
 
 		var streamWriter *os.File
-		if GetOutput() != "json" {
+		if !isJSONOutput() {
 			streamWriter = os.Stdout
 		}
 		result, err := core.RunCommand(ctx, cmdSpec, logPath, streamWriter)
+		secrets := core.ScanCommandOutput(result, logPath, nil)
+
+		var exitCode *int
+		if result != nil {
+			code := result.ExitCode
+			exitCode = &code
+		}
+		if updateErr := dbConn.UpdateBreakglassResult(inc.ID, exitCode, logPath); updateErr != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to record break-glass result: %v\n", updateErr)
+		}
 
 		// Build output
 		type emergencyResult struct {
-			Command      string `json:"command"`
-			Hash         string `json:"hash"`
-			ExitCode     int    `json:"exit_code"`
-			DurationMs   int64  `json:"duration_ms"`
-			LogPath      string `json:"log_path"`
-			RollbackPath string `json:"rollback_path,omitempty"`
-			Reason       string `json:"reason"`
-			Actor        string `json:"actor"`
-			ExecutedAt   string `json:"executed_at"`
-			Error        string `json:"error,omitempty"`
+			Command      string   `json:"command"`
+			Hash         string   `json:"hash"`
+			ExitCode     int      `json:"exit_code"`
+			DurationMs   int64    `json:"duration_ms"`
+			LogPath      string   `json:"log_path"`
+			RollbackPath string   `json:"rollback_path,omitempty"`
+			Reason       string   `json:"reason"`
+			Actor        string   `json:"actor"`
+			IncidentID   string   `json:"incident_id"`
+			ExecutedAt   string   `json:"executed_at"`
+			Error        string   `json:"error,omitempty"`
+			SecretsFound []string `json:"secrets_found,omitempty"`
 		}
 
 		resp := emergencyResult{
@@ -215,8 +190,10 @@ To get the command hash for --ack, run:
 			LogPath:      logPath,
 			RollbackPath: rollbackPath,
 			Reason:       flagEmergencyReason,
-			Actor:        GetActor(),
-			ExecutedAt:   time.Now().Format(time.RFC3339),
+			Actor:        inc.Actor,
+			IncidentID:   inc.ID,
+			ExecutedAt:   timefmt.Format(time.Now()),
+			SecretsFound: secrets,
 		}
 
 		if result != nil {
@@ -229,7 +206,7 @@ To get the command hash for --ack, run:
 		}
 
 		out := output.New(output.Format(GetOutput()))
-		if GetOutput() == "json" {
+		if isJSONOutput() {
 			if writeErr := out.Write(resp); writeErr != nil {
 				return writeErr
 			}
@@ -237,20 +214,23 @@ To get the command hash for --ack, run:
 		}
 
 		// Human-readable output
-		fmt.Println()
+		output.Notef(os.Stdout, "\n")
 		if err != nil {
 			fmt.Printf("Emergency execution failed: %s\n", err)
 		} else {
-			fmt.Printf("Emergency execution completed\n")
-			fmt.Printf("Exit code: %d\n", resp.ExitCode)
-			fmt.Printf("Duration: %dms\n", resp.DurationMs)
+			output.Notef(os.Stdout, "Emergency execution completed\n")
+			output.Notef(os.Stdout, "Exit code: %d\n", resp.ExitCode)
+			output.Notef(os.Stdout, "Duration: %s\n", numfmt.Millis(resp.DurationMs))
 		}
-		fmt.Printf("Log: %s\n", resp.LogPath)
+		warnBreakglassSecrets(secrets)
+		output.Notef(os.Stdout, "Log: %s\n", resp.LogPath)
 		if resp.RollbackPath != "" {
-			fmt.Printf("Rollback: %s\n", resp.RollbackPath)
+			output.Notef(os.Stdout, "Rollback: %s\n", resp.RollbackPath)
 		}
-		fmt.Println()
-		fmt.Println("Note: This execution was logged for audit purposes.")
+		output.Notef(os.Stdout, "\n")
+		output.Notef(os.Stdout, "Incident: %s\n", inc.ID)
+		output.Notef(os.Stdout, "A postmortem is due by %s:\n", inc.AckDueAt.Local().Format("Mon Jan 2 15:04 MST"))
+		output.Notef(os.Stdout, "  slb breakglass ack %s --postmortem \"...\"\n", inc.ID)
 
 		if err != nil {
 			return err
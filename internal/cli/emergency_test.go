@@ -3,9 +3,12 @@ package cli
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"strings"
 	"testing"
 
+	"github.com/Dicklesworthstone/slb/internal/core"
 	"github.com/Dicklesworthstone/slb/internal/testutil"
 	"github.com/spf13/cobra"
 )
@@ -32,8 +35,6 @@ func newTestEmergencyCmd(dbPath string) *cobra.Command {
 		RunE:  emergencyCmd.RunE,
 	}
 	emCmd.Flags().StringVarP(&flagEmergencyReason, "reason", "r", "", "reason for emergency execution")
-	emCmd.Flags().BoolVarP(&flagEmergencyYes, "yes", "y", false, "skip interactive confirmation")
-	emCmd.Flags().StringVar(&flagEmergencyAck, "ack", "", "command hash acknowledgment")
 	emCmd.Flags().BoolVar(&flagEmergencyCapture, "capture-rollback", false, "capture state for rollback")
 	// No -t shorthand: -t (--toon) is owned by the root persistent flags.
 	emCmd.Flags().IntVar(&flagEmergencyTimeout, "timeout", 300, "execution timeout")
@@ -51,8 +52,6 @@ func resetEmergencyFlags() {
 	flagProject = ""
 	flagConfig = ""
 	flagEmergencyReason = ""
-	flagEmergencyYes = false
-	flagEmergencyAck = ""
 	flagEmergencyCapture = false
 	flagEmergencyTimeout = 300
 	flagEmergencyLogDir = ".slb/logs"
@@ -80,7 +79,6 @@ func TestEmergencyCommand_RequiresReason(t *testing.T) {
 	cmd := newTestEmergencyCmd(h.DBPath)
 	_, err := executeCommandCapture(t, cmd, "emergency-execute", "echo hello",
 		"-C", h.ProjectDir,
-		"-y",
 		"-j",
 	)
 
@@ -92,89 +90,73 @@ func TestEmergencyCommand_RequiresReason(t *testing.T) {
 	}
 }
 
-func TestEmergencyCommand_RequiresAckWithYes(t *testing.T) {
+func TestEmergencyCommand_RequiresTerminalConfirmation(t *testing.T) {
 	h := testutil.NewHarness(t)
 	resetEmergencyFlags()
+	refused := errors.New("break-glass execution needs a human at a terminal")
+	answerHuman(t, "", refused)
 
 	cmd := newTestEmergencyCmd(h.DBPath)
 	_, err := executeCommandCapture(t, cmd, "emergency-execute", "echo hello",
 		"-C", h.ProjectDir,
 		"-r", "Test reason",
-		"-y",
 		"-j",
 	)
-
-	if err == nil {
-		t.Fatal("expected error when --ack is missing with --yes")
-	}
-	if !strings.Contains(err.Error(), "--ack is required when using --yes") {
-		t.Errorf("unexpected error: %v", err)
-	}
-}
-
-func TestEmergencyCommand_AckMustBeMinLength(t *testing.T) {
-	h := testutil.NewHarness(t)
-	resetEmergencyFlags()
-
-	cmd := newTestEmergencyCmd(h.DBPath)
-	_, err := executeCommandCapture(t, cmd, "emergency-execute", "echo hello",
-		"-C", h.ProjectDir,
-		"-r", "Test reason",
-		"-y",
-		"--ack", "abc",
-		"-j",
-	)
-
-	if err == nil {
-		t.Fatal("expected error when --ack is too short")
-	}
-	if !strings.Contains(err.Error(), "at least 8 characters") {
-		t.Errorf("unexpected error: %v", err)
-	}
-}
-
-func TestEmergencyCommand_AckMustMatch(t *testing.T) {
-	h := testutil.NewHarness(t)
-	resetEmergencyFlags()
-
-	cmd := newTestEmergencyCmd(h.DBPath)
-	_, err := executeCommandCapture(t, cmd, "emergency-execute", "echo hello",
-		"-C", h.ProjectDir,
-		"-r", "Test reason",
-		"-y",
-		"--ack", "12345678",
-		"-j",
-	)
-
-	if err == nil {
-		t.Fatal("expected error when --ack does not match")
+	if !errors.Is(err, refused) {
+		t.Fatalf("expected the unconfirmed execution to be refused, got %v", err)
 	}
-	if !strings.Contains(err.Error(), "does not match") {
-		t.Errorf("unexpected error: %v", err)
+	if incidents, _ := h.DB.ListBreakglassIncidents(h.ProjectDir, false); len(incidents) != 0 {
+		t.Fatalf("an unconfirmed execution must not open an incident, got %v", incidents)
 	}
 }
 
-func TestEmergencyCommand_ExecutesWithValidAck(t *testing.T) {
+func TestEmergencyCommand_OpensBreakglassIncident(t *testing.T) {
 	h := testutil.NewHarness(t)
 	resetEmergencyFlags()
+	asked := answerHuman(t, "Dana", nil)
 
 	command := testutil.TruePath()
 	hash := sha256.Sum256([]byte(command))
 	commandHash := hex.EncodeToString(hash[:])
 
-	cmd := newTestEmergencyCmd(h.DBPath)
-	_, err := executeCommandCapture(t, cmd, "emergency-execute", command,
-		"-C", h.ProjectDir,
-		"-r", "Test emergency execution",
-		"-y",
-		"--ack", commandHash[:8],
-		"--log-dir", h.ProjectDir+"/.slb/logs",
-		"-j",
-	)
+	run := func() (string, error) {
+		resetEmergencyFlags()
+		cmd := newTestEmergencyCmd(h.DBPath)
+		return executeCommandCapture(t, cmd, "emergency-execute", command,
+			"-C", h.ProjectDir,
+			"-r", "Test emergency execution",
+			"--log-dir", t.TempDir(),
+			"-j",
+		)
+	}
 
+	stdout, err := run()
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	var result map[string]any
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("parse JSON: %v\n%s", err, stdout)
+	}
+	if result["actor"] != "Dana" || result["hash"] != commandHash || result["incident_id"] == "" {
+		t.Fatalf("unexpected result: %v", result)
+	}
+	if len(*asked) != 1 {
+		t.Fatalf("expected one terminal confirmation, got %v", *asked)
+	}
+
+	inc, err := h.DB.GetBreakglassIncident(result["incident_id"].(string))
+	if err != nil {
+		t.Fatalf("GetBreakglassIncident: %v", err)
+	}
+	if inc.Actor != "Dana" || inc.ExitCode == nil || *inc.ExitCode != 0 || inc.AcknowledgedAt != nil {
+		t.Fatalf("unexpected incident: %+v", inc)
+	}
+
+	// The break-glass cooldown applies to emergency executions too.
+	if _, err := run(); !errors.Is(err, core.ErrBreakglassCooldown) {
+		t.Fatalf("expected cooldown error, got %v", err)
+	}
 }
 
 func TestEmergencyCommand_Help(t *testing.T) {
@@ -194,11 +176,8 @@ func TestEmergencyCommand_Help(t *testing.T) {
 	if !strings.Contains(stdout, "--reason") {
 		t.Error("expected help to mention '--reason' flag")
 	}
-	if !strings.Contains(stdout, "--yes") {
-		t.Error("expected help to mention '--yes' flag")
-	}
-	if !strings.Contains(stdout, "--ack") {
-		t.Error("expected help to mention '--ack' flag")
+	if strings.Contains(stdout, "--yes") || strings.Contains(stdout, "--ack") {
+		t.Error("expected no way to skip the terminal confirmation")
 	}
 	// Check for "without approval" from Short description
 	if !strings.Contains(stdout, "without approval") {
@@ -1,14 +1,18 @@
 package cli
 
 import (
+	"bufio"
 	"context"
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/Dicklesworthstone/slb/internal/config"
 	"github.com/Dicklesworthstone/slb/internal/core"
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/Dicklesworthstone/slb/internal/integrations"
+	"github.com/Dicklesworthstone/slb/internal/numfmt"
 	"github.com/Dicklesworthstone/slb/internal/output"
 	"github.com/spf13/cobra"
 )
@@ -18,6 +22,7 @@ var (
 	flagExecuteTimeout    int
 	flagExecuteBackground bool
 	flagExecuteLogDir     string
+	flagExecuteAllowDrift bool
 )
 
 func init() {
@@ -29,6 +34,7 @@ func init() {
 	executeCmd.Flags().IntVar(&flagExecuteTimeout, "timeout", 300, "execution timeout in seconds")
 	executeCmd.Flags().BoolVar(&flagExecuteBackground, "background", false, "run in background, return immediately")
 	executeCmd.Flags().StringVar(&flagExecuteLogDir, "log-dir", ".slb/logs", "directory for execution logs")
+	executeCmd.Flags().BoolVar(&flagExecuteAllowDrift, "allow-drift", false, "run despite a changed directory, binary or pinned environment (confirmed at the terminal)")
 	// Reuse Agent Mail notifier builder from approve/reject
 	_ = integrations.NoopNotifier{} // keep import if build tags change
 
@@ -48,6 +54,10 @@ Gate conditions are validated before execution:
 - Approval must not be expired
 - Command hash must match (no tampering)
 - Current pattern policy must not require higher tier
+- The working directory, the executable the command resolves to, and the
+  pinned environment variables (general.pinned_env) must match the ones the
+  request was created with. --allow-drift overrides this after a human
+  confirms at the terminal.
 
 Examples:
   slb execute abc123 --session-id $SESSION_ID
@@ -92,7 +102,19 @@ Examples:
 		}
 
 		// Create executor
-		executor := core.NewExecutor(dbConn, nil).WithNotifier(buildAgentMailNotifier(req.ProjectPath))
+		windows, err := buildExecutionWindows(cfg)
+		if err != nil {
+			return err
+		}
+		isolation, err := buildIsolation(cfg, req.ProjectPath)
+		if err != nil {
+			return err
+		}
+		executor := core.NewExecutor(dbConn, nil).
+			WithNotifier(buildAgentMailNotifier(req.ProjectPath)).
+			WithExecutionWindows(windows).
+			WithIsolation(isolation).
+			WithTierOverrides(agentTierOverrides(cfg))
 
 		// Check if we can execute first
 		canExec, reason := executor.CanExecute(requestID)
@@ -107,10 +129,26 @@ Examples:
 			Timeout:           time.Duration(flagExecuteTimeout) * time.Second,
 			Background:        flagExecuteBackground,
 			LogDir:            flagExecuteLogDir,
-			SuppressOutput:    GetOutput() == "json",
+			SuppressOutput:    isJSONOutput(),
 			CaptureRollback:   cfg.General.EnableRollbackCapture,
 			MaxRollbackSizeMB: cfg.General.MaxRollbackSizeMB,
 		}
+		if wd, err := os.Getwd(); err == nil {
+			opts.Cwd = wd
+		}
+		if flagExecuteAllowDrift {
+			drifts, err := executor.CheckDrift(req, opts)
+			if err != nil {
+				return err
+			}
+			if len(drifts) > 0 {
+				if err := confirmDrift(req, drifts); err != nil {
+					return err
+				}
+				opts.AllowDrift = true
+				opts.DriftAllowedBy = GetActor()
+			}
+		}
 
 		// Execute
 		ctx := context.Background()
@@ -118,12 +156,13 @@ Examples:
 
 		// Build output
 		type executeResult struct {
-			RequestID  string `json:"request_id"`
-			ExitCode   int    `json:"exit_code"`
-			DurationMs int64  `json:"duration_ms"`
-			LogPath    string `json:"log_path"`
-			TimedOut   bool   `json:"timed_out,omitempty"`
-			Error      string `json:"error,omitempty"`
+			RequestID    string   `json:"request_id"`
+			ExitCode     int      `json:"exit_code"`
+			DurationMs   int64    `json:"duration_ms"`
+			LogPath      string   `json:"log_path"`
+			TimedOut     bool     `json:"timed_out,omitempty"`
+			SecretsFound []string `json:"secrets_found,omitempty"`
+			Error        string   `json:"error,omitempty"`
 		}
 
 		resp := executeResult{
@@ -135,6 +174,7 @@ Examples:
 			resp.DurationMs = result.Duration.Milliseconds()
 			resp.LogPath = result.LogPath
 			resp.TimedOut = result.TimedOut
+			resp.SecretsFound = result.SecretsFound
 		}
 
 		if err != nil {
@@ -142,7 +182,7 @@ Examples:
 		}
 
 		out := output.New(output.Format(GetOutput()))
-		if GetOutput() == "json" {
+		if isJSONOutput() {
 			if writeErr := out.Write(resp); writeErr != nil {
 				return writeErr
 			}
@@ -158,11 +198,40 @@ Examples:
 			return err
 		}
 
-		fmt.Printf("Executed request %s\n", requestID)
-		fmt.Printf("Exit code: %d\n", resp.ExitCode)
-		fmt.Printf("Duration: %dms\n", resp.DurationMs)
-		fmt.Printf("Log: %s\n", resp.LogPath)
+		output.Notef(os.Stdout, "Executed request %s\n", requestID)
+		output.Notef(os.Stdout, "Exit code: %d\n", resp.ExitCode)
+		output.Notef(os.Stdout, "Duration: %s\n", numfmt.Millis(resp.DurationMs))
+		output.Notef(os.Stdout, "Log: %s\n", resp.LogPath)
+		warnSecretsFound(resp.SecretsFound)
 
 		return nil
 	},
 }
+
+// confirmDrift asks the human at the terminal to allow a drifted execution.
+// It is a variable so tests can answer it.
+var confirmDrift = confirmDriftOnTTY
+
+// confirmDriftOnTTY lists the drift and requires DRIFT to be typed at the
+// controlling terminal, so an agent cannot allow drift for itself.
+func confirmDriftOnTTY(request *db.Request, drifts []core.Drift) error {
+	in, out, closeTTY, err := openTTY()
+	if err != nil {
+		return fmt.Errorf("--allow-drift needs a human at a terminal: %w", err)
+	}
+	defer closeTTY()
+
+	fmt.Fprintf(out, "Request %s would run in a different environment than it was created in:\n", request.ID)
+	for _, d := range drifts {
+		fmt.Fprintf(out, "  %s\n", d)
+	}
+	fmt.Fprint(out, "Type DRIFT to run it anyway: ")
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("reading confirmation: %w", err)
+	}
+	if strings.TrimSpace(answer) != "DRIFT" {
+		return fmt.Errorf("drift not allowed")
+	}
+	return nil
+}
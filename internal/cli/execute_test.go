@@ -2,9 +2,11 @@ package cli
 
 import (
 	"encoding/json"
+	"errors"
 	"strings"
 	"testing"
 
+	"github.com/Dicklesworthstone/slb/internal/core"
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/Dicklesworthstone/slb/internal/testutil"
 	"github.com/spf13/cobra"
@@ -38,6 +40,7 @@ func newTestExecuteCmd(dbPath string) *cobra.Command {
 	execCmd.Flags().IntVar(&flagExecuteTimeout, "timeout", 300, "timeout seconds")
 	execCmd.Flags().BoolVar(&flagExecuteBackground, "background", false, "run in background")
 	execCmd.Flags().StringVar(&flagExecuteLogDir, "log-dir", ".slb/logs", "log directory")
+	execCmd.Flags().BoolVar(&flagExecuteAllowDrift, "allow-drift", false, "allow drift")
 
 	root.AddCommand(execCmd)
 
@@ -55,6 +58,7 @@ func resetExecuteFlags() {
 	flagExecuteTimeout = 300
 	flagExecuteBackground = false
 	flagExecuteLogDir = ".slb/logs"
+	flagExecuteAllowDrift = false
 }
 
 func TestExecuteCommand_RequiresRequestID(t *testing.T) {
@@ -155,6 +159,7 @@ func TestExecuteCommand_ExecutesApprovedRequest(t *testing.T) {
 	cmd := newTestExecuteCmd(h.DBPath)
 	stdout, err := executeCommandCapture(t, cmd, "execute", req.ID,
 		"--session-id", sess.ID,
+		"--log-dir", t.TempDir(),
 		"-j",
 	)
 
@@ -293,6 +298,7 @@ func TestExecuteCommand_CustomTimeout(t *testing.T) {
 	stdout, err := executeCommandCapture(t, cmd, "execute", req.ID,
 		"--session-id", sess.ID,
 		"--timeout", "10", // Short timeout (-t is now persistent --toon)
+		"--log-dir", t.TempDir(),
 		"-j",
 	)
 
@@ -309,3 +315,68 @@ func TestExecuteCommand_CustomTimeout(t *testing.T) {
 		t.Errorf("expected exit_code=0, got %v", result["exit_code"])
 	}
 }
+
+func TestExecuteCommand_AllowDrift(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetExecuteFlags()
+	t.Chdir(h.ProjectDir)
+	t.Setenv("KUBECONFIG", "/home/me/.kube/prod")
+	t.Setenv("SLB_ACTOR", "alice")
+
+	sess := testutil.MakeSession(t, h.DB,
+		testutil.WithProject(h.ProjectDir),
+		testutil.WithAgent("TestAgent"),
+	)
+	req := testutil.MakeRequest(t, h.DB, sess,
+		testutil.WithCommand(testutil.TruePath(), h.ProjectDir, true),
+	)
+	req.Command.Hash = db.ComputeCommandHash(req.Command)
+	h.DB.Exec(`UPDATE requests SET command_hash = ? WHERE id = ?`, req.Command.Hash, req.ID)
+	h.DB.UpdateRequestStatus(req.ID, db.StatusApproved)
+	pin := core.PinEnvironment(h.ProjectDir, []string{"KUBECONFIG=/home/me/.kube/staging"}, []string{"KUBECONFIG"})
+	pin.RequestID = req.ID
+	if err := h.DB.SetRequestEnvironment(pin); err != nil {
+		t.Fatal(err)
+	}
+
+	var confirmed []core.Drift
+	answer := errors.New("drift not allowed")
+	orig := confirmDrift
+	confirmDrift = func(_ *db.Request, drifts []core.Drift) error {
+		confirmed = drifts
+		return answer
+	}
+	t.Cleanup(func() { confirmDrift = orig })
+
+	run := func(args ...string) error {
+		resetExecuteFlags()
+		cmd := newTestExecuteCmd(h.DBPath)
+		_, err := executeCommandCapture(t, cmd, append([]string{"execute", req.ID, "--session-id", sess.ID, "-j"}, args...)...)
+		return err
+	}
+
+	if err := run(); err == nil || !strings.Contains(err.Error(), "--allow-drift") {
+		t.Fatalf("expected a drift refusal, got %v", err)
+	}
+	if confirmed != nil {
+		t.Fatal("confirmation asked for without --allow-drift")
+	}
+	if err := run("--allow-drift"); err == nil || !strings.Contains(err.Error(), "not allowed") {
+		t.Fatalf("expected the declined confirmation, got %v", err)
+	}
+	if len(confirmed) != 1 || confirmed[0].Kind != "env:KUBECONFIG" {
+		t.Fatalf("confirmed drifts = %v", confirmed)
+	}
+
+	answer = nil
+	if err := run("--allow-drift"); err != nil {
+		t.Fatalf("execute with allowed drift: %v", err)
+	}
+	if updated, _ := h.DB.GetRequest(req.ID); updated.Status != db.StatusExecuted {
+		t.Errorf("status = %s, want executed", updated.Status)
+	}
+	drifts, err := h.DB.ListExecutionDrifts(req.ID)
+	if err != nil || len(drifts) != 2 || drifts[1].AllowedBy != "alice" {
+		t.Errorf("drifts = %+v, %v", drifts, err)
+	}
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/daemon"
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// Exit codes scripts and hooks can rely on. They describe slb's own
+// outcome; once slb has run a command, the command's exit status is passed
+// through instead.
+const (
+	exitOK    = 0
+	exitError = 1
+	// exitNeedsApproval: the command was not run because it needs (or is
+	// still waiting for) approval.
+	exitNeedsApproval = 2
+	exitRejected      = 3
+	// exitTimeout: no decision arrived in time.
+	exitTimeout = 4
+	// exitConfig: the configuration could not be read or is invalid.
+	exitConfig = 5
+	// exitDaemonUnreachable: the daemon did not answer, or failed its
+	// health check.
+	exitDaemonUnreachable = 6
+	exitCancelled         = 7
+)
+
+// codedError carries the exit code for err. A nil err still fails the
+// command, e.g. to pass through an executed command's status.
+type codedError struct {
+	code int
+	err  error
+}
+
+func (e *codedError) Error() string {
+	if e.err == nil {
+		return fmt.Sprintf("exit status %d", e.code)
+	}
+	return e.err.Error()
+}
+
+func (e *codedError) Unwrap() error { return e.err }
+
+// withExitCode makes the command exit with code, returning err from RunE.
+// With exitOK, err is returned as is.
+func withExitCode(code int, err error) error {
+	if code == exitOK {
+		return err
+	}
+	return &codedError{code: code, err: err}
+}
+
+// ExitCode maps an error returned by Execute to the process exit code.
+func ExitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+	var coded *codedError
+	if errors.As(err, &coded) {
+		return coded.code
+	}
+	var cfgErr *config.LoadError
+	if errors.As(err, &cfgErr) {
+		return exitConfig
+	}
+	if errors.Is(err, daemon.ErrDaemonUnreachable) {
+		return exitDaemonUnreachable
+	}
+	return exitError
+}
+
+// statusExitCode is the exit code for a request left in status without
+// slb running its command.
+func statusExitCode(status db.RequestStatus) int {
+	switch status {
+	case db.StatusPending, db.StatusEscalated:
+		return exitNeedsApproval
+	case db.StatusRejected:
+		return exitRejected
+	case db.StatusTimeout, db.StatusTimedOut:
+		return exitTimeout
+	case db.StatusCancelled:
+		return exitCancelled
+	case db.StatusExecutionFailed:
+		return exitError
+	default:
+		return exitOK
+	}
+}
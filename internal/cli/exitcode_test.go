@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/daemon"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestExitCode(t *testing.T) {
+	badConfig := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(badConfig, []byte("general = ["), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, cfgErr := config.Load(config.LoadOptions{ProjectDir: t.TempDir(), ConfigPath: badConfig})
+	if cfgErr == nil {
+		t.Fatal("expected an invalid config file to fail to load")
+	}
+
+	client := daemon.NewIPCClient(filepath.Join(t.TempDir(), "missing.sock"))
+	daemonErr := client.Connect(t.Context())
+	if daemonErr == nil {
+		t.Fatal("expected connecting to a missing socket to fail")
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, 0},
+		{"generic", errors.New("boom"), 1},
+		{"needs approval", withExitCode(exitNeedsApproval, nil), 2},
+		{"rejected", withExitCode(exitRejected, errors.New("rejected")), 3},
+		{"timeout", withExitCode(exitTimeout, nil), 4},
+		{"config", fmt.Errorf("loading config: %w", cfgErr), 5},
+		{"daemon unreachable", daemonErr, 6},
+		{"daemon not running", daemon.NewClient(
+			daemon.WithPIDFile(filepath.Join(t.TempDir(), "slb.pid")),
+			daemon.WithSocketPath(filepath.Join(t.TempDir(), "slb.sock")),
+		).MustHaveDaemon(), 6},
+		{"passed through", withExitCode(42, nil), 42},
+		{"ok keeps error", withExitCode(exitOK, errors.New("boom")), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+
+	if err := withExitCode(exitTimeout, nil); err.Error() != "exit status 4" {
+		t.Errorf("bare coded error = %q", err.Error())
+	}
+}
+
+func TestStatusExitCode(t *testing.T) {
+	for status, want := range map[db.RequestStatus]int{
+		db.StatusPending:         exitNeedsApproval,
+		db.StatusEscalated:       exitNeedsApproval,
+		db.StatusApproved:        exitOK,
+		db.StatusExecuted:        exitOK,
+		db.StatusRejected:        exitRejected,
+		db.StatusTimeout:         exitTimeout,
+		db.StatusTimedOut:        exitTimeout,
+		db.StatusCancelled:       exitCancelled,
+		db.StatusExecutionFailed: exitError,
+	} {
+		if got := statusExitCode(status); got != want {
+			t.Errorf("statusExitCode(%s) = %d, want %d", status, got, want)
+		}
+	}
+}
+
+func TestExitCode_TestCommands(t *testing.T) {
+	h := testutil.NewHarness(t)
+	t.Cleanup(func() {
+		resetPatternsFlags()
+		resetHookFlags()
+	})
+
+	resetPatternsFlags()
+	_, err := executeCommandCapture(t, newTestPatternsCmd(h.DBPath), "patterns", "test", "--exit-code", "rm -rf /", "-j")
+	if got := ExitCode(err); got != exitNeedsApproval {
+		t.Errorf("patterns test --exit-code on a critical command: exit %d (%v), want %d", got, err, exitNeedsApproval)
+	}
+	resetPatternsFlags()
+	_, err = executeCommandCapture(t, newTestPatternsCmd(h.DBPath), "patterns", "test", "--exit-code", "echo hello", "-j")
+	if got := ExitCode(err); got != exitOK {
+		t.Errorf("patterns test --exit-code on a safe command: exit %d (%v), want 0", got, err)
+	}
+
+	resetHookFlags()
+	_, err = executeCommandCapture(t, newTestHookCmd(h.DBPath), "hook", "test", "--exit-code", "rm -rf node_modules", "-j")
+	if got := ExitCode(err); got != exitNeedsApproval {
+		t.Errorf("hook test --exit-code on a dangerous command: exit %d (%v), want %d", got, err, exitNeedsApproval)
+	}
+	resetHookFlags()
+	_, err = executeCommandCapture(t, newTestHookCmd(h.DBPath), "hook", "test", "rm -rf node_modules", "-j")
+	if got := ExitCode(err); got != exitOK {
+		t.Errorf("hook test without --exit-code: exit %d (%v), want 0", got, err)
+	}
+}
+
+func TestExitCode_StatusWait(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetStatusFlags()
+	t.Cleanup(resetStatusFlags)
+	t.Setenv("HOME", t.TempDir())
+
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	req := testutil.MakeRequest(t, h.DB, sess, testutil.WithRisk(db.RiskTierDangerous))
+	h.DB.UpdateRequestStatus(req.ID, db.StatusRejected)
+
+	_, err := executeCommandCapture(t, newTestStatusCmd(h.DBPath), "status", req.ID, "--wait", "-C", h.ProjectDir, "-j")
+	if got := ExitCode(err); got != exitRejected {
+		t.Errorf("status --wait on a rejected request: exit %d (%v), want %d", got, err, exitRejected)
+	}
+}
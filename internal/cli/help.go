@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/Dicklesworthstone/slb/internal/utils"
 	"github.com/charmbracelet/lipgloss"
 	"golang.org/x/term"
 )
@@ -103,6 +104,7 @@ func showQuickReference() {
 		bullet("slb run \"rm -rf ./build\" -s $SID --reason \"Cleanup\" --timeout 300 -j", "classify, request approval, wait, then execute"),
 		bullet("slb status <request-id> --wait -j", "block until approved/rejected/timeout"),
 		bullet("slb execute <request-id> --session-id $SID -j", "execute once approved (client-side)"),
+		bullet("slb accept-edit <edit-id> -s $SID -k $SKEY -j", "take a reviewer's corrected command"),
 	})
 
 	plumbing := renderSection(useUnicode, "🔧 PLUMBING (advanced)", []string{
@@ -112,15 +114,20 @@ func showQuickReference() {
 	})
 
 	reviewer := renderSection(useUnicode, "🔷 AS REVIEWER (check frequently)", []string{
+		bullet("slb next -s $SID -j", "your requests, reviews waiting on you, and what to run next"),
 		bullet("slb pending -j", "list pending approvals"),
 		bullet("slb review <id> -j", "inspect details"),
 		bullet("slb approve <id> --session-id $SID -k $SKEY --reason-response \"Verified\"", "approve (signed)"),
 		bullet("slb reject <id> --session-id $SID -k $SKEY --reason \"Need safer path\"", "reject (signed)"),
+		bullet("slb approve <id> --session-id $SID -k $SKEY --edit \"<fixed command>\"", "approve a corrected command instead"),
 	})
 
 	patterns := renderSection(useUnicode, "🛡️ PATTERNS (agents can add, not remove)", []string{
 		bullet("slb patterns add --tier critical \"^helm upgrade.*--force\" --reason \"Avoid outages\"", "tighten safety net"),
 		bullet("slb patterns list --json", "see current patterns and tiers"),
+		bullet("slb patterns import team.json --dry-run", "preview merging a shared bundle"),
+		bullet("slb policy test scenarios.toml --policy candidate.toml", "try a policy change first"),
+		bullet("slb config apply staged.toml --reason \"...\"", "route a config change through approval"),
 	})
 
 	tiers := tierLegend(useUnicode)
@@ -166,6 +173,9 @@ func detectWidth() int {
 }
 
 func supportsUnicode() bool {
+	if utils.PlainOutput() {
+		return false
+	}
 	termEnv := strings.ToLower(os.Getenv("TERM"))
 	locale := strings.ToLower(strings.Join([]string{
 		os.Getenv("LC_ALL"),
@@ -7,6 +7,7 @@ import (
 
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
 	"github.com/spf13/cobra"
 )
 
@@ -17,6 +18,8 @@ var (
 	flagHistoryTier   string
 	flagHistorySince  string
 	flagHistoryLimit  int
+	flagHistoryLabels []string
+	flagHistoryCursor string
 )
 
 func init() {
@@ -26,6 +29,8 @@ func init() {
 	historyCmd.Flags().StringVar(&flagHistoryTier, "tier", "", "filter by risk tier (safe, caution, dangerous, critical)")
 	historyCmd.Flags().StringVar(&flagHistorySince, "since", "", "only show requests after this date (RFC3339 or YYYY-MM-DD)")
 	historyCmd.Flags().IntVar(&flagHistoryLimit, "limit", 50, "max results to return")
+	historyCmd.Flags().StringArrayVar(&flagHistoryLabels, "label", nil, "filter by label (key=value or key; repeatable)")
+	historyCmd.Flags().StringVar(&flagHistoryCursor, "cursor", "", "continue after this cursor (printed when more results exist)")
 
 	rootCmd.AddCommand(historyCmd)
 }
@@ -41,48 +46,48 @@ Examples:
   slb history --status executed        # Show only executed requests
   slb history --tier critical          # Show only critical tier requests
   slb history --agent "BrownStone"     # Show requests from specific agent
-  slb history --since 2025-12-01       # Show requests since date`,
+  slb history --since 2025-12-01       # Show requests since date
+  slb history --label team=infra       # Show requests labeled team=infra
+  slb history --cursor <token>         # Show the next page of results
+
+When more results exist than --limit, the cursor for the next page is
+printed to stderr.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		query, err := historyPageQuery()
+		if err != nil {
+			return err
+		}
+
 		dbConn, err := db.Open(GetDB())
 		if err != nil {
 			return fmt.Errorf("opening database: %w", err)
 		}
 		defer dbConn.Close()
 
-		var requests []*db.Request
-
-		// If query is provided, use FTS search
-		if flagHistoryQuery != "" {
-			requests, err = dbConn.SearchRequests(flagHistoryQuery)
-			if err != nil {
-				return fmt.Errorf("searching requests: %w", err)
-			}
-		} else {
-			// Use filtered listing
-			requests, err = listRequestsWithFilters(dbConn)
-			if err != nil {
-				return fmt.Errorf("listing requests: %w", err)
-			}
+		requests, next, err := dbConn.ListRequestsPage(query)
+		if err != nil {
+			return fmt.Errorf("listing requests: %w", err)
 		}
-
-		// Apply additional filters
-		requests = applyHistoryFilters(requests)
-
-		// Limit results
-		if len(requests) > flagHistoryLimit {
-			requests = requests[:flagHistoryLimit]
+		if err := dbConn.LoadRequestLabels(requests); err != nil {
+			return err
+		}
+		if next != nil {
+			// stdout stays a plain list; the cursor for the next page goes to stderr.
+			fmt.Fprintf(cmd.ErrOrStderr(), "more results: slb history --cursor %s\n", next)
 		}
 
 		// Build response
 		type historyView struct {
-			RequestID      string `json:"request_id"`
-			Command        string `json:"command"`
-			RiskTier       string `json:"risk_tier"`
-			Status         string `json:"status"`
-			RequestorAgent string `json:"requestor_agent"`
-			ProjectPath    string `json:"project_path"`
-			CreatedAt      string `json:"created_at"`
-			ResolvedAt     string `json:"resolved_at,omitempty"`
+			RequestID      string            `json:"request_id"`
+			Command        string            `json:"command"`
+			RiskTier       string            `json:"risk_tier"`
+			Status         string            `json:"status"`
+			RequestorAgent string            `json:"requestor_agent"`
+			ProjectPath    string            `json:"project_path"`
+			CreatedAt      string            `json:"created_at"`
+			ResolvedAt     string            `json:"resolved_at,omitempty"`
+			Labels         map[string]string `json:"labels,omitempty"`
+			Import         *db.RequestImport `json:"import,omitempty"`
 		}
 
 		resp := make([]historyView, 0, len(requests))
@@ -94,14 +99,16 @@ Examples:
 				Status:         string(r.Status),
 				RequestorAgent: r.RequestorAgent,
 				ProjectPath:    r.ProjectPath,
-				CreatedAt:      r.CreatedAt.Format(time.RFC3339),
+				CreatedAt:      timefmt.Format(r.CreatedAt),
+				Labels:         r.Labels,
+				Import:         r.Import,
 			}
 			// Use redacted version for display if available
 			if r.Command.DisplayRedacted != "" {
 				view.Command = r.Command.DisplayRedacted
 			}
 			if r.ResolvedAt != nil {
-				view.ResolvedAt = r.ResolvedAt.Format(time.RFC3339)
+				view.ResolvedAt = timefmt.Format(*r.ResolvedAt)
 			}
 			resp = append(resp, view)
 		}
@@ -111,76 +118,45 @@ Examples:
 	},
 }
 
-// listRequestsWithFilters retrieves requests with basic filtering.
-// For now this returns all requests - we could add more DB-level filtering.
-func listRequestsWithFilters(dbConn *db.DB) ([]*db.Request, error) {
-	project, _ := projectPath()
-
-	// If status filter is set, use status-based listing
-	if flagHistoryStatus != "" {
-		status := db.RequestStatus(flagHistoryStatus)
-		return dbConn.ListRequestsByStatus(status, project)
-	}
-
-	// Otherwise get all pending (and use in-memory filtering)
-	// Note: This is a simplification - in production we'd want a more flexible DB query
-	pending, err := dbConn.ListPendingRequests(project)
+// historyPageQuery builds the page query from the history flags.
+func historyPageQuery() (db.RequestPageQuery, error) {
+	labels, err := db.ParseLabelFilters(flagHistoryLabels)
 	if err != nil {
-		return nil, err
+		return db.RequestPageQuery{}, fmt.Errorf("parsing --label: %w", err)
 	}
-
-	// Also get from FTS with wildcard to get all
-	// Note: This is a workaround - ideally we'd have a ListAllRequests function
-	all, err := dbConn.SearchRequests("*")
-	if err != nil {
-		// FTS might not work with wildcard, fall back to pending only
-		return pending, nil
+	query := db.RequestPageQuery{
+		Search: flagHistoryQuery,
+		Status: db.RequestStatus(flagHistoryStatus),
+		Tier:   db.RiskTier(flagHistoryTier),
+		Agent:  flagHistoryAgent,
+		Since:  parseHistorySince(flagHistorySince),
+		Labels: labels,
+		Limit:  flagHistoryLimit,
 	}
-
-	return all, nil
-}
-
-// applyHistoryFilters applies in-memory filters to requests.
-func applyHistoryFilters(requests []*db.Request) []*db.Request {
-	result := make([]*db.Request, 0, len(requests))
-
-	var sinceTime time.Time
-	if flagHistorySince != "" {
-		// Try parsing as RFC3339 first, then as date only
-		var err error
-		sinceTime, err = time.Parse(time.RFC3339, flagHistorySince)
+	if project, err := projectPath(); err == nil {
+		query.ProjectPath = project
+	}
+	if flagHistoryCursor != "" {
+		cursor, err := db.ParseRequestCursor(flagHistoryCursor)
 		if err != nil {
-			sinceTime, err = time.Parse("2006-01-02", flagHistorySince)
-			if err != nil {
-				// Invalid date, ignore filter
-				sinceTime = time.Time{}
-			}
+			return db.RequestPageQuery{}, fmt.Errorf("parsing --cursor: %w", err)
 		}
+		query.After = cursor
 	}
+	return query, nil
+}
 
-	for _, r := range requests {
-		// Filter by status
-		if flagHistoryStatus != "" && string(r.Status) != flagHistoryStatus {
-			continue
-		}
-
-		// Filter by agent
-		if flagHistoryAgent != "" && r.RequestorAgent != flagHistoryAgent {
-			continue
-		}
-
-		// Filter by tier
-		if flagHistoryTier != "" && string(r.RiskTier) != flagHistoryTier {
-			continue
-		}
-
-		// Filter by since
-		if !sinceTime.IsZero() && r.CreatedAt.Before(sinceTime) {
-			continue
-		}
-
-		result = append(result, r)
+// parseHistorySince parses --since as RFC3339 or a date. Invalid values are
+// ignored (zero time).
+func parseHistorySince(value string) time.Time {
+	if value == "" {
+		return time.Time{}
 	}
-
-	return result
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t
+	}
+	return time.Time{}
 }
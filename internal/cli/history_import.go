@@ -0,0 +1,211 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/git"
+	"github.com/Dicklesworthstone/slb/internal/numfmt"
+	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagHistoryImportSource string
+	flagHistoryImportFormat string
+	flagHistoryImportDryRun bool
+)
+
+func init() {
+	historyImportCmd.Flags().StringVar(&flagHistoryImportSource, "source", "", "name of the process the records come from, tagged on every imported request (required)")
+	historyImportCmd.Flags().StringVar(&flagHistoryImportFormat, "format", "", "csv or json (default: from the file extension)")
+	historyImportCmd.Flags().BoolVar(&flagHistoryImportDryRun, "dry-run", false, "check the records without importing them")
+	historyImportCmd.Flags().StringVar(&flagHistoryRepoPath, "repo", "", "history repo path (default: history.git_repo_path)")
+
+	historyCmd.AddCommand(historyImportCmd)
+}
+
+var historyImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import approval records from a previous process",
+	Long: `Import approval records kept by a previous (often homegrown) process, so
+the audit trail continues across the move to slb.
+
+Records are a JSON array of objects, or CSV with a header row, using these
+fields:
+
+  id            the record's ID in the old process (required)
+  requested_at  when it was requested, RFC 3339 (required)
+  requested_by  who asked for it
+  command       the command (required)
+  cwd           where it ran (default: the project)
+  reason        the justification
+  tier          critical, dangerous, caution or safe (default: classified now)
+  status        executed, execution_failed, rejected, cancelled or timed_out (required)
+  approved_by   approvers (a JSON list, or ';'-separated in CSV)
+  rejected_by   rejecters, likewise
+  reviewed_at   when they decided (default: requested_at)
+  comment       the reviewers' comment
+  executed_at   when it ran
+  exit_code     how it exited
+
+Each record becomes a finished request tagged with --source and its ID, with
+its original times, unsigned reviews, and a timeline. Requests that are
+imported can never be executed. Every record is checked first; if any is
+invalid nothing is imported. Records imported before from the same source
+are skipped, so an import can be rerun.
+
+When a history repo is configured (history.git_repo_path or --repo), the
+imported requests, reviews and executions are committed to it too, with
+commit subjects naming the source.
+
+Examples:
+  slb history import change-board.csv --source change-board --dry-run
+  slb history import approvals.json --source ops-sheet`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := args[0]
+		format := flagHistoryImportFormat
+		if format == "" {
+			format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("reading records: %w", err)
+		}
+		records, err := core.ParseHistoryRecords(data, format)
+		if err != nil {
+			return err
+		}
+		project, err := projectPath()
+		if err != nil {
+			return err
+		}
+		if _, err := loadCustomPatternsIntoDefaultEngine(); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		}
+
+		dbConn, err := db.OpenAndMigrate(GetDB())
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer dbConn.Close()
+
+		entries, err := core.ImportHistory(dbConn, records, core.HistoryImportOptions{
+			Source:      flagHistoryImportSource,
+			ProjectPath: project,
+			DryRun:      flagHistoryImportDryRun,
+		})
+		if err != nil && entries == nil {
+			return err
+		}
+		importErr := err
+
+		counts := make(map[string]int)
+		var imported []string
+		for _, e := range entries {
+			counts[e.Status]++
+			if e.Status == core.HistoryImported {
+				imported = append(imported, e.RequestID)
+			}
+		}
+
+		var repoPath string
+		var committed int
+		if len(imported) > 0 {
+			repo, err := historyRepoFromFlags()
+			switch {
+			case errors.Is(err, errNoHistoryRepo):
+			case err != nil:
+				return err
+			default:
+				repoPath = repo.Path
+				if committed, err = commitImportedRequests(dbConn, repo, imported); err != nil {
+					return fmt.Errorf("committing to history repo: %w", err)
+				}
+			}
+		}
+
+		if isJSONOutput() {
+			if err := output.New(output.Format(GetOutput())).Write(map[string]any{
+				"file":         path,
+				"source":       flagHistoryImportSource,
+				"dry_run":      flagHistoryImportDryRun,
+				"imported":     counts[core.HistoryImported],
+				"new":          counts[core.HistoryNew],
+				"existing":     counts[core.HistoryExists],
+				"invalid":      counts[core.HistoryInvalid],
+				"history_repo": repoPath,
+				"committed":    committed,
+				"records":      entries,
+			}); err != nil {
+				return err
+			}
+		} else {
+			for _, e := range entries {
+				line := fmt.Sprintf("%-8s  %-20s  %s", e.Status, e.ExternalID, e.RequestID)
+				if e.Error != "" {
+					line = fmt.Sprintf("%-8s  %-20s  %s", e.Status, e.ExternalID, e.Error)
+				}
+				fmt.Println(strings.TrimRight(line, " "))
+			}
+			invalid := counts[core.HistoryInvalid]
+			if flagHistoryImportDryRun || invalid > 0 {
+				output.Notef(os.Stdout, "Would import %s record(s) from %s: %s already imported, %s invalid\n",
+					numfmt.Count(counts[core.HistoryNew]), path, numfmt.Count(counts[core.HistoryExists]), numfmt.Count(invalid))
+			} else {
+				output.Notef(os.Stdout, "Imported %s record(s) from %s as source %s: %s already imported\n",
+					numfmt.Count(counts[core.HistoryImported]), path, flagHistoryImportSource, numfmt.Count(counts[core.HistoryExists]))
+			}
+			if repoPath != "" {
+				output.Notef(os.Stdout, "Committed %s artifact(s) to %s\n", numfmt.Count(committed), repoPath)
+			}
+		}
+
+		if importErr != nil {
+			return importErr
+		}
+		if n := counts[core.HistoryInvalid]; n > 0 {
+			return fmt.Errorf("%d invalid record(s) in %s; nothing imported", n, path)
+		}
+		return nil
+	},
+}
+
+// commitImportedRequests commits the request, review and execution
+// snapshots of freshly imported requests to the history repo, in the order
+// of the imported file. It returns how many commits were made.
+func commitImportedRequests(dbConn *db.DB, repo *git.HistoryRepo, requestIDs []string) (int, error) {
+	var commits int
+	count := func(committed bool, _ string, err error) error {
+		if committed {
+			commits++
+		}
+		return err
+	}
+	for _, id := range requestIDs {
+		req, reviews, err := dbConn.GetRequestWithReviews(id)
+		if err != nil {
+			return commits, err
+		}
+		if err := count(repo.CommitRequest(req)); err != nil {
+			return commits, err
+		}
+		for _, rev := range reviews {
+			if err := count(repo.CommitReview(rev)); err != nil {
+				return commits, err
+			}
+		}
+		if req.Execution != nil {
+			if err := count(repo.CommitExecution(req.ID, req.Execution)); err != nil {
+				return commits, err
+			}
+		}
+	}
+	return commits, nil
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestHistoryImport(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	h := testutil.NewHarness(t)
+	repo := filepath.Join(t.TempDir(), "history")
+	file := filepath.Join(t.TempDir(), "approvals.csv")
+	testutil.RequireNoError(t, os.WriteFile(file, []byte(`id,requested_at,requested_by,command,reason,status,approved_by,executed_at,exit_code
+CHG-1,2024-03-01T14:05:00Z,alice,rm -rf ./build,clean build,executed,bob,2024-03-01T15:00:00Z,0
+CHG-2,2024-03-02T09:00:00Z,dave,git push --force,hotfix,cancelled,,,
+`), 0600), "write records")
+
+	importRecords := func(args ...string) (string, error) {
+		t.Helper()
+		resetHistoryRepoFlags()
+		args = append([]string{"history", "import", file, "-C", h.ProjectDir, "--source", "change-board"}, args...)
+		return executeCommandCapture(t, newTestHistoryRepoCmd(h.DBPath), args...)
+	}
+
+	stdout, err := importRecords("--dry-run")
+	testutil.RequireNoError(t, err, "dry run")
+	if !strings.Contains(stdout, "Would import 2 record(s)") {
+		t.Errorf("dry run output:\n%s", stdout)
+	}
+
+	stdout, err = importRecords("--repo", repo, "-j")
+	testutil.RequireNoError(t, err, "import")
+	var resp struct {
+		Imported  int `json:"imported"`
+		Committed int `json:"committed"`
+		Records   []struct {
+			ExternalID string `json:"external_id"`
+			RequestID  string `json:"request_id"`
+			Status     string `json:"status"`
+		} `json:"records"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &resp); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	// Two requests, one review and one execution.
+	if resp.Imported != 2 || resp.Committed != 4 || len(resp.Records) != 2 {
+		t.Fatalf("unexpected response: %s", stdout)
+	}
+
+	req, err := h.DB.GetRequest(resp.Records[0].RequestID)
+	testutil.RequireNoError(t, err, "get imported request")
+	if req.Import == nil || req.Import.Source != "change-board" || req.Import.ExternalID != "CHG-1" {
+		t.Errorf("import tag = %+v", req.Import)
+	}
+
+	log, err := exec.Command("git", "-C", repo, "log", "--format=%s").Output()
+	testutil.RequireNoError(t, err, "git log")
+	if !strings.Contains(string(log), "Import (change-board CHG-1): ") {
+		t.Errorf("history repo log:\n%s", log)
+	}
+
+	// The committed snapshots match the database, and a rerun adds nothing.
+	resetHistoryRepoFlags()
+	stdout, err = executeCommandCapture(t, newTestHistoryRepoCmd(h.DBPath), "history", "reconcile", "-C", h.ProjectDir, "--repo", repo)
+	testutil.RequireNoError(t, err, "reconcile")
+	if !strings.Contains(stdout, "0 missing, 0 backfilled, 0 mismatched") {
+		t.Errorf("reconcile output:\n%s", stdout)
+	}
+	stdout, err = importRecords("--repo", repo)
+	testutil.RequireNoError(t, err, "re-import")
+	if !strings.Contains(stdout, "Imported 0 record(s)") || !strings.Contains(stdout, "2 already imported") {
+		t.Errorf("re-import output:\n%s", stdout)
+	}
+}
@@ -0,0 +1,276 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/git"
+	"github.com/Dicklesworthstone/slb/internal/numfmt"
+	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagHistoryRepoPath        string
+	flagHistoryRepoDiff        bool
+	flagHistoryReconcileDryRun bool
+)
+
+func init() {
+	historyRepoCmd.PersistentFlags().StringVar(&flagHistoryRepoPath, "repo", "", "history repo path (default: history.git_repo_path)")
+	historyRepoShowCmd.Flags().BoolVar(&flagHistoryRepoDiff, "diff", false, "compare each artifact with the database record")
+
+	historyReconcileCmd.Flags().StringVar(&flagHistoryRepoPath, "repo", "", "history repo path (default: history.git_repo_path)")
+	historyReconcileCmd.Flags().BoolVar(&flagHistoryReconcileDryRun, "dry-run", false, "report missing artifacts without committing them")
+
+	historyRepoCmd.AddCommand(historyRepoShowCmd)
+	historyCmd.AddCommand(historyRepoCmd)
+	historyCmd.AddCommand(historyReconcileCmd)
+}
+
+var historyRepoCmd = &cobra.Command{
+	Use:   "repo",
+	Short: "Browse the Git history repo",
+}
+
+var historyRepoShowCmd = &cobra.Command{
+	Use:   "show <request-id>",
+	Short: "Show the history repo artifacts committed for a request",
+	Long: `Show the request, review and execution snapshots committed to the history
+repo (history.git_repo_path) for a request, with the commit that last
+touched each one.
+
+With --diff, each committed snapshot is compared field by field with the
+current database record, so a database that diverged from the audit trail
+(or a snapshot that was never updated) stands out.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		requestID := args[0]
+
+		repo, err := historyRepoFromFlags()
+		if err != nil {
+			return err
+		}
+		if !git.IsRepo(repo.Path) {
+			return fmt.Errorf("history repo %s is not a git repository", repo.Path)
+		}
+
+		artifacts, err := repo.RequestArtifacts(requestID)
+		if err != nil {
+			return fmt.Errorf("reading history repo: %w", err)
+		}
+		if len(artifacts) == 0 {
+			return fmt.Errorf("no artifacts for request %s in %s", requestID, repo.Path)
+		}
+
+		type artifactView struct {
+			git.Artifact
+			InDatabase  *bool           `json:"in_database,omitempty"`
+			Diverged    bool            `json:"diverged,omitempty"`
+			Differences []git.FieldDiff `json:"differences,omitempty"`
+		}
+		views := make([]artifactView, len(artifacts))
+		for i, a := range artifacts {
+			views[i] = artifactView{Artifact: a}
+		}
+
+		if flagHistoryRepoDiff {
+			dbConn, err := db.Open(GetDB())
+			if err != nil {
+				return fmt.Errorf("opening database: %w", err)
+			}
+			defer dbConn.Close()
+
+			records, err := historyRepoRecords(dbConn, requestID)
+			if err != nil {
+				return err
+			}
+			for i := range views {
+				record, ok := records[views[i].Kind+"/"+views[i].ID]
+				views[i].InDatabase = &ok
+				if !ok {
+					views[i].Diverged = true
+					continue
+				}
+				diffs, err := git.CompareArtifact(views[i].Content, record)
+				if err != nil {
+					return fmt.Errorf("%s: %w", views[i].Path, err)
+				}
+				views[i].Differences = diffs
+				views[i].Diverged = len(diffs) > 0
+			}
+		}
+
+		if isJSONOutput() {
+			return output.New(output.Format(GetOutput())).Write(map[string]any{
+				"request_id": requestID,
+				"repo":       repo.Path,
+				"artifacts":  views,
+			})
+		}
+
+		fmt.Printf("History repo: %s\n", repo.Path)
+		for _, v := range views {
+			fmt.Printf("\n%-9s  %s\n", v.Kind, v.Path)
+			if v.Commit != nil {
+				fmt.Printf("           commit %s  %s  %s  %s\n", shortHash(v.Commit.Hash), v.Commit.Date.Local().Format(time.DateTime), v.Commit.Author, v.Commit.Subject)
+			} else {
+				fmt.Println("           never committed")
+			}
+			if v.Commit != nil && v.Modified {
+				fmt.Println("           has uncommitted changes")
+			}
+			switch {
+			case v.InDatabase == nil:
+			case !*v.InDatabase:
+				fmt.Println("           not in the database")
+			case !v.Diverged:
+				fmt.Println("           matches the database")
+			default:
+				fmt.Printf("           differs from the database in %d field(s):\n", len(v.Differences))
+				for _, d := range v.Differences {
+					fmt.Printf("             %s: %s -> %s\n", d.Field, historyRepoValue(d.Committed), historyRepoValue(d.Current))
+				}
+			}
+		}
+		return nil
+	},
+}
+
+var historyReconcileCmd = &cobra.Command{
+	Use:   "reconcile",
+	Short: "Backfill missing history repo artifacts for finished requests",
+	Long: `Check that every finished (executed, failed, rejected, cancelled or timed
+out) request in the project has committed request, review and execution
+snapshots in the history repo (history.git_repo_path).
+
+Missing snapshots are written from the database and committed. Snapshots
+that differ from the database, or have uncommitted edits, are reported but
+left alone, and make the command exit non-zero. Use --dry-run to only
+report.
+
+The daemon runs the same backfill every history.reconcile_interval_minutes.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		project, err := projectPath()
+		if err != nil {
+			return err
+		}
+		repo, err := historyRepoFromFlags()
+		if err != nil {
+			return err
+		}
+
+		dbConn, err := db.Open(GetDB())
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer dbConn.Close()
+
+		report, err := repo.Reconcile(dbConn, git.ReconcileOptions{ProjectPath: project, DryRun: flagHistoryReconcileDryRun})
+		if err != nil {
+			return fmt.Errorf("reconciling history repo: %w", err)
+		}
+
+		if isJSONOutput() {
+			if err := output.New(output.Format(GetOutput())).Write(map[string]any{
+				"repo":       repo.Path,
+				"dry_run":    flagHistoryReconcileDryRun,
+				"requests":   report.Requests,
+				"artifacts":  report.Artifacts,
+				"backfilled": report.Backfilled,
+				"mismatched": report.Mismatched,
+				"issues":     report.Issues,
+			}); err != nil {
+				return err
+			}
+		} else {
+			for _, issue := range report.Issues {
+				switch {
+				case issue.Backfilled:
+					fmt.Printf("backfilled  %-9s %s  %s\n", issue.Kind, issue.ID, issue.Path)
+				case issue.Problem == git.ProblemMissing:
+					fmt.Printf("missing     %-9s %s\n", issue.Kind, issue.ID)
+				case issue.Problem == git.ProblemModified:
+					fmt.Printf("modified    %-9s %s  %s has uncommitted edits\n", issue.Kind, issue.ID, issue.Path)
+				default:
+					fmt.Printf("mismatch    %-9s %s  %s\n", issue.Kind, issue.ID, issue.Path)
+					for _, d := range issue.Differences {
+						fmt.Printf("              %s: %s -> %s\n", d.Field, historyRepoValue(d.Committed), historyRepoValue(d.Current))
+					}
+				}
+			}
+			missing := len(report.Issues) - report.Mismatched
+			fmt.Printf("%s finished request(s), %s artifact(s): %s missing, %s backfilled, %s mismatched\n",
+				numfmt.Count(report.Requests), numfmt.Count(report.Artifacts), numfmt.Count(missing),
+				numfmt.Count(report.Backfilled), numfmt.Count(report.Mismatched))
+		}
+
+		if report.Mismatched > 0 {
+			return fmt.Errorf("%d artifact(s) in %s do not match the database", report.Mismatched, repo.Path)
+		}
+		return nil
+	},
+}
+
+// errNoHistoryRepo is returned by historyRepoFromFlags when neither --repo
+// nor history.git_repo_path names a repo.
+var errNoHistoryRepo = errors.New("no history repo configured; set history.git_repo_path or pass --repo")
+
+// historyRepoFromFlags opens the history repo named by --repo, falling back
+// to history.git_repo_path.
+func historyRepoFromFlags() (*git.HistoryRepo, error) {
+	repoPath := flagHistoryRepoPath
+	if repoPath == "" {
+		project, err := projectPath()
+		if err != nil {
+			return nil, err
+		}
+		cfg, err := config.Load(config.LoadOptions{ProjectDir: project, ConfigPath: flagConfig})
+		if err != nil {
+			return nil, fmt.Errorf("loading config: %w", err)
+		}
+		repoPath = cfg.History.GitRepoPath
+	}
+	if repoPath == "" {
+		return nil, errNoHistoryRepo
+	}
+	return git.NewHistoryRepo(repoPath)
+}
+
+// historyRepoRecords returns the database records a request's artifacts
+// snapshot, keyed by artifact kind and ID.
+func historyRepoRecords(dbConn *db.DB, requestID string) (map[string]any, error) {
+	records := make(map[string]any)
+	req, err := dbConn.GetRequest(requestID)
+	if errors.Is(err, db.ErrRequestNotFound) {
+		return records, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting request: %w", err)
+	}
+	records[git.ArtifactRequest+"/"+req.ID] = req
+	if req.Execution != nil {
+		records[git.ArtifactExecution+"/"+req.ID] = req.Execution
+	}
+	reviews, err := dbConn.ListReviewsForRequest(requestID)
+	if err != nil {
+		return nil, fmt.Errorf("listing reviews: %w", err)
+	}
+	for _, rev := range reviews {
+		records[git.ArtifactReview+"/"+rev.ID] = rev
+	}
+	return records, nil
+}
+
+// historyRepoValue renders a field value from a diff, where empty means the
+// field is absent.
+func historyRepoValue(v string) string {
+	if v == "" {
+		return "(missing)"
+	}
+	return v
+}
@@ -0,0 +1,262 @@
+package cli
+
+import (
+	"encoding/json"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/git"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+	"github.com/spf13/cobra"
+)
+
+// newTestHistoryRepoCmd creates a fresh history repo command tree for testing.
+func newTestHistoryRepoCmd(dbPath string) *cobra.Command {
+	root := &cobra.Command{
+		Use:           "slb",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	root.PersistentFlags().StringVar(&flagDB, "db", dbPath, "database path")
+	root.PersistentFlags().StringVarP(&flagOutput, "output", "o", "text", "output format")
+	root.PersistentFlags().BoolVarP(&flagJSON, "json", "j", false, "json output")
+	root.PersistentFlags().StringVarP(&flagProject, "project", "C", "", "project directory")
+	root.PersistentFlags().StringVarP(&flagConfig, "config", "c", "", "config file")
+
+	histCmd := &cobra.Command{Use: "history"}
+	repoCmd := &cobra.Command{Use: "repo"}
+	repoCmd.PersistentFlags().StringVar(&flagHistoryRepoPath, "repo", "", "history repo path")
+	showCmd := &cobra.Command{
+		Use:  "show <request-id>",
+		Args: historyRepoShowCmd.Args,
+		RunE: historyRepoShowCmd.RunE,
+	}
+	showCmd.Flags().BoolVar(&flagHistoryRepoDiff, "diff", false, "compare with the database")
+
+	reconcileCmd := &cobra.Command{
+		Use:  "reconcile",
+		Args: historyReconcileCmd.Args,
+		RunE: historyReconcileCmd.RunE,
+	}
+	reconcileCmd.Flags().StringVar(&flagHistoryRepoPath, "repo", "", "history repo path")
+	reconcileCmd.Flags().BoolVar(&flagHistoryReconcileDryRun, "dry-run", false, "report only")
+
+	importCmd := &cobra.Command{
+		Use:  "import <file>",
+		Args: historyImportCmd.Args,
+		RunE: historyImportCmd.RunE,
+	}
+	importCmd.Flags().StringVar(&flagHistoryImportSource, "source", "", "import source")
+	importCmd.Flags().StringVar(&flagHistoryImportFormat, "format", "", "csv or json")
+	importCmd.Flags().BoolVar(&flagHistoryImportDryRun, "dry-run", false, "check only")
+	importCmd.Flags().StringVar(&flagHistoryRepoPath, "repo", "", "history repo path")
+
+	repoCmd.AddCommand(showCmd)
+	histCmd.AddCommand(repoCmd, reconcileCmd, importCmd)
+	root.AddCommand(histCmd)
+	return root
+}
+
+func resetHistoryRepoFlags() {
+	flagDB = ""
+	flagOutput = "text"
+	flagJSON = false
+	flagProject = ""
+	flagConfig = ""
+	flagHistoryRepoPath = ""
+	flagHistoryRepoDiff = false
+	flagHistoryReconcileDryRun = false
+	flagHistoryImportSource = ""
+	flagHistoryImportFormat = ""
+	flagHistoryImportDryRun = false
+}
+
+type historyRepoShowResponse struct {
+	Repo      string `json:"repo"`
+	Artifacts []struct {
+		Kind   string `json:"kind"`
+		ID     string `json:"id"`
+		Path   string `json:"path"`
+		Commit *struct {
+			Hash    string `json:"hash"`
+			Subject string `json:"subject"`
+		} `json:"commit"`
+		InDatabase  *bool `json:"in_database"`
+		Diverged    bool  `json:"diverged"`
+		Differences []struct {
+			Field     string `json:"field"`
+			Committed string `json:"committed"`
+			Current   string `json:"current"`
+		} `json:"differences"`
+	} `json:"artifacts"`
+}
+
+func TestHistoryRepoShow(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	h := testutil.NewHarness(t)
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir), testutil.WithAgent("Requestor"))
+	created := testutil.MakeRequest(t, h.DB, sess)
+
+	repo, err := git.NewHistoryRepo(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := h.DB.GetRequest(created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := repo.CommitRequest(req); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := repo.CommitReview(&db.Review{ID: "gone", RequestID: req.ID, Decision: db.DecisionApprove, CreatedAt: req.CreatedAt}); err != nil {
+		t.Fatal(err)
+	}
+
+	show := func(args ...string) historyRepoShowResponse {
+		t.Helper()
+		resetHistoryRepoFlags()
+		args = append([]string{"history", "repo", "show", req.ID, "--repo", repo.Path, "-j"}, args...)
+		stdout, err := executeCommandCapture(t, newTestHistoryRepoCmd(h.DBPath), args...)
+		testutil.RequireNoError(t, err, "history repo show")
+		var resp historyRepoShowResponse
+		if err := json.Unmarshal([]byte(stdout), &resp); err != nil {
+			t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+		}
+		return resp
+	}
+
+	resp := show()
+	if resp.Repo != repo.Path || len(resp.Artifacts) != 2 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	a := resp.Artifacts[0]
+	if a.Kind != "request" || a.ID != req.ID || a.Commit == nil || !strings.HasPrefix(a.Commit.Subject, "Request: ") || a.InDatabase != nil {
+		t.Errorf("request artifact = %+v", a)
+	}
+
+	resp = show("--diff")
+	if a := resp.Artifacts[0]; a.InDatabase == nil || !*a.InDatabase || a.Diverged || len(a.Differences) != 0 {
+		t.Errorf("request artifact before the database changed = %+v", a)
+	}
+	if a := resp.Artifacts[1]; a.Kind != "review" || a.ID != "gone" || a.InDatabase == nil || *a.InDatabase || !a.Diverged {
+		t.Errorf("review artifact = %+v", a)
+	}
+
+	if err := h.DB.UpdateRequestStatus(req.ID, db.StatusCancelled); err != nil {
+		t.Fatal(err)
+	}
+	resp = show("--diff")
+	a = resp.Artifacts[0]
+	if !a.Diverged || len(a.Differences) == 0 {
+		t.Fatalf("request artifact after the database changed = %+v", a)
+	}
+	found := false
+	for _, d := range a.Differences {
+		if d.Field == "status" && d.Committed == `"pending"` && d.Current == `"cancelled"` {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected a status difference, got %+v", a.Differences)
+	}
+
+	resetHistoryRepoFlags()
+	stdout, err := executeCommandCapture(t, newTestHistoryRepoCmd(h.DBPath), "history", "repo", "show", req.ID, "--repo", repo.Path, "--diff")
+	testutil.RequireNoError(t, err, "history repo show")
+	for _, want := range []string{"History repo: " + repo.Path, "commit ", "differs from the database", `status: "pending" -> "cancelled"`, "not in the database"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in output:\n%s", want, stdout)
+		}
+	}
+}
+
+func TestHistoryRepoShow_Errors(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	h := testutil.NewHarness(t)
+	repo := t.TempDir()
+	if err := git.InitHistoryRepo(repo); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"not configured", []string{"-C", h.ProjectDir}, "no history repo configured"},
+		{"not a repo", []string{"--repo", t.TempDir()}, "not a git repository"},
+		{"no artifacts", []string{"--repo", repo}, "no artifacts for request"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetHistoryRepoFlags()
+			args := append([]string{"history", "repo", "show", "missing"}, tt.args...)
+			_, err := executeCommandCapture(t, newTestHistoryRepoCmd(h.DBPath), args...)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestHistoryReconcile(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	h := testutil.NewHarness(t)
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir), testutil.WithAgent("Requestor"))
+	done := testutil.MakeRequest(t, h.DB, sess, testutil.WithStatus(db.StatusCancelled))
+	testutil.MakeRequest(t, h.DB, sess)
+	repo := filepath.Join(t.TempDir(), "history")
+
+	reconcile := func(args ...string) (string, error) {
+		t.Helper()
+		resetHistoryRepoFlags()
+		args = append([]string{"history", "reconcile", "-C", h.ProjectDir, "--repo", repo}, args...)
+		return executeCommandCapture(t, newTestHistoryRepoCmd(h.DBPath), args...)
+	}
+
+	stdout, err := reconcile("--dry-run")
+	testutil.RequireNoError(t, err, "dry run")
+	if !strings.Contains(stdout, "missing     request   "+done.ID) || !strings.Contains(stdout, "1 finished request(s), 1 artifact(s): 1 missing, 0 backfilled, 0 mismatched") {
+		t.Errorf("dry run output:\n%s", stdout)
+	}
+
+	stdout, err = reconcile("-j")
+	testutil.RequireNoError(t, err, "reconcile")
+	var resp struct {
+		Requests   int `json:"requests"`
+		Backfilled int `json:"backfilled"`
+		Issues     []struct {
+			ID         string `json:"id"`
+			Path       string `json:"path"`
+			Backfilled bool   `json:"backfilled"`
+		} `json:"issues"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &resp); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if resp.Requests != 1 || resp.Backfilled != 1 || len(resp.Issues) != 1 || resp.Issues[0].ID != done.ID || !resp.Issues[0].Backfilled {
+		t.Fatalf("unexpected response: %s", stdout)
+	}
+
+	// The database drifting from the committed snapshot fails the run.
+	if _, err := h.DB.Exec(`UPDATE requests SET justification_reason = 'edited' WHERE id = ?`, done.ID); err != nil {
+		t.Fatal(err)
+	}
+	stdout, err = reconcile()
+	if err == nil || !strings.Contains(err.Error(), "1 artifact(s)") {
+		t.Fatalf("expected a mismatch error, got %v", err)
+	}
+	if !strings.Contains(stdout, "mismatch    request   "+done.ID) || !strings.Contains(stdout, `justification.reason: "test" -> "edited"`) {
+		t.Errorf("mismatch output:\n%s", stdout)
+	}
+}
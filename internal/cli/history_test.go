@@ -37,6 +37,8 @@ func newTestHistoryCmd(dbPath string) *cobra.Command {
 	histCmd.Flags().StringVar(&flagHistoryTier, "tier", "", "filter by risk tier")
 	histCmd.Flags().StringVar(&flagHistorySince, "since", "", "filter by date")
 	histCmd.Flags().IntVar(&flagHistoryLimit, "limit", 50, "max results")
+	histCmd.Flags().StringArrayVar(&flagHistoryLabels, "label", nil, "filter by label")
+	histCmd.Flags().StringVar(&flagHistoryCursor, "cursor", "", "continue after this cursor")
 
 	root.AddCommand(histCmd)
 
@@ -54,6 +56,8 @@ func resetHistoryFlags() {
 	flagHistoryTier = ""
 	flagHistorySince = ""
 	flagHistoryLimit = 50
+	flagHistoryLabels = nil
+	flagHistoryCursor = ""
 }
 
 func TestHistoryCommand_ListsRequests(t *testing.T) {
@@ -326,117 +330,137 @@ func TestHistoryCommand_Help(t *testing.T) {
 	}
 }
 
-func TestApplyHistoryFilters(t *testing.T) {
-	// Test the in-memory filtering function
-	requests := []*db.Request{
-		{ID: "1", Status: db.StatusPending, RequestorAgent: "Agent1", RiskTier: db.RiskTierDangerous},
-		{ID: "2", Status: db.StatusApproved, RequestorAgent: "Agent2", RiskTier: db.RiskTierCritical},
-		{ID: "3", Status: db.StatusRejected, RequestorAgent: "Agent1", RiskTier: db.RiskTierCritical},
-	}
-
-	tests := []struct {
-		name     string
-		status   string
-		agent    string
-		tier     string
-		expected int
-	}{
-		{"no filters", "", "", "", 3},
-		{"filter by status", "pending", "", "", 1},
-		{"filter by agent", "", "Agent1", "", 2},
-		{"filter by tier", "", "", "critical", 2},
-		{"multiple filters", "approved", "Agent2", "critical", 1},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			flagHistoryStatus = tt.status
-			flagHistoryAgent = tt.agent
-			flagHistoryTier = tt.tier
-			flagHistorySince = ""
-
-			result := applyHistoryFilters(requests)
-			if len(result) != tt.expected {
-				t.Errorf("expected %d results, got %d", tt.expected, len(result))
-			}
-		})
-	}
-
-	// Reset flags
+func TestHistoryPageQuery(t *testing.T) {
 	resetHistoryFlags()
-}
+	defer resetHistoryFlags()
 
-func TestApplyHistoryFilters_SinceDateRFC3339(t *testing.T) {
-	now := time.Now()
-	old := now.Add(-48 * time.Hour)
-	recent := now.Add(-1 * time.Hour)
+	flagProject = "/work/project"
+	flagHistoryQuery = "rm"
+	flagHistoryStatus = "approved"
+	flagHistoryAgent = "Agent2"
+	flagHistoryTier = "critical"
+	flagHistoryLimit = 10
+	flagHistoryLabels = []string{"team=infra", "ticket"}
 
-	requests := []*db.Request{
-		{ID: "1", Status: db.StatusPending, CreatedAt: old},
-		{ID: "2", Status: db.StatusPending, CreatedAt: recent},
+	query, err := historyPageQuery()
+	if err != nil {
+		t.Fatalf("historyPageQuery failed: %v", err)
+	}
+	if query.ProjectPath != "/work/project" || query.Search != "rm" || query.Status != db.StatusApproved ||
+		query.Agent != "Agent2" || query.Tier != db.RiskTierCritical || query.Limit != 10 {
+		t.Errorf("unexpected query: %+v", query)
+	}
+	if len(query.Labels) != 2 || query.Labels[0].Value != "infra" || !query.Labels[1].AnyValue {
+		t.Errorf("unexpected label filters: %+v", query.Labels)
+	}
+	if query.After != nil {
+		t.Errorf("expected no cursor, got %+v", query.After)
 	}
 
-	// Filter to only recent (since 24 hours ago)
-	flagHistoryStatus = ""
-	flagHistoryAgent = ""
-	flagHistoryTier = ""
-	flagHistorySince = now.Add(-24 * time.Hour).Format(time.RFC3339)
-
-	result := applyHistoryFilters(requests)
-	if len(result) != 1 {
-		t.Errorf("expected 1 result with since filter, got %d", len(result))
+	flagHistoryCursor = "not-a-cursor!"
+	if _, err := historyPageQuery(); err == nil {
+		t.Error("expected an invalid --cursor to fail")
 	}
-	if len(result) > 0 && result[0].ID != "2" {
-		t.Errorf("expected request 2, got %s", result[0].ID)
+	flagHistoryCursor = ""
+	flagHistoryLabels = []string{"bad key=x"}
+	if _, err := historyPageQuery(); err == nil {
+		t.Error("expected an invalid --label to fail")
 	}
+}
 
-	resetHistoryFlags()
+func TestParseHistorySince(t *testing.T) {
+	rfc := "2025-12-01T10:30:00Z"
+	if got := parseHistorySince(rfc); !got.Equal(time.Date(2025, 12, 1, 10, 30, 0, 0, time.UTC)) {
+		t.Errorf("parseHistorySince(%q) = %v", rfc, got)
+	}
+	if got := parseHistorySince("2025-12-01"); !got.Equal(time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("parseHistorySince(date) = %v", got)
+	}
+	// Invalid dates are ignored.
+	if got := parseHistorySince("invalid-date"); !got.IsZero() {
+		t.Errorf("expected invalid date to be ignored, got %v", got)
+	}
+	if got := parseHistorySince(""); !got.IsZero() {
+		t.Errorf("expected empty value to be ignored, got %v", got)
+	}
 }
 
-func TestApplyHistoryFilters_SinceDateOnly(t *testing.T) {
-	now := time.Now()
-	old := now.Add(-48 * time.Hour)
-	recent := now.Add(-1 * time.Hour)
+func TestHistoryCommand_FilterBySince(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetHistoryFlags()
 
-	requests := []*db.Request{
-		{ID: "1", Status: db.StatusPending, CreatedAt: old},
-		{ID: "2", Status: db.StatusPending, CreatedAt: recent},
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	old := testutil.MakeRequest(t, h.DB, sess, testutil.WithCommand("echo old", h.ProjectDir, true))
+	testutil.MakeRequest(t, h.DB, sess, testutil.WithCommand("echo recent", h.ProjectDir, true))
+	if _, err := h.DB.Exec(`UPDATE requests SET created_at = ? WHERE id = ?`,
+		time.Now().Add(-48*time.Hour).UTC().Format(time.RFC3339), old.ID); err != nil {
+		t.Fatalf("backdating request: %v", err)
 	}
 
-	// Filter using date-only format
-	flagHistoryStatus = ""
-	flagHistoryAgent = ""
-	flagHistoryTier = ""
-	flagHistorySince = now.Format("2006-01-02")
+	cmd := newTestHistoryCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "history",
+		"-C", h.ProjectDir,
+		"--since", time.Now().Add(-24*time.Hour).UTC().Format(time.RFC3339),
+		"-j",
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 
-	result := applyHistoryFilters(requests)
-	// Both should pass since they're on or after today's date
-	// Actually, the old one is 2 days ago so it should fail
-	if len(result) != 1 {
-		t.Errorf("expected 1 result with date-only since filter, got %d", len(result))
+	var result []map[string]any
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
 	}
+	if len(result) != 1 || result[0]["command"] != "echo recent" {
+		t.Errorf("expected only the recent request, got %v", result)
+	}
+}
 
+func TestHistoryCommand_CursorPagination(t *testing.T) {
+	h := testutil.NewHarness(t)
 	resetHistoryFlags()
-}
 
-func TestApplyHistoryFilters_InvalidSinceDate(t *testing.T) {
-	requests := []*db.Request{
-		{ID: "1", Status: db.StatusPending, CreatedAt: time.Now()},
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	for i := 0; i < 5; i++ {
+		testutil.MakeRequest(t, h.DB, sess, testutil.WithCommand(fmt.Sprintf("echo page-%d", i), h.ProjectDir, true))
 	}
 
-	// Invalid date should be ignored
-	flagHistoryStatus = ""
-	flagHistoryAgent = ""
-	flagHistoryTier = ""
-	flagHistorySince = "invalid-date"
+	seen := make(map[string]bool)
+	cursor := ""
+	for pages := 0; pages < 5; pages++ {
+		resetHistoryFlags()
+		args := []string{"history", "-C", h.ProjectDir, "--limit", "2", "-j"}
+		if cursor != "" {
+			args = append(args, "--cursor", cursor)
+		}
+		var stderr strings.Builder
+		cmd := newTestHistoryCmd(h.DBPath)
+		cmd.SetErr(&stderr)
+		stdout, err := executeCommandCapture(t, cmd, args...)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
 
-	result := applyHistoryFilters(requests)
-	// Should return all since invalid date is ignored
-	if len(result) != 1 {
-		t.Errorf("expected 1 result with invalid since filter (ignored), got %d", len(result))
-	}
+		var result []map[string]any
+		if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+			t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+		}
+		for _, r := range result {
+			id := r["request_id"].(string)
+			if seen[id] {
+				t.Fatalf("request %s returned on two pages", id)
+			}
+			seen[id] = true
+		}
 
-	resetHistoryFlags()
+		_, cursor, _ = strings.Cut(strings.TrimSpace(stderr.String()), "--cursor ")
+		if cursor == "" {
+			break
+		}
+	}
+	if len(seen) != 5 {
+		t.Errorf("expected to page through 5 requests, saw %d", len(seen))
+	}
 }
 
 func TestHistoryCommand_FilterByStatusApproved(t *testing.T) {
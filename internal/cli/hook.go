@@ -17,6 +17,7 @@ var (
 	flagHookMerge     bool
 	flagHookForce     bool
 	flagHookOutputDir string
+	flagHookExitCode  bool
 )
 
 func init() {
@@ -32,6 +33,8 @@ func init() {
 	// to a directory literally named "json" instead of emitting JSON.
 	hookGenerateCmd.Flags().StringVar(&flagHookOutputDir, "output-dir", "", "output directory (default: ~/.slb/hooks/)")
 
+	hookTestCmd.Flags().BoolVar(&flagHookExitCode, "exit-code", false, "exit 2 if the hook would not allow the command outright")
+
 	// Add subcommands
 	hookCmd.AddCommand(hookGenerateCmd)
 	hookCmd.AddCommand(hookInstallCmd)
@@ -117,9 +120,13 @@ var hookTestCmd = &cobra.Command{
 This simulates the hook's decision without actually running the command.
 Useful for verifying pattern classification and approval logic.
 
+With --exit-code, exit 2 when approval is needed (the hook would block
+or ask), matching 'slb patterns test --exit-code'.
+
 Examples:
   slb hook test "rm -rf node_modules"
-  slb hook test "git push --force"`,
+  slb hook test "git push --force"
+  slb hook test --exit-code "$CMD" || echo "needs approval"`,
 	Args: cobra.ExactArgs(1),
 	RunE: runHookTest,
 }
@@ -387,14 +394,6 @@ func runHookUninstall(cmd *cobra.Command, args []string) error {
 }
 
 func runHookStatus(cmd *cobra.Command, args []string) error {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
-	}
-
-	hookScriptPath := filepath.Join(home, ".slb", "hooks", "slb_guard.py")
-	settingsPath := filepath.Join(home, ".claude", "settings.json")
-
 	// Reflect persisted customs in the current_pattern_hash — the
 	// hash must compare apples-to-apples against what the next
 	// `slb hook generate` would produce, which now includes them.
@@ -402,6 +401,27 @@ func runHookStatus(cmd *cobra.Command, args []string) error {
 		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
 	}
 
+	status, err := hookStatus()
+	if err != nil {
+		return err
+	}
+	out := output.New(output.Format(GetOutput()))
+	return out.Write(status)
+}
+
+// hookStatus reports whether the Claude Code hook script exists and is
+// configured in settings.json, with an overall "status" of installed,
+// partial or not_installed. The pattern hash is the default engine's as
+// currently loaded.
+func hookStatus() (map[string]any, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	hookScriptPath := filepath.Join(home, ".slb", "hooks", "slb_guard.py")
+	settingsPath := filepath.Join(home, ".claude", "settings.json")
+
 	status := map[string]any{
 		"hook_script_exists":   false,
 		"hook_script_path":     hookScriptPath,
@@ -458,9 +478,7 @@ func runHookStatus(cmd *cobra.Command, args []string) error {
 	} else {
 		status["status"] = "not_installed"
 	}
-
-	out := output.New(output.Format(GetOutput()))
-	return out.Write(status)
+	return status, nil
 }
 
 func runHookTest(cmd *cobra.Command, args []string) error {
@@ -497,7 +515,7 @@ func runHookTest(cmd *cobra.Command, args []string) error {
 	}
 
 	out := output.New(output.Format(GetOutput()))
-	return out.Write(map[string]any{
+	err := out.Write(map[string]any{
 		"command":         command,
 		"action":          action,
 		"message":         message,
@@ -506,6 +524,10 @@ func runHookTest(cmd *cobra.Command, args []string) error {
 		"min_approvals":   result.MinApprovals,
 		"needs_approval":  result.NeedsApproval,
 	})
+	if err == nil && flagHookExitCode && result.NeedsApproval {
+		return withExitCode(exitNeedsApproval, nil)
+	}
+	return err
 }
 
 // generateHookScript creates the complete Python hook script with embedded patterns.
@@ -531,6 +553,7 @@ import hashlib
 import tempfile
 
 SLB_TIMEOUT = 0.05  # 50ms timeout
+SLB_CREATE_TIMEOUT = 2.0  # creating a request writes to the database
 
 def _project_root_for_socket(start: str) -> str:
     """Walk up from start looking for a .slb/ directory and return
@@ -556,35 +579,50 @@ def _project_root_for_socket(start: str) -> str:
             return os.path.abspath(start)
         path = parent
 
+def _configured_socket_path(root: str) -> str:
+    """Socket override from SLB_DAEMON_IPC_SOCKET, else [daemon] ipc_socket
+    in the project's .slb/config.toml; empty when neither is set. Relative
+    paths are taken from the project root, as the daemon does."""
+    path = os.environ.get("SLB_DAEMON_IPC_SOCKET", "").strip()
+    if not path:
+        try:
+            import tomllib
+            with open(os.path.join(root, ".slb", "config.toml"), "rb") as f:
+                path = str(tomllib.load(f).get("daemon", {}).get("ipc_socket", "")).strip()
+        except Exception:
+            path = ""
+    if path and not os.path.isabs(path):
+        path = os.path.join(root, path)
+    return path
+
 def get_socket_path() -> str:
     """Get the SLB daemon socket path for the current project."""
     cwd = os.getcwd()
     hash_base = _project_root_for_socket(cwd)
+    configured = _configured_socket_path(hash_base)
+    if configured:
+        return configured
     hash_digest = hashlib.sha256(hash_base.encode()).hexdigest()[:12]
     return os.path.join(tempfile.gettempdir(), f"slb-{hash_digest}.sock")
 
-def query_slb_daemon(command: str, session_id: str, cwd: str) -> Optional[dict]:
-    """Query SLB daemon for approval status. Returns None if unavailable."""
+def _call_slb_daemon(method: str, params: dict, timeout: float) -> Optional[dict]:
+    """Send one JSON-RPC call to the SLB daemon. Returns None if unavailable."""
     socket_path = get_socket_path()
     if not os.path.exists(socket_path):
         return None
 
     try:
         with socket.socket(socket.AF_UNIX, socket.SOCK_STREAM) as sock:
-            sock.settimeout(SLB_TIMEOUT)
+            sock.settimeout(timeout)
             sock.connect(socket_path)
-            # Use JSON-RPC format expected by the daemon
-            request = json.dumps({
-                "method": "hook_query",
-                "params": {
-                    "command": command,
-                    "session_id": session_id,
-                    "cwd": cwd
-                },
-                "id": 1
-            })
+            request = json.dumps({"method": method, "params": params, "id": 1})
             sock.sendall(request.encode() + b'\n')
-            response = sock.recv(4096)
+            response = b""
+            while not response.endswith(b'\n'):
+                chunk = sock.recv(4096)
+                if not chunk:
+                    break
+                response += chunk
             data = json.loads(response.decode())
             # Extract result from JSON-RPC response
             if "result" in data:
@@ -593,6 +631,37 @@ def query_slb_daemon(command: str, session_id: str, cwd: str) -> Optional[dict]:
     except (socket.error, json.JSONDecodeError, TimeoutError, OSError):
         return None
 
+def query_slb_daemon(command: str, session_id: str, cwd: str, tool_call_id: str = "", transcript_path: str = "", description: str = "") -> Optional[dict]:
+    """Query SLB daemon for approval status. Returns None if unavailable."""
+    return _call_slb_daemon("hook_query", {
+        "command": command,
+        "session_id": session_id,
+        "cwd": cwd,
+        # Provenance: lets reviewers see which tool call tried it.
+        "agent_program": "claude-code",
+        "tool_call_id": tool_call_id,
+        "transcript_path": transcript_path,
+        # Used when the daemon submits the blocked command as a request.
+        "slb_session_id": os.environ.get("SLB_SESSION_ID", ""),
+        "description": description
+    }, SLB_TIMEOUT)
+
+def submit_auto_request(params: dict) -> Optional[str]:
+    """Create the pending request the daemon prepared for a blocked command
+    and return the block message naming it, or None if it was not created."""
+    result = _call_slb_daemon("create_request", params, SLB_CREATE_TIMEOUT)
+    if not result or not result.get("request_id") or result.get("status") == "skipped":
+        return None
+    request_id = result["request_id"]
+    tier = str(result.get("tier", "")).upper()
+    return (
+        f"SLB {tier}: submitted as request {request_id} "
+        f"({result.get('status', 'pending')}, needs {result.get('min_approvals', 1)} approval(s)). "
+        f"Wait with 'slb status {request_id} --wait', then run "
+        f"'slb execute {request_id} --session-id {params.get('session_id', '')}'. "
+        "Do not resubmit it with 'slb request'."
+    )
+
 # Map SLB's internal verdict to the JSON shape Claude Code 2026.04
 # recognizes for PreToolUse hooks. The legacy {'action': 'block',
 # 'message': ...} shape is silently ignored by current Claude Code,
@@ -649,6 +718,9 @@ def main():
     tool_input = input_data.get("tool_input", {})
     command = tool_input.get("command", "")
     session_id = input_data.get("session_id", "")
+    tool_call_id = input_data.get("tool_use_id", "")
+    transcript_path = input_data.get("transcript_path", "")
+    description = tool_input.get("description", "")
     cwd = os.getcwd()
 
     if not command:
@@ -659,10 +731,13 @@ def main():
     # {'action', 'message'} shape; translate it here rather than
     # changing the daemon's RPC contract (which other callers
     # depend on).
-    daemon_response = query_slb_daemon(command, session_id, cwd)
+    daemon_response = query_slb_daemon(command, session_id, cwd, tool_call_id, transcript_path, description)
     if daemon_response:
         action = daemon_response.get("action", "allow")
         message = daemon_response.get("message", "")
+        auto_request = daemon_response.get("auto_request")
+        if action == "block" and auto_request:
+            message = submit_auto_request(auto_request) or message
         _emit_decision(action, message)
         return
 
@@ -66,6 +66,7 @@ func newTestHookCmd(dbPath string) *cobra.Command {
 		Args:  cobra.ExactArgs(1),
 		RunE:  hookTestCmd.RunE,
 	}
+	testCmd.Flags().BoolVar(&flagHookExitCode, "exit-code", false, "exit 2 if approval needed")
 
 	hkCmd.AddCommand(generateCmd, installCmd, uninstallCmd, statusCmd, testCmd)
 	root.AddCommand(hkCmd)
@@ -82,6 +83,7 @@ func resetHookFlags() {
 	flagHookMerge = true
 	flagHookForce = false
 	flagHookOutputDir = ""
+	flagHookExitCode = false
 }
 
 func TestHookCommand_Help(t *testing.T) {
@@ -713,7 +715,10 @@ func TestGenerateHookScript_ContainsEssentialComponents(t *testing.T) {
 		"def classify(command:",        // Classify function
 		"def is_blocked(command:",      // Block check function
 		"def query_slb_daemon",         // Daemon query function
+		"def submit_auto_request",      // Auto-created requests
+		"SLB_SESSION_ID",               // Session for auto-created requests
 		"def get_socket_path",          // Socket path function
+		"SLB_DAEMON_IPC_SOCKET",        // Socket path override
 		"def main():",                  // Entry point
 		"if __name__ == \"__main__\":", // Module guard
 	}
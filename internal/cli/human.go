@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os/user"
+	"strings"
+)
+
+// confirmHuman asks the human at the terminal to confirm an action and
+// returns the name they gave. It is a variable so tests can answer it.
+var confirmHuman = confirmHumanOnTTY
+
+// currentOSUser returns the operating-system account slb runs as, or ""
+// when it cannot be resolved. Unlike the name given at the terminal, the
+// caller cannot choose it. It is a variable so tests can switch accounts.
+var currentOSUser = func() string {
+	u, err := user.Current()
+	if err != nil {
+		return ""
+	}
+	return u.Username
+}
+
+// confirmHumanOnTTY shows summary, asks for the confirmer's name and
+// requires word to be typed, all at the controlling terminal, so neither an
+// agent nor a piped stdin can answer. The name is what callers record as
+// the human who confirmed, rather than --actor or SLB_ACTOR, which any
+// caller can set.
+func confirmHumanOnTTY(action string, summary []string, word string) (string, error) {
+	in, out, closeTTY, err := openTTY()
+	if err != nil {
+		return "", fmt.Errorf("%s needs a human at a terminal: %w", action, err)
+	}
+	defer closeTTY()
+
+	for _, line := range summary {
+		fmt.Fprintln(out, line)
+	}
+	reader := bufio.NewReader(in)
+	fmt.Fprint(out, "Your name (recorded for audit): ")
+	name, err := reader.ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("reading confirmation: %w", err)
+	}
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" {
+		return "", fmt.Errorf("%s not confirmed: a name is required", action)
+	}
+	fmt.Fprintf(out, "Type %s to confirm: ", word)
+	answer, err := reader.ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("reading confirmation: %w", err)
+	}
+	if strings.TrimSpace(answer) != word {
+		return "", fmt.Errorf("%s not confirmed", action)
+	}
+	return name, nil
+}
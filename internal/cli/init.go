@@ -106,25 +106,25 @@ func runInit(cmd *cobra.Command, args []string) error {
 	}
 
 	switch GetOutput() {
-	case "json", "yaml":
+	case "json", "ndjson", "yaml", "porcelain":
 		out := output.New(output.Format(GetOutput()))
 		return out.Write(result)
 	case "text":
-		fmt.Printf("Initialized SLB in %s\n", slbDir)
-		fmt.Println()
-		fmt.Println("Created:")
-		fmt.Printf("  %s/state.db      - SQLite database\n", ".slb")
-		fmt.Printf("  %s/config.toml   - Configuration file\n", ".slb")
-		fmt.Printf("  %s/logs/         - Execution logs\n", ".slb")
-		fmt.Printf("  %s/pending/      - Pending request snapshots\n", ".slb")
-		fmt.Printf("  %s/sessions/     - Active sessions\n", ".slb")
-		fmt.Printf("  %s/rollback/     - Rollback capture data\n", ".slb")
-		fmt.Printf("  %s/processed/    - Processed requests\n", ".slb")
-		fmt.Println()
-		fmt.Println("Next steps:")
-		fmt.Println("  1. Review .slb/config.toml and customize as needed")
-		fmt.Println("  2. Start a session: slb session start --agent <name>")
-		fmt.Println("  3. Submit a request: slb request --command 'rm -rf ./build'")
+		output.Notef(os.Stdout, "Initialized SLB in %s\n", slbDir)
+		output.Notef(os.Stdout, "\n")
+		output.Notef(os.Stdout, "Created:\n")
+		output.Notef(os.Stdout, "  %s/state.db      - SQLite database\n", ".slb")
+		output.Notef(os.Stdout, "  %s/config.toml   - Configuration file\n", ".slb")
+		output.Notef(os.Stdout, "  %s/logs/         - Execution logs\n", ".slb")
+		output.Notef(os.Stdout, "  %s/pending/      - Pending request snapshots\n", ".slb")
+		output.Notef(os.Stdout, "  %s/sessions/     - Active sessions\n", ".slb")
+		output.Notef(os.Stdout, "  %s/rollback/     - Rollback capture data\n", ".slb")
+		output.Notef(os.Stdout, "  %s/processed/    - Processed requests\n", ".slb")
+		output.Notef(os.Stdout, "\n")
+		output.Notef(os.Stdout, "Next steps:\n")
+		output.Notef(os.Stdout, "  1. Review .slb/config.toml and customize as needed\n")
+		output.Notef(os.Stdout, "  2. Start a session: slb session start --agent <name>\n")
+		output.Notef(os.Stdout, "  3. Submit a request: slb request --command 'rm -rf ./build'\n")
 		return nil
 	default:
 		return fmt.Errorf("unsupported format: %s", GetOutput())
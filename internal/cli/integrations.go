@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 
 	"github.com/Dicklesworthstone/slb/internal/integrations"
+	"github.com/Dicklesworthstone/slb/internal/output"
 	"github.com/spf13/cobra"
 )
 
@@ -74,7 +75,7 @@ var cursorRulesCmd = &cobra.Command{
 			return fmt.Errorf("writing %s: %w", path, err)
 		}
 
-		fmt.Fprintf(os.Stderr, "Wrote %s\n", path)
+		output.Notef(os.Stderr, "Wrote %s\n", path)
 		return nil
 	},
 }
@@ -129,9 +130,9 @@ and prompt for slb approval when the command requires review.`,
 		}
 
 		if merged {
-			fmt.Fprintf(os.Stderr, "Merged SLB hooks into %s\n", path)
+			output.Notef(os.Stderr, "Merged SLB hooks into %s\n", path)
 		} else {
-			fmt.Fprintf(os.Stderr, "Wrote %s\n", path)
+			output.Notef(os.Stderr, "Wrote %s\n", path)
 		}
 		return nil
 	},
@@ -0,0 +1,239 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/daemon"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/keyring"
+	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// llmReviewToken is the keyring name of the LLM reviewer's API key.
+const llmReviewToken = daemon.LLMReviewToken
+
+// keyringTokens are the API tokens `slb keyring set` accepts.
+var keyringTokens = map[string]string{
+	llmReviewToken: "API key for the LLM second-opinion reviewer (when SLB_LLM_REVIEW_API_KEY is unset)",
+}
+
+func init() {
+	keyringCmd.AddCommand(keyringStatusCmd)
+	keyringCmd.AddCommand(keyringSetCmd)
+	keyringCmd.AddCommand(keyringDeleteCmd)
+	rootCmd.AddCommand(keyringCmd)
+}
+
+var keyringCmd = &cobra.Command{
+	Use:   "keyring",
+	Short: "Manage secrets kept in the OS keychain",
+	Long: `slb keeps session keys and API tokens in the OS credential store: the macOS
+Keychain, the Secret Service (libsecret, via secret-tool) on Linux, or the
+Windows Credential Manager. Where none is usable it falls back to
+~/.slb/keyring.json, readable only by you.
+
+'slb session start' and 'slb session resume' store the new session's key, so
+approve, reject, review and the TUI find it from --session-id alone, and
+'slb session end' removes it. general.session_key_store (env
+SLB_SESSION_KEY_STORE) picks the backend: auto (default), keyring, file or off.
+
+Tokens:
+  llm-review-api-key   ` + keyringTokens[llmReviewToken],
+}
+
+var keyringStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show which backend stores secrets",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadKeyringConfig()
+		if err != nil {
+			return err
+		}
+		result := map[string]any{"mode": cfg.General.SessionKeyStore, "backend": "none"}
+		store, err := keyring.OpenDefault(cfg.General.SessionKeyStore)
+		if err != nil {
+			result["error"] = err.Error()
+		} else if store != nil {
+			result["backend"] = store.Name()
+		}
+		if dir, err := keyring.DefaultDir(); err == nil {
+			result["file"] = filepath.Join(dir, keyring.FileName)
+		}
+		if GetOutput() != "text" {
+			return output.New(output.Format(GetOutput())).Write(result)
+		}
+		fmt.Printf("Mode:    %s\n", result["mode"])
+		fmt.Printf("Backend: %s\n", result["backend"])
+		if msg, ok := result["error"]; ok {
+			fmt.Printf("Error:   %s\n", msg)
+		}
+		if file, ok := result["file"]; ok {
+			fmt.Printf("File:    %s\n", file)
+		}
+		return nil
+	},
+}
+
+var keyringSetCmd = &cobra.Command{
+	Use:   "set <token>",
+	Short: "Store an API token, read from stdin",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, err := keyringTokenName(args[0])
+		if err != nil {
+			return err
+		}
+		store, err := openConfiguredKeyring()
+		if err != nil {
+			return err
+		}
+		if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+			fmt.Fprintf(os.Stderr, "Enter %s: ", name)
+		}
+		secret, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && secret == "" {
+			return fmt.Errorf("reading token: %w", err)
+		}
+		secret = strings.TrimRight(secret, "\r\n")
+		if secret == "" {
+			return errors.New("empty token")
+		}
+		if err := store.Set(keyring.TokenAccount(name), secret); err != nil {
+			return fmt.Errorf("storing token: %w", err)
+		}
+		return output.New(output.Format(GetOutput())).Write(map[string]any{
+			"token":   name,
+			"backend": store.Name(),
+			"status":  "stored",
+		})
+	},
+}
+
+var keyringDeleteCmd = &cobra.Command{
+	Use:   "delete <token>",
+	Short: "Remove a stored API token",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, err := keyringTokenName(args[0])
+		if err != nil {
+			return err
+		}
+		store, err := openConfiguredKeyring()
+		if err != nil {
+			return err
+		}
+		if err := store.Delete(keyring.TokenAccount(name)); err != nil {
+			return fmt.Errorf("deleting token: %w", err)
+		}
+		return output.New(output.Format(GetOutput())).Write(map[string]any{
+			"token":  name,
+			"status": "deleted",
+		})
+	},
+}
+
+func keyringTokenName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if _, ok := keyringTokens[name]; ok {
+		return name, nil
+	}
+	known := make([]string, 0, len(keyringTokens))
+	for k := range keyringTokens {
+		known = append(known, k)
+	}
+	sort.Strings(known)
+	return "", fmt.Errorf("unknown token %q (known: %s)", name, strings.Join(known, ", "))
+}
+
+func loadKeyringConfig() (config.Config, error) {
+	project, err := projectPath()
+	if err != nil {
+		return config.Config{}, err
+	}
+	return config.Load(config.LoadOptions{ProjectDir: project, ConfigPath: flagConfig})
+}
+
+// openConfiguredKeyring opens the configured store, failing when storage
+// is off.
+func openConfiguredKeyring() (keyring.Store, error) {
+	cfg, err := loadKeyringConfig()
+	if err != nil {
+		return nil, err
+	}
+	store, err := keyring.OpenDefault(cfg.General.SessionKeyStore)
+	if err != nil {
+		return nil, err
+	}
+	if store == nil {
+		return nil, errors.New("secret storage is off (general.session_key_store = \"off\")")
+	}
+	return store, nil
+}
+
+// storeSessionKey saves a session's key so later commands can find it from
+// the session ID. It returns the backend used, or "" when storage is off
+// or failed; a failure is a warning since the key is also printed.
+func storeSessionKey(sess *db.Session) string {
+	cfg, err := config.Load(config.LoadOptions{ProjectDir: sess.ProjectPath, ConfigPath: flagConfig})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: session key not stored: %v\n", err)
+		return ""
+	}
+	store, err := keyring.OpenDefault(cfg.General.SessionKeyStore)
+	if err == nil && store != nil {
+		err = store.Set(keyring.SessionAccount(sess.ID), sess.SessionKey)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: session key not stored: %v\n", err)
+		return ""
+	}
+	if store == nil {
+		return ""
+	}
+	return store.Name()
+}
+
+// lookupSessionKey returns the stored key for a session, or "".
+func lookupSessionKey(sessionID string) string {
+	if sessionID == "" {
+		return ""
+	}
+	cfg, err := loadKeyringConfig()
+	if err != nil {
+		return ""
+	}
+	store, err := keyring.OpenDefault(cfg.General.SessionKeyStore)
+	if err != nil || store == nil {
+		return ""
+	}
+	key, err := store.Get(keyring.SessionAccount(sessionID))
+	if err != nil {
+		return ""
+	}
+	return key
+}
+
+// forgetSessionKeys removes ended sessions' keys. Failures are ignored: a
+// stale key no longer authenticates anything.
+func forgetSessionKeys(project string, sessionIDs ...string) {
+	cfg, err := config.Load(config.LoadOptions{ProjectDir: project, ConfigPath: flagConfig})
+	if err != nil {
+		return
+	}
+	store, err := keyring.OpenDefault(cfg.General.SessionKeyStore)
+	if err != nil || store == nil {
+		return
+	}
+	for _, id := range sessionIDs {
+		_ = store.Delete(keyring.SessionAccount(id))
+	}
+}
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/daemon"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/keyring"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+// useFileKeyring points the keyring at a file under a temporary HOME.
+func useFileKeyring(t *testing.T) *keyring.FileStore {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("SLB_SESSION_KEY_STORE", "file")
+	return keyring.NewFileStore(filepath.Join(home, ".slb", keyring.FileName))
+}
+
+func TestSessionStart_StoresKeyForApprovals(t *testing.T) {
+	h := testutil.NewHarness(t)
+	store := useFileKeyring(t)
+	t.Setenv("SLB_SESSION_KEY", "")
+	resetSessionFlags()
+
+	cmd := newTestSessionCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "session", "start", "-a", "Reviewer", "-m", "model-b", "-C", h.ProjectDir, "-j")
+	if err != nil {
+		t.Fatalf("session start: %v", err)
+	}
+	var started map[string]any
+	if err := json.Unmarshal([]byte(stdout), &started); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if started["key_storage"] != "file" {
+		t.Errorf("key_storage = %v, want file", started["key_storage"])
+	}
+	reviewerID := started["session_id"].(string)
+	stored, err := store.Get(keyring.SessionAccount(reviewerID))
+	if err != nil || stored != started["session_key"] {
+		t.Fatalf("stored key = %q, %v; want %v", stored, err, started["session_key"])
+	}
+
+	// approve finds the key from --session-id alone.
+	requestor := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir), testutil.WithAgent("Requestor"), testutil.WithModel("model-a"))
+	req := testutil.MakeRequest(t, h.DB, requestor,
+		testutil.WithCommand("rm -rf ./build", h.ProjectDir, true),
+		testutil.WithRisk(db.RiskTierDangerous),
+	)
+	h.DB.Exec(`UPDATE requests SET min_approvals = 1, require_different_model = false WHERE id = ?`, req.ID)
+	resetApproveFlags()
+	_, err = executeCommandCapture(t, newTestApproveCmd(h.DBPath), "approve", req.ID, "--session-id", reviewerID, "-C", h.ProjectDir, "-j")
+	if err != nil {
+		t.Fatalf("approve without --session-key: %v", err)
+	}
+
+	resetSessionFlags()
+	cmd = newTestSessionCmd(h.DBPath)
+	if _, err := executeCommandCapture(t, cmd, "session", "end", "-s", reviewerID, "-j"); err != nil {
+		t.Fatalf("session end: %v", err)
+	}
+	if _, err := store.Get(keyring.SessionAccount(reviewerID)); !errors.Is(err, keyring.ErrNotFound) {
+		t.Errorf("expected session end to remove the key, got %v", err)
+	}
+}
+
+func TestSessionStart_KeyStorageOff(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetSessionFlags()
+
+	cmd := newTestSessionCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "session", "start", "-a", "Agent", "-C", h.ProjectDir, "-j")
+	if err != nil {
+		t.Fatalf("session start: %v", err)
+	}
+	if strings.Contains(stdout, "key_storage") {
+		t.Errorf("expected no key_storage with storage off:\n%s", stdout)
+	}
+}
+
+func TestResolveSessionKey_Order(t *testing.T) {
+	store := useFileKeyring(t)
+	resetSessionFlags()
+	if err := store.Set(keyring.SessionAccount("sess-1"), "from-keyring"); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("SLB_SESSION_KEY", "from-env")
+	if got, _ := resolveSessionKey("from-flag", "sess-1"); got != "from-flag" {
+		t.Errorf("flag: got %q", got)
+	}
+	if got, _ := resolveSessionKey("", "sess-1"); got != "from-env" {
+		t.Errorf("env: got %q", got)
+	}
+	t.Setenv("SLB_SESSION_KEY", "")
+	if got, _ := resolveSessionKey("", "sess-1"); got != "from-keyring" {
+		t.Errorf("keyring: got %q", got)
+	}
+	if _, err := resolveSessionKey("", "sess-2"); err == nil || !strings.Contains(err.Error(), "--session-key is required") {
+		t.Errorf("expected the required error for an unknown session, got %v", err)
+	}
+}
+
+func TestLLMReviewAPIKey_FromKeyring(t *testing.T) {
+	store := useFileKeyring(t)
+	cfg := config.DefaultConfig()
+	cfg.General.SessionKeyStore = "file"
+
+	t.Setenv("SLB_LLM_REVIEW_API_KEY", "")
+	if got := daemon.LLMReviewAPIKey(cfg); got != "" {
+		t.Errorf("expected no key, got %q", got)
+	}
+	if err := store.Set(keyring.TokenAccount(llmReviewToken), "sk-stored"); err != nil {
+		t.Fatal(err)
+	}
+	if got := daemon.LLMReviewAPIKey(cfg); got != "sk-stored" {
+		t.Errorf("got %q, want the stored token", got)
+	}
+	t.Setenv("SLB_LLM_REVIEW_API_KEY", "sk-env")
+	if got := daemon.LLMReviewAPIKey(cfg); got != "sk-env" {
+		t.Errorf("got %q, want the env token", got)
+	}
+}
+
+func TestKeyringTokenName(t *testing.T) {
+	if name, err := keyringTokenName(" LLM-Review-API-Key "); err != nil || name != llmReviewToken {
+		t.Errorf("got %q, %v", name, err)
+	}
+	if _, err := keyringTokenName("github"); err == nil || !strings.Contains(err.Error(), llmReviewToken) {
+		t.Errorf("expected an error listing known tokens, got %v", err)
+	}
+}
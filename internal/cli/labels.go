@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/spf13/cobra"
+)
+
+// flagLabels holds --label key=value pairs for commands that create requests.
+var flagLabels []string
+
+// flagTask groups the request under a task (the task label).
+var flagTask string
+
+// addLabelFlags registers --label and --task on a request-creating command.
+func addLabelFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(&flagLabels, "label", nil, "attach a key=value label to the request (repeatable, e.g. --label team=infra)")
+	cmd.Flags().StringVar(&flagTask, "task", "", "group the request under a task or ticket ID, e.g. OPS-123 (default $SLB_TASK)")
+}
+
+// labelsFromFlags parses the --label flags, adding the task label from
+// --task or SLB_TASK. Returns nil when there are no labels.
+func labelsFromFlags() (map[string]string, error) {
+	labels, err := db.ParseLabels(flagLabels)
+	if err != nil {
+		return nil, fmt.Errorf("parsing --label: %w", err)
+	}
+	task := flagTask
+	if task == "" {
+		task = os.Getenv("SLB_TASK")
+	}
+	if task != "" {
+		if err := db.ValidateLabel(db.TaskLabel, task); err != nil {
+			return nil, fmt.Errorf("parsing --task: %w", err)
+		}
+		if labels == nil {
+			labels = make(map[string]string, 1)
+		}
+		labels[db.TaskLabel] = task
+	}
+	return labels, nil
+}
+
+// filterByLabels loads labels for requests and keeps those matching every
+// filter spec ("key=value" or bare "key"). With no specs, requests are
+// returned unchanged (labels are still loaded for display).
+func filterByLabels(dbConn *db.DB, requests []*db.Request, specs []string) ([]*db.Request, error) {
+	filters, err := db.ParseLabelFilters(specs)
+	if err != nil {
+		return nil, fmt.Errorf("parsing --label: %w", err)
+	}
+	if err := dbConn.LoadRequestLabels(requests); err != nil {
+		return nil, err
+	}
+	if len(filters) == 0 {
+		return requests, nil
+	}
+
+	out := make([]*db.Request, 0, len(requests))
+	for _, r := range requests {
+		if db.MatchLabels(r.Labels, filters) {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+// resetLabelFlags resets the shared --label flag.
+func resetLabelFlags() {
+	flagLabels = nil
+	flagTask = ""
+}
+
+func TestRequestCommand_RecordsLabels(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetRequestFlags()
+	defer resetLabelFlags()
+
+	sess := testutil.MakeSession(t, h.DB,
+		testutil.WithProject(h.ProjectDir),
+		testutil.WithAgent("TestAgent"),
+	)
+
+	cmd := newTestRequestCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "request", "rm -rf ./build",
+		"-s", sess.ID,
+		"-C", h.ProjectDir,
+		"--label", "team=infra",
+		"--label", "ticket=OPS-12,OPS-13",
+		"-j",
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var result struct {
+		RequestID string            `json:"request_id"`
+		Labels    map[string]string `json:"labels"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if result.Labels["team"] != "infra" {
+		t.Errorf("expected labels in response, got %v", result.Labels)
+	}
+
+	labels, err := h.DB.GetRequestLabels(result.RequestID)
+	if err != nil {
+		t.Fatalf("GetRequestLabels: %v", err)
+	}
+	if labels["team"] != "infra" || labels["ticket"] != "OPS-12,OPS-13" {
+		t.Fatalf("unexpected stored labels: %v", labels)
+	}
+}
+
+func TestRequestCommand_InvalidLabel(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetRequestFlags()
+	defer resetLabelFlags()
+
+	sess := testutil.MakeSession(t, h.DB,
+		testutil.WithProject(h.ProjectDir),
+		testutil.WithAgent("TestAgent"),
+	)
+
+	cmd := newTestRequestCmd(h.DBPath)
+	_, err := executeCommandCapture(t, cmd, "request", "rm -rf ./build",
+		"-s", sess.ID,
+		"-C", h.ProjectDir,
+		"--label", "team",
+		"-j",
+	)
+	if err == nil || !strings.Contains(err.Error(), "key=value") {
+		t.Fatalf("expected label parse error, got %v", err)
+	}
+}
+
+func TestReviewListCommand_FiltersByLabel(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetReviewFlags()
+
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	infra := testutil.MakeRequest(t, h.DB, sess, testutil.WithLabels(map[string]string{"team": "infra"}))
+	testutil.MakeRequest(t, h.DB, sess, testutil.WithLabels(map[string]string{"team": "data"}))
+	testutil.MakeRequest(t, h.DB, sess)
+
+	cmd := newTestReviewCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "review", "list", "--label", "team=infra", "-C", h.ProjectDir, "-j")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var result []struct {
+		ID     string            `json:"id"`
+		Labels map[string]string `json:"labels"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if len(result) != 1 || result[0].ID != infra.ID || result[0].Labels["team"] != "infra" {
+		t.Fatalf("expected only the infra request, got %+v", result)
+	}
+}
+
+func TestHistoryCommand_FiltersByLabelKey(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetHistoryFlags()
+
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	labeled := testutil.MakeRequest(t, h.DB, sess, testutil.WithLabels(map[string]string{"ticket": "OPS-1"}))
+	testutil.MakeRequest(t, h.DB, sess)
+
+	cmd := newTestHistoryCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "history", "--label", "ticket", "-C", h.ProjectDir, "-j")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var result []struct {
+		RequestID string `json:"request_id"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if len(result) != 1 || result[0].RequestID != labeled.ID {
+		t.Fatalf("expected only the labeled request, got %+v", result)
+	}
+}
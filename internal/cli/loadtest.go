@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagLoadTestAgents   int
+	flagLoadTestDuration time.Duration
+	flagLoadTestThink    time.Duration
+	flagLoadTestKeep     bool
+)
+
+func init() {
+	loadtestCmd.Flags().IntVar(&flagLoadTestAgents, "agents", 10, "synthetic agents working at once (at least 2)")
+	loadtestCmd.Flags().DurationVar(&flagLoadTestDuration, "duration", 5*time.Minute, "how long the agents work")
+	loadtestCmd.Flags().DurationVar(&flagLoadTestThink, "think", 0, "each agent's pause between steps (0 runs flat out)")
+	loadtestCmd.Flags().BoolVar(&flagLoadTestKeep, "keep", false, "keep the temporary project and its database afterwards")
+
+	rootCmd.AddCommand(loadtestCmd)
+}
+
+var loadtestCmd = &cobra.Command{
+	Use:   "loadtest",
+	Short: "Soak-test slb with synthetic agents in a temporary project",
+	Long: `Simulate a multi-agent day against a temporary project. Each synthetic
+agent has its own session and database connection, as separate agent
+processes would, and loops: execute its approved requests, submit a new
+dangerous-tier request (rm -rf of a scratch directory inside the temporary
+project), and approve another agent's pending request.
+
+The report gives throughput, latency percentiles for submit, review and
+execute, races between agents (expected), and database lock errors (there
+should be none). Interrupt with Ctrl-C to stop early and still get the
+report. Your own project and database are not touched.
+
+Examples:
+  slb loadtest                                  # 10 agents for 5 minutes
+  slb loadtest --agents 25 --duration 30s -j
+  slb loadtest --agents 4 --think 500ms --keep  # Gentler, keep the project`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := os.MkdirTemp("", "slb-loadtest-")
+		if err != nil {
+			return fmt.Errorf("creating temporary project: %w", err)
+		}
+		if !flagLoadTestKeep {
+			defer os.RemoveAll(dir)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		if !isJSONOutput() {
+			output.Notef(os.Stderr, "Running %d agents for %s in %s...\n", flagLoadTestAgents, flagLoadTestDuration, dir)
+		}
+
+		report, err := core.RunLoadTest(ctx, core.LoadTestOptions{
+			ProjectPath: dir,
+			Agents:      flagLoadTestAgents,
+			Duration:    flagLoadTestDuration,
+			Think:       flagLoadTestThink,
+		})
+		if err != nil {
+			return err
+		}
+
+		if GetOutput() != "text" {
+			return output.New(output.Format(GetOutput())).Write(report)
+		}
+		fmt.Print(report.Text())
+		return nil
+	},
+}
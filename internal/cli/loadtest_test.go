@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+	"github.com/spf13/cobra"
+)
+
+func newTestLoadtestCmd() *cobra.Command {
+	root := &cobra.Command{Use: "slb", SilenceUsage: true, SilenceErrors: true}
+	root.PersistentFlags().StringVarP(&flagOutput, "output", "o", "text", "output format")
+	root.PersistentFlags().BoolVarP(&flagJSON, "json", "j", false, "json output")
+	cmd := &cobra.Command{Use: "loadtest", Args: cobra.NoArgs, RunE: loadtestCmd.RunE}
+	cmd.Flags().IntVar(&flagLoadTestAgents, "agents", 10, "")
+	cmd.Flags().DurationVar(&flagLoadTestDuration, "duration", 5*time.Minute, "")
+	cmd.Flags().DurationVar(&flagLoadTestThink, "think", 0, "")
+	cmd.Flags().BoolVar(&flagLoadTestKeep, "keep", false, "")
+	root.AddCommand(cmd)
+	return root
+}
+
+func TestLoadtestCommand(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping load test in -short mode")
+	}
+	t.Cleanup(func() {
+		flagOutput, flagJSON = "text", false
+	})
+
+	stdout, err := executeCommandCapture(t, newTestLoadtestCmd(), "loadtest", "--agents", "3", "--duration", "700ms", "-j")
+	testutil.RequireNoError(t, err, "loadtest -j")
+	var report core.LoadTestReport
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if report.Agents != 3 || report.Executed == 0 || report.LockErrors != 0 || len(report.Ops) != 3 {
+		t.Errorf("unexpected report:\n%s", stdout)
+	}
+	if _, err := os.Stat(report.ProjectPath); !os.IsNotExist(err) {
+		t.Errorf("temporary project %s should be removed without --keep", report.ProjectPath)
+	}
+
+	flagJSON = false
+	stdout, err = executeCommandCapture(t, newTestLoadtestCmd(), "loadtest", "--agents", "2", "--duration", "300ms")
+	testutil.RequireNoError(t, err, "loadtest")
+	for _, want := range []string{"Load test: 2 agents", "Database lock errors: 0", "execute"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("text output missing %q:\n%s", want, stdout)
+		}
+	}
+
+	if _, err := executeCommandCapture(t, newTestLoadtestCmd(), "loadtest", "--agents", "1"); err == nil {
+		t.Error("a single agent should be refused")
+	}
+}
@@ -0,0 +1,14 @@
+package cli
+
+import (
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	// Keep session keys out of the developer's keychain and ~/.slb; tests
+	// that exercise storage opt back in with SLB_SESSION_KEY_STORE=file and
+	// a temporary HOME.
+	os.Setenv("SLB_SESSION_KEY_STORE", "off")
+	os.Exit(m.Run())
+}
@@ -0,0 +1,353 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
+	"github.com/spf13/cobra"
+)
+
+// nextRecentWindow is how long a resolved request keeps showing up in
+// `slb next`, so an agent that was not waiting still learns its outcome.
+const nextRecentWindow = time.Hour
+
+// nextPollSeconds is the poll interval suggested while the agent has
+// nothing to do but wait on its own requests.
+const nextPollSeconds = 30
+
+var (
+	flagNextWait    bool
+	flagNextTimeout int
+)
+
+func init() {
+	nextCmd.Flags().BoolVar(&flagNextWait, "wait", false, "when only waiting is left, block until one of your requests changes")
+	nextCmd.Flags().IntVar(&flagNextTimeout, "timeout", 300, "longest --wait blocks, in seconds")
+
+	rootCmd.AddCommand(nextCmd)
+}
+
+var nextCmd = &cobra.Command{
+	Use:   "next",
+	Short: "Show what an agent should do next",
+	Long: `Summarise what needs the session's attention in one call: its own open
+requests (and those resolved in the last hour) with their state, the
+pending requests it can review, most urgent first, and the commands to run
+next.
+
+The session is the one given by --session-id, else SLB_SESSION_ID, else
+your active session in this project.
+
+With --wait, when the only thing left is waiting on your own requests,
+block until one of them changes state (or --timeout passes) and report
+then. A running daemon pushes the change as soon as it happens; without
+one the requests are polled.
+
+Examples:
+  slb next --session-id $SESSION_ID -j
+  slb next --session-id $SESSION_ID --wait --timeout 600 -j`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		project, err := projectPath()
+		if err != nil {
+			return err
+		}
+
+		dbConn, err := db.Open(GetDB())
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer dbConn.Close()
+
+		sessionID, err := resolveReviewerSessionID(dbConn, project, flagSessionID)
+		if err != nil {
+			return err
+		}
+		sess, err := dbConn.GetSession(sessionID)
+		if err != nil {
+			return fmt.Errorf("getting session: %w", err)
+		}
+		if !sess.IsActive() {
+			return fmt.Errorf("session %s has ended", sess.ID)
+		}
+
+		cfg, err := config.Load(config.LoadOptions{
+			ProjectDir: sess.ProjectPath,
+			ConfigPath: flagConfig,
+		})
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+
+		view, err := buildNextView(dbConn, sess, cfg, time.Now())
+		if err != nil {
+			return err
+		}
+		if flagNextWait && view.PollAfterSeconds > 0 {
+			if err := waitForOwnRequests(dbConn, sess.ID, time.Duration(flagNextTimeout)*time.Second); err != nil {
+				return err
+			}
+			if view, err = buildNextView(dbConn, sess, cfg, time.Now()); err != nil {
+				return err
+			}
+		}
+		if GetOutput() != "text" {
+			return output.New(output.Format(GetOutput())).Write(view)
+		}
+		printNextView(view)
+		return nil
+	},
+}
+
+// nextView is what `slb next` reports.
+type nextView struct {
+	SessionID   string `json:"session_id"`
+	AgentName   string `json:"agent_name"`
+	ProjectPath string `json:"project_path"`
+	// MyRequests are the session's open requests and those resolved within
+	// the last hour, newest first.
+	MyRequests []nextRequestView `json:"my_requests"`
+	// AwaitingMyReview are pending requests the session can review, most
+	// urgent first.
+	AwaitingMyReview []nextReviewView `json:"awaiting_my_review"`
+	// Recommended lists the commands to run, most useful first.
+	Recommended []nextAction `json:"recommended"`
+	// PollAfterSeconds is set when the only thing left is waiting on the
+	// session's own requests.
+	PollAfterSeconds int `json:"poll_after_seconds,omitempty"`
+}
+
+type nextRequestView struct {
+	RequestID         string `json:"request_id"`
+	Command           string `json:"command"`
+	RiskTier          string `json:"risk_tier"`
+	Status            string `json:"status"`
+	Approvals         int    `json:"approvals"`
+	Rejections        int    `json:"rejections"`
+	MinApprovals      int    `json:"min_approvals"`
+	ProposedEditID    string `json:"proposed_edit_id,omitempty"`
+	ApprovalExpiresAt string `json:"approval_expires_at,omitempty"`
+	CreatedAt         string `json:"created_at"`
+	ResolvedAt        string `json:"resolved_at,omitempty"`
+}
+
+type nextReviewView struct {
+	RequestID      string `json:"request_id"`
+	Command        string `json:"command"`
+	RiskTier       string `json:"risk_tier"`
+	RiskScore      int    `json:"risk_score"`
+	RequestorAgent string `json:"requestor_agent"`
+	Priority       int    `json:"priority"`
+	SLABreached    bool   `json:"sla_breached,omitempty"`
+	CreatedAt      string `json:"created_at"`
+}
+
+// nextAction is one recommended command and why.
+type nextAction struct {
+	Command   string `json:"command"`
+	Reason    string `json:"reason"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+func buildNextView(dbConn *db.DB, sess *db.Session, cfg config.Config, now time.Time) (*nextView, error) {
+	view := &nextView{
+		SessionID:        sess.ID,
+		AgentName:        sess.AgentName,
+		ProjectPath:      sess.ProjectPath,
+		MyRequests:       []nextRequestView{},
+		AwaitingMyReview: []nextReviewView{},
+		Recommended:      []nextAction{},
+	}
+
+	mine, err := dbConn.ListRequestsBySession(sess.ID)
+	if err != nil {
+		return nil, fmt.Errorf("listing session requests: %w", err)
+	}
+	// Actions are collected by kind and emitted in this order: unblocking
+	// the session's own work first, then reviews, then waiting.
+	var runs, edits, inspects, waits []nextAction
+	for _, r := range mine {
+		if r.Status.IsTerminal() && (r.ResolvedAt == nil || now.Sub(*r.ResolvedAt) > nextRecentWindow) {
+			continue
+		}
+		approvals, rejections, err := dbConn.CountReviewsByDecision(r.ID)
+		if err != nil {
+			return nil, fmt.Errorf("counting reviews: %w", err)
+		}
+		rv := nextRequestView{
+			RequestID:    r.ID,
+			Command:      displayCommand(r),
+			RiskTier:     string(r.RiskTier),
+			Status:       string(r.Status),
+			Approvals:    approvals,
+			Rejections:   rejections,
+			MinApprovals: r.MinApprovals,
+			CreatedAt:    timefmt.Format(r.CreatedAt),
+		}
+		if r.ApprovalExpiresAt != nil {
+			rv.ApprovalExpiresAt = timefmt.Format(*r.ApprovalExpiresAt)
+		}
+		if r.ResolvedAt != nil {
+			rv.ResolvedAt = timefmt.Format(*r.ResolvedAt)
+		}
+
+		switch r.Status {
+		case db.StatusApproved:
+			reason := "approved, ready to run"
+			if rv.ApprovalExpiresAt != "" {
+				reason += " before the approval expires at " + rv.ApprovalExpiresAt
+			}
+			runs = append(runs, nextAction{
+				Command:   fmt.Sprintf("slb execute %s --session-id %s", r.ID, sess.ID),
+				Reason:    reason,
+				RequestID: r.ID,
+			})
+		case db.StatusPending:
+			if edit := proposedEdit(dbConn, r.ID); edit != nil {
+				rv.ProposedEditID = edit.ID
+				edits = append(edits, nextAction{
+					Command:   fmt.Sprintf("slb accept-edit %s --session-id %s", edit.ID, sess.ID),
+					Reason:    fmt.Sprintf("%s would approve a corrected command: %s", edit.ReviewerAgent, edit.Command),
+					RequestID: r.ID,
+				})
+			}
+			waits = append(waits, nextAction{
+				Command:   fmt.Sprintf("slb status %s --wait", r.ID),
+				Reason:    fmt.Sprintf("pending with %d of %d approvals", approvals, r.MinApprovals),
+				RequestID: r.ID,
+			})
+		case db.StatusEscalated:
+			waits = append(waits, nextAction{
+				Command:   fmt.Sprintf("slb status %s --wait", r.ID),
+				Reason:    "escalated, waiting on a human",
+				RequestID: r.ID,
+			})
+		case db.StatusRejected, db.StatusTimeout, db.StatusExecutionFailed, db.StatusTimedOut:
+			inspects = append(inspects, nextAction{
+				Command:   fmt.Sprintf("slb show %s", r.ID),
+				Reason:    fmt.Sprintf("%s; read the outcome before retrying", r.Status),
+				RequestID: r.ID,
+			})
+		}
+		view.MyRequests = append(view.MyRequests, rv)
+	}
+
+	reviewable, err := reviewableRequests(dbConn, sess.ProjectPath, sess.ID)
+	if err != nil {
+		return nil, err
+	}
+	eligible := reviewable[:0]
+	for _, r := range reviewable {
+		if r.RequireDifferentModel && r.RequestorModel == sess.Model {
+			continue
+		}
+		eligible = append(eligible, r)
+	}
+	var reviews []nextAction
+	for _, item := range reviewQueue(dbConn, eligible, cfg, queueSortPriority, now) {
+		r := item.Request
+		breached := item.SLA != nil && item.SLA.Breached
+		view.AwaitingMyReview = append(view.AwaitingMyReview, nextReviewView{
+			RequestID:      r.ID,
+			Command:        displayCommand(r),
+			RiskTier:       string(r.RiskTier),
+			RiskScore:      r.RiskScore,
+			RequestorAgent: r.RequestorAgent,
+			Priority:       item.Priority,
+			SLABreached:    breached,
+			CreatedAt:      timefmt.Format(r.CreatedAt),
+		})
+		reason := fmt.Sprintf("%s request from %s awaiting your review", r.RiskTier, r.RequestorAgent)
+		switch {
+		case breached:
+			reason += ", review SLA breached"
+		case item.Waiting:
+			reason += ", requestor is blocked waiting"
+		}
+		reviews = append(reviews, nextAction{
+			Command:   fmt.Sprintf("slb review %s", r.ID),
+			Reason:    reason,
+			RequestID: r.ID,
+		})
+	}
+
+	for _, group := range [][]nextAction{runs, edits, reviews, inspects, waits} {
+		view.Recommended = append(view.Recommended, group...)
+	}
+	if len(waits) > 0 && len(waits) == len(view.Recommended) {
+		view.PollAfterSeconds = nextPollSeconds
+	}
+	return view, nil
+}
+
+// waitForOwnRequests blocks until one of the session's requests changes
+// status or timeout passes.
+func waitForOwnRequests(dbConn *db.DB, sessionID string, timeout time.Duration) error {
+	waiter := newDecisionWaiter(sessionID)
+	defer waiter.Close()
+
+	since := time.Now().Add(-nextRecentWindow)
+	before, err := dbConn.RequestStatusesBySession(sessionID, since)
+	if err != nil {
+		return fmt.Errorf("reading request statuses: %w", err)
+	}
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		waiter.Wait(time.Until(deadline))
+		now, err := dbConn.RequestStatusesBySession(sessionID, since)
+		if err != nil {
+			return fmt.Errorf("reading request statuses: %w", err)
+		}
+		for id, status := range now {
+			if before[id] != status {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// proposedEdit returns the newest edit still awaiting the requestor, nil if
+// there is none or the lookup fails.
+func proposedEdit(dbConn *db.DB, requestID string) *db.CommandEdit {
+	edits, err := dbConn.ListCommandEdits(requestID)
+	if err != nil {
+		return nil
+	}
+	for i := len(edits) - 1; i >= 0; i-- {
+		if edits[i].Status == db.CommandEditProposed {
+			return edits[i]
+		}
+	}
+	return nil
+}
+
+// displayCommand is the command as it may be shown: redacted when it
+// contains secrets.
+func displayCommand(r *db.Request) string {
+	if r.Command.ContainsSensitive && r.Command.DisplayRedacted != "" {
+		return r.Command.DisplayRedacted
+	}
+	return r.Command.Raw
+}
+
+func printNextView(view *nextView) {
+	fmt.Printf("Session: %s (%s)\n", view.AgentName, view.SessionID)
+	fmt.Printf("Mine:    %d open or recently resolved\n", len(view.MyRequests))
+	fmt.Printf("Review:  %d awaiting you\n", len(view.AwaitingMyReview))
+	if len(view.Recommended) == 0 {
+		fmt.Println("Nothing to do.")
+		return
+	}
+	fmt.Println()
+	for _, a := range view.Recommended {
+		fmt.Printf("  %s\n      %s\n", a.Command, a.Reason)
+	}
+	if view.PollAfterSeconds > 0 {
+		fmt.Printf("\nNothing else to do; check again in %ds.\n", view.PollAfterSeconds)
+	}
+}
@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+	"github.com/spf13/cobra"
+)
+
+func newTestNextCmd(dbPath string) *cobra.Command {
+	root := &cobra.Command{
+		Use:           "slb",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	root.PersistentFlags().StringVar(&flagDB, "db", dbPath, "database path")
+	root.PersistentFlags().StringVarP(&flagOutput, "output", "o", "text", "output format")
+	root.PersistentFlags().BoolVarP(&flagJSON, "json", "j", false, "json output")
+	root.PersistentFlags().StringVarP(&flagProject, "project", "C", "", "project directory")
+	root.PersistentFlags().StringVarP(&flagSessionID, "session-id", "s", "", "session ID")
+	root.PersistentFlags().StringVarP(&flagConfig, "config", "c", "", "config file")
+
+	root.AddCommand(nextCmd)
+
+	return root
+}
+
+func resetNextFlags() {
+	flagDB = ""
+	flagOutput = "text"
+	flagJSON = false
+	flagProject = ""
+	flagSessionID = ""
+	flagConfig = ""
+	flagNextWait = false
+	flagNextTimeout = 300
+}
+
+func TestNextCommand_SummarisesWork(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetNextFlags()
+
+	me := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir), testutil.WithAgent("Me"))
+	peer := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir), testutil.WithAgent("Peer"))
+
+	waiting := testutil.MakeRequest(t, h.DB, me,
+		testutil.WithCommand("rm -rf ./build", h.ProjectDir, true),
+		testutil.WithRisk(db.RiskTierDangerous),
+	)
+	approved := testutil.MakeRequest(t, h.DB, me,
+		testutil.WithCommand("git push --force", h.ProjectDir, true),
+		testutil.WithRisk(db.RiskTierDangerous),
+		testutil.WithStatus(db.StatusApproved),
+	)
+	toReview := testutil.MakeRequest(t, h.DB, peer,
+		testutil.WithCommand("kubectl delete ns staging", h.ProjectDir, true),
+		testutil.WithRisk(db.RiskTierCritical),
+	)
+
+	stdout, err := executeCommandCapture(t, newTestNextCmd(h.DBPath), "next", "-C", h.ProjectDir, "--session-id", me.ID, "-j")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var view nextView
+	if err := json.Unmarshal([]byte(stdout), &view); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+
+	if view.SessionID != me.ID || len(view.MyRequests) != 2 {
+		t.Fatalf("unexpected own requests: %+v", view)
+	}
+	if len(view.AwaitingMyReview) != 1 || view.AwaitingMyReview[0].RequestID != toReview.ID {
+		t.Fatalf("expected %s awaiting review, got %+v", toReview.ID, view.AwaitingMyReview)
+	}
+
+	want := []string{
+		"slb execute " + approved.ID,
+		"slb review " + toReview.ID,
+		"slb status " + waiting.ID + " --wait",
+	}
+	if len(view.Recommended) != len(want) {
+		t.Fatalf("expected %d recommendations, got %+v", len(want), view.Recommended)
+	}
+	for i, prefix := range want {
+		if !strings.HasPrefix(view.Recommended[i].Command, prefix) {
+			t.Errorf("recommendation %d = %q, want prefix %q", i, view.Recommended[i].Command, prefix)
+		}
+	}
+	if view.PollAfterSeconds != 0 {
+		t.Errorf("expected no poll hint while there is work, got %d", view.PollAfterSeconds)
+	}
+}
+
+func TestNextCommand_OnlyWaiting(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetNextFlags()
+
+	me := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	testutil.MakeRequest(t, h.DB, me,
+		testutil.WithCommand("rm -rf ./build", h.ProjectDir, true),
+		testutil.WithRisk(db.RiskTierDangerous),
+	)
+	// Finished with no recent resolution, so no longer reported.
+	testutil.MakeRequest(t, h.DB, me,
+		testutil.WithCommand("git clean -fdx", h.ProjectDir, true),
+		testutil.WithStatus(db.StatusExecuted),
+	)
+
+	stdout, err := executeCommandCapture(t, newTestNextCmd(h.DBPath), "next", "-C", h.ProjectDir, "--session-id", me.ID, "-j")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var view nextView
+	if err := json.Unmarshal([]byte(stdout), &view); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if len(view.Recommended) != 1 || view.PollAfterSeconds != nextPollSeconds {
+		t.Errorf("expected a single wait with a poll hint, got %+v", view)
+	}
+}
+
+func TestNextCommand_WaitReturnsOnChange(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetNextFlags()
+
+	me := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	req := testutil.MakeRequest(t, h.DB, me,
+		testutil.WithCommand("rm -rf ./build", h.ProjectDir, true),
+		testutil.WithRisk(db.RiskTierDangerous),
+	)
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		_ = h.DB.UpdateRequestStatus(req.ID, db.StatusApproved)
+	}()
+
+	start := time.Now()
+	stdout, err := executeCommandCapture(t, newTestNextCmd(h.DBPath), "next", "-C", h.ProjectDir, "--session-id", me.ID, "--wait", "--timeout", "10", "-j")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if waited := time.Since(start); waited > 5*time.Second {
+		t.Errorf("--wait should return soon after the change, took %s", waited)
+	}
+	var view nextView
+	if err := json.Unmarshal([]byte(stdout), &view); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if len(view.Recommended) != 1 || !strings.HasPrefix(view.Recommended[0].Command, "slb execute "+req.ID) {
+		t.Errorf("expected to be told to execute after waiting, got %+v", view.Recommended)
+	}
+}
+
+func TestNextCommand_EndedSession(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetNextFlags()
+
+	me := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	if err := h.DB.EndSession(me.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := executeCommandCapture(t, newTestNextCmd(h.DBPath), "next", "-C", h.ProjectDir, "--session-id", me.ID, "-j")
+	if err == nil || !strings.Contains(err.Error(), "has ended") {
+		t.Fatalf("expected ended session error, got %v", err)
+	}
+}
@@ -5,8 +5,13 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/daemon"
 	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/numfmt"
 	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
 	"github.com/spf13/cobra"
 )
 
@@ -113,7 +118,7 @@ This data helps improve pattern classification and identify risky commands.`,
 			"problem_description": outcome.ProblemDescription,
 			"human_rating":        outcome.HumanRating,
 			"human_notes":         outcome.HumanNotes,
-			"recorded_at":         outcome.CreatedAt.Format(time.RFC3339),
+			"recorded_at":         timefmt.Format(outcome.CreatedAt),
 		})
 	},
 }
@@ -146,7 +151,7 @@ var outcomeListCmd = &cobra.Command{
 				"id":              o.ID,
 				"request_id":      o.RequestID,
 				"caused_problems": o.CausedProblems,
-				"created_at":      o.CreatedAt.Format(time.RFC3339),
+				"created_at":      timefmt.Format(o.CreatedAt),
 			}
 			if o.ProblemDescription != "" {
 				item["problem_description"] = o.ProblemDescription
@@ -177,8 +182,21 @@ Shows:
 - Total outcome count
 - Problematic percentage
 - Average human rating
-- Time-to-approval statistics`,
+- Time-to-approval statistics
+- Review SLA compliance per tier (requests, breaches, median time to first review)`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		project, err := projectPath()
+		if err != nil {
+			return err
+		}
+		cfg, err := config.Load(config.LoadOptions{
+			ProjectDir: project,
+			ConfigPath: flagConfig,
+		})
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+
 		dbConn, err := db.Open(GetDB())
 		if err != nil {
 			return fmt.Errorf("opening database: %w", err)
@@ -197,6 +215,16 @@ Shows:
 			return fmt.Errorf("getting approval stats: %w", err)
 		}
 
+		timings, err := dbConn.ListReviewTimings(time.Time{})
+		if err != nil {
+			return fmt.Errorf("getting review timings: %w", err)
+		}
+		slaStats := core.ComputeSLAStats(timings, daemon.ReviewSLAFromConfig(cfg.Notifications), time.Now())
+
+		if GetOutput() == "text" {
+			printOutcomeStats(outcomeStats, approvalStats, slaStats)
+			return nil
+		}
 		out := output.New(output.Format(GetOutput()))
 		return out.Write(map[string]any{
 			"outcomes": map[string]any{
@@ -213,10 +241,48 @@ Shows:
 				"min_minutes":    approvalStats.MinMinutes,
 				"max_minutes":    approvalStats.MaxMinutes,
 			},
+			"review_sla": slaStats,
 		})
 	},
 }
 
+// printOutcomeStats renders outcome stats for people, with counts,
+// percentages and durations formatted by numfmt (or left raw with --raw).
+func printOutcomeStats(o *db.OutcomeStats, a *db.TimeToApprovalStats, sla map[string]*core.TierSLAStats) {
+	fmt.Printf("Outcomes:       %s recorded, %s problematic (%s)\n",
+		numfmt.Count(o.TotalOutcomes), numfmt.Count(o.ProblematicCount), numfmt.Percent(o.ProblematicPercent))
+	if o.RatedCount > 0 {
+		fmt.Printf("Human rating:   %s average over %s rating(s)\n", numfmt.Decimal(o.AvgHumanRating, 1), numfmt.Count(o.RatedCount))
+	}
+
+	if a.SampleSize == 0 {
+		fmt.Println("Approval time:  no approved requests yet")
+	} else {
+		fmt.Printf("Approval time:  median %s, average %s (min %s, max %s) over %s request(s)\n",
+			minutesDuration(a.MedianMinutes), minutesDuration(a.AvgMinutes),
+			minutesDuration(a.MinMinutes), minutesDuration(a.MaxMinutes), numfmt.Count(a.SampleSize))
+	}
+
+	if len(sla) == 0 {
+		return
+	}
+	fmt.Println("Review SLA:")
+	for _, tier := range []db.RiskTier{db.RiskTierCritical, db.RiskTierDangerous, db.RiskTierCaution} {
+		s := sla[string(tier)]
+		if s == nil {
+			continue
+		}
+		fmt.Printf("  %-9s  target %s: %s request(s), %s breached (%s), median first review %s\n",
+			tier, minutesDuration(s.TargetMinutes), numfmt.Count(s.Requests), numfmt.Count(s.Breached),
+			numfmt.Percent(s.BreachPercent), minutesDuration(s.MedianFirstReviewMinutes))
+	}
+}
+
+// minutesDuration formats a duration given in fractional minutes.
+func minutesDuration(minutes float64) string {
+	return numfmt.Duration(time.Duration(minutes * float64(time.Minute)).Round(time.Second))
+}
+
 var outcomeAgentStatsCmd = &cobra.Command{
 	Use:   "agent-stats <agent-name>",
 	Short: "Show statistics for a specific agent",
@@ -4,8 +4,12 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/Dicklesworthstone/slb/internal/core"
 	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/i18n"
+	"github.com/Dicklesworthstone/slb/internal/numfmt"
 	"github.com/Dicklesworthstone/slb/internal/testutil"
 	"github.com/spf13/cobra"
 )
@@ -21,6 +25,7 @@ func newTestOutcomeCmd(dbPath string) *cobra.Command {
 	root.PersistentFlags().StringVar(&flagDB, "db", dbPath, "database path")
 	root.PersistentFlags().StringVarP(&flagOutput, "output", "o", "text", "output format")
 	root.PersistentFlags().BoolVarP(&flagJSON, "json", "j", false, "json output")
+	root.PersistentFlags().StringVarP(&flagProject, "project", "C", "", "project directory")
 
 	// Create a fresh outcome command tree
 	outCmd := &cobra.Command{
@@ -63,6 +68,7 @@ func resetOutcomeFlags() {
 	flagDB = ""
 	flagOutput = "text"
 	flagJSON = false
+	flagProject = ""
 	outcomeProblems = false
 	outcomeDescription = ""
 	outcomeRating = 0
@@ -252,6 +258,72 @@ func TestOutcomeStatsCommand_ShowsStats(t *testing.T) {
 	}
 }
 
+func TestPrintOutcomeStats_FormatsNumbers(t *testing.T) {
+	prevLocale := i18n.Locale()
+	t.Cleanup(func() {
+		i18n.SetLocale(prevLocale)
+		numfmt.SetRaw(false)
+	})
+	i18n.SetLocale("en")
+
+	outcomes := &db.OutcomeStats{TotalOutcomes: 12345, ProblematicCount: 1234, ProblematicPercent: 9.996, RatedCount: 2000, AvgHumanRating: 4.25}
+	approvals := &db.TimeToApprovalStats{SampleSize: 4321, MedianMinutes: 90.5, AvgMinutes: 2, MinMinutes: 0.25, MaxMinutes: 3000}
+	sla := map[string]*core.TierSLAStats{"critical": {TargetMinutes: 30, Requests: 1500, Breached: 15, BreachPercent: 1, MedianFirstReviewMinutes: 12}}
+
+	stdout := captureStdout(t, func() { printOutcomeStats(outcomes, approvals, sla) })
+	for _, want := range []string{
+		"12,345 recorded, 1,234 problematic (10.0%)",
+		"4.2 average over 2,000 rating(s)",
+		"median 1h 30m, average 2m 00s (min 15.0s, max 2d 02h) over 4,321 request(s)",
+		"critical   target 30m 00s: 1,500 request(s), 15 breached (1.0%)",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in:\n%s", want, stdout)
+		}
+	}
+
+	numfmt.SetRaw(true)
+	stdout = captureStdout(t, func() { printOutcomeStats(outcomes, approvals, sla) })
+	for _, want := range []string{"12345 recorded, 1234 problematic (10.0%)", "median 1h30m30s", "over 4321 request(s)"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in raw output:\n%s", want, stdout)
+		}
+	}
+}
+
+func TestOutcomeStatsCommand_ReviewSLA(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetOutcomeFlags()
+
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	for i := 0; i < 2; i++ {
+		testutil.MakeRequest(t, h.DB, sess, testutil.WithRisk(db.RiskTierDangerous))
+	}
+	if _, err := h.DB.Exec(`UPDATE requests SET created_at = ?`, time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, err := executeCommandCapture(t, newTestOutcomeCmd(h.DBPath), "outcome", "stats", "-C", h.ProjectDir, "-j")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var result struct {
+		ReviewSLA map[string]struct {
+			TargetMinutes float64 `json:"target_minutes"`
+			Requests      int     `json:"requests"`
+			Breached      int     `json:"breached"`
+			BreachPercent float64 `json:"breach_percent"`
+		} `json:"review_sla"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	d, ok := result.ReviewSLA["dangerous"]
+	if !ok || d.TargetMinutes != 10 || d.Requests != 2 || d.Breached != 2 || d.BreachPercent != 100 {
+		t.Errorf("unexpected review SLA stats: %+v", result.ReviewSLA)
+	}
+}
+
 func TestOutcomeCommand_Help(t *testing.T) {
 	h := testutil.NewHarness(t)
 	resetOutcomeFlags()
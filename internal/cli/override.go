@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
+	"github.com/spf13/cobra"
+)
+
+var flagOverrideReason string
+
+func init() {
+	overrideWindowCmd.Flags().StringVarP(&flagOverrideReason, "reason", "r", "", "reason for running inside a restricted window (required)")
+
+	rootCmd.AddCommand(overrideWindowCmd)
+}
+
+var overrideWindowCmd = &cobra.Command{
+	Use:   "override-window <request-id>",
+	Short: "Allow an approved request to run during quiet hours (human override)",
+	Long: `Allow an approved request to execute inside a restricted execution window.
+
+Execution windows (see [execution_windows] in config) block risky tiers during
+quiet hours or weekends. This is a HUMAN OVERRIDE for a single request. It
+requires:
+- A mandatory reason explaining why the window must be overridden
+- Confirmation at the controlling terminal: the human enters their name and
+  types OVERRIDE, so an agent cannot grant itself an override
+
+The override is bound to the approved command hash and recorded for audit
+under the name given at the terminal.
+
+Examples:
+  slb override-window abc123 -r "Hotfix for outage INC-42"`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		requestID := args[0]
+
+		if strings.TrimSpace(flagOverrideReason) == "" {
+			return fmt.Errorf("--reason is required to override an execution window")
+		}
+
+		dbConn, err := db.OpenAndMigrate(GetDB())
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer dbConn.Close()
+
+		request, err := dbConn.GetRequest(requestID)
+		if err != nil {
+			return fmt.Errorf("getting request: %w", err)
+		}
+		if request.Status != db.StatusApproved {
+			return fmt.Errorf("request must be approved before overriding its execution window (status: %s)", request.Status)
+		}
+
+		display := request.Command.Raw
+		if request.Command.ContainsSensitive && request.Command.DisplayRedacted != "" {
+			display = request.Command.DisplayRedacted
+		}
+		grantedBy, err := confirmHuman("execution window override", []string{
+			"=== EXECUTION WINDOW OVERRIDE ===",
+			"Request: " + request.ID,
+			"Risk:    " + strings.ToUpper(string(request.RiskTier)),
+			"Command: " + display,
+			"Reason:  " + flagOverrideReason,
+			"",
+			"This allows execution during a restricted window.",
+		}, "OVERRIDE")
+		if err != nil {
+			return err
+		}
+
+		o := &db.ExecutionOverride{
+			RequestID:   request.ID,
+			CommandHash: request.Command.Hash,
+			GrantedBy:   grantedBy,
+			Reason:      flagOverrideReason,
+		}
+		if err := dbConn.CreateExecutionOverride(o); err != nil {
+			return err
+		}
+
+		out := output.New(output.Format(GetOutput()))
+		if isJSONOutput() {
+			return out.Write(map[string]any{
+				"request_id": o.RequestID,
+				"granted_by": o.GrantedBy,
+				"reason":     o.Reason,
+				"created_at": timefmt.Format(o.CreatedAt),
+			})
+		}
+		output.Notef(os.Stdout, "Execution window override recorded for %s by %s\n", o.RequestID, o.GrantedBy)
+		return nil
+	},
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+	"github.com/spf13/cobra"
+)
+
+// newTestOverrideWindowCmd creates a fresh override-window command tree for testing.
+func newTestOverrideWindowCmd(dbPath string) *cobra.Command {
+	root := &cobra.Command{
+		Use:           "slb",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	root.PersistentFlags().StringVarP(&flagOutput, "output", "o", "text", "output format")
+	root.PersistentFlags().BoolVarP(&flagJSON, "json", "j", false, "shorthand for --output=json")
+	root.PersistentFlags().StringVar(&flagDB, "db", dbPath, "database path")
+	root.PersistentFlags().StringVar(&flagActor, "actor", "", "actor identifier")
+
+	root.AddCommand(overrideWindowCmd)
+
+	return root
+}
+
+// resetOverrideWindowFlags resets all override-window flags to defaults.
+func resetOverrideWindowFlags() {
+	flagOutput = "text"
+	flagJSON = false
+	flagDB = ""
+	flagActor = ""
+	flagOverrideReason = ""
+}
+
+// answerHuman makes confirmHuman return name and err for the rest of the
+// test, recording the actions it was asked to confirm.
+func answerHuman(t *testing.T, name string, err error) *[]string {
+	t.Helper()
+	var asked []string
+	orig := confirmHuman
+	confirmHuman = func(action string, _ []string, _ string) (string, error) {
+		asked = append(asked, action)
+		return name, err
+	}
+	t.Cleanup(func() { confirmHuman = orig })
+	return &asked
+}
+
+func TestOverrideWindow_RequiresReason(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetOverrideWindowFlags()
+
+	cmd := newTestOverrideWindowCmd(h.DBPath)
+	_, _, err := executeCommand(cmd, "override-window", "req-123")
+	if err == nil || !strings.Contains(err.Error(), "--reason is required") {
+		t.Fatalf("expected --reason error, got %v", err)
+	}
+}
+
+func TestOverrideWindow_RequiresApprovedRequest(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetOverrideWindowFlags()
+
+	sess := testutil.MakeSession(t, h.DB)
+	req := testutil.MakeRequest(t, h.DB, sess, testutil.WithRisk(db.RiskTierCritical))
+
+	cmd := newTestOverrideWindowCmd(h.DBPath)
+	_, _, err := executeCommand(cmd, "override-window", req.ID, "-r", "hotfix")
+	if err == nil || !strings.Contains(err.Error(), "must be approved") {
+		t.Fatalf("expected approval error, got %v", err)
+	}
+}
+
+func TestOverrideWindow_RecordsOverride(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetOverrideWindowFlags()
+
+	sess := testutil.MakeSession(t, h.DB)
+	req := testutil.MakeRequest(t, h.DB, sess,
+		testutil.WithRisk(db.RiskTierCritical),
+		testutil.WithStatus(db.StatusApproved),
+	)
+
+	refused := errors.New("execution window override not confirmed")
+	answerHuman(t, "", refused)
+	cmd := newTestOverrideWindowCmd(h.DBPath)
+	_, err := executeCommandCapture(t, cmd, "override-window", req.ID, "-r", "hotfix", "--actor", "agent")
+	if !errors.Is(err, refused) {
+		t.Fatalf("expected the unconfirmed override to be refused, got %v", err)
+	}
+	if _, err := h.DB.GetExecutionOverride(req.ID); !errors.Is(err, db.ErrExecutionOverrideNotFound) {
+		t.Fatalf("an unconfirmed override must not be recorded, got %v", err)
+	}
+
+	asked := answerHuman(t, "Dana", nil)
+	resetOverrideWindowFlags()
+	cmd = newTestOverrideWindowCmd(h.DBPath)
+	_, err = executeCommandCapture(t, cmd, "override-window", req.ID, "-r", "hotfix", "--actor", "agent", "-j")
+	if err != nil {
+		t.Fatalf("override-window: %v", err)
+	}
+	if len(*asked) != 1 {
+		t.Fatalf("expected one terminal confirmation, got %v", *asked)
+	}
+
+	o, err := h.DB.GetExecutionOverride(req.ID)
+	if err != nil {
+		t.Fatalf("GetExecutionOverride: %v", err)
+	}
+	if o.GrantedBy != "Dana" || o.Reason != "hotfix" || o.CommandHash != req.Command.Hash {
+		t.Fatalf("expected the override granted by the human at the terminal, got %+v", o)
+	}
+}
@@ -6,9 +6,12 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/Dicklesworthstone/slb/internal/config"
 	"github.com/Dicklesworthstone/slb/internal/core"
 	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/numfmt"
 	"github.com/Dicklesworthstone/slb/internal/output"
 	"github.com/spf13/cobra"
 )
@@ -19,10 +22,34 @@ var (
 	flagPatternExitCode   bool
 	flagPatternFormat     string
 	flagPatternOutputFile string
+	flagPatternPack       bool
+	flagPatternStats      bool
 )
 
+// enableConfiguredPatternPacks enables the pattern packs listed in the
+// project's patterns.packs config on the global engine. Best-effort like the
+// custom pattern merge: an unreadable config leaves the engine unchanged and
+// unknown packs are reported as warnings.
+func enableConfiguredPatternPacks() {
+	project, err := projectPath()
+	if err != nil {
+		return
+	}
+	cfg, err := config.Load(config.LoadOptions{ProjectDir: project, ConfigPath: flagConfig})
+	if err != nil {
+		return
+	}
+	engine := core.GetDefaultEngine()
+	for _, name := range cfg.Patterns.Packs {
+		if err := engine.EnablePack(strings.TrimSpace(name)); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		}
+	}
+}
+
 // loadCustomPatternsIntoDefaultEngine merges every row in the project's
-// `custom_patterns` table into the global pattern engine. Without this,
+// `custom_patterns` table (and the project's configured pattern packs)
+// into the global pattern engine. Without this,
 // `slb patterns add` persists the row to SQLite but a fresh CLI process
 // (e.g. `slb patterns test`) only ever sees the builtin patterns
 // because the engine is initialized at package load with builtins only.
@@ -32,6 +59,10 @@ var (
 // `slb init`) still work against builtins. Returns the number of
 // patterns loaded.
 func loadCustomPatternsIntoDefaultEngine() (int, error) {
+	// Project pattern packs merge alongside custom patterns so every
+	// classification path sees the same set.
+	enableConfiguredPatternPacks()
+
 	dbConn, err := db.OpenAndMigrate(GetDB())
 	if err != nil {
 		// No project DB yet: silently fall back to builtins-only.
@@ -46,54 +77,11 @@ func loadCustomPatternsIntoDefaultEngine() (int, error) {
 		return 0, fmt.Errorf("loading custom patterns: %w", err)
 	}
 
-	engine := core.GetDefaultEngine()
-	// Snapshot existing engine patterns by (tier, pattern) so this
-	// helper is idempotent across calls in the same process. Without
-	// dedup, calling loadCustomPatternsIntoDefaultEngine twice (e.g.
-	// in a test that creates two cobra command trees, or any future
-	// long-running mode) would append the same row twice and the
-	// in-memory engine would diverge from the SQLite source of truth.
-	existing := make(map[string]struct{})
-	for tierName, list := range engine.AllPatterns() {
-		for _, p := range list {
-			existing[tierName+"\x00"+p.Pattern] = struct{}{}
-		}
-	}
-
-	loaded := 0
-	for _, row := range rows {
-		tier := parseTier(row.Tier)
-		if tier == "" {
-			// Unknown tier — persisted by an older CLI version or
-			// edited directly in SQL. Skipping is safer than
-			// blindly routing it to the safe bucket (which is
-			// what engine.AddPattern's default arm would do).
-			fmt.Fprintf(os.Stderr,
-				"warning: skipping persisted pattern with unrecognized tier %q (pattern=%q)\n",
-				row.Tier, row.Pattern)
-			continue
-		}
-		// Use the canonical lowercase tier name (from parseTier) as
-		// the dedup key. If a row was inserted with mixed-case tier
-		// (e.g. via direct SQL edits), it would still match the
-		// engine's lowercase keys.
-		key := string(tier) + "\x00" + row.Pattern
-		if _, dup := existing[key]; dup {
-			continue
-		}
-		if err := engine.AddPattern(tier, row.Pattern, row.Description, row.Source); err != nil {
-			// A persisted pattern that won't compile is a real
-			// problem, but shouldn't take down the whole CLI —
-			// log and continue so other patterns still load.
-			fmt.Fprintf(os.Stderr, "warning: skipping invalid persisted pattern %q (tier=%s): %v\n",
-				row.Pattern, row.Tier, err)
-			continue
-		}
-		// Mark loaded so that two duplicate rows in the same DB
-		// table (e.g. inserted before the UNIQUE constraint was
-		// added) don't both populate the engine.
-		existing[key] = struct{}{}
-		loaded++
+	loaded, skipped := core.GetDefaultEngine().MergeCustomPatterns(rows)
+	for _, err := range skipped {
+		// A persisted pattern that won't load is a real problem, but
+		// shouldn't take down the whole CLI; other patterns still load.
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
 	}
 	return loaded, nil
 }
@@ -104,13 +92,17 @@ func init() {
 	patternsCmd.PersistentFlags().StringVarP(&flagPatternReason, "reason", "r", "", "reason for adding/removing pattern")
 
 	// patterns test/check flags
-	patternsTestCmd.Flags().BoolVar(&flagPatternExitCode, "exit-code", false, "return non-zero exit code if approval needed")
+	// patterns list flags
+	patternsListCmd.Flags().BoolVar(&flagPatternPack, "pack", false, "list available pattern packs, or a pack's patterns when a name is given")
+	patternsListCmd.Flags().BoolVar(&flagPatternStats, "stats", false, "show how often each pattern matched and when it last did")
+
+	patternsTestCmd.Flags().BoolVar(&flagPatternExitCode, "exit-code", false, "exit 2 if approval is needed")
 
 	// patterns export flags.
 	// Named --output-file (not --output): the persistent --output/-o is the
 	// output FORMAT (text/json/yaml/toon). A local --output here would shadow
 	// that persistent flag, breaking `slb patterns export -o json`.
-	patternsExportCmd.Flags().StringVarP(&flagPatternFormat, "format", "f", "json", "export format: json, yaml, claude-hook")
+	patternsExportCmd.Flags().StringVarP(&flagPatternFormat, "format", "f", "json", "export format: json, yaml, claude-hook, rego, semgrep")
 	patternsExportCmd.Flags().StringVar(&flagPatternOutputFile, "output-file", "", "output file (default: stdout)")
 
 	// Add subcommands
@@ -147,14 +139,47 @@ var patternsListCmd = &cobra.Command{
 	Long: `List all patterns used for command classification.
 
 Use --tier to filter by a specific tier (safe, critical, dangerous, caution).
-Without --tier, all patterns from all tiers are shown.`,
+Without --tier, all patterns from all tiers are shown.
+
+Use --pack to list the optional pattern packs and whether each is enabled,
+or --pack <name> to show one pack's patterns. Enable packs per project with
+patterns.packs in the config.
+
+Use --stats to show how many commands each pattern classified (requests
+and hook checks) and when it last matched. 'slb patterns unused' lists the
+custom patterns that never have.
+
+Examples:
+  slb patterns list --tier critical
+  slb patterns list --stats
+  slb patterns list --pack
+  slb patterns list --pack kubernetes`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 0 && !flagPatternPack {
+			return fmt.Errorf("unexpected argument %q (did you mean --pack %s?)", args[0], args[0])
+		}
 		if _, err := loadCustomPatternsIntoDefaultEngine(); err != nil {
 			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
 		}
 		engine := core.GetDefaultEngine()
 		out := output.New(output.Format(GetOutput()))
 
+		if flagPatternPack {
+			if len(args) == 1 {
+				return outputPackPatterns(out, args[0])
+			}
+			return outputPacks(out, engine.EnabledPacks())
+		}
+
+		var hits map[string]*db.PatternHit
+		if flagPatternStats {
+			var err error
+			if hits, err = loadPatternHits(); err != nil {
+				return err
+			}
+		}
+
 		if flagPatternTier != "" {
 			// Filter by tier
 			tier := parseTier(flagPatternTier)
@@ -162,12 +187,12 @@ Without --tier, all patterns from all tiers are shown.`,
 				return fmt.Errorf("invalid tier: %s (must be safe, critical, dangerous, or caution)", flagPatternTier)
 			}
 			patterns := engine.ListPatterns(tier)
-			return outputPatterns(out, map[string][]*core.Pattern{flagPatternTier: patterns})
+			return outputPatterns(out, map[string][]*core.Pattern{flagPatternTier: patterns}, hits)
 		}
 
 		// All patterns
 		all := engine.AllPatterns()
-		return outputPatterns(out, all)
+		return outputPatterns(out, all, hits)
 	},
 }
 
@@ -179,8 +204,8 @@ var patternsTestCmd = &cobra.Command{
 Returns the tier, matched pattern, minimum approvals required, and whether
 approval is needed.
 
-Use --exit-code to return non-zero (exit 1) if approval is needed.
-This is useful for Claude Code hooks integration.`,
+Use --exit-code to exit 2 if approval is needed, so hooks and scripts
+can gate on it.`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Merge custom_patterns from the project DB on top of the
@@ -218,6 +243,26 @@ This is useful for Claude Code hooks integration.`,
 			resp["parse_error"] = true
 		}
 
+		if len(result.Explanation) > 0 {
+			resp["explanation"] = result.Explanation
+		}
+		if len(result.PathEscapes) > 0 {
+			escapes := make([]map[string]any, 0, len(result.PathEscapes))
+			for _, esc := range result.PathEscapes {
+				entry := map[string]any{
+					"path":      esc.Path,
+					"real_path": esc.RealPath,
+					"reason":    esc.Reason,
+				}
+				if esc.MountPoint != "" {
+					entry["mount_point"] = esc.MountPoint
+					entry["fs_type"] = esc.FSType
+				}
+				escapes = append(escapes, entry)
+			}
+			resp["path_escapes"] = escapes
+		}
+
 		if len(result.MatchedSegments) > 0 {
 			segments := make([]map[string]any, 0, len(result.MatchedSegments))
 			for _, seg := range result.MatchedSegments {
@@ -254,6 +299,12 @@ This is useful for Claude Code hooks integration.`,
 					fmt.Printf("  - %s (%s)\n", seg.Segment, seg.Tier)
 				}
 			}
+			if len(result.Explanation) > 0 {
+				fmt.Printf("Notes:\n")
+				for _, note := range result.Explanation {
+					fmt.Printf("  - %s\n", note)
+				}
+			}
 		} else {
 			out := output.New(output.Format(format))
 			if err := out.Write(resp); err != nil {
@@ -261,13 +312,9 @@ This is useful for Claude Code hooks integration.`,
 			}
 		}
 
-		// Exit code handling for hooks integration
 		if flagPatternExitCode && result.NeedsApproval {
-			// Flush stdout before exiting
-			os.Stdout.Sync()
-			os.Exit(1)
+			return withExitCode(exitNeedsApproval, nil)
 		}
-
 		return nil
 	},
 }
@@ -374,7 +421,7 @@ or use 'slb patterns request-removal' to create a pending removal request.`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		out := output.New(output.Format(GetOutput()))
-		if GetOutput() == "json" {
+		if isJSONOutput() {
 			return out.Write(map[string]any{
 				"error":   "pattern_removal_blocked",
 				"message": "Pattern removal requires human approval. Use slb tui.",
@@ -455,12 +502,19 @@ Available formats:
   json        - Full JSON export with metadata (default)
   yaml        - YAML format
   claude-hook - Python code for Claude Code hooks
+  rego        - OPA/Rego policy (package slb.patterns) for OPA-gated pipelines
+  semgrep     - Semgrep rules for scanning scripts and CI configs in a repo
+
+Every format lists patterns in the same sorted order, and the generated
+formats start with the pattern hash so stale copies can be detected.
 
 Examples:
   slb patterns export                              # JSON to stdout
   slb patterns export --format=claude-hook         # Python to stdout
   slb patterns export --output-file patterns.json  # JSON to file
-  slb patterns export -f claude-hook --output-file hook.py  # Python to file`,
+  slb patterns export -f claude-hook --output-file hook.py  # Python to file
+  slb patterns export -f rego --output-file policy/slb.rego
+  slb patterns export -f semgrep --output-file .semgrep/slb.yml`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if _, err := loadCustomPatternsIntoDefaultEngine(); err != nil {
 			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
@@ -486,8 +540,19 @@ Examples:
 				return fmt.Errorf("failed to export: %w", err)
 			}
 			content = string(data)
+		case "rego", "opa":
+			content = engine.ExportRego()
+		case "semgrep":
+			var warnings []string
+			content, warnings, err = engine.ExportSemgrep()
+			if err != nil {
+				return fmt.Errorf("failed to export Semgrep rules: %w", err)
+			}
+			for _, w := range warnings {
+				fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+			}
 		default:
-			return fmt.Errorf("unknown format: %s (use json, yaml, or claude-hook)", flagPatternFormat)
+			return fmt.Errorf("unknown format: %s (use json, yaml, claude-hook, rego, or semgrep)", flagPatternFormat)
 		}
 
 		// Output to file or stdout
@@ -548,32 +613,33 @@ Examples:
 // Helper functions
 
 func parseTier(s string) core.RiskTier {
-	switch strings.ToLower(s) {
-	case "critical":
-		return core.RiskTierCritical
-	case "dangerous":
-		return core.RiskTierDangerous
-	case "caution":
-		return core.RiskTierCaution
-	case "safe":
-		return core.RiskTier(core.RiskSafe)
-	default:
-		return ""
-	}
+	return core.ParseRiskTier(s)
 }
 
-func outputPatterns(out *output.Writer, patterns map[string][]*core.Pattern) error {
-	if GetOutput() == "json" {
+// outputPatterns lists patterns grouped by tier. hits, keyed by
+// patternHitKey, adds match statistics when non-nil.
+func outputPatterns(out *output.Writer, patterns map[string][]*core.Pattern, hits map[string]*db.PatternHit) error {
+	if isJSONOutput() {
 		// JSON output: clean structure with snake_case
 		result := make(map[string][]patternJSON)
 		for tier, list := range patterns {
 			plist := make([]patternJSON, 0, len(list))
 			for _, p := range list {
-				plist = append(plist, patternJSON{
+				pj := patternJSON{
 					Pattern:     p.Pattern,
 					Description: p.Description,
 					Source:      p.Source,
-				})
+					Pack:        p.Pack,
+				}
+				if hits != nil {
+					count := 0
+					if h := hits[patternHitKey(tier, p.Pattern)]; h != nil {
+						count = h.Hits
+						pj.LastMatchedAt = &h.LastMatchedAt
+					}
+					pj.Hits = &count
+				}
+				plist = append(plist, pj)
 			}
 			result[tier] = plist
 		}
@@ -591,7 +657,18 @@ func outputPatterns(out *output.Writer, patterns map[string][]*core.Pattern) err
 		}
 		fmt.Printf("\n%s (%d patterns):\n", strings.ToUpper(tier), len(list))
 		for _, p := range list {
-			fmt.Printf("  %s\n", p.Pattern)
+			line := "  " + p.Pattern
+			if p.Pack != "" {
+				line += "  [pack: " + p.Pack + "]"
+			}
+			if hits != nil {
+				if h := hits[patternHitKey(tier, p.Pattern)]; h != nil {
+					line += fmt.Sprintf("  (%s hits, last %s)", numfmt.Count(h.Hits), h.LastMatchedAt.Local().Format(time.DateTime))
+				} else {
+					line += "  (never matched)"
+				}
+			}
+			fmt.Println(line)
 			if p.Description != "" {
 				fmt.Printf("    # %s\n", p.Description)
 			}
@@ -605,4 +682,72 @@ type patternJSON struct {
 	Pattern     string `json:"pattern"`
 	Description string `json:"description,omitempty"`
 	Source      string `json:"source,omitempty"`
+	Pack        string `json:"pack,omitempty"`
+	// Hits and LastMatchedAt are set with --stats.
+	Hits          *int       `json:"hits,omitempty"`
+	LastMatchedAt *time.Time `json:"last_matched_at,omitempty"`
+}
+
+type packJSON struct {
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	Enabled      bool   `json:"enabled"`
+	PatternCount int    `json:"pattern_count"`
+}
+
+// outputPacks lists the available pattern packs.
+func outputPacks(out *output.Writer, enabled []string) error {
+	packs, err := core.PatternPacks()
+	if err != nil {
+		return err
+	}
+	isEnabled := make(map[string]bool, len(enabled))
+	for _, name := range enabled {
+		isEnabled[name] = true
+	}
+
+	list := make([]packJSON, 0, len(packs))
+	for _, p := range packs {
+		list = append(list, packJSON{
+			Name:         p.Name,
+			Description:  p.Description,
+			Enabled:      isEnabled[p.Name],
+			PatternCount: len(p.Patterns),
+		})
+	}
+	if isJSONOutput() {
+		return out.Write(list)
+	}
+
+	fmt.Println()
+	for _, p := range list {
+		state := "available"
+		if p.Enabled {
+			state = "enabled"
+		}
+		fmt.Printf("  %-20s %-9s %2d patterns  %s\n", p.Name, state, p.PatternCount, p.Description)
+	}
+	fmt.Println()
+	output.Notef(os.Stdout, "Enable packs for yourself with: slb config set --global patterns.packs <name>[,<name>...]\n")
+	output.Notef(os.Stdout, "For a project, set patterns.packs in a staged config and run: slb config apply <file>\n")
+	return nil
+}
+
+// outputPackPatterns shows one pack's patterns grouped by tier.
+func outputPackPatterns(out *output.Writer, name string) error {
+	pack, err := core.LookupPatternPack(name)
+	if err != nil {
+		return err
+	}
+	grouped := make(map[string][]*core.Pattern)
+	for _, pp := range pack.Patterns {
+		grouped[string(pp.Tier)] = append(grouped[string(pp.Tier)], &core.Pattern{
+			Tier:        pp.Tier,
+			Pattern:     pp.Pattern,
+			Description: pp.Description,
+			Source:      core.PatternSourcePack,
+			Pack:        pack.Name,
+		})
+	}
+	return outputPatterns(out, grouped, nil)
 }
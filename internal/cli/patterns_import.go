@@ -0,0 +1,204 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// Import statuses set by the command on top of core's plan.
+const (
+	importAdded   = "added"
+	importSkipped = "skipped"
+)
+
+var (
+	flagPatternImportDryRun   bool
+	flagPatternImportSkipSafe bool
+)
+
+func init() {
+	patternsImportCmd.Flags().BoolVar(&flagPatternImportDryRun, "dry-run", false, "show what would be imported without changing anything")
+	patternsImportCmd.Flags().BoolVar(&flagPatternImportSkipSafe, "skip-safe", false, "import everything except new safe-tier patterns, without prompting")
+
+	patternsCmd.AddCommand(patternsImportCmd)
+}
+
+var patternsImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import patterns from a JSON or YAML bundle",
+	Long: `Import patterns from a bundle written by 'slb patterns export' (JSON or
+YAML) on another machine, or from a hand-written YAML list:
+
+  patterns:
+    - tier: dangerous
+      pattern: ^make\s+deploy
+      description: deploys go through CI
+
+Entries without a tier use --tier. Every regex is validated first; if any
+entry is invalid nothing is imported. Patterns already present in the same
+tier (builtins, packs or earlier custom patterns) are skipped. New patterns
+are persisted like 'slb patterns add' with the source import:<file>.
+
+A safe-tier pattern lets matching commands skip review, so new safe
+patterns are only imported after a human types SAFE at the terminal.
+--skip-safe imports the rest without prompting.
+
+Examples:
+  slb patterns import team-patterns.json --dry-run
+  slb patterns import extra.yaml --tier dangerous
+  slb patterns import vendor.json --skip-safe`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := args[0]
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("reading bundle: %w", err)
+		}
+		patterns, err := core.ParsePatternBundle(data, flagPatternTier)
+		if err != nil {
+			return err
+		}
+		source := "import:" + filepath.Base(path)
+
+		// Dedup against everything a classification would see: builtins,
+		// configured packs and persisted custom patterns.
+		if _, err := loadCustomPatternsIntoDefaultEngine(); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		}
+		engine := core.GetDefaultEngine()
+		entries := engine.PlanImport(patterns)
+
+		var invalid int
+		var safe []core.ImportEntry
+		for _, e := range entries {
+			switch {
+			case e.Status == core.ImportInvalid:
+				invalid++
+			case e.Status == core.ImportNew && e.Tier == core.RiskSafe:
+				safe = append(safe, e)
+			}
+		}
+
+		if invalid == 0 && !flagPatternImportDryRun {
+			if len(safe) > 0 && flagPatternImportSkipSafe {
+				for i := range entries {
+					if entries[i].Status == core.ImportNew && entries[i].Tier == core.RiskSafe {
+						entries[i].Status = importSkipped
+						entries[i].Error = "safe tier needs confirmation"
+					}
+				}
+			} else if len(safe) > 0 {
+				if err := confirmSafeImport(path, safe); err != nil {
+					return err
+				}
+			}
+
+			dbConn, err := db.OpenAndMigrate(GetDB())
+			if err != nil {
+				return fmt.Errorf("opening project database to persist patterns: %w", err)
+			}
+			defer dbConn.Close()
+
+			for i := range entries {
+				e := &entries[i]
+				if e.Status != core.ImportNew {
+					continue
+				}
+				if err := engine.AddPattern(core.ParseRiskTier(e.Tier), e.Pattern, e.Description, source); err != nil {
+					return fmt.Errorf("adding pattern %q: %w", e.Pattern, err)
+				}
+				if _, err := dbConn.InsertCustomPattern(e.Tier, e.Pattern, e.Description, source); err != nil {
+					if !errors.Is(err, db.ErrCustomPatternExists) {
+						return fmt.Errorf("persisting pattern %q: %w", e.Pattern, err)
+					}
+					e.Status = core.ImportDuplicate
+					continue
+				}
+				e.Status = importAdded
+			}
+		}
+
+		counts := make(map[string]int)
+		for _, e := range entries {
+			counts[e.Status]++
+		}
+
+		if isJSONOutput() {
+			if err := output.New(output.Format(GetOutput())).Write(map[string]any{
+				"file":       path,
+				"source":     source,
+				"dry_run":    flagPatternImportDryRun,
+				"added":      counts[importAdded],
+				"new":        counts[core.ImportNew],
+				"duplicates": counts[core.ImportDuplicate],
+				"invalid":    counts[core.ImportInvalid],
+				"skipped":    counts[importSkipped],
+				"patterns":   entries,
+			}); err != nil {
+				return err
+			}
+		} else {
+			for _, e := range entries {
+				if e.Status == core.ImportDuplicate {
+					continue
+				}
+				line := fmt.Sprintf("%-9s  %-9s  %s", e.Status, e.Tier, e.Pattern)
+				if e.Error != "" {
+					line += "  (" + e.Error + ")"
+				}
+				fmt.Println(line)
+			}
+			verb := "Imported"
+			n := counts[importAdded]
+			if flagPatternImportDryRun || invalid > 0 {
+				verb = "Would import"
+				n = counts[core.ImportNew]
+			}
+			output.Notef(os.Stdout, "%s %d pattern(s) from %s: %d duplicate(s), %d invalid, %d skipped\n",
+				verb, n, path, counts[core.ImportDuplicate], invalid, counts[importSkipped])
+		}
+
+		if invalid > 0 {
+			return fmt.Errorf("%d invalid pattern(s) in %s; nothing imported", invalid, path)
+		}
+		return nil
+	},
+}
+
+// confirmSafeImport asks the human at the terminal to allow new safe-tier
+// patterns. It is a variable so tests can answer it.
+var confirmSafeImport = confirmSafeImportOnTTY
+
+// confirmSafeImportOnTTY lists the safe patterns and requires SAFE to be
+// typed at the controlling terminal, so an agent cannot exempt commands
+// from review for itself.
+func confirmSafeImportOnTTY(path string, entries []core.ImportEntry) error {
+	in, out, closeTTY, err := openTTY()
+	if err != nil {
+		return fmt.Errorf("importing safe patterns needs a human at a terminal (use --skip-safe to import the rest): %w", err)
+	}
+	defer closeTTY()
+
+	fmt.Fprintf(out, "%s adds %d safe pattern(s); matching commands will skip review:\n", path, len(entries))
+	for _, e := range entries {
+		fmt.Fprintf(out, "  %s\n", e.Pattern)
+	}
+	fmt.Fprint(out, "Type SAFE to import them: ")
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("reading confirmation: %w", err)
+	}
+	if strings.TrimSpace(answer) != "SAFE" {
+		return fmt.Errorf("safe patterns not confirmed; nothing imported")
+	}
+	return nil
+}
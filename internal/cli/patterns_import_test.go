@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+type patternsImportResponse struct {
+	Source     string `json:"source"`
+	Added      int    `json:"added"`
+	New        int    `json:"new"`
+	Duplicates int    `json:"duplicates"`
+	Invalid    int    `json:"invalid"`
+	Skipped    int    `json:"skipped"`
+	Patterns   []struct {
+		Tier    string `json:"tier"`
+		Pattern string `json:"pattern"`
+		Status  string `json:"status"`
+		Error   string `json:"error"`
+	} `json:"patterns"`
+}
+
+// stubConfirmSafeImport answers the safe-pattern prompt and records that it
+// was asked.
+func stubConfirmSafeImport(t *testing.T, answer error) *int {
+	t.Helper()
+	asked := 0
+	orig := confirmSafeImport
+	confirmSafeImport = func(_ string, entries []core.ImportEntry) error {
+		asked += len(entries)
+		return answer
+	}
+	t.Cleanup(func() { confirmSafeImport = orig })
+	return &asked
+}
+
+func runPatternsImport(t *testing.T, h *testutil.Harness, args ...string) (patternsImportResponse, error) {
+	t.Helper()
+	resetPatternsFlags()
+	args = append([]string{"patterns", "import", "-j"}, args...)
+	stdout, err := executeCommandCapture(t, newTestPatternsCmd(h.DBPath), args...)
+	var resp patternsImportResponse
+	if jsonErr := json.Unmarshal([]byte(stdout), &resp); jsonErr != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s\nerr: %v", jsonErr, stdout, err)
+	}
+	return resp, err
+}
+
+func TestPatternsImportCommand(t *testing.T) {
+	h := testutil.NewHarness(t)
+	asked := stubConfirmSafeImport(t, nil)
+	bundle := h.WriteFile("team.yaml", []byte(`patterns:
+  - tier: critical
+    pattern: ^uniq-import-vault\s+seal
+    description: seals the vault
+  - pattern: ^uniq-import-make\s+deploy
+  - tier: safe
+    pattern: ^uniq-import-make\s+lint$
+  - tier: critical
+    pattern: ^rm\s+(-[rf]+\s+)+/\*
+`), 0o644)
+
+	resp, err := runPatternsImport(t, h, bundle, "--dry-run", "-T", "dangerous")
+	testutil.RequireNoError(t, err, "dry run")
+	if resp.New != 3 || resp.Duplicates != 1 || resp.Added != 0 || *asked != 0 {
+		t.Fatalf("dry run = %+v (asked %d)", resp, *asked)
+	}
+	if n, _ := h.DB.CountCustomPatterns(); n != 0 {
+		t.Fatalf("dry run persisted %d pattern(s)", n)
+	}
+
+	resp, err = runPatternsImport(t, h, bundle, "-T", "dangerous")
+	testutil.RequireNoError(t, err, "import")
+	if resp.Added != 3 || resp.Duplicates != 1 || resp.Source != "import:team.yaml" || *asked != 1 {
+		t.Fatalf("import = %+v (asked %d)", resp, *asked)
+	}
+	rows, err := h.DB.ListCustomPatterns()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tiers := make(map[string]string)
+	for _, row := range rows {
+		if row.Source != "import:team.yaml" {
+			t.Errorf("row %q source = %q", row.Pattern, row.Source)
+		}
+		tiers[row.Pattern] = row.Tier
+	}
+	if tiers[`^uniq-import-vault\s+seal`] != "critical" || tiers[`^uniq-import-make\s+deploy`] != "dangerous" || tiers[`^uniq-import-make\s+lint$`] != "safe" {
+		t.Errorf("persisted tiers = %v", tiers)
+	}
+
+	// A second import finds everything already present.
+	resp, err = runPatternsImport(t, h, bundle, "-T", "dangerous")
+	testutil.RequireNoError(t, err, "re-import")
+	if resp.Added != 0 || resp.Duplicates != 4 || *asked != 1 {
+		t.Errorf("re-import = %+v (asked %d)", resp, *asked)
+	}
+}
+
+func TestPatternsImportCommand_SafeNeedsConfirmation(t *testing.T) {
+	h := testutil.NewHarness(t)
+	asked := stubConfirmSafeImport(t, errors.New("safe patterns not confirmed; nothing imported"))
+	bundle := h.WriteFile("bundle.json", []byte(`{"tiers": {
+  "safe": {"patterns": [{"pattern": "^uniq-confirm-safe$"}]},
+  "dangerous": {"patterns": [{"pattern": "^uniq-confirm-dangerous$"}]}
+}}`), 0o644)
+
+	resetPatternsFlags()
+	_, err := executeCommandCapture(t, newTestPatternsCmd(h.DBPath), "patterns", "import", bundle)
+	if err == nil || !strings.Contains(err.Error(), "not confirmed") {
+		t.Fatalf("expected the refused confirmation to fail the import, got %v", err)
+	}
+	if n, _ := h.DB.CountCustomPatterns(); n != 0 || *asked != 1 {
+		t.Fatalf("refused import persisted %d pattern(s) (asked %d)", n, *asked)
+	}
+
+	resp, err := runPatternsImport(t, h, bundle, "--skip-safe")
+	testutil.RequireNoError(t, err, "import --skip-safe")
+	if resp.Added != 1 || resp.Skipped != 1 || *asked != 1 {
+		t.Fatalf("skip-safe import = %+v (asked %d)", resp, *asked)
+	}
+	rows, _ := h.DB.ListCustomPatterns()
+	if len(rows) != 1 || rows[0].Pattern != "^uniq-confirm-dangerous$" {
+		t.Errorf("persisted rows = %+v", rows)
+	}
+}
+
+func TestPatternsImportCommand_InvalidImportsNothing(t *testing.T) {
+	h := testutil.NewHarness(t)
+	stubConfirmSafeImport(t, nil)
+	bundle := h.WriteFile("bad.yaml", []byte(`patterns:
+  - tier: dangerous
+    pattern: ^uniq-invalid-ok
+  - tier: dangerous
+    pattern: ^uniq-invalid-(
+  - tier: severe
+    pattern: ^uniq-invalid-tier
+`), 0o644)
+
+	resetPatternsFlags()
+	stdout, err := executeCommandCapture(t, newTestPatternsCmd(h.DBPath), "patterns", "import", bundle)
+	if err == nil || !strings.Contains(err.Error(), "2 invalid pattern(s)") {
+		t.Fatalf("expected an invalid-pattern error, got %v", err)
+	}
+	for _, want := range []string{"invalid    dangerous  ^uniq-invalid-(  (", `unknown tier "severe"`, "Would import 1 pattern(s)"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in output:\n%s", want, stdout)
+		}
+	}
+	if n, _ := h.DB.CountCustomPatterns(); n != 0 {
+		t.Errorf("invalid bundle persisted %d pattern(s)", n)
+	}
+
+	resetPatternsFlags()
+	if _, err := executeCommandCapture(t, newTestPatternsCmd(h.DBPath), "patterns", "import", h.WriteFile("empty.yaml", []byte("version: 1\n"), 0o644)); err == nil || !strings.Contains(err.Error(), "no patterns") {
+		t.Errorf("expected an empty-bundle error, got %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/numfmt"
+	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var flagPatternUnusedOlderThan time.Duration
+
+func init() {
+	patternsUnusedCmd.Flags().DurationVar(&flagPatternUnusedOlderThan, "older-than", 0, "only patterns added at least this long ago (e.g. 720h)")
+
+	patternsCmd.AddCommand(patternsUnusedCmd)
+}
+
+var patternsUnusedCmd = &cobra.Command{
+	Use:   "unused",
+	Short: "List custom patterns that have never matched a command",
+	Long: `List the custom patterns (added with 'slb patterns add' or 'slb patterns
+import') that have never classified a command, so teams can prune rules
+that only add noise.
+
+Matches are counted when a request is created and when the hook checks a
+command through the daemon; 'slb patterns list --stats' shows the counts
+for every pattern. Use --older-than to leave out recently added patterns
+that simply have not had a chance to match yet.
+
+Examples:
+  slb patterns unused
+  slb patterns unused --older-than 720h --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagPatternUnusedOlderThan < 0 {
+			return fmt.Errorf("--older-than cannot be negative")
+		}
+
+		dbConn, err := db.OpenAndMigrate(GetDB())
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer dbConn.Close()
+
+		custom, err := dbConn.ListCustomPatterns()
+		if err != nil {
+			return err
+		}
+		hits, err := patternHitsByKey(dbConn)
+		if err != nil {
+			return err
+		}
+
+		cutoff := dbConn.Now().Add(-flagPatternUnusedOlderThan)
+		unused := make([]*db.CustomPattern, 0)
+		for _, p := range custom {
+			if hits[patternHitKey(p.Tier, p.Pattern)] != nil {
+				continue
+			}
+			if flagPatternUnusedOlderThan > 0 && p.CreatedAt.After(cutoff) {
+				continue
+			}
+			unused = append(unused, p)
+		}
+
+		if isJSONOutput() {
+			return output.New(output.Format(GetOutput())).Write(map[string]any{
+				"custom_patterns": len(custom),
+				"unused":          unused,
+			})
+		}
+
+		if len(unused) == 0 {
+			fmt.Printf("All %s custom pattern(s) have matched at least once.\n", numfmt.Count(len(custom)))
+			return nil
+		}
+		fmt.Printf("%s of %s custom pattern(s) never matched:\n", numfmt.Count(len(unused)), numfmt.Count(len(custom)))
+		for _, p := range unused {
+			line := fmt.Sprintf("  %-9s  %s  (added %s", p.Tier, p.Pattern, p.CreatedAt.Local().Format(time.DateOnly))
+			if p.Source != "" {
+				line += " by " + p.Source
+			}
+			fmt.Println(line + ")")
+			if p.Description != "" {
+				fmt.Printf("    # %s\n", p.Description)
+			}
+		}
+		output.Notef(os.Stdout, "\nRemoving a pattern needs human review: slb patterns request-removal <pattern> --reason \"...\"\n")
+		return nil
+	},
+}
+
+// loadPatternHits returns the project's pattern match counts keyed by
+// patternHitKey.
+func loadPatternHits() (map[string]*db.PatternHit, error) {
+	dbConn, err := db.OpenAndMigrate(GetDB())
+	if err != nil {
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+	defer dbConn.Close()
+	return patternHitsByKey(dbConn)
+}
+
+// patternHitsByKey indexes the recorded pattern hits by patternHitKey.
+func patternHitsByKey(dbConn *db.DB) (map[string]*db.PatternHit, error) {
+	list, err := dbConn.ListPatternHits()
+	if err != nil {
+		return nil, err
+	}
+	hits := make(map[string]*db.PatternHit, len(list))
+	for _, h := range list {
+		hits[patternHitKey(string(h.Tier), h.Pattern)] = h
+	}
+	return hits, nil
+}
+
+// patternHitKey identifies a pattern within its tier; the same regex in two
+// tiers is counted separately.
+func patternHitKey(tier, pattern string) string {
+	return strings.ToLower(tier) + "\x00" + pattern
+}
@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestPatternsListCommand_Stats(t *testing.T) {
+	h := testutil.NewHarness(t)
+	for i := 0; i < 3; i++ {
+		if err := h.DB.RecordPatternHits([]*db.PatternHit{{Tier: db.RiskTierCritical, Pattern: `^rm\s+(-[rf]+\s+)+/\*`}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resetPatternsFlags()
+	stdout, err := executeCommandCapture(t, newTestPatternsCmd(h.DBPath), "patterns", "list", "--stats", "-T", "critical", "-j")
+	testutil.RequireNoError(t, err, "patterns list --stats")
+	var result map[string][]struct {
+		Pattern       string     `json:"pattern"`
+		Hits          *int       `json:"hits"`
+		LastMatchedAt *time.Time `json:"last_matched_at"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	var matched, unmatched bool
+	for _, p := range result["critical"] {
+		if p.Hits == nil {
+			t.Fatalf("expected hits on every pattern with --stats, got %+v", p)
+		}
+		switch {
+		case p.Pattern == `^rm\s+(-[rf]+\s+)+/\*`:
+			matched = *p.Hits == 3 && p.LastMatchedAt != nil
+		case *p.Hits == 0 && p.LastMatchedAt == nil:
+			unmatched = true
+		}
+	}
+	if !matched || !unmatched {
+		t.Errorf("unexpected stats (matched %v, unmatched %v):\n%s", matched, unmatched, stdout)
+	}
+
+	resetPatternsFlags()
+	stdout, err = executeCommandCapture(t, newTestPatternsCmd(h.DBPath), "patterns", "list", "--stats")
+	testutil.RequireNoError(t, err, "patterns list --stats")
+	if !strings.Contains(stdout, `^rm\s+(-[rf]+\s+)+/\*  (3 hits, last `) || !strings.Contains(stdout, "(never matched)") {
+		t.Errorf("unexpected text output:\n%s", stdout)
+	}
+
+	// Without --stats the counts are left out.
+	resetPatternsFlags()
+	stdout, err = executeCommandCapture(t, newTestPatternsCmd(h.DBPath), "patterns", "list", "-T", "critical", "-j")
+	testutil.RequireNoError(t, err, "patterns list")
+	if strings.Contains(stdout, `"hits"`) {
+		t.Errorf("expected no hits without --stats:\n%s", stdout)
+	}
+}
+
+func TestPatternsUnusedCommand(t *testing.T) {
+	h := testutil.NewHarness(t)
+	insert := func(tier, pattern, source string) {
+		t.Helper()
+		if _, err := h.DB.InsertCustomPattern(tier, pattern, "", source); err != nil {
+			t.Fatal(err)
+		}
+	}
+	insert("dangerous", `^uniq-unused-deploy`, "agent")
+	insert("caution", `^uniq-unused-old`, "import:team.yaml")
+	insert("critical", `^uniq-unused-used`, "agent")
+	if err := h.DB.RecordPatternHits([]*db.PatternHit{{Tier: db.RiskTierCritical, Pattern: `^uniq-unused-used`}}); err != nil {
+		t.Fatal(err)
+	}
+	// A hit in another tier does not count for this pattern.
+	if err := h.DB.RecordPatternHits([]*db.PatternHit{{Tier: db.RiskTierCritical, Pattern: `^uniq-unused-deploy`}}); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-60 * 24 * time.Hour).UTC().Format(time.RFC3339)
+	if _, err := h.DB.Exec(`UPDATE custom_patterns SET created_at = ? WHERE pattern = ?`, old, `^uniq-unused-old`); err != nil {
+		t.Fatal(err)
+	}
+
+	unused := func(args ...string) []string {
+		t.Helper()
+		resetPatternsFlags()
+		args = append([]string{"patterns", "unused", "-j"}, args...)
+		stdout, err := executeCommandCapture(t, newTestPatternsCmd(h.DBPath), args...)
+		testutil.RequireNoError(t, err, "patterns unused")
+		var resp struct {
+			CustomPatterns int                 `json:"custom_patterns"`
+			Unused         []*db.CustomPattern `json:"unused"`
+		}
+		if err := json.Unmarshal([]byte(stdout), &resp); err != nil {
+			t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+		}
+		if resp.CustomPatterns != 3 {
+			t.Errorf("custom_patterns = %d, want 3", resp.CustomPatterns)
+		}
+		var patterns []string
+		for _, p := range resp.Unused {
+			patterns = append(patterns, p.Pattern)
+		}
+		return patterns
+	}
+
+	if got := unused(); strings.Join(got, ",") != `^uniq-unused-old,^uniq-unused-deploy` {
+		t.Errorf("unused = %v", got)
+	}
+	if got := unused("--older-than", "720h"); strings.Join(got, ",") != `^uniq-unused-old` {
+		t.Errorf("unused --older-than 720h = %v", got)
+	}
+
+	resetPatternsFlags()
+	stdout, err := executeCommandCapture(t, newTestPatternsCmd(h.DBPath), "patterns", "unused")
+	testutil.RequireNoError(t, err, "patterns unused")
+	for _, want := range []string{"2 of 3 custom pattern(s) never matched", `dangerous  ^uniq-unused-deploy  (added `, "by import:team.yaml)", "request-removal"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in output:\n%s", want, stdout)
+		}
+	}
+
+	resetPatternsFlags()
+	if _, err := executeCommandCapture(t, newTestPatternsCmd(h.DBPath), "patterns", "unused", "--older-than", "-1h"); err == nil {
+		t.Error("expected an error for a negative --older-than")
+	}
+}
@@ -2,6 +2,8 @@ package cli
 
 import (
 	"encoding/json"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -34,8 +36,11 @@ func newTestPatternsCmd(dbPath string) *cobra.Command {
 	listCmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all patterns grouped by tier",
+		Args:  cobra.MaximumNArgs(1),
 		RunE:  patternsListCmd.RunE,
 	}
+	listCmd.Flags().BoolVar(&flagPatternPack, "pack", false, "list pattern packs")
+	listCmd.Flags().BoolVar(&flagPatternStats, "stats", false, "show match statistics")
 
 	testCmd := &cobra.Command{
 		Use:   "test <command>",
@@ -99,7 +104,22 @@ func newTestPatternsCmd(dbPath string) *cobra.Command {
 		RunE:  patternsVersionCmd.RunE,
 	}
 
-	patCmd.AddCommand(listCmd, testCmd, addCmd, removeCmd, requestRemovalCmd, suggestCmd, exportCmd, versionCmd)
+	importCmd := &cobra.Command{
+		Use:  "import <file>",
+		Args: cobra.ExactArgs(1),
+		RunE: patternsImportCmd.RunE,
+	}
+	importCmd.Flags().BoolVar(&flagPatternImportDryRun, "dry-run", false, "dry run")
+	importCmd.Flags().BoolVar(&flagPatternImportSkipSafe, "skip-safe", false, "skip safe patterns")
+
+	unusedCmd := &cobra.Command{
+		Use:  "unused",
+		Args: cobra.NoArgs,
+		RunE: patternsUnusedCmd.RunE,
+	}
+	unusedCmd.Flags().DurationVar(&flagPatternUnusedOlderThan, "older-than", 0, "minimum pattern age")
+
+	patCmd.AddCommand(listCmd, testCmd, addCmd, removeCmd, requestRemovalCmd, suggestCmd, exportCmd, versionCmd, importCmd, unusedCmd)
 	root.AddCommand(patCmd, checkCmdTest)
 
 	return root
@@ -115,6 +135,11 @@ func resetPatternsFlags() {
 	flagPatternExitCode = false
 	flagPatternFormat = "json"
 	flagPatternOutputFile = ""
+	flagPatternPack = false
+	flagPatternImportDryRun = false
+	flagPatternImportSkipSafe = false
+	flagPatternStats = false
+	flagPatternUnusedOlderThan = 0
 }
 
 func TestPatternsListCommand_ListsPatterns(t *testing.T) {
@@ -942,3 +967,133 @@ func TestPatternsVersionCommand_DeterministicHash(t *testing.T) {
 		t.Errorf("hash not deterministic: %v != %v", result1["sha256"], result2["sha256"])
 	}
 }
+
+func TestPatternsListCommand_Packs(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetPatternsFlags()
+
+	cmd := newTestPatternsCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "patterns", "list", "--pack", "-C", h.ProjectDir, "-j")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var packs []packJSON
+	if err := json.Unmarshal([]byte(stdout), &packs); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	names := make([]string, 0, len(packs))
+	for _, p := range packs {
+		names = append(names, p.Name)
+		if p.PatternCount == 0 {
+			t.Errorf("pack %q reports no patterns", p.Name)
+		}
+	}
+	if got := strings.Join(names, ","); got != "database,deploy,kubernetes,nodejs,terraform,windows-powershell" {
+		t.Fatalf("unexpected packs: %s", got)
+	}
+}
+
+func TestPatternsListCommand_PackPatterns(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetPatternsFlags()
+
+	cmd := newTestPatternsCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "patterns", "list", "--pack", "kubernetes", "-C", h.ProjectDir, "-j")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var result map[string][]patternJSON
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if len(result["dangerous"]) == 0 {
+		t.Fatalf("expected dangerous kubernetes patterns, got %v", result)
+	}
+	if _, ok := result["safe"]; ok {
+		t.Fatalf("packs must not contribute safe patterns")
+	}
+	for _, p := range result["dangerous"] {
+		if p.Pack != "kubernetes" || p.Source != core.PatternSourcePack {
+			t.Errorf("pattern %q missing pack provenance: %+v", p.Pattern, p)
+		}
+	}
+
+	resetPatternsFlags()
+	cmd = newTestPatternsCmd(h.DBPath)
+	if _, err := executeCommandCapture(t, cmd, "patterns", "list", "--pack", "nope", "-C", h.ProjectDir, "-j"); err == nil {
+		t.Fatal("expected error for unknown pack")
+	}
+
+	resetPatternsFlags()
+	cmd = newTestPatternsCmd(h.DBPath)
+	if _, err := executeCommandCapture(t, cmd, "patterns", "list", "kubernetes", "-C", h.ProjectDir, "-j"); err == nil {
+		t.Fatal("expected error for positional argument without --pack")
+	}
+}
+
+func TestLoadCustomPatternsIntoDefaultEngine_EnablesConfiguredPacks(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetPatternsFlags()
+
+	engine := core.GetDefaultEngine()
+	t.Cleanup(engine.LoadDefaultPatterns)
+
+	if err := os.MkdirAll(filepath.Join(h.ProjectDir, ".slb"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	cfg := "[patterns]\npacks = [\"kubernetes\"]\n"
+	if err := os.WriteFile(filepath.Join(h.ProjectDir, ".slb", "config.toml"), []byte(cfg), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cmd := newTestPatternsCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "patterns", "test", "kubectl drain node1", "-C", h.ProjectDir, "-j")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var result struct {
+		Tier string `json:"tier"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if result.Tier != "dangerous" {
+		t.Fatalf("expected kubernetes pack to classify drain as dangerous, got %q", result.Tier)
+	}
+	if got := engine.EnabledPacks(); len(got) != 1 || got[0] != "kubernetes" {
+		t.Fatalf("EnabledPacks = %v", got)
+	}
+}
+
+func TestPatternsExportCommand_RegoAndSemgrep(t *testing.T) {
+	h := testutil.NewHarness(t)
+
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{"rego", []string{"--format=rego", "package slb.patterns", "critical_patterns := [", "deny contains msg if"}},
+		{"opa", []string{"--format=rego", "package slb.patterns"}},
+		{"semgrep", []string{"--format=semgrep", "rules:", "id: slb-critical-001", "pattern-regex: "}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			resetPatternsFlags()
+			stdout, err := executeCommandCapture(t, newTestPatternsCmd(h.DBPath), "patterns", "export", "--format="+tt.format)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !strings.Contains(stdout, "SHA256: ") {
+				t.Error("expected the pattern hash in the export header")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(stdout, want) {
+					t.Errorf("expected %q in %s export", want, tt.format)
+				}
+			}
+		})
+	}
+}
@@ -8,17 +8,20 @@ import (
 	"github.com/Dicklesworthstone/slb/internal/config"
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
 	"github.com/spf13/cobra"
 )
 
 var (
 	flagPendingAllProjects bool
 	flagPendingReviewPool  bool
+	flagPendingSort        string
 )
 
 func init() {
 	pendingCmd.Flags().BoolVar(&flagPendingAllProjects, "all-projects", false, "list pending requests across all projects")
 	pendingCmd.Flags().BoolVar(&flagPendingReviewPool, "review-pool", false, "only show requests you can review (not your own)")
+	pendingCmd.Flags().StringVar(&flagPendingSort, "sort", queueSortCreated, queueSortUsage)
 
 	rootCmd.AddCommand(pendingCmd)
 }
@@ -31,11 +34,17 @@ var pendingCmd = &cobra.Command{
 By default, shows pending requests for the current project.
 Use --all-projects to see pending requests across all projects.
 Use --review-pool to filter to requests you can review (excludes your own).
+Use --sort score to review the riskiest requests first, or --sort priority
+for the most urgent: priority combines tier, risk score, age, whether the
+requestor is blocked waiting, and review SLA breaches.
 
 When [general.cross_project_reviews] is true and review_pool is configured,
 --review-pool will pull requests from those projects in addition to the
 current project.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateQueueSort(flagPendingSort); err != nil {
+			return err
+		}
 		project, err := projectPath()
 		if err != nil {
 			return err
@@ -86,37 +95,61 @@ current project.`,
 
 		// Build response
 		type pendingView struct {
-			RequestID       string `json:"request_id"`
-			Command         string `json:"command"`
-			CommandRedacted string `json:"command_redacted,omitempty"`
-			RiskTier        string `json:"risk_tier"`
-			MinApprovals    int    `json:"min_approvals"`
-			RequestorAgent  string `json:"requestor_agent"`
-			RequestorModel  string `json:"requestor_model"`
-			ProjectPath     string `json:"project_path"`
-			Reason          string `json:"reason,omitempty"`
-			CreatedAt       string `json:"created_at"`
-			ExpiresAt       string `json:"expires_at,omitempty"`
+			RequestID       string   `json:"request_id"`
+			Command         string   `json:"command"`
+			CommandRedacted string   `json:"command_redacted,omitempty"`
+			RiskTier        string   `json:"risk_tier"`
+			RiskScore       int      `json:"risk_score"`
+			MinApprovals    int      `json:"min_approvals"`
+			RequestorAgent  string   `json:"requestor_agent"`
+			RequestorModel  string   `json:"requestor_model"`
+			ProjectPath     string   `json:"project_path"`
+			Reason          string   `json:"reason,omitempty"`
+			CreatedAt       string   `json:"created_at"`
+			ExpiresAt       string   `json:"expires_at,omitempty"`
+			Priority        int      `json:"priority"`
+			Waiting         bool     `json:"requestor_waiting,omitempty"`
+			SLADueAt        string   `json:"sla_due_at,omitempty"`
+			SLABreached     bool     `json:"sla_breached,omitempty"`
+			AwaitingHuman   bool     `json:"awaiting_human,omitempty"`
+			SeenBy          []string `json:"seen_by,omitempty"`
+			Seen            string   `json:"seen,omitempty"`
+			Version         int      `json:"version"`
 		}
 
-		resp := make([]pendingView, 0, len(requests))
-		for _, r := range requests {
+		awaitingHuman := awaitingHumanSet(dbConn, requests)
+		seenBy := undecidedViewerSet(dbConn, requests)
+
+		queue := reviewQueue(dbConn, requests, cfg, flagPendingSort, time.Now())
+
+		resp := make([]pendingView, 0, len(queue))
+		for _, item := range queue {
+			r := item.Request
 			view := pendingView{
 				RequestID:      r.ID,
 				Command:        r.Command.Raw,
 				RiskTier:       string(r.RiskTier),
+				RiskScore:      r.RiskScore,
 				MinApprovals:   r.MinApprovals,
 				RequestorAgent: r.RequestorAgent,
 				RequestorModel: r.RequestorModel,
 				ProjectPath:    r.ProjectPath,
 				Reason:         r.Justification.Reason,
-				CreatedAt:      r.CreatedAt.Format(time.RFC3339),
+				CreatedAt:      timefmt.Format(r.CreatedAt),
+				Priority:       item.Priority,
+				Waiting:        item.Waiting,
+				SLADueAt:       slaDueAt(item.SLA),
+				SLABreached:    item.SLA != nil && item.SLA.Breached,
+				AwaitingHuman:  awaitingHuman[r.ID],
+				SeenBy:         seenBy[r.ID],
+				Seen:           seenSummary(seenBy[r.ID]),
+				Version:        r.Version,
 			}
 			if r.Command.DisplayRedacted != "" {
 				view.CommandRedacted = r.Command.DisplayRedacted
 			}
 			if r.ExpiresAt != nil {
-				view.ExpiresAt = r.ExpiresAt.Format(time.RFC3339)
+				view.ExpiresAt = timefmt.Format(*r.ExpiresAt)
 			}
 			resp = append(resp, view)
 		}
@@ -126,6 +159,47 @@ current project.`,
 	},
 }
 
+// awaitingHumanSet returns the IDs of requests that were paged to a human
+// after reviewer inactivity. Lookup failures are treated as "none flagged".
+func awaitingHumanSet(dbConn *db.DB, requests []*db.Request) map[string]bool {
+	ids := make([]string, 0, len(requests))
+	for _, r := range requests {
+		ids = append(ids, r.ID)
+	}
+	flagged, err := dbConn.AwaitingHumanRequestIDs(ids)
+	if err != nil {
+		return map[string]bool{}
+	}
+	return flagged
+}
+
+// undecidedViewerSet returns, per request, the reviewers who opened it
+// without deciding. Lookup failures are treated as "nobody looked".
+func undecidedViewerSet(dbConn *db.DB, requests []*db.Request) map[string][]string {
+	ids := make([]string, 0, len(requests))
+	for _, r := range requests {
+		ids = append(ids, r.ID)
+	}
+	viewers, err := dbConn.UndecidedViewers(ids)
+	if err != nil {
+		return map[string][]string{}
+	}
+	return viewers
+}
+
+// seenSummary describes reviewers who looked without deciding, e.g.
+// "seen by 2 reviewers, no decision"; "" when there are none.
+func seenSummary(viewers []string) string {
+	switch len(viewers) {
+	case 0:
+		return ""
+	case 1:
+		return "seen by 1 reviewer, no decision"
+	default:
+		return fmt.Sprintf("seen by %d reviewers, no decision", len(viewers))
+	}
+}
+
 // dedupeStrings returns a copy with duplicates removed, preserving order.
 func dedupeStrings(in []string) []string {
 	seen := make(map[string]bool, len(in))
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/Dicklesworthstone/slb/internal/testutil"
@@ -39,6 +40,7 @@ func resetPendingFlags() {
 	flagConfig = ""
 	flagPendingAllProjects = false
 	flagPendingReviewPool = false
+	flagPendingSort = "created"
 }
 
 func TestPendingCommand_ListsPendingRequests(t *testing.T) {
@@ -87,6 +89,41 @@ func TestPendingCommand_ListsPendingRequests(t *testing.T) {
 	}
 }
 
+func TestPendingCommand_ShowsAwaitingHuman(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetPendingFlags()
+
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	paged := testutil.MakeRequest(t, h.DB, sess,
+		testutil.WithCommand("rm -rf ./build", h.ProjectDir, true),
+		testutil.WithRisk(db.RiskTierDangerous),
+	)
+	testutil.MakeRequest(t, h.DB, sess,
+		testutil.WithCommand("git push --force", h.ProjectDir, true),
+		testutil.WithRisk(db.RiskTierDangerous),
+	)
+	if _, err := h.DB.MarkAwaitingHuman(&db.HumanEscalation{RequestID: paged.ID, Reason: "no reviewer"}); err != nil {
+		t.Fatalf("MarkAwaitingHuman: %v", err)
+	}
+
+	cmd := newTestPendingCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "pending", "-C", h.ProjectDir, "-j")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var result []map[string]any
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	for _, r := range result {
+		want := r["request_id"] == paged.ID
+		if got, _ := r["awaiting_human"].(bool); got != want {
+			t.Errorf("request %v: awaiting_human = %v, want %v", r["request_id"], got, want)
+		}
+	}
+}
+
 func TestPendingCommand_EmptyList(t *testing.T) {
 	h := testutil.NewHarness(t)
 	resetPendingFlags()
@@ -108,6 +145,94 @@ func TestPendingCommand_EmptyList(t *testing.T) {
 	}
 }
 
+func TestPendingCommand_SortByScore(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetPendingFlags()
+
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	for _, score := range []int{40, 90, 65} {
+		testutil.MakeRequest(t, h.DB, sess,
+			testutil.WithCommand("rm -rf ./build", h.ProjectDir, true),
+			testutil.WithRisk(db.RiskTierDangerous),
+			testutil.WithRiskScore(score),
+		)
+	}
+
+	stdout, err := executeCommandCapture(t, newTestPendingCmd(h.DBPath), "pending", "-C", h.ProjectDir, "--sort", "score", "-j")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var result []struct {
+		RiskScore int `json:"risk_score"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if len(result) != 3 || result[0].RiskScore != 90 || result[1].RiskScore != 65 || result[2].RiskScore != 40 {
+		t.Errorf("expected requests ordered by score, got %+v", result)
+	}
+
+	resetPendingFlags()
+	if _, err := executeCommandCapture(t, newTestPendingCmd(h.DBPath), "pending", "-C", h.ProjectDir, "--sort", "tier"); err == nil {
+		t.Error("expected an error for an unknown --sort")
+	}
+}
+
+func TestPendingCommand_SortByPriority(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetPendingFlags()
+
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	overdue := testutil.MakeRequest(t, h.DB, sess,
+		testutil.WithCommand("rm -rf ./build", h.ProjectDir, true),
+		testutil.WithRisk(db.RiskTierDangerous),
+		testutil.WithRiskScore(10),
+	)
+	blocked := testutil.MakeRequest(t, h.DB, sess,
+		testutil.WithCommand("git stash drop", h.ProjectDir, true),
+		testutil.WithRisk(db.RiskTierCaution),
+		testutil.WithRiskScore(20),
+	)
+	fresh := testutil.MakeRequest(t, h.DB, sess,
+		testutil.WithCommand("git push --force", h.ProjectDir, true),
+		testutil.WithRisk(db.RiskTierDangerous),
+		testutil.WithRiskScore(60),
+	)
+	// Past the default 10 minute dangerous-tier review SLA.
+	old := time.Now().Add(-20 * time.Minute).UTC().Format(time.RFC3339)
+	if _, err := h.DB.Exec(`UPDATE requests SET created_at = ? WHERE id = ?`, old, overdue.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.DB.MarkRequestorWaiting(blocked.ID, sess.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, err := executeCommandCapture(t, newTestPendingCmd(h.DBPath), "pending", "-C", h.ProjectDir, "--sort", "priority", "-j")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var result []struct {
+		RequestID   string `json:"request_id"`
+		Priority    int    `json:"priority"`
+		Waiting     bool   `json:"requestor_waiting"`
+		SLADueAt    string `json:"sla_due_at"`
+		SLABreached bool   `json:"sla_breached"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if len(result) != 3 || result[0].RequestID != overdue.ID || result[1].RequestID != fresh.ID || result[2].RequestID != blocked.ID {
+		t.Fatalf("unexpected priority order: %+v", result)
+	}
+	if !result[0].SLABreached || result[0].SLADueAt == "" || result[1].SLABreached {
+		t.Errorf("unexpected SLA flags: %+v", result)
+	}
+	// Caution has no review SLA by default.
+	if !result[2].Waiting || result[2].SLADueAt != "" {
+		t.Errorf("unexpected blocked request: %+v", result[2])
+	}
+}
+
 func TestPendingCommand_OnlyShowsPending(t *testing.T) {
 	h := testutil.NewHarness(t)
 	resetPendingFlags()
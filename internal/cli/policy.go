@@ -0,0 +1,361 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var flagPolicyFile string
+
+func init() {
+	policyTestCmd.Flags().StringVar(&flagPolicyFile, "policy", "", "candidate policy file (default: the project's current policy)")
+
+	policyCmd.AddCommand(policyTestCmd)
+	rootCmd.AddCommand(policyCmd)
+}
+
+var policyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Work with classification policies",
+	Long: `Work with the classification policy: the pattern set (builtin patterns,
+pattern packs and custom patterns) and the agents.tier_overrides applied
+after it.`,
+}
+
+var policyTestCmd = &cobra.Command{
+	Use:   "test <scenario-file>",
+	Short: "Run scenarios against a policy and report which rules fired",
+	Long: `Classify each scenario in a TOML scenario file and report every rule
+that fired and the final tier, without creating requests.
+
+Without --policy, scenarios run against the project's current policy. With
+--policy, they run against a candidate policy file, and each result also
+shows the tier under the current policy so changes stand out before rollout.
+
+Scenario file:
+
+  [[scenarios]]
+  name = "force push to main"
+  command = "git push --force origin main"
+  cwd = "."                  # relative to the scenario file
+  branch = "main"            # default: the branch checked out in cwd
+  expect = "critical"        # safe, caution, dangerous, critical or none
+  [scenarios.context]
+  program = "codex-cli"
+  model = "gpt-4o"
+
+Policy file:
+
+  base = "builtin"           # builtin (default), project or none
+  packs = ["kubernetes"]
+  tier_overrides = ["branch:main=critical", "codex-cli=dangerous"]
+
+  [[patterns]]
+  tier = "dangerous"
+  pattern = '^docker\s+system\s+prune'
+  description = "removes all unused images"
+
+The "project" base starts from the current policy: builtins, configured
+packs, custom patterns and configured tier overrides.
+
+Exits non-zero when a scenario's expected tier does not match.`,
+	Example: `  slb policy test scenarios.toml
+  slb policy test scenarios.toml --policy candidate.toml --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		scenarios, err := loadPolicyScenarios(args[0])
+		if err != nil {
+			return err
+		}
+		current, err := currentPolicy()
+		if err != nil {
+			return err
+		}
+		policy, source := current, "current"
+		if flagPolicyFile != "" {
+			if policy, err = loadPolicyFile(flagPolicyFile, current); err != nil {
+				return err
+			}
+			source = flagPolicyFile
+		}
+
+		type scenarioResult struct {
+			*core.PolicyTestResult
+			CurrentTier string `json:"current_tier,omitempty"`
+			Changed     bool   `json:"changed,omitempty"`
+		}
+		results := make([]scenarioResult, 0, len(scenarios))
+		failed, changed := 0, 0
+		for _, s := range scenarios {
+			r := scenarioResult{PolicyTestResult: policy.Test(s)}
+			if policy != current {
+				r.CurrentTier = current.Test(s).Tier
+				r.Changed = r.CurrentTier != r.Tier
+			}
+			if !r.Passed {
+				failed++
+			}
+			if r.Changed {
+				changed++
+			}
+			results = append(results, r)
+		}
+
+		if isJSONOutput() {
+			resp := map[string]any{
+				"policy":      source,
+				"policy_hash": core.PolicyHash(policy.Engine, policy.TierOverrides),
+				"scenarios":   results,
+				"passed":      len(results) - failed,
+				"failed":      failed,
+			}
+			if policy != current {
+				resp["changed"] = changed
+			}
+			if err := output.New(output.Format(GetOutput())).Write(resp); err != nil {
+				return err
+			}
+		} else {
+			for _, r := range results {
+				printPolicyTestResult(r.PolicyTestResult, r.CurrentTier, r.Changed)
+			}
+			summary := fmt.Sprintf("%d scenario(s): %d passed, %d failed", len(results), len(results)-failed, failed)
+			if policy != current {
+				summary += fmt.Sprintf("; %d changed from the current policy", changed)
+			}
+			fmt.Println(summary)
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("%d of %d scenario(s) did not get their expected tier", failed, len(results))
+		}
+		return nil
+	},
+}
+
+// printPolicyTestResult prints one scenario's result.
+func printPolicyTestResult(r *core.PolicyTestResult, currentTier string, changed bool) {
+	status := "PASS"
+	if !r.Passed {
+		status = "FAIL"
+	}
+	name := r.Scenario.Name
+	if name == "" {
+		name = r.Scenario.Command
+	}
+	line := fmt.Sprintf("%s  %s: %s", status, name, strings.ToUpper(r.Tier))
+	if r.Scenario.Expect != "" {
+		line += fmt.Sprintf(" (expected %s)", strings.ToLower(r.Scenario.Expect))
+	}
+	fmt.Println(line)
+	fmt.Printf("  Command: %s\n", r.Scenario.Command)
+
+	var context []string
+	if r.Scenario.Cwd != "" {
+		context = append(context, "cwd "+r.Scenario.Cwd)
+	}
+	if r.Branch != "" {
+		context = append(context, "branch "+r.Branch)
+	}
+	if r.Scenario.Program != "" {
+		context = append(context, "program "+r.Scenario.Program)
+	}
+	if r.Scenario.Model != "" {
+		context = append(context, "model "+r.Scenario.Model)
+	}
+	if len(context) > 0 {
+		fmt.Printf("  Context: %s\n", strings.Join(context, ", "))
+	}
+
+	if len(r.Fired) == 0 {
+		fmt.Println("  Fired:   (no rules)")
+	} else {
+		fmt.Println("  Fired:")
+		for _, f := range r.Fired {
+			mark := " "
+			if f.Decisive {
+				mark = "*"
+			}
+			label := string(f.Tier)
+			if f.Kind == core.FiredRuleTierOverride {
+				label = "override"
+			}
+			line := fmt.Sprintf("    %s %-9s %s", mark, label, f.Rule)
+			switch {
+			case f.Pack != "":
+				line += " (pack " + f.Pack + ")"
+			case f.Source != "":
+				line += " (" + f.Source + ")"
+			}
+			if f.Segment != "" {
+				line += " in: " + f.Segment
+			}
+			fmt.Println(line)
+		}
+	}
+	for _, note := range r.Notes {
+		fmt.Printf("  Note:    %s\n", note)
+	}
+	if changed {
+		fmt.Printf("  Changed: %s under the current policy\n", strings.ToUpper(currentTier))
+	}
+	fmt.Println()
+}
+
+// policyFile is a candidate policy for `slb policy test`.
+type policyFile struct {
+	// Base is what the policy starts from: builtin, project or none.
+	Base          string              `toml:"base"`
+	Packs         []string            `toml:"packs"`
+	TierOverrides []string            `toml:"tier_overrides"`
+	Patterns      []policyFilePattern `toml:"patterns"`
+}
+
+type policyFilePattern struct {
+	Tier        string `toml:"tier"`
+	Pattern     string `toml:"pattern"`
+	Description string `toml:"description"`
+}
+
+// scenarioFile is the scenario file for `slb policy test`.
+type scenarioFile struct {
+	Scenarios []struct {
+		Name    string `toml:"name"`
+		Command string `toml:"command"`
+		Cwd     string `toml:"cwd"`
+		Branch  string `toml:"branch"`
+		Expect  string `toml:"expect"`
+		Context struct {
+			Program string `toml:"program"`
+			Model   string `toml:"model"`
+		} `toml:"context"`
+	} `toml:"scenarios"`
+}
+
+// decodeStrictTOML decodes a TOML file, refusing keys the target does not
+// have so a misspelled key is not silently ignored.
+func decodeStrictTOML(path string, v any) error {
+	md, err := toml.DecodeFile(path, v)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, 0, len(undecoded))
+		for _, k := range undecoded {
+			keys = append(keys, k.String())
+		}
+		return fmt.Errorf("reading %s: unknown key(s) %s", path, strings.Join(keys, ", "))
+	}
+	return nil
+}
+
+// loadPolicyScenarios reads a scenario file. Relative cwds are resolved
+// against the file's directory; an empty cwd is the current directory.
+func loadPolicyScenarios(path string) ([]core.PolicyScenario, error) {
+	var f scenarioFile
+	if err := decodeStrictTOML(path, &f); err != nil {
+		return nil, err
+	}
+	if len(f.Scenarios) == 0 {
+		return nil, fmt.Errorf("reading %s: no [[scenarios]]", path)
+	}
+	dir := filepath.Dir(path)
+	wd, _ := os.Getwd()
+
+	scenarios := make([]core.PolicyScenario, 0, len(f.Scenarios))
+	for _, raw := range f.Scenarios {
+		s := core.PolicyScenario{
+			Name:    raw.Name,
+			Command: raw.Command,
+			Cwd:     raw.Cwd,
+			Branch:  raw.Branch,
+			Program: raw.Context.Program,
+			Model:   raw.Context.Model,
+			Expect:  strings.ToLower(strings.TrimSpace(raw.Expect)),
+		}
+		switch {
+		case s.Cwd == "":
+			s.Cwd = wd
+		case !filepath.IsAbs(s.Cwd):
+			if abs, err := filepath.Abs(filepath.Join(dir, s.Cwd)); err == nil {
+				s.Cwd = abs
+			}
+		}
+		if err := core.ValidateScenario(s); err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		scenarios = append(scenarios, s)
+	}
+	return scenarios, nil
+}
+
+// currentPolicy returns the project's policy as request creation applies
+// it: builtins, configured packs, custom patterns and tier overrides.
+func currentPolicy() (*core.Policy, error) {
+	project, err := projectPath()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := config.Load(config.LoadOptions{ProjectDir: project, ConfigPath: flagConfig})
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+	if _, err := loadCustomPatternsIntoDefaultEngine(); err != nil {
+		return nil, fmt.Errorf("loading custom patterns: %w", err)
+	}
+	return &core.Policy{Engine: core.GetDefaultEngine(), TierOverrides: agentTierOverrides(cfg)}, nil
+}
+
+// loadPolicyFile reads a candidate policy. A "project" base starts from a
+// copy of current, which is left unchanged.
+func loadPolicyFile(path string, current *core.Policy) (*core.Policy, error) {
+	var f policyFile
+	if err := decodeStrictTOML(path, &f); err != nil {
+		return nil, err
+	}
+
+	policy := &core.Policy{}
+	switch strings.ToLower(strings.TrimSpace(f.Base)) {
+	case "", "builtin":
+		policy.Engine = core.NewPatternEngine()
+	case "project":
+		policy.Engine = current.Engine.Clone()
+		policy.TierOverrides = append(policy.TierOverrides, current.TierOverrides...)
+	case "none":
+		policy.Engine = &core.PatternEngine{}
+	default:
+		return nil, fmt.Errorf("reading %s: unknown base %q (want builtin, project or none)", path, f.Base)
+	}
+
+	for _, name := range f.Packs {
+		if err := policy.Engine.EnablePack(strings.TrimSpace(name)); err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+	}
+	for _, p := range f.Patterns {
+		tier := core.ParseRiskTier(p.Tier)
+		if tier == "" {
+			return nil, fmt.Errorf("reading %s: pattern %q: unknown tier %q", path, p.Pattern, p.Tier)
+		}
+		if strings.TrimSpace(p.Pattern) == "" {
+			return nil, fmt.Errorf("reading %s: pattern with tier %q is empty", path, p.Tier)
+		}
+		if err := policy.Engine.AddPattern(tier, p.Pattern, p.Description, "policy"); err != nil {
+			return nil, fmt.Errorf("reading %s: pattern %q: %w", path, p.Pattern, err)
+		}
+	}
+	overrides, err := core.ParseTierOverrides(f.TierOverrides)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	policy.TierOverrides = append(policy.TierOverrides, overrides...)
+	return policy, nil
+}
@@ -0,0 +1,187 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+	"github.com/spf13/cobra"
+)
+
+// newTestPolicyCmd creates a fresh policy command tree for testing.
+func newTestPolicyCmd(dbPath string) *cobra.Command {
+	root := &cobra.Command{
+		Use:           "slb",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	root.PersistentFlags().StringVar(&flagDB, "db", dbPath, "database path")
+	root.PersistentFlags().StringVarP(&flagOutput, "output", "o", "text", "output format")
+	root.PersistentFlags().BoolVarP(&flagJSON, "json", "j", false, "json output")
+	root.PersistentFlags().StringVarP(&flagProject, "project", "C", "", "project directory")
+	root.PersistentFlags().StringVarP(&flagConfig, "config", "c", "", "config file")
+
+	polCmd := &cobra.Command{Use: "policy"}
+	testCmd := &cobra.Command{
+		Use:  "test <scenario-file>",
+		Args: policyTestCmd.Args,
+		RunE: policyTestCmd.RunE,
+	}
+	testCmd.Flags().StringVar(&flagPolicyFile, "policy", "", "candidate policy file")
+	polCmd.AddCommand(testCmd)
+	root.AddCommand(polCmd)
+	return root
+}
+
+func resetPolicyFlags() {
+	flagDB = ""
+	flagOutput = "text"
+	flagJSON = false
+	flagProject = ""
+	flagConfig = ""
+	flagPolicyFile = ""
+}
+
+func writePolicyTestFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const policyTestScenarios = `
+[[scenarios]]
+name = "clean on main"
+command = "rm -rf ./build"
+cwd = "."
+branch = "main"
+expect = "critical"
+[scenarios.context]
+program = "codex-cli"
+
+[[scenarios]]
+name = "prune"
+command = "docker system prune"
+expect = "dangerous"
+`
+
+func TestPolicyTestCommand_CandidatePolicy(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetPolicyFlags()
+
+	dir := t.TempDir()
+	scenarios := writePolicyTestFile(t, dir, "scenarios.toml", policyTestScenarios)
+	policy := writePolicyTestFile(t, dir, "policy.toml", `
+tier_overrides = ["branch:main=critical"]
+
+[[patterns]]
+tier = "dangerous"
+pattern = '^docker\s+system\s+prune'
+description = "removes unused images"
+`)
+
+	cmd := newTestPolicyCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "policy", "test", scenarios, "--policy", policy, "-C", h.ProjectDir, "-j")
+	testutil.RequireNoError(t, err, "policy test")
+
+	var resp struct {
+		Policy     string `json:"policy"`
+		PolicyHash string `json:"policy_hash"`
+		Passed     int    `json:"passed"`
+		Failed     int    `json:"failed"`
+		Changed    int    `json:"changed"`
+		Scenarios  []struct {
+			Tier        string `json:"tier"`
+			Passed      bool   `json:"passed"`
+			Branch      string `json:"branch"`
+			CurrentTier string `json:"current_tier"`
+			Changed     bool   `json:"changed"`
+			Fired       []struct {
+				Kind     string `json:"kind"`
+				Rule     string `json:"rule"`
+				Source   string `json:"source"`
+				Decisive bool   `json:"decisive"`
+			} `json:"fired"`
+		} `json:"scenarios"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &resp); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if resp.Policy != policy || resp.PolicyHash == "" || resp.Passed != 2 || resp.Failed != 0 || resp.Changed != 2 || len(resp.Scenarios) != 2 {
+		t.Fatalf("unexpected response: %s", stdout)
+	}
+
+	clean := resp.Scenarios[0]
+	if clean.Tier != "critical" || clean.Branch != "main" || clean.CurrentTier != "dangerous" || !clean.Changed {
+		t.Errorf("clean on main = %+v", clean)
+	}
+	last := clean.Fired[len(clean.Fired)-1]
+	if last.Kind != "tier_override" || last.Rule != "branch:main=critical" || !last.Decisive {
+		t.Errorf("clean on main fired = %+v", clean.Fired)
+	}
+
+	prune := resp.Scenarios[1]
+	if prune.Tier != "dangerous" || prune.CurrentTier != "none" || len(prune.Fired) != 1 || prune.Fired[0].Source != "policy" {
+		t.Errorf("prune = %+v", prune)
+	}
+}
+
+func TestPolicyTestCommand_CurrentPolicyFails(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetPolicyFlags()
+
+	scenarios := writePolicyTestFile(t, t.TempDir(), "scenarios.toml", policyTestScenarios)
+	cmd := newTestPolicyCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "policy", "test", scenarios, "-C", h.ProjectDir)
+	if err == nil || !strings.Contains(err.Error(), "2 of 2 scenario(s)") {
+		t.Fatalf("expected both scenarios to fail, got %v", err)
+	}
+	for _, want := range []string{"FAIL  clean on main: DANGEROUS (expected critical)", "Context: ", "branch main, program codex-cli", "FAIL  prune: NONE", "Fired:   (no rules)", "2 scenario(s): 0 passed, 2 failed"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in output:\n%s", want, stdout)
+		}
+	}
+}
+
+func TestPolicyTestCommand_InvalidFiles(t *testing.T) {
+	h := testutil.NewHarness(t)
+	dir := t.TempDir()
+	scenarios := writePolicyTestFile(t, dir, "scenarios.toml", policyTestScenarios)
+
+	tests := []struct {
+		name      string
+		scenarios string
+		policy    string
+		want      string
+	}{
+		{"unknown scenario key", "[[scenarios]]\ncommand = \"ls\"\nexpected = \"safe\"\n", "", "unknown key(s) scenarios.expected"},
+		{"no scenarios", "# nothing\n", "", "no [[scenarios]]"},
+		{"bad expected tier", "[[scenarios]]\ncommand = \"ls\"\nexpect = \"high\"\n", "", "unknown expected tier"},
+		{"bad base", "", "base = \"everything\"\n", "unknown base"},
+		{"bad pattern tier", "", "[[patterns]]\ntier = \"severe\"\npattern = \"^x\"\n", "unknown tier"},
+		{"bad override", "", "tier_overrides = [\"branch:main=urgent\"]\n", "unknown action"},
+		{"unknown pack", "", "packs = [\"nope\"]\n", "nope"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetPolicyFlags()
+			args := []string{"policy", "test", scenarios, "-C", h.ProjectDir}
+			if tt.scenarios != "" {
+				args[2] = writePolicyTestFile(t, t.TempDir(), "scenarios.toml", tt.scenarios)
+			}
+			if tt.policy != "" {
+				args = append(args, "--policy", writePolicyTestFile(t, t.TempDir(), "policy.toml", tt.policy))
+			}
+			_, err := executeCommandCapture(t, newTestPolicyCmd(h.DBPath), args...)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
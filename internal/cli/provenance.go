@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/spf13/cobra"
+)
+
+// Provenance flags shared by commands that create requests.
+var (
+	flagProvenanceProgram      string
+	flagProvenanceConversation string
+	flagProvenanceToolCall     string
+	flagProvenancePromptHash   string
+)
+
+// addProvenanceFlags registers the provenance flags on a request-creating command.
+func addProvenanceFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&flagProvenanceProgram, "agent-program", "", "agent tool that invoked the command (env: SLB_AGENT_PROGRAM; defaults to the session program)")
+	cmd.Flags().StringVar(&flagProvenanceConversation, "conversation-id", "", "agent conversation/session ID (env: SLB_CONVERSATION_ID)")
+	cmd.Flags().StringVar(&flagProvenanceToolCall, "tool-call-id", "", "tool call ID that attempted the command (env: SLB_TOOL_CALL_ID)")
+	cmd.Flags().StringVar(&flagProvenancePromptHash, "prompt-hash", "", "hash of the originating prompt (env: SLB_PROMPT_HASH)")
+}
+
+// provenanceFromFlags builds request provenance from flags, falling back to
+// environment variables. Returns nil when nothing was provided.
+func provenanceFromFlags() *db.RequestProvenance {
+	pick := func(flag, env string) string {
+		if v := strings.TrimSpace(flag); v != "" {
+			return v
+		}
+		return strings.TrimSpace(os.Getenv(env))
+	}
+
+	p := &db.RequestProvenance{
+		AgentProgram:   pick(flagProvenanceProgram, "SLB_AGENT_PROGRAM"),
+		ConversationID: pick(flagProvenanceConversation, "SLB_CONVERSATION_ID"),
+		ToolCallID:     pick(flagProvenanceToolCall, "SLB_TOOL_CALL_ID"),
+		PromptHash:     pick(flagProvenancePromptHash, "SLB_PROMPT_HASH"),
+	}
+	if p.IsEmpty() {
+		return nil
+	}
+	return p
+}
+
+// formatPeer describes the Unix socket peer that submitted a request, with
+// the user name when it resolves on this machine.
+func formatPeer(p *db.RequestProvenance) string {
+	uid := strconv.Itoa(*p.PeerUID)
+	if u, err := user.LookupId(uid); err == nil {
+		uid += "(" + u.Username + ")"
+	}
+	parts := []string{"uid=" + uid}
+	if p.PeerGID != nil {
+		parts = append(parts, "gid="+strconv.Itoa(*p.PeerGID))
+	}
+	if p.PeerPID != nil {
+		parts = append(parts, "pid="+strconv.Itoa(*p.PeerPID))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+// resetProvenanceFlags resets the shared provenance flags to defaults.
+func resetProvenanceFlags() {
+	flagProvenanceProgram = ""
+	flagProvenanceConversation = ""
+	flagProvenanceToolCall = ""
+	flagProvenancePromptHash = ""
+}
+
+func TestProvenanceFromFlags(t *testing.T) {
+	resetProvenanceFlags()
+	t.Setenv("SLB_AGENT_PROGRAM", "")
+	t.Setenv("SLB_CONVERSATION_ID", "")
+	t.Setenv("SLB_TOOL_CALL_ID", "")
+	t.Setenv("SLB_PROMPT_HASH", "")
+
+	if p := provenanceFromFlags(); p != nil {
+		t.Fatalf("expected nil provenance with nothing set, got %+v", p)
+	}
+
+	t.Setenv("SLB_CONVERSATION_ID", "conv-env")
+	t.Setenv("SLB_PROMPT_HASH", "hash-env")
+	flagProvenanceConversation = "conv-flag"
+	defer resetProvenanceFlags()
+
+	p := provenanceFromFlags()
+	if p == nil || p.ConversationID != "conv-flag" || p.PromptHash != "hash-env" {
+		t.Fatalf("expected flag to win over env and env fallback, got %+v", p)
+	}
+}
+
+func TestRequestCommand_RecordsProvenance(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetRequestFlags()
+	defer resetProvenanceFlags()
+
+	sess := testutil.MakeSession(t, h.DB,
+		testutil.WithProject(h.ProjectDir),
+		testutil.WithAgent("TestAgent"),
+	)
+
+	cmd := newTestRequestCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "request", "rm -rf ./build",
+		"-s", sess.ID,
+		"-C", h.ProjectDir,
+		"--conversation-id", "conv-1",
+		"--tool-call-id", "toolu_1",
+		"-j",
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	id, _ := result["request_id"].(string)
+
+	prov, err := h.DB.GetRequestProvenance(id)
+	if err != nil {
+		t.Fatalf("GetRequestProvenance: %v", err)
+	}
+	if prov.ConversationID != "conv-1" || prov.ToolCallID != "toolu_1" || prov.AgentProgram != sess.Program {
+		t.Fatalf("unexpected provenance: %+v", prov)
+	}
+}
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/daemon"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
+)
+
+// Review queue orders accepted by pending and review list --sort.
+const (
+	queueSortCreated  = "created"
+	queueSortScore    = "score"
+	queueSortPriority = "priority"
+)
+
+const queueSortUsage = "order requests by: created (newest first), score (highest risk score first) or priority (most urgent first)"
+
+func validateQueueSort(sortBy string) error {
+	switch sortBy {
+	case queueSortCreated, queueSortScore, queueSortPriority:
+		return nil
+	default:
+		return fmt.Errorf("invalid --sort %q (use created, score or priority)", sortBy)
+	}
+}
+
+// reviewQueue annotates pending requests with their priority and SLA
+// standing, in the order sortBy asks for. Ties keep the listing's newest
+// first order.
+func reviewQueue(dbConn *db.DB, requests []*db.Request, cfg config.Config, sortBy string, now time.Time) []*core.QueueItem {
+	items := core.BuildQueue(dbConn, requests, daemon.ReviewSLAFromConfig(cfg.Notifications), now)
+	switch sortBy {
+	case queueSortScore:
+		sort.SliceStable(items, func(i, j int) bool {
+			return items[i].Request.RiskScore > items[j].Request.RiskScore
+		})
+	case queueSortPriority:
+		core.SortQueueByPriority(items)
+	}
+	return items
+}
+
+// slaDueAt formats an SLA deadline for listings, "" when there is none.
+func slaDueAt(sla *core.SLAState) string {
+	if sla == nil {
+		return ""
+	}
+	return timefmt.Format(sla.DueAt)
+}
+
+// markRequestorWaiting records that the requestor is blocked on a request
+// so the review queue can prioritise it, and returns a func that clears the
+// mark. Both are best effort.
+func markRequestorWaiting(dbConn *db.DB, requestID, sessionID string) func() {
+	_ = dbConn.MarkRequestorWaiting(requestID, sessionID)
+	return func() { _ = dbConn.ClearRequestorWaiting(requestID) }
+}
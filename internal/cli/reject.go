@@ -1,13 +1,16 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
-	"time"
 
 	"github.com/Dicklesworthstone/slb/internal/core"
 	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/i18n"
 	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
 	"github.com/spf13/cobra"
 )
 
@@ -17,23 +20,28 @@ var (
 	flagRejectReason        string
 	flagRejectComments      string
 	flagRejectTargetProject string
+	flagRejectLatest        bool
+	flagRejectVersion       int
 )
 
 func init() {
 	// -s is owned by the root persistent --session-id; don't reclaim the
 	// shorthand here (it collides/shadows the persistent flag). Pass the
 	// session via the long --session-id flag.
-	rejectCmd.Flags().StringVar(&flagRejectSessionID, "session-id", "", "reviewer session ID (required)")
-	rejectCmd.Flags().StringVarP(&flagRejectSessionKey, "session-key", "k", "", "session HMAC key for signing (required)")
+	rejectCmd.Flags().StringVar(&flagRejectSessionID, "session-id", "", "reviewer session ID (default: SLB_SESSION_ID, then your active session)")
+	rejectCmd.Flags().StringVarP(&flagRejectSessionKey, "session-key", "k", "", "session HMAC key for signing (default: SLB_SESSION_KEY)")
 	rejectCmd.Flags().StringVarP(&flagRejectReason, "reason", "r", "", "reason for rejection (required)")
 	rejectCmd.Flags().StringVarP(&flagRejectComments, "comments", "m", "", "additional comments")
+	rejectCmd.Flags().StringVar(&flagRejectComments, "comment", "", "alias for --comments")
 	rejectCmd.Flags().StringVar(&flagRejectTargetProject, "target-project", "", "target project path for cross-project rejections")
+	rejectCmd.Flags().IntVar(&flagRejectVersion, "expected-version", 0, "fail if the request changed since this version (from 'slb show --json')")
+	rejectCmd.Flags().BoolVar(&flagRejectLatest, "latest", false, "reject the newest pending request you have not reviewed")
 
 	rootCmd.AddCommand(rejectCmd)
 }
 
 var rejectCmd = &cobra.Command{
-	Use:   "reject <request-id>",
+	Use:   "reject [request-id]",
 	Short: "Reject a pending request",
 	Long: `Reject a command request, preventing it from being executed.
 
@@ -43,28 +51,26 @@ what was wrong and potentially submit a corrected request.
 The rejection is cryptographically signed with your session key to ensure
 authenticity.
 
+The reviewer is resolved the same way as for 'slb approve': --session-id,
+then SLB_SESSION_ID, then your active session in this project; the key may
+come from SLB_SESSION_KEY or the keyring. Use --latest instead of a request ID to reject the
+newest pending request you have not reviewed. JSON output includes the
+request's updated quorum state.
+
+Pass --expected-version with the version you reviewed (the "version" field
+of 'slb show --json' or 'slb pending --json') to refuse the decision if
+another reviewer changed the request in the meantime; refresh and retry.
+
 For cross-project reviews, use --target-project to specify which project's
 database contains the request you want to reject.
 
 	Examples:
+	  slb reject --latest -r "Wrong directory" --comment "Use ./build instead"
 	  slb reject abc123 --session-id $SESSION_ID -k $SESSION_KEY -r "Command too dangerous"
 	  slb reject abc123 --session-id $SESSION_ID -k $SESSION_KEY -r "Justification insufficient" -m "Please add more context"
 	  slb reject abc123 --session-id $SESSION_ID -k $SESSION_KEY -r "Too risky" --target-project /path/to/other/project`,
-	Args: cobra.ExactArgs(1),
+	Args: requestIDOrLatest(&flagRejectLatest),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		requestID := args[0]
-
-		// Validate required flags
-		if flagRejectSessionID == "" {
-			return fmt.Errorf("--session-id is required")
-		}
-		if flagRejectSessionKey == "" {
-			return fmt.Errorf("--session-key is required")
-		}
-		if flagRejectReason == "" {
-			return fmt.Errorf("--reason is required for rejections")
-		}
-
 		// Determine project and database path
 		project, err := projectPath()
 		if err != nil && flagRejectTargetProject == "" {
@@ -85,6 +91,27 @@ database contains the request you want to reject.
 		}
 		defer dbConn.Close()
 
+		reviewerID, err := resolveReviewerSessionID(dbConn, project, flagRejectSessionID)
+		if err != nil {
+			return err
+		}
+		sessionKey, err := resolveSessionKey(flagRejectSessionKey, reviewerID)
+		if err != nil {
+			return err
+		}
+		if flagRejectReason == "" {
+			return errors.New(i18n.T("error.reason_required"))
+		}
+
+		var requestID string
+		if flagRejectLatest {
+			if requestID, err = latestReviewableRequest(dbConn, project, reviewerID); err != nil {
+				return err
+			}
+		} else {
+			requestID = args[0]
+		}
+
 		// Build review options - reason goes in comments for rejections
 		comments := flagRejectReason
 		if flagRejectComments != "" {
@@ -92,11 +119,12 @@ database contains the request you want to reject.
 		}
 
 		opts := core.ReviewOptions{
-			SessionID:  flagRejectSessionID,
-			SessionKey: flagRejectSessionKey,
-			RequestID:  requestID,
-			Decision:   db.DecisionReject,
-			Comments:   comments,
+			SessionID:       reviewerID,
+			SessionKey:      sessionKey,
+			RequestID:       requestID,
+			Decision:        db.DecisionReject,
+			Comments:        comments,
+			ExpectedVersion: flagRejectVersion,
 		}
 
 		// Create review service and submit
@@ -107,17 +135,24 @@ database contains the request you want to reject.
 			return fmt.Errorf("submitting rejection: %w", err)
 		}
 
+		quorum, err := buildQuorumState(dbConn, requestID, result)
+		if err != nil {
+			return err
+		}
+
 		// Build output
 		type rejectionResult struct {
-			ReviewID             string `json:"review_id"`
-			RequestID            string `json:"request_id"`
-			Decision             string `json:"decision"`
-			Reason               string `json:"reason"`
-			Approvals            int    `json:"approvals"`
-			Rejections           int    `json:"rejections"`
-			RequestStatusChanged bool   `json:"request_status_changed"`
-			NewRequestStatus     string `json:"new_request_status,omitempty"`
-			CreatedAt            string `json:"created_at"`
+			ReviewID             string      `json:"review_id"`
+			RequestID            string      `json:"request_id"`
+			Decision             string      `json:"decision"`
+			Reason               string      `json:"reason"`
+			Approvals            int         `json:"approvals"`
+			Rejections           int         `json:"rejections"`
+			RequestStatusChanged bool        `json:"request_status_changed"`
+			NewRequestStatus     string      `json:"new_request_status,omitempty"`
+			Quorum               quorumState `json:"quorum"`
+			Version              int         `json:"version"`
+			CreatedAt            string      `json:"created_at"`
 		}
 
 		resp := rejectionResult{
@@ -128,7 +163,9 @@ database contains the request you want to reject.
 			Approvals:            result.Approvals,
 			Rejections:           result.Rejections,
 			RequestStatusChanged: result.RequestStatusChanged,
-			CreatedAt:            result.Review.CreatedAt.Format(time.RFC3339),
+			Quorum:               quorum,
+			Version:              result.Version,
+			CreatedAt:            timefmt.Format(result.Review.CreatedAt),
 		}
 
 		if result.RequestStatusChanged {
@@ -136,18 +173,18 @@ database contains the request you want to reject.
 		}
 
 		out := output.New(output.Format(GetOutput()))
-		if GetOutput() == "json" {
+		if isJSONOutput() {
 			return out.Write(resp)
 		}
 
 		// Human-readable output
-		fmt.Printf("Rejected request %s\n", requestID)
-		fmt.Printf("Review ID: %s\n", resp.ReviewID)
-		fmt.Printf("Reason: %s\n", flagRejectReason)
-		fmt.Printf("Approvals: %d, Rejections: %d\n", resp.Approvals, resp.Rejections)
+		output.Notef(os.Stdout, "%s\n", i18n.T("reject.done", requestID))
+		output.Notef(os.Stdout, "%s\n", i18n.T("review.id", resp.ReviewID))
+		output.Notef(os.Stdout, "%s\n", i18n.T("review.reason", flagRejectReason))
+		output.Notef(os.Stdout, "%s\n", i18n.T("review.counts", resp.Approvals, resp.Rejections))
 
 		if result.RequestStatusChanged {
-			fmt.Printf("Request status changed to: %s\n", resp.NewRequestStatus)
+			output.Notef(os.Stdout, "%s\n", i18n.T("review.status_changed", i18n.Status(resp.NewRequestStatus)))
 		}
 
 		return nil
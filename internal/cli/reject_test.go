@@ -28,16 +28,19 @@ func newTestRejectCmd(dbPath string) *cobra.Command {
 	// production: no -s local shorthand (-s is owned by the persistent
 	// --session-id); the session is passed via the long --session-id flag.
 	reject := &cobra.Command{
-		Use:   "reject <request-id>",
+		Use:   "reject [request-id]",
 		Short: "Reject a pending request",
-		Args:  cobra.ExactArgs(1),
+		Args:  rejectCmd.Args,
 		RunE:  rejectCmd.RunE,
 	}
 	reject.Flags().StringVar(&flagRejectSessionID, "session-id", "", "reviewer session ID (required)")
 	reject.Flags().StringVarP(&flagRejectSessionKey, "session-key", "k", "", "session HMAC key for signing (required)")
 	reject.Flags().StringVarP(&flagRejectReason, "reason", "r", "", "reason for rejection (required)")
 	reject.Flags().StringVarP(&flagRejectComments, "comments", "m", "", "additional comments")
+	reject.Flags().StringVar(&flagRejectComments, "comment", "", "alias for --comments")
+	reject.Flags().BoolVar(&flagRejectLatest, "latest", false, "reject the newest pending request you have not reviewed")
 	reject.Flags().StringVar(&flagRejectTargetProject, "target-project", "", "target project path for cross-project rejections")
+	reject.Flags().IntVar(&flagRejectVersion, "expected-version", 0, "fail if the request changed since this version (from 'slb show --json')")
 
 	root.AddCommand(reject)
 
@@ -55,6 +58,8 @@ func resetRejectFlags() {
 	flagRejectReason = ""
 	flagRejectComments = ""
 	flagRejectTargetProject = ""
+	flagRejectLatest = false
+	flagRejectVersion = 0
 }
 
 func TestRejectCommand_RequiresRequestID(t *testing.T) {
@@ -232,6 +237,54 @@ func TestRejectCommand_WithComments(t *testing.T) {
 	}
 }
 
+func TestRejectCommand_LatestWithQuorum(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetRejectFlags()
+
+	requestorSess := testutil.MakeSession(t, h.DB,
+		testutil.WithProject(h.ProjectDir),
+		testutil.WithAgent("Requestor"),
+	)
+	reviewerSess := testutil.MakeSession(t, h.DB,
+		testutil.WithProject(h.ProjectDir),
+		testutil.WithAgent("Reviewer"),
+	)
+	req := testutil.MakeRequest(t, h.DB, requestorSess)
+	h.DB.Exec(`UPDATE requests SET min_approvals = 1, require_different_model = false WHERE id = ?`, req.ID)
+
+	cmd := newTestRejectCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "reject", "--latest",
+		"--session-id", reviewerSess.ID,
+		"-k", reviewerSess.SessionKey,
+		"-r", "Wrong directory",
+		"--comment", "Use ./build",
+		"-C", h.ProjectDir,
+		"-j",
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var result struct {
+		RequestID string      `json:"request_id"`
+		Quorum    quorumState `json:"quorum"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if result.RequestID != req.ID {
+		t.Errorf("expected request_id=%s, got %s", req.ID, result.RequestID)
+	}
+	if result.Quorum.Status != string(db.StatusRejected) || result.Quorum.Rejections != 1 || result.Quorum.Satisfied {
+		t.Errorf("unexpected quorum state: %+v", result.Quorum)
+	}
+
+	reviews, _ := h.DB.ListReviewsForRequest(req.ID)
+	if len(reviews) != 1 || reviews[0].Comments != "Wrong directory\n\nUse ./build" {
+		t.Fatalf("expected reason and comment on review, got %+v", reviews)
+	}
+}
+
 func TestRejectCommand_SelfReviewPrevented(t *testing.T) {
 	h := testutil.NewHarness(t)
 	resetRejectFlags()
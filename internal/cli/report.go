@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagReportMarkdown    bool
+	flagReportEmail       bool
+	flagReportUntil       string
+	flagReportAllProjects bool
+)
+
+func init() {
+	reportWeeklyCmd.Flags().BoolVar(&flagReportMarkdown, "markdown", false, "render as markdown for posting into team channels")
+	reportWeeklyCmd.Flags().BoolVar(&flagReportEmail, "email", false, "render as a plain-text email message (pipe to sendmail)")
+	reportWeeklyCmd.Flags().StringVar(&flagReportUntil, "until", "", "end of the 7-day window (RFC3339 or YYYY-MM-DD; default now)")
+	reportWeeklyCmd.Flags().BoolVar(&flagReportAllProjects, "all-projects", false, "report on every project instead of the current one")
+
+	reportCmd.AddCommand(reportWeeklyCmd)
+	rootCmd.AddCommand(reportCmd)
+}
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Generate digest reports of request activity",
+}
+
+var reportWeeklyCmd = &cobra.Command{
+	Use:   "weekly",
+	Short: "Summarize the last 7 days of requests",
+	Long: `Summarize a week of requests: counts by tier and status, the riskiest
+actions, rejection reasons, mean time to approval, and break-glass incidents.
+
+Break-glass incidents are only listed for a single project.
+
+Examples:
+  slb report weekly                          # Text summary for this project
+  slb report weekly --markdown > week.md     # Post into a team channel
+  slb report weekly --email | sendmail team@example.com
+  slb report weekly --until 2026-01-05 -j    # A past week, as JSON`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagReportMarkdown && flagReportEmail {
+			return fmt.Errorf("--markdown and --email are mutually exclusive")
+		}
+
+		// Timestamps are stored to the second; round up so requests made
+		// this second are included.
+		until := time.Now().UTC().Truncate(time.Second).Add(time.Second)
+		if flagReportUntil != "" {
+			until = parseHistorySince(flagReportUntil)
+			if until.IsZero() {
+				return fmt.Errorf("invalid --until %q: use RFC3339 or YYYY-MM-DD", flagReportUntil)
+			}
+		}
+		since := until.AddDate(0, 0, -7)
+
+		project := ""
+		if !flagReportAllProjects {
+			p, err := projectPath()
+			if err != nil {
+				return err
+			}
+			project = p
+		}
+
+		dbConn, err := db.Open(GetDB())
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer dbConn.Close()
+
+		report, err := core.BuildWeeklyReport(dbConn, project, since, until)
+		if err != nil {
+			return fmt.Errorf("building report: %w", err)
+		}
+
+		switch {
+		case flagReportMarkdown:
+			fmt.Print(report.Markdown())
+			return nil
+		case flagReportEmail:
+			fmt.Printf("Subject: slb weekly report %s – %s\n", since.Format("2006-01-02"), until.Add(-time.Second).Format("2006-01-02"))
+			fmt.Print("Content-Type: text/plain; charset=utf-8\n\n")
+			fmt.Print(report.Text())
+			return nil
+		}
+
+		if GetOutput() != "text" {
+			return output.New(output.Format(GetOutput())).Write(report)
+		}
+		fmt.Print(report.Text())
+		return nil
+	},
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+	"github.com/spf13/cobra"
+)
+
+func newTestReportCmd(dbPath string) *cobra.Command {
+	root := &cobra.Command{
+		Use:           "slb",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	root.PersistentFlags().StringVar(&flagDB, "db", dbPath, "database path")
+	root.PersistentFlags().StringVarP(&flagOutput, "output", "o", "text", "output format")
+	root.PersistentFlags().BoolVarP(&flagJSON, "json", "j", false, "json output")
+	root.PersistentFlags().StringVarP(&flagProject, "project", "C", "", "project directory")
+
+	repCmd := &cobra.Command{Use: "report"}
+	weeklyCmd := &cobra.Command{
+		Use:  "weekly",
+		Args: cobra.NoArgs,
+		RunE: reportWeeklyCmd.RunE,
+	}
+	weeklyCmd.Flags().BoolVar(&flagReportMarkdown, "markdown", false, "")
+	weeklyCmd.Flags().BoolVar(&flagReportEmail, "email", false, "")
+	weeklyCmd.Flags().StringVar(&flagReportUntil, "until", "", "")
+	weeklyCmd.Flags().BoolVar(&flagReportAllProjects, "all-projects", false, "")
+	repCmd.AddCommand(weeklyCmd)
+	root.AddCommand(repCmd)
+	return root
+}
+
+func resetReportFlags() {
+	flagOutput = "text"
+	flagJSON = false
+	flagProject = ""
+	flagReportMarkdown = false
+	flagReportEmail = false
+	flagReportUntil = ""
+	flagReportAllProjects = false
+}
+
+func TestReportWeeklyCommand(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetReportFlags()
+	t.Cleanup(resetReportFlags)
+
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	testutil.MakeRequest(t, h.DB, sess,
+		testutil.WithCommand("terraform destroy", h.ProjectDir, true),
+		testutil.WithRisk(db.RiskTierCritical))
+
+	stdout, err := executeCommandCapture(t, newTestReportCmd(h.DBPath), "report", "weekly", "-C", h.ProjectDir, "--markdown")
+	testutil.RequireNoError(t, err, "report weekly --markdown")
+	if !strings.Contains(stdout, "## Riskiest actions") || !strings.Contains(stdout, "`terraform destroy`") {
+		t.Fatalf("unexpected markdown:\n%s", stdout)
+	}
+
+	resetReportFlags()
+	stdout, err = executeCommandCapture(t, newTestReportCmd(h.DBPath), "report", "weekly", "-C", h.ProjectDir, "--email")
+	testutil.RequireNoError(t, err, "report weekly --email")
+	if !strings.HasPrefix(stdout, "Subject: slb weekly report ") || strings.Contains(stdout, "`") {
+		t.Fatalf("unexpected email:\n%s", stdout)
+	}
+
+	resetReportFlags()
+	stdout, err = executeCommandCapture(t, newTestReportCmd(h.DBPath), "report", "weekly", "-C", h.ProjectDir, "-j")
+	testutil.RequireNoError(t, err, "report weekly -j")
+	var report struct {
+		TotalRequests int            `json:"total_requests"`
+		ByTier        map[string]int `json:"by_tier"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	testutil.RequireEqual(t, 1, report.TotalRequests, "total")
+	testutil.RequireEqual(t, 1, report.ByTier["critical"], "critical")
+
+	resetReportFlags()
+	stdout, err = executeCommandCapture(t, newTestReportCmd(h.DBPath), "report", "weekly", "-C", h.ProjectDir, "--until", "2020-01-01", "-j")
+	testutil.RequireNoError(t, err, "report weekly --until")
+	if !strings.Contains(stdout, `"total_requests": 0`) {
+		t.Fatalf("expected an empty past week:\n%s", stdout)
+	}
+}
+
+func TestReportWeeklyCommand_InvalidFlags(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetReportFlags()
+	t.Cleanup(resetReportFlags)
+
+	_, err := executeCommandCapture(t, newTestReportCmd(h.DBPath), "report", "weekly", "--markdown", "--email")
+	if err == nil || !strings.Contains(err.Error(), "mutually exclusive") {
+		t.Fatalf("expected a mutually exclusive error, got %v", err)
+	}
+
+	resetReportFlags()
+	_, err = executeCommandCapture(t, newTestReportCmd(h.DBPath), "report", "weekly", "--until", "last tuesday")
+	if err == nil || !strings.Contains(err.Error(), "invalid --until") {
+		t.Fatalf("expected an invalid --until error, got %v", err)
+	}
+}
@@ -4,13 +4,17 @@ package cli
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/Dicklesworthstone/slb/internal/config"
 	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/daemon"
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
 	"github.com/spf13/cobra"
 )
 
@@ -26,6 +30,10 @@ var (
 	flagRequestAttachFile     []string
 	flagRequestAttachContext  []string
 	flagRequestAttachScreen   []string
+	flagRequestContextFile    []string
+	flagRequestIdempotencyKey string
+	flagRequestScript         string
+	flagRequestInterpreter    string
 )
 
 func init() {
@@ -33,19 +41,25 @@ func init() {
 	requestCmd.Flags().StringVar(&flagRequestExpectedEffect, "expected-effect", "", "expected effect of the command")
 	requestCmd.Flags().StringVar(&flagRequestGoal, "goal", "", "goal this command helps achieve")
 	requestCmd.Flags().StringVar(&flagRequestSafety, "safety", "", "safety argument (why this is safe to run)")
-	requestCmd.Flags().StringSliceVar(&flagRequestRedact, "redact", nil, "regex patterns to redact from display")
+	requestCmd.Flags().StringSliceVar(&flagRequestRedact, "redact", nil, "regex patterns to redact from display and from the execution output")
 	requestCmd.Flags().BoolVar(&flagRequestWait, "wait", false, "block until a decision is made")
 	requestCmd.Flags().BoolVar(&flagRequestExecute, "execute", false, "execute the command if approved (use 'slb run' for atomic flow)")
 	requestCmd.Flags().IntVar(&flagRequestTimeout, "timeout", 300, "timeout in seconds when waiting")
 	requestCmd.Flags().StringSliceVar(&flagRequestAttachFile, "attach-file", nil, "attach file content as context")
 	requestCmd.Flags().StringSliceVar(&flagRequestAttachContext, "attach-context", nil, "run command and attach output as context")
 	requestCmd.Flags().StringSliceVar(&flagRequestAttachScreen, "attach-screenshot", nil, "attach screenshot/image file")
+	requestCmd.Flags().StringSliceVar(&flagRequestContextFile, "context-file", nil, "attach an agent transcript snippet (tail only, capped and redacted)")
+	requestCmd.Flags().StringVar(&flagRequestIdempotencyKey, "idempotency-key", "", "key that makes retries return the original request instead of creating a duplicate")
+	requestCmd.Flags().StringVar(&flagRequestScript, "script", "", "submit a multi-line script read from this file (- for stdin) instead of a command")
+	requestCmd.Flags().StringVar(&flagRequestInterpreter, "interpreter", "", "shell that runs the --script (default: its #! line, else sh)")
+	addProvenanceFlags(requestCmd)
+	addLabelFlags(requestCmd)
 
 	rootCmd.AddCommand(requestCmd)
 }
 
 var requestCmd = &cobra.Command{
-	Use:   "request <command>",
+	Use:   "request <command> | --script <file>",
 	Short: "Create a command approval request",
 	Long: `Create a new command approval request (plumbing command).
 
@@ -59,14 +73,46 @@ The command is classified by risk tier:
   SAFE       - Skipped (no request created)
 
 Use --wait to block until approval/rejection.
-Use --execute with --wait to execute after approval.`,
-	Args: cobra.ExactArgs(1),
+Use --execute with --wait to execute after approval.
+
+Pass --idempotency-key when retrying after a failure: resubmitting the same
+command with the same key returns the original request (with "replayed":
+true) until the key expires after general.idempotency_ttl_minutes.
+
+Use --script to submit a whole shell script (a file, or - for a heredoc on
+stdin) instead of one command. Each line is classified on its own and the
+riskiest decides the tier; reviewers see per-line annotations in 'slb
+review show'. When executed, the script runs from a temp file with its
+interpreter, after checking it against the SHA-256 recorded now.
+
+  slb request --script deploy.sh --reason "..."
+  slb request --script - --reason "..." <<'EOF'
+  set -e
+  kubectl drain node-7
+  kubectl delete node node-7
+  EOF`,
+	Args: requestArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		command := args[0]
+		var command string
+		var script *core.ScriptOptions
+		if flagRequestScript != "" {
+			content, err := readRequestScript(cmd, flagRequestScript)
+			if err != nil {
+				return err
+			}
+			command = content
+			script = &core.ScriptOptions{Interpreter: flagRequestInterpreter, Source: flagRequestScript}
+		} else {
+			command = args[0]
+		}
 
 		if flagSessionID == "" {
 			return fmt.Errorf("--session-id is required to create a request")
 		}
+		labels, err := labelsFromFlags()
+		if err != nil {
+			return err
+		}
 
 		project, err := projectPath()
 		if err != nil {
@@ -97,6 +143,7 @@ Use --execute with --wait to execute after approval.`,
 			Files:       flagRequestAttachFile,
 			Contexts:    flagRequestAttachContext,
 			Screenshots: flagRequestAttachScreen,
+			Transcripts: flagRequestContextFile,
 		})
 		if err != nil {
 			return fmt.Errorf("collecting attachments: %w", err)
@@ -114,10 +161,15 @@ Use --execute with --wait to execute after approval.`,
 		// Create the request using the core logic (config-driven rate limits + integrations).
 		rl := core.NewRateLimiter(dbConn, toRateLimitConfig(cfg))
 		creator := core.NewRequestCreator(dbConn, rl, nil, toRequestCreatorConfig(cfg))
-		result, err := creator.CreateRequest(core.CreateRequestOptions{
+		if advisor := daemon.LLMAdvisorFromConfig(cfg); advisor != nil {
+			creator.SetAdvisor(advisor)
+			defer creator.WaitForAdvice()
+		}
+		result, err := creator.CreateRequest(cmd.Context(), core.CreateRequestOptions{
 			SessionID: flagSessionID,
 			Command:   command,
 			Cwd:       cwd,
+			Environ:   os.Environ(),
 			Justification: core.Justification{
 				Reason:         flagRequestReason,
 				ExpectedEffect: flagRequestExpectedEffect,
@@ -127,6 +179,10 @@ Use --execute with --wait to execute after approval.`,
 			Attachments:    attachments,
 			RedactPatterns: flagRequestRedact,
 			ProjectPath:    project,
+			Provenance:     provenanceFromFlags(),
+			Labels:         labels,
+			IdempotencyKey: flagRequestIdempotencyKey,
+			Script:         script,
 		})
 		if err != nil {
 			return fmt.Errorf("creating request: %w", err)
@@ -146,17 +202,45 @@ Use --execute with --wait to execute after approval.`,
 			"request_id":    request.ID,
 			"status":        string(request.Status),
 			"tier":          string(request.RiskTier),
+			"risk_score":    request.RiskScore,
 			"command":       request.Command.Raw,
 			"command_hash":  request.Command.Hash,
 			"min_approvals": request.MinApprovals,
-			"created_at":    request.CreatedAt.Format(time.RFC3339),
+			"created_at":    timefmt.Format(request.CreatedAt),
 		}
 
 		if request.Command.DisplayRedacted != "" {
 			resp["command_redacted"] = request.Command.DisplayRedacted
 		}
 		if request.ExpiresAt != nil {
-			resp["expires_at"] = request.ExpiresAt.Format(time.RFC3339)
+			resp["expires_at"] = timefmt.Format(*request.ExpiresAt)
+		}
+		if len(request.Labels) > 0 {
+			resp["labels"] = request.Labels
+		}
+		if result.Replayed {
+			resp["replayed"] = true
+		}
+		if result.Script != nil {
+			resp["script"] = map[string]any{
+				"interpreter": result.Script.Interpreter,
+				"source":      result.Script.Source,
+				"sha256":      result.Script.SHA256,
+				"statements":  len(core.ScriptStatements(request.Command.Raw)),
+			}
+		}
+		if len(result.RemoteScripts) > 0 {
+			resp["remote_scripts"] = remoteScriptViews(result.RemoteScripts)
+		}
+		if len(result.TemplateVars) > 0 {
+			resp["template_vars"] = result.TemplateVars
+			warnTemplateVars(result.TemplateVars)
+		}
+		if result.Classification != nil && len(result.Classification.Explanation) > 0 {
+			resp["explanation"] = result.Classification.Explanation
+		}
+		if result.RiskScore != nil && len(result.RiskScore.Factors) > 0 {
+			resp["risk_factors"] = result.RiskScore.Factors
 		}
 
 		// If not waiting, return now
@@ -165,6 +249,10 @@ Use --execute with --wait to execute after approval.`,
 		}
 
 		// Wait for decision with timeout
+		stopWaiting := markRequestorWaiting(dbConn, request.ID, request.RequestorSessionID)
+		defer stopWaiting()
+		waiter := newDecisionWaiter(request.RequestorSessionID, request.ID)
+		defer waiter.Close()
 		deadline := time.Now().Add(time.Duration(flagRequestTimeout) * time.Second)
 		for time.Now().Before(deadline) {
 			request, _, err = dbConn.GetRequestWithReviews(request.ID)
@@ -176,23 +264,36 @@ Use --execute with --wait to execute after approval.`,
 				break
 			}
 
-			time.Sleep(500 * time.Millisecond)
+			waiter.Wait(time.Until(deadline))
 		}
+		stopWaiting()
 
 		// Update response with final status
 		resp["status"] = string(request.Status)
 		if request.ResolvedAt != nil {
-			resp["resolved_at"] = request.ResolvedAt.Format(time.RFC3339)
+			resp["resolved_at"] = timefmt.Format(*request.ResolvedAt)
 		}
 
 		// Execute if approved and --execute was specified
 		if flagRequestExecute && request.Status == db.StatusApproved {
-			executor := core.NewExecutor(dbConn, nil).WithNotifier(buildAgentMailNotifier(project))
+			windows, err := buildExecutionWindows(cfg)
+			if err != nil {
+				return err
+			}
+			isolation, err := buildIsolation(cfg, project)
+			if err != nil {
+				return err
+			}
+			executor := core.NewExecutor(dbConn, nil).
+				WithNotifier(buildAgentMailNotifier(project)).
+				WithExecutionWindows(windows).
+				WithIsolation(isolation).
+				WithTierOverrides(agentTierOverrides(cfg))
 			execResult, execErr := executor.ExecuteApprovedRequest(context.Background(), core.ExecuteOptions{
 				RequestID:         request.ID,
 				SessionID:         flagSessionID,
-				LogDir:            ".slb/logs",
-				SuppressOutput:    GetOutput() == "json",
+				LogDir:            projectLogDir(project),
+				SuppressOutput:    isJSONOutput(),
 				CaptureRollback:   cfg.General.EnableRollbackCapture,
 				MaxRollbackSizeMB: cfg.General.MaxRollbackSizeMB,
 			})
@@ -210,6 +311,9 @@ Use --execute with --wait to execute after approval.`,
 			resp["exit_code"] = exitCode
 			resp["duration_ms"] = durationMs
 			resp["log_path"] = logPath
+			if execResult != nil && len(execResult.SecretsFound) > 0 {
+				resp["secrets_found"] = execResult.SecretsFound
+			}
 
 			if execErr != nil {
 				resp["execution_error"] = execErr.Error()
@@ -220,29 +324,59 @@ Use --execute with --wait to execute after approval.`,
 				resp["status"] = string(updated.Status)
 			}
 
-			if GetOutput() == "json" {
+			if isJSONOutput() {
 				_ = out.Write(resp)
 				if execErr != nil {
-					os.Exit(1)
+					return withExitCode(exitError, fmt.Errorf("executing request: %w", execErr))
 				}
-				if exitCode != 0 {
-					os.Exit(exitCode)
-				}
-				return nil
+				return withExitCode(exitCode, nil)
 			}
 
 			if execErr != nil {
 				return fmt.Errorf("executing request: %w", execErr)
 			}
 			if exitCode != 0 {
-				os.Exit(exitCode)
+				return withExitCode(exitCode, out.Write(resp))
 			}
+			return out.Write(resp)
 		}
 
-		return out.Write(resp)
+		// Waiting ended without running anything: still pending means no
+		// decision arrived in time.
+		code := statusExitCode(request.Status)
+		if request.Status == db.StatusPending {
+			code = exitTimeout
+		}
+		return withExitCode(code, out.Write(resp))
 	},
 }
 
+// requestArgs takes the command, or no argument with --script.
+func requestArgs(cmd *cobra.Command, args []string) error {
+	if flagRequestScript != "" {
+		return cobra.NoArgs(cmd, args)
+	}
+	return cobra.ExactArgs(1)(cmd, args)
+}
+
+// readRequestScript reads a --script file, or stdin for "-".
+func readRequestScript(cmd *cobra.Command, path string) (string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(io.LimitReader(cmd.InOrStdin(), core.MaxCommandBytes+1))
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading script: %w", err)
+	}
+	if strings.TrimSpace(string(data)) == "" {
+		return "", fmt.Errorf("script %s is empty", path)
+	}
+	return string(data), nil
+}
+
 // skippedRequestResponse builds the JSON payload for a request that was skipped
 // (a safe/unmatched command, so no request row was created).
 //
@@ -261,6 +395,16 @@ func skippedRequestResponse(result *core.CreateRequestResult, command string) ma
 	}
 	if result.Classification != nil {
 		resp["tier"] = result.Classification.Tier
+		if len(result.Classification.Explanation) > 0 {
+			resp["explanation"] = result.Classification.Explanation
+		}
 	}
 	return resp
 }
+
+// warnTemplateVars tells the requestor that reviewers will see the
+// command's placeholders unexpanded.
+func warnTemplateVars(vars []string) {
+	fmt.Fprintf(os.Stderr, "warning: command has unexpanded template variables (%s); reviewers must acknowledge them to approve, so prefer resubmitting with concrete values\n",
+		strings.Join(vars, ", "))
+}
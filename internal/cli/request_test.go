@@ -2,6 +2,8 @@ package cli
 
 import (
 	"encoding/json"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -30,7 +32,7 @@ func newTestRequestCmd(dbPath string) *cobra.Command {
 	reqCmd := &cobra.Command{
 		Use:   "request <command>",
 		Short: "Create a command approval request",
-		Args:  cobra.ExactArgs(1),
+		Args:  requestArgs,
 		RunE:  requestCmd.RunE,
 	}
 	reqCmd.Flags().StringVar(&flagRequestReason, "reason", "", "reason/justification")
@@ -44,6 +46,12 @@ func newTestRequestCmd(dbPath string) *cobra.Command {
 	reqCmd.Flags().StringSliceVar(&flagRequestAttachFile, "attach-file", nil, "attach files")
 	reqCmd.Flags().StringSliceVar(&flagRequestAttachContext, "attach-context", nil, "attach context")
 	reqCmd.Flags().StringSliceVar(&flagRequestAttachScreen, "attach-screenshot", nil, "attach screenshots")
+	reqCmd.Flags().StringSliceVar(&flagRequestContextFile, "context-file", nil, "attach transcript snippet")
+	reqCmd.Flags().StringVar(&flagRequestIdempotencyKey, "idempotency-key", "", "idempotency key")
+	reqCmd.Flags().StringVar(&flagRequestScript, "script", "", "script file")
+	reqCmd.Flags().StringVar(&flagRequestInterpreter, "interpreter", "", "script interpreter")
+	addProvenanceFlags(reqCmd)
+	addLabelFlags(reqCmd)
 
 	root.AddCommand(reqCmd)
 
@@ -68,6 +76,113 @@ func resetRequestFlags() {
 	flagRequestAttachFile = nil
 	flagRequestAttachContext = nil
 	flagRequestAttachScreen = nil
+	flagRequestContextFile = nil
+	flagRequestIdempotencyKey = ""
+	flagRequestScript = ""
+	flagRequestInterpreter = ""
+	resetProvenanceFlags()
+	resetLabelFlags()
+}
+
+func TestRequestCommand_IdempotencyKeyReplays(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetRequestFlags()
+
+	sess := testutil.MakeSession(t, h.DB,
+		testutil.WithProject(h.ProjectDir),
+		testutil.WithAgent("TestAgent"),
+	)
+
+	submit := func() map[string]any {
+		t.Helper()
+		resetRequestFlags()
+		cmd := newTestRequestCmd(h.DBPath)
+		stdout, err := executeCommandCapture(t, cmd, "request", "rm -rf ./build",
+			"-s", sess.ID,
+			"-C", h.ProjectDir,
+			"--idempotency-key", "retry-1",
+			"-j",
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var result map[string]any
+		if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+			t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+		}
+		return result
+	}
+
+	first := submit()
+	if _, ok := first["replayed"]; ok {
+		t.Errorf("first submission reported as replayed: %v", first)
+	}
+	again := submit()
+	if again["replayed"] != true {
+		t.Errorf("expected replayed=true on retry, got %v", again["replayed"])
+	}
+	if again["request_id"] != first["request_id"] {
+		t.Errorf("retry returned request %v, want %v", again["request_id"], first["request_id"])
+	}
+}
+
+func TestRequestCommand_Script(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetRequestFlags()
+
+	sess := testutil.MakeSession(t, h.DB,
+		testutil.WithProject(h.ProjectDir),
+		testutil.WithAgent("TestAgent"),
+	)
+	path := filepath.Join(h.ProjectDir, "reset.sh")
+	if err := os.WriteFile(path, []byte("#!/bin/bash\ngit fetch\ngit reset --hard origin/main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := newTestRequestCmd(h.DBPath)
+	if _, _, err := executeCommand(cmd, "request", "echo hi", "--script", path, "-s", sess.ID); err == nil {
+		t.Fatal("expected an error for a command together with --script")
+	}
+
+	resetRequestFlags()
+	cmd = newTestRequestCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "request",
+		"--script", path,
+		"--reason", "reset to upstream",
+		"-s", sess.ID,
+		"-C", h.ProjectDir,
+		"-j",
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var result map[string]any
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if result["tier"] != string(db.RiskTierDangerous) {
+		t.Errorf("expected tier=dangerous, got %v", result["tier"])
+	}
+	script, _ := result["script"].(map[string]any)
+	if script["interpreter"] != "/bin/bash" || script["source"] != path || script["statements"] != float64(2) {
+		t.Errorf("unexpected script: %v", result["script"])
+	}
+
+	resetReviewFlags()
+	show := newTestReviewCmd(h.DBPath)
+	stdout, err = executeCommandCapture(t, show, "review", "show", result["request_id"].(string), "-j")
+	if err != nil {
+		t.Fatalf("review show: %v", err)
+	}
+	var detail struct {
+		ScriptLines []core.ScriptLine `json:"script_lines"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &detail); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if len(detail.ScriptLines) != 2 || detail.ScriptLines[1].Line != 3 || detail.ScriptLines[1].Tier != core.RiskTierDangerous {
+		t.Errorf("unexpected script lines: %+v", detail.ScriptLines)
+	}
 }
 
 func TestRequestCommand_RequiresCommand(t *testing.T) {
@@ -141,6 +256,9 @@ func TestRequestCommand_CreatesDangerousRequest(t *testing.T) {
 	if tier != string(db.RiskTierDangerous) && tier != string(db.RiskTierCritical) {
 		t.Errorf("expected tier=dangerous or critical, got %v", tier)
 	}
+	if score, _ := result["risk_score"].(float64); score <= 0 || result["risk_factors"] == nil {
+		t.Errorf("expected a risk score and its factors, got %v / %v", result["risk_score"], result["risk_factors"])
+	}
 }
 
 func TestRequestCommand_WithJustification(t *testing.T) {
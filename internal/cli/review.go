@@ -1,25 +1,54 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
+	"net/url"
 	"strings"
 	"time"
 
 	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/core"
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/Dicklesworthstone/slb/internal/qrcode"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
+	"github.com/Dicklesworthstone/slb/internal/tui/components"
 	"github.com/spf13/cobra"
 )
 
 var (
-	flagReviewAll  bool
-	flagReviewPool bool
+	flagReviewAll      bool
+	flagReviewPool     bool
+	flagReviewLabels   []string
+	flagReviewExplain  bool
+	flagReviewTimeline bool
+	flagReviewSort     string
+
+	flagReviewWithAttachments bool
+	flagReviewTokenBudget     int
+	flagReviewQR              bool
+	flagReviewFormat          string
 )
 
 func init() {
 	reviewCmd.PersistentFlags().BoolVarP(&flagReviewAll, "all", "a", false, "show requests from all projects")
 	reviewCmd.PersistentFlags().BoolVar(&flagReviewPool, "review-pool", false, "show requests from configured review pool (cross-project)")
 
+	for _, c := range []*cobra.Command{reviewCmd, reviewListCmd} {
+		c.Flags().StringArrayVar(&flagReviewLabels, "label", nil, "only requests with this label (key=value or key; repeatable)")
+		c.Flags().StringVar(&flagReviewSort, "sort", queueSortCreated, queueSortUsage)
+	}
+
+	for _, c := range []*cobra.Command{reviewCmd, reviewShowCmd} {
+		c.Flags().BoolVar(&flagReviewExplain, "explain", false, "explain the classification, touched paths, impact, similar past requests and constraints")
+		c.Flags().BoolVar(&flagReviewTimeline, "timeline", false, "show the request's recorded lifecycle events")
+		c.Flags().BoolVar(&flagReviewWithAttachments, "with-attachments", false, "include attachment content")
+		c.Flags().IntVar(&flagReviewTokenBudget, "token-budget", 0, "cut attachments, transcript and dry-run output to fit about this many tokens (0 uses reviewers.token_budget, -1 disables)")
+		c.Flags().BoolVar(&flagReviewQR, "qr", false, "draw a QR code of a request summary (or reviewers.qr_url) to review on a phone")
+		c.Flags().StringVar(&flagReviewFormat, "format", "text", "text, or markdown for a standalone report to paste into a PR, incident doc or chat")
+	}
+
 	reviewCmd.AddCommand(reviewListCmd)
 	reviewCmd.AddCommand(reviewShowCmd)
 
@@ -50,6 +79,9 @@ var reviewListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List pending requests awaiting review",
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateQueueSort(flagReviewSort); err != nil {
+			return err
+		}
 		project, err := projectPath()
 		if err != nil {
 			return err
@@ -83,10 +115,14 @@ var reviewListCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("listing requests: %w", err)
 		}
+		requests, err = filterByLabels(dbConn, requests, flagReviewLabels)
+		if err != nil {
+			return err
+		}
 
 		if len(requests) == 0 {
 			out := output.New(output.Format(GetOutput()))
-			if GetOutput() == "json" {
+			if isJSONOutput() {
 				return out.Write([]any{})
 			}
 			fmt.Println("No pending requests found.")
@@ -95,17 +131,32 @@ var reviewListCmd = &cobra.Command{
 
 		// Build output
 		type requestSummary struct {
-			ID             string `json:"id"`
-			Command        string `json:"command"`
-			RiskTier       string `json:"risk_tier"`
-			RequestorAgent string `json:"requestor_agent"`
-			MinApprovals   int    `json:"min_approvals"`
-			CreatedAt      string `json:"created_at"`
-			ProjectPath    string `json:"project_path,omitempty"`
+			ID             string            `json:"id"`
+			Command        string            `json:"command"`
+			RiskTier       string            `json:"risk_tier"`
+			RequestorAgent string            `json:"requestor_agent"`
+			MinApprovals   int               `json:"min_approvals"`
+			CreatedAt      string            `json:"created_at"`
+			ProjectPath    string            `json:"project_path,omitempty"`
+			RiskScore      int               `json:"risk_score"`
+			Priority       int               `json:"priority"`
+			Waiting        bool              `json:"requestor_waiting,omitempty"`
+			SLADueAt       string            `json:"sla_due_at,omitempty"`
+			SLABreached    bool              `json:"sla_breached,omitempty"`
+			AwaitingHuman  bool              `json:"awaiting_human,omitempty"`
+			SeenBy         []string          `json:"seen_by,omitempty"`
+			Seen           string            `json:"seen,omitempty"`
+			Labels         map[string]string `json:"labels,omitempty"`
 		}
 
-		summaries := make([]requestSummary, 0, len(requests))
-		for _, r := range requests {
+		awaitingHuman := awaitingHumanSet(dbConn, requests)
+		seenBy := undecidedViewerSet(dbConn, requests)
+
+		queue := reviewQueue(dbConn, requests, cfg, flagReviewSort, time.Now())
+
+		summaries := make([]requestSummary, 0, len(queue))
+		for _, item := range queue {
+			r := item.Request
 			cmd := r.Command.Raw
 			if r.Command.ContainsSensitive && r.Command.DisplayRedacted != "" {
 				cmd = r.Command.DisplayRedacted
@@ -121,7 +172,16 @@ var reviewListCmd = &cobra.Command{
 				RiskTier:       string(r.RiskTier),
 				RequestorAgent: r.RequestorAgent,
 				MinApprovals:   r.MinApprovals,
-				CreatedAt:      r.CreatedAt.Format(time.RFC3339),
+				CreatedAt:      timefmt.Format(r.CreatedAt),
+				RiskScore:      r.RiskScore,
+				Priority:       item.Priority,
+				Waiting:        item.Waiting,
+				SLADueAt:       slaDueAt(item.SLA),
+				SLABreached:    item.SLA != nil && item.SLA.Breached,
+				AwaitingHuman:  awaitingHuman[r.ID],
+				SeenBy:         seenBy[r.ID],
+				Seen:           seenSummary(seenBy[r.ID]),
+				Labels:         r.Labels,
 			}
 			if flagReviewAll {
 				summary.ProjectPath = r.ProjectPath
@@ -137,13 +197,50 @@ var reviewListCmd = &cobra.Command{
 var reviewShowCmd = &cobra.Command{
 	Use:   "show <request-id>",
 	Short: "Show full details of a request",
-	Args:  cobra.ExactArgs(1),
+	Long: `Show full details of a request.
+
+With --format markdown the request is rendered as a self-contained report:
+the command, its classification, the justification, the reviews and, once
+it ran, the execution result with the end of its log.
+
+Examples:
+  slb review show <request-id> --explain
+  slb review show <request-id> --format markdown > request.md`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return showRequestDetails(args[0])
 	},
 }
 
+// recordViewed leaves a viewed receipt on a pending request for whoever is
+// looking at it: the --session-id agent, else the actor. Receipts are best
+// effort; a failure never stops the request from being shown.
+func recordViewed(dbConn *db.DB, request *db.Request) {
+	if request.Status != db.StatusPending {
+		return
+	}
+	viewer := GetActor()
+	if flagSessionID != "" {
+		sess, err := dbConn.GetSession(flagSessionID)
+		if err != nil {
+			return
+		}
+		viewer = sess.AgentName
+	}
+	_, _ = dbConn.RecordRequestViewed(request.ID, viewer)
+}
+
 func showRequestDetails(requestID string) error {
+	switch flagReviewFormat {
+	case "", "text":
+	case reviewFormatMarkdown:
+		if isJSONOutput() {
+			return fmt.Errorf("--format markdown and --json are mutually exclusive")
+		}
+	default:
+		return fmt.Errorf("invalid --format %q (want text or markdown)", flagReviewFormat)
+	}
+
 	dbConn, err := db.Open(GetDB())
 	if err != nil {
 		return fmt.Errorf("opening database: %w", err)
@@ -154,6 +251,7 @@ func showRequestDetails(requestID string) error {
 	if err != nil {
 		return fmt.Errorf("getting request: %w", err)
 	}
+	recordViewed(dbConn, request)
 
 	// Count approvals and rejections
 	var approvals, rejections int
@@ -166,6 +264,11 @@ func showRequestDetails(requestID string) error {
 		}
 	}
 
+	annotations, err := dbConn.ListAnnotationsForRequest(requestID)
+	if err != nil {
+		return fmt.Errorf("getting annotations: %w", err)
+	}
+
 	// Build output structure
 	type reviewView struct {
 		ID            string `json:"id"`
@@ -176,29 +279,64 @@ func showRequestDetails(requestID string) error {
 		CreatedAt     string `json:"created_at"`
 	}
 
+	type advisoryView struct {
+		Source         string `json:"source"`
+		Author         string `json:"author,omitempty"`
+		RiskSummary    string `json:"risk_summary"`
+		Recommendation string `json:"recommendation"`
+		CreatedAt      string `json:"created_at"`
+	}
+
+	type attachmentView struct {
+		Type     string         `json:"type"`
+		Content  string         `json:"content,omitempty"`
+		Metadata map[string]any `json:"metadata,omitempty"`
+	}
+
 	type requestDetail struct {
-		ID                    string       `json:"id"`
-		Status                string       `json:"status"`
-		RiskTier              string       `json:"risk_tier"`
-		Command               string       `json:"command"`
-		CommandHash           string       `json:"command_hash"`
-		Cwd                   string       `json:"cwd"`
-		ProjectPath           string       `json:"project_path"`
-		RequestorAgent        string       `json:"requestor_agent"`
-		RequestorModel        string       `json:"requestor_model"`
-		JustificationReason   string       `json:"justification_reason"`
-		JustificationEffect   string       `json:"justification_expected_effect,omitempty"`
-		JustificationGoal     string       `json:"justification_goal,omitempty"`
-		JustificationSafety   string       `json:"justification_safety_argument,omitempty"`
-		MinApprovals          int          `json:"min_approvals"`
-		CurrentApprovals      int          `json:"current_approvals"`
-		CurrentRejections     int          `json:"current_rejections"`
-		RequireDifferentModel bool         `json:"require_different_model"`
-		Reviews               []reviewView `json:"reviews,omitempty"`
-		DryRunCommand         string       `json:"dry_run_command,omitempty"`
-		DryRunOutput          string       `json:"dry_run_output,omitempty"`
-		CreatedAt             string       `json:"created_at"`
-		ExpiresAt             string       `json:"expires_at,omitempty"`
+		ID                    string                `json:"id"`
+		Status                string                `json:"status"`
+		RiskTier              string                `json:"risk_tier"`
+		Command               string                `json:"command"`
+		CommandHash           string                `json:"command_hash"`
+		Cwd                   string                `json:"cwd"`
+		ProjectPath           string                `json:"project_path"`
+		RequestorAgent        string                `json:"requestor_agent"`
+		RequestorModel        string                `json:"requestor_model"`
+		JustificationReason   string                `json:"justification_reason"`
+		JustificationEffect   string                `json:"justification_expected_effect,omitempty"`
+		JustificationGoal     string                `json:"justification_goal,omitempty"`
+		JustificationSafety   string                `json:"justification_safety_argument,omitempty"`
+		Labels                map[string]string     `json:"labels,omitempty"`
+		Provenance            *db.RequestProvenance `json:"provenance,omitempty"`
+		Binary                *db.RequestBinary     `json:"binary,omitempty"`
+		Script                *db.RequestScript     `json:"script,omitempty"`
+		ScriptLines           []core.ScriptLine     `json:"script_lines,omitempty"`
+		RemoteScripts         []*db.RemoteScript    `json:"remote_scripts,omitempty"`
+		Transcript            string                `json:"transcript,omitempty"`
+		MinApprovals          int                   `json:"min_approvals"`
+		CurrentApprovals      int                   `json:"current_approvals"`
+		CurrentRejections     int                   `json:"current_rejections"`
+		RequireDifferentModel bool                  `json:"require_different_model"`
+		Reviews               []reviewView          `json:"reviews,omitempty"`
+		Advisories            []advisoryView        `json:"advisories,omitempty"`
+		GitRewrite            string                `json:"git_rewrite,omitempty"`
+		Targets               []string              `json:"targets,omitempty"`
+		Deployments           []string              `json:"deployments,omitempty"`
+		Attachments           []attachmentView      `json:"attachments,omitempty"`
+		Instructions          string                `json:"reviewer_instructions,omitempty"`
+		Effort                core.ReviewEffort     `json:"review_effort"`
+		QR                    string                `json:"qr,omitempty"`
+		TemplateVars          []string              `json:"template_vars,omitempty"`
+		DryRunCommand         string                `json:"dry_run_command,omitempty"`
+		DryRunOutput          string                `json:"dry_run_output,omitempty"`
+		CreatedAt             string                `json:"created_at"`
+		ExpiresAt             string                `json:"expires_at,omitempty"`
+		AwaitingHumanSince    string                `json:"awaiting_human_since,omitempty"`
+		Explain               *requestExplanation   `json:"explain,omitempty"`
+		Timeline              []*db.RequestEvent    `json:"timeline,omitempty"`
+		EditedFrom            *db.CommandEdit       `json:"edited_from,omitempty"`
+		Edits                 []*db.CommandEdit     `json:"edits,omitempty"`
 	}
 
 	// Build command display
@@ -225,11 +363,48 @@ func showRequestDetails(requestID string) error {
 		CurrentApprovals:      approvals,
 		CurrentRejections:     rejections,
 		RequireDifferentModel: request.RequireDifferentModel,
-		CreatedAt:             request.CreatedAt.Format(time.RFC3339),
+		CreatedAt:             timefmt.Format(request.CreatedAt),
 	}
 
 	if request.ExpiresAt != nil {
-		detail.ExpiresAt = request.ExpiresAt.Format(time.RFC3339)
+		detail.ExpiresAt = timefmt.Format(*request.ExpiresAt)
+	}
+
+	detail.Transcript = transcriptSnippet(request.Attachments)
+	detail.GitRewrite = gitRewriteSummary(request.Attachments)
+	detail.Targets = contextLines(request.Attachments, "targets")
+	detail.Deployments = contextLines(request.Attachments, "deployment")
+	detail.TemplateVars = core.TemplateVars(request.Command.Raw)
+
+	if labels, err := dbConn.GetRequestLabels(requestID); err == nil {
+		detail.Labels = labels
+	}
+
+	if prov, err := dbConn.GetRequestProvenance(requestID); err == nil {
+		detail.Provenance = prov
+	}
+
+	if bin, err := dbConn.GetRequestBinary(requestID); err == nil {
+		detail.Binary = bin
+	}
+
+	// Scripts and other multi-line commands are shown a statement at a
+	// time with each statement's own tier.
+	if script, err := dbConn.GetRequestScript(requestID); err == nil {
+		detail.Script = script
+	}
+	if detail.RemoteScripts, err = dbConn.ListRequestRemoteScripts(requestID); err != nil {
+		return fmt.Errorf("getting remote scripts: %w", err)
+	}
+	if detail.Script != nil || core.IsMultilineCommand(cmd) {
+		_, _ = loadCustomPatternsIntoDefaultEngine()
+		detail.ScriptLines = core.GetDefaultEngine().AnnotateScript(cmd, request.Command.Cwd)
+	}
+
+	if request.Status == db.StatusPending {
+		if esc, err := dbConn.GetHumanEscalation(requestID); err == nil {
+			detail.AwaitingHumanSince = timefmt.Format(esc.PagedAt)
+		}
 	}
 
 	if request.DryRun != nil {
@@ -245,12 +420,94 @@ func showRequestDetails(requestID string) error {
 			ReviewerModel: rev.ReviewerModel,
 			Decision:      string(rev.Decision),
 			Comments:      rev.Comments,
-			CreatedAt:     rev.CreatedAt.Format(time.RFC3339),
+			CreatedAt:     timefmt.Format(rev.CreatedAt),
+		})
+	}
+
+	// Add advisory annotations (informational only; never approvals)
+	for _, a := range annotations {
+		detail.Advisories = append(detail.Advisories, advisoryView{
+			Source:         a.Source,
+			Author:         a.Author,
+			RiskSummary:    a.RiskSummary,
+			Recommendation: a.Recommendation,
+			CreatedAt:      timefmt.Format(a.CreatedAt),
 		})
 	}
 
+	if detail.Edits, err = dbConn.ListCommandEdits(requestID); err != nil {
+		return fmt.Errorf("getting edits: %w", err)
+	}
+	if edit, err := dbConn.GetCommandEditForRequest(requestID); err == nil {
+		detail.EditedFrom = edit
+	} else if !errors.Is(err, db.ErrCommandEditNotFound) {
+		return fmt.Errorf("getting edit: %w", err)
+	}
+
+	if flagReviewWithAttachments {
+		for _, a := range request.Attachments {
+			av := attachmentView{Type: string(a.Type), Metadata: a.Metadata}
+			switch text, err := a.Text(); {
+			case a.Type == db.AttachmentTypeScreenshot:
+				av.Content = fmt.Sprintf("[screenshot, %d bytes of base64 not shown]", len(a.Content))
+			case err == nil:
+				av.Content = text
+			default:
+				av.Content = a.Content
+			}
+			detail.Attachments = append(detail.Attachments, av)
+		}
+	}
+
+	// Estimate what the request costs an LLM reviewer to read and, under a
+	// token budget, cut the bulky context to fit.
+	budget := flagReviewTokenBudget
+	cfg, cfgErr := config.Load(config.LoadOptions{ProjectDir: request.ProjectPath, ConfigPath: flagConfig})
+	if cfgErr == nil {
+		detail.Instructions = strings.TrimSpace(cfg.Reviewers.Instructions)
+		if budget == 0 {
+			budget = cfg.Reviewers.TokenBudget
+		}
+	}
+	budget = max(budget, 0)
+	sections := []core.ReviewSection{
+		{Name: "transcript", Text: &detail.Transcript},
+		{Name: "dry-run output", Text: &detail.DryRunOutput},
+	}
+	for i := range detail.Attachments {
+		sections = append(sections, core.ReviewSection{
+			Name: fmt.Sprintf("attachment %d (%s)", i+1, detail.Attachments[i].Type),
+			Text: &detail.Attachments[i].Content,
+		})
+	}
+	detail.Effort = core.FitReviewBudget(budget, detail.Instructions, strings.Join([]string{
+		detail.Command, detail.JustificationReason, detail.JustificationEffect,
+		detail.JustificationGoal, detail.JustificationSafety,
+	}, "\n"), sections)
+
+	if flagReviewExplain || flagReviewFormat == reviewFormatMarkdown {
+		detail.Explain = explainRequest(dbConn, request, approvals, detail.GitRewrite, detail.Binary)
+	}
+	if flagReviewTimeline {
+		if detail.Timeline, err = dbConn.ListRequestEvents(requestID); err != nil {
+			return fmt.Errorf("getting timeline: %w", err)
+		}
+	}
+	var qr *qrcode.Code
+	if flagReviewQR {
+		detail.QR = requestQRContent(request, cfg.Reviewers.QRURL)
+		if qr, err = qrcode.Encode([]byte(detail.QR)); err != nil {
+			return fmt.Errorf("drawing QR code: %w", err)
+		}
+	}
+
+	if flagReviewFormat == reviewFormatMarkdown {
+		fmt.Print(requestMarkdown(request, reviews, detail.Labels, detail.Explain))
+		return nil
+	}
+
 	out := output.New(output.Format(GetOutput()))
-	if GetOutput() == "json" {
+	if isJSONOutput() {
 		return out.Write(detail)
 	}
 
@@ -258,12 +515,72 @@ func showRequestDetails(requestID string) error {
 	fmt.Printf("Request: %s\n", detail.ID)
 	fmt.Printf("Status:  %s\n", strings.ToUpper(detail.Status))
 	fmt.Printf("Risk:    %s\n", strings.ToUpper(detail.RiskTier))
+	if len(detail.Labels) > 0 {
+		fmt.Printf("Labels:  %s\n", db.FormatLabels(detail.Labels, ", "))
+	}
+	if detail.AwaitingHumanSince != "" {
+		fmt.Printf("AWAITING HUMAN: no agent reviewer acted (paged %s)\n", detail.AwaitingHumanSince)
+	}
+	if detail.Instructions != "" {
+		fmt.Println()
+		fmt.Println("Reviewer Instructions:")
+		for _, line := range strings.Split(detail.Instructions, "\n") {
+			fmt.Printf("  %s\n", line)
+		}
+	}
 	fmt.Println()
-	fmt.Printf("Command: %s\n", detail.Command)
+	if len(detail.ScriptLines) > 0 {
+		printScriptLines(detail.Script, detail.ScriptLines)
+	} else {
+		fmt.Printf("Command: %s\n", detail.Command)
+	}
 	fmt.Printf("Hash:    %s\n", detail.CommandHash)
 	fmt.Printf("CWD:     %s\n", detail.Cwd)
+	if b := detail.Binary; b != nil {
+		fmt.Printf("Runs:    %s\n", formatBinary(b))
+		if notice := binaryNotice(b); notice != "" {
+			fmt.Printf("LOCAL BINARY: %s\n", notice)
+		}
+	}
+	printRemoteScripts(detail.RemoteScripts)
+	if len(detail.Targets) > 0 {
+		fmt.Printf("Targets: %s\n", strings.Join(detail.Targets, ", "))
+	}
+	for _, d := range detail.Deployments {
+		fmt.Printf("Deploy:  %s\n", d)
+	}
+	if len(detail.TemplateVars) > 0 {
+		fmt.Printf("UNEXPANDED: %s (approving needs --ack-vars naming all of them)\n", strings.Join(detail.TemplateVars, ", "))
+	}
+	if detail.GitRewrite != "" {
+		fmt.Println("Git:")
+		for _, line := range strings.Split(detail.GitRewrite, "\n") {
+			fmt.Printf("  %s\n", line)
+		}
+	}
+	if e := detail.EditedFrom; e != nil {
+		fmt.Printf("Edited:  from request %s by %s (edit %s)\n", e.RequestID, e.ReviewerAgent, e.ID)
+	}
 	fmt.Println()
 	fmt.Printf("Requestor: %s (%s)\n", detail.RequestorAgent, detail.RequestorModel)
+	if p := detail.Provenance; p != nil {
+		fmt.Println("Provenance:")
+		if p.AgentProgram != "" {
+			fmt.Printf("  Program: %s\n", p.AgentProgram)
+		}
+		if p.ConversationID != "" {
+			fmt.Printf("  Conversation: %s\n", p.ConversationID)
+		}
+		if p.ToolCallID != "" {
+			fmt.Printf("  Tool Call: %s\n", p.ToolCallID)
+		}
+		if p.PromptHash != "" {
+			fmt.Printf("  Prompt Hash: %s\n", p.PromptHash)
+		}
+		if p.PeerUID != nil {
+			fmt.Printf("  Peer: %s\n", formatPeer(p))
+		}
+	}
 	fmt.Println()
 	fmt.Println("Justification:")
 	fmt.Printf("  Reason: %s\n", detail.JustificationReason)
@@ -276,6 +593,13 @@ func showRequestDetails(requestID string) error {
 	if detail.JustificationSafety != "" {
 		fmt.Printf("  Safety Argument: %s\n", detail.JustificationSafety)
 	}
+	if detail.Transcript != "" {
+		fmt.Println()
+		fmt.Println("Agent Transcript (snippet):")
+		for _, line := range strings.Split(detail.Transcript, "\n") {
+			fmt.Printf("  %s\n", line)
+		}
+	}
 	fmt.Println()
 	fmt.Printf("Approvals: %d/%d required\n", detail.CurrentApprovals, detail.MinApprovals)
 	if detail.CurrentRejections > 0 {
@@ -297,6 +621,17 @@ func showRequestDetails(requestID string) error {
 		}
 	}
 
+	if len(detail.Attachments) > 0 {
+		fmt.Println()
+		fmt.Println("Attachments:")
+		for i, a := range detail.Attachments {
+			fmt.Printf("  [%d] %s\n", i+1, a.Type)
+			for _, line := range strings.Split(a.Content, "\n") {
+				fmt.Printf("    %s\n", line)
+			}
+		}
+	}
+
 	if len(detail.Reviews) > 0 {
 		fmt.Println()
 		fmt.Println("Reviews:")
@@ -308,11 +643,119 @@ func showRequestDetails(requestID string) error {
 		}
 	}
 
+	if len(detail.Edits) > 0 {
+		fmt.Println()
+		fmt.Println("Proposed edits (approve-with-edit):")
+		for _, e := range detail.Edits {
+			fmt.Printf("  - %s by %s [%s]: %s\n", e.ID, e.ReviewerAgent, e.Status, e.Command)
+			if e.Comments != "" {
+				fmt.Printf("    Comment: %s\n", e.Comments)
+			}
+			if e.AcceptedRequestID != "" {
+				fmt.Printf("    Became request %s\n", e.AcceptedRequestID)
+			} else if e.Status == db.CommandEditProposed && detail.Status == string(db.StatusPending) {
+				fmt.Printf("    Accept with: slb accept-edit %s\n", e.ID)
+			}
+		}
+	}
+
+	if len(detail.Advisories) > 0 {
+		fmt.Println()
+		fmt.Println("Advisory (second opinion, does not count as approval):")
+		for _, a := range detail.Advisories {
+			author := a.Source
+			if a.Author != "" {
+				author = fmt.Sprintf("%s/%s", a.Source, a.Author)
+			}
+			fmt.Printf("  - %s (%s): %s\n", strings.ToUpper(a.Recommendation), author, a.RiskSummary)
+		}
+	}
+
+	if detail.Explain != nil {
+		printRequestExplanation(detail.Explain)
+	}
+
+	if flagReviewTimeline {
+		fmt.Println()
+		fmt.Println("Timeline:")
+		if len(detail.Timeline) == 0 {
+			fmt.Println("  no events recorded (request predates the timeline)")
+		} else {
+			fmt.Println(components.RenderTimelineExpanded(components.TimelineFromEvents(detail.Timeline), detail.Status))
+		}
+	}
+
+	fmt.Println()
+	printReviewEffort(detail.Effort)
+	times, now := timefmt.Current(), dbConn.Now()
+	fmt.Printf("Created: %s\n", times.Show(request.CreatedAt, now))
+	if request.ExpiresAt != nil {
+		fmt.Printf("Expires: %s\n", times.Show(*request.ExpiresAt, now))
+	}
+	if qr != nil {
+		fmt.Println()
+		fmt.Println("Scan to review on a phone:")
+		fmt.Print(qr.Terminal())
+	}
+
+	return nil
+}
+
+// requestQRContent is what `slb review show --qr` encodes: the configured
+// review page URL for the request, or else a plain-text summary of it.
+func requestQRContent(request *db.Request, pageURL string) string {
+	if pageURL != "" {
+		return strings.ReplaceAll(pageURL, "{id}", url.PathEscape(request.ID))
+	}
+	return core.RequestSummary(request)
+}
+
+// printReviewEffort prints the estimated cost of reviewing a request and
+// anything cut to fit the token budget.
+func printReviewEffort(e core.ReviewEffort) {
+	fmt.Printf("Review effort: ~%d tokens (instructions %d, command %d, context %d)",
+		e.TotalTokens, e.InstructionsTokens, e.CommandTokens, e.ContextTokens)
+	if e.Budget > 0 {
+		fmt.Printf(", budget %d", e.Budget)
+	}
 	fmt.Println()
-	fmt.Printf("Created: %s\n", detail.CreatedAt)
-	if detail.ExpiresAt != "" {
-		fmt.Printf("Expires: %s\n", detail.ExpiresAt)
+	for _, note := range e.Truncated {
+		fmt.Printf("  truncated: %s\n", note)
 	}
+}
 
+// transcriptSnippet returns the decoded text of all transcript attachments.
+func transcriptSnippet(attachments []db.Attachment) string {
+	var parts []string
+	for _, a := range attachments {
+		if a.Type != db.AttachmentTypeTranscript {
+			continue
+		}
+		if text, err := a.Text(); err == nil && text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, "\n---\n")
+}
+
+// gitRewriteSummary returns the branch/upstream summary attached to requests
+// that rewrite git history, or "" when there is none.
+func gitRewriteSummary(attachments []db.Attachment) string {
+	for _, a := range attachments {
+		if a.Type == db.AttachmentTypeContext && a.Metadata["type"] == "git_rewrite" {
+			return a.Content
+		}
+	}
+	return ""
+}
+
+// contextLines returns the lines of the context attachment of a type
+// ("targets", "deployment"), or nil when the request has none.
+func contextLines(attachments []db.Attachment, kind string) []string {
+	for _, a := range attachments {
+		if a.Type == db.AttachmentTypeContext && a.Metadata["type"] == kind {
+			return strings.Split(a.Content, "\n")
+		}
+	}
 	return nil
 }
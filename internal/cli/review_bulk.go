@@ -0,0 +1,326 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/i18n"
+	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagBulkIDs           []string
+	flagBulkAll           bool
+	flagBulkOlderThan     time.Duration
+	flagBulkReason        string
+	flagBulkComment       string
+	flagBulkSessionID     string
+	flagBulkSessionKey    string
+	flagBulkForceCritical bool
+)
+
+func init() {
+	for _, c := range []*cobra.Command{reviewApproveCmd, reviewRejectCmd} {
+		c.Flags().StringSliceVar(&flagBulkIDs, "ids", nil, "comma-separated request IDs to review (current project only)")
+		// Shadows the review-wide --all (all projects): here it selects every
+		// pending request in this project that you can review.
+		c.Flags().BoolVar(&flagBulkAll, "all", false, "review every pending request in this project you have not reviewed")
+		c.Flags().DurationVar(&flagBulkOlderThan, "older-than", 0, "with --all, only requests older than this (e.g. 2h)")
+		c.Flags().StringVarP(&flagBulkComment, "comment", "m", "", "comment recorded on every review")
+		c.Flags().StringVar(&flagBulkSessionID, "session-id", "", "reviewer session ID (default: SLB_SESSION_ID, then your active session)")
+		c.Flags().StringVarP(&flagBulkSessionKey, "session-key", "k", "", "session HMAC key for signing (default: SLB_SESSION_KEY, then the keyring)")
+	}
+	reviewApproveCmd.Flags().BoolVar(&flagBulkForceCritical, "force-critical", false, "allow CRITICAL tier requests in a bulk approval")
+	reviewRejectCmd.Flags().StringVarP(&flagBulkReason, "reason", "r", "", "reason recorded on every rejection (required)")
+
+	reviewCmd.AddCommand(reviewApproveCmd)
+	reviewCmd.AddCommand(reviewRejectCmd)
+}
+
+var reviewApproveCmd = &cobra.Command{
+	Use:   "approve",
+	Short: "Approve several pending requests at once",
+	Long: `Approve several requests in one transaction.
+
+Select requests with --ids or --all (optionally narrowed with --older-than).
+Every request is validated first; if any of them cannot be approved, nothing
+is recorded and the per-request errors are reported.
+
+CRITICAL tier requests are refused unless --force-critical is given.
+
+Examples:
+  slb review approve --ids a1b2,c3d4 --comment "batch of build cleanups"
+  slb review approve --all --older-than 30m --force-critical`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBulkReview(db.DecisionApprove)
+	},
+}
+
+var reviewRejectCmd = &cobra.Command{
+	Use:   "reject",
+	Short: "Reject several pending requests at once",
+	Long: `Reject several requests in one transaction.
+
+Select requests with --ids or --all (optionally narrowed with --older-than).
+Every request is validated first; if any of them cannot be rejected, nothing
+is recorded and the per-request errors are reported.
+
+Examples:
+  slb review reject --all --older-than 2h --reason "stale"
+  slb review reject --ids a1b2,c3d4 -r "superseded by a1b9"`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBulkReview(db.DecisionReject)
+	},
+}
+
+// bulkItemResult is the outcome for one request in a bulk review.
+type bulkItemResult struct {
+	RequestID        string       `json:"request_id"`
+	RiskTier         string       `json:"risk_tier,omitempty"`
+	OK               bool         `json:"ok"`
+	Error            string       `json:"error,omitempty"`
+	ReviewID         string       `json:"review_id,omitempty"`
+	NewRequestStatus string       `json:"new_request_status,omitempty"`
+	Quorum           *quorumState `json:"quorum,omitempty"`
+}
+
+// bulkReviewResult is the JSON output of a bulk review.
+type bulkReviewResult struct {
+	Decision  string           `json:"decision"`
+	Committed bool             `json:"committed"`
+	Total     int              `json:"total"`
+	Failed    int              `json:"failed"`
+	Items     []bulkItemResult `json:"items"`
+}
+
+func runBulkReview(decision db.Decision) error {
+	if len(flagBulkIDs) > 0 == flagBulkAll {
+		return fmt.Errorf("specify exactly one of --ids or --all")
+	}
+	if flagBulkOlderThan < 0 {
+		return fmt.Errorf("--older-than cannot be negative")
+	}
+	if flagBulkOlderThan > 0 && !flagBulkAll {
+		return fmt.Errorf("--older-than can only be used with --all")
+	}
+	if decision == db.DecisionReject && flagBulkReason == "" {
+		return errors.New(i18n.T("error.reason_required"))
+	}
+
+	project, err := projectPath()
+	if err != nil {
+		return err
+	}
+	dbConn, err := db.OpenAndMigrate(GetDB())
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer dbConn.Close()
+
+	reviewerID, err := resolveReviewerSessionID(dbConn, project, flagBulkSessionID)
+	if err != nil {
+		return err
+	}
+	sessionKey, err := resolveSessionKey(flagBulkSessionKey, reviewerID)
+	if err != nil {
+		return err
+	}
+
+	requests, items, err := selectBulkRequests(dbConn, project, reviewerID)
+	if err != nil {
+		return err
+	}
+
+	res := bulkReviewResult{Decision: string(decision), Total: len(items), Items: items}
+	if len(items) == 0 {
+		return writeBulkResult(res, nil)
+	}
+
+	// Safety rail: bulk approvals skip the per-request look a CRITICAL
+	// command deserves unless explicitly forced.
+	if decision == db.DecisionApprove && !flagBulkForceCritical {
+		for i, r := range requests {
+			if r != nil && r.RiskTier == db.RiskTierCritical {
+				res.Items[i].Error = "critical tier requires --force-critical for bulk approval"
+			}
+		}
+	}
+	if failed := countBulkFailures(res.Items); failed > 0 {
+		res.Failed = failed
+		return writeBulkResult(res, bulkAbortError(failed))
+	}
+
+	comments := flagBulkComment
+	if decision == db.DecisionReject {
+		comments = flagBulkReason
+		if flagBulkComment != "" {
+			comments = flagBulkReason + "\n\n" + flagBulkComment
+		}
+	}
+	cfg, err := config.Load(config.LoadOptions{ProjectDir: project, ConfigPath: flagConfig})
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	reviewCfg := core.ReviewConfigFromConfig(cfg)
+
+	// Sudo mode: one authentication covers the whole batch.
+	var freshAuth *core.FreshAuth
+	if decision == db.DecisionApprove {
+		for _, r := range requests {
+			if reviewCfg.RequiresFreshAuth(r.RiskTier) {
+				if freshAuth, err = authenticateSudo(cfg); err != nil {
+					return fmt.Errorf("sudo mode: %w", err)
+				}
+				break
+			}
+		}
+	}
+
+	batch := make([]core.ReviewOptions, len(requests))
+	for i, r := range requests {
+		batch[i] = core.ReviewOptions{
+			SessionID:  reviewerID,
+			SessionKey: sessionKey,
+			RequestID:  r.ID,
+			Decision:   decision,
+			Comments:   comments,
+			FreshAuth:  freshAuth,
+		}
+	}
+
+	reviewSvc := core.NewReviewService(dbConn, reviewCfg)
+	reviewSvc.SetNotifier(buildAgentMailNotifier(project))
+	results, err := reviewSvc.SubmitReviews(batch)
+	if err != nil {
+		var batchErr *core.BatchReviewError
+		if !errors.As(err, &batchErr) {
+			return fmt.Errorf("submitting reviews: %w", err)
+		}
+		for i, itemErr := range batchErr.Errs {
+			res.Items[i].Error = itemErr.Error()
+		}
+		res.Failed = len(batchErr.Errs)
+		return writeBulkResult(res, bulkAbortError(res.Failed))
+	}
+
+	res.Committed = true
+	for i, result := range results {
+		item := &res.Items[i]
+		item.OK = true
+		item.ReviewID = result.Review.ID
+		if result.RequestStatusChanged {
+			item.NewRequestStatus = string(result.NewRequestStatus)
+		}
+		if q, err := buildQuorumState(dbConn, item.RequestID, result); err == nil {
+			item.Quorum = &q
+		}
+	}
+	return writeBulkResult(res, nil)
+}
+
+// selectBulkRequests resolves --ids or --all into requests. With --ids,
+// unknown IDs and requests of other projects are reported as item errors,
+// as --all only selects the current project's requests.
+func selectBulkRequests(dbConn *db.DB, project, reviewerID string) ([]*db.Request, []bulkItemResult, error) {
+	var requests []*db.Request
+	if flagBulkAll {
+		candidates, err := reviewableRequests(dbConn, project, reviewerID)
+		if err != nil {
+			return nil, nil, err
+		}
+		cutoff := time.Now().Add(-flagBulkOlderThan)
+		for _, r := range candidates {
+			if flagBulkOlderThan > 0 && !r.CreatedAt.Before(cutoff) {
+				continue
+			}
+			requests = append(requests, r)
+		}
+	} else {
+		for _, id := range dedupeStrings(flagBulkIDs) {
+			id = strings.TrimSpace(id)
+			if id == "" {
+				continue
+			}
+			r, err := dbConn.GetRequest(id)
+			if err != nil && !errors.Is(err, db.ErrRequestNotFound) {
+				return nil, nil, fmt.Errorf("getting request %s: %w", id, err)
+			}
+			if r == nil {
+				r = &db.Request{ID: id}
+			}
+			requests = append(requests, r)
+		}
+	}
+
+	items := make([]bulkItemResult, len(requests))
+	for i, r := range requests {
+		items[i] = bulkItemResult{RequestID: r.ID, RiskTier: string(r.RiskTier)}
+		switch {
+		case r.RiskTier == "":
+			items[i].Error = "request not found"
+		case r.ProjectPath != project:
+			items[i].Error = "request belongs to another project"
+		}
+	}
+	return requests, items, nil
+}
+
+func countBulkFailures(items []bulkItemResult) int {
+	n := 0
+	for _, it := range items {
+		if it.Error != "" {
+			n++
+		}
+	}
+	return n
+}
+
+func bulkAbortError(failed int) error {
+	return fmt.Errorf("bulk review aborted: %d request(s) failed validation; no reviews were recorded", failed)
+}
+
+// writeBulkResult prints the per-request results and returns runErr so the
+// command still exits non-zero after reporting a failed batch.
+func writeBulkResult(res bulkReviewResult, runErr error) error {
+	if isJSONOutput() {
+		out := output.New(output.Format(GetOutput()))
+		if err := out.Write(res); err != nil {
+			return err
+		}
+		return runErr
+	}
+
+	if res.Total == 0 {
+		fmt.Println("No matching pending requests.")
+		return nil
+	}
+
+	verb := "Approved"
+	if res.Decision == string(db.DecisionReject) {
+		verb = "Rejected"
+	}
+	if res.Committed {
+		fmt.Printf("%s %d request(s)\n", verb, res.Total)
+	}
+	for _, it := range res.Items {
+		switch {
+		case it.Error != "":
+			fmt.Printf("  %s  %s  error: %s\n", it.RequestID, it.RiskTier, it.Error)
+		case it.NewRequestStatus != "":
+			fmt.Printf("  %s  %s  -> %s\n", it.RequestID, it.RiskTier, it.NewRequestStatus)
+		case it.Quorum != nil:
+			fmt.Printf("  %s  %s  %d/%d approvals\n", it.RequestID, it.RiskTier, it.Quorum.Approvals, it.Quorum.MinApprovals)
+		default:
+			fmt.Printf("  %s  %s  not recorded\n", it.RequestID, it.RiskTier)
+		}
+	}
+	return runErr
+}
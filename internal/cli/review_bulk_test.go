@@ -0,0 +1,302 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+	"github.com/spf13/cobra"
+)
+
+// newTestBulkReviewCmd creates a review command tree with the bulk
+// approve/reject subcommands, keeping the review-wide --all persistent flag
+// so the local --all shadowing is exercised.
+func newTestBulkReviewCmd(dbPath string) *cobra.Command {
+	root := &cobra.Command{
+		Use:           "slb",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	root.PersistentFlags().StringVar(&flagDB, "db", dbPath, "database path")
+	root.PersistentFlags().StringVarP(&flagOutput, "output", "o", "text", "output format")
+	root.PersistentFlags().BoolVarP(&flagJSON, "json", "j", false, "json output")
+	root.PersistentFlags().StringVarP(&flagProject, "project", "C", "", "project directory")
+	root.PersistentFlags().StringVarP(&flagConfig, "config", "c", "", "config file")
+
+	revCmd := &cobra.Command{Use: "review", RunE: reviewCmd.RunE}
+	revCmd.PersistentFlags().BoolVarP(&flagReviewAll, "all", "a", false, "show requests from all projects")
+
+	approve := &cobra.Command{Use: "approve", Args: cobra.NoArgs, RunE: reviewApproveCmd.RunE}
+	reject := &cobra.Command{Use: "reject", Args: cobra.NoArgs, RunE: reviewRejectCmd.RunE}
+	for _, c := range []*cobra.Command{approve, reject} {
+		c.Flags().StringSliceVar(&flagBulkIDs, "ids", nil, "request IDs")
+		c.Flags().BoolVar(&flagBulkAll, "all", false, "all reviewable requests")
+		c.Flags().DurationVar(&flagBulkOlderThan, "older-than", 0, "minimum age")
+		c.Flags().StringVarP(&flagBulkComment, "comment", "m", "", "comment")
+		c.Flags().StringVar(&flagBulkSessionID, "session-id", "", "reviewer session ID")
+		c.Flags().StringVarP(&flagBulkSessionKey, "session-key", "k", "", "session key")
+	}
+	approve.Flags().BoolVar(&flagBulkForceCritical, "force-critical", false, "allow critical")
+	reject.Flags().StringVarP(&flagBulkReason, "reason", "r", "", "reason")
+
+	revCmd.AddCommand(approve, reject)
+	root.AddCommand(revCmd)
+	return root
+}
+
+func resetBulkReviewFlags() {
+	resetReviewFlags()
+	flagBulkIDs = nil
+	flagBulkAll = false
+	flagBulkOlderThan = 0
+	flagBulkReason = ""
+	flagBulkComment = ""
+	flagBulkSessionID = ""
+	flagBulkSessionKey = ""
+	flagBulkForceCritical = false
+}
+
+// bulkFixture creates a requestor and a reviewer session in the harness project.
+func bulkFixture(t *testing.T, h *testutil.Harness) (requestor, reviewer *db.Session) {
+	t.Helper()
+	requestor = testutil.MakeSession(t, h.DB,
+		testutil.WithProject(h.ProjectDir),
+		testutil.WithAgent("Requestor"),
+	)
+	reviewer = testutil.MakeSession(t, h.DB,
+		testutil.WithProject(h.ProjectDir),
+		testutil.WithAgent("Reviewer"),
+	)
+	return requestor, reviewer
+}
+
+func makeBulkRequest(t *testing.T, h *testutil.Harness, sess *db.Session, tier db.RiskTier) *db.Request {
+	t.Helper()
+	req := testutil.MakeRequest(t, h.DB, sess, testutil.WithRisk(tier))
+	h.DB.Exec(`UPDATE requests SET min_approvals = 1, require_different_model = false WHERE id = ?`, req.ID)
+	return req
+}
+
+func TestReviewApproveBulk_IDs(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetBulkReviewFlags()
+	requestor, reviewer := bulkFixture(t, h)
+	a := makeBulkRequest(t, h, requestor, db.RiskTierDangerous)
+	b := makeBulkRequest(t, h, requestor, db.RiskTierDangerous)
+
+	cmd := newTestBulkReviewCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "review", "approve",
+		"--ids", a.ID+","+b.ID,
+		"--session-id", reviewer.ID,
+		"-k", reviewer.SessionKey,
+		"-m", "batch ok",
+		"-C", h.ProjectDir,
+		"-j",
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var res bulkReviewResult
+	if err := json.Unmarshal([]byte(stdout), &res); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if !res.Committed || res.Total != 2 || res.Failed != 0 || res.Decision != "approve" {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	for _, it := range res.Items {
+		if !it.OK || it.ReviewID == "" || it.NewRequestStatus != string(db.StatusApproved) {
+			t.Errorf("unexpected item: %+v", it)
+		}
+		if it.Quorum == nil || !it.Quorum.Satisfied {
+			t.Errorf("expected satisfied quorum for %s, got %+v", it.RequestID, it.Quorum)
+		}
+	}
+	for _, id := range []string{a.ID, b.ID} {
+		got, _ := h.DB.GetRequest(id)
+		if got.Status != db.StatusApproved {
+			t.Errorf("request %s status = %s, want approved", id, got.Status)
+		}
+	}
+}
+
+func TestReviewRejectBulk_AllOlderThan(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetBulkReviewFlags()
+	requestor, reviewer := bulkFixture(t, h)
+	stale := makeBulkRequest(t, h, requestor, db.RiskTierDangerous)
+	fresh := makeBulkRequest(t, h, requestor, db.RiskTierDangerous)
+	own := makeBulkRequest(t, h, reviewer, db.RiskTierDangerous)
+	old := time.Now().Add(-3 * time.Hour).UTC().Format(time.RFC3339)
+	h.DB.Exec(`UPDATE requests SET created_at = ? WHERE id IN (?, ?)`, old, stale.ID, own.ID)
+
+	cmd := newTestBulkReviewCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "review", "reject",
+		"--all", "--older-than", "2h",
+		"--reason", "stale",
+		"--session-id", reviewer.ID,
+		"-k", reviewer.SessionKey,
+		"-C", h.ProjectDir,
+		"-j",
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var res bulkReviewResult
+	if err := json.Unmarshal([]byte(stdout), &res); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if !res.Committed || res.Total != 1 || res.Items[0].RequestID != stale.ID {
+		t.Fatalf("expected only the stale request to be rejected, got %+v", res)
+	}
+
+	for id, want := range map[string]db.RequestStatus{
+		stale.ID: db.StatusRejected,
+		fresh.ID: db.StatusPending,
+		own.ID:   db.StatusPending,
+	} {
+		got, _ := h.DB.GetRequest(id)
+		if got.Status != want {
+			t.Errorf("request %s status = %s, want %s", id, got.Status, want)
+		}
+	}
+	reviews, _ := h.DB.ListReviewsForRequest(stale.ID)
+	if len(reviews) != 1 || reviews[0].Comments != "stale" {
+		t.Errorf("expected reason on review, got %+v", reviews)
+	}
+}
+
+func TestReviewApproveBulk_CriticalRequiresForce(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetBulkReviewFlags()
+	requestor, reviewer := bulkFixture(t, h)
+	safe := makeBulkRequest(t, h, requestor, db.RiskTierDangerous)
+	crit := makeBulkRequest(t, h, requestor, db.RiskTierCritical)
+
+	args := []string{"review", "approve",
+		"--ids", safe.ID + "," + crit.ID,
+		"--session-id", reviewer.ID,
+		"-k", reviewer.SessionKey,
+		"-C", h.ProjectDir,
+		"-j",
+	}
+	cmd := newTestBulkReviewCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, args...)
+	if err == nil || !strings.Contains(err.Error(), "no reviews were recorded") {
+		t.Fatalf("expected aborted batch, got %v", err)
+	}
+	var res bulkReviewResult
+	if err := json.Unmarshal([]byte(stdout), &res); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if res.Committed || res.Failed != 1 || !strings.Contains(res.Items[1].Error, "--force-critical") {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if reviews, _ := h.DB.ListReviewsForRequest(safe.ID); len(reviews) != 0 {
+		t.Fatalf("expected no reviews recorded, got %d", len(reviews))
+	}
+
+	resetBulkReviewFlags()
+	cmd = newTestBulkReviewCmd(h.DBPath)
+	if _, err := executeCommandCapture(t, cmd, append(args, "--force-critical")...); err != nil {
+		t.Fatalf("unexpected error with --force-critical: %v", err)
+	}
+	if reviews, _ := h.DB.ListReviewsForRequest(crit.ID); len(reviews) != 1 {
+		t.Fatalf("expected critical request reviewed, got %d reviews", len(reviews))
+	}
+}
+
+func TestReviewApproveBulk_UnknownIDRecordsNothing(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetBulkReviewFlags()
+	requestor, reviewer := bulkFixture(t, h)
+	a := makeBulkRequest(t, h, requestor, db.RiskTierDangerous)
+
+	cmd := newTestBulkReviewCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "review", "approve",
+		"--ids", a.ID+",does-not-exist",
+		"--session-id", reviewer.ID,
+		"-k", reviewer.SessionKey,
+		"-C", h.ProjectDir,
+		"-j",
+	)
+	if err == nil {
+		t.Fatal("expected error for unknown request ID")
+	}
+	var res bulkReviewResult
+	if err := json.Unmarshal([]byte(stdout), &res); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if res.Committed || res.Items[1].Error == "" || res.Items[0].OK {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if reviews, _ := h.DB.ListReviewsForRequest(a.ID); len(reviews) != 0 {
+		t.Fatalf("expected no reviews recorded, got %d", len(reviews))
+	}
+}
+
+func TestReviewApproveBulk_OtherProjectIDRecordsNothing(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetBulkReviewFlags()
+	requestor, reviewer := bulkFixture(t, h)
+	a := makeBulkRequest(t, h, requestor, db.RiskTierDangerous)
+	outsider := testutil.MakeSession(t, h.DB,
+		testutil.WithProject(t.TempDir()),
+		testutil.WithAgent("Outsider"),
+	)
+	other := makeBulkRequest(t, h, outsider, db.RiskTierDangerous)
+
+	cmd := newTestBulkReviewCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "review", "approve",
+		"--ids", a.ID+","+other.ID,
+		"--session-id", reviewer.ID,
+		"-k", reviewer.SessionKey,
+		"-C", h.ProjectDir,
+		"-j",
+	)
+	if err == nil {
+		t.Fatal("expected error for a request of another project")
+	}
+	var res bulkReviewResult
+	if err := json.Unmarshal([]byte(stdout), &res); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if res.Committed || !strings.Contains(res.Items[1].Error, "another project") || res.Items[0].OK {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	for _, id := range []string{a.ID, other.ID} {
+		if reviews, _ := h.DB.ListReviewsForRequest(id); len(reviews) != 0 {
+			t.Fatalf("expected no reviews recorded for %s, got %d", id, len(reviews))
+		}
+	}
+}
+
+func TestReviewBulk_SelectorValidation(t *testing.T) {
+	h := testutil.NewHarness(t)
+
+	cases := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"neither", []string{"review", "approve"}, "exactly one of --ids or --all"},
+		{"both", []string{"review", "approve", "--all", "--ids", "x"}, "exactly one of --ids or --all"},
+		{"older-than without all", []string{"review", "approve", "--ids", "x", "--older-than", "1h"}, "only be used with --all"},
+		{"reject without reason", []string{"review", "reject", "--all"}, "--reason"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resetBulkReviewFlags()
+			cmd := newTestBulkReviewCmd(h.DBPath)
+			_, err := executeCommandCapture(t, cmd, tc.args...)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,323 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/i18n"
+	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagBundleReviewer   string
+	flagBundleOutputFile string
+	flagBundleApprove    bool
+	flagBundleReject     bool
+	flagBundleReason     string
+	flagBundleComments   string
+	flagBundleSessionKey string
+)
+
+func init() {
+	reviewExportCmd.Flags().StringVar(&flagBundleReviewer, "reviewer", "", "session ID of the reviewer who will sign the bundle (required)")
+	// Named --output-file: the persistent --output/-o is the output format.
+	reviewExportCmd.Flags().StringVar(&flagBundleOutputFile, "output-file", "", "bundle path (default: slb-review-<request-id>.json)")
+
+	reviewSignCmd.Flags().BoolVar(&flagBundleApprove, "approve", false, "approve the request")
+	reviewSignCmd.Flags().BoolVar(&flagBundleReject, "reject", false, "reject the request")
+	reviewSignCmd.Flags().StringVarP(&flagBundleReason, "reason", "r", "", "reason for rejection (required with --reject)")
+	reviewSignCmd.Flags().StringVarP(&flagBundleComments, "comments", "m", "", "additional comments")
+	reviewSignCmd.Flags().StringVarP(&flagBundleSessionKey, "session-key", "k", "", "reviewer session key (default: SLB_SESSION_KEY, then the keyring)")
+	reviewSignCmd.Flags().StringVar(&flagBundleOutputFile, "output-file", "", "write the signed bundle here instead of over the input")
+	reviewSignCmd.MarkFlagsMutuallyExclusive("approve", "reject")
+
+	reviewCmd.AddCommand(reviewExportCmd)
+	reviewCmd.AddCommand(reviewSignCmd)
+	reviewCmd.AddCommand(reviewImportCmd)
+}
+
+var reviewExportCmd = &cobra.Command{
+	Use:   "export <request-id>",
+	Short: "Write a signed review bundle for an offline reviewer",
+	Long: `Write a pending request to a review bundle for a reviewer who cannot reach
+the daemon or this machine, e.g. on an air-gapped workstation.
+
+The bundle is a JSON file holding the command, its tier and justification,
+signed with the reviewer session's key. Carry it to the reviewer, who checks
+and signs it with 'slb review sign', then bring it back and apply the
+decision with 'slb review import'. Only the session named by --reviewer can
+sign it.
+
+Examples:
+  slb review export abc123 --reviewer $REVIEWER_SESSION
+  slb review export abc123 --reviewer $REVIEWER_SESSION --output-file /media/usb/abc123.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagBundleReviewer == "" {
+			return fmt.Errorf("--reviewer is required")
+		}
+
+		dbConn, err := db.Open(GetDB())
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer dbConn.Close()
+
+		req, err := dbConn.GetRequest(args[0])
+		if err != nil {
+			return fmt.Errorf("getting request: %w", err)
+		}
+		reviewer, err := dbConn.GetSession(flagBundleReviewer)
+		if err != nil {
+			return fmt.Errorf("getting reviewer session: %w", err)
+		}
+		bundle, err := core.NewReviewBundle(req, reviewer, time.Now())
+		if err != nil {
+			return err
+		}
+
+		path := flagBundleOutputFile
+		if path == "" {
+			path = fmt.Sprintf("slb-review-%s.json", req.ID)
+		}
+		if err := writeReviewBundle(path, bundle); err != nil {
+			return err
+		}
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+
+		if GetOutput() != "text" {
+			return output.New(output.Format(GetOutput())).Write(map[string]any{
+				"request_id":          bundle.RequestID,
+				"reviewer_session_id": bundle.ReviewerSessionID,
+				"file":                path,
+			})
+		}
+		output.Notef(os.Stdout, "Exported request %s for %s to %s\n", bundle.RequestID, bundle.ReviewerAgent, path)
+		output.Notef(os.Stdout, "Sign it with: slb review sign %s --approve|--reject\n", filepath.Base(path))
+		return nil
+	},
+}
+
+var reviewSignCmd = &cobra.Command{
+	Use:   "sign <bundle.json>",
+	Short: "Check a review bundle and sign a decision into it",
+	Long: `Check a review bundle written by 'slb review export' and sign your decision
+into it. Needs no daemon or database: the bundle's signature is checked with
+your session key, given by --session-key, SLB_SESSION_KEY or the keyring.
+
+Without --approve or --reject the bundle is only checked and the request
+shown, so you can read it before deciding. A rejection needs --reason.
+
+The signed bundle replaces the input unless --output-file is given. Take it
+back to the machine it came from and run 'slb review import'.
+
+Examples:
+  slb review sign slb-review-abc123.json -k $SESSION_KEY
+  slb review sign slb-review-abc123.json --approve -m "Checked the backup first"
+  slb review sign slb-review-abc123.json --reject -r "Wrong cluster"`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bundle, err := readReviewBundle(args[0])
+		if err != nil {
+			return err
+		}
+		sessionKey, err := resolveSessionKey(flagBundleSessionKey, bundle.ReviewerSessionID)
+		if err != nil {
+			return err
+		}
+		if err := bundle.VerifyExport(sessionKey); err != nil {
+			return err
+		}
+
+		if !flagBundleApprove && !flagBundleReject {
+			return writeBundleSummary(bundle)
+		}
+
+		decision, comments := db.DecisionApprove, flagBundleComments
+		if flagBundleReject {
+			if flagBundleReason == "" {
+				return errors.New(i18n.T("error.reason_required"))
+			}
+			decision, comments = db.DecisionReject, flagBundleReason
+			if flagBundleComments != "" {
+				comments = flagBundleReason + "\n\n" + flagBundleComments
+			}
+		}
+		if err := bundle.Sign(sessionKey, decision, comments, time.Now()); err != nil {
+			return err
+		}
+
+		path := flagBundleOutputFile
+		if path == "" {
+			path = args[0]
+		}
+		if err := writeReviewBundle(path, bundle); err != nil {
+			return err
+		}
+
+		if GetOutput() != "text" {
+			return output.New(output.Format(GetOutput())).Write(map[string]any{
+				"request_id": bundle.RequestID,
+				"decision":   string(decision),
+				"signed_at":  timefmt.Format(bundle.Decision.SignedAt),
+				"file":       path,
+			})
+		}
+		output.Notef(os.Stdout, "Signed %s of request %s into %s\n", decision, bundle.RequestID, path)
+		output.Notef(os.Stdout, "Apply it with: slb review import %s\n", filepath.Base(path))
+		return nil
+	},
+}
+
+var reviewImportCmd = &cobra.Command{
+	Use:   "import <bundle.json>",
+	Short: "Apply the decision signed into a review bundle",
+	Long: `Apply the decision in a review bundle signed with 'slb review sign'.
+
+The export and decision signatures are checked against the reviewer
+session's key, and the request's command must be unchanged since export. The
+decision is then recorded as that session's review, under the same rules as
+'slb approve' and 'slb reject': the session must still be active, and
+approvals of tiers covered by sudo mode need fresh authentication here.
+
+Examples:
+  slb review import slb-review-abc123.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bundle, err := readReviewBundle(args[0])
+		if err != nil {
+			return err
+		}
+
+		dbConn, err := db.OpenAndMigrate(GetDB())
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer dbConn.Close()
+
+		req, err := dbConn.GetRequest(bundle.RequestID)
+		if err != nil {
+			return fmt.Errorf("getting request: %w", err)
+		}
+		reviewer, err := dbConn.GetSession(bundle.ReviewerSessionID)
+		if err != nil {
+			return fmt.Errorf("getting reviewer session: %w", err)
+		}
+		if err := bundle.Verify(req, reviewer.SessionKey); err != nil {
+			return err
+		}
+
+		cfg, err := config.Load(config.LoadOptions{ProjectDir: req.ProjectPath, ConfigPath: flagConfig})
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		var freshAuth *core.FreshAuth
+		if bundle.Decision.Decision == db.DecisionApprove {
+			if freshAuth, err = freshAuthFor(cfg, req.RiskTier); err != nil {
+				return fmt.Errorf("sudo mode: %w", err)
+			}
+		}
+
+		reviewSvc := core.NewReviewService(dbConn, core.ReviewConfigFromConfig(cfg))
+		reviewSvc.SetNotifier(buildAgentMailNotifier(req.ProjectPath))
+		result, err := reviewSvc.SubmitReview(core.ReviewOptions{
+			SessionID:  reviewer.ID,
+			SessionKey: reviewer.SessionKey,
+			RequestID:  req.ID,
+			Decision:   bundle.Decision.Decision,
+			Comments:   bundle.Decision.Comments,
+			FreshAuth:  freshAuth,
+		})
+		if err != nil {
+			return fmt.Errorf("submitting review: %w", err)
+		}
+
+		if GetOutput() != "text" {
+			resp := map[string]any{
+				"review_id":              result.Review.ID,
+				"request_id":             req.ID,
+				"decision":               string(result.Review.Decision),
+				"reviewer_agent":         reviewer.AgentName,
+				"signed_at":              timefmt.Format(bundle.Decision.SignedAt),
+				"approvals":              result.Approvals,
+				"rejections":             result.Rejections,
+				"request_status_changed": result.RequestStatusChanged,
+			}
+			if result.RequestStatusChanged {
+				resp["new_request_status"] = string(result.NewRequestStatus)
+			}
+			return output.New(output.Format(GetOutput())).Write(resp)
+		}
+		output.Notef(os.Stdout, "Imported %s of request %s by %s (signed %s)\n",
+			result.Review.Decision, req.ID, reviewer.AgentName, timefmt.Format(bundle.Decision.SignedAt))
+		output.Notef(os.Stdout, "%s\n", i18n.T("review.id", result.Review.ID))
+		output.Notef(os.Stdout, "%s\n", i18n.T("review.counts", result.Approvals, result.Rejections))
+		if result.RequestStatusChanged {
+			output.Notef(os.Stdout, "%s\n", i18n.T("review.status_changed", i18n.Status(string(result.NewRequestStatus))))
+		}
+		return nil
+	},
+}
+
+// readReviewBundle loads a review bundle file.
+func readReviewBundle(path string) (*core.ReviewBundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading bundle: %w", err)
+	}
+	return core.ParseReviewBundle(data)
+}
+
+// writeReviewBundle writes a review bundle file readable only by its owner.
+func writeReviewBundle(path string, bundle *core.ReviewBundle) error {
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding bundle: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("writing bundle: %w", err)
+	}
+	return nil
+}
+
+// writeBundleSummary shows the verified request in a review bundle.
+func writeBundleSummary(b *core.ReviewBundle) error {
+	if GetOutput() != "text" {
+		return output.New(output.Format(GetOutput())).Write(b)
+	}
+	fmt.Printf("Request:   %s (signature verified)\n", b.RequestID)
+	fmt.Printf("Tier:      %s\n", b.RiskTier)
+	fmt.Printf("Command:   %s\n", b.Command)
+	fmt.Printf("Cwd:       %s\n", b.Cwd)
+	fmt.Printf("Project:   %s\n", b.ProjectPath)
+	fmt.Printf("Requestor: %s\n", b.RequestorAgent)
+	fmt.Printf("Reason:    %s\n", b.Justification.Reason)
+	if b.Justification.ExpectedEffect != "" {
+		fmt.Printf("Effect:    %s\n", b.Justification.ExpectedEffect)
+	}
+	if b.Justification.Goal != "" {
+		fmt.Printf("Goal:      %s\n", b.Justification.Goal)
+	}
+	if b.Justification.SafetyArgument != "" {
+		fmt.Printf("Safety:    %s\n", b.Justification.SafetyArgument)
+	}
+	fmt.Printf("Created:   %s\n", timefmt.Format(b.CreatedAt))
+	if b.ExpiresAt != nil {
+		fmt.Printf("Expires:   %s\n", timefmt.Format(*b.ExpiresAt))
+	}
+	if b.Decision != nil {
+		fmt.Printf("Decision:  %s (signed %s)\n", b.Decision.Decision, timefmt.Format(b.Decision.SignedAt))
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+	"github.com/spf13/cobra"
+)
+
+// newTestReviewBundleCmd creates a review command tree with the export,
+// sign and import subcommands.
+func newTestReviewBundleCmd(dbPath string) *cobra.Command {
+	root := &cobra.Command{
+		Use:           "slb",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	root.PersistentFlags().StringVar(&flagDB, "db", dbPath, "database path")
+	root.PersistentFlags().StringVarP(&flagOutput, "output", "o", "text", "output format")
+	root.PersistentFlags().BoolVarP(&flagJSON, "json", "j", false, "json output")
+	root.PersistentFlags().StringVarP(&flagProject, "project", "C", "", "project directory")
+	root.PersistentFlags().StringVarP(&flagConfig, "config", "c", "", "config file")
+
+	revCmd := &cobra.Command{Use: "review"}
+	export := &cobra.Command{Use: "export", Args: cobra.ExactArgs(1), RunE: reviewExportCmd.RunE}
+	export.Flags().StringVar(&flagBundleReviewer, "reviewer", "", "reviewer session")
+	export.Flags().StringVar(&flagBundleOutputFile, "output-file", "", "bundle path")
+	sign := &cobra.Command{Use: "sign", Args: cobra.ExactArgs(1), RunE: reviewSignCmd.RunE}
+	sign.Flags().BoolVar(&flagBundleApprove, "approve", false, "approve")
+	sign.Flags().BoolVar(&flagBundleReject, "reject", false, "reject")
+	sign.Flags().StringVarP(&flagBundleReason, "reason", "r", "", "reason")
+	sign.Flags().StringVarP(&flagBundleComments, "comments", "m", "", "comments")
+	sign.Flags().StringVarP(&flagBundleSessionKey, "session-key", "k", "", "session key")
+	sign.Flags().StringVar(&flagBundleOutputFile, "output-file", "", "bundle path")
+	imp := &cobra.Command{Use: "import", Args: cobra.ExactArgs(1), RunE: reviewImportCmd.RunE}
+
+	revCmd.AddCommand(export, sign, imp)
+	root.AddCommand(revCmd)
+	return root
+}
+
+func resetReviewBundleFlags() {
+	resetReviewFlags()
+	flagBundleReviewer = ""
+	flagBundleOutputFile = ""
+	flagBundleApprove = false
+	flagBundleReject = false
+	flagBundleReason = ""
+	flagBundleComments = ""
+	flagBundleSessionKey = ""
+}
+
+func TestReviewBundle_ExportSignImport(t *testing.T) {
+	h := testutil.NewHarness(t)
+	requestor, reviewer := bulkFixture(t, h)
+	req := makeBulkRequest(t, h, requestor, db.RiskTierDangerous)
+	path := filepath.Join(t.TempDir(), "bundle.json")
+
+	resetReviewBundleFlags()
+	_, err := executeCommandCapture(t, newTestReviewBundleCmd(h.DBPath), "review", "export", req.ID,
+		"--reviewer", reviewer.ID, "--output-file", path)
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	// Signing needs only the bundle and the reviewer's key.
+	resetReviewBundleFlags()
+	stdout, err := executeCommandCapture(t, newTestReviewBundleCmd(""), "review", "sign", path,
+		"-k", reviewer.SessionKey)
+	if err != nil {
+		t.Fatalf("sign (check only): %v", err)
+	}
+	if !strings.Contains(stdout, "signature verified") || !strings.Contains(stdout, req.Command.Raw) {
+		t.Errorf("expected the verified request, got:\n%s", stdout)
+	}
+
+	resetReviewBundleFlags()
+	if _, err := executeCommandCapture(t, newTestReviewBundleCmd(""), "review", "sign", path,
+		"--approve", "-m", "checked offline", "-k", reviewer.SessionKey); err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	resetReviewBundleFlags()
+	stdout, err = executeCommandCapture(t, newTestReviewBundleCmd(h.DBPath), "review", "import", path, "-j")
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	var res map[string]any
+	if err := json.Unmarshal([]byte(stdout), &res); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if res["decision"] != "approve" || res["new_request_status"] != string(db.StatusApproved) {
+		t.Errorf("unexpected result: %v", res)
+	}
+	reviews, err := h.DB.ListReviewsForRequest(req.ID)
+	if err != nil || len(reviews) != 1 || reviews[0].ReviewerSessionID != reviewer.ID || reviews[0].Comments != "checked offline" {
+		t.Fatalf("expected the reviewer's review, got %+v (%v)", reviews, err)
+	}
+
+	// The same bundle cannot be applied twice.
+	resetReviewBundleFlags()
+	if _, err := executeCommandCapture(t, newTestReviewBundleCmd(h.DBPath), "review", "import", path); err == nil {
+		t.Error("expected a second import to fail")
+	}
+}
+
+func TestReviewBundle_ImportRejectsTampering(t *testing.T) {
+	h := testutil.NewHarness(t)
+	requestor, reviewer := bulkFixture(t, h)
+	req := makeBulkRequest(t, h, requestor, db.RiskTierDangerous)
+	path := filepath.Join(t.TempDir(), "bundle.json")
+
+	resetReviewBundleFlags()
+	if _, err := executeCommandCapture(t, newTestReviewBundleCmd(h.DBPath), "review", "export", req.ID,
+		"--reviewer", reviewer.ID, "--output-file", path); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	resetReviewBundleFlags()
+	if _, err := executeCommandCapture(t, newTestReviewBundleCmd(""), "review", "sign", path,
+		"--reject", "-r", "wrong host", "-k", reviewer.SessionKey); err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := strings.Replace(string(data), `"decision": "reject"`, `"decision": "approve"`, 1)
+	if err := os.WriteFile(path, []byte(tampered), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	resetReviewBundleFlags()
+	_, err = executeCommandCapture(t, newTestReviewBundleCmd(h.DBPath), "review", "import", path)
+	if err == nil || !strings.Contains(err.Error(), "signature") {
+		t.Fatalf("expected a signature error, got %v", err)
+	}
+	if got, _ := h.DB.GetRequest(req.ID); got.Status != db.StatusPending {
+		t.Errorf("request status = %s, want pending", got.Status)
+	}
+}
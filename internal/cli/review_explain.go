@@ -0,0 +1,283 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
+	"github.com/Dicklesworthstone/slb/internal/utils"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// explainSimilarLimit caps the past similar requests shown by --explain.
+const explainSimilarLimit = 5
+
+var (
+	explainHeadingStyle = lipgloss.NewStyle().Bold(true).Foreground(colorBlue)
+	explainGoodStyle    = lipgloss.NewStyle().Foreground(colorGreen)
+	explainBadStyle     = lipgloss.NewStyle().Foreground(colorRed)
+)
+
+// tierImpact is the plain-language consequence of each tier.
+var tierImpact = map[db.RiskTier]string{
+	db.RiskTierCritical:  "This can destroy data or affect production, and usually cannot be undone.",
+	db.RiskTierDangerous: "This changes or deletes state that is hard to restore.",
+	db.RiskTierCaution:   "This has side effects worth a look but is usually recoverable.",
+}
+
+// requestExplanation is the --explain section of `slb review show`: why the
+// request got its tier, what it touches, how similar requests went, and
+// what must happen before it can run.
+type requestExplanation struct {
+	Tier string `json:"tier"`
+	// MatchedPattern is the pattern recorded when the request was created.
+	MatchedPattern string `json:"matched_pattern,omitempty"`
+	// CurrentTier is how today's patterns classify the command, set only
+	// when it differs from Tier.
+	CurrentTier string               `json:"current_tier,omitempty"`
+	Segments    []explainSegment     `json:"segments,omitempty"`
+	Notes       []string             `json:"notes,omitempty"`
+	Paths       []core.ResolvedPath  `json:"paths,omitempty"`
+	Impact      []string             `json:"impact"`
+	Similar     []*db.SimilarRequest `json:"similar,omitempty"`
+	Constraints []string             `json:"constraints"`
+}
+
+type explainSegment struct {
+	Command        string `json:"command"`
+	Tier           string `json:"tier"`
+	MatchedPattern string `json:"matched_pattern,omitempty"`
+}
+
+// explainRequest builds the explanation for a request. Lookups that fail
+// leave their part empty: the explanation is advisory and must not block
+// showing the request.
+func explainRequest(dbConn *db.DB, request *db.Request, approvals int, gitRewrite string, binary *db.RequestBinary) *requestExplanation {
+	ex := &requestExplanation{Tier: string(request.RiskTier)}
+
+	if _, err := loadCustomPatternsIntoDefaultEngine(); err != nil {
+		ex.Notes = append(ex.Notes, "custom patterns could not be loaded: "+err.Error())
+	}
+	cfg, cfgErr := config.Load(config.LoadOptions{ProjectDir: request.ProjectPath, ConfigPath: flagConfig})
+	match := core.GetDefaultEngine().ClassifyCommand(request.Command.Raw, request.Command.Cwd)
+	if cfgErr == nil && len(cfg.Agents.TierOverrides) > 0 {
+		if sess, err := dbConn.GetSession(request.RequestorSessionID); err == nil {
+			overrides, _ := core.ParseTierOverrides(cfg.Agents.TierOverrides)
+			match = core.ApplyTierOverrides(match, overrides,
+				core.OverrideSubjectFor(overrides, sess.Program, sess.Model, request.Command.Raw, request.Command.Cwd))
+		}
+	}
+	if p, err := dbConn.GetRequestPattern(request.ID); err == nil {
+		ex.MatchedPattern = p.Pattern
+	} else {
+		ex.MatchedPattern = match.MatchedPattern
+	}
+	if match.Tier != request.RiskTier {
+		ex.CurrentTier = string(match.Tier)
+		if ex.CurrentTier == "" {
+			ex.CurrentTier = "none"
+		}
+	}
+	if len(match.MatchedSegments) > 1 {
+		for _, seg := range match.MatchedSegments {
+			ex.Segments = append(ex.Segments, explainSegment{
+				Command:        seg.Segment,
+				Tier:           string(seg.Tier),
+				MatchedPattern: seg.MatchedPattern,
+			})
+		}
+	}
+	ex.Notes = append(ex.Notes, match.Explanation...)
+	ex.Paths = core.ResolvePaths(request.Command.Raw, request.Command.Cwd)
+
+	ex.Impact = explainImpact(request, ex.Paths, gitRewrite, binary, cfg, cfgErr == nil)
+	if similar, err := dbConn.ListSimilarRequests(request.ID, explainSimilarLimit); err == nil {
+		ex.Similar = similar
+	}
+	ex.Constraints = explainConstraints(request, approvals, cfg, cfgErr == nil, time.Now())
+	return ex
+}
+
+func explainImpact(request *db.Request, paths []core.ResolvedPath, gitRewrite string, binary *db.RequestBinary, cfg config.Config, haveConfig bool) []string {
+	var impact []string
+	if s, ok := tierImpact[request.RiskTier]; ok {
+		impact = append(impact, s)
+	}
+	var outside []string
+	for _, p := range paths {
+		if !p.InProject {
+			outside = append(outside, p.RealPath)
+		}
+	}
+	if len(outside) > 0 {
+		impact = append(impact, fmt.Sprintf("Reaches %d path(s) outside the project: %s.", len(outside), strings.Join(outside, ", ")))
+	} else if len(paths) > 0 {
+		impact = append(impact, "Every path it names stays inside the project.")
+	}
+	if gitRewrite != "" {
+		impact = append(impact, "Rewrites git history: "+strings.SplitN(gitRewrite, "\n", 2)[0])
+	}
+	if binary != nil {
+		if notice := binaryNotice(binary); notice != "" {
+			impact = append(impact, notice)
+		}
+	}
+	if request.Command.ContainsSensitive {
+		impact = append(impact, "The command carries sensitive values; they are redacted above.")
+	}
+	if request.DryRun != nil && request.DryRun.Output != "" {
+		impact = append(impact, fmt.Sprintf("A dry run was recorded (%d line(s) of output).", strings.Count(strings.TrimRight(request.DryRun.Output, "\n"), "\n")+1))
+	}
+	if haveConfig {
+		if cfg.General.EnableRollbackCapture {
+			impact = append(impact, "Rollback capture is on, so `slb rollback` may be able to restore affected files.")
+		} else {
+			impact = append(impact, "Rollback capture is off; there is no automatic way back.")
+		}
+	}
+	return impact
+}
+
+func explainConstraints(request *db.Request, approvals int, cfg config.Config, haveConfig bool, now time.Time) []string {
+	constraints := []string{
+		fmt.Sprintf("Needs %d approval(s); has %d.", request.MinApprovals, approvals),
+	}
+	if request.RequireDifferentModel {
+		constraints = append(constraints, fmt.Sprintf("Approvers must use a model other than %s.", request.RequestorModel))
+	}
+	if request.ExpiresAt != nil && request.Status == db.StatusPending {
+		constraints = append(constraints, fmt.Sprintf("Times out at %s if nobody decides.", timefmt.Format(*request.ExpiresAt)))
+	}
+	if vars := core.TemplateVars(request.Command.Raw); len(vars) > 0 {
+		constraints = append(constraints, fmt.Sprintf("Approving needs the unexpanded template variables acknowledged (--ack-vars): %s.", strings.Join(vars, ", ")))
+	}
+	if !haveConfig {
+		return constraints
+	}
+
+	selfApprove := false
+	for _, name := range cfg.Agents.TrustedSelfApprove {
+		if name == request.RequestorAgent {
+			selfApprove = true
+		}
+	}
+	if selfApprove {
+		constraints = append(constraints, fmt.Sprintf("%s is trusted to self-approve after %ds.", request.RequestorAgent, cfg.Agents.TrustedSelfApproveDelaySecs))
+	} else {
+		constraints = append(constraints, fmt.Sprintf("%s cannot approve its own request.", request.RequestorAgent))
+	}
+	constraints = append(constraints, "Conflicting reviews resolve by "+cfg.General.ConflictResolution+".")
+
+	ttl := cfg.General.ApprovalTTLMins
+	if request.RiskTier == db.RiskTierCritical {
+		ttl = cfg.General.ApprovalTTLCriticalMins
+	}
+	if ttl > 0 {
+		constraints = append(constraints, fmt.Sprintf("An approval stays valid for %d minute(s).", ttl))
+	}
+
+	if windows, err := buildExecutionWindows(cfg); err == nil && windows != nil {
+		if restricted, reason := windows.Restricted(request.RiskTier, now); restricted {
+			constraints = append(constraints, "Cannot run right now: "+reason+".")
+		} else if windows.Tiers[request.RiskTier] {
+			constraints = append(constraints, "Execution windows apply to this tier; it may run now.")
+		}
+	}
+	return constraints
+}
+
+// tierStyle colors a tier name as the quick reference card does.
+func tierStyle(tier string) lipgloss.Style {
+	switch db.RiskTier(tier) {
+	case db.RiskTierCritical:
+		return criticalStyle
+	case db.RiskTierDangerous:
+		return dangerousStyle
+	case db.RiskTierCaution:
+		return cautionStyle
+	}
+	return mutedStyle
+}
+
+func printRequestExplanation(ex *requestExplanation) {
+	heading := func(s string) {
+		fmt.Println()
+		fmt.Println(explainHeadingStyle.Render(s))
+	}
+	bullet := utils.Glyph("•", "-")
+
+	heading("Why it needs review")
+	tier := tierStyle(ex.Tier).Render(strings.ToUpper(ex.Tier))
+	if ex.MatchedPattern != "" {
+		fmt.Printf("  %s %s because it matched %s\n", bullet, tier, ex.MatchedPattern)
+	} else {
+		fmt.Printf("  %s %s\n", bullet, tier)
+	}
+	if ex.CurrentTier != "" {
+		fmt.Printf("  %s The current patterns would classify it as %s.\n", bullet, tierStyle(ex.CurrentTier).Render(strings.ToUpper(ex.CurrentTier)))
+	}
+	for _, seg := range ex.Segments {
+		fmt.Printf("  %s %s: %s\n", bullet, tierStyle(seg.Tier).Render(strings.ToUpper(seg.Tier)), seg.Command)
+	}
+	for _, note := range ex.Notes {
+		fmt.Printf("  %s %s\n", bullet, note)
+	}
+
+	if len(ex.Paths) > 0 {
+		heading("Paths it touches")
+		for _, p := range ex.Paths {
+			where := explainGoodStyle.Render("in project")
+			if !p.InProject {
+				where = explainBadStyle.Render("outside project")
+			}
+			if p.RealPath != p.Path {
+				fmt.Printf("  %s %s -> %s (%s)\n", bullet, p.Path, p.RealPath, where)
+			} else {
+				fmt.Printf("  %s %s (%s)\n", bullet, p.Path, where)
+			}
+		}
+	}
+
+	heading("What it could do")
+	for _, line := range ex.Impact {
+		fmt.Printf("  %s %s\n", bullet, line)
+	}
+
+	heading("How similar requests went")
+	if len(ex.Similar) == 0 {
+		fmt.Printf("  %s Nothing similar has been requested in this project.\n", bullet)
+	}
+	for _, s := range ex.Similar {
+		match := "same pattern"
+		if s.SameCommand {
+			match = "same command"
+		}
+		fmt.Printf("  %s %s %s (%s, %s): %s\n", bullet, s.CreatedAt.Format("2006-01-02"), similarOutcome(s), match, s.ID, s.Command)
+	}
+
+	heading("Before it can run")
+	for _, line := range ex.Constraints {
+		fmt.Printf("  %s %s\n", bullet, line)
+	}
+}
+
+// similarOutcome summarizes how a past request ended, colored by how well
+// it went.
+func similarOutcome(s *db.SimilarRequest) string {
+	status := strings.ToUpper(string(s.Status))
+	switch {
+	case s.CausedProblems != nil && *s.CausedProblems:
+		return explainBadStyle.Render(status + ", caused problems")
+	case s.ExitCode != nil && *s.ExitCode != 0:
+		return explainBadStyle.Render(fmt.Sprintf("%s, exit %d", status, *s.ExitCode))
+	case s.Status == db.StatusExecuted:
+		return explainGoodStyle.Render(status)
+	case s.Status == db.StatusRejected || s.Status == db.StatusExecutionFailed:
+		return dangerousStyle.Render(status)
+	}
+	return status
+}
@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
+)
+
+// reviewFormatMarkdown is the --format of `slb review show` that renders a
+// request as a standalone report.
+const reviewFormatMarkdown = "markdown"
+
+// markdownLogLines caps how much of the execution log a markdown report
+// quotes.
+const markdownLogLines = 40
+
+// requestMarkdown renders a request as a self-contained markdown report for
+// pasting into a PR, an incident doc or chat: the command, why it got its
+// tier, the justification, the reviews and, once it ran, the result.
+// Sensitive commands appear redacted, as they do everywhere else.
+func requestMarkdown(request *db.Request, reviews []*db.Review, labels map[string]string, ex *requestExplanation) string {
+	var b strings.Builder
+	command := request.Command.Raw
+	if request.Command.ContainsSensitive && request.Command.DisplayRedacted != "" {
+		command = request.Command.DisplayRedacted
+	}
+
+	fmt.Fprintf(&b, "# slb request %s\n\n", request.ID)
+	fmt.Fprintf(&b, "- Status: %s\n", strings.ToUpper(string(request.Status)))
+	fmt.Fprintf(&b, "- Risk tier: %s\n", strings.ToUpper(string(request.RiskTier)))
+	fmt.Fprintf(&b, "- Requested by: %s (%s)\n", request.RequestorAgent, request.RequestorModel)
+	fmt.Fprintf(&b, "- Project: `%s`\n", markdownInline(request.ProjectPath))
+	fmt.Fprintf(&b, "- Created: %s\n", timefmt.Format(request.CreatedAt))
+	if request.ResolvedAt != nil {
+		fmt.Fprintf(&b, "- Resolved: %s\n", timefmt.Format(*request.ResolvedAt))
+	}
+	if len(labels) > 0 {
+		fmt.Fprintf(&b, "- Labels: %s\n", db.FormatLabels(labels, ", "))
+	}
+
+	b.WriteString("\n## Command\n\n")
+	writeMarkdownBlock(&b, "sh", command)
+	fmt.Fprintf(&b, "\nRuns in `%s`. Hash `%s`.\n", markdownInline(request.Command.Cwd), request.Command.Hash)
+
+	if ex != nil {
+		b.WriteString("\n## Classification\n\n")
+		if ex.MatchedPattern != "" {
+			fmt.Fprintf(&b, "- %s because it matched `%s`\n", strings.ToUpper(ex.Tier), markdownInline(ex.MatchedPattern))
+		} else {
+			fmt.Fprintf(&b, "- %s\n", strings.ToUpper(ex.Tier))
+		}
+		if ex.CurrentTier != "" {
+			fmt.Fprintf(&b, "- The current patterns would classify it as %s.\n", strings.ToUpper(ex.CurrentTier))
+		}
+		for _, seg := range ex.Segments {
+			fmt.Fprintf(&b, "- %s: `%s`\n", strings.ToUpper(seg.Tier), markdownInline(seg.Command))
+		}
+		for _, note := range ex.Notes {
+			fmt.Fprintf(&b, "- %s\n", oneLineText(note))
+		}
+		for _, line := range ex.Impact {
+			fmt.Fprintf(&b, "- %s\n", oneLineText(line))
+		}
+	}
+
+	b.WriteString("\n## Justification\n\n")
+	j := request.Justification
+	fmt.Fprintf(&b, "- Reason: %s\n", oneLineText(j.Reason))
+	if j.ExpectedEffect != "" {
+		fmt.Fprintf(&b, "- Expected effect: %s\n", oneLineText(j.ExpectedEffect))
+	}
+	if j.Goal != "" {
+		fmt.Fprintf(&b, "- Goal: %s\n", oneLineText(j.Goal))
+	}
+	if j.SafetyArgument != "" {
+		fmt.Fprintf(&b, "- Safety argument: %s\n", oneLineText(j.SafetyArgument))
+	}
+
+	var approvals int
+	for _, rev := range reviews {
+		if rev.Decision == db.DecisionApprove {
+			approvals++
+		}
+	}
+	b.WriteString("\n## Reviews\n\n")
+	fmt.Fprintf(&b, "%d of %d required approvals.\n", approvals, request.MinApprovals)
+	if len(reviews) > 0 {
+		b.WriteString("\n| Decision | Reviewer | Model | When | Comment |\n|---|---|---|---|---|\n")
+		for _, rev := range reviews {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n",
+				strings.ToUpper(string(rev.Decision)), markdownTableCell(rev.ReviewerAgent), markdownTableCell(rev.ReviewerModel),
+				timefmt.Format(rev.CreatedAt), markdownTableCell(rev.Comments))
+		}
+	}
+
+	if e := request.Execution; e != nil && e.ExecutedAt != nil {
+		b.WriteString("\n## Execution\n\n")
+		fmt.Fprintf(&b, "- Executed: %s by %s\n", timefmt.Format(*e.ExecutedAt), e.ExecutedByAgent)
+		if e.ExitCode != nil {
+			fmt.Fprintf(&b, "- Exit code: %d\n", *e.ExitCode)
+		}
+		if e.DurationMs != nil {
+			fmt.Fprintf(&b, "- Duration: %dms\n", *e.DurationMs)
+		}
+		if len(e.SecretsFound) > 0 {
+			fmt.Fprintf(&b, "- Secrets redacted from output: %s\n", strings.Join(e.SecretsFound, ", "))
+		}
+		if tail := logTail(e.LogPath, markdownLogLines); tail != "" {
+			fmt.Fprintf(&b, "\nLast lines of the log:\n\n")
+			writeMarkdownBlock(&b, "", tail)
+		}
+	}
+	return b.String()
+}
+
+// writeMarkdownBlock writes text as a fenced code block, with a fence
+// longer than any run of backticks inside it.
+func writeMarkdownBlock(b *strings.Builder, lang, text string) {
+	fence := "```"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	fmt.Fprintf(b, "%s%s\n%s\n%s\n", fence, lang, strings.TrimRight(text, "\n"), fence)
+}
+
+// logTail returns the last n lines of an execution log, or "" when it
+// cannot be read.
+func logTail(path string, n int) string {
+	if path == "" {
+		return ""
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func oneLineText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// markdownInline keeps text inside a single-backtick code span.
+func markdownInline(s string) string {
+	return strings.ReplaceAll(oneLineText(s), "`", "'")
+}
+
+func markdownTableCell(s string) string {
+	return strings.ReplaceAll(oneLineText(s), "|", `\|`)
+}
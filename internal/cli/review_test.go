@@ -2,9 +2,13 @@ package cli
 
 import (
 	"encoding/json"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/Dicklesworthstone/slb/internal/core"
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/Dicklesworthstone/slb/internal/testutil"
 	"github.com/spf13/cobra"
@@ -23,6 +27,7 @@ func newTestReviewCmd(dbPath string) *cobra.Command {
 	root.PersistentFlags().BoolVarP(&flagJSON, "json", "j", false, "json output")
 	root.PersistentFlags().StringVarP(&flagProject, "project", "C", "", "project directory")
 	root.PersistentFlags().StringVarP(&flagConfig, "config", "c", "", "config file")
+	root.PersistentFlags().StringVarP(&flagSessionID, "session-id", "s", "", "session ID")
 
 	// Create fresh review commands
 	revCmd := &cobra.Command{
@@ -40,6 +45,7 @@ func newTestReviewCmd(dbPath string) *cobra.Command {
 		RunE:  reviewListCmd.RunE,
 	}
 	listCmd.Flags().BoolVarP(&flagReviewAll, "all", "a", false, "show requests from all projects")
+	listCmd.Flags().StringArrayVar(&flagReviewLabels, "label", nil, "filter by label")
 
 	showCmd := &cobra.Command{
 		Use:   "show <request-id>",
@@ -47,6 +53,12 @@ func newTestReviewCmd(dbPath string) *cobra.Command {
 		Args:  cobra.ExactArgs(1),
 		RunE:  reviewShowCmd.RunE,
 	}
+	showCmd.Flags().BoolVar(&flagReviewExplain, "explain", false, "explain the request")
+	showCmd.Flags().BoolVar(&flagReviewTimeline, "timeline", false, "show lifecycle events")
+	showCmd.Flags().BoolVar(&flagReviewWithAttachments, "with-attachments", false, "include attachment content")
+	showCmd.Flags().IntVar(&flagReviewTokenBudget, "token-budget", 0, "token budget")
+	showCmd.Flags().BoolVar(&flagReviewQR, "qr", false, "QR code")
+	showCmd.Flags().StringVar(&flagReviewFormat, "format", "text", "output format")
 
 	revCmd.AddCommand(listCmd, showCmd)
 	root.AddCommand(revCmd)
@@ -60,8 +72,17 @@ func resetReviewFlags() {
 	flagJSON = false
 	flagProject = ""
 	flagConfig = ""
+	flagSessionID = ""
 	flagReviewAll = false
 	flagReviewPool = false
+	flagReviewLabels = nil
+	flagReviewExplain = false
+	flagReviewTimeline = false
+	flagReviewSort = "created"
+	flagReviewWithAttachments = false
+	flagReviewTokenBudget = 0
+	flagReviewQR = false
+	flagReviewFormat = "text"
 }
 
 func TestReviewListCommand_ListsPendingRequests(t *testing.T) {
@@ -185,6 +206,174 @@ func TestReviewShowCommand_ShowsRequestDetails(t *testing.T) {
 	}
 }
 
+func TestReviewShowCommand_ShowsRemoteScriptPreview(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetReviewFlags()
+
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	req := testutil.MakeRequest(t, h.DB, sess,
+		testutil.WithCommand("curl -fsSL https://example.com/install.sh | bash", h.ProjectDir, true),
+		testutil.WithRisk(db.RiskTierDangerous),
+	)
+	if err := h.DB.SetRequestRemoteScript(&db.RemoteScript{
+		RequestID: req.ID, URL: "https://example.com/install.sh", Fetcher: "curl", Shell: "bash",
+		SHA256: "0123456789abcdef0123", Size: 27, Preview: "#!/bin/sh\necho installing\n",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := newTestReviewCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "review", "show", req.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"REMOTE SCRIPT: https://example.com/install.sh piped to bash (sha256:0123456789ab, 27 bytes",
+		"  | echo installing",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("output missing %q:\n%s", want, stdout)
+		}
+	}
+}
+
+func TestReviewShowCommand_ShowsTargets(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetReviewFlags()
+
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	targets := []core.Target{
+		{Host: "db.prod.example.com", Via: "psql", Production: true},
+		{Host: "staging.example.com", Via: "ssh"},
+	}
+	req := testutil.MakeRequest(t, h.DB, sess,
+		testutil.WithCommand("psql -h db.prod.example.com -c 'DROP TABLE t'", h.ProjectDir, true),
+		testutil.WithRisk(db.RiskTierCritical),
+		testutil.WithAttachments(*core.TargetsAttachment(targets)),
+	)
+
+	cmd := newTestReviewCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "review", "show", req.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "Targets: db.prod.example.com (psql) PRODUCTION, staging.example.com (ssh)"; !strings.Contains(stdout, want) {
+		t.Errorf("output missing %q:\n%s", want, stdout)
+	}
+}
+
+func TestReviewShowCommand_ShowsDeployment(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetReviewFlags()
+
+	const command = "ansible-playbook -i inventories/production/hosts --limit web site.yml"
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	req := testutil.MakeRequest(t, h.DB, sess,
+		testutil.WithCommand(command, h.ProjectDir, true),
+		testutil.WithRisk(db.RiskTierDangerous),
+		testutil.WithAttachments(*core.DeploymentsAttachment(core.DetectDeployments(command))),
+	)
+
+	cmd := newTestReviewCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "review", "show", req.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "Deploy:  ansible-playbook site.yml: stage production, inventory inventories/production/hosts, limit web"; !strings.Contains(stdout, want) {
+		t.Errorf("output missing %q:\n%s", want, stdout)
+	}
+}
+
+func TestReviewShowCommand_TokenBudget(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetReviewFlags()
+	h.WriteFile(".slb/config.toml", []byte("[reviewers]\ninstructions = \"Reject anything touching /etc.\"\ntoken_budget = 400\n"), 0o600)
+
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	diff := strings.Repeat("+ added line\n", 500)
+	req := testutil.MakeRequest(t, h.DB, sess,
+		testutil.WithCommand("rm -rf ./build", h.ProjectDir, true),
+		testutil.WithRisk(db.RiskTierDangerous),
+		testutil.WithAttachments(db.Attachment{Type: db.AttachmentTypeGitDiff, Content: diff}),
+	)
+
+	stdout, err := executeCommandCapture(t, newTestReviewCmd(h.DBPath), "review", "show", req.ID, "--with-attachments")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"Reviewer Instructions:\n  Reject anything touching /etc.",
+		"[1] git_diff",
+		"[truncated to fit the review token budget]",
+		"budget 400",
+		"truncated: attachment 1 (git_diff) cut from ~1625",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("output missing %q:\n%s", want, stdout)
+		}
+	}
+
+	// A flag budget overrides the configured one; -1 disables it.
+	resetReviewFlags()
+	stdout, err = executeCommandCapture(t, newTestReviewCmd(h.DBPath), "review", "show", req.ID, "--with-attachments", "--token-budget", "-1", "-j")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var detail struct {
+		Instructions string `json:"reviewer_instructions"`
+		Attachments  []struct {
+			Content string `json:"content"`
+		} `json:"attachments"`
+		Effort core.ReviewEffort `json:"review_effort"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &detail); err != nil {
+		t.Fatalf("decoding output: %v\n%s", err, stdout)
+	}
+	if len(detail.Attachments) != 1 || detail.Attachments[0].Content != diff {
+		t.Errorf("unbudgeted attachment was cut")
+	}
+	if detail.Effort.Budget != 0 || detail.Effort.ContextTokens != core.EstimateTokens(diff) || detail.Instructions == "" {
+		t.Errorf("unexpected effort: %+v", detail.Effort)
+	}
+}
+
+func TestReviewShowCommand_QR(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetReviewFlags()
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	req := testutil.MakeRequest(t, h.DB, sess, testutil.WithCommand("rm -rf ./build", h.ProjectDir, true))
+
+	stdout, err := executeCommandCapture(t, newTestReviewCmd(h.DBPath), "review", "show", req.ID, "--qr", "-j")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var detail struct {
+		QR string `json:"qr"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &detail); err != nil {
+		t.Fatalf("decoding output: %v\n%s", err, stdout)
+	}
+	if !strings.HasPrefix(detail.QR, "SLB "+req.ID+"\n") || !strings.Contains(detail.QR, "Cmd: rm -rf ./build\n") {
+		t.Errorf("unexpected summary: %q", detail.QR)
+	}
+
+	// A configured review page replaces the summary.
+	h.WriteFile(".slb/config.toml", []byte("[reviewers]\nqr_url = \"https://slb.example.com/r/{id}\"\n"), 0o600)
+	resetReviewFlags()
+	stdout, err = executeCommandCapture(t, newTestReviewCmd(h.DBPath), "review", "show", req.ID, "--qr")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "Scan to review on a phone:\n████") {
+		t.Errorf("expected a QR code:\n%s", stdout)
+	}
+	resetReviewFlags()
+	stdout, _ = executeCommandCapture(t, newTestReviewCmd(h.DBPath), "review", "show", req.ID, "--qr", "-j")
+	if !strings.Contains(stdout, `"qr": "https://slb.example.com/r/`+req.ID+`"`) {
+		t.Errorf("expected the review page URL:\n%s", stdout)
+	}
+}
+
 func TestReviewShowCommand_RequestNotFound(t *testing.T) {
 	h := testutil.NewHarness(t)
 	resetReviewFlags()
@@ -260,6 +449,92 @@ func TestReviewShowCommand_IncludesReviews(t *testing.T) {
 	}
 }
 
+func TestReviewShowCommand_IncludesAdvisories(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetReviewFlags()
+
+	sess := testutil.MakeSession(t, h.DB,
+		testutil.WithProject(h.ProjectDir),
+		testutil.WithAgent("TestAgent"),
+	)
+	req := testutil.MakeRequest(t, h.DB, sess,
+		testutil.WithCommand("rm -rf ./build", h.ProjectDir, true),
+		testutil.WithRisk(db.RiskTierDangerous),
+	)
+	if err := h.DB.CreateAnnotation(&db.RequestAnnotation{
+		RequestID:      req.ID,
+		Source:         db.AnnotationSourceLLM,
+		Author:         "second-opinion",
+		RiskSummary:    "Removes build artifacts only",
+		Recommendation: db.RecommendationApprove,
+	}); err != nil {
+		t.Fatalf("CreateAnnotation: %v", err)
+	}
+
+	cmd := newTestReviewCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "review", "show", req.ID, "-j")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+
+	advisories, ok := result["advisories"].([]any)
+	if !ok || len(advisories) != 1 {
+		t.Fatalf("expected 1 advisory, got %v", result["advisories"])
+	}
+	adv := advisories[0].(map[string]any)
+	if adv["recommendation"] != db.RecommendationApprove {
+		t.Errorf("unexpected advisory: %v", adv)
+	}
+	// Advisories never count as approvals.
+	if result["current_approvals"] != float64(0) {
+		t.Errorf("expected current_approvals=0, got %v", result["current_approvals"])
+	}
+}
+
+func TestReviewShowCommand_IncludesTranscript(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetReviewFlags()
+
+	transcript := filepath.Join(t.TempDir(), "session.txt")
+	if err := os.WriteFile(transcript, []byte("user: clear the build cache\nassistant: running rm -rf ./build\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	att, err := core.LoadTranscriptSnippet(transcript, nil)
+	if err != nil {
+		t.Fatalf("LoadTranscriptSnippet: %v", err)
+	}
+
+	sess := testutil.MakeSession(t, h.DB,
+		testutil.WithProject(h.ProjectDir),
+		testutil.WithAgent("TestAgent"),
+	)
+	req := testutil.MakeRequest(t, h.DB, sess,
+		testutil.WithCommand("rm -rf ./build", h.ProjectDir, true),
+		testutil.WithRisk(db.RiskTierDangerous),
+		testutil.WithAttachments(*att),
+	)
+
+	cmd := newTestReviewCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "review", "show", req.ID, "-j")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	got, _ := result["transcript"].(string)
+	if !strings.Contains(got, "user: clear the build cache") {
+		t.Errorf("expected decoded transcript, got %q", got)
+	}
+}
+
 func TestReviewCommand_NoArgs_ShowsList(t *testing.T) {
 	h := testutil.NewHarness(t)
 	resetReviewFlags()
@@ -744,3 +1019,309 @@ func TestReviewShowCommand_TextOutputWithSafetyArgument(t *testing.T) {
 		t.Error("expected text output to contain 'Safety Argument:'")
 	}
 }
+
+func TestReviewShowCommand_IncludesGitRewrite(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetReviewFlags()
+
+	rewrite := &core.GitRewrite{
+		Kind: core.GitRewritePushMirror, Command: "git push --mirror origin",
+		Remote: "origin", DeletedBranches: []string{"release"},
+		Branch: "main", Upstream: "origin/main",
+	}
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	req := testutil.MakeRequest(t, h.DB, sess,
+		testutil.WithCommand("git push --mirror origin", h.ProjectDir, true),
+		testutil.WithRisk(db.RiskTierCritical),
+		testutil.WithAttachments(*rewrite.Attachment()),
+	)
+
+	cmd := newTestReviewCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "review", "show", req.ID, "-j")
+	testutil.RequireNoError(t, err, "review show")
+
+	var result struct {
+		GitRewrite string `json:"git_rewrite"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if !strings.Contains(result.GitRewrite, "Deletes 1 remote branch(es) with no local copy: release") {
+		t.Fatalf("expected the mirror summary, got %q", result.GitRewrite)
+	}
+}
+
+func TestReviewShowCommand_Explain(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetReviewFlags()
+
+	if err := os.Mkdir(filepath.Join(h.ProjectDir, "build"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	sess := testutil.MakeSession(t, h.DB,
+		testutil.WithProject(h.ProjectDir),
+		testutil.WithAgent("TestAgent"),
+		testutil.WithModel("test-model"),
+	)
+	past := testutil.MakeRequest(t, h.DB, sess,
+		testutil.WithCommand("rm -rf ./build", h.ProjectDir, true),
+		testutil.WithRisk(db.RiskTierDangerous),
+	)
+	testutil.RequireNoError(t, h.DB.UpdateRequestStatus(past.ID, db.StatusRejected), "reject past request")
+	req := testutil.MakeRequest(t, h.DB, sess,
+		testutil.WithCommand("rm -rf ./build", h.ProjectDir, true),
+		testutil.WithRisk(db.RiskTierDangerous),
+	)
+	testutil.RequireNoError(t, h.DB.SetRequestPattern(&db.RequestPattern{
+		RequestID: req.ID, Tier: db.RiskTierDangerous, Pattern: `^rm\s+-rf`,
+	}), "record pattern")
+
+	cmd := newTestReviewCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "review", "show", req.ID, "--explain", "-C", h.ProjectDir, "-j")
+	testutil.RequireNoError(t, err, "review show --explain")
+
+	var result struct {
+		Explain *requestExplanation `json:"explain"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	ex := result.Explain
+	if ex == nil {
+		t.Fatalf("expected an explain section, got %s", stdout)
+	}
+	testutil.RequireEqual(t, `^rm\s+-rf`, ex.MatchedPattern, "recorded pattern")
+	if ex.CurrentTier != "" {
+		t.Errorf("expected the current patterns to agree, got %q", ex.CurrentTier)
+	}
+	if len(ex.Paths) != 1 || ex.Paths[0].Path != "./build" || !ex.Paths[0].InProject {
+		t.Errorf("unexpected paths: %+v", ex.Paths)
+	}
+	if len(ex.Similar) != 1 || ex.Similar[0].ID != past.ID || ex.Similar[0].Status != db.StatusRejected || !ex.Similar[0].SameCommand {
+		t.Errorf("unexpected similar requests: %+v", ex.Similar)
+	}
+	if len(ex.Impact) == 0 || len(ex.Constraints) == 0 || !strings.Contains(ex.Constraints[0], "Needs 1 approval(s); has 0") {
+		t.Errorf("unexpected impact/constraints: %v / %v", ex.Impact, ex.Constraints)
+	}
+
+	resetReviewFlags()
+	cmd = newTestReviewCmd(h.DBPath)
+	stdout, err = executeCommandCapture(t, cmd, "review", "show", req.ID, "--explain", "-C", h.ProjectDir)
+	testutil.RequireNoError(t, err, "review show --explain (text)")
+	for _, want := range []string{"Why it needs review", "Paths it touches", "What it could do", "How similar requests went", "REJECTED", "Before it can run", "TestAgent cannot approve its own request"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in text output:\n%s", want, stdout)
+		}
+	}
+
+	resetReviewFlags()
+	cmd = newTestReviewCmd(h.DBPath)
+	stdout, err = executeCommandCapture(t, cmd, "review", "show", req.ID, "-j")
+	testutil.RequireNoError(t, err, "review show")
+	if strings.Contains(stdout, `"explain"`) {
+		t.Error("expected no explain section without --explain")
+	}
+}
+
+func TestReviewShowCommand_Timeline(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetReviewFlags()
+
+	requestorSess := testutil.MakeSession(t, h.DB,
+		testutil.WithProject(h.ProjectDir),
+		testutil.WithAgent("Requestor"),
+		testutil.WithModel("model-a"),
+	)
+	reviewerSess := testutil.MakeSession(t, h.DB,
+		testutil.WithProject(h.ProjectDir),
+		testutil.WithAgent("Reviewer"),
+		testutil.WithModel("model-b"),
+	)
+	req := testutil.MakeRequest(t, h.DB, requestorSess,
+		testutil.WithCommand("rm -rf ./build", h.ProjectDir, true),
+	)
+	testutil.RequireNoError(t, h.DB.CreateReview(&db.Review{
+		RequestID:         req.ID,
+		ReviewerSessionID: reviewerSess.ID,
+		ReviewerAgent:     reviewerSess.AgentName,
+		ReviewerModel:     reviewerSess.Model,
+		Decision:          db.DecisionApprove,
+		Comments:          "LGTM",
+	}), "create review")
+
+	cmd := newTestReviewCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "review", "show", req.ID, "--timeline", "-j")
+	testutil.RequireNoError(t, err, "review show --timeline")
+
+	var result struct {
+		Timeline []*db.RequestEvent `json:"timeline"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	// Opening the request leaves the viewer's receipt at the end.
+	if len(result.Timeline) != 3 || result.Timeline[2].Type != db.RequestEventViewed {
+		t.Fatalf("expected created, reviewed and viewed events, got %+v", result.Timeline)
+	}
+	if result.Timeline[0].Type != db.RequestEventCreated || result.Timeline[0].Actor != "Requestor" {
+		t.Errorf("unexpected first event: %+v", result.Timeline[0])
+	}
+	if result.Timeline[1].Type != db.RequestEventReviewed || result.Timeline[1].Actor != "Reviewer" || !strings.Contains(result.Timeline[1].Details, "LGTM") {
+		t.Errorf("unexpected second event: %+v", result.Timeline[1])
+	}
+
+	resetReviewFlags()
+	cmd = newTestReviewCmd(h.DBPath)
+	stdout, err = executeCommandCapture(t, cmd, "review", "show", req.ID, "--timeline")
+	testutil.RequireNoError(t, err, "review show --timeline (text)")
+	for _, want := range []string{"Timeline:", "Requestor", "Reviewer", "LGTM"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in text output:\n%s", want, stdout)
+		}
+	}
+
+	resetReviewFlags()
+	cmd = newTestReviewCmd(h.DBPath)
+	stdout, err = executeCommandCapture(t, cmd, "review", "show", req.ID, "-j")
+	testutil.RequireNoError(t, err, "review show")
+	if strings.Contains(stdout, `"timeline"`) {
+		t.Error("expected no timeline without --timeline")
+	}
+}
+
+func TestReviewShowCommand_Markdown(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetReviewFlags()
+
+	requestorSess := testutil.MakeSession(t, h.DB,
+		testutil.WithProject(h.ProjectDir),
+		testutil.WithAgent("Requestor"),
+		testutil.WithModel("model-a"),
+	)
+	reviewerSess := testutil.MakeSession(t, h.DB,
+		testutil.WithProject(h.ProjectDir),
+		testutil.WithAgent("Reviewer"),
+		testutil.WithModel("model-b"),
+	)
+	req := testutil.MakeRequest(t, h.DB, requestorSess,
+		testutil.WithCommand("rm -rf ./build", h.ProjectDir, true),
+		testutil.WithRisk(db.RiskTierDangerous),
+		testutil.WithJustification("clean stale build output", "build dir removed", "", ""),
+		testutil.WithLabels(map[string]string{"task": "OPS-123"}),
+	)
+	testutil.RequireNoError(t, h.DB.CreateReview(&db.Review{
+		RequestID:         req.ID,
+		ReviewerSessionID: reviewerSess.ID,
+		ReviewerAgent:     reviewerSess.AgentName,
+		ReviewerModel:     reviewerSess.Model,
+		Decision:          db.DecisionApprove,
+		Comments:          "only | the build dir",
+	}), "create review")
+
+	logPath := filepath.Join(t.TempDir(), "exec.log")
+	testutil.RequireNoError(t, os.WriteFile(logPath, []byte("Command: rm -rf ./build\nremoved ```build```\n"), 0600), "write log")
+	now := time.Now()
+	exitCode := 0
+	testutil.RequireNoError(t, h.DB.UpdateRequestExecution(req.ID, &db.Execution{
+		ExecutedAt:      &now,
+		ExecutedByAgent: "Requestor",
+		LogPath:         logPath,
+		ExitCode:        &exitCode,
+	}), "record execution")
+
+	cmd := newTestReviewCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "review", "show", req.ID, "--format", "markdown")
+	testutil.RequireNoError(t, err, "review show --format markdown")
+	for _, want := range []string{
+		"# slb request " + req.ID,
+		"- Risk tier: DANGEROUS",
+		"- Labels: task=OPS-123",
+		"```sh\nrm -rf ./build\n```",
+		"## Classification",
+		"- Reason: clean stale build output",
+		"1 of 1 required approvals.",
+		`| APPROVE | Reviewer | model-b |`,
+		`only \| the build dir`,
+		"- Exit code: 0",
+		"````\nCommand: rm -rf ./build\nremoved ```build```\n````",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in markdown:\n%s", want, stdout)
+		}
+	}
+
+	resetReviewFlags()
+	cmd = newTestReviewCmd(h.DBPath)
+	if _, err := executeCommandCapture(t, cmd, "review", "show", req.ID, "--format", "markdown", "-j"); err == nil {
+		t.Error("expected --format markdown with --json to fail")
+	}
+	resetReviewFlags()
+	cmd = newTestReviewCmd(h.DBPath)
+	if _, err := executeCommandCapture(t, cmd, "review", "show", req.ID, "--format", "html"); err == nil {
+		t.Error("expected an unknown --format to fail")
+	}
+}
+
+func TestReviewShowCommand_RecordsViewedReceipt(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetReviewFlags()
+
+	requestorSess := testutil.MakeSession(t, h.DB,
+		testutil.WithProject(h.ProjectDir),
+		testutil.WithAgent("Requestor"),
+	)
+	reviewerSess := testutil.MakeSession(t, h.DB,
+		testutil.WithProject(h.ProjectDir),
+		testutil.WithAgent("Reviewer"),
+	)
+	req := testutil.MakeRequest(t, h.DB, requestorSess,
+		testutil.WithCommand("rm -rf ./build", h.ProjectDir, true),
+	)
+
+	// The requestor looking at its own request is not a receipt.
+	for _, sess := range []*db.Session{requestorSess, reviewerSess, reviewerSess} {
+		resetReviewFlags()
+		cmd := newTestReviewCmd(h.DBPath)
+		_, err := executeCommandCapture(t, cmd, "review", "show", req.ID, "-s", sess.ID, "-j")
+		testutil.RequireNoError(t, err, "review show")
+	}
+
+	events, err := h.DB.ListRequestEvents(req.ID)
+	testutil.RequireNoError(t, err, "list events")
+	viewed := 0
+	for _, e := range events {
+		if e.Type == db.RequestEventViewed {
+			viewed++
+			testutil.RequireEqual(t, "Reviewer", e.Actor, "viewer")
+		}
+	}
+	testutil.RequireEqual(t, 1, viewed, "viewed receipts")
+
+	resetReviewFlags()
+	cmd := newTestReviewCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "review", "list", "-C", h.ProjectDir, "-j")
+	testutil.RequireNoError(t, err, "review list")
+	var list []map[string]any
+	if err := json.Unmarshal([]byte(stdout), &list); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if len(list) != 1 || list[0]["seen"] != "seen by 1 reviewer, no decision" {
+		t.Fatalf("unexpected list: %v", list)
+	}
+	if seenBy, _ := list[0]["seen_by"].([]any); len(seenBy) != 1 || seenBy[0] != "Reviewer" {
+		t.Errorf("unexpected seen_by: %v", list[0]["seen_by"])
+	}
+}
+
+func TestSeenSummary(t *testing.T) {
+	cases := map[string][]string{
+		"":                                 nil,
+		"seen by 1 reviewer, no decision":  {"a"},
+		"seen by 2 reviewers, no decision": {"a", "b"},
+	}
+	for want, viewers := range cases {
+		if got := seenSummary(viewers); got != want {
+			t.Errorf("seenSummary(%v) = %q, want %q", viewers, got, want)
+		}
+	}
+}
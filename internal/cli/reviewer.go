@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/i18n"
+	"github.com/spf13/cobra"
+)
+
+// quorumState summarizes a request's approval progress after a review.
+type quorumState struct {
+	Status       string `json:"status"`
+	MinApprovals int    `json:"min_approvals"`
+	Approvals    int    `json:"approvals"`
+	Rejections   int    `json:"rejections"`
+	Remaining    int    `json:"remaining"`
+	Satisfied    bool   `json:"satisfied"`
+}
+
+// buildQuorumState reads the request after a review and reports where it
+// stands relative to its approval quorum.
+func buildQuorumState(dbConn *db.DB, requestID string, result *core.ReviewResult) (quorumState, error) {
+	req, err := dbConn.GetRequest(requestID)
+	if err != nil {
+		return quorumState{}, fmt.Errorf("getting request: %w", err)
+	}
+	q := quorumState{
+		Status:       string(req.Status),
+		MinApprovals: req.MinApprovals,
+		Approvals:    result.Approvals,
+		Rejections:   result.Rejections,
+		Satisfied:    req.Status == db.StatusApproved,
+	}
+	if remaining := req.MinApprovals - result.Approvals; remaining > 0 && req.Status == db.StatusPending {
+		q.Remaining = remaining
+	}
+	return q, nil
+}
+
+// resolveReviewerSessionID determines the reviewing session: an explicit
+// --session-id, then SLB_SESSION_ID, then the actor's active session in the
+// project. Explicit IDs are checked (existence, active, key) by the review
+// service when the review is submitted.
+func resolveReviewerSessionID(dbConn *db.DB, project, flagValue string) (string, error) {
+	if flagValue != "" {
+		return flagValue, nil
+	}
+	if id := os.Getenv("SLB_SESSION_ID"); id != "" {
+		return id, nil
+	}
+
+	actor := GetActor()
+	sess, err := dbConn.GetActiveSession(actor, project)
+	if err != nil {
+		if errors.Is(err, db.ErrSessionNotFound) {
+			return "", errors.New(i18n.T("error.no_reviewer_session", actor))
+		}
+		return "", fmt.Errorf("resolving reviewer session: %w", err)
+	}
+	return sess.ID, nil
+}
+
+// resolveSessionKey returns the --session-key value, falling back to
+// SLB_SESSION_KEY and then to the key stored for sessionID by
+// `slb session start`.
+func resolveSessionKey(flagValue, sessionID string) (string, error) {
+	if flagValue != "" {
+		return flagValue, nil
+	}
+	if key := os.Getenv("SLB_SESSION_KEY"); key != "" {
+		return key, nil
+	}
+	if key := lookupSessionKey(sessionID); key != "" {
+		return key, nil
+	}
+	return "", errors.New(i18n.T("error.session_key_required"))
+}
+
+// reviewableRequests returns the pending requests in project that the
+// reviewer neither submitted nor already reviewed, newest first.
+func reviewableRequests(dbConn *db.DB, project, sessionID string) ([]*db.Request, error) {
+	pending, err := dbConn.ListPendingRequests(project)
+	if err != nil {
+		return nil, fmt.Errorf("listing pending requests: %w", err)
+	}
+
+	var out []*db.Request
+	for _, r := range pending {
+		if r.RequestorSessionID == sessionID {
+			continue
+		}
+		reviewed, err := dbConn.HasReviewerAlreadyReviewed(r.ID, sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("checking reviews: %w", err)
+		}
+		if !reviewed {
+			out = append(out, r)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	return out, nil
+}
+
+// latestReviewableRequest returns the newest pending request in project that
+// the reviewer neither submitted nor already reviewed.
+func latestReviewableRequest(dbConn *db.DB, project, sessionID string) (string, error) {
+	candidates, err := reviewableRequests(dbConn, project, sessionID)
+	if err != nil {
+		return "", err
+	}
+	if len(candidates) == 0 {
+		return "", errors.New(i18n.T("error.no_reviewable_request"))
+	}
+	return candidates[0].ID, nil
+}
+
+// requestIDOrLatest accepts exactly one request ID, or none when --latest
+// is set.
+func requestIDOrLatest(latest *bool) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if *latest {
+			if len(args) > 0 {
+				return fmt.Errorf("cannot combine a request ID with --latest")
+			}
+			return nil
+		}
+		if len(args) != 1 {
+			return fmt.Errorf("accepts 1 arg(s), received %d (or use --latest)", len(args))
+		}
+		return nil
+	}
+}
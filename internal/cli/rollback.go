@@ -3,11 +3,13 @@ package cli
 import (
 	"context"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/Dicklesworthstone/slb/internal/core"
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
 	"github.com/spf13/cobra"
 )
 
@@ -68,7 +70,7 @@ Examples:
 		if request.Rollback.RolledBackAt != nil {
 			if !flagRollbackForce {
 				return fmt.Errorf("request was already rolled back at %s (use --force to rollback again)",
-					request.Rollback.RolledBackAt.Format(time.RFC3339))
+					timefmt.Format(*request.Rollback.RolledBackAt))
 			}
 		}
 
@@ -99,21 +101,21 @@ Examples:
 		resp := rollbackResult{
 			RequestID:    requestID,
 			RollbackPath: request.Rollback.Path,
-			RolledBackAt: now.Format(time.RFC3339),
+			RolledBackAt: timefmt.Format(now),
 			Status:       "rolled_back",
 			Message:      "Rollback completed using captured state.",
 		}
 
 		out := output.New(output.Format(GetOutput()))
-		if GetOutput() == "json" {
+		if isJSONOutput() {
 			return out.Write(resp)
 		}
 
 		// Human-readable output
-		fmt.Printf("Rollback for request %s\n", requestID)
-		fmt.Printf("Rollback data: %s\n", request.Rollback.Path)
-		fmt.Println()
-		fmt.Println("Rollback completed.")
+		output.Notef(os.Stdout, "Rollback for request %s\n", requestID)
+		output.Notef(os.Stdout, "Rollback data: %s\n", request.Rollback.Path)
+		output.Notef(os.Stdout, "\n")
+		output.Notef(os.Stdout, "Rollback completed.\n")
 
 		return nil
 	},
@@ -7,8 +7,14 @@ import (
 	"path/filepath"
 	"runtime"
 
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/i18n"
+	"github.com/Dicklesworthstone/slb/internal/numfmt"
 	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
+	"github.com/Dicklesworthstone/slb/internal/utils"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // Version information set by goreleaser
@@ -30,6 +36,12 @@ var (
 	flagActor     string
 	flagSessionID string
 	flagProject   string
+	flagNoColor   bool
+	flagTimes     string
+	flagRaw       bool
+	flagPorcelain bool
+	flagQuiet     bool
+	flagProfile   string
 )
 
 var rootCmd = &cobra.Command{
@@ -48,13 +60,22 @@ Commands are classified by risk level:
 	SilenceUsage:  true,
 	SilenceErrors: true,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-		if flagProject == "" {
-			return nil
+		if err := applyEnvFlags(cmd.Flags()); err != nil {
+			return err
+		}
+		if flagNoColor {
+			utils.SetPlainOutput(true)
 		}
-		if err := os.Chdir(flagProject); err != nil {
-			return fmt.Errorf("changing directory to %s: %w", flagProject, err)
+		if flagProject != "" {
+			if err := os.Chdir(flagProject); err != nil {
+				return fmt.Errorf("changing directory to %s: %w", flagProject, err)
+			}
 		}
-		return nil
+		if err := applyProfile(); err != nil {
+			return err
+		}
+		applyOutputMode()
+		return applyDisplayConfig()
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		// When no subcommand given, show quick reference card
@@ -86,7 +107,7 @@ var versionCmd = &cobra.Command{
 		}
 
 		switch GetOutput() {
-		case "json", "yaml", "toon":
+		case "json", "ndjson", "yaml", "toon", "porcelain":
 			out := output.New(output.Format(GetOutput()), output.WithStats(GetStats()))
 			return out.Write(payload)
 		case "text":
@@ -110,7 +131,7 @@ func Execute() error {
 }
 
 // GetOutput returns the configured output format.
-// Precedence: CLI flags > SLB_OUTPUT_FORMAT env > TOON_DEFAULT_FORMAT env > default
+// Precedence: CLI flags (or SLB_OUTPUT) > SLB_OUTPUT_FORMAT env > TOON_DEFAULT_FORMAT env > default
 func GetOutput() string {
 	// CLI flags have highest precedence
 	if flagJSON {
@@ -119,6 +140,9 @@ func GetOutput() string {
 	if flagTOON {
 		return "toon"
 	}
+	if flagPorcelain {
+		return "porcelain"
+	}
 	if flagOutput != "text" {
 		return flagOutput
 	}
@@ -126,13 +150,13 @@ func GetOutput() string {
 	// Check environment variables
 	if envFormat := os.Getenv("SLB_OUTPUT_FORMAT"); envFormat != "" {
 		switch envFormat {
-		case "json", "yaml", "toon", "text":
+		case "json", "ndjson", "yaml", "toon", "porcelain", "text":
 			return envFormat
 		}
 	}
 	if envFormat := os.Getenv("TOON_DEFAULT_FORMAT"); envFormat != "" {
 		switch envFormat {
-		case "json", "yaml", "toon", "text":
+		case "json", "ndjson", "yaml", "toon", "porcelain", "text":
 			return envFormat
 		}
 	}
@@ -140,12 +164,23 @@ func GetOutput() string {
 	return flagOutput
 }
 
+// isJSONOutput reports whether the output format is JSON, NDJSON or
+// porcelain, i.e. whether stdout must carry only machine-readable records.
+func isJSONOutput() bool {
+	switch GetOutput() {
+	case "json", "ndjson", "porcelain":
+		return true
+	}
+	return false
+}
+
 // GetStats returns whether to show token savings statistics.
 func GetStats() bool {
 	return flagStats
 }
 
-// GetDB returns the database path.
+// GetDB returns the database path: --db (or SLB_DB), else the profile's
+// db, else the project's .slb/state.db.
 func GetDB() string {
 	if flagDB != "" {
 		return flagDB
@@ -184,18 +219,119 @@ func GetActor() string {
 
 func init() {
 	// Global flags with short aliases as specified in plan
-	rootCmd.PersistentFlags().StringVarP(&flagConfig, "config", "c", "", "config file path")
-	rootCmd.PersistentFlags().StringVarP(&flagOutput, "output", "o", "text", "output format: text, json, yaml, toon (env: SLB_OUTPUT_FORMAT, TOON_DEFAULT_FORMAT)")
+	rootCmd.PersistentFlags().StringVarP(&flagConfig, "config", "c", "", "config file path (env: SLB_CONFIG)")
+	rootCmd.PersistentFlags().StringVarP(&flagOutput, "output", "o", "text", "output format: text, json, ndjson, yaml, toon, porcelain (env: SLB_OUTPUT, SLB_OUTPUT_FORMAT, TOON_DEFAULT_FORMAT)")
 	rootCmd.PersistentFlags().BoolVarP(&flagJSON, "json", "j", false, "shorthand for --output=json")
 	rootCmd.PersistentFlags().BoolVarP(&flagTOON, "toon", "t", false, "shorthand for --output=toon")
-	rootCmd.PersistentFlags().BoolVar(&flagStats, "stats", false, "show token savings statistics (JSON vs TOON bytes)")
-	rootCmd.PersistentFlags().BoolVarP(&flagVerbose, "verbose", "v", false, "verbose output")
-	rootCmd.PersistentFlags().StringVar(&flagDB, "db", "", "database path")
-	rootCmd.PersistentFlags().StringVar(&flagActor, "actor", "", "actor identifier")
-	rootCmd.PersistentFlags().StringVarP(&flagSessionID, "session-id", "s", "", "session ID")
-	rootCmd.PersistentFlags().StringVarP(&flagProject, "project", "C", "", "project directory")
+	rootCmd.PersistentFlags().BoolVar(&flagPorcelain, "porcelain", false, "shorthand for --output=porcelain: stable tab-separated lines for scripts")
+	rootCmd.PersistentFlags().BoolVar(&flagQuiet, "quiet", false, "print only errors and requested records; rely on the exit code (env: SLB_QUIET)")
+	rootCmd.PersistentFlags().BoolVar(&flagStats, "stats", false, "show token savings statistics (JSON vs TOON bytes) (env: SLB_STATS)")
+	rootCmd.PersistentFlags().BoolVarP(&flagVerbose, "verbose", "v", false, "verbose output (env: SLB_VERBOSE)")
+	rootCmd.PersistentFlags().StringVar(&flagDB, "db", "", "database path (env: SLB_DB)")
+	rootCmd.PersistentFlags().StringVar(&flagActor, "actor", "", "actor identifier (env: SLB_ACTOR, AGENT_NAME)")
+	rootCmd.PersistentFlags().StringVarP(&flagSessionID, "session-id", "s", "", "session ID (env: SLB_SESSION_ID)")
+	rootCmd.PersistentFlags().StringVarP(&flagProject, "project", "C", "", "project directory (env: SLB_PROJECT)")
+	rootCmd.PersistentFlags().StringVar(&flagProfile, "profile", "", "config profile: a [profiles.<name>] table of settings such as db, daemon address and review pool (env: SLB_PROFILE)")
+	rootCmd.PersistentFlags().BoolVar(&flagNoColor, "no-color", false, "accessible output: no colors, ASCII labels instead of emoji (env: SLB_NO_COLOR, NO_COLOR)")
+	rootCmd.PersistentFlags().BoolVar(&flagRaw, "raw", false, "print counts, durations and sizes unformatted in text output (12345, 1h2m3s, 1536 B) (env: SLB_RAW)")
+	rootCmd.PersistentFlags().StringVar(&flagTimes, "timestamps", "", "show times as relative or absolute (RFC3339 in general.timezone) (env: SLB_TIMESTAMPS)")
 
 	// Add subcommands
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(sessionCmd)
 }
+
+// envFlags are the global flags that can be set from the environment, so
+// agents in containers can be configured without passing flags on every
+// call. A flag given on the command line wins over its variable.
+var envFlags = []struct {
+	flag string
+	env  string
+}{
+	{"config", "SLB_CONFIG"},
+	{"profile", "SLB_PROFILE"},
+	{"db", "SLB_DB"},
+	{"project", "SLB_PROJECT"},
+	{"output", "SLB_OUTPUT"},
+	{"actor", "SLB_ACTOR"},
+	{"session-id", "SLB_SESSION_ID"},
+	{"verbose", "SLB_VERBOSE"},
+	{"stats", "SLB_STATS"},
+	{"quiet", "SLB_QUIET"},
+	{"raw", "SLB_RAW"},
+}
+
+// applyEnvFlags sets each flag in envFlags that was not given on the
+// command line from its SLB_ variable, if set. Empty variables are ignored;
+// an invalid value (e.g. SLB_QUIET=maybe) is a config error.
+func applyEnvFlags(flags *pflag.FlagSet) error {
+	for _, ef := range envFlags {
+		f := flags.Lookup(ef.flag)
+		if f == nil || f.Changed {
+			continue
+		}
+		value := os.Getenv(ef.env)
+		if value == "" {
+			continue
+		}
+		if err := f.Value.Set(value); err != nil {
+			return withExitCode(exitConfig, fmt.Errorf("%s=%q: invalid value for --%s: %w", ef.env, value, ef.flag, err))
+		}
+	}
+	return nil
+}
+
+// applyProfile selects the config profile given by --profile (or
+// SLB_PROFILE) for every config load in the process, and makes its db the
+// database unless --db or SLB_DB is set. An undefined profile is an error
+// here, so a typo fails every command rather than being ignored.
+func applyProfile() error {
+	config.SetProfile(flagProfile)
+	_, db, err := config.ActiveProfile(config.LoadOptions{ConfigPath: flagConfig})
+	if err != nil {
+		return err
+	}
+	if flagDB == "" && db != "" {
+		flagDB = db
+	}
+	return nil
+}
+
+// applyOutputMode applies --quiet: the output package drops its own notes
+// and the status lines commands print with output.Notef. Records, the
+// output of executed commands, errors and exit codes are unchanged.
+func applyOutputMode() {
+	output.SetQuiet(flagQuiet)
+}
+
+// applyDisplayConfig selects the message catalog from general.locale (or
+// SLB_LOCALE), falling back to the POSIX locale variables, and how times are
+// shown from general.timestamps and general.timezone, with --timestamps
+// taking precedence, and whether numbers are formatted (--raw). Config
+// errors are ignored here; commands that need the config report them. Only
+// a bad --timestamps value is an error.
+func applyDisplayConfig() error {
+	numfmt.SetRaw(flagRaw)
+
+	var general config.GeneralConfig
+	if cfg, err := config.Load(config.LoadOptions{ConfigPath: flagConfig}); err == nil {
+		general = cfg.General
+	}
+	i18n.SetLocale(general.Locale)
+
+	style, err := timefmt.ParseStyle(general.Timestamps)
+	if err != nil {
+		style = timefmt.Relative
+	}
+	if flagTimes != "" {
+		if style, err = timefmt.ParseStyle(flagTimes); err != nil {
+			return fmt.Errorf("--timestamps: %w", err)
+		}
+	}
+	loc, err := timefmt.LoadLocation(general.Timezone)
+	if err != nil {
+		loc = nil
+	}
+	timefmt.Set(timefmt.Formatter{Style: style, Location: loc})
+	return nil
+}
@@ -8,7 +8,11 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/numfmt"
+	"github.com/Dicklesworthstone/slb/internal/output"
 	"github.com/Dicklesworthstone/slb/internal/testutil"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
@@ -284,6 +288,61 @@ func TestGetOutput(t *testing.T) {
 	flagOutput = "text"
 }
 
+func TestGetOutput_EnvNDJSON(t *testing.T) {
+	flagJSON = false
+	flagOutput = "text"
+	t.Setenv("SLB_OUTPUT_FORMAT", "ndjson")
+
+	if got := GetOutput(); got != "ndjson" {
+		t.Fatalf("GetOutput() = %v, want ndjson", got)
+	}
+	if !isJSONOutput() {
+		t.Fatal("expected ndjson to count as JSON output")
+	}
+
+	t.Setenv("SLB_OUTPUT_FORMAT", "text")
+	if isJSONOutput() {
+		t.Fatal("expected text not to count as JSON output")
+	}
+}
+
+func TestGetOutput_Porcelain(t *testing.T) {
+	flagJSON, flagOutput = false, "text"
+	t.Setenv("SLB_OUTPUT_FORMAT", "")
+	flagPorcelain = true
+	t.Cleanup(func() { flagPorcelain = false })
+
+	if got := GetOutput(); got != "porcelain" {
+		t.Fatalf("GetOutput() = %v, want porcelain", got)
+	}
+	if !isJSONOutput() {
+		t.Fatal("expected porcelain to keep stdout to records")
+	}
+
+	flagPorcelain = false
+	t.Setenv("SLB_OUTPUT_FORMAT", "porcelain")
+	if got := GetOutput(); got != "porcelain" {
+		t.Fatalf("GetOutput() from env = %v, want porcelain", got)
+	}
+}
+
+func TestApplyOutputMode_Quiet(t *testing.T) {
+	prevStdout := os.Stdout
+	t.Cleanup(func() {
+		flagQuiet = false
+		output.SetQuiet(false)
+	})
+
+	flagQuiet = true
+	applyOutputMode()
+	if !output.Quiet() {
+		t.Fatal("--quiet should turn on quiet mode")
+	}
+	if os.Stdout != prevStdout {
+		t.Fatal("--quiet must leave stdout to records and executed commands")
+	}
+}
+
 func TestGetDB(t *testing.T) {
 	// Save original values
 	origDB := flagDB
@@ -568,3 +627,162 @@ func TestUnknownFlag(t *testing.T) {
 		t.Error("expected error for unknown flag")
 	}
 }
+
+func TestApplyDisplayConfig_Timestamps(t *testing.T) {
+	prev := timefmt.Current()
+	oldConfig, oldTimes := flagConfig, flagTimes
+	t.Cleanup(func() {
+		timefmt.Set(prev)
+		flagConfig, flagTimes = oldConfig, oldTimes
+	})
+	t.Setenv("SLB_TIMEZONE", "")
+	t.Setenv("SLB_TIMESTAMPS", "")
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte("[general]\ntimezone = \"Asia/Tokyo\"\ntimestamps = \"absolute\"\n"), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	flagConfig, flagTimes = path, ""
+
+	if err := applyDisplayConfig(); err != nil {
+		t.Fatalf("applyDisplayConfig: %v", err)
+	}
+	f := timefmt.Current()
+	if !f.IsAbsolute() || f.Location.String() != "Asia/Tokyo" {
+		t.Fatalf("expected absolute Asia/Tokyo, got %s %s", f.Style, f.Location)
+	}
+
+	flagTimes = "relative"
+	if err := applyDisplayConfig(); err != nil {
+		t.Fatalf("applyDisplayConfig: %v", err)
+	}
+	if timefmt.Current().IsAbsolute() {
+		t.Error("--timestamps should override general.timestamps")
+	}
+
+	flagTimes = "sometimes"
+	if err := applyDisplayConfig(); err == nil {
+		t.Error("expected error for an unknown --timestamps value")
+	}
+}
+
+func TestApplyDisplayConfig_Raw(t *testing.T) {
+	oldConfig, oldTimes, oldRaw := flagConfig, flagTimes, flagRaw
+	t.Cleanup(func() {
+		flagConfig, flagTimes, flagRaw = oldConfig, oldTimes, oldRaw
+		numfmt.SetRaw(false)
+	})
+	flagConfig, flagTimes = filepath.Join(t.TempDir(), "missing.toml"), ""
+
+	flagRaw = true
+	if err := applyDisplayConfig(); err != nil {
+		t.Fatalf("applyDisplayConfig: %v", err)
+	}
+	if !numfmt.Raw() || numfmt.Count(12345) != "12345" {
+		t.Error("--raw should turn number formatting off")
+	}
+	flagRaw = false
+	if err := applyDisplayConfig(); err != nil {
+		t.Fatalf("applyDisplayConfig: %v", err)
+	}
+	if numfmt.Raw() {
+		t.Error("raw mode should be off without --raw")
+	}
+}
+
+func TestApplyEnvFlags(t *testing.T) {
+	origDB, origProject, origOutput, origSession, origQuiet := flagDB, flagProject, flagOutput, flagSessionID, flagQuiet
+	t.Cleanup(func() {
+		flagDB, flagProject, flagOutput, flagSessionID, flagQuiet = origDB, origProject, origOutput, origSession, origQuiet
+	})
+
+	newFlags := func() *pflag.FlagSet {
+		fs := pflag.NewFlagSet("slb", pflag.ContinueOnError)
+		fs.StringVar(&flagDB, "db", "", "")
+		fs.StringVarP(&flagProject, "project", "C", "", "")
+		fs.StringVarP(&flagOutput, "output", "o", "text", "")
+		fs.StringVarP(&flagSessionID, "session-id", "s", "", "")
+		fs.BoolVar(&flagQuiet, "quiet", false, "")
+		return fs
+	}
+
+	t.Setenv("SLB_DB", "/env/state.db")
+	t.Setenv("SLB_PROJECT", "/env/project")
+	t.Setenv("SLB_OUTPUT", "json")
+	t.Setenv("SLB_SESSION_ID", "")
+	t.Setenv("SLB_QUIET", "1")
+
+	fs := newFlags()
+	if err := fs.Parse([]string{"--db", "/flag/state.db"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := applyEnvFlags(fs); err != nil {
+		t.Fatalf("applyEnvFlags: %v", err)
+	}
+	if flagDB != "/flag/state.db" {
+		t.Errorf("--db should win over SLB_DB, got %q", flagDB)
+	}
+	if flagProject != "/env/project" || flagOutput != "json" || !flagQuiet {
+		t.Errorf("env not applied: project=%q output=%q quiet=%v", flagProject, flagOutput, flagQuiet)
+	}
+	if flagSessionID != "" {
+		t.Errorf("empty SLB_SESSION_ID should be ignored, got %q", flagSessionID)
+	}
+	if GetOutput() != "json" {
+		t.Errorf("GetOutput() = %q, want json from SLB_OUTPUT", GetOutput())
+	}
+
+	t.Setenv("SLB_QUIET", "maybe")
+	fs = newFlags()
+	if err := fs.Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+	err := applyEnvFlags(fs)
+	if err == nil || !strings.Contains(err.Error(), "SLB_QUIET") || ExitCode(err) != exitConfig {
+		t.Fatalf("invalid SLB_QUIET: err=%v exit=%d", err, ExitCode(err))
+	}
+}
+
+func TestApplyProfile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("SLB_PROFILE", "")
+	origDB, origProfile, origConfig := flagDB, flagProfile, flagConfig
+	t.Cleanup(func() {
+		flagDB, flagProfile, flagConfig = origDB, origProfile, origConfig
+		config.SetProfile("")
+	})
+	flagConfig = ""
+
+	cfgPath := filepath.Join(home, ".slb", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(cfgPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	toml := "[profiles.staging]\ndb = \"/srv/staging/state.db\"\n\n[profiles.staging.daemon]\ntcp_addr = \"staging-host:9876\"\n"
+	if err := os.WriteFile(cfgPath, []byte(toml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	flagDB, flagProfile = "", "staging"
+	if err := applyProfile(); err != nil {
+		t.Fatalf("applyProfile: %v", err)
+	}
+	if GetDB() != "/srv/staging/state.db" {
+		t.Errorf("GetDB() = %q, want the profile's db", GetDB())
+	}
+	// Later config loads in the process see the profile.
+	cfg, err := config.Load(config.LoadOptions{ProjectDir: t.TempDir()})
+	if err != nil || cfg.Daemon.TCPAddr != "staging-host:9876" {
+		t.Errorf("profile not applied to config loads: %q, %v", cfg.Daemon.TCPAddr, err)
+	}
+
+	flagDB = "/explicit.db"
+	if err := applyProfile(); err != nil || GetDB() != "/explicit.db" {
+		t.Errorf("--db should win over the profile's db, got %q (%v)", GetDB(), err)
+	}
+
+	flagProfile = "prod"
+	if err := applyProfile(); ExitCode(err) != exitConfig {
+		t.Fatalf("undefined profile: err=%v exit=%d", err, ExitCode(err))
+	}
+}
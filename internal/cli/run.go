@@ -2,17 +2,23 @@
 package cli
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"math"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/Dicklesworthstone/slb/internal/config"
 	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/core/statemachine"
+	"github.com/Dicklesworthstone/slb/internal/daemon"
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
 	"github.com/spf13/cobra"
 )
 
@@ -26,6 +32,7 @@ var (
 	flagRunAttachFile     []string
 	flagRunAttachContext  []string
 	flagRunAttachScreen   []string
+	flagRunContextFile    []string
 )
 
 func init() {
@@ -38,6 +45,9 @@ func init() {
 	runCmd.Flags().StringSliceVar(&flagRunAttachFile, "attach-file", nil, "attach file content as context")
 	runCmd.Flags().StringSliceVar(&flagRunAttachContext, "attach-context", nil, "run command and attach output as context")
 	runCmd.Flags().StringSliceVar(&flagRunAttachScreen, "attach-screenshot", nil, "attach screenshot/image file")
+	runCmd.Flags().StringSliceVar(&flagRunContextFile, "context-file", nil, "attach an agent transcript snippet (tail only, capped and redacted)")
+	addProvenanceFlags(runCmd)
+	addLabelFlags(runCmd)
 
 	rootCmd.AddCommand(runCmd)
 }
@@ -96,11 +106,17 @@ Examples:
 
 		out := output.New(output.Format(GetOutput()))
 
+		labels, err := labelsFromFlags()
+		if err != nil {
+			return writeError(cmd, out, "invalid_label", command, err)
+		}
+
 		// Collect attachments from flags
 		attachments, err := CollectAttachments(cmd.Context(), AttachmentFlags{
 			Files:       flagRunAttachFile,
 			Contexts:    flagRunAttachContext,
 			Screenshots: flagRunAttachScreen,
+			Transcripts: flagRunContextFile,
 		})
 		if err != nil {
 			return writeError(cmd, out, "attachment_error", command, err)
@@ -118,11 +134,16 @@ Examples:
 		// Step 1: Classify and create request using config-derived limits and notifiers
 		rl := core.NewRateLimiter(dbConn, toRateLimitConfig(cfg))
 		creator := core.NewRequestCreator(dbConn, rl, nil, toRequestCreatorConfig(cfg))
-		result, err := creator.CreateRequest(core.CreateRequestOptions{
+		if advisor := daemon.LLMAdvisorFromConfig(cfg); advisor != nil {
+			creator.SetAdvisor(advisor)
+			defer creator.WaitForAdvice()
+		}
+		result, err := creator.CreateRequest(cmd.Context(), core.CreateRequestOptions{
 			SessionID: flagSessionID,
 			Command:   command,
 			Cwd:       cwd,
 			Shell:     true, // run always uses shell
+			Environ:   os.Environ(),
 			Justification: core.Justification{
 				Reason:         flagRunReason,
 				ExpectedEffect: flagRunExpectedEffect,
@@ -131,6 +152,8 @@ Examples:
 			},
 			Attachments: attachments,
 			ProjectPath: project,
+			Provenance:  provenanceFromFlags(),
+			Labels:      labels,
 		})
 		if err != nil {
 			return writeError(cmd, out, "request_failed", command, err)
@@ -142,32 +165,52 @@ Examples:
 			if err != nil {
 				return err
 			}
-			if exitCode != 0 {
-				os.Exit(exitCode)
-			}
-			return nil
+			return withExitCode(exitCode, nil)
 		}
 
 		request := result.Request
+		created := map[string]any{
+			"request_id":    request.ID,
+			"tier":          string(request.RiskTier),
+			"status":        string(request.Status),
+			"min_approvals": request.MinApprovals,
+		}
+		if len(result.TemplateVars) > 0 {
+			created["template_vars"] = result.TemplateVars
+			warnTemplateVars(result.TemplateVars)
+		}
+		emitRunEvent(out, "request_created", created)
 
 		// Step 3: If yield mode and not immediately approved, return request info
 		if flagRunYield && request.Status == db.StatusPending {
-			return out.Write(map[string]any{
+			return withExitCode(exitNeedsApproval, out.Write(map[string]any{
 				"status":        "pending",
 				"request_id":    request.ID,
 				"tier":          string(request.RiskTier),
 				"min_approvals": request.MinApprovals,
 				"message":       "Request created, yielding to background. Check status with: slb status " + request.ID,
-			})
+			}))
 		}
 
 		// Step 4: Wait for approval
+		stopWaiting := markRequestorWaiting(dbConn, request.ID, request.RequestorSessionID)
+		defer stopWaiting()
+		waiter := newDecisionWaiter(request.RequestorSessionID, request.ID)
+		defer waiter.Close()
 		deadline := time.Now().Add(time.Duration(flagRunTimeout) * time.Second)
+		lastStatus := request.Status
 		for time.Now().Before(deadline) {
 			request, _, err = dbConn.GetRequestWithReviews(request.ID)
 			if err != nil {
 				return writeError(cmd, out, "poll_failed", command, err)
 			}
+			if request.Status != lastStatus {
+				lastStatus = request.Status
+				emitRunEvent(out, "request_status", map[string]any{
+					"request_id": request.ID,
+					"status":     string(request.Status),
+				})
+			}
 
 			// Evaluate status
 			decision := evaluateRequestForExecution(request.Status)
@@ -177,19 +220,24 @@ Examples:
 			}
 
 			if !decision.ShouldContinuePolling {
-				return writeError(cmd, out, string(request.Status), command,
-					fmt.Errorf("request %s: %s", request.ID, decision.Reason))
+				return withExitCode(statusExitCode(request.Status), writeError(cmd, out, string(request.Status), command,
+					fmt.Errorf("request %s: %s", request.ID, decision.Reason)))
 			}
 
-			time.Sleep(500 * time.Millisecond)
+			waiter.Wait(time.Until(deadline))
 		}
+		stopWaiting()
 
 		// Check if we timed out waiting
 		if request.Status == db.StatusPending {
 			// Mark as timeout
-			_ = dbConn.UpdateRequestStatus(request.ID, db.StatusTimeout)
-			return writeError(cmd, out, "timeout", command,
-				fmt.Errorf("request %s timed out waiting for approval", request.ID))
+			_, _ = statemachine.New(dbConn).Commit(request.ID, db.StatusTimeout, statemachine.Input{
+				Authority: statemachine.AuthoritySystem,
+				Actor:     request.RequestorAgent,
+				Reason:    "requestor stopped waiting for approval",
+			})
+			return withExitCode(exitTimeout, writeError(cmd, out, "timeout", command,
+				fmt.Errorf("request %s timed out waiting for approval", request.ID)))
 		}
 
 		// Step 5: Execute the approved command
@@ -197,10 +245,7 @@ Examples:
 		if err != nil {
 			return err
 		}
-		if exitCode != 0 {
-			os.Exit(exitCode)
-		}
-		return nil
+		return withExitCode(exitCode, nil)
 	},
 }
 
@@ -217,12 +262,17 @@ func runSafeCommand(cmd *cobra.Command, out *output.Writer, command, cwd, projec
 	}
 	spec.Hash = db.ComputeCommandHash(*spec)
 
-	var streamWriter *os.File
-	if GetOutput() != "json" {
-		streamWriter = os.Stdout
+	var streams core.OutputStreams
+	if !isJSONOutput() {
+		streams = core.OutputStreams{Stdout: os.Stdout, Stderr: os.Stdout}
+	}
+	events := newOutputEvents(out)
+	if events != nil {
+		streams = events.Streams()
 	}
 
-	result, execErr := core.RunCommand(cmd.Context(), spec, logPath, streamWriter)
+	result, execErr := core.RunCommandStreams(cmd.Context(), spec, logPath, streams)
+	events.Flush()
 
 	exitCode := 0
 	durationMs := int64(0)
@@ -243,8 +293,11 @@ func runSafeCommand(cmd *cobra.Command, out *output.Writer, command, cwd, projec
 	if execErr != nil {
 		resp["error"] = execErr.Error()
 	}
+	if GetOutput() == string(output.FormatNDJSON) {
+		resp["event"] = "result"
+	}
 
-	if GetOutput() == "json" {
+	if isJSONOutput() {
 		_ = out.Write(resp)
 		if execErr != nil {
 			return 1, nil // JSON output success, but command failed
@@ -257,31 +310,52 @@ func runSafeCommand(cmd *cobra.Command, out *output.Writer, command, cwd, projec
 		return 1, nil
 	}
 	if exitCode != 0 {
-		fmt.Fprintf(os.Stderr, "\n[slb] Command exited with code %d\n", exitCode)
+		output.Notef(os.Stderr, "\n[slb] Command exited with code %d\n", exitCode)
 		return exitCode, nil
 	}
 	return 0, nil
 }
 
 func runApprovedRequest(ctx context.Context, out *output.Writer, dbConn *db.DB, cfg config.Config, project, requestID string) (int, error) {
-	executor := core.NewExecutor(dbConn, nil).WithNotifier(buildAgentMailNotifier(project))
+	windows, err := buildExecutionWindows(cfg)
+	if err != nil {
+		return 1, err
+	}
+	isolation, err := buildIsolation(cfg, project)
+	if err != nil {
+		return 1, err
+	}
+	executor := core.NewExecutor(dbConn, nil).
+		WithNotifier(buildAgentMailNotifier(project)).
+		WithExecutionWindows(windows).
+		WithIsolation(isolation).
+		WithTierOverrides(agentTierOverrides(cfg))
 
-	execResult, execErr := executor.ExecuteApprovedRequest(ctx, core.ExecuteOptions{
+	opts := core.ExecuteOptions{
 		RequestID:         requestID,
 		SessionID:         flagSessionID,
-		LogDir:            ".slb/logs",
-		SuppressOutput:    GetOutput() == "json",
+		LogDir:            projectLogDir(project),
+		SuppressOutput:    isJSONOutput(),
 		CaptureRollback:   cfg.General.EnableRollbackCapture,
 		MaxRollbackSizeMB: cfg.General.MaxRollbackSizeMB,
-	})
+	}
+	events := newOutputEvents(out)
+	if events != nil {
+		streams := events.Streams()
+		opts.Streams = &streams
+	}
+	execResult, execErr := executor.ExecuteApprovedRequest(ctx, opts)
+	events.Flush()
 
 	exitCode := 0
 	durationMs := int64(0)
 	logPath := ""
+	var secretsFound []string
 	if execResult != nil {
 		exitCode = execResult.ExitCode
 		durationMs = execResult.Duration.Milliseconds()
 		logPath = execResult.LogPath
+		secretsFound = execResult.SecretsFound
 	}
 
 	resp := map[string]any{
@@ -291,11 +365,17 @@ func runApprovedRequest(ctx context.Context, out *output.Writer, dbConn *db.DB,
 		"duration_ms": durationMs,
 		"log_path":    logPath,
 	}
+	if len(secretsFound) > 0 {
+		resp["secrets_found"] = secretsFound
+	}
 	if execErr != nil {
 		resp["error"] = execErr.Error()
 	}
+	if GetOutput() == string(output.FormatNDJSON) {
+		resp["event"] = "result"
+	}
 
-	if GetOutput() == "json" {
+	if isJSONOutput() {
 		_ = out.Write(resp)
 		if execErr != nil {
 			return 1, nil
@@ -307,21 +387,128 @@ func runApprovedRequest(ctx context.Context, out *output.Writer, dbConn *db.DB,
 		fmt.Fprintf(os.Stderr, "[slb] Execution failed: %s\n", execErr.Error())
 		return 1, nil
 	}
+	warnSecretsFound(secretsFound)
 	if exitCode != 0 {
-		fmt.Fprintf(os.Stderr, "\n[slb] Command exited with code %d\n", exitCode)
+		output.Notef(os.Stderr, "\n[slb] Command exited with code %d\n", exitCode)
 		return exitCode, nil
 	}
 	return 0, nil
 }
 
+// emitRunEvent writes a progress event as one NDJSON line so agents can
+// follow a blocking run while it waits. Other formats only report the
+// final result.
+func emitRunEvent(out *output.Writer, event string, fields map[string]any) {
+	if GetOutput() != string(output.FormatNDJSON) {
+		return
+	}
+	line := map[string]any{
+		"event":     event,
+		"timestamp": timefmt.Format(time.Now()),
+	}
+	for k, v := range fields {
+		line[k] = v
+	}
+	_ = out.WriteNDJSON(line)
+}
+
+// maxOutputEventBytes bounds the line an output event carries; longer
+// lines are split across events.
+const maxOutputEventBytes = 64 * 1024
+
+// outputEvents turns a command's output into "output" events, one per
+// line with the stream it came from, so an NDJSON consumer sees a long
+// command's output as it is produced rather than only its result.
+type outputEvents struct {
+	mu      sync.Mutex
+	out     *output.Writer
+	pending map[string][]byte
+}
+
+// newOutputEvents returns nil unless the output format is NDJSON.
+func newOutputEvents(out *output.Writer) *outputEvents {
+	if GetOutput() != string(output.FormatNDJSON) {
+		return nil
+	}
+	return &outputEvents{out: out, pending: map[string][]byte{}}
+}
+
+// Streams returns the writers to run the command with.
+func (o *outputEvents) Streams() core.OutputStreams {
+	return core.OutputStreams{
+		Stdout: outputEventStream{o, "stdout"},
+		Stderr: outputEventStream{o, "stderr"},
+	}
+}
+
+// Flush emits any output left without a trailing newline.
+func (o *outputEvents) Flush() {
+	if o == nil {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, stream := range []string{"stdout", "stderr"} {
+		if rest := o.pending[stream]; len(rest) > 0 {
+			o.emit(stream, rest)
+			delete(o.pending, stream)
+		}
+	}
+}
+
+func (o *outputEvents) write(stream string, p []byte) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	buf := append(o.pending[stream], p...)
+	for {
+		i := bytes.IndexByte(buf, '\n')
+		if i < 0 {
+			if len(buf) < maxOutputEventBytes {
+				break
+			}
+			i = maxOutputEventBytes
+			o.emit(stream, buf[:i])
+			buf = buf[i:]
+			continue
+		}
+		o.emit(stream, bytes.TrimSuffix(buf[:i], []byte("\r")))
+		buf = buf[i+1:]
+	}
+	o.pending[stream] = append([]byte(nil), buf...)
+}
+
+func (o *outputEvents) emit(stream string, line []byte) {
+	emitRunEvent(o.out, "output", map[string]any{
+		"stream": stream,
+		"line":   string(line),
+	})
+}
+
+// outputEventStream is the writer for one of a command's streams.
+type outputEventStream struct {
+	o      *outputEvents
+	stream string
+}
+
+func (w outputEventStream) Write(p []byte) (int, error) {
+	w.o.write(w.stream, p)
+	return len(p), nil
+}
+
+// projectLogDir is the execution log directory of project, or .slb/logs
+// in the working directory when project is empty.
+func projectLogDir(project string) string {
+	if project == "" {
+		return ".slb/logs"
+	}
+	return filepath.Join(project, ".slb", "logs")
+}
+
 func createRunLogFile(project, prefix string) (string, error) {
 	if prefix == "" {
 		prefix = "run"
 	}
-	baseDir := ".slb/logs"
-	if project != "" {
-		baseDir = filepath.Join(project, ".slb", "logs")
-	}
+	baseDir := projectLogDir(project)
 	if err := os.MkdirAll(baseDir, 0700); err != nil {
 		return "", fmt.Errorf("creating log dir: %w", err)
 	}
@@ -411,9 +598,71 @@ func toRequestCreatorConfig(cfg config.Config) *core.RequestCreatorConfig {
 		AgentMailEnabled:           cfg.Integrations.AgentMailEnabled,
 		AgentMailThread:            cfg.Integrations.AgentMailThread,
 		AgentMailSender:            "",
+		AdvisoryTimeout:            time.Duration(cfg.Integrations.LLMReviewTimeoutSecs) * time.Second,
+		IdempotencyTTLMinutes:      cfg.General.IdempotencyTTLMins,
+		TierOverrides:              agentTierOverrides(cfg),
+		PinnedEnv:                  cfg.General.PinnedEnv,
+		ProductionHosts:            cfg.Targets.ProductionHosts,
+		Anomaly: core.AnomalyConfig{
+			Enabled:        cfg.Anomaly.Enabled,
+			BurstWindow:    time.Duration(cfg.Anomaly.BurstWindowSecs) * time.Second,
+			BurstThreshold: cfg.Anomaly.BurstThreshold,
+			NovelFamily:    cfg.Anomaly.NovelFamily,
+			MinHistory:     cfg.Anomaly.MinHistory,
+			BaselineDays:   cfg.Anomaly.BaselineDays,
+		},
 	}
 }
 
+// agentTierOverrides returns the configured agents.tier_overrides. Entries
+// were checked when the config loaded.
+func agentTierOverrides(cfg config.Config) []core.TierOverride {
+	overrides, _ := core.ParseTierOverrides(cfg.Agents.TierOverrides)
+	return overrides
+}
+
+// buildExecutionWindows returns the configured quiet-hours policy, or nil when disabled.
+func buildExecutionWindows(cfg config.Config) (*core.ExecutionWindowPolicy, error) {
+	w := cfg.ExecutionWindows
+	if !w.Enabled {
+		return nil, nil
+	}
+	policy, err := core.NewExecutionWindowPolicy(w.QuietHours, w.BlockWeekends, w.Tiers, w.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("execution_windows: %w", err)
+	}
+	return policy, nil
+}
+
+// buildIsolation returns the configured isolation for approved commands run
+// in project, or nil when they run directly.
+func buildIsolation(cfg config.Config, project string) (*core.Isolation, error) {
+	iso := cfg.Isolation
+	isolation, err := core.NewIsolation(iso.Mode, iso.User, iso.Runtime, iso.Image, iso.Network, project)
+	if err != nil {
+		return nil, fmt.Errorf("isolation: %w", err)
+	}
+	return isolation, nil
+}
+
+// warnSecretsFound tells the human that the command printed secrets, which
+// are redacted from the log but have already been shown.
+func warnSecretsFound(kinds []string) {
+	if len(kinds) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "\n[slb] Output contained secrets (%s): redacted from the log and reviewers notified. Rotate them.\n", strings.Join(kinds, ", "))
+}
+
+// warnBreakglassSecrets is warnSecretsFound for break-glass runs, which
+// have no request to report the secrets on.
+func warnBreakglassSecrets(kinds []string) {
+	if len(kinds) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "\n[slb] Output contained secrets (%s): redacted from the log. Rotate them.\n", strings.Join(kinds, ", "))
+}
+
 // writeError outputs an error response.
 func writeError(cmd *cobra.Command, out *output.Writer, status, command string, err error) error {
 	resp := map[string]any{
@@ -422,7 +671,11 @@ func writeError(cmd *cobra.Command, out *output.Writer, status, command string,
 		"error":   err.Error(),
 	}
 
-	if GetOutput() == "json" {
+	if GetOutput() == string(output.FormatNDJSON) {
+		resp["event"] = "error"
+	}
+
+	if isJSONOutput() {
 		_ = out.Write(resp)
 	} else {
 		fmt.Fprintf(os.Stderr, "[slb] Error: %s\n", err.Error())
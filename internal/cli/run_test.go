@@ -9,6 +9,7 @@ import (
 
 	"github.com/Dicklesworthstone/slb/internal/config"
 	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/output"
 	"github.com/Dicklesworthstone/slb/internal/testutil"
 	"github.com/spf13/cobra"
 )
@@ -44,6 +45,7 @@ func newTestRunCmd(dbPath string) *cobra.Command {
 	rCmd.Flags().StringSliceVar(&flagRunAttachFile, "attach-file", nil, "attach file")
 	rCmd.Flags().StringSliceVar(&flagRunAttachContext, "attach-context", nil, "attach context")
 	rCmd.Flags().StringSliceVar(&flagRunAttachScreen, "attach-screenshot", nil, "attach screenshot")
+	rCmd.Flags().StringSliceVar(&flagRunContextFile, "context-file", nil, "attach transcript snippet")
 
 	root.AddCommand(rCmd)
 
@@ -66,6 +68,9 @@ func resetRunFlags() {
 	flagRunAttachFile = nil
 	flagRunAttachContext = nil
 	flagRunAttachScreen = nil
+	flagRunContextFile = nil
+	resetProvenanceFlags()
+	resetLabelFlags()
 }
 
 func TestRunCommand_RequiresCommand(t *testing.T) {
@@ -161,8 +166,8 @@ func TestRunCommand_HonorsCustomPattern(t *testing.T) {
 		"--yield",
 		"-j",
 	)
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
+	if ExitCode(err) != exitNeedsApproval {
+		t.Fatalf("expected needs-approval exit, got %v", err)
 	}
 
 	var result map[string]any
@@ -674,3 +679,113 @@ func TestCreateRunLogFile_FileCreationError(t *testing.T) {
 		t.Errorf("expected error about creating log file, got: %v", err)
 	}
 }
+
+func TestRunCommand_NDJSONEmitsProgressEvents(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetRunFlags()
+
+	sess := testutil.MakeSession(t, h.DB,
+		testutil.WithProject(h.ProjectDir),
+		testutil.WithAgent("TestAgent"),
+	)
+
+	cmd := newTestRunCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "run", "rm -rf ./build",
+		"-s", sess.ID,
+		"-C", h.ProjectDir,
+		"--yield",
+		"-o", "ndjson",
+	)
+	if ExitCode(err) != exitNeedsApproval {
+		t.Fatalf("expected needs-approval exit, got %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 NDJSON lines, got %d: %q", len(lines), stdout)
+	}
+	var created, result map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &created); err != nil {
+		t.Fatalf("line 1 is not JSON: %v (%q)", err, lines[0])
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &result); err != nil {
+		t.Fatalf("line 2 is not JSON: %v (%q)", err, lines[1])
+	}
+	if created["event"] != "request_created" || created["status"] != "pending" || created["request_id"] == "" {
+		t.Errorf("unexpected created event: %+v", created)
+	}
+	if result["status"] != "pending" || result["request_id"] != created["request_id"] {
+		t.Errorf("unexpected yield result: %+v", result)
+	}
+}
+
+// TestRunCommand_NDJSONStreamsOutput checks that the command's output
+// arrives as output events ahead of the result.
+func TestRunCommand_NDJSONStreamsOutput(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetRunFlags()
+
+	sess := testutil.MakeSession(t, h.DB,
+		testutil.WithProject(h.ProjectDir),
+		testutil.WithAgent("TestAgent"),
+	)
+
+	cmd := newTestRunCmd(h.DBPath)
+	stdout, err := executeCommandCapture(t, cmd, "run", "echo hello-ndjson",
+		"-s", sess.ID,
+		"-C", h.ProjectDir,
+		"-o", "ndjson",
+	)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	var events []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(stdout), "\n") {
+		var event map[string]any
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("line is not JSON: %v (%q)", err, line)
+		}
+		events = append(events, event)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected an output and a result event, got %q", stdout)
+	}
+	if events[0]["event"] != "output" || events[0]["stream"] != "stdout" || events[0]["line"] != "hello-ndjson" {
+		t.Errorf("unexpected output event: %+v", events[0])
+	}
+	if events[1]["event"] != "result" || events[1]["exit_code"] != float64(0) {
+		t.Errorf("unexpected result event: %+v", events[1])
+	}
+}
+
+// TestRunCommand_QuietKeepsCommandOutput guards --quiet: it drops slb's own
+// status lines, not the output of the command the user asked slb to run.
+func TestRunCommand_QuietKeepsCommandOutput(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetRunFlags()
+	t.Cleanup(func() {
+		flagQuiet = false
+		output.SetQuiet(false)
+	})
+
+	sess := testutil.MakeSession(t, h.DB,
+		testutil.WithProject(h.ProjectDir),
+		testutil.WithAgent("TestAgent"),
+	)
+
+	cmd := newTestRunCmd(h.DBPath)
+	cmd.PersistentFlags().BoolVar(&flagQuiet, "quiet", false, "quiet output")
+	cmd.PersistentPreRun = func(*cobra.Command, []string) { applyOutputMode() }
+	stdout, err := executeCommandCapture(t, cmd, "run", "echo hello-from-command",
+		"-s", sess.ID,
+		"-C", h.ProjectDir,
+		"--quiet",
+	)
+	if err != nil {
+		t.Fatalf("run --quiet: %v", err)
+	}
+	if !strings.Contains(stdout, "hello-from-command") {
+		t.Fatalf("--quiet swallowed the command's output: %q", stdout)
+	}
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// printScriptLines prints a multi-line command a statement at a time, each
+// with its own tier, so reviewers can see which lines make it risky.
+// Here-document lines are marked with <<.
+func printScriptLines(script *db.RequestScript, lines []core.ScriptLine) {
+	if script != nil {
+		fmt.Printf("Script:  %s\n", formatScript(script))
+	}
+	fmt.Printf("Command: %d statements\n", len(lines))
+	for _, l := range lines {
+		tier := "-"
+		if l.Tier != "" {
+			tier = strings.ToUpper(string(l.Tier))
+		}
+		text := l.Text
+		if l.Heredoc {
+			text = "<< " + text
+		}
+		text = strings.ReplaceAll(text, "\n", "\n"+strings.Repeat(" ", 19))
+		fmt.Printf("  %4d  %-9s  %s\n", l.Line, tier, text)
+	}
+}
+
+// formatScript describes how a script request runs: its interpreter, where
+// it was read from and a short digest.
+func formatScript(s *db.RequestScript) string {
+	out := "run by " + s.Interpreter
+	if s.Source != "" && s.Source != "-" {
+		out += " from " + s.Source
+	}
+	return out + " (sha256:" + shortHash(s.SHA256) + ")"
+}
+
+// remoteScriptPreviewLines is how much of a remote script's preview the
+// text output of review show prints.
+const remoteScriptPreviewLines = 20
+
+// remoteScriptViews summarizes pinned remote scripts for a request
+// response; the previews are left to review show.
+func remoteScriptViews(pins []*db.RemoteScript) []map[string]any {
+	views := make([]map[string]any, 0, len(pins))
+	for _, p := range pins {
+		views = append(views, map[string]any{
+			"url":    p.URL,
+			"shell":  p.Shell,
+			"sha256": p.SHA256,
+			"size":   p.Size,
+		})
+	}
+	return views
+}
+
+// printRemoteScripts shows reviewers what each downloaded script that is
+// piped into a shell contained when the request was created.
+func printRemoteScripts(pins []*db.RemoteScript) {
+	for _, p := range pins {
+		fmt.Printf("REMOTE SCRIPT: %s piped to %s (sha256:%s, %d bytes; execution refuses changed content)\n",
+			p.URL, p.Shell, shortHash(p.SHA256), p.Size)
+		lines := strings.Split(strings.TrimRight(p.Preview, "\n"), "\n")
+		shown := lines
+		if len(shown) > remoteScriptPreviewLines {
+			shown = shown[:remoteScriptPreviewLines]
+		}
+		for _, line := range shown {
+			fmt.Printf("  | %s\n", line)
+		}
+		if len(shown) < len(lines) || int64(len(p.Preview)) < p.Size {
+			fmt.Println("  | ... (use -j for the full preview)")
+		}
+	}
+}
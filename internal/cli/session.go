@@ -2,15 +2,18 @@ package cli
 
 import (
 	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/Dicklesworthstone/slb/internal/config"
 	"github.com/Dicklesworthstone/slb/internal/core"
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
 	"github.com/spf13/cobra"
 )
 
@@ -70,6 +73,17 @@ var sessionStartCmd = &cobra.Command{
 		}
 		defer dbConn.Close()
 
+		cfg, err := config.Load(config.LoadOptions{ProjectDir: project, ConfigPath: flagConfig})
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		if _, err := dbConn.GetActiveSession(flagSessionAgent, project); err == nil {
+			return fmt.Errorf("active session already exists for agent %q in project %q (try: slb session resume -a %s)", flagSessionAgent, project, flagSessionAgent)
+		}
+		if err := core.WaitForSessionSlot(context.Background(), dbConn, sessionLimits(cfg), flagSessionAgent, project); err != nil {
+			return err
+		}
+
 		session := &db.Session{
 			AgentName:   flagSessionAgent,
 			Program:     flagSessionProg,
@@ -92,7 +106,10 @@ var sessionStartCmd = &cobra.Command{
 			"program":      session.Program,
 			"model":        session.Model,
 			"project_path": session.ProjectPath,
-			"started_at":   session.StartedAt.Format(time.RFC3339),
+			"started_at":   timefmt.Format(session.StartedAt),
+		}
+		if backend := storeSessionKey(session); backend != "" {
+			result["key_storage"] = backend
 		}
 		return out.Write(result)
 	},
@@ -114,11 +131,16 @@ var sessionEndCmd = &cobra.Command{
 		if err := dbConn.EndSession(flagSessionID); err != nil {
 			return err
 		}
+		project := ""
+		if sess, err := dbConn.GetSession(flagSessionID); err == nil {
+			project = sess.ProjectPath
+		}
+		forgetSessionKeys(project, flagSessionID)
 
 		out := output.New(output.Format(GetOutput()))
 		return out.Write(map[string]any{
 			"session_id": flagSessionID,
-			"ended_at":   time.Now().UTC().Format(time.RFC3339),
+			"ended_at":   timefmt.Format(time.Now()),
 		})
 	},
 }
@@ -140,6 +162,10 @@ var sessionResumeCmd = &cobra.Command{
 		}
 		defer dbConn.Close()
 
+		cfg, err := config.Load(config.LoadOptions{ProjectDir: project, ConfigPath: flagConfig})
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
 		sess, err := core.ResumeSession(dbConn, core.ResumeOptions{
 			AgentName:        flagSessionAgent,
 			Program:          flagSessionProg,
@@ -147,22 +173,27 @@ var sessionResumeCmd = &cobra.Command{
 			ProjectPath:      project,
 			CreateIfMissing:  flagResumeCreateIfMissing,
 			ForceEndMismatch: flagResumeForce,
+			Limits:           sessionLimits(cfg),
 		})
 		if err != nil {
 			return err
 		}
 
 		out := output.New(output.Format(GetOutput()))
-		return out.Write(map[string]any{
+		result := map[string]any{
 			"session_id":     sess.ID,
 			"session_key":    sess.SessionKey,
 			"agent_name":     sess.AgentName,
 			"program":        sess.Program,
 			"model":          sess.Model,
 			"project_path":   sess.ProjectPath,
-			"started_at":     sess.StartedAt.Format(time.RFC3339),
-			"last_active_at": sess.LastActiveAt.Format(time.RFC3339),
-		})
+			"started_at":     timefmt.Format(sess.StartedAt),
+			"last_active_at": timefmt.Format(sess.LastActiveAt),
+		}
+		if backend := storeSessionKey(sess); backend != "" {
+			result["key_storage"] = backend
+		}
+		return out.Write(result)
 	},
 }
 
@@ -203,8 +234,8 @@ var sessionListCmd = &cobra.Command{
 				Program:     s.Program,
 				Model:       s.Model,
 				ProjectPath: s.ProjectPath,
-				StartedAt:   s.StartedAt.Format(time.RFC3339),
-				LastActive:  s.LastActiveAt.Format(time.RFC3339),
+				StartedAt:   timefmt.Format(s.StartedAt),
+				LastActive:  timefmt.Format(s.LastActiveAt),
 			})
 		}
 
@@ -233,7 +264,7 @@ var sessionHeartbeatCmd = &cobra.Command{
 		out := output.New(output.Format(GetOutput()))
 		return out.Write(map[string]any{
 			"session_id":     flagSessionID,
-			"last_active_at": time.Now().UTC().Format(time.RFC3339),
+			"last_active_at": timefmt.Format(time.Now()),
 		})
 	},
 }
@@ -261,7 +292,7 @@ var sessionResetLimitsCmd = &cobra.Command{
 		out := output.New(output.Format(GetOutput()))
 		return out.Write(map[string]any{
 			"session_id":          flagSessionID,
-			"rate_limit_reset_at": resetAt.Format(time.RFC3339),
+			"rate_limit_reset_at": timefmt.Format(resetAt),
 			"status":              "ok",
 		})
 	},
@@ -310,8 +341,8 @@ var sessionGcCmd = &cobra.Command{
 					Program:      s.Program,
 					Model:        s.Model,
 					ProjectPath:  s.ProjectPath,
-					StartedAt:    s.StartedAt.Format(time.RFC3339),
-					LastActiveAt: s.LastActiveAt.Format(time.RFC3339),
+					StartedAt:    timefmt.Format(s.StartedAt),
+					LastActiveAt: timefmt.Format(s.LastActiveAt),
 				})
 			}
 			return views
@@ -345,11 +376,11 @@ var sessionGcCmd = &cobra.Command{
 		}
 
 		// Optional interactive confirmation in human/text mode.
-		if GetOutput() != "json" && !flagSessionGCForce {
+		if !isJSONOutput() && !flagSessionGCForce {
 			headers := []string{"SESSION_ID", "AGENT", "PROGRAM", "MODEL", "LAST_ACTIVE_AT"}
 			rows := make([][]string, 0, len(candidates.Sessions))
 			for _, s := range candidates.Sessions {
-				rows = append(rows, []string{s.ID, s.AgentName, s.Program, s.Model, s.LastActiveAt.Format(time.RFC3339)})
+				rows = append(rows, []string{s.ID, s.AgentName, s.Program, s.Model, timefmt.Format(s.LastActiveAt)})
 			}
 			output.OutputTable(headers, rows)
 			fmt.Fprintln(os.Stderr)
@@ -374,6 +405,7 @@ var sessionGcCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
+		forgetSessionKeys(project, res.EndedIDs...)
 
 		out := output.New(output.Format(GetOutput()))
 		return out.Write(map[string]any{
@@ -391,6 +423,22 @@ var sessionGcCmd = &cobra.Command{
 	},
 }
 
+// sessionLimits returns the configured caps on active sessions, queueing
+// new sessions when rate_limits.session_limit_action is "queue".
+func sessionLimits(cfg config.Config) core.SessionLimits {
+	limits := core.SessionLimits{
+		MaxPerProject: cfg.RateLimits.MaxSessionsPerProject,
+		MaxPerAgent:   cfg.RateLimits.MaxSessionsPerAgent,
+	}
+	if cfg.RateLimits.SessionLimitAction == "queue" {
+		limits.QueueTimeout = time.Duration(cfg.RateLimits.SessionQueueTimeoutSecs) * time.Second
+		limits.OnQueued = func(reason error) {
+			fmt.Fprintf(os.Stderr, "%v; queued for up to %s\n", reason, limits.QueueTimeout)
+		}
+	}
+	return limits
+}
+
 func projectPath() (string, error) {
 	if flagProject != "" {
 		return flagProject, nil
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -179,6 +180,35 @@ func TestSessionStart_DuplicatePrevented(t *testing.T) {
 	}
 }
 
+func TestSessionStart_SessionLimit(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetSessionFlags()
+	configPath := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(configPath, []byte("[rate_limits]\nmax_sessions_per_project = 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	start := func(sub, agent string) error {
+		resetSessionFlags()
+		cmd := newTestSessionCmd(h.DBPath)
+		_, err := executeCommandCapture(t, cmd, "session", sub, "-a", agent, "-C", h.ProjectDir, "-c", configPath, "-j")
+		return err
+	}
+
+	if err := start("start", "AgentOne"); err != nil {
+		t.Fatalf("first session start failed: %v", err)
+	}
+	for _, sub := range []string{"start", "resume"} {
+		err := start(sub, "AgentTwo")
+		if err == nil || !strings.Contains(err.Error(), "session limit reached") {
+			t.Errorf("%s past the cap: expected a session limit error, got %v", sub, err)
+		}
+	}
+	if err := start("resume", "AgentOne"); err != nil {
+		t.Errorf("resuming the existing session: %v", err)
+	}
+}
+
 func TestSessionEnd_RequiresSessionID(t *testing.T) {
 	h := testutil.NewHarness(t)
 	resetSessionFlags()
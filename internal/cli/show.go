@@ -3,10 +3,10 @@ package cli
 
 import (
 	"fmt"
-	"time"
 
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
 	"github.com/spf13/cobra"
 )
 
@@ -79,13 +79,14 @@ This shows the full request details including:
 		}
 
 		type executionView struct {
-			LogPath             string `json:"log_path,omitempty"`
-			ExitCode            *int   `json:"exit_code,omitempty"`
-			DurationMs          *int64 `json:"duration_ms,omitempty"`
-			ExecutedAt          string `json:"executed_at,omitempty"`
-			ExecutedBySessionID string `json:"executed_by_session_id,omitempty"`
-			ExecutedByAgent     string `json:"executed_by_agent,omitempty"`
-			ExecutedByModel     string `json:"executed_by_model,omitempty"`
+			LogPath             string   `json:"log_path,omitempty"`
+			ExitCode            *int     `json:"exit_code,omitempty"`
+			DurationMs          *int64   `json:"duration_ms,omitempty"`
+			ExecutedAt          string   `json:"executed_at,omitempty"`
+			ExecutedBySessionID string   `json:"executed_by_session_id,omitempty"`
+			ExecutedByAgent     string   `json:"executed_by_agent,omitempty"`
+			ExecutedByModel     string   `json:"executed_by_model,omitempty"`
+			SecretsFound        []string `json:"secrets_found,omitempty"`
 		}
 
 		type rollbackView struct {
@@ -115,40 +116,57 @@ This shows the full request details including:
 			Output  string `json:"output,omitempty"`
 		}
 
+		type advisoryView struct {
+			Source         string `json:"source"`
+			Author         string `json:"author,omitempty"`
+			RiskSummary    string `json:"risk_summary"`
+			Recommendation string `json:"recommendation"`
+			CreatedAt      string `json:"created_at"`
+		}
+
 		type showView struct {
-			RequestID             string            `json:"request_id"`
-			ProjectPath           string            `json:"project_path"`
-			Command               commandView       `json:"command"`
-			RiskTier              string            `json:"risk_tier"`
-			Status                string            `json:"status"`
-			MinApprovals          int               `json:"min_approvals"`
-			RequireDifferentModel bool              `json:"require_different_model"`
-			RequestorSessionID    string            `json:"requestor_session_id"`
-			RequestorAgent        string            `json:"requestor_agent"`
-			RequestorModel        string            `json:"requestor_model"`
-			Justification         justificationView `json:"justification"`
-			DryRun                *dryRunView       `json:"dry_run,omitempty"`
-			Attachments           []attachmentView  `json:"attachments,omitempty"`
-			Reviews               []reviewView      `json:"reviews,omitempty"`
-			Execution             *executionView    `json:"execution,omitempty"`
-			Rollback              *rollbackView     `json:"rollback,omitempty"`
-			CreatedAt             string            `json:"created_at"`
-			ResolvedAt            string            `json:"resolved_at,omitempty"`
-			ExpiresAt             string            `json:"expires_at,omitempty"`
-			ApprovalExpiresAt     string            `json:"approval_expires_at,omitempty"`
+			RequestID             string                `json:"request_id"`
+			ProjectPath           string                `json:"project_path"`
+			Command               commandView           `json:"command"`
+			RiskTier              string                `json:"risk_tier"`
+			RiskScore             int                   `json:"risk_score"`
+			Status                string                `json:"status"`
+			MinApprovals          int                   `json:"min_approvals"`
+			RequireDifferentModel bool                  `json:"require_different_model"`
+			RequestorSessionID    string                `json:"requestor_session_id"`
+			RequestorAgent        string                `json:"requestor_agent"`
+			RequestorModel        string                `json:"requestor_model"`
+			Justification         justificationView     `json:"justification"`
+			Provenance            *db.RequestProvenance `json:"provenance,omitempty"`
+			Import                *db.RequestImport     `json:"import,omitempty"`
+			Binary                *db.RequestBinary     `json:"binary,omitempty"`
+			DryRun                *dryRunView           `json:"dry_run,omitempty"`
+			Attachments           []attachmentView      `json:"attachments,omitempty"`
+			Reviews               []reviewView          `json:"reviews,omitempty"`
+			Advisories            []advisoryView        `json:"advisories,omitempty"`
+			Execution             *executionView        `json:"execution,omitempty"`
+			Rollback              *rollbackView         `json:"rollback,omitempty"`
+			CreatedAt             string                `json:"created_at"`
+			ResolvedAt            string                `json:"resolved_at,omitempty"`
+			ExpiresAt             string                `json:"expires_at,omitempty"`
+			ApprovalExpiresAt     string                `json:"approval_expires_at,omitempty"`
+			Version               int                   `json:"version"`
 		}
 
 		view := showView{
 			RequestID:             request.ID,
 			ProjectPath:           request.ProjectPath,
 			RiskTier:              string(request.RiskTier),
+			RiskScore:             request.RiskScore,
 			Status:                string(request.Status),
 			MinApprovals:          request.MinApprovals,
 			RequireDifferentModel: request.RequireDifferentModel,
 			RequestorSessionID:    request.RequestorSessionID,
 			RequestorAgent:        request.RequestorAgent,
 			RequestorModel:        request.RequestorModel,
-			CreatedAt:             request.CreatedAt.Format(time.RFC3339),
+			CreatedAt:             timefmt.Format(request.CreatedAt),
+			Version:               request.Version,
+			Import:                request.Import,
 			Command: commandView{
 				Raw:               request.Command.Raw,
 				DisplayRedacted:   request.Command.DisplayRedacted,
@@ -168,13 +186,13 @@ This shows the full request details including:
 
 		// Timestamps
 		if request.ResolvedAt != nil {
-			view.ResolvedAt = request.ResolvedAt.Format(time.RFC3339)
+			view.ResolvedAt = timefmt.Format(*request.ResolvedAt)
 		}
 		if request.ExpiresAt != nil {
-			view.ExpiresAt = request.ExpiresAt.Format(time.RFC3339)
+			view.ExpiresAt = timefmt.Format(*request.ExpiresAt)
 		}
 		if request.ApprovalExpiresAt != nil {
-			view.ApprovalExpiresAt = request.ApprovalExpiresAt.Format(time.RFC3339)
+			view.ApprovalExpiresAt = timefmt.Format(*request.ApprovalExpiresAt)
 		}
 
 		// Dry run
@@ -197,10 +215,10 @@ This shows the full request details including:
 					Decision:          string(r.Decision),
 					Signature:         r.Signature,
 					Comments:          r.Comments,
-					CreatedAt:         r.CreatedAt.Format(time.RFC3339),
+					CreatedAt:         timefmt.Format(r.CreatedAt),
 				}
 				if !r.SignatureTimestamp.IsZero() {
-					rv.SignatureTime = r.SignatureTimestamp.Format(time.RFC3339)
+					rv.SignatureTime = timefmt.Format(r.SignatureTimestamp)
 				}
 				// Include responses if any field is non-empty
 				if r.Responses.ReasonResponse != "" || r.Responses.EffectResponse != "" ||
@@ -216,6 +234,31 @@ This shows the full request details including:
 			}
 		}
 
+		// Advisory annotations (never count as approvals)
+		annotations, err := dbConn.ListAnnotationsForRequest(request.ID)
+		if err != nil {
+			return fmt.Errorf("getting annotations: %w", err)
+		}
+		for _, a := range annotations {
+			view.Advisories = append(view.Advisories, advisoryView{
+				Source:         a.Source,
+				Author:         a.Author,
+				RiskSummary:    a.RiskSummary,
+				Recommendation: a.Recommendation,
+				CreatedAt:      timefmt.Format(a.CreatedAt),
+			})
+		}
+
+		// Provenance (which agent tool attempted the command)
+		if prov, err := dbConn.GetRequestProvenance(request.ID); err == nil {
+			view.Provenance = prov
+		}
+
+		// Executable the command would run
+		if bin, err := dbConn.GetRequestBinary(request.ID); err == nil {
+			view.Binary = bin
+		}
+
 		// Execution
 		if flagShowWithExecution && request.Execution != nil {
 			view.Execution = &executionView{
@@ -225,9 +268,10 @@ This shows the full request details including:
 				ExecutedBySessionID: request.Execution.ExecutedBySessionID,
 				ExecutedByAgent:     request.Execution.ExecutedByAgent,
 				ExecutedByModel:     request.Execution.ExecutedByModel,
+				SecretsFound:        request.Execution.SecretsFound,
 			}
 			if request.Execution.ExecutedAt != nil {
-				view.Execution.ExecutedAt = request.Execution.ExecutedAt.Format(time.RFC3339)
+				view.Execution.ExecutedAt = timefmt.Format(*request.Execution.ExecutedAt)
 			}
 		}
 
@@ -237,7 +281,7 @@ This shows the full request details including:
 				Path: request.Rollback.Path,
 			}
 			if request.Rollback.RolledBackAt != nil {
-				view.Rollback.RolledBackAt = request.Rollback.RolledBackAt.Format(time.RFC3339)
+				view.Rollback.RolledBackAt = timefmt.Format(*request.Rollback.RolledBackAt)
 			}
 		}
 
@@ -251,7 +295,12 @@ This shows the full request details including:
 				}
 				// Only include content if requested
 				if flagShowWithAttachments {
-					av.Content = a.Content
+					// Transcript snippets are stored compressed; show them as text.
+					if text, err := a.Text(); err == nil {
+						av.Content = text
+					} else {
+						av.Content = a.Content
+					}
 				}
 				view.Attachments = append(view.Attachments, av)
 			}
@@ -3,10 +3,10 @@ package cli
 
 import (
 	"fmt"
-	"time"
 
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
 	"github.com/spf13/cobra"
 )
 
@@ -21,14 +21,24 @@ func init() {
 }
 
 var statusCmd = &cobra.Command{
-	Use:   "status <request-id>",
-	Short: "Show status of a request",
-	Long: `Show the current status of a command approval request.
-
-Use --wait to block until the request reaches a terminal state
-(approved, rejected, cancelled, timeout, executed, etc).`,
-	Args: cobra.ExactArgs(1),
+	Use:   "status [request-id]",
+	Short: "Show an overview, or the status of a request",
+	Long: `Without a request ID, show an overview of the project: whether the
+daemon is up, pending requests by tier and the oldest one's age, active
+sessions, hook status, the pattern hash and the last execution.
+
+With a request ID, show the current status of that request. Use --wait
+to block until it reaches a terminal state (approved, rejected,
+cancelled, timeout, executed, etc); the exit code then reflects it
+(3 rejected, 4 timed out, 7 cancelled, 1 execution failed).`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			if flagStatusWait {
+				return fmt.Errorf("--wait needs a request ID")
+			}
+			return runStatusOverview()
+		}
 		requestID := args[0]
 
 		dbConn, err := db.Open(GetDB())
@@ -45,9 +55,14 @@ Use --wait to block until the request reaches a terminal state
 
 		// If wait is requested and status is pending, poll until resolved
 		if flagStatusWait && !request.Status.IsTerminal() {
-			// Simple polling - in production this would use daemon notifications
+			if request.Status == db.StatusPending {
+				defer markRequestorWaiting(dbConn, request.ID, flagSessionID)()
+			}
+			// The daemon pushes changes when it runs; otherwise this polls.
+			waiter := newDecisionWaiter(request.RequestorSessionID, request.ID)
+			defer waiter.Close()
 			for !request.Status.IsTerminal() {
-				time.Sleep(500 * time.Millisecond)
+				waiter.Wait(0)
 				request, reviews, err = dbConn.GetRequestWithReviews(requestID)
 				if err != nil {
 					return fmt.Errorf("polling request: %w", err)
@@ -107,7 +122,7 @@ Use --wait to block until the request reaches a terminal state
 			ExpectedEffect:        request.Justification.ExpectedEffect,
 			Goal:                  request.Justification.Goal,
 			SafetyArgument:        request.Justification.SafetyArgument,
-			CreatedAt:             request.CreatedAt.Format(time.RFC3339),
+			CreatedAt:             timefmt.Format(request.CreatedAt),
 			Reviews:               make([]reviewView, 0, len(reviews)),
 		}
 
@@ -115,13 +130,13 @@ Use --wait to block until the request reaches a terminal state
 			view.CommandRedacted = request.Command.DisplayRedacted
 		}
 		if request.ResolvedAt != nil {
-			view.ResolvedAt = request.ResolvedAt.Format(time.RFC3339)
+			view.ResolvedAt = timefmt.Format(*request.ResolvedAt)
 		}
 		if request.ExpiresAt != nil {
-			view.ExpiresAt = request.ExpiresAt.Format(time.RFC3339)
+			view.ExpiresAt = timefmt.Format(*request.ExpiresAt)
 		}
 		if request.ApprovalExpiresAt != nil {
-			view.ApprovalExpiresAt = request.ApprovalExpiresAt.Format(time.RFC3339)
+			view.ApprovalExpiresAt = timefmt.Format(*request.ApprovalExpiresAt)
 		}
 
 		// Count approvals and rejections, build review list
@@ -138,12 +153,15 @@ Use --wait to block until the request reaches a terminal state
 				Model:     r.ReviewerModel,
 				Decision:  string(r.Decision),
 				Comments:  r.Comments,
-				CreatedAt: r.CreatedAt.Format(time.RFC3339),
+				CreatedAt: timefmt.Format(r.CreatedAt),
 			}
 			view.Reviews = append(view.Reviews, rv)
 		}
 
 		out := output.New(output.Format(GetOutput()))
+		if flagStatusWait {
+			return withExitCode(statusExitCode(request.Status), out.Write(view))
+		}
 		return out.Write(view)
 	},
 }
@@ -0,0 +1,212 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/daemon"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/numfmt"
+	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
+)
+
+// statusOverview is what `slb status` shows without a request ID.
+type statusOverview struct {
+	ProjectPath string `json:"project_path"`
+	// Database is empty when the database is readable, otherwise why not.
+	Database       string          `json:"database_error,omitempty"`
+	Daemon         daemonOverview  `json:"daemon"`
+	PendingTotal   int             `json:"pending_total"`
+	PendingByTier  map[string]int  `json:"pending_by_tier"`
+	OldestPending  *pendingAgeView `json:"oldest_pending,omitempty"`
+	ActiveSessions []string        `json:"active_sessions"`
+	Hook           string          `json:"hook"`
+	PatternHash    string          `json:"pattern_hash"`
+	LastExecution  *lastExecView   `json:"last_execution,omitempty"`
+}
+
+type daemonOverview struct {
+	Running bool   `json:"running"`
+	PID     int    `json:"pid,omitempty"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+type pendingAgeView struct {
+	RequestID  string `json:"request_id"`
+	RiskTier   string `json:"risk_tier"`
+	CreatedAt  string `json:"created_at"`
+	AgeSeconds int64  `json:"age_seconds"`
+}
+
+type lastExecView struct {
+	RequestID  string `json:"request_id"`
+	Command    string `json:"command"`
+	Status     string `json:"status"`
+	ExitCode   *int   `json:"exit_code,omitempty"`
+	ExecutedBy string `json:"executed_by,omitempty"`
+	ExecutedAt string `json:"executed_at"`
+}
+
+// overviewTiers are the tiers that can be pending, most severe first.
+var overviewTiers = []db.RiskTier{db.RiskTierCritical, db.RiskTierDangerous, db.RiskTierCaution}
+
+func runStatusOverview() error {
+	project, err := projectPath()
+	if err != nil {
+		return err
+	}
+	view, err := buildStatusOverview(project, time.Now())
+	if err != nil {
+		return err
+	}
+	if GetOutput() != "text" {
+		return output.New(output.Format(GetOutput())).Write(view)
+	}
+	printStatusOverview(view, time.Now())
+	return nil
+}
+
+func buildStatusOverview(project string, now time.Time) (*statusOverview, error) {
+	view := &statusOverview{
+		ProjectPath:    project,
+		PendingByTier:  make(map[string]int, len(overviewTiers)),
+		ActiveSessions: []string{},
+	}
+	for _, tier := range overviewTiers {
+		view.PendingByTier[string(tier)] = 0
+	}
+
+	info := daemon.NewClient().GetStatusInfo()
+	view.Daemon = daemonOverview{Running: info.Status == daemon.DaemonRunning, PID: info.PID}
+	if !view.Daemon.Running {
+		view.Daemon.Detail = info.Status.String()
+		if msg := strings.TrimSpace(info.Message); msg != "" {
+			view.Daemon.Detail = msg
+		}
+	}
+
+	dbPath := GetDB()
+	var dbConn *db.DB
+	if _, err := os.Stat(dbPath); errors.Is(err, os.ErrNotExist) {
+		view.Database = dbPath + " does not exist (run 'slb init')"
+	} else if dbConn, err = db.OpenWithOptions(dbPath, db.OpenOptions{ReadOnly: true}); err != nil {
+		view.Database = err.Error()
+	} else {
+		defer dbConn.Close()
+		if _, err := loadCustomPatternsIntoDefaultEngine(); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		}
+	}
+	view.PatternHash = core.GetDefaultEngine().ComputeHash()
+	if hook, err := hookStatus(); err == nil {
+		view.Hook, _ = hook["status"].(string)
+	}
+	if dbConn == nil {
+		return view, nil
+	}
+
+	pending, err := dbConn.ListPendingRequests(project)
+	if err != nil {
+		return nil, err
+	}
+	view.PendingTotal = len(pending)
+	var oldest *db.Request
+	for _, r := range pending {
+		view.PendingByTier[string(r.RiskTier)]++
+		if oldest == nil || r.CreatedAt.Before(oldest.CreatedAt) {
+			oldest = r
+		}
+	}
+	if oldest != nil {
+		view.OldestPending = &pendingAgeView{
+			RequestID:  oldest.ID,
+			RiskTier:   string(oldest.RiskTier),
+			CreatedAt:  timefmt.Format(oldest.CreatedAt),
+			AgeSeconds: int64(now.Sub(oldest.CreatedAt).Seconds()),
+		}
+	}
+
+	sessions, err := dbConn.ListActiveSessions(project)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range sessions {
+		view.ActiveSessions = append(view.ActiveSessions, s.AgentName)
+	}
+
+	last, err := dbConn.LastExecutedRequest(project)
+	switch {
+	case errors.Is(err, db.ErrRequestNotFound):
+	case err != nil:
+		return nil, err
+	default:
+		command := last.Command.Raw
+		if last.Command.DisplayRedacted != "" {
+			command = last.Command.DisplayRedacted
+		}
+		view.LastExecution = &lastExecView{
+			RequestID:  last.ID,
+			Command:    command,
+			Status:     string(last.Status),
+			ExitCode:   last.Execution.ExitCode,
+			ExecutedBy: last.Execution.ExecutedByAgent,
+			ExecutedAt: timefmt.Format(*last.Execution.ExecutedAt),
+		}
+	}
+	return view, nil
+}
+
+func printStatusOverview(view *statusOverview, now time.Time) {
+	fmt.Printf("Project:  %s\n", view.ProjectPath)
+
+	if view.Daemon.Running {
+		fmt.Printf("Daemon:   running (pid %d)\n", view.Daemon.PID)
+	} else {
+		fmt.Printf("Daemon:   %s\n", view.Daemon.Detail)
+	}
+	if view.Database != "" {
+		fmt.Printf("Database: %s\n", view.Database)
+	}
+
+	tiers := make([]string, 0, len(overviewTiers))
+	for _, tier := range overviewTiers {
+		tiers = append(tiers, fmt.Sprintf("%s %s", tier, numfmt.Count(view.PendingByTier[string(tier)])))
+	}
+	fmt.Printf("Pending:  %s (%s)", numfmt.Count(view.PendingTotal), strings.Join(tiers, ", "))
+	if p := view.OldestPending; p != nil {
+		fmt.Printf(", oldest %s waiting %s", p.RequestID, numfmt.Duration(time.Duration(p.AgeSeconds)*time.Second))
+	}
+	fmt.Println()
+
+	if len(view.ActiveSessions) == 0 {
+		fmt.Println("Sessions: none active")
+	} else {
+		fmt.Printf("Sessions: %s active (%s)\n", numfmt.Count(len(view.ActiveSessions)), strings.Join(view.ActiveSessions, ", "))
+	}
+	fmt.Printf("Hook:     %s\n", strings.ReplaceAll(view.Hook, "_", " "))
+	fmt.Printf("Patterns: %s\n", shortHash(view.PatternHash))
+
+	if e := view.LastExecution; e != nil {
+		exit := ""
+		if e.ExitCode != nil {
+			exit = fmt.Sprintf(", exit %d", *e.ExitCode)
+		}
+		at, _ := time.Parse(time.RFC3339, e.ExecutedAt)
+		when := fmt.Sprintf("%s ago", now.Sub(at).Truncate(time.Second))
+		if timefmt.Current().IsAbsolute() {
+			when = "at " + e.ExecutedAt
+		}
+		fmt.Printf("Last run: %s %q (%s%s) %s", e.RequestID, e.Command, e.Status, exit, when)
+		if e.ExecutedBy != "" {
+			fmt.Printf(" by %s", e.ExecutedBy)
+		}
+		fmt.Println()
+	} else {
+		fmt.Println("Last run: none")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/Dicklesworthstone/slb/internal/testutil"
@@ -36,18 +37,89 @@ func resetStatusFlags() {
 	flagStatusWait = false
 }
 
-func TestStatusCommand_RequiresRequestID(t *testing.T) {
+func TestStatusCommand_Overview(t *testing.T) {
 	h := testutil.NewHarness(t)
 	resetStatusFlags()
+	t.Setenv("HOME", t.TempDir())
+
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir), testutil.WithAgent("BlueLake"))
+	critical := testutil.MakeRequest(t, h.DB, sess, testutil.WithRisk(db.RiskTierCritical))
+	testutil.MakeRequest(t, h.DB, sess, testutil.WithRisk(db.RiskTierDangerous))
+	h.DB.Exec(`UPDATE requests SET created_at = ? WHERE id = ?`, time.Now().Add(-time.Hour).UTC().Format(time.RFC3339), critical.ID)
+	ran := testutil.MakeRequest(t, h.DB, sess, testutil.WithCommand("make deploy", h.ProjectDir, true))
+	h.DB.UpdateRequestStatus(ran.ID, db.StatusApproved)
+	h.DB.UpdateRequestStatus(ran.ID, db.StatusExecuting)
+	h.DB.UpdateRequestStatus(ran.ID, db.StatusExecuted)
+	at, exit := time.Now().Add(-time.Minute), 0
+	if err := h.DB.UpdateRequestExecution(ran.ID, &db.Execution{ExecutedAt: &at, ExitCode: &exit, ExecutedByAgent: "BlueLake"}); err != nil {
+		t.Fatal(err)
+	}
 
 	cmd := newTestStatusCmd(h.DBPath)
-	_, _, err := executeCommand(cmd, "status")
+	stdout, err := executeCommandCapture(t, cmd, "status", "-C", h.ProjectDir, "-j")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var view statusOverview
+	if err := json.Unmarshal([]byte(stdout), &view); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if view.PendingTotal != 2 || view.PendingByTier["critical"] != 1 || view.PendingByTier["dangerous"] != 1 || view.PendingByTier["caution"] != 0 {
+		t.Errorf("pending = %d %v", view.PendingTotal, view.PendingByTier)
+	}
+	if view.OldestPending == nil || view.OldestPending.RequestID != critical.ID {
+		t.Errorf("oldest pending = %+v, want %s", view.OldestPending, critical.ID)
+	}
+	if len(view.ActiveSessions) != 1 || view.ActiveSessions[0] != "BlueLake" {
+		t.Errorf("active sessions = %v", view.ActiveSessions)
+	}
+	if view.Daemon.Running || view.Hook != "not_installed" || view.PatternHash == "" {
+		t.Errorf("daemon %+v, hook %q, pattern hash %q", view.Daemon, view.Hook, view.PatternHash)
+	}
+	if e := view.LastExecution; e == nil || e.RequestID != ran.ID || e.Command != "make deploy" || e.ExitCode == nil || *e.ExitCode != 0 {
+		t.Errorf("last execution = %+v", e)
+	}
 
-	if err == nil {
-		t.Fatal("expected error when request ID is missing")
+	resetStatusFlags()
+	cmd = newTestStatusCmd(h.DBPath)
+	stdout, err = executeCommandCapture(t, cmd, "status", "-C", h.ProjectDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
-	if !strings.Contains(err.Error(), "accepts 1 arg") {
-		t.Errorf("unexpected error message: %v", err)
+	for _, want := range []string{"Pending:  2 (critical 1, dangerous 1, caution 0), oldest " + critical.ID, "Sessions: 1 active (BlueLake)", "Hook:     not installed", `"make deploy" (executed, exit 0)`} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("text output missing %q:\n%s", want, stdout)
+		}
+	}
+}
+
+func TestStatusCommand_OverviewWithoutDatabase(t *testing.T) {
+	resetStatusFlags()
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+
+	cmd := newTestStatusCmd(dir + "/missing.db")
+	stdout, err := executeCommandCapture(t, cmd, "status", "-C", dir, "-j")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var view statusOverview
+	if err := json.Unmarshal([]byte(stdout), &view); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if !strings.Contains(view.Database, "slb init") || view.PendingTotal != 0 || view.LastExecution != nil {
+		t.Errorf("unexpected overview: %+v", view)
+	}
+}
+
+func TestStatusCommand_WaitRequiresRequestID(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetStatusFlags()
+
+	cmd := newTestStatusCmd(h.DBPath)
+	_, _, err := executeCommand(cmd, "status", "--wait")
+	if err == nil || !strings.Contains(err.Error(), "--wait needs a request ID") {
+		t.Errorf("unexpected error: %v", err)
 	}
 }
 
@@ -0,0 +1,359 @@
+package cli
+
+import (
+	"context"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/keyring"
+	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+const (
+	// sudoPassphraseAccount holds the passphrase hash in the keyring.
+	sudoPassphraseAccount = "sudo:passphrase"
+	sudoPBKDF2Iterations  = 600_000
+	sudoFIDO2Timeout      = 60 * time.Second
+	sudoMinPassphraseLen  = 8
+)
+
+// readSudoPassphrase prompts on the controlling terminal; tests replace it.
+var readSudoPassphrase = readPassphraseFromTTY
+
+func init() {
+	sudoCmd.AddCommand(sudoStatusCmd)
+	sudoCmd.AddCommand(sudoSetPassphraseCmd)
+	sudoCmd.AddCommand(sudoCheckCmd)
+	rootCmd.AddCommand(sudoCmd)
+}
+
+var sudoCmd = &cobra.Command{
+	Use:   "sudo",
+	Short: "Manage sudo mode (fresh authentication for approvals)",
+	Long: `With [sudo_mode] enabled = true, approving a request in one of the sudo_mode
+tiers (critical by default) requires fresh authentication at the reviewer's
+terminal, so approvals cannot come from unattended terminals or agents:
+
+  method = "passphrase"  type the passphrase set with 'slb sudo set-passphrase'
+  method = "fido2"       touch the hardware key; slb runs fido2_command, which
+                         must exit 0 once the key is touched
+
+The prompt is read from the controlling terminal, never from stdin. An
+authentication counts for max_age_seconds and is recorded on the review
+(auth_method, authenticated_at). Rejections never need it.`,
+}
+
+var sudoStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the sudo mode settings",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadKeyringConfig()
+		if err != nil {
+			return err
+		}
+		s := cfg.SudoMode
+		result := map[string]any{
+			"enabled":         s.Enabled,
+			"method":          s.Method,
+			"tiers":           s.Tiers,
+			"max_age_seconds": s.MaxAgeSecs,
+		}
+		if s.Method == "fido2" {
+			result["fido2_command"] = s.FIDO2Command
+		} else {
+			_, err := storedSudoPassphrase(cfg)
+			result["passphrase_set"] = err == nil
+		}
+		if GetOutput() != "text" {
+			return output.New(output.Format(GetOutput())).Write(result)
+		}
+		if !s.Enabled {
+			fmt.Println("Sudo mode: off")
+			return nil
+		}
+		fmt.Printf("Sudo mode: on for %s approvals (%s, fresh for %ds)\n", strings.Join(s.Tiers, ", "), s.Method, s.MaxAgeSecs)
+		if set, ok := result["passphrase_set"].(bool); ok && !set {
+			fmt.Println("No passphrase set; run 'slb sudo set-passphrase'.")
+		}
+		return nil
+	},
+}
+
+var sudoSetPassphraseCmd = &cobra.Command{
+	Use:   "set-passphrase",
+	Short: "Set or change the sudo mode passphrase",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadKeyringConfig()
+		if err != nil {
+			return err
+		}
+		store, err := openConfiguredKeyring()
+		if err != nil {
+			return err
+		}
+		if _, err := storedSudoPassphrase(cfg); err == nil {
+			current, err := readSudoPassphrase("Current sudo passphrase: ")
+			if err != nil {
+				return err
+			}
+			if err := verifySudoPassphrase(cfg, current); err != nil {
+				return err
+			}
+		}
+		passphrase, err := readSudoPassphrase("New sudo passphrase: ")
+		if err != nil {
+			return err
+		}
+		if len(passphrase) < sudoMinPassphraseLen {
+			return fmt.Errorf("passphrase must be at least %d characters", sudoMinPassphraseLen)
+		}
+		confirm, err := readSudoPassphrase("Repeat sudo passphrase: ")
+		if err != nil {
+			return err
+		}
+		if confirm != passphrase {
+			return errors.New("passphrases do not match")
+		}
+		encoded, err := hashSudoPassphrase(passphrase)
+		if err != nil {
+			return err
+		}
+		if err := store.Set(sudoPassphraseAccount, encoded); err != nil {
+			return fmt.Errorf("storing passphrase: %w", err)
+		}
+		return output.New(output.Format(GetOutput())).Write(map[string]any{
+			"status":  "passphrase set",
+			"backend": store.Name(),
+		})
+	},
+}
+
+var sudoCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Authenticate once to test the sudo mode setup",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadKeyringConfig()
+		if err != nil {
+			return err
+		}
+		auth, err := authenticateSudo(cfg)
+		if err != nil {
+			return err
+		}
+		return output.New(output.Format(GetOutput())).Write(map[string]any{
+			"status":           "authenticated",
+			"method":           auth.Method,
+			"authenticated_at": timefmt.Format(auth.At),
+		})
+	},
+}
+
+// freshAuthFor authenticates the reviewer when sudo mode covers approving a
+// request of tier, and returns nil when it does not.
+func freshAuthFor(cfg config.Config, tier db.RiskTier) (*core.FreshAuth, error) {
+	if !core.ReviewConfigFromConfig(cfg).RequiresFreshAuth(tier) {
+		return nil, nil
+	}
+	return authenticateSudo(cfg)
+}
+
+// authenticateSudo runs the configured sudo mode check.
+func authenticateSudo(cfg config.Config) (*core.FreshAuth, error) {
+	switch cfg.SudoMode.Method {
+	case "fido2":
+		if err := runFIDO2Command(cfg.SudoMode.FIDO2Command); err != nil {
+			return nil, err
+		}
+		return &core.FreshAuth{Method: "fido2", At: time.Now()}, nil
+	default:
+		if _, err := storedSudoPassphrase(cfg); err != nil {
+			return nil, err
+		}
+		passphrase, err := readSudoPassphrase("Sudo mode: passphrase to approve: ")
+		if err != nil {
+			return nil, err
+		}
+		if err := verifySudoPassphrase(cfg, passphrase); err != nil {
+			return nil, err
+		}
+		return &core.FreshAuth{Method: "passphrase", At: time.Now()}, nil
+	}
+}
+
+func storedSudoPassphrase(cfg config.Config) (string, error) {
+	store, err := keyring.OpenDefault(cfg.General.SessionKeyStore)
+	if err != nil {
+		return "", err
+	}
+	if store == nil {
+		return "", errors.New("sudo mode passphrase needs secret storage (general.session_key_store is off)")
+	}
+	encoded, err := store.Get(sudoPassphraseAccount)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", errors.New("no sudo passphrase set (run 'slb sudo set-passphrase' at your terminal)")
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading sudo passphrase: %w", err)
+	}
+	return encoded, nil
+}
+
+func verifySudoPassphrase(cfg config.Config, passphrase string) error {
+	encoded, err := storedSudoPassphrase(cfg)
+	if err != nil {
+		return err
+	}
+	ok, err := checkSudoPassphrase(encoded, passphrase)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: wrong passphrase", core.ErrFreshAuthRequired)
+	}
+	return nil
+}
+
+// hashSudoPassphrase encodes a passphrase as
+// pbkdf2-sha256$<iterations>$<salt>$<hash>.
+func hashSudoPassphrase(passphrase string) (string, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("generating salt: %w", err)
+	}
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, sudoPBKDF2Iterations, sha256.Size)
+	if err != nil {
+		return "", fmt.Errorf("hashing passphrase: %w", err)
+	}
+	return fmt.Sprintf("pbkdf2-sha256$%d$%s$%s", sudoPBKDF2Iterations,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+func checkSudoPassphrase(encoded, passphrase string) (bool, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 4 || parts[0] != "pbkdf2-sha256" {
+		return false, errors.New("stored sudo passphrase is malformed (run 'slb sudo set-passphrase')")
+	}
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil || iterations < 1 {
+		return false, errors.New("stored sudo passphrase is malformed (run 'slb sudo set-passphrase')")
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return false, errors.New("stored sudo passphrase is malformed (run 'slb sudo set-passphrase')")
+	}
+	want, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return false, errors.New("stored sudo passphrase is malformed (run 'slb sudo set-passphrase')")
+	}
+	got, err := pbkdf2.Key(sha256.New, passphrase, salt, iterations, len(want))
+	if err != nil {
+		return false, fmt.Errorf("hashing passphrase: %w", err)
+	}
+	return subtle.ConstantTimeCompare(got, want) == 1, nil
+}
+
+// readPassphraseFromTTY reads a passphrase from the controlling terminal
+// without echo. Reading the terminal rather than stdin keeps a piped or
+// scripted caller from answering the prompt.
+func readPassphraseFromTTY(prompt string) (string, error) {
+	tty, w, closeTTY, err := openTTY()
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", core.ErrFreshAuthRequired, err)
+	}
+	defer closeTTY()
+	fmt.Fprint(w, prompt)
+	secret, err := term.ReadPassword(int(tty.Fd()))
+	fmt.Fprintln(w)
+	if err != nil {
+		return "", fmt.Errorf("reading passphrase: %w", err)
+	}
+	return string(secret), nil
+}
+
+// openTTY opens the controlling terminal, so a prompt reaches the human at
+// the keyboard even when stdin and stdout are redirected. Agents running
+// without a terminal get an error.
+func openTTY() (in, out *os.File, closeTTY func(), err error) {
+	inName, outName := "/dev/tty", "/dev/tty"
+	if runtime.GOOS == "windows" {
+		inName, outName = "CONIN$", "CONOUT$"
+	}
+	in, err = os.OpenFile(inName, os.O_RDWR, 0)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("no terminal to prompt on (%v)", err)
+	}
+	if !term.IsTerminal(int(in.Fd())) {
+		in.Close()
+		return nil, nil, nil, errors.New("no terminal to prompt on")
+	}
+	out = in
+	if outName != inName {
+		if out, err = os.OpenFile(outName, os.O_WRONLY, 0); err != nil {
+			in.Close()
+			return nil, nil, nil, fmt.Errorf("no terminal to prompt on (%v)", err)
+		}
+	}
+	return in, out, func() {
+		if out != in {
+			out.Close()
+		}
+		in.Close()
+	}, nil
+}
+
+// runFIDO2Command runs the configured hardware key command with a random
+// challenge on stdin, attached to the terminal so its touch prompt shows.
+func runFIDO2Command(command string) error {
+	if strings.TrimSpace(command) == "" {
+		return errors.New("sudo_mode.fido2_command is not set")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), sudoFIDO2Timeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd.exe", "/C", command)
+	} else {
+		shell := strings.TrimSpace(os.Getenv("SHELL"))
+		if shell == "" {
+			shell = "/bin/sh"
+		}
+		cmd = exec.CommandContext(ctx, shell, "-c", command)
+	}
+	challenge := make([]byte, 32)
+	if _, err := rand.Read(challenge); err != nil {
+		return fmt.Errorf("generating challenge: %w", err)
+	}
+	cmd.Stdin = strings.NewReader("slb sudo " + hex.EncodeToString(challenge) + "\n")
+	cmd.Stdout = nil
+	cmd.Stderr = os.Stderr
+	fmt.Fprintln(os.Stderr, "Sudo mode: touch your security key to approve.")
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("%w: no key touch within %s", core.ErrFreshAuthRequired, sudoFIDO2Timeout)
+		}
+		return fmt.Errorf("%w: fido2 command failed: %v", core.ErrFreshAuthRequired, err)
+	}
+	return nil
+}
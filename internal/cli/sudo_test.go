@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+// fakePassphrases answers sudo prompts in order and records them.
+func fakePassphrases(t *testing.T, answers ...string) *[]string {
+	t.Helper()
+	var prompts []string
+	prev := readSudoPassphrase
+	readSudoPassphrase = func(prompt string) (string, error) {
+		prompts = append(prompts, prompt)
+		if len(answers) == 0 {
+			return "", errors.New("unexpected prompt: " + prompt)
+		}
+		answer := answers[0]
+		answers = answers[1:]
+		return answer, nil
+	}
+	t.Cleanup(func() { readSudoPassphrase = prev })
+	return &prompts
+}
+
+func TestSudoPassphraseHash(t *testing.T) {
+	encoded, err := hashSudoPassphrase("correct horse")
+	if err != nil {
+		t.Fatalf("hash: %v", err)
+	}
+	if !strings.HasPrefix(encoded, "pbkdf2-sha256$") || strings.Contains(encoded, "correct horse") {
+		t.Errorf("encoded = %q", encoded)
+	}
+	if ok, err := checkSudoPassphrase(encoded, "correct horse"); err != nil || !ok {
+		t.Errorf("check(right) = %v, %v", ok, err)
+	}
+	if ok, err := checkSudoPassphrase(encoded, "battery staple"); err != nil || ok {
+		t.Errorf("check(wrong) = %v, %v", ok, err)
+	}
+	if _, err := checkSudoPassphrase("plain", "x"); err == nil {
+		t.Error("expected an error for a malformed hash")
+	}
+}
+
+func TestFreshAuthFor(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.SudoMode.Enabled = true
+	cfg.SudoMode.Tiers = []string{"critical", " Dangerous "}
+	cfg.SudoMode.MaxAgeSecs = 30
+	if auth, err := freshAuthFor(cfg, db.RiskTierCaution); auth != nil || err != nil {
+		t.Errorf("freshAuthFor(caution) = %v, %v; want no prompt", auth, err)
+	}
+}
+
+func TestSudoSetPassphrase(t *testing.T) {
+	h := testutil.NewHarness(t)
+	store := useFileKeyring(t)
+	resetApproveFlags()
+	flagProject = h.ProjectDir
+
+	fakePassphrases(t, "short", "short")
+	if err := sudoSetPassphraseCmd.RunE(sudoSetPassphraseCmd, nil); err == nil || !strings.Contains(err.Error(), "at least") {
+		t.Fatalf("expected a length error, got %v", err)
+	}
+	fakePassphrases(t, "long enough", "different")
+	if err := sudoSetPassphraseCmd.RunE(sudoSetPassphraseCmd, nil); err == nil || !strings.Contains(err.Error(), "do not match") {
+		t.Fatalf("expected a mismatch error, got %v", err)
+	}
+	fakePassphrases(t, "long enough", "long enough")
+	if err := sudoSetPassphraseCmd.RunE(sudoSetPassphraseCmd, nil); err != nil {
+		t.Fatalf("set-passphrase: %v", err)
+	}
+	if stored, err := store.Get(sudoPassphraseAccount); err != nil || !strings.HasPrefix(stored, "pbkdf2-sha256$") {
+		t.Fatalf("stored = %q, %v", stored, err)
+	}
+
+	// Changing it needs the current passphrase.
+	prompts := fakePassphrases(t, "guess", "new passphrase", "new passphrase")
+	if err := sudoSetPassphraseCmd.RunE(sudoSetPassphraseCmd, nil); !errors.Is(err, core.ErrFreshAuthRequired) {
+		t.Fatalf("expected a wrong passphrase error, got %v", err)
+	}
+	if len(*prompts) != 1 || !strings.Contains((*prompts)[0], "Current") {
+		t.Errorf("prompts = %q", *prompts)
+	}
+}
+
+func TestApproveCommand_SudoMode(t *testing.T) {
+	h := testutil.NewHarness(t)
+	useFileKeyring(t)
+	t.Setenv("SLB_SUDO_MODE", "true")
+	resetApproveFlags()
+
+	requestor := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir), testutil.WithAgent("Requestor"), testutil.WithModel("model-a"))
+	reviewer := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir), testutil.WithAgent("Reviewer"), testutil.WithModel("model-b"))
+	req := testutil.MakeRequest(t, h.DB, requestor,
+		testutil.WithCommand("terraform destroy", h.ProjectDir, true),
+		testutil.WithRisk(db.RiskTierCritical),
+	)
+	h.DB.Exec(`UPDATE requests SET min_approvals = 1, require_different_model = false WHERE id = ?`, req.ID)
+
+	approve := func() error {
+		resetApproveFlags()
+		_, err := executeCommandCapture(t, newTestApproveCmd(h.DBPath), "approve", req.ID,
+			"--session-id", reviewer.ID, "-k", reviewer.SessionKey, "-C", h.ProjectDir, "-j")
+		return err
+	}
+
+	// No passphrase set yet.
+	fakePassphrases(t)
+	if err := approve(); err == nil || !strings.Contains(err.Error(), "set-passphrase") {
+		t.Fatalf("expected a missing passphrase error, got %v", err)
+	}
+
+	flagProject = h.ProjectDir
+	fakePassphrases(t, "long enough", "long enough")
+	if err := sudoSetPassphraseCmd.RunE(sudoSetPassphraseCmd, nil); err != nil {
+		t.Fatalf("set-passphrase: %v", err)
+	}
+
+	fakePassphrases(t, "wrong one")
+	if err := approve(); !errors.Is(err, core.ErrFreshAuthRequired) {
+		t.Fatalf("expected a wrong passphrase error, got %v", err)
+	}
+	if reviews, _ := h.DB.ListReviewsForRequest(req.ID); len(reviews) != 0 {
+		t.Fatalf("a failed authentication recorded %d reviews", len(reviews))
+	}
+
+	fakePassphrases(t, "long enough")
+	if err := approve(); err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	reviews, err := h.DB.ListReviewsForRequest(req.ID)
+	if err != nil || len(reviews) != 1 {
+		t.Fatalf("reviews = %v, %v", reviews, err)
+	}
+	if reviews[0].AuthMethod != "passphrase" || reviews[0].AuthenticatedAt == nil {
+		t.Errorf("review auth = %q at %v", reviews[0].AuthMethod, reviews[0].AuthenticatedAt)
+	}
+}
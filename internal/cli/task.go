@@ -0,0 +1,242 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
+	"github.com/spf13/cobra"
+)
+
+var flagTaskAllProjects bool
+
+func init() {
+	for _, c := range []*cobra.Command{taskShowCmd, taskListCmd} {
+		c.Flags().BoolVar(&flagTaskAllProjects, "all-projects", false, "include requests from every project")
+	}
+	taskCmd.AddCommand(taskShowCmd)
+	taskCmd.AddCommand(taskListCmd)
+	rootCmd.AddCommand(taskCmd)
+}
+
+var taskCmd = &cobra.Command{
+	Use:   "task",
+	Short: "Follow requests grouped under a task or ticket",
+	Long: `Requests created with --task OPS-123 (or with SLB_TASK=OPS-123 in the
+environment) are grouped under that task, so the steps of a multi-command
+migration can be followed together. The task is stored as the label
+task=OPS-123 and can be filtered on like any label.`,
+}
+
+var taskShowCmd = &cobra.Command{
+	Use:   "show <task-id>",
+	Short: "Show a task's requests, roll-up status and combined timeline",
+	Long: `Show every request in a task, oldest first, with the task's roll-up
+status and the lifecycle events of all its requests in one timeline.
+
+The roll-up is the first that applies: failed (an execution failed or timed
+out), pending (a request awaits review), in_progress (a request is approved
+or executing), rejected, done (everything not withdrawn executed), or
+cancelled.
+
+Examples:
+  slb task show OPS-123
+  slb task show OPS-123 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dbConn, err := db.Open(GetDB())
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer dbConn.Close()
+
+		task, err := core.LoadTask(dbConn, taskProject(), args[0])
+		if err != nil {
+			return err
+		}
+		view := buildTaskView(task)
+
+		if isJSONOutput() {
+			return output.New(output.Format(GetOutput())).Write(view)
+		}
+
+		fmt.Printf("Task %s: %s (%s)\n\n", view.Task, view.Status, formatTaskCounts(task.Counts))
+		fmt.Printf("%-36s %-16s %-9s %-16s %s\n", "REQUEST", "STATUS", "TIER", "AGENT", "COMMAND")
+		for _, r := range view.Requests {
+			fmt.Printf("%-36s %-16s %-9s %-16s %s\n", r.RequestID, r.Status, r.RiskTier, r.RequestorAgent, r.Command)
+		}
+
+		fmt.Println()
+		fmt.Println("Timeline:")
+		if len(task.Timeline) == 0 {
+			fmt.Println("  no events recorded")
+			return nil
+		}
+		times, now := timefmt.Current(), dbConn.Now()
+		for _, e := range task.Timeline {
+			line := fmt.Sprintf("  %-10s %s %s", times.Show(e.CreatedAt, now), e.RequestID, e.Type)
+			if e.Actor != "" {
+				line += " by " + e.Actor
+			}
+			if e.Details != "" {
+				line += ": " + e.Details
+			}
+			fmt.Println(line)
+		}
+		return nil
+	},
+}
+
+var taskListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List tasks with their roll-up status",
+	Long: `List the tasks requests are grouped under, most recently active first,
+with each task's roll-up status and request counts.
+
+Examples:
+  slb task list
+  slb task list --all-projects --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dbConn, err := db.Open(GetDB())
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer dbConn.Close()
+
+		tasks, err := dbConn.ListTasks(taskProject())
+		if err != nil {
+			return err
+		}
+
+		type taskSummary struct {
+			Task     string                   `json:"task"`
+			Status   string                   `json:"status"`
+			Requests int                      `json:"requests"`
+			Counts   map[db.RequestStatus]int `json:"counts"`
+			LastAt   string                   `json:"last_at"`
+		}
+		views := make([]taskSummary, 0, len(tasks))
+		for _, t := range tasks {
+			total := 0
+			for _, n := range t.Counts {
+				total += n
+			}
+			views = append(views, taskSummary{
+				Task:     t.Task,
+				Status:   core.TaskRollup(t.Counts),
+				Requests: total,
+				Counts:   t.Counts,
+				LastAt:   timefmt.Format(t.LastAt),
+			})
+		}
+
+		if isJSONOutput() {
+			return output.New(output.Format(GetOutput())).Write(map[string]any{"tasks": views})
+		}
+
+		if len(views) == 0 {
+			fmt.Println("No tasks. Group requests with --task <id> or SLB_TASK.")
+			return nil
+		}
+		now := time.Now()
+		fmt.Printf("%-20s %-12s %-10s %s\n", "TASK", "STATUS", "LAST", "REQUESTS")
+		for i, v := range views {
+			fmt.Printf("%-20s %-12s %-10s %s\n", v.Task, v.Status, timefmt.Current().Show(tasks[i].LastAt, now), formatTaskCounts(v.Counts))
+		}
+		return nil
+	},
+}
+
+// taskRequestView is one request in `slb task show`.
+type taskRequestView struct {
+	RequestID      string `json:"request_id"`
+	Status         string `json:"status"`
+	RiskTier       string `json:"risk_tier"`
+	RequestorAgent string `json:"requestor_agent"`
+	Command        string `json:"command"`
+	CreatedAt      string `json:"created_at"`
+	ResolvedAt     string `json:"resolved_at,omitempty"`
+	ExitCode       *int   `json:"exit_code,omitempty"`
+}
+
+// taskView is the output of `slb task show`.
+type taskView struct {
+	Task     string                   `json:"task"`
+	Status   string                   `json:"status"`
+	Counts   map[db.RequestStatus]int `json:"counts"`
+	Requests []taskRequestView        `json:"requests"`
+	Timeline []*db.RequestEvent       `json:"timeline"`
+}
+
+func buildTaskView(task *core.Task) taskView {
+	view := taskView{
+		Task:     task.ID,
+		Status:   task.Status,
+		Counts:   task.Counts,
+		Requests: make([]taskRequestView, 0, len(task.Requests)),
+		Timeline: task.Timeline,
+	}
+	if view.Timeline == nil {
+		view.Timeline = []*db.RequestEvent{}
+	}
+	for _, r := range task.Requests {
+		rv := taskRequestView{
+			RequestID:      r.ID,
+			Status:         string(r.Status),
+			RiskTier:       string(r.RiskTier),
+			RequestorAgent: r.RequestorAgent,
+			Command:        r.Command.Raw,
+			CreatedAt:      timefmt.Format(r.CreatedAt),
+		}
+		if r.Command.DisplayRedacted != "" {
+			rv.Command = r.Command.DisplayRedacted
+		}
+		if r.ResolvedAt != nil {
+			rv.ResolvedAt = timefmt.Format(*r.ResolvedAt)
+		}
+		if r.Execution != nil {
+			rv.ExitCode = r.Execution.ExitCode
+		}
+		view.Requests = append(view.Requests, rv)
+	}
+	return view
+}
+
+// taskProject is the project tasks are looked up in: the current one, or
+// all with --all-projects.
+func taskProject() string {
+	if flagTaskAllProjects {
+		return ""
+	}
+	project, err := projectPath()
+	if err != nil {
+		return ""
+	}
+	return project
+}
+
+// formatTaskCounts renders status counts as "3 requests: 2 executed, 1 pending".
+func formatTaskCounts(counts map[db.RequestStatus]int) string {
+	total := 0
+	statuses := make([]string, 0, len(counts))
+	for s, n := range counts {
+		total += n
+		statuses = append(statuses, string(s))
+	}
+	sort.Strings(statuses)
+	parts := make([]string, len(statuses))
+	for i, s := range statuses {
+		parts[i] = fmt.Sprintf("%d %s", counts[db.RequestStatus(s)], s)
+	}
+	noun := "requests"
+	if total == 1 {
+		noun = "request"
+	}
+	return fmt.Sprintf("%d %s: %s", total, noun, strings.Join(parts, ", "))
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+	"github.com/spf13/cobra"
+)
+
+func newTestTaskCmd(dbPath string) *cobra.Command {
+	root := &cobra.Command{Use: "slb", SilenceUsage: true, SilenceErrors: true}
+	root.PersistentFlags().StringVar(&flagDB, "db", dbPath, "database path")
+	root.PersistentFlags().StringVarP(&flagOutput, "output", "o", "text", "output format")
+	root.PersistentFlags().BoolVarP(&flagJSON, "json", "j", false, "json output")
+	root.PersistentFlags().StringVarP(&flagProject, "project", "C", "", "project directory")
+	task := &cobra.Command{Use: "task"}
+	show := &cobra.Command{Use: "show <task-id>", Args: cobra.ExactArgs(1), RunE: taskShowCmd.RunE}
+	list := &cobra.Command{Use: "list", Args: cobra.NoArgs, RunE: taskListCmd.RunE}
+	for _, c := range []*cobra.Command{show, list} {
+		c.Flags().BoolVar(&flagTaskAllProjects, "all-projects", false, "include every project")
+		task.AddCommand(c)
+	}
+	root.AddCommand(task)
+	return root
+}
+
+func TestTaskCommands(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetRequestFlags()
+	defer resetLabelFlags()
+	flagTaskAllProjects = false
+
+	sess := testutil.MakeSession(t, h.DB,
+		testutil.WithProject(h.ProjectDir),
+		testutil.WithAgent("Migrator"),
+	)
+
+	var ids []string
+	for _, command := range []string{"rm -rf ./migrations/old", "rm -rf ./migrations/tmp"} {
+		resetRequestFlags()
+		stdout, err := executeCommandCapture(t, newTestRequestCmd(h.DBPath), "request", command,
+			"-s", sess.ID, "-C", h.ProjectDir, "--task", "OPS-123", "-j")
+		testutil.RequireNoError(t, err, "request --task")
+		var created struct {
+			RequestID string            `json:"request_id"`
+			Labels    map[string]string `json:"labels"`
+		}
+		if err := json.Unmarshal([]byte(stdout), &created); err != nil {
+			t.Fatalf("parse request output: %v\n%s", err, stdout)
+		}
+		if created.Labels["task"] != "OPS-123" {
+			t.Fatalf("expected task label, got %v", created.Labels)
+		}
+		ids = append(ids, created.RequestID)
+	}
+
+	stdout, err := executeCommandCapture(t, newTestTaskCmd(h.DBPath), "task", "show", "OPS-123", "-C", h.ProjectDir, "-j")
+	testutil.RequireNoError(t, err, "task show")
+	var shown struct {
+		Task     string `json:"task"`
+		Status   string `json:"status"`
+		Requests []struct {
+			RequestID string `json:"request_id"`
+			Status    string `json:"status"`
+		} `json:"requests"`
+		Timeline []struct {
+			RequestID string `json:"request_id"`
+			Type      string `json:"type"`
+		} `json:"timeline"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &shown); err != nil {
+		t.Fatalf("parse task show output: %v\n%s", err, stdout)
+	}
+	if shown.Task != "OPS-123" || shown.Status != core.TaskPending {
+		t.Errorf("task = %q status %q, want OPS-123 pending", shown.Task, shown.Status)
+	}
+	if len(shown.Requests) != 2 || shown.Requests[0].RequestID != ids[0] || shown.Requests[1].RequestID != ids[1] {
+		t.Errorf("requests = %+v, want %v in order", shown.Requests, ids)
+	}
+	if len(shown.Timeline) < 2 {
+		t.Errorf("expected both requests' events in the timeline, got %+v", shown.Timeline)
+	}
+
+	stdout, err = executeCommandCapture(t, newTestTaskCmd(h.DBPath), "task", "show", "OPS-123", "-C", h.ProjectDir)
+	testutil.RequireNoError(t, err, "task show text")
+	for _, want := range []string{"Task OPS-123: pending (2 requests: 2 pending)", ids[0], "Timeline:", "created by Migrator"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("text output missing %q:\n%s", want, stdout)
+		}
+	}
+
+	stdout, err = executeCommandCapture(t, newTestTaskCmd(h.DBPath), "task", "list", "-C", h.ProjectDir, "-j")
+	testutil.RequireNoError(t, err, "task list")
+	var listed struct {
+		Tasks []struct {
+			Task     string `json:"task"`
+			Status   string `json:"status"`
+			Requests int    `json:"requests"`
+		} `json:"tasks"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &listed); err != nil {
+		t.Fatalf("parse task list output: %v\n%s", err, stdout)
+	}
+	if len(listed.Tasks) != 1 || listed.Tasks[0].Task != "OPS-123" || listed.Tasks[0].Requests != 2 || listed.Tasks[0].Status != core.TaskPending {
+		t.Errorf("task list = %+v", listed.Tasks)
+	}
+
+	if _, err := executeCommandCapture(t, newTestTaskCmd(h.DBPath), "task", "show", "OPS-404", "-C", h.ProjectDir); err == nil || !strings.Contains(err.Error(), "no requests found") {
+		t.Errorf("expected not-found error for unknown task, got %v", err)
+	}
+}
@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/integrations"
+	"github.com/Dicklesworthstone/slb/internal/output"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	telemetryCmd.AddCommand(telemetryStatusCmd)
+	telemetryCmd.AddCommand(telemetryPreviewCmd)
+	telemetryCmd.AddCommand(telemetrySendCmd)
+	rootCmd.AddCommand(telemetryCmd)
+}
+
+var telemetryCmd = &cobra.Command{
+	Use:   "telemetry",
+	Short: "Inspect the opt-in pattern effectiveness telemetry",
+	Long: `Telemetry is off unless [telemetry] enabled = true and an endpoint is set
+in config. When on, the daemon reports once per interval_hours how often each
+pattern's requests were approved, rejected, timed out or cancelled.
+
+Reports identify patterns only by SHA-256 hash and tier. Commands, paths,
+projects, agent names and request IDs are never sent. Use
+'slb telemetry preview' to see exactly what the next report contains.`,
+}
+
+var telemetryStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether telemetry is enabled and when it last reported",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadTelemetryConfig()
+		if err != nil {
+			return err
+		}
+		dbConn, err := db.Open(GetDB())
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer dbConn.Close()
+
+		status, err := integrations.GetTelemetryStatus(dbConn, cfg.Enabled, cfg.Endpoint)
+		if err != nil {
+			return err
+		}
+		if GetOutput() != "text" {
+			return output.New(output.Format(GetOutput())).Write(status)
+		}
+		fmt.Printf("Telemetry: %s\n", telemetryStatusLine(status))
+		if status.LastAttempt != nil && status.LastAttempt.Error != "" {
+			fmt.Printf("Last error: %s\n", status.LastAttempt.Error)
+		}
+		return nil
+	},
+}
+
+var telemetryPreviewCmd = &cobra.Command{
+	Use:   "preview",
+	Short: "Print the report the next send would contain",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dbConn, err := db.Open(GetDB())
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer dbConn.Close()
+
+		until := time.Now().UTC().Truncate(time.Second)
+		since, err := integrations.NextTelemetryPeriodStart(dbConn, until)
+		if err != nil {
+			return err
+		}
+		payload, err := integrations.BuildTelemetryPayload(dbConn, since, until)
+		if err != nil {
+			return err
+		}
+		if GetOutput() != "text" {
+			return output.New(output.Format(GetOutput())).Write(payload)
+		}
+		// The wire format is the point of a preview, so text output is the JSON too.
+		body, err := json.MarshalIndent(payload, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(body))
+		return nil
+	},
+}
+
+var telemetrySendCmd = &cobra.Command{
+	Use:   "send",
+	Short: "Send the pending report now instead of waiting for the daemon",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadTelemetryConfig()
+		if err != nil {
+			return err
+		}
+		if !cfg.Enabled {
+			return fmt.Errorf("telemetry is disabled (set [telemetry] enabled = true and endpoint in config)")
+		}
+		dbConn, err := db.Open(GetDB())
+		if err != nil {
+			return fmt.Errorf("opening database: %w", err)
+		}
+		defer dbConn.Close()
+
+		client := integrations.NewTelemetryClient(cfg.Endpoint, integrations.DefaultTelemetryTimeout)
+		report, err := integrations.SendTelemetryReport(context.Background(), dbConn, client, time.Now())
+		if err != nil {
+			return err
+		}
+		if GetOutput() != "text" {
+			return output.New(output.Format(GetOutput())).Write(report)
+		}
+		output.Notef(os.Stdout, "Reported %d pattern(s) for %s to %s\n", report.Patterns,
+			timefmt.Format(report.PeriodStart), timefmt.Format(report.PeriodEnd))
+		return nil
+	},
+}
+
+func loadTelemetryConfig() (config.TelemetryConfig, error) {
+	project, err := projectPath()
+	if err != nil {
+		return config.TelemetryConfig{}, err
+	}
+	cfg, err := config.Load(config.LoadOptions{ProjectDir: project, ConfigPath: flagConfig})
+	if err != nil {
+		return config.TelemetryConfig{}, fmt.Errorf("loading config: %w", err)
+	}
+	return cfg.Telemetry, nil
+}
+
+// telemetryStatusLine summarizes telemetry in one line for status displays.
+func telemetryStatusLine(status *integrations.TelemetryStatus) string {
+	if !status.Enabled {
+		return "disabled (opt-in; see 'slb telemetry --help')"
+	}
+	line := "enabled, reporting to " + status.Endpoint
+	switch {
+	case status.LastAttempt == nil:
+		line += ", nothing sent yet"
+	case status.LastAttempt.Error != "":
+		line += ", last attempt failed " + timefmt.Format(status.LastAttempt.SentAt)
+	default:
+		line += ", last sent " + timefmt.Format(status.LastAttempt.SentAt)
+	}
+	return line
+}
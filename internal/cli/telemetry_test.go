@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/integrations"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+	"github.com/spf13/cobra"
+)
+
+func newTestTelemetryCmd(dbPath string) *cobra.Command {
+	root := &cobra.Command{
+		Use:           "slb",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	root.PersistentFlags().StringVar(&flagDB, "db", dbPath, "database path")
+	root.PersistentFlags().StringVarP(&flagOutput, "output", "o", "text", "output format")
+	root.PersistentFlags().BoolVarP(&flagJSON, "json", "j", false, "json output")
+	root.PersistentFlags().StringVarP(&flagProject, "project", "C", "", "project directory")
+	root.PersistentFlags().StringVarP(&flagConfig, "config", "c", "", "config file")
+
+	telCmd := &cobra.Command{Use: "telemetry"}
+	telCmd.AddCommand(&cobra.Command{Use: "status", Args: cobra.NoArgs, RunE: telemetryStatusCmd.RunE})
+	telCmd.AddCommand(&cobra.Command{Use: "preview", Args: cobra.NoArgs, RunE: telemetryPreviewCmd.RunE})
+	telCmd.AddCommand(&cobra.Command{Use: "send", Args: cobra.NoArgs, RunE: telemetrySendCmd.RunE})
+	root.AddCommand(telCmd)
+	root.AddCommand(&cobra.Command{Use: "doctor", Args: cobra.NoArgs, RunE: doctorCmd.RunE})
+	return root
+}
+
+func resetTelemetryFlags() {
+	flagOutput = "text"
+	flagJSON = false
+	flagProject = ""
+	flagConfig = ""
+}
+
+// rejectedPatternRequest creates a rejected request classified by pattern.
+func rejectedPatternRequest(t *testing.T, h *testutil.Harness, pattern string) {
+	t.Helper()
+	// Resolved an hour ago: the current second belongs to the next report.
+	h.DB.SetClock(testutil.NewFakeClock(time.Now().UTC().Add(-time.Hour)))
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	req := testutil.MakeRequest(t, h.DB, sess,
+		testutil.WithCommand("terraform destroy -auto-approve", h.ProjectDir, true),
+		testutil.WithRisk(db.RiskTierCritical))
+	testutil.RequireNoError(t, h.DB.SetRequestPattern(&db.RequestPattern{
+		RequestID: req.ID, Tier: db.RiskTierCritical, Pattern: pattern,
+	}), "set pattern")
+	testutil.RequireNoError(t, h.DB.UpdateRequestStatus(req.ID, db.StatusRejected), "reject")
+}
+
+func TestTelemetryCommands_Disabled(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetTelemetryFlags()
+	t.Cleanup(resetTelemetryFlags)
+
+	stdout, err := executeCommandCapture(t, newTestTelemetryCmd(h.DBPath), "telemetry", "status", "-C", h.ProjectDir)
+	testutil.RequireNoError(t, err, "telemetry status")
+	if !strings.Contains(stdout, "Telemetry: disabled") {
+		t.Fatalf("unexpected status:\n%s", stdout)
+	}
+
+	_, err = executeCommandCapture(t, newTestTelemetryCmd(h.DBPath), "telemetry", "send", "-C", h.ProjectDir)
+	if err == nil || !strings.Contains(err.Error(), "telemetry is disabled") {
+		t.Fatalf("expected send to refuse while disabled, got %v", err)
+	}
+}
+
+func TestTelemetryCommands_PreviewAndSend(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetTelemetryFlags()
+	t.Cleanup(resetTelemetryFlags)
+
+	var received []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p integrations.TelemetryPayload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		for _, pat := range p.Patterns {
+			received = append(received, pat.PatternHash)
+		}
+	}))
+	defer srv.Close()
+
+	cfgPath := filepath.Join(t.TempDir(), "config.toml")
+	cfgBody := "[telemetry]\nenabled = true\nendpoint = \"" + srv.URL + "\"\n"
+	testutil.RequireNoError(t, os.WriteFile(cfgPath, []byte(cfgBody), 0o600), "write config")
+
+	rejectedPatternRequest(t, h, `^terraform\s+destroy`)
+
+	stdout, err := executeCommandCapture(t, newTestTelemetryCmd(h.DBPath), "telemetry", "preview", "-C", h.ProjectDir)
+	testutil.RequireNoError(t, err, "telemetry preview")
+	if !strings.Contains(stdout, integrations.HashPattern(`^terraform\s+destroy`)) || !strings.Contains(stdout, `"rejected": 1`) {
+		t.Fatalf("unexpected preview:\n%s", stdout)
+	}
+	if strings.Contains(stdout, "terraform") {
+		t.Fatalf("preview leaks the command:\n%s", stdout)
+	}
+
+	resetTelemetryFlags()
+	stdout, err = executeCommandCapture(t, newTestTelemetryCmd(h.DBPath), "telemetry", "send", "-C", h.ProjectDir, "-c", cfgPath)
+	testutil.RequireNoError(t, err, "telemetry send")
+	if !strings.Contains(stdout, "Reported 1 pattern(s)") {
+		t.Fatalf("unexpected send output:\n%s", stdout)
+	}
+	testutil.RequireLen(t, received, 1, "received patterns")
+
+	resetTelemetryFlags()
+	stdout, err = executeCommandCapture(t, newTestTelemetryCmd(h.DBPath), "telemetry", "status", "-C", h.ProjectDir, "-c", cfgPath, "-j")
+	testutil.RequireNoError(t, err, "telemetry status -j")
+	var status integrations.TelemetryStatus
+	if err := json.Unmarshal([]byte(stdout), &status); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	if !status.Enabled || status.LastSuccess == nil || status.LastSuccess.Patterns != 1 {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+}
+
+func TestDoctorCommand(t *testing.T) {
+	h := testutil.NewHarness(t)
+	resetTelemetryFlags()
+	t.Cleanup(resetTelemetryFlags)
+
+	stdout, err := executeCommandCapture(t, newTestTelemetryCmd(h.DBPath), "doctor", "-C", h.ProjectDir, "-j")
+	testutil.RequireNoError(t, err, "doctor -j")
+	var result struct {
+		OK     bool          `json:"ok"`
+		Checks []doctorCheck `json:"checks"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("failed to parse JSON: %v\nstdout: %s", err, stdout)
+	}
+	byName := map[string]doctorCheck{}
+	for _, c := range result.Checks {
+		byName[c.Name] = c
+	}
+	if !result.OK || byName["config"].Status != doctorOK || byName["database"].Status != doctorOK {
+		t.Fatalf("unexpected doctor result: %+v", result)
+	}
+	if !strings.Contains(byName["telemetry"].Detail, "disabled") {
+		t.Fatalf("expected telemetry status line, got %+v", byName["telemetry"])
+	}
+
+	resetTelemetryFlags()
+	badCfg := filepath.Join(t.TempDir(), "config.toml")
+	testutil.RequireNoError(t, os.WriteFile(badCfg, []byte("[telemetry]\nenabled = true\n"), 0o600), "write config")
+	stdout, err = executeCommandCapture(t, newTestTelemetryCmd(h.DBPath), "doctor", "-C", h.ProjectDir, "-c", badCfg)
+	if err == nil || !strings.Contains(stdout, "[fail] config") || !strings.Contains(stdout, "telemetry.endpoint") {
+		t.Fatalf("expected a failing config check, got err=%v\n%s", err, stdout)
+	}
+}
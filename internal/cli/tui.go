@@ -4,7 +4,11 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/core"
 	"github.com/Dicklesworthstone/slb/internal/tui"
+	"github.com/Dicklesworthstone/slb/internal/tui/theme"
+	"github.com/Dicklesworthstone/slb/internal/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -14,14 +18,16 @@ var (
 	flagTuiTheme          string
 	flagTuiSessionID      string
 	flagTuiSessionKey     string
+	flagTuiReadOnly       bool
 )
 
 func init() {
 	tuiCmd.Flags().BoolVar(&flagTuiNoMouse, "no-mouse", false, "disable mouse support")
 	tuiCmd.Flags().IntVar(&flagTuiRefreshSeconds, "refresh-interval", 5, "polling interval when no daemon (seconds)")
-	tuiCmd.Flags().StringVar(&flagTuiTheme, "theme", "", "override theme (mocha, macchiato, frappe, latte)")
+	tuiCmd.Flags().StringVar(&flagTuiTheme, "theme", "", "override theme (mocha, macchiato, frappe, latte, high-contrast)")
 	tuiCmd.Flags().StringVar(&flagTuiSessionID, "session-id", "", "session ID for approvals")
 	tuiCmd.Flags().StringVar(&flagTuiSessionKey, "session-key", "", "session key for approvals")
+	tuiCmd.Flags().BoolVar(&flagTuiReadOnly, "read-only", false, "spectator mode: observe the queue and history without approve/reject")
 
 	rootCmd.AddCommand(tuiCmd)
 }
@@ -33,16 +39,36 @@ var tuiCmd = &cobra.Command{
 
 If the daemon is running, live updates are streamed; otherwise polling is used.
 Providing --session-id and --session-key enables interactive approval/rejection.
+The key may be omitted when 'slb session start' stored it in the keyring.
+Use --read-only for stakeholders who should observe without acting: approve and
+reject are disabled in every view (even if session credentials are given) and
+the header shows a READ-ONLY badge.
+
+On large terminals (140x30 or bigger) a detail preview of the selected request
+is shown under the pending queue. Pane visibility and split sizes are saved to
+~/.slb/tui-layout.json.
 
 Key bindings:
   tab/shift+tab  Switch between panels
+  1-4            Focus agents / pending / activity / preview
   up/down (j/k)  Navigate within panels
   enter          View selected request details
+  +/-            Grow or shrink the focused pane
+  A / E / P      Toggle agents, activity, and preview panes
+  0              Reset the layout
   m              Pattern management
   H              History browser
   q              Quit
 
-Theme options: mocha (default), macchiato, frappe, latte`,
+Mouse (unless --no-mouse): click a row to select it and focus its pane,
+double-click a request to open its details, and use the scroll wheel to move
+through lists (the history browser turns pages at either end).
+
+With --no-color (or SLB_NO_COLOR / NO_COLOR set) the TUI drops colors and
+emoji: tiers and statuses are shown as ASCII labels such as [CRIT] and [PEND],
+and the selected row is marked with ">".
+
+Theme options: mocha (default), macchiato, frappe, latte, high-contrast`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Determine project path
 		projectPath, err := os.Getwd()
@@ -50,13 +76,28 @@ Theme options: mocha (default), macchiato, frappe, latte`,
 			return fmt.Errorf("getting working directory: %w", err)
 		}
 
+		themeName := flagTuiTheme
+		if themeName == "" && utils.PlainOutput() {
+			themeName = string(theme.FlavorHighContrast)
+		}
+
+		cfg, err := config.Load(config.LoadOptions{ProjectDir: projectPath, ConfigPath: flagConfig})
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+
 		opts := tui.Options{
 			ProjectPath:     projectPath,
-			Theme:           flagTuiTheme,
+			Theme:           themeName,
 			DisableMouse:    flagTuiNoMouse,
 			RefreshInterval: flagTuiRefreshSeconds,
 			SessionID:       flagTuiSessionID,
-			SessionKey:      flagTuiSessionKey,
+			SessionKey:      tuiSessionKey(),
+			ReadOnly:        flagTuiReadOnly,
+			ReviewConfig:    core.ReviewConfigFromConfig(cfg),
+			Authenticate: func() (*core.FreshAuth, error) {
+				return authenticateSudo(cfg)
+			},
 		}
 
 		if err := tui.RunWithOptions(opts); err != nil {
@@ -65,3 +106,11 @@ Theme options: mocha (default), macchiato, frappe, latte`,
 		return nil
 	},
 }
+
+// tuiSessionKey returns --session-key, or the key stored for --session-id.
+func tuiSessionKey() string {
+	if flagTuiSessionKey != "" {
+		return flagTuiSessionKey
+	}
+	return lookupSessionKey(flagTuiSessionID)
+}
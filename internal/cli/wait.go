@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"context"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/daemon"
+)
+
+const (
+	// waitPollInterval paces wait loops when no daemon pushes decisions.
+	waitPollInterval = 500 * time.Millisecond
+	// waitPushFallback is how often a wait loop re-reads the request even
+	// though the daemon pushes changes, in case a push was dropped.
+	waitPushFallback = 5 * time.Second
+)
+
+// decisionWaiter paces a loop waiting on requests. With a daemon running
+// it subscribes to the requests through watch_requests and wakes as soon
+// as one changes; otherwise, or if the daemon cannot watch the session, it
+// falls back to polling.
+type decisionWaiter struct {
+	events <-chan daemon.Event
+	cancel context.CancelFunc
+}
+
+// newDecisionWaiter watches sessionID's requests, or only requestIDs when
+// given. The caller must Close it.
+func newDecisionWaiter(sessionID string, requestIDs ...string) *decisionWaiter {
+	w := &decisionWaiter{cancel: func() {}}
+	if sessionID == "" || !daemon.NewClient().IsDaemonRunning() {
+		return w
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	client := daemon.NewIPCClient(daemon.DefaultSocketPath())
+	events, err := client.WatchRequests(ctx, sessionID, requestIDs)
+	if err != nil {
+		cancel()
+		_ = client.Close()
+		return w
+	}
+	w.events = events
+	w.cancel = func() {
+		cancel()
+		_ = client.Close()
+	}
+	return w
+}
+
+// Pushed reports whether the daemon pushes changes to this waiter.
+func (w *decisionWaiter) Pushed() bool {
+	return w.events != nil
+}
+
+// Wait blocks until a watched request may have changed, for at most max
+// (when positive).
+func (w *decisionWaiter) Wait(max time.Duration) {
+	interval := waitPollInterval
+	if w.events != nil {
+		interval = waitPushFallback
+	}
+	if max > 0 && max < interval {
+		interval = max
+	}
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	select {
+	case _, ok := <-w.events:
+		if !ok {
+			// The daemon went away; poll from now on.
+			w.events = nil
+		}
+	case <-timer.C:
+	}
+}
+
+// Close ends the subscription.
+func (w *decisionWaiter) Close() {
+	w.cancel()
+}
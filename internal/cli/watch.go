@@ -11,8 +11,10 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/Dicklesworthstone/slb/internal/core/statemachine"
 	"github.com/Dicklesworthstone/slb/internal/daemon"
 	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
 	"github.com/spf13/cobra"
 )
 
@@ -39,7 +41,7 @@ var watchCmd = &cobra.Command{
 	Long: `Stream pending request events in NDJSON format for programmatic consumption.
 
 This command is designed for AI agents that review and approve requests.
-Events are streamed as newline-delimited JSON objects.
+Events are streamed as newline-delimited JSON objects regardless of --output.
 
 If the daemon is running, events are received in real-time via IPC subscription.
 If the daemon is not running, the command falls back to polling the database.
@@ -295,7 +297,7 @@ func processPolledRequest(ctx context.Context, req *db.Request, enc *json.Encode
 			RiskTier:  string(req.RiskTier),
 			Command:   req.Command.DisplayRedacted,
 			Requestor: req.RequestorAgent,
-			CreatedAt: req.CreatedAt.Format(time.RFC3339),
+			CreatedAt: timefmt.Format(req.CreatedAt),
 		}
 		if req.Command.DisplayRedacted == "" {
 			event.Command = req.Command.Raw
@@ -439,7 +441,8 @@ func autoApproveCaution(ctx context.Context, requestID string) error {
 	}
 
 	if approvals >= request.MinApprovals {
-		if err := dbConn.UpdateRequestStatus(requestID, db.StatusApproved); err != nil {
+		in := statemachine.Input{Approvals: approvals, Actor: agent, Reason: "auto-approved CAUTION tier request"}
+		if _, err := statemachine.New(dbConn).Commit(requestID, db.StatusApproved, in); err != nil {
 			return fmt.Errorf("approving request: %w", err)
 		}
 	}
@@ -0,0 +1,31 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReal(t *testing.T) {
+	before := time.Now()
+	got := Real.Now()
+	if got.Before(before) || got.After(time.Now()) {
+		t.Fatalf("Real.Now() = %v, not between calls to time.Now", got)
+	}
+}
+
+func TestFunc(t *testing.T) {
+	fixed := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	if got := Func(func() time.Time { return fixed }).Now(); !got.Equal(fixed) {
+		t.Fatalf("Func.Now() = %v, want %v", got, fixed)
+	}
+}
+
+func TestOrReal(t *testing.T) {
+	if OrReal(nil) != Real {
+		t.Error("expected nil to fall back to Real")
+	}
+	c := Func(time.Now)
+	if _, ok := OrReal(c).(Func); !ok {
+		t.Error("expected a non-nil clock to be returned as is")
+	}
+}
@@ -1,5 +1,5 @@
 // Package config implements hierarchical configuration for SLB.
-// Precedence: defaults < user (~/.slb/config.toml) < project (.slb/config.toml) < env (SLB_*) < flags.
+// Precedence: defaults < user (~/.slb/config.toml) < project (.slb/config.toml) < profile < env (SLB_*) < flags.
 package config
 
 // Note: Additional imports will be added as needed during implementation.
@@ -14,6 +14,15 @@ type Config struct {
 	Patterns      PatternsConfig      `toml:"patterns" mapstructure:"patterns"`
 	Integrations  IntegrationsConfig  `toml:"integrations" mapstructure:"integrations"`
 	Agents        AgentsConfig        `toml:"agents" mapstructure:"agents"`
+
+	ExecutionWindows ExecutionWindowsConfig `toml:"execution_windows" mapstructure:"execution_windows"`
+	Telemetry        TelemetryConfig        `toml:"telemetry" mapstructure:"telemetry"`
+	SudoMode         SudoModeConfig         `toml:"sudo_mode" mapstructure:"sudo_mode"`
+	Anomaly          AnomalyConfig          `toml:"anomaly" mapstructure:"anomaly"`
+	Targets          TargetsConfig          `toml:"targets" mapstructure:"targets"`
+	Reviewers        ReviewersConfig        `toml:"reviewers" mapstructure:"reviewers"`
+	Jobs             JobsConfig             `toml:"jobs" mapstructure:"jobs"`
+	Isolation        IsolationConfig        `toml:"isolation" mapstructure:"isolation"`
 }
 
 // GeneralConfig holds core behavior knobs.
@@ -25,12 +34,26 @@ type GeneralConfig struct {
 	RequestTimeoutSecs        int      `toml:"request_timeout" mapstructure:"request_timeout"`
 	ApprovalTTLMins           int      `toml:"approval_ttl_minutes" mapstructure:"approval_ttl_minutes"`
 	ApprovalTTLCriticalMins   int      `toml:"approval_ttl_critical_minutes" mapstructure:"approval_ttl_critical_minutes"`
+	IdempotencyTTLMins        int      `toml:"idempotency_ttl_minutes" mapstructure:"idempotency_ttl_minutes"`
 	TimeoutAction             string   `toml:"timeout_action" mapstructure:"timeout_action"` // escalate | auto_reject | auto_approve_warn
 	EnableDryRun              bool     `toml:"enable_dry_run" mapstructure:"enable_dry_run"`
 	EnableRollbackCapture     bool     `toml:"enable_rollback_capture" mapstructure:"enable_rollback_capture"`
 	MaxRollbackSizeMB         int      `toml:"max_rollback_size_mb" mapstructure:"max_rollback_size_mb"`
 	CrossProjectReviews       bool     `toml:"cross_project_reviews" mapstructure:"cross_project_reviews"`
 	ReviewPool                []string `toml:"review_pool" mapstructure:"review_pool"`
+	BreakglassCooldownHours   int      `toml:"breakglass_cooldown_hours" mapstructure:"breakglass_cooldown_hours"`
+	BreakglassAckHours        int      `toml:"breakglass_ack_hours" mapstructure:"breakglass_ack_hours"`
+	Locale                    string   `toml:"locale" mapstructure:"locale"` // "" (detect from LANG) | en | es
+	// Timezone is the IANA zone timestamps are shown in; empty = local.
+	Timezone string `toml:"timezone" mapstructure:"timezone"`
+	// Timestamps is how the CLI and TUI show times: relative | absolute.
+	Timestamps string `toml:"timestamps" mapstructure:"timestamps"`
+	// SessionKeyStore is where `slb session start` keeps session keys so
+	// later commands can find them: auto | keyring | file | off.
+	SessionKeyStore string `toml:"session_key_store" mapstructure:"session_key_store"`
+	// PinnedEnv names environment variables recorded with each request;
+	// executing with different values needs a human --allow-drift.
+	PinnedEnv []string `toml:"pinned_env" mapstructure:"pinned_env"`
 }
 
 // DaemonConfig holds daemon process settings.
@@ -40,8 +63,34 @@ type DaemonConfig struct {
 	TCPAddr        string   `toml:"tcp_addr" mapstructure:"tcp_addr"`
 	TCPRequireAuth bool     `toml:"tcp_require_auth" mapstructure:"tcp_require_auth"`
 	TCPAllowedIPs  []string `toml:"tcp_allowed_ips" mapstructure:"tcp_allowed_ips"`
-	LogLevel       string   `toml:"log_level" mapstructure:"log_level"`
-	PIDFile        string   `toml:"pid_file" mapstructure:"pid_file"`
+	// WebAddr serves the browser review UI and its JSON API (empty = off);
+	// WebAllowedIPs limits which clients may connect.
+	WebAddr       string   `toml:"web_addr" mapstructure:"web_addr"`
+	WebAllowedIPs []string `toml:"web_allowed_ips" mapstructure:"web_allowed_ips"`
+	// AllowedPeerUsers and AllowedPeerGroups name the users and groups
+	// (or numeric uids and gids), besides the daemon's own user, that may
+	// connect to the Unix socket.
+	AllowedPeerUsers  []string `toml:"allowed_peer_users" mapstructure:"allowed_peer_users"`
+	AllowedPeerGroups []string `toml:"allowed_peer_groups" mapstructure:"allowed_peer_groups"`
+	// AllowedPeerAccess is what those users may do: "write" (default) or
+	// "read" for status, subscriptions and classification only.
+	AllowedPeerAccess string `toml:"allowed_peer_access" mapstructure:"allowed_peer_access"`
+	LogLevel          string `toml:"log_level" mapstructure:"log_level"`
+	PIDFile           string `toml:"pid_file" mapstructure:"pid_file"`
+	// ClientRequestsPerSecond and ClientRequestBurst rate-limit each IPC
+	// connection; ClientMaxSubscriptions caps its event subscriptions; and
+	// SessionRequestsPerMinute caps the requests naming one session across
+	// all connections. 0 turns a limit off, except ClientRequestBurst,
+	// where 0 means one second's worth of ClientRequestsPerSecond.
+	ClientRequestsPerSecond  int `toml:"client_requests_per_second" mapstructure:"client_requests_per_second"`
+	ClientRequestBurst       int `toml:"client_request_burst" mapstructure:"client_request_burst"`
+	ClientMaxSubscriptions   int `toml:"client_max_subscriptions" mapstructure:"client_max_subscriptions"`
+	SessionRequestsPerMinute int `toml:"session_requests_per_minute" mapstructure:"session_requests_per_minute"`
+	// SubscriberQueueSize is how many events each subscriber may fall
+	// behind by; SubscriberOverflow is what happens past that:
+	// "drop-oldest" (default) or "disconnect".
+	SubscriberQueueSize int    `toml:"subscriber_queue_size" mapstructure:"subscriber_queue_size"`
+	SubscriberOverflow  string `toml:"subscriber_overflow" mapstructure:"subscriber_overflow"`
 }
 
 // RateLimitConfig holds rate-limiting settings.
@@ -49,6 +98,16 @@ type RateLimitConfig struct {
 	MaxPendingPerSession int    `toml:"max_pending_per_session" mapstructure:"max_pending_per_session"`
 	MaxRequestsPerMinute int    `toml:"max_requests_per_minute" mapstructure:"max_requests_per_minute"`
 	RateLimitAction      string `toml:"rate_limit_action" mapstructure:"rate_limit_action"` // reject | queue | warn
+
+	// MaxSessionsPerProject caps active sessions in one project; 0 = unlimited.
+	MaxSessionsPerProject int `toml:"max_sessions_per_project" mapstructure:"max_sessions_per_project"`
+	// MaxSessionsPerAgent caps active sessions for one agent name across
+	// projects; 0 = unlimited.
+	MaxSessionsPerAgent int `toml:"max_sessions_per_agent" mapstructure:"max_sessions_per_agent"`
+	// SessionLimitAction is what session start/resume does at a cap:
+	// reject, or queue until a session ends (up to SessionQueueTimeoutSecs).
+	SessionLimitAction      string `toml:"session_limit_action" mapstructure:"session_limit_action"`
+	SessionQueueTimeoutSecs int    `toml:"session_queue_timeout_seconds" mapstructure:"session_queue_timeout_seconds"`
 }
 
 // NotificationsConfig holds notification settings.
@@ -56,7 +115,18 @@ type NotificationsConfig struct {
 	DesktopEnabled   bool   `toml:"desktop_enabled" mapstructure:"desktop_enabled"`
 	DesktopDelaySecs int    `toml:"desktop_delay_seconds" mapstructure:"desktop_delay_seconds"`
 	WebhookURL       string `toml:"webhook_url" mapstructure:"webhook_url"`
-	EmailEnabled     bool   `toml:"email_enabled" mapstructure:"email_enabled"`
+
+	// ReviewerInactivityMinutes pages a human when no agent reviewer has acted
+	// on a DANGEROUS or CRITICAL request within this window (0 disables).
+	ReviewerInactivityMinutes int `toml:"reviewer_inactivity_minutes" mapstructure:"reviewer_inactivity_minutes"`
+
+	// ReviewSLA*Minutes are how soon a request of each tier should get its
+	// first review (0 = no SLA). Breaches are flagged in review listings
+	// and stats, and paged to a human when ReviewSLAEscalate is set.
+	ReviewSLACriticalMinutes  int  `toml:"review_sla_critical_minutes" mapstructure:"review_sla_critical_minutes"`
+	ReviewSLADangerousMinutes int  `toml:"review_sla_dangerous_minutes" mapstructure:"review_sla_dangerous_minutes"`
+	ReviewSLACautionMinutes   int  `toml:"review_sla_caution_minutes" mapstructure:"review_sla_caution_minutes"`
+	ReviewSLAEscalate         bool `toml:"review_sla_escalate" mapstructure:"review_sla_escalate"`
 }
 
 // HistoryConfig holds history/audit persistence settings.
@@ -65,6 +135,9 @@ type HistoryConfig struct {
 	GitRepoPath   string `toml:"git_repo_path" mapstructure:"git_repo_path"`
 	RetentionDays int    `toml:"retention_days" mapstructure:"retention_days"`
 	AutoGitCommit bool   `toml:"auto_git_commit" mapstructure:"auto_git_commit"`
+	// ReconcileIntervalMinutes is how often the daemon backfills missing
+	// history repo artifacts for finished requests (0 disables).
+	ReconcileIntervalMinutes int `toml:"reconcile_interval_minutes" mapstructure:"reconcile_interval_minutes"`
 }
 
 // PatternsConfig defines tiers and patterns.
@@ -73,6 +146,8 @@ type PatternsConfig struct {
 	Dangerous PatternTierConfig `toml:"dangerous" mapstructure:"dangerous"`
 	Caution   PatternTierConfig `toml:"caution" mapstructure:"caution"`
 	Safe      PatternTierConfig `toml:"safe" mapstructure:"safe"`
+	// Packs lists optional pattern packs to enable (e.g. kubernetes, terraform).
+	Packs []string `toml:"packs" mapstructure:"packs"`
 }
 
 // PatternTierConfig represents configuration for a risk tier.
@@ -81,6 +156,9 @@ type PatternTierConfig struct {
 	DynamicQuorum           bool     `toml:"dynamic_quorum" mapstructure:"dynamic_quorum"`
 	DynamicQuorumFloor      int      `toml:"dynamic_quorum_floor" mapstructure:"dynamic_quorum_floor"`
 	AutoApproveDelaySeconds int      `toml:"auto_approve_delay_seconds" mapstructure:"auto_approve_delay_seconds"`
+	AutoApprove             bool     `toml:"auto_approve" mapstructure:"auto_approve"`                     // daemon approves pending requests after the delay
+	AutoApproveNotify       bool     `toml:"auto_approve_notify" mapstructure:"auto_approve_notify"`       // desktop notification on automatic approval
+	AutoApproveMaxScore     int      `toml:"auto_approve_max_score" mapstructure:"auto_approve_max_score"` // requests scoring higher wait for a reviewer; 0 = no limit
 	Patterns                []string `toml:"patterns" mapstructure:"patterns"`
 }
 
@@ -89,6 +167,39 @@ type IntegrationsConfig struct {
 	AgentMailEnabled   bool   `toml:"agent_mail_enabled" mapstructure:"agent_mail_enabled"`
 	AgentMailThread    string `toml:"agent_mail_thread" mapstructure:"agent_mail_thread"`
 	ClaudeHooksEnabled bool   `toml:"claude_hooks_enabled" mapstructure:"claude_hooks_enabled"`
+	// HookAutoRequest makes the Claude hook create a pending request for a
+	// command it blocks, so the agent gets a request ID to wait on.
+	HookAutoRequest bool `toml:"hook_auto_request" mapstructure:"hook_auto_request"`
+	// HookReuseWindowMins is how long after an executed request the hook
+	// lets the same session run the same command again without a new
+	// request; 0 disables reuse. HookReuseTiers lists the tiers it applies to.
+	HookReuseWindowMins int      `toml:"hook_reuse_window_minutes" mapstructure:"hook_reuse_window_minutes"`
+	HookReuseTiers      []string `toml:"hook_reuse_tiers" mapstructure:"hook_reuse_tiers"`
+
+	// LLM second-opinion reviewer (advisory only; never counts as an approval).
+	LLMReviewEnabled     bool   `toml:"llm_review_enabled" mapstructure:"llm_review_enabled"`
+	LLMReviewEndpoint    string `toml:"llm_review_endpoint" mapstructure:"llm_review_endpoint"`
+	LLMReviewModel       string `toml:"llm_review_model" mapstructure:"llm_review_model"`
+	LLMReviewTimeoutSecs int    `toml:"llm_review_timeout_seconds" mapstructure:"llm_review_timeout_seconds"`
+}
+
+// ExecutionWindowsConfig restricts when approved requests may execute.
+// Inside a restricted window, execution requires a human override.
+type ExecutionWindowsConfig struct {
+	Enabled       bool     `toml:"enabled" mapstructure:"enabled"`
+	QuietHours    string   `toml:"quiet_hours" mapstructure:"quiet_hours"` // "HH:MM-HH:MM", may wrap midnight
+	BlockWeekends bool     `toml:"block_weekends" mapstructure:"block_weekends"`
+	Tiers         []string `toml:"tiers" mapstructure:"tiers"`       // risk tiers the windows apply to
+	Timezone      string   `toml:"timezone" mapstructure:"timezone"` // IANA name; empty = local time
+}
+
+// TelemetryConfig controls the opt-in report of anonymized pattern-match
+// statistics. Reports carry pattern hashes, tiers and decision counts only;
+// commands, paths and agent names are never sent.
+type TelemetryConfig struct {
+	Enabled       bool   `toml:"enabled" mapstructure:"enabled"`
+	Endpoint      string `toml:"endpoint" mapstructure:"endpoint"`
+	IntervalHours int    `toml:"interval_hours" mapstructure:"interval_hours"`
 }
 
 // AgentsConfig holds agent-specific allow/deny lists.
@@ -96,4 +207,108 @@ type AgentsConfig struct {
 	TrustedSelfApprove          []string `toml:"trusted_self_approve" mapstructure:"trusted_self_approve"`
 	TrustedSelfApproveDelaySecs int      `toml:"trusted_self_approve_delay_seconds" mapstructure:"trusted_self_approve_delay_seconds"`
 	Blocked                     []string `toml:"blocked" mapstructure:"blocked"`
+	// TierOverrides adjust the tier of commands by the requestor's program
+	// or model, the branch they run on or the stage they deploy to, as
+	// SELECTOR=ACTION: "codex-cli=dangerous" (at least dangerous),
+	// "model:gpt-4o*=critical", "branch:main=critical",
+	// "stage:production=critical", "shell=skip_caution".
+	TierOverrides []string `toml:"tier_overrides" mapstructure:"tier_overrides"`
+}
+
+// SudoModeConfig makes approvals of the listed tiers require fresh
+// authentication at the reviewer's terminal, a passphrase or a hardware key
+// touch, so unattended terminals and agents cannot approve them.
+type SudoModeConfig struct {
+	Enabled bool     `toml:"enabled" mapstructure:"enabled"`
+	Method  string   `toml:"method" mapstructure:"method"` // passphrase | fido2
+	Tiers   []string `toml:"tiers" mapstructure:"tiers"`
+	// FIDO2Command runs for method "fido2" and must exit 0 once the key is
+	// touched, e.g. "ssh-keygen -Y sign -n slb-sudo -f ~/.ssh/id_ed25519_sk".
+	FIDO2Command string `toml:"fido2_command" mapstructure:"fido2_command"`
+	// MaxAgeSecs is how long an authentication stays fresh.
+	MaxAgeSecs int `toml:"max_age_seconds" mapstructure:"max_age_seconds"`
+}
+
+// AnomalyConfig flags commands that break from a project's history: a burst
+// of one command family from a session, or a family never seen before in
+// the project. Flagged commands have their tier raised and carry a note.
+type AnomalyConfig struct {
+	Enabled bool `toml:"enabled" mapstructure:"enabled"`
+	// BurstWindowSecs and BurstThreshold flag a session issuing at least
+	// BurstThreshold commands of one family within the window.
+	BurstWindowSecs int `toml:"burst_window_seconds" mapstructure:"burst_window_seconds"`
+	BurstThreshold  int `toml:"burst_threshold" mapstructure:"burst_threshold"`
+	// NovelFamily flags the first command of a family in a project once the
+	// project has at least MinHistory observations.
+	NovelFamily bool `toml:"novel_family" mapstructure:"novel_family"`
+	MinHistory  int  `toml:"min_history" mapstructure:"min_history"`
+	// BaselineDays is how long observations are kept.
+	BaselineDays int `toml:"baseline_days" mapstructure:"baseline_days"`
+}
+
+// TargetsConfig controls how the remote hosts a command acts on (ssh and
+// ansible hosts, mutating HTTP requests, database servers) are judged.
+// Commands aimed at a production host are raised a tier.
+type TargetsConfig struct {
+	// ProductionHosts are glob patterns matched against each target host
+	// and each of its dot-separated labels ("prod", "*-prod", "db1.example.com").
+	// An empty list flags nothing.
+	ProductionHosts []string `toml:"production_hosts" mapstructure:"production_hosts"`
+}
+
+// ReviewersConfig shapes what `slb review show` hands agent reviewers, so
+// reviewers that are LLMs with a limited context behave predictably.
+type ReviewersConfig struct {
+	// Instructions are shown to reviewers with every request.
+	Instructions string `toml:"instructions" mapstructure:"instructions"`
+	// TokenBudget caps the estimated tokens a request costs to review;
+	// attachments, the transcript and dry-run output are cut to fit and
+	// the cuts are noted. 0 = no budget.
+	TokenBudget int `toml:"token_budget" mapstructure:"token_budget"`
+	// QRURL is a review page URL for `slb review show --qr`, with {id}
+	// replaced by the request ID, such as the daemon web UI's
+	// /requests/{id}. Empty puts a plain-text summary of the request in the
+	// code instead.
+	QRURL string `toml:"qr_url" mapstructure:"qr_url"`
+}
+
+// JobsConfig turns the daemon's recurring jobs on and off and sets when
+// they run.
+type JobsConfig struct {
+	// ExpirySweep moves pending requests past their timeout along per
+	// general.timeout_action.
+	ExpirySweep JobConfig `toml:"expiry_sweep" mapstructure:"expiry_sweep"`
+	// ReviewReminders reminds reviewers of requests still pending.
+	ReviewReminders JobConfig `toml:"review_reminders" mapstructure:"review_reminders"`
+	// RetentionPrune deletes daemon events older than history.retention_days.
+	RetentionPrune JobConfig `toml:"retention_prune" mapstructure:"retention_prune"`
+	// WeeklyDigest writes the week's `slb report weekly` to .slb/reports.
+	WeeklyDigest JobConfig `toml:"weekly_digest" mapstructure:"weekly_digest"`
+
+	// ReminderMinutes is how long a request waits before its first review
+	// reminder; each later one waits twice as long as the one before, up
+	// to ReminderMaxMinutes.
+	ReminderMinutes    int `toml:"reminder_minutes" mapstructure:"reminder_minutes"`
+	ReminderMaxMinutes int `toml:"reminder_max_minutes" mapstructure:"reminder_max_minutes"`
+}
+
+// IsolationConfig runs approved commands away from the invoking user's
+// host state: as another low-privilege account (via sudo -n), or in a
+// throwaway container with only the project mounted. User mode changes the
+// account, not what the command can reach; only container mode confines it
+// to the project. emergency-execute and break-glass runs are not isolated.
+type IsolationConfig struct {
+	Mode    string `toml:"mode" mapstructure:"mode"`       // none | user | container
+	User    string `toml:"user" mapstructure:"user"`       // required for user mode; container --user (default: caller's uid:gid)
+	Runtime string `toml:"runtime" mapstructure:"runtime"` // docker | podman
+	Image   string `toml:"image" mapstructure:"image"`     // required for container mode
+	Network bool   `toml:"network" mapstructure:"network"` // give the container network access
+}
+
+// JobConfig enables one daemon job and sets its schedule: an interval
+// such as "10s" or "1h", or a five-field cron expression such as
+// "0 9 * * 1", in local time.
+type JobConfig struct {
+	Enabled  bool   `toml:"enabled" mapstructure:"enabled"`
+	Schedule string `toml:"schedule" mapstructure:"schedule"`
 }
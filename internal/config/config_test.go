@@ -1,6 +1,7 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -23,18 +24,27 @@ func TestValidate_Errors(t *testing.T) {
 	cfg.General.RequestTimeoutSecs = 0
 	cfg.General.ApprovalTTLMins = 0
 	cfg.General.ApprovalTTLCriticalMins = 0
+	cfg.General.IdempotencyTTLMins = 0
 	cfg.General.MaxRollbackSizeMB = -1
 	cfg.General.ConflictResolution = "bad"
 	cfg.General.TimeoutAction = "bad"
+	cfg.General.SessionKeyStore = "vault"
 	cfg.RateLimits.MaxPendingPerSession = -1
 	cfg.RateLimits.MaxRequestsPerMinute = -1
 	cfg.RateLimits.RateLimitAction = "bad"
 	cfg.Notifications.DesktopDelaySecs = -1
+	cfg.Notifications.ReviewerInactivityMinutes = -1
 	cfg.History.RetentionDays = -1
 	cfg.Patterns.Critical.MinApprovals = -1
 	cfg.Patterns.Dangerous.DynamicQuorumFloor = -1
 	cfg.Patterns.Caution.AutoApproveDelaySeconds = -1
 	cfg.Agents.TrustedSelfApproveDelaySecs = -1
+	cfg.Integrations.HookReuseWindowMins = -1
+	cfg.Telemetry.IntervalHours = 0
+	cfg.Daemon.AllowedPeerUsers = []string{" "}
+	cfg.Daemon.AllowedPeerAccess = "admin"
+	cfg.Daemon.SubscriberQueueSize = 0
+	cfg.Daemon.SubscriberOverflow = "block"
 
 	err := Validate(cfg)
 	if err == nil {
@@ -45,6 +55,350 @@ func TestValidate_Errors(t *testing.T) {
 	}
 }
 
+func TestValidate_LLMReview(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Integrations.LLMReviewEnabled = true
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "llm_review_endpoint") {
+		t.Fatalf("expected endpoint validation error, got %v", err)
+	}
+
+	cfg.Integrations.LLMReviewEndpoint = "http://127.0.0.1:9999/review"
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg.Integrations.LLMReviewTimeoutSecs = -1
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "llm_review_timeout_seconds") {
+		t.Fatalf("expected timeout validation error, got %v", err)
+	}
+}
+
+func TestValidate_ReviewSLA(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.Notifications.ReviewSLADangerousMinutes != 10 || cfg.Notifications.ReviewSLAEscalate {
+		t.Fatalf("unexpected review SLA defaults: %+v", cfg.Notifications)
+	}
+	cfg.Notifications.ReviewSLACautionMinutes = -1
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "review_sla") {
+		t.Fatalf("expected review SLA validation error, got %v", err)
+	}
+}
+
+func TestValidate_AutoApproveMaxScore(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Patterns.Caution.AutoApproveMaxScore = 40
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, score := range []int{-1, 101} {
+		cfg.Patterns.Caution.AutoApproveMaxScore = score
+		if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "patterns.caution.auto_approve_max_score") {
+			t.Fatalf("expected auto_approve_max_score error for %d, got %v", score, err)
+		}
+	}
+}
+
+func TestValidate_AutoApproveHighTiers(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Patterns.Critical.AutoApprove = true
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "patterns.critical.auto_approve") {
+		t.Fatalf("expected critical auto_approve error, got %v", err)
+	}
+
+	cfg = DefaultConfig()
+	cfg.Patterns.Dangerous.AutoApprove = true
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "patterns.dangerous.auto_approve") {
+		t.Fatalf("expected dangerous auto_approve error, got %v", err)
+	}
+}
+
+func TestValidate_ExecutionWindows(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ExecutionWindows.Enabled = true
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg.ExecutionWindows.QuietHours = "10pm-6am"
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "quiet_hours") {
+		t.Fatalf("expected quiet_hours validation error, got %v", err)
+	}
+
+	cfg = DefaultConfig()
+	cfg.ExecutionWindows.Enabled = true
+	cfg.ExecutionWindows.Tiers = []string{"safe"}
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "execution_windows.tiers") {
+		t.Fatalf("expected tiers validation error, got %v", err)
+	}
+
+	cfg = DefaultConfig()
+	cfg.Integrations.HookReuseTiers = []string{"safe"}
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "hook_reuse_tiers") {
+		t.Fatalf("expected hook_reuse_tiers validation error, got %v", err)
+	}
+
+	cfg = DefaultConfig()
+	cfg.ExecutionWindows.Enabled = true
+	cfg.ExecutionWindows.Timezone = "Mars/Olympus"
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "execution_windows.timezone") {
+		t.Fatalf("expected timezone validation error, got %v", err)
+	}
+}
+
+func TestValidate_Isolation(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Isolation.Mode = "user"
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "isolation.user") {
+		t.Fatalf("expected isolation.user validation error, got %v", err)
+	}
+	cfg.Isolation.User = "slb-runner"
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg.Isolation.Mode = "container"
+	cfg.Isolation.Runtime = "lxc"
+	err := Validate(cfg)
+	for _, want := range []string{"isolation.runtime", "isolation.image"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %s validation error, got %v", want, err)
+		}
+	}
+	cfg.Isolation.Runtime = "podman"
+	cfg.Isolation.Image = "alpine:3.20"
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg.Isolation.Mode = "vm"
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "isolation.mode") {
+		t.Fatalf("expected isolation.mode validation error, got %v", err)
+	}
+}
+
+func TestValidate_SudoMode(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.SudoMode.Enabled = true
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg.SudoMode.Method = "fido2"
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "sudo_mode.fido2_command") {
+		t.Fatalf("expected fido2_command validation error, got %v", err)
+	}
+	cfg.SudoMode.FIDO2Command = "ssh-keygen -Y sign -n slb-sudo -f key_sk"
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg.SudoMode.Method = "smartcard"
+	cfg.SudoMode.Tiers = []string{"safe"}
+	cfg.SudoMode.MaxAgeSecs = 0
+	err := Validate(cfg)
+	for _, want := range []string{"sudo_mode.method", "sudo_mode.tiers", "sudo_mode.max_age_seconds"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %s validation error, got %v", want, err)
+		}
+	}
+
+	// Settings are not checked while sudo mode is off.
+	cfg.SudoMode.Enabled = false
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("unexpected error with sudo mode off: %v", err)
+	}
+}
+
+func TestValidate_PinnedEnv(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.General.PinnedEnv = []string{"KUBECONFIG", "_private", "AWS_REGION2"}
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, bad := range []string{"", "2FA", "AWS-PROFILE", "PATH=/bin"} {
+		cfg.General.PinnedEnv = []string{bad}
+		if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "general.pinned_env") {
+			t.Errorf("%q: expected pinned_env validation error, got %v", bad, err)
+		}
+	}
+}
+
+func TestValidate_SessionLimits(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.RateLimits.MaxSessionsPerProject = 4
+	cfg.RateLimits.MaxSessionsPerAgent = 2
+	cfg.RateLimits.SessionLimitAction = "queue"
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg.RateLimits.MaxSessionsPerProject = -1
+	cfg.RateLimits.MaxSessionsPerAgent = -1
+	cfg.RateLimits.SessionQueueTimeoutSecs = 0
+	err := Validate(cfg)
+	for _, want := range []string{"rate_limits.max_sessions_per_project", "rate_limits.max_sessions_per_agent", "rate_limits.session_queue_timeout_seconds"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %s validation error, got %v", want, err)
+		}
+	}
+
+	cfg = DefaultConfig()
+	cfg.RateLimits.SessionLimitAction = "warn"
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "rate_limits.session_limit_action") {
+		t.Errorf("expected session_limit_action validation error, got %v", err)
+	}
+}
+
+func TestValidate_Anomaly(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Anomaly.Enabled = true
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg.Anomaly.BurstWindowSecs = 0
+	cfg.Anomaly.BurstThreshold = 1
+	cfg.Anomaly.MinHistory = -1
+	cfg.Anomaly.BaselineDays = 0
+	err := Validate(cfg)
+	for _, want := range []string{"anomaly.burst_window_seconds", "anomaly.burst_threshold", "anomaly.min_history", "anomaly.baseline_days"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %s validation error, got %v", want, err)
+		}
+	}
+
+	cfg.Anomaly.Enabled = false
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("unexpected error with anomaly detection off: %v", err)
+	}
+}
+
+func TestValidate_ProductionHosts(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cfg.Targets.ProductionHosts = []string{"prod", "db[1-"}
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), `targets.production_hosts: invalid pattern "db[1-"`) {
+		t.Errorf("expected a pattern error, got %v", err)
+	}
+}
+
+func TestValidate_Jobs(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Jobs.WeeklyDigest.Schedule = "30 8 * * 1-5"
+	cfg.Jobs.ExpirySweep.Schedule = "@every 30s"
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cfg.Jobs.RetentionPrune.Schedule = "0 25 * * *"
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "jobs.retention_prune.schedule") {
+		t.Errorf("expected a schedule error, got %v", err)
+	}
+	cfg = DefaultConfig()
+	cfg.Jobs.ReminderMaxMinutes = cfg.Jobs.ReminderMinutes - 1
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "jobs.reminder_max_minutes") {
+		t.Errorf("expected a reminder error, got %v", err)
+	}
+}
+
+func TestValidate_ReviewQRURL(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Reviewers.QRURL = "https://slb.example.com/requests/{id}"
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, bad := range []string{"https://slb.example.com/requests", "slb.example.com/{id}"} {
+		cfg.Reviewers.QRURL = bad
+		if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "reviewers.qr_url") {
+			t.Errorf("Validate(%q) = %v, want a qr_url error", bad, err)
+		}
+	}
+}
+
+func TestValidate_TierOverrides(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Agents.TierOverrides = []string{"codex-cli=dangerous", "model:gpt-4o*=critical", "program:shell=skip_caution", "branch:main=critical", "stage:prod*=critical"}
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, bad := range []string{"codex-cli", "=dangerous", "codex-cli=safe", "host:ci=dangerous", "model:=critical", "[a=dangerous"} {
+		cfg.Agents.TierOverrides = []string{bad}
+		if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "agents.tier_overrides") {
+			t.Errorf("Validate(%q) = %v, want a tier_overrides error", bad, err)
+		}
+	}
+}
+
+func TestValidate_Telemetry(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Telemetry.Enabled = true
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "telemetry.endpoint") {
+		t.Fatalf("expected endpoint validation error, got %v", err)
+	}
+
+	cfg.Telemetry.Endpoint = "ftp://stats.example.com"
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "telemetry.endpoint") {
+		t.Fatalf("expected endpoint scheme error, got %v", err)
+	}
+
+	cfg.Telemetry.Endpoint = "https://stats.example.com/v1/patterns"
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg.Telemetry.IntervalHours = 0
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "telemetry.interval_hours") {
+		t.Fatalf("expected interval validation error, got %v", err)
+	}
+}
+
+func TestValidate_Locale(t *testing.T) {
+	cfg := DefaultConfig()
+	for _, locale := range []string{"", "en", "es", "es_MX.UTF-8"} {
+		cfg.General.Locale = locale
+		if err := Validate(cfg); err != nil {
+			t.Fatalf("locale %q: unexpected error: %v", locale, err)
+		}
+	}
+
+	cfg.General.Locale = "klingon"
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "general.locale") {
+		t.Fatalf("expected locale validation error, got %v", err)
+	}
+}
+
+func TestValidate_Timestamps(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.General.Timezone = "America/New_York"
+	cfg.General.Timestamps = "absolute"
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg.General.Timestamps = "sometimes"
+	cfg.General.Timezone = "Mars/Olympus"
+	err := Validate(cfg)
+	if err == nil || !strings.Contains(err.Error(), "general.timestamps") || !strings.Contains(err.Error(), "general.timezone") {
+		t.Fatalf("expected timestamps and timezone errors, got %v", err)
+	}
+}
+
+func TestValidate_SessionKeyStore(t *testing.T) {
+	cfg := DefaultConfig()
+	for _, store := range []string{"auto", "keyring", "file", "off"} {
+		cfg.General.SessionKeyStore = store
+		if err := Validate(cfg); err != nil {
+			t.Fatalf("store %q: unexpected error: %v", store, err)
+		}
+	}
+
+	cfg.General.SessionKeyStore = ""
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "general.session_key_store") {
+		t.Fatalf("expected session_key_store validation error, got %v", err)
+	}
+}
+
 func TestLoad_Precedence_DefaultsUserProjectEnvFlags(t *testing.T) {
 	home := t.TempDir()
 	t.Setenv("HOME", home)
@@ -81,6 +435,80 @@ func TestLoad_Precedence_DefaultsUserProjectEnvFlags(t *testing.T) {
 	}
 }
 
+func TestLoad_Profile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("SLB_PROFILE", "")
+	project := t.TempDir()
+
+	userPath := filepath.Join(home, ".slb", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(userPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	toml := `[general]
+min_approvals = 2
+
+[profiles.staging]
+db = "/srv/staging/state.db"
+
+[profiles.staging.general]
+min_approvals = 3
+review_pool = ["ops-lead", "oncall"]
+
+[profiles.staging.daemon]
+tcp_addr = "staging-host:9876"
+
+[profiles.staging.notifications]
+webhook_url = "https://hooks.example.com/staging"
+
+[profiles.broken]
+bogus = 1
+`
+	if err := os.WriteFile(userPath, []byte(toml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(LoadOptions{ProjectDir: project})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.General.MinApprovals != 2 || cfg.Daemon.TCPAddr != DefaultConfig().Daemon.TCPAddr {
+		t.Fatalf("profile applied without being selected: %+v", cfg.General)
+	}
+
+	cfg, err = Load(LoadOptions{ProjectDir: project, Profile: "staging"})
+	if err != nil {
+		t.Fatalf("Load staging: %v", err)
+	}
+	if cfg.General.MinApprovals != 3 || cfg.Daemon.TCPAddr != "staging-host:9876" ||
+		cfg.Notifications.WebhookURL != "https://hooks.example.com/staging" ||
+		!reflect.DeepEqual(cfg.General.ReviewPool, []string{"ops-lead", "oncall"}) {
+		t.Fatalf("staging profile not applied: %+v %+v", cfg.General, cfg.Daemon)
+	}
+
+	// SLB_PROFILE selects it too, and env overrides still win.
+	t.Setenv("SLB_PROFILE", "staging")
+	t.Setenv("SLB_MIN_APPROVALS", "4")
+	name, db, err := ActiveProfile(LoadOptions{ProjectDir: project})
+	if err != nil || name != "staging" || db != "/srv/staging/state.db" {
+		t.Fatalf("ActiveProfile = %q, %q, %v", name, db, err)
+	}
+	if cfg, err = Load(LoadOptions{ProjectDir: project}); err != nil || cfg.General.MinApprovals != 4 {
+		t.Fatalf("env over profile: min_approvals=%d, %v", cfg.General.MinApprovals, err)
+	}
+
+	for profile, want := range map[string]string{
+		"prod":   `profile "prod" is not defined (defined: broken, staging)`,
+		"broken": `profile "broken": unknown key "bogus"`,
+	} {
+		_, err := Load(LoadOptions{ProjectDir: project, Profile: profile})
+		var loadErr *LoadError
+		if !errors.As(err, &loadErr) || !strings.Contains(err.Error(), want) {
+			t.Errorf("Load(%s) error = %v, want %q", profile, err, want)
+		}
+	}
+}
+
 func TestLoad_InvalidEnvValueErrors(t *testing.T) {
 	t.Setenv("SLB_MIN_APPROVALS", "not-an-int")
 	if _, err := Load(LoadOptions{ProjectDir: t.TempDir()}); err == nil {
@@ -232,29 +660,44 @@ func TestGetValue(t *testing.T) {
 		{"general.request_timeout", cfg.General.RequestTimeoutSecs},
 		{"general.approval_ttl_minutes", cfg.General.ApprovalTTLMins},
 		{"general.approval_ttl_critical_minutes", cfg.General.ApprovalTTLCriticalMins},
+		{"general.idempotency_ttl_minutes", cfg.General.IdempotencyTTLMins},
 		{"general.timeout_action", cfg.General.TimeoutAction},
 		{"general.enable_dry_run", cfg.General.EnableDryRun},
 		{"general.enable_rollback_capture", cfg.General.EnableRollbackCapture},
 		{"general.max_rollback_size_mb", cfg.General.MaxRollbackSizeMB},
 		{"general.cross_project_reviews", cfg.General.CrossProjectReviews},
 		{"general.review_pool", cfg.General.ReviewPool},
+		{"general.locale", cfg.General.Locale},
+		{"general.timezone", cfg.General.Timezone},
+		{"general.timestamps", cfg.General.Timestamps},
+		{"general.session_key_store", cfg.General.SessionKeyStore},
+		{"general.pinned_env", cfg.General.PinnedEnv},
+		{"general.breakglass_cooldown_hours", cfg.General.BreakglassCooldownHours},
+		{"general.breakglass_ack_hours", cfg.General.BreakglassAckHours},
 
 		{"daemon.use_file_watcher", cfg.Daemon.UseFileWatcher},
 		{"daemon.ipc_socket", cfg.Daemon.IPCSocket},
 		{"daemon.tcp_addr", cfg.Daemon.TCPAddr},
 		{"daemon.tcp_require_auth", cfg.Daemon.TCPRequireAuth},
 		{"daemon.tcp_allowed_ips", cfg.Daemon.TCPAllowedIPs},
+		{"daemon.web_addr", cfg.Daemon.WebAddr},
+		{"daemon.web_allowed_ips", cfg.Daemon.WebAllowedIPs},
+		{"daemon.allowed_peer_users", cfg.Daemon.AllowedPeerUsers},
+		{"daemon.allowed_peer_groups", cfg.Daemon.AllowedPeerGroups},
+		{"daemon.allowed_peer_access", cfg.Daemon.AllowedPeerAccess},
 		{"daemon.log_level", cfg.Daemon.LogLevel},
 		{"daemon.pid_file", cfg.Daemon.PIDFile},
 
 		{"rate_limits.max_pending_per_session", cfg.RateLimits.MaxPendingPerSession},
 		{"rate_limits.max_requests_per_minute", cfg.RateLimits.MaxRequestsPerMinute},
 		{"rate_limits.rate_limit_action", cfg.RateLimits.RateLimitAction},
+		{"rate_limits.max_sessions_per_project", cfg.RateLimits.MaxSessionsPerProject},
+		{"rate_limits.session_limit_action", cfg.RateLimits.SessionLimitAction},
 
 		{"notifications.desktop_enabled", cfg.Notifications.DesktopEnabled},
 		{"notifications.desktop_delay_seconds", cfg.Notifications.DesktopDelaySecs},
+		{"notifications.reviewer_inactivity_minutes", cfg.Notifications.ReviewerInactivityMinutes},
 		{"notifications.webhook_url", cfg.Notifications.WebhookURL},
-		{"notifications.email_enabled", cfg.Notifications.EmailEnabled},
 
 		{"history.database_path", cfg.History.DatabasePath},
 		{"history.git_repo_path", cfg.History.GitRepoPath},
@@ -266,6 +709,8 @@ func TestGetValue(t *testing.T) {
 		{"patterns.critical.dynamic_quorum", cfg.Patterns.Critical.DynamicQuorum},
 		{"patterns.critical.dynamic_quorum_floor", cfg.Patterns.Critical.DynamicQuorumFloor},
 		{"patterns.critical.auto_approve_delay_seconds", cfg.Patterns.Critical.AutoApproveDelaySeconds},
+		{"patterns.critical.auto_approve", cfg.Patterns.Critical.AutoApprove},
+		{"patterns.critical.auto_approve_notify", cfg.Patterns.Critical.AutoApproveNotify},
 		{"patterns.critical.patterns", cfg.Patterns.Critical.Patterns},
 
 		{"patterns.dangerous", cfg.Patterns.Dangerous},
@@ -273,6 +718,8 @@ func TestGetValue(t *testing.T) {
 		{"patterns.dangerous.dynamic_quorum", cfg.Patterns.Dangerous.DynamicQuorum},
 		{"patterns.dangerous.dynamic_quorum_floor", cfg.Patterns.Dangerous.DynamicQuorumFloor},
 		{"patterns.dangerous.auto_approve_delay_seconds", cfg.Patterns.Dangerous.AutoApproveDelaySeconds},
+		{"patterns.dangerous.auto_approve", cfg.Patterns.Dangerous.AutoApprove},
+		{"patterns.dangerous.auto_approve_notify", cfg.Patterns.Dangerous.AutoApproveNotify},
 		{"patterns.dangerous.patterns", cfg.Patterns.Dangerous.Patterns},
 
 		{"patterns.caution", cfg.Patterns.Caution},
@@ -280,6 +727,8 @@ func TestGetValue(t *testing.T) {
 		{"patterns.caution.dynamic_quorum", cfg.Patterns.Caution.DynamicQuorum},
 		{"patterns.caution.dynamic_quorum_floor", cfg.Patterns.Caution.DynamicQuorumFloor},
 		{"patterns.caution.auto_approve_delay_seconds", cfg.Patterns.Caution.AutoApproveDelaySeconds},
+		{"patterns.caution.auto_approve", cfg.Patterns.Caution.AutoApprove},
+		{"patterns.caution.auto_approve_notify", cfg.Patterns.Caution.AutoApproveNotify},
 		{"patterns.caution.patterns", cfg.Patterns.Caution.Patterns},
 
 		{"patterns.safe", cfg.Patterns.Safe},
@@ -287,15 +736,56 @@ func TestGetValue(t *testing.T) {
 		{"patterns.safe.dynamic_quorum", cfg.Patterns.Safe.DynamicQuorum},
 		{"patterns.safe.dynamic_quorum_floor", cfg.Patterns.Safe.DynamicQuorumFloor},
 		{"patterns.safe.auto_approve_delay_seconds", cfg.Patterns.Safe.AutoApproveDelaySeconds},
+		{"patterns.safe.auto_approve", cfg.Patterns.Safe.AutoApprove},
+		{"patterns.safe.auto_approve_notify", cfg.Patterns.Safe.AutoApproveNotify},
 		{"patterns.safe.patterns", cfg.Patterns.Safe.Patterns},
+		{"patterns.packs", cfg.Patterns.Packs},
 
 		{"integrations.agent_mail_enabled", cfg.Integrations.AgentMailEnabled},
 		{"integrations.agent_mail_thread", cfg.Integrations.AgentMailThread},
 		{"integrations.claude_hooks_enabled", cfg.Integrations.ClaudeHooksEnabled},
+		{"integrations.hook_auto_request", cfg.Integrations.HookAutoRequest},
+		{"integrations.hook_reuse_window_minutes", cfg.Integrations.HookReuseWindowMins},
+		{"integrations.hook_reuse_tiers", cfg.Integrations.HookReuseTiers},
+		{"integrations.llm_review_enabled", cfg.Integrations.LLMReviewEnabled},
+		{"integrations.llm_review_endpoint", cfg.Integrations.LLMReviewEndpoint},
+		{"integrations.llm_review_model", cfg.Integrations.LLMReviewModel},
+		{"integrations.llm_review_timeout_seconds", cfg.Integrations.LLMReviewTimeoutSecs},
 
 		{"agents.trusted_self_approve", cfg.Agents.TrustedSelfApprove},
 		{"agents.trusted_self_approve_delay_seconds", cfg.Agents.TrustedSelfApproveDelaySecs},
 		{"agents.blocked", cfg.Agents.Blocked},
+		{"agents.tier_overrides", cfg.Agents.TierOverrides},
+
+		{"execution_windows.enabled", cfg.ExecutionWindows.Enabled},
+		{"execution_windows.quiet_hours", cfg.ExecutionWindows.QuietHours},
+		{"execution_windows.block_weekends", cfg.ExecutionWindows.BlockWeekends},
+		{"execution_windows.tiers", cfg.ExecutionWindows.Tiers},
+		{"execution_windows.timezone", cfg.ExecutionWindows.Timezone},
+
+		{"telemetry.enabled", cfg.Telemetry.Enabled},
+		{"telemetry.endpoint", cfg.Telemetry.Endpoint},
+		{"telemetry.interval_hours", cfg.Telemetry.IntervalHours},
+		{"sudo_mode.enabled", cfg.SudoMode.Enabled},
+		{"sudo_mode.method", cfg.SudoMode.Method},
+		{"sudo_mode.tiers", cfg.SudoMode.Tiers},
+		{"sudo_mode.fido2_command", cfg.SudoMode.FIDO2Command},
+		{"sudo_mode.max_age_seconds", cfg.SudoMode.MaxAgeSecs},
+		{"anomaly.enabled", cfg.Anomaly.Enabled},
+		{"anomaly.burst_window_seconds", cfg.Anomaly.BurstWindowSecs},
+		{"anomaly.burst_threshold", cfg.Anomaly.BurstThreshold},
+		{"anomaly.novel_family", cfg.Anomaly.NovelFamily},
+		{"anomaly.min_history", cfg.Anomaly.MinHistory},
+		{"anomaly.baseline_days", cfg.Anomaly.BaselineDays},
+		{"targets.production_hosts", cfg.Targets.ProductionHosts},
+		{"reviewers.instructions", cfg.Reviewers.Instructions},
+		{"reviewers.token_budget", cfg.Reviewers.TokenBudget},
+		{"reviewers.qr_url", cfg.Reviewers.QRURL},
+		{"jobs.review_reminders.enabled", cfg.Jobs.ReviewReminders.Enabled},
+		{"jobs.weekly_digest.schedule", cfg.Jobs.WeeklyDigest.Schedule},
+		{"jobs.reminder_max_minutes", cfg.Jobs.ReminderMaxMinutes},
+		{"isolation.mode", cfg.Isolation.Mode},
+		{"isolation.network", cfg.Isolation.Network},
 
 		{"general", cfg.General},
 		{"daemon", cfg.Daemon},
@@ -305,6 +795,8 @@ func TestGetValue(t *testing.T) {
 		{"patterns", cfg.Patterns},
 		{"integrations", cfg.Integrations},
 		{"agents", cfg.Agents},
+		{"execution_windows", cfg.ExecutionWindows},
+		{"telemetry", cfg.Telemetry},
 	}
 
 	for _, tc := range cases {
@@ -378,3 +870,64 @@ func TestWriteValue_DecodeExistingInvalidTOMLErrors(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestValidate_PatternPacks(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Patterns.Packs = []string{"kubernetes", "windows-powershell"}
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg.Patterns.Packs = []string{"Kube Rnetes"}
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "patterns.packs") {
+		t.Fatalf("expected pack name validation error, got %v", err)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	oldContent := []byte(`
+[general]
+min_approvals = 2
+request_timeout = 30
+
+[agents]
+tier_overrides = ["codex-cli=dangerous"]
+`)
+	newContent := []byte(`
+# reformatted, with a comment
+[general]
+request_timeout = 30
+min_approvals = 1
+
+[patterns]
+packs = ["kubernetes"]
+`)
+	entries, err := Diff(oldContent, newContent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []DiffEntry{
+		{Key: "agents.tier_overrides", Old: `["codex-cli=dangerous"]`},
+		{Key: "general.min_approvals", Old: "2", New: "1"},
+		{Key: "patterns.packs", New: `["kubernetes"]`},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Fatalf("Diff = %+v, want %+v", entries, want)
+	}
+	text := FormatDiff(entries)
+	for _, line := range []string{`- agents.tier_overrides = ["codex-cli=dangerous"]`, "~ general.min_approvals = 2 -> 1", `+ patterns.packs = ["kubernetes"]`} {
+		if !strings.Contains(text, line) {
+			t.Errorf("FormatDiff missing %q:\n%s", line, text)
+		}
+	}
+
+	if entries, err := Diff(nil, nil); err != nil || len(entries) != 0 {
+		t.Errorf("Diff of empty files = %+v, %v", entries, err)
+	}
+	if _, err := Diff(oldContent, []byte("[general")); err == nil || !strings.Contains(err.Error(), "staged config") {
+		t.Errorf("expected a parse error for the staged config, got %v", err)
+	}
+	if Hash(oldContent) == Hash(newContent) || len(Hash(nil)) != 64 {
+		t.Error("Hash does not tell the files apart")
+	}
+}
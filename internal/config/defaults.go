@@ -18,6 +18,9 @@ var (
 		`^helm\s+uninstall.*--all`,
 		`^docker\s+system\s+prune\s+-a`,
 		`^git\s+push\s+.*--force($|\s)`,
+		`^git\s+push\s+(.*\s)?--mirror($|\s)`,
+		`^git\s+filter-(branch|repo)($|\s)`,
+		`^git\s+reflog\s+expire\s+(.*\s)?--expire(-unreachable)?=(now|all)($|\s)`,
 		`^aws\s+.*terminate-instances`,
 		`^gcloud.*delete.*--quiet`,
 	}
@@ -28,6 +31,8 @@ var (
 		`^git\s+reset\s+--hard`,
 		`^git\s+clean\s+-fd`,
 		`^git\s+push.*--force-with-lease`,
+		`^git\s+rebase\s+(.*\s)?(-i|--interactive|--root)($|\s)`,
+		`^git\s+reflog\s+(expire|delete)($|\s)`,
 		`^kubectl\s+delete`,
 		`^helm\s+uninstall`,
 		`^docker\s+rm`,
@@ -70,38 +75,74 @@ func DefaultConfig() Config {
 			RequestTimeoutSecs:        1800,
 			ApprovalTTLMins:           30,
 			ApprovalTTLCriticalMins:   10,
+			IdempotencyTTLMins:        1440,
 			TimeoutAction:             "escalate",
 			EnableDryRun:              true,
 			EnableRollbackCapture:     true,
 			MaxRollbackSizeMB:         100,
 			CrossProjectReviews:       false,
 			ReviewPool:                []string{},
+			BreakglassCooldownHours:   4,
+			BreakglassAckHours:        24,
+			Locale:                    "",
+			Timezone:                  "",
+			Timestamps:                "relative",
+			SessionKeyStore:           "auto",
+			PinnedEnv: []string{
+				"KUBECONFIG", "KUBE_CONTEXT", "AWS_PROFILE", "AWS_REGION", "AWS_DEFAULT_REGION",
+				"CLOUDSDK_CORE_PROJECT", "GOOGLE_CLOUD_PROJECT", "DOCKER_HOST", "DOCKER_CONTEXT",
+				"TF_WORKSPACE", "VIRTUAL_ENV",
+			},
 		},
 		Daemon: DaemonConfig{
-			UseFileWatcher: true,
-			IPCSocket:      "",
-			TCPAddr:        "",
-			TCPRequireAuth: true,
-			TCPAllowedIPs:  []string{},
-			LogLevel:       "info",
-			PIDFile:        "",
+			UseFileWatcher:    true,
+			IPCSocket:         "",
+			TCPAddr:           "",
+			TCPRequireAuth:    true,
+			TCPAllowedIPs:     []string{},
+			WebAddr:           "",
+			WebAllowedIPs:     []string{},
+			AllowedPeerUsers:  []string{},
+			AllowedPeerGroups: []string{},
+			AllowedPeerAccess: "write",
+			LogLevel:          "info",
+			PIDFile:           "",
+
+			ClientRequestsPerSecond:  50,
+			ClientRequestBurst:       100,
+			ClientMaxSubscriptions:   16,
+			SessionRequestsPerMinute: 600,
+
+			SubscriberQueueSize: 100,
+			SubscriberOverflow:  "drop-oldest",
 		},
 		RateLimits: RateLimitConfig{
 			MaxPendingPerSession: 5,
 			MaxRequestsPerMinute: 10,
 			RateLimitAction:      "reject",
+
+			MaxSessionsPerProject:   0,
+			MaxSessionsPerAgent:     0,
+			SessionLimitAction:      "reject",
+			SessionQueueTimeoutSecs: 300,
 		},
 		Notifications: NotificationsConfig{
 			DesktopEnabled:   true,
 			DesktopDelaySecs: 60,
 			WebhookURL:       "",
-			EmailEnabled:     false,
+
+			ReviewerInactivityMinutes: 15,
+			ReviewSLACriticalMinutes:  10,
+			ReviewSLADangerousMinutes: 10,
+			ReviewSLACautionMinutes:   0,
+			ReviewSLAEscalate:         false,
 		},
 		History: HistoryConfig{
-			DatabasePath:  "",
-			GitRepoPath:   "",
-			RetentionDays: 365,
-			AutoGitCommit: true,
+			DatabasePath:             "",
+			GitRepoPath:              "",
+			RetentionDays:            365,
+			AutoGitCommit:            true,
+			ReconcileIntervalMinutes: 60,
 		},
 		Patterns: PatternsConfig{
 			Critical: PatternTierConfig{
@@ -109,6 +150,9 @@ func DefaultConfig() Config {
 				DynamicQuorum:           false,
 				DynamicQuorumFloor:      2,
 				AutoApproveDelaySeconds: 0,
+				AutoApprove:             false,
+				AutoApproveNotify:       false,
+				AutoApproveMaxScore:     0,
 				Patterns:                defaultCriticalPatterns,
 			},
 			Dangerous: PatternTierConfig{
@@ -116,6 +160,9 @@ func DefaultConfig() Config {
 				DynamicQuorum:           false,
 				DynamicQuorumFloor:      1,
 				AutoApproveDelaySeconds: 0,
+				AutoApprove:             false,
+				AutoApproveNotify:       false,
+				AutoApproveMaxScore:     0,
 				Patterns:                defaultDangerousPatterns,
 			},
 			Caution: PatternTierConfig{
@@ -123,6 +170,9 @@ func DefaultConfig() Config {
 				DynamicQuorum:           false,
 				DynamicQuorumFloor:      0,
 				AutoApproveDelaySeconds: 30,
+				AutoApprove:             true,
+				AutoApproveNotify:       true,
+				AutoApproveMaxScore:     0,
 				Patterns:                defaultCautionPatterns,
 			},
 			Safe: PatternTierConfig{
@@ -130,18 +180,86 @@ func DefaultConfig() Config {
 				DynamicQuorum:           false,
 				DynamicQuorumFloor:      0,
 				AutoApproveDelaySeconds: 0,
+				AutoApprove:             false,
+				AutoApproveNotify:       false,
+				AutoApproveMaxScore:     0,
 				Patterns:                defaultSafePatterns,
 			},
+			Packs: []string{},
 		},
 		Integrations: IntegrationsConfig{
 			AgentMailEnabled:   true,
 			AgentMailThread:    "SLB-Reviews",
 			ClaudeHooksEnabled: true,
+			HookAutoRequest:    false,
+			// Retries of a command that already ran are let through for an
+			// hour; critical commands are reviewed again every time.
+			HookReuseWindowMins: 60,
+			HookReuseTiers:      []string{"caution", "dangerous"},
+
+			LLMReviewEnabled:     false,
+			LLMReviewEndpoint:    "",
+			LLMReviewModel:       "",
+			LLMReviewTimeoutSecs: 15,
 		},
 		Agents: AgentsConfig{
 			TrustedSelfApprove:          []string{},
 			TrustedSelfApproveDelaySecs: 300,
 			Blocked:                     []string{},
+			TierOverrides:               []string{},
+		},
+		ExecutionWindows: ExecutionWindowsConfig{
+			Enabled:       false,
+			QuietHours:    "22:00-06:00",
+			BlockWeekends: true,
+			Tiers:         []string{"critical"},
+			Timezone:      "",
+		},
+		Telemetry: TelemetryConfig{
+			Enabled:       false,
+			Endpoint:      "",
+			IntervalHours: 24,
+		},
+		SudoMode: SudoModeConfig{
+			Enabled:      false,
+			Method:       "passphrase",
+			Tiers:        []string{"critical"},
+			FIDO2Command: "",
+			MaxAgeSecs:   120,
+		},
+		Anomaly: AnomalyConfig{
+			Enabled:         false,
+			BurstWindowSecs: 60,
+			BurstThreshold:  20,
+			NovelFamily:     true,
+			MinHistory:      50,
+			BaselineDays:    90,
+		},
+		Targets: TargetsConfig{
+			ProductionHosts: []string{
+				"prod", "prod-*", "*-prod", "*-prod-*", "prod[0-9]*",
+				"production", "production-*", "*-production", "prd",
+			},
+		},
+		Reviewers: ReviewersConfig{
+			Instructions: "",
+			TokenBudget:  0,
+			QRURL:        "",
+		},
+		Jobs: JobsConfig{
+			ExpirySweep:        JobConfig{Enabled: true, Schedule: "10s"},
+			ReviewReminders:    JobConfig{Enabled: true, Schedule: "1m"},
+			RetentionPrune:     JobConfig{Enabled: true, Schedule: "0 3 * * *"},
+			WeeklyDigest:       JobConfig{Enabled: false, Schedule: "0 9 * * 1"},
+			ReminderMinutes:    15,
+			ReminderMaxMinutes: 240,
+		},
+		Isolation: IsolationConfig{
+			Mode:    "none",
+			User:    "",
+			Runtime: "docker",
+			Image:   "",
+			Network: false,
 		},
 	}
 }
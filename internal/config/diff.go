@@ -0,0 +1,113 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Hash returns the SHA-256 of a config file's content, as recorded in the
+// config history.
+func Hash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// DiffEntry is a setting that differs between two config files. Old is
+// empty for an added setting and New for a removed one; values are JSON.
+type DiffEntry struct {
+	Key string `json:"key"`
+	Old string `json:"old,omitempty"`
+	New string `json:"new,omitempty"`
+}
+
+// Diff compares two config files setting by setting (dotted keys), so
+// reviewers see what changes rather than how the file was reformatted.
+func Diff(oldContent, newContent []byte) ([]DiffEntry, error) {
+	oldValues, err := flattenTOML(oldContent)
+	if err != nil {
+		return nil, fmt.Errorf("parsing current config: %w", err)
+	}
+	newValues, err := flattenTOML(newContent)
+	if err != nil {
+		return nil, fmt.Errorf("parsing staged config: %w", err)
+	}
+
+	keys := make(map[string]bool, len(oldValues)+len(newValues))
+	for k := range oldValues {
+		keys[k] = true
+	}
+	for k := range newValues {
+		keys[k] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var entries []DiffEntry
+	for _, k := range sorted {
+		if oldValues[k] != newValues[k] {
+			entries = append(entries, DiffEntry{Key: k, Old: oldValues[k], New: newValues[k]})
+		}
+	}
+	return entries, nil
+}
+
+// FormatDiff renders diff entries one per line: "+" for an added setting,
+// "-" for a removed one and "~" for a changed one.
+func FormatDiff(entries []DiffEntry) string {
+	var b strings.Builder
+	for _, e := range entries {
+		switch {
+		case e.Old == "":
+			fmt.Fprintf(&b, "+ %s = %s\n", e.Key, e.New)
+		case e.New == "":
+			fmt.Fprintf(&b, "- %s = %s\n", e.Key, e.Old)
+		default:
+			fmt.Fprintf(&b, "~ %s = %s -> %s\n", e.Key, e.Old, e.New)
+		}
+	}
+	return b.String()
+}
+
+// flattenTOML maps each setting's dotted key to its JSON-encoded value.
+// Arrays, including arrays of tables, are compared as a whole.
+func flattenTOML(content []byte) (map[string]string, error) {
+	var doc map[string]any
+	if _, err := toml.Decode(string(content), &doc); err != nil {
+		return nil, err
+	}
+	out := make(map[string]string)
+	var walk func(prefix string, m map[string]any) error
+	walk = func(prefix string, m map[string]any) error {
+		for k, v := range m {
+			key := k
+			if prefix != "" {
+				key = prefix + "." + k
+			}
+			if table, ok := v.(map[string]any); ok {
+				if err := walk(key, table); err != nil {
+					return err
+				}
+				continue
+			}
+			encoded, err := json.Marshal(v)
+			if err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+			out[key] = string(encoded)
+		}
+		return nil
+	}
+	if err := walk("", doc); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -20,34 +20,50 @@ type LoadOptions struct {
 	ConfigPath string
 	// FlagOverrides are highest-priority overrides from CLI flags (dot-notated keys).
 	FlagOverrides map[string]any
+	// Profile selects a [profiles.<name>] table to layer over the config
+	// files. When empty, the profile set with SetProfile or SLB_PROFILE is
+	// used.
+	Profile string
 }
 
+// LoadError is returned by Load when a config file, environment override
+// or the merged result is invalid. It reads as the error it wraps.
+type LoadError struct {
+	Err error
+}
+
+func (e *LoadError) Error() string { return e.Err.Error() }
+
+func (e *LoadError) Unwrap() error { return e.Err }
+
 // Load returns the effective configuration after applying precedence:
-// defaults < user (~/.slb/config.toml) < project (.slb/config.toml) < env (SLB_*) < flags.
+// defaults < user (~/.slb/config.toml) < project (.slb/config.toml) < profile < env (SLB_*) < flags.
+// Errors are *LoadError.
 func Load(opts LoadOptions) (Config, error) {
+	cfg, err := load(opts)
+	if err != nil {
+		return Config{}, &LoadError{Err: err}
+	}
+	return cfg, nil
+}
+
+func load(opts LoadOptions) (Config, error) {
 	v := viper.New()
 	setDefaults(v)
 
-	projectDir := opts.ProjectDir
-	if projectDir == "" {
-		if cwd, err := os.Getwd(); err == nil {
-			projectDir = cwd
-		}
-	}
-
-	// 1) User config
-	if err := mergeConfigFile(v, userConfigPath()); err != nil {
+	// 1-2) User and project config
+	if err := mergeConfigFiles(v, opts); err != nil {
 		return Config{}, err
 	}
-	// 2) Project config
-	if err := mergeConfigFile(v, projectConfigPath(projectDir, opts.ConfigPath)); err != nil {
+	// 3) Profile
+	if _, err := applyProfile(v, profileName(opts)); err != nil {
 		return Config{}, err
 	}
-	// 3) Environment variables
+	// 4) Environment variables
 	if err := applyEnvOverrides(v); err != nil {
 		return Config{}, err
 	}
-	// 4) CLI flags (highest)
+	// 5) CLI flags (highest)
 	applyFlagOverrides(v, opts.FlagOverrides)
 
 	var cfg Config
@@ -71,58 +87,159 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("general.request_timeout", def.General.RequestTimeoutSecs)
 	v.SetDefault("general.approval_ttl_minutes", def.General.ApprovalTTLMins)
 	v.SetDefault("general.approval_ttl_critical_minutes", def.General.ApprovalTTLCriticalMins)
+	v.SetDefault("general.idempotency_ttl_minutes", def.General.IdempotencyTTLMins)
 	v.SetDefault("general.timeout_action", def.General.TimeoutAction)
 	v.SetDefault("general.enable_dry_run", def.General.EnableDryRun)
 	v.SetDefault("general.enable_rollback_capture", def.General.EnableRollbackCapture)
 	v.SetDefault("general.max_rollback_size_mb", def.General.MaxRollbackSizeMB)
 	v.SetDefault("general.cross_project_reviews", def.General.CrossProjectReviews)
 	v.SetDefault("general.review_pool", def.General.ReviewPool)
+	v.SetDefault("general.breakglass_cooldown_hours", def.General.BreakglassCooldownHours)
+	v.SetDefault("general.breakglass_ack_hours", def.General.BreakglassAckHours)
+	v.SetDefault("general.locale", def.General.Locale)
+	v.SetDefault("general.timezone", def.General.Timezone)
+	v.SetDefault("general.timestamps", def.General.Timestamps)
+	v.SetDefault("general.session_key_store", def.General.SessionKeyStore)
+	v.SetDefault("general.pinned_env", def.General.PinnedEnv)
 
 	v.SetDefault("daemon.use_file_watcher", def.Daemon.UseFileWatcher)
 	v.SetDefault("daemon.ipc_socket", def.Daemon.IPCSocket)
 	v.SetDefault("daemon.tcp_addr", def.Daemon.TCPAddr)
 	v.SetDefault("daemon.tcp_require_auth", def.Daemon.TCPRequireAuth)
 	v.SetDefault("daemon.tcp_allowed_ips", def.Daemon.TCPAllowedIPs)
+	v.SetDefault("daemon.web_addr", def.Daemon.WebAddr)
+	v.SetDefault("daemon.web_allowed_ips", def.Daemon.WebAllowedIPs)
+	v.SetDefault("daemon.allowed_peer_users", def.Daemon.AllowedPeerUsers)
+	v.SetDefault("daemon.allowed_peer_groups", def.Daemon.AllowedPeerGroups)
+	v.SetDefault("daemon.allowed_peer_access", def.Daemon.AllowedPeerAccess)
 	v.SetDefault("daemon.log_level", def.Daemon.LogLevel)
 	v.SetDefault("daemon.pid_file", def.Daemon.PIDFile)
+	v.SetDefault("daemon.client_requests_per_second", def.Daemon.ClientRequestsPerSecond)
+	v.SetDefault("daemon.client_request_burst", def.Daemon.ClientRequestBurst)
+	v.SetDefault("daemon.client_max_subscriptions", def.Daemon.ClientMaxSubscriptions)
+	v.SetDefault("daemon.session_requests_per_minute", def.Daemon.SessionRequestsPerMinute)
+	v.SetDefault("daemon.subscriber_queue_size", def.Daemon.SubscriberQueueSize)
+	v.SetDefault("daemon.subscriber_overflow", def.Daemon.SubscriberOverflow)
 
 	v.SetDefault("rate_limits.max_pending_per_session", def.RateLimits.MaxPendingPerSession)
 	v.SetDefault("rate_limits.max_requests_per_minute", def.RateLimits.MaxRequestsPerMinute)
 	v.SetDefault("rate_limits.rate_limit_action", def.RateLimits.RateLimitAction)
+	v.SetDefault("rate_limits.max_sessions_per_project", def.RateLimits.MaxSessionsPerProject)
+	v.SetDefault("rate_limits.max_sessions_per_agent", def.RateLimits.MaxSessionsPerAgent)
+	v.SetDefault("rate_limits.session_limit_action", def.RateLimits.SessionLimitAction)
+	v.SetDefault("rate_limits.session_queue_timeout_seconds", def.RateLimits.SessionQueueTimeoutSecs)
 
 	v.SetDefault("notifications.desktop_enabled", def.Notifications.DesktopEnabled)
 	v.SetDefault("notifications.desktop_delay_seconds", def.Notifications.DesktopDelaySecs)
 	v.SetDefault("notifications.webhook_url", def.Notifications.WebhookURL)
-	v.SetDefault("notifications.email_enabled", def.Notifications.EmailEnabled)
+	v.SetDefault("notifications.reviewer_inactivity_minutes", def.Notifications.ReviewerInactivityMinutes)
+	v.SetDefault("notifications.review_sla_critical_minutes", def.Notifications.ReviewSLACriticalMinutes)
+	v.SetDefault("notifications.review_sla_dangerous_minutes", def.Notifications.ReviewSLADangerousMinutes)
+	v.SetDefault("notifications.review_sla_caution_minutes", def.Notifications.ReviewSLACautionMinutes)
+	v.SetDefault("notifications.review_sla_escalate", def.Notifications.ReviewSLAEscalate)
 
 	v.SetDefault("history.database_path", def.History.DatabasePath)
 	v.SetDefault("history.git_repo_path", def.History.GitRepoPath)
 	v.SetDefault("history.retention_days", def.History.RetentionDays)
 	v.SetDefault("history.auto_git_commit", def.History.AutoGitCommit)
+	v.SetDefault("history.reconcile_interval_minutes", def.History.ReconcileIntervalMinutes)
 
 	// Pattern tiers
 	setTierDefaults(v, "patterns.critical", def.Patterns.Critical)
 	setTierDefaults(v, "patterns.dangerous", def.Patterns.Dangerous)
 	setTierDefaults(v, "patterns.caution", def.Patterns.Caution)
 	setTierDefaults(v, "patterns.safe", def.Patterns.Safe)
+	v.SetDefault("patterns.packs", def.Patterns.Packs)
 
 	v.SetDefault("integrations.agent_mail_enabled", def.Integrations.AgentMailEnabled)
 	v.SetDefault("integrations.agent_mail_thread", def.Integrations.AgentMailThread)
 	v.SetDefault("integrations.claude_hooks_enabled", def.Integrations.ClaudeHooksEnabled)
+	v.SetDefault("integrations.hook_auto_request", def.Integrations.HookAutoRequest)
+	v.SetDefault("integrations.hook_reuse_window_minutes", def.Integrations.HookReuseWindowMins)
+	v.SetDefault("integrations.hook_reuse_tiers", def.Integrations.HookReuseTiers)
+	v.SetDefault("integrations.llm_review_enabled", def.Integrations.LLMReviewEnabled)
+	v.SetDefault("integrations.llm_review_endpoint", def.Integrations.LLMReviewEndpoint)
+	v.SetDefault("integrations.llm_review_model", def.Integrations.LLMReviewModel)
+	v.SetDefault("integrations.llm_review_timeout_seconds", def.Integrations.LLMReviewTimeoutSecs)
 
 	v.SetDefault("agents.trusted_self_approve", def.Agents.TrustedSelfApprove)
 	v.SetDefault("agents.trusted_self_approve_delay_seconds", def.Agents.TrustedSelfApproveDelaySecs)
 	v.SetDefault("agents.blocked", def.Agents.Blocked)
+	v.SetDefault("agents.tier_overrides", def.Agents.TierOverrides)
+
+	v.SetDefault("execution_windows.enabled", def.ExecutionWindows.Enabled)
+	v.SetDefault("execution_windows.quiet_hours", def.ExecutionWindows.QuietHours)
+	v.SetDefault("execution_windows.block_weekends", def.ExecutionWindows.BlockWeekends)
+	v.SetDefault("execution_windows.tiers", def.ExecutionWindows.Tiers)
+	v.SetDefault("execution_windows.timezone", def.ExecutionWindows.Timezone)
+
+	v.SetDefault("telemetry.enabled", def.Telemetry.Enabled)
+	v.SetDefault("telemetry.endpoint", def.Telemetry.Endpoint)
+	v.SetDefault("telemetry.interval_hours", def.Telemetry.IntervalHours)
+	v.SetDefault("sudo_mode.enabled", def.SudoMode.Enabled)
+	v.SetDefault("sudo_mode.method", def.SudoMode.Method)
+	v.SetDefault("sudo_mode.tiers", def.SudoMode.Tiers)
+	v.SetDefault("sudo_mode.fido2_command", def.SudoMode.FIDO2Command)
+	v.SetDefault("sudo_mode.max_age_seconds", def.SudoMode.MaxAgeSecs)
+
+	v.SetDefault("anomaly.enabled", def.Anomaly.Enabled)
+	v.SetDefault("anomaly.burst_window_seconds", def.Anomaly.BurstWindowSecs)
+	v.SetDefault("anomaly.burst_threshold", def.Anomaly.BurstThreshold)
+	v.SetDefault("anomaly.novel_family", def.Anomaly.NovelFamily)
+	v.SetDefault("anomaly.min_history", def.Anomaly.MinHistory)
+	v.SetDefault("anomaly.baseline_days", def.Anomaly.BaselineDays)
+
+	v.SetDefault("targets.production_hosts", def.Targets.ProductionHosts)
+
+	v.SetDefault("reviewers.instructions", def.Reviewers.Instructions)
+	v.SetDefault("reviewers.token_budget", def.Reviewers.TokenBudget)
+	v.SetDefault("reviewers.qr_url", def.Reviewers.QRURL)
+
+	setJobDefaults(v, "jobs.expiry_sweep", def.Jobs.ExpirySweep)
+	setJobDefaults(v, "jobs.review_reminders", def.Jobs.ReviewReminders)
+	setJobDefaults(v, "jobs.retention_prune", def.Jobs.RetentionPrune)
+	setJobDefaults(v, "jobs.weekly_digest", def.Jobs.WeeklyDigest)
+	v.SetDefault("jobs.reminder_minutes", def.Jobs.ReminderMinutes)
+	v.SetDefault("jobs.reminder_max_minutes", def.Jobs.ReminderMaxMinutes)
+
+	v.SetDefault("isolation.mode", def.Isolation.Mode)
+	v.SetDefault("isolation.user", def.Isolation.User)
+	v.SetDefault("isolation.runtime", def.Isolation.Runtime)
+	v.SetDefault("isolation.image", def.Isolation.Image)
+	v.SetDefault("isolation.network", def.Isolation.Network)
 }
 
 func setTierDefaults(v *viper.Viper, prefix string, tier PatternTierConfig) {
 	v.SetDefault(prefix+".min_approvals", tier.MinApprovals)
 	v.SetDefault(prefix+".dynamic_quorum", tier.DynamicQuorum)
 	v.SetDefault(prefix+".dynamic_quorum_floor", tier.DynamicQuorumFloor)
+	v.SetDefault(prefix+".auto_approve", tier.AutoApprove)
+	v.SetDefault(prefix+".auto_approve_notify", tier.AutoApproveNotify)
+	v.SetDefault(prefix+".auto_approve_max_score", tier.AutoApproveMaxScore)
 	v.SetDefault(prefix+".auto_approve_delay_seconds", tier.AutoApproveDelaySeconds)
 	v.SetDefault(prefix+".patterns", tier.Patterns)
 }
 
+func setJobDefaults(v *viper.Viper, prefix string, job JobConfig) {
+	v.SetDefault(prefix+".enabled", job.Enabled)
+	v.SetDefault(prefix+".schedule", job.Schedule)
+}
+
+// mergeConfigFiles merges the user config, then the project config (or
+// opts.ConfigPath).
+func mergeConfigFiles(v *viper.Viper, opts LoadOptions) error {
+	projectDir := opts.ProjectDir
+	if projectDir == "" {
+		if cwd, err := os.Getwd(); err == nil {
+			projectDir = cwd
+		}
+	}
+	if err := mergeConfigFile(v, userConfigPath()); err != nil {
+		return err
+	}
+	return mergeConfigFile(v, projectConfigPath(projectDir, opts.ConfigPath))
+}
+
 // mergeConfigFile merges the TOML config file if it exists.
 func mergeConfigFile(v *viper.Viper, path string) error {
 	if path == "" {
@@ -227,6 +344,22 @@ func GetValue(cfg Config, key string) (any, bool) {
 				current = c.Integrations
 			case "agents":
 				current = c.Agents
+			case "execution_windows":
+				current = c.ExecutionWindows
+			case "telemetry":
+				current = c.Telemetry
+			case "sudo_mode":
+				current = c.SudoMode
+			case "anomaly":
+				current = c.Anomaly
+			case "targets":
+				current = c.Targets
+			case "reviewers":
+				current = c.Reviewers
+			case "jobs":
+				current = c.Jobs
+			case "isolation":
+				current = c.Isolation
 			default:
 				return nil, false
 			}
@@ -246,6 +379,8 @@ func GetValue(cfg Config, key string) (any, bool) {
 				return c.ApprovalTTLMins, true
 			case "approval_ttl_critical_minutes":
 				return c.ApprovalTTLCriticalMins, true
+			case "idempotency_ttl_minutes":
+				return c.IdempotencyTTLMins, true
 			case "timeout_action":
 				return c.TimeoutAction, true
 			case "enable_dry_run":
@@ -258,6 +393,20 @@ func GetValue(cfg Config, key string) (any, bool) {
 				return c.CrossProjectReviews, true
 			case "review_pool":
 				return c.ReviewPool, true
+			case "breakglass_cooldown_hours":
+				return c.BreakglassCooldownHours, true
+			case "breakglass_ack_hours":
+				return c.BreakglassAckHours, true
+			case "locale":
+				return c.Locale, true
+			case "timezone":
+				return c.Timezone, true
+			case "timestamps":
+				return c.Timestamps, true
+			case "session_key_store":
+				return c.SessionKeyStore, true
+			case "pinned_env":
+				return c.PinnedEnv, true
 			default:
 				return nil, false
 			}
@@ -273,10 +422,32 @@ func GetValue(cfg Config, key string) (any, bool) {
 				return c.TCPRequireAuth, true
 			case "tcp_allowed_ips":
 				return c.TCPAllowedIPs, true
+			case "web_addr":
+				return c.WebAddr, true
+			case "web_allowed_ips":
+				return c.WebAllowedIPs, true
+			case "allowed_peer_users":
+				return c.AllowedPeerUsers, true
+			case "allowed_peer_groups":
+				return c.AllowedPeerGroups, true
+			case "allowed_peer_access":
+				return c.AllowedPeerAccess, true
 			case "log_level":
 				return c.LogLevel, true
 			case "pid_file":
 				return c.PIDFile, true
+			case "client_requests_per_second":
+				return c.ClientRequestsPerSecond, true
+			case "client_request_burst":
+				return c.ClientRequestBurst, true
+			case "client_max_subscriptions":
+				return c.ClientMaxSubscriptions, true
+			case "session_requests_per_minute":
+				return c.SessionRequestsPerMinute, true
+			case "subscriber_queue_size":
+				return c.SubscriberQueueSize, true
+			case "subscriber_overflow":
+				return c.SubscriberOverflow, true
 			default:
 				return nil, false
 			}
@@ -288,6 +459,14 @@ func GetValue(cfg Config, key string) (any, bool) {
 				return c.MaxRequestsPerMinute, true
 			case "rate_limit_action":
 				return c.RateLimitAction, true
+			case "max_sessions_per_project":
+				return c.MaxSessionsPerProject, true
+			case "max_sessions_per_agent":
+				return c.MaxSessionsPerAgent, true
+			case "session_limit_action":
+				return c.SessionLimitAction, true
+			case "session_queue_timeout_seconds":
+				return c.SessionQueueTimeoutSecs, true
 			default:
 				return nil, false
 			}
@@ -299,8 +478,16 @@ func GetValue(cfg Config, key string) (any, bool) {
 				return c.DesktopDelaySecs, true
 			case "webhook_url":
 				return c.WebhookURL, true
-			case "email_enabled":
-				return c.EmailEnabled, true
+			case "reviewer_inactivity_minutes":
+				return c.ReviewerInactivityMinutes, true
+			case "review_sla_critical_minutes":
+				return c.ReviewSLACriticalMinutes, true
+			case "review_sla_dangerous_minutes":
+				return c.ReviewSLADangerousMinutes, true
+			case "review_sla_caution_minutes":
+				return c.ReviewSLACautionMinutes, true
+			case "review_sla_escalate":
+				return c.ReviewSLAEscalate, true
 			default:
 				return nil, false
 			}
@@ -314,6 +501,8 @@ func GetValue(cfg Config, key string) (any, bool) {
 				return c.RetentionDays, true
 			case "auto_git_commit":
 				return c.AutoGitCommit, true
+			case "reconcile_interval_minutes":
+				return c.ReconcileIntervalMinutes, true
 			default:
 				return nil, false
 			}
@@ -327,6 +516,8 @@ func GetValue(cfg Config, key string) (any, bool) {
 				current = c.Caution
 			case "safe":
 				current = c.Safe
+			case "packs":
+				return c.Packs, true
 			default:
 				return nil, false
 			}
@@ -340,6 +531,12 @@ func GetValue(cfg Config, key string) (any, bool) {
 				return c.DynamicQuorumFloor, true
 			case "auto_approve_delay_seconds":
 				return c.AutoApproveDelaySeconds, true
+			case "auto_approve":
+				return c.AutoApprove, true
+			case "auto_approve_notify":
+				return c.AutoApproveNotify, true
+			case "auto_approve_max_score":
+				return c.AutoApproveMaxScore, true
 			case "patterns":
 				return c.Patterns, true
 			default:
@@ -353,6 +550,20 @@ func GetValue(cfg Config, key string) (any, bool) {
 				return c.AgentMailThread, true
 			case "claude_hooks_enabled":
 				return c.ClaudeHooksEnabled, true
+			case "hook_auto_request":
+				return c.HookAutoRequest, true
+			case "hook_reuse_window_minutes":
+				return c.HookReuseWindowMins, true
+			case "hook_reuse_tiers":
+				return c.HookReuseTiers, true
+			case "llm_review_enabled":
+				return c.LLMReviewEnabled, true
+			case "llm_review_endpoint":
+				return c.LLMReviewEndpoint, true
+			case "llm_review_model":
+				return c.LLMReviewModel, true
+			case "llm_review_timeout_seconds":
+				return c.LLMReviewTimeoutSecs, true
 			default:
 				return nil, false
 			}
@@ -364,6 +575,125 @@ func GetValue(cfg Config, key string) (any, bool) {
 				return c.TrustedSelfApproveDelaySecs, true
 			case "blocked":
 				return c.Blocked, true
+			case "tier_overrides":
+				return c.TierOverrides, true
+			default:
+				return nil, false
+			}
+		case ExecutionWindowsConfig:
+			switch seg {
+			case "enabled":
+				return c.Enabled, true
+			case "quiet_hours":
+				return c.QuietHours, true
+			case "block_weekends":
+				return c.BlockWeekends, true
+			case "tiers":
+				return c.Tiers, true
+			case "timezone":
+				return c.Timezone, true
+			default:
+				return nil, false
+			}
+		case TelemetryConfig:
+			switch seg {
+			case "enabled":
+				return c.Enabled, true
+			case "endpoint":
+				return c.Endpoint, true
+			case "interval_hours":
+				return c.IntervalHours, true
+			default:
+				return nil, false
+			}
+		case SudoModeConfig:
+			switch seg {
+			case "enabled":
+				return c.Enabled, true
+			case "method":
+				return c.Method, true
+			case "tiers":
+				return c.Tiers, true
+			case "fido2_command":
+				return c.FIDO2Command, true
+			case "max_age_seconds":
+				return c.MaxAgeSecs, true
+			default:
+				return nil, false
+			}
+		case AnomalyConfig:
+			switch seg {
+			case "enabled":
+				return c.Enabled, true
+			case "burst_window_seconds":
+				return c.BurstWindowSecs, true
+			case "burst_threshold":
+				return c.BurstThreshold, true
+			case "novel_family":
+				return c.NovelFamily, true
+			case "min_history":
+				return c.MinHistory, true
+			case "baseline_days":
+				return c.BaselineDays, true
+			default:
+				return nil, false
+			}
+		case TargetsConfig:
+			switch seg {
+			case "production_hosts":
+				return c.ProductionHosts, true
+			default:
+				return nil, false
+			}
+		case ReviewersConfig:
+			switch seg {
+			case "instructions":
+				return c.Instructions, true
+			case "token_budget":
+				return c.TokenBudget, true
+			case "qr_url":
+				return c.QRURL, true
+			default:
+				return nil, false
+			}
+		case JobsConfig:
+			switch seg {
+			case "expiry_sweep":
+				current = c.ExpirySweep
+			case "review_reminders":
+				current = c.ReviewReminders
+			case "retention_prune":
+				current = c.RetentionPrune
+			case "weekly_digest":
+				current = c.WeeklyDigest
+			case "reminder_minutes":
+				return c.ReminderMinutes, true
+			case "reminder_max_minutes":
+				return c.ReminderMaxMinutes, true
+			default:
+				return nil, false
+			}
+		case JobConfig:
+			switch seg {
+			case "enabled":
+				return c.Enabled, true
+			case "schedule":
+				return c.Schedule, true
+			default:
+				return nil, false
+			}
+		case IsolationConfig:
+			switch seg {
+			case "mode":
+				return c.Mode, true
+			case "user":
+				return c.User, true
+			case "runtime":
+				return c.Runtime, true
+			case "image":
+				return c.Image, true
+			case "network":
+				return c.Network, true
 			default:
 				return nil, false
 			}
@@ -458,66 +788,160 @@ var keyKinds = map[string]valueKind{
 	"general.request_timeout":               kindInt,
 	"general.approval_ttl_minutes":          kindInt,
 	"general.approval_ttl_critical_minutes": kindInt,
+	"general.idempotency_ttl_minutes":       kindInt,
 	"general.timeout_action":                kindString,
 	"general.enable_dry_run":                kindBool,
 	"general.enable_rollback_capture":       kindBool,
 	"general.max_rollback_size_mb":          kindInt,
 	"general.cross_project_reviews":         kindBool,
 	"general.review_pool":                   kindStringSlice,
+	"general.breakglass_cooldown_hours":     kindInt,
+	"general.breakglass_ack_hours":          kindInt,
+	"general.locale":                        kindString,
+	"general.timezone":                      kindString,
+	"general.timestamps":                    kindString,
+	"general.session_key_store":             kindString,
+	"general.pinned_env":                    kindStringSlice,
 
-	"daemon.use_file_watcher": kindBool,
-	"daemon.ipc_socket":       kindString,
-	"daemon.tcp_addr":         kindString,
-	"daemon.tcp_require_auth": kindBool,
-	"daemon.tcp_allowed_ips":  kindStringSlice,
-	"daemon.log_level":        kindString,
-	"daemon.pid_file":         kindString,
+	"daemon.use_file_watcher":    kindBool,
+	"daemon.ipc_socket":          kindString,
+	"daemon.tcp_addr":            kindString,
+	"daemon.tcp_require_auth":    kindBool,
+	"daemon.tcp_allowed_ips":     kindStringSlice,
+	"daemon.web_addr":            kindString,
+	"daemon.web_allowed_ips":     kindStringSlice,
+	"daemon.allowed_peer_users":  kindStringSlice,
+	"daemon.allowed_peer_groups": kindStringSlice,
+	"daemon.allowed_peer_access": kindString,
+	"daemon.log_level":           kindString,
+	"daemon.pid_file":            kindString,
+
+	"daemon.client_requests_per_second":  kindInt,
+	"daemon.client_request_burst":        kindInt,
+	"daemon.client_max_subscriptions":    kindInt,
+	"daemon.session_requests_per_minute": kindInt,
+	"daemon.subscriber_queue_size":       kindInt,
+	"daemon.subscriber_overflow":         kindString,
 
 	"rate_limits.max_pending_per_session": kindInt,
 	"rate_limits.max_requests_per_minute": kindInt,
 	"rate_limits.rate_limit_action":       kindString,
 
-	"notifications.desktop_enabled":       kindBool,
-	"notifications.desktop_delay_seconds": kindInt,
-	"notifications.webhook_url":           kindString,
-	"notifications.email_enabled":         kindBool,
+	"rate_limits.max_sessions_per_project":      kindInt,
+	"rate_limits.max_sessions_per_agent":        kindInt,
+	"rate_limits.session_limit_action":          kindString,
+	"rate_limits.session_queue_timeout_seconds": kindInt,
+
+	"notifications.desktop_enabled":              kindBool,
+	"notifications.desktop_delay_seconds":        kindInt,
+	"notifications.webhook_url":                  kindString,
+	"notifications.reviewer_inactivity_minutes":  kindInt,
+	"notifications.review_sla_critical_minutes":  kindInt,
+	"notifications.review_sla_dangerous_minutes": kindInt,
+	"notifications.review_sla_caution_minutes":   kindInt,
+	"notifications.review_sla_escalate":          kindBool,
 
-	"history.database_path":   kindString,
-	"history.git_repo_path":   kindString,
-	"history.retention_days":  kindInt,
-	"history.auto_git_commit": kindBool,
+	"history.database_path":              kindString,
+	"history.git_repo_path":              kindString,
+	"history.retention_days":             kindInt,
+	"history.auto_git_commit":            kindBool,
+	"history.reconcile_interval_minutes": kindInt,
 
 	"patterns.critical.min_approvals":              kindInt,
 	"patterns.critical.dynamic_quorum":             kindBool,
 	"patterns.critical.dynamic_quorum_floor":       kindInt,
 	"patterns.critical.auto_approve_delay_seconds": kindInt,
+	"patterns.critical.auto_approve":               kindBool,
+	"patterns.critical.auto_approve_notify":        kindBool,
+	"patterns.critical.auto_approve_max_score":     kindInt,
 	"patterns.critical.patterns":                   kindStringSlice,
 
 	"patterns.dangerous.min_approvals":              kindInt,
 	"patterns.dangerous.dynamic_quorum":             kindBool,
 	"patterns.dangerous.dynamic_quorum_floor":       kindInt,
 	"patterns.dangerous.auto_approve_delay_seconds": kindInt,
+	"patterns.dangerous.auto_approve":               kindBool,
+	"patterns.dangerous.auto_approve_notify":        kindBool,
+	"patterns.dangerous.auto_approve_max_score":     kindInt,
 	"patterns.dangerous.patterns":                   kindStringSlice,
 
 	"patterns.caution.min_approvals":              kindInt,
 	"patterns.caution.dynamic_quorum":             kindBool,
 	"patterns.caution.dynamic_quorum_floor":       kindInt,
 	"patterns.caution.auto_approve_delay_seconds": kindInt,
+	"patterns.caution.auto_approve":               kindBool,
+	"patterns.caution.auto_approve_notify":        kindBool,
+	"patterns.caution.auto_approve_max_score":     kindInt,
 	"patterns.caution.patterns":                   kindStringSlice,
 
 	"patterns.safe.min_approvals":              kindInt,
 	"patterns.safe.dynamic_quorum":             kindBool,
 	"patterns.safe.dynamic_quorum_floor":       kindInt,
 	"patterns.safe.auto_approve_delay_seconds": kindInt,
+	"patterns.safe.auto_approve":               kindBool,
+	"patterns.safe.auto_approve_notify":        kindBool,
+	"patterns.safe.auto_approve_max_score":     kindInt,
 	"patterns.safe.patterns":                   kindStringSlice,
 
-	"integrations.agent_mail_enabled":   kindBool,
-	"integrations.agent_mail_thread":    kindString,
-	"integrations.claude_hooks_enabled": kindBool,
+	"patterns.packs": kindStringSlice,
+
+	"integrations.agent_mail_enabled":         kindBool,
+	"integrations.agent_mail_thread":          kindString,
+	"integrations.claude_hooks_enabled":       kindBool,
+	"integrations.hook_auto_request":          kindBool,
+	"integrations.hook_reuse_window_minutes":  kindInt,
+	"integrations.hook_reuse_tiers":           kindStringSlice,
+	"integrations.llm_review_enabled":         kindBool,
+	"integrations.llm_review_endpoint":        kindString,
+	"integrations.llm_review_model":           kindString,
+	"integrations.llm_review_timeout_seconds": kindInt,
 
 	"agents.trusted_self_approve":               kindStringSlice,
 	"agents.trusted_self_approve_delay_seconds": kindInt,
 	"agents.blocked":                            kindStringSlice,
+	"agents.tier_overrides":                     kindStringSlice,
+
+	"execution_windows.enabled":        kindBool,
+	"execution_windows.quiet_hours":    kindString,
+	"execution_windows.block_weekends": kindBool,
+	"execution_windows.tiers":          kindStringSlice,
+	"execution_windows.timezone":       kindString,
+
+	"telemetry.enabled":            kindBool,
+	"telemetry.endpoint":           kindString,
+	"telemetry.interval_hours":     kindInt,
+	"sudo_mode.enabled":            kindBool,
+	"sudo_mode.method":             kindString,
+	"sudo_mode.tiers":              kindStringSlice,
+	"sudo_mode.fido2_command":      kindString,
+	"sudo_mode.max_age_seconds":    kindInt,
+	"anomaly.enabled":              kindBool,
+	"anomaly.burst_window_seconds": kindInt,
+	"anomaly.burst_threshold":      kindInt,
+	"anomaly.novel_family":         kindBool,
+	"anomaly.min_history":          kindInt,
+	"anomaly.baseline_days":        kindInt,
+	"targets.production_hosts":     kindStringSlice,
+	"reviewers.instructions":       kindString,
+	"reviewers.token_budget":       kindInt,
+	"reviewers.qr_url":             kindString,
+
+	"jobs.expiry_sweep.enabled":      kindBool,
+	"jobs.expiry_sweep.schedule":     kindString,
+	"jobs.review_reminders.enabled":  kindBool,
+	"jobs.review_reminders.schedule": kindString,
+	"jobs.retention_prune.enabled":   kindBool,
+	"jobs.retention_prune.schedule":  kindString,
+	"jobs.weekly_digest.enabled":     kindBool,
+	"jobs.weekly_digest.schedule":    kindString,
+	"jobs.reminder_minutes":          kindInt,
+	"jobs.reminder_max_minutes":      kindInt,
+
+	"isolation.mode":    kindString,
+	"isolation.user":    kindString,
+	"isolation.runtime": kindString,
+	"isolation.image":   kindString,
+	"isolation.network": kindBool,
 }
 
 var envBindings = []struct {
@@ -532,42 +956,97 @@ var envBindings = []struct {
 	{"SLB_REQUEST_TIMEOUT", "general.request_timeout", kindInt},
 	{"SLB_APPROVAL_TTL_MINUTES", "general.approval_ttl_minutes", kindInt},
 	{"SLB_APPROVAL_TTL_CRITICAL_MINUTES", "general.approval_ttl_critical_minutes", kindInt},
+	{"SLB_IDEMPOTENCY_TTL_MINUTES", "general.idempotency_ttl_minutes", kindInt},
 	{"SLB_TIMEOUT_ACTION", "general.timeout_action", kindString},
 	{"SLB_ENABLE_DRY_RUN", "general.enable_dry_run", kindBool},
 	{"SLB_ENABLE_ROLLBACK_CAPTURE", "general.enable_rollback_capture", kindBool},
 	{"SLB_MAX_ROLLBACK_SIZE_MB", "general.max_rollback_size_mb", kindInt},
 	{"SLB_CROSS_PROJECT_REVIEWS", "general.cross_project_reviews", kindBool},
 	{"SLB_REVIEW_POOL", "general.review_pool", kindStringSlice},
+	{"SLB_BREAKGLASS_COOLDOWN_HOURS", "general.breakglass_cooldown_hours", kindInt},
+	{"SLB_BREAKGLASS_ACK_HOURS", "general.breakglass_ack_hours", kindInt},
+	{"SLB_LOCALE", "general.locale", kindString},
+	{"SLB_TIMEZONE", "general.timezone", kindString},
+	{"SLB_TIMESTAMPS", "general.timestamps", kindString},
+	{"SLB_SESSION_KEY_STORE", "general.session_key_store", kindString},
+	{"SLB_PINNED_ENV", "general.pinned_env", kindStringSlice},
 
 	{"SLB_DAEMON_USE_FILE_WATCHER", "daemon.use_file_watcher", kindBool},
 	{"SLB_DAEMON_IPC_SOCKET", "daemon.ipc_socket", kindString},
 	{"SLB_DAEMON_TCP_ADDR", "daemon.tcp_addr", kindString},
 	{"SLB_DAEMON_TCP_REQUIRE_AUTH", "daemon.tcp_require_auth", kindBool},
 	{"SLB_DAEMON_TCP_ALLOWED_IPS", "daemon.tcp_allowed_ips", kindStringSlice},
+	{"SLB_DAEMON_WEB_ADDR", "daemon.web_addr", kindString},
+	{"SLB_DAEMON_ALLOWED_PEER_USERS", "daemon.allowed_peer_users", kindStringSlice},
+	{"SLB_DAEMON_ALLOWED_PEER_GROUPS", "daemon.allowed_peer_groups", kindStringSlice},
+	{"SLB_DAEMON_ALLOWED_PEER_ACCESS", "daemon.allowed_peer_access", kindString},
 	{"SLB_DAEMON_LOG_LEVEL", "daemon.log_level", kindString},
 	{"SLB_DAEMON_PID_FILE", "daemon.pid_file", kindString},
+	{"SLB_DAEMON_CLIENT_REQUESTS_PER_SECOND", "daemon.client_requests_per_second", kindInt},
+	{"SLB_DAEMON_CLIENT_REQUEST_BURST", "daemon.client_request_burst", kindInt},
+	{"SLB_DAEMON_CLIENT_MAX_SUBSCRIPTIONS", "daemon.client_max_subscriptions", kindInt},
+	{"SLB_DAEMON_SESSION_REQUESTS_PER_MINUTE", "daemon.session_requests_per_minute", kindInt},
+	{"SLB_DAEMON_SUBSCRIBER_QUEUE_SIZE", "daemon.subscriber_queue_size", kindInt},
+	{"SLB_DAEMON_SUBSCRIBER_OVERFLOW", "daemon.subscriber_overflow", kindString},
 
 	{"SLB_MAX_PENDING_PER_SESSION", "rate_limits.max_pending_per_session", kindInt},
 	{"SLB_MAX_REQUESTS_PER_MINUTE", "rate_limits.max_requests_per_minute", kindInt},
 	{"SLB_RATE_LIMIT_ACTION", "rate_limits.rate_limit_action", kindString},
+	{"SLB_MAX_SESSIONS_PER_PROJECT", "rate_limits.max_sessions_per_project", kindInt},
+	{"SLB_MAX_SESSIONS_PER_AGENT", "rate_limits.max_sessions_per_agent", kindInt},
+	{"SLB_SESSION_LIMIT_ACTION", "rate_limits.session_limit_action", kindString},
+	{"SLB_SESSION_QUEUE_TIMEOUT_SECONDS", "rate_limits.session_queue_timeout_seconds", kindInt},
 
 	{"SLB_DESKTOP_NOTIFICATIONS", "notifications.desktop_enabled", kindBool},
 	{"SLB_DESKTOP_DELAY_SECONDS", "notifications.desktop_delay_seconds", kindInt},
 	{"SLB_WEBHOOK_URL", "notifications.webhook_url", kindString},
-	{"SLB_EMAIL_ENABLED", "notifications.email_enabled", kindBool},
+	{"SLB_REVIEWER_INACTIVITY_MINUTES", "notifications.reviewer_inactivity_minutes", kindInt},
 
 	{"SLB_HISTORY_DB_PATH", "history.database_path", kindString},
 	{"SLB_HISTORY_GIT_PATH", "history.git_repo_path", kindString},
 	{"SLB_HISTORY_RETENTION_DAYS", "history.retention_days", kindInt},
 	{"SLB_HISTORY_AUTO_GIT_COMMIT", "history.auto_git_commit", kindBool},
 
+	{"SLB_PATTERN_PACKS", "patterns.packs", kindStringSlice},
+
 	{"SLB_AGENT_MAIL_ENABLED", "integrations.agent_mail_enabled", kindBool},
 	{"SLB_AGENT_MAIL_THREAD", "integrations.agent_mail_thread", kindString},
 	{"SLB_CLAUDE_HOOKS_ENABLED", "integrations.claude_hooks_enabled", kindBool},
+	{"SLB_HOOK_AUTO_REQUEST", "integrations.hook_auto_request", kindBool},
+	{"SLB_HOOK_REUSE_WINDOW_MINUTES", "integrations.hook_reuse_window_minutes", kindInt},
+	{"SLB_HOOK_REUSE_TIERS", "integrations.hook_reuse_tiers", kindStringSlice},
+	{"SLB_LLM_REVIEW_ENABLED", "integrations.llm_review_enabled", kindBool},
+	{"SLB_LLM_REVIEW_ENDPOINT", "integrations.llm_review_endpoint", kindString},
+	{"SLB_LLM_REVIEW_MODEL", "integrations.llm_review_model", kindString},
+	{"SLB_LLM_REVIEW_TIMEOUT_SECONDS", "integrations.llm_review_timeout_seconds", kindInt},
 
 	{"SLB_TRUSTED_SELF_APPROVE", "agents.trusted_self_approve", kindStringSlice},
 	{"SLB_TRUSTED_SELF_APPROVE_DELAY_SECONDS", "agents.trusted_self_approve_delay_seconds", kindInt},
 	{"SLB_BLOCKED_AGENTS", "agents.blocked", kindStringSlice},
+	{"SLB_AGENT_TIER_OVERRIDES", "agents.tier_overrides", kindStringSlice},
+
+	{"SLB_EXECUTION_WINDOWS_ENABLED", "execution_windows.enabled", kindBool},
+	{"SLB_QUIET_HOURS", "execution_windows.quiet_hours", kindString},
+	{"SLB_QUIET_BLOCK_WEEKENDS", "execution_windows.block_weekends", kindBool},
+	{"SLB_QUIET_TIERS", "execution_windows.tiers", kindStringSlice},
+	{"SLB_QUIET_TIMEZONE", "execution_windows.timezone", kindString},
+
+	{"SLB_TELEMETRY_ENABLED", "telemetry.enabled", kindBool},
+	{"SLB_TELEMETRY_ENDPOINT", "telemetry.endpoint", kindString},
+	{"SLB_TELEMETRY_INTERVAL_HOURS", "telemetry.interval_hours", kindInt},
+	{"SLB_SUDO_MODE", "sudo_mode.enabled", kindBool},
+	{"SLB_SUDO_METHOD", "sudo_mode.method", kindString},
+	{"SLB_SUDO_TIERS", "sudo_mode.tiers", kindStringSlice},
+	{"SLB_SUDO_FIDO2_COMMAND", "sudo_mode.fido2_command", kindString},
+	{"SLB_SUDO_MAX_AGE_SECONDS", "sudo_mode.max_age_seconds", kindInt},
+	{"SLB_ANOMALY", "anomaly.enabled", kindBool},
+	{"SLB_ANOMALY_BURST_WINDOW_SECONDS", "anomaly.burst_window_seconds", kindInt},
+	{"SLB_ANOMALY_BURST_THRESHOLD", "anomaly.burst_threshold", kindInt},
+	{"SLB_ANOMALY_NOVEL_FAMILY", "anomaly.novel_family", kindBool},
+	{"SLB_ANOMALY_MIN_HISTORY", "anomaly.min_history", kindInt},
+	{"SLB_ANOMALY_BASELINE_DAYS", "anomaly.baseline_days", kindInt},
+	{"SLB_PRODUCTION_HOSTS", "targets.production_hosts", kindStringSlice},
+	{"SLB_REVIEW_TOKEN_BUDGET", "reviewers.token_budget", kindInt},
 }
 
 func parseValueByKind(raw string, kind valueKind) (any, error) {
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/spf13/viper"
+)
+
+// Profiles are named sets of overrides in a config file, so one user can
+// switch between projects or environments with a single flag:
+//
+//	[profiles.staging]
+//	db = "/srv/staging/.slb/state.db"
+//
+//	[profiles.staging.daemon]
+//	tcp_addr = "staging-host:9876"
+//
+//	[profiles.staging.general]
+//	review_pool = ["ops-lead", "oncall"]
+//
+//	[profiles.staging.notifications]
+//	webhook_url = "https://hooks.example.com/staging"
+//
+// A selected profile is layered over the config files and under SLB_*
+// env vars and flags. Besides db, which the CLI uses as the default
+// database path, it may set any config key.
+
+var selectedProfile atomic.Value // string
+
+// SetProfile selects the profile Load applies when LoadOptions.Profile is
+// empty; the CLI sets it from --profile. Like the i18n locale, it is
+// process-wide.
+func SetProfile(name string) {
+	selectedProfile.Store(name)
+}
+
+// profileName returns the profile to apply: opts.Profile, else the one
+// set with SetProfile, else SLB_PROFILE.
+func profileName(opts LoadOptions) string {
+	if opts.Profile != "" {
+		return opts.Profile
+	}
+	if name, _ := selectedProfile.Load().(string); name != "" {
+		return name
+	}
+	return strings.TrimSpace(os.Getenv("SLB_PROFILE"))
+}
+
+// ActiveProfile returns the profile Load applies for opts and the database
+// path it sets, which is empty when it sets none. name is empty when no
+// profile is selected. An undefined or invalid profile is a *LoadError.
+func ActiveProfile(opts LoadOptions) (name, db string, err error) {
+	name = profileName(opts)
+	if name == "" {
+		return "", "", nil
+	}
+	v := viper.New()
+	if err := mergeConfigFiles(v, opts); err != nil {
+		return name, "", &LoadError{Err: err}
+	}
+	db, err = applyProfile(v, name)
+	if err != nil {
+		return name, "", &LoadError{Err: err}
+	}
+	return name, db, nil
+}
+
+// applyProfile sets the keys of profiles.<name> on v and returns the
+// profile's db path. name must be defined, and every key other than db
+// must be a config key.
+func applyProfile(v *viper.Viper, name string) (string, error) {
+	if name == "" {
+		return "", nil
+	}
+	sub := v.Sub("profiles." + name)
+	if sub == nil {
+		return "", fmt.Errorf("profile %q is not defined%s", name, definedProfiles(v))
+	}
+	defaults := DefaultConfig()
+	keys := sub.AllKeys()
+	sort.Strings(keys)
+	for _, key := range keys {
+		if key == "db" {
+			continue
+		}
+		if _, ok := GetValue(defaults, key); !ok {
+			return "", fmt.Errorf("profile %q: unknown key %q", name, key)
+		}
+		v.Set(key, sub.Get(key))
+	}
+	return sub.GetString("db"), nil
+}
+
+// definedProfiles lists the profiles in v for error messages.
+func definedProfiles(v *viper.Viper) string {
+	var names []string
+	for name := range v.GetStringMap("profiles") {
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return " (no [profiles.<name>] tables in config)"
+	}
+	sort.Strings(names)
+	return " (defined: " + strings.Join(names, ", ") + ")"
+}
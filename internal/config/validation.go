@@ -2,9 +2,21 @@ package config
 
 import (
 	"fmt"
+	"net/url"
+	"path"
+	"regexp"
 	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/i18n"
+	"github.com/Dicklesworthstone/slb/internal/schedule"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
 )
 
+// packNameRe matches pattern pack names. Whether a named pack exists is
+// checked when packs are enabled, since the pack registry lives in core.
+var packNameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
 // Validate checks the configuration for semantic errors.
 func Validate(cfg Config) error {
 	var errs []string
@@ -21,15 +33,78 @@ func Validate(cfg Config) error {
 	if cfg.General.ApprovalTTLCriticalMins <= 0 {
 		errs = append(errs, "general.approval_ttl_critical_minutes must be > 0")
 	}
+	if cfg.General.IdempotencyTTLMins <= 0 {
+		errs = append(errs, "general.idempotency_ttl_minutes must be > 0")
+	}
 	if cfg.General.MaxRollbackSizeMB < 0 {
 		errs = append(errs, "general.max_rollback_size_mb cannot be negative")
 	}
+	if cfg.General.BreakglassCooldownHours < 0 {
+		errs = append(errs, "general.breakglass_cooldown_hours cannot be negative")
+	}
+	if cfg.General.BreakglassAckHours <= 0 {
+		errs = append(errs, "general.breakglass_ack_hours must be > 0")
+	}
 	if !oneOf(cfg.General.ConflictResolution, "any_rejection_blocks", "first_wins", "human_breaks_tie") {
 		errs = append(errs, "general.conflict_resolution must be one of any_rejection_blocks|first_wins|human_breaks_tie")
 	}
 	if !oneOf(cfg.General.TimeoutAction, "escalate", "auto_reject", "auto_approve_warn") {
 		errs = append(errs, "general.timeout_action must be one of escalate|auto_reject|auto_approve_warn")
 	}
+	if !oneOf(cfg.General.SessionKeyStore, "auto", "keyring", "file", "off") {
+		errs = append(errs, "general.session_key_store must be one of auto|keyring|file|off")
+	}
+	for _, name := range cfg.General.PinnedEnv {
+		if !validEnvName(name) {
+			errs = append(errs, fmt.Sprintf("general.pinned_env: invalid variable name %q", name))
+		}
+	}
+	if cfg.General.Locale != "" && !i18n.IsSupported(cfg.General.Locale) {
+		errs = append(errs, fmt.Sprintf("general.locale must be one of %s (or empty to detect)", strings.Join(i18n.Supported(), "|")))
+	}
+	if _, err := timefmt.ParseStyle(cfg.General.Timestamps); err != nil {
+		errs = append(errs, "general.timestamps must be one of relative|absolute")
+	}
+	if _, err := timefmt.LoadLocation(cfg.General.Timezone); err != nil {
+		errs = append(errs, fmt.Sprintf("general.timezone: unknown timezone %q", cfg.General.Timezone))
+	}
+
+	for _, list := range []struct {
+		key     string
+		entries []string
+	}{
+		{"daemon.allowed_peer_users", cfg.Daemon.AllowedPeerUsers},
+		{"daemon.allowed_peer_groups", cfg.Daemon.AllowedPeerGroups},
+	} {
+		for _, e := range list.entries {
+			if strings.TrimSpace(e) == "" {
+				errs = append(errs, list.key+" entries cannot be blank")
+				break
+			}
+		}
+	}
+	if !oneOf(cfg.Daemon.AllowedPeerAccess, "read", "write") {
+		errs = append(errs, "daemon.allowed_peer_access must be one of read|write")
+	}
+	for _, limit := range []struct {
+		key   string
+		value int
+	}{
+		{"daemon.client_requests_per_second", cfg.Daemon.ClientRequestsPerSecond},
+		{"daemon.client_request_burst", cfg.Daemon.ClientRequestBurst},
+		{"daemon.client_max_subscriptions", cfg.Daemon.ClientMaxSubscriptions},
+		{"daemon.session_requests_per_minute", cfg.Daemon.SessionRequestsPerMinute},
+	} {
+		if limit.value < 0 {
+			errs = append(errs, limit.key+" cannot be negative")
+		}
+	}
+	if cfg.Daemon.SubscriberQueueSize < 1 {
+		errs = append(errs, "daemon.subscriber_queue_size must be at least 1")
+	}
+	if !oneOf(cfg.Daemon.SubscriberOverflow, "drop-oldest", "disconnect") {
+		errs = append(errs, "daemon.subscriber_overflow must be one of drop-oldest|disconnect")
+	}
 
 	if cfg.RateLimits.MaxPendingPerSession < 0 {
 		errs = append(errs, "rate_limits.max_pending_per_session cannot be negative")
@@ -40,14 +115,35 @@ func Validate(cfg Config) error {
 	if !oneOf(cfg.RateLimits.RateLimitAction, "reject", "queue", "warn") {
 		errs = append(errs, "rate_limits.rate_limit_action must be one of reject|queue|warn")
 	}
+	if cfg.RateLimits.MaxSessionsPerProject < 0 {
+		errs = append(errs, "rate_limits.max_sessions_per_project cannot be negative")
+	}
+	if cfg.RateLimits.MaxSessionsPerAgent < 0 {
+		errs = append(errs, "rate_limits.max_sessions_per_agent cannot be negative")
+	}
+	if !oneOf(cfg.RateLimits.SessionLimitAction, "reject", "queue") {
+		errs = append(errs, "rate_limits.session_limit_action must be one of reject|queue")
+	}
+	if cfg.RateLimits.SessionLimitAction == "queue" && cfg.RateLimits.SessionQueueTimeoutSecs < 1 {
+		errs = append(errs, "rate_limits.session_queue_timeout_seconds must be at least 1 when queueing")
+	}
 
 	if cfg.Notifications.DesktopDelaySecs < 0 {
 		errs = append(errs, "notifications.desktop_delay_seconds cannot be negative")
 	}
+	if cfg.Notifications.ReviewerInactivityMinutes < 0 {
+		errs = append(errs, "notifications.reviewer_inactivity_minutes cannot be negative")
+	}
+	if cfg.Notifications.ReviewSLACriticalMinutes < 0 || cfg.Notifications.ReviewSLADangerousMinutes < 0 || cfg.Notifications.ReviewSLACautionMinutes < 0 {
+		errs = append(errs, "notifications.review_sla_*_minutes cannot be negative")
+	}
 
 	if cfg.History.RetentionDays < 0 {
 		errs = append(errs, "history.retention_days cannot be negative")
 	}
+	if cfg.History.ReconcileIntervalMinutes < 0 {
+		errs = append(errs, "history.reconcile_interval_minutes cannot be negative")
+	}
 
 	validateTier := func(name string, tier PatternTierConfig) {
 		if tier.MinApprovals < 0 {
@@ -59,15 +155,160 @@ func Validate(cfg Config) error {
 		if tier.AutoApproveDelaySeconds < 0 {
 			errs = append(errs, fmt.Sprintf("patterns.%s.auto_approve_delay_seconds cannot be negative", name))
 		}
+		if tier.AutoApproveMaxScore < 0 || tier.AutoApproveMaxScore > 100 {
+			errs = append(errs, fmt.Sprintf("patterns.%s.auto_approve_max_score must be between 0 and 100", name))
+		}
+	}
+	// Higher tiers always need explicit approval.
+	if cfg.Patterns.Critical.AutoApprove {
+		errs = append(errs, "patterns.critical.auto_approve cannot be enabled")
+	}
+	if cfg.Patterns.Dangerous.AutoApprove {
+		errs = append(errs, "patterns.dangerous.auto_approve cannot be enabled")
 	}
 	validateTier("critical", cfg.Patterns.Critical)
 	validateTier("dangerous", cfg.Patterns.Dangerous)
 	validateTier("caution", cfg.Patterns.Caution)
 	validateTier("safe", cfg.Patterns.Safe)
+	for _, pack := range cfg.Patterns.Packs {
+		if !packNameRe.MatchString(strings.TrimSpace(pack)) {
+			errs = append(errs, fmt.Sprintf("patterns.packs: invalid pack name %q", pack))
+		}
+	}
+
+	if cfg.Integrations.LLMReviewTimeoutSecs < 0 {
+		errs = append(errs, "integrations.llm_review_timeout_seconds cannot be negative")
+	}
+	if cfg.Integrations.LLMReviewEnabled && strings.TrimSpace(cfg.Integrations.LLMReviewEndpoint) == "" {
+		errs = append(errs, "integrations.llm_review_endpoint is required when llm_review_enabled is true")
+	}
+
+	if cfg.Integrations.HookReuseWindowMins < 0 {
+		errs = append(errs, "integrations.hook_reuse_window_minutes cannot be negative")
+	}
+	for _, tier := range cfg.Integrations.HookReuseTiers {
+		if !oneOf(strings.ToLower(strings.TrimSpace(tier)), "critical", "dangerous", "caution") {
+			errs = append(errs, fmt.Sprintf("integrations.hook_reuse_tiers: invalid tier %q", tier))
+		}
+	}
 
 	if cfg.Agents.TrustedSelfApproveDelaySecs < 0 {
 		errs = append(errs, "agents.trusted_self_approve_delay_seconds cannot be negative")
 	}
+	for _, entry := range cfg.Agents.TierOverrides {
+		if !validTierOverride(entry) {
+			errs = append(errs, fmt.Sprintf("agents.tier_overrides: invalid entry %q (want SELECTOR=TIER or SELECTOR=skip_caution)", entry))
+		}
+	}
+
+	if cfg.ExecutionWindows.Enabled {
+		if qh := strings.TrimSpace(cfg.ExecutionWindows.QuietHours); qh != "" && !validClockRange(qh) {
+			errs = append(errs, "execution_windows.quiet_hours must be HH:MM-HH:MM")
+		}
+		for _, tier := range cfg.ExecutionWindows.Tiers {
+			if !oneOf(strings.ToLower(strings.TrimSpace(tier)), "critical", "dangerous", "caution") {
+				errs = append(errs, fmt.Sprintf("execution_windows.tiers: invalid tier %q", tier))
+			}
+		}
+		if tz := strings.TrimSpace(cfg.ExecutionWindows.Timezone); tz != "" {
+			if _, err := time.LoadLocation(tz); err != nil {
+				errs = append(errs, fmt.Sprintf("execution_windows.timezone: unknown timezone %q", tz))
+			}
+		}
+	}
+
+	if cfg.SudoMode.Enabled {
+		if !oneOf(cfg.SudoMode.Method, "passphrase", "fido2") {
+			errs = append(errs, "sudo_mode.method must be one of passphrase|fido2")
+		}
+		if cfg.SudoMode.Method == "fido2" && strings.TrimSpace(cfg.SudoMode.FIDO2Command) == "" {
+			errs = append(errs, "sudo_mode.fido2_command is required when sudo_mode.method is fido2")
+		}
+		for _, tier := range cfg.SudoMode.Tiers {
+			if !oneOf(strings.ToLower(strings.TrimSpace(tier)), "critical", "dangerous", "caution") {
+				errs = append(errs, fmt.Sprintf("sudo_mode.tiers: invalid tier %q", tier))
+			}
+		}
+		if cfg.SudoMode.MaxAgeSecs < 1 {
+			errs = append(errs, "sudo_mode.max_age_seconds must be at least 1")
+		}
+	}
+
+	if cfg.Anomaly.Enabled {
+		if cfg.Anomaly.BurstWindowSecs < 1 {
+			errs = append(errs, "anomaly.burst_window_seconds must be at least 1")
+		}
+		if cfg.Anomaly.BurstThreshold < 2 {
+			errs = append(errs, "anomaly.burst_threshold must be at least 2")
+		}
+		if cfg.Anomaly.MinHistory < 0 {
+			errs = append(errs, "anomaly.min_history cannot be negative")
+		}
+		if cfg.Anomaly.BaselineDays < 1 {
+			errs = append(errs, "anomaly.baseline_days must be at least 1")
+		}
+	}
+
+	for _, p := range cfg.Targets.ProductionHosts {
+		if _, err := path.Match(strings.TrimSpace(p), ""); err != nil {
+			errs = append(errs, fmt.Sprintf("targets.production_hosts: invalid pattern %q", p))
+		}
+	}
+
+	if cfg.Reviewers.TokenBudget < 0 {
+		errs = append(errs, "reviewers.token_budget cannot be negative")
+	}
+	if u := cfg.Reviewers.QRURL; u != "" &&
+		(!strings.Contains(u, "{id}") || !(strings.HasPrefix(u, "https://") || strings.HasPrefix(u, "http://"))) {
+		errs = append(errs, "reviewers.qr_url must be an http(s) URL containing {id}")
+	}
+
+	for _, job := range []struct {
+		name string
+		cfg  JobConfig
+	}{
+		{"expiry_sweep", cfg.Jobs.ExpirySweep},
+		{"review_reminders", cfg.Jobs.ReviewReminders},
+		{"retention_prune", cfg.Jobs.RetentionPrune},
+		{"weekly_digest", cfg.Jobs.WeeklyDigest},
+	} {
+		if _, err := schedule.Parse(job.cfg.Schedule); err != nil {
+			errs = append(errs, fmt.Sprintf("jobs.%s.schedule: %v", job.name, err))
+		}
+	}
+	if cfg.Jobs.ReminderMinutes < 1 {
+		errs = append(errs, "jobs.reminder_minutes must be at least 1")
+	}
+	if cfg.Jobs.ReminderMaxMinutes < cfg.Jobs.ReminderMinutes {
+		errs = append(errs, "jobs.reminder_max_minutes cannot be less than jobs.reminder_minutes")
+	}
+
+	switch cfg.Isolation.Mode {
+	case "", "none":
+	case "user":
+		if strings.TrimSpace(cfg.Isolation.User) == "" {
+			errs = append(errs, "isolation.user is required when isolation.mode is user")
+		}
+	case "container":
+		if !oneOf(cfg.Isolation.Runtime, "docker", "podman") {
+			errs = append(errs, "isolation.runtime must be one of docker|podman")
+		}
+		if strings.TrimSpace(cfg.Isolation.Image) == "" {
+			errs = append(errs, "isolation.image is required when isolation.mode is container")
+		}
+	default:
+		errs = append(errs, "isolation.mode must be one of none|user|container")
+	}
+
+	if cfg.Telemetry.IntervalHours < 1 {
+		errs = append(errs, "telemetry.interval_hours must be at least 1")
+	}
+	if cfg.Telemetry.Enabled {
+		endpoint := strings.TrimSpace(cfg.Telemetry.Endpoint)
+		if u, err := url.Parse(endpoint); endpoint == "" || err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, "telemetry.endpoint must be an http(s) URL when telemetry is enabled")
+		}
+	}
 
 	if len(errs) > 0 {
 		return fmt.Errorf("config validation failed: %s", strings.Join(errs, "; "))
@@ -83,3 +324,53 @@ func oneOf(val string, options ...string) bool {
 	}
 	return false
 }
+
+// validClockRange reports whether s has the form HH:MM-HH:MM.
+// validTierOverride checks the SELECTOR=ACTION form of an
+// agents.tier_overrides entry; core.ParseTierOverride interprets it.
+func validTierOverride(entry string) bool {
+	selector, action, ok := strings.Cut(entry, "=")
+	selector = strings.TrimSpace(selector)
+	if !ok || selector == "" {
+		return false
+	}
+	if kind, glob, hasKind := strings.Cut(selector, ":"); hasKind {
+		if !oneOf(strings.ToLower(kind), "program", "model", "branch", "stage") || strings.TrimSpace(glob) == "" {
+			return false
+		}
+		selector = glob
+	}
+	if _, err := path.Match(strings.TrimSpace(selector), ""); err != nil {
+		return false
+	}
+	return oneOf(strings.ToLower(strings.TrimSpace(action)), "critical", "dangerous", "caution", "skip_caution")
+}
+
+func validClockRange(s string) bool {
+	parts := strings.Split(s, "-")
+	if len(parts) != 2 {
+		return false
+	}
+	for _, p := range parts {
+		if _, err := time.Parse("15:04", strings.TrimSpace(p)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// validEnvName reports whether name is a portable environment variable name.
+func validEnvName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
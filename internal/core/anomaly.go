@@ -0,0 +1,170 @@
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// AnomalyConfig controls command frequency anomaly detection. The zero
+// value disables it.
+type AnomalyConfig struct {
+	Enabled bool
+	// BurstWindow and BurstThreshold flag a session issuing at least
+	// BurstThreshold commands of one family within the window.
+	BurstWindow    time.Duration
+	BurstThreshold int
+	// NovelFamily flags the first command of a family in a project that
+	// already has MinHistory observations.
+	NovelFamily bool
+	MinHistory  int
+	// BaselineDays is how long observations are kept.
+	BaselineDays int
+}
+
+// subcommandPrograms are tools whose first word names what they do, so
+// their family includes it: "aws iam" is not "aws s3".
+var subcommandPrograms = map[string]bool{
+	"aws": true, "az": true, "gcloud": true, "gsutil": true, "kubectl": true,
+	"helm": true, "docker": true, "podman": true, "terraform": true,
+	"git": true, "gh": true, "npm": true, "pnpm": true, "yarn": true,
+	"cargo": true, "go": true, "systemctl": true, "vault": true,
+	"heroku": true, "fly": true, "flyctl": true, "doctl": true,
+}
+
+// CommandFamily returns the family a command belongs to for anomaly
+// detection: the program name, plus the first subcommand for tools such
+// as aws or kubectl ("rm", "aws iam", "git reset"). Wrappers and env
+// assignments are stripped first. It returns "" when the command has no
+// usable program name.
+func CommandFamily(cmd string) string {
+	fields := strings.Fields(NormalizeCommand(cmd).Primary)
+	for len(fields) > 0 && isEnvAssignment(fields[0]) {
+		fields = fields[1:]
+	}
+	if len(fields) == 0 {
+		return ""
+	}
+	name := strings.ToLower(filepath.Base(fields[0]))
+	if !subcommandToken.MatchString(name) {
+		return ""
+	}
+	// Global flags before the subcommand may take values, so the family
+	// falls back to the program name rather than guessing past them.
+	if subcommandPrograms[name] && len(fields) > 1 && subcommandToken.MatchString(fields[1]) {
+		return name + " " + strings.ToLower(fields[1])
+	}
+	return name
+}
+
+// Anomaly describes how a command breaks from its project's history.
+type Anomaly struct {
+	// Family is the command family that was flagged.
+	Family string
+	// Burst is set when the session reached the burst threshold.
+	Burst bool
+	// Novel is set when the project has never seen the family.
+	Novel bool
+	// Notes describe each finding for reviewers.
+	Notes []string
+}
+
+// DetectAnomaly compares a command against the baseline and returns what
+// is anomalous about it, or nil. The command itself is not yet part of
+// the baseline.
+func DetectAnomaly(cfg AnomalyConfig, family string, b db.FamilyBaseline) *Anomaly {
+	if !cfg.Enabled || family == "" {
+		return nil
+	}
+	a := &Anomaly{Family: family}
+	if cfg.BurstThreshold > 0 && b.SessionRecent+1 >= cfg.BurstThreshold {
+		a.Burst = true
+		a.Notes = append(a.Notes, fmt.Sprintf("anomaly: %d `%s` commands from this session within %s (threshold %d)",
+			b.SessionRecent+1, family, cfg.BurstWindow, cfg.BurstThreshold))
+	}
+	if cfg.NovelFamily && b.FamilyObservations == 0 && b.ProjectObservations >= cfg.MinHistory && b.ProjectObservations > 0 {
+		a.Novel = true
+		a.Notes = append(a.Notes, fmt.Sprintf("anomaly: first `%s` command in this project (%d commands observed)",
+			family, b.ProjectObservations))
+	}
+	if !a.Burst && !a.Novel {
+		return nil
+	}
+	return a
+}
+
+// ApplyAnomaly raises the tier of an anomalous command one step and notes
+// why: a command that needs approval moves up a tier, and an unmatched
+// command becomes caution so it is tracked. Safe commands are left alone.
+// res is not modified; a copy is returned when anything changes.
+func ApplyAnomaly(res *MatchResult, a *Anomaly) *MatchResult {
+	if res == nil || a == nil || res.IsSafe {
+		return res
+	}
+	out := *res
+	out.Explanation = append(append([]string(nil), res.Explanation...), a.Notes...)
+	raised := RiskTierCaution
+	if res.NeedsApproval {
+		raised = upgradeTier(res.Tier)
+	}
+	if tierRank(raised) > tierRank(res.Tier) {
+		out.Explanation = append(out.Explanation, fmt.Sprintf("tier raised from %s to %s by anomaly detection", tierLabel(res.Tier), raised))
+		out.Tier = raised
+		out.MinApprovals = tierApprovals(raised)
+	}
+	out.NeedsApproval = true
+	return &out
+}
+
+// Attachment returns the anomaly as a context attachment for reviewers.
+func (a *Anomaly) Attachment() *db.Attachment {
+	return &db.Attachment{
+		Type:    db.AttachmentTypeContext,
+		Content: strings.Join(a.Notes, "\n"),
+		Metadata: map[string]any{
+			"type":   "anomaly",
+			"family": a.Family,
+			"burst":  a.Burst,
+			"novel":  a.Novel,
+		},
+	}
+}
+
+func tierLabel(t RiskTier) string {
+	if t == "" {
+		return "unmatched"
+	}
+	return string(t)
+}
+
+// checkAnomaly records the command in the project's baseline and returns
+// the anomaly it represents, if detection is enabled. Every failure is
+// ignored: anomaly detection never blocks a request.
+func (rc *RequestCreator) checkAnomaly(command, projectPath, sessionID string) *Anomaly {
+	if rc.config == nil || !rc.config.Anomaly.Enabled || projectPath == "" {
+		return nil
+	}
+	cfg := rc.config.Anomaly
+	family := CommandFamily(command)
+	if family == "" {
+		return nil
+	}
+	now := rc.db.Now()
+	b, err := rc.db.CommandFamilyBaseline(projectPath, family, sessionID, now.Add(-cfg.BurstWindow))
+	if err != nil {
+		return nil
+	}
+	_ = rc.db.RecordCommandObservation(&db.CommandObservation{
+		ProjectPath: projectPath,
+		SessionID:   sessionID,
+		Family:      family,
+		ObservedAt:  now,
+	})
+	if cfg.BaselineDays > 0 {
+		_, _ = rc.db.PruneCommandObservations(now.AddDate(0, 0, -cfg.BaselineDays))
+	}
+	return DetectAnomaly(cfg, family, b)
+}
@@ -0,0 +1,150 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestCommandFamily(t *testing.T) {
+	tests := []struct {
+		cmd  string
+		want string
+	}{
+		{"rm -rf build", "rm"},
+		{"/bin/rm notes.txt", "rm"},
+		{"sudo rm -f /tmp/x", "rm"},
+		{"aws iam create-user --user-name bob", "aws iam"},
+		{"aws --profile prod s3 ls", "aws"},
+		{"git reset --hard HEAD~1", "git reset"},
+		{"KUBECONFIG=/tmp/k kubectl delete pod web", "kubectl delete"},
+		{"git", "git"},
+		{"make clean && echo done", "make"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := CommandFamily(tt.cmd); got != tt.want {
+			t.Errorf("CommandFamily(%q) = %q, want %q", tt.cmd, got, tt.want)
+		}
+	}
+}
+
+func TestDetectAnomaly(t *testing.T) {
+	cfg := AnomalyConfig{Enabled: true, BurstWindow: time.Minute, BurstThreshold: 5, NovelFamily: true, MinHistory: 10}
+
+	if a := DetectAnomaly(cfg, "rm", db.FamilyBaseline{ProjectObservations: 100, FamilyObservations: 10, SessionRecent: 3}); a != nil {
+		t.Errorf("ordinary command flagged: %+v", a)
+	}
+	a := DetectAnomaly(cfg, "rm", db.FamilyBaseline{ProjectObservations: 100, FamilyObservations: 10, SessionRecent: 4})
+	if a == nil || !a.Burst || a.Novel || !strings.Contains(a.Notes[0], "5 `rm` commands") {
+		t.Errorf("burst = %+v", a)
+	}
+	a = DetectAnomaly(cfg, "aws iam", db.FamilyBaseline{ProjectObservations: 10})
+	if a == nil || a.Burst || !a.Novel || !strings.Contains(a.Notes[0], "first `aws iam` command") {
+		t.Errorf("novel = %+v", a)
+	}
+
+	// A project without enough history has nothing to be novel against.
+	if a := DetectAnomaly(cfg, "aws iam", db.FamilyBaseline{ProjectObservations: 9}); a != nil {
+		t.Errorf("young project flagged: %+v", a)
+	}
+	cfg.NovelFamily = false
+	if a := DetectAnomaly(cfg, "aws iam", db.FamilyBaseline{ProjectObservations: 10}); a != nil {
+		t.Errorf("novel_family off flagged: %+v", a)
+	}
+	if a := DetectAnomaly(AnomalyConfig{}, "rm", db.FamilyBaseline{SessionRecent: 100}); a != nil {
+		t.Errorf("disabled detection flagged: %+v", a)
+	}
+}
+
+func TestApplyAnomaly(t *testing.T) {
+	a := &Anomaly{Family: "rm", Burst: true, Notes: []string{"anomaly: burst"}}
+
+	caution := &MatchResult{Tier: RiskTierCaution, NeedsApproval: true}
+	got := ApplyAnomaly(caution, a)
+	if got.Tier != RiskTierDangerous || got.MinApprovals != 1 || len(got.Explanation) != 2 {
+		t.Errorf("caution: %+v", got)
+	}
+	if caution.Tier != RiskTierCaution || len(caution.Explanation) != 0 {
+		t.Error("the input classification was modified")
+	}
+
+	got = ApplyAnomaly(&MatchResult{}, a)
+	if got.Tier != RiskTierCaution || !got.NeedsApproval || !strings.Contains(got.Explanation[1], "from unmatched to caution") {
+		t.Errorf("unmatched: %+v", got)
+	}
+
+	if got := ApplyAnomaly(&MatchResult{Tier: RiskTierCritical, NeedsApproval: true, MinApprovals: 2}, a); got.Tier != RiskTierCritical || len(got.Explanation) != 1 {
+		t.Errorf("critical: %+v", got)
+	}
+	safe := &MatchResult{Tier: RiskTier(RiskSafe), IsSafe: true}
+	if got := ApplyAnomaly(safe, a); got != safe {
+		t.Errorf("safe command changed: %+v", got)
+	}
+}
+
+func TestCreateRequest_Anomalies(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	sess := testutil.MakeSession(t, database, testutil.WithProject("/project"))
+	config := DefaultRequestCreatorConfig()
+	config.AgentMailEnabled = false
+	config.Anomaly = AnomalyConfig{Enabled: true, BurstWindow: time.Minute, BurstThreshold: 3, NovelFamily: true, MinHistory: 2, BaselineDays: 90}
+	creator := NewRequestCreator(database, NewRateLimiter(database, RateLimitConfig{}), nil, config)
+
+	create := func(cmd string) *CreateRequestResult {
+		t.Helper()
+		result, err := creator.CreateRequest(context.Background(), CreateRequestOptions{
+			SessionID:     sess.ID,
+			Command:       cmd,
+			Cwd:           "/project",
+			Justification: Justification{Reason: "cleanup"},
+		})
+		if err != nil {
+			t.Fatalf("CreateRequest(%q): %v", cmd, err)
+		}
+		return result
+	}
+
+	// Too little history for novelty, and below the burst threshold.
+	if r := create("ls -la"); !r.Skipped {
+		t.Fatalf("ls: %+v", r)
+	}
+	if r := create("rm a.txt"); r.Skipped || r.Request.RiskTier != RiskTierCaution {
+		t.Fatalf("first rm: %+v", r.Classification)
+	}
+	if r := create("rm b.txt"); r.Request.RiskTier != RiskTierCaution {
+		t.Fatalf("second rm: %+v", r.Classification)
+	}
+
+	// The third rm within the window is a burst.
+	r := create("rm c.txt")
+	if r.Request.RiskTier != RiskTierDangerous || r.Request.MinApprovals != 1 {
+		t.Fatalf("burst rm: tier %s, approvals %d", r.Request.RiskTier, r.Request.MinApprovals)
+	}
+	var found bool
+	for _, att := range r.Request.Attachments {
+		if att.Metadata["type"] == "anomaly" && strings.Contains(att.Content, "`rm` commands") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("no anomaly attachment: %+v", r.Request.Attachments)
+	}
+
+	// An unmatched family the project has never run becomes a request.
+	r = create("aws iam list-users")
+	if r.Skipped || r.Request.RiskTier != RiskTierCaution {
+		t.Fatalf("novel aws iam: skipped %v (%s) %+v", r.Skipped, r.SkipReason, r.Classification)
+	}
+	if n := len(r.Classification.Explanation); n == 0 || !strings.Contains(strings.Join(r.Classification.Explanation, "\n"), "first `aws iam` command") {
+		t.Errorf("explanation = %q", r.Classification.Explanation)
+	}
+	// Once seen, it is part of the baseline.
+	if r := create("aws iam get-user"); !r.Skipped {
+		t.Errorf("repeat aws iam: %+v", r.Classification)
+	}
+}
@@ -34,6 +34,10 @@ type AttachmentConfig struct {
 	MaxImageSize int
 	// AllowedFileTypes restricts file types (empty means all allowed).
 	AllowedFileTypes []string
+	// MaxTranscriptBytes caps transcript snippets before compression (default 8KB).
+	MaxTranscriptBytes int
+	// MaxTranscriptLines caps transcript snippets to the most recent lines (default 40).
+	MaxTranscriptLines int
 }
 
 // DefaultAttachmentConfig returns default configuration.
@@ -44,6 +48,9 @@ func DefaultAttachmentConfig() AttachmentConfig {
 		MaxCommandRuntime: 10 * time.Second,
 		MaxImageSize:      4096,       // 4096px
 		AllowedFileTypes:  []string{}, // Allow all
+
+		MaxTranscriptBytes: 8 * 1024, // 8KB
+		MaxTranscriptLines: 40,
 	}
 }
 
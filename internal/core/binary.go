@@ -0,0 +1,158 @@
+package core
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// maxBinaryHashBytes bounds how much is read to hash an executable at
+// request time; larger files are recorded without a digest.
+const maxBinaryHashBytes = 512 << 20
+
+// ResolveBinary finds the executable a command would run: its first word
+// after any VAR=value assignments, looked up in pathEnv (a PATH value) the
+// way a shell would, with relative PATH entries taken from cwd. A first word
+// containing a slash is taken as a path from cwd. It returns nil when no
+// executable file matches, e.g. for shell builtins and functions.
+func ResolveBinary(command, cwd, pathEnv, projectPath string) *db.RequestBinary {
+	name := firstCommandWord(command)
+	if name == "" {
+		return nil
+	}
+
+	path := lookPathIn(name, cwd, pathEnv)
+	if path == "" {
+		return nil
+	}
+
+	b := &db.RequestBinary{Name: name, Path: path}
+	real, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		real = path
+	}
+	if real != path {
+		b.RealPath = real
+	}
+	b.InProject = withinDir(projectPath, path) || withinDir(projectPath, real)
+	b.SHA256, b.Interpreter = inspectExecutable(real)
+	return b
+}
+
+// firstCommandWord returns the first word of command that is not an
+// environment assignment.
+func firstCommandWord(command string) string {
+	words, err := ParseCommandToArgv(command)
+	if err != nil || len(words) == 0 {
+		words = strings.Fields(command)
+	}
+	for _, w := range words {
+		if eq := strings.IndexByte(w, '='); eq > 0 && !strings.ContainsAny(w[:eq], "/\\") {
+			continue
+		}
+		return w
+	}
+	return ""
+}
+
+// lookPathIn resolves name like exec.LookPath, but against pathEnv and cwd
+// instead of this process's environment.
+func lookPathIn(name, cwd, pathEnv string) string {
+	abs := func(p string) string {
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(cwd, p)
+		}
+		return filepath.Clean(p)
+	}
+
+	if strings.ContainsAny(name, `/\`) {
+		for _, candidate := range executableCandidates(abs(name)) {
+			if isExecutableFile(candidate) {
+				return candidate
+			}
+		}
+		return ""
+	}
+
+	for _, dir := range filepath.SplitList(pathEnv) {
+		if dir == "" {
+			// An empty PATH entry means the current directory.
+			dir = "."
+		}
+		for _, candidate := range executableCandidates(filepath.Join(abs(dir), name)) {
+			if isExecutableFile(candidate) {
+				return candidate
+			}
+		}
+	}
+	return ""
+}
+
+// executableCandidates returns the file names path may refer to; on
+// Windows these include the PATHEXT extensions.
+func executableCandidates(path string) []string {
+	if runtime.GOOS != "windows" || filepath.Ext(path) != "" {
+		return []string{path}
+	}
+	exts := os.Getenv("PATHEXT")
+	if exts == "" {
+		exts = ".com;.exe;.bat;.cmd"
+	}
+	var out []string
+	for _, ext := range strings.Split(exts, ";") {
+		if ext != "" {
+			out = append(out, path+strings.ToLower(ext))
+		}
+	}
+	return out
+}
+
+func isExecutableFile(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+	return runtime.GOOS == "windows" || info.Mode().Perm()&0o111 != 0
+}
+
+// withinDir reports whether path is dir or inside it.
+func withinDir(dir, path string) bool {
+	if dir == "" {
+		return false
+	}
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// inspectExecutable hashes the file and reads its #! line, if any. Either
+// is empty when the file cannot be read.
+func inspectExecutable(path string) (sum, interpreter string) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", ""
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	if head, err := r.Peek(2); err == nil && string(head) == "#!" {
+		line, _ := r.Peek(256)
+		if nl := strings.IndexByte(string(line), '\n'); nl >= 0 {
+			line = line[:nl]
+		}
+		interpreter = strings.TrimSpace(string(line))
+	}
+
+	h := sha256.New()
+	n, err := io.Copy(h, io.LimitReader(r, maxBinaryHashBytes+1))
+	if err != nil || n > maxBinaryHashBytes {
+		return "", interpreter
+	}
+	return hex.EncodeToString(h.Sum(nil)), interpreter
+}
@@ -0,0 +1,126 @@
+package core
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+// writeExecutable creates an executable file with content under dir.
+func writeExecutable(t *testing.T, path, content string) string {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestResolveBinary(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses Unix executable bits and symlinks")
+	}
+	project := t.TempDir()
+	system := t.TempDir()
+
+	wrapper := "#!/bin/sh\nexec /usr/bin/terraform \"$@\"\n"
+	writeExecutable(t, filepath.Join(project, "bin", "terraform"), wrapper)
+	writeExecutable(t, filepath.Join(system, "terraform"), "\x7fELF fake")
+	writeExecutable(t, filepath.Join(system, "kubectl"), "\x7fELF kubectl")
+	writeExecutable(t, filepath.Join(project, "node_modules", "prisma", "cli.js"), "#!/usr/bin/env node\n")
+	if err := os.MkdirAll(filepath.Join(project, "node_modules", ".bin"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("../prisma/cli.js", filepath.Join(project, "node_modules", ".bin", "prisma")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(system, "notexec"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join("bin") + string(filepath.ListSeparator) +
+		filepath.Join("node_modules", ".bin") + string(filepath.ListSeparator) + system
+
+	t.Run("local wrapper shadows the installed tool", func(t *testing.T) {
+		b := ResolveBinary("TF_LOG=debug terraform destroy -auto-approve", project, path, project)
+		if b == nil {
+			t.Fatal("expected terraform to resolve")
+		}
+		testutil.RequireEqual(t, "terraform", b.Name, "name")
+		testutil.RequireEqual(t, filepath.Join(project, "bin", "terraform"), b.Path, "path")
+		if !b.InProject || !b.IsScript() || b.Interpreter != "#!/bin/sh" {
+			t.Fatalf("expected an in-project script, got %+v", b)
+		}
+		sum := sha256.Sum256([]byte(wrapper))
+		testutil.RequireEqual(t, hex.EncodeToString(sum[:]), b.SHA256, "sha256")
+	})
+
+	t.Run("installed binary", func(t *testing.T) {
+		b := ResolveBinary("kubectl delete ns prod", project, path, project)
+		if b == nil || b.Path != filepath.Join(system, "kubectl") || b.InProject || b.IsScript() || b.SHA256 == "" {
+			t.Fatalf("unexpected binary %+v", b)
+		}
+	})
+
+	t.Run("symlink is followed", func(t *testing.T) {
+		b := ResolveBinary("prisma migrate reset --force", project, path, project)
+		if b == nil {
+			t.Fatal("expected prisma to resolve")
+		}
+		realCLI, _ := filepath.EvalSymlinks(filepath.Join(project, "node_modules", "prisma", "cli.js"))
+		testutil.RequireEqual(t, realCLI, b.RealPath, "real path")
+		if !b.InProject || b.Interpreter != "#!/usr/bin/env node" {
+			t.Fatalf("unexpected binary %+v", b)
+		}
+	})
+
+	t.Run("explicit path", func(t *testing.T) {
+		b := ResolveBinary("./bin/terraform apply", project, "", project)
+		if b == nil || b.Path != filepath.Join(project, "bin", "terraform") {
+			t.Fatalf("unexpected binary %+v", b)
+		}
+	})
+
+	for _, cmd := range []string{"cd /tmp", "notexec --now", "FOO=bar", ""} {
+		if b := ResolveBinary(cmd, project, path, project); b != nil {
+			t.Errorf("ResolveBinary(%q) = %+v, want nil", cmd, b)
+		}
+	}
+}
+
+func TestCreateRequest_RecordsBinary(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses Unix executable bits")
+	}
+	database := testutil.NewTestDB(t)
+	session := testutil.MakeSession(t, database, testutil.SessionWithAgentName("agent1"))
+	creator := NewRequestCreator(database, nil, nil, nil)
+
+	project := t.TempDir()
+	writeExecutable(t, filepath.Join(project, "bin", "git"), "#!/bin/sh\necho pretend\n")
+
+	result, err := creator.CreateRequest(context.Background(), CreateRequestOptions{
+		SessionID:     session.ID,
+		Command:       "git reset --hard HEAD~3",
+		Cwd:           project,
+		PathEnv:       "bin",
+		ProjectPath:   project,
+		Justification: Justification{Reason: "reset"},
+	})
+	testutil.RequireNoError(t, err, "create request")
+
+	b, err := database.GetRequestBinary(result.Request.ID)
+	testutil.RequireNoError(t, err, "get binary")
+	testutil.RequireEqual(t, filepath.Join(project, "bin", "git"), b.Path, "path")
+	if !b.InProject || !b.IsScript() {
+		t.Fatalf("expected an in-project script, got %+v", b)
+	}
+}
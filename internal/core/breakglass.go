@@ -0,0 +1,50 @@
+// Package core implements break-glass execution policy.
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// Break-glass errors.
+var (
+	ErrBreakglassCooldown       = errors.New("break-glass is rate limited")
+	ErrBreakglassUnacknowledged = errors.New("break-glass incident awaiting postmortem")
+)
+
+// BreakglassPolicy controls how often break-glass may be used.
+type BreakglassPolicy struct {
+	// Cooldown is the minimum time between break-glass executions per project (0 disables).
+	Cooldown time.Duration
+	// AckWindow is how long the actor has to record a postmortem.
+	AckWindow time.Duration
+}
+
+// CheckBreakglassAllowed returns an error when a new break-glass execution is
+// not allowed given the project's previous incidents (newest first).
+func CheckBreakglassAllowed(policy BreakglassPolicy, incidents []*db.BreakglassIncident, now time.Time) error {
+	for _, inc := range incidents {
+		if inc.IsOverdue(now) {
+			return fmt.Errorf("%w: incident %s was due %s; run 'slb breakglass ack %s --postmortem ...' first",
+				ErrBreakglassUnacknowledged, inc.ID, inc.AckDueAt.Local().Format(time.RFC3339), inc.ID)
+		}
+	}
+
+	if policy.Cooldown <= 0 || len(incidents) == 0 {
+		return nil
+	}
+	latest := incidents[0]
+	for _, inc := range incidents[1:] {
+		if inc.ExecutedAt.After(latest.ExecutedAt) {
+			latest = inc
+		}
+	}
+	if next := latest.ExecutedAt.Add(policy.Cooldown); now.Before(next) {
+		return fmt.Errorf("%w: last used %s, next allowed at %s",
+			ErrBreakglassCooldown, latest.ExecutedAt.Local().Format(time.RFC3339), next.Local().Format(time.RFC3339))
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+func TestCheckBreakglassAllowed(t *testing.T) {
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	policy := BreakglassPolicy{Cooldown: 4 * time.Hour, AckWindow: 24 * time.Hour}
+
+	if err := CheckBreakglassAllowed(policy, nil, now); err != nil {
+		t.Fatalf("first use should be allowed: %v", err)
+	}
+
+	recent := &db.BreakglassIncident{ID: "recent", ExecutedAt: now.Add(-time.Hour), AckDueAt: now.Add(23 * time.Hour)}
+	if err := CheckBreakglassAllowed(policy, []*db.BreakglassIncident{recent}, now); !errors.Is(err, ErrBreakglassCooldown) {
+		t.Fatalf("expected cooldown error, got %v", err)
+	}
+
+	old := &db.BreakglassIncident{ID: "old", ExecutedAt: now.Add(-5 * time.Hour), AckDueAt: now.Add(19 * time.Hour)}
+	if err := CheckBreakglassAllowed(policy, []*db.BreakglassIncident{old}, now); err != nil {
+		t.Fatalf("expected allowed after cooldown, got %v", err)
+	}
+
+	if err := CheckBreakglassAllowed(BreakglassPolicy{}, []*db.BreakglassIncident{recent}, now); err != nil {
+		t.Fatalf("zero cooldown should not rate limit: %v", err)
+	}
+
+	overdue := &db.BreakglassIncident{ID: "overdue", ExecutedAt: now.Add(-30 * time.Hour), AckDueAt: now.Add(-6 * time.Hour)}
+	if err := CheckBreakglassAllowed(policy, []*db.BreakglassIncident{overdue}, now); !errors.Is(err, ErrBreakglassUnacknowledged) {
+		t.Fatalf("expected unacknowledged error, got %v", err)
+	}
+
+	ackedAt := now.Add(-7 * time.Hour)
+	overdue.AcknowledgedAt = &ackedAt
+	if err := CheckBreakglassAllowed(policy, []*db.BreakglassIncident{overdue}, now); err != nil {
+		t.Fatalf("acknowledged incident should not block: %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+var clockStart = time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+
+func TestRateLimiter_CooldownWithFakeClock(t *testing.T) {
+	dbConn := testutil.NewTestDB(t)
+	clk := testutil.NewFakeClock(clockStart)
+	dbConn.SetClock(clk)
+
+	sess := testutil.MakeSession(t, dbConn)
+	rl := NewRateLimiter(dbConn, RateLimitConfig{MaxPendingPerSession: 100, MaxRequestsPerMinute: 2, Action: RateLimitActionQueue})
+	for i := 0; i < 2; i++ {
+		testutil.MakeRequest(t, dbConn, sess)
+		clk.Advance(10 * time.Second)
+	}
+
+	result, err := rl.CheckRateLimit(sess.ID)
+	if err != nil {
+		t.Fatalf("CheckRateLimit: %v", err)
+	}
+	if result.Allowed || !result.ResetAt.Equal(clockStart.Add(time.Minute)) {
+		t.Fatalf("expected block until %v, got allowed=%v reset=%v", clockStart.Add(time.Minute), result.Allowed, result.ResetAt)
+	}
+
+	clk.Set(result.ResetAt.Add(time.Second))
+	if result, err = rl.CheckRateLimit(sess.ID); err != nil || !result.Allowed {
+		t.Fatalf("expected one request to age out of the window, got %+v (err %v)", result, err)
+	}
+}
+
+func TestGarbageCollectStaleSessions_FakeClock(t *testing.T) {
+	dbConn := testutil.NewTestDB(t)
+	clk := testutil.NewFakeClock(clockStart)
+	dbConn.SetClock(clk)
+
+	sess := testutil.MakeSession(t, dbConn, testutil.WithProject("/gc/project"))
+	opts := SessionGCOptions{ProjectPath: "/gc/project", Threshold: time.Hour, DryRun: true}
+
+	clk.Advance(59 * time.Minute)
+	res, err := GarbageCollectStaleSessions(dbConn, opts)
+	if err != nil || len(res.Sessions) != 0 {
+		t.Fatalf("expected no stale sessions yet, got %+v (err %v)", res, err)
+	}
+
+	clk.Advance(2 * time.Minute)
+	res, err = GarbageCollectStaleSessions(dbConn, opts)
+	if err != nil || len(res.Sessions) != 1 || res.Sessions[0].ID != sess.ID {
+		t.Fatalf("expected the session to be stale, got %+v (err %v)", res, err)
+	}
+	if !res.Cutoff.Equal(clk.Now().Add(-time.Hour)) {
+		t.Errorf("cutoff = %v, want %v", res.Cutoff, clk.Now().Add(-time.Hour))
+	}
+}
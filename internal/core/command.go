@@ -9,6 +9,7 @@ import (
 	"os"
 	"os/exec"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Dicklesworthstone/slb/internal/db"
@@ -24,9 +25,34 @@ type CommandResult struct {
 	Duration time.Duration
 }
 
+// OutputStreams receives a command's output as it runs. Stdout and Stderr
+// may be the same writer; a nil one drops that stream, which is still
+// captured and logged.
+type OutputStreams struct {
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// streamTo sends both of a command's streams to w, which may be nil.
+func streamTo(w io.Writer) OutputStreams {
+	return OutputStreams{Stdout: w, Stderr: w}
+}
+
 // RunCommand executes a command and captures output to both terminal and log file.
 // The command runs in the current shell environment, inheriting all env vars.
 func RunCommand(ctx context.Context, spec *db.CommandSpec, logPath string, stream io.Writer) (*CommandResult, error) {
+	return runCommand(ctx, spec, nil, nil, logPath, streamTo(stream))
+}
+
+// RunCommandStreams is RunCommand with the command's stdout and stderr
+// streamed to separate writers.
+func RunCommandStreams(ctx context.Context, spec *db.CommandSpec, logPath string, streams OutputStreams) (*CommandResult, error) {
+	return runCommand(ctx, spec, nil, nil, logPath, streams)
+}
+
+// runCommand is RunCommand under an optional isolation. mounts are host
+// files the command needs to read, passed on to the isolation.
+func runCommand(ctx context.Context, spec *db.CommandSpec, iso *Isolation, mounts []string, logPath string, streams OutputStreams) (*CommandResult, error) {
 	startTime := time.Now()
 
 	// Open log file for writing
@@ -46,28 +72,38 @@ func RunCommand(ctx context.Context, spec *db.CommandSpec, logPath string, strea
 		fmt.Fprintf(logFile, "CWD: %s\n", spec.Cwd)
 		fmt.Fprintf(logFile, "Shell: %v\n", spec.Shell)
 		fmt.Fprintf(logFile, "Hash: %s\n", spec.Hash)
+		if iso != nil {
+			fmt.Fprintf(logFile, "Isolation: %s\n", iso)
+		}
 		fmt.Fprintf(logFile, "=============================\n\n")
 	}
 
 	// Build the command
-	var cmd *exec.Cmd
+	var argv []string
 	if spec.Shell {
 		// Use shell execution
-		shell := os.Getenv("SHELL")
-		if shell == "" {
-			shell = "/bin/sh"
-		}
-		cmd = exec.CommandContext(ctx, shell, "-c", spec.Raw)
+		argv = []string{iso.shell(), "-c", spec.Raw}
 	} else if len(spec.Argv) > 0 {
 		// Use parsed argv
-		cmd = exec.CommandContext(ctx, spec.Argv[0], spec.Argv[1:]...)
+		argv = spec.Argv
 	} else {
 		// Parse the raw command
-		parts := strings.Fields(spec.Raw)
-		if len(parts) == 0 {
+		argv = strings.Fields(spec.Raw)
+		if len(argv) == 0 {
 			return nil, fmt.Errorf("empty command")
 		}
-		cmd = exec.CommandContext(ctx, parts[0], parts[1:]...)
+	}
+	argv, err := iso.wrap(argv, spec.Cwd, mounts...)
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	if iso != nil && iso.Mode == IsolationContainer {
+		// Killing the container CLI would leave the container running;
+		// interrupt it instead so it stops the container, and kill it only
+		// if that takes too long.
+		cmd.Cancel = func() error { return cmd.Process.Signal(os.Interrupt) }
+		cmd.WaitDelay = 10 * time.Second
 	}
 
 	// Set working directory
@@ -78,33 +114,35 @@ func RunCommand(ctx context.Context, spec *db.CommandSpec, logPath string, strea
 	// Inherit environment
 	cmd.Env = os.Environ()
 
-	// Set up output capture
+	// Set up output capture: always to the buffer, and to the log file
 	var outputBuf bytes.Buffer
-	var writers []io.Writer
-
-	// Always capture to buffer
-	writers = append(writers, &outputBuf)
-
-	// Stream to caller-provided writer (optional)
-	if stream != nil {
-		writers = append(writers, stream)
-	}
-
-	// Write to log file
+	capture := []io.Writer{&outputBuf}
 	if logFile != nil {
-		writers = append(writers, logFile)
+		capture = append(capture, logFile)
 	}
 
-	// Combine writers
-	multiWriter := io.MultiWriter(writers...)
-	cmd.Stdout = multiWriter
-	cmd.Stderr = multiWriter
+	if streams.Stdout == streams.Stderr {
+		// One writer for both keeps them interleaved as written
+		writers := capture
+		if streams.Stdout != nil {
+			writers = append(writers, streams.Stdout)
+		}
+		multiWriter := io.MultiWriter(writers...)
+		cmd.Stdout = multiWriter
+		cmd.Stderr = multiWriter
+	} else {
+		// Separate streams are copied concurrently; the shared capture
+		// must not be written by both at once
+		shared := &lockedWriter{w: io.MultiWriter(capture...)}
+		cmd.Stdout = teeStream(shared, streams.Stdout)
+		cmd.Stderr = teeStream(shared, streams.Stderr)
+	}
 
 	// Connect stdin to terminal for interactive commands
 	cmd.Stdin = os.Stdin
 
 	// Run the command
-	err := cmd.Run()
+	err = cmd.Run()
 
 	duration := time.Since(startTime)
 
@@ -135,3 +173,23 @@ func RunCommand(ctx context.Context, spec *db.CommandSpec, logPath string, strea
 		Duration: duration,
 	}, nil
 }
+
+// teeStream writes to the shared capture and then to stream, if any.
+func teeStream(shared io.Writer, stream io.Writer) io.Writer {
+	if stream == nil {
+		return shared
+	}
+	return io.MultiWriter(shared, stream)
+}
+
+// lockedWriter serializes writes to w.
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
@@ -0,0 +1,246 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/core/statemachine"
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// Approve-with-edit errors.
+var (
+	ErrEditUnchanged   = errors.New("edited command is the same as the requested one")
+	ErrEditNotProposed = errors.New("edit was already accepted")
+	ErrNotRequestor    = errors.New("only the requestor can accept an edit")
+)
+
+// ProposeEditOptions contains the options for proposing a corrected command.
+type ProposeEditOptions struct {
+	// SessionID is the reviewer's session ID (required).
+	SessionID string
+	// SessionKey is the session's HMAC key for signing (required).
+	SessionKey string
+	// RequestID is the request being corrected (required).
+	RequestID string
+	// Command is the corrected command the reviewer would approve (required).
+	Command string
+	// Comments explains the correction (optional).
+	Comments string
+	// FreshAuth is required when approving the request's tier needs it, as
+	// for an approval; it is carried over to the approval the edit becomes.
+	FreshAuth *FreshAuth
+	// AcknowledgedVars acknowledges template variables left in the
+	// corrected command, as for an approval.
+	AcknowledgedVars []string
+}
+
+// ProposeEdit records a reviewer's offer to approve a corrected command in
+// place of the requested one. The reviewer must be able to approve the
+// request; the original request stays pending until its requestor accepts
+// the edit or it is decided otherwise.
+func (rs *ReviewService) ProposeEdit(opts ProposeEditOptions) (*db.CommandEdit, error) {
+	command := strings.TrimSpace(opts.Command)
+	if command == "" {
+		return nil, ErrCommandRequired
+	}
+	if err := ValidateExact("command", command, MaxCommandBytes); err != nil {
+		return nil, err
+	}
+
+	p, err := rs.prepareReview(ReviewOptions{
+		SessionID:        opts.SessionID,
+		SessionKey:       opts.SessionKey,
+		RequestID:        opts.RequestID,
+		Decision:         db.DecisionApprove,
+		Comments:         opts.Comments,
+		FreshAuth:        opts.FreshAuth,
+		AcknowledgedVars: opts.AcknowledgedVars,
+		approvedCommand:  command,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if command == strings.TrimSpace(p.request.Command.Raw) {
+		return nil, ErrEditUnchanged
+	}
+
+	edit := &db.CommandEdit{
+		RequestID:          opts.RequestID,
+		ReviewerSessionID:  p.review.ReviewerSessionID,
+		ReviewerAgent:      p.review.ReviewerAgent,
+		ReviewerModel:      p.review.ReviewerModel,
+		Command:            command,
+		Comments:           p.review.Comments,
+		SignatureTimestamp: p.review.SignatureTimestamp,
+		AuthMethod:         p.review.AuthMethod,
+		AuthenticatedAt:    p.review.AuthenticatedAt,
+	}
+	edit.Signature = db.ComputeEditSignature(opts.SessionKey, opts.RequestID, command, edit.SignatureTimestamp)
+	if err := rs.db.CreateCommandEdit(edit); err != nil {
+		return nil, err
+	}
+	return edit, nil
+}
+
+// AcceptEditOptions contains the options for accepting a proposed edit.
+type AcceptEditOptions struct {
+	// SessionID is the requestor's session ID (required).
+	SessionID string
+	// SessionKey is the requestor's session key (required); it proves the
+	// caller holds the session, as SessionID alone is shown to reviewers.
+	SessionKey string
+	// EditID is the edit being accepted (required).
+	EditID string
+	// PathEnv and Environ describe the requestor's environment, as for
+	// CreateRequestOptions.
+	PathEnv string
+	Environ []string
+}
+
+// AcceptEditResult holds the result of accepting an edit.
+type AcceptEditResult struct {
+	// Edit is the accepted edit.
+	Edit *db.CommandEdit
+	// Original is the corrected request, now cancelled.
+	Original *db.Request
+	// Created is the outcome of requesting the corrected command. Its
+	// Request is nil when the corrected command needs no approval.
+	Created *CreateRequestResult
+	// PreApproval is the proposing reviewer's approval of the new request.
+	PreApproval *ReviewResult
+	// PreApprovalErr explains why the reviewer's approval could not be
+	// recorded (for example, their session ended); the new request then
+	// waits for review like any other.
+	PreApprovalErr error
+}
+
+// AcceptEdit lets the requestor take a reviewer's correction: it requests
+// the corrected command with the original justification, records the
+// reviewer's approval of it, cancels the original request and links the two
+// through the edit.
+func (rs *ReviewService) AcceptEdit(ctx context.Context, creator *RequestCreator, opts AcceptEditOptions) (*AcceptEditResult, error) {
+	if opts.SessionID == "" {
+		return nil, ErrSessionRequired
+	}
+	if opts.SessionKey == "" {
+		return nil, ErrMissingSessionKey
+	}
+	session, err := rs.db.GetSession(opts.SessionID)
+	if err != nil {
+		return nil, fmt.Errorf("getting session: %w", err)
+	}
+	if !db.SessionKeyMatches(opts.SessionKey, session.SessionKey) {
+		return nil, ErrSessionKeyMismatch
+	}
+	edit, err := rs.db.GetCommandEdit(opts.EditID)
+	if err != nil {
+		return nil, err
+	}
+	if edit.Status != db.CommandEditProposed {
+		return nil, ErrEditNotProposed
+	}
+	original, err := rs.db.GetRequest(edit.RequestID)
+	if err != nil {
+		return nil, fmt.Errorf("getting request: %w", err)
+	}
+	if original.RequestorSessionID != opts.SessionID {
+		return nil, ErrNotRequestor
+	}
+	if original.Status != db.StatusPending {
+		return nil, fmt.Errorf("%w: status is %s", ErrRequestNotPending, original.Status)
+	}
+	// Labels are carried over when they can be read.
+	_ = rs.db.LoadRequestLabels([]*db.Request{original})
+
+	created, err := creator.CreateRequest(ctx, CreateRequestOptions{
+		SessionID:     opts.SessionID,
+		Command:       edit.Command,
+		Cwd:           original.Command.Cwd,
+		Shell:         original.Command.Shell,
+		PathEnv:       opts.PathEnv,
+		Environ:       opts.Environ,
+		Justification: original.Justification,
+		ProjectPath:   original.ProjectPath,
+		Labels:        original.Labels,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("requesting edited command: %w", err)
+	}
+	newID := ""
+	if created.Request != nil {
+		newID = created.Request.ID
+	}
+
+	claimed, err := rs.db.ClaimCommandEdit(edit.ID)
+	if err == nil && !claimed {
+		err = ErrEditNotProposed
+	}
+	if err != nil {
+		// Someone else accepted it first; withdraw the duplicate.
+		if newID != "" {
+			_, _ = rs.machine.Commit(newID, db.StatusCancelled, statemachine.Input{
+				Authority: statemachine.AuthoritySystem,
+				Actor:     original.RequestorAgent,
+				Reason:    "edit " + edit.ID + " was already accepted",
+			})
+		}
+		return nil, err
+	}
+	if err := rs.db.LinkCommandEdit(edit, newID, original.RequestorAgent); err != nil {
+		return nil, err
+	}
+	edit.Status = db.CommandEditAccepted
+	edit.AcceptedRequestID = newID
+
+	reason := "superseded by edit " + edit.ID + " from " + edit.ReviewerAgent
+	if newID != "" {
+		reason += " (request " + newID + ")"
+	}
+	if _, err := rs.machine.Commit(original.ID, db.StatusCancelled, statemachine.Input{
+		Authority: statemachine.AuthoritySystem,
+		Actor:     original.RequestorAgent,
+		Reason:    reason,
+	}); err != nil {
+		return nil, fmt.Errorf("cancelling original request: %w", err)
+	}
+	original.Status = db.StatusCancelled
+
+	result := &AcceptEditResult{Edit: edit, Original: original, Created: created}
+	if created.Request != nil && created.Request.Status == db.StatusPending {
+		result.PreApproval, result.PreApprovalErr = rs.preApprove(edit, original, created.Request)
+	}
+	return result, nil
+}
+
+// preApprove records the proposing reviewer's approval of the request
+// created for their edit, signed with their session key.
+func (rs *ReviewService) preApprove(edit *db.CommandEdit, original, req *db.Request) (*ReviewResult, error) {
+	reviewer, err := rs.db.GetSession(edit.ReviewerSessionID)
+	if err != nil {
+		return nil, fmt.Errorf("getting reviewer session: %w", err)
+	}
+	comments := fmt.Sprintf("approved with edit %s of request %s", edit.ID, original.ID)
+	if edit.Comments != "" {
+		comments += ": " + edit.Comments
+	}
+	// The reviewer acknowledged any template variables in the edit when
+	// proposing it, and re-authenticated then if the tier needs it. That
+	// check already passed, so the approval records when it was accepted
+	// rather than the proposal's timestamp, which may have aged out since.
+	opts := ReviewOptions{
+		SessionID:        reviewer.ID,
+		SessionKey:       reviewer.SessionKey,
+		RequestID:        req.ID,
+		Decision:         db.DecisionApprove,
+		Comments:         comments,
+		AcknowledgedVars: TemplateVars(edit.Command),
+	}
+	if edit.AuthMethod != "" && edit.AuthenticatedAt != nil {
+		opts.FreshAuth = &FreshAuth{Method: edit.AuthMethod, At: time.Now()}
+	}
+	return rs.SubmitReview(opts)
+}
@@ -0,0 +1,228 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+// editFixture creates a pending git push --force request and a reviewer in
+// another session, with a creator that classifies git pushes as dangerous.
+func editFixture(t *testing.T) (*db.DB, *RequestCreator, *db.Session, *db.Session, *db.Request) {
+	t.Helper()
+	database := testutil.NewTestDB(t)
+	requestor := testutil.MakeSession(t, database, testutil.WithAgent("Requestor"), testutil.WithModel("model-a"))
+	reviewer := testutil.MakeSession(t, database, testutil.WithAgent("Reviewer"), testutil.WithModel("model-b"),
+		testutil.WithProject(requestor.ProjectPath))
+
+	engine := &PatternEngine{}
+	if err := engine.AddPattern(RiskTierDangerous, `^git\s+push\b.*--force`, "", "test"); err != nil {
+		t.Fatal(err)
+	}
+	config := DefaultRequestCreatorConfig()
+	config.AgentMailEnabled = false
+	creator := NewRequestCreator(database, NewRateLimiter(database, RateLimitConfig{}), engine, config)
+
+	result, err := creator.CreateRequest(context.Background(), CreateRequestOptions{
+		SessionID:     requestor.ID,
+		Command:       "git push --force origin main",
+		Cwd:           requestor.ProjectPath,
+		Justification: Justification{Reason: "rewrite history"},
+	})
+	if err != nil || result.Request == nil {
+		t.Fatalf("CreateRequest: %v %+v", err, result)
+	}
+	return database, creator, requestor, reviewer, result.Request
+}
+
+func TestProposeEdit(t *testing.T) {
+	database, _, requestor, reviewer, req := editFixture(t)
+	rs := NewReviewService(database, DefaultReviewConfig())
+
+	propose := func(sess *db.Session, command string) (*db.CommandEdit, error) {
+		return rs.ProposeEdit(ProposeEditOptions{
+			SessionID:  sess.ID,
+			SessionKey: sess.SessionKey,
+			RequestID:  req.ID,
+			Command:    command,
+			Comments:   "keep others' commits",
+		})
+	}
+	if _, err := propose(reviewer, "  git push --force origin main "); !errors.Is(err, ErrEditUnchanged) {
+		t.Errorf("unchanged command: %v", err)
+	}
+	if _, err := propose(reviewer, ""); !errors.Is(err, ErrCommandRequired) {
+		t.Errorf("empty command: %v", err)
+	}
+	if _, err := propose(requestor, "git push --force-with-lease origin main"); !errors.Is(err, ErrSelfReview) {
+		t.Errorf("requestor proposing: %v", err)
+	}
+	if _, err := rs.ProposeEdit(ProposeEditOptions{SessionID: reviewer.ID, SessionKey: "00", RequestID: req.ID, Command: "git push"}); !errors.Is(err, ErrSessionKeyMismatch) {
+		t.Errorf("wrong key: %v", err)
+	}
+
+	edit, err := propose(reviewer, "git push --force-with-lease origin main")
+	if err != nil {
+		t.Fatalf("ProposeEdit: %v", err)
+	}
+	if edit.ReviewerAgent != "Reviewer" || edit.Status != db.CommandEditProposed ||
+		edit.Signature != db.ComputeEditSignature(reviewer.SessionKey, req.ID, edit.Command, edit.SignatureTimestamp) {
+		t.Errorf("unexpected edit: %+v", edit)
+	}
+
+	// Proposing is not a decision: the request stays pending and the
+	// reviewer can still approve or reject it as is.
+	got, err := database.GetRequest(req.ID)
+	if err != nil || got.Status != db.StatusPending {
+		t.Fatalf("request after proposal: %v %v", got.Status, err)
+	}
+	if reviewed, _ := database.HasReviewerAlreadyReviewed(req.ID, reviewer.ID); reviewed {
+		t.Error("proposal recorded a review")
+	}
+}
+
+func TestAcceptEdit(t *testing.T) {
+	database, creator, requestor, reviewer, req := editFixture(t)
+	rs := NewReviewService(database, DefaultReviewConfig())
+
+	edit, err := rs.ProposeEdit(ProposeEditOptions{
+		SessionID:  reviewer.ID,
+		SessionKey: reviewer.SessionKey,
+		RequestID:  req.ID,
+		Command:    "git push --force-with-lease origin main",
+		Comments:   "keep others' commits",
+	})
+	if err != nil {
+		t.Fatalf("ProposeEdit: %v", err)
+	}
+
+	if _, err := rs.AcceptEdit(context.Background(), creator, AcceptEditOptions{SessionID: requestor.ID, EditID: edit.ID}); !errors.Is(err, ErrMissingSessionKey) {
+		t.Errorf("accepting without a key: %v", err)
+	}
+	// The requestor's session ID is shown to reviewers; it is not enough.
+	if _, err := rs.AcceptEdit(context.Background(), creator, AcceptEditOptions{SessionID: requestor.ID, SessionKey: reviewer.SessionKey, EditID: edit.ID}); !errors.Is(err, ErrSessionKeyMismatch) {
+		t.Errorf("accepting with another session's key: %v", err)
+	}
+	if _, err := rs.AcceptEdit(context.Background(), creator, AcceptEditOptions{SessionID: reviewer.ID, SessionKey: reviewer.SessionKey, EditID: edit.ID}); !errors.Is(err, ErrNotRequestor) {
+		t.Errorf("reviewer accepting: %v", err)
+	}
+	if _, err := rs.AcceptEdit(context.Background(), creator, AcceptEditOptions{SessionID: requestor.ID, SessionKey: requestor.SessionKey, EditID: "missing"}); !errors.Is(err, db.ErrCommandEditNotFound) {
+		t.Errorf("unknown edit: %v", err)
+	}
+
+	result, err := rs.AcceptEdit(context.Background(), creator, AcceptEditOptions{SessionID: requestor.ID, SessionKey: requestor.SessionKey, EditID: edit.ID})
+	if err != nil {
+		t.Fatalf("AcceptEdit: %v", err)
+	}
+	if result.PreApprovalErr != nil {
+		t.Fatalf("pre-approval failed: %v", result.PreApprovalErr)
+	}
+	created := result.Created.Request
+	if created == nil || created.Command.Raw != edit.Command || created.Justification.Reason != "rewrite history" {
+		t.Fatalf("unexpected new request: %+v", created)
+	}
+	if result.PreApproval == nil || result.PreApproval.NewRequestStatus != db.StatusApproved {
+		t.Errorf("expected the reviewer's approval to satisfy quorum, got %+v", result.PreApproval)
+	}
+
+	reviews, err := database.ListReviewsForRequest(created.ID)
+	if err != nil || len(reviews) != 1 || reviews[0].ReviewerAgent != "Reviewer" ||
+		!db.VerifyReviewSignature(reviewer.SessionKey, created.ID, db.DecisionApprove, reviews[0].SignatureTimestamp, reviews[0].Signature) {
+		t.Fatalf("reviews = %+v, %v", reviews, err)
+	}
+
+	original, err := database.GetRequest(req.ID)
+	if err != nil || original.Status != db.StatusCancelled {
+		t.Errorf("original request: %v %v", original.Status, err)
+	}
+	linked, err := database.GetCommandEditForRequest(created.ID)
+	if err != nil || linked.ID != edit.ID || linked.RequestID != req.ID || linked.Status != db.CommandEditAccepted || linked.AcceptedAt == nil {
+		t.Errorf("linked edit = %+v, %v", linked, err)
+	}
+	for _, id := range []string{req.ID, created.ID} {
+		events, err := database.ListRequestEvents(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		found := false
+		for _, e := range events {
+			found = found || e.Type == db.RequestEventEditAccepted
+		}
+		if !found {
+			t.Errorf("request %s has no edit_accepted event: %+v", id, events)
+		}
+	}
+
+	if _, err := rs.AcceptEdit(context.Background(), creator, AcceptEditOptions{SessionID: requestor.ID, SessionKey: requestor.SessionKey, EditID: edit.ID}); !errors.Is(err, ErrEditNotProposed) {
+		t.Errorf("accepting twice: %v", err)
+	}
+}
+
+// TestAcceptEdit_FreshAuth checks that a re-authenticated edit is approved
+// with a fresh authentication recorded at acceptance, not the proposal's.
+func TestAcceptEdit_FreshAuth(t *testing.T) {
+	database, creator, requestor, reviewer, req := editFixture(t)
+	config := DefaultReviewConfig()
+	config.FreshAuthTiers = []db.RiskTier{db.RiskTierDangerous}
+	rs := NewReviewService(database, config)
+
+	authAt := time.Now().Add(-time.Minute)
+	edit, err := rs.ProposeEdit(ProposeEditOptions{
+		SessionID:  reviewer.ID,
+		SessionKey: reviewer.SessionKey,
+		RequestID:  req.ID,
+		Command:    "git push --force-with-lease origin main",
+		FreshAuth:  &FreshAuth{Method: "passphrase", At: authAt},
+	})
+	if err != nil {
+		t.Fatalf("ProposeEdit: %v", err)
+	}
+
+	accepted := time.Now().Add(-time.Second)
+	result, err := rs.AcceptEdit(context.Background(), creator, AcceptEditOptions{SessionID: requestor.ID, SessionKey: requestor.SessionKey, EditID: edit.ID})
+	if err != nil {
+		t.Fatalf("AcceptEdit: %v", err)
+	}
+	if result.PreApprovalErr != nil {
+		t.Fatalf("pre-approval failed: %v", result.PreApprovalErr)
+	}
+	reviews, err := database.ListReviewsForRequest(result.Created.Request.ID)
+	if err != nil || len(reviews) != 1 {
+		t.Fatalf("reviews = %+v, %v", reviews, err)
+	}
+	if at := reviews[0].AuthenticatedAt; reviews[0].AuthMethod != "passphrase" || at == nil || at.Before(accepted) {
+		t.Errorf("approval auth = %s at %v, want passphrase at acceptance (after %s)", reviews[0].AuthMethod, at, accepted)
+	}
+}
+
+func TestAcceptEdit_SafeCommand(t *testing.T) {
+	database, creator, requestor, reviewer, req := editFixture(t)
+	rs := NewReviewService(database, DefaultReviewConfig())
+
+	edit, err := rs.ProposeEdit(ProposeEditOptions{
+		SessionID:  reviewer.ID,
+		SessionKey: reviewer.SessionKey,
+		RequestID:  req.ID,
+		Command:    "git status",
+	})
+	if err != nil {
+		t.Fatalf("ProposeEdit: %v", err)
+	}
+	result, err := rs.AcceptEdit(context.Background(), creator, AcceptEditOptions{SessionID: requestor.ID, SessionKey: requestor.SessionKey, EditID: edit.ID})
+	if err != nil {
+		t.Fatalf("AcceptEdit: %v", err)
+	}
+	if result.Created.Request != nil || !result.Created.Skipped || result.PreApproval != nil {
+		t.Errorf("expected no new request for a safe command, got %+v", result)
+	}
+	if original, _ := database.GetRequest(req.ID); original.Status != db.StatusCancelled {
+		t.Errorf("original request status = %s", original.Status)
+	}
+	if got, _ := database.GetCommandEdit(edit.ID); got.Status != db.CommandEditAccepted || got.AcceptedRequestID != "" {
+		t.Errorf("edit = %+v", got)
+	}
+}
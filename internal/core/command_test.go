@@ -129,6 +129,28 @@ func TestRunCommand(t *testing.T) {
 		}
 	})
 
+	t.Run("separate streams receive stdout and stderr apart", func(t *testing.T) {
+		spec := &db.CommandSpec{
+			Raw:   "echo to-out; echo to-err >&2",
+			Shell: true,
+		}
+		logPath := filepath.Join(t.TempDir(), "run.log")
+		var stdout, stderr bytes.Buffer
+		result, err := RunCommandStreams(context.Background(), spec, logPath, OutputStreams{Stdout: &stdout, Stderr: &stderr})
+		if err != nil {
+			t.Fatalf("RunCommandStreams error: %v", err)
+		}
+		if stdout.String() != "to-out\n" || stderr.String() != "to-err\n" {
+			t.Errorf("stdout = %q, stderr = %q", stdout.String(), stderr.String())
+		}
+		if !strings.Contains(result.Output, "to-out") || !strings.Contains(result.Output, "to-err") {
+			t.Errorf("captured output missing a stream: %q", result.Output)
+		}
+		if logged, _ := os.ReadFile(logPath); !strings.Contains(string(logged), "to-err") {
+			t.Errorf("log missing stderr: %q", logged)
+		}
+	})
+
 	t.Run("argv mode executes parsed command", func(t *testing.T) {
 		spec := &db.CommandSpec{
 			Raw:   "echo hello",
@@ -0,0 +1,580 @@
+package core
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// ComplianceFormatVersion identifies the layout of compliance bundles.
+const ComplianceFormatVersion = 1
+
+// Files inside a compliance bundle.
+const (
+	ComplianceManifestFile     = "manifest.json"
+	ComplianceAuditFile        = "audit.jsonl"
+	CompliancePatternsFile     = "patterns.json"
+	CompliancePoliciesFile     = "policies.json"
+	ComplianceVerificationFile = "verification.json"
+)
+
+// Audit record types written to audit.jsonl.
+const (
+	AuditRequestCreated  = "request_created"
+	AuditReview          = "review"
+	AuditExecution       = "execution"
+	AuditRequestResolved = "request_resolved"
+	AuditBreakglass      = "breakglass"
+	AuditBreakglassAck   = "breakglass_ack"
+	AuditPatternChange   = "pattern_change"
+	AuditCustomPattern   = "custom_pattern"
+)
+
+// ErrInvalidPeriod is returned for a period that ParseCompliancePeriod does
+// not understand.
+var ErrInvalidPeriod = errors.New("invalid period")
+
+var (
+	quarterPeriodRe = regexp.MustCompile(`^(\d{4})-[Qq]([1-4])$`)
+	monthPeriodRe   = regexp.MustCompile(`^(\d{4})-(\d{2})$`)
+	yearPeriodRe    = regexp.MustCompile(`^(\d{4})$`)
+)
+
+// ParseCompliancePeriod parses a reporting period: a quarter (2025-Q1), a
+// month (2025-03) or a year (2025). It returns the UTC bounds [since, until).
+func ParseCompliancePeriod(period string) (since, until time.Time, err error) {
+	if m := quarterPeriodRe.FindStringSubmatch(period); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		q, _ := strconv.Atoi(m[2])
+		since = time.Date(year, time.Month(3*(q-1)+1), 1, 0, 0, 0, 0, time.UTC)
+		return since, since.AddDate(0, 3, 0), nil
+	}
+	if m := monthPeriodRe.FindStringSubmatch(period); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		month, _ := strconv.Atoi(m[2])
+		if month >= 1 && month <= 12 {
+			since = time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+			return since, since.AddDate(0, 1, 0), nil
+		}
+	}
+	if m := yearPeriodRe.FindStringSubmatch(period); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		since = time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+		return since, since.AddDate(1, 0, 0), nil
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("%w %q: use YYYY-Qn, YYYY-MM or YYYY", ErrInvalidPeriod, period)
+}
+
+// AuditRecord is one line of a bundle's audit.jsonl. Records are chained:
+// each Hash covers the record (without Hash) and PrevHash, so removing,
+// reordering or editing a line breaks every hash after it.
+type AuditRecord struct {
+	Seq       int             `json:"seq"`
+	Type      string          `json:"type"`
+	At        time.Time       `json:"at"`
+	RequestID string          `json:"request_id,omitempty"`
+	Data      json.RawMessage `json:"data"`
+	PrevHash  string          `json:"prev_hash"`
+	Hash      string          `json:"hash,omitempty"`
+}
+
+// computeHash returns the chain hash of the record.
+func (r AuditRecord) computeHash() (string, error) {
+	r.Hash = ""
+	body, err := json.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// ComplianceOptions selects what a compliance bundle covers.
+type ComplianceOptions struct {
+	// Period is the label recorded in the manifest (e.g. 2025-Q1).
+	Period string
+	// Since and Until bound the period: requests created in [Since, Until)
+	// are exported with their whole lifecycle.
+	Since time.Time
+	Until time.Time
+	// ProjectPath limits the export to one project; empty means all.
+	ProjectPath string
+	// Policies is the approval policy in effect, written to policies.json.
+	Policies any
+	// Patterns is the classification pattern set in effect.
+	Patterns *PatternExport
+	// Now is the generation time; time.Now when zero.
+	Now time.Time
+}
+
+// ComplianceManifest describes a bundle and pins the digest of every file in
+// it.
+type ComplianceManifest struct {
+	FormatVersion int               `json:"format_version"`
+	Period        string            `json:"period"`
+	Since         time.Time         `json:"since"`
+	Until         time.Time         `json:"until"`
+	ProjectPath   string            `json:"project_path,omitempty"`
+	GeneratedAt   time.Time         `json:"generated_at"`
+	SchemaVersion int               `json:"schema_version"`
+	Records       int               `json:"records"`
+	ChainHead     string            `json:"chain_head"`
+	Files         map[string]string `json:"files"`
+}
+
+// ComplianceVerification holds the integrity checks run while exporting (or
+// when verifying a bundle).
+type ComplianceVerification struct {
+	Chain      ChainVerification     `json:"audit_chain"`
+	Signatures SignatureVerification `json:"review_signatures"`
+	// Files lists bundle files whose digest does not match the manifest
+	// (only set by VerifyComplianceBundle).
+	FileMismatches []string `json:"file_mismatches,omitempty"`
+}
+
+// ChainVerification is the result of recomputing the audit chain.
+type ChainVerification struct {
+	Records int    `json:"records"`
+	Head    string `json:"head"`
+	Valid   bool   `json:"valid"`
+	// BrokenAt is the sequence number of the first bad record.
+	BrokenAt int    `json:"broken_at,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// SignatureVerification is the result of checking review HMACs against the
+// reviewers' session keys.
+type SignatureVerification struct {
+	Checked int `json:"checked"`
+	Valid   int `json:"valid"`
+	// Invalid lists reviews whose signature does not match.
+	Invalid []string `json:"invalid,omitempty"`
+	// Unverifiable lists reviews whose session (and key) no longer exists.
+	Unverifiable []string `json:"unverifiable,omitempty"`
+}
+
+// OK reports whether every check passed. Unverifiable signatures do not
+// count as failures.
+func (v *ComplianceVerification) OK() bool {
+	return v.Chain.Valid && len(v.Signatures.Invalid) == 0 && len(v.FileMismatches) == 0
+}
+
+// ExportCompliance writes a zip bundle of change-management evidence for the
+// period to w: the hash-chained audit log, the pattern set and its change
+// history, the approval policies, and the verification results.
+func ExportCompliance(w io.Writer, database *db.DB, opts ComplianceOptions) (*ComplianceManifest, *ComplianceVerification, error) {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now().UTC()
+	}
+
+	records, sigs, err := collectAuditRecords(database, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	var audit bytes.Buffer
+	prev := ""
+	for i := range records {
+		records[i].Seq = i + 1
+		records[i].PrevHash = prev
+		if records[i].Hash, err = records[i].computeHash(); err != nil {
+			return nil, nil, fmt.Errorf("hashing audit record: %w", err)
+		}
+		prev = records[i].Hash
+		line, err := json.Marshal(records[i])
+		if err != nil {
+			return nil, nil, fmt.Errorf("encoding audit record: %w", err)
+		}
+		audit.Write(line)
+		audit.WriteByte('\n')
+	}
+
+	verification := &ComplianceVerification{
+		Chain:      VerifyAuditChain(bytes.NewReader(audit.Bytes())),
+		Signatures: *sigs,
+	}
+
+	patterns, err := compliancePatterns(database, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	files := []bundleFile{{ComplianceAuditFile, audit.Bytes()}}
+	for _, f := range []struct {
+		name string
+		data any
+	}{
+		{CompliancePatternsFile, patterns},
+		{CompliancePoliciesFile, opts.Policies},
+		{ComplianceVerificationFile, verification},
+	} {
+		body, err := json.MarshalIndent(f.data, "", "  ")
+		if err != nil {
+			return nil, nil, fmt.Errorf("encoding %s: %w", f.name, err)
+		}
+		files = append(files, bundleFile{f.name, body})
+	}
+
+	manifest := &ComplianceManifest{
+		FormatVersion: ComplianceFormatVersion,
+		Period:        opts.Period,
+		Since:         opts.Since,
+		Until:         opts.Until,
+		ProjectPath:   opts.ProjectPath,
+		GeneratedAt:   now,
+		SchemaVersion: db.SchemaVersion,
+		Records:       len(records),
+		ChainHead:     prev,
+		Files:         map[string]string{},
+	}
+
+	zw := zip.NewWriter(w)
+	for _, f := range files {
+		if err := writeZipFile(zw, f.name, f.body, now); err != nil {
+			return nil, nil, err
+		}
+		sum := sha256.Sum256(f.body)
+		manifest.Files[f.name] = hex.EncodeToString(sum[:])
+	}
+	body, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, nil, fmt.Errorf("encoding manifest: %w", err)
+	}
+	if err := writeZipFile(zw, ComplianceManifestFile, body, now); err != nil {
+		return nil, nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, nil, fmt.Errorf("finishing bundle: %w", err)
+	}
+	return manifest, verification, nil
+}
+
+// bundleFile is one file written into a compliance bundle.
+type bundleFile struct {
+	name string
+	body []byte
+}
+
+func writeZipFile(zw *zip.Writer, name string, body []byte, modified time.Time) error {
+	fw, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified})
+	if err != nil {
+		return fmt.Errorf("adding %s: %w", name, err)
+	}
+	if _, err := fw.Write(body); err != nil {
+		return fmt.Errorf("writing %s: %w", name, err)
+	}
+	return nil
+}
+
+// collectAuditRecords gathers the period's records in time order (without
+// chain fields) and checks review signatures along the way.
+func collectAuditRecords(database *db.DB, opts ComplianceOptions) ([]AuditRecord, *SignatureVerification, error) {
+	var records []AuditRecord
+	add := func(typ string, at time.Time, requestID string, data any) error {
+		raw, err := json.Marshal(data)
+		if err != nil {
+			return fmt.Errorf("encoding %s record: %w", typ, err)
+		}
+		records = append(records, AuditRecord{Type: typ, At: at.UTC(), RequestID: requestID, Data: raw})
+		return nil
+	}
+	inPeriod := func(t time.Time) bool {
+		return !t.Before(opts.Since) && t.Before(opts.Until)
+	}
+
+	sigs := &SignatureVerification{}
+	sessionKeys := map[string]*string{}
+	sessionKey := func(id string) *string {
+		if key, ok := sessionKeys[id]; ok {
+			return key
+		}
+		var key *string
+		if s, err := database.GetSession(id); err == nil {
+			key = &s.SessionKey
+		}
+		sessionKeys[id] = key
+		return key
+	}
+
+	query := db.RequestPageQuery{ProjectPath: opts.ProjectPath, Since: opts.Since, Until: opts.Until, Limit: reportPageSize}
+	for {
+		page, next, err := database.ListRequestsPage(query)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, r := range page {
+			if err := add(AuditRequestCreated, r.CreatedAt, r.ID, map[string]any{
+				"project_path":            r.ProjectPath,
+				"command":                 reportCommand(r),
+				"command_hash":            r.Command.Hash,
+				"cwd":                     r.Command.Cwd,
+				"risk_tier":               r.RiskTier,
+				"requestor_agent":         r.RequestorAgent,
+				"requestor_model":         r.RequestorModel,
+				"justification":           r.Justification,
+				"min_approvals":           r.MinApprovals,
+				"require_different_model": r.RequireDifferentModel,
+			}); err != nil {
+				return nil, nil, err
+			}
+
+			reviews, err := database.ListReviewsForRequest(r.ID)
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, rev := range reviews {
+				sigs.Checked++
+				signatureValid := false
+				if key := sessionKey(rev.ReviewerSessionID); key == nil {
+					sigs.Unverifiable = append(sigs.Unverifiable, rev.ID)
+				} else if db.VerifyReviewSignature(*key, rev.RequestID, rev.Decision, rev.SignatureTimestamp, rev.Signature) {
+					sigs.Valid++
+					signatureValid = true
+				} else {
+					sigs.Invalid = append(sigs.Invalid, rev.ID)
+				}
+				if err := add(AuditReview, rev.CreatedAt, r.ID, map[string]any{
+					"review_id":       rev.ID,
+					"reviewer_agent":  rev.ReviewerAgent,
+					"reviewer_model":  rev.ReviewerModel,
+					"decision":        rev.Decision,
+					"comments":        rev.Comments,
+					"signature":       rev.Signature,
+					"signature_valid": signatureValid,
+				}); err != nil {
+					return nil, nil, err
+				}
+			}
+
+			if e := r.Execution; e != nil && e.ExecutedAt != nil {
+				if err := add(AuditExecution, *e.ExecutedAt, r.ID, map[string]any{
+					"exit_code":         e.ExitCode,
+					"duration_ms":       e.DurationMs,
+					"executed_by":       e.ExecutedByAgent,
+					"executed_by_model": e.ExecutedByModel,
+				}); err != nil {
+					return nil, nil, err
+				}
+			}
+			if r.ResolvedAt != nil {
+				if err := add(AuditRequestResolved, *r.ResolvedAt, r.ID, map[string]any{
+					"status": r.Status,
+				}); err != nil {
+					return nil, nil, err
+				}
+			}
+		}
+		if next == nil {
+			break
+		}
+		query.After = next
+	}
+
+	if opts.ProjectPath != "" {
+		incidents, err := database.ListBreakglassIncidents(opts.ProjectPath, false)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, inc := range incidents {
+			if !inPeriod(inc.ExecutedAt) {
+				continue
+			}
+			if err := add(AuditBreakglass, inc.ExecutedAt, "", map[string]any{
+				"incident_id": inc.ID,
+				"actor":       inc.Actor,
+				"command":     inc.Command,
+				"reason":      inc.Reason,
+				"exit_code":   inc.ExitCode,
+				"ack_due_at":  inc.AckDueAt,
+			}); err != nil {
+				return nil, nil, err
+			}
+			if inc.AcknowledgedAt != nil {
+				if err := add(AuditBreakglassAck, *inc.AcknowledgedAt, "", map[string]any{
+					"incident_id": inc.ID,
+					"by":          inc.AcknowledgedBy,
+					"postmortem":  inc.Postmortem,
+				}); err != nil {
+					return nil, nil, err
+				}
+			}
+		}
+	}
+
+	changes, err := database.ListAllPatternChanges()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, pc := range changes {
+		if inPeriod(pc.CreatedAt) {
+			if err := add(AuditPatternChange, pc.CreatedAt, "", pc); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	custom, err := database.ListCustomPatterns()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, cp := range custom {
+		if inPeriod(cp.CreatedAt) {
+			if err := add(AuditCustomPattern, cp.CreatedAt, "", cp); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	sort.SliceStable(records, func(i, j int) bool { return records[i].At.Before(records[j].At) })
+	return records, sigs, nil
+}
+
+// CompliancePatterns is the content of patterns.json: the pattern set in
+// effect at export time and how it changed up to the end of the period.
+type CompliancePatterns struct {
+	Current *PatternExport `json:"current,omitempty"`
+	// Custom lists patterns added to the database by the period's end.
+	Custom []*db.CustomPattern `json:"custom"`
+	// Changes lists proposed and decided pattern changes by the period's
+	// end, oldest first.
+	Changes []*db.PatternChange `json:"changes"`
+}
+
+func compliancePatterns(database *db.DB, opts ComplianceOptions) (*CompliancePatterns, error) {
+	out := &CompliancePatterns{
+		Current: opts.Patterns,
+		Custom:  []*db.CustomPattern{},
+		Changes: []*db.PatternChange{},
+	}
+	custom, err := database.ListCustomPatterns()
+	if err != nil {
+		return nil, err
+	}
+	for _, cp := range custom {
+		if cp.CreatedAt.Before(opts.Until) {
+			out.Custom = append(out.Custom, cp)
+		}
+	}
+	changes, err := database.ListAllPatternChanges()
+	if err != nil {
+		return nil, err
+	}
+	for _, pc := range changes {
+		if pc.CreatedAt.Before(opts.Until) {
+			out.Changes = append(out.Changes, pc)
+		}
+	}
+	sort.SliceStable(out.Changes, func(i, j int) bool { return out.Changes[i].CreatedAt.Before(out.Changes[j].CreatedAt) })
+	return out, nil
+}
+
+// VerifyAuditChain recomputes the hash chain of an audit.jsonl stream.
+func VerifyAuditChain(r io.Reader) ChainVerification {
+	result := ChainVerification{Valid: true}
+	fail := func(seq int, format string, args ...any) ChainVerification {
+		result.Valid = false
+		result.BrokenAt = seq
+		result.Error = fmt.Sprintf(format, args...)
+		return result
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16<<20)
+	prev := ""
+	for scanner.Scan() {
+		seq := result.Records + 1
+		var rec AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return fail(seq, "record %d: %v", seq, err)
+		}
+		if rec.Seq != seq {
+			return fail(seq, "record %d has sequence number %d", seq, rec.Seq)
+		}
+		if rec.PrevHash != prev {
+			return fail(seq, "record %d does not follow the previous record", seq)
+		}
+		hash, err := rec.computeHash()
+		if err != nil {
+			return fail(seq, "record %d: %v", seq, err)
+		}
+		if hash != rec.Hash {
+			return fail(seq, "record %d hash mismatch", seq)
+		}
+		prev = rec.Hash
+		result.Records++
+	}
+	if err := scanner.Err(); err != nil {
+		return fail(result.Records+1, "reading audit log: %v", err)
+	}
+	result.Head = prev
+	return result
+}
+
+// VerifyComplianceBundle checks a bundle written by ExportCompliance: every
+// file digest against the manifest and the audit chain against the
+// manifest's head. Signature results are those recorded at export time,
+// since session keys are not part of the bundle.
+func VerifyComplianceBundle(r io.ReaderAt, size int64) (*ComplianceManifest, *ComplianceVerification, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening bundle: %w", err)
+	}
+	contents := map[string][]byte{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading %s: %w", f.Name, err)
+		}
+		body, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading %s: %w", f.Name, err)
+		}
+		contents[f.Name] = body
+	}
+
+	manifest := &ComplianceManifest{}
+	body, ok := contents[ComplianceManifestFile]
+	if !ok {
+		return nil, nil, fmt.Errorf("bundle has no %s", ComplianceManifestFile)
+	}
+	if err := json.Unmarshal(body, manifest); err != nil {
+		return nil, nil, fmt.Errorf("parsing %s: %w", ComplianceManifestFile, err)
+	}
+
+	verification := &ComplianceVerification{}
+	if body, ok := contents[ComplianceVerificationFile]; ok {
+		_ = json.Unmarshal(body, verification)
+	}
+	verification.FileMismatches = nil
+	names := make([]string, 0, len(manifest.Files))
+	for name := range manifest.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		body, ok := contents[name]
+		sum := sha256.Sum256(body)
+		if !ok || hex.EncodeToString(sum[:]) != manifest.Files[name] {
+			verification.FileMismatches = append(verification.FileMismatches, name)
+		}
+	}
+
+	verification.Chain = VerifyAuditChain(bytes.NewReader(contents[ComplianceAuditFile]))
+	if verification.Chain.Valid && (verification.Chain.Head != manifest.ChainHead || verification.Chain.Records != manifest.Records) {
+		verification.Chain.Valid = false
+		verification.Chain.Error = "audit log does not end at the manifest's chain head"
+	}
+	return manifest, verification, nil
+}
@@ -0,0 +1,219 @@
+package core
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestParseCompliancePeriod(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		period      string
+		since, till time.Time
+	}{
+		{"2025-Q1", day(2025, 1, 1), day(2025, 4, 1)},
+		{"2025-q4", day(2025, 10, 1), day(2026, 1, 1)},
+		{"2025-03", day(2025, 3, 1), day(2025, 4, 1)},
+		{"2025-12", day(2025, 12, 1), day(2026, 1, 1)},
+		{"2025", day(2025, 1, 1), day(2026, 1, 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.period, func(t *testing.T) {
+			since, until, err := ParseCompliancePeriod(tt.period)
+			testutil.RequireNoError(t, err, "parse")
+			testutil.RequireEqual(t, tt.since, since, "since")
+			testutil.RequireEqual(t, tt.till, until, "until")
+		})
+	}
+	for _, bad := range []string{"", "2025-Q5", "2025-13", "Q1", "last quarter"} {
+		if _, _, err := ParseCompliancePeriod(bad); !errors.Is(err, ErrInvalidPeriod) {
+			t.Errorf("ParseCompliancePeriod(%q) error = %v, want ErrInvalidPeriod", bad, err)
+		}
+	}
+}
+
+// exportTestBundle creates a small history in January 2026 and exports it.
+func exportTestBundle(t *testing.T) ([]byte, *ComplianceManifest, *ComplianceVerification) {
+	t.Helper()
+	database := testutil.NewTestDB(t)
+	clk := testutil.NewFakeClock(time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC))
+	database.SetClock(clk)
+
+	session := testutil.MakeSession(t, database, testutil.WithProject("/proj"))
+	reviewer := testutil.MakeSession(t, database, testutil.WithProject("/proj"), testutil.WithAgent("Reviewer"))
+	req := testutil.MakeRequest(t, database, session,
+		testutil.WithCommand("kubectl delete namespace staging", "/proj", true),
+		testutil.WithRisk(db.RiskTierCritical))
+
+	clk.Advance(5 * time.Minute)
+	signedAt := clk.Now().UTC()
+	testutil.RequireNoError(t, database.CreateReview(&db.Review{
+		RequestID: req.ID, ReviewerSessionID: reviewer.ID, ReviewerAgent: "Reviewer",
+		Decision:           db.DecisionApprove,
+		Signature:          db.ComputeReviewSignature(reviewer.SessionKey, req.ID, db.DecisionApprove, signedAt),
+		SignatureTimestamp: signedAt,
+	}), "review")
+
+	clk.Advance(time.Minute)
+	testutil.RequireNoError(t, database.CreatePatternChange(&db.PatternChange{
+		Tier: "critical", Pattern: `^kubectl\s+drain`, ChangeType: db.PatternChangeTypeAdd, Reason: "drains evict workloads",
+	}), "pattern change")
+
+	// Outside the period.
+	clk.Set(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC))
+	testutil.MakeRequest(t, database, session, testutil.WithCommand("rm -rf ./tmp", "/proj", true))
+
+	since, until, err := ParseCompliancePeriod("2026-01")
+	testutil.RequireNoError(t, err, "period")
+
+	var buf bytes.Buffer
+	manifest, verification, err := ExportCompliance(&buf, database, ComplianceOptions{
+		Period: "2026-01", Since: since, Until: until, ProjectPath: "/proj",
+		Policies: map[string]any{"min_approvals": 2},
+		Patterns: NewPatternEngine().Export(),
+		Now:      clk.Now(),
+	})
+	testutil.RequireNoError(t, err, "export")
+	return buf.Bytes(), manifest, verification
+}
+
+func readBundle(t *testing.T, bundle []byte) map[string][]byte {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(bundle), int64(len(bundle)))
+	testutil.RequireNoError(t, err, "open zip")
+	files := map[string][]byte{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		testutil.RequireNoError(t, err, "open "+f.Name)
+		body, err := io.ReadAll(rc)
+		rc.Close()
+		testutil.RequireNoError(t, err, "read "+f.Name)
+		files[f.Name] = body
+	}
+	return files
+}
+
+func writeBundle(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, body := range files {
+		w, err := zw.Create(name)
+		testutil.RequireNoError(t, err, "create "+name)
+		_, err = w.Write(body)
+		testutil.RequireNoError(t, err, "write "+name)
+	}
+	testutil.RequireNoError(t, zw.Close(), "close zip")
+	return buf.Bytes()
+}
+
+func TestExportCompliance(t *testing.T) {
+	bundle, manifest, verification := exportTestBundle(t)
+
+	testutil.RequireEqual(t, 3, manifest.Records, "records")
+	testutil.RequireEqual(t, db.SchemaVersion, manifest.SchemaVersion, "schema version")
+	if !verification.OK() || !verification.Chain.Valid {
+		t.Fatalf("expected a clean verification, got %+v", verification)
+	}
+	testutil.RequireEqual(t, 1, verification.Signatures.Checked, "signatures checked")
+	testutil.RequireEqual(t, 1, verification.Signatures.Valid, "signatures valid")
+
+	files := readBundle(t, bundle)
+	for _, name := range []string{ComplianceManifestFile, ComplianceAuditFile, CompliancePatternsFile, CompliancePoliciesFile, ComplianceVerificationFile} {
+		if _, ok := files[name]; !ok {
+			t.Fatalf("bundle is missing %s", name)
+		}
+	}
+
+	var types []string
+	for _, line := range strings.Split(strings.TrimSpace(string(files[ComplianceAuditFile])), "\n") {
+		var rec AuditRecord
+		testutil.RequireNoError(t, json.Unmarshal([]byte(line), &rec), "parse audit line")
+		types = append(types, rec.Type)
+	}
+	testutil.RequireEqual(t, "request_created,review,pattern_change", strings.Join(types, ","), "record types")
+
+	var patterns CompliancePatterns
+	testutil.RequireNoError(t, json.Unmarshal(files[CompliancePatternsFile], &patterns), "parse patterns")
+	testutil.RequireLen(t, patterns.Changes, 1, "pattern changes")
+	if patterns.Current == nil || patterns.Current.Metadata.PatternCount == 0 {
+		t.Fatal("expected the current pattern set")
+	}
+	if !strings.Contains(string(files[CompliancePoliciesFile]), `"min_approvals": 2`) {
+		t.Fatalf("unexpected policies.json: %s", files[CompliancePoliciesFile])
+	}
+
+	_, verified, err := VerifyComplianceBundle(bytes.NewReader(bundle), int64(len(bundle)))
+	testutil.RequireNoError(t, err, "verify")
+	if !verified.OK() {
+		t.Fatalf("expected the exported bundle to verify, got %+v", verified)
+	}
+}
+
+func TestVerifyComplianceBundle_DetectsTampering(t *testing.T) {
+	bundle, _, _ := exportTestBundle(t)
+
+	t.Run("edited record", func(t *testing.T) {
+		files := readBundle(t, bundle)
+		files[ComplianceAuditFile] = bytes.Replace(files[ComplianceAuditFile], []byte("staging"), []byte("scratch"), 1)
+		tampered := writeBundle(t, files)
+		_, v, err := VerifyComplianceBundle(bytes.NewReader(tampered), int64(len(tampered)))
+		testutil.RequireNoError(t, err, "verify")
+		if v.OK() || v.Chain.Valid || v.Chain.BrokenAt != 1 {
+			t.Fatalf("expected a broken chain at record 1, got %+v", v)
+		}
+		testutil.RequireEqual(t, ComplianceAuditFile, strings.Join(v.FileMismatches, ","), "mismatches")
+	})
+
+	t.Run("dropped record", func(t *testing.T) {
+		files := readBundle(t, bundle)
+		lines := strings.SplitAfter(string(files[ComplianceAuditFile]), "\n")
+		files[ComplianceAuditFile] = []byte(strings.Join(lines[:len(lines)-2], ""))
+		tampered := writeBundle(t, files)
+		_, v, err := VerifyComplianceBundle(bytes.NewReader(tampered), int64(len(tampered)))
+		testutil.RequireNoError(t, err, "verify")
+		if v.OK() || v.Chain.Valid {
+			t.Fatalf("expected a truncated chain to fail, got %+v", v)
+		}
+	})
+
+	t.Run("edited policy", func(t *testing.T) {
+		files := readBundle(t, bundle)
+		files[CompliancePoliciesFile] = []byte(`{"min_approvals": 0}`)
+		tampered := writeBundle(t, files)
+		_, v, err := VerifyComplianceBundle(bytes.NewReader(tampered), int64(len(tampered)))
+		testutil.RequireNoError(t, err, "verify")
+		if v.OK() || !v.Chain.Valid {
+			t.Fatalf("expected only a file mismatch, got %+v", v)
+		}
+		testutil.RequireEqual(t, CompliancePoliciesFile, strings.Join(v.FileMismatches, ","), "mismatches")
+	})
+}
+
+func TestExportCompliance_InvalidSignature(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	session := testutil.MakeSession(t, database, testutil.WithProject("/proj"))
+	reviewer := testutil.MakeSession(t, database, testutil.WithProject("/proj"))
+	req := testutil.MakeRequest(t, database, session)
+	testutil.RequireNoError(t, database.CreateReview(&db.Review{
+		RequestID: req.ID, ReviewerSessionID: reviewer.ID, Decision: db.DecisionApprove, Signature: "forged",
+	}), "review")
+
+	now := time.Now().UTC()
+	_, verification, err := ExportCompliance(io.Discard, database, ComplianceOptions{
+		Since: now.Add(-time.Hour), Until: now.Add(time.Hour), ProjectPath: "/proj",
+	})
+	testutil.RequireNoError(t, err, "export")
+	if verification.OK() || len(verification.Signatures.Invalid) != 1 {
+		t.Fatalf("expected one invalid signature, got %+v", verification.Signatures)
+	}
+}
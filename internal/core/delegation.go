@@ -0,0 +1,204 @@
+// Package core provides approval delegation logic.
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// ParseDelegationUntil parses the end of a delegation window relative to now.
+//
+// Accepted forms:
+//   - durations: "8h", "90m", "3d", "2w"
+//   - weekdays: "fri", "friday" (end of the next such day, today included)
+//   - "today", "tomorrow"
+//   - dates: "2006-01-02" (end of that day, local time)
+//   - RFC3339 timestamps
+func ParseDelegationUntil(s string, now time.Time) (time.Time, error) {
+	raw := strings.ToLower(strings.TrimSpace(s))
+	if raw == "" {
+		return time.Time{}, fmt.Errorf("until is required")
+	}
+
+	endOfDay := func(t time.Time) time.Time {
+		y, m, d := t.Date()
+		return time.Date(y, m, d, 23, 59, 59, 0, t.Location())
+	}
+
+	switch raw {
+	case "today":
+		return endOfDay(now), nil
+	case "tomorrow":
+		return endOfDay(now.AddDate(0, 0, 1)), nil
+	}
+
+	if wd, ok := parseWeekday(raw); ok {
+		days := (int(wd) - int(now.Weekday()) + 7) % 7
+		return endOfDay(now.AddDate(0, 0, days)), nil
+	}
+
+	if n := len(raw); n > 1 && (raw[n-1] == 'd' || raw[n-1] == 'w') {
+		if v, err := strconv.Atoi(raw[:n-1]); err == nil && v > 0 {
+			days := v
+			if raw[n-1] == 'w' {
+				days = v * 7
+			}
+			return now.Add(time.Duration(days) * 24 * time.Hour), nil
+		}
+	}
+	if d, err := time.ParseDuration(raw); err == nil {
+		if d <= 0 {
+			return time.Time{}, fmt.Errorf("duration must be positive: %s", s)
+		}
+		return now.Add(d), nil
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02", raw, now.Location()); err == nil {
+		return endOfDay(t), nil
+	}
+	if t, err := time.Parse(time.RFC3339, strings.TrimSpace(s)); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("unrecognized time %q (use e.g. fri, tomorrow, 3d, 8h, 2006-01-02)", s)
+}
+
+func parseWeekday(s string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if s == name || s == name[:3] {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// DelegationOptions describes a delegation to create.
+type DelegationOptions struct {
+	// SessionID and SessionKey identify the delegator; the key signs the
+	// delegation.
+	SessionID   string
+	SessionKey  string
+	ProjectPath string
+	ToAgent     string
+	Reason      string
+	ExpiresAt   time.Time
+}
+
+// CreateDelegation records a delegation from the session in opts, signed
+// with its key. The session must be active and belong to the project.
+func CreateDelegation(database *db.DB, opts DelegationOptions) (*db.Delegation, error) {
+	if opts.SessionKey == "" {
+		return nil, ErrMissingSessionKey
+	}
+	session, err := delegationSession(database, opts.SessionID, opts.SessionKey, opts.ProjectPath)
+	if err != nil {
+		return nil, err
+	}
+	d := &db.Delegation{
+		ProjectPath:   opts.ProjectPath,
+		FromAgent:     session.AgentName,
+		FromSessionID: session.ID,
+		ToAgent:       strings.TrimSpace(opts.ToAgent),
+		Reason:        opts.Reason,
+		ExpiresAt:     opts.ExpiresAt.UTC(),
+	}
+	database.PrepareDelegation(d)
+	d.Signature = db.ComputeDelegationSignature(opts.SessionKey, d)
+	if err := database.CreateDelegation(d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// RevokeDelegation ends a delegation in projectPath early. The session
+// must be the delegator's or the delegate's.
+func RevokeDelegation(database *db.DB, sessionID, sessionKey, projectPath, id string) error {
+	if sessionKey == "" {
+		return ErrMissingSessionKey
+	}
+	session, err := delegationSession(database, sessionID, sessionKey, projectPath)
+	if err != nil {
+		return err
+	}
+	return database.RevokeDelegation(id, projectPath, session.AgentName)
+}
+
+// delegationSession returns the active session sessionID in projectPath,
+// after checking its key.
+func delegationSession(database *db.DB, sessionID, sessionKey, projectPath string) (*db.Session, error) {
+	session, err := database.GetSession(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("getting session: %w", err)
+	}
+	if !session.IsActive() {
+		return nil, ErrSessionInactive
+	}
+	if !db.SessionKeyMatches(sessionKey, session.SessionKey) {
+		return nil, ErrSessionKeyMismatch
+	}
+	if session.ProjectPath != projectPath {
+		return nil, fmt.Errorf("session %s belongs to project %s, not %s", session.ID, session.ProjectPath, projectPath)
+	}
+	return session, nil
+}
+
+// maxDelegatedApprovals caps the weight delegations add to one request.
+const maxDelegatedApprovals = 1
+
+// DelegatedApprovals returns the extra approval weight contributed by
+// active delegations, keyed by delegator session in delegators.
+//
+// A delegation counts when its delegate approved and its delegator is an
+// active session in the request's project that signed it, has not
+// reviewed the request, is not the requestor, and, if the request needs a
+// different model, runs one. Delegations are not transitive and add at
+// most one approval per request. On critical requests they count only
+// once two reviewers approved directly, so no single reviewer reaches
+// critical quorum through delegation.
+func DelegatedApprovals(request *db.Request, reviews []*db.Review, delegations []*db.Delegation, delegators map[string]*db.Session) (int, []string) {
+	if request == nil || len(delegations) == 0 {
+		return 0, nil
+	}
+
+	reviewed := make(map[string]bool, len(reviews))
+	approvers := make(map[string]bool, len(reviews))
+	for _, r := range reviews {
+		reviewed[r.ReviewerAgent] = true
+		if r.Decision == db.DecisionApprove {
+			approvers[r.ReviewerAgent] = true
+		}
+	}
+	if request.RiskTier == db.RiskTierCritical && len(approvers) < 2 {
+		return 0, nil
+	}
+
+	var onBehalfOf []string
+	for _, d := range delegations {
+		if len(onBehalfOf) >= maxDelegatedApprovals {
+			break
+		}
+		if d == nil || !approvers[d.ToAgent] || reviewed[d.FromAgent] {
+			continue
+		}
+		sess := delegators[d.FromSessionID]
+		if sess == nil || !sess.IsActive() || sess.AgentName != d.FromAgent || sess.ProjectPath != request.ProjectPath {
+			continue
+		}
+		if !db.VerifyDelegationSignature(sess.SessionKey, d) {
+			continue
+		}
+		if d.FromAgent == request.RequestorAgent || sess.ID == request.RequestorSessionID {
+			continue
+		}
+		if request.RequireDifferentModel && sess.Model == request.RequestorModel {
+			continue
+		}
+		onBehalfOf = append(onBehalfOf, d.FromAgent)
+	}
+	return len(onBehalfOf), onBehalfOf
+}
@@ -0,0 +1,334 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestParseDelegationUntil(t *testing.T) {
+	// Wednesday 2025-01-15 10:00 UTC
+	now := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		in   string
+		want time.Time
+	}{
+		{"fri", time.Date(2025, 1, 17, 23, 59, 59, 0, time.UTC)},
+		{"Friday", time.Date(2025, 1, 17, 23, 59, 59, 0, time.UTC)},
+		{"wed", time.Date(2025, 1, 15, 23, 59, 59, 0, time.UTC)},
+		{"tue", time.Date(2025, 1, 21, 23, 59, 59, 0, time.UTC)},
+		{"today", time.Date(2025, 1, 15, 23, 59, 59, 0, time.UTC)},
+		{"tomorrow", time.Date(2025, 1, 16, 23, 59, 59, 0, time.UTC)},
+		{"8h", now.Add(8 * time.Hour)},
+		{"3d", now.Add(72 * time.Hour)},
+		{"2w", now.Add(14 * 24 * time.Hour)},
+		{"2025-02-01", time.Date(2025, 2, 1, 23, 59, 59, 0, time.UTC)},
+		{"2025-02-01T12:00:00Z", time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range cases {
+		got, err := ParseDelegationUntil(tc.in, now)
+		if err != nil {
+			t.Fatalf("ParseDelegationUntil(%q) error: %v", tc.in, err)
+		}
+		if !got.Equal(tc.want) {
+			t.Errorf("ParseDelegationUntil(%q) = %v, want %v", tc.in, got, tc.want)
+		}
+	}
+
+	for _, bad := range []string{"", "someday", "-3h", "0d"} {
+		if _, err := ParseDelegationUntil(bad, now); err == nil {
+			t.Errorf("ParseDelegationUntil(%q) expected error", bad)
+		}
+	}
+}
+
+// delegate creates a delegation signed by from's session.
+func delegate(t *testing.T, database *db.DB, from *db.Session, to string) *db.Delegation {
+	t.Helper()
+	d, err := CreateDelegation(database, DelegationOptions{
+		SessionID:   from.ID,
+		SessionKey:  from.SessionKey,
+		ProjectPath: from.ProjectPath,
+		ToAgent:     to,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("CreateDelegation: %v", err)
+	}
+	return d
+}
+
+func TestDelegatedApprovals(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	project := "/test/project"
+	session := func(agent, model string) *db.Session {
+		return testutil.MakeSession(t, database,
+			testutil.WithProject(project), testutil.WithAgent(agent), testutil.WithModel(model))
+	}
+	requestor := session("Requestor", "model-a")
+	alice := session("Alice", "model-b")
+	carol := session("Carol", "model-b")
+	dave := session("Dave", "model-b")
+	frank := session("Frank", "model-b")
+	sameModel := session("Gina", "model-a")
+	other := testutil.MakeSession(t, database,
+		testutil.WithProject("/other/project"), testutil.WithAgent("Hank"), testutil.WithModel("model-b"))
+
+	req := &db.Request{ProjectPath: project, RequestorAgent: "Requestor", RequestorSessionID: requestor.ID,
+		RequestorModel: "model-a", RiskTier: db.RiskTierDangerous}
+	reviews := []*db.Review{
+		{ReviewerAgent: "Bob", Decision: db.DecisionApprove},
+		{ReviewerAgent: "Carol", Decision: db.DecisionReject},
+	}
+	sessions := map[string]*db.Session{}
+	for _, s := range []*db.Session{requestor, alice, carol, dave, frank, sameModel, other} {
+		sessions[s.ID] = s
+	}
+	count := func(t *testing.T, req *db.Request, reviews []*db.Review, delegations ...*db.Delegation) (int, []string) {
+		t.Helper()
+		return DelegatedApprovals(req, reviews, delegations, sessions)
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		extra, from := count(t, req, reviews, delegate(t, database, alice, "Bob"))
+		if extra != 1 || len(from) != 1 || from[0] != "Alice" {
+			t.Fatalf("DelegatedApprovals = %d %v, want 1 [Alice]", extra, from)
+		}
+	})
+
+	t.Run("ignored", func(t *testing.T) {
+		forged := delegate(t, database, alice, "Bob")
+		forged.FromAgent = "Mallory"
+		tampered := delegate(t, database, alice, "Bob")
+		tampered.ExpiresAt = tampered.ExpiresAt.Add(24 * time.Hour)
+		wrongKey := delegate(t, database, alice, "Bob")
+		wrongKey.Signature = db.ComputeDelegationSignature(dave.SessionKey, wrongKey)
+		unsigned := &db.Delegation{FromAgent: "Ghost", ToAgent: "Bob"}
+		noSession := &db.Delegation{FromAgent: "Ghost", FromSessionID: "no-such-session", ToAgent: "Bob", Signature: "00"}
+
+		cases := map[string]*db.Delegation{
+			"forged delegator":        forged,
+			"tampered window":         tampered,
+			"signed with another key": wrongKey,
+			"unsigned":                unsigned,
+			"non-session delegator":   noSession,
+			"delegator reviewed":      delegate(t, database, carol, "Bob"),
+			"requestor":               delegate(t, database, requestor, "Bob"),
+			"other project":           delegate(t, database, other, "Bob"),
+			"delegate rejected":       delegate(t, database, dave, "Carol"),
+			"delegate didn't review":  delegate(t, database, frank, "Nobody"),
+		}
+		for name, d := range cases {
+			if extra, _ := count(t, req, reviews, d); extra != 0 {
+				t.Errorf("%s: counted %d delegated approval(s)", name, extra)
+			}
+		}
+
+		diffModel := *req
+		diffModel.RequireDifferentModel = true
+		if extra, _ := count(t, &diffModel, reviews, delegate(t, database, sameModel, "Bob")); extra != 0 {
+			t.Error("a delegator on the requestor's model counted toward a different-model request")
+		}
+
+		ended := session("Ivy", "model-b")
+		d := delegate(t, database, ended, "Bob")
+		if err := database.EndSession(ended.ID); err != nil {
+			t.Fatal(err)
+		}
+		ended, _ = database.GetSession(ended.ID)
+		sessions[ended.ID] = ended
+		if extra, _ := count(t, req, reviews, d); extra != 0 {
+			t.Error("a delegator whose session ended was counted")
+		}
+	})
+
+	t.Run("capped at one", func(t *testing.T) {
+		extra, from := count(t, req, reviews, delegate(t, database, alice, "Bob"), delegate(t, database, frank, "Bob"))
+		if extra != 1 || len(from) != 1 {
+			t.Fatalf("DelegatedApprovals = %d %v, want a single extra approval", extra, from)
+		}
+	})
+
+	t.Run("critical needs two direct approvers", func(t *testing.T) {
+		critical := *req
+		critical.RiskTier = db.RiskTierCritical
+		if extra, _ := count(t, &critical, reviews, delegate(t, database, alice, "Bob")); extra != 0 {
+			t.Fatal("one direct approver reached critical quorum through delegation")
+		}
+		two := append(reviews[:1:1], &db.Review{ReviewerAgent: "Dave", Decision: db.DecisionApprove})
+		if extra, _ := count(t, &critical, two, delegate(t, database, alice, "Bob")); extra != 1 {
+			t.Fatal("delegation should count on a critical request with two direct approvers")
+		}
+	})
+
+	if extra, _ := DelegatedApprovals(req, reviews, nil, sessions); extra != 0 {
+		t.Fatalf("expected 0 without delegations, got %d", extra)
+	}
+}
+
+func TestCreateDelegation_RequiresOwnSession(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	project := "/test/project"
+	alice := testutil.MakeSession(t, database, testutil.WithProject(project), testutil.WithAgent("Alice"))
+	bob := testutil.MakeSession(t, database, testutil.WithProject(project), testutil.WithAgent("Bob"))
+
+	opts := DelegationOptions{SessionID: alice.ID, SessionKey: bob.SessionKey, ProjectPath: project,
+		ToAgent: "Bob", ExpiresAt: time.Now().Add(time.Hour)}
+	if _, err := CreateDelegation(database, opts); !errors.Is(err, ErrSessionKeyMismatch) {
+		t.Fatalf("delegating with another session's key: %v, want ErrSessionKeyMismatch", err)
+	}
+	opts.SessionKey = ""
+	if _, err := CreateDelegation(database, opts); !errors.Is(err, ErrMissingSessionKey) {
+		t.Fatalf("delegating without a key: %v, want ErrMissingSessionKey", err)
+	}
+	opts.SessionKey = alice.SessionKey
+	opts.ProjectPath = "/other/project"
+	if _, err := CreateDelegation(database, opts); err == nil {
+		t.Fatal("delegating into another project should fail")
+	}
+	if err := database.CreateDelegation(&db.Delegation{ProjectPath: project, FromAgent: "Ghost", ToAgent: "Bob",
+		ExpiresAt: time.Now().Add(time.Hour)}); err == nil {
+		t.Fatal("recording an unsigned delegation should fail")
+	}
+}
+
+func TestRevokeDelegation_OnlyParties(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	project := "/test/project"
+	alice := testutil.MakeSession(t, database, testutil.WithProject(project), testutil.WithAgent("Alice"))
+	bob := testutil.MakeSession(t, database, testutil.WithProject(project), testutil.WithAgent("Bob"))
+	carol := testutil.MakeSession(t, database, testutil.WithProject(project), testutil.WithAgent("Carol"))
+	elsewhere := testutil.MakeSession(t, database, testutil.WithProject("/other/project"), testutil.WithAgent("Alice"))
+
+	d := delegate(t, database, alice, "Bob")
+	if err := RevokeDelegation(database, carol.ID, carol.SessionKey, project, d.ID); !errors.Is(err, db.ErrDelegationNotFound) {
+		t.Fatalf("third party revoke: %v, want ErrDelegationNotFound", err)
+	}
+	if err := RevokeDelegation(database, elsewhere.ID, elsewhere.SessionKey, "/other/project", d.ID); !errors.Is(err, db.ErrDelegationNotFound) {
+		t.Fatalf("revoke from another project: %v, want ErrDelegationNotFound", err)
+	}
+	if err := RevokeDelegation(database, bob.ID, bob.SessionKey, project, d.ID); err != nil {
+		t.Fatalf("delegate revoke: %v", err)
+	}
+
+	d = delegate(t, database, alice, "Bob")
+	if err := RevokeDelegation(database, alice.ID, alice.SessionKey, project, d.ID); err != nil {
+		t.Fatalf("delegator revoke: %v", err)
+	}
+}
+
+func TestSubmitReview_DelegationSatisfiesQuorum(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	project := "/test/project"
+
+	requestor := testutil.MakeSession(t, database,
+		testutil.WithProject(project), testutil.WithAgent("Requestor"), testutil.WithModel("model-a"))
+	alice := testutil.MakeSession(t, database,
+		testutil.WithProject(project), testutil.WithAgent("Alice"), testutil.WithModel("model-b"))
+	bob := testutil.MakeSession(t, database,
+		testutil.WithProject(project), testutil.WithAgent("Bob"), testutil.WithModel("model-b"))
+
+	req := testutil.MakeRequest(t, database, requestor,
+		testutil.WithRisk(db.RiskTierDangerous),
+		testutil.WithMinApprovals(2),
+	)
+	delegate(t, database, alice, "Bob")
+
+	rs := NewReviewService(database, DefaultReviewConfig())
+	result, err := rs.SubmitReview(ReviewOptions{
+		SessionID:  bob.ID,
+		SessionKey: bob.SessionKey,
+		RequestID:  req.ID,
+		Decision:   db.DecisionApprove,
+	})
+	if err != nil {
+		t.Fatalf("SubmitReview: %v", err)
+	}
+	if result.Approvals != 2 || result.DelegatedApprovals != 1 {
+		t.Fatalf("expected 2 approvals (1 delegated), got %d (%d)", result.Approvals, result.DelegatedApprovals)
+	}
+	if !result.RequestStatusChanged || result.NewRequestStatus != db.StatusApproved {
+		t.Fatalf("expected request approved, got changed=%v status=%s", result.RequestStatusChanged, result.NewRequestStatus)
+	}
+}
+
+func TestSubmitReview_ForgedDelegationsIgnored(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	project := "/test/project"
+
+	requestor := testutil.MakeSession(t, database,
+		testutil.WithProject(project), testutil.WithAgent("Requestor"), testutil.WithModel("model-a"))
+	bob := testutil.MakeSession(t, database,
+		testutil.WithProject(project), testutil.WithAgent("Bob"), testutil.WithModel("model-b"))
+
+	req := testutil.MakeRequest(t, database, requestor,
+		testutil.WithRisk(db.RiskTierCritical),
+		testutil.WithMinApprovals(2),
+	)
+	// Rows for delegators with no session, as an older slb or a direct
+	// database write could leave.
+	for _, ghost := range []string{"ghost1", "ghost2"} {
+		if _, err := database.Exec(`INSERT INTO delegations (id, project_path, from_agent, from_session_id, to_agent,
+			starts_at, expires_at, created_at, signature) VALUES (?, ?, ?, ?, 'Bob', ?, ?, ?, 'deadbeef')`,
+			ghost, project, ghost, ghost+"-session",
+			time.Now().Add(-time.Hour).UTC().Format(time.RFC3339), time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+			time.Now().UTC().Format(time.RFC3339)); err != nil {
+			t.Fatalf("insert delegation: %v", err)
+		}
+	}
+
+	rs := NewReviewService(database, DefaultReviewConfig())
+	result, err := rs.SubmitReview(ReviewOptions{
+		SessionID:  bob.ID,
+		SessionKey: bob.SessionKey,
+		RequestID:  req.ID,
+		Decision:   db.DecisionApprove,
+	})
+	if err != nil {
+		t.Fatalf("SubmitReview: %v", err)
+	}
+	if result.Approvals != 1 || result.DelegatedApprovals != 0 || result.RequestStatusChanged {
+		t.Fatalf("forged delegations counted: approvals=%d delegated=%d changed=%v",
+			result.Approvals, result.DelegatedApprovals, result.RequestStatusChanged)
+	}
+}
+
+func TestSubmitReview_RevokedDelegationIgnored(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	project := "/test/project"
+
+	requestor := testutil.MakeSession(t, database,
+		testutil.WithProject(project), testutil.WithAgent("Requestor"), testutil.WithModel("model-a"))
+	alice := testutil.MakeSession(t, database,
+		testutil.WithProject(project), testutil.WithAgent("Alice"), testutil.WithModel("model-b"))
+	bob := testutil.MakeSession(t, database,
+		testutil.WithProject(project), testutil.WithAgent("Bob"), testutil.WithModel("model-b"))
+
+	req := testutil.MakeRequest(t, database, requestor,
+		testutil.WithRisk(db.RiskTierDangerous),
+		testutil.WithMinApprovals(2),
+	)
+
+	d := delegate(t, database, alice, "Bob")
+	if err := database.RevokeDelegation(d.ID, project, "Alice"); err != nil {
+		t.Fatalf("RevokeDelegation: %v", err)
+	}
+
+	rs := NewReviewService(database, DefaultReviewConfig())
+	result, err := rs.SubmitReview(ReviewOptions{
+		SessionID:  bob.ID,
+		SessionKey: bob.SessionKey,
+		RequestID:  req.ID,
+		Decision:   db.DecisionApprove,
+	})
+	if err != nil {
+		t.Fatalf("SubmitReview: %v", err)
+	}
+	if result.Approvals != 1 || result.RequestStatusChanged {
+		t.Fatalf("expected 1 approval and no status change, got %d changed=%v", result.Approvals, result.RequestStatusChanged)
+	}
+}
@@ -0,0 +1,271 @@
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// DeployPack is the pattern pack for deployment runners. Deployment context
+// is only attached to requests when it is enabled.
+const DeployPack = "deploy"
+
+// Deployment describes one deployment runner invocation in a command.
+type Deployment struct {
+	// Tool is the runner: ansible, ansible-playbook, cap or fab.
+	Tool string `json:"tool"`
+	// Stage is the environment deployed to, e.g. production: the
+	// Capistrano stage, a Fabric stage task, an ansible env/stage extra
+	// var, or the environment named by the inventory path.
+	Stage string `json:"stage,omitempty"`
+	// Inventory is the ansible inventory.
+	Inventory string `json:"inventory,omitempty"`
+	// Hosts is the ansible host pattern, or the Fabric hosts or roles.
+	Hosts string `json:"hosts,omitempty"`
+	// Limit is the ansible --limit.
+	Limit string `json:"limit,omitempty"`
+	// Playbooks are the ansible playbooks run.
+	Playbooks []string `json:"playbooks,omitempty"`
+	// Tasks are the Capistrano or Fabric tasks, or the ansible module.
+	Tasks []string `json:"tasks,omitempty"`
+}
+
+// stageNames are Fabric task names that select a deployment environment
+// (fab production deploy).
+var stageNames = map[string]bool{
+	"prod": true, "production": true, "prd": true, "live": true,
+	"staging": true, "stage": true, "preprod": true, "uat": true, "qa": true,
+	"test": true, "testing": true, "dev": true, "development": true,
+	"demo": true, "sandbox": true,
+}
+
+// genericInventoryNames are inventory file and directory names that say
+// nothing about the environment, so an enclosing directory names it instead.
+var genericInventoryNames = map[string]bool{
+	"hosts": true, "inventory": true, "inventories": true, "main": true,
+	"all": true, ".": true, "..": true,
+}
+
+// stageVarRe matches an env or stage assignment in ansible extra vars.
+var stageVarRe = regexp.MustCompile(`(?:^|[\s,{"'])(?:env|environment|stage|deploy_env)["']?\s*[=:]\s*["']?([A-Za-z0-9_-]+)`)
+
+// fabValueFlags are Fabric options that take a value.
+var fabValueFlags = map[string]bool{
+	"-H": true, "--hosts": true, "-R": true, "--roles": true,
+	"-i": true, "--identity": true, "-f": true, "--fabfile": true,
+	"-c": true, "--config": true, "-S": true, "--ssh-config": true,
+	"-t": true, "--connect-timeout": true, "-T": true, "--command-timeout": true,
+	"-u": true, "--user": true,
+}
+
+// DetectDeployments returns the deployment runners a command invokes
+// (ansible, ansible-playbook, Capistrano's cap and Fabric's fab) with the
+// stage, inventory, hosts and tasks each one names.
+func DetectDeployments(cmd string) []Deployment {
+	var out []Deployment
+	for _, st := range ScriptStatements(cmd) {
+		for _, seg := range splitCompoundShellAware(st.Text) {
+			for _, part := range splitPipeline(seg, true) {
+				words := commandWords(part)
+				if len(words) > 2 && filepath.Base(words[0]) == "bundle" && words[1] == "exec" {
+					words = words[2:]
+				}
+				if len(words) == 0 {
+					continue
+				}
+				var d *Deployment
+				switch name := filepath.Base(words[0]); name {
+				case "ansible", "ansible-playbook":
+					d = ansibleDeployment(name, words[1:])
+				case "cap":
+					d = capDeployment(words[1:])
+				case "fab":
+					d = fabDeployment(words[1:])
+				}
+				if d != nil {
+					out = append(out, *d)
+				}
+			}
+		}
+	}
+	return out
+}
+
+func ansibleDeployment(name string, args []string) *Deployment {
+	d := &Deployment{Tool: name}
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		flag, value, inline := strings.Cut(a, "=")
+		if strings.HasPrefix(a, "-") && ansibleValueFlags[flag] {
+			if !inline {
+				if i+1 >= len(args) {
+					break
+				}
+				i++
+				value = args[i]
+			}
+			switch flag {
+			case "-i", "--inventory":
+				d.Inventory = value
+			case "-l", "--limit":
+				d.Limit = value
+			case "-m", "--module-name":
+				d.Tasks = append(d.Tasks, value)
+			case "-e", "--extra-vars":
+				if m := stageVarRe.FindStringSubmatch(value); m != nil && d.Stage == "" {
+					d.Stage = strings.ToLower(m[1])
+				}
+			}
+			continue
+		}
+		if strings.HasPrefix(a, "-") {
+			continue
+		}
+		if name == "ansible" {
+			if d.Hosts == "" {
+				d.Hosts = a
+			}
+		} else {
+			d.Playbooks = append(d.Playbooks, a)
+		}
+	}
+	if d.Stage == "" {
+		d.Stage = inventoryStage(d.Inventory)
+	}
+	return d
+}
+
+// inventoryStage returns the environment an inventory path names: its file
+// name without extension, or the directory holding a generically named
+// file (inventories/production/hosts). Host lists name no stage.
+func inventoryStage(inv string) string {
+	if inv == "" || strings.Contains(inv, ",") {
+		return ""
+	}
+	parts := strings.FieldsFunc(strings.ToLower(inv), func(r rune) bool { return r == '/' || r == '\\' })
+	for i := len(parts) - 1; i >= 0; i-- {
+		name := strings.TrimSuffix(parts[i], filepath.Ext(parts[i]))
+		if genericInventoryNames[name] {
+			continue
+		}
+		return name
+	}
+	return ""
+}
+
+// capDeployment parses `cap STAGE TASK...`. A first argument that looks
+// like a task (deploy:check) means no stage was given.
+func capDeployment(args []string) *Deployment {
+	d := &Deployment{Tool: "cap"}
+	for _, a := range args {
+		if strings.HasPrefix(a, "-") {
+			continue
+		}
+		if d.Stage == "" && len(d.Tasks) == 0 && !strings.Contains(a, ":") {
+			d.Stage = strings.ToLower(a)
+			continue
+		}
+		d.Tasks = append(d.Tasks, a)
+	}
+	return d
+}
+
+// fabDeployment parses `fab [-H hosts] [-R roles] TASK...`. A leading task
+// named after an environment (fab production deploy) is the stage.
+func fabDeployment(args []string) *Deployment {
+	d := &Deployment{Tool: "fab"}
+	var hosts []string
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		flag, value, inline := strings.Cut(a, "=")
+		if strings.HasPrefix(a, "-") {
+			if fabValueFlags[flag] {
+				if !inline {
+					if i+1 >= len(args) {
+						break
+					}
+					i++
+					value = args[i]
+				}
+				if flag == "-H" || flag == "--hosts" || flag == "-R" || flag == "--roles" {
+					hosts = append(hosts, value)
+				}
+			}
+			continue
+		}
+		task := strings.SplitN(a, ":", 2)[0]
+		if d.Stage == "" && len(d.Tasks) == 0 && stageNames[strings.ToLower(task)] {
+			d.Stage = strings.ToLower(task)
+			continue
+		}
+		d.Tasks = append(d.Tasks, a)
+	}
+	d.Hosts = strings.Join(hosts, ",")
+	return d
+}
+
+// String describes the deployment for reviewers, e.g. "ansible-playbook
+// site.yml: stage production, inventory inventories/production/hosts".
+func (d Deployment) String() string {
+	head := d.Tool
+	if len(d.Playbooks) > 0 {
+		head += " " + strings.Join(d.Playbooks, " ")
+	}
+	var fields []string
+	if d.Stage != "" {
+		fields = append(fields, "stage "+d.Stage)
+	}
+	if d.Inventory != "" {
+		fields = append(fields, "inventory "+d.Inventory)
+	}
+	if d.Hosts != "" {
+		fields = append(fields, "hosts "+d.Hosts)
+	}
+	if d.Limit != "" {
+		fields = append(fields, "limit "+d.Limit)
+	}
+	if len(d.Tasks) > 0 {
+		fields = append(fields, "tasks "+strings.Join(d.Tasks, " "))
+	}
+	if len(fields) == 0 {
+		return head
+	}
+	return fmt.Sprintf("%s: %s", head, strings.Join(fields, ", "))
+}
+
+// DeploymentStage returns the first stage a command deploys to, or "".
+func DeploymentStage(cmd string) string {
+	for _, d := range DetectDeployments(cmd) {
+		if d.Stage != "" {
+			return d.Stage
+		}
+	}
+	return ""
+}
+
+// DeploymentsAttachment returns a command's deployments as a context
+// attachment for reviewers, one line each, or nil when there are none.
+func DeploymentsAttachment(deployments []Deployment) *db.Attachment {
+	if len(deployments) == 0 {
+		return nil
+	}
+	lines := make([]string, 0, len(deployments))
+	stage := ""
+	for _, d := range deployments {
+		lines = append(lines, d.String())
+		if stage == "" {
+			stage = d.Stage
+		}
+	}
+	return &db.Attachment{
+		Type:    db.AttachmentTypeContext,
+		Content: strings.Join(lines, "\n"),
+		Metadata: map[string]any{
+			"type":  "deployment",
+			"stage": stage,
+		},
+	}
+}
@@ -0,0 +1,130 @@
+package core
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestDetectDeployments(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []Deployment
+	}{
+		{"ansible-playbook -i inventories/production/hosts.ini --limit web site.yml",
+			[]Deployment{{Tool: "ansible-playbook", Stage: "production", Inventory: "inventories/production/hosts.ini", Limit: "web", Playbooks: []string{"site.yml"}}}},
+		{"ansible-playbook -i staging.yml -e 'env=prod' deploy.yml",
+			[]Deployment{{Tool: "ansible-playbook", Stage: "prod", Inventory: "staging.yml", Playbooks: []string{"deploy.yml"}}}},
+		{"ansible webservers -i prod -m shell -a 'systemctl restart app'",
+			[]Deployment{{Tool: "ansible", Stage: "prod", Inventory: "prod", Hosts: "webservers", Tasks: []string{"shell"}}}},
+		{"bundle exec cap production deploy deploy:migrate",
+			[]Deployment{{Tool: "cap", Stage: "production", Tasks: []string{"deploy", "deploy:migrate"}}}},
+		{"cap deploy:check", []Deployment{{Tool: "cap", Tasks: []string{"deploy:check"}}}},
+		{"fab -H web1,web2 production deploy:branch=main",
+			[]Deployment{{Tool: "fab", Stage: "production", Hosts: "web1,web2", Tasks: []string{"deploy:branch=main"}}}},
+		{"make build && ansible-playbook -i 'h1,h2,' site.yml",
+			[]Deployment{{Tool: "ansible-playbook", Inventory: "h1,h2,", Playbooks: []string{"site.yml"}}}},
+		{"kubectl apply -f deploy.yaml", nil},
+	}
+	for _, tt := range tests {
+		if got := DetectDeployments(tt.command); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("DetectDeployments(%q) =\n%+v\nwant\n%+v", tt.command, got, tt.want)
+		}
+	}
+
+	d := DetectDeployments("ansible-playbook -i inventories/production/hosts --limit web site.yml")[0]
+	if got, want := d.String(), "ansible-playbook site.yml: stage production, inventory inventories/production/hosts, limit web"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestDeployPack(t *testing.T) {
+	engine := NewPatternEngine()
+	if err := engine.EnablePack(DeployPack); err != nil {
+		t.Fatalf("EnablePack: %v", err)
+	}
+	tests := []struct {
+		command string
+		want    RiskTier
+	}{
+		{"ansible prod-web -i inventories/prod -m shell -a 'rm -f /tmp/x'", RiskTierCritical},
+		{"cap production db:reset", RiskTierCritical},
+		{"ansible-playbook -i inventories/production/hosts site.yml", RiskTierDangerous},
+		{"ansible-playbook --limit=prod-web site.yml", RiskTierDangerous},
+		{"bundle exec cap production deploy", RiskTierDangerous},
+		{"fab -H prod-web1 deploy", RiskTierDangerous},
+		{"fab production deploy", RiskTierDangerous},
+		{"ansible-playbook -i inventories/staging site.yml", RiskTierCaution},
+		{"cap staging deploy", RiskTierCaution},
+		{"fab staging deploy", RiskTierCaution},
+	}
+	for _, tt := range tests {
+		if got := engine.ClassifyCommand(tt.command, ""); got.Tier != tt.want {
+			t.Errorf("ClassifyCommand(%q) = %q, want %q", tt.command, got.Tier, tt.want)
+		}
+	}
+}
+
+func TestApplyTierOverrides_Stage(t *testing.T) {
+	overrides, err := ParseTierOverrides([]string{"stage:prod*=critical"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	caution := &MatchResult{Tier: RiskTierCaution, NeedsApproval: true}
+
+	s := OverrideSubjectFor(overrides, "codex-cli", "o3", "cap production deploy", "")
+	testutil.RequireEqual(t, "production", s.Stage, "stage")
+	if got := ApplyTierOverrides(caution, overrides, s); got.Tier != RiskTierCritical {
+		t.Errorf("production stage: tier %s", got.Tier)
+	}
+	s = OverrideSubjectFor(overrides, "codex-cli", "o3", "cap staging deploy", "")
+	if got := ApplyTierOverrides(caution, overrides, s); got != caution {
+		t.Errorf("staging changed: %+v", got)
+	}
+	if s := OverrideSubjectFor(nil, "codex-cli", "o3", "cap production deploy", ""); s.Stage != "" {
+		t.Errorf("stage looked up without stage overrides: %q", s.Stage)
+	}
+}
+
+func TestCreateRequest_DeploymentContext(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	session := testutil.MakeSession(t, database)
+	engine := NewPatternEngine()
+
+	// Without the pack the deploy is not matched and carries no context.
+	creator := NewRequestCreator(database, nil, engine, nil)
+	result, err := creator.CreateRequest(context.Background(), CreateRequestOptions{
+		SessionID:     session.ID,
+		Command:       "cap production deploy",
+		Justification: Justification{Reason: "ship"},
+	})
+	testutil.RequireNoError(t, err, "create request without pack")
+	if !result.Skipped {
+		t.Fatalf("expected an unmatched command to be skipped, got %+v", result.Request)
+	}
+
+	if err := engine.EnablePack(DeployPack); err != nil {
+		t.Fatalf("EnablePack: %v", err)
+	}
+	result, err = creator.CreateRequest(context.Background(), CreateRequestOptions{
+		SessionID:     session.ID,
+		Command:       "cap production deploy",
+		Justification: Justification{Reason: "ship"},
+	})
+	testutil.RequireNoError(t, err, "create request")
+	testutil.RequireEqual(t, RiskTierDangerous, result.Request.RiskTier, "tier")
+
+	var found bool
+	for _, a := range result.Request.Attachments {
+		if a.Metadata["type"] == "deployment" {
+			found = true
+			testutil.RequireEqual(t, "cap: stage production, tasks deploy", a.Content, "deployment attachment")
+			testutil.RequireEqual(t, "production", a.Metadata["stage"], "stage metadata")
+		}
+	}
+	if !found {
+		t.Errorf("expected a deployment attachment, got %+v", result.Request.Attachments)
+	}
+}
@@ -0,0 +1,201 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// ErrEnvironmentDrift is returned when a request would execute from a
+// different directory, binary or pinned environment than it was created
+// with, and no human allowed the drift.
+var ErrEnvironmentDrift = errors.New("execution environment differs from the request's")
+
+// Drift is one difference between a request's pinned environment and the
+// one it is executed from.
+type Drift struct {
+	// Kind is what drifted: cwd, binary or env:NAME.
+	Kind     string `json:"kind"`
+	Recorded string `json:"recorded"`
+	Current  string `json:"current"`
+}
+
+func (d Drift) String() string {
+	return fmt.Sprintf("%s %q, now %q", d.Kind, d.Recorded, d.Current)
+}
+
+// PinEnvironment records the working directory of a request and the values
+// of the pinned variables in environ (KEY=VALUE entries), "" for unset ones.
+// A nil environ means the requester's environment is unknown and pins no
+// variables.
+func PinEnvironment(cwd string, environ, pinned []string) *db.RequestEnvironment {
+	e := &db.RequestEnvironment{Cwd: cwd, CwdReal: realDir(cwd)}
+	if environ != nil {
+		e.Env = make(map[string]string, len(pinned))
+		for _, name := range pinned {
+			e.Env[name] = lookupEnv(environ, name)
+		}
+	}
+	return e
+}
+
+// lookupEnv returns the value of name in environ; later entries win, as
+// they do for exec.
+func lookupEnv(environ []string, name string) string {
+	value := ""
+	for _, kv := range environ {
+		if k, v, ok := strings.Cut(kv, "="); ok && k == name {
+			value = v
+		}
+	}
+	return value
+}
+
+// realDir resolves symlinks in dir, returning "" when it cannot.
+func realDir(dir string) string {
+	if dir == "" {
+		return ""
+	}
+	real, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return ""
+	}
+	return real
+}
+
+// CheckDrift compares the environment a request was created with against
+// the one it would execute from: the working directory (swapped for
+// another, or a different directory in opts.Cwd), the executable the
+// command resolves to now, and the pinned variables in opts.Environ (by
+// default this process's environment, which the command inherits).
+// Requests created before pins were recorded only have their executable
+// checked. A pin that exists but cannot be read is an error, not a reason
+// to skip the check.
+func (e *Executor) CheckDrift(request *db.Request, opts ExecuteOptions) ([]Drift, error) {
+	environ := opts.Environ
+	if environ == nil {
+		environ = os.Environ()
+	}
+	var drifts []Drift
+
+	pinned, err := e.db.GetRequestEnvironment(request.ID)
+	switch {
+	case errors.Is(err, db.ErrRequestEnvironmentNotFound):
+		// Created before environments were pinned.
+	case err != nil:
+		return nil, fmt.Errorf("reading pinned environment: %w", err)
+	default:
+		want := pinned.CwdReal
+		if want == "" {
+			want = filepath.Clean(pinned.Cwd)
+		}
+		if now := realDir(request.Command.Cwd); pinned.CwdReal != "" && now != pinned.CwdReal {
+			drifts = append(drifts, Drift{Kind: "cwd", Recorded: pinned.CwdReal, Current: orMissing(now)})
+		} else if opts.Cwd != "" {
+			now := realDir(opts.Cwd)
+			if now == "" {
+				now = filepath.Clean(opts.Cwd)
+			}
+			if now != want {
+				drifts = append(drifts, Drift{Kind: "cwd", Recorded: want, Current: now})
+			}
+		}
+
+		names := make([]string, 0, len(pinned.Env))
+		for name := range pinned.Env {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if now := lookupEnv(environ, name); now != pinned.Env[name] {
+				drifts = append(drifts, Drift{Kind: "env:" + name, Recorded: pinned.Env[name], Current: now})
+			}
+		}
+	}
+
+	recorded, err := e.db.GetRequestBinary(request.ID)
+	switch {
+	case errors.Is(err, db.ErrBinaryNotFound):
+		// Nothing resolved on PATH, or created before binaries were pinned.
+	case err != nil:
+		return nil, fmt.Errorf("reading pinned binary: %w", err)
+	default:
+		now := ResolveBinary(request.Command.Raw, request.Command.Cwd, lookupEnv(environ, "PATH"), request.ProjectPath)
+		switch {
+		case now == nil:
+			drifts = append(drifts, Drift{Kind: "binary", Recorded: binaryLabel(recorded), Current: "(not found)"})
+		case binaryPath(now) != binaryPath(recorded),
+			recorded.SHA256 != "" && now.SHA256 != "" && now.SHA256 != recorded.SHA256:
+			drifts = append(drifts, Drift{Kind: "binary", Recorded: binaryLabel(recorded), Current: binaryLabel(now)})
+		}
+	}
+	return drifts, nil
+}
+
+// checkDrift refuses execution when CheckDrift finds differences, unless
+// opts allows the drift, and records each difference either way (best
+// effort). It also refuses execution when the pins cannot be read.
+func (e *Executor) checkDrift(request *db.Request, opts ExecuteOptions) error {
+	drifts, err := e.CheckDrift(request, opts)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrEnvironmentDrift, err)
+	}
+	if len(drifts) == 0 {
+		return nil
+	}
+	allowedBy := ""
+	if opts.AllowDrift {
+		allowedBy = opts.DriftAllowedBy
+		if allowedBy == "" {
+			allowedBy = "unknown"
+		}
+	}
+	notes := make([]string, 0, len(drifts))
+	for _, d := range drifts {
+		_ = e.db.RecordExecutionDrift(&db.ExecutionDrift{
+			RequestID: request.ID,
+			Kind:      d.Kind,
+			Recorded:  d.Recorded,
+			Current:   d.Current,
+			AllowedBy: allowedBy,
+		})
+		notes = append(notes, d.String())
+	}
+	if opts.AllowDrift {
+		return nil
+	}
+	return fmt.Errorf("%w: %s; a human can allow it with 'slb execute %s --allow-drift'",
+		ErrEnvironmentDrift, strings.Join(notes, "; "), request.ID)
+}
+
+// binaryPath is the file a resolved binary runs.
+func binaryPath(b *db.RequestBinary) string {
+	if b.RealPath != "" {
+		return b.RealPath
+	}
+	return b.Path
+}
+
+// binaryLabel describes a resolved binary by file and digest.
+func binaryLabel(b *db.RequestBinary) string {
+	if b.SHA256 == "" {
+		return binaryPath(b)
+	}
+	sum := b.SHA256
+	if len(sum) > 12 {
+		sum = sum[:12]
+	}
+	return binaryPath(b) + " sha256:" + sum
+}
+
+func orMissing(s string) string {
+	if s == "" {
+		return "(missing)"
+	}
+	return s
+}
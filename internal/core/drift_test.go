@@ -0,0 +1,196 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestPinEnvironment(t *testing.T) {
+	dir := t.TempDir()
+	environ := []string{"KUBECONFIG=/a", "PATH=/bin", "KUBECONFIG=/b"}
+
+	pin := PinEnvironment(dir, environ, []string{"KUBECONFIG", "AWS_PROFILE"})
+	if pin.Cwd != dir || pin.CwdReal == "" {
+		t.Errorf("cwd = %q, real %q", pin.Cwd, pin.CwdReal)
+	}
+	if len(pin.Env) != 2 || pin.Env["KUBECONFIG"] != "/b" || pin.Env["AWS_PROFILE"] != "" {
+		t.Errorf("env = %v", pin.Env)
+	}
+	if _, ok := pin.Env["PATH"]; ok {
+		t.Error("unpinned variable recorded")
+	}
+
+	if pin := PinEnvironment("/does/not/exist", nil, []string{"KUBECONFIG"}); pin.Env != nil || pin.CwdReal != "" {
+		t.Errorf("unknown environment = %+v", pin)
+	}
+}
+
+// driftFixture creates an approved request for a script in its own
+// directory on PATH, with KUBECONFIG pinned.
+func driftFixture(t *testing.T) (*db.DB, *db.Session, *db.Request, string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script on PATH")
+	}
+	binDir := t.TempDir()
+	tool := filepath.Join(binDir, "deploy-tool")
+	if err := os.WriteFile(tool, []byte("#!/bin/sh\nexit 0\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("KUBECONFIG", "/home/me/.kube/staging")
+
+	database := testutil.NewTestDB(t)
+	sess := testutil.MakeSession(t, database)
+	engine := &PatternEngine{}
+	if err := engine.AddPattern(RiskTierDangerous, `^deploy-tool\b`, "", "test"); err != nil {
+		t.Fatal(err)
+	}
+	config := DefaultRequestCreatorConfig()
+	config.AgentMailEnabled = false
+	config.PinnedEnv = []string{"KUBECONFIG"}
+	creator := NewRequestCreator(database, NewRateLimiter(database, RateLimitConfig{}), engine, config)
+
+	cwd := t.TempDir()
+	result, err := creator.CreateRequest(context.Background(), CreateRequestOptions{
+		SessionID:     sess.ID,
+		Command:       "deploy-tool --all",
+		Cwd:           cwd,
+		Environ:       os.Environ(),
+		Justification: Justification{Reason: "deploy"},
+	})
+	if err != nil || result.Skipped {
+		t.Fatalf("CreateRequest: %v %+v", err, result)
+	}
+	if err := database.UpdateRequestStatus(result.Request.ID, db.StatusApproved); err != nil {
+		t.Fatal(err)
+	}
+	return database, sess, result.Request, tool
+}
+
+func TestCheckDrift(t *testing.T) {
+	database, _, req, tool := driftFixture(t)
+	exec := NewExecutor(database, nil)
+
+	if drifts, _ := exec.CheckDrift(req, ExecuteOptions{Cwd: req.Command.Cwd}); len(drifts) != 0 {
+		t.Fatalf("unexpected drift: %v", drifts)
+	}
+
+	if drifts, _ := exec.CheckDrift(req, ExecuteOptions{Cwd: t.TempDir()}); len(drifts) != 1 || drifts[0].Kind != "cwd" {
+		t.Errorf("executing elsewhere: %v", drifts)
+	}
+
+	t.Setenv("KUBECONFIG", "/home/me/.kube/prod")
+	drifts, _ := exec.CheckDrift(req, ExecuteOptions{})
+	if len(drifts) != 1 || drifts[0].Kind != "env:KUBECONFIG" || drifts[0].Current != "/home/me/.kube/prod" {
+		t.Errorf("changed KUBECONFIG: %v", drifts)
+	}
+	t.Setenv("KUBECONFIG", "/home/me/.kube/staging")
+
+	if err := os.WriteFile(tool, []byte("#!/bin/sh\nrm -rf \"$HOME\"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	drifts, _ = exec.CheckDrift(req, ExecuteOptions{})
+	if len(drifts) != 1 || drifts[0].Kind != "binary" || !strings.Contains(drifts[0].Recorded, "sha256:") {
+		t.Errorf("swapped binary: %v", drifts)
+	}
+}
+
+// TestCheckDrift_UnreadablePin checks that a pin which exists but cannot be
+// read refuses execution instead of skipping the checks.
+func TestCheckDrift_UnreadablePin(t *testing.T) {
+	database, sess, req, _ := driftFixture(t)
+	if pin, err := database.GetRequestEnvironment(req.ID); err != nil || pin.Cwd != req.Command.Cwd {
+		t.Fatalf("creation should pin the environment: %+v, %v", pin, err)
+	}
+	if _, err := database.Exec(`UPDATE request_environments SET env_json = '{' WHERE request_id = ?`, req.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	exec := NewExecutor(database, nil)
+	if _, err := exec.CheckDrift(req, ExecuteOptions{}); err == nil {
+		t.Fatal("expected an error for an unreadable pin")
+	}
+	_, err := exec.ExecuteApprovedRequest(context.Background(), ExecuteOptions{
+		RequestID: req.ID,
+		SessionID: sess.ID,
+		LogDir:    t.TempDir(),
+	})
+	if !errors.Is(err, ErrEnvironmentDrift) {
+		t.Fatalf("execution with an unreadable pin: %v, want ErrEnvironmentDrift", err)
+	}
+}
+
+func TestCheckDrift_SwappedDirectory(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses symlinks")
+	}
+	database := testutil.NewTestDB(t)
+	sess := testutil.MakeSession(t, database)
+	base := t.TempDir()
+	real1, real2 := filepath.Join(base, "one"), filepath.Join(base, "two")
+	for _, d := range []string{real1, real2} {
+		if err := os.Mkdir(d, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	link := filepath.Join(base, "work")
+	if err := os.Symlink(real1, link); err != nil {
+		t.Fatal(err)
+	}
+	req := testutil.MakeRequest(t, database, sess, testutil.WithCommand("rm -rf build", link, false))
+	pin := PinEnvironment(link, nil, nil)
+	pin.RequestID = req.ID
+	if err := database.SetRequestEnvironment(pin); err != nil {
+		t.Fatal(err)
+	}
+
+	exec := NewExecutor(database, nil)
+	if drifts, _ := exec.CheckDrift(req, ExecuteOptions{}); len(drifts) != 0 {
+		t.Fatalf("unexpected drift: %v", drifts)
+	}
+	if err := os.Remove(link); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(real2, link); err != nil {
+		t.Fatal(err)
+	}
+	drifts, _ := exec.CheckDrift(req, ExecuteOptions{})
+	if len(drifts) != 1 || drifts[0].Kind != "cwd" || !strings.HasSuffix(drifts[0].Current, "two") {
+		t.Errorf("swapped directory: %v", drifts)
+	}
+}
+
+func TestExecuteApprovedRequest_Drift(t *testing.T) {
+	database, sess, req, _ := driftFixture(t)
+	execute := func(opts ExecuteOptions) error {
+		opts.RequestID, opts.SessionID, opts.LogDir, opts.SuppressOutput = req.ID, sess.ID, t.TempDir(), true
+		_, err := NewExecutor(database, nil).ExecuteApprovedRequest(context.Background(), opts)
+		return err
+	}
+
+	t.Setenv("KUBECONFIG", "/home/me/.kube/prod")
+	err := execute(ExecuteOptions{})
+	if !errors.Is(err, ErrEnvironmentDrift) || !strings.Contains(err.Error(), "--allow-drift") {
+		t.Fatalf("expected ErrEnvironmentDrift, got %v", err)
+	}
+	if err := execute(ExecuteOptions{AllowDrift: true, DriftAllowedBy: "alice"}); err != nil {
+		t.Fatalf("execute with allowed drift: %v", err)
+	}
+
+	drifts, err := database.ListExecutionDrifts(req.ID)
+	if err != nil || len(drifts) != 2 {
+		t.Fatalf("drifts = %+v, %v", drifts, err)
+	}
+	if drifts[0].AllowedBy != "" || drifts[1].AllowedBy != "alice" || drifts[1].Kind != "env:KUBECONFIG" {
+		t.Errorf("drifts = %+v %+v", drifts[0], drifts[1])
+	}
+}
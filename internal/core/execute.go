@@ -9,6 +9,8 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/Dicklesworthstone/slb/internal/clock"
+	"github.com/Dicklesworthstone/slb/internal/core/statemachine"
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/Dicklesworthstone/slb/internal/integrations"
 )
@@ -42,11 +44,26 @@ type ExecuteOptions struct {
 	// SuppressOutput prevents streaming command output to stdout (still logged to file).
 	// Useful for machine-readable output formats (e.g., --output json).
 	SuppressOutput bool
+	// Streams, when set, receives the command's stdout and stderr in place
+	// of this process's stdout, even with SuppressOutput.
+	Streams *OutputStreams
 
 	// CaptureRollback enables rollback state capture for supported destructive commands.
 	CaptureRollback bool
 	// MaxRollbackSizeMB limits filesystem rollback capture (0 uses config default).
 	MaxRollbackSizeMB int
+
+	// Cwd is the directory execution was started from; it must be the
+	// request's working directory. Empty skips that check.
+	Cwd string
+	// Environ is the environment the command runs with, checked against
+	// the request's pinned variables. Nil means this process's environment.
+	Environ []string
+	// AllowDrift runs the command even though its directory, binary or
+	// pinned environment changed since the request was created. Only a
+	// human may set it; DriftAllowedBy names them for the audit record.
+	AllowDrift     bool
+	DriftAllowedBy string
 }
 
 // ExecutionResult holds the result of command execution.
@@ -63,6 +80,9 @@ type ExecutionResult struct {
 	Output string
 	// TimedOut indicates if the command timed out.
 	TimedOut bool
+	// SecretsFound lists the kinds of secret found in Output, which has
+	// them redacted.
+	SecretsFound []string
 	// Error contains any execution error.
 	Error error
 }
@@ -72,6 +92,11 @@ type Executor struct {
 	db            *db.DB
 	patternEngine *PatternEngine
 	notifier      integrations.RequestNotifier
+	windows       *ExecutionWindowPolicy
+	clock         clock.Clock
+	machine       *statemachine.Machine
+	tierOverrides []TierOverride
+	isolation     *Isolation
 }
 
 // NewExecutor creates a new executor.
@@ -83,7 +108,35 @@ func NewExecutor(database *db.DB, patternEngine *PatternEngine) *Executor {
 		db:            database,
 		patternEngine: patternEngine,
 		notifier:      integrations.NoopNotifier{},
+		clock:         dbClock(database),
+		machine:       statemachine.New(database),
+	}
+}
+
+// WithClock sets the clock used for approval expiry and execution windows.
+// By default the database clock is used.
+func (e *Executor) WithClock(c clock.Clock) *Executor {
+	e.clock = clock.OrReal(c)
+	e.machine.WithClock(e.clock)
+	return e
+}
+
+// WithStateMachine sets the machine that applies execution status changes,
+// so callers can listen for its transition events. Its clock is replaced by
+// the executor's.
+func (e *Executor) WithStateMachine(m *statemachine.Machine) *Executor {
+	if m != nil {
+		e.machine = m.WithClock(e.clock)
+	}
+	return e
+}
+
+// dbClock returns the clock of database, or the system clock without one.
+func dbClock(database *db.DB) clock.Clock {
+	if database == nil {
+		return clock.Real
 	}
+	return clock.Func(database.Now)
 }
 
 // WithNotifier sets the notifier used for execution events.
@@ -94,6 +147,37 @@ func (e *Executor) WithNotifier(n integrations.RequestNotifier) *Executor {
 	return e
 }
 
+// WithExecutionWindows sets the quiet-hours policy enforced before execution.
+func (e *Executor) WithExecutionWindows(p *ExecutionWindowPolicy) *Executor {
+	e.windows = p
+	return e
+}
+
+// WithIsolation runs approved commands under iso; nil runs them directly
+// as the invoking user.
+func (e *Executor) WithIsolation(iso *Isolation) *Executor {
+	e.isolation = iso
+	return e
+}
+
+// checkExecutionWindow returns an error when the request falls inside a
+// restricted window and no human override has been recorded for it.
+func (e *Executor) checkExecutionWindow(request *db.Request) error {
+	restricted, reason := e.windows.Restricted(request.RiskTier, e.clock.Now())
+	if !restricted {
+		return nil
+	}
+	override, err := e.db.GetExecutionOverride(request.ID)
+	if err != nil && !errors.Is(err, db.ErrExecutionOverrideNotFound) {
+		return fmt.Errorf("checking execution override: %w", err)
+	}
+	if override != nil && override.CommandHash == request.Command.Hash {
+		return nil
+	}
+	return fmt.Errorf("%w: %s; a human can allow it with 'slb override-window %s --reason ...'",
+		ErrExecutionWindowClosed, reason, request.ID)
+}
+
 // ExecuteApprovedRequest validates and executes an approved request.
 // This runs the command in the CALLER'S shell environment (client-side execution).
 func (e *Executor) ExecuteApprovedRequest(ctx context.Context, opts ExecuteOptions) (*ExecutionResult, error) {
@@ -129,33 +213,25 @@ func (e *Executor) ExecuteApprovedRequest(ctx context.Context, opts ExecuteOptio
 		return nil, fmt.Errorf("getting session: %w", err)
 	}
 
-	// Gate 1: Request must be approved
-	if request.Status == db.StatusExecuting {
-		return nil, ErrAlreadyExecuting
-	}
-	if request.Status == db.StatusExecuted || request.Status == db.StatusExecutionFailed {
-		return nil, ErrAlreadyExecuted
-	}
-	if request.Status != db.StatusApproved {
-		return nil, fmt.Errorf("%w: status is %s", ErrRequestNotApproved, request.Status)
-	}
-
-	// Gate 2: Approval must not be expired
-	if request.ApprovalExpiresAt != nil && time.Now().After(*request.ApprovalExpiresAt) {
-		return nil, ErrApprovalExpired
-	}
-
-	// Gate 3: Command hash must match (prevents mutation)
-	expectedHash := db.ComputeCommandHash(request.Command)
-	if expectedHash != request.Command.Hash {
-		return nil, fmt.Errorf("%w: stored=%s computed=%s", ErrCommandHashMismatch, request.Command.Hash, expectedHash)
+	script, err := e.checkGates(request, opts)
+	if err != nil {
+		return nil, err
 	}
 
-	// Gate 4: Current pattern policy doesn't require higher tier
-	classification := e.patternEngine.ClassifyCommand(request.Command.Raw, request.Command.Cwd)
-	if tierHigher(classification.Tier, request.RiskTier) {
-		return nil, fmt.Errorf("%w: approved as %s but now classified as %s",
-			ErrTierEscalated, request.RiskTier, classification.Tier)
+	// Gate 3c: Scripts the command downloads into a shell must still be
+	// the ones pinned for review. They are fetched once here and the
+	// command runs the saved copies, never downloading them again.
+	var staged *stagedRemoteScripts
+	if len(DetectRemoteScripts(request.Command.Raw)) > 0 {
+		pinned, err := e.db.ListRequestRemoteScripts(request.ID)
+		if err != nil {
+			return nil, fmt.Errorf("getting remote scripts: %w", err)
+		}
+		staged, err = stageRemoteScripts(ctx, request.Command.Raw, pinned, e.isolation != nil)
+		if err != nil {
+			return nil, err
+		}
+		defer staged.cleanup()
 	}
 
 	// Preflight: create log file and capture rollback state before locking EXECUTING.
@@ -179,8 +255,12 @@ func (e *Executor) ExecuteApprovedRequest(ctx context.Context, opts ExecuteOptio
 		}
 	}
 
-	// Gate 5: First executor wins - transition to EXECUTING
-	if err := e.db.UpdateRequestStatus(opts.RequestID, db.StatusExecuting); err != nil {
+	// Gate 6: First executor wins - transition to EXECUTING
+	if _, err := e.machine.Commit(opts.RequestID, db.StatusExecuting, e.executionInput(session)); err != nil {
+		// The approval can go stale while rollback state is captured
+		if errors.Is(err, statemachine.ErrApprovalExpired) {
+			return nil, ErrApprovalExpired
+		}
 		// If another executor already started, we'll get an error
 		if errors.Is(err, db.ErrInvalidTransition) {
 			return nil, ErrAlreadyExecuting
@@ -189,7 +269,7 @@ func (e *Executor) ExecuteApprovedRequest(ctx context.Context, opts ExecuteOptio
 	}
 
 	// Record executor info
-	now := time.Now().UTC()
+	now := e.clock.Now().UTC()
 	exec := &db.Execution{
 		ExecutedAt:          &now,
 		ExecutedBySessionID: opts.SessionID,
@@ -213,21 +293,29 @@ func (e *Executor) ExecuteApprovedRequest(ctx context.Context, opts ExecuteOptio
 	execCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
 	defer cancel()
 
-	var streamWriter *os.File
-	if !opts.SuppressOutput {
-		streamWriter = os.Stdout
+	var streams OutputStreams
+	switch {
+	case opts.Streams != nil:
+		streams = *opts.Streams
+	case !opts.SuppressOutput:
+		streams = streamTo(os.Stdout)
+	}
+	var cmdResult *CommandResult
+	if script != nil {
+		cmdResult, err = runScript(execCtx, &request.Command, script, staged, e.isolation, logPath, streams)
+	} else {
+		cmdResult, err = runCommand(execCtx, staged.spec(&request.Command), e.isolation, staged.paths(), logPath, streams)
 	}
-	cmdResult, err := RunCommand(execCtx, &request.Command, logPath, streamWriter)
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
 			result.TimedOut = true
 			result.Error = ErrExecutionTimeout
-			if statusErr := e.db.UpdateRequestStatus(opts.RequestID, db.StatusTimedOut); statusErr != nil {
+			if statusErr := e.setStatus(opts.RequestID, db.StatusTimedOut, session); statusErr != nil {
 				fmt.Fprintf(os.Stderr, "warning: failed to update status to timed_out: %v\n", statusErr)
 			}
 		} else {
 			result.Error = err
-			if statusErr := e.db.UpdateRequestStatus(opts.RequestID, db.StatusExecutionFailed); statusErr != nil {
+			if statusErr := e.setStatus(opts.RequestID, db.StatusExecutionFailed, session); statusErr != nil {
 				fmt.Fprintf(os.Stderr, "warning: failed to update status to execution_failed: %v\n", statusErr)
 			}
 		}
@@ -238,11 +326,11 @@ func (e *Executor) ExecuteApprovedRequest(ctx context.Context, opts ExecuteOptio
 
 		// Determine final status based on exit code
 		if cmdResult.ExitCode == 0 {
-			if statusErr := e.db.UpdateRequestStatus(opts.RequestID, db.StatusExecuted); statusErr != nil {
+			if statusErr := e.setStatus(opts.RequestID, db.StatusExecuted, session); statusErr != nil {
 				fmt.Fprintf(os.Stderr, "warning: failed to update status to executed: %v\n", statusErr)
 			}
 		} else {
-			if statusErr := e.db.UpdateRequestStatus(opts.RequestID, db.StatusExecutionFailed); statusErr != nil {
+			if statusErr := e.setStatus(opts.RequestID, db.StatusExecutionFailed, session); statusErr != nil {
 				fmt.Fprintf(os.Stderr, "warning: failed to update status to execution_failed: %v\n", statusErr)
 			}
 		}
@@ -255,6 +343,7 @@ func (e *Executor) ExecuteApprovedRequest(ctx context.Context, opts ExecuteOptio
 		durationMs := result.Duration.Milliseconds()
 		exec.ExitCode = &exitCode
 		exec.DurationMs = &durationMs
+		e.scanOutput(result, exec)
 	}
 	if execErr := e.db.UpdateRequestExecution(opts.RequestID, exec); execErr != nil {
 		fmt.Fprintf(os.Stderr, "warning: failed to update execution details: %v\n", execErr)
@@ -266,6 +355,70 @@ func (e *Executor) ExecuteApprovedRequest(ctx context.Context, opts ExecuteOptio
 	return result, result.Error
 }
 
+// CheckGates applies the gates an approved request must pass before it
+// runs: its status and approval expiry, the command and script hashes, the
+// current policy, execution windows and environment drift. Scripts the
+// command downloads are checked when they are fetched to run.
+func (e *Executor) CheckGates(request *db.Request, opts ExecuteOptions) error {
+	_, err := e.checkGates(request, opts)
+	return err
+}
+
+// checkGates is CheckGates, also returning the request's recorded script
+// (nil if it has none).
+func (e *Executor) checkGates(request *db.Request, opts ExecuteOptions) (*db.RequestScript, error) {
+	// Gate 1: Request must be approved
+	if request.Status == db.StatusExecuting {
+		return nil, ErrAlreadyExecuting
+	}
+	if request.Status == db.StatusExecuted || request.Status == db.StatusExecutionFailed {
+		return nil, ErrAlreadyExecuted
+	}
+	if request.Status != db.StatusApproved {
+		return nil, fmt.Errorf("%w: status is %s", ErrRequestNotApproved, request.Status)
+	}
+
+	// Gate 2: Approval must not be expired
+	if request.ApprovalExpiresAt != nil && e.clock.Now().After(*request.ApprovalExpiresAt) {
+		return nil, ErrApprovalExpired
+	}
+
+	// Gate 3: Command hash must match (prevents mutation)
+	expectedHash := db.ComputeCommandHash(request.Command)
+	if expectedHash != request.Command.Hash {
+		return nil, fmt.Errorf("%w: stored=%s computed=%s", ErrCommandHashMismatch, request.Command.Hash, expectedHash)
+	}
+
+	// Gate 3b: A script must still match the hash recorded with it
+	script, err := e.db.GetRequestScript(request.ID)
+	switch {
+	case errors.Is(err, db.ErrRequestScriptNotFound):
+		script = nil
+	case err != nil:
+		return nil, fmt.Errorf("getting request script: %w", err)
+	case ScriptSHA256(request.Command.Raw) != script.SHA256:
+		return nil, fmt.Errorf("%w: recorded %s", ErrScriptHashMismatch, script.SHA256)
+	}
+
+	// Gate 4: Current pattern policy doesn't require higher tier
+	if err := e.revalidate(request); err != nil {
+		return nil, err
+	}
+
+	// Gate 5: Restricted execution windows (quiet hours) need a human override
+	if err := e.checkExecutionWindow(request); err != nil {
+		return nil, err
+	}
+
+	// Gate 5b: The directory, binary and pinned environment must be the
+	// ones the request was created with, unless a human allowed the drift
+	if err := e.checkDrift(request, opts); err != nil {
+		return nil, err
+	}
+
+	return script, nil
+}
+
 // createLogFile creates the log file for command output.
 func (e *Executor) createLogFile(logDir, requestID string) (string, error) {
 	// Ensure log directory exists
@@ -301,6 +454,18 @@ func tierHigher(tier1, tier2 db.RiskTier) bool {
 	return tierOrder[tier1] > tierOrder[tier2]
 }
 
+// executionInput is the statemachine input for status changes the executor
+// makes on behalf of the executing session.
+func (e *Executor) executionInput(session *db.Session) statemachine.Input {
+	return statemachine.Input{Authority: statemachine.AuthoritySystem, Actor: session.AgentName}
+}
+
+// setStatus records how execution ended.
+func (e *Executor) setStatus(requestID string, to db.RequestStatus, session *db.Session) error {
+	_, err := e.machine.Commit(requestID, to, e.executionInput(session))
+	return err
+}
+
 // CanExecute checks if a request can be executed and returns the reason if not.
 func (e *Executor) CanExecute(requestID string) (bool, string) {
 	request, err := e.db.GetRequest(requestID)
@@ -317,7 +482,7 @@ func (e *Executor) CanExecute(requestID string) (bool, string) {
 	if request.Status != db.StatusApproved {
 		return false, fmt.Sprintf("request is not approved (status: %s)", request.Status)
 	}
-	if request.ApprovalExpiresAt != nil && time.Now().After(*request.ApprovalExpiresAt) {
+	if request.ApprovalExpiresAt != nil && e.clock.Now().After(*request.ApprovalExpiresAt) {
 		return false, "approval has expired"
 	}
 
@@ -326,10 +491,14 @@ func (e *Executor) CanExecute(requestID string) (bool, string) {
 		return false, "command hash mismatch (command may have been modified)"
 	}
 
-	classification := e.patternEngine.ClassifyCommand(request.Command.Raw, request.Command.Cwd)
+	classification := e.classifyCurrent(request)
 	if tierHigher(classification.Tier, request.RiskTier) {
 		return false, fmt.Sprintf("policy escalation: command now classified as %s", classification.Tier)
 	}
 
+	if err := e.checkExecutionWindow(request); err != nil {
+		return false, err.Error()
+	}
+
 	return true, ""
 }
@@ -0,0 +1,151 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+// fuzzSeeds are inputs that stress the normalizer beyond the classification
+// table: unbalanced quotes, substitutions, wrappers, xargs and Windows shells.
+var fuzzSeeds = []string{
+	"",
+	"   ",
+	`echo "unterminated`,
+	`rm -rf "unterminated`,
+	`sudo rm -rf /* "unterminated`,
+	"bash -c 'rm -rf /tmp/x'",
+	`sh -c "git push --force"`,
+	"echo $(rm -rf /)",
+	"echo `rm -rf /`",
+	"(cd /tmp && rm -rf .)",
+	"find . -name '*.tmp' | xargs rm -rf",
+	"xargs",
+	"xargs -0 -n1 kubectl delete pod",
+	"FOO=bar BAZ=qux env sudo nice -n 10 rm -rf /var",
+	"cd /tmp; rm -rf . && echo done || true &",
+	"git commit -m 'a; b && c'",
+	"powershell -EncodedCommand UgBlAG0AbwB2AGUALQBJAHQAZQBtAA==",
+	"powershell -EncodedCommand !!!",
+	`cmd /c "del /s /q C:\temp"`,
+	"rm -rf (ls)",
+	"rm -rf {a,b}",
+	"DROP TABLE users; --",
+	"\x00\xff\xfe",
+	strings.Repeat("(", 64),
+	strings.Repeat("sudo ", 32) + "rm -rf /",
+}
+
+func addFuzzCorpus(f *testing.F) {
+	for _, s := range fuzzSeeds {
+		f.Add(s)
+	}
+	for _, tc := range classifyCommandCases {
+		f.Add(tc.cmd)
+	}
+}
+
+func FuzzNormalizeCommand(f *testing.F) {
+	addFuzzCorpus(f)
+
+	f.Fuzz(func(t *testing.T, cmd string) {
+		n := NormalizeCommand(cmd)
+		if n.Original != cmd {
+			t.Fatalf("Original = %q, want %q", n.Original, cmd)
+		}
+		for i, seg := range n.Segments {
+			if seg == "" {
+				t.Fatalf("segment %d is empty: %#v", i, n)
+			}
+		}
+		if len(n.Segments) > 0 && n.Primary != n.Segments[0] {
+			t.Fatalf("Primary = %q, want first segment %q", n.Primary, n.Segments[0])
+		}
+		if len(n.Segments) == 0 && n.Primary != "" {
+			t.Fatalf("Primary = %q with no segments", n.Primary)
+		}
+		if strings.TrimSpace(cmd) == "" && (len(n.Segments) > 0 || n.ParseError) {
+			t.Fatalf("blank command produced %#v", n)
+		}
+	})
+}
+
+func FuzzExtractXargsCommand(f *testing.F) {
+	addFuzzCorpus(f)
+
+	f.Fuzz(func(t *testing.T, seg string) {
+		got := ExtractXargsCommand(seg)
+		if got == "" {
+			return
+		}
+		if !strings.Contains(seg, "xargs") {
+			t.Fatalf("ExtractXargsCommand(%q) = %q without xargs", seg, got)
+		}
+		if got != strings.TrimSpace(got) {
+			t.Fatalf("ExtractXargsCommand(%q) = %q is not trimmed", seg, got)
+		}
+		if !strings.HasSuffix(strings.TrimSpace(seg), got) {
+			t.Fatalf("ExtractXargsCommand(%q) = %q is not the tail of the segment", seg, got)
+		}
+	})
+}
+
+func FuzzClassifyCommand(f *testing.F) {
+	addFuzzCorpus(f)
+	engine := NewPatternEngine()
+
+	f.Fuzz(func(t *testing.T, cmd string) {
+		res := engine.ClassifyCommand(cmd, "")
+
+		rank, ok := fuzzTierRank(res.Tier)
+		if !ok {
+			t.Fatalf("ClassifyCommand(%q) tier = %q", cmd, res.Tier)
+		}
+		if res.Tier != "" && res.MinApprovals != tierApprovals(res.Tier) {
+			t.Fatalf("ClassifyCommand(%q) = %s with %d approvals", cmd, res.Tier, res.MinApprovals)
+		}
+		if res.IsSafe && res.NeedsApproval {
+			t.Fatalf("ClassifyCommand(%q) is both safe and needs approval", cmd)
+		}
+
+		again := engine.ClassifyCommand(cmd, "")
+		if again.Tier != res.Tier || again.ParseError != res.ParseError {
+			t.Fatalf("ClassifyCommand(%q) unstable: %s/%v then %s/%v", cmd, res.Tier, res.ParseError, again.Tier, again.ParseError)
+		}
+
+		if !res.ParseError {
+			return
+		}
+		// A parse error never makes a command look safer: the tier needs
+		// approval and is at least what the same segments get without it.
+		if !res.NeedsApproval || res.IsSafe || res.Tier == "" || res.Tier == RiskTier(RiskSafe) {
+			t.Fatalf("ClassifyCommand(%q) with parse error = %s (needs approval %v)", cmd, res.Tier, res.NeedsApproval)
+		}
+		n := NormalizeCommand(cmd)
+		n.ParseError = false
+		engine.mu.RLock()
+		base := engine.classifyNormalized(cmd, n, "")
+		engine.mu.RUnlock()
+		baseRank, _ := fuzzTierRank(base.Tier)
+		if rank < baseRank {
+			t.Fatalf("ClassifyCommand(%q) with parse error = %s, below %s without it", cmd, res.Tier, base.Tier)
+		}
+	})
+}
+
+// fuzzTierRank orders tiers from no match to critical.
+func fuzzTierRank(tier RiskTier) (int, bool) {
+	switch tier {
+	case "":
+		return 0, true
+	case RiskTier(RiskSafe):
+		return 1, true
+	case RiskTierCaution:
+		return 2, true
+	case RiskTierDangerous:
+		return 3, true
+	case RiskTierCritical:
+		return 4, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,307 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// Git history-rewrite kinds reported by DetectGitRewrite.
+const (
+	GitRewriteRebaseInteractive = "rebase_interactive"
+	GitRewriteFilterBranch      = "filter_branch"
+	GitRewriteFilterRepo        = "filter_repo"
+	GitRewritePushMirror        = "push_mirror"
+	GitRewriteReflogExpire      = "reflog_expire"
+)
+
+// gitRewriteTimeout bounds the git queries made while analyzing a request.
+const gitRewriteTimeout = 5 * time.Second
+
+// GitRewrite describes a git command that rewrites or discards history,
+// with the repository state a reviewer needs to judge it.
+type GitRewrite struct {
+	// Kind is one of the GitRewrite* constants.
+	Kind string `json:"kind"`
+	// Command is the git command, from a compound command if need be.
+	Command string `json:"command"`
+	// Dir is the repository directory the command runs in.
+	Dir string `json:"dir,omitempty"`
+
+	// Branch is the checked-out branch, and Upstream its tracking branch.
+	Branch   string `json:"branch,omitempty"`
+	Upstream string `json:"upstream,omitempty"`
+	// Ahead and Behind count commits relative to Upstream.
+	Ahead  int `json:"ahead,omitempty"`
+	Behind int `json:"behind,omitempty"`
+
+	// Base is what an interactive rebase replays onto ("--root" for all).
+	Base string `json:"base,omitempty"`
+	// Rewritten counts the commits that would get new ids, and Published
+	// how many of those are already on Upstream.
+	Rewritten int `json:"rewritten,omitempty"`
+	Published int `json:"published,omitempty"`
+
+	// Remote and RemoteURL are the target of a mirror push, and
+	// DeletedBranches the remote branches it would delete because they
+	// have no local counterpart.
+	Remote          string   `json:"remote,omitempty"`
+	RemoteURL       string   `json:"remote_url,omitempty"`
+	DeletedBranches []string `json:"deleted_branches,omitempty"`
+
+	// ReflogEntries counts the HEAD reflog entries an expiry may drop.
+	ReflogEntries int `json:"reflog_entries,omitempty"`
+}
+
+// gitRewriteInvocation is a history-rewriting git command split into its
+// parts.
+type gitRewriteInvocation struct {
+	kind    string
+	command string
+	dir     string
+	args    []string
+}
+
+// DetectGitRewrite reports which kind of history rewrite command performs,
+// looking at each segment of a compound command. It returns "" for other
+// commands.
+func DetectGitRewrite(command string) string {
+	if inv := detectGitRewrite(command, ""); inv != nil {
+		return inv.kind
+	}
+	return ""
+}
+
+func detectGitRewrite(command, cwd string) *gitRewriteInvocation {
+	for _, seg := range NormalizeCommand(command).Segments {
+		tokens := parseShellTokens(seg)
+		if len(tokens) < 2 || tokens[0] != "git" {
+			continue
+		}
+		sub, args, dir := splitGitCommand(tokens[1:], cwd)
+		inv := &gitRewriteInvocation{command: seg, dir: dir, args: args}
+		switch sub {
+		case "rebase":
+			if hasFlag(args, "-i") || hasFlag(args, "--interactive") || hasFlag(args, "--root") {
+				inv.kind = GitRewriteRebaseInteractive
+			}
+		case "filter-branch":
+			inv.kind = GitRewriteFilterBranch
+		case "filter-repo":
+			inv.kind = GitRewriteFilterRepo
+		case "push":
+			if hasFlag(args, "--mirror") {
+				inv.kind = GitRewritePushMirror
+			}
+		case "reflog":
+			if len(args) > 0 && (args[0] == "expire" || args[0] == "delete") {
+				inv.kind = GitRewriteReflogExpire
+			}
+		}
+		if inv.kind != "" {
+			return inv
+		}
+	}
+	return nil
+}
+
+// splitGitCommand skips git's global options and returns the subcommand,
+// its arguments and the directory set by -C (relative to cwd).
+func splitGitCommand(tokens []string, cwd string) (sub string, args []string, dir string) {
+	dir = cwd
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch {
+		case tok == "-C" && i+1 < len(tokens):
+			i++
+			if filepath.IsAbs(tokens[i]) || dir == "" {
+				dir = tokens[i]
+			} else {
+				dir = filepath.Join(dir, tokens[i])
+			}
+		case (tok == "-c" || tok == "--git-dir" || tok == "--work-tree" || tok == "--namespace") && i+1 < len(tokens):
+			i++
+		case strings.HasPrefix(tok, "-"):
+		default:
+			return tok, tokens[i+1:], dir
+		}
+	}
+	return "", nil, dir
+}
+
+// AnalyzeGitRewrite detects a history-rewriting git command and gathers
+// the branch, upstream and commit counts it affects by querying the
+// repository at cwd. It returns nil for other commands. Queries that fail,
+// e.g. outside a repository, leave their fields empty.
+func AnalyzeGitRewrite(command, cwd string) *GitRewrite {
+	inv := detectGitRewrite(command, cwd)
+	if inv == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), gitRewriteTimeout)
+	defer cancel()
+	git := func(args ...string) string {
+		out, err := runCmdString(ctx, inv.dir, "git", args...)
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(out)
+	}
+	count := func(args ...string) int {
+		n, _ := strconv.Atoi(git(append([]string{"rev-list", "--count"}, args...)...))
+		return n
+	}
+
+	r := &GitRewrite{Kind: inv.kind, Command: inv.command, Dir: inv.dir}
+	if branch := git("symbolic-ref", "--short", "-q", "HEAD"); branch != "" {
+		r.Branch = branch
+		r.Upstream = git("rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{u}")
+	}
+	if r.Upstream != "" {
+		if fields := strings.Fields(git("rev-list", "--left-right", "--count", "@{u}...HEAD")); len(fields) == 2 {
+			r.Behind, _ = strconv.Atoi(fields[0])
+			r.Ahead, _ = strconv.Atoi(fields[1])
+		}
+	}
+
+	switch inv.kind {
+	case GitRewriteRebaseInteractive:
+		r.Base = rebaseBase(inv.args, r.Upstream)
+		rng := "HEAD"
+		if r.Base != "--root" {
+			rng = r.Base + "..HEAD"
+		}
+		if r.Base != "" {
+			r.Rewritten = count(rng)
+			if r.Upstream != "" {
+				r.Published = r.Rewritten - count(rng, "^"+r.Upstream)
+			}
+		}
+	case GitRewriteFilterBranch, GitRewriteFilterRepo:
+		r.Rewritten = count("--all")
+		if r.Upstream != "" {
+			r.Published = count("--remotes")
+		}
+	case GitRewritePushMirror:
+		r.Remote = "origin"
+		for _, a := range inv.args {
+			if !strings.HasPrefix(a, "-") {
+				r.Remote = a
+				break
+			}
+		}
+		r.RemoteURL = git("remote", "get-url", r.Remote)
+		r.DeletedBranches = mirrorDeletions(
+			git("for-each-ref", "--format=%(refname:strip=3)", "refs/remotes/"+r.Remote),
+			git("for-each-ref", "--format=%(refname:strip=2)", "refs/heads"))
+	case GitRewriteReflogExpire:
+		if out := git("reflog", "show", "--format=%h", "HEAD"); out != "" {
+			r.ReflogEntries = len(strings.Split(out, "\n"))
+		}
+	}
+	return r
+}
+
+// rebaseBase returns the commit whose descendants an interactive rebase
+// rewrites: its <upstream> argument, else the branch's upstream. The --onto
+// target only says where they land, so it is skipped.
+func rebaseBase(args []string, upstream string) string {
+	if hasFlag(args, "--root") {
+		return "--root"
+	}
+	for i, a := range args {
+		if strings.HasPrefix(a, "-") || (i > 0 && args[i-1] == "--onto") {
+			continue
+		}
+		return a
+	}
+	return upstream
+}
+
+// mirrorDeletions returns the remote-tracking branches with no local
+// branch of the same name; a mirror push deletes them on the remote.
+func mirrorDeletions(remote, local string) []string {
+	have := map[string]bool{}
+	for _, b := range strings.Fields(local) {
+		have[b] = true
+	}
+	var out []string
+	for _, b := range strings.Fields(remote) {
+		if b != "HEAD" && !have[b] {
+			out = append(out, b)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Summary describes the rewrite in a few lines for reviewers.
+func (r *GitRewrite) Summary() string {
+	var lines []string
+	switch r.Kind {
+	case GitRewriteRebaseInteractive:
+		lines = append(lines, "History rewrite: interactive rebase onto "+orDefault(r.Base, "(unknown base)"))
+	case GitRewriteFilterBranch, GitRewriteFilterRepo:
+		lines = append(lines, "History rewrite: every commit on every branch gets a new id")
+	case GitRewritePushMirror:
+		target := r.Remote
+		if r.RemoteURL != "" {
+			target += " (" + r.RemoteURL + ")"
+		}
+		lines = append(lines, "Mirror push: all remote refs on "+target+" are overwritten to match this repository")
+		if len(r.DeletedBranches) > 0 {
+			lines = append(lines, fmt.Sprintf("Deletes %d remote branch(es) with no local copy: %s",
+				len(r.DeletedBranches), strings.Join(r.DeletedBranches, ", ")))
+		}
+	case GitRewriteReflogExpire:
+		line := "Reflog expiry: unreachable commits can no longer be recovered after gc"
+		if r.ReflogEntries > 0 {
+			line += fmt.Sprintf(" (HEAD reflog has %d entries)", r.ReflogEntries)
+		}
+		lines = append(lines, line)
+	}
+
+	if r.Branch != "" {
+		line := "Branch: " + r.Branch
+		if r.Upstream != "" {
+			line += fmt.Sprintf(" (upstream %s, %d ahead, %d behind)", r.Upstream, r.Ahead, r.Behind)
+		} else {
+			line += " (no upstream)"
+		}
+		lines = append(lines, line)
+	}
+	if r.Rewritten > 0 {
+		line := fmt.Sprintf("Rewrites %d commit(s)", r.Rewritten)
+		if r.Published > 0 {
+			line += fmt.Sprintf(", %d already pushed; others' clones will diverge", r.Published)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+// Attachment returns the analysis as a request attachment.
+func (r *GitRewrite) Attachment() *db.Attachment {
+	return &db.Attachment{
+		Type:    db.AttachmentTypeContext,
+		Content: r.Summary(),
+		Metadata: map[string]any{
+			"type":        "git_rewrite",
+			"git_rewrite": r,
+		},
+	}
+}
@@ -0,0 +1,185 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestDetectGitRewrite(t *testing.T) {
+	tests := []struct {
+		cmd  string
+		want string
+	}{
+		{"git rebase -i HEAD~3", GitRewriteRebaseInteractive},
+		{"git rebase --interactive origin/main", GitRewriteRebaseInteractive},
+		{"git rebase --root", GitRewriteRebaseInteractive},
+		{"git -C repo rebase -i main", GitRewriteRebaseInteractive},
+		{"git rebase main", ""},
+		{"git filter-branch --tree-filter 'rm -f secrets.txt' HEAD", GitRewriteFilterBranch},
+		{"git filter-repo --path secrets.txt --invert-paths", GitRewriteFilterRepo},
+		{"git push --mirror backup", GitRewritePushMirror},
+		{"git -c http.sslVerify=false push --mirror", GitRewritePushMirror},
+		{"git push origin main", ""},
+		{"git reflog expire --expire=now --all", GitRewriteReflogExpire},
+		{"git reflog delete HEAD@{2}", GitRewriteReflogExpire},
+		{"git reflog", ""},
+		{"cd repo && git rebase -i HEAD~2", GitRewriteRebaseInteractive},
+		{"echo git rebase -i", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cmd, func(t *testing.T) {
+			testutil.RequireEqual(t, tt.want, DetectGitRewrite(tt.cmd), "kind")
+		})
+	}
+}
+
+func TestGitRewriteClassification(t *testing.T) {
+	engine := NewPatternEngine()
+	tests := []struct {
+		cmd  string
+		want RiskTier
+	}{
+		{"git push --mirror backup", RiskTierCritical},
+		{"git filter-branch --force --index-filter 'git rm --cached secrets' HEAD", RiskTierCritical},
+		{"git filter-repo --invert-paths --path secrets", RiskTierCritical},
+		{"git reflog expire --expire=now --all", RiskTierCritical},
+		{"git reflog expire --expire-unreachable=now --all", RiskTierCritical},
+		{"git reflog expire --expire=90.days.ago", RiskTierDangerous},
+		{"git reflog delete HEAD@{1}", RiskTierDangerous},
+		{"git rebase -i HEAD~3", RiskTierDangerous},
+		{"git rebase --interactive --autosquash main", RiskTierDangerous},
+		{"git rebase --root", RiskTierDangerous},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cmd, func(t *testing.T) {
+			testutil.RequireEqual(t, tt.want, engine.ClassifyCommand(tt.cmd, "").Tier, "tier")
+		})
+	}
+}
+
+func TestRebaseBase(t *testing.T) {
+	testutil.RequireEqual(t, "HEAD~3", rebaseBase([]string{"-i", "HEAD~3"}, "origin/main"), "explicit")
+	testutil.RequireEqual(t, "origin/main", rebaseBase([]string{"-i"}, "origin/main"), "upstream")
+	testutil.RequireEqual(t, "--root", rebaseBase([]string{"-i", "--root"}, ""), "root")
+	testutil.RequireEqual(t, "topic", rebaseBase([]string{"-i", "--onto", "main", "topic"}, ""), "onto")
+}
+
+func TestMirrorDeletions(t *testing.T) {
+	got := mirrorDeletions("HEAD\nmain\nrelease\nfeature", "main\nfeature")
+	testutil.RequireEqual(t, "release", strings.Join(got, ","), "deleted")
+}
+
+// setupRewriteRepo creates a repo with three commits, a bare "origin" that
+// has the first two, and a "release" branch only on origin.
+func setupRewriteRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := execLookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	root := t.TempDir()
+	origin := filepath.Join(root, "origin.git")
+	repo := filepath.Join(root, "repo")
+	git := func(dir string, args ...string) {
+		t.Helper()
+		if out, err := runCmdString(context.Background(), dir, "git", args...); err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+	commit := func(msg string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, msg+".txt"), []byte(msg), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		git(repo, "add", ".")
+		git(repo, "commit", "-q", "-m", msg)
+	}
+
+	git(root, "init", "-q", "--bare", origin)
+	git(root, "init", "-q", "-b", "main", repo)
+	git(repo, "config", "user.name", "Test")
+	git(repo, "config", "user.email", "test@example.com")
+	git(repo, "remote", "add", "origin", origin)
+	commit("one")
+	commit("two")
+	git(repo, "push", "-q", "-u", "origin", "main")
+	git(repo, "push", "-q", "origin", "main:release")
+	git(repo, "fetch", "-q", "origin")
+	commit("three")
+	return repo
+}
+
+func TestAnalyzeGitRewrite(t *testing.T) {
+	repo := setupRewriteRepo(t)
+
+	if AnalyzeGitRewrite("git push origin main", repo) != nil {
+		t.Fatal("expected nil for an ordinary push")
+	}
+
+	r := AnalyzeGitRewrite("git rebase -i HEAD~2", repo)
+	if r == nil {
+		t.Fatal("expected a rebase analysis")
+	}
+	testutil.RequireEqual(t, "main", r.Branch, "branch")
+	testutil.RequireEqual(t, "origin/main", r.Upstream, "upstream")
+	testutil.RequireEqual(t, 1, r.Ahead, "ahead")
+	testutil.RequireEqual(t, 0, r.Behind, "behind")
+	testutil.RequireEqual(t, 2, r.Rewritten, "rewritten")
+	testutil.RequireEqual(t, 1, r.Published, "published")
+	if !strings.Contains(r.Summary(), "1 already pushed") {
+		t.Fatalf("summary should warn about pushed commits:\n%s", r.Summary())
+	}
+
+	m := AnalyzeGitRewrite("git push --mirror origin", repo)
+	if m == nil {
+		t.Fatal("expected a mirror analysis")
+	}
+	testutil.RequireEqual(t, "origin", m.Remote, "remote")
+	testutil.RequireEqual(t, "release", strings.Join(m.DeletedBranches, ","), "deleted branches")
+	if !strings.Contains(m.Summary(), "release") {
+		t.Fatalf("summary should name the deleted branch:\n%s", m.Summary())
+	}
+
+	// -C is resolved against cwd.
+	c := AnalyzeGitRewrite("git -C repo rebase -i", filepath.Dir(repo))
+	if c == nil || c.Base != "origin/main" || c.Rewritten != 1 || c.Published != 0 {
+		t.Fatalf("unexpected -C analysis: %+v", c)
+	}
+}
+
+func TestCreateRequest_AttachesGitRewrite(t *testing.T) {
+	repo := setupRewriteRepo(t)
+	database := testutil.NewTestDB(t)
+	session := testutil.MakeSession(t, database, testutil.WithProject(repo))
+	creator := NewRequestCreator(database, nil, nil, nil)
+
+	result, err := creator.CreateRequest(context.Background(), CreateRequestOptions{
+		SessionID:     session.ID,
+		Command:       "git rebase -i HEAD~2",
+		Cwd:           repo,
+		Justification: Justification{Reason: "squash fixups"},
+	})
+	testutil.RequireNoError(t, err, "create request")
+	testutil.RequireEqual(t, RiskTierDangerous, result.Request.RiskTier, "tier")
+
+	stored, err := database.GetRequest(result.Request.ID)
+	testutil.RequireNoError(t, err, "get request")
+	var found *db.Attachment
+	for i, a := range stored.Attachments {
+		if a.Metadata["type"] == "git_rewrite" {
+			found = &stored.Attachments[i]
+		}
+	}
+	if found == nil {
+		t.Fatalf("expected a git_rewrite attachment, got %+v", stored.Attachments)
+	}
+	if !strings.Contains(found.Content, "upstream origin/main") {
+		t.Fatalf("attachment should name the upstream:\n%s", found.Content)
+	}
+}
@@ -0,0 +1,362 @@
+package core
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// History import formats.
+const (
+	HistoryFormatCSV  = "csv"
+	HistoryFormatJSON = "json"
+)
+
+// History import statuses, per record.
+const (
+	// HistoryNew is a valid record not imported yet, as a dry run reports.
+	HistoryNew      = "new"
+	HistoryImported = "imported"
+	HistoryExists   = "exists"
+	HistoryInvalid  = "invalid"
+)
+
+// HistoryRecord is one approval record from another process, as read from
+// a JSON array of objects or a CSV file with these names as its header.
+// In CSV, approved_by and rejected_by separate names with ';'.
+type HistoryRecord struct {
+	// ID is the record's ID in the source; re-importing it is a no-op.
+	ID          string `json:"id"`
+	RequestedAt string `json:"requested_at"`
+	RequestedBy string `json:"requested_by"`
+	Command     string `json:"command"`
+	// Cwd defaults to the project the records are imported into.
+	Cwd    string `json:"cwd,omitempty"`
+	Reason string `json:"reason,omitempty"`
+	// Tier defaults to how the current patterns classify the command.
+	Tier string `json:"tier,omitempty"`
+	// Status is a finished slb status: executed, execution_failed,
+	// rejected, cancelled or timed_out.
+	Status     string   `json:"status"`
+	ApprovedBy []string `json:"approved_by,omitempty"`
+	RejectedBy []string `json:"rejected_by,omitempty"`
+	// ReviewedAt is when the reviews were given; it defaults to
+	// RequestedAt.
+	ReviewedAt string `json:"reviewed_at,omitempty"`
+	// Comment is attached to every review of the record.
+	Comment    string `json:"comment,omitempty"`
+	ExecutedAt string `json:"executed_at,omitempty"`
+	ExitCode   *int   `json:"exit_code,omitempty"`
+}
+
+// historyColumns are the CSV columns ParseHistoryRecords understands.
+var historyColumns = map[string]bool{
+	"id": true, "requested_at": true, "requested_by": true, "command": true,
+	"cwd": true, "reason": true, "tier": true, "status": true,
+	"approved_by": true, "rejected_by": true, "reviewed_at": true,
+	"comment": true, "executed_at": true, "exit_code": true,
+}
+
+// ParseHistoryRecords reads approval records in format (HistoryFormatCSV
+// or HistoryFormatJSON).
+func ParseHistoryRecords(data []byte, format string) ([]HistoryRecord, error) {
+	switch format {
+	case HistoryFormatJSON:
+		var records []HistoryRecord
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&records); err != nil {
+			return nil, fmt.Errorf("parsing JSON records: %w", err)
+		}
+		return records, nil
+	case HistoryFormatCSV:
+		return parseHistoryCSV(data)
+	default:
+		return nil, fmt.Errorf("unknown import format %q (want csv or json)", format)
+	}
+}
+
+func parseHistoryCSV(data []byte) ([]HistoryRecord, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.TrimLeadingSpace = true
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV header: %w", err)
+	}
+	for i, name := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(name))
+		if !historyColumns[header[i]] {
+			return nil, fmt.Errorf("unknown CSV column %q", name)
+		}
+	}
+
+	var records []HistoryRecord
+	for line := 2; ; line++ {
+		row, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading CSV: %w", err)
+		}
+		var rec HistoryRecord
+		for i, value := range row {
+			value = strings.TrimSpace(value)
+			switch header[i] {
+			case "id":
+				rec.ID = value
+			case "requested_at":
+				rec.RequestedAt = value
+			case "requested_by":
+				rec.RequestedBy = value
+			case "command":
+				rec.Command = value
+			case "cwd":
+				rec.Cwd = value
+			case "reason":
+				rec.Reason = value
+			case "tier":
+				rec.Tier = value
+			case "status":
+				rec.Status = value
+			case "approved_by":
+				rec.ApprovedBy = splitNames(value)
+			case "rejected_by":
+				rec.RejectedBy = splitNames(value)
+			case "reviewed_at":
+				rec.ReviewedAt = value
+			case "comment":
+				rec.Comment = value
+			case "executed_at":
+				rec.ExecutedAt = value
+			case "exit_code":
+				if value == "" {
+					continue
+				}
+				code, err := strconv.Atoi(value)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: invalid exit_code %q", line, value)
+				}
+				rec.ExitCode = &code
+			}
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+func splitNames(s string) []string {
+	var names []string
+	for _, name := range strings.Split(s, ";") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// HistoryImportEntry is the outcome of importing one record.
+type HistoryImportEntry struct {
+	ExternalID string `json:"external_id"`
+	// RequestID is the slb request the record became, or already is.
+	RequestID string `json:"request_id,omitempty"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+
+	request *db.Request
+	reviews []*db.Review
+}
+
+// HistoryImportOptions configures ImportHistory.
+type HistoryImportOptions struct {
+	// Source tags every imported request, e.g. "change-board".
+	Source      string
+	ProjectPath string
+	// DryRun validates the records without storing anything.
+	DryRun bool
+}
+
+// ImportHistory stores approval records from another process as finished
+// slb requests tagged with opts.Source, keeping their original times. All
+// records are checked first: if any is invalid, nothing is imported.
+// Records already imported from the same source are skipped.
+func ImportHistory(database *db.DB, records []HistoryRecord, opts HistoryImportOptions) ([]HistoryImportEntry, error) {
+	if strings.TrimSpace(opts.Source) == "" {
+		return nil, fmt.Errorf("an import source is required")
+	}
+	if err := db.ValidateLabel("source", opts.Source); err != nil || strings.ContainsAny(opts.Source, " \t") {
+		return nil, fmt.Errorf("invalid import source %q: use a short name like change-board", opts.Source)
+	}
+
+	entries := make([]HistoryImportEntry, len(records))
+	seen := make(map[string]bool, len(records))
+	var invalid int
+	for i, rec := range records {
+		e := &entries[i]
+		e.ExternalID = rec.ID
+		req, reviews, err := rec.toRequest(opts.ProjectPath)
+		if err == nil && seen[rec.ID] {
+			err = fmt.Errorf("duplicate id %q", rec.ID)
+		}
+		seen[rec.ID] = true
+		if err != nil {
+			e.Status, e.Error = HistoryInvalid, err.Error()
+			invalid++
+			continue
+		}
+		req.Import = &db.RequestImport{Source: opts.Source, ExternalID: rec.ID}
+		e.Status, e.request, e.reviews = HistoryNew, req, reviews
+	}
+	if invalid > 0 {
+		return entries, nil
+	}
+	if opts.DryRun {
+		for i := range entries {
+			if existing, err := database.GetImportedRequest(opts.Source, entries[i].ExternalID); err == nil {
+				entries[i].Status, entries[i].RequestID = HistoryExists, existing.ID
+			}
+		}
+		return entries, nil
+	}
+
+	for i := range entries {
+		e := &entries[i]
+		err := database.ImportRequest(e.request, e.reviews)
+		switch {
+		case errors.Is(err, db.ErrRequestAlreadyImported):
+			e.Status = HistoryExists
+			if existing, err := database.GetImportedRequest(opts.Source, e.ExternalID); err == nil {
+				e.RequestID = existing.ID
+			}
+		case err != nil:
+			return entries, fmt.Errorf("record %s: %w", e.ExternalID, err)
+		default:
+			e.Status = HistoryImported
+			e.RequestID = e.request.ID
+		}
+	}
+	return entries, nil
+}
+
+// historyTimeLayouts are the timestamp forms accepted in records; times
+// without a zone are UTC.
+var historyTimeLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02"}
+
+func parseHistoryTime(field, s string) (time.Time, error) {
+	for _, layout := range historyTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid %s %q (want RFC 3339, e.g. 2025-03-01T14:05:00Z)", field, s)
+}
+
+// toRequest converts a record into a finished request and its reviews.
+func (rec HistoryRecord) toRequest(projectPath string) (*db.Request, []*db.Review, error) {
+	if rec.ID == "" {
+		return nil, nil, fmt.Errorf("id is required")
+	}
+	if strings.TrimSpace(rec.Command) == "" {
+		return nil, nil, fmt.Errorf("command is required")
+	}
+	if rec.RequestedAt == "" {
+		return nil, nil, fmt.Errorf("requested_at is required")
+	}
+	requestedAt, err := parseHistoryTime("requested_at", rec.RequestedAt)
+	if err != nil {
+		return nil, nil, err
+	}
+	status := db.RequestStatus(strings.ToLower(rec.Status))
+	if !status.IsTerminal() {
+		return nil, nil, fmt.Errorf("status %q is not a finished status (executed, execution_failed, rejected, cancelled, timed_out)", rec.Status)
+	}
+
+	cwd := rec.Cwd
+	if cwd == "" {
+		cwd = projectPath
+	}
+	tier := ParseRiskTier(rec.Tier)
+	if rec.Tier != "" && tier == "" {
+		return nil, nil, fmt.Errorf("unknown tier %q", rec.Tier)
+	}
+	if tier == "" {
+		tier = GetDefaultEngine().ClassifyCommand(rec.Command, cwd).Tier
+	}
+	if tier == "" {
+		tier = RiskTier(RiskSafe)
+	}
+
+	requestor := rec.RequestedBy
+	if requestor == "" {
+		requestor = "unknown"
+	}
+	reason := rec.Reason
+	if reason == "" {
+		reason = "(imported without a reason)"
+	}
+	spec := db.CommandSpec{Raw: rec.Command, Cwd: cwd, Shell: true}
+	if redacted := ApplyRedaction(rec.Command, nil); redacted != rec.Command {
+		spec.DisplayRedacted = redacted
+		spec.ContainsSensitive = true
+	}
+	req := &db.Request{
+		ProjectPath:    projectPath,
+		Command:        spec,
+		RiskTier:       tier,
+		RequestorAgent: requestor,
+		Justification:  db.Justification{Reason: reason},
+		Status:         status,
+		MinApprovals:   len(rec.ApprovedBy),
+		CreatedAt:      requestedAt,
+	}
+
+	reviewedAt := requestedAt
+	if rec.ReviewedAt != "" {
+		if reviewedAt, err = parseHistoryTime("reviewed_at", rec.ReviewedAt); err != nil {
+			return nil, nil, err
+		}
+	}
+	var reviews []*db.Review
+	reviewers := make(map[string]bool)
+	for _, decision := range []struct {
+		names    []string
+		decision db.Decision
+	}{{rec.ApprovedBy, db.DecisionApprove}, {rec.RejectedBy, db.DecisionReject}} {
+		for _, name := range decision.names {
+			if reviewers[name] {
+				return nil, nil, fmt.Errorf("%s is listed as a reviewer twice", name)
+			}
+			reviewers[name] = true
+			reviews = append(reviews, &db.Review{
+				ReviewerAgent: name,
+				Decision:      decision.decision,
+				Comments:      rec.Comment,
+				CreatedAt:     reviewedAt,
+			})
+		}
+	}
+	resolvedAt := reviewedAt
+	req.ResolvedAt = &resolvedAt
+
+	if rec.ExecutedAt != "" || rec.ExitCode != nil {
+		exec := &db.Execution{ExecutedByAgent: requestor, ExitCode: rec.ExitCode}
+		if rec.ExecutedAt != "" {
+			executedAt, err := parseHistoryTime("executed_at", rec.ExecutedAt)
+			if err != nil {
+				return nil, nil, err
+			}
+			exec.ExecutedAt = &executedAt
+			req.ResolvedAt = &executedAt
+		}
+		req.Execution = exec
+	}
+	return req, reviews, nil
+}
@@ -0,0 +1,123 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+const historyCSV = `id,requested_at,requested_by,command,reason,status,approved_by,rejected_by,reviewed_at,comment,executed_at,exit_code
+CHG-1,2024-03-01T14:05:00Z,alice,"rm -rf ./build",clean build,executed,bob;carol,,2024-03-01 14:20:00,looks fine,2024-03-01T15:00:00Z,0
+CHG-2,2024-03-02,dave,"git push --force origin main",hotfix,rejected,,bob,,not on main,,
+`
+
+func TestParseHistoryRecords(t *testing.T) {
+	records, err := ParseHistoryRecords([]byte(historyCSV), HistoryFormatCSV)
+	if err != nil {
+		t.Fatalf("ParseHistoryRecords(csv) error = %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+	if r := records[0]; r.ID != "CHG-1" || r.Command != "rm -rf ./build" || !reflect.DeepEqual(r.ApprovedBy, []string{"bob", "carol"}) || r.ExitCode == nil || *r.ExitCode != 0 {
+		t.Errorf("record 0 = %+v", r)
+	}
+	if r := records[1]; !reflect.DeepEqual(r.RejectedBy, []string{"bob"}) || r.ApprovedBy != nil || r.ExitCode != nil {
+		t.Errorf("record 1 = %+v", r)
+	}
+
+	records, err = ParseHistoryRecords([]byte(`[{"id":"A-1","requested_at":"2024-03-01T14:05:00Z","command":"make deploy","status":"executed","approved_by":["bob"]}]`), HistoryFormatJSON)
+	if err != nil || len(records) != 1 || records[0].ApprovedBy[0] != "bob" {
+		t.Fatalf("ParseHistoryRecords(json) = %+v, %v", records, err)
+	}
+
+	for name, tc := range map[string]struct{ data, format string }{
+		"unknown csv column": {"id,approver\nA,b\n", HistoryFormatCSV},
+		"bad exit code":      {"id,exit_code\nA,x\n", HistoryFormatCSV},
+		"unknown json field": {`[{"id":"A","approver":"b"}]`, HistoryFormatJSON},
+		"unknown format":     {"", "xml"},
+	} {
+		if _, err := ParseHistoryRecords([]byte(tc.data), tc.format); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestImportHistory(t *testing.T) {
+	dbConn, err := db.Open(":memory:")
+	if err != nil {
+		t.Fatalf("db.Open(:memory:) error = %v", err)
+	}
+	defer dbConn.Close()
+
+	records, err := ParseHistoryRecords([]byte(historyCSV), HistoryFormatCSV)
+	if err != nil {
+		t.Fatalf("ParseHistoryRecords error = %v", err)
+	}
+	opts := HistoryImportOptions{Source: "change-board", ProjectPath: "/proj", DryRun: true}
+
+	entries, err := ImportHistory(dbConn, records, opts)
+	if err != nil {
+		t.Fatalf("dry run error = %v", err)
+	}
+	if entries[0].Status != HistoryNew || entries[1].Status != HistoryNew {
+		t.Fatalf("dry run entries = %+v", entries)
+	}
+
+	opts.DryRun = false
+	entries, err = ImportHistory(dbConn, records, opts)
+	if err != nil {
+		t.Fatalf("ImportHistory error = %v", err)
+	}
+	if entries[0].Status != HistoryImported || entries[0].RequestID == "" {
+		t.Fatalf("entries = %+v", entries)
+	}
+	req, reviews, err := dbConn.GetRequestWithReviews(entries[0].RequestID)
+	if err != nil {
+		t.Fatalf("GetRequestWithReviews error = %v", err)
+	}
+	if req.Status != db.StatusExecuted || req.RequestorAgent != "alice" || req.Command.Cwd != "/proj" || req.MinApprovals != 2 {
+		t.Errorf("request = %+v", req)
+	}
+	if req.RiskTier != db.RiskTierDangerous {
+		t.Errorf("tier = %q, want the classification of the command (dangerous)", req.RiskTier)
+	}
+	if len(reviews) != 2 || reviews[0].Comments != "looks fine" || reviews[0].CreatedAt.Format("15:04") != "14:20" {
+		t.Errorf("reviews = %+v", reviews)
+	}
+	rejected, err := dbConn.GetRequest(entries[1].RequestID)
+	if err != nil || rejected.Status != db.StatusRejected || rejected.Execution != nil {
+		t.Errorf("rejected request = %+v, %v", rejected, err)
+	}
+
+	// Rerunning the import skips what is already there.
+	entries, err = ImportHistory(dbConn, records, opts)
+	if err != nil {
+		t.Fatalf("re-import error = %v", err)
+	}
+	if entries[0].Status != HistoryExists || entries[0].RequestID != req.ID {
+		t.Errorf("re-import entries = %+v", entries)
+	}
+
+	// One bad record stops the whole import.
+	bad := []HistoryRecord{
+		{ID: "CHG-3", RequestedAt: "2024-03-03T10:00:00Z", Command: "make deploy", Status: "executed"},
+		{ID: "CHG-4", RequestedAt: "2024-03-03T10:00:00Z", Command: "make deploy", Status: "approved"},
+		{ID: "CHG-3", RequestedAt: "yesterday", Command: "make deploy", Status: "executed"},
+	}
+	entries, err = ImportHistory(dbConn, bad, opts)
+	if err != nil {
+		t.Fatalf("ImportHistory(bad) error = %v", err)
+	}
+	if entries[0].Status != HistoryNew || entries[1].Status != HistoryInvalid || entries[2].Status != HistoryInvalid {
+		t.Errorf("bad entries = %+v", entries)
+	}
+	if _, err := dbConn.GetImportedRequest("change-board", "CHG-3"); err == nil {
+		t.Error("expected nothing imported when a record is invalid")
+	}
+
+	if _, err := ImportHistory(dbConn, records, HistoryImportOptions{Source: "change board"}); err == nil {
+		t.Error("expected an invalid source to be refused")
+	}
+}
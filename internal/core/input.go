@@ -0,0 +1,98 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Input limits for agent-supplied text. They keep a misbehaving agent from
+// bloating the database or flooding a reviewer's terminal.
+const (
+	// MaxCommandBytes bounds a command, including multi-line scripts.
+	MaxCommandBytes = 64 << 10
+	// MaxCwdBytes bounds a working directory (PATH_MAX on Linux).
+	MaxCwdBytes = 4096
+	// MaxTextBytes bounds each free-text field: justification fields,
+	// review responses and comments.
+	MaxTextBytes = 8 << 10
+)
+
+// ErrInvalidInput is wrapped by every *InputError.
+var ErrInvalidInput = errors.New("invalid input")
+
+// InputError reports a field that failed input validation.
+type InputError struct {
+	// Field is the name of the input, e.g. "command" or "reason".
+	Field string
+	// Reason says what is wrong with it.
+	Reason string
+}
+
+func (e *InputError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Field, e.Reason)
+}
+
+// Unwrap lets callers match any input error with errors.Is(err, ErrInvalidInput).
+func (e *InputError) Unwrap() error {
+	return ErrInvalidInput
+}
+
+// ValidateExact checks input that must be kept byte for byte, such as a
+// command or a path: it must fit in max bytes, be valid UTF-8 and contain
+// no NUL bytes. Nothing is rewritten, since the value is what will run.
+func ValidateExact(field, value string, max int) error {
+	if len(value) > max {
+		return &InputError{Field: field, Reason: fmt.Sprintf("too long (%d bytes, max %d)", len(value), max)}
+	}
+	if strings.IndexByte(value, 0) >= 0 {
+		return &InputError{Field: field, Reason: "contains a NUL byte"}
+	}
+	if !utf8.ValidString(value) {
+		return &InputError{Field: field, Reason: "not valid UTF-8"}
+	}
+	return nil
+}
+
+// SanitizeText checks and cleans free text shown to reviewers. Text over max
+// bytes or containing NUL is rejected; invalid UTF-8 is replaced with U+FFFD
+// and control characters other than newline and tab are dropped, so escape
+// sequences cannot restyle or clear a reviewer's terminal.
+func SanitizeText(field, value string, max int) (string, error) {
+	if len(value) > max {
+		return "", &InputError{Field: field, Reason: fmt.Sprintf("too long (%d bytes, max %d)", len(value), max)}
+	}
+	if strings.IndexByte(value, 0) >= 0 {
+		return "", &InputError{Field: field, Reason: "contains a NUL byte"}
+	}
+	value = strings.ToValidUTF8(value, string(utf8.RuneError))
+	return strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\t' || !unicode.IsControl(r) {
+			return r
+		}
+		return -1
+	}, value), nil
+}
+
+// sanitizeJustification applies SanitizeText to each justification field.
+func sanitizeJustification(j Justification) (Justification, error) {
+	fields := []struct {
+		name  string
+		value *string
+	}{
+		{"reason", &j.Reason},
+		{"expected_effect", &j.ExpectedEffect},
+		{"goal", &j.Goal},
+		{"safety_argument", &j.SafetyArgument},
+	}
+	for _, f := range fields {
+		clean, err := SanitizeText(f.name, *f.value, MaxTextBytes)
+		if err != nil {
+			return Justification{}, err
+		}
+		*f.value = clean
+	}
+	return j, nil
+}
@@ -0,0 +1,134 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestValidateExact(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		max     int
+		wantErr string
+	}{
+		{"ok", "rm -rf ./build", 64, ""},
+		{"multi-line", "set -e\nmake clean", 64, ""},
+		{"utf-8", "rm -rf ./données", 64, ""},
+		{"too long", strings.Repeat("a", 65), 64, "too long (65 bytes, max 64)"},
+		{"nul", "rm -rf ./build\x00 /", 64, "contains a NUL byte"},
+		{"invalid utf-8", "rm -rf ./\xff", 64, "not valid UTF-8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateExact("command", tt.value, tt.max)
+			if tt.wantErr == "" {
+				testutil.RequireNoError(t, err, "ValidateExact")
+				return
+			}
+			var ierr *InputError
+			if !errors.As(err, &ierr) || ierr.Field != "command" || ierr.Reason != tt.wantErr {
+				t.Fatalf("ValidateExact() = %v, want InputError %q", err, tt.wantErr)
+			}
+			if !errors.Is(err, ErrInvalidInput) {
+				t.Fatalf("expected %v to match ErrInvalidInput", err)
+			}
+		})
+	}
+}
+
+func TestSanitizeText(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"plain", "clean up old builds", "clean up old builds", false},
+		{"keeps newlines and tabs", "line one\n\tline two", "line one\n\tline two", false},
+		{"drops escapes", "ok\x1b[2J\x1b[31mred", "ok[2J[31mred", false},
+		{"drops carriage returns", "a\r\nb", "a\nb", false},
+		{"drops c1 controls", "a\u009bb", "ab", false},
+		{"replaces invalid utf-8", "a\xffb", "a�b", false},
+		{"rejects nul", "a\x00b", "", true},
+		{"rejects oversize", strings.Repeat("x", MaxTextBytes+1), "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SanitizeText("reason", tt.value, MaxTextBytes)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidInput) {
+					t.Fatalf("SanitizeText() error = %v, want ErrInvalidInput", err)
+				}
+				return
+			}
+			testutil.RequireNoError(t, err, "SanitizeText")
+			testutil.RequireEqual(t, tt.want, got, "sanitized text")
+		})
+	}
+}
+
+func TestCreateRequest_InputLimits(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	session := testutil.MakeSession(t, database, testutil.SessionWithAgentName("agent1"))
+	creator := NewRequestCreator(database, nil, nil, nil)
+
+	for name, opts := range map[string]CreateRequestOptions{
+		"nul in command":   {Command: "git reset --hard\x00HEAD~3"},
+		"oversize command": {Command: "git reset --hard " + strings.Repeat("a", MaxCommandBytes)},
+		"oversize cwd":     {Command: "git reset --hard HEAD~3", Cwd: "/" + strings.Repeat("d", MaxCwdBytes)},
+		"nul in reason":    {Command: "git reset --hard HEAD~3", Justification: Justification{Reason: "a\x00b"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			opts.SessionID = session.ID
+			if _, err := creator.CreateRequest(context.Background(), opts); !errors.Is(err, ErrInvalidInput) {
+				t.Fatalf("expected ErrInvalidInput, got %v", err)
+			}
+		})
+	}
+
+	result, err := creator.CreateRequest(context.Background(), CreateRequestOptions{
+		SessionID: session.ID,
+		Command:   "git reset --hard HEAD~3",
+		Justification: Justification{
+			Reason: "reset\x1b[2J commits",
+			Goal:   "bad \xff byte",
+		},
+	})
+	testutil.RequireNoError(t, err, "create request")
+	stored, err := database.GetRequest(result.Request.ID)
+	testutil.RequireNoError(t, err, "get request")
+	testutil.RequireEqual(t, "reset[2J commits", stored.Justification.Reason, "reason")
+	testutil.RequireEqual(t, "bad � byte", stored.Justification.Goal, "goal")
+}
+
+func TestSubmitReview_SanitizesComments(t *testing.T) {
+	dbConn, _, req := setupReviewTest(t)
+	defer dbConn.Close()
+
+	reviewer := &db.Session{AgentName: "GreenLake", Program: "claude-code", Model: "opus-4.5", ProjectPath: "/test/project"}
+	testutil.RequireNoError(t, dbConn.CreateSession(reviewer), "create reviewer")
+	rs := NewReviewService(dbConn, DefaultReviewConfig())
+
+	_, err := rs.SubmitReview(ReviewOptions{
+		SessionID: reviewer.ID, SessionKey: reviewer.SessionKey, RequestID: req.ID,
+		Decision: db.DecisionApprove, Comments: "nope\x00",
+	})
+	if !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput for a NUL in comments, got %v", err)
+	}
+
+	result, err := rs.SubmitReview(ReviewOptions{
+		SessionID: reviewer.ID, SessionKey: reviewer.SessionKey, RequestID: req.ID,
+		Decision: db.DecisionApprove, Comments: "\x1b]0;pwned\x07looks fine",
+		Responses: db.ReviewResponse{ReasonResponse: "ok\r\n"},
+	})
+	testutil.RequireNoError(t, err, "submit review")
+	testutil.RequireEqual(t, "]0;pwnedlooks fine", result.Review.Comments, "comments")
+	testutil.RequireEqual(t, "ok\n", result.Review.Responses.ReasonResponse, "reason response")
+}
@@ -0,0 +1,186 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrIsolationCwd is returned when a command would run outside the project
+// its isolation confines it to.
+var ErrIsolationCwd = errors.New("working directory is outside the isolated project")
+
+// Isolation modes.
+const (
+	IsolationNone      = "none"
+	IsolationUser      = "user"
+	IsolationContainer = "container"
+)
+
+// Isolation runs approved commands away from the invoking user's host
+// state: as a different, low-privilege account, or in a throwaway
+// container that sees only the project directory. User mode changes who a
+// command runs as; it does not confine the command to the project, which
+// only container mode does. Break-glass and emergency executions bypass
+// approval and are not isolated.
+type Isolation struct {
+	// Mode is IsolationUser or IsolationContainer.
+	Mode string
+	// User is the account commands run as. In user mode it is required; in
+	// container mode it is passed to --user and defaults to the caller's
+	// uid:gid so files written in the project keep their owner.
+	User string
+	// Runtime is the container CLI, docker or podman.
+	Runtime string
+	// Image is the container image commands run in.
+	Image string
+	// Network gives the container network access; by default it has none.
+	Network bool
+	// ProjectPath is the only host directory mounted into the container.
+	ProjectPath string
+}
+
+// NewIsolation builds an isolation from configuration values. It returns
+// nil when mode is empty or none.
+func NewIsolation(mode, user, runtime, image string, network bool, projectPath string) (*Isolation, error) {
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	if mode == "" || mode == IsolationNone {
+		return nil, nil
+	}
+	iso := &Isolation{
+		Mode:    mode,
+		User:    strings.TrimSpace(user),
+		Runtime: strings.TrimSpace(runtime),
+		Image:   strings.TrimSpace(image),
+		Network: network,
+	}
+	switch mode {
+	case IsolationUser:
+		if iso.User == "" {
+			return nil, fmt.Errorf("user isolation needs a user")
+		}
+	case IsolationContainer:
+		if iso.Runtime == "" {
+			iso.Runtime = "docker"
+		}
+		if iso.Runtime != "docker" && iso.Runtime != "podman" {
+			return nil, fmt.Errorf("unsupported container runtime %q (want docker or podman)", iso.Runtime)
+		}
+		if iso.Image == "" {
+			return nil, fmt.Errorf("container isolation needs an image")
+		}
+		if strings.TrimSpace(projectPath) == "" {
+			return nil, fmt.Errorf("container isolation needs a project path")
+		}
+		abs, err := filepath.Abs(projectPath)
+		if err != nil {
+			return nil, fmt.Errorf("resolving project path: %w", err)
+		}
+		iso.ProjectPath = abs
+	default:
+		return nil, fmt.Errorf("unknown isolation mode %q (want none, user or container)", mode)
+	}
+	return iso, nil
+}
+
+// String describes the isolation for logs, e.g. "user slb-runner" or
+// "container docker alpine:3.20 (no network)".
+func (iso *Isolation) String() string {
+	if iso == nil {
+		return IsolationNone
+	}
+	if iso.Mode == IsolationUser {
+		return "user " + iso.User
+	}
+	s := fmt.Sprintf("container %s %s", iso.Runtime, iso.Image)
+	if !iso.Network {
+		s += " (no network)"
+	}
+	return s
+}
+
+// shell is the shell that runs shell-mode commands. The caller's $SHELL
+// may not exist in a container image, so containers use /bin/sh.
+func (iso *Isolation) shell() string {
+	if iso != nil && iso.Mode == IsolationContainer {
+		return "/bin/sh"
+	}
+	if shell := os.Getenv("SHELL"); shell != "" {
+		return shell
+	}
+	return "/bin/sh"
+}
+
+// wrap returns the argv that runs argv in cwd under the isolation. mounts
+// are extra host files (such as a script) made visible read-only inside
+// the container at the same path. A nil isolation returns argv unchanged.
+func (iso *Isolation) wrap(argv []string, cwd string, mounts ...string) ([]string, error) {
+	if iso == nil {
+		return argv, nil
+	}
+	if iso.Mode == IsolationUser {
+		return append([]string{"sudo", "-n", "-u", iso.User, "--"}, argv...), nil
+	}
+
+	dir := iso.ProjectPath
+	if cwd != "" {
+		abs, err := filepath.Abs(cwd)
+		if err != nil {
+			return nil, fmt.Errorf("resolving working directory: %w", err)
+		}
+		rel, err := filepath.Rel(iso.ProjectPath, abs)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("%w: %s is not under %s", ErrIsolationCwd, abs, iso.ProjectPath)
+		}
+		dir = abs
+	}
+
+	args := []string{iso.Runtime, "run", "--rm", "-i"}
+	if !iso.Network {
+		args = append(args, "--network", "none")
+	}
+	if user := iso.containerUser(); user != "" {
+		args = append(args, "--user", user)
+	}
+	project, err := bindMount(iso.ProjectPath, false)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, "--mount", project)
+	for _, m := range mounts {
+		mount, err := bindMount(m, true)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "--mount", mount)
+	}
+	args = append(args, "-w", dir, iso.Image)
+	return append(args, argv...), nil
+}
+
+// bindMount returns the --mount value that binds host path at the same path
+// in the container. --mount is used rather than -v because -v splits on
+// ':'; a ',' would split the --mount value instead, so such paths are
+// refused.
+func bindMount(path string, readonly bool) (string, error) {
+	if strings.Contains(path, ",") {
+		return "", fmt.Errorf("cannot mount %s into the container: path contains a comma", path)
+	}
+	mount := "type=bind,source=" + path + ",target=" + path
+	if readonly {
+		mount += ",readonly"
+	}
+	return mount, nil
+}
+
+func (iso *Isolation) containerUser() string {
+	if iso.User != "" {
+		return iso.User
+	}
+	if uid, gid := os.Getuid(), os.Getgid(); uid >= 0 && gid >= 0 {
+		return fmt.Sprintf("%d:%d", uid, gid)
+	}
+	return ""
+}
@@ -0,0 +1,170 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+func TestNewIsolation(t *testing.T) {
+	for _, mode := range []string{"", "none"} {
+		iso, err := NewIsolation(mode, "", "", "", false, "/work")
+		if err != nil || iso != nil {
+			t.Errorf("NewIsolation(%q) = %v, %v; want nil, nil", mode, iso, err)
+		}
+	}
+
+	for _, tc := range []struct {
+		name                       string
+		mode, user, runtime, image string
+		want                       string
+	}{
+		{"user without user", "user", "", "", "", "needs a user"},
+		{"container without image", "container", "", "docker", "", "needs an image"},
+		{"unknown runtime", "container", "", "lxc", "alpine", "unsupported container runtime"},
+		{"unknown mode", "vm", "", "", "", "unknown isolation mode"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewIsolation(tc.mode, tc.user, tc.runtime, tc.image, false, "/work")
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("err = %v, want %q", err, tc.want)
+			}
+		})
+	}
+
+	iso, err := NewIsolation("container", "", "", "alpine:3.20", false, "/work")
+	if err != nil {
+		t.Fatalf("NewIsolation: %v", err)
+	}
+	if iso.Runtime != "docker" {
+		t.Errorf("Runtime = %q, want docker by default", iso.Runtime)
+	}
+	if got := iso.String(); got != "container docker alpine:3.20 (no network)" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestIsolationWrap(t *testing.T) {
+	argv := []string{"rm", "-rf", "build"}
+
+	user := &Isolation{Mode: IsolationUser, User: "slb-runner"}
+	got, err := user.wrap(argv, "/work/app")
+	if err != nil {
+		t.Fatalf("user wrap: %v", err)
+	}
+	want := []string{"sudo", "-n", "-u", "slb-runner", "--", "rm", "-rf", "build"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("user wrap = %q, want %q", got, want)
+	}
+
+	project := filepath.Join(t.TempDir(), "app")
+	container := &Isolation{Mode: IsolationContainer, User: "1000:1000", Runtime: "podman", Image: "alpine:3.20", ProjectPath: project}
+	got, err = container.wrap(argv, filepath.Join(project, "sub"), "/tmp/slb-script-1.sh")
+	if err != nil {
+		t.Fatalf("container wrap: %v", err)
+	}
+	want = []string{
+		"podman", "run", "--rm", "-i", "--network", "none", "--user", "1000:1000",
+		"--mount", "type=bind,source=" + project + ",target=" + project,
+		"--mount", "type=bind,source=/tmp/slb-script-1.sh,target=/tmp/slb-script-1.sh,readonly",
+		"-w", filepath.Join(project, "sub"), "alpine:3.20",
+		"rm", "-rf", "build",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("container wrap =\n%q\nwant\n%q", got, want)
+	}
+
+	container.Network = true
+	got, _ = container.wrap(argv, "")
+	if strings.Contains(strings.Join(got, " "), "--network") {
+		t.Errorf("network-enabled wrap should not pass --network: %q", got)
+	}
+	if i := indexOf(got, "-w"); i < 0 || got[i+1] != project {
+		t.Errorf("empty cwd should run in the project: %q", got)
+	}
+
+	for _, cwd := range []string{filepath.Dir(project), project + "-other", filepath.Join(project, "..", "etc")} {
+		if _, err := container.wrap(argv, cwd); !errors.Is(err, ErrIsolationCwd) {
+			t.Errorf("wrap(cwd=%s) err = %v, want ErrIsolationCwd", cwd, err)
+		}
+	}
+
+	colon := &Isolation{Mode: IsolationContainer, Runtime: "docker", Image: "alpine:3.20", ProjectPath: "/work/a:b"}
+	got, err = colon.wrap(argv, "")
+	if err != nil {
+		t.Fatalf("wrap with ':' in project: %v", err)
+	}
+	if i := indexOf(got, "--mount"); i < 0 || got[i+1] != "type=bind,source=/work/a:b,target=/work/a:b" {
+		t.Errorf("project with ':' mounted as %q", got)
+	}
+	colon.ProjectPath = "/work/a,b"
+	if _, err := colon.wrap(argv, ""); err == nil {
+		t.Error("wrap with ',' in project should fail")
+	}
+
+	var none *Isolation
+	if got, _ := none.wrap(argv, "/anywhere"); !reflect.DeepEqual(got, argv) {
+		t.Errorf("nil wrap = %q, want argv unchanged", got)
+	}
+}
+
+func indexOf(s []string, v string) int {
+	for i, x := range s {
+		if x == v {
+			return i
+		}
+	}
+	return -1
+}
+
+// TestRunCommand_Container runs a command through a stand-in docker that
+// prints its arguments, checking the command reaches the runtime with the
+// project mounted and the shell swapped for /bin/sh.
+func TestRunCommand_Container(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("stand-in runtime is a shell script")
+	}
+	bin := t.TempDir()
+	fake := "#!/bin/sh\nfor a in \"$@\"; do echo \"arg:$a\"; done\n"
+	if err := os.WriteFile(filepath.Join(bin, "docker"), []byte(fake), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("SHELL", "/usr/bin/zsh")
+
+	project := t.TempDir()
+	iso, err := NewIsolation("container", "", "docker", "alpine:3.20", false, project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logPath := filepath.Join(t.TempDir(), "run.log")
+	spec := &db.CommandSpec{Raw: "echo hi > out.txt", Cwd: project, Shell: true}
+	result, err := runCommand(context.Background(), spec, iso, nil, logPath, OutputStreams{})
+	if err != nil {
+		t.Fatalf("runCommand: %v", err)
+	}
+	for _, want := range []string{"arg:run", "arg:type=bind,source=" + project + ",target=" + project, "arg:alpine:3.20", "arg:/bin/sh", "arg:echo hi > out.txt"} {
+		if !strings.Contains(result.Output, want+"\n") {
+			t.Errorf("output missing %q:\n%s", want, result.Output)
+		}
+	}
+	log, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(log), "Isolation: container docker alpine:3.20 (no network)") {
+		t.Errorf("log header should record the isolation:\n%s", log)
+	}
+
+	spec.Cwd = t.TempDir()
+	if _, err := runCommand(context.Background(), spec, iso, nil, "", OutputStreams{}); !errors.Is(err, ErrIsolationCwd) {
+		t.Fatalf("cwd outside project: err = %v, want ErrIsolationCwd", err)
+	}
+}
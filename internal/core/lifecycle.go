@@ -0,0 +1,213 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/core/statemachine"
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// LifecycleEventKind identifies something that happens to a request.
+type LifecycleEventKind string
+
+const (
+	// EventReview is an agent's approve or reject review.
+	EventReview LifecycleEventKind = "review"
+	// EventHumanDecision is a human approving or rejecting an escalated request.
+	EventHumanDecision LifecycleEventKind = "human_decision"
+	// EventExpire is the pending request's deadline passing.
+	EventExpire LifecycleEventKind = "expire"
+	// EventEscalate hands a timed-out request to a human.
+	EventEscalate LifecycleEventKind = "escalate"
+	// EventCancel is the requestor withdrawing the request.
+	EventCancel LifecycleEventKind = "cancel"
+	// EventExecuteStart is an executor claiming an approved request.
+	EventExecuteStart LifecycleEventKind = "execute_start"
+	// EventExecuteFinish records how execution ended (see LifecycleEvent.Outcome).
+	EventExecuteFinish LifecycleEventKind = "execute_finish"
+)
+
+// LifecycleEvent is one input to Lifecycle.Apply.
+type LifecycleEvent struct {
+	Kind LifecycleEventKind
+	// At is when the event happened; it guards expiry and approval staleness.
+	At time.Time
+	// Decision is set for EventReview and EventHumanDecision.
+	Decision db.Decision
+	// Outcome is set for EventExecuteFinish: executed, execution_failed,
+	// timed_out, or approved when execution was reverted before it started.
+	Outcome db.RequestStatus
+}
+
+// Lifecycle is the part of a request that decides its status transitions.
+// Apply models the review service, expiry sweeper and executor in memory,
+// deciding each transition with the same statemachine checks they use, so
+// the lifecycle can be tested without a database.
+type Lifecycle struct {
+	Status            db.RequestStatus
+	RiskTier          db.RiskTier
+	MinApprovals      int
+	Policy            ConflictResolution
+	ExpiresAt         *time.Time
+	ApprovalExpiresAt *time.Time
+
+	// Approvals and Rejections count agent reviews.
+	Approvals  int
+	Rejections int
+	// HumanDecided is set once a human resolved an escalation.
+	HumanDecided bool
+}
+
+// NewLifecycle returns the lifecycle of a newly created (pending) request.
+func NewLifecycle(req *db.Request, policy ConflictResolution) Lifecycle {
+	return Lifecycle{
+		Status:            req.Status,
+		RiskTier:          req.RiskTier,
+		MinApprovals:      req.MinApprovals,
+		Policy:            policy,
+		ExpiresAt:         req.ExpiresAt,
+		ApprovalExpiresAt: req.ApprovalExpiresAt,
+	}
+}
+
+// Apply returns the lifecycle after ev, or an error if ev is not allowed in
+// the current state. The receiver is never modified.
+func (l Lifecycle) Apply(ev LifecycleEvent) (Lifecycle, error) {
+	next := l
+	var to db.RequestStatus
+	in := statemachine.Input{Authority: statemachine.AuthoritySystem}
+
+	switch ev.Kind {
+	case EventReview:
+		if !statemachine.CanApprove(l.Status) {
+			return l, fmt.Errorf("%w: status is %s", ErrRequestNotPending, l.Status)
+		}
+		switch ev.Decision {
+		case db.DecisionApprove:
+			next.Approvals++
+		case db.DecisionReject:
+			next.Rejections++
+		default:
+			return l, ErrInvalidDecision
+		}
+		to = reviewOutcome(l.Policy, l.MinApprovals, ev.Decision, next.Approvals, next.Rejections)
+		in = reviewInput(l.Policy, next.Approvals)
+
+	case EventHumanDecision:
+		if l.Status != db.StatusEscalated {
+			return l, &statemachine.TransitionError{From: l.Status, To: decisionStatus(ev.Decision), Message: "only escalated requests take a human decision"}
+		}
+		if ev.Decision != db.DecisionApprove && ev.Decision != db.DecisionReject {
+			return l, ErrInvalidDecision
+		}
+		next.HumanDecided = true
+		to = decisionStatus(ev.Decision)
+		in.Authority = statemachine.AuthorityHuman
+
+	case EventExpire:
+		if l.ExpiresAt == nil || !ev.At.After(*l.ExpiresAt) {
+			return l, &statemachine.TransitionError{From: l.Status, To: db.StatusTimeout, Message: "request has not expired"}
+		}
+		to = db.StatusTimeout
+
+	case EventEscalate:
+		to = db.StatusEscalated
+		if l.Status != db.StatusTimeout {
+			return l, &statemachine.TransitionError{From: l.Status, To: to, Message: "only timed-out requests are escalated"}
+		}
+
+	case EventCancel:
+		to = db.StatusCancelled
+		if !statemachine.CanCancel(l.Status) {
+			return l, &statemachine.TransitionError{From: l.Status, To: to, Message: "request cannot be cancelled"}
+		}
+
+	case EventExecuteStart:
+		to = db.StatusExecuting
+
+	case EventExecuteFinish:
+		to = ev.Outcome
+		if l.Status != db.StatusExecuting {
+			return l, &statemachine.TransitionError{From: l.Status, To: to, Message: "request is not executing"}
+		}
+
+	default:
+		return l, fmt.Errorf("unknown lifecycle event %q", ev.Kind)
+	}
+
+	if to == "" || to == l.Status {
+		return next, nil
+	}
+	req := &db.Request{
+		Status:            l.Status,
+		RiskTier:          l.RiskTier,
+		MinApprovals:      l.MinApprovals,
+		ExpiresAt:         l.ExpiresAt,
+		ApprovalExpiresAt: l.ApprovalExpiresAt,
+	}
+	if err := statemachine.TransitionAt(req, to, in, ev.At); err != nil {
+		return l, err
+	}
+	next.Status = req.Status
+	next.ApprovalExpiresAt = req.ApprovalExpiresAt
+	return next, nil
+}
+
+// reviewInput is the statemachine input for a status change caused by a
+// review: first_wins decides on a single approval, other policies need the
+// request's quorum.
+func reviewInput(policy ConflictResolution, approvals int) statemachine.Input {
+	in := statemachine.Input{Authority: statemachine.AuthorityReviews, Approvals: approvals}
+	if policy == ConflictFirstWins {
+		in.Quorum = 1
+	}
+	return in
+}
+
+// reviewOutcome is the status a request moves to after a review under
+// policy, given the review counts including that review. It returns "" when
+// the status doesn't change.
+func reviewOutcome(policy ConflictResolution, minApprovals int, decision db.Decision, approvals, rejections int) db.RequestStatus {
+	switch policy {
+	case ConflictAnyRejectionBlocks:
+		// Any rejection immediately blocks
+		if rejections > 0 {
+			return db.StatusRejected
+		}
+		// Check if we have enough approvals
+		if approvals >= minApprovals {
+			return db.StatusApproved
+		}
+
+	case ConflictFirstWins:
+		// First review determines outcome
+		if approvals+rejections == 1 {
+			return decisionStatus(decision)
+		}
+
+	case ConflictHumanBreaksTie:
+		// If there's a mix of approvals and rejections, escalate
+		if approvals > 0 && rejections > 0 {
+			return db.StatusEscalated
+		}
+		// Otherwise, check if we have enough approvals
+		if approvals >= minApprovals {
+			return db.StatusApproved
+		}
+		// Or if any rejections
+		if rejections > 0 {
+			return db.StatusRejected
+		}
+	}
+
+	return "" // No status change
+}
+
+// decisionStatus is the status a decisive review leads to.
+func decisionStatus(d db.Decision) db.RequestStatus {
+	if d == db.DecisionApprove {
+		return db.StatusApproved
+	}
+	return db.StatusRejected
+}
@@ -0,0 +1,250 @@
+package core
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/core/statemachine"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+	"pgregory.net/rapid"
+)
+
+var (
+	lifecyclePolicies = []ConflictResolution{ConflictAnyRejectionBlocks, ConflictFirstWins, ConflictHumanBreaksTie}
+	lifecycleTiers    = []db.RiskTier{db.RiskTierCritical, db.RiskTierDangerous, db.RiskTierCaution}
+	executeOutcomes   = []db.RequestStatus{db.StatusExecuted, db.StatusExecutionFailed, db.StatusTimedOut, db.StatusApproved}
+)
+
+// lifecycleMachine drives a Lifecycle through random events and checks the
+// invariants after each one.
+type lifecycleMachine struct {
+	l   Lifecycle
+	now time.Time
+}
+
+func newLifecycleMachine(t *rapid.T) *lifecycleMachine {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tier := rapid.SampledFrom(lifecycleTiers).Draw(t, "tier")
+	expires := now.Add(time.Duration(rapid.IntRange(1, 60).Draw(t, "ttl_minutes")) * time.Minute)
+	req := &db.Request{
+		Status:       db.StatusPending,
+		RiskTier:     tier,
+		MinApprovals: rapid.IntRange(0, 3).Draw(t, "min_approvals"),
+		ExpiresAt:    &expires,
+	}
+	return &lifecycleMachine{
+		l:   NewLifecycle(req, rapid.SampledFrom(lifecyclePolicies).Draw(t, "policy")),
+		now: now,
+	}
+}
+
+func (m *lifecycleMachine) apply(t *rapid.T, ev LifecycleEvent) {
+	ev.At = m.now
+	prev := m.l
+	next, err := prev.Apply(ev)
+
+	if statemachine.IsTerminal(prev.Status) && err == nil {
+		t.Fatalf("%s accepted %s in terminal state %s", ev.Kind, ev.Decision, prev.Status)
+	}
+	// Only a late approval of a pending request may be refused.
+	if ev.Kind == EventReview && statemachine.CanApprove(prev.Status) && err != nil && !errors.Is(err, statemachine.ErrRequestExpired) {
+		t.Fatalf("review (%s) rejected in %s under %s: %v", ev.Decision, prev.Status, prev.Policy, err)
+	}
+	if err != nil {
+		if !reflect.DeepEqual(next, prev) {
+			t.Fatalf("rejected %s changed the lifecycle: %+v -> %+v", ev.Kind, prev, next)
+		}
+		return
+	}
+	if next.Status != prev.Status && !statemachine.CanTransition(prev.Status, next.Status) {
+		t.Fatalf("%s moved %s -> %s, which the transition table forbids", ev.Kind, prev.Status, next.Status)
+	}
+	if next.Approvals < prev.Approvals || next.Rejections < prev.Rejections {
+		t.Fatalf("%s lowered review counts: %+v -> %+v", ev.Kind, prev, next)
+	}
+	m.l = next
+}
+
+func (m *lifecycleMachine) Review(t *rapid.T) {
+	decision := rapid.SampledFrom([]db.Decision{db.DecisionApprove, db.DecisionReject}).Draw(t, "decision")
+	m.apply(t, LifecycleEvent{Kind: EventReview, Decision: decision})
+}
+
+func (m *lifecycleMachine) HumanDecision(t *rapid.T) {
+	decision := rapid.SampledFrom([]db.Decision{db.DecisionApprove, db.DecisionReject}).Draw(t, "decision")
+	m.apply(t, LifecycleEvent{Kind: EventHumanDecision, Decision: decision})
+}
+
+func (m *lifecycleMachine) Wait(t *rapid.T) {
+	m.now = m.now.Add(time.Duration(rapid.IntRange(1, 45).Draw(t, "minutes")) * time.Minute)
+}
+
+func (m *lifecycleMachine) Expire(t *rapid.T) {
+	m.apply(t, LifecycleEvent{Kind: EventExpire})
+}
+
+func (m *lifecycleMachine) Escalate(t *rapid.T) {
+	m.apply(t, LifecycleEvent{Kind: EventEscalate})
+}
+
+func (m *lifecycleMachine) Cancel(t *rapid.T) {
+	m.apply(t, LifecycleEvent{Kind: EventCancel})
+}
+
+func (m *lifecycleMachine) ExecuteStart(t *rapid.T) {
+	m.apply(t, LifecycleEvent{Kind: EventExecuteStart})
+}
+
+func (m *lifecycleMachine) ExecuteFinish(t *rapid.T) {
+	outcome := rapid.SampledFrom(executeOutcomes).Draw(t, "outcome")
+	m.apply(t, LifecycleEvent{Kind: EventExecuteFinish, Outcome: outcome})
+}
+
+// Check runs after every action.
+func (m *lifecycleMachine) Check(t *rapid.T) {
+	l := m.l
+	switch l.Status {
+	case db.StatusApproved, db.StatusExecuting, db.StatusExecuted, db.StatusExecutionFailed, db.StatusTimedOut:
+		if !l.HumanDecided && !reachedQuorum(l) {
+			t.Fatalf("%s without quorum or a human decision: %+v", l.Status, l)
+		}
+		if l.ApprovalExpiresAt == nil {
+			t.Fatalf("%s without an approval expiry", l.Status)
+		}
+	case db.StatusTimeout, db.StatusEscalated:
+		if l.Status == db.StatusTimeout && !m.now.After(*l.ExpiresAt) {
+			t.Fatalf("timed out at %s before expiry %s", m.now, l.ExpiresAt)
+		}
+	}
+	if l.Policy == ConflictAnyRejectionBlocks && l.Rejections > 0 && l.Status == db.StatusPending {
+		t.Fatalf("pending with %d rejections under %s", l.Rejections, l.Policy)
+	}
+}
+
+// reachedQuorum reports whether agent reviews alone approve the request
+// under its policy.
+func reachedQuorum(l Lifecycle) bool {
+	switch l.Policy {
+	case ConflictFirstWins:
+		return l.Approvals >= 1
+	case ConflictAnyRejectionBlocks:
+		return l.Rejections == 0 && l.Approvals >= l.MinApprovals
+	default:
+		return l.Approvals >= l.MinApprovals
+	}
+}
+
+func TestLifecycle_Invariants(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		m := newLifecycleMachine(t)
+		t.Repeat(rapid.StateMachineActions(m))
+	})
+}
+
+func TestLifecycle_TerminalStatesRejectEveryEvent(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	past := at.Add(-time.Hour)
+	events := []LifecycleEvent{
+		{Kind: EventReview, Decision: db.DecisionApprove},
+		{Kind: EventReview, Decision: db.DecisionReject},
+		{Kind: EventHumanDecision, Decision: db.DecisionApprove},
+		{Kind: EventExpire},
+		{Kind: EventEscalate},
+		{Kind: EventCancel},
+		{Kind: EventExecuteStart},
+		{Kind: EventExecuteFinish, Outcome: db.StatusExecuted},
+	}
+	for status := range statemachine.TerminalStates {
+		for _, ev := range events {
+			ev.At = at
+			l := Lifecycle{Status: status, MinApprovals: 1, Policy: ConflictAnyRejectionBlocks, ExpiresAt: &past}
+			if _, err := l.Apply(ev); err == nil {
+				t.Errorf("%s accepted %s", status, ev.Kind)
+			}
+		}
+	}
+}
+
+func TestLifecycle_HumanBreaksTieEscalatesSplitVote(t *testing.T) {
+	l := Lifecycle{Status: db.StatusPending, MinApprovals: 2, Policy: ConflictHumanBreaksTie}
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	var err error
+	l, err = l.Apply(LifecycleEvent{Kind: EventReview, Decision: db.DecisionApprove, At: at})
+	testutil.RequireNoError(t, err, "approve")
+	l, err = l.Apply(LifecycleEvent{Kind: EventReview, Decision: db.DecisionReject, At: at})
+	testutil.RequireNoError(t, err, "reject")
+	testutil.RequireEqual(t, db.StatusEscalated, l.Status, "status after split vote")
+
+	l, err = l.Apply(LifecycleEvent{Kind: EventHumanDecision, Decision: db.DecisionApprove, At: at})
+	testutil.RequireNoError(t, err, "human approve")
+	testutil.RequireEqual(t, db.StatusApproved, l.Status, "status after human decision")
+}
+
+func TestLifecycle_StaleApprovalCannotExecute(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	l := Lifecycle{Status: db.StatusPending, RiskTier: db.RiskTierCritical, MinApprovals: 1, Policy: ConflictAnyRejectionBlocks}
+
+	l, err := l.Apply(LifecycleEvent{Kind: EventReview, Decision: db.DecisionApprove, At: at})
+	testutil.RequireNoError(t, err, "approve")
+	if l.ApprovalExpiresAt == nil || !l.ApprovalExpiresAt.Equal(at.Add(statemachine.DefaultApprovalTTL.Critical)) {
+		t.Fatalf("ApprovalExpiresAt = %v, want %v", l.ApprovalExpiresAt, at.Add(statemachine.DefaultApprovalTTL.Critical))
+	}
+
+	_, err = l.Apply(LifecycleEvent{Kind: EventExecuteStart, At: at.Add(time.Hour)})
+	var terr *statemachine.TransitionError
+	if !errors.As(err, &terr) {
+		t.Fatalf("expected a statemachine.TransitionError for a stale approval, got %v", err)
+	}
+}
+
+// TestReviewService_MatchesLifecycle submits random review sequences through
+// the database and checks the stored status against the Lifecycle model.
+func TestReviewService_MatchesLifecycle(t *testing.T) {
+	dbConn := testutil.NewTestDB(t)
+	project := t.TempDir()
+	requestor := testutil.MakeSession(t, dbConn, testutil.WithProject(project))
+	reviewers := make([]*db.Session, 4)
+	for i := range reviewers {
+		reviewers[i] = testutil.MakeSession(t, dbConn, testutil.WithProject(project))
+	}
+
+	rapid.Check(t, func(rt *rapid.T) {
+		policy := rapid.SampledFrom(lifecyclePolicies).Draw(rt, "policy")
+		minApprovals := rapid.IntRange(1, 3).Draw(rt, "min_approvals")
+		req := testutil.MakeRequest(t, dbConn, requestor, testutil.WithMinApprovals(minApprovals))
+		rs := NewReviewService(dbConn, ReviewConfig{ConflictResolution: policy})
+		model := NewLifecycle(req, policy)
+
+		n := rapid.IntRange(1, len(reviewers)).Draw(rt, "reviews")
+		for i := 0; i < n; i++ {
+			decision := rapid.SampledFrom([]db.Decision{db.DecisionApprove, db.DecisionReject}).Draw(rt, "decision")
+			next, modelErr := model.Apply(LifecycleEvent{Kind: EventReview, Decision: decision, At: time.Now().UTC()})
+
+			_, err := rs.SubmitReview(ReviewOptions{
+				SessionID:  reviewers[i].ID,
+				SessionKey: reviewers[i].SessionKey,
+				RequestID:  req.ID,
+				Decision:   decision,
+			})
+			if (err == nil) != (modelErr == nil) {
+				rt.Fatalf("review %d (%s): service err=%v, model err=%v", i, decision, err, modelErr)
+			}
+			if err != nil {
+				break
+			}
+			model = next
+
+			got, err := dbConn.GetRequest(req.ID)
+			if err != nil {
+				rt.Fatalf("GetRequest: %v", err)
+			}
+			if got.Status != model.Status {
+				rt.Fatalf("review %d (%s) under %s: stored status %s, model %s", i, decision, policy, got.Status, model.Status)
+			}
+		}
+	})
+}
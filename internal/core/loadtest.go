@@ -0,0 +1,372 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// Load test operations, as reported in LoadTestReport.Ops.
+const (
+	LoadOpSubmit  = "submit"
+	LoadOpReview  = "review"
+	LoadOpExecute = "execute"
+)
+
+// loadTestMaxPending is how many of its own requests an agent lets wait
+// for review before it stops submitting and only reviews.
+const loadTestMaxPending = 3
+
+// LoadTestOptions configures RunLoadTest.
+type LoadTestOptions struct {
+	// ProjectPath is the project the agents work in; its .slb/state.db is
+	// created. Use a scratch directory: approved commands are run there.
+	ProjectPath string
+	// Agents is how many synthetic agents run at once (at least 2, so
+	// each request has someone else to review it).
+	Agents int
+	// Duration is how long the agents keep working.
+	Duration time.Duration
+	// Think is each agent's pause between steps; 0 runs flat out.
+	Think time.Duration
+}
+
+// LoadTestReport is what a load test measured.
+type LoadTestReport struct {
+	ProjectPath     string  `json:"project_path"`
+	Agents          int     `json:"agents"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	Submitted       int     `json:"submitted"`
+	Approved        int     `json:"approved"`
+	Executed        int     `json:"executed"`
+	// ExecutedPerSecond is end-to-end throughput: requests submitted,
+	// approved and run.
+	ExecutedPerSecond float64 `json:"executed_per_second"`
+	// Conflicts are races another agent won: reviewing a request already
+	// decided, or executing one already running. They are expected.
+	Conflicts int `json:"conflicts"`
+	// LockErrors are operations that failed because the database was
+	// locked; there should be none.
+	LockErrors int               `json:"lock_errors"`
+	Ops        []LoadTestOpStats `json:"ops"`
+	// Errors are the distinct failures, most frequent first.
+	Errors []LoadTestError `json:"errors,omitempty"`
+}
+
+// LoadTestOpStats is the latency of one kind of operation.
+type LoadTestOpStats struct {
+	Op     string  `json:"op"`
+	Count  int     `json:"count"`
+	Errors int     `json:"errors"`
+	P50MS  float64 `json:"p50_ms"`
+	P95MS  float64 `json:"p95_ms"`
+	P99MS  float64 `json:"p99_ms"`
+	MaxMS  float64 `json:"max_ms"`
+}
+
+// LoadTestError is a failure and how often it happened.
+type LoadTestError struct {
+	Op      string `json:"op"`
+	Message string `json:"message"`
+	Count   int    `json:"count"`
+}
+
+// loadRecorder collects every agent's samples and outcomes.
+type loadRecorder struct {
+	mu        sync.Mutex
+	samples   map[string][]time.Duration
+	errs      map[string]int
+	errMsgs   map[string]map[string]int
+	submitted int
+	approved  int
+	executed  int
+	conflicts int
+	locks     int
+}
+
+// RunLoadTest simulates a multi-agent day: Agents synthetic agents, each
+// with its own session and database connection as separate processes
+// would have, submit dangerous-tier requests, approve each other's and
+// execute their own approved ones until Duration has passed or ctx is
+// done.
+func RunLoadTest(ctx context.Context, opts LoadTestOptions) (*LoadTestReport, error) {
+	if opts.Agents < 2 {
+		return nil, fmt.Errorf("load test needs at least 2 agents, got %d", opts.Agents)
+	}
+	if opts.Duration <= 0 {
+		return nil, fmt.Errorf("load test duration must be positive")
+	}
+	dbPath := filepath.Join(opts.ProjectPath, ".slb", "state.db")
+	setup, err := db.OpenAndMigrate(dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("creating load test database: %w", err)
+	}
+	_ = setup.Close()
+
+	rec := &loadRecorder{
+		samples: make(map[string][]time.Duration),
+		errs:    make(map[string]int),
+		errMsgs: make(map[string]map[string]int),
+	}
+	ctx, cancel := context.WithTimeout(ctx, opts.Duration)
+	defer cancel()
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	errc := make(chan error, opts.Agents)
+	for i := range opts.Agents {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := runLoadAgent(ctx, opts, dbPath, i, rec); err != nil {
+				errc <- err
+			}
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+	close(errc)
+	if err := <-errc; err != nil {
+		return nil, err
+	}
+	return rec.report(opts, elapsed), nil
+}
+
+// runLoadAgent is one synthetic agent's working day.
+func runLoadAgent(ctx context.Context, opts LoadTestOptions, dbPath string, n int, rec *loadRecorder) error {
+	conn, err := db.Open(dbPath)
+	if err != nil {
+		return fmt.Errorf("agent %d: opening database: %w", n, err)
+	}
+	defer conn.Close()
+
+	session := &db.Session{
+		AgentName:   fmt.Sprintf("LoadAgent%02d", n+1),
+		Program:     "slb-loadtest",
+		Model:       "synthetic",
+		ProjectPath: opts.ProjectPath,
+	}
+	if err := conn.CreateSession(session); err != nil {
+		return fmt.Errorf("agent %d: creating session: %w", n, err)
+	}
+
+	config := DefaultRequestCreatorConfig()
+	config.AgentMailEnabled = false
+	limiter := NewRateLimiter(conn, RateLimitConfig{
+		MaxPendingPerSession: 1 << 20,
+		MaxRequestsPerMinute: 1 << 20,
+		Action:               RateLimitActionReject,
+	})
+	creator := NewRequestCreator(conn, limiter, nil, config)
+	reviews := NewReviewService(conn, DefaultReviewConfig())
+	executor := NewExecutor(conn, nil)
+	logDir := filepath.Join(opts.ProjectPath, ".slb", "logs")
+	rng := rand.New(rand.NewPCG(uint64(n), uint64(time.Now().UnixNano())))
+
+	for seq := 0; ctx.Err() == nil; seq++ {
+		mine, err := conn.ListRequestsBySession(session.ID)
+		if err != nil {
+			rec.record(LoadOpSubmit, 0, err)
+			continue
+		}
+		pending := 0
+		for _, req := range mine {
+			switch req.Status {
+			case db.StatusPending:
+				pending++
+			case db.StatusApproved:
+				rec.timed(LoadOpExecute, func() error {
+					// A command already approved runs to completion when
+					// the test ends.
+					_, err := executor.ExecuteApprovedRequest(context.WithoutCancel(ctx), ExecuteOptions{
+						RequestID:      req.ID,
+						SessionID:      session.ID,
+						LogDir:         logDir,
+						SuppressOutput: true,
+					})
+					return err
+				})
+			}
+		}
+
+		if pending < loadTestMaxPending {
+			rec.timed(LoadOpSubmit, func() error {
+				_, err := creator.CreateRequest(ctx, CreateRequestOptions{
+					SessionID: session.ID,
+					Command:   fmt.Sprintf("rm -rf ./loadtest-scratch/%s-%d", session.AgentName, seq),
+					Cwd:       opts.ProjectPath,
+					Shell:     true,
+					Justification: Justification{
+						Reason: "load test: clear a scratch directory",
+					},
+				})
+				return err
+			})
+		}
+
+		others, err := conn.ListPendingRequests(opts.ProjectPath)
+		if err != nil {
+			rec.record(LoadOpReview, 0, err)
+			continue
+		}
+		rng.Shuffle(len(others), func(i, j int) { others[i], others[j] = others[j], others[i] })
+		for _, req := range others {
+			if req.RequestorSessionID == session.ID {
+				continue
+			}
+			rec.timed(LoadOpReview, func() error {
+				_, err := reviews.SubmitReview(ReviewOptions{
+					SessionID:  session.ID,
+					SessionKey: session.SessionKey,
+					RequestID:  req.ID,
+					Decision:   db.DecisionApprove,
+					Comments:   "load test",
+				})
+				return err
+			})
+			break
+		}
+
+		if opts.Think > 0 {
+			select {
+			case <-ctx.Done():
+			case <-time.After(opts.Think):
+			}
+		}
+	}
+	_ = conn.EndSession(session.ID)
+	return nil
+}
+
+// timed runs fn and records how long it took and how it went.
+func (r *loadRecorder) timed(op string, fn func() error) {
+	start := time.Now()
+	err := fn()
+	r.record(op, time.Since(start), err)
+}
+
+func (r *loadRecorder) record(op string, took time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	switch {
+	case err == nil:
+		r.samples[op] = append(r.samples[op], took)
+		switch op {
+		case LoadOpSubmit:
+			r.submitted++
+		case LoadOpReview:
+			r.approved++
+		case LoadOpExecute:
+			r.executed++
+		}
+	case isLoadConflict(err):
+		r.conflicts++
+	default:
+		if isLockError(err) {
+			r.locks++
+		}
+		r.errs[op]++
+		if r.errMsgs[op] == nil {
+			r.errMsgs[op] = make(map[string]int)
+		}
+		r.errMsgs[op][err.Error()]++
+	}
+}
+
+// isLoadConflict reports whether err is a race another agent won.
+func isLoadConflict(err error) bool {
+	var versionErr *db.VersionConflictError
+	return errors.Is(err, ErrRequestNotPending) ||
+		errors.Is(err, ErrAlreadyReviewed) ||
+		errors.Is(err, ErrAlreadyExecuting) ||
+		errors.Is(err, ErrAlreadyExecuted) ||
+		errors.Is(err, db.ErrInvalidTransition) ||
+		errors.As(err, &versionErr)
+}
+
+// isLockError reports whether err is SQLite refusing a locked database.
+func isLockError(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "database is locked") ||
+		strings.Contains(msg, "database table is locked") ||
+		strings.Contains(msg, "SQLITE_BUSY")
+}
+
+func (r *loadRecorder) report(opts LoadTestOptions, elapsed time.Duration) *LoadTestReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rep := &LoadTestReport{
+		ProjectPath:     opts.ProjectPath,
+		Agents:          opts.Agents,
+		DurationSeconds: elapsed.Seconds(),
+		Submitted:       r.submitted,
+		Approved:        r.approved,
+		Executed:        r.executed,
+		Conflicts:       r.conflicts,
+		LockErrors:      r.locks,
+	}
+	if secs := elapsed.Seconds(); secs > 0 {
+		rep.ExecutedPerSecond = float64(r.executed) / secs
+	}
+	for _, op := range []string{LoadOpSubmit, LoadOpReview, LoadOpExecute} {
+		samples := r.samples[op]
+		sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+		stats := LoadTestOpStats{Op: op, Count: len(samples), Errors: r.errs[op]}
+		if len(samples) > 0 {
+			stats.P50MS = durationMS(nearestRank(samples, 50))
+			stats.P95MS = durationMS(nearestRank(samples, 95))
+			stats.P99MS = durationMS(nearestRank(samples, 99))
+			stats.MaxMS = durationMS(samples[len(samples)-1])
+		}
+		rep.Ops = append(rep.Ops, stats)
+		for msg, count := range r.errMsgs[op] {
+			rep.Errors = append(rep.Errors, LoadTestError{Op: op, Message: msg, Count: count})
+		}
+	}
+	sort.Slice(rep.Errors, func(i, j int) bool {
+		if rep.Errors[i].Count != rep.Errors[j].Count {
+			return rep.Errors[i].Count > rep.Errors[j].Count
+		}
+		return rep.Errors[i].Message < rep.Errors[j].Message
+	})
+	return rep
+}
+
+// nearestRank is the p-th percentile (0-100) of sorted samples.
+func nearestRank(sorted []time.Duration, p float64) time.Duration {
+	rank := int(p/100*float64(len(sorted)) + 0.5)
+	rank = max(1, min(rank, len(sorted)))
+	return sorted[rank-1]
+}
+
+func durationMS(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// Text renders the report for a terminal.
+func (r *LoadTestReport) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Load test: %d agents for %.1fs in %s\n\n", r.Agents, r.DurationSeconds, r.ProjectPath)
+	fmt.Fprintf(&b, "Submitted %d, approved %d, executed %d (%.1f/s end to end)\n", r.Submitted, r.Approved, r.Executed, r.ExecutedPerSecond)
+	fmt.Fprintf(&b, "Conflicts (races another agent won): %d\n", r.Conflicts)
+	fmt.Fprintf(&b, "Database lock errors: %d\n\n", r.LockErrors)
+	fmt.Fprintf(&b, "  %-8s %7s %7s %9s %9s %9s %9s\n", "OP", "COUNT", "ERRORS", "P50", "P95", "P99", "MAX")
+	for _, op := range r.Ops {
+		fmt.Fprintf(&b, "  %-8s %7d %7d %7.1fms %7.1fms %7.1fms %7.1fms\n", op.Op, op.Count, op.Errors, op.P50MS, op.P95MS, op.P99MS, op.MaxMS)
+	}
+	if len(r.Errors) > 0 {
+		b.WriteString("\nErrors:\n")
+		for _, e := range r.Errors {
+			fmt.Fprintf(&b, "  %dx %s: %s\n", e.Count, e.Op, e.Message)
+		}
+	}
+	return b.String()
+}
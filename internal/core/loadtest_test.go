@@ -0,0 +1,43 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRunLoadTest(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping load test in -short mode")
+	}
+	report, err := RunLoadTest(context.Background(), LoadTestOptions{
+		ProjectPath: t.TempDir(),
+		Agents:      4,
+		Duration:    1500 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("RunLoadTest: %v", err)
+	}
+	t.Logf("report: %+v", report)
+
+	if report.Submitted == 0 || report.Approved == 0 || report.Executed == 0 {
+		t.Errorf("agents should submit, approve and execute: %+v", report)
+	}
+	if report.LockErrors != 0 || len(report.Errors) != 0 {
+		t.Errorf("unexpected failures: %d lock errors, %+v", report.LockErrors, report.Errors)
+	}
+	if len(report.Ops) != 3 || report.Ops[2].Op != LoadOpExecute || report.Ops[2].Count != report.Executed {
+		t.Errorf("ops = %+v", report.Ops)
+	}
+	for _, op := range report.Ops {
+		if op.Count > 0 && (op.P50MS > op.P95MS || op.P95MS > op.P99MS || op.P99MS > op.MaxMS) {
+			t.Errorf("%s percentiles out of order: %+v", op.Op, op)
+		}
+	}
+}
+
+func TestRunLoadTest_NeedsTwoAgents(t *testing.T) {
+	if _, err := RunLoadTest(context.Background(), LoadTestOptions{ProjectPath: t.TempDir(), Agents: 1, Duration: time.Second}); err == nil {
+		t.Error("one agent has nobody to review its requests")
+	}
+}
@@ -55,9 +55,6 @@ var xargsPattern = regexp.MustCompile(`xargs\s+(.+)$`)
 // Compound command separators
 var compoundSeparators = regexp.MustCompile(`\s*(?:;|&&|\|\||&)\s*`)
 
-// Pipe detection
-var pipePattern = regexp.MustCompile(`\s*\|\s*`)
-
 // Subshell patterns: $(...) or `...` or (...)
 var subshellPattern = regexp.MustCompile("\\$\\([^)]+\\)|`[^`]+`|\\([^)]+\\)")
 
@@ -66,11 +63,31 @@ var envAssignPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*=`)
 // splitCompoundShellAware splits a command on compound separators (;, &&, ||, &)
 // while respecting shell quoting rules. Separators inside quotes are not split.
 func splitCompoundShellAware(cmd string) []string {
+	return splitCompound(cmd, true)
+}
+
+// splitCompound implements splitCompoundShellAware. backslashEscapes is false
+// for Windows command lines, where a backslash is a path separator.
+func splitCompound(cmd string, backslashEscapes bool) []string {
+	return splitShellAware(cmd, backslashEscapes, false)
+}
+
+// splitPipeline splits a segment on pipes (|) with the same quoting rules as
+// splitCompoundShellAware.
+func splitPipeline(seg string, backslashEscapes bool) []string {
+	return splitShellAware(seg, backslashEscapes, true)
+}
+
+// splitShellAware splits on compound separators, or on pipes when pipes is
+// set. Separators inside quotes, or inside parentheses opened mid-command
+// ($(...), fish (...) substitutions), are not split.
+func splitShellAware(cmd string, backslashEscapes, pipes bool) []string {
 	var segments []string
 	var current strings.Builder
 	inSingleQuote := false
 	inDoubleQuote := false
 	escaped := false
+	parenDepth := 0
 	runes := []rune(cmd)
 
 	for i := 0; i < len(runes); i++ {
@@ -83,7 +100,7 @@ func splitCompoundShellAware(cmd string) []string {
 			continue
 		}
 
-		if r == '\\' && !inSingleQuote {
+		if r == '\\' && backslashEscapes && !inSingleQuote {
 			current.WriteRune(r)
 			escaped = true
 			continue
@@ -104,6 +121,32 @@ func splitCompoundShellAware(cmd string) []string {
 
 		// Check for compound separators only when outside quotes
 		if !inSingleQuote && !inDoubleQuote {
+			// A parenthesis at the start of a segment is a subshell whose
+			// commands are split as usual; one opened mid-command is a
+			// substitution that belongs to this segment.
+			if r == '(' && (parenDepth > 0 || strings.TrimSpace(current.String()) != "") {
+				parenDepth++
+			} else if r == ')' && parenDepth > 0 {
+				parenDepth--
+			}
+			if parenDepth > 0 {
+				current.WriteRune(r)
+				continue
+			}
+
+			if pipes {
+				if r == '|' && (i+1 >= len(runes) || runes[i+1] != '|') && (i == 0 || runes[i-1] != '|') {
+					seg := strings.TrimSpace(current.String())
+					if seg != "" {
+						segments = append(segments, seg)
+					}
+					current.Reset()
+					continue
+				}
+				current.WriteRune(r)
+				continue
+			}
+
 			// Check for && or ||
 			if i+1 < len(runes) {
 				if (r == '&' && runes[i+1] == '&') || (r == '|' && runes[i+1] == '|') {
@@ -154,34 +197,27 @@ func NormalizeCommand(cmd string) *NormalizedCommand {
 		return result
 	}
 
+	// Multi-line scripts are normalized a statement at a time. A single
+	// statement with newlines only inside quotes is a plain command.
+	if strings.ContainsRune(cmd, '\n') {
+		if stmts := ScriptStatements(cmd); len(stmts) != 1 || stmts[0].Text != cmd {
+			return normalizeStatements(result, stmts)
+		}
+	}
+
 	// Check for subshells
 	result.HasSubshell = subshellPattern.MatchString(cmd)
 
 	// Split on compound separators using shell-aware parsing.
 	// We use proper tokenization to determine if separators are inside quotes.
-	segments := splitCompoundShellAware(cmd)
+	segments := splitCompound(cmd, !isWindowsCommandLine(cmd))
 	if len(segments) > 1 {
 		result.IsCompound = true
 	}
 
 	// Also check for pipes (not technically compound, but multiple commands)
 	for _, seg := range segments {
-		if pipePattern.MatchString(seg) {
-			result.IsCompound = true
-			// Split on pipes and add each segment
-			pipeParts := pipePattern.Split(seg, -1)
-			for _, part := range pipeParts {
-				part = strings.TrimSpace(part)
-				if part != "" {
-					result.Segments = append(result.Segments, part)
-				}
-			}
-		} else {
-			seg = strings.TrimSpace(seg)
-			if seg != "" {
-				result.Segments = append(result.Segments, seg)
-			}
-		}
+		result.addSegment(seg)
 	}
 
 	// Normalize each segment (strip wrappers with shell-aware parsing)
@@ -206,8 +242,80 @@ func NormalizeCommand(cmd string) *NormalizedCommand {
 	return result
 }
 
+// normalizeStatements merges the normalized statements of a script into
+// result, as segments of one compound command.
+func normalizeStatements(result *NormalizedCommand, stmts []ScriptStatement) *NormalizedCommand {
+	for _, st := range stmts {
+		n := NormalizeCommand(st.Text)
+		result.Segments = append(result.Segments, n.Segments...)
+		result.StrippedWrappers = append(result.StrippedWrappers, n.StrippedWrappers...)
+		result.HasSubshell = result.HasSubshell || n.HasSubshell
+		result.ParseError = result.ParseError || n.ParseError
+	}
+	result.IsCompound = len(result.Segments) > 1
+	if len(result.Segments) > 0 {
+		result.Primary = result.Segments[0]
+	}
+	return result
+}
+
+// addSegment splits seg on pipes and records the parts. PowerShell and cmd.exe
+// wrappers are unwrapped so their inner commands are classified individually,
+// and fish/zsh syntax is rewritten (see rewriteShellSyntax).
+func (r *NormalizedCommand) addSegment(seg string) {
+	if inner, wrapper, ok, decodeErr := unwrapWindowsShell(seg); ok {
+		r.StrippedWrappers = append(r.StrippedWrappers, wrapper)
+		if decodeErr {
+			r.ParseError = true
+			return
+		}
+		parts := splitCompound(inner, false)
+		if len(parts) > 1 {
+			r.IsCompound = true
+		}
+		for _, part := range parts {
+			r.addSegment(part)
+		}
+		return
+	}
+
+	windows := isWindowsCommandLine(seg)
+	var substitutions []string
+	if !windows {
+		seg, substitutions = rewriteShellSyntax(seg)
+	}
+
+	pipeParts := splitPipeline(seg, !windows)
+	if len(pipeParts) > 1 {
+		r.IsCompound = true
+		pipeParts = rewritePowerShellPipeline(pipeParts)
+	}
+	for _, part := range pipeParts {
+		if _, _, ok, _ := unwrapWindowsShell(part); ok && len(pipeParts) > 1 {
+			r.addSegment(part)
+			continue
+		}
+		r.Segments = append(r.Segments, part)
+	}
+
+	// Commands run by substitutions are classified as segments of their own.
+	for _, sub := range substitutions {
+		r.IsCompound = true
+		r.HasSubshell = true
+		for _, part := range splitCompoundShellAware(sub) {
+			r.addSegment(part)
+		}
+	}
+}
+
 // normalizeSegment strips wrappers using a shell-aware tokenizer.
 func normalizeSegment(seg string) (string, []string, bool) {
+	// Windows deletions use backslash paths that the POSIX tokenizer would
+	// mangle, so they are rewritten before it runs.
+	if canonical, ok := normalizeWindowsSegment(seg); ok {
+		return canonical, nil, false
+	}
+
 	// First check for shell -c 'command' pattern and extract inner command
 	if match := shellCPattern.FindStringSubmatch(seg); match != nil {
 		innerCmd := match[2]
@@ -241,7 +349,7 @@ func normalizeSegment(seg string) (string, []string, bool) {
 			continue
 		}
 
-		if isWrapper(tok) {
+		if isWrapper(tok) || isFishKeyword(tok) {
 			stripped = append(stripped, tok)
 			i++
 			continue
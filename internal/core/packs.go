@@ -0,0 +1,194 @@
+// Package core implements optional, namespaced pattern packs.
+package core
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// PatternSourcePack is the Source of patterns contributed by a pack.
+const PatternSourcePack = "pack"
+
+//go:embed packs/*.json
+var packFS embed.FS
+
+// PatternPack is an optional, named set of patterns for one ecosystem
+// (e.g. kubernetes). Packs only add risk patterns; they never mark commands
+// safe, so enabling one can only tighten review.
+type PatternPack struct {
+	Name        string
+	Description string
+	Patterns    []PackPattern
+}
+
+// PackPattern is a single pattern within a pack.
+type PackPattern struct {
+	Tier        RiskTier
+	Pattern     string
+	Description string
+}
+
+// packFile is the embedded JSON layout of a pack.
+type packFile struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Patterns    map[string][]struct {
+		Pattern     string `json:"pattern"`
+		Description string `json:"description"`
+	} `json:"patterns"`
+}
+
+var (
+	packsOnce sync.Once
+	packs     map[string]*PatternPack
+	packsErr  error
+)
+
+// loadPacks parses the embedded pack files once.
+func loadPacks() (map[string]*PatternPack, error) {
+	packsOnce.Do(func() {
+		packs, packsErr = parsePacks()
+	})
+	return packs, packsErr
+}
+
+func parsePacks() (map[string]*PatternPack, error) {
+	entries, err := packFS.ReadDir("packs")
+	if err != nil {
+		return nil, fmt.Errorf("reading pattern packs: %w", err)
+	}
+
+	out := make(map[string]*PatternPack, len(entries))
+	for _, entry := range entries {
+		data, err := packFS.ReadFile(path.Join("packs", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("reading pattern pack %s: %w", entry.Name(), err)
+		}
+		var f packFile
+		if err := json.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("parsing pattern pack %s: %w", entry.Name(), err)
+		}
+
+		pack := &PatternPack{Name: f.Name, Description: f.Description}
+		// Walk tiers in classification order so listings are stable.
+		for _, tier := range []RiskTier{RiskTierCritical, RiskTierDangerous, RiskTierCaution} {
+			for _, p := range f.Patterns[string(tier)] {
+				if _, err := regexp.Compile("(?i)" + p.Pattern); err != nil {
+					return nil, fmt.Errorf("pattern pack %s: invalid pattern %q: %w", f.Name, p.Pattern, err)
+				}
+				pack.Patterns = append(pack.Patterns, PackPattern{Tier: tier, Pattern: p.Pattern, Description: p.Description})
+			}
+			delete(f.Patterns, string(tier))
+		}
+		for tier := range f.Patterns {
+			return nil, fmt.Errorf("pattern pack %s: unsupported tier %q", f.Name, tier)
+		}
+		out[pack.Name] = pack
+	}
+	return out, nil
+}
+
+// PatternPacks returns every available pack sorted by name.
+func PatternPacks() ([]*PatternPack, error) {
+	all, err := loadPacks()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*PatternPack, 0, len(all))
+	for _, p := range all {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// LookupPatternPack returns the named pack.
+func LookupPatternPack(name string) (*PatternPack, error) {
+	all, err := loadPacks()
+	if err != nil {
+		return nil, err
+	}
+	pack, ok := all[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown pattern pack %q", name)
+	}
+	return pack, nil
+}
+
+// EnablePack adds a pack's patterns to the engine. Enabling an already
+// enabled pack is a no-op. Patterns already present in the same tier (e.g.
+// added as custom patterns) are not duplicated.
+func (e *PatternEngine) EnablePack(name string) error {
+	pack, err := LookupPatternPack(name)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.packs[name] {
+		return nil
+	}
+	for _, pp := range pack.Patterns {
+		list := e.tierListLocked(pp.Tier)
+		dup := false
+		for _, existing := range *list {
+			if existing.Pattern == pp.Pattern {
+				dup = true
+				break
+			}
+		}
+		if dup {
+			continue
+		}
+		*list = append(*list, &Pattern{
+			Tier:        pp.Tier,
+			Pattern:     pp.Pattern,
+			Compiled:    regexp.MustCompile("(?i)" + pp.Pattern),
+			Description: pp.Description,
+			Source:      PatternSourcePack,
+			Pack:        name,
+		})
+	}
+	if e.packs == nil {
+		e.packs = make(map[string]bool)
+	}
+	e.packs[name] = true
+	return nil
+}
+
+// EnabledPacks returns the names of the packs enabled on the engine, sorted.
+func (e *PatternEngine) EnabledPacks() []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.enabledPacksLocked()
+}
+
+func (e *PatternEngine) enabledPacksLocked() []string {
+	names := make([]string, 0, len(e.packs))
+	for name := range e.packs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// tierListLocked returns the pattern list for a tier (caller must hold the lock).
+func (e *PatternEngine) tierListLocked(tier RiskTier) *[]*Pattern {
+	switch tier {
+	case RiskTierCritical:
+		return &e.critical
+	case RiskTierDangerous:
+		return &e.dangerous
+	case RiskTierCaution:
+		return &e.caution
+	default:
+		return &e.safe
+	}
+}
@@ -0,0 +1,98 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPatternPacks_AllParse(t *testing.T) {
+	packs, err := PatternPacks()
+	if err != nil {
+		t.Fatalf("PatternPacks failed: %v", err)
+	}
+
+	want := []string{"database", "deploy", "kubernetes", "nodejs", "terraform", "windows-powershell"}
+	if len(packs) != len(want) {
+		t.Fatalf("expected %d packs, got %d", len(want), len(packs))
+	}
+	for i, p := range packs {
+		if p.Name != want[i] {
+			t.Errorf("pack %d = %q, want %q", i, p.Name, want[i])
+		}
+		if p.Description == "" || len(p.Patterns) == 0 {
+			t.Errorf("pack %q missing description or patterns", p.Name)
+		}
+		for _, pp := range p.Patterns {
+			switch pp.Tier {
+			case RiskTierCritical, RiskTierDangerous, RiskTierCaution:
+			default:
+				t.Errorf("pack %q marks %q safe; packs may only tighten review", p.Name, pp.Pattern)
+			}
+		}
+	}
+
+	if _, err := LookupPatternPack("no-such-pack"); err == nil {
+		t.Fatal("expected error for unknown pack")
+	}
+}
+
+func TestEnablePack_ClassifiesAndIsIdempotent(t *testing.T) {
+	engine := NewPatternEngine()
+
+	if got := engine.ClassifyCommand("kubectl drain node1", ""); got.Tier == RiskTierDangerous {
+		t.Fatalf("precondition: kubectl drain already dangerous without pack")
+	}
+
+	if err := engine.EnablePack("kubernetes"); err != nil {
+		t.Fatalf("EnablePack failed: %v", err)
+	}
+	before := len(engine.ListPatterns(RiskTierDangerous))
+	if err := engine.EnablePack("kubernetes"); err != nil {
+		t.Fatalf("second EnablePack failed: %v", err)
+	}
+	if after := len(engine.ListPatterns(RiskTierDangerous)); after != before {
+		t.Fatalf("re-enabling pack changed pattern count: %d -> %d", before, after)
+	}
+
+	if got := engine.ClassifyCommand("kubectl drain node1", ""); got.Tier != RiskTierDangerous {
+		t.Fatalf("expected dangerous with kubernetes pack, got %s", got.Tier)
+	}
+	if got := engine.EnabledPacks(); len(got) != 1 || got[0] != "kubernetes" {
+		t.Fatalf("EnabledPacks = %v", got)
+	}
+
+	if err := engine.EnablePack("no-such-pack"); err == nil {
+		t.Fatal("expected error enabling unknown pack")
+	}
+}
+
+func TestEnablePack_ExportProvenance(t *testing.T) {
+	engine := NewPatternEngine()
+	if err := engine.EnablePack("terraform"); err != nil {
+		t.Fatalf("EnablePack failed: %v", err)
+	}
+
+	export := engine.Export()
+	if len(export.Metadata.Packs) != 1 || export.Metadata.Packs[0] != "terraform" {
+		t.Fatalf("expected terraform in metadata packs, got %v", export.Metadata.Packs)
+	}
+	found := false
+	for _, tier := range export.Tiers {
+		for _, p := range tier.Patterns {
+			if p.Pack == "terraform" {
+				if p.Source != PatternSourcePack {
+					t.Errorf("pack pattern %q has source %q", p.Pattern, p.Source)
+				}
+				found = true
+			}
+		}
+	}
+	if !found {
+		t.Fatal("expected exported patterns tagged with pack terraform")
+	}
+
+	hook := engine.ExportClaudeHook()
+	if !strings.Contains(hook, "# Packs: terraform") || !strings.Contains(hook, "# pack: terraform") {
+		t.Fatalf("expected pack provenance in hook export:\n%s", hook)
+	}
+}
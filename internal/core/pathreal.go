@@ -0,0 +1,320 @@
+// Package core detects path arguments that resolve outside their apparent
+// location through symlinks or mounts.
+package core
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mattn/go-shellwords"
+)
+
+// PathEscape describes a path argument whose real location differs from how
+// it reads, e.g. ./data being a symlink to /etc.
+type PathEscape struct {
+	// Path is the argument as written.
+	Path string
+	// RealPath is the resolved location after following symlinks.
+	RealPath string
+	// Reason is "system location" or "network mount".
+	Reason string
+	// MountPoint and FSType are set for network mounts.
+	MountPoint string
+	FSType     string
+}
+
+// Path escape reasons.
+const (
+	PathEscapeSystem  = "system location"
+	PathEscapeNetwork = "network mount"
+)
+
+// systemLocations are real paths that upgrade the tier when reached through
+// a symlink. Children of these count too, except for the temp directories
+// below.
+var systemLocations = []string{
+	"/bin", "/boot", "/dev", "/etc", "/lib", "/lib64", "/proc", "/root",
+	"/sbin", "/srv", "/sys", "/usr", "/var",
+	"/System", "/Library", "/private/etc", "/private/var",
+}
+
+var systemLocationExceptions = []string{"/tmp", "/var/tmp", "/private/tmp", "/private/var/tmp", "/private/var/folders"}
+
+// sensitiveHomeDirs are directories under $HOME treated as system locations.
+var sensitiveHomeDirs = []string{".ssh", ".gnupg", ".aws", ".kube", ".config/gcloud"}
+
+// networkFSTypes are filesystem types treated as network mounts.
+var networkFSTypes = map[string]bool{
+	"nfs": true, "nfs4": true, "cifs": true, "smb3": true, "smbfs": true,
+	"afs": true, "9p": true, "ceph": true, "glusterfs": true, "lustre": true,
+	"gpfs": true, "beegfs": true, "davfs": true, "fuse.sshfs": true,
+	"sshfs": true, "fuse.glusterfs": true, "fuse.rclone": true, "fuse.s3fs": true,
+}
+
+// mountTablePath is the mount table consulted for network mounts. Systems
+// without it skip network mount detection.
+var mountTablePath = "/proc/self/mounts"
+
+type mountEntry struct {
+	point  string
+	fsType string
+}
+
+// applyPathEscapes upgrades a matched tier by one level when a path argument
+// resolves (through symlinks or mounts) to a system location or a network
+// mount, and records why. Commands that matched no pattern are left alone:
+// reading through a symlink is not risky by itself.
+func applyPathEscapes(res *MatchResult, normalized *NormalizedCommand, cwd string) *MatchResult {
+	if cwd == "" || res.Tier == "" {
+		return res
+	}
+
+	escapes := findPathEscapes(normalized.Segments, cwd)
+	if len(escapes) == 0 {
+		return res
+	}
+	res.PathEscapes = escapes
+	for _, esc := range escapes {
+		note := fmt.Sprintf("%s resolves to %s (%s)", esc.Path, esc.RealPath, esc.Reason)
+		if esc.Reason == PathEscapeNetwork {
+			note = fmt.Sprintf("%s resolves to %s (%s %s at %s)", esc.Path, esc.RealPath, esc.FSType, esc.Reason, esc.MountPoint)
+		}
+		res.Explanation = append(res.Explanation, note)
+	}
+
+	upgraded := upgradeTier(res.Tier)
+	if upgraded != res.Tier {
+		res.Explanation = append(res.Explanation, fmt.Sprintf("tier upgraded from %s to %s", res.Tier, upgraded))
+		res.Tier = upgraded
+		res.MinApprovals = tierApprovals(res.Tier)
+		res.NeedsApproval = true
+		res.IsSafe = false
+	}
+	return res
+}
+
+// findPathEscapes inspects the path arguments of each segment.
+func findPathEscapes(segments []string, cwd string) []PathEscape {
+	home, _ := os.UserHomeDir()
+	realCwd := realPath(cwd)
+	var mounts []mountEntry
+	mountsLoaded := false
+	cwdMount := ""
+
+	seen := make(map[string]bool)
+	var escapes []PathEscape
+	for _, seg := range segments {
+		for _, arg := range pathArguments(seg, cwd, home) {
+			if seen[arg] {
+				continue
+			}
+			seen[arg] = true
+
+			lexical := cleanPathToken(arg, cwd, home)
+			if !filepath.IsAbs(lexical) {
+				lexical = filepath.Join(cwd, lexical)
+			}
+			real := realPath(lexical)
+
+			// Only links that lead out of the project count; paths written as
+			// system paths are already covered by the patterns.
+			inProject := isWithin(lexical, cwd) || isWithin(lexical, realCwd)
+			if real != lexical && isSystemLocation(real, home) && !isWithin(real, realCwd) &&
+				(inProject || !isSystemLocation(lexical, home)) {
+				escapes = append(escapes, PathEscape{Path: arg, RealPath: real, Reason: PathEscapeSystem})
+				continue
+			}
+
+			if !mountsLoaded {
+				mounts = readMountTable(mountTablePath)
+				cwdMount = mountFor(realCwd, mounts).point
+				mountsLoaded = true
+			}
+			if m := mountFor(real, mounts); networkFSTypes[m.fsType] && m.point != cwdMount {
+				escapes = append(escapes, PathEscape{
+					Path: arg, RealPath: real, Reason: PathEscapeNetwork,
+					MountPoint: m.point, FSType: m.fsType,
+				})
+			}
+		}
+	}
+	return escapes
+}
+
+// ResolvedPath is a path argument of a command and where it really points.
+type ResolvedPath struct {
+	// Path is the argument as written.
+	Path string `json:"path"`
+	// RealPath is the absolute location after following symlinks.
+	RealPath string `json:"real_path"`
+	// InProject reports whether RealPath is inside cwd.
+	InProject bool `json:"in_project"`
+}
+
+// ResolvePaths lists every path argument of command resolved against cwd,
+// in order of appearance. Unlike PathEscapes it includes ordinary paths; it
+// is meant for showing reviewers what a command touches.
+func ResolvePaths(command, cwd string) []ResolvedPath {
+	if cwd == "" {
+		return nil
+	}
+	home, _ := os.UserHomeDir()
+	realCwd := realPath(cwd)
+
+	seen := make(map[string]bool)
+	var resolved []ResolvedPath
+	for _, seg := range NormalizeCommand(command).Segments {
+		for _, arg := range pathArguments(seg, cwd, home) {
+			if seen[arg] {
+				continue
+			}
+			seen[arg] = true
+
+			lexical := cleanPathToken(arg, cwd, home)
+			if !filepath.IsAbs(lexical) {
+				lexical = filepath.Join(cwd, lexical)
+			}
+			real := realPath(lexical)
+			resolved = append(resolved, ResolvedPath{
+				Path:      arg,
+				RealPath:  real,
+				InProject: isWithin(real, realCwd) || isWithin(real, cwd),
+			})
+		}
+	}
+	return resolved
+}
+
+// pathArguments returns the arguments of a segment that name paths: those
+// written as paths (./x, ../x, a/b, ~/x, /x) and bare names that exist in
+// cwd. Flags and the command name are skipped.
+func pathArguments(seg, cwd, home string) []string {
+	parser := shellwords.NewParser()
+	parser.ParseEnv = false
+	parser.ParseBacktick = false
+	tokens, err := parser.Parse(seg)
+	if err != nil {
+		tokens = strings.Fields(seg)
+	}
+
+	var args []string
+	for i, tok := range tokens {
+		if i == 0 || tok == "" || strings.HasPrefix(tok, "-") || strings.ContainsAny(tok, "*?$`") {
+			continue
+		}
+		if strings.Contains(tok, "/") || tok == "." || tok == ".." || tok == "~" {
+			args = append(args, tok)
+			continue
+		}
+		if _, err := os.Lstat(filepath.Join(cwd, tok)); err == nil {
+			args = append(args, tok)
+		}
+	}
+	return args
+}
+
+// realPath follows symlinks in p. When p does not exist, the longest
+// existing parent is resolved and the remainder appended.
+func realPath(p string) string {
+	p = filepath.Clean(p)
+	var rest []string
+	for {
+		if resolved, err := filepath.EvalSymlinks(p); err == nil {
+			return filepath.Join(append([]string{resolved}, rest...)...)
+		}
+		parent := filepath.Dir(p)
+		if parent == p {
+			return filepath.Join(append([]string{p}, rest...)...)
+		}
+		rest = append([]string{filepath.Base(p)}, rest...)
+		p = parent
+	}
+}
+
+func isSystemLocation(p, home string) bool {
+	if p == "/" {
+		return true
+	}
+	for _, ex := range systemLocationExceptions {
+		if isWithin(p, ex) {
+			return false
+		}
+	}
+	for _, loc := range systemLocations {
+		if isWithin(p, loc) {
+			return true
+		}
+	}
+	if home != "" {
+		if p == filepath.Clean(home) {
+			return true
+		}
+		for _, dir := range sensitiveHomeDirs {
+			if isWithin(p, filepath.Join(home, dir)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isWithin reports whether p is dir or inside it.
+func isWithin(p, dir string) bool {
+	return p == dir || strings.HasPrefix(p, dir+string(filepath.Separator))
+}
+
+// readMountTable parses a /proc/self/mounts style table. Unreadable tables
+// yield no entries.
+func readMountTable(path string) []mountEntry {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var mounts []mountEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 {
+			continue
+		}
+		mounts = append(mounts, mountEntry{point: unescapeMountField(fields[1]), fsType: fields[2]})
+	}
+	return mounts
+}
+
+// unescapeMountField decodes the octal escapes (\040 for space) used in
+// mount tables.
+func unescapeMountField(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) {
+			var c byte
+			if _, err := fmt.Sscanf(s[i+1:i+4], "%03o", &c); err == nil {
+				b.WriteByte(c)
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// mountFor returns the mount with the longest mount point containing p.
+func mountFor(p string, mounts []mountEntry) mountEntry {
+	var best mountEntry
+	for _, m := range mounts {
+		if (m.point == "/" || isWithin(p, m.point)) && len(m.point) >= len(best.point) {
+			best = m
+		}
+	}
+	return best
+}
@@ -0,0 +1,152 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// symlinkOrSkip creates a symlink or skips on platforms without support.
+func symlinkOrSkip(t *testing.T, target, link string) {
+	t.Helper()
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+}
+
+func TestClassifyCommand_SymlinkToSystemLocation(t *testing.T) {
+	engine := NewPatternEngine()
+	project := t.TempDir()
+	symlinkOrSkip(t, "/etc", filepath.Join(project, "data"))
+	if err := os.Mkdir(filepath.Join(project, "build"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	result := engine.ClassifyCommand("rm -rf ./data", project)
+	if result.Tier != RiskTierCritical {
+		t.Fatalf("expected dangerous rm through a symlink to /etc to be upgraded to critical, got %s", result.Tier)
+	}
+	if len(result.PathEscapes) != 1 || result.PathEscapes[0].RealPath != "/etc" || result.PathEscapes[0].Reason != PathEscapeSystem {
+		t.Fatalf("unexpected path escapes: %+v", result.PathEscapes)
+	}
+	if len(result.Explanation) == 0 || !strings.Contains(result.Explanation[0], "./data resolves to /etc") {
+		t.Fatalf("expected explanation to note the real path, got %v", result.Explanation)
+	}
+
+	// Bare names that exist in the project are checked too.
+	if got := engine.ClassifyCommand("rm -rf data", project); got.Tier != RiskTierCritical {
+		t.Errorf("expected bare symlinked name to be upgraded, got %s", got.Tier)
+	}
+	// Paths below the link resolve through it.
+	if got := engine.ClassifyCommand("rm -r ./data/ssh", project); got.Tier != RiskTierCritical {
+		t.Errorf("expected path below the link to be upgraded, got %s", got.Tier)
+	}
+
+	// Ordinary project directories are unaffected.
+	plain := engine.ClassifyCommand("rm -rf ./build", project)
+	if plain.Tier != RiskTierDangerous || len(plain.Explanation) != 0 {
+		t.Errorf("expected plain directory to stay dangerous without notes, got %s %v", plain.Tier, plain.Explanation)
+	}
+	// Commands that match no pattern are not upgraded.
+	if got := engine.ClassifyCommand("cat ./data/hostname", project); got.Tier != "" {
+		t.Errorf("expected read through symlink to stay unclassified, got %s", got.Tier)
+	}
+	// Without a cwd nothing is resolved.
+	if got := engine.ClassifyCommand("rm -rf ./data", ""); got.Tier != RiskTierDangerous {
+		t.Errorf("expected no resolution without cwd, got %s", got.Tier)
+	}
+}
+
+func TestClassifyCommand_SymlinkWithinProject(t *testing.T) {
+	engine := NewPatternEngine()
+	project := t.TempDir()
+	if err := os.Mkdir(filepath.Join(project, "real"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	symlinkOrSkip(t, filepath.Join(project, "real"), filepath.Join(project, "alias"))
+
+	result := engine.ClassifyCommand("rm -rf ./alias", project)
+	if result.Tier != RiskTierDangerous || len(result.PathEscapes) != 0 {
+		t.Fatalf("expected link inside the project not to upgrade, got %s %+v", result.Tier, result.PathEscapes)
+	}
+}
+
+func TestClassifyCommand_NetworkMount(t *testing.T) {
+	engine := NewPatternEngine()
+	project := t.TempDir()
+	share := t.TempDir()
+	symlinkOrSkip(t, share, filepath.Join(project, "share"))
+
+	realShare := realPath(share)
+	table := filepath.Join(t.TempDir(), "mounts")
+	content := "rootfs / ext4 rw 0 0\n" +
+		"server:/export " + strings.ReplaceAll(realShare, " ", `\040`) + " nfs4 rw 0 0\n"
+	if err := os.WriteFile(table, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	prev := mountTablePath
+	mountTablePath = table
+	t.Cleanup(func() { mountTablePath = prev })
+
+	result := engine.ClassifyCommand("rm -rf ./share/old", project)
+	if result.Tier != RiskTierCritical {
+		t.Fatalf("expected rm on a network mount to be upgraded, got %s", result.Tier)
+	}
+	if len(result.PathEscapes) != 1 {
+		t.Fatalf("expected one path escape, got %+v", result.PathEscapes)
+	}
+	esc := result.PathEscapes[0]
+	if esc.Reason != PathEscapeNetwork || esc.FSType != "nfs4" || esc.MountPoint != realShare {
+		t.Fatalf("unexpected escape: %+v", esc)
+	}
+
+	// A project that itself lives on the network mount is not flagged.
+	if got := engine.ClassifyCommand("rm -rf ./old", realShare); len(got.PathEscapes) != 0 {
+		t.Errorf("expected no escape within the project's own mount, got %+v", got.PathEscapes)
+	}
+}
+
+func TestRealPath_NonexistentTail(t *testing.T) {
+	dir := t.TempDir()
+	symlinkOrSkip(t, "/etc", filepath.Join(dir, "link"))
+
+	if got := realPath(filepath.Join(dir, "link", "missing", "file")); got != "/etc/missing/file" {
+		t.Fatalf("realPath = %q, want /etc/missing/file", got)
+	}
+}
+
+func TestUnescapeMountField(t *testing.T) {
+	if got := unescapeMountField(`/mnt/my\040share`); got != "/mnt/my share" {
+		t.Fatalf("unescapeMountField = %q", got)
+	}
+}
+
+func TestResolvePaths_Arguments(t *testing.T) {
+	project := t.TempDir()
+	symlinkOrSkip(t, "/etc", filepath.Join(project, "data"))
+	if err := os.Mkdir(filepath.Join(project, "build"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	got := ResolvePaths("rm -rf build ./data && cp -r ./build/out /srv/www", project)
+	realProject := realPath(project)
+	want := []ResolvedPath{
+		{Path: "build", RealPath: filepath.Join(realProject, "build"), InProject: true},
+		{Path: "./data", RealPath: "/etc", InProject: false},
+		{Path: "./build/out", RealPath: filepath.Join(realProject, "build", "out"), InProject: true},
+		{Path: "/srv/www", RealPath: realPath("/srv/www"), InProject: false},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d paths, got %+v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("path %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if got := ResolvePaths("rm -rf ./build", ""); got != nil {
+		t.Errorf("expected nothing without a cwd, got %+v", got)
+	}
+}
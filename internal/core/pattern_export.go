@@ -0,0 +1,273 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp/syntax"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// exportTier is one tier's patterns in export order.
+type exportTier struct {
+	name      string
+	approvals int
+	patterns  []*Pattern
+}
+
+// exportTiersLocked returns the tiers in safe, caution, dangerous, critical
+// order, each sorted by pattern for deterministic output (caller must hold
+// lock).
+func (e *PatternEngine) exportTiersLocked() []exportTier {
+	tiers := []exportTier{
+		{"safe", 0, e.safe},
+		{"caution", 0, e.caution},
+		{"dangerous", 1, e.dangerous},
+		{"critical", 2, e.critical},
+	}
+	for i := range tiers {
+		sorted := make([]*Pattern, len(tiers[i].patterns))
+		copy(sorted, tiers[i].patterns)
+		sort.Slice(sorted, func(a, b int) bool {
+			return sorted[a].Pattern < sorted[b].Pattern
+		})
+		tiers[i].patterns = sorted
+	}
+	return tiers
+}
+
+// writeExportHeaderLocked writes the comment header shared by the generated
+// exports, including the pattern hash used to detect stale copies (caller
+// must hold lock).
+func (e *PatternEngine) writeExportHeaderLocked(sb *strings.Builder, format string) {
+	sb.WriteString(fmt.Sprintf("# Auto-generated by: slb patterns export --format=%s\n", format))
+	sb.WriteString(fmt.Sprintf("# Generated: %s\n", time.Now().UTC().Format(time.RFC3339)))
+	sb.WriteString(fmt.Sprintf("# SHA256: %s\n", e.computeHashLocked()))
+	if len(e.packs) > 0 {
+		sb.WriteString(fmt.Sprintf("# Packs: %s\n", strings.Join(e.enabledPacksLocked(), ", ")))
+	}
+	sb.WriteString(fmt.Sprintf("# DO NOT EDIT - regenerate with: slb patterns export --format=%s\n", format))
+	sb.WriteString("\n")
+}
+
+// ExportRego returns patterns as an OPA/Rego policy (package slb.patterns)
+// that classifies input.command the way the hook export does: tier,
+// min_approvals, needs_approval and a deny set for pipelines to gate on.
+// OPA uses the same RE2 syntax as SLB, so patterns are copied verbatim.
+func (e *PatternEngine) ExportRego() string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	var sb strings.Builder
+	e.writeExportHeaderLocked(&sb, "rego")
+	sb.WriteString("package slb.patterns\n\n")
+	sb.WriteString("import rego.v1\n\n")
+
+	for _, tier := range e.exportTiersLocked() {
+		sb.WriteString(fmt.Sprintf("# %s tier: %d patterns\n", strings.ToUpper(tier.name), len(tier.patterns)))
+		if len(tier.patterns) == 0 {
+			sb.WriteString(fmt.Sprintf("%s_patterns := []\n\n", tier.name))
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("%s_patterns := [\n", tier.name))
+		for _, p := range tier.patterns {
+			if p.Pack != "" {
+				sb.WriteString(fmt.Sprintf("\t# pack: %s\n", p.Pack))
+			}
+			sb.WriteString(fmt.Sprintf("\t%s,\n", regoString(p.Pattern)))
+		}
+		sb.WriteString("]\n\n")
+	}
+
+	sb.WriteString(`command := trim_space(input.command)
+
+# Tiers are checked in order: safe -> critical -> dangerous -> caution.
+# regex.match is unanchored, like SLB's classifier.
+default tier := "unknown"
+
+tier := "safe" if {
+	matches_any(safe_patterns)
+} else := "critical" if {
+	matches_any(critical_patterns)
+} else := "dangerous" if {
+	matches_any(dangerous_patterns)
+} else := "caution" if {
+	matches_any(caution_patterns)
+}
+
+matches_any(patterns) if {
+	some p in patterns
+	regex.match(concat("", ["(?i)", p]), command)
+}
+
+min_approvals := {"safe": 0, "caution": 0, "dangerous": 1, "critical": 2, "unknown": 0}[tier]
+
+needs_approval if tier in {"caution", "dangerous", "critical"}
+
+deny contains msg if {
+	tier in {"dangerous", "critical"}
+	msg := sprintf("%s: %q requires %d approval(s); submit it with 'slb request'", [upper(tier), command, min_approvals])
+}
+`)
+
+	return sb.String()
+}
+
+// regoString quotes a pattern for Rego, preferring a raw string so
+// backslashes stay readable.
+func regoString(s string) string {
+	if !strings.Contains(s, "`") {
+		return "`" + s + "`"
+	}
+	quoted, _ := json.Marshal(s) //nolint:errcheck // strings always marshal
+	return string(quoted)
+}
+
+// semgrepRule is one rule of a Semgrep config.
+type semgrepRule struct {
+	ID           string         `yaml:"id"`
+	Message      string         `yaml:"message"`
+	Severity     string         `yaml:"severity"`
+	Languages    []string       `yaml:"languages"`
+	PatternRegex string         `yaml:"pattern-regex"`
+	Metadata     map[string]any `yaml:"metadata"`
+}
+
+// ExportSemgrep returns patterns as Semgrep rules for scanning scripts,
+// Makefiles and CI configs in a repo. Each caution, dangerous and critical
+// pattern becomes a generic-language pattern-regex rule (safe patterns
+// produce no findings and are skipped). A leading ^ is widened to allow
+// indentation, since commands in scripts rarely start at column 0.
+//
+// Semgrep runs pattern-regex through PCRE, not RE2. Patterns are copied
+// as written, so each one is checked by semgrepRegex first; patterns that
+// PCRE would reject or read differently are left out, listed as comments
+// at the top of the file and returned as warnings. The remaining gap is
+// \s, which PCRE widens to include vertical tab.
+func (e *PatternEngine) ExportSemgrep() (string, []string, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	severities := map[string]string{"caution": "INFO", "dangerous": "WARNING", "critical": "ERROR"}
+	var rules []semgrepRule
+	var warnings []string
+	for _, tier := range e.exportTiersLocked() {
+		severity, ok := severities[tier.name]
+		if !ok {
+			continue
+		}
+		for i, p := range tier.patterns {
+			message := fmt.Sprintf("%s command: needs %d approval(s) through slb", strings.ToUpper(tier.name), tier.approvals)
+			if tier.approvals == 0 {
+				message = fmt.Sprintf("%s command: reviewed by slb before it runs", strings.ToUpper(tier.name))
+			}
+			if p.Description != "" {
+				message = p.Description + " (" + message + ")"
+			}
+			metadata := map[string]any{
+				"slb_tier":      tier.name,
+				"slb_pattern":   p.Pattern,
+				"source":        p.Source,
+				"min_approvals": tier.approvals,
+			}
+			if p.Pack != "" {
+				metadata["pack"] = p.Pack
+			}
+			id := fmt.Sprintf("slb-%s-%03d", tier.name, i+1)
+			regex, err := semgrepRegex(p.Pattern)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("skipped %s %q: %v", id, p.Pattern, err))
+				continue
+			}
+			rules = append(rules, semgrepRule{
+				ID:           id,
+				Message:      message,
+				Severity:     severity,
+				Languages:    []string{"generic"},
+				PatternRegex: regex,
+				Metadata:     metadata,
+			})
+		}
+	}
+
+	var body strings.Builder
+	enc := yaml.NewEncoder(&body)
+	enc.SetIndent(2)
+	if err := enc.Encode(map[string]any{"rules": rules}); err != nil {
+		return "", nil, fmt.Errorf("encoding semgrep rules: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return "", nil, fmt.Errorf("encoding semgrep rules: %w", err)
+	}
+
+	var sb strings.Builder
+	e.writeExportHeaderLocked(&sb, "semgrep")
+	if len(warnings) > 0 {
+		sb.WriteString("# Not portable to Semgrep's PCRE:\n")
+		for _, w := range warnings {
+			sb.WriteString("#   " + w + "\n")
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString(body.String())
+	return sb.String(), warnings, nil
+}
+
+// semgrepRegex turns an SLB pattern into a Semgrep pattern-regex, or
+// explains why PCRE would not match it the way RE2 does. The pattern must
+// parse as RE2, and the few RE2 spellings PCRE treats differently are
+// refused: \C (any byte), \x{...} above 0xFF (needs UTF mode) and {,n}
+// (a literal in RE2, a quantifier in PCRE2 10.43+).
+func semgrepRegex(pattern string) (string, error) {
+	if _, err := syntax.Parse(pattern, syntax.Perl); err != nil {
+		return "", err
+	}
+	inClass := false
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			switch pattern[i] {
+			case 'C':
+				return "", errors.New("\\C matches a byte in RE2 but a code unit in PCRE")
+			case 'x':
+				if i+1 < len(pattern) && pattern[i+1] == '{' {
+					end := strings.IndexByte(pattern[i:], '}')
+					if end < 0 {
+						break
+					}
+					if v, err := strconv.ParseUint(pattern[i+2:i+end], 16, 32); err == nil && v > 0xff {
+						return "", fmt.Errorf("\\x{%s} needs PCRE's UTF mode", pattern[i+2:i+end])
+					}
+					i += end
+				}
+			}
+		case inClass && c == '[' && strings.HasPrefix(pattern[i:], "[:"):
+			if end := strings.Index(pattern[i+2:], ":]"); end >= 0 {
+				i += end + 3
+			}
+		case inClass && c == ']':
+			inClass = false
+		case c == '[':
+			inClass = true
+			// A ] right after [ or [^ is a literal, not the end.
+			if strings.HasPrefix(pattern[i+1:], "^") {
+				i++
+			}
+			if strings.HasPrefix(pattern[i+1:], "]") {
+				i++
+			}
+		case !inClass && c == '{' && strings.HasPrefix(pattern[i+1:], ","):
+			return "", errors.New("{, is a literal in RE2 but a quantifier in PCRE")
+		}
+	}
+	if strings.HasPrefix(pattern, "^") {
+		pattern = `^[ \t]*` + pattern[1:]
+	}
+	return "(?im)" + pattern, nil
+}
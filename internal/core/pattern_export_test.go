@@ -0,0 +1,275 @@
+package core
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// withoutGenerated drops the timestamp line so two exports can be compared.
+func withoutGenerated(s string) string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if !strings.HasPrefix(line, "# Generated: ") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestExportRego(t *testing.T) {
+	engine := NewPatternEngine()
+	if err := engine.EnablePack("terraform"); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.AddPattern(RiskTierCritical, "^echo\\s+`whoami`", "backticks", "test"); err != nil {
+		t.Fatal(err)
+	}
+	out := engine.ExportRego()
+
+	for _, want := range []string{
+		"# Auto-generated by: slb patterns export --format=rego\n",
+		"# SHA256: " + engine.ComputeHash() + "\n",
+		"# Packs: terraform\n",
+		"package slb.patterns\n",
+		"import rego.v1\n",
+		"safe_patterns := [\n",
+		"critical_patterns := [\n",
+		"\t`^rm\\s+(-[rf]+\\s+)+/\\*`,\n",
+		"\t# pack: terraform\n",
+		"\t\"^echo\\\\s+`whoami`\",\n",
+		`default tier := "unknown"`,
+		"deny contains msg if {",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in rego export:\n%s", want, extractFirstNLines(out, 40))
+		}
+	}
+
+	// Patterns keep the deterministic export order: sorted within a tier.
+	safe := out[strings.Index(out, "safe_patterns := ["):]
+	safe = safe[:strings.Index(safe, "]\n")]
+	if !strings.Contains(safe, "`^git\\s+stash\\s*$`,\n\t`^kubectl") {
+		t.Errorf("safe patterns not sorted:\n%s", safe)
+	}
+	if withoutGenerated(engine.ExportRego()) != withoutGenerated(out) {
+		t.Error("rego export is not deterministic")
+	}
+
+	empty := &PatternEngine{}
+	if !strings.Contains(empty.ExportRego(), "caution_patterns := []\n") {
+		t.Error("expected an empty list for a tier without patterns")
+	}
+}
+
+func TestExportSemgrep(t *testing.T) {
+	engine := NewPatternEngine()
+	if err := engine.AddPattern(RiskTierDangerous, `^make\s+deploy`, "don't deploy from a laptop", "test"); err != nil {
+		t.Fatal(err)
+	}
+	out, warnings, err := engine.ExportSemgrep()
+	if err != nil {
+		t.Fatalf("ExportSemgrep: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("built-in patterns should all be portable, got %v", warnings)
+	}
+	if !strings.HasPrefix(out, "# Auto-generated by: slb patterns export --format=semgrep\n") ||
+		!strings.Contains(out, "# SHA256: "+engine.ComputeHash()+"\n") {
+		t.Fatalf("missing header:\n%s", extractFirstNLines(out, 10))
+	}
+
+	var doc struct {
+		Rules []struct {
+			ID           string         `yaml:"id"`
+			Message      string         `yaml:"message"`
+			Severity     string         `yaml:"severity"`
+			Languages    []string       `yaml:"languages"`
+			PatternRegex string         `yaml:"pattern-regex"`
+			Metadata     map[string]any `yaml:"metadata"`
+		} `yaml:"rules"`
+	}
+	if err := yaml.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("export is not valid YAML: %v", err)
+	}
+
+	counts := engine.Export().Metadata.TierCounts
+	if want := counts["caution"] + counts["dangerous"] + counts["critical"]; len(doc.Rules) != want {
+		t.Fatalf("expected %d rules (safe tier skipped), got %d", want, len(doc.Rules))
+	}
+
+	ids := make(map[string]bool)
+	var deploy, rmRoot bool
+	for _, r := range doc.Rules {
+		if ids[r.ID] {
+			t.Errorf("duplicate rule id %s", r.ID)
+		}
+		ids[r.ID] = true
+		if len(r.Languages) != 1 || r.Languages[0] != "generic" || !strings.HasPrefix(r.PatternRegex, "(?im)") {
+			t.Errorf("rule %s = %+v", r.ID, r)
+		}
+		re, err := regexp.Compile(r.PatternRegex)
+		if err != nil {
+			t.Errorf("rule %s regex does not compile: %v", r.ID, err)
+			continue
+		}
+		switch r.Metadata["slb_pattern"] {
+		case `^make\s+deploy`:
+			deploy = true
+			if r.Severity != "WARNING" || !strings.HasPrefix(r.Message, "don't deploy from a laptop (DANGEROUS") || r.Metadata["source"] != "test" {
+				t.Errorf("deploy rule = %+v", r)
+			}
+			// Indented commands in scripts still match.
+			if !re.MatchString("#!/bin/sh\nset -e\n  make deploy\n") {
+				t.Errorf("deploy rule does not match an indented command")
+			}
+		case `^rm\s+(-[rf]+\s+)+/\*`:
+			rmRoot = true
+			if r.Severity != "ERROR" || r.Metadata["slb_tier"] != "critical" || r.Metadata["min_approvals"] != 2 {
+				t.Errorf("rm rule = %+v", r)
+			}
+		}
+	}
+	if !deploy || !rmRoot {
+		t.Errorf("expected deploy and rm rules (found %v, %v)", deploy, rmRoot)
+	}
+
+	again, _, _ := engine.ExportSemgrep()
+	if withoutGenerated(again) != withoutGenerated(out) {
+		t.Error("semgrep export is not deterministic")
+	}
+}
+
+func TestSemgrepRegex(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string
+		ok      bool
+	}{
+		{`^rm\s+-rf`, `(?im)^[ \t]*rm\s+-rf`, true},
+		{`DROP\s+TABLE`, `(?im)DROP\s+TABLE`, true},
+		{`[{,]x{2,3}`, `(?im)[{,]x{2,3}`, true},
+		{`[[:space:]]{,`, `(?im)[[:space:]]{,`, false},
+		{`\x{1b}\[`, `(?im)\x{1b}\[`, true},
+		{`\x{263a}`, "", false},
+		{`a{,2}`, "", false},
+		{`(unclosed`, "", false},
+	}
+	for _, tt := range tests {
+		got, err := semgrepRegex(tt.pattern)
+		if (err == nil) != tt.ok {
+			t.Errorf("semgrepRegex(%q) err = %v, want ok=%v", tt.pattern, err, tt.ok)
+			continue
+		}
+		if tt.ok && got != tt.want {
+			t.Errorf("semgrepRegex(%q) = %q, want %q", tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestExportSemgrep_SkipsNonPortable(t *testing.T) {
+	engine := &PatternEngine{}
+	if err := engine.AddPattern(RiskTierDangerous, `deploy\s+x{,2}`, "", "test"); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.AddPattern(RiskTierDangerous, `^make\s+deploy`, "", "test"); err != nil {
+		t.Fatal(err)
+	}
+	out, warnings, err := engine.ExportSemgrep()
+	if err != nil {
+		t.Fatalf("ExportSemgrep: %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], `x{,2}`) {
+		t.Fatalf("warnings = %v", warnings)
+	}
+	if !strings.Contains(out, "# Not portable to Semgrep's PCRE:\n#   "+warnings[0]+"\n") {
+		t.Errorf("skipped pattern not listed in the header:\n%s", extractFirstNLines(out, 12))
+	}
+	if strings.Contains(out, "pattern-regex: (?im)deploy") || !strings.Contains(out, "make\\s+deploy") {
+		t.Errorf("expected only the portable rule:\n%s", out)
+	}
+}
+
+// TestExportSemgrep_PCRE runs the exported built-in rules through a real
+// PCRE engine (grep -P) and checks they compile and match the same
+// commands as the RE2 engine.
+func TestExportSemgrep_PCRE(t *testing.T) {
+	grep, err := exec.LookPath("grep")
+	if err != nil {
+		t.Skip("grep not installed")
+	}
+	if err := exec.Command(grep, "-P", "-q", "x", os.DevNull).Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+			t.Skip("grep has no PCRE support")
+		}
+	}
+
+	commands := []string{
+		"rm -rf /",
+		"  rm -rf /*",
+		"rm -rf ./build",
+		"git push --force origin main",
+		"git reset --hard HEAD~3",
+		"kubectl delete namespace prod",
+		"terraform destroy -auto-approve",
+		"psql -c 'DROP TABLE users'",
+		"DELETE FROM accounts;",
+		"chmod -R 777 /",
+		"dd if=/dev/zero of=/dev/sda",
+		"docker system prune -af",
+		"ls -la",
+		"echo hello",
+	}
+	samples := filepath.Join(t.TempDir(), "commands.txt")
+	if err := os.WriteFile(samples, []byte(strings.Join(commands, "\n")+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	out, _, err := NewPatternEngine().ExportSemgrep()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Rules []struct {
+			ID           string `yaml:"id"`
+			PatternRegex string `yaml:"pattern-regex"`
+		} `yaml:"rules"`
+	}
+	if err := yaml.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	matched := 0
+	for _, r := range doc.Rules {
+		re := regexp.MustCompile(r.PatternRegex)
+		var want []string
+		for _, c := range commands {
+			if re.MatchString(c) {
+				want = append(want, c)
+			}
+		}
+		cmd := exec.Command(grep, "-P", "-e", r.PatternRegex, samples)
+		got, err := cmd.Output()
+		if err != nil {
+			var exitErr *exec.ExitError
+			if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+				t.Errorf("rule %s: PCRE rejects %q: %v", r.ID, r.PatternRegex, err)
+				continue
+			}
+		}
+		if gotLines := strings.TrimSuffix(string(got), "\n"); gotLines != strings.Join(want, "\n") {
+			t.Errorf("rule %s (%s): PCRE matched %q, RE2 matched %q", r.ID, r.PatternRegex, gotLines, want)
+		}
+		matched += len(want)
+	}
+	if matched == 0 {
+		t.Error("no sample command matched any rule")
+	}
+}
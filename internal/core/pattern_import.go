@@ -0,0 +1,147 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Import statuses.
+const (
+	// ImportNew means the pattern is valid and not yet in the engine.
+	ImportNew = "new"
+	// ImportDuplicate means the engine (or an earlier bundle entry) already
+	// has the pattern in the same tier.
+	ImportDuplicate = "duplicate"
+	// ImportInvalid means the tier is unknown or the regex does not compile.
+	ImportInvalid = "invalid"
+)
+
+// ImportedPattern is one pattern read from a bundle.
+type ImportedPattern struct {
+	Tier        string `json:"tier" yaml:"tier"`
+	Pattern     string `json:"pattern" yaml:"pattern"`
+	Description string `json:"description,omitempty" yaml:"description"`
+}
+
+// ImportEntry is a bundle pattern with the outcome of checking it against
+// the engine.
+type ImportEntry struct {
+	ImportedPattern
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// patternBundle is the union of the accepted bundle shapes. JSON is valid
+// YAML, so one decoder reads both.
+type patternBundle struct {
+	// Tiers is the PatternExport shape written by `slb patterns export`.
+	Tiers map[string]struct {
+		Patterns []ImportedPattern `yaml:"patterns"`
+	} `yaml:"tiers"`
+	// Patterns is the hand-written shape: a flat list with a tier per entry.
+	Patterns []ImportedPattern `yaml:"patterns"`
+}
+
+// ParsePatternBundle reads patterns from a bundle: either the JSON (or YAML)
+// written by `slb patterns export`, or a flat list
+//
+//	patterns:
+//	  - tier: dangerous
+//	    pattern: ^make\s+deploy
+//	    description: deploys go through CI
+//
+// Entries in a flat list without a tier get defaultTier. Export tiers are
+// read in safe, caution, dangerous, critical order; unknown tier names are
+// kept so PlanImport can report them.
+func ParsePatternBundle(data []byte, defaultTier string) ([]ImportedPattern, error) {
+	var bundle patternBundle
+	if err := yaml.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("parsing pattern bundle: %w", err)
+	}
+	if len(bundle.Tiers) == 0 && len(bundle.Patterns) == 0 {
+		return nil, fmt.Errorf("pattern bundle has no patterns (expected a tiers map or a patterns list)")
+	}
+
+	order := map[string]int{"safe": 0, "caution": 1, "dangerous": 2, "critical": 3}
+	names := make([]string, 0, len(bundle.Tiers))
+	for name := range bundle.Tiers {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		oi, iKnown := order[strings.ToLower(names[i])]
+		oj, jKnown := order[strings.ToLower(names[j])]
+		if iKnown != jKnown {
+			return iKnown
+		}
+		if oi != oj {
+			return oi < oj
+		}
+		return names[i] < names[j]
+	})
+
+	var out []ImportedPattern
+	for _, name := range names {
+		for _, p := range bundle.Tiers[name].Patterns {
+			p.Tier = name
+			out = append(out, p)
+		}
+	}
+	for _, p := range bundle.Patterns {
+		if p.Tier == "" {
+			p.Tier = defaultTier
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// PlanImport checks bundle patterns against the engine without changing it.
+// Each entry is new, a duplicate of a pattern already in that tier (or of an
+// earlier entry), or invalid. Tiers are normalized to their lowercase names.
+func (e *PatternEngine) PlanImport(patterns []ImportedPattern) []ImportEntry {
+	existing := make(map[string]struct{})
+	for tierName, list := range e.AllPatterns() {
+		for _, p := range list {
+			existing[tierName+"\x00"+p.Pattern] = struct{}{}
+		}
+	}
+
+	entries := make([]ImportEntry, 0, len(patterns))
+	for _, p := range patterns {
+		entry := ImportEntry{ImportedPattern: p}
+		entry.Pattern = strings.TrimSpace(p.Pattern)
+		tier := ParseRiskTier(strings.TrimSpace(p.Tier))
+		switch {
+		case tier == "":
+			entry.Status = ImportInvalid
+			if p.Tier == "" {
+				entry.Error = "no tier"
+			} else {
+				entry.Error = fmt.Sprintf("unknown tier %q", p.Tier)
+			}
+		case entry.Pattern == "":
+			entry.Status = ImportInvalid
+			entry.Error = "empty pattern"
+		default:
+			entry.Tier = string(tier)
+			if _, err := regexp.Compile("(?i)" + entry.Pattern); err != nil {
+				entry.Status = ImportInvalid
+				entry.Error = err.Error()
+				break
+			}
+			key := entry.Tier + "\x00" + entry.Pattern
+			if _, dup := existing[key]; dup {
+				entry.Status = ImportDuplicate
+				break
+			}
+			existing[key] = struct{}{}
+			entry.Status = ImportNew
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
@@ -0,0 +1,106 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePatternBundle_Export(t *testing.T) {
+	engine := NewPatternEngine()
+	data, err := engine.ExportJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	patterns, err := ParsePatternBundle([]byte(data), "")
+	if err != nil {
+		t.Fatalf("ParsePatternBundle: %v", err)
+	}
+	if want := engine.Export().Metadata.PatternCount; len(patterns) != want {
+		t.Fatalf("expected %d patterns, got %d", want, len(patterns))
+	}
+	if patterns[0].Tier != "safe" || patterns[len(patterns)-1].Tier != "critical" {
+		t.Errorf("tiers out of order: first %q, last %q", patterns[0].Tier, patterns[len(patterns)-1].Tier)
+	}
+
+	// Re-importing an engine's own export adds nothing.
+	for _, e := range engine.PlanImport(patterns) {
+		if e.Status != ImportDuplicate {
+			t.Fatalf("expected every exported pattern to be a duplicate, got %+v", e)
+		}
+	}
+}
+
+func TestParsePatternBundle_List(t *testing.T) {
+	bundle := `
+patterns:
+  - tier: Critical
+    pattern: ^vault\s+operator\s+seal
+    description: seals the vault
+  - pattern: ^make\s+deploy
+`
+	patterns, err := ParsePatternBundle([]byte(bundle), "dangerous")
+	if err != nil {
+		t.Fatalf("ParsePatternBundle: %v", err)
+	}
+	if len(patterns) != 2 {
+		t.Fatalf("expected 2 patterns, got %+v", patterns)
+	}
+	if patterns[0].Tier != "Critical" || patterns[0].Description != "seals the vault" {
+		t.Errorf("first pattern = %+v", patterns[0])
+	}
+	if patterns[1].Tier != "dangerous" || patterns[1].Pattern != `^make\s+deploy` {
+		t.Errorf("untiered pattern should get the default tier, got %+v", patterns[1])
+	}
+
+	for _, bad := range []string{"", "version: 1\n", "patterns: [\n"} {
+		if _, err := ParsePatternBundle([]byte(bad), ""); err == nil {
+			t.Errorf("expected an error for bundle %q", bad)
+		}
+	}
+}
+
+func TestPlanImport(t *testing.T) {
+	engine := NewPatternEngine()
+	if err := engine.AddPattern(RiskTierDangerous, `^make\s+deploy`, "", "test"); err != nil {
+		t.Fatal(err)
+	}
+
+	entries := engine.PlanImport([]ImportedPattern{
+		{Tier: "DANGEROUS", Pattern: `^make\s+deploy`},
+		{Tier: "critical", Pattern: `^make\s+deploy`},
+		{Tier: "critical", Pattern: ` ^make\s+deploy `},
+		{Tier: "safe", Pattern: `^make\s+lint$`},
+		{Tier: "caution", Pattern: `^foo(`},
+		{Tier: "severe", Pattern: `^bar`},
+		{Tier: "", Pattern: `^baz`},
+		{Tier: "caution", Pattern: "  "},
+	})
+
+	want := []struct{ tier, status, err string }{
+		{"dangerous", ImportDuplicate, ""},
+		{"critical", ImportNew, ""},
+		{"critical", ImportDuplicate, ""},
+		{"safe", ImportNew, ""},
+		{"caution", ImportInvalid, "missing closing )"},
+		{"severe", ImportInvalid, `unknown tier "severe"`},
+		{"", ImportInvalid, "no tier"},
+		{"caution", ImportInvalid, "empty pattern"},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("expected %d entries, got %d", len(want), len(entries))
+	}
+	for i, w := range want {
+		e := entries[i]
+		if e.Tier != w.tier || e.Status != w.status || !strings.Contains(e.Error, w.err) || (w.err == "" && e.Error != "") {
+			t.Errorf("entry %d = %+v, want tier %q status %q error %q", i, e, w.tier, w.status, w.err)
+		}
+	}
+	if entries[2].Pattern != `^make\s+deploy` {
+		t.Errorf("pattern not trimmed: %q", entries[2].Pattern)
+	}
+
+	// Planning does not change the engine.
+	if len(engine.ListPatterns(RiskTierCritical)) != len(NewPatternEngine().ListPatterns(RiskTierCritical)) {
+		t.Error("PlanImport added patterns to the engine")
+	}
+}
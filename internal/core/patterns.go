@@ -11,6 +11,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
 )
 
 // Pattern represents a risk classification pattern.
@@ -24,7 +26,10 @@ type Pattern struct {
 	// Description describes why this pattern is risky.
 	Description string
 	// Source indicates where this pattern came from.
-	Source string // "builtin", "agent", "human", "suggested"
+	Source string // "builtin", "agent", "human", "suggested", "pack"
+	// Pack is the pattern pack (namespace) that contributed this pattern,
+	// empty for patterns not from a pack.
+	Pack string
 }
 
 // MatchResult contains the result of pattern matching.
@@ -43,6 +48,11 @@ type MatchResult struct {
 	ParseError bool
 	// Segments lists matched segments for compound commands.
 	MatchedSegments []SegmentMatch
+	// PathEscapes lists path arguments whose real location (after following
+	// symlinks) is a system location or a network mount.
+	PathEscapes []PathEscape
+	// Explanation holds notes on adjustments made to the matched tier.
+	Explanation []string
 }
 
 // SegmentMatch describes a match within a compound command.
@@ -60,6 +70,8 @@ type PatternEngine struct {
 	critical  []*Pattern
 	dangerous []*Pattern
 	caution   []*Pattern
+	// packs records which pattern packs have been enabled.
+	packs map[string]bool
 }
 
 // NewPatternEngine creates a new pattern engine with default patterns.
@@ -74,6 +86,8 @@ func (e *PatternEngine) LoadDefaultPatterns() {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
+	e.packs = nil
+
 	// Safe patterns (skip review entirely)
 	e.safe = compilePatterns(RiskTier(RiskSafe), []string{
 		`^rm\s+.*\.log$`,
@@ -91,6 +105,8 @@ func (e *PatternEngine) LoadDefaultPatterns() {
 		`^rm\s+(-[rf]+\s+)+/($|\s)`, // rm -rf / (root)
 		`^rm\s+(-[rf]+\s+)+/\*`,     // rm -rf /* (root wildcard)
 		`^rm\s+(-[rf]+\s+)+~`,       // rm -rf ~
+		// Windows system folders (PowerShell/cmd deletions normalize to rm -rf /c/Windows)
+		`^rm\s+(-[rf]+\s+)+/[a-z]/(windows|windows/system32|program files|program files \(x86\)|programdata|users)/?(\*)?(\s|$)`,
 		// SQL data destruction
 		`DROP\s+DATABASE`,
 		`DROP\s+SCHEMA`,
@@ -106,6 +122,10 @@ func (e *PatternEngine) LoadDefaultPatterns() {
 		// Git force push - both --force and -f (but not --force-with-lease)
 		`^git\s+push\s+.*--force($|\s)`,
 		`^git\s+push\s+.*-f($|\s)`,
+		// Git history rewrites that replace or drop refs wholesale
+		`^git\s+push\s+(.*\s)?--mirror($|\s)`,
+		`^git\s+filter-(branch|repo)($|\s)`,
+		`^git\s+reflog\s+expire\s+(.*\s)?--expire(-unreachable)?=(now|all)($|\s)`,
 		// Cloud resource destruction
 		`^aws\s+.*terminate-instances`,
 		`^gcloud.*delete.*--quiet`,
@@ -126,6 +146,8 @@ func (e *PatternEngine) LoadDefaultPatterns() {
 		`^git\s+reset\s+--hard`,
 		`^git\s+clean\s+-fd`,
 		`^git\s+push.*--force-with-lease`,
+		`^git\s+rebase\s+(.*\s)?(-i|--interactive|--root)($|\s)`,
+		`^git\s+reflog\s+(expire|delete)($|\s)`,
 		`^kubectl\s+delete`,
 		`^helm\s+uninstall`,
 		`^docker\s+rm`,
@@ -179,6 +201,13 @@ func (e *PatternEngine) ClassifyCommand(cmd, cwd string) *MatchResult {
 	// Normalize the command
 	normalized := NormalizeCommand(cmd)
 
+	result := e.classifyNormalized(cmd, normalized, cwd)
+	return applyRemoteScripts(applyPathEscapes(result, normalized, cwd), cmd)
+}
+
+// classifyNormalized matches a normalized command against the patterns
+// (caller must hold the lock).
+func (e *PatternEngine) classifyNormalized(cmd string, normalized *NormalizedCommand, cwd string) *MatchResult {
 	// Initialize result
 	result := &MatchResult{
 		NeedsApproval: false,
@@ -475,6 +504,27 @@ func (e *PatternEngine) AddPattern(tier RiskTier, pattern, description, source s
 	return nil
 }
 
+// Clone returns an engine with the same patterns and enabled packs, so
+// patterns can be added to the copy without affecting e.
+func (e *PatternEngine) Clone() *PatternEngine {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	c := &PatternEngine{
+		safe:      append([]*Pattern(nil), e.safe...),
+		critical:  append([]*Pattern(nil), e.critical...),
+		dangerous: append([]*Pattern(nil), e.dangerous...),
+		caution:   append([]*Pattern(nil), e.caution...),
+	}
+	for name := range e.packs {
+		if c.packs == nil {
+			c.packs = make(map[string]bool)
+		}
+		c.packs[name] = true
+	}
+	return c
+}
+
 // RemovePattern removes a pattern from the engine.
 func (e *PatternEngine) RemovePattern(tier RiskTier, pattern string) bool {
 	e.mu.Lock()
@@ -532,6 +582,68 @@ func (e *PatternEngine) AllPatterns() map[string][]*Pattern {
 	}
 }
 
+// ParseRiskTier parses a tier name case-insensitively, returning "" for
+// unknown names.
+func ParseRiskTier(s string) RiskTier {
+	switch strings.ToLower(s) {
+	case "critical":
+		return RiskTierCritical
+	case "dangerous":
+		return RiskTierDangerous
+	case "caution":
+		return RiskTierCaution
+	case "safe":
+		return RiskTier(RiskSafe)
+	default:
+		return ""
+	}
+}
+
+// MergeCustomPatterns adds persisted custom patterns to the engine,
+// skipping any (tier, pattern) the engine already has, so it is safe to call
+// repeatedly. Rows with an unknown tier or a regex that won't compile are
+// skipped and reported in the returned errors rather than stopping the merge.
+// Returns the number of patterns added.
+func (e *PatternEngine) MergeCustomPatterns(rows []*db.CustomPattern) (int, []error) {
+	// Snapshot existing engine patterns by (tier, pattern). Without dedup,
+	// merging twice in one process would append the same row twice and the
+	// in-memory engine would diverge from the SQLite source of truth.
+	existing := make(map[string]struct{})
+	for tierName, list := range e.AllPatterns() {
+		for _, p := range list {
+			existing[tierName+"\x00"+p.Pattern] = struct{}{}
+		}
+	}
+
+	loaded := 0
+	var skipped []error
+	for _, row := range rows {
+		tier := ParseRiskTier(row.Tier)
+		if tier == "" {
+			// Persisted by an older version or edited directly in SQL.
+			// Skipping is safer than AddPattern's default arm, which
+			// would route it to the safe bucket.
+			skipped = append(skipped, fmt.Errorf("skipping persisted pattern with unrecognized tier %q (pattern=%q)", row.Tier, row.Pattern))
+			continue
+		}
+		// The canonical lowercase tier is the dedup key, so mixed-case
+		// rows still match the engine's keys.
+		key := string(tier) + "\x00" + row.Pattern
+		if _, dup := existing[key]; dup {
+			continue
+		}
+		if err := e.AddPattern(tier, row.Pattern, row.Description, row.Source); err != nil {
+			skipped = append(skipped, fmt.Errorf("skipping invalid persisted pattern %q (tier=%s): %w", row.Pattern, row.Tier, err))
+			continue
+		}
+		// Duplicate rows in one table (inserted before the UNIQUE
+		// constraint existed) load once.
+		existing[key] = struct{}{}
+		loaded++
+	}
+	return loaded, skipped
+}
+
 // Global pattern engine instance
 var defaultEngine = NewPatternEngine()
 
@@ -582,12 +694,14 @@ type PatternDetails struct {
 	Pattern     string `json:"pattern"`
 	Description string `json:"description,omitempty"`
 	Source      string `json:"source"`
+	Pack        string `json:"pack,omitempty"`
 }
 
 // PatternExportMetadata contains summary information about the export.
 type PatternExportMetadata struct {
 	PatternCount int            `json:"pattern_count"`
 	TierCounts   map[string]int `json:"tier_counts"`
+	Packs        []string       `json:"packs,omitempty"`
 }
 
 // Export exports all patterns in a structured format suitable for external tools.
@@ -624,6 +738,7 @@ func (e *PatternEngine) Export() *PatternExport {
 				Pattern:     p.Pattern,
 				Description: p.Description,
 				Source:      p.Source,
+				Pack:        p.Pack,
 			})
 		}
 
@@ -642,6 +757,10 @@ func (e *PatternEngine) Export() *PatternExport {
 		export.Metadata.PatternCount += len(patterns)
 	}
 
+	if len(e.packs) > 0 {
+		export.Metadata.Packs = e.enabledPacksLocked()
+	}
+
 	// Compute hash for change detection
 	export.SHA256 = e.computeHashLocked()
 
@@ -706,41 +825,20 @@ func (e *PatternEngine) ExportClaudeHook() string {
 
 	var sb strings.Builder
 
-	// Header
-	sb.WriteString("# Auto-generated by: slb patterns export --format=claude-hook\n")
-	sb.WriteString(fmt.Sprintf("# Generated: %s\n", time.Now().UTC().Format(time.RFC3339)))
-	sb.WriteString(fmt.Sprintf("# SHA256: %s\n", e.computeHashLocked()))
-	sb.WriteString("# DO NOT EDIT - regenerate with: slb patterns export --format=claude-hook\n")
-	sb.WriteString("\n")
+	e.writeExportHeaderLocked(&sb, "claude-hook")
 	sb.WriteString("import re\n")
 	sb.WriteString("from typing import Tuple, Optional\n")
 	sb.WriteString("\n")
 
 	// Export each tier
-	tiers := []struct {
-		name      string
-		patterns  []*Pattern
-		varName   string
-		approvals int
-	}{
-		{"safe", e.safe, "SAFE_PATTERNS", 0},
-		{"caution", e.caution, "CAUTION_PATTERNS", 0},
-		{"dangerous", e.dangerous, "DANGEROUS_PATTERNS", 1},
-		{"critical", e.critical, "CRITICAL_PATTERNS", 2},
-	}
-
-	for _, tier := range tiers {
+	for _, tier := range e.exportTiersLocked() {
 		sb.WriteString(fmt.Sprintf("# %s tier: %d patterns\n", strings.ToUpper(tier.name), len(tier.patterns)))
-		sb.WriteString(fmt.Sprintf("%s = [\n", tier.varName))
+		sb.WriteString(fmt.Sprintf("%s_PATTERNS = [\n", strings.ToUpper(tier.name)))
 
-		// Sort patterns for deterministic output
-		sortedPatterns := make([]*Pattern, len(tier.patterns))
-		copy(sortedPatterns, tier.patterns)
-		sort.Slice(sortedPatterns, func(i, j int) bool {
-			return sortedPatterns[i].Pattern < sortedPatterns[j].Pattern
-		})
-
-		for _, p := range sortedPatterns {
+		for _, p := range tier.patterns {
+			if p.Pack != "" {
+				sb.WriteString(fmt.Sprintf("    # pack: %s\n", p.Pack))
+			}
 			// Emit a Python raw string for patterns without
 			// apostrophes — raw strings preserve backslashes
 			// verbatim, so `\s`, `\b`, `\w`, `\.`, etc. survive
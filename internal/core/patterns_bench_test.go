@@ -0,0 +1,68 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+// benchCommands covers the main normalization paths: plain commands,
+// wrappers, compound commands, substitutions and Windows syntax.
+var benchCommands = []struct {
+	name string
+	cmd  string
+}{
+	{"safe", "git status"},
+	{"simple", "rm -rf ./build"},
+	{"critical", "rm -rf /"},
+	{"wrapped", `sudo env FOO=1 bash -c "git reset --hard HEAD~3"`},
+	{"compound", "cd /srv/app && git pull && make build && kubectl apply -f deploy.yaml"},
+	{"pipeline", "find . -name '*.o' | xargs rm -f"},
+	{"substitution", "echo $(rm -rf /tmp/cache) (date)"},
+	{"windows", `powershell -NoProfile -Command "Remove-Item -Recurse -Force C:\temp\build"`},
+	{"unmatched", "go test ./... -run TestSomething -count=1"},
+}
+
+// classificationP99Budget is the latency budget for a single ClassifyCommand
+// call. Hooks classify every command an agent runs, so this must stay well
+// below anything a user would notice.
+const classificationP99Budget = time.Millisecond
+
+func BenchmarkClassifyCommand(b *testing.B) {
+	engine := NewPatternEngine()
+	for _, bc := range benchCommands {
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				engine.ClassifyCommand(bc.cmd, "/work")
+			}
+		})
+	}
+}
+
+func BenchmarkNormalizeCommand(b *testing.B) {
+	for _, bc := range benchCommands {
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				NormalizeCommand(bc.cmd)
+			}
+		})
+	}
+}
+
+func TestClassifyCommand_PerfBudget(t *testing.T) {
+	testutil.RequirePerfBudgets(t)
+
+	engine := NewPatternEngine()
+	// Warm up compiled patterns and caches.
+	for _, bc := range benchCommands {
+		engine.ClassifyCommand(bc.cmd, "/work")
+	}
+	for _, bc := range benchCommands {
+		testutil.RequireP99(t, "classify "+bc.name, 500, classificationP99Budget, func() {
+			engine.ClassifyCommand(bc.cmd, "/work")
+		})
+	}
+}
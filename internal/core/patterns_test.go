@@ -4,137 +4,140 @@ package core
 import (
 	"strings"
 	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
 )
 
+// classifyCommandCases is shared with the fuzz targets as seed corpus.
+var classifyCommandCases = []struct {
+	name              string
+	cmd               string
+	wantTier          RiskTier
+	wantApprovals     int
+	wantNeedsApproval bool
+}{
+	// Critical commands
+	{
+		name:              "rm -rf root",
+		cmd:               "rm -rf /etc",
+		wantTier:          RiskTierCritical,
+		wantApprovals:     2,
+		wantNeedsApproval: true,
+	},
+	{
+		name:              "DROP DATABASE",
+		cmd:               "psql -c 'DROP DATABASE mydb'",
+		wantTier:          RiskTierCritical,
+		wantApprovals:     2,
+		wantNeedsApproval: true,
+	},
+	{
+		name:              "terraform destroy",
+		cmd:               "terraform destroy",
+		wantTier:          RiskTierCritical,
+		wantApprovals:     2,
+		wantNeedsApproval: true,
+	},
+	{
+		name:              "kubectl delete node",
+		cmd:               "kubectl delete node worker-1",
+		wantTier:          RiskTierCritical,
+		wantApprovals:     2,
+		wantNeedsApproval: true,
+	},
+	{
+		name:              "git push --force",
+		cmd:               "git push --force origin main",
+		wantTier:          RiskTierCritical,
+		wantApprovals:     2,
+		wantNeedsApproval: true,
+	},
+	// Dangerous commands
+	{
+		name:              "rm -rf local",
+		cmd:               "rm -rf ./build",
+		wantTier:          RiskTierDangerous,
+		wantApprovals:     1,
+		wantNeedsApproval: true,
+	},
+	{
+		name:              "rm -fr local (reversed flags)",
+		cmd:               "rm -fr ./build",
+		wantTier:          RiskTierDangerous,
+		wantApprovals:     1,
+		wantNeedsApproval: true,
+	},
+	{
+		name:              "git reset --hard",
+		cmd:               "git reset --hard HEAD~3",
+		wantTier:          RiskTierDangerous,
+		wantApprovals:     1,
+		wantNeedsApproval: true,
+	},
+	{
+		name:              "git clean -fd",
+		cmd:               "git clean -fd",
+		wantTier:          RiskTierDangerous,
+		wantApprovals:     1,
+		wantNeedsApproval: true,
+	},
+	{
+		name:              "kubectl delete pod",
+		cmd:               "kubectl delete deployment nginx",
+		wantTier:          RiskTierDangerous,
+		wantApprovals:     1,
+		wantNeedsApproval: true,
+	},
+	{
+		name:              "docker rm",
+		cmd:               "docker rm container1",
+		wantTier:          RiskTierDangerous,
+		wantApprovals:     1,
+		wantNeedsApproval: true,
+	},
+	// Caution commands
+	{
+		name:              "git stash drop",
+		cmd:               "git stash drop",
+		wantTier:          RiskTierCaution,
+		wantApprovals:     0,
+		wantNeedsApproval: true,
+	},
+	{
+		name:              "npm uninstall",
+		cmd:               "npm uninstall lodash",
+		wantTier:          RiskTierCaution,
+		wantApprovals:     0,
+		wantNeedsApproval: true,
+	},
+	// Safe commands (no approval needed)
+	{
+		name:              "git status",
+		cmd:               "git status",
+		wantTier:          "",
+		wantApprovals:     0,
+		wantNeedsApproval: false,
+	},
+	{
+		name:              "ls",
+		cmd:               "ls -la",
+		wantTier:          "",
+		wantApprovals:     0,
+		wantNeedsApproval: false,
+	},
+	{
+		name:              "cat file",
+		cmd:               "cat README.md",
+		wantTier:          "",
+		wantApprovals:     0,
+		wantNeedsApproval: false,
+	},
+}
+
 func TestClassifyCommand(t *testing.T) {
 	engine := NewPatternEngine()
 
-	tests := []struct {
-		name              string
-		cmd               string
-		wantTier          RiskTier
-		wantApprovals     int
-		wantNeedsApproval bool
-	}{
-		// Critical commands
-		{
-			name:              "rm -rf root",
-			cmd:               "rm -rf /etc",
-			wantTier:          RiskTierCritical,
-			wantApprovals:     2,
-			wantNeedsApproval: true,
-		},
-		{
-			name:              "DROP DATABASE",
-			cmd:               "psql -c 'DROP DATABASE mydb'",
-			wantTier:          RiskTierCritical,
-			wantApprovals:     2,
-			wantNeedsApproval: true,
-		},
-		{
-			name:              "terraform destroy",
-			cmd:               "terraform destroy",
-			wantTier:          RiskTierCritical,
-			wantApprovals:     2,
-			wantNeedsApproval: true,
-		},
-		{
-			name:              "kubectl delete node",
-			cmd:               "kubectl delete node worker-1",
-			wantTier:          RiskTierCritical,
-			wantApprovals:     2,
-			wantNeedsApproval: true,
-		},
-		{
-			name:              "git push --force",
-			cmd:               "git push --force origin main",
-			wantTier:          RiskTierCritical,
-			wantApprovals:     2,
-			wantNeedsApproval: true,
-		},
-		// Dangerous commands
-		{
-			name:              "rm -rf local",
-			cmd:               "rm -rf ./build",
-			wantTier:          RiskTierDangerous,
-			wantApprovals:     1,
-			wantNeedsApproval: true,
-		},
-		{
-			name:              "rm -fr local (reversed flags)",
-			cmd:               "rm -fr ./build",
-			wantTier:          RiskTierDangerous,
-			wantApprovals:     1,
-			wantNeedsApproval: true,
-		},
-		{
-			name:              "git reset --hard",
-			cmd:               "git reset --hard HEAD~3",
-			wantTier:          RiskTierDangerous,
-			wantApprovals:     1,
-			wantNeedsApproval: true,
-		},
-		{
-			name:              "git clean -fd",
-			cmd:               "git clean -fd",
-			wantTier:          RiskTierDangerous,
-			wantApprovals:     1,
-			wantNeedsApproval: true,
-		},
-		{
-			name:              "kubectl delete pod",
-			cmd:               "kubectl delete deployment nginx",
-			wantTier:          RiskTierDangerous,
-			wantApprovals:     1,
-			wantNeedsApproval: true,
-		},
-		{
-			name:              "docker rm",
-			cmd:               "docker rm container1",
-			wantTier:          RiskTierDangerous,
-			wantApprovals:     1,
-			wantNeedsApproval: true,
-		},
-		// Caution commands
-		{
-			name:              "git stash drop",
-			cmd:               "git stash drop",
-			wantTier:          RiskTierCaution,
-			wantApprovals:     0,
-			wantNeedsApproval: true,
-		},
-		{
-			name:              "npm uninstall",
-			cmd:               "npm uninstall lodash",
-			wantTier:          RiskTierCaution,
-			wantApprovals:     0,
-			wantNeedsApproval: true,
-		},
-		// Safe commands (no approval needed)
-		{
-			name:              "git status",
-			cmd:               "git status",
-			wantTier:          "",
-			wantApprovals:     0,
-			wantNeedsApproval: false,
-		},
-		{
-			name:              "ls",
-			cmd:               "ls -la",
-			wantTier:          "",
-			wantApprovals:     0,
-			wantNeedsApproval: false,
-		},
-		{
-			name:              "cat file",
-			cmd:               "cat README.md",
-			wantTier:          "",
-			wantApprovals:     0,
-			wantNeedsApproval: false,
-		},
-	}
-
-	for _, tt := range tests {
+	for _, tt := range classifyCommandCases {
 		t.Run(tt.name, func(t *testing.T) {
 			result := engine.ClassifyCommand(tt.cmd, "")
 
@@ -1050,10 +1053,36 @@ func TestExportClaudeHook_UsesSearchNotMatch(t *testing.T) {
 	// .search is the unanchored matcher; .match is anchored to
 	// position 0. The hook should use .search.
 	if strings.Contains(out, "if p.match(command):") {
-		t.Errorf("ExportClaudeHook still uses p.match(); should use p.search() (issue #4 follow-on).\n"+
+		t.Errorf("ExportClaudeHook still uses p.match(); should use p.search() (issue #4 follow-on).\n" +
 			"Anchored matching loses mid-command hits like `DROP DATABASE` inside `psql -c '...'`.")
 	}
 	if !strings.Contains(out, "if p.search(command):") {
 		t.Errorf("ExportClaudeHook does not use p.search(); generated classify() may be broken.")
 	}
 }
+
+func TestMergeCustomPatterns(t *testing.T) {
+	engine := NewPatternEngine()
+	rows := []*db.CustomPattern{
+		{Tier: "CRITICAL", Pattern: `^deploy\s+--prod`, Source: "human"},
+		{Tier: "critical", Pattern: `^deploy\s+--prod`, Source: "human"}, // duplicate row
+		{Tier: "extreme", Pattern: `^x`},
+		{Tier: "dangerous", Pattern: `(unclosed`},
+	}
+
+	loaded, skipped := engine.MergeCustomPatterns(rows)
+	if loaded != 1 || len(skipped) != 2 {
+		t.Fatalf("loaded=%d skipped=%v, want 1 loaded and 2 skipped", loaded, skipped)
+	}
+	if got := engine.ClassifyCommand("deploy --prod", ""); got.Tier != RiskTierCritical {
+		t.Fatalf("expected the merged pattern to classify critical, got %s", got.Tier)
+	}
+
+	// Merging again is a no-op.
+	if loaded, _ := engine.MergeCustomPatterns(rows); loaded != 0 {
+		t.Fatalf("expected a repeated merge to add nothing, got %d", loaded)
+	}
+	if ParseRiskTier("Caution") != RiskTierCaution || ParseRiskTier("nope") != "" {
+		t.Fatal("unexpected ParseRiskTier result")
+	}
+}
@@ -0,0 +1,98 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// PolicyHash identifies the classification policy: the engine's pattern set
+// and the tier overrides applied after it. Without overrides it equals the
+// engine's pattern hash.
+func PolicyHash(engine *PatternEngine, overrides []TierOverride) string {
+	patterns := engine.ComputeHash()
+	if len(overrides) == 0 {
+		return patterns
+	}
+	entries := make([]string, 0, len(overrides))
+	for _, o := range overrides {
+		entries = append(entries, o.Entry)
+	}
+	sort.Strings(entries)
+
+	h := sha256.New()
+	h.Write([]byte(patterns))
+	for _, entry := range entries {
+		h.Write([]byte{0})
+		h.Write([]byte(entry))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// PolicyHash returns the hash of the policy the creator classifies under.
+func (rc *RequestCreator) PolicyHash() string {
+	var overrides []TierOverride
+	if rc.config != nil {
+		overrides = rc.config.TierOverrides
+	}
+	return PolicyHash(rc.patternEngine, overrides)
+}
+
+// WithTierOverrides sets the tier overrides applied when a request is
+// re-classified before execution, matching the ones used at creation.
+func (e *Executor) WithTierOverrides(overrides []TierOverride) *Executor {
+	e.tierOverrides = overrides
+	return e
+}
+
+// classifyCurrent classifies a request's command under the current policy,
+// including the requestor's tier overrides.
+func (e *Executor) classifyCurrent(request *db.Request) *MatchResult {
+	classification := e.patternEngine.ClassifyCommand(request.Command.Raw, request.Command.Cwd)
+	if len(e.tierOverrides) == 0 {
+		return classification
+	}
+	var program, model string
+	if session, err := e.db.GetSession(request.RequestorSessionID); err == nil {
+		program, model = session.Program, session.Model
+	}
+	return ApplyTierOverrides(classification, e.tierOverrides,
+		OverrideSubjectFor(e.tierOverrides, program, model, request.Command.Raw, request.Command.Cwd))
+}
+
+// revalidate re-classifies an approved request under the current policy and
+// refuses execution when the tier rose above the approved one. When the
+// policy changed since the request was classified, the outcome is recorded
+// as a discrepancy (best effort) and a changed but allowed run is warned
+// about.
+func (e *Executor) revalidate(request *db.Request) error {
+	classification := e.classifyCurrent(request)
+	escalated := tierHigher(classification.Tier, request.RiskTier)
+
+	if recorded, err := e.db.GetRequestPolicy(request.ID); err == nil {
+		if current := PolicyHash(e.patternEngine, e.tierOverrides); current != recorded.PolicyHash {
+			_ = e.db.RecordPolicyDiscrepancy(&db.PolicyDiscrepancy{
+				RequestID:    request.ID,
+				RecordedHash: recorded.PolicyHash,
+				CurrentHash:  current,
+				RecordedTier: request.RiskTier,
+				CurrentTier:  classification.Tier,
+				Blocked:      escalated,
+			})
+			if !escalated {
+				fmt.Fprintf(os.Stderr, "warning: policy changed since request %s was classified; re-validated as %s, approved as %s\n",
+					request.ID, tierLabel(classification.Tier), request.RiskTier)
+			}
+		}
+	}
+
+	if escalated {
+		return fmt.Errorf("%w: approved as %s but now classified as %s",
+			ErrTierEscalated, request.RiskTier, classification.Tier)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestPolicyHash(t *testing.T) {
+	engine := &PatternEngine{}
+	if err := engine.AddPattern(RiskTierDangerous, `^true\b`, "", "test"); err != nil {
+		t.Fatal(err)
+	}
+	if got := PolicyHash(engine, nil); got != engine.ComputeHash() {
+		t.Errorf("hash without overrides = %s, want the pattern hash", got)
+	}
+
+	a, _ := ParseTierOverrides([]string{"codex-cli=dangerous", "shell=skip_caution"})
+	b, _ := ParseTierOverrides([]string{"shell=skip_caution", "codex-cli=dangerous"})
+	c, _ := ParseTierOverrides([]string{"codex-cli=critical"})
+	if PolicyHash(engine, a) != PolicyHash(engine, b) {
+		t.Error("override order changed the hash")
+	}
+	if PolicyHash(engine, a) == PolicyHash(engine, c) || PolicyHash(engine, a) == PolicyHash(engine, nil) {
+		t.Error("different overrides share a hash")
+	}
+}
+
+func TestExecuteApprovedRequest_PolicyChanged(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell execution test uses /bin/sh or $SHELL")
+	}
+	database := testutil.NewTestDB(t)
+	sess := testutil.MakeSession(t, database)
+	engine := &PatternEngine{}
+	if err := engine.AddPattern(RiskTierDangerous, `^true\b`, "", "test"); err != nil {
+		t.Fatal(err)
+	}
+	config := DefaultRequestCreatorConfig()
+	config.AgentMailEnabled = false
+	creator := NewRequestCreator(database, NewRateLimiter(database, RateLimitConfig{}), engine, config)
+
+	approved := func(cmd string) *db.Request {
+		t.Helper()
+		result, err := creator.CreateRequest(context.Background(), CreateRequestOptions{
+			SessionID:     sess.ID,
+			Command:       cmd,
+			Cwd:           t.TempDir(),
+			Justification: Justification{Reason: "test"},
+		})
+		if err != nil || result.Skipped {
+			t.Fatalf("CreateRequest(%q): %v %+v", cmd, err, result)
+		}
+		if err := database.UpdateRequestStatus(result.Request.ID, db.StatusApproved); err != nil {
+			t.Fatalf("UpdateRequestStatus: %v", err)
+		}
+		return result.Request
+	}
+	execute := func(req *db.Request) error {
+		_, err := NewExecutor(database, engine).ExecuteApprovedRequest(context.Background(), ExecuteOptions{
+			RequestID:      req.ID,
+			SessionID:      sess.ID,
+			LogDir:         t.TempDir(),
+			SuppressOutput: true,
+		})
+		return err
+	}
+
+	unchanged := approved("true unchanged")
+	if policy, err := database.GetRequestPolicy(unchanged.ID); err != nil || policy.PolicyHash != engine.ComputeHash() {
+		t.Fatalf("recorded policy = %+v, %v", policy, err)
+	}
+	if err := execute(unchanged); err != nil {
+		t.Fatalf("execute under the same policy: %v", err)
+	}
+	if list, _ := database.ListPolicyDiscrepancies(unchanged.ID); len(list) != 0 {
+		t.Errorf("discrepancy logged without a policy change: %+v", list)
+	}
+
+	// A pattern change that leaves the tier alone is logged but allowed.
+	sameTier := approved("true same tier")
+	if err := engine.AddPattern(RiskTierCritical, `^false\b`, "", "test"); err != nil {
+		t.Fatal(err)
+	}
+	if err := execute(sameTier); err != nil {
+		t.Fatalf("execute after unrelated change: %v", err)
+	}
+	list, err := database.ListPolicyDiscrepancies(sameTier.ID)
+	if err != nil || len(list) != 1 || list[0].Blocked || list[0].CurrentTier != RiskTierDangerous {
+		t.Fatalf("discrepancies = %+v, %v", list, err)
+	}
+
+	// One that raises the tier blocks execution.
+	raised := approved("true raised")
+	if err := engine.AddPattern(RiskTierCritical, `^true\s+raised`, "", "test"); err != nil {
+		t.Fatal(err)
+	}
+	if err := execute(raised); !errors.Is(err, ErrTierEscalated) {
+		t.Fatalf("expected ErrTierEscalated, got %v", err)
+	}
+	list, err = database.ListPolicyDiscrepancies(raised.ID)
+	if err != nil || len(list) != 1 || !list[0].Blocked || list[0].RecordedTier != RiskTierDangerous || list[0].CurrentTier != RiskTierCritical {
+		t.Fatalf("discrepancies = %+v, %v", list, err)
+	}
+}
+
+func TestExecutorCanExecute_TierOverrides(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	sess := testutil.MakeSession(t, database, testutil.WithProgram("codex-cli"))
+	req := testutil.MakeRequest(t, database, sess, testutil.WithCommand("rm notes.txt", "/tmp", false), testutil.WithRisk(db.RiskTierCaution))
+	if err := database.UpdateRequestStatus(req.ID, db.StatusApproved); err != nil {
+		t.Fatal(err)
+	}
+
+	exec := NewExecutor(database, nil)
+	if ok, reason := exec.CanExecute(req.ID); !ok {
+		t.Fatalf("CanExecute without overrides: %s", reason)
+	}
+	overrides, _ := ParseTierOverrides([]string{"codex-cli=dangerous"})
+	if ok, _ := exec.WithTierOverrides(overrides).CanExecute(req.ID); ok {
+		t.Error("an override added after approval should block execution")
+	}
+}
@@ -0,0 +1,234 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Policy is a classification policy: a pattern set and the tier overrides
+// applied after it. It is what PolicyHash identifies.
+type Policy struct {
+	Engine        *PatternEngine
+	TierOverrides []TierOverride
+}
+
+// PolicyScenario is a command to classify under a policy, with the context
+// it would run in.
+type PolicyScenario struct {
+	// Name labels the scenario in reports.
+	Name string `json:"name,omitempty"`
+	// Command is the command to classify (required).
+	Command string `json:"command"`
+	// Cwd is the directory the command runs in; relative paths in the
+	// command are resolved against it.
+	Cwd string `json:"cwd,omitempty"`
+	// Branch is the checked-out branch. When empty, the branch in Cwd is
+	// used if an override selects on it.
+	Branch string `json:"branch,omitempty"`
+	// Program and Model describe the requesting session.
+	Program string `json:"program,omitempty"`
+	Model   string `json:"model,omitempty"`
+	// Expect is the tier the scenario should end with (safe, caution,
+	// dangerous, critical, or none for an unmatched command); empty
+	// expects nothing.
+	Expect string `json:"expect,omitempty"`
+}
+
+// Rule kinds reported in FiredRule.Kind.
+const (
+	FiredRulePattern      = "pattern"
+	FiredRuleFallback     = "fallback"
+	FiredRuleTierOverride = "tier_override"
+)
+
+// FiredRule is a rule that matched a scenario.
+type FiredRule struct {
+	// Kind is one of the FiredRule* constants.
+	Kind string `json:"kind"`
+	// Tier is the pattern's tier, or the tier an override raises to.
+	Tier RiskTier `json:"tier,omitempty"`
+	// Rule is the pattern, or the override entry.
+	Rule        string `json:"rule"`
+	Description string `json:"description,omitempty"`
+	Source      string `json:"source,omitempty"`
+	Pack        string `json:"pack,omitempty"`
+	// Segment is the part of a compound command the pattern matched.
+	Segment string `json:"segment,omitempty"`
+	// Decisive is set for the rules that determined the final tier; the
+	// others matched but were outranked.
+	Decisive bool `json:"decisive"`
+}
+
+// PolicyTestResult is the outcome of classifying a scenario.
+type PolicyTestResult struct {
+	Scenario PolicyScenario `json:"scenario"`
+	// Branch is the branch the overrides were matched against.
+	Branch string `json:"branch,omitempty"`
+	// Fired lists every rule that matched, in precedence order.
+	Fired []FiredRule `json:"fired"`
+	// Tier is the final tier ("none" when no rule matched).
+	Tier          string `json:"tier"`
+	NeedsApproval bool   `json:"needs_approval"`
+	MinApprovals  int    `json:"min_approvals"`
+	// Notes explains adjustments to the matched tier (parse errors, path
+	// escapes, overrides).
+	Notes []string `json:"notes,omitempty"`
+	// Passed reports whether Tier is the expected one; always true when
+	// nothing is expected.
+	Passed bool `json:"passed"`
+}
+
+// ScenarioTier returns the tier label used by policy tests: the tier, or
+// "none" for an unmatched command.
+func ScenarioTier(t RiskTier) string {
+	if t == "" {
+		return "none"
+	}
+	return string(t)
+}
+
+// ValidateScenario checks a scenario before it is run.
+func ValidateScenario(s PolicyScenario) error {
+	if strings.TrimSpace(s.Command) == "" {
+		return fmt.Errorf("scenario %q: command is required", s.Name)
+	}
+	switch strings.ToLower(s.Expect) {
+	case "", "none", RiskSafe, string(RiskTierCaution), string(RiskTierDangerous), string(RiskTierCritical):
+		return nil
+	}
+	return fmt.Errorf("scenario %q: unknown expected tier %q (want safe, caution, dangerous, critical or none)", s.Name, s.Expect)
+}
+
+// Test classifies a scenario under the policy, as request creation would,
+// and reports every rule that fired.
+func (p *Policy) Test(s PolicyScenario) *PolicyTestResult {
+	classification := p.Engine.ClassifyCommand(s.Command, s.Cwd)
+
+	subject := OverrideSubject{Program: s.Program, Model: s.Model, Branch: s.Branch}
+	if subject.Branch == "" {
+		subject = OverrideSubjectFor(p.TierOverrides, s.Program, s.Model, s.Command, s.Cwd)
+	}
+	final := ApplyTierOverrides(classification, p.TierOverrides, subject)
+
+	result := &PolicyTestResult{
+		Scenario:      s,
+		Branch:        subject.Branch,
+		Fired:         p.Engine.firedPatterns(s.Command, s.Cwd, classification),
+		Tier:          ScenarioTier(final.Tier),
+		NeedsApproval: final.NeedsApproval,
+		MinApprovals:  final.MinApprovals,
+		Notes:         final.Explanation,
+	}
+	if strings.HasPrefix(classification.MatchedPattern, "fallback_") {
+		result.Fired = append(result.Fired, FiredRule{
+			Kind:     FiredRuleFallback,
+			Tier:     classification.Tier,
+			Rule:     classification.MatchedPattern,
+			Decisive: true,
+		})
+	}
+
+	// Overrides only apply to commands that need approval.
+	if classification.NeedsApproval {
+		for _, o := range p.TierOverrides {
+			if !o.Matches(subject) {
+				continue
+			}
+			rule := FiredRule{Kind: FiredRuleTierOverride, Tier: o.MinTier, Rule: o.Entry}
+			if o.SkipCaution {
+				rule.Decisive = final.Tier == RiskTierCaution && !final.NeedsApproval
+			} else {
+				rule.Decisive = final.Tier == o.MinTier && tierRank(classification.Tier) < tierRank(o.MinTier)
+			}
+			result.Fired = append(result.Fired, rule)
+		}
+	}
+	if final.Tier != classification.Tier {
+		// An override decided the tier, not the patterns.
+		for i := range result.Fired {
+			if result.Fired[i].Kind != FiredRuleTierOverride {
+				result.Fired[i].Decisive = false
+			}
+		}
+	}
+
+	result.Passed = s.Expect == "" || strings.EqualFold(s.Expect, result.Tier)
+	return result
+}
+
+// firedPatterns lists every pattern matching the command, segment by
+// segment as ClassifyCommand splits it, marking the ones the
+// classification was decided by.
+func (e *PatternEngine) firedPatterns(cmd, cwd string, classification *MatchResult) []FiredRule {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	normalized := NormalizeCommand(cmd)
+	var checks []string
+	if normalized.IsCompound && len(normalized.Segments) > 1 {
+		for _, segment := range normalized.Segments {
+			if cwd != "" {
+				segment = ResolvePathsInCommand(segment, cwd)
+			}
+			if xargsCmd := ExtractXargsCommand(segment); xargsCmd != "" {
+				segment = xargsCmd
+			}
+			checks = append(checks, segment)
+		}
+	} else {
+		check := cmd
+		if normalized.Primary != "" {
+			check = normalized.Primary
+		} else if len(normalized.Segments) > 0 {
+			check = normalized.Segments[0]
+		}
+		if cwd != "" {
+			check = ResolvePathsInCommand(check, cwd)
+		}
+		checks = []string{check}
+	}
+
+	decided := func(segment, pattern string) bool {
+		if classification.Tier == "" {
+			return false
+		}
+		if len(classification.MatchedSegments) == 0 {
+			return pattern == classification.MatchedPattern
+		}
+		for _, seg := range classification.MatchedSegments {
+			if seg.Segment == segment && seg.MatchedPattern == pattern && seg.Tier == classification.Tier {
+				return true
+			}
+		}
+		return false
+	}
+
+	var fired []FiredRule
+	for _, check := range checks {
+		segment := ""
+		if len(checks) > 1 {
+			segment = check
+		}
+		first := true
+		for _, list := range [][]*Pattern{e.safe, e.critical, e.dangerous, e.caution} {
+			for _, p := range list {
+				if !p.Compiled.MatchString(check) {
+					continue
+				}
+				fired = append(fired, FiredRule{
+					Kind:        FiredRulePattern,
+					Tier:        p.Tier,
+					Rule:        p.Pattern,
+					Description: p.Description,
+					Source:      p.Source,
+					Pack:        p.Pack,
+					Segment:     segment,
+					// Only the first match of a segment decides it.
+					Decisive: first && decided(check, p.Pattern),
+				})
+				first = false
+			}
+		}
+	}
+	return fired
+}
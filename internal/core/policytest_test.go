@@ -0,0 +1,142 @@
+package core
+
+import (
+	"testing"
+)
+
+func testPolicy(t *testing.T, overrides ...string) *Policy {
+	t.Helper()
+	engine := &PatternEngine{}
+	for _, p := range []struct {
+		tier    RiskTier
+		pattern string
+	}{
+		{RiskTier(RiskSafe), `^ls\b`},
+		{RiskTierCritical, `^git\s+push\b.*--force`},
+		{RiskTierDangerous, `^git\s+push\b`},
+		{RiskTierCaution, `^rm\s`},
+	} {
+		if err := engine.AddPattern(p.tier, p.pattern, "", "test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	parsed, err := ParseTierOverrides(overrides)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Policy{Engine: engine, TierOverrides: parsed}
+}
+
+func decisive(fired []FiredRule) []string {
+	var rules []string
+	for _, f := range fired {
+		if f.Decisive {
+			rules = append(rules, f.Rule)
+		}
+	}
+	return rules
+}
+
+func TestPolicyTest_FiredPatterns(t *testing.T) {
+	policy := testPolicy(t)
+
+	r := policy.Test(PolicyScenario{Command: "git push --force origin main", Expect: "critical"})
+	if r.Tier != "critical" || !r.Passed || r.MinApprovals != 2 || len(r.Fired) != 2 {
+		t.Fatalf("force push = %+v", r)
+	}
+	if r.Fired[0].Tier != RiskTierCritical || !r.Fired[0].Decisive || r.Fired[1].Tier != RiskTierDangerous || r.Fired[1].Decisive {
+		t.Errorf("fired = %+v", r.Fired)
+	}
+
+	// Each segment of a compound command is matched on its own; the
+	// highest tier decides.
+	r = policy.Test(PolicyScenario{Command: "ls && rm notes.txt", Expect: "dangerous"})
+	if r.Tier != "caution" || r.Passed {
+		t.Errorf("compound = %s, passed %v", r.Tier, r.Passed)
+	}
+	if len(r.Fired) != 2 || r.Fired[0].Segment != "ls" || r.Fired[1].Segment == "" {
+		t.Errorf("compound fired = %+v", r.Fired)
+	}
+	if got := decisive(r.Fired); len(got) != 1 || got[0] != `^rm\s` {
+		t.Errorf("compound decisive = %v", got)
+	}
+
+	r = policy.Test(PolicyScenario{Command: "echo hello", Expect: "none"})
+	if r.Tier != "none" || !r.Passed || len(r.Fired) != 0 {
+		t.Errorf("unmatched = %+v", r)
+	}
+
+	r = policy.Test(PolicyScenario{Command: `psql -c "DELETE FROM users"`})
+	if r.Tier != "critical" || len(r.Fired) != 1 || r.Fired[0].Kind != FiredRuleFallback || !r.Fired[0].Decisive {
+		t.Errorf("fallback = %+v", r)
+	}
+}
+
+func TestPolicyTest_TierOverrides(t *testing.T) {
+	policy := testPolicy(t, "branch:main=critical", "shell=skip_caution", "model:gpt-4o*=dangerous")
+
+	r := policy.Test(PolicyScenario{Command: "git push origin main", Branch: "main", Program: "codex-cli"})
+	if r.Tier != "critical" || r.Branch != "main" {
+		t.Fatalf("push on main = %+v", r)
+	}
+	if got := decisive(r.Fired); len(got) != 1 || got[0] != "branch:main=critical" {
+		t.Errorf("decisive = %v (fired %+v)", got, r.Fired)
+	}
+
+	r = policy.Test(PolicyScenario{Command: "git push origin feature", Branch: "feature"})
+	if r.Tier != "dangerous" || len(r.Fired) != 1 || !r.Fired[0].Decisive {
+		t.Errorf("push on a feature branch = %+v", r)
+	}
+
+	r = policy.Test(PolicyScenario{Command: "rm notes.txt", Program: "shell"})
+	if r.Tier != "caution" || r.NeedsApproval || len(r.Fired) != 2 || !r.Fired[1].Decisive || len(r.Notes) == 0 {
+		t.Errorf("skip_caution = %+v", r)
+	}
+
+	// A matching override that does not raise the tier fired but did not
+	// decide it.
+	r = policy.Test(PolicyScenario{Command: "git push --force", Model: "gpt-4o"})
+	if r.Tier != "critical" || len(r.Fired) != 3 || r.Fired[2].Kind != FiredRuleTierOverride || r.Fired[2].Decisive || !r.Fired[0].Decisive {
+		t.Errorf("model override = %+v", r.Fired)
+	}
+
+	// Overrides never apply to commands that need no approval.
+	r = policy.Test(PolicyScenario{Command: "ls", Branch: "main"})
+	if r.Tier != "safe" || len(r.Fired) != 1 {
+		t.Errorf("safe command = %+v", r)
+	}
+}
+
+func TestValidateScenario(t *testing.T) {
+	if err := ValidateScenario(PolicyScenario{Name: "empty"}); err == nil {
+		t.Error("expected an error without a command")
+	}
+	if err := ValidateScenario(PolicyScenario{Command: "ls", Expect: "high"}); err == nil {
+		t.Error("expected an error for an unknown tier")
+	}
+	for _, expect := range []string{"", "none", "safe", "caution", "dangerous", "critical"} {
+		if err := ValidateScenario(PolicyScenario{Command: "ls", Expect: expect}); err != nil {
+			t.Errorf("expect %q: %v", expect, err)
+		}
+	}
+}
+
+func TestPatternEngineClone(t *testing.T) {
+	engine := NewPatternEngine()
+	if err := engine.EnablePack("kubernetes"); err != nil {
+		t.Fatal(err)
+	}
+	clone := engine.Clone()
+	if clone.ComputeHash() != engine.ComputeHash() || len(clone.EnabledPacks()) != 1 {
+		t.Fatal("clone differs from the original")
+	}
+	if err := clone.AddPattern(RiskTierCritical, `^make\s+deploy`, "", "test"); err != nil {
+		t.Fatal(err)
+	}
+	if clone.ComputeHash() == engine.ComputeHash() {
+		t.Error("adding to the clone did not change it")
+	}
+	if engine.ClassifyCommand("make deploy", "").Tier == RiskTierCritical {
+		t.Error("adding to the clone changed the original")
+	}
+}
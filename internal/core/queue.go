@@ -0,0 +1,204 @@
+package core
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// ReviewSLA is how soon a request of each tier should get its first
+// review. A zero duration means the tier has no SLA.
+type ReviewSLA struct {
+	Critical  time.Duration
+	Dangerous time.Duration
+	Caution   time.Duration
+}
+
+// For returns the SLA of a tier.
+func (s ReviewSLA) For(tier RiskTier) time.Duration {
+	switch tier {
+	case RiskTierCritical:
+		return s.Critical
+	case RiskTierDangerous:
+		return s.Dangerous
+	case RiskTierCaution:
+		return s.Caution
+	default:
+		return 0
+	}
+}
+
+// SLAState is a request's standing against its tier's review SLA.
+type SLAState struct {
+	DueAt time.Time `json:"due_at"`
+	// Breached is set when the first review came after DueAt, or when the
+	// request is still unreviewed past it.
+	Breached bool `json:"breached"`
+}
+
+// Evaluate reports where a request stands against the SLA, or nil when its
+// tier has none. firstReview is the earliest review, nil if none; a request
+// resolved without a review (auto-approved, cancelled, timed out) is
+// measured to when it resolved.
+func (s ReviewSLA) Evaluate(tier RiskTier, createdAt time.Time, firstReview, resolvedAt *time.Time, now time.Time) *SLAState {
+	window := s.For(tier)
+	if window <= 0 {
+		return nil
+	}
+	due := createdAt.Add(window)
+	end := now
+	switch {
+	case firstReview != nil:
+		end = *firstReview
+	case resolvedAt != nil:
+		end = *resolvedAt
+	}
+	return &SLAState{DueAt: due, Breached: end.After(due)}
+}
+
+// Queue priority weights. Tier dominates, the risk score separates
+// requests within a tier, and waiting time, a blocked requestor and an SLA
+// breach push a request up the queue.
+const (
+	priorityWaitingPoints  = 20
+	priorityBreachedPoints = 30
+	priorityMaxAgePoints   = 30
+)
+
+var tierPriorityPoints = map[RiskTier]int{
+	RiskTierCaution:   10,
+	RiskTierDangerous: 25,
+	RiskTierCritical:  40,
+}
+
+// QueueItem is a pending request with what its queue priority is derived
+// from.
+type QueueItem struct {
+	Request *db.Request
+	// Waiting is set when the requestor is blocked waiting for a decision.
+	Waiting bool
+	// SLA is the request's SLA standing, nil when its tier has none.
+	SLA *SLAState
+	// Priority orders the review queue, highest first.
+	Priority int
+}
+
+// QueuePriority computes the priority of a pending request: tier points,
+// half the risk score, a point per minute waited (up to 30), and bonuses
+// for a blocked requestor and an SLA breach.
+func QueuePriority(req *db.Request, waiting bool, sla *SLAState, now time.Time) int {
+	if req == nil {
+		return 0
+	}
+	p := tierPriorityPoints[req.RiskTier] + req.RiskScore/2
+	if age := now.Sub(req.CreatedAt); age > 0 {
+		p += min(int(age/time.Minute), priorityMaxAgePoints)
+	}
+	if waiting {
+		p += priorityWaitingPoints
+	}
+	if sla != nil && sla.Breached {
+		p += priorityBreachedPoints
+	}
+	return p
+}
+
+// BuildQueue annotates pending requests with their SLA standing and
+// priority. Lookups are best effort: a failed query leaves requests
+// unreviewed and not waiting.
+func BuildQueue(database *db.DB, requests []*db.Request, sla ReviewSLA, now time.Time) []*QueueItem {
+	ids := make([]string, 0, len(requests))
+	for _, r := range requests {
+		ids = append(ids, r.ID)
+	}
+	firstReviews, err := database.FirstReviewTimes(ids)
+	if err != nil {
+		firstReviews = map[string]time.Time{}
+	}
+	waiting, err := database.WaitingRequestIDs(ids)
+	if err != nil {
+		waiting = map[string]bool{}
+	}
+
+	items := make([]*QueueItem, 0, len(requests))
+	for _, r := range requests {
+		var first *time.Time
+		if t, ok := firstReviews[r.ID]; ok {
+			first = &t
+		}
+		item := &QueueItem{
+			Request: r,
+			Waiting: waiting[r.ID],
+			SLA:     sla.Evaluate(r.RiskTier, r.CreatedAt, first, r.ResolvedAt, now),
+		}
+		item.Priority = QueuePriority(r, item.Waiting, item.SLA, now)
+		items = append(items, item)
+	}
+	return items
+}
+
+// SortQueueByPriority orders queue items by priority, highest first,
+// keeping the existing order for ties.
+func SortQueueByPriority(items []*QueueItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Priority > items[j].Priority
+	})
+}
+
+// TierSLAStats summarises SLA compliance for one tier.
+type TierSLAStats struct {
+	TargetMinutes float64 `json:"target_minutes"`
+	// Requests counts requests measured against the SLA.
+	Requests int `json:"requests"`
+	Breached int `json:"breached"`
+	// BreachPercent is Breached as a share of Requests.
+	BreachPercent float64 `json:"breach_percent"`
+	// MedianFirstReviewMinutes is the median time to first review among
+	// reviewed requests.
+	MedianFirstReviewMinutes float64 `json:"median_first_review_minutes"`
+}
+
+// ComputeSLAStats summarises SLA compliance per tier for the tiers that
+// have an SLA.
+func ComputeSLAStats(timings []*db.ReviewTiming, sla ReviewSLA, now time.Time) map[string]*TierSLAStats {
+	stats := make(map[string]*TierSLAStats)
+	reviewMinutes := make(map[string][]float64)
+	for _, t := range timings {
+		state := sla.Evaluate(t.RiskTier, t.CreatedAt, t.FirstReviewAt, t.ResolvedAt, now)
+		if state == nil {
+			continue
+		}
+		key := string(t.RiskTier)
+		s, ok := stats[key]
+		if !ok {
+			s = &TierSLAStats{TargetMinutes: sla.For(t.RiskTier).Minutes()}
+			stats[key] = s
+		}
+		s.Requests++
+		if state.Breached {
+			s.Breached++
+		}
+		if t.FirstReviewAt != nil {
+			reviewMinutes[key] = append(reviewMinutes[key], t.FirstReviewAt.Sub(t.CreatedAt).Minutes())
+		}
+	}
+	for key, s := range stats {
+		s.BreachPercent = float64(s.Breached) * 100 / float64(s.Requests)
+		s.MedianFirstReviewMinutes = median(reviewMinutes[key])
+	}
+	return stats
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,133 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestReviewSLAEvaluate(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	created := now.Add(-30 * time.Minute)
+	sla := ReviewSLA{Dangerous: 10 * time.Minute}
+	at := func(d time.Duration) *time.Time {
+		t := created.Add(d)
+		return &t
+	}
+
+	cases := []struct {
+		name        string
+		tier        RiskTier
+		firstReview *time.Time
+		resolvedAt  *time.Time
+		want        *bool
+	}{
+		{"no SLA for tier", RiskTierCritical, nil, nil, nil},
+		{"unreviewed past due", RiskTierDangerous, nil, nil, ptrBool(true)},
+		{"reviewed in time", RiskTierDangerous, at(5 * time.Minute), nil, ptrBool(false)},
+		{"reviewed late", RiskTierDangerous, at(15 * time.Minute), at(16 * time.Minute), ptrBool(true)},
+		{"resolved without review in time", RiskTierDangerous, nil, at(time.Minute), ptrBool(false)},
+	}
+	for _, tc := range cases {
+		got := sla.Evaluate(tc.tier, created, tc.firstReview, tc.resolvedAt, now)
+		if tc.want == nil {
+			if got != nil {
+				t.Errorf("%s: expected no SLA, got %+v", tc.name, got)
+			}
+			continue
+		}
+		if got == nil || got.Breached != *tc.want || !got.DueAt.Equal(created.Add(10*time.Minute)) {
+			t.Errorf("%s: Evaluate = %+v, want breached %v", tc.name, got, *tc.want)
+		}
+	}
+}
+
+func ptrBool(b bool) *bool { return &b }
+
+func TestQueuePriority(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	req := &db.Request{RiskTier: RiskTierDangerous, RiskScore: 60, CreatedAt: now.Add(-10 * time.Minute)}
+
+	if got := QueuePriority(req, false, nil, now); got != 25+30+10 {
+		t.Errorf("base priority = %d, want 65", got)
+	}
+	if got := QueuePriority(req, true, &SLAState{Breached: true}, now); got != 65+20+30 {
+		t.Errorf("waiting and breached priority = %d, want 115", got)
+	}
+	old := &db.Request{RiskTier: RiskTierCaution, CreatedAt: now.Add(-5 * time.Hour)}
+	if got := QueuePriority(old, false, nil, now); got != 10+30 {
+		t.Errorf("age points should be capped, got %d", got)
+	}
+	if got := QueuePriority(nil, true, nil, now); got != 0 {
+		t.Errorf("nil request priority = %d", got)
+	}
+}
+
+func TestBuildQueue(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	sess := testutil.MakeSession(t, database)
+	reviewer := testutil.MakeSession(t, database, testutil.WithAgent("Reviewer"))
+	now := time.Now().UTC()
+
+	calm := testutil.MakeRequest(t, database, sess, testutil.WithRisk(RiskTierDangerous), testutil.WithRiskScore(80))
+	blocked := testutil.MakeRequest(t, database, sess, testutil.WithRisk(RiskTierCaution), testutil.WithRiskScore(20))
+	reviewed := testutil.MakeRequest(t, database, sess, testutil.WithRisk(RiskTierDangerous), testutil.WithRiskScore(50))
+	if err := database.MarkRequestorWaiting(blocked.ID, sess.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.CreateReview(&db.Review{
+		RequestID:         reviewed.ID,
+		ReviewerSessionID: reviewer.ID,
+		ReviewerAgent:     reviewer.AgentName,
+		ReviewerModel:     reviewer.Model,
+		Decision:          db.DecisionApprove,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// An hour from now every unreviewed request has breached its SLA.
+	later := now.Add(time.Hour)
+	items := BuildQueue(database, []*db.Request{calm, blocked, reviewed}, ReviewSLA{Dangerous: 10 * time.Minute, Caution: 10 * time.Minute}, later)
+	byID := make(map[string]*QueueItem)
+	for _, it := range items {
+		byID[it.Request.ID] = it
+	}
+	if !byID[blocked.ID].Waiting || byID[calm.ID].Waiting {
+		t.Errorf("unexpected waiting flags: %+v / %+v", byID[blocked.ID], byID[calm.ID])
+	}
+	if !byID[calm.ID].SLA.Breached || !byID[blocked.ID].SLA.Breached || byID[reviewed.ID].SLA.Breached {
+		t.Errorf("unexpected breaches: calm %+v, blocked %+v, reviewed %+v", byID[calm.ID].SLA, byID[blocked.ID].SLA, byID[reviewed.ID].SLA)
+	}
+
+	SortQueueByPriority(items)
+	// calm: 25+40+30+30, blocked: 10+10+30+20+30, reviewed: 25+25+30.
+	if items[0].Request.ID != calm.ID || items[1].Request.ID != blocked.ID || items[2].Request.ID != reviewed.ID {
+		t.Errorf("unexpected order: %d, %d, %d", items[0].Priority, items[1].Priority, items[2].Priority)
+	}
+}
+
+func TestComputeSLAStats(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	created := now.Add(-time.Hour)
+	at := func(d time.Duration) *time.Time {
+		t := created.Add(d)
+		return &t
+	}
+	timings := []*db.ReviewTiming{
+		{RiskTier: RiskTierDangerous, CreatedAt: created, FirstReviewAt: at(2 * time.Minute)},
+		{RiskTier: RiskTierDangerous, CreatedAt: created, FirstReviewAt: at(20 * time.Minute)},
+		{RiskTier: RiskTierDangerous, CreatedAt: created, FirstReviewAt: at(4 * time.Minute)},
+		{RiskTier: RiskTierDangerous, CreatedAt: created}, // still waiting
+		{RiskTier: RiskTierCaution, CreatedAt: created, ResolvedAt: at(time.Minute)},
+	}
+	stats := ComputeSLAStats(timings, ReviewSLA{Dangerous: 10 * time.Minute}, now)
+	if len(stats) != 1 {
+		t.Fatalf("expected stats only for tiers with an SLA, got %v", stats)
+	}
+	d := stats["dangerous"]
+	if d.TargetMinutes != 10 || d.Requests != 4 || d.Breached != 2 || d.BreachPercent != 50 || d.MedianFirstReviewMinutes != 4 {
+		t.Errorf("unexpected dangerous stats: %+v", d)
+	}
+}
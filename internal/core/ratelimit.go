@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/Dicklesworthstone/slb/internal/clock"
 	"github.com/Dicklesworthstone/slb/internal/db"
 )
 
@@ -98,25 +99,32 @@ type RateLimiter struct {
 	db  *db.DB
 	cfg RateLimitConfig
 
-	now func() time.Time
+	clock clock.Clock
 }
 
 // NewRateLimiter constructs a rate limiter.
 func NewRateLimiter(database *db.DB, cfg RateLimitConfig) *RateLimiter {
 	return &RateLimiter{
-		db:  database,
-		cfg: cfg.normalized(),
-		now: time.Now,
+		db:    database,
+		cfg:   cfg.normalized(),
+		clock: dbClock(database),
 	}
 }
 
+// WithClock sets the clock used for rate limit windows. By default the
+// database clock is used.
+func (rl *RateLimiter) WithClock(c clock.Clock) *RateLimiter {
+	rl.clock = clock.OrReal(c)
+	return rl
+}
+
 // ResetRateLimits resets the per-minute counter for a session by recording a reset timestamp.
 // Callers can expose this via a human-only CLI command (e.g. `slb session reset-limits`).
 func (rl *RateLimiter) ResetRateLimits(sessionID string) (time.Time, error) {
 	if sessionID == "" {
 		return time.Time{}, fmt.Errorf("session_id is required")
 	}
-	return rl.db.ResetSessionRateLimits(sessionID, rl.now().UTC())
+	return rl.db.ResetSessionRateLimits(sessionID, rl.clock.Now().UTC())
 }
 
 // CheckRateLimit checks whether the session may submit a new request.
@@ -126,7 +134,7 @@ func (rl *RateLimiter) CheckRateLimit(sessionID string) (*RateLimitResult, error
 	}
 	cfg := rl.cfg.normalized()
 
-	now := rl.now().UTC()
+	now := rl.clock.Now().UTC()
 	windowStart := now.Add(-time.Minute)
 
 	if resetAt, err := rl.db.GetSessionRateLimitResetAt(sessionID); err != nil {
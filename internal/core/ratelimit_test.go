@@ -206,8 +206,8 @@ func TestNewRateLimiter(t *testing.T) {
 	if rl.cfg.MaxPendingPerSession != 10 {
 		t.Errorf("cfg.MaxPendingPerSession = %d, want 10", rl.cfg.MaxPendingPerSession)
 	}
-	if rl.now == nil {
-		t.Error("RateLimiter.now function not set")
+	if rl.clock == nil {
+		t.Error("RateLimiter clock not set")
 	}
 }
 
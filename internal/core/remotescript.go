@@ -0,0 +1,354 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// Remote script errors.
+var (
+	ErrRemoteScriptUnpinned   = errors.New("remote script was not pinned")
+	ErrRemoteScriptUnverified = errors.New("remote script could not be verified")
+	ErrRemoteScriptChanged    = errors.New("remote script changed since the request was created")
+)
+
+const (
+	// MaxRemoteScriptBytes caps how much of a remote script is fetched.
+	MaxRemoteScriptBytes = 1 << 20
+	// remoteScriptPreviewBytes caps the preview kept for reviewers.
+	remoteScriptPreviewBytes = 4 << 10
+	// remoteScriptFetchTimeout bounds one fetch.
+	remoteScriptFetchTimeout = 15 * time.Second
+)
+
+// RemoteScriptPipe is a download piped straight into a shell, such as
+// `curl -fsSL https://example.com/install.sh | bash`.
+type RemoteScriptPipe struct {
+	// URL is the downloaded script.
+	URL string `json:"url"`
+	// Fetcher is the download tool: curl or wget.
+	Fetcher string `json:"fetcher"`
+	// Shell is the shell that runs it.
+	Shell string `json:"shell"`
+}
+
+var (
+	urlArgRe = regexp.MustCompile(`^https?://`)
+	// fetchSubstRe matches a download inside a process or command
+	// substitution: bash <(curl ...), sh -c "$(curl ...)".
+	fetchSubstRe = regexp.MustCompile(`(?:<\(|\$\()\s*((?:curl|wget)\s[^)]*)\)`)
+)
+
+// DetectRemoteScripts finds downloads that are run by a shell without being
+// saved first: curl or wget piped to a shell, or fed to one through a
+// process or command substitution.
+func DetectRemoteScripts(cmd string) []RemoteScriptPipe {
+	var pipes []RemoteScriptPipe
+	seen := make(map[string]bool)
+	for _, site := range remoteFetchSites(cmd) {
+		if !seen[site.pipe.URL] {
+			seen[site.pipe.URL] = true
+			pipes = append(pipes, site.pipe)
+		}
+	}
+	return pipes
+}
+
+// remoteFetchSite is one download run by a shell, with the download command
+// as written in the command line.
+type remoteFetchSite struct {
+	pipe  RemoteScriptPipe
+	fetch string
+}
+
+// remoteFetchSites returns every download in cmd that a shell runs, in
+// order, repeats included.
+func remoteFetchSites(cmd string) []remoteFetchSite {
+	var sites []remoteFetchSite
+	add := func(fetch, shell string) {
+		if p := remoteFetch(fetch); p != nil {
+			p.Shell = shell
+			sites = append(sites, remoteFetchSite{pipe: *p, fetch: strings.TrimSpace(fetch)})
+		}
+	}
+
+	for _, st := range ScriptStatements(cmd) {
+		for _, seg := range splitCompoundShellAware(st.Text) {
+			parts := splitPipeline(seg, true)
+			for i, part := range parts {
+				shell := pipeShell(part)
+				if shell == "" {
+					continue
+				}
+				if i > 0 {
+					add(parts[i-1], shell)
+				}
+				for _, m := range fetchSubstRe.FindAllStringSubmatch(part, -1) {
+					add(m[1], shell)
+				}
+			}
+		}
+	}
+	return sites
+}
+
+// commandWords returns a command's words with wrappers (sudo, env, ...),
+// their flags and leading VAR=value assignments removed.
+func commandWords(part string) []string {
+	words, err := ParseCommandToArgv(part)
+	if err != nil {
+		words = strings.Fields(part)
+	}
+	wrapped := false
+	for len(words) > 0 {
+		w := words[0]
+		switch {
+		case isWrapperPrefix(w):
+			wrapped = true
+		case wrapped && strings.HasPrefix(w, "-"):
+		case envAssignPattern.MatchString(w):
+		default:
+			return words
+		}
+		words = words[1:]
+	}
+	return nil
+}
+
+func isWrapperPrefix(w string) bool {
+	for _, p := range wrapperPrefixes {
+		if w == p {
+			return true
+		}
+	}
+	return false
+}
+
+// pipeShell returns the shell a pipeline part runs, or "".
+func pipeShell(part string) string {
+	words := commandWords(part)
+	if len(words) == 0 {
+		return ""
+	}
+	name := filepath.Base(words[0])
+	for _, shell := range shellExecutors {
+		if name == shell {
+			return name
+		}
+	}
+	return ""
+}
+
+// remoteFetch returns the download a curl or wget command makes, or nil.
+func remoteFetch(part string) *RemoteScriptPipe {
+	words := commandWords(part)
+	if len(words) == 0 {
+		return nil
+	}
+	fetcher := filepath.Base(words[0])
+	if fetcher != "curl" && fetcher != "wget" {
+		return nil
+	}
+	for _, w := range words[1:] {
+		if urlArgRe.MatchString(w) {
+			return &RemoteScriptPipe{URL: w, Fetcher: fetcher}
+		}
+	}
+	return nil
+}
+
+// applyRemoteScripts raises a command that pipes a download into a shell to
+// at least dangerous: its real content is only known once fetched.
+func applyRemoteScripts(res *MatchResult, cmd string) *MatchResult {
+	pipes := DetectRemoteScripts(cmd)
+	if len(pipes) == 0 {
+		return res
+	}
+	for _, p := range pipes {
+		res.Explanation = append(res.Explanation, fmt.Sprintf("runs %s from %s with %s; its content is pinned by hash for review", p.URL, p.Fetcher, p.Shell))
+	}
+	if tierRank(res.Tier) < tierRank(RiskTierDangerous) {
+		res.Explanation = append(res.Explanation, fmt.Sprintf("tier raised from %s to %s: remote script piped to a shell", tierLabel(res.Tier), RiskTierDangerous))
+		res.Tier = RiskTierDangerous
+		res.MinApprovals = tierApprovals(RiskTierDangerous)
+		res.NeedsApproval = true
+		res.IsSafe = false
+	}
+	return res
+}
+
+// FetchRemoteScript downloads a remote script, up to MaxRemoteScriptBytes.
+func FetchRemoteScript(ctx context.Context, url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, remoteScriptFetchTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", url, err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: %s", url, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, MaxRemoteScriptBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", url, err)
+	}
+	if len(body) > MaxRemoteScriptBytes {
+		return nil, fmt.Errorf("fetching %s: larger than %d bytes", url, MaxRemoteScriptBytes)
+	}
+	return body, nil
+}
+
+// PinRemoteScript fetches a remote script and records its hash and a
+// preview for reviewers.
+func PinRemoteScript(ctx context.Context, p RemoteScriptPipe) (*db.RemoteScript, error) {
+	body, err := FetchRemoteScript(ctx, p.URL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v; a script that cannot be fetched now cannot be reviewed", ErrRemoteScriptUnpinned, err)
+	}
+	pin := &db.RemoteScript{
+		URL:     p.URL,
+		Fetcher: p.Fetcher,
+		Shell:   p.Shell,
+		SHA256:  ScriptSHA256(string(body)),
+		Size:    int64(len(body)),
+		Preview: string(body),
+	}
+	if len(body) > remoteScriptPreviewBytes {
+		pin.Preview = strings.ToValidUTF8(string(body[:remoteScriptPreviewBytes]), "")
+	}
+	return pin, nil
+}
+
+// stagedRemoteScripts are the remote scripts of a command, fetched once at
+// execution, checked against their pins and saved to temp files. The
+// command reads those files instead of downloading again, so the shell runs
+// exactly the bytes that were verified.
+type stagedRemoteScripts struct {
+	// command is the command with each download replaced by reading the
+	// saved script.
+	command string
+	files   []string
+}
+
+// stageRemoteScripts fetches each remote script cmd pipes into a shell,
+// checks it against the hash pinned at request time and saves it to a temp
+// file readable only by the current user, or by everyone when shared is set
+// for an isolated run. It returns nil if cmd runs no remote scripts; the
+// caller must call cleanup otherwise.
+func stageRemoteScripts(ctx context.Context, cmd string, pinned []*db.RemoteScript, shared bool) (_ *stagedRemoteScripts, err error) {
+	sites := remoteFetchSites(cmd)
+	if len(sites) == 0 {
+		return nil, nil
+	}
+	byURL := make(map[string]*db.RemoteScript, len(pinned))
+	for _, p := range pinned {
+		byURL[p.URL] = p
+	}
+
+	staged := &stagedRemoteScripts{command: cmd}
+	defer func() {
+		if err != nil {
+			staged.cleanup()
+		}
+	}()
+	saved := make(map[string]string)
+	for _, site := range sites {
+		url := site.pipe.URL
+		if _, ok := saved[url]; ok {
+			continue
+		}
+		pin := byURL[url]
+		if pin == nil {
+			return nil, fmt.Errorf("%w: %s; resubmit the request", ErrRemoteScriptUnpinned, url)
+		}
+		body, err := FetchRemoteScript(ctx, url)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrRemoteScriptUnverified, err)
+		}
+		if got := ScriptSHA256(string(body)); got != pin.SHA256 {
+			return nil, fmt.Errorf("%w: %s was %s, now %s", ErrRemoteScriptChanged, url, pin.SHA256, got)
+		}
+		path, err := staged.save(body, shared)
+		if err != nil {
+			return nil, err
+		}
+		saved[url] = path
+	}
+
+	// Replace longer downloads first, so one that is a prefix of another
+	// does not break it.
+	sort.SliceStable(sites, func(i, j int) bool { return len(sites[i].fetch) > len(sites[j].fetch) })
+	for _, site := range sites {
+		staged.command = strings.ReplaceAll(staged.command, site.fetch, "cat "+shellQuote(saved[site.pipe.URL]))
+	}
+	if left := DetectRemoteScripts(staged.command); len(left) > 0 {
+		return nil, fmt.Errorf("%w: cannot run %s without downloading it again", ErrRemoteScriptUnverified, left[0].URL)
+	}
+	return staged, nil
+}
+
+// save writes a verified script to a new temp file.
+func (s *stagedRemoteScripts) save(body []byte, shared bool) (string, error) {
+	f, err := os.CreateTemp("", "slb-remote-*.sh")
+	if err != nil {
+		return "", fmt.Errorf("saving remote script: %w", err)
+	}
+	path := f.Name()
+	s.files = append(s.files, path)
+	if _, err := f.Write(body); err != nil {
+		f.Close()
+		return "", fmt.Errorf("saving remote script: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("saving remote script: %w", err)
+	}
+	if shared {
+		if err := os.Chmod(path, 0o644); err != nil {
+			return "", fmt.Errorf("saving remote script: %w", err)
+		}
+	}
+	return path, nil
+}
+
+// spec returns spec with the downloads replaced by the saved scripts.
+func (s *stagedRemoteScripts) spec(spec *db.CommandSpec) *db.CommandSpec {
+	if s == nil {
+		return spec
+	}
+	run := *spec
+	run.Raw = s.command
+	return &run
+}
+
+// paths returns the saved scripts, for an isolation to mount.
+func (s *stagedRemoteScripts) paths() []string {
+	if s == nil {
+		return nil
+	}
+	return s.files
+}
+
+func (s *stagedRemoteScripts) cleanup() {
+	if s == nil {
+		return
+	}
+	for _, path := range s.files {
+		_ = os.Remove(path)
+	}
+}
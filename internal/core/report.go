@@ -0,0 +1,288 @@
+// Package core builds periodic digest reports of request activity.
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// reportPageSize is how many requests are read per query while building a
+// report.
+const reportPageSize = 500
+
+// reportTopN bounds the riskiest-actions and rejection-reason lists.
+const reportTopN = 10
+
+// WeeklyReport summarizes request activity over a time window.
+type WeeklyReport struct {
+	ProjectPath string    `json:"project_path,omitempty"`
+	Since       time.Time `json:"since"`
+	Until       time.Time `json:"until"`
+
+	TotalRequests int            `json:"total_requests"`
+	ByStatus      map[string]int `json:"by_status"`
+	ByTier        map[string]int `json:"by_tier"`
+
+	// MeanApprovalMinutes is the mean time from creation to the first
+	// approving review, over ApprovalSamples requests.
+	MeanApprovalMinutes float64 `json:"mean_approval_minutes"`
+	ApprovalSamples     int     `json:"approval_samples"`
+
+	RiskiestActions  []ReportAction           `json:"riskiest_actions,omitempty"`
+	RejectionReasons []ReportRejection        `json:"rejection_reasons,omitempty"`
+	Breakglass       []*db.BreakglassIncident `json:"breakglass,omitempty"`
+}
+
+// ReportAction is one high-risk request listed in a report.
+type ReportAction struct {
+	RequestID string    `json:"request_id"`
+	Command   string    `json:"command"`
+	Tier      string    `json:"tier"`
+	Status    string    `json:"status"`
+	Agent     string    `json:"agent"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ReportRejection is one rejecting review listed in a report.
+type ReportRejection struct {
+	RequestID string `json:"request_id"`
+	Command   string `json:"command"`
+	Reviewer  string `json:"reviewer"`
+	Reason    string `json:"reason"`
+}
+
+// BuildWeeklyReport summarizes the requests created in [since, until) for a
+// project (all projects when projectPath is empty). Break-glass incidents
+// are only included for a specific project.
+func BuildWeeklyReport(database *db.DB, projectPath string, since, until time.Time) (*WeeklyReport, error) {
+	report := &WeeklyReport{
+		ProjectPath: projectPath,
+		Since:       since,
+		Until:       until,
+		ByStatus:    map[string]int{},
+		ByTier:      map[string]int{},
+	}
+
+	query := db.RequestPageQuery{ProjectPath: projectPath, Since: since, Until: until, Limit: reportPageSize}
+	var requests []*db.Request
+	for {
+		page, next, err := database.ListRequestsPage(query)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, page...)
+		if next == nil {
+			break
+		}
+		query.After = next
+	}
+
+	var approvalTotal time.Duration
+	for _, r := range requests {
+		report.TotalRequests++
+		report.ByStatus[string(r.Status)]++
+		report.ByTier[string(r.RiskTier)]++
+
+		reviews, err := database.ListReviewsForRequest(r.ID)
+		if err != nil {
+			return nil, err
+		}
+		var firstApproval *time.Time
+		for _, rev := range reviews {
+			switch rev.Decision {
+			case db.DecisionApprove:
+				if firstApproval == nil || rev.CreatedAt.Before(*firstApproval) {
+					at := rev.CreatedAt
+					firstApproval = &at
+				}
+			case db.DecisionReject:
+				report.RejectionReasons = append(report.RejectionReasons, ReportRejection{
+					RequestID: r.ID,
+					Command:   reportCommand(r),
+					Reviewer:  rev.ReviewerAgent,
+					Reason:    rev.Comments,
+				})
+			}
+		}
+		if firstApproval != nil && !firstApproval.Before(r.CreatedAt) {
+			approvalTotal += firstApproval.Sub(r.CreatedAt)
+			report.ApprovalSamples++
+		}
+
+		if r.RiskTier == db.RiskTierCritical || r.RiskTier == db.RiskTierDangerous {
+			report.RiskiestActions = append(report.RiskiestActions, ReportAction{
+				RequestID: r.ID,
+				Command:   reportCommand(r),
+				Tier:      string(r.RiskTier),
+				Status:    string(r.Status),
+				Agent:     r.RequestorAgent,
+				CreatedAt: r.CreatedAt,
+			})
+		}
+	}
+	if report.ApprovalSamples > 0 {
+		report.MeanApprovalMinutes = (approvalTotal / time.Duration(report.ApprovalSamples)).Minutes()
+	}
+
+	// Critical before dangerous, and actions that actually ran first.
+	sort.SliceStable(report.RiskiestActions, func(i, j int) bool {
+		a, b := report.RiskiestActions[i], report.RiskiestActions[j]
+		if a.Tier != b.Tier {
+			return a.Tier == string(db.RiskTierCritical)
+		}
+		return ranAction(a.Status) && !ranAction(b.Status)
+	})
+	if len(report.RiskiestActions) > reportTopN {
+		report.RiskiestActions = report.RiskiestActions[:reportTopN]
+	}
+	if len(report.RejectionReasons) > reportTopN {
+		report.RejectionReasons = report.RejectionReasons[:reportTopN]
+	}
+
+	if projectPath != "" {
+		incidents, err := database.ListBreakglassIncidents(projectPath, false)
+		if err != nil {
+			return nil, err
+		}
+		for _, inc := range incidents {
+			if !inc.ExecutedAt.Before(since) && inc.ExecutedAt.Before(until) {
+				report.Breakglass = append(report.Breakglass, inc)
+			}
+		}
+	}
+	return report, nil
+}
+
+// reportCommand is the command as it may be shown in a report.
+func reportCommand(r *db.Request) string {
+	if r.Command.DisplayRedacted != "" {
+		return r.Command.DisplayRedacted
+	}
+	return r.Command.Raw
+}
+
+func ranAction(status string) bool {
+	return status == string(db.StatusExecuted) || status == string(db.StatusExecutionFailed)
+}
+
+// Markdown renders the report for posting into a team channel or wiki.
+func (r *WeeklyReport) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# slb weekly report: %s – %s\n\n", r.Since.Format("2006-01-02"), r.Until.Add(-time.Second).Format("2006-01-02"))
+	if r.ProjectPath != "" {
+		fmt.Fprintf(&b, "Project: `%s`\n\n", r.ProjectPath)
+	}
+
+	b.WriteString("## Summary\n\n")
+	fmt.Fprintf(&b, "- Requests: %d\n", r.TotalRequests)
+	if len(r.ByTier) > 0 {
+		fmt.Fprintf(&b, "- By tier: %s\n", formatCounts(r.ByTier, []string{"critical", "dangerous", "caution", "safe"}))
+	}
+	if len(r.ByStatus) > 0 {
+		fmt.Fprintf(&b, "- By status: %s\n", formatCounts(r.ByStatus, nil))
+	}
+	if r.ApprovalSamples > 0 {
+		fmt.Fprintf(&b, "- Mean time to approval: %s (%d approved)\n", formatReportDuration(time.Duration(r.MeanApprovalMinutes*float64(time.Minute))), r.ApprovalSamples)
+	} else {
+		b.WriteString("- Mean time to approval: n/a\n")
+	}
+	fmt.Fprintf(&b, "- Break-glass incidents: %d\n", len(r.Breakglass))
+
+	if len(r.RiskiestActions) > 0 {
+		b.WriteString("\n## Riskiest actions\n\n")
+		b.WriteString("| Tier | Status | Agent | Command |\n|---|---|---|---|\n")
+		for _, a := range r.RiskiestActions {
+			fmt.Fprintf(&b, "| %s | %s | %s | `%s` |\n", strings.ToUpper(a.Tier), a.Status, markdownCell(a.Agent), markdownCell(markdownCode(a.Command)))
+		}
+	}
+
+	if len(r.RejectionReasons) > 0 {
+		b.WriteString("\n## Rejection reasons\n\n")
+		for _, rej := range r.RejectionReasons {
+			reason := rej.Reason
+			if reason == "" {
+				reason = "(no comment)"
+			}
+			fmt.Fprintf(&b, "- `%s` — %s: %s\n", markdownCode(rej.Command), rej.Reviewer, oneLine(reason))
+		}
+	}
+
+	if len(r.Breakglass) > 0 {
+		b.WriteString("\n## Break-glass incidents\n\n")
+		for _, inc := range r.Breakglass {
+			state := "postmortem pending"
+			if inc.AcknowledgedAt != nil {
+				state = "acknowledged by " + inc.AcknowledgedBy
+			}
+			fmt.Fprintf(&b, "- %s `%s` by %s (%s): %s\n", inc.ExecutedAt.Format("2006-01-02 15:04"),
+				markdownCode(inc.Command), inc.Actor, state, oneLine(inc.Reason))
+		}
+	}
+	return b.String()
+}
+
+// Text renders the report as plain text, e.g. for an email body.
+func (r *WeeklyReport) Text() string {
+	md := r.Markdown()
+	replacer := strings.NewReplacer("# ", "", "## ", "", "`", "")
+	var lines []string
+	for _, line := range strings.Split(md, "\n") {
+		if strings.HasPrefix(line, "|---") {
+			continue
+		}
+		if strings.HasPrefix(line, "|") {
+			cells := strings.Split(strings.Trim(line, "| "), " | ")
+			line = "  " + strings.Join(cells, "  ")
+		}
+		lines = append(lines, replacer.Replace(line))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatCounts renders "key n, key n" in the given order, then the rest
+// alphabetically.
+func formatCounts(counts map[string]int, order []string) string {
+	seen := map[string]bool{}
+	var parts []string
+	for _, k := range order {
+		if n := counts[k]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", k, n))
+		}
+		seen[k] = true
+	}
+	var rest []string
+	for k := range counts {
+		if !seen[k] {
+			rest = append(rest, k)
+		}
+	}
+	sort.Strings(rest)
+	for _, k := range rest {
+		parts = append(parts, fmt.Sprintf("%s %d", k, counts[k]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatReportDuration(d time.Duration) string {
+	if d < time.Minute {
+		return d.Round(time.Second).String()
+	}
+	return d.Round(time.Minute).String()
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// markdownCode keeps a command inside a single-backtick code span.
+func markdownCode(s string) string {
+	return strings.ReplaceAll(oneLine(s), "`", "'")
+}
+
+func markdownCell(s string) string {
+	return strings.ReplaceAll(oneLine(s), "|", `\|`)
+}
@@ -0,0 +1,107 @@
+package core
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestBuildWeeklyReport(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	clk := testutil.NewFakeClock(start.AddDate(0, 0, -10))
+	database.SetClock(clk)
+
+	session := testutil.MakeSession(t, database, testutil.WithProject("/proj"))
+	reviewer := testutil.MakeSession(t, database, testutil.WithProject("/proj"), testutil.WithAgent("Reviewer"))
+
+	// Outside the window.
+	testutil.MakeRequest(t, database, session, testutil.WithCommand("rm -rf ./old", "/proj", true))
+
+	clk.Set(start)
+	critical := testutil.MakeRequest(t, database, session,
+		testutil.WithCommand("git push --mirror origin", "/proj", true),
+		testutil.WithRisk(db.RiskTierCritical),
+		testutil.WithStatus(db.StatusExecuted))
+	clk.Advance(30 * time.Minute)
+	testutil.RequireNoError(t, database.CreateReview(&db.Review{
+		RequestID: critical.ID, ReviewerSessionID: reviewer.ID, ReviewerAgent: "Reviewer",
+		Decision: db.DecisionApprove,
+	}), "approve")
+
+	clk.Advance(time.Hour)
+	rejected := testutil.MakeRequest(t, database, session,
+		testutil.WithCommand("rm -rf ./build", "/proj", true),
+		testutil.WithStatus(db.StatusRejected))
+	clk.Advance(10 * time.Minute)
+	testutil.RequireNoError(t, database.CreateReview(&db.Review{
+		RequestID: rejected.ID, ReviewerSessionID: reviewer.ID, ReviewerAgent: "Reviewer",
+		Decision: db.DecisionReject, Comments: "build dir\nis shared",
+	}), "reject")
+
+	testutil.MakeRequest(t, database, session,
+		testutil.WithCommand("npm uninstall left-pad", "/proj", true),
+		testutil.WithRisk(db.RiskTierCaution))
+
+	testutil.RequireNoError(t, database.CreateBreakglassIncident(&db.BreakglassIncident{
+		ProjectPath: "/proj", Actor: "oncall", Command: "systemctl restart api", Reason: "API down",
+	}), "breakglass")
+
+	// Another project is not included.
+	other := testutil.MakeSession(t, database, testutil.WithProject("/other"))
+	testutil.MakeRequest(t, database, other, testutil.WithRisk(db.RiskTierCritical))
+
+	report, err := BuildWeeklyReport(database, "/proj", start.AddDate(0, 0, -1), start.AddDate(0, 0, 6))
+	testutil.RequireNoError(t, err, "build report")
+
+	testutil.RequireEqual(t, 3, report.TotalRequests, "total")
+	testutil.RequireEqual(t, 1, report.ByTier["critical"], "critical")
+	testutil.RequireEqual(t, 1, report.ByTier["dangerous"], "dangerous")
+	testutil.RequireEqual(t, 1, report.ByStatus["rejected"], "rejected")
+	testutil.RequireEqual(t, 1, report.ApprovalSamples, "approval samples")
+	testutil.RequireEqual(t, 30.0, report.MeanApprovalMinutes, "mean approval minutes")
+	testutil.RequireLen(t, report.RiskiestActions, 2, "riskiest")
+	testutil.RequireEqual(t, critical.ID, report.RiskiestActions[0].RequestID, "riskiest first")
+	testutil.RequireLen(t, report.RejectionReasons, 1, "rejections")
+	testutil.RequireLen(t, report.Breakglass, 1, "breakglass")
+
+	md := report.Markdown()
+	for _, want := range []string{
+		"# slb weekly report: 2026-01-04 – 2026-01-11",
+		"- Requests: 3",
+		"- By tier: critical 1, dangerous 1, caution 1",
+		"- Mean time to approval: 30m0s (1 approved)",
+		"| CRITICAL | executed | ",
+		"`git push --mirror origin`",
+		"- `rm -rf ./build` — Reviewer: build dir is shared",
+		"`systemctl restart api` by oncall (postmortem pending): API down",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+
+	text := report.Text()
+	if strings.Contains(text, "`") || strings.Contains(text, "|---") {
+		t.Errorf("text rendering should drop markdown syntax:\n%s", text)
+	}
+}
+
+func TestBuildWeeklyReport_Empty(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	now := time.Now().UTC()
+	report, err := BuildWeeklyReport(database, "", now.AddDate(0, 0, -7), now)
+	testutil.RequireNoError(t, err, "build report")
+	testutil.RequireEqual(t, 0, report.TotalRequests, "total")
+	if !strings.Contains(report.Markdown(), "Mean time to approval: n/a") {
+		t.Fatalf("unexpected markdown:\n%s", report.Markdown())
+	}
+}
+
+func TestMarkdownEscaping(t *testing.T) {
+	testutil.RequireEqual(t, "echo 'hi' a b", markdownCode("echo `hi`\na   b"), "code span")
+	testutil.RequireEqual(t, `a \| b`, markdownCell("a | b"), "table cell")
+}
@@ -2,10 +2,14 @@
 package core
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"os"
 	"regexp"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Dicklesworthstone/slb/internal/db"
@@ -23,6 +27,12 @@ type CreateRequestOptions struct {
 	Cwd string
 	// Shell indicates if the command should be run through a shell.
 	Shell bool
+	// PathEnv is the requester's PATH, used to record which executable the
+	// command would run (defaults to this process's PATH).
+	PathEnv string
+	// Environ is the requester's environment (KEY=VALUE entries), from
+	// which the pinned variables are recorded. Nil pins no variables.
+	Environ []string
 	// Justification contains the reasoning for the request.
 	Justification Justification
 	// Attachments are optional context files.
@@ -31,6 +41,21 @@ type CreateRequestOptions struct {
 	RedactPatterns []string
 	// ProjectPath overrides the project path (defaults to session's project).
 	ProjectPath string
+	// Provenance describes which agent tool attempted the command (optional).
+	// AgentProgram defaults to the session's program.
+	Provenance *db.RequestProvenance
+	// Labels are org-specific key/value metadata for triage (optional).
+	Labels map[string]string
+	// IdempotencyKey makes retries safe (optional). A second submission of
+	// the same command with the same key from the same session returns the
+	// original request instead of creating another.
+	IdempotencyKey string
+	// MinTier raises the classification to at least this tier, whatever
+	// the patterns say (optional); slb's own config changes are critical.
+	MinTier RiskTier
+	// Script marks Command as a multi-line script, run from a temp file by
+	// an interpreter instead of as one command line (optional).
+	Script *ScriptOptions
 }
 
 // CreateRequestResult holds the result of creating a request.
@@ -43,6 +68,20 @@ type CreateRequestResult struct {
 	SkipReason string
 	// Classification is the risk classification result.
 	Classification *MatchResult
+	// RiskScore is the request's 0-100 risk score and what it is made of
+	// (nil if skipped or replayed).
+	RiskScore *RiskScore
+	// Replayed indicates Request was created by an earlier submission with
+	// the same idempotency key.
+	Replayed bool
+	// TemplateVars are the command's unexpanded placeholders ($TARGET,
+	// {{env}}). Approving it needs a reviewer to acknowledge them.
+	TemplateVars []string
+	// Script is the recorded script, when the request was submitted as one.
+	Script *db.RequestScript
+	// RemoteScripts are the downloads the command pipes into a shell, as
+	// fetched and pinned for review.
+	RemoteScripts []*db.RemoteScript
 }
 
 // Request creation errors.
@@ -66,6 +105,16 @@ type RequestCreator struct {
 	patternEngine *PatternEngine
 	config        *RequestCreatorConfig
 	notifier      integrations.RequestNotifier
+	advisor       AdvisoryReviewer
+	// advice tracks advisory reviews still running after CreateRequest
+	// returned.
+	advice sync.WaitGroup
+}
+
+// AdvisoryReviewer produces a non-binding second opinion for a new request.
+// Its annotations are shown to reviewers but never count as approvals.
+type AdvisoryReviewer interface {
+	Review(ctx context.Context, req *db.Request) (*db.RequestAnnotation, error)
 }
 
 // RequestCreatorConfig holds configuration for request creation.
@@ -88,6 +137,22 @@ type RequestCreatorConfig struct {
 	AgentMailThread string
 	// AgentMailSender optional sender name.
 	AgentMailSender string
+	// AdvisoryTimeout bounds how long the advisory reviewer may take.
+	AdvisoryTimeout time.Duration
+	// IdempotencyTTLMinutes is how long an idempotency key replays its
+	// request.
+	IdempotencyTTLMinutes int
+	// TierOverrides adjust classifications by the requestor's program and
+	// model, or the branch (agents.tier_overrides).
+	TierOverrides []TierOverride
+	// Anomaly configures command frequency anomaly detection.
+	Anomaly AnomalyConfig
+	// PinnedEnv names the environment variables recorded with each request
+	// and checked again at execution.
+	PinnedEnv []string
+	// ProductionHosts are host patterns (see IsProductionHost); commands
+	// aimed at a matching host are raised a tier.
+	ProductionHosts []string
 }
 
 // DefaultRequestCreatorConfig returns the default configuration.
@@ -102,6 +167,8 @@ func DefaultRequestCreatorConfig() *RequestCreatorConfig {
 		AgentMailEnabled:           true,
 		AgentMailThread:            "SLB-Reviews",
 		AgentMailSender:            "SLB-System",
+		IdempotencyTTLMinutes:      1440,
+		ProductionHosts:            DefaultProductionHosts,
 	}
 }
 
@@ -125,8 +192,28 @@ func NewRequestCreator(database *db.DB, rateLimiter *RateLimiter, patternEngine
 	}
 }
 
+// SetAdvisor sets the optional advisory reviewer consulted after creation.
+func (rc *RequestCreator) SetAdvisor(a AdvisoryReviewer) {
+	rc.advisor = a
+}
+
+// WaitForAdvice waits for the advisory reviews CreateRequest started, so a
+// short-lived caller can store their annotations before it exits.
+func (rc *RequestCreator) WaitForAdvice() {
+	rc.advice.Wait()
+}
+
+// SetPatternEngine sets the engine used to classify commands, for callers
+// that keep their own engine instead of the global default.
+func (rc *RequestCreator) SetPatternEngine(e *PatternEngine) {
+	if e != nil {
+		rc.patternEngine = e
+	}
+}
+
 // CreateRequest creates a new command approval request with full validation.
-func (rc *RequestCreator) CreateRequest(opts CreateRequestOptions) (*CreateRequestResult, error) {
+// ctx bounds the fetches of any scripts the command downloads.
+func (rc *RequestCreator) CreateRequest(ctx context.Context, opts CreateRequestOptions) (*CreateRequestResult, error) {
 	// Validate required fields
 	if opts.SessionID == "" {
 		return nil, ErrSessionRequired
@@ -134,6 +221,40 @@ func (rc *RequestCreator) CreateRequest(opts CreateRequestOptions) (*CreateReque
 	if opts.Command == "" {
 		return nil, ErrCommandRequired
 	}
+	if err := ValidateExact("command", opts.Command, MaxCommandBytes); err != nil {
+		return nil, err
+	}
+	if err := ValidateExact("cwd", opts.Cwd, MaxCwdBytes); err != nil {
+		return nil, err
+	}
+	var script *db.RequestScript
+	if opts.Script != nil {
+		interpreter, err := ScriptInterpreter(opts.Script.Interpreter, opts.Command)
+		if err != nil {
+			return nil, err
+		}
+		script = &db.RequestScript{
+			Interpreter: interpreter,
+			Source:      opts.Script.Source,
+			SHA256:      ScriptSHA256(opts.Command),
+		}
+		opts.Shell = true
+	}
+	justification, err := sanitizeJustification(opts.Justification)
+	if err != nil {
+		return nil, err
+	}
+	opts.Justification = justification
+	for key, value := range opts.Labels {
+		if err := db.ValidateLabel(key, value); err != nil {
+			return nil, err
+		}
+	}
+	if opts.IdempotencyKey != "" {
+		if err := db.ValidateIdempotencyKey(opts.IdempotencyKey); err != nil {
+			return nil, err
+		}
+	}
 
 	// Step 1: Validate session exists and is active
 	session, err := rc.db.GetSession(opts.SessionID)
@@ -158,6 +279,19 @@ func (rc *RequestCreator) CreateRequest(opts CreateRequestOptions) (*CreateReque
 		return nil, fmt.Errorf("%w: %s", ErrAgentBlocked, session.AgentName)
 	}
 
+	// Steps 6-7 run early: a retry is matched by command hash and replays
+	// the original request before rate limits count it as a new submission.
+	cmdSpec := commandSpec(opts)
+	if opts.IdempotencyKey != "" {
+		original, err := rc.db.LookupIdempotentRequest(opts.SessionID, opts.IdempotencyKey, cmdSpec.Hash)
+		if err == nil {
+			return rc.replayed(original), nil
+		}
+		if !errors.Is(err, db.ErrIdempotencyKeyNotFound) {
+			return nil, err
+		}
+	}
+
 	// Step 3: Check rate limits
 	// CheckRateLimit returns an error when Action=reject and limits are exceeded
 	limitResult, err := rc.rateLimiter.CheckRateLimit(opts.SessionID)
@@ -169,8 +303,29 @@ func (rc *RequestCreator) CreateRequest(opts CreateRequestOptions) (*CreateReque
 		return nil, fmt.Errorf("rate limit exceeded (action=%s): %s", limitResult.Action, limitResult.Message)
 	}
 
-	// Step 4: Classify command
+	// Step 4: Classify command, then apply the requestor's tier overrides
 	classification := rc.patternEngine.ClassifyCommand(opts.Command, opts.Cwd)
+	rc.RecordPatternHits(classification)
+	classification = rc.ApplyTierOverrides(classification, session.Program, session.Model, opts.Command, opts.Cwd)
+
+	// Determine project path
+	projectPath := opts.ProjectPath
+	if projectPath == "" {
+		projectPath = session.ProjectPath
+	}
+
+	// Step 4b: Compare against the project's command history (best effort)
+	anomaly := rc.checkAnomaly(opts.Command, projectPath, opts.SessionID)
+	classification = ApplyAnomaly(classification, anomaly)
+
+	// Step 4c: Find the remote hosts the command acts on; production ones
+	// raise the tier
+	targets := ExtractTargets(opts.Command)
+	MarkProductionTargets(targets, rc.config.ProductionHosts)
+	classification = ApplyTargets(classification, targets)
+
+	// Step 4d: Raise to the caller's minimum tier
+	classification = applyMinTier(classification, opts.MinTier)
 
 	// Step 5: If SAFE, skip
 	if classification.IsSafe {
@@ -182,6 +337,16 @@ func (rc *RequestCreator) CreateRequest(opts CreateRequestOptions) (*CreateReque
 		}, nil
 	}
 
+	// A caution-tier command an override stopped tracking
+	if !classification.NeedsApproval && classification.Tier == RiskTierCaution {
+		return &CreateRequestResult{
+			Request:        nil,
+			Skipped:        true,
+			SkipReason:     "Caution-tier commands are not tracked for this agent (agents.tier_overrides)",
+			Classification: classification,
+		}, nil
+	}
+
 	// If no approval needed (no pattern match), also skip
 	if !classification.NeedsApproval {
 		return &CreateRequestResult{
@@ -192,21 +357,13 @@ func (rc *RequestCreator) CreateRequest(opts CreateRequestOptions) (*CreateReque
 		}, nil
 	}
 
-	// Step 6: Parse command to argv
-	argv, _ := ParseCommandToArgv(opts.Command)
-
-	// Step 7: Build command spec (hash computed by db.CreateRequest)
-	cmdSpec := db.CommandSpec{
-		Raw:   opts.Command,
-		Argv:  argv,
-		Cwd:   opts.Cwd,
-		Shell: opts.Shell,
-	}
-
 	// Step 8: Apply redaction
 	cmdSpec.DisplayRedacted = ApplyRedaction(opts.Command, opts.RedactPatterns)
 	cmdSpec.ContainsSensitive = cmdSpec.DisplayRedacted != opts.Command
 
+	// Step 8b: Score the request for queue ordering and policy thresholds
+	score := rc.riskScore(classification, cmdSpec, projectPath, anomaly)
+
 	// Step 9: Get min approvals (with dynamic quorum check)
 	minApprovals := classification.MinApprovals
 	if rc.config.DynamicQuorumEnabled {
@@ -217,22 +374,66 @@ func (rc *RequestCreator) CreateRequest(opts CreateRequestOptions) (*CreateReque
 	now := time.Now().UTC()
 	requestExpiry := now.Add(time.Duration(rc.config.RequestTimeoutMinutes) * time.Minute)
 
-	// Determine project path
-	projectPath := opts.ProjectPath
-	if projectPath == "" {
-		projectPath = session.ProjectPath
+	// Step 10b: Attach branch/upstream details for git history rewrites
+	// (best effort; a failed git query just leaves fields empty)
+	attachments := opts.Attachments
+	if rewrite := AnalyzeGitRewrite(opts.Command, opts.Cwd); rewrite != nil {
+		attachments = append(append([]db.Attachment(nil), attachments...), *rewrite.Attachment())
+	}
+	if anomaly != nil {
+		attachments = append(append([]db.Attachment(nil), attachments...), *anomaly.Attachment())
+	}
+	if a := TargetsAttachment(targets); a != nil {
+		attachments = append(append([]db.Attachment(nil), attachments...), *a)
+	}
+	if slices.Contains(rc.patternEngine.EnabledPacks(), DeployPack) {
+		if a := DeploymentsAttachment(DetectDeployments(opts.Command)); a != nil {
+			attachments = append(append([]db.Attachment(nil), attachments...), *a)
+		}
+	}
+
+	// Step 10c: Fetch the scripts the command downloads into a shell, so
+	// reviewers see what will run and execution can refuse changed content
+	var remoteScripts []*db.RemoteScript
+	for _, pipe := range DetectRemoteScripts(opts.Command) {
+		pin, err := PinRemoteScript(ctx, pipe)
+		if err != nil {
+			return nil, err
+		}
+		remoteScripts = append(remoteScripts, pin)
+	}
+
+	// Step 10d: Pin the executable that would run (a script is run by its
+	// interpreter, not its first word), the working directory and the
+	// environment, so execution can refuse drift from them
+	pathEnv := opts.PathEnv
+	if pathEnv == "" {
+		pathEnv = os.Getenv("PATH")
+	}
+	var binary *db.RequestBinary
+	if script == nil {
+		binary = ResolveBinary(opts.Command, opts.Cwd, pathEnv, projectPath)
 	}
+	environment := PinEnvironment(opts.Cwd, opts.Environ, rc.config.PinnedEnv)
 
-	// Step 11: Create request in DB
+	// Step 11: Create request in DB, with the script and remote script
+	// hashes and the pins execution checks against
 	request := &db.Request{
 		ProjectPath:        projectPath,
 		Command:            cmdSpec,
 		RiskTier:           classification.Tier,
+		RiskScore:          score.Score,
 		RequestorSessionID: opts.SessionID,
 		RequestorAgent:     session.AgentName,
 		RequestorModel:     session.Model,
 		Justification:      opts.Justification,
-		Attachments:        opts.Attachments,
+		Attachments:        attachments,
+		Labels:             opts.Labels,
+		Script:             script,
+		RemoteScripts:      remoteScripts,
+		Binary:             binary,
+		Environment:        environment,
+		RedactPatterns:     opts.RedactPatterns,
 		Status:             db.StatusPending,
 		MinApprovals:       minApprovals,
 		ExpiresAt:          &requestExpiry,
@@ -243,23 +444,232 @@ func (rc *RequestCreator) CreateRequest(opts CreateRequestOptions) (*CreateReque
 		request.RequireDifferentModel = true
 	}
 
-	if err := rc.db.CreateRequest(request); err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
+	if opts.IdempotencyKey == "" {
+		if err := rc.db.CreateRequest(request); err != nil {
+			return nil, fmt.Errorf("creating request: %w", err)
+		}
+	} else {
+		stored, replayed, err := rc.db.CreateRequestIdempotent(request, opts.IdempotencyKey, rc.idempotencyTTL())
+		if err != nil {
+			return nil, fmt.Errorf("creating request: %w", err)
+		}
+		if replayed {
+			// A concurrent retry with the same key won the insert.
+			return rc.replayed(stored), nil
+		}
+	}
+
+	// Step 12: Record provenance (best effort; never blocks creation)
+	if !opts.Provenance.IsEmpty() {
+		prov := *opts.Provenance
+		prov.RequestID = request.ID
+		if prov.AgentProgram == "" {
+			prov.AgentProgram = session.Program
+		}
+		_ = rc.db.SetRequestProvenance(&prov)
+	}
+
+	// Step 12b: Record the matched pattern for effectiveness stats (best effort)
+	if classification.MatchedPattern != "" {
+		_ = rc.db.SetRequestPattern(&db.RequestPattern{
+			RequestID: request.ID,
+			Tier:      classification.Tier,
+			Pattern:   classification.MatchedPattern,
+		})
 	}
 
-	// Step 12: Notify via Agent Mail (best effort; errors ignored)
+	// Step 12c: Record the policy it was classified under, so execution can
+	// tell when it changed (best effort)
+	_ = rc.db.SetRequestPolicy(&db.RequestPolicy{
+		RequestID:  request.ID,
+		PolicyHash: rc.PolicyHash(),
+		Tier:       classification.Tier,
+	})
+
+	// Step 13: Notify via Agent Mail (best effort; errors ignored)
 	_ = notifier.NotifyNewRequest(request)
 
-	// Step 12: (TODO) Materialize JSON file in .slb/pending/
+	// Step 14: Ask the advisory reviewer for a second opinion in the
+	// background (best effort); reviewers see it once it is stored
+	rc.requestAdvice(request, opts.RedactPatterns)
+
+	// Step 15: (TODO) Materialize JSON file in .slb/pending/
 	// This will be implemented when file materialization is needed
 
 	return &CreateRequestResult{
 		Request:        request,
 		Skipped:        false,
 		Classification: classification,
+		RiskScore:      &score,
+		TemplateVars:   TemplateVars(request.Command.Raw),
+		Script:         script,
+		RemoteScripts:  remoteScripts,
 	}, nil
 }
 
+// ApplyTierOverrides applies the configured tier overrides for a requestor
+// running cmd in cwd to a classification (see ApplyTierOverrides).
+func (rc *RequestCreator) ApplyTierOverrides(res *MatchResult, program, model, cmd, cwd string) *MatchResult {
+	if rc.config == nil {
+		return res
+	}
+	overrides := rc.config.TierOverrides
+	return ApplyTierOverrides(res, overrides, OverrideSubjectFor(overrides, program, model, cmd, cwd))
+}
+
+// RecordPatternHits counts a hit for each pattern that classified a
+// command. Pass the pattern engine's result, before overrides change its
+// tier. Best effort: stats never block classification.
+func (rc *RequestCreator) RecordPatternHits(res *MatchResult) {
+	if rc.db == nil {
+		return
+	}
+	if hits := PatternHits(res, rc.db.Now()); len(hits) > 0 {
+		_ = rc.db.RecordPatternHits(hits)
+	}
+}
+
+// PatternHits returns one hit at time at for each distinct pattern that
+// classified a command (every matched segment of a compound command).
+func PatternHits(res *MatchResult, at time.Time) []*db.PatternHit {
+	if res == nil {
+		return nil
+	}
+	var hits []*db.PatternHit
+	seen := make(map[string]bool)
+	add := func(tier RiskTier, pattern string) {
+		key := string(tier) + "\x00" + pattern
+		if tier == "" || pattern == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		hits = append(hits, &db.PatternHit{Tier: tier, Pattern: pattern, Hits: 1, FirstMatchedAt: at, LastMatchedAt: at})
+	}
+	add(res.Tier, res.MatchedPattern)
+	for _, seg := range res.MatchedSegments {
+		add(seg.Tier, seg.MatchedPattern)
+	}
+	return hits
+}
+
+// applyMinTier raises a classification to at least minTier, so the
+// command needs approval whatever the patterns say. res is not modified; a
+// copy is returned when anything changes.
+func applyMinTier(res *MatchResult, minTier RiskTier) *MatchResult {
+	if minTier == "" || (res.NeedsApproval && tierRank(res.Tier) >= tierRank(minTier)) {
+		return res
+	}
+	out := *res
+	out.Explanation = append(append([]string(nil), res.Explanation...),
+		fmt.Sprintf("tier raised from %s to %s: this kind of request is always at least %s", tierLabel(res.Tier), minTier, minTier))
+	out.Tier = minTier
+	out.MinApprovals = tierApprovals(minTier)
+	out.NeedsApproval = true
+	out.IsSafe = false
+	return &out
+}
+
+// commandSpec parses the command to argv and builds its hashed spec.
+func commandSpec(opts CreateRequestOptions) db.CommandSpec {
+	argv, _ := ParseCommandToArgv(opts.Command)
+	spec := db.CommandSpec{
+		Raw:   opts.Command,
+		Argv:  argv,
+		Cwd:   opts.Cwd,
+		Shell: opts.Shell,
+	}
+	spec.Hash = db.ComputeCommandHash(spec)
+	return spec
+}
+
+// replayed returns the result of a submission that reused the idempotency
+// key of an earlier one. Nothing is notified or reviewed again.
+func (rc *RequestCreator) replayed(original *db.Request) *CreateRequestResult {
+	_ = rc.db.LoadRequestLabels([]*db.Request{original})
+	script, _ := rc.db.GetRequestScript(original.ID)
+	remoteScripts, _ := rc.db.ListRequestRemoteScripts(original.ID)
+	return &CreateRequestResult{
+		Request:        original,
+		Classification: rc.patternEngine.ClassifyCommand(original.Command.Raw, original.Command.Cwd),
+		Replayed:       true,
+		TemplateVars:   TemplateVars(original.Command.Raw),
+		Script:         script,
+		RemoteScripts:  remoteScripts,
+	}
+}
+
+func (rc *RequestCreator) idempotencyTTL() time.Duration {
+	minutes := rc.config.IdempotencyTTLMinutes
+	if minutes <= 0 {
+		minutes = DefaultRequestCreatorConfig().IdempotencyTTLMinutes
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// requestAdvice consults the advisory reviewer in the background and stores
+// its annotation. Failures are swallowed: a second opinion must never block
+// or fail request creation. The advisor may send what it reads off the
+// machine, so it gets a copy redacted with redactPatterns as well as the
+// default patterns.
+func (rc *RequestCreator) requestAdvice(request *db.Request, redactPatterns []string) {
+	if rc.advisor == nil {
+		return
+	}
+	timeout := rc.config.AdvisoryTimeout
+	if timeout <= 0 {
+		timeout = integrations.DefaultLLMReviewTimeout
+	}
+	// The advisor reads its own copy while the caller goes on with request.
+	req := adviceRequest(request, redactPatterns)
+	rc.advice.Add(1)
+	go func() {
+		defer rc.advice.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		annotation, err := rc.advisor.Review(ctx, &req)
+		if err != nil || annotation == nil {
+			return
+		}
+		annotation.RequestID = req.ID
+		_ = rc.db.CreateAnnotation(annotation)
+	}()
+}
+
+// adviceRequest returns a copy of request with every free-text field
+// redacted: the command, working directory, justification, dry run and
+// attachments.
+func adviceRequest(request *db.Request, redactPatterns []string) db.Request {
+	redact := func(s string) string { return ApplyRedaction(s, redactPatterns) }
+
+	req := *request
+	req.Command.Raw = redact(request.Command.Raw)
+	req.Command.DisplayRedacted = redact(request.Command.DisplayRedacted)
+	req.Command.Cwd = redact(request.Command.Cwd)
+	req.Command.Argv = nil
+	for _, arg := range request.Command.Argv {
+		req.Command.Argv = append(req.Command.Argv, redact(arg))
+	}
+	req.Justification = Justification{
+		Reason:         redact(request.Justification.Reason),
+		ExpectedEffect: redact(request.Justification.ExpectedEffect),
+		Goal:           redact(request.Justification.Goal),
+		SafetyArgument: redact(request.Justification.SafetyArgument),
+	}
+	if request.DryRun != nil {
+		req.DryRun = &db.DryRunResult{
+			Command: redact(request.DryRun.Command),
+			Output:  redact(request.DryRun.Output),
+		}
+	}
+	req.Attachments = nil
+	for _, a := range request.Attachments {
+		a.Content = redact(a.Content)
+		req.Attachments = append(req.Attachments, a)
+	}
+	return req
+}
+
 // isAgentBlocked checks if an agent is in the blocked list.
 func (rc *RequestCreator) isAgentBlocked(agentName string) bool {
 	for _, blocked := range rc.config.BlockedAgents {
@@ -305,56 +715,83 @@ func ParseCommandToArgv(cmd string) ([]string, error) {
 	return parser.Parse(cmd)
 }
 
+// redactionRule is one kind of sensitive data and the pattern that finds
+// it. Kind names what leaked when the pattern matches execution output.
+type redactionRule struct {
+	Kind    string
+	Pattern string
+}
+
 // Default redaction patterns for sensitive data.
-var defaultRedactionPatterns = []string{
+var defaultRedactionPatterns = []redactionRule{
 	// API keys and tokens
-	`(?i)(api[_-]?key|apikey|token|secret|password|passwd|pwd)\s*[=:]\s*['"]?[^\s'"]+['"]?`,
+	{"credential", `(?i)(api[_-]?key|apikey|token|secret|password|passwd|pwd)\s*[=:]\s*['"]?[^\s'"]+['"]?`},
 	// AWS credentials
-	`(?i)aws[_-]?(access[_-]?key|secret[_-]?key|session[_-]?token)\s*[=:]\s*['"]?[^\s'"]+['"]?`,
+	{"aws_credential", `(?i)aws[_-]?(access[_-]?key|secret[_-]?key|session[_-]?token)\s*[=:]\s*['"]?[^\s'"]+['"]?`},
+	{"aws_access_key_id", `\b(AKIA|ASIA)[0-9A-Z]{16}\b`},
 	// Environment variable exports with sensitive names
-	`(?i)export\s+(API_KEY|SECRET|TOKEN|PASSWORD|AWS_ACCESS_KEY_ID|AWS_SECRET_ACCESS_KEY|DATABASE_URL)\s*=\s*['"]?[^\s'"]+['"]?`,
+	{"credential", `(?i)export\s+(API_KEY|SECRET|TOKEN|PASSWORD|AWS_ACCESS_KEY_ID|AWS_SECRET_ACCESS_KEY|DATABASE_URL)\s*=\s*['"]?[^\s'"]+['"]?`},
 	// Connection strings
-	`(?i)(postgres|mysql|mongodb|redis)://[^@\s]+@`,
+	{"connection_string", `(?i)(postgres|mysql|mongodb|redis)://[^@\s]+@`},
 	// Bearer tokens
-	`(?i)bearer\s+[a-zA-Z0-9._-]+`,
-	// Private keys (just the header)
-	`(?i)-----BEGIN\s+[A-Z]+\s+PRIVATE\s+KEY-----`,
+	{"bearer_token", `(?i)bearer\s+[a-zA-Z0-9._-]+`},
+	// Provider tokens with a recognizable prefix
+	{"github_token", `\b(gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`},
+	{"slack_token", `\bxox[abposr]-[A-Za-z0-9-]{10,}`},
+	// Private keys: the whole block, else just the header
+	{"private_key", `(?s)-----BEGIN[A-Z ]*PRIVATE KEY-----.*?-----END[A-Z ]*PRIVATE KEY-----`},
+	{"private_key", `(?i)-----BEGIN\s+[A-Z]+\s+PRIVATE\s+KEY-----`},
 }
 
-// ApplyRedaction applies redaction patterns to a command string.
-// Returns a display-safe version of the command with sensitive data masked.
-func ApplyRedaction(cmd string, customPatterns []string) string {
-	result := cmd
+// defaultRedactionRules are defaultRedactionPatterns, compiled once.
+var defaultRedactionRules = compileRedactionRules(defaultRedactionPatterns)
 
-	// Apply default patterns
-	for _, pattern := range defaultRedactionPatterns {
-		re, err := regexp.Compile(pattern)
+// compiledRedactionRule is a redactionRule with its pattern compiled.
+type compiledRedactionRule struct {
+	Kind string
+	Re   *regexp.Regexp
+}
+
+// compileRedactionRules compiles rules, skipping any invalid pattern.
+func compileRedactionRules(rules []redactionRule) []compiledRedactionRule {
+	compiled := make([]compiledRedactionRule, 0, len(rules))
+	for _, rule := range rules {
+		re, err := regexp.Compile(rule.Pattern)
 		if err != nil {
 			continue
 		}
-		result = re.ReplaceAllString(result, "[REDACTED]")
+		compiled = append(compiled, compiledRedactionRule{Kind: rule.Kind, Re: re})
 	}
+	return compiled
+}
 
-	// Apply custom patterns
+// redactionRules returns the default rules followed by customPatterns, of
+// kind "custom".
+func redactionRules(customPatterns []string) []compiledRedactionRule {
+	if len(customPatterns) == 0 {
+		return defaultRedactionRules
+	}
+	custom := make([]redactionRule, 0, len(customPatterns))
 	for _, pattern := range customPatterns {
-		re, err := regexp.Compile(pattern)
-		if err != nil {
-			continue
-		}
-		result = re.ReplaceAllString(result, "[REDACTED]")
+		custom = append(custom, redactionRule{Kind: "custom", Pattern: pattern})
 	}
+	return append(defaultRedactionRules[:len(defaultRedactionRules):len(defaultRedactionRules)], compileRedactionRules(custom)...)
+}
 
+// ApplyRedaction applies redaction patterns to a command string.
+// Returns a display-safe version of the command with sensitive data masked.
+func ApplyRedaction(cmd string, customPatterns []string) string {
+	result := cmd
+	for _, rule := range redactionRules(customPatterns) {
+		result = rule.Re.ReplaceAllString(result, "[REDACTED]")
+	}
 	return result
 }
 
 // DetectSensitiveContent checks if a command contains sensitive data.
 func DetectSensitiveContent(cmd string) bool {
-	for _, pattern := range defaultRedactionPatterns {
-		re, err := regexp.Compile(pattern)
-		if err != nil {
-			continue
-		}
-		if re.MatchString(cmd) {
+	for _, rule := range defaultRedactionRules {
+		if rule.Re.MatchString(cmd) {
 			return true
 		}
 	}
@@ -1,9 +1,18 @@
 package core
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/integrations"
 	"github.com/Dicklesworthstone/slb/internal/testutil"
 )
 
@@ -11,7 +20,7 @@ func TestCreateRequest_MissingSessionID(t *testing.T) {
 	database := testutil.NewTestDB(t)
 	creator := NewRequestCreator(database, nil, nil, nil)
 
-	_, err := creator.CreateRequest(CreateRequestOptions{
+	_, err := creator.CreateRequest(context.Background(), CreateRequestOptions{
 		Command: "rm -rf /tmp/test",
 	})
 
@@ -25,7 +34,7 @@ func TestCreateRequest_MissingCommand(t *testing.T) {
 	session := testutil.MakeSession(t, database, testutil.SessionWithAgentName("agent1"))
 	creator := NewRequestCreator(database, nil, nil, nil)
 
-	_, err := creator.CreateRequest(CreateRequestOptions{
+	_, err := creator.CreateRequest(context.Background(), CreateRequestOptions{
 		SessionID: session.ID,
 	})
 
@@ -38,7 +47,7 @@ func TestCreateRequest_SessionNotFound(t *testing.T) {
 	database := testutil.NewTestDB(t)
 	creator := NewRequestCreator(database, nil, nil, nil)
 
-	_, err := creator.CreateRequest(CreateRequestOptions{
+	_, err := creator.CreateRequest(context.Background(), CreateRequestOptions{
 		SessionID: "nonexistent-session",
 		Command:   "rm -rf /tmp/test",
 	})
@@ -55,7 +64,7 @@ func TestCreateRequest_BlockedAgent(t *testing.T) {
 	config.BlockedAgents = []string{"blocked-agent"}
 	creator := NewRequestCreator(database, nil, nil, config)
 
-	_, err := creator.CreateRequest(CreateRequestOptions{
+	_, err := creator.CreateRequest(context.Background(), CreateRequestOptions{
 		SessionID: session.ID,
 		Command:   "rm -rf /tmp/test",
 	})
@@ -70,7 +79,7 @@ func TestCreateRequest_SafeCommand_Skipped(t *testing.T) {
 	session := testutil.MakeSession(t, database, testutil.SessionWithAgentName("agent1"))
 	creator := NewRequestCreator(database, nil, nil, nil)
 
-	result, err := creator.CreateRequest(CreateRequestOptions{
+	result, err := creator.CreateRequest(context.Background(), CreateRequestOptions{
 		SessionID: session.ID,
 		Command:   "rm test.log", // .log files are safe
 	})
@@ -92,7 +101,7 @@ func TestCreateRequest_DangerousCommand_Created(t *testing.T) {
 	creator := NewRequestCreator(database, nil, nil, nil)
 
 	// Use git reset --hard which is dangerous (not critical)
-	result, err := creator.CreateRequest(CreateRequestOptions{
+	result, err := creator.CreateRequest(context.Background(), CreateRequestOptions{
 		SessionID: session.ID,
 		Command:   "git reset --hard HEAD~3",
 		Cwd:       "/project",
@@ -115,12 +124,157 @@ func TestCreateRequest_DangerousCommand_Created(t *testing.T) {
 	}
 }
 
+func TestCreateRequest_MinTier(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	session := testutil.MakeSession(t, database, testutil.SessionWithAgentName("agent1"))
+	creator := NewRequestCreator(database, nil, nil, nil)
+
+	// An unmatched command is requested at the minimum tier.
+	result, err := creator.CreateRequest(context.Background(), CreateRequestOptions{
+		SessionID:     session.ID,
+		Command:       "slb config activate 1234",
+		Cwd:           "/project",
+		Justification: Justification{Reason: "apply config"},
+		MinTier:       RiskTierCritical,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Skipped || result.Request.RiskTier != RiskTierCritical || result.Request.MinApprovals != 2 {
+		t.Fatalf("expected a critical request, got %+v", result)
+	}
+	if n := len(result.Classification.Explanation); n == 0 || !strings.Contains(result.Classification.Explanation[n-1], "unmatched to critical") {
+		t.Errorf("explanation = %q", result.Classification.Explanation)
+	}
+
+	// A higher tier from the patterns is kept.
+	result, err = creator.CreateRequest(context.Background(), CreateRequestOptions{
+		SessionID:     session.ID,
+		Command:       "git reset --hard HEAD~3",
+		Cwd:           "/project",
+		Justification: Justification{Reason: "reset"},
+		MinTier:       RiskTierCaution,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Request.RiskTier != RiskTierDangerous || len(result.Classification.Explanation) != 0 {
+		t.Errorf("expected the dangerous tier unchanged, got %s %q", result.Request.RiskTier, result.Classification.Explanation)
+	}
+}
+
+func TestCreateRequest_RecordsProvenance(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	session := testutil.MakeSession(t, database, testutil.SessionWithAgentName("agent1"))
+	creator := NewRequestCreator(database, nil, nil, nil)
+
+	result, err := creator.CreateRequest(context.Background(), CreateRequestOptions{
+		SessionID:     session.ID,
+		Command:       "git reset --hard HEAD~3",
+		Cwd:           "/project",
+		Justification: Justification{Reason: "Need to reset commits"},
+		Provenance: &db.RequestProvenance{
+			ConversationID: "conv-1",
+			ToolCallID:     "toolu_1",
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	prov, err := database.GetRequestProvenance(result.Request.ID)
+	if err != nil {
+		t.Fatalf("GetRequestProvenance: %v", err)
+	}
+	if prov.ConversationID != "conv-1" || prov.ToolCallID != "toolu_1" {
+		t.Fatalf("unexpected provenance: %+v", prov)
+	}
+	if prov.AgentProgram != session.Program {
+		t.Errorf("expected agent program to default to session program %q, got %q", session.Program, prov.AgentProgram)
+	}
+}
+
+func TestCreateRequest_RecordsMatchedPattern(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	session := testutil.MakeSession(t, database, testutil.SessionWithAgentName("agent1"))
+	creator := NewRequestCreator(database, nil, nil, nil)
+
+	result, err := creator.CreateRequest(context.Background(), CreateRequestOptions{
+		SessionID:     session.ID,
+		Command:       "git reset --hard HEAD~3",
+		Cwd:           "/project",
+		Justification: Justification{Reason: "Need to reset commits"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	p, err := database.GetRequestPattern(result.Request.ID)
+	if err != nil {
+		t.Fatalf("GetRequestPattern: %v", err)
+	}
+	want := creator.patternEngine.ClassifyCommand("git reset --hard HEAD~3", "/project").MatchedPattern
+	if p.Pattern != want || p.Tier != db.RiskTierDangerous {
+		t.Fatalf("unexpected pattern: %+v (want %q)", p, want)
+	}
+}
+
+func TestCreateRequest_RecordsPatternHits(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	session := testutil.MakeSession(t, database, testutil.SessionWithAgentName("agent1"))
+	creator := NewRequestCreator(database, nil, nil, nil)
+
+	for _, cmd := range []string{"git reset --hard HEAD~3", "git reset --hard HEAD~1", "git stash"} {
+		if _, err := creator.CreateRequest(context.Background(), CreateRequestOptions{
+			SessionID:     session.ID,
+			Command:       cmd,
+			Cwd:           "/project",
+			Justification: Justification{Reason: "Need to reset commits"},
+		}); err != nil {
+			t.Fatalf("CreateRequest(%q): %v", cmd, err)
+		}
+	}
+
+	hits, err := database.ListPatternHits()
+	if err != nil {
+		t.Fatalf("ListPatternHits: %v", err)
+	}
+	reset := creator.patternEngine.ClassifyCommand("git reset --hard HEAD~3", "/project").MatchedPattern
+	status := creator.patternEngine.ClassifyCommand("git stash", "/project").MatchedPattern
+	got := make(map[string]int)
+	for _, h := range hits {
+		got[string(h.Tier)+" "+h.Pattern] = h.Hits
+	}
+	// Safe commands are counted too, even though they create no request.
+	if got["dangerous "+reset] != 2 || got["safe "+status] != 1 || len(got) != 2 {
+		t.Fatalf("unexpected hits: %v", got)
+	}
+
+	// Each pattern of a compound command counts once.
+	creator.RecordPatternHits(&MatchResult{
+		Tier:           db.RiskTierDangerous,
+		MatchedPattern: reset,
+		MatchedSegments: []SegmentMatch{
+			{Segment: "git reset --hard", Tier: db.RiskTierDangerous, MatchedPattern: reset},
+			{Segment: "git stash", Tier: RiskTier(RiskSafe), MatchedPattern: status},
+		},
+	})
+	creator.RecordPatternHits(&MatchResult{})
+	hits, _ = database.ListPatternHits()
+	for _, h := range hits {
+		got[string(h.Tier)+" "+h.Pattern] = h.Hits
+	}
+	if got["dangerous "+reset] != 3 || got["safe "+status] != 2 {
+		t.Fatalf("unexpected hits after a compound command: %v", got)
+	}
+}
+
 func TestCreateRequest_CriticalCommand_RequiresDifferentModel(t *testing.T) {
 	database := testutil.NewTestDB(t)
 	session := testutil.MakeSession(t, database, testutil.SessionWithAgentName("agent1"))
 	creator := NewRequestCreator(database, nil, nil, nil)
 
-	result, err := creator.CreateRequest(CreateRequestOptions{
+	result, err := creator.CreateRequest(context.Background(), CreateRequestOptions{
 		SessionID: session.ID,
 		Command:   "rm -rf /etc/test",
 		Cwd:       "/",
@@ -304,7 +458,7 @@ func TestCreateRequest_SessionInactive(t *testing.T) {
 
 	creator := NewRequestCreator(database, nil, nil, nil)
 
-	_, err := creator.CreateRequest(CreateRequestOptions{
+	_, err := creator.CreateRequest(context.Background(), CreateRequestOptions{
 		SessionID: session.ID,
 		Command:   "rm -rf /tmp/test",
 	})
@@ -320,7 +474,7 @@ func TestCreateRequest_UnmatchedCommand(t *testing.T) {
 	creator := NewRequestCreator(database, nil, nil, nil)
 
 	// Command that doesn't match any patterns
-	result, err := creator.CreateRequest(CreateRequestOptions{
+	result, err := creator.CreateRequest(context.Background(), CreateRequestOptions{
 		SessionID: session.ID,
 		Command:   "echo hello world",
 	})
@@ -359,7 +513,7 @@ func TestCreateRequest_RateLimitActionQueue(t *testing.T) {
 	limiter := NewRateLimiter(database, config)
 	creator := NewRequestCreator(database, limiter, nil, nil)
 
-	_, err := creator.CreateRequest(CreateRequestOptions{
+	_, err := creator.CreateRequest(context.Background(), CreateRequestOptions{
 		SessionID: session.ID,
 		Command:   "rm -rf /tmp/test",
 	})
@@ -368,3 +522,235 @@ func TestCreateRequest_RateLimitActionQueue(t *testing.T) {
 		t.Error("expected error for rate limit queue action")
 	}
 }
+
+type stubAdvisor struct {
+	annotation *db.RequestAnnotation
+	err        error
+	calls      int
+}
+
+func (s *stubAdvisor) Review(ctx context.Context, req *db.Request) (*db.RequestAnnotation, error) {
+	s.calls++
+	return s.annotation, s.err
+}
+
+func TestCreateRequest_AdvisorAnnotationStored(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	session := testutil.MakeSession(t, database, testutil.SessionWithAgentName("agent1"))
+	config := DefaultRequestCreatorConfig()
+	config.AgentMailEnabled = false
+	creator := NewRequestCreator(database, nil, nil, config)
+	advisor := &stubAdvisor{annotation: &db.RequestAnnotation{
+		Source:         db.AnnotationSourceLLM,
+		Author:         "second-opinion",
+		RiskSummary:    "Discards three commits",
+		Recommendation: db.RecommendationReject,
+	}}
+	creator.SetAdvisor(advisor)
+
+	result, err := creator.CreateRequest(context.Background(), CreateRequestOptions{
+		SessionID:     session.ID,
+		Command:       "git reset --hard HEAD~3",
+		Justification: Justification{Reason: "Need to reset commits"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	creator.WaitForAdvice()
+	if advisor.calls != 1 {
+		t.Fatalf("expected advisor to be called once, got %d", advisor.calls)
+	}
+
+	stored, err := database.ListAnnotationsForRequest(result.Request.ID)
+	if err != nil {
+		t.Fatalf("ListAnnotationsForRequest: %v", err)
+	}
+	if len(stored) != 1 || stored[0].Recommendation != db.RecommendationReject || stored[0].RequestID != result.Request.ID {
+		t.Fatalf("unexpected stored annotations: %+v", stored)
+	}
+
+	// Advisory rejections never change the request status.
+	req, err := database.GetRequest(result.Request.ID)
+	if err != nil {
+		t.Fatalf("GetRequest: %v", err)
+	}
+	if req.Status != db.StatusPending {
+		t.Fatalf("expected pending status, got %s", req.Status)
+	}
+}
+
+func TestCreateRequest_AdvisorErrorIsNonFatal(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	session := testutil.MakeSession(t, database, testutil.SessionWithAgentName("agent1"))
+	config := DefaultRequestCreatorConfig()
+	config.AgentMailEnabled = false
+	creator := NewRequestCreator(database, nil, nil, config)
+	creator.SetAdvisor(&stubAdvisor{err: errors.New("endpoint down")})
+
+	result, err := creator.CreateRequest(context.Background(), CreateRequestOptions{
+		SessionID: session.ID,
+		Command:   "git reset --hard HEAD~3",
+	})
+	if err != nil {
+		t.Fatalf("advisor failure should not fail creation: %v", err)
+	}
+	creator.WaitForAdvice()
+	if result.Request == nil {
+		t.Fatalf("expected a request, got %+v", result)
+	}
+	if stored, _ := database.ListAnnotationsForRequest(result.Request.ID); len(stored) != 0 {
+		t.Fatalf("expected no annotation, got %+v", stored)
+	}
+}
+
+// blockingAdvisor answers only once released.
+type blockingAdvisor struct {
+	release chan struct{}
+}
+
+func (b *blockingAdvisor) Review(ctx context.Context, req *db.Request) (*db.RequestAnnotation, error) {
+	<-b.release
+	return &db.RequestAnnotation{
+		Source:         db.AnnotationSourceLLM,
+		Author:         "second-opinion",
+		RiskSummary:    "Discards three commits",
+		Recommendation: db.RecommendationUncertain,
+	}, nil
+}
+
+func TestCreateRequest_AdvisorDoesNotBlockCreation(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	session := testutil.MakeSession(t, database, testutil.SessionWithAgentName("agent1"))
+	config := DefaultRequestCreatorConfig()
+	config.AgentMailEnabled = false
+	creator := NewRequestCreator(database, nil, nil, config)
+	advisor := &blockingAdvisor{release: make(chan struct{})}
+	creator.SetAdvisor(advisor)
+
+	result, err := creator.CreateRequest(context.Background(), CreateRequestOptions{
+		SessionID:     session.ID,
+		Command:       "git reset --hard HEAD~3",
+		Justification: Justification{Reason: "Need to reset commits"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored, _ := database.ListAnnotationsForRequest(result.Request.ID); len(stored) != 0 {
+		t.Fatalf("expected no annotation before the advisor answers, got %+v", stored)
+	}
+
+	close(advisor.release)
+	creator.WaitForAdvice()
+	if stored, _ := database.ListAnnotationsForRequest(result.Request.ID); len(stored) != 1 {
+		t.Fatalf("expected the annotation once the advisor answered, got %+v", stored)
+	}
+}
+
+func TestCreateRequest_IdempotencyKeyReplays(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	clk := testutil.NewFakeClock(time.Now().UTC().Truncate(time.Second))
+	database.SetClock(clk)
+	session := testutil.MakeSession(t, database)
+
+	// One pending request per session: a retry must replay, not be limited.
+	limits := DefaultRateLimitConfig()
+	limits.MaxPendingPerSession = 1
+	config := DefaultRequestCreatorConfig()
+	config.AgentMailEnabled = false
+	config.IdempotencyTTLMinutes = 10
+	creator := NewRequestCreator(database, NewRateLimiter(database, limits), nil, config)
+
+	opts := CreateRequestOptions{
+		SessionID:      session.ID,
+		Command:        "git reset --hard HEAD~3",
+		Cwd:            "/project",
+		Justification:  Justification{Reason: "Need to reset commits"},
+		Labels:         map[string]string{"team": "infra"},
+		IdempotencyKey: "retry-1",
+	}
+	first, err := creator.CreateRequest(context.Background(), opts)
+	testutil.RequireNoError(t, err, "first submission")
+	if first.Replayed {
+		t.Fatal("first submission reported as replayed")
+	}
+
+	again, err := creator.CreateRequest(context.Background(), opts)
+	testutil.RequireNoError(t, err, "retry")
+	if !again.Replayed {
+		t.Fatal("retry was not replayed")
+	}
+	testutil.RequireEqual(t, first.Request.ID, again.Request.ID, "replayed request id")
+	testutil.RequireEqual(t, "infra", again.Request.Labels["team"], "replayed labels")
+
+	opts.Command = "git reset --hard HEAD~4"
+	if _, err := creator.CreateRequest(context.Background(), opts); !errors.Is(err, db.ErrIdempotencyKeyReused) {
+		t.Fatalf("expected ErrIdempotencyKeyReused for a different command, got %v", err)
+	}
+
+	// Once the key expires it no longer replays; the rate limit applies again.
+	clk.Advance(11 * time.Minute)
+	opts.Command = "git reset --hard HEAD~3"
+	if _, err := creator.CreateRequest(context.Background(), opts); err == nil || errors.Is(err, db.ErrIdempotencyKeyReused) {
+		t.Fatalf("expected the rate limit after the key expired, got %v", err)
+	}
+}
+
+func TestCreateRequest_InvalidIdempotencyKey(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	session := testutil.MakeSession(t, database)
+	creator := NewRequestCreator(database, nil, nil, nil)
+
+	_, err := creator.CreateRequest(context.Background(), CreateRequestOptions{
+		SessionID:      session.ID,
+		Command:        "rm -rf /tmp/test",
+		IdempotencyKey: "has space",
+	})
+	if err == nil {
+		t.Fatal("expected an invalid idempotency key to be refused")
+	}
+}
+
+// TestRequestAdvice_RedactsPayload guards the LLM second opinion: nothing
+// the endpoint receives may carry a secret, whether the default patterns or
+// the request's own --redact patterns catch it.
+func TestRequestAdvice_RedactsPayload(t *testing.T) {
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		_ = json.NewEncoder(w).Encode(integrations.LLMReviewResponse{RiskSummary: "ok", Recommendation: "approve"})
+	}))
+	defer srv.Close()
+
+	database := testutil.NewTestDB(t)
+	config := DefaultRequestCreatorConfig()
+	config.AgentMailEnabled = false
+	creator := NewRequestCreator(database, nil, nil, config)
+	creator.SetAdvisor(integrations.NewLLMReviewer(srv.URL, "", "", time.Second))
+
+	creator.requestAdvice(&db.Request{
+		ID:       "req-advice",
+		RiskTier: db.RiskTierDangerous,
+		Command:  db.CommandSpec{Raw: "deploy --key hunter2", Cwd: "/srv/hunter2"},
+		Justification: db.Justification{
+			Reason: "rotate token=abc123",
+			Goal:   "use hunter2 everywhere",
+		},
+		DryRun: &db.DryRunResult{
+			Command: "deploy --dry-run",
+			Output:  "connecting with password=s3cr3t as hunter2",
+		},
+	}, []string{"hunter2"})
+	creator.WaitForAdvice()
+
+	if len(body) == 0 {
+		t.Fatal("advisor endpoint was not called")
+	}
+	for _, secret := range []string{"s3cr3t", "abc123", "hunter2"} {
+		if strings.Contains(string(body), secret) {
+			t.Errorf("secret %q sent to the LLM endpoint: %s", secret, body)
+		}
+	}
+	if !strings.Contains(string(body), "[REDACTED]") {
+		t.Errorf("expected redaction markers in the payload: %s", body)
+	}
+}
@@ -0,0 +1,292 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// maxWordDiffCells bounds the LCS table DiffWords builds. Past it the
+// differing middle is reported as one removal and one addition.
+const maxWordDiffCells = 1 << 20
+
+// WordOp says whether a run of words is in both texts, only the old one, or
+// only the new one.
+type WordOp string
+
+const (
+	WordSame    WordOp = "same"
+	WordRemoved WordOp = "removed"
+	WordAdded   WordOp = "added"
+)
+
+// WordChange is a run of consecutive words sharing an op, joined by single
+// spaces.
+type WordChange struct {
+	Op   WordOp `json:"op"`
+	Text string `json:"text"`
+}
+
+// DiffWords compares two texts word by word (split on whitespace) and
+// returns the runs of kept, removed and added words in order.
+func DiffWords(oldText, newText string) []WordChange {
+	a, b := strings.Fields(oldText), strings.Fields(newText)
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix &&
+		a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var ops []wordOp
+	for _, w := range a[:prefix] {
+		ops = append(ops, wordOp{WordSame, w})
+	}
+	ops = append(ops, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, w := range a[len(a)-suffix:] {
+		ops = append(ops, wordOp{WordSame, w})
+	}
+
+	var out []WordChange
+	for _, op := range ops {
+		if n := len(out); n > 0 && out[n-1].Op == op.op {
+			out[n-1].Text += " " + op.word
+			continue
+		}
+		out = append(out, WordChange{Op: op.op, Text: op.word})
+	}
+	return out
+}
+
+type wordOp struct {
+	op   WordOp
+	word string
+}
+
+// diffMiddle diffs the words between the common prefix and suffix using a
+// longest-common-subsequence table, listing removals before additions
+// within each changed stretch.
+func diffMiddle(a, b []string) []wordOp {
+	var ops []wordOp
+	if len(a)*len(b) > maxWordDiffCells {
+		for _, w := range a {
+			ops = append(ops, wordOp{WordRemoved, w})
+		}
+		for _, w := range b {
+			ops = append(ops, wordOp{WordAdded, w})
+		}
+		return ops
+	}
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, wordOp{WordSame, a[i]})
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, wordOp{WordRemoved, a[i]})
+			i++
+		default:
+			ops = append(ops, wordOp{WordAdded, b[j]})
+			j++
+		}
+	}
+	return ops
+}
+
+// FormatWordDiff renders a word diff inline, as `git diff --word-diff`
+// does: removed words as [-...-] and added words as {+...+}.
+func FormatWordDiff(changes []WordChange) string {
+	parts := make([]string, 0, len(changes))
+	for _, c := range changes {
+		switch c.Op {
+		case WordRemoved:
+			parts = append(parts, "[-"+c.Text+"-]")
+		case WordAdded:
+			parts = append(parts, "{+"+c.Text+"+}")
+		default:
+			parts = append(parts, c.Text)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// FieldChange is a field whose value differs between two requests. An
+// empty side means the field is unset on that request.
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old,omitempty"`
+	New   string `json:"new,omitempty"`
+}
+
+// RequestSnapshot is one side of a request diff: the request with its
+// reviews and recorded outcome (nil when none was recorded).
+type RequestSnapshot struct {
+	Request *db.Request
+	Reviews []*db.Review
+	Outcome *db.ExecutionOutcome
+}
+
+// RequestDiff compares two requests, typically a rejected request and the
+// revised version its agent resubmitted.
+type RequestDiff struct {
+	From           string       `json:"from"`
+	To             string       `json:"to"`
+	CommandChanged bool         `json:"command_changed"`
+	Command        []WordChange `json:"command"`
+	// Justification, Context and Outcome list only the fields that differ.
+	// Context covers where and how the command runs plus its attachments;
+	// Outcome covers the decision, reviews, execution and recorded outcome.
+	Justification []FieldChange `json:"justification,omitempty"`
+	Context       []FieldChange `json:"context,omitempty"`
+	Outcome       []FieldChange `json:"outcome,omitempty"`
+}
+
+// Identical reports whether the two requests differ in nothing compared.
+func (d *RequestDiff) Identical() bool {
+	return !d.CommandChanged && len(d.Justification) == 0 && len(d.Context) == 0 && len(d.Outcome) == 0
+}
+
+// DiffRequests compares two requests. Sensitive commands are compared in
+// their redacted form so the diff never reveals what was redacted.
+func DiffRequests(from, to RequestSnapshot) *RequestDiff {
+	d := &RequestDiff{From: from.Request.ID, To: to.Request.ID}
+
+	oldCmd, newCmd := diffCommandText(from.Request), diffCommandText(to.Request)
+	d.CommandChanged = strings.Join(strings.Fields(oldCmd), " ") != strings.Join(strings.Fields(newCmd), " ")
+	d.Command = DiffWords(oldCmd, newCmd)
+
+	d.Justification = diffFields(justificationFields(from.Request), justificationFields(to.Request))
+	d.Context = diffFields(contextFields(from.Request), contextFields(to.Request))
+	d.Outcome = diffFields(outcomeFields(from), outcomeFields(to))
+	return d
+}
+
+func diffCommandText(r *db.Request) string {
+	if r.Command.ContainsSensitive && r.Command.DisplayRedacted != "" {
+		return r.Command.DisplayRedacted
+	}
+	return r.Command.Raw
+}
+
+// namedValue is a field and its rendered value, kept in display order.
+type namedValue struct {
+	field string
+	value string
+}
+
+// diffFields compares two field lists by name. Fields present on only one
+// side come after the shared ones, in the order that side lists them.
+func diffFields(from, to []namedValue) []FieldChange {
+	toValues := make(map[string]string, len(to))
+	for _, f := range to {
+		toValues[f.field] = f.value
+	}
+	seen := make(map[string]bool, len(from))
+	var out []FieldChange
+	for _, f := range from {
+		seen[f.field] = true
+		if f.value != toValues[f.field] {
+			out = append(out, FieldChange{Field: f.field, Old: f.value, New: toValues[f.field]})
+		}
+	}
+	for _, f := range to {
+		if !seen[f.field] && f.value != "" {
+			out = append(out, FieldChange{Field: f.field, New: f.value})
+		}
+	}
+	return out
+}
+
+func justificationFields(r *db.Request) []namedValue {
+	return []namedValue{
+		{"reason", r.Justification.Reason},
+		{"expected_effect", r.Justification.ExpectedEffect},
+		{"goal", r.Justification.Goal},
+		{"safety_argument", r.Justification.SafetyArgument},
+	}
+}
+
+func contextFields(r *db.Request) []namedValue {
+	fields := []namedValue{
+		{"project_path", r.ProjectPath},
+		{"cwd", r.Command.Cwd},
+		{"shell", strconv.FormatBool(r.Command.Shell)},
+		{"risk_tier", string(r.RiskTier)},
+		{"risk_score", strconv.Itoa(r.RiskScore)},
+		{"labels", db.FormatLabels(r.Labels, ", ")},
+	}
+	if r.DryRun != nil {
+		fields = append(fields,
+			namedValue{"dry_run.command", r.DryRun.Command},
+			namedValue{"dry_run.output", r.DryRun.Output},
+		)
+	}
+	for i, a := range r.Attachments {
+		text, err := a.Text()
+		if err != nil {
+			text = a.Content
+		}
+		fields = append(fields, namedValue{fmt.Sprintf("attachments[%d]", i), fmt.Sprintf("%s: %s", a.Type, text)})
+	}
+	return fields
+}
+
+func outcomeFields(s RequestSnapshot) []namedValue {
+	r := s.Request
+	fields := []namedValue{
+		{"status", string(r.Status)},
+		{"reviews", formatReviews(s.Reviews)},
+	}
+	if r.Execution != nil && r.Execution.ExitCode != nil {
+		fields = append(fields, namedValue{"exit_code", strconv.Itoa(*r.Execution.ExitCode)})
+	}
+	if o := s.Outcome; o != nil {
+		fields = append(fields,
+			namedValue{"caused_problems", strconv.FormatBool(o.CausedProblems)},
+			namedValue{"problem_description", o.ProblemDescription},
+			namedValue{"human_notes", o.HumanNotes},
+		)
+		if o.HumanRating != nil {
+			fields = append(fields, namedValue{"human_rating", strconv.Itoa(*o.HumanRating)})
+		}
+	}
+	return fields
+}
+
+// formatReviews renders reviews as "agent decision: comments" entries
+// joined by "; ".
+func formatReviews(reviews []*db.Review) string {
+	parts := make([]string, 0, len(reviews))
+	for _, r := range reviews {
+		part := fmt.Sprintf("%s %s", r.ReviewerAgent, r.Decision)
+		if r.Comments != "" {
+			part += ": " + r.Comments
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,109 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+func TestDiffWords(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		want     string
+	}{
+		{"identical", "rm -rf ./build", "rm  -rf ./build", "rm -rf ./build"},
+		{"replace", "rm -rf ./build", "rm -rf ./build/cache", "rm -rf [-./build-] {+./build/cache+}"},
+		{"insert", "git push origin main", "git push --force-with-lease origin main", "git push {+--force-with-lease+} origin main"},
+		{"delete", "kubectl delete ns staging --force --grace-period=0", "kubectl delete ns staging", "kubectl delete ns staging [---force --grace-period=0-]"},
+		{"from empty", "", "ls -la", "{+ls -la+}"},
+		{"to empty", "ls -la", "", "[-ls -la-]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatWordDiff(DiffWords(tt.old, tt.new)); got != tt.want {
+				t.Errorf("DiffWords(%q, %q) = %q, want %q", tt.old, tt.new, got, tt.want)
+			}
+		})
+	}
+
+	got := DiffWords("a b c", "a x c")
+	want := []WordChange{{WordSame, "a"}, {WordRemoved, "b"}, {WordAdded, "x"}, {WordSame, "c"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffWords runs = %+v, want %+v", got, want)
+	}
+}
+
+func TestDiffRequests(t *testing.T) {
+	exit := 1
+	rating := 2
+	from := RequestSnapshot{
+		Request: &db.Request{
+			ID:            "req-1",
+			ProjectPath:   "/p",
+			Command:       db.CommandSpec{Raw: "rm -rf ./build", Cwd: "/p"},
+			RiskTier:      db.RiskTierDangerous,
+			RiskScore:     60,
+			Status:        db.StatusRejected,
+			Justification: db.Justification{Reason: "clean build", Goal: "rebuild"},
+			Execution:     &db.Execution{ExitCode: &exit},
+		},
+		Reviews: []*db.Review{{ReviewerAgent: "Bob", Decision: db.DecisionReject, Comments: "too broad"}},
+		Outcome: &db.ExecutionOutcome{CausedProblems: true, HumanRating: &rating},
+	}
+	to := RequestSnapshot{
+		Request: &db.Request{
+			ID:            "req-2",
+			ProjectPath:   "/p",
+			Command:       db.CommandSpec{Raw: "rm -rf ./build/cache", Cwd: "/p"},
+			RiskTier:      db.RiskTierDangerous,
+			RiskScore:     55,
+			Status:        db.StatusPending,
+			Justification: db.Justification{Reason: "clear stale cache only", Goal: "rebuild"},
+			Attachments:   []db.Attachment{{Type: db.AttachmentTypeContext, Content: "cache is 4GB"}},
+		},
+	}
+
+	d := DiffRequests(from, to)
+	if d.From != "req-1" || d.To != "req-2" || !d.CommandChanged || d.Identical() {
+		t.Fatalf("unexpected diff header: %+v", d)
+	}
+	if got := FormatWordDiff(d.Command); got != "rm -rf [-./build-] {+./build/cache+}" {
+		t.Errorf("command diff = %q", got)
+	}
+	if want := []FieldChange{{Field: "reason", Old: "clean build", New: "clear stale cache only"}}; !reflect.DeepEqual(d.Justification, want) {
+		t.Errorf("justification = %+v", d.Justification)
+	}
+	if want := []FieldChange{
+		{Field: "risk_score", Old: "60", New: "55"},
+		{Field: "attachments[0]", New: "context: cache is 4GB"},
+	}; !reflect.DeepEqual(d.Context, want) {
+		t.Errorf("context = %+v", d.Context)
+	}
+	if want := []FieldChange{
+		{Field: "status", Old: "rejected", New: "pending"},
+		{Field: "reviews", Old: "Bob reject: too broad"},
+		{Field: "exit_code", Old: "1"},
+		{Field: "caused_problems", Old: "true"},
+		{Field: "human_rating", Old: "2"},
+	}; !reflect.DeepEqual(d.Outcome, want) {
+		t.Errorf("outcome = %+v", d.Outcome)
+	}
+
+	if same := DiffRequests(to, to); !same.Identical() {
+		t.Errorf("a request should not differ from itself: %+v", same)
+	}
+}
+
+func TestDiffRequests_ComparesRedactedCommands(t *testing.T) {
+	secret := func(id, raw string) RequestSnapshot {
+		return RequestSnapshot{Request: &db.Request{ID: id, Command: db.CommandSpec{
+			Raw: raw, DisplayRedacted: "curl -H [REDACTED] https://api", ContainsSensitive: true,
+		}}}
+	}
+	d := DiffRequests(secret("a", "curl -H token1 https://api"), secret("b", "curl -H token2 https://api"))
+	if d.CommandChanged {
+		t.Errorf("redacted commands should compare equal: %+v", d.Command)
+	}
+}
@@ -5,8 +5,11 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/core/statemachine"
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/Dicklesworthstone/slb/internal/integrations"
 )
@@ -20,8 +23,22 @@ var (
 	ErrInvalidDecision    = errors.New("invalid decision (must be approve or reject)")
 	ErrMissingSessionKey  = errors.New("session key required for signature")
 	ErrSessionKeyMismatch = errors.New("session key does not match session")
+	ErrFreshAuthRequired  = errors.New("fresh authentication required (sudo mode)")
 )
 
+// DefaultFreshAuthMaxAge is how long a sudo-mode re-authentication counts
+// as fresh when ReviewConfig.FreshAuthMaxAge is unset.
+const DefaultFreshAuthMaxAge = 2 * time.Minute
+
+// FreshAuth is proof that the reviewer re-authenticated (passphrase or
+// hardware key) just before deciding.
+type FreshAuth struct {
+	// Method is "passphrase" or "fido2".
+	Method string
+	// At is when the reviewer authenticated.
+	At time.Time
+}
+
 // ConflictResolution specifies how to handle conflicting reviews.
 type ConflictResolution string
 
@@ -48,6 +65,20 @@ type ReviewOptions struct {
 	Responses db.ReviewResponse
 	// Comments contains optional additional comments.
 	Comments string
+	// ExpectedVersion is the request version the decision was based on
+	// (optional). If the request has changed since, the review fails with a
+	// *db.VersionConflictError and the reviewer should refresh.
+	ExpectedVersion int
+	// FreshAuth is required to approve requests in ReviewConfig's
+	// FreshAuthTiers and is recorded on the review when given.
+	FreshAuth *FreshAuth
+	// AcknowledgedVars must name exactly the command's template variables
+	// (see TemplateVars) to approve it while they are unexpanded.
+	AcknowledgedVars []string
+
+	// approvedCommand is the command an approval covers when it is not the
+	// request's own: a reviewer's proposed edit.
+	approvedCommand string
 }
 
 // ReviewConfig provides configuration for the review process.
@@ -61,6 +92,12 @@ type ReviewConfig struct {
 	// DifferentModelTimeout is how long to wait for a different-model reviewer
 	// before escalating to human when require_different_model is set.
 	DifferentModelTimeout time.Duration
+	// FreshAuthTiers are the tiers whose approvals need FreshAuth (sudo
+	// mode). Rejections never do.
+	FreshAuthTiers []db.RiskTier
+	// FreshAuthMaxAge bounds how old FreshAuth may be; zero means
+	// DefaultFreshAuthMaxAge.
+	FreshAuthMaxAge time.Duration
 }
 
 // DefaultReviewConfig returns the default review configuration.
@@ -73,6 +110,21 @@ func DefaultReviewConfig() ReviewConfig {
 	}
 }
 
+// ReviewConfigFromConfig returns the default review config with cfg's sudo
+// mode applied. Every review path (CLI, TUI, web) builds its config here so
+// they enforce the same fresh-auth rules.
+func ReviewConfigFromConfig(cfg config.Config) ReviewConfig {
+	rc := DefaultReviewConfig()
+	if !cfg.SudoMode.Enabled {
+		return rc
+	}
+	for _, tier := range cfg.SudoMode.Tiers {
+		rc.FreshAuthTiers = append(rc.FreshAuthTiers, db.RiskTier(strings.ToLower(strings.TrimSpace(tier))))
+	}
+	rc.FreshAuthMaxAge = time.Duration(cfg.SudoMode.MaxAgeSecs) * time.Second
+	return rc
+}
+
 // ReviewResult contains the result of submitting a review.
 type ReviewResult struct {
 	// Review is the created review.
@@ -85,6 +137,15 @@ type ReviewResult struct {
 	Approvals int
 	// Rejections is the current rejection count.
 	Rejections int
+	// DelegatedApprovals is how many of Approvals come from delegations.
+	DelegatedApprovals int
+	// DelegatedFrom lists the delegators whose authority was exercised.
+	DelegatedFrom []string
+	// Version is the request's version after this review.
+	Version int
+
+	// event is the stored transition, emitted once the review commits.
+	event *statemachine.Event
 }
 
 // ReviewService handles review operations.
@@ -92,6 +153,7 @@ type ReviewService struct {
 	db       *db.DB
 	config   ReviewConfig
 	notifier integrations.RequestNotifier
+	machine  *statemachine.Machine
 }
 
 // NewReviewService creates a new review service.
@@ -100,6 +162,7 @@ func NewReviewService(database *db.DB, config ReviewConfig) *ReviewService {
 		db:       database,
 		config:   config,
 		notifier: integrations.NoopNotifier{},
+		machine:  statemachine.New(database),
 	}
 }
 
@@ -110,9 +173,110 @@ func (rs *ReviewService) SetNotifier(n integrations.RequestNotifier) {
 	}
 }
 
+// SetStateMachine sets the machine that applies review-driven status changes,
+// so callers can listen for its transition events (optional).
+func (rs *ReviewService) SetStateMachine(m *statemachine.Machine) {
+	if m != nil {
+		rs.machine = m
+	}
+}
+
 // SubmitReview validates and submits a review for a request.
 // Returns the created review and any status change to the request.
 func (rs *ReviewService) SubmitReview(opts ReviewOptions) (*ReviewResult, error) {
+	p, err := rs.prepareReview(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *ReviewResult
+	err = rs.db.Transaction(func(tx *sql.Tx) error {
+		var err error
+		result, err = rs.recordReviewTx(tx, p)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rs.machine.Emit(result.event)
+	rs.notify(p)
+	rs.suggestFromRejection(p)
+	return result, nil
+}
+
+// BatchReviewError reports the items of a batch that could not be reviewed.
+// When it is returned, no review in the batch was recorded.
+type BatchReviewError struct {
+	// Errs maps the index of each failed item to its error.
+	Errs map[int]error
+}
+
+func (e *BatchReviewError) Error() string {
+	return fmt.Sprintf("%d review(s) in batch failed; no reviews were recorded", len(e.Errs))
+}
+
+// SubmitReviews validates and submits several reviews atomically: every item
+// is validated first, then all reviews are recorded in a single transaction.
+// If any item fails, nothing is recorded and a *BatchReviewError describes
+// the failures. Results are returned in input order.
+func (rs *ReviewService) SubmitReviews(batch []ReviewOptions) ([]*ReviewResult, error) {
+	prepared := make([]*preparedReview, len(batch))
+	batchErr := &BatchReviewError{Errs: map[int]error{}}
+	seen := make(map[string]int, len(batch))
+	for i, opts := range batch {
+		if j, dup := seen[opts.RequestID]; dup {
+			batchErr.Errs[i] = fmt.Errorf("request %s appears more than once in batch (item %d)", opts.RequestID, j)
+			continue
+		}
+		seen[opts.RequestID] = i
+
+		p, err := rs.prepareReview(opts)
+		if err != nil {
+			batchErr.Errs[i] = err
+			continue
+		}
+		prepared[i] = p
+	}
+	if len(batchErr.Errs) > 0 {
+		return nil, batchErr
+	}
+
+	results := make([]*ReviewResult, len(prepared))
+	err := rs.db.Transaction(func(tx *sql.Tx) error {
+		for i, p := range prepared {
+			result, err := rs.recordReviewTx(tx, p)
+			if err != nil {
+				batchErr.Errs[i] = err
+				return batchErr
+			}
+			results[i] = result
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, p := range prepared {
+		rs.machine.Emit(results[i].event)
+		rs.notify(p)
+		rs.suggestFromRejection(p)
+	}
+	return results, nil
+}
+
+// preparedReview is a validated, signed review ready to be recorded.
+type preparedReview struct {
+	request         *db.Request
+	review          *db.Review
+	decision        db.Decision
+	expectedVersion int
+}
+
+// prepareReview runs the checks that don't need a write transaction and
+// builds the signed review.
+func (rs *ReviewService) prepareReview(opts ReviewOptions) (*preparedReview, error) {
 	// Validate required fields
 	if opts.SessionID == "" {
 		return nil, errors.New("session_id is required")
@@ -126,6 +290,22 @@ func (rs *ReviewService) SubmitReview(opts ReviewOptions) (*ReviewResult, error)
 	if opts.Decision != db.DecisionApprove && opts.Decision != db.DecisionReject {
 		return nil, ErrInvalidDecision
 	}
+	for _, f := range []struct {
+		name  string
+		value *string
+	}{
+		{"comments", &opts.Comments},
+		{"reason_response", &opts.Responses.ReasonResponse},
+		{"effect_response", &opts.Responses.EffectResponse},
+		{"goal_response", &opts.Responses.GoalResponse},
+		{"safety_response", &opts.Responses.SafetyResponse},
+	} {
+		clean, err := SanitizeText(f.name, *f.value, MaxTextBytes)
+		if err != nil {
+			return nil, err
+		}
+		*f.value = clean
+	}
 
 	// Step 1: Get and validate session
 	session, err := rs.db.GetSession(opts.SessionID)
@@ -135,7 +315,7 @@ func (rs *ReviewService) SubmitReview(opts ReviewOptions) (*ReviewResult, error)
 	if !session.IsActive() {
 		return nil, ErrSessionInactive
 	}
-	if opts.SessionKey != session.SessionKey {
+	if !db.SessionKeyMatches(opts.SessionKey, session.SessionKey) {
 		return nil, ErrSessionKeyMismatch
 	}
 
@@ -144,7 +324,7 @@ func (rs *ReviewService) SubmitReview(opts ReviewOptions) (*ReviewResult, error)
 	if err != nil {
 		return nil, fmt.Errorf("getting request: %w", err)
 	}
-	if !CanApprove(request.Status) {
+	if !statemachine.CanApprove(request.Status) {
 		return nil, fmt.Errorf("%w: status is %s", ErrRequestNotPending, request.Status)
 	}
 
@@ -177,7 +357,26 @@ func (rs *ReviewService) SubmitReview(opts ReviewOptions) (*ReviewResult, error)
 		}
 	}
 
-	// Step 6: Generate signature
+	// Step 6: Sudo mode: approving these tiers needs a fresh re-authentication
+	if opts.Decision == db.DecisionApprove && rs.config.RequiresFreshAuth(request.RiskTier) {
+		if err := rs.checkFreshAuth(opts.FreshAuth, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	// Step 7: Approving a command with unexpanded template variables needs
+	// them acknowledged
+	if opts.Decision == db.DecisionApprove {
+		command := request.Command.Raw
+		if opts.approvedCommand != "" {
+			command = opts.approvedCommand
+		}
+		if err := CheckTemplateVarsAcknowledged(command, opts.AcknowledgedVars); err != nil {
+			return nil, err
+		}
+	}
+
+	// Step 8: Generate signature
 	timestamp := time.Now().UTC()
 	signature := db.ComputeReviewSignature(opts.SessionKey, opts.RequestID, opts.Decision, timestamp)
 
@@ -192,67 +391,156 @@ func (rs *ReviewService) SubmitReview(opts ReviewOptions) (*ReviewResult, error)
 		Responses:          opts.Responses,
 		Comments:           opts.Comments,
 	}
+	if opts.FreshAuth != nil {
+		at := opts.FreshAuth.At.UTC()
+		review.AuthMethod = opts.FreshAuth.Method
+		review.AuthenticatedAt = &at
+	}
+
+	return &preparedReview{request: request, review: review, decision: opts.Decision, expectedVersion: opts.ExpectedVersion}, nil
+}
+
+// RequiresFreshAuth reports whether approving a request of tier needs
+// FreshAuth.
+func (c ReviewConfig) RequiresFreshAuth(tier db.RiskTier) bool {
+	for _, t := range c.FreshAuthTiers {
+		if t == tier {
+			return true
+		}
+	}
+	return false
+}
+
+func (rs *ReviewService) checkFreshAuth(auth *FreshAuth, now time.Time) error {
+	if auth == nil || auth.Method == "" || auth.At.IsZero() {
+		return ErrFreshAuthRequired
+	}
+	maxAge := rs.config.FreshAuthMaxAge
+	if maxAge <= 0 {
+		maxAge = DefaultFreshAuthMaxAge
+	}
+	if age := now.Sub(auth.At); age > maxAge {
+		return fmt.Errorf("%w: authenticated %s ago, limit %s", ErrFreshAuthRequired, age.Round(time.Second), maxAge)
+	}
+	return nil
+}
 
+// recordReviewTx inserts a prepared review and applies any resulting status
+// change inside tx.
+func (rs *ReviewService) recordReviewTx(tx *sql.Tx, p *preparedReview) (*ReviewResult, error) {
+	review := p.review
+	requestID := review.RequestID
 	result := &ReviewResult{
 		Review: review,
 	}
 
-	// Execute review creation and status update in a transaction
-	err = rs.db.Transaction(func(tx *sql.Tx) error {
-		// Re-fetch request inside transaction to lock (if using serialized) or at least get fresh state
-		// Note: SQLite doesn't strictly lock on read unless BEGIN IMMEDIATE, but this helps.
-		// However, CreateReviewTx (insert) will lock the DB for writing.
-
-		// Check duplicate again inside transaction
-		if exists, err := rs.db.HasReviewerAlreadyReviewedTx(tx, opts.RequestID, opts.SessionID); err != nil {
-			return err
-		} else if exists {
-			return ErrAlreadyReviewed
+	// A decision made on a stale view must not count toward quorum.
+	if p.expectedVersion > 0 {
+		current, err := rs.db.RequestVersionTx(tx, requestID)
+		if err != nil {
+			return nil, fmt.Errorf("getting request version: %w", err)
 		}
-
-		if err := rs.db.CreateReviewTx(tx, review); err != nil {
-			return fmt.Errorf("creating review: %w", err)
+		if current != p.expectedVersion {
+			return nil, &db.VersionConflictError{RequestID: requestID, Expected: p.expectedVersion, Current: current}
 		}
+	}
 
-		approvals, rejections, err := rs.db.CountReviewsByDecisionTx(tx, opts.RequestID)
-		if err != nil {
-			return fmt.Errorf("counting reviews: %w", err)
-		}
-		result.Approvals = approvals
-		result.Rejections = rejections
+	// The request may have been decided, or even started executing, since
+	// prepareReview read it.
+	reqTx, err := rs.db.GetRequestTx(tx, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("getting request: %w", err)
+	}
+	if !statemachine.CanApprove(reqTx.Status) {
+		return nil, fmt.Errorf("%w: status is %s", ErrRequestNotPending, reqTx.Status)
+	}
 
-		// Get latest status for transition check
-		reqTx, err := rs.db.GetRequestTx(tx, opts.RequestID)
+	// Re-check duplicates inside the transaction.
+	if exists, err := rs.db.HasReviewerAlreadyReviewedTx(tx, requestID, review.ReviewerSessionID); err != nil {
+		return nil, err
+	} else if exists {
+		return nil, ErrAlreadyReviewed
+	}
+
+	if err := rs.db.CreateReviewTx(tx, review); err != nil {
+		return nil, fmt.Errorf("creating review: %w", err)
+	}
+
+	approvals, rejections, err := rs.db.CountReviewsByDecisionTx(tx, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("counting reviews: %w", err)
+	}
+	result.Rejections = rejections
+
+	if err := rs.db.BumpRequestVersionTx(tx, requestID, reqTx.Version); err != nil {
+		return nil, err
+	}
+
+	// Approvals from delegates also count for absent delegators.
+	// first_wins decides on the first review alone, so quorum weighting
+	// doesn't apply, and a delegator cannot re-authenticate for tiers that
+	// need fresh auth.
+	if approvals > 0 && rs.config.ConflictResolution != ConflictFirstWins && !rs.config.RequiresFreshAuth(reqTx.RiskTier) {
+		delegations, err := rs.db.ListActiveDelegationsTx(tx, reqTx.ProjectPath, review.SignatureTimestamp)
 		if err != nil {
-			return fmt.Errorf("getting request: %w", err)
+			return nil, fmt.Errorf("listing delegations: %w", err)
 		}
-
-		// Apply conflict resolution rules
-		newStatus := rs.determineNewStatus(reqTx, opts.Decision, approvals, rejections)
-		if newStatus != "" && newStatus != reqTx.Status {
-			// Pass current status for optimistic locking check
-			if err := rs.db.UpdateRequestStatusTx(tx, opts.RequestID, newStatus, reqTx.Status); err != nil {
-				return fmt.Errorf("updating request status: %w", err)
+		if len(delegations) > 0 {
+			reviews, err := rs.db.ListReviewsForRequestTx(tx, requestID)
+			if err != nil {
+				return nil, fmt.Errorf("listing reviews: %w", err)
+			}
+			delegators := make(map[string]*db.Session)
+			for _, d := range delegations {
+				if d.FromSessionID == "" || delegators[d.FromSessionID] != nil {
+					continue
+				}
+				sess, err := rs.db.GetSessionTx(tx, d.FromSessionID)
+				if err != nil {
+					if errors.Is(err, db.ErrSessionNotFound) {
+						continue
+					}
+					return nil, fmt.Errorf("getting delegator session: %w", err)
+				}
+				delegators[d.FromSessionID] = sess
 			}
-			result.RequestStatusChanged = true
-			result.NewRequestStatus = newStatus
+			extra, onBehalfOf := DelegatedApprovals(reqTx, reviews, delegations, delegators)
+			approvals += extra
+			result.DelegatedApprovals = extra
+			result.DelegatedFrom = onBehalfOf
 		}
-		return nil
-	})
+	}
+	result.Approvals = approvals
 
-	if err != nil {
-		return nil, err
+	// Apply conflict resolution rules
+	newStatus := rs.determineNewStatus(reqTx, p.decision, approvals, rejections)
+	if newStatus != "" && newStatus != reqTx.Status {
+		in := reviewInput(rs.config.ConflictResolution, approvals)
+		in.Reason = fmt.Sprintf("%d approval(s), %d rejection(s) under %s", approvals, rejections, rs.config.ConflictResolution)
+		in.Actor = review.ReviewerAgent
+		ev, err := rs.machine.CommitTx(tx, reqTx, newStatus, in)
+		if err != nil {
+			return nil, fmt.Errorf("updating request status: %w", err)
+		}
+		result.RequestStatusChanged = true
+		result.NewRequestStatus = newStatus
+		result.event = ev
+	}
+
+	if result.Version, err = rs.db.RequestVersionTx(tx, requestID); err != nil {
+		return nil, fmt.Errorf("getting request version: %w", err)
 	}
+	return result, nil
+}
 
-	// Notify asynchronously (best effort)
-	switch opts.Decision {
+// notify sends the best-effort notification for a recorded review.
+func (rs *ReviewService) notify(p *preparedReview) {
+	switch p.decision {
 	case db.DecisionApprove:
-		_ = rs.notifier.NotifyRequestApproved(request, review)
+		_ = rs.notifier.NotifyRequestApproved(p.request, p.review)
 	case db.DecisionReject:
-		_ = rs.notifier.NotifyRequestRejected(request, review)
+		_ = rs.notifier.NotifyRequestRejected(p.request, p.review)
 	}
-
-	return result, nil
 }
 
 // isTrustedSelfApprove checks if an agent is in the trusted self-approve list.
@@ -271,42 +559,7 @@ func (rs *ReviewService) determineNewStatus(
 	decision db.Decision,
 	approvals, rejections int,
 ) db.RequestStatus {
-	switch rs.config.ConflictResolution {
-	case ConflictAnyRejectionBlocks:
-		// Any rejection immediately blocks
-		if rejections > 0 {
-			return db.StatusRejected
-		}
-		// Check if we have enough approvals
-		if approvals >= request.MinApprovals {
-			return db.StatusApproved
-		}
-
-	case ConflictFirstWins:
-		// First review determines outcome
-		if approvals+rejections == 1 {
-			if decision == db.DecisionApprove {
-				return db.StatusApproved
-			}
-			return db.StatusRejected
-		}
-
-	case ConflictHumanBreaksTie:
-		// If there's a mix of approvals and rejections, escalate
-		if approvals > 0 && rejections > 0 {
-			return db.StatusEscalated
-		}
-		// Otherwise, check if we have enough approvals
-		if approvals >= request.MinApprovals {
-			return db.StatusApproved
-		}
-		// Or if any rejections
-		if rejections > 0 {
-			return db.StatusRejected
-		}
-	}
-
-	return "" // No status change
+	return reviewOutcome(rs.config.ConflictResolution, request.MinApprovals, decision, approvals, rejections)
 }
 
 // VerifyReview validates a review's signature.
@@ -336,7 +589,7 @@ func (rs *ReviewService) CanReview(sessionID, requestID string) (bool, string) {
 	if err != nil {
 		return false, fmt.Sprintf("request not found: %v", err)
 	}
-	if !CanApprove(request.Status) {
+	if !statemachine.CanApprove(request.Status) {
 		return false, fmt.Sprintf("request cannot be reviewed (status: %s)", request.Status)
 	}
 
@@ -513,14 +766,15 @@ func (rs *ReviewService) EscalateDifferentModelTimeout(requestID string) error {
 	}
 
 	// State machine requires: pending → timeout → escalated
+	in := statemachine.Input{Authority: statemachine.AuthoritySystem, Reason: status.EscalationReason}
 	if request.Status == db.StatusPending {
-		if err := rs.db.UpdateRequestStatus(requestID, db.StatusTimeout); err != nil {
+		if _, err := rs.machine.Commit(requestID, db.StatusTimeout, in); err != nil {
 			return fmt.Errorf("transitioning to timeout: %w", err)
 		}
 	}
 
 	// Now transition to escalated
-	if err := rs.db.UpdateRequestStatus(requestID, db.StatusEscalated); err != nil {
+	if _, err := rs.machine.Commit(requestID, db.StatusEscalated, in); err != nil {
 		return fmt.Errorf("transitioning to escalated: %w", err)
 	}
 
@@ -1,10 +1,14 @@
 package core
 
 import (
+	"database/sql"
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/Dicklesworthstone/slb/internal/config"
 	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
 )
 
 // setupReviewTest creates a DB with a session and request for testing.
@@ -351,6 +355,121 @@ func TestSubmitReview_DifferentModelRequired_DifferentModelAccepted(t *testing.T
 	}
 }
 
+func TestSubmitReview_RequestDecidedAfterPrepare(t *testing.T) {
+	dbConn, _, req := setupReviewTest(t)
+	defer dbConn.Close()
+
+	var reviewers []*db.Session
+	for _, name := range []string{"GreenLake", "RedRiver"} {
+		sess := &db.Session{AgentName: name, Program: "claude-code", Model: "opus-4.5", ProjectPath: "/test/project"}
+		if err := dbConn.CreateSession(sess); err != nil {
+			t.Fatalf("CreateSession() error = %v", err)
+		}
+		reviewers = append(reviewers, sess)
+	}
+	rs := NewReviewService(dbConn, DefaultReviewConfig())
+	opts := func(sess *db.Session) ReviewOptions {
+		return ReviewOptions{SessionID: sess.ID, SessionKey: sess.SessionKey, RequestID: req.ID, Decision: db.DecisionApprove}
+	}
+
+	// The second reviewer reads the request while it is still pending...
+	late, err := rs.prepareReview(opts(reviewers[1]))
+	if err != nil {
+		t.Fatalf("prepareReview() error = %v", err)
+	}
+	// ...then the first approves it and execution starts.
+	if _, err := rs.SubmitReview(opts(reviewers[0])); err != nil {
+		t.Fatalf("SubmitReview() error = %v", err)
+	}
+	if err := dbConn.UpdateRequestStatus(req.ID, db.StatusExecuting); err != nil {
+		t.Fatalf("UpdateRequestStatus() error = %v", err)
+	}
+
+	err = dbConn.Transaction(func(tx *sql.Tx) error {
+		_, err := rs.recordReviewTx(tx, late)
+		return err
+	})
+	if !errors.Is(err, ErrRequestNotPending) {
+		t.Fatalf("late review error = %v, want ErrRequestNotPending", err)
+	}
+	got, err := dbConn.GetRequest(req.ID)
+	if err != nil {
+		t.Fatalf("GetRequest() error = %v", err)
+	}
+	if got.Status != db.StatusExecuting {
+		t.Errorf("status = %s, want executing left alone", got.Status)
+	}
+}
+
+func TestSubmitReview_FreshAuth(t *testing.T) {
+	dbConn, _, req := setupReviewTest(t)
+	defer dbConn.Close()
+
+	newReviewer := func(name string) *db.Session {
+		sess := &db.Session{AgentName: name, Program: "claude-code", Model: "opus-4.5", ProjectPath: "/test/project"}
+		if err := dbConn.CreateSession(sess); err != nil {
+			t.Fatalf("CreateSession() error = %v", err)
+		}
+		return sess
+	}
+
+	cfg := DefaultReviewConfig()
+	cfg.FreshAuthTiers = []db.RiskTier{db.RiskTierDangerous}
+	cfg.FreshAuthMaxAge = time.Minute
+	rs := NewReviewService(dbConn, cfg)
+
+	// Approving without re-authenticating, or with a stale one, fails.
+	reviewer := newReviewer("GreenLake")
+	for _, auth := range []*FreshAuth{nil, {Method: "passphrase", At: time.Now().Add(-2 * time.Minute)}} {
+		_, err := rs.SubmitReview(ReviewOptions{
+			SessionID: reviewer.ID, SessionKey: reviewer.SessionKey, RequestID: req.ID,
+			Decision: db.DecisionApprove, FreshAuth: auth,
+		})
+		if !errors.Is(err, ErrFreshAuthRequired) {
+			t.Errorf("auth %+v: expected ErrFreshAuthRequired, got %v", auth, err)
+		}
+	}
+
+	// Rejections never need it.
+	rejecter := newReviewer("RedFox")
+	if _, err := rs.SubmitReview(ReviewOptions{
+		SessionID: rejecter.ID, SessionKey: rejecter.SessionKey, RequestID: req.ID, Decision: db.DecisionReject,
+	}); err != nil {
+		t.Fatalf("reject without fresh auth: %v", err)
+	}
+
+	// A fresh one is accepted and recorded.
+	req2 := &db.Request{
+		ProjectPath: "/test/project", RequestorSessionID: req.RequestorSessionID, RequestorAgent: req.RequestorAgent,
+		RequestorModel: req.RequestorModel, RiskTier: db.RiskTierDangerous, MinApprovals: 1,
+		Command: db.CommandSpec{Raw: "rm -rf ./dist", Cwd: "/test/project"},
+	}
+	if err := dbConn.CreateRequest(req2); err != nil {
+		t.Fatalf("CreateRequest() error = %v", err)
+	}
+	authAt := time.Now().Add(-5 * time.Second)
+	result, err := rs.SubmitReview(ReviewOptions{
+		SessionID: reviewer.ID, SessionKey: reviewer.SessionKey, RequestID: req2.ID,
+		Decision: db.DecisionApprove, FreshAuth: &FreshAuth{Method: "fido2", At: authAt},
+	})
+	if err != nil {
+		t.Fatalf("approve with fresh auth: %v", err)
+	}
+	stored, err := dbConn.GetReview(result.Review.ID)
+	if err != nil {
+		t.Fatalf("GetReview() error = %v", err)
+	}
+	if stored.AuthMethod != "fido2" || stored.AuthenticatedAt == nil || stored.AuthenticatedAt.Unix() != authAt.Unix() {
+		t.Errorf("fresh auth not recorded: %q %v", stored.AuthMethod, stored.AuthenticatedAt)
+	}
+
+	// Tiers outside the list are unaffected.
+	cfg.FreshAuthTiers = []db.RiskTier{db.RiskTierCritical}
+	if cfg.RequiresFreshAuth(db.RiskTierDangerous) {
+		t.Error("expected dangerous approvals not to need fresh auth")
+	}
+}
+
 func TestSubmitReview_SessionKeyMismatch_Rejected(t *testing.T) {
 	dbConn, _, req := setupReviewTest(t)
 	defer dbConn.Close()
@@ -1518,3 +1637,136 @@ func TestGetReviewStatus_Errors(t *testing.T) {
 		}
 	})
 }
+
+func TestSubmitReviews_AllOrNothing(t *testing.T) {
+	dbConn, requestor, first := setupReviewTest(t)
+	defer dbConn.Close()
+
+	reviewer := &db.Session{
+		AgentName:   "GreenLake",
+		Program:     "claude-code",
+		Model:       "opus-4.5",
+		ProjectPath: "/test/project",
+	}
+	if err := dbConn.CreateSession(reviewer); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	second := &db.Request{
+		ProjectPath:        "/test/project",
+		RequestorSessionID: requestor.ID,
+		RequestorAgent:     requestor.AgentName,
+		RequestorModel:     requestor.Model,
+		RiskTier:           db.RiskTierDangerous,
+		MinApprovals:       2,
+		Command:            db.CommandSpec{Raw: "git push --force", Cwd: "/test/project"},
+		Justification:      db.Justification{Reason: "Rewrite history"},
+	}
+	if err := dbConn.CreateRequest(second); err != nil {
+		t.Fatalf("CreateRequest() error = %v", err)
+	}
+
+	rs := NewReviewService(dbConn, DefaultReviewConfig())
+	opts := func(requestID string) ReviewOptions {
+		return ReviewOptions{
+			SessionID:  reviewer.ID,
+			SessionKey: reviewer.SessionKey,
+			RequestID:  requestID,
+			Decision:   db.DecisionApprove,
+		}
+	}
+
+	// One invalid item (unknown request) aborts the whole batch.
+	_, err := rs.SubmitReviews([]ReviewOptions{opts(first.ID), opts("missing")})
+	var batchErr *BatchReviewError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("expected BatchReviewError, got %v", err)
+	}
+	if _, ok := batchErr.Errs[1]; !ok || len(batchErr.Errs) != 1 {
+		t.Errorf("expected only item 1 to fail, got %v", batchErr.Errs)
+	}
+	if reviews, _ := dbConn.ListReviewsForRequest(first.ID); len(reviews) != 0 {
+		t.Fatalf("failed batch must not record reviews, got %d", len(reviews))
+	}
+
+	// Duplicate request IDs are rejected up front.
+	if _, err := rs.SubmitReviews([]ReviewOptions{opts(first.ID), opts(first.ID)}); !errors.As(err, &batchErr) {
+		t.Fatalf("expected duplicate error, got %v", err)
+	}
+
+	results, err := rs.SubmitReviews([]ReviewOptions{opts(first.ID), opts(second.ID)})
+	if err != nil {
+		t.Fatalf("SubmitReviews() error = %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if !results[0].RequestStatusChanged || results[0].NewRequestStatus != db.StatusApproved {
+		t.Errorf("first request should be approved, got %+v", results[0])
+	}
+	if results[1].RequestStatusChanged || results[1].Approvals != 1 {
+		t.Errorf("second request needs another approval, got %+v", results[1])
+	}
+}
+
+func TestSubmitReview_ExpectedVersionConflict(t *testing.T) {
+	dbConn := testutil.NewTestDB(t)
+	requestor := testutil.MakeSession(t, dbConn)
+	alice := testutil.MakeSession(t, dbConn)
+	bob := testutil.MakeSession(t, dbConn)
+	req := testutil.MakeRequest(t, dbConn, requestor, testutil.WithMinApprovals(2))
+	testutil.RequireEqual(t, 1, req.Version, "initial version")
+
+	rs := NewReviewService(dbConn, DefaultReviewConfig())
+	first, err := rs.SubmitReview(ReviewOptions{
+		SessionID: alice.ID, SessionKey: alice.SessionKey, RequestID: req.ID,
+		Decision: db.DecisionApprove, ExpectedVersion: req.Version,
+	})
+	testutil.RequireNoError(t, err, "alice approves")
+	testutil.RequireEqual(t, 2, first.Version, "version after a review")
+
+	// Bob decided on the same snapshot as Alice.
+	_, err = rs.SubmitReview(ReviewOptions{
+		SessionID: bob.ID, SessionKey: bob.SessionKey, RequestID: req.ID,
+		Decision: db.DecisionApprove, ExpectedVersion: req.Version,
+	})
+	var conflict *db.VersionConflictError
+	if !errors.As(err, &conflict) || !errors.Is(err, db.ErrVersionConflict) {
+		t.Fatalf("expected a VersionConflictError, got %v", err)
+	}
+	testutil.RequireEqual(t, 2, conflict.Current, "current version")
+	if reviewed, _ := dbConn.HasReviewerAlreadyReviewed(req.ID, bob.ID); reviewed {
+		t.Fatal("a conflicting review was recorded")
+	}
+
+	// After refreshing, Bob's approval reaches quorum.
+	second, err := rs.SubmitReview(ReviewOptions{
+		SessionID: bob.ID, SessionKey: bob.SessionKey, RequestID: req.ID,
+		Decision: db.DecisionApprove, ExpectedVersion: conflict.Current,
+	})
+	testutil.RequireNoError(t, err, "bob approves after refresh")
+	testutil.RequireEqual(t, db.StatusApproved, second.NewRequestStatus, "status")
+
+	got, err := dbConn.GetRequest(req.ID)
+	testutil.RequireNoError(t, err, "get request")
+	testutil.RequireEqual(t, second.Version, got.Version, "stored version")
+	if got.Version != 4 {
+		t.Fatalf("version = %d, want 4 (two reviews and one status change)", got.Version)
+	}
+}
+
+func TestReviewConfigFromConfig(t *testing.T) {
+	cfg := config.DefaultConfig()
+	if rc := ReviewConfigFromConfig(cfg); rc.RequiresFreshAuth(db.RiskTierCritical) {
+		t.Error("sudo mode off should not require fresh auth")
+	}
+	cfg.SudoMode.Enabled = true
+	cfg.SudoMode.Tiers = []string{"critical", " Dangerous "}
+	cfg.SudoMode.MaxAgeSecs = 30
+	rc := ReviewConfigFromConfig(cfg)
+	if !rc.RequiresFreshAuth(db.RiskTierCritical) || !rc.RequiresFreshAuth(db.RiskTierDangerous) || rc.RequiresFreshAuth(db.RiskTierCaution) {
+		t.Errorf("FreshAuthTiers = %v", rc.FreshAuthTiers)
+	}
+	if rc.FreshAuthMaxAge != 30*time.Second {
+		t.Errorf("FreshAuthMaxAge = %s", rc.FreshAuthMaxAge)
+	}
+}
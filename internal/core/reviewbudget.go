@@ -0,0 +1,108 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// bytesPerToken is the rough size of an LLM token in English text and
+// code. Estimates only need to be stable, not exact.
+const bytesPerToken = 4
+
+// EstimateTokens returns roughly how many LLM tokens text costs.
+func EstimateTokens(text string) int {
+	return (len(text) + bytesPerToken - 1) / bytesPerToken
+}
+
+// ReviewSection is one piece of review context that may be cut to fit a
+// token budget: an attachment, the transcript snippet, dry-run output.
+type ReviewSection struct {
+	// Name identifies the section in truncation notes, e.g. "transcript"
+	// or "attachment 2 (git_diff)".
+	Name string
+	// Text is the section content; FitReviewBudget shortens it in place.
+	Text *string
+}
+
+// ReviewEffort estimates what reading a request costs an LLM reviewer.
+type ReviewEffort struct {
+	// InstructionsTokens is the configured reviewer instructions.
+	InstructionsTokens int `json:"instructions_tokens"`
+	// CommandTokens is the command and justification, which are never cut.
+	CommandTokens int `json:"command_tokens"`
+	// ContextTokens is the attachments, transcript and dry-run output as
+	// shown, after any truncation.
+	ContextTokens int `json:"context_tokens"`
+	// TotalTokens is the sum of the above.
+	TotalTokens int `json:"total_tokens"`
+	// Budget is the token budget applied; 0 means none.
+	Budget int `json:"budget,omitempty"`
+	// Truncated notes each section that was cut to fit the budget.
+	Truncated []string `json:"truncated,omitempty"`
+}
+
+// FitReviewBudget estimates the review effort for a request and, when
+// budget is positive, shortens the context sections so the total fits it.
+// Instructions and the command are always kept whole; whatever budget they
+// leave is shared among the sections so that small sections stay intact and
+// large ones are cut to equal shares. Each cut section ends with a marker
+// and is listed in Truncated.
+func FitReviewBudget(budget int, instructions, command string, sections []ReviewSection) ReviewEffort {
+	effort := ReviewEffort{
+		InstructionsTokens: EstimateTokens(instructions),
+		CommandTokens:      EstimateTokens(command),
+	}
+	if budget > 0 {
+		effort.Budget = budget
+		remaining := budget - effort.InstructionsTokens - effort.CommandTokens
+		if remaining < 0 {
+			remaining = 0
+		}
+
+		// Hand out the remaining budget smallest section first, so a
+		// section that fits its share leaves the rest to larger ones.
+		order := make([]int, len(sections))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool {
+			return len(*sections[order[a]].Text) < len(*sections[order[b]].Text)
+		})
+		for n, i := range order {
+			s := sections[i]
+			share := remaining / (len(order) - n)
+			size := EstimateTokens(*s.Text)
+			if size <= share {
+				remaining -= size
+				continue
+			}
+			*s.Text = truncateToTokens(*s.Text, share)
+			remaining -= EstimateTokens(*s.Text)
+			effort.Truncated = append(effort.Truncated,
+				fmt.Sprintf("%s cut from ~%d to ~%d tokens", s.Name, size, EstimateTokens(*s.Text)))
+		}
+	}
+	for _, s := range sections {
+		effort.ContextTokens += EstimateTokens(*s.Text)
+	}
+	effort.TotalTokens = effort.InstructionsTokens + effort.CommandTokens + effort.ContextTokens
+	return effort
+}
+
+// truncationMarker ends text cut to fit a review budget.
+const truncationMarker = "\n[truncated to fit the review token budget]"
+
+// truncateToTokens cuts text to about tokens tokens, marker included, on a
+// line boundary when one is near and never inside a UTF-8 sequence.
+func truncateToTokens(text string, tokens int) string {
+	limit := tokens*bytesPerToken - len(truncationMarker)
+	if limit <= 0 {
+		return strings.TrimPrefix(truncationMarker, "\n")
+	}
+	cut := text[:limit]
+	if i := strings.LastIndexByte(cut, '\n'); i >= limit/2 {
+		cut = cut[:i]
+	}
+	return strings.ToValidUTF8(cut, "") + truncationMarker
+}
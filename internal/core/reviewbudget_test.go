@@ -0,0 +1,57 @@
+package core
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	for text, want := range map[string]int{"": 0, "a": 1, "abcd": 1, "abcde": 2} {
+		if got := EstimateTokens(text); got != want {
+			t.Errorf("EstimateTokens(%q) = %d, want %d", text, got, want)
+		}
+	}
+}
+
+func TestFitReviewBudget(t *testing.T) {
+	small := "short note"
+	diff := strings.Repeat("+ added line\n", 400)
+	transcript := strings.Repeat("é", 3000)
+	sections := func() []ReviewSection {
+		s, d, tr := small, diff, transcript
+		return []ReviewSection{{Name: "note", Text: &s}, {Name: "diff", Text: &d}, {Name: "transcript", Text: &tr}}
+	}
+
+	// Without a budget nothing is cut and the effort is just counted.
+	secs := sections()
+	effort := FitReviewBudget(0, "be strict", "rm -rf ./build", secs)
+	if *secs[1].Text != diff || len(effort.Truncated) != 0 {
+		t.Fatalf("unbudgeted review was cut: %+v", effort)
+	}
+	want := EstimateTokens("be strict") + EstimateTokens("rm -rf ./build") +
+		EstimateTokens(small) + EstimateTokens(diff) + EstimateTokens(transcript)
+	if effort.TotalTokens != want {
+		t.Errorf("TotalTokens = %d, want %d", effort.TotalTokens, want)
+	}
+
+	secs = sections()
+	effort = FitReviewBudget(1000, "be strict", "rm -rf ./build", secs)
+	if effort.TotalTokens > 1000 {
+		t.Errorf("TotalTokens = %d, over the budget", effort.TotalTokens)
+	}
+	if *secs[0].Text != small {
+		t.Errorf("a section within its share was cut: %q", *secs[0].Text)
+	}
+	for _, s := range secs[1:] {
+		if !strings.HasSuffix(*s.Text, truncationMarker) || !utf8.ValidString(*s.Text) {
+			t.Errorf("%s not cleanly truncated: %q", s.Name, (*s.Text)[max(0, len(*s.Text)-60):])
+		}
+	}
+	if len(effort.Truncated) != 2 || !strings.HasPrefix(effort.Truncated[0], "diff cut from ~1300") {
+		t.Errorf("Truncated = %v", effort.Truncated)
+	}
+	if !strings.HasSuffix(strings.TrimSuffix(*secs[1].Text, truncationMarker), "+ added line") {
+		t.Error("diff should be cut on a line boundary")
+	}
+}
@@ -0,0 +1,213 @@
+package core
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// ReviewBundleVersion is the format version of review bundles.
+const ReviewBundleVersion = 1
+
+// Review bundle errors.
+var (
+	ErrBundleSignature     = errors.New("review bundle signature does not verify")
+	ErrBundleUnsigned      = errors.New("review bundle has no decision")
+	ErrBundleAlreadySigned = errors.New("review bundle already has a decision")
+	ErrBundleStale         = errors.New("request changed since the review bundle was exported")
+)
+
+// ReviewBundle carries a pending request to a reviewer who cannot reach the
+// daemon or the database, and their decision back. It is exported for one
+// reviewer session and signed with that session's key, so the reviewer can
+// check offline that the request is the one slb recorded; the reviewer
+// signs their decision with the same key and the decision is applied on
+// import once both signatures verify against the key slb holds.
+type ReviewBundle struct {
+	Version     int    `json:"version"`
+	RequestID   string `json:"request_id"`
+	ProjectPath string `json:"project_path"`
+	// Command is the command as shown to reviewers (redacted when it
+	// contains sensitive data); CommandHash binds the bundle to the exact
+	// command requested.
+	Command        string           `json:"command"`
+	CommandHash    string           `json:"command_hash"`
+	Cwd            string           `json:"cwd"`
+	RiskTier       db.RiskTier      `json:"risk_tier"`
+	RequestorAgent string           `json:"requestor_agent"`
+	RequestorModel string           `json:"requestor_model,omitempty"`
+	Justification  db.Justification `json:"justification"`
+	CreatedAt      time.Time        `json:"created_at"`
+	ExpiresAt      *time.Time       `json:"expires_at,omitempty"`
+	ExportedAt     time.Time        `json:"exported_at"`
+
+	// ReviewerSessionID is the session the bundle was exported for; only
+	// its key can sign the decision.
+	ReviewerSessionID string `json:"reviewer_session_id"`
+	ReviewerAgent     string `json:"reviewer_agent"`
+
+	// Signature is the export signature over the fields above.
+	Signature string `json:"signature"`
+	// Decision is the reviewer's signed decision, once made.
+	Decision *BundleDecision `json:"decision,omitempty"`
+}
+
+// BundleDecision is a reviewer's decision recorded in a review bundle.
+type BundleDecision struct {
+	Decision db.Decision `json:"decision"`
+	Comments string      `json:"comments,omitempty"`
+	SignedAt time.Time   `json:"signed_at"`
+	// Signature covers the bundle, the decision, the time and the comments.
+	Signature string `json:"signature"`
+}
+
+// NewReviewBundle exports pending request req for reviewer and signs it
+// with the reviewer's session key.
+func NewReviewBundle(req *db.Request, reviewer *db.Session, now time.Time) (*ReviewBundle, error) {
+	if req.Status != db.StatusPending {
+		return nil, fmt.Errorf("request %s is %s, not pending", req.ID, req.Status)
+	}
+	if !reviewer.IsActive() {
+		return nil, fmt.Errorf("reviewer session %s has ended", reviewer.ID)
+	}
+	if reviewer.ID == req.RequestorSessionID {
+		return nil, fmt.Errorf("session %s submitted request %s and cannot review it", reviewer.ID, req.ID)
+	}
+
+	command := req.Command.Raw
+	if req.Command.DisplayRedacted != "" {
+		command = req.Command.DisplayRedacted
+	}
+	b := &ReviewBundle{
+		Version:           ReviewBundleVersion,
+		RequestID:         req.ID,
+		ProjectPath:       req.ProjectPath,
+		Command:           command,
+		CommandHash:       req.Command.Hash,
+		Cwd:               req.Command.Cwd,
+		RiskTier:          req.RiskTier,
+		RequestorAgent:    req.RequestorAgent,
+		RequestorModel:    req.RequestorModel,
+		Justification:     req.Justification,
+		CreatedAt:         req.CreatedAt.UTC(),
+		ExportedAt:        now.UTC().Truncate(time.Second),
+		ReviewerSessionID: reviewer.ID,
+		ReviewerAgent:     reviewer.AgentName,
+	}
+	if req.ExpiresAt != nil {
+		expires := req.ExpiresAt.UTC()
+		b.ExpiresAt = &expires
+	}
+	sig, err := sessionMAC(reviewer.SessionKey, b.digest())
+	if err != nil {
+		return nil, err
+	}
+	b.Signature = sig
+	return b, nil
+}
+
+// ParseReviewBundle decodes a review bundle.
+func ParseReviewBundle(data []byte) (*ReviewBundle, error) {
+	var b ReviewBundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("parsing review bundle: %w", err)
+	}
+	if b.Version != ReviewBundleVersion {
+		return nil, fmt.Errorf("unsupported review bundle version %d", b.Version)
+	}
+	if b.RequestID == "" || b.ReviewerSessionID == "" || b.Signature == "" {
+		return nil, fmt.Errorf("review bundle is incomplete")
+	}
+	return &b, nil
+}
+
+// VerifyExport checks the export signature with the reviewer's session key.
+func (b *ReviewBundle) VerifyExport(sessionKey string) error {
+	want, err := sessionMAC(sessionKey, b.digest())
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal([]byte(want), []byte(b.Signature)) {
+		return ErrBundleSignature
+	}
+	return nil
+}
+
+// Sign records the reviewer's decision after checking the export signature.
+func (b *ReviewBundle) Sign(sessionKey string, decision db.Decision, comments string, now time.Time) error {
+	if decision != db.DecisionApprove && decision != db.DecisionReject {
+		return fmt.Errorf("invalid decision %q", decision)
+	}
+	if b.Decision != nil {
+		return ErrBundleAlreadySigned
+	}
+	if err := b.VerifyExport(sessionKey); err != nil {
+		return err
+	}
+	d := &BundleDecision{Decision: decision, Comments: comments, SignedAt: now.UTC().Truncate(time.Second)}
+	sig, err := sessionMAC(sessionKey, b.decisionPayload(d))
+	if err != nil {
+		return err
+	}
+	d.Signature = sig
+	b.Decision = d
+	return nil
+}
+
+// Verify checks a signed bundle against the request it was exported from
+// and the reviewer's session key: both signatures must verify and the
+// request's command must be unchanged.
+func (b *ReviewBundle) Verify(req *db.Request, sessionKey string) error {
+	if err := b.VerifyExport(sessionKey); err != nil {
+		return err
+	}
+	if b.Decision == nil {
+		return ErrBundleUnsigned
+	}
+	want, err := sessionMAC(sessionKey, b.decisionPayload(b.Decision))
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal([]byte(want), []byte(b.Decision.Signature)) {
+		return ErrBundleSignature
+	}
+	if b.RequestID != req.ID || b.CommandHash != req.Command.Hash {
+		return ErrBundleStale
+	}
+	return nil
+}
+
+// digest hashes the exported fields: everything but the signatures and the
+// decision.
+func (b *ReviewBundle) digest() []byte {
+	exported := *b
+	exported.Signature = ""
+	exported.Decision = nil
+	data, _ := json.Marshal(exported)
+	sum := sha256.Sum256(data)
+	return sum[:]
+}
+
+// decisionPayload is what a decision signature covers.
+func (b *ReviewBundle) decisionPayload(d *BundleDecision) []byte {
+	payload := hex.EncodeToString(b.digest()) + "\n" + string(d.Decision) + "\n" +
+		d.SignedAt.UTC().Format(time.RFC3339) + "\n" + d.Comments
+	return []byte(payload)
+}
+
+// sessionMAC signs data with a hex session key.
+func sessionMAC(sessionKey string, data []byte) (string, error) {
+	key, err := hex.DecodeString(sessionKey)
+	if err != nil || len(key) == 0 {
+		return "", fmt.Errorf("invalid session key")
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
@@ -0,0 +1,65 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestReviewBundle_SignAndVerify(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	requestor := testutil.MakeSession(t, database, testutil.WithAgent("Requestor"))
+	reviewer := testutil.MakeSession(t, database, testutil.WithAgent("Reviewer"))
+	req := testutil.MakeRequest(t, database, requestor, testutil.WithCommand("rm -rf ./build", "/tmp", true))
+	now := time.Now()
+
+	if _, err := NewReviewBundle(req, requestor, now); err == nil {
+		t.Error("expected the requestor to be refused as reviewer")
+	}
+	b, err := NewReviewBundle(req, reviewer, now)
+	testutil.RequireNoError(t, err, "export")
+
+	// Round-trip through JSON as the file does.
+	data, err := json.Marshal(b)
+	testutil.RequireNoError(t, err, "marshal")
+	b, err = ParseReviewBundle(data)
+	testutil.RequireNoError(t, err, "parse")
+
+	other := testutil.MakeSession(t, database, testutil.WithAgent("Other"))
+	if err := b.Sign(other.SessionKey, db.DecisionApprove, "", now); !errors.Is(err, ErrBundleSignature) {
+		t.Errorf("signing with another session's key: err = %v, want ErrBundleSignature", err)
+	}
+	testutil.RequireNoError(t, b.Sign(reviewer.SessionKey, db.DecisionApprove, "ok", now), "sign")
+	if err := b.Sign(reviewer.SessionKey, db.DecisionReject, "", now); !errors.Is(err, ErrBundleAlreadySigned) {
+		t.Errorf("second decision: err = %v, want ErrBundleAlreadySigned", err)
+	}
+
+	data, err = json.Marshal(b)
+	testutil.RequireNoError(t, err, "marshal signed")
+	b, err = ParseReviewBundle(data)
+	testutil.RequireNoError(t, err, "parse signed")
+	testutil.RequireNoError(t, b.Verify(req, reviewer.SessionKey), "verify")
+
+	tampered := *b
+	tampered.Command = "true"
+	if err := tampered.Verify(req, reviewer.SessionKey); !errors.Is(err, ErrBundleSignature) {
+		t.Errorf("edited command: err = %v, want ErrBundleSignature", err)
+	}
+	decision := *b.Decision
+	decision.Comments = "approved by someone else"
+	tampered = *b
+	tampered.Decision = &decision
+	if err := tampered.Verify(req, reviewer.SessionKey); !errors.Is(err, ErrBundleSignature) {
+		t.Errorf("edited comments: err = %v, want ErrBundleSignature", err)
+	}
+
+	changed := *req
+	changed.Command.Hash = "different"
+	if err := b.Verify(&changed, reviewer.SessionKey); !errors.Is(err, ErrBundleStale) {
+		t.Errorf("changed request: err = %v, want ErrBundleStale", err)
+	}
+}
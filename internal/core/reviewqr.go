@@ -0,0 +1,52 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// Request summaries are cut to keep their QR codes small enough to scan
+// from a terminal.
+const (
+	summaryCommandBytes = 300
+	summaryReasonBytes  = 200
+	summaryHashChars    = 16
+)
+
+// RequestSummary is the compact, human-readable text of a request that
+// `slb review show --qr` puts in a QR code: ID, tier, command, reason,
+// requestor and the start of the command hash. It is for reading on a
+// phone and carries no signature; to act on the request, compare the hash
+// with `slb review show` or point reviewers.qr_url at a review page.
+func RequestSummary(req *db.Request) string {
+	command := req.Command.Raw
+	if req.Command.ContainsSensitive && req.Command.DisplayRedacted != "" {
+		command = req.Command.DisplayRedacted
+	}
+	hash := req.Command.Hash
+	if len(hash) > summaryHashChars {
+		hash = hash[:summaryHashChars]
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "SLB %s\n", req.ID)
+	fmt.Fprintf(&b, "Tier: %s\n", req.RiskTier)
+	fmt.Fprintf(&b, "Cmd: %s\n", summaryField(command, summaryCommandBytes))
+	if req.Justification.Reason != "" {
+		fmt.Fprintf(&b, "Why: %s\n", summaryField(req.Justification.Reason, summaryReasonBytes))
+	}
+	fmt.Fprintf(&b, "By: %s\n", req.RequestorAgent)
+	fmt.Fprintf(&b, "Hash: %s", hash)
+	return b.String()
+}
+
+// summaryField puts text on one line and cuts it to at most n bytes.
+func summaryField(text string, n int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if len(text) <= n {
+		return text
+	}
+	return strings.ToValidUTF8(text[:n-len("...")], "") + "..."
+}
@@ -0,0 +1,33 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestRequestSummary(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	sess := testutil.MakeSession(t, database)
+	long := "echo " + strings.Repeat("é", 400)
+	req := testutil.MakeRequest(t, database, sess,
+		testutil.WithCommand(long, "/tmp", true),
+		testutil.WithRisk(db.RiskTierCritical),
+		testutil.WithJustification("clean up\nthe   build", "", "", ""),
+	)
+
+	summary := RequestSummary(req)
+	for _, want := range []string{"SLB " + req.ID + "\n", "Tier: critical\n", "Why: clean up the build\n", "Hash: " + req.Command.Hash[:16]} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("summary missing %q:\n%s", want, summary)
+		}
+	}
+	for _, line := range strings.Split(summary, "\n") {
+		if strings.HasPrefix(line, "Cmd: ") && (len(line) > len("Cmd: ")+summaryCommandBytes || !strings.HasSuffix(line, "...")) {
+			t.Errorf("command not cut: %d bytes", len(line))
+		}
+	}
+
+}
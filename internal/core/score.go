@@ -0,0 +1,153 @@
+package core
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// RiskScoreMax is the highest risk score.
+const RiskScoreMax = 100
+
+// tierBaseScores place each tier in its own band so the score mostly
+// agrees with the tier; the other factors move a request within and
+// slightly across bands.
+var tierBaseScores = map[RiskTier]int{
+	RiskTierCaution:   25,
+	RiskTierDangerous: 55,
+	RiskTierCritical:  80,
+}
+
+// RiskFactor is one contribution to a risk score.
+type RiskFactor struct {
+	// Name identifies the factor: tier, segments, path_escape,
+	// sensitive_path, outside_project, parse_error, sensitive_content,
+	// anomaly or history.
+	Name string `json:"name"`
+	// Points is what the factor added (negative when it lowered the score).
+	Points int `json:"points"`
+	// Detail explains the factor for reviewers.
+	Detail string `json:"detail"`
+}
+
+// RiskScore grades a request from 0 to RiskScoreMax. Tiers still decide how
+// many approvals a request needs; the score orders the review queue and
+// feeds policy thresholds.
+type RiskScore struct {
+	Score   int          `json:"score"`
+	Factors []RiskFactor `json:"factors,omitempty"`
+}
+
+// RiskScoreInput is what a risk score is computed from.
+type RiskScoreInput struct {
+	// Classification is the final classification, after overrides and
+	// anomaly adjustments.
+	Classification *MatchResult
+	// Command and Cwd locate the paths the command touches.
+	Command string
+	Cwd     string
+	// ContainsSensitive is set when redaction changed the command.
+	ContainsSensitive bool
+	// Anomaly is the command's anomaly, if any.
+	Anomaly *Anomaly
+	// History is how earlier runs of the same command went.
+	History db.CommandHistory
+}
+
+// ComputeRiskScore scores a classified command. It is pure apart from
+// resolving path arguments against the filesystem.
+func ComputeRiskScore(in RiskScoreInput) RiskScore {
+	var s RiskScore
+	add := func(name string, points int, detail string) {
+		if points == 0 {
+			return
+		}
+		s.Factors = append(s.Factors, RiskFactor{Name: name, Points: points, Detail: detail})
+		s.Score += points
+	}
+
+	res := in.Classification
+	if res != nil {
+		add("tier", tierBaseScores[res.Tier], "matched "+tierLabel(res.Tier)+" tier")
+		if extra := len(res.MatchedSegments) - 1; extra > 0 {
+			add("segments", min(extra*5, 10), fmt.Sprintf("%d segments of the command matched patterns", extra+1))
+		}
+		if n := len(res.PathEscapes); n > 0 {
+			add("path_escape", min(n*10, 20), fmt.Sprintf("%d path(s) resolve through links or mounts to %s", n, res.PathEscapes[0].Reason))
+		}
+		if res.ParseError {
+			add("parse_error", 5, "command could not be fully parsed")
+		}
+	}
+	addPathFactors(in.Command, in.Cwd, add)
+	if in.ContainsSensitive {
+		add("sensitive_content", 5, "command contains redacted secrets")
+	}
+	if a := in.Anomaly; a != nil {
+		points := 0
+		if a.Burst {
+			points += 10
+		}
+		if a.Novel {
+			points += 10
+		}
+		add("anomaly", points, "command breaks from the project's history ("+a.Family+")")
+	}
+	addHistoryFactors(in.History, add)
+
+	s.Score = max(0, min(s.Score, RiskScoreMax))
+	return s
+}
+
+// addPathFactors scores the path arguments a command touches: system
+// locations weigh more than ordinary paths outside the project.
+func addPathFactors(command, cwd string, add func(string, int, string)) {
+	home, _ := os.UserHomeDir()
+	var sensitive, outside []string
+	for _, p := range ResolvePaths(command, cwd) {
+		switch {
+		case isSystemLocation(p.RealPath, home):
+			sensitive = append(sensitive, p.Path)
+		case !p.InProject:
+			outside = append(outside, p.Path)
+		}
+	}
+	if len(sensitive) > 0 {
+		add("sensitive_path", 15, fmt.Sprintf("touches a system or credential location (%s)", sensitive[0]))
+	}
+	if len(outside) > 0 {
+		add("outside_project", 5, fmt.Sprintf("touches paths outside the working directory (%s)", outside[0]))
+	}
+}
+
+// addHistoryFactors raises the score for commands that were rejected or
+// went wrong before and lowers it for commands that ran cleanly.
+func addHistoryFactors(h db.CommandHistory, add func(string, int, string)) {
+	if h.CausedProblems > 0 {
+		add("history", min(h.CausedProblems*10, 20), fmt.Sprintf("caused problems %d time(s) before", h.CausedProblems))
+	}
+	if h.Rejected > 0 {
+		add("history", min(h.Rejected*5, 15), fmt.Sprintf("rejected %d time(s) before", h.Rejected))
+	}
+	if h.Failed > 0 {
+		add("history", min(h.Failed*3, 9), fmt.Sprintf("failed %d time(s) before", h.Failed))
+	}
+	if h.Succeeded > 0 {
+		add("history", -min(h.Succeeded*3, 15), fmt.Sprintf("ran cleanly %d time(s) before", h.Succeeded))
+	}
+}
+
+// riskScore computes the score of a request about to be created. History
+// lookups are best effort: a failed query scores as no history.
+func (rc *RequestCreator) riskScore(classification *MatchResult, cmd db.CommandSpec, projectPath string, anomaly *Anomaly) RiskScore {
+	history, _ := rc.db.CommandHistoryFor(projectPath, cmd.Hash)
+	return ComputeRiskScore(RiskScoreInput{
+		Classification:    classification,
+		Command:           cmd.Raw,
+		Cwd:               cmd.Cwd,
+		ContainsSensitive: cmd.ContainsSensitive,
+		Anomaly:           anomaly,
+		History:           history,
+	})
+}
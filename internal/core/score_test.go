@@ -0,0 +1,143 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestComputeRiskScore(t *testing.T) {
+	cwd := t.TempDir()
+	dangerous := &MatchResult{Tier: RiskTierDangerous, NeedsApproval: true}
+
+	cases := []struct {
+		name    string
+		in      RiskScoreInput
+		want    int
+		factors []string
+	}{
+		{"no classification", RiskScoreInput{}, 0, nil},
+		{"caution", RiskScoreInput{Classification: &MatchResult{Tier: RiskTierCaution}}, 25, []string{"tier"}},
+		{"dangerous", RiskScoreInput{Classification: dangerous, Command: "rm -rf ./build", Cwd: cwd}, 55, []string{"tier"}},
+		{"critical", RiskScoreInput{Classification: &MatchResult{Tier: RiskTierCritical}}, 80, []string{"tier"}},
+		{
+			"compound with parse error",
+			RiskScoreInput{Classification: &MatchResult{
+				Tier:       RiskTierDangerous,
+				ParseError: true,
+				MatchedSegments: []SegmentMatch{
+					{Segment: "rm -rf ./a", Tier: RiskTierDangerous},
+					{Segment: "git reset --hard", Tier: RiskTierDangerous},
+				},
+			}},
+			65, []string{"tier", "segments", "parse_error"},
+		},
+		{
+			"path escape",
+			RiskScoreInput{Classification: &MatchResult{
+				Tier:        RiskTierCritical,
+				PathEscapes: []PathEscape{{Path: "./etc", RealPath: "/etc", Reason: PathEscapeSystem}},
+			}},
+			90, []string{"tier", "path_escape"},
+		},
+		{"system path", RiskScoreInput{Classification: dangerous, Command: "rm -rf /etc/nginx", Cwd: cwd}, 70, []string{"tier", "sensitive_path"}},
+		{"outside project", RiskScoreInput{Classification: dangerous, Command: "rm -rf ../sibling", Cwd: cwd}, 60, []string{"tier", "outside_project"}},
+		{
+			"context",
+			RiskScoreInput{
+				Classification:    &MatchResult{Tier: RiskTierCaution},
+				ContainsSensitive: true,
+				Anomaly:           &Anomaly{Family: "aws iam", Burst: true, Novel: true},
+			},
+			50, []string{"tier", "sensitive_content", "anomaly"},
+		},
+		{
+			"bad history",
+			RiskScoreInput{Classification: dangerous, History: db.CommandHistory{Rejected: 1, Failed: 1, CausedProblems: 1}},
+			73, []string{"tier", "history", "history", "history"},
+		},
+		{
+			"clean history",
+			RiskScoreInput{Classification: dangerous, History: db.CommandHistory{Succeeded: 20}},
+			40, []string{"tier", "history"},
+		},
+		{
+			"clamped",
+			RiskScoreInput{
+				Classification: &MatchResult{
+					Tier:        RiskTierCritical,
+					PathEscapes: []PathEscape{{Reason: PathEscapeSystem}, {Reason: PathEscapeNetwork}},
+				},
+				History: db.CommandHistory{CausedProblems: 3, Rejected: 3},
+			},
+			100, []string{"tier", "path_escape", "history", "history"},
+		},
+	}
+	for _, tc := range cases {
+		got := ComputeRiskScore(tc.in)
+		if got.Score != tc.want {
+			t.Errorf("%s: score = %d, want %d (factors %+v)", tc.name, got.Score, tc.want, got.Factors)
+		}
+		var names []string
+		for _, f := range got.Factors {
+			names = append(names, f.Name)
+			if f.Detail == "" {
+				t.Errorf("%s: factor %s has no detail", tc.name, f.Name)
+			}
+		}
+		if len(names) != len(tc.factors) {
+			t.Errorf("%s: factors = %v, want %v", tc.name, names, tc.factors)
+			continue
+		}
+		for i := range names {
+			if names[i] != tc.factors[i] {
+				t.Errorf("%s: factors = %v, want %v", tc.name, names, tc.factors)
+				break
+			}
+		}
+	}
+}
+
+func TestCreateRequest_StoresRiskScore(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	project := t.TempDir()
+	session := testutil.MakeSession(t, database, testutil.WithProject(project))
+	creator := NewRequestCreator(database, nil, nil, nil)
+
+	create := func() *CreateRequestResult {
+		t.Helper()
+		result, err := creator.CreateRequest(context.Background(), CreateRequestOptions{
+			SessionID:     session.ID,
+			Command:       "git reset --hard HEAD~3",
+			Cwd:           project,
+			ProjectPath:   project,
+			Justification: Justification{Reason: "Need to reset commits"},
+		})
+		if err != nil {
+			t.Fatalf("CreateRequest: %v", err)
+		}
+		return result
+	}
+
+	first := create()
+	if first.RiskScore == nil || first.RiskScore.Score != 55 {
+		t.Fatalf("unexpected risk score: %+v", first.RiskScore)
+	}
+	stored, err := database.GetRequest(first.Request.ID)
+	if err != nil {
+		t.Fatalf("GetRequest: %v", err)
+	}
+	if stored.RiskScore != first.RiskScore.Score {
+		t.Errorf("stored score = %d, want %d", stored.RiskScore, first.RiskScore.Score)
+	}
+
+	// A rejected run of the same command raises the next request's score.
+	if err := database.UpdateRequestStatus(first.Request.ID, db.StatusRejected); err != nil {
+		t.Fatalf("UpdateRequestStatus: %v", err)
+	}
+	if second := create(); second.RiskScore.Score != 60 || second.Request.RiskScore != 60 {
+		t.Errorf("expected the rejection to add 5 points, got %+v", second.RiskScore)
+	}
+}
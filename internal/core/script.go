@@ -0,0 +1,350 @@
+package core
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// Script errors.
+var (
+	ErrScriptHashMismatch = errors.New("script does not match its recorded hash")
+	ErrScriptInterpreter  = errors.New("unsupported script interpreter")
+)
+
+// ScriptOptions describe a request submitted as a script.
+type ScriptOptions struct {
+	// Interpreter runs the script; empty uses its #! line, else sh.
+	Interpreter string
+	// Source is where the script was read from (a path, or - for stdin).
+	Source string
+}
+
+// ScriptStatement is one statement of a multi-line script.
+type ScriptStatement struct {
+	// Line is the 1-based line the statement starts on.
+	Line int
+	// Text is the statement with line continuations joined and comments
+	// removed.
+	Text string
+	// Heredoc is set for a line of a here-document body. Bodies are kept
+	// as statements because they are often fed to a shell or database
+	// client (bash <<EOF, psql <<EOF).
+	Heredoc bool
+}
+
+// ScriptLine is a script statement with its classification, for review.
+type ScriptLine struct {
+	Line    int    `json:"line"`
+	Text    string `json:"text"`
+	Heredoc bool   `json:"heredoc,omitempty"`
+	// Tier is the statement's tier: critical, dangerous, caution, safe, or
+	// empty when no pattern matched.
+	Tier           RiskTier `json:"tier,omitempty"`
+	MatchedPattern string   `json:"matched_pattern,omitempty"`
+}
+
+// heredocDelim is a pending here-document: the body starts on the line
+// after the statement that opened it.
+type heredocDelim struct {
+	word      string
+	stripTabs bool
+}
+
+// ScriptStatements splits a script into statements, one per line outside
+// quotes. Backslash-newline continuations are joined, blank lines and #
+// comments are dropped, and here-document bodies become statements marked
+// Heredoc. Compound separators within a line (;, &&, |) are left for
+// NormalizeCommand.
+func ScriptStatements(script string) []ScriptStatement {
+	var stmts []ScriptStatement
+	var cur strings.Builder
+	var heredocs []heredocDelim
+	runes := []rune(script)
+	line, start := 1, 0
+	prev := ' '
+	inSingle, inDouble := false, false
+
+	write := func(r rune) {
+		if start == 0 && !unicode.IsSpace(r) {
+			start = line
+		}
+		cur.WriteRune(r)
+		prev = r
+	}
+	flush := func() {
+		if text := strings.TrimSpace(cur.String()); text != "" {
+			stmts = append(stmts, ScriptStatement{Line: start, Text: text})
+		}
+		cur.Reset()
+		start, prev = 0, ' '
+	}
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case inSingle:
+			if r == '\'' {
+				inSingle = false
+			}
+			if r == '\n' {
+				line++
+			}
+			write(r)
+		case r == '\\' && i+1 < len(runes) && runes[i+1] == '\n':
+			// Line continuation; the next line's indentation is dropped.
+			if !unicode.IsSpace(prev) {
+				write(' ')
+			}
+			line++
+			i++
+			for i+1 < len(runes) && (runes[i+1] == ' ' || runes[i+1] == '\t') {
+				i++
+			}
+		case r == '\\' && i+1 < len(runes):
+			write(r)
+			write(runes[i+1])
+			i++
+		case inDouble:
+			if r == '"' {
+				inDouble = false
+			}
+			if r == '\n' {
+				line++
+			}
+			write(r)
+		case r == '\'':
+			inSingle = true
+			write(r)
+		case r == '"':
+			inDouble = true
+			write(r)
+		case r == '#' && (unicode.IsSpace(prev) || strings.ContainsRune(";&|(", prev)):
+			for i+1 < len(runes) && runes[i+1] != '\n' {
+				i++
+			}
+		case r == '<' && i+1 < len(runes) && runes[i+1] == '<':
+			end, delim := parseHeredoc(runes, i)
+			if delim.word != "" {
+				heredocs = append(heredocs, delim)
+			}
+			for _, c := range runes[i:end] {
+				write(c)
+			}
+			i = end - 1
+		case r == '\n':
+			flush()
+			line++
+			if len(heredocs) > 0 {
+				var body []ScriptStatement
+				i, line, body = readHeredocBodies(runes, i+1, line, heredocs)
+				stmts = append(stmts, body...)
+				heredocs = nil
+				i--
+			}
+		default:
+			write(r)
+		}
+	}
+	flush()
+	return stmts
+}
+
+// parseHeredoc reads the redirection starting at runes[i] ("<<"), returning
+// the index just past it and the delimiter it names. A here-string (<<<)
+// has no delimiter.
+func parseHeredoc(runes []rune, i int) (int, heredocDelim) {
+	j := i + 2
+	if j < len(runes) && runes[j] == '<' {
+		return j + 1, heredocDelim{}
+	}
+	var delim heredocDelim
+	if j < len(runes) && runes[j] == '-' {
+		delim.stripTabs = true
+		j++
+	}
+	for j < len(runes) && (runes[j] == ' ' || runes[j] == '\t') {
+		j++
+	}
+	var word strings.Builder
+	quoted := false
+	for j < len(runes) {
+		c := runes[j]
+		if c == '\'' || c == '"' {
+			quoted = true
+			j++
+			for j < len(runes) && runes[j] != c && runes[j] != '\n' {
+				word.WriteRune(runes[j])
+				j++
+			}
+			if j < len(runes) && runes[j] == c {
+				j++
+			}
+			continue
+		}
+		if c == '\\' {
+			j++
+			continue
+		}
+		if unicode.IsSpace(c) || strings.ContainsRune(";&|<>()", c) {
+			break
+		}
+		word.WriteRune(c)
+		j++
+	}
+	// An unquoted word must look like a name; anything else is a shift
+	// such as $((1<<2)).
+	if w := word.String(); quoted || (w != "" && (unicode.IsLetter(rune(w[0])) || w[0] == '_')) {
+		delim.word = w
+	}
+	return j, delim
+}
+
+// readHeredocBodies reads the bodies of the pending here-documents starting
+// at runes[pos], returning the index after the last delimiter line, the
+// line number there and the body lines as statements.
+func readHeredocBodies(runes []rune, pos, line int, heredocs []heredocDelim) (int, int, []ScriptStatement) {
+	var body []ScriptStatement
+	for _, h := range heredocs {
+		for pos < len(runes) {
+			end := pos
+			for end < len(runes) && runes[end] != '\n' {
+				end++
+			}
+			text := string(runes[pos:end])
+			pos = end + 1
+			line++
+			cmp := text
+			if h.stripTabs {
+				cmp = strings.TrimLeft(cmp, "\t")
+			}
+			if cmp == h.word {
+				break
+			}
+			if trimmed := strings.TrimSpace(text); trimmed != "" {
+				body = append(body, ScriptStatement{Line: line - 1, Text: trimmed, Heredoc: true})
+			}
+		}
+	}
+	if pos > len(runes) {
+		pos = len(runes)
+	}
+	return pos, line, body
+}
+
+// IsMultilineCommand reports whether a command holds more than one
+// statement across lines, and so is classified (and shown) a line at a
+// time.
+func IsMultilineCommand(cmd string) bool {
+	return strings.ContainsRune(cmd, '\n') && len(ScriptStatements(cmd)) > 1
+}
+
+// AnnotateScript classifies each statement of a script on its own, so a
+// reviewer can see which lines make it risky.
+func (e *PatternEngine) AnnotateScript(script, cwd string) []ScriptLine {
+	stmts := ScriptStatements(script)
+	lines := make([]ScriptLine, 0, len(stmts))
+	for _, st := range stmts {
+		res := e.ClassifyCommand(st.Text, cwd)
+		lines = append(lines, ScriptLine{
+			Line:           st.Line,
+			Text:           st.Text,
+			Heredoc:        st.Heredoc,
+			Tier:           res.Tier,
+			MatchedPattern: res.MatchedPattern,
+		})
+	}
+	return lines
+}
+
+// ScriptSHA256 returns the hex SHA-256 digest of a script's content.
+func ScriptSHA256(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// ScriptInterpreter picks the interpreter for a script: the one given, else
+// the script's #! line, else sh. Only shells are accepted, since scripts
+// are classified as shell.
+func ScriptInterpreter(interpreter, script string) (string, error) {
+	interpreter = strings.TrimSpace(interpreter)
+	if interpreter == "" {
+		if first, _, _ := strings.Cut(script, "\n"); strings.HasPrefix(first, "#!") {
+			interpreter = strings.TrimSpace(strings.TrimPrefix(first, "#!"))
+		}
+	}
+	if interpreter == "" {
+		return "sh", nil
+	}
+
+	fields := strings.Fields(interpreter)
+	name := filepath.Base(fields[0])
+	if name == "env" && len(fields) > 1 {
+		name = filepath.Base(fields[1])
+	}
+	for _, shell := range shellExecutors {
+		if name == shell {
+			return interpreter, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s (use one of %s)", ErrScriptInterpreter, interpreter, strings.Join(shellExecutors, ", "))
+}
+
+// RunScript runs a script request from a temp file with its recorded
+// interpreter. The request's content, and the file written from it, must
+// both match the SHA-256 recorded when the request was created.
+func RunScript(ctx context.Context, spec *db.CommandSpec, script *db.RequestScript, logPath string, stream io.Writer) (*CommandResult, error) {
+	return runScript(ctx, spec, script, nil, nil, logPath, streamTo(stream))
+}
+
+// runScript is RunScript under an optional isolation. The script file is
+// made readable by the isolated user, and mounted read-only into a
+// container. When staged is set, the file has the script's downloads
+// replaced by the verified copies, which are mounted too.
+func runScript(ctx context.Context, spec *db.CommandSpec, script *db.RequestScript, staged *stagedRemoteScripts, iso *Isolation, logPath string, streams OutputStreams) (*CommandResult, error) {
+	if got := ScriptSHA256(spec.Raw); got != script.SHA256 {
+		return nil, fmt.Errorf("%w: recorded %s, request has %s", ErrScriptHashMismatch, script.SHA256, got)
+	}
+
+	f, err := os.CreateTemp("", "slb-script-*.sh")
+	if err != nil {
+		return nil, fmt.Errorf("creating script file: %w", err)
+	}
+	path := f.Name()
+	defer os.Remove(path)
+	content := staged.spec(spec).Raw
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("writing script file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("writing script file: %w", err)
+	}
+	if iso != nil {
+		if err := os.Chmod(path, 0o644); err != nil {
+			return nil, fmt.Errorf("writing script file: %w", err)
+		}
+	}
+
+	written, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading script file: %w", err)
+	}
+	if string(written) != content {
+		return nil, fmt.Errorf("%w: %s was modified after it was written", ErrScriptHashMismatch, path)
+	}
+
+	run := *spec
+	run.Shell = false
+	run.Argv = append(strings.Fields(script.Interpreter), path)
+	return runCommand(ctx, &run, iso, append([]string{path}, staged.paths()...), logPath, streams)
+}
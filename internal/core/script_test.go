@@ -0,0 +1,185 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestScriptStatements(t *testing.T) {
+	script := `#!/bin/bash
+set -e
+
+# drain first
+kubectl drain node-7 \
+    --ignore-daemonsets
+git commit -m "two
+lines" && git push  # then push
+psql "$DB" <<-'SQL'
+	DROP TABLE users;
+	SQL
+echo $((1<<2)); cat <<< "here"
+`
+	want := []ScriptStatement{
+		{Line: 2, Text: "set -e"},
+		{Line: 5, Text: "kubectl drain node-7 --ignore-daemonsets"},
+		{Line: 7, Text: "git commit -m \"two\nlines\" && git push"},
+		{Line: 9, Text: `psql "$DB" <<-'SQL'`},
+		{Line: 10, Text: "DROP TABLE users;", Heredoc: true},
+		{Line: 12, Text: `echo $((1<<2)); cat <<< "here"`},
+	}
+	if got := ScriptStatements(script); !reflect.DeepEqual(got, want) {
+		t.Errorf("ScriptStatements =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if IsMultilineCommand("git commit -m \"two\nlines\"") {
+		t.Error("a newline inside quotes is one statement")
+	}
+	if !IsMultilineCommand("ls\nrm -rf /") {
+		t.Error("two lines are two statements")
+	}
+}
+
+func TestClassifyCommand_Script(t *testing.T) {
+	engine := NewPatternEngine()
+
+	// Before scripts were split a line at a time, only the first line
+	// was classified.
+	res := engine.ClassifyCommand("ls -la\nrm -rf /etc", "")
+	if res.Tier != RiskTierCritical {
+		t.Errorf("tier = %q, want critical", res.Tier)
+	}
+	if res := engine.ClassifyCommand("# tidy up\nrm -rf ./build", ""); res.Tier != RiskTierDangerous {
+		t.Errorf("commented script tier = %q, want dangerous", res.Tier)
+	}
+	if res := engine.ClassifyCommand("bash <<EOF\nrm -rf /etc\nEOF", ""); res.Tier != RiskTierCritical {
+		t.Errorf("heredoc body tier = %q, want critical", res.Tier)
+	}
+
+	lines := engine.AnnotateScript("echo start\nrm -rf ./build\nrm debug.log", "")
+	tiers := []RiskTier{lines[0].Tier, lines[1].Tier, lines[2].Tier}
+	if tiers[1] != RiskTierDangerous || tiers[2] != RiskTier(RiskSafe) || lines[1].MatchedPattern == "" {
+		t.Errorf("unexpected annotations: %+v", lines)
+	}
+}
+
+func TestScriptInterpreter(t *testing.T) {
+	tests := []struct {
+		interpreter, script, want string
+		err                       bool
+	}{
+		{"", "echo hi\n", "sh", false},
+		{"", "#!/usr/bin/env bash\necho hi\n", "/usr/bin/env bash", false},
+		{"zsh", "#!/bin/bash\necho hi\n", "zsh", false},
+		{"", "#!/usr/bin/python3\nprint('hi')\n", "", true},
+		{"ruby", "puts 1\n", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ScriptInterpreter(tt.interpreter, tt.script)
+		if tt.err {
+			if !errors.Is(err, ErrScriptInterpreter) {
+				t.Errorf("ScriptInterpreter(%q) error = %v, want ErrScriptInterpreter", tt.interpreter, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ScriptInterpreter(%q) = %q, %v, want %q", tt.interpreter, got, err, tt.want)
+		}
+	}
+}
+
+func TestCreateRequest_Script(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	session := testutil.MakeSession(t, database, testutil.SessionWithAgentName("agent1"))
+	creator := NewRequestCreator(database, nil, nil, nil)
+	content := "#!/bin/bash\necho building\nrm -rf ./build\n"
+
+	result, err := creator.CreateRequest(context.Background(), CreateRequestOptions{
+		SessionID:     session.ID,
+		Command:       content,
+		Cwd:           t.TempDir(),
+		Justification: Justification{Reason: "clean"},
+		Script:        &ScriptOptions{Source: "clean.sh"},
+	})
+	testutil.RequireNoError(t, err, "create request")
+	testutil.RequireEqual(t, RiskTierDangerous, result.Request.RiskTier, "tier")
+	if !result.Request.Command.Shell {
+		t.Error("scripts should be recorded as shell commands")
+	}
+
+	s, err := database.GetRequestScript(result.Request.ID)
+	testutil.RequireNoError(t, err, "get script")
+	if s.Interpreter != "/bin/bash" || s.Source != "clean.sh" || s.SHA256 != ScriptSHA256(content) {
+		t.Fatalf("unexpected script: %+v", s)
+	}
+	if _, err := database.GetRequestBinary(result.Request.ID); !errors.Is(err, db.ErrBinaryNotFound) {
+		t.Errorf("a script's first line is not its binary: %v", err)
+	}
+
+	_, err = creator.CreateRequest(context.Background(), CreateRequestOptions{
+		SessionID: session.ID,
+		Command:   "print('hi')\nimport shutil\n",
+		Script:    &ScriptOptions{Interpreter: "python3"},
+	})
+	if !errors.Is(err, ErrScriptInterpreter) {
+		t.Errorf("expected ErrScriptInterpreter, got %v", err)
+	}
+}
+
+func TestExecuteApprovedRequest_Script(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("runs a shell script")
+	}
+	database := testutil.NewTestDB(t)
+	session := testutil.MakeSession(t, database)
+	dir := t.TempDir()
+
+	newRequest := func(content, sha string) *db.Request {
+		spec := db.CommandSpec{Raw: content, Cwd: dir, Shell: true}
+		spec.Hash = db.ComputeCommandHash(spec)
+		expires := time.Now().Add(time.Hour)
+		req := &db.Request{
+			ProjectPath:        dir,
+			RequestorSessionID: session.ID,
+			RequestorAgent:     session.AgentName,
+			RequestorModel:     session.Model,
+			RiskTier:           db.RiskTierCaution,
+			Command:            spec,
+			Status:             db.StatusApproved,
+			ApprovalExpiresAt:  &expires,
+		}
+		testutil.RequireNoError(t, database.CreateRequest(req), "create request")
+		testutil.RequireNoError(t, database.SetRequestScript(&db.RequestScript{
+			RequestID: req.ID, Interpreter: "sh", SHA256: sha,
+		}), "set script")
+		return req
+	}
+	execute := func(req *db.Request) (*ExecutionResult, error) {
+		return NewExecutor(database, nil).ExecuteApprovedRequest(context.Background(), ExecuteOptions{
+			RequestID:      req.ID,
+			SessionID:      session.ID,
+			LogDir:         filepath.Join(dir, "logs"),
+			SuppressOutput: true,
+		})
+	}
+
+	content := "echo one > out.txt\n# comment\necho \"two\nlines\" >> out.txt\n"
+	result, err := execute(newRequest(content, ScriptSHA256(content)))
+	testutil.RequireNoError(t, err, "execute script")
+	testutil.RequireEqual(t, 0, result.ExitCode, "exit code")
+	data, err := os.ReadFile(filepath.Join(dir, "out.txt"))
+	testutil.RequireNoError(t, err, "read output")
+	testutil.RequireEqual(t, "one\ntwo\nlines\n", string(data), "script output")
+
+	if _, err := execute(newRequest("echo changed\n", ScriptSHA256(content))); !errors.Is(err, ErrScriptHashMismatch) {
+		t.Errorf("expected ErrScriptHashMismatch, got %v", err)
+	}
+}
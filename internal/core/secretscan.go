@@ -0,0 +1,84 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// ScanSecrets looks for sensitive data in command output with the
+// redaction patterns. It returns the text with each match replaced by
+// [REDACTED] and the kinds of secret found, sorted, without duplicates.
+// Matches of customPatterns are of kind "custom".
+func ScanSecrets(text string, customPatterns []string) (string, []string) {
+	found := make(map[string]bool)
+	for _, rule := range redactionRules(customPatterns) {
+		if !rule.Re.MatchString(text) {
+			continue
+		}
+		found[rule.Kind] = true
+		text = rule.Re.ReplaceAllString(text, "[REDACTED]")
+	}
+
+	kinds := make([]string, 0, len(found))
+	for kind := range found {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return text, kinds
+}
+
+// ScanCommandOutput checks a finished command's output for secrets with
+// the default patterns and customPatterns. When any are found it redacts
+// them from result and the log at logPath, and returns their kinds. The
+// output already streamed to the caller's terminal cannot be recalled.
+func ScanCommandOutput(result *CommandResult, logPath string, customPatterns []string) []string {
+	if result == nil {
+		return nil
+	}
+	redacted, kinds := ScanSecrets(result.Output, customPatterns)
+	if len(kinds) == 0 {
+		return nil
+	}
+	result.Output = redacted
+	if logPath != "" {
+		if err := redactLogFile(logPath, customPatterns); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to redact execution log: %v\n", err)
+		}
+	}
+	return kinds
+}
+
+// scanOutput checks a finished request's output for secrets, with the
+// request's own redaction patterns as well as the defaults. When any are
+// found it redacts them from the result and the stored log, and records
+// their kinds on the execution, which the notifier passes on to reviewers.
+func (e *Executor) scanOutput(result *ExecutionResult, exec *db.Execution) {
+	var patterns []string
+	if result.Request != nil {
+		patterns, _ = e.db.GetRequestRedactPatterns(result.Request.ID)
+	}
+	cmd := &CommandResult{Output: result.Output}
+	kinds := ScanCommandOutput(cmd, result.LogPath, patterns)
+	if len(kinds) == 0 {
+		return
+	}
+	result.Output = cmd.Output
+	result.SecretsFound = kinds
+	exec.SecretsFound = kinds
+}
+
+// redactLogFile rewrites an execution log with its secrets redacted.
+func redactLogFile(path string, customPatterns []string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	redacted, kinds := ScanSecrets(string(data), customPatterns)
+	if len(kinds) == 0 {
+		return nil
+	}
+	return os.WriteFile(path, []byte(redacted), 0600)
+}
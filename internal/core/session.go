@@ -1,6 +1,7 @@
 package core
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -11,6 +12,75 @@ import (
 // ErrSessionProgramMismatch indicates an active session exists, but belongs to a different program.
 var ErrSessionProgramMismatch = errors.New("active session belongs to a different program")
 
+// ErrSessionLimit indicates that starting a session would exceed a
+// configured cap on active sessions.
+var ErrSessionLimit = errors.New("session limit reached")
+
+// SessionLimits caps concurrently active sessions, so a runaway agent
+// spawner cannot flood the reviewer pool. Zero caps are unlimited.
+type SessionLimits struct {
+	// MaxPerProject caps active sessions in one project.
+	MaxPerProject int
+	// MaxPerAgent caps active sessions for one agent name across projects.
+	MaxPerAgent int
+	// QueueTimeout, when positive, makes a new session wait up to this long
+	// for another to end instead of failing at once.
+	QueueTimeout time.Duration
+	// OnQueued, if set, is called once when a new session starts waiting.
+	OnQueued func(reason error)
+}
+
+// sessionSlotPoll is how often a queued session re-checks the limits.
+var sessionSlotPoll = time.Second
+
+// CheckSessionLimits returns an ErrSessionLimit error when one more active
+// session for agentName in projectPath would exceed limits.
+func CheckSessionLimits(dbConn *db.DB, limits SessionLimits, agentName, projectPath string) error {
+	if limits.MaxPerProject <= 0 && limits.MaxPerAgent <= 0 {
+		return nil
+	}
+	inProject, forAgent, err := dbConn.CountActiveSessions(projectPath, agentName)
+	if err != nil {
+		return err
+	}
+	if limits.MaxPerProject > 0 && inProject >= limits.MaxPerProject {
+		return fmt.Errorf("%w: %d of %d sessions already active in project %q (end one with 'slb session end' or 'slb session gc')",
+			ErrSessionLimit, inProject, limits.MaxPerProject, projectPath)
+	}
+	if limits.MaxPerAgent > 0 && forAgent >= limits.MaxPerAgent {
+		return fmt.Errorf("%w: %d of %d sessions already active for agent %q across projects",
+			ErrSessionLimit, forAgent, limits.MaxPerAgent, agentName)
+	}
+	return nil
+}
+
+// WaitForSessionSlot is CheckSessionLimits that, when limits.QueueTimeout is
+// set, waits for a slot to free up before giving up.
+func WaitForSessionSlot(ctx context.Context, dbConn *db.DB, limits SessionLimits, agentName, projectPath string) error {
+	err := CheckSessionLimits(dbConn, limits, agentName, projectPath)
+	if !errors.Is(err, ErrSessionLimit) || limits.QueueTimeout <= 0 {
+		return err
+	}
+	if limits.OnQueued != nil {
+		limits.OnQueued(err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, limits.QueueTimeout)
+	defer cancel()
+	ticker := time.NewTicker(sessionSlotPoll)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w; gave up after waiting %s", err, limits.QueueTimeout)
+		case <-ticker.C:
+		}
+		if err = CheckSessionLimits(dbConn, limits, agentName, projectPath); !errors.Is(err, ErrSessionLimit) {
+			return err
+		}
+	}
+}
+
 // SessionSummary is a safe-to-serialize view of a session (excludes session_key).
 type SessionSummary struct {
 	ID           string
@@ -47,6 +117,8 @@ type ResumeOptions struct {
 	ProjectPath      string
 	CreateIfMissing  bool
 	ForceEndMismatch bool
+	// Limits applies when a new session has to be created.
+	Limits SessionLimits
 }
 
 // ResumeSession resumes an existing active session (agent_name + project_path) or creates a new one.
@@ -55,7 +127,7 @@ type ResumeOptions struct {
 // - If an active session exists and Program is specified, it must match (unless ForceEndMismatch is true).
 // - On successful resume, updates the session heartbeat (last_active_at) and returns the session (with session_key).
 // - If no active session exists:
-//   - CreateIfMissing=true → creates a new session and returns it
+//   - CreateIfMissing=true → creates a new session within Limits and returns it
 //   - CreateIfMissing=false → returns db.ErrSessionNotFound
 func ResumeSession(dbConn *db.DB, opts ResumeOptions) (*db.Session, error) {
 	if opts.AgentName == "" {
@@ -71,6 +143,9 @@ func ResumeSession(dbConn *db.DB, opts ResumeOptions) (*db.Session, error) {
 			if !opts.CreateIfMissing {
 				return nil, db.ErrSessionNotFound
 			}
+			if err := WaitForSessionSlot(context.Background(), dbConn, opts.Limits, opts.AgentName, opts.ProjectPath); err != nil {
+				return nil, err
+			}
 
 			newSess := &db.Session{
 				AgentName:   opts.AgentName,
@@ -133,7 +208,7 @@ func GarbageCollectStaleSessions(dbConn *db.DB, opts SessionGCOptions) (*Session
 		return nil, fmt.Errorf("threshold must be > 0")
 	}
 
-	now := time.Now().UTC()
+	now := dbConn.Now()
 	res := &SessionGCResult{
 		ProjectPath: opts.ProjectPath,
 		Threshold:   opts.Threshold,
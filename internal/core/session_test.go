@@ -1,7 +1,9 @@
 package core
 
 import (
+	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -49,6 +51,90 @@ func TestResumeSession_CreatesNewSession(t *testing.T) {
 	}
 }
 
+func TestCheckSessionLimits(t *testing.T) {
+	dbConn, err := db.Open(":memory:")
+	if err != nil {
+		t.Fatalf("db.Open(:memory:) error = %v", err)
+	}
+	defer dbConn.Close()
+
+	for _, s := range []*db.Session{
+		{AgentName: "BlueSnow", ProjectPath: "/test/a"},
+		{AgentName: "BlueSnow", ProjectPath: "/test/b"},
+		{AgentName: "RedStone", ProjectPath: "/test/a"},
+	} {
+		if err := dbConn.CreateSession(s); err != nil {
+			t.Fatalf("CreateSession: %v", err)
+		}
+	}
+
+	if err := CheckSessionLimits(dbConn, SessionLimits{}, "GreenLake", "/test/a"); err != nil {
+		t.Errorf("no limits: %v", err)
+	}
+	if err := CheckSessionLimits(dbConn, SessionLimits{MaxPerProject: 3}, "GreenLake", "/test/a"); err != nil {
+		t.Errorf("under the project cap: %v", err)
+	}
+	err = CheckSessionLimits(dbConn, SessionLimits{MaxPerProject: 2}, "GreenLake", "/test/a")
+	if !errors.Is(err, ErrSessionLimit) || !strings.Contains(err.Error(), "/test/a") {
+		t.Errorf("at the project cap: %v", err)
+	}
+	err = CheckSessionLimits(dbConn, SessionLimits{MaxPerAgent: 2}, "BlueSnow", "/test/c")
+	if !errors.Is(err, ErrSessionLimit) || !strings.Contains(err.Error(), "BlueSnow") {
+		t.Errorf("at the agent cap: %v", err)
+	}
+
+	_, err = ResumeSession(dbConn, ResumeOptions{
+		AgentName:       "GreenLake",
+		ProjectPath:     "/test/a",
+		CreateIfMissing: true,
+		Limits:          SessionLimits{MaxPerProject: 2},
+	})
+	if !errors.Is(err, ErrSessionLimit) {
+		t.Errorf("resume creating past the cap: %v", err)
+	}
+	// Resuming an existing session adds none, so the cap does not apply.
+	if _, err := ResumeSession(dbConn, ResumeOptions{
+		AgentName:   "RedStone",
+		ProjectPath: "/test/a",
+		Limits:      SessionLimits{MaxPerProject: 1},
+	}); err != nil {
+		t.Errorf("resume of an existing session: %v", err)
+	}
+}
+
+func TestWaitForSessionSlot(t *testing.T) {
+	dbConn, err := db.Open(":memory:")
+	if err != nil {
+		t.Fatalf("db.Open(:memory:) error = %v", err)
+	}
+	defer dbConn.Close()
+
+	orig := sessionSlotPoll
+	sessionSlotPoll = 5 * time.Millisecond
+	t.Cleanup(func() { sessionSlotPoll = orig })
+
+	busy := &db.Session{AgentName: "BlueSnow", ProjectPath: "/test/a"}
+	if err := dbConn.CreateSession(busy); err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+
+	limits := SessionLimits{MaxPerProject: 1, QueueTimeout: 20 * time.Millisecond}
+	if err := WaitForSessionSlot(context.Background(), dbConn, limits, "GreenLake", "/test/a"); !errors.Is(err, ErrSessionLimit) {
+		t.Fatalf("expected ErrSessionLimit after the queue timeout, got %v", err)
+	}
+
+	queued := make(chan struct{})
+	limits.QueueTimeout = 5 * time.Second
+	limits.OnQueued = func(error) { close(queued) }
+	go func() {
+		<-queued
+		_ = dbConn.EndSession(busy.ID)
+	}()
+	if err := WaitForSessionSlot(context.Background(), dbConn, limits, "GreenLake", "/test/a"); err != nil {
+		t.Fatalf("expected a slot once the session ended, got %v", err)
+	}
+}
+
 func TestResumeSession_ProgramMismatch(t *testing.T) {
 	dbConn, err := db.Open(":memory:")
 	if err != nil {
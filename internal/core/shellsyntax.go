@@ -0,0 +1,122 @@
+// Package core normalizes fish and zsh syntax for pattern matching.
+package core
+
+import "strings"
+
+// Fish keywords that chain a command onto the previous one's status
+// (`make; and rm -rf build`). They are stripped like wrappers so the chained
+// command is classified.
+var fishKeywordPrefixes = []string{"and", "or", "not"}
+
+func isFishKeyword(tok string) bool {
+	for _, k := range fishKeywordPrefixes {
+		if tok == k {
+			return true
+		}
+	}
+	return false
+}
+
+// rewriteShellSyntax rewrites fish and zsh constructs that the POSIX
+// tokenizer cannot parse, and returns the commands run by command
+// substitutions so they can be classified on their own:
+//   - fish command substitution `(cmd)` becomes `$(cmd)`
+//   - zsh glob qualifiers (`*(/)`, `**/*.log(.om[1,5])`) are dropped
+//
+// A parenthesis at the start of the segment is a POSIX subshell and is left
+// alone, as are zsh alternation groups (`*.(c|h)`).
+func rewriteShellSyntax(seg string) (string, []string) {
+	if !strings.Contains(seg, "(") {
+		return seg, nil
+	}
+
+	var out strings.Builder
+	var subs []string
+	inSingle, inDouble := false, false
+
+	for i := 0; i < len(seg); i++ {
+		c := seg[i]
+		switch {
+		case c == '\\' && !inSingle && i+1 < len(seg):
+			out.WriteByte(c)
+			out.WriteByte(seg[i+1])
+			i++
+			continue
+		case c == '\'' && !inDouble:
+			inSingle = !inSingle
+		case c == '"' && !inSingle:
+			inDouble = !inDouble
+		case c == '(' && !inSingle:
+			prev := lastByte(out.String())
+			// Inside double quotes only $(...) substitutes.
+			if inDouble && prev != '$' {
+				break
+			}
+			end := matchingParen(seg, i)
+			if end < 0 {
+				break
+			}
+			inner := seg[i+1 : end]
+			switch {
+			case prev == '$':
+				subs = append(subs, inner)
+			case isGlobWord(out.String()) && !strings.ContainsAny(inner, " \t|"):
+				// zsh glob qualifier: drop it.
+				i = end
+				continue
+			case strings.TrimSpace(out.String()) == "":
+				// POSIX subshell at the start of the segment.
+			case prev == ' ' || prev == '\t':
+				subs = append(subs, inner)
+				out.WriteString("$(")
+				out.WriteString(inner)
+				out.WriteByte(')')
+				i = end
+				continue
+			}
+		}
+		out.WriteByte(c)
+	}
+	return out.String(), subs
+}
+
+func lastByte(s string) byte {
+	if s == "" {
+		return 0
+	}
+	return s[len(s)-1]
+}
+
+// isGlobWord reports whether the word being written (the text after the last
+// blank) is a glob, which is what a zsh qualifier attaches to.
+func isGlobWord(s string) bool {
+	word := s[strings.LastIndexAny(s, " \t")+1:]
+	return strings.ContainsAny(word, "*?[")
+}
+
+// matchingParen returns the index of the parenthesis closing the one at
+// open, honoring nesting and quotes, or -1 if it is unbalanced.
+func matchingParen(s string, open int) int {
+	depth := 0
+	inSingle, inDouble := false, false
+	for i := open; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\' && !inSingle:
+			i++
+		case c == '\'' && !inDouble:
+			inSingle = !inSingle
+		case c == '"' && !inSingle:
+			inDouble = !inDouble
+		case inSingle || inDouble:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRewriteShellSyntax(t *testing.T) {
+	tests := []struct {
+		seg  string
+		want string
+		subs []string
+	}{
+		// fish command substitution
+		{`echo (date)`, `echo $(date)`, []string{"date"}},
+		{`rm -rf (pwd)/build`, `rm -rf $(pwd)/build`, []string{"pwd"}},
+		{`set x (string split , (cat list))`, `set x $(string split , (cat list))`, []string{"string split , (cat list)"}},
+		// zsh glob qualifiers
+		{`rm -rf *(/)`, `rm -rf *`, nil},
+		{`rm **/*.log(.om[1,5])`, `rm **/*.log`, nil},
+		{`ls -d /etc/**/*(N)`, `ls -d /etc/**/*`, nil},
+		// Left alone
+		{`rm *.(o|a)`, `rm *.(o|a)`, nil},
+		{`(cd /tmp && rm -rf x)`, `(cd /tmp && rm -rf x)`, nil},
+		{`echo "(not a sub)"`, `echo "(not a sub)"`, nil},
+		{`echo '(date)'`, `echo '(date)'`, nil},
+		{`echo "$(date)"`, `echo "$(date)"`, []string{"date"}},
+		{`git status`, `git status`, nil},
+	}
+	for _, tt := range tests {
+		got, subs := rewriteShellSyntax(tt.seg)
+		if got != tt.want || !reflect.DeepEqual(subs, tt.subs) {
+			t.Errorf("rewriteShellSyntax(%q) = %q, %q; want %q, %q", tt.seg, got, subs, tt.want, tt.subs)
+		}
+	}
+}
+
+func TestClassifyFishAndZshSyntax(t *testing.T) {
+	engine := NewPatternEngine()
+
+	tests := []struct {
+		cmd  string
+		want RiskTier
+	}{
+		{`echo (date)`, ""},
+		{`ls *(.)`, ""},
+		{`rm (ls *.tmp)`, RiskTierCaution},
+		{`rm -rf (pwd)/build`, RiskTierDangerous},
+		{`rm -rf *(/)`, RiskTierDangerous},
+		{`rm -rf /etc/**/*(N)`, RiskTierCritical},
+		{`rm -rf **/node_modules`, RiskTierDangerous},
+		// fish chaining keywords
+		{`make; and rm -rf build`, RiskTierDangerous},
+		{`test -d build; or rm -rf /`, RiskTierCritical},
+		{`not git diff --quiet; and git reset --hard`, RiskTierDangerous},
+		// Substituted commands are classified too
+		{`echo (rm -rf /)`, RiskTierCritical},
+		{`set files (find . -name '*.o'; rm -rf /etc)`, RiskTierCritical},
+		{`echo $(git reset --hard)`, RiskTierDangerous},
+		{`set x (ls | grep foo)`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cmd, func(t *testing.T) {
+			result := engine.ClassifyCommand(tt.cmd, "/work")
+			if result.ParseError {
+				t.Errorf("ClassifyCommand(%q) reported a parse error", tt.cmd)
+			}
+			if result.Tier != tt.want {
+				t.Errorf("ClassifyCommand(%q) = %q (pattern %q), want %q", tt.cmd, result.Tier, result.MatchedPattern, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitPipelineShellAware(t *testing.T) {
+	tests := []struct {
+		seg  string
+		want []string
+	}{
+		{`ls | grep foo`, []string{"ls", "grep foo"}},
+		{`grep "a|b" file | wc -l`, []string{`grep "a|b" file`, "wc -l"}},
+		{`echo $(ls | grep x) | cat`, []string{"echo $(ls | grep x)", "cat"}},
+	}
+	for _, tt := range tests {
+		if got := splitPipeline(tt.seg, true); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitPipeline(%q) = %q, want %q", tt.seg, got, tt.want)
+		}
+	}
+}
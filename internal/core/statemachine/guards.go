@@ -0,0 +1,107 @@
+package statemachine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// Authority is who decided a transition. Guards that protect approvals only
+// bind decisions made by agent reviews.
+type Authority string
+
+const (
+	// AuthorityReviews is a decision reached by counting agent reviews. It is
+	// the default, so approvals are held to quorum unless a caller says
+	// otherwise.
+	AuthorityReviews Authority = "reviews"
+	// AuthorityHuman is a human deciding an escalated request.
+	AuthorityHuman Authority = "human"
+	// AuthorityPolicy is a configured rule, such as tier auto-approval or the
+	// auto_approve_warn timeout action.
+	AuthorityPolicy Authority = "policy"
+	// AuthoritySystem is bookkeeping by the sweeper, executor or requestor:
+	// expiry, escalation, cancellation and execution outcomes.
+	AuthoritySystem Authority = "system"
+)
+
+// Input describes why a transition is happening.
+type Input struct {
+	// Authority is who decided the transition; empty means AuthorityReviews.
+	Authority Authority
+	// Approvals is the number of approvals counted toward quorum, including
+	// delegated ones.
+	Approvals int
+	// Quorum is the number of approvals needed; the request's MinApprovals
+	// when zero.
+	Quorum int
+	// Reason and Actor are carried on the emitted Event.
+	Reason string
+	Actor  string
+}
+
+func (in Input) byReviews() bool {
+	return in.Authority == "" || in.Authority == AuthorityReviews
+}
+
+// guard vetoes a transition the table allows.
+type guard func(req *db.Request, to db.RequestStatus, in Input, now time.Time) error
+
+// guards run in order after the transition table.
+var guards = []guard{quorumGuard, requestExpiryGuard, approvalExpiryGuard}
+
+// quorumGuard keeps reviews from approving a request without enough
+// approvals. Reverting an execution back to approved is not a new approval.
+func quorumGuard(req *db.Request, to db.RequestStatus, in Input, _ time.Time) error {
+	if to != db.StatusApproved || !CanApprove(req.Status) || !in.byReviews() {
+		return nil
+	}
+	quorum := in.Quorum
+	if quorum == 0 {
+		quorum = req.MinApprovals
+	}
+	if in.Approvals >= quorum {
+		return nil
+	}
+	return &TransitionError{
+		From:    req.Status,
+		To:      to,
+		Message: fmt.Sprintf("%d of %d required approvals", in.Approvals, quorum),
+		Err:     ErrQuorumNotMet,
+	}
+}
+
+// requestExpiryGuard keeps late reviews from approving a pending request the
+// sweeper is about to time out.
+func requestExpiryGuard(req *db.Request, to db.RequestStatus, in Input, now time.Time) error {
+	if req.Status != db.StatusPending || to != db.StatusApproved || !in.byReviews() {
+		return nil
+	}
+	if req.ExpiresAt == nil || !now.After(*req.ExpiresAt) {
+		return nil
+	}
+	return &TransitionError{
+		From:    req.Status,
+		To:      to,
+		Message: fmt.Sprintf("request expired at %s", req.ExpiresAt.UTC().Format(time.RFC3339)),
+		Err:     ErrRequestExpired,
+	}
+}
+
+// approvalExpiryGuard keeps a stale approval from starting execution,
+// whoever asks.
+func approvalExpiryGuard(req *db.Request, to db.RequestStatus, _ Input, now time.Time) error {
+	if req.Status != db.StatusApproved || to != db.StatusExecuting {
+		return nil
+	}
+	if req.ApprovalExpiresAt == nil || !now.After(*req.ApprovalExpiresAt) {
+		return nil
+	}
+	return &TransitionError{
+		From:    req.Status,
+		To:      to,
+		Message: fmt.Sprintf("approval expired at %s", req.ApprovalExpiresAt.UTC().Format(time.RFC3339)),
+		Err:     ErrApprovalExpired,
+	}
+}
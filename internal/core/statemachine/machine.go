@@ -0,0 +1,187 @@
+package statemachine
+
+import (
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/clock"
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// ApprovalTTL is how long an approval stays valid for execution.
+type ApprovalTTL struct {
+	Default  time.Duration
+	Critical time.Duration
+}
+
+// DefaultApprovalTTL matches general.approval_ttl_minutes and
+// general.approval_ttl_critical_minutes in the default config.
+var DefaultApprovalTTL = ApprovalTTL{Default: 30 * time.Minute, Critical: 10 * time.Minute}
+
+// For returns the TTL of an approval for a request of the given tier.
+func (t ApprovalTTL) For(tier db.RiskTier) time.Duration {
+	if tier == db.RiskTierCritical {
+		return t.Critical
+	}
+	return t.Default
+}
+
+// Event is emitted after a transition is stored.
+type Event struct {
+	RequestID string
+	From      db.RequestStatus
+	To        db.RequestStatus
+	Authority Authority
+	Reason    string
+	Actor     string
+	At        time.Time
+}
+
+// Listener receives transition events. Listeners run synchronously on the
+// goroutine that committed the transition and must not block.
+type Listener func(Event)
+
+// Machine applies transitions to stored requests.
+type Machine struct {
+	db    *db.DB
+	clock clock.Clock
+	ttl   ApprovalTTL
+
+	mu        sync.RWMutex
+	listeners []Listener
+}
+
+// New creates a machine that stores transitions in database, on its clock.
+// A machine without a database only transitions requests in memory.
+func New(database *db.DB) *Machine {
+	m := &Machine{db: database, clock: clock.Real, ttl: DefaultApprovalTTL}
+	if database != nil {
+		m.clock = clock.Func(database.Now)
+	}
+	return m
+}
+
+// WithClock sets the clock used for timestamps and guards.
+func (m *Machine) WithClock(c clock.Clock) *Machine {
+	m.clock = clock.OrReal(c)
+	return m
+}
+
+// WithApprovalTTL sets how long approvals made by this machine stay valid.
+func (m *Machine) WithApprovalTTL(ttl ApprovalTTL) *Machine {
+	m.ttl = ttl
+	return m
+}
+
+// OnTransition registers a listener for stored transitions.
+func (m *Machine) OnTransition(l Listener) *Machine {
+	if l == nil {
+		return m
+	}
+	m.mu.Lock()
+	m.listeners = append(m.listeners, l)
+	m.mu.Unlock()
+	return m
+}
+
+func (m *Machine) now() time.Time {
+	return m.clock.Now().UTC()
+}
+
+// Transition checks and applies a transition to req in memory.
+func (m *Machine) Transition(req *db.Request, to db.RequestStatus, in Input) error {
+	return transition(req, to, in, m.now(), m.ttl)
+}
+
+// CheckExpiry checks if a pending request has expired by the machine's clock.
+func (m *Machine) CheckExpiry(req *db.Request) (db.RequestStatus, bool) {
+	return checkExpiryAt(req, m.clock.Now())
+}
+
+// CheckApprovalExpiry checks if an approval has become stale by the
+// machine's clock.
+func (m *Machine) CheckApprovalExpiry(req *db.Request) bool {
+	return checkApprovalExpiryAt(req, m.clock.Now())
+}
+
+// Commit loads a request, checks the transition, stores it and notifies
+// listeners. It fails with db.ErrInvalidTransition if the request changed
+// status concurrently.
+func (m *Machine) Commit(requestID string, to db.RequestStatus, in Input) (*Event, error) {
+	if m.db == nil {
+		return nil, errors.New("state machine has no database")
+	}
+	req, err := m.db.GetRequest(requestID)
+	if err != nil {
+		return nil, err
+	}
+	change, ev, err := m.prepare(req, to, in)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.db.ApplyStatusChange(change); err != nil {
+		return nil, err
+	}
+	m.Emit(ev)
+	return ev, nil
+}
+
+// CommitTx checks and stores a transition of req, read inside tx. Listeners
+// are not notified until the caller passes the event to Emit after the
+// transaction commits.
+func (m *Machine) CommitTx(tx *sql.Tx, req *db.Request, to db.RequestStatus, in Input) (*Event, error) {
+	if m.db == nil {
+		return nil, errors.New("state machine has no database")
+	}
+	change, ev, err := m.prepare(req, to, in)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.db.ApplyStatusChangeTx(tx, change); err != nil {
+		return nil, err
+	}
+	return ev, nil
+}
+
+// prepare applies the transition to a copy of req and returns what to store.
+func (m *Machine) prepare(req *db.Request, to db.RequestStatus, in Input) (db.StatusChange, *Event, error) {
+	now := m.now()
+	next := *req
+	if err := transition(&next, to, in, now, m.ttl); err != nil {
+		return db.StatusChange{}, nil, err
+	}
+
+	change := db.StatusChange{ID: req.ID, From: req.Status, To: to, At: now, Actor: in.Actor, Reason: in.Reason}
+	if next.ApprovalExpiresAt != req.ApprovalExpiresAt {
+		change.ApprovalExpiresAt = next.ApprovalExpiresAt
+	}
+	authority := in.Authority
+	if authority == "" {
+		authority = AuthorityReviews
+	}
+	ev := &Event{
+		RequestID: req.ID,
+		From:      req.Status,
+		To:        to,
+		Authority: authority,
+		Reason:    in.Reason,
+		Actor:     in.Actor,
+		At:        now,
+	}
+	return change, ev, nil
+}
+
+// Emit notifies listeners of a stored transition. A nil event is ignored.
+func (m *Machine) Emit(ev *Event) {
+	if ev == nil {
+		return
+	}
+	m.mu.RLock()
+	listeners := append([]Listener(nil), m.listeners...)
+	m.mu.RUnlock()
+	for _, l := range listeners {
+		l(*ev)
+	}
+}
@@ -0,0 +1,233 @@
+package statemachine
+
+import (
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+var clockStart = time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+
+func TestMachine_WithClock(t *testing.T) {
+	clk := testutil.NewFakeClock(clockStart)
+	m := New(nil).WithClock(clk)
+
+	req := &db.Request{RiskTier: db.RiskTierDangerous}
+	if err := m.Transition(req, db.StatusPending, Input{}); err != nil {
+		t.Fatalf("Transition to pending: %v", err)
+	}
+	if !req.CreatedAt.Equal(clockStart) {
+		t.Fatalf("created_at = %v, want %v", req.CreatedAt, clockStart)
+	}
+	exp := clockStart.Add(30 * time.Minute)
+	req.ExpiresAt = &exp
+
+	clk.Advance(29 * time.Minute)
+	if _, expired := m.CheckExpiry(req); expired {
+		t.Fatal("expected request to be live before its expiry")
+	}
+	clk.Advance(2 * time.Minute)
+	if status, expired := m.CheckExpiry(req); !expired || status != db.StatusTimeout {
+		t.Fatalf("expected timeout after expiry, got %q %v", status, expired)
+	}
+
+	if err := m.Transition(req, db.StatusApproved, Input{Authority: AuthorityPolicy}); err != nil {
+		t.Fatalf("Transition to approved: %v", err)
+	}
+	if want := clk.Now().Add(DefaultApprovalTTL.Default); !req.ApprovalExpiresAt.Equal(want) {
+		t.Fatalf("approval_expires_at = %v, want %v", req.ApprovalExpiresAt, want)
+	}
+	if m.CheckApprovalExpiry(req) {
+		t.Fatal("expected fresh approval")
+	}
+	clk.Advance(DefaultApprovalTTL.Default + time.Second)
+	if !m.CheckApprovalExpiry(req) {
+		t.Fatal("expected approval to go stale after its TTL")
+	}
+}
+
+func TestGuards(t *testing.T) {
+	past := clockStart.Add(-time.Minute)
+	future := clockStart.Add(time.Minute)
+
+	tests := []struct {
+		name    string
+		req     db.Request
+		to      db.RequestStatus
+		in      Input
+		wantErr error
+	}{
+		{"quorum met", db.Request{Status: db.StatusPending, MinApprovals: 2}, db.StatusApproved, Input{Approvals: 2}, nil},
+		{"quorum not met", db.Request{Status: db.StatusPending, MinApprovals: 2}, db.StatusApproved, Input{Approvals: 1}, ErrQuorumNotMet},
+		{"explicit quorum", db.Request{Status: db.StatusPending, MinApprovals: 2}, db.StatusApproved, Input{Approvals: 1, Quorum: 1}, nil},
+		{"escalated needs quorum from reviews", db.Request{Status: db.StatusEscalated, MinApprovals: 2}, db.StatusApproved, Input{Authority: AuthorityReviews, Approvals: 1}, ErrQuorumNotMet},
+		{"human skips quorum", db.Request{Status: db.StatusEscalated, MinApprovals: 2}, db.StatusApproved, Input{Authority: AuthorityHuman}, nil},
+		{"policy skips quorum", db.Request{Status: db.StatusPending, MinApprovals: 2}, db.StatusApproved, Input{Authority: AuthorityPolicy}, nil},
+		{"revert is not an approval", db.Request{Status: db.StatusExecuting, MinApprovals: 2}, db.StatusApproved, Input{Authority: AuthoritySystem}, nil},
+		{"reviews after expiry", db.Request{Status: db.StatusPending, MinApprovals: 1, ExpiresAt: &past}, db.StatusApproved, Input{Approvals: 1}, ErrRequestExpired},
+		{"reviews before expiry", db.Request{Status: db.StatusPending, MinApprovals: 1, ExpiresAt: &future}, db.StatusApproved, Input{Approvals: 1}, nil},
+		{"policy after expiry", db.Request{Status: db.StatusPending, ExpiresAt: &past}, db.StatusApproved, Input{Authority: AuthorityPolicy}, nil},
+		{"reject after expiry", db.Request{Status: db.StatusPending, ExpiresAt: &past}, db.StatusRejected, Input{}, nil},
+		{"stale approval", db.Request{Status: db.StatusApproved, ApprovalExpiresAt: &past}, db.StatusExecuting, Input{Authority: AuthoritySystem}, ErrApprovalExpired},
+		{"fresh approval", db.Request{Status: db.StatusApproved, ApprovalExpiresAt: &future}, db.StatusExecuting, Input{Authority: AuthoritySystem}, nil},
+		{"table first", db.Request{Status: db.StatusPending}, db.StatusExecuting, Input{}, db.ErrInvalidTransition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check(&tt.req, tt.to, tt.in, clockStart)
+			if tt.wantErr == nil {
+				testutil.RequireNoError(t, err, "Check")
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Check() = %v, want %v", err, tt.wantErr)
+			}
+			var terr *TransitionError
+			if !errors.As(err, &terr) || terr.From != tt.req.Status || terr.To != tt.to {
+				t.Fatalf("expected a TransitionError for %s -> %s, got %#v", tt.req.Status, tt.to, err)
+			}
+		})
+	}
+}
+
+func TestMachine_CommitStoresApprovalAndEmits(t *testing.T) {
+	dbConn := testutil.NewTestDB(t)
+	clk := testutil.NewFakeClock(time.Now().UTC().Truncate(time.Second))
+	dbConn.SetClock(clk)
+	sess := testutil.MakeSession(t, dbConn)
+	req := testutil.MakeRequest(t, dbConn, sess, testutil.WithRisk(db.RiskTierCritical), testutil.WithMinApprovals(1))
+
+	var events []Event
+	m := New(dbConn).OnTransition(func(ev Event) { events = append(events, ev) })
+
+	if _, err := m.Commit(req.ID, db.StatusApproved, Input{Approvals: 0}); !errors.Is(err, ErrQuorumNotMet) {
+		t.Fatalf("expected ErrQuorumNotMet, got %v", err)
+	}
+	testutil.RequireLen(t, events, 0, "events after a vetoed transition")
+
+	ev, err := m.Commit(req.ID, db.StatusApproved, Input{Approvals: 1, Reason: "quorum reached", Actor: "reviewer"})
+	testutil.RequireNoError(t, err, "commit approved")
+	want := Event{
+		RequestID: req.ID, From: db.StatusPending, To: db.StatusApproved,
+		Authority: AuthorityReviews, Reason: "quorum reached", Actor: "reviewer", At: clk.Now(),
+	}
+	testutil.RequireEqual(t, want, *ev, "event")
+	testutil.RequireLen(t, events, 1, "events")
+	testutil.RequireEqual(t, want, events[0], "emitted event")
+
+	got, err := dbConn.GetRequest(req.ID)
+	testutil.RequireNoError(t, err, "get request")
+	testutil.RequireEqual(t, db.StatusApproved, got.Status, "status")
+	if got.ApprovalExpiresAt == nil || !got.ApprovalExpiresAt.Equal(clk.Now().Add(DefaultApprovalTTL.Critical)) {
+		t.Fatalf("approval_expires_at = %v, want %v", got.ApprovalExpiresAt, clk.Now().Add(DefaultApprovalTTL.Critical))
+	}
+
+	clk.Advance(DefaultApprovalTTL.Critical + time.Second)
+	if _, err := m.Commit(req.ID, db.StatusExecuting, Input{Authority: AuthoritySystem}); !errors.Is(err, ErrApprovalExpired) {
+		t.Fatalf("expected ErrApprovalExpired, got %v", err)
+	}
+}
+
+func TestMachine_CommitTxEmitsAfterCommit(t *testing.T) {
+	dbConn := testutil.NewTestDB(t)
+	sess := testutil.MakeSession(t, dbConn)
+	req := testutil.MakeRequest(t, dbConn, sess)
+
+	var events []Event
+	m := New(dbConn).OnTransition(func(ev Event) { events = append(events, ev) })
+
+	var ev *Event
+	err := dbConn.Transaction(func(tx *sql.Tx) error {
+		reqTx, err := dbConn.GetRequestTx(tx, req.ID)
+		if err != nil {
+			return err
+		}
+		ev, err = m.CommitTx(tx, reqTx, db.StatusCancelled, Input{Authority: AuthoritySystem})
+		return err
+	})
+	testutil.RequireNoError(t, err, "transaction")
+	testutil.RequireLen(t, events, 0, "events before Emit")
+
+	m.Emit(ev)
+	testutil.RequireLen(t, events, 1, "events after Emit")
+	testutil.RequireEqual(t, db.StatusCancelled, events[0].To, "emitted status")
+
+	got, err := dbConn.GetRequest(req.ID)
+	testutil.RequireNoError(t, err, "get request")
+	testutil.RequireEqual(t, db.StatusCancelled, got.Status, "status")
+	if got.ResolvedAt == nil {
+		t.Fatal("expected resolved_at on a terminal transition")
+	}
+}
+
+func TestMachine_CommitFirstWins(t *testing.T) {
+	dbConn := testutil.NewTestDB(t)
+	sess := testutil.MakeSession(t, dbConn)
+	future := time.Now().Add(time.Hour)
+	req := testutil.MakeRequest(t, dbConn, sess, testutil.WithStatus(db.StatusApproved))
+	_, err := dbConn.Exec(`UPDATE requests SET approval_expires_at = ? WHERE id = ?`,
+		future.UTC().Format(time.RFC3339), req.ID)
+	testutil.RequireNoError(t, err, "set approval expiry")
+
+	m := New(dbConn)
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = m.Commit(req.ID, db.StatusExecuting, Input{Authority: AuthoritySystem})
+		}(i)
+	}
+	wg.Wait()
+
+	won := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			won++
+		case !errors.Is(err, db.ErrInvalidTransition):
+			t.Fatalf("expected ErrInvalidTransition for losers, got %v", err)
+		}
+	}
+	testutil.RequireEqual(t, 1, won, "executors that won")
+}
+
+var allStatuses = []db.RequestStatus{
+	db.StatusPending, db.StatusApproved, db.StatusRejected, db.StatusExecuting, db.StatusExecuted,
+	db.StatusExecutionFailed, db.StatusCancelled, db.StatusTimeout, db.StatusTimedOut, db.StatusEscalated,
+}
+
+// TestMachine_CommitFollowsTransitionTable checks that exactly the moves in
+// the transition table are stored.
+func TestMachine_CommitFollowsTransitionTable(t *testing.T) {
+	dbConn := testutil.NewTestDB(t)
+	sess := testutil.MakeSession(t, dbConn)
+	m := New(dbConn)
+
+	for _, from := range allStatuses {
+		for _, to := range allStatuses {
+			req := testutil.MakeRequest(t, dbConn, sess, testutil.WithStatus(from))
+			_, err := m.Commit(req.ID, to, Input{Authority: AuthoritySystem})
+			if got, want := err == nil, CanTransition(from, to); got != want {
+				t.Errorf("%s -> %s: stored=%v, table allows=%v (%v)", from, to, got, want, err)
+			}
+
+			got, getErr := dbConn.GetRequest(req.ID)
+			testutil.RequireNoError(t, getErr, "get request")
+			want := from
+			if err == nil {
+				want = to
+			}
+			if got.Status != want {
+				t.Errorf("%s -> %s: stored status %s, want %s", from, to, got.Status, want)
+			}
+		}
+	}
+}
@@ -1,18 +1,18 @@
-// Package core implements the request lifecycle state machine.
-package core
+// Package statemachine is the single authority on request status
+// transitions: which moves are allowed, the guards (quorum, request expiry,
+// approval staleness) that can veto an allowed move, and the events emitted
+// once a transition is stored. Every status change in the CLI, daemon, TUI
+// and review/execution services goes through a Machine.
+package statemachine
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/Dicklesworthstone/slb/internal/db"
 )
 
-const (
-	defaultApprovalTTL         = 30 * time.Minute
-	defaultApprovalTTLCritical = 10 * time.Minute
-)
-
 // validTransitions defines all valid state transitions.
 // Map key is the from state, value is a list of valid to states.
 var validTransitions = map[db.RequestStatus][]db.RequestStatus{
@@ -21,6 +21,7 @@ var validTransitions = map[db.RequestStatus][]db.RequestStatus{
 		db.StatusRejected,
 		db.StatusCancelled,
 		db.StatusTimeout,
+		db.StatusEscalated, // Split vote under human_breaks_tie
 	},
 	db.StatusApproved: {
 		db.StatusExecuting,
@@ -50,17 +51,36 @@ var TerminalStates = map[db.RequestStatus]bool{
 	db.StatusRejected:        true,
 }
 
+var (
+	// ErrQuorumNotMet is returned when reviews approve a request without
+	// enough approvals.
+	ErrQuorumNotMet = errors.New("approval quorum not met")
+	// ErrRequestExpired is returned when reviews approve a pending request
+	// after its deadline.
+	ErrRequestExpired = errors.New("request has expired")
+	// ErrApprovalExpired is returned when execution starts on a stale approval.
+	ErrApprovalExpired = errors.New("approval has expired")
+)
+
 // TransitionError represents an invalid state transition.
 type TransitionError struct {
 	From    db.RequestStatus
 	To      db.RequestStatus
 	Message string
+	// Err is the cause: db.ErrInvalidTransition when the transition table
+	// forbids the move, or the sentinel of the guard that vetoed it.
+	Err error
 }
 
 func (e *TransitionError) Error() string {
 	return fmt.Sprintf("invalid transition from %s to %s: %s", e.From, e.To, e.Message)
 }
 
+// Unwrap returns the cause, so callers can match the guard with errors.Is.
+func (e *TransitionError) Unwrap() error {
+	return e.Err
+}
+
 // CanTransition returns true if the transition from one state to another is valid.
 func CanTransition(from, to db.RequestStatus) bool {
 	// Allow creation-time transition.
@@ -95,6 +115,7 @@ func ValidateTransition(from, to db.RequestStatus) error {
 			From:    from,
 			To:      to,
 			Message: fmt.Sprintf("%s is a terminal state", from),
+			Err:     db.ErrInvalidTransition,
 		}
 	}
 
@@ -103,32 +124,51 @@ func ValidateTransition(from, to db.RequestStatus) error {
 			From:    from,
 			To:      to,
 			Message: "transition not allowed",
+			Err:     db.ErrInvalidTransition,
 		}
 	}
 
 	return nil
 }
 
-// Transition attempts to transition a request to a new state.
-// Returns an error if the transition is invalid.
-func Transition(req *db.Request, to db.RequestStatus) error {
+// Check validates moving req to the given state at now: the transition table
+// first, then every guard.
+func Check(req *db.Request, to db.RequestStatus, in Input, now time.Time) error {
 	if err := ValidateTransition(req.Status, to); err != nil {
 		return err
 	}
+	for _, guard := range guards {
+		if err := guard(req, to, in, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Transition checks and applies a transition to req in memory, on the system
+// clock with the default approval TTL. Machine.Commit also stores it.
+func Transition(req *db.Request, to db.RequestStatus, in Input) error {
+	return TransitionAt(req, to, in, time.Now().UTC())
+}
+
+// TransitionAt is Transition at the given time.
+func TransitionAt(req *db.Request, to db.RequestStatus, in Input, now time.Time) error {
+	return transition(req, to, in, now, DefaultApprovalTTL)
+}
+
+func transition(req *db.Request, to db.RequestStatus, in Input, now time.Time, ttl ApprovalTTL) error {
+	if err := Check(req, to, in, now); err != nil {
+		return err
+	}
 
 	// Update the request
-	now := time.Now().UTC()
 	if req.Status == "" && to == db.StatusPending && req.CreatedAt.IsZero() {
 		req.CreatedAt = now
 	}
 	req.Status = to
 
 	if to == db.StatusApproved && req.ApprovalExpiresAt == nil {
-		ttl := defaultApprovalTTL
-		if req.RiskTier == db.RiskTierCritical {
-			ttl = defaultApprovalTTLCritical
-		}
-		expiresAt := now.Add(ttl)
+		expiresAt := now.Add(ttl.For(req.RiskTier))
 		req.ApprovalExpiresAt = &expiresAt
 	}
 
@@ -140,17 +180,6 @@ func Transition(req *db.Request, to db.RequestStatus) error {
 	return nil
 }
 
-// TransitionWithReason transitions a request and logs the reason.
-// This is useful for audit logging.
-func TransitionWithReason(req *db.Request, to db.RequestStatus, reason string) error {
-	if err := Transition(req, to); err != nil {
-		return err
-	}
-	// Reason could be logged to the request's audit trail
-	// For now, just return success
-	return nil
-}
-
 // GetValidTransitions returns all valid target states from the given state.
 func GetValidTransitions(from db.RequestStatus) []db.RequestStatus {
 	if from == "" {
@@ -209,6 +238,10 @@ func CanCancel(status db.RequestStatus) bool {
 // CheckExpiry checks if a pending request has expired.
 // Returns the appropriate status transition if expired.
 func CheckExpiry(req *db.Request) (db.RequestStatus, bool) {
+	return checkExpiryAt(req, time.Now())
+}
+
+func checkExpiryAt(req *db.Request, now time.Time) (db.RequestStatus, bool) {
 	if req.Status != db.StatusPending {
 		return "", false
 	}
@@ -217,7 +250,7 @@ func CheckExpiry(req *db.Request) (db.RequestStatus, bool) {
 		return "", false
 	}
 
-	if time.Now().After(*req.ExpiresAt) {
+	if now.After(*req.ExpiresAt) {
 		return db.StatusTimeout, true
 	}
 
@@ -226,6 +259,10 @@ func CheckExpiry(req *db.Request) (db.RequestStatus, bool) {
 
 // CheckApprovalExpiry checks if an approved request's approval has become stale.
 func CheckApprovalExpiry(req *db.Request) bool {
+	return checkApprovalExpiryAt(req, time.Now())
+}
+
+func checkApprovalExpiryAt(req *db.Request, now time.Time) bool {
 	if req.Status != db.StatusApproved {
 		return false
 	}
@@ -234,23 +271,5 @@ func CheckApprovalExpiry(req *db.Request) bool {
 		return false
 	}
 
-	return time.Now().After(*req.ApprovalExpiresAt)
-}
-
-// StateMachine provides request state management.
-type StateMachine struct{}
-
-// NewStateMachine creates a new state machine.
-func NewStateMachine() *StateMachine {
-	return &StateMachine{}
-}
-
-// Transition transitions a request to a new state.
-func (sm *StateMachine) Transition(req *db.Request, to db.RequestStatus) error {
-	return Transition(req, to)
-}
-
-// CanTransition checks if a transition is valid.
-func (sm *StateMachine) CanTransition(from, to db.RequestStatus) bool {
-	return CanTransition(from, to)
+	return now.After(*req.ApprovalExpiresAt)
 }
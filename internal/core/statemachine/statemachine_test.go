@@ -1,4 +1,4 @@
-package core
+package statemachine
 
 import (
 	"testing"
@@ -21,6 +21,7 @@ func TestCanTransition(t *testing.T) {
 		{"pending->rejected", db.StatusPending, db.StatusRejected, true},
 		{"pending->cancelled", db.StatusPending, db.StatusCancelled, true},
 		{"pending->timeout", db.StatusPending, db.StatusTimeout, true},
+		{"pending->escalated", db.StatusPending, db.StatusEscalated, true},
 		{"pending->executing (invalid)", db.StatusPending, db.StatusExecuting, false},
 
 		{"timeout->escalated", db.StatusTimeout, db.StatusEscalated, true},
@@ -55,7 +56,7 @@ func TestCanTransition(t *testing.T) {
 func TestTransitionSetsResolvedAtForTerminalStates(t *testing.T) {
 	t.Run("sets created_at for new->pending when missing", func(t *testing.T) {
 		req := &db.Request{Status: ""}
-		if err := Transition(req, db.StatusPending); err != nil {
+		if err := Transition(req, db.StatusPending, Input{}); err != nil {
 			t.Fatalf("Transition() error = %v", err)
 		}
 		if req.Status != db.StatusPending {
@@ -68,7 +69,7 @@ func TestTransitionSetsResolvedAtForTerminalStates(t *testing.T) {
 
 	t.Run("sets resolved_at for rejected", func(t *testing.T) {
 		req := &db.Request{Status: db.StatusPending}
-		if err := Transition(req, db.StatusRejected); err != nil {
+		if err := Transition(req, db.StatusRejected, Input{}); err != nil {
 			t.Fatalf("Transition() error = %v", err)
 		}
 		if req.Status != db.StatusRejected {
@@ -81,7 +82,7 @@ func TestTransitionSetsResolvedAtForTerminalStates(t *testing.T) {
 
 	t.Run("does not set resolved_at for timeout", func(t *testing.T) {
 		req := &db.Request{Status: db.StatusPending}
-		if err := Transition(req, db.StatusTimeout); err != nil {
+		if err := Transition(req, db.StatusTimeout, Input{}); err != nil {
 			t.Fatalf("Transition() error = %v", err)
 		}
 		if req.Status != db.StatusTimeout {
@@ -94,7 +95,7 @@ func TestTransitionSetsResolvedAtForTerminalStates(t *testing.T) {
 
 	t.Run("sets resolved_at for executed", func(t *testing.T) {
 		req := &db.Request{Status: db.StatusExecuting}
-		if err := Transition(req, db.StatusExecuted); err != nil {
+		if err := Transition(req, db.StatusExecuted, Input{}); err != nil {
 			t.Fatalf("Transition() error = %v", err)
 		}
 		if req.Status != db.StatusExecuted {
@@ -109,7 +110,7 @@ func TestTransitionSetsResolvedAtForTerminalStates(t *testing.T) {
 		req := &db.Request{Status: db.StatusPending, RiskTier: db.RiskTierDangerous}
 
 		before := time.Now().UTC()
-		if err := Transition(req, db.StatusApproved); err != nil {
+		if err := Transition(req, db.StatusApproved, Input{}); err != nil {
 			t.Fatalf("Transition() error = %v", err)
 		}
 		after := time.Now().UTC()
@@ -118,8 +119,8 @@ func TestTransitionSetsResolvedAtForTerminalStates(t *testing.T) {
 			t.Fatalf("ApprovalExpiresAt is nil, want non-nil after approved transition")
 		}
 
-		min := before.Add(defaultApprovalTTL)
-		max := after.Add(defaultApprovalTTL)
+		min := before.Add(DefaultApprovalTTL.Default)
+		max := after.Add(DefaultApprovalTTL.Default)
 		if req.ApprovalExpiresAt.Before(min) || req.ApprovalExpiresAt.After(max) {
 			t.Fatalf("ApprovalExpiresAt = %s, want between [%s, %s]", req.ApprovalExpiresAt.Format(time.RFC3339), min.Format(time.RFC3339), max.Format(time.RFC3339))
 		}
@@ -129,7 +130,7 @@ func TestTransitionSetsResolvedAtForTerminalStates(t *testing.T) {
 		req := &db.Request{Status: db.StatusPending, RiskTier: db.RiskTierCritical}
 
 		before := time.Now().UTC()
-		if err := Transition(req, db.StatusApproved); err != nil {
+		if err := Transition(req, db.StatusApproved, Input{}); err != nil {
 			t.Fatalf("Transition() error = %v", err)
 		}
 		after := time.Now().UTC()
@@ -138,8 +139,8 @@ func TestTransitionSetsResolvedAtForTerminalStates(t *testing.T) {
 			t.Fatalf("ApprovalExpiresAt is nil, want non-nil after approved transition")
 		}
 
-		min := before.Add(defaultApprovalTTLCritical)
-		max := after.Add(defaultApprovalTTLCritical)
+		min := before.Add(DefaultApprovalTTL.Critical)
+		max := after.Add(DefaultApprovalTTL.Critical)
 		if req.ApprovalExpiresAt.Before(min) || req.ApprovalExpiresAt.After(max) {
 			t.Fatalf("ApprovalExpiresAt = %s, want between [%s, %s]", req.ApprovalExpiresAt.Format(time.RFC3339), min.Format(time.RFC3339), max.Format(time.RFC3339))
 		}
@@ -148,7 +149,7 @@ func TestTransitionSetsResolvedAtForTerminalStates(t *testing.T) {
 
 func TestTransitionRejectsInvalidMoves(t *testing.T) {
 	req := &db.Request{Status: db.StatusPending}
-	if err := Transition(req, db.StatusExecuting); err == nil {
+	if err := Transition(req, db.StatusExecuting, Input{}); err == nil {
 		t.Fatalf("expected error for invalid transition")
 	}
 	if req.Status != db.StatusPending {
@@ -185,7 +186,7 @@ func TestIsTerminal(t *testing.T) {
 
 func TestTransitionRejectsSameState(t *testing.T) {
 	req := &db.Request{Status: db.StatusPending}
-	if err := Transition(req, db.StatusPending); err == nil {
+	if err := Transition(req, db.StatusPending, Input{}); err == nil {
 		t.Fatalf("expected error for same-state transition")
 	}
 }
@@ -231,25 +232,6 @@ func TestValidateTransition_ValidTransition(t *testing.T) {
 	}
 }
 
-func TestTransitionWithReason(t *testing.T) {
-	req := &db.Request{Status: db.StatusPending}
-	err := TransitionWithReason(req, db.StatusApproved, "manually approved by admin")
-	if err != nil {
-		t.Fatalf("TransitionWithReason() error = %v", err)
-	}
-	if req.Status != db.StatusApproved {
-		t.Errorf("Status = %q, want %q", req.Status, db.StatusApproved)
-	}
-}
-
-func TestTransitionWithReason_Invalid(t *testing.T) {
-	req := &db.Request{Status: db.StatusPending}
-	err := TransitionWithReason(req, db.StatusExecuted, "invalid")
-	if err == nil {
-		t.Fatal("expected error for invalid transition")
-	}
-}
-
 func TestGetValidTransitions(t *testing.T) {
 	tests := []struct {
 		name string
@@ -257,7 +239,7 @@ func TestGetValidTransitions(t *testing.T) {
 		want []db.RequestStatus
 	}{
 		{"empty->pending", "", []db.RequestStatus{db.StatusPending}},
-		{"pending", db.StatusPending, []db.RequestStatus{db.StatusApproved, db.StatusRejected, db.StatusCancelled, db.StatusTimeout}},
+		{"pending", db.StatusPending, []db.RequestStatus{db.StatusApproved, db.StatusRejected, db.StatusCancelled, db.StatusTimeout, db.StatusEscalated}},
 		{"approved", db.StatusApproved, []db.RequestStatus{db.StatusExecuting, db.StatusCancelled}},
 		{"executing", db.StatusExecuting, []db.RequestStatus{db.StatusExecuted, db.StatusExecutionFailed, db.StatusTimedOut, db.StatusApproved}},
 		{"timeout", db.StatusTimeout, []db.RequestStatus{db.StatusEscalated}},
@@ -476,33 +458,3 @@ func TestCheckApprovalExpiry(t *testing.T) {
 		})
 	}
 }
-
-func TestStateMachine(t *testing.T) {
-	t.Run("NewStateMachine", func(t *testing.T) {
-		sm := NewStateMachine()
-		if sm == nil {
-			t.Fatal("NewStateMachine() returned nil")
-		}
-	})
-
-	t.Run("Transition", func(t *testing.T) {
-		sm := NewStateMachine()
-		req := &db.Request{Status: db.StatusPending}
-		if err := sm.Transition(req, db.StatusApproved); err != nil {
-			t.Fatalf("Transition() error = %v", err)
-		}
-		if req.Status != db.StatusApproved {
-			t.Errorf("Status = %q, want %q", req.Status, db.StatusApproved)
-		}
-	})
-
-	t.Run("CanTransition", func(t *testing.T) {
-		sm := NewStateMachine()
-		if !sm.CanTransition(db.StatusPending, db.StatusApproved) {
-			t.Error("CanTransition(pending, approved) = false, want true")
-		}
-		if sm.CanTransition(db.StatusPending, db.StatusExecuted) {
-			t.Error("CanTransition(pending, executed) = true, want false")
-		}
-	})
-}
@@ -0,0 +1,125 @@
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// maxSuggestSubcommands caps how many subcommand words a suggested pattern
+// keeps after the program name ("aws s3 rm" but not the bucket).
+const maxSuggestSubcommands = 2
+
+// subcommandToken matches words that read as subcommands rather than
+// arguments: no flags, paths, assignments or quoting.
+var subcommandToken = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_.:-]*$`)
+
+var (
+	builtinEngineOnce sync.Once
+	builtinEngine     *PatternEngine
+)
+
+// builtinPatterns returns an engine holding only the builtin patterns.
+func builtinPatterns() *PatternEngine {
+	builtinEngineOnce.Do(func() { builtinEngine = NewPatternEngine() })
+	return builtinEngine
+}
+
+// SuggestPatternForCommand builds a candidate pattern for a command from its
+// program and leading subcommand words, anchored like the builtins: for
+// "aws s3 rm s3://bucket --recursive" it returns `^aws\s+s3\s+rm($|\s)`.
+// Wrappers such as sudo and env assignments are stripped first. It returns
+// "" when the command has no usable program name.
+func SuggestPatternForCommand(cmd string) string {
+	primary := NormalizeCommand(cmd).Primary
+	fields := strings.Fields(primary)
+	for len(fields) > 0 && isEnvAssignment(fields[0]) {
+		fields = fields[1:]
+	}
+	if len(fields) == 0 {
+		return ""
+	}
+	name := filepath.Base(fields[0])
+	if !subcommandToken.MatchString(name) {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	if strings.Contains(fields[0], "/") {
+		// Match the program by any path as well as bare.
+		b.WriteString(`(\S*/)?`)
+	}
+	b.WriteString(regexp.QuoteMeta(name))
+	for i := 1; i < len(fields) && i <= maxSuggestSubcommands; i++ {
+		if !subcommandToken.MatchString(fields[i]) {
+			break
+		}
+		b.WriteString(`\s+`)
+		b.WriteString(regexp.QuoteMeta(fields[i]))
+	}
+	b.WriteString(`($|\s)`)
+	return b.String()
+}
+
+// rejectionSuggestion returns the pattern change to queue when a reviewer
+// rejects req: a command that no builtin pattern ranks above caution is a
+// gap in the builtins. It returns nil when the builtins already cover the
+// command or no candidate can be built.
+func rejectionSuggestion(req *db.Request, review *db.Review) *db.PatternChange {
+	match := builtinPatterns().ClassifyCommand(req.Command.Raw, req.Command.Cwd)
+	if match.MatchedPattern != "" && match.Tier != RiskTierCaution {
+		return nil
+	}
+	pattern := SuggestPatternForCommand(req.Command.Raw)
+	if pattern == "" {
+		return nil
+	}
+
+	tier := RiskTierDangerous
+	if req.RiskTier == RiskTierCritical {
+		tier = RiskTierCritical
+	}
+	command := req.Command.Raw
+	if req.Command.ContainsSensitive && req.Command.DisplayRedacted != "" {
+		command = req.Command.DisplayRedacted
+	}
+	reason := fmt.Sprintf("Rejected request %s (%s)", req.ID, command)
+	if comments := strings.TrimSpace(review.Comments); comments != "" {
+		reason += ": " + comments
+	}
+	return &db.PatternChange{
+		Tier:       string(tier),
+		Pattern:    pattern,
+		ChangeType: db.PatternChangeTypeSuggest,
+		Reason:     reason,
+	}
+}
+
+// suggestFromRejection queues a candidate pattern for a rejected command
+// the builtins missed. It is best-effort, like notifications: the review
+// is already recorded, and a pending suggestion for the same pattern is
+// not duplicated.
+func (rs *ReviewService) suggestFromRejection(p *preparedReview) {
+	if p.decision != db.DecisionReject {
+		return
+	}
+	pc := rejectionSuggestion(p.request, p.review)
+	if pc == nil {
+		return
+	}
+	pending, err := rs.db.ListPendingPatternChanges()
+	if err != nil {
+		return
+	}
+	for _, existing := range pending {
+		if existing.ChangeType == db.PatternChangeTypeSuggest && existing.Pattern == pc.Pattern {
+			return
+		}
+	}
+	_ = rs.db.CreatePatternChange(pc)
+}
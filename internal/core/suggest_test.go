@@ -0,0 +1,149 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+func TestSuggestPatternForCommand(t *testing.T) {
+	tests := []struct {
+		cmd  string
+		want string
+	}{
+		{"aws s3 rm s3://bucket --recursive", `^aws\s+s3\s+rm($|\s)`},
+		{"sudo redis-cli flushall", `^redis-cli\s+flushall($|\s)`},
+		{"/usr/local/bin/psql -c 'DELETE FROM users'", `^(\S*/)?psql($|\s)`},
+		{"vault kv delete secret/app", `^vault\s+kv\s+delete($|\s)`},
+		{"gh repo delete owner/repo --yes", `^gh\s+repo\s+delete($|\s)`},
+		{"FOO=1 make clean-all && echo done", `^make\s+clean-all($|\s)`},
+		{"", ""},
+		{"./run.sh --all", `^(\S*/)?run\.sh($|\s)`},
+		{"'$(whoami)'", ""},
+	}
+	for _, tt := range tests {
+		if got := SuggestPatternForCommand(tt.cmd); got != tt.want {
+			t.Errorf("SuggestPatternForCommand(%q) = %q, want %q", tt.cmd, got, tt.want)
+		}
+	}
+}
+
+func TestSuggestPatternForCommand_MatchesItsCommand(t *testing.T) {
+	engine := &PatternEngine{}
+	cmd := "aws s3 rm s3://bucket --recursive"
+	if err := engine.AddPattern(RiskTierDangerous, SuggestPatternForCommand(cmd), "", "suggested"); err != nil {
+		t.Fatalf("AddPattern: %v", err)
+	}
+	if got := engine.ClassifyCommand(cmd, "/tmp"); got.Tier != RiskTierDangerous {
+		t.Errorf("suggested pattern does not classify its command, tier = %q", got.Tier)
+	}
+	if got := engine.ClassifyCommand("aws s3 ls", "/tmp"); got.MatchedPattern != "" {
+		t.Errorf("suggested pattern is too broad, matched %q", got.MatchedPattern)
+	}
+	if err := engine.AddPattern(RiskTierCritical, SuggestPatternForCommand("/opt/bin/wipe-db --all"), "", "suggested"); err != nil {
+		t.Fatalf("AddPattern: %v", err)
+	}
+	for _, c := range []string{"/opt/bin/wipe-db --all", "wipe-db"} {
+		if got := engine.ClassifyCommand(c, "/tmp"); got.Tier != RiskTierCritical {
+			t.Errorf("%q tier = %q, want critical", c, got.Tier)
+		}
+	}
+}
+
+func TestRejectionSuggestion(t *testing.T) {
+	review := &db.Review{Comments: "wipes the shared cache"}
+	tests := []struct {
+		name     string
+		raw      string
+		tier     db.RiskTier
+		wantTier string
+		want     bool
+	}{
+		{"no builtin match", "redis-cli flushall", db.RiskTierDangerous, "dangerous", true},
+		{"caution only", "rm notes.txt", db.RiskTierCaution, "dangerous", true},
+		{"critical request keeps its tier", "redis-cli flushall", db.RiskTierCritical, "critical", true},
+		{"builtin dangerous", "git reset --hard HEAD~3", db.RiskTierDangerous, "", false},
+		{"builtin critical", "terraform destroy", db.RiskTierCritical, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &db.Request{ID: "req-1", RiskTier: tt.tier, Command: db.CommandSpec{Raw: tt.raw, Cwd: "/tmp"}}
+			pc := rejectionSuggestion(req, review)
+			if (pc != nil) != tt.want {
+				t.Fatalf("rejectionSuggestion = %+v, want suggestion %v", pc, tt.want)
+			}
+			if pc == nil {
+				return
+			}
+			if pc.Tier != tt.wantTier || pc.ChangeType != db.PatternChangeTypeSuggest {
+				t.Errorf("tier/type = %s/%s", pc.Tier, pc.ChangeType)
+			}
+			if !strings.Contains(pc.Reason, "req-1") || !strings.Contains(pc.Reason, "wipes the shared cache") {
+				t.Errorf("reason = %q", pc.Reason)
+			}
+		})
+	}
+
+	// Sensitive commands are quoted in their redacted form.
+	req := &db.Request{ID: "req-2", RiskTier: db.RiskTierDangerous, Command: db.CommandSpec{
+		Raw: "mysql -psecret -e 'drop user x'", Cwd: "/tmp",
+		ContainsSensitive: true, DisplayRedacted: "mysql -p*** -e 'drop user x'",
+	}}
+	pc := rejectionSuggestion(req, review)
+	if pc == nil || strings.Contains(pc.Reason, "secret") {
+		t.Errorf("suggestion = %+v", pc)
+	}
+}
+
+func TestSubmitReview_RejectionSuggestsPattern(t *testing.T) {
+	dbConn, sess, _ := setupReviewTest(t)
+	defer dbConn.Close()
+
+	reviewer := &db.Session{AgentName: "GreenLake", Program: "claude-code", Model: "opus-4.5", ProjectPath: "/test/project"}
+	if err := dbConn.CreateSession(reviewer); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	rs := NewReviewService(dbConn, DefaultReviewConfig())
+
+	reject := func(raw string) {
+		t.Helper()
+		req := &db.Request{
+			ProjectPath:        "/test/project",
+			RequestorSessionID: sess.ID,
+			RequestorAgent:     sess.AgentName,
+			RequestorModel:     sess.Model,
+			RiskTier:           db.RiskTierDangerous,
+			MinApprovals:       1,
+			Command:            db.CommandSpec{Raw: raw, Cwd: "/test/project"},
+			Justification:      db.Justification{Reason: "cleanup"},
+		}
+		if err := dbConn.CreateRequest(req); err != nil {
+			t.Fatalf("CreateRequest() error = %v", err)
+		}
+		if _, err := rs.SubmitReview(ReviewOptions{
+			SessionID: reviewer.ID, SessionKey: reviewer.SessionKey, RequestID: req.ID,
+			Decision: db.DecisionReject, Comments: "flushes production cache",
+		}); err != nil {
+			t.Fatalf("SubmitReview() error = %v", err)
+		}
+	}
+
+	reject("redis-cli flushall")
+	reject("sudo redis-cli flushall") // same candidate: not queued twice
+	reject("git reset --hard HEAD")   // builtin covers it
+
+	changes, err := dbConn.ListPendingPatternChanges()
+	if err != nil {
+		t.Fatalf("ListPendingPatternChanges() error = %v", err)
+	}
+	if len(changes) != 1 {
+		t.Fatalf("expected 1 suggestion, got %d: %+v", len(changes), changes)
+	}
+	if changes[0].Pattern != `^redis-cli\s+flushall($|\s)` || changes[0].ChangeType != db.PatternChangeTypeSuggest {
+		t.Errorf("suggestion = %+v", changes[0])
+	}
+	if !strings.Contains(changes[0].Reason, "flushes production cache") {
+		t.Errorf("reason = %q", changes[0].Reason)
+	}
+}
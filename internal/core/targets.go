@@ -0,0 +1,425 @@
+package core
+
+import (
+	"fmt"
+	"net"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// DefaultProductionHosts are the host patterns treated as production when
+// no configuration is loaded; they match the targets.production_hosts
+// default.
+var DefaultProductionHosts = []string{
+	"prod", "prod-*", "*-prod", "*-prod-*", "prod[0-9]*",
+	"production", "production-*", "*-production", "prd",
+}
+
+// Target is a remote system a command acts on.
+type Target struct {
+	// Host is the host name or address, without user or port.
+	Host string `json:"host"`
+	// Via is how the command reaches it: ssh, scp, rsync, ansible, http,
+	// or a database scheme or client such as postgres or psql.
+	Via string `json:"via"`
+	// Production is set when Host matches a production host pattern.
+	Production bool `json:"production,omitempty"`
+}
+
+var (
+	// dbURLRe matches a database connection string anywhere in a command,
+	// including env assignments such as DATABASE_URL=postgres://...
+	dbURLRe = regexp.MustCompile(`(?i)\b(postgres(?:ql)?|mysql|mariadb|mongodb(?:\+srv)?|rediss?|amqps?|clickhouse)://([^\s'"/?#]+)`)
+	// conninfoHostRe matches host=... in a libpq connection string.
+	conninfoHostRe = regexp.MustCompile(`\bhost=([^\s'"]+)`)
+)
+
+// sshValueFlags are ssh options that take a value.
+var sshValueFlags = map[string]bool{
+	"-b": true, "-c": true, "-D": true, "-E": true, "-e": true, "-F": true,
+	"-I": true, "-i": true, "-J": true, "-L": true, "-l": true, "-m": true,
+	"-O": true, "-o": true, "-p": true, "-Q": true, "-R": true, "-S": true,
+	"-W": true, "-w": true,
+}
+
+// ansibleValueFlags are ansible options that take a value.
+var ansibleValueFlags = map[string]bool{
+	"-i": true, "--inventory": true, "-l": true, "--limit": true,
+	"-m": true, "--module-name": true, "-a": true, "--args": true,
+	"-u": true, "--user": true, "-f": true, "--forks": true,
+	"-e": true, "--extra-vars": true, "-t": true, "--tags": true,
+	"-M": true, "--module-path": true, "-T": true, "--timeout": true,
+	"-B": true, "-P": true, "-c": true, "--connection": true,
+	"--private-key": true, "--vault-password-file": true, "--become-user": true,
+}
+
+// dbHostClients are database clients that name their server with -h/--host.
+var dbHostClients = map[string]bool{
+	"psql": true, "pg_dump": true, "pg_dumpall": true, "pg_restore": true,
+	"createdb": true, "dropdb": true, "mysql": true, "mysqldump": true,
+	"mysqladmin": true, "mariadb": true, "mongo": true, "mongosh": true,
+	"mongodump": true, "mongorestore": true, "redis-cli": true,
+}
+
+// mutatingMethods are HTTP methods that change the remote side.
+var mutatingMethods = map[string]bool{
+	"DELETE": true, "PUT": true, "POST": true, "PATCH": true,
+}
+
+// ExtractTargets returns the remote hosts a command acts on: ssh, scp and
+// rsync destinations, ansible host patterns and host-list inventories,
+// URLs sent mutating HTTP requests (curl -X DELETE, wget --method=PUT,
+// http DELETE ...), and database servers from connection strings or
+// client -h flags. Local hosts are left out.
+func ExtractTargets(cmd string) []Target {
+	var targets []Target
+	seen := make(map[string]bool)
+	add := func(host, via string) {
+		host = normalizeTargetHost(host)
+		if host == "" || seen[host] || isLocalHost(host) {
+			return
+		}
+		seen[host] = true
+		targets = append(targets, Target{Host: host, Via: via})
+	}
+
+	for _, st := range ScriptStatements(cmd) {
+		for _, m := range dbURLRe.FindAllStringSubmatch(st.Text, -1) {
+			hosts := m[2]
+			if i := strings.LastIndex(hosts, "@"); i >= 0 {
+				hosts = hosts[i+1:]
+			}
+			for _, h := range strings.Split(hosts, ",") {
+				add(h, strings.ToLower(m[1]))
+			}
+		}
+		for _, seg := range splitCompoundShellAware(st.Text) {
+			for _, part := range splitPipeline(seg, true) {
+				words := commandWords(part)
+				if len(words) == 0 {
+					continue
+				}
+				name := filepath.Base(words[0])
+				for _, host := range commandTargets(name, words[1:]) {
+					add(host, name)
+				}
+			}
+		}
+	}
+	return targets
+}
+
+// commandTargets returns the hosts one command names, by program.
+func commandTargets(name string, args []string) []string {
+	switch {
+	case name == "ssh":
+		return sshTargets(args)
+	case name == "scp" || name == "rsync":
+		return copyTargets(args)
+	case name == "ansible" || name == "ansible-playbook":
+		return ansibleTargets(name, args)
+	case name == "curl" || name == "wget" || name == "http" || name == "https" || name == "xh":
+		return httpTargets(name, args)
+	case dbHostClients[name]:
+		return dbClientTargets(args)
+	}
+	return nil
+}
+
+func sshTargets(args []string) []string {
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if a == "--" {
+			if i+1 < len(args) {
+				return []string{args[i+1]}
+			}
+			return nil
+		}
+		if strings.HasPrefix(a, "-") {
+			if sshValueFlags[a] {
+				i++
+			}
+			continue
+		}
+		return []string{a}
+	}
+	return nil
+}
+
+// copyTargets returns the hosts of [user@]host:path and host::module
+// operands.
+func copyTargets(args []string) []string {
+	var hosts []string
+	for _, a := range args {
+		if strings.HasPrefix(a, "-") || strings.Contains(a, "://") {
+			continue
+		}
+		i := strings.Index(a, ":")
+		// A bracketed IPv6 address: user@[2001:db8::1]:/path
+		if end := strings.Index(a, "]:"); end > 0 && strings.Contains(a[:i], "[") {
+			i = end + 1
+		}
+		if i <= 0 || strings.Contains(a[:i], "/") {
+			continue
+		}
+		hosts = append(hosts, a[:i])
+	}
+	return hosts
+}
+
+// ansibleTargets returns ansible's host pattern (ad hoc runs only), the
+// --limit patterns, and the hosts of a comma-separated inventory. An
+// inventory file is kept as a target too: its path often names the
+// environment (inventories/production).
+func ansibleTargets(name string, args []string) []string {
+	var hosts []string
+	positional := false
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		flag, value, inline := strings.Cut(a, "=")
+		if strings.HasPrefix(a, "-") && ansibleValueFlags[flag] {
+			if !inline {
+				if i+1 >= len(args) {
+					break
+				}
+				i++
+				value = args[i]
+			}
+			switch flag {
+			case "-l", "--limit":
+				hosts = append(hosts, splitHostPattern(value)...)
+			case "-i", "--inventory":
+				if strings.Contains(value, ",") {
+					hosts = append(hosts, splitHostPattern(value)...)
+				} else {
+					hosts = append(hosts, value)
+				}
+			}
+			continue
+		}
+		if strings.HasPrefix(a, "-") {
+			continue
+		}
+		if name == "ansible" && !positional {
+			positional = true
+			hosts = append(hosts, splitHostPattern(a)...)
+		}
+	}
+	return hosts
+}
+
+// splitHostPattern splits an ansible host pattern or inventory list.
+func splitHostPattern(p string) []string {
+	var hosts []string
+	for _, h := range strings.FieldsFunc(p, func(r rune) bool { return r == ',' || r == ':' }) {
+		h = strings.TrimLeft(h, "!&")
+		if h != "" && h != "all" {
+			hosts = append(hosts, h)
+		}
+	}
+	return hosts
+}
+
+// httpTargets returns the hosts of URLs a command sends a mutating request
+// to. Plain downloads are not targets.
+func httpTargets(name string, args []string) []string {
+	mutating := false
+	var urls []string
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if urlArgRe.MatchString(a) {
+			urls = append(urls, a)
+			continue
+		}
+		switch name {
+		case "curl":
+			switch {
+			case a == "-X" || a == "--request":
+				if i+1 < len(args) {
+					i++
+					mutating = mutating || mutatingMethods[strings.ToUpper(args[i])]
+				}
+			case strings.HasPrefix(a, "-X") && len(a) > 2:
+				mutating = mutating || mutatingMethods[strings.ToUpper(a[2:])]
+			case strings.HasPrefix(a, "--request="):
+				mutating = mutating || mutatingMethods[strings.ToUpper(strings.TrimPrefix(a, "--request="))]
+			case a == "-d" || a == "-F" || a == "-T" || strings.HasPrefix(a, "--data") ||
+				a == "--form" || a == "--json" || a == "--upload-file":
+				mutating = true
+			}
+		case "wget":
+			switch {
+			case strings.HasPrefix(a, "--method="):
+				mutating = mutating || mutatingMethods[strings.ToUpper(strings.TrimPrefix(a, "--method="))]
+			case a == "--method" && i+1 < len(args):
+				i++
+				mutating = mutating || mutatingMethods[strings.ToUpper(args[i])]
+			case strings.HasPrefix(a, "--post-data") || strings.HasPrefix(a, "--post-file") ||
+				strings.HasPrefix(a, "--body-data") || strings.HasPrefix(a, "--body-file"):
+				mutating = true
+			}
+		default: // httpie and xh: http [flags] METHOD URL
+			mutating = mutating || mutatingMethods[a]
+		}
+	}
+	if !mutating {
+		return nil
+	}
+	hosts := make([]string, 0, len(urls))
+	for _, u := range urls {
+		rest := u[strings.Index(u, "://")+3:]
+		if i := strings.IndexAny(rest, "/?#"); i >= 0 {
+			rest = rest[:i]
+		}
+		hosts = append(hosts, rest)
+	}
+	return hosts
+}
+
+// dbClientTargets returns the server a database client connects to.
+func dbClientTargets(args []string) []string {
+	var hosts []string
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch {
+		case a == "-h" || a == "--host":
+			if i+1 < len(args) {
+				i++
+				hosts = append(hosts, args[i])
+			}
+		case strings.HasPrefix(a, "--host="):
+			hosts = append(hosts, strings.TrimPrefix(a, "--host="))
+		case strings.HasPrefix(a, "-h") && len(a) > 2:
+			hosts = append(hosts, a[2:])
+		default:
+			for _, m := range conninfoHostRe.FindAllStringSubmatch(a, -1) {
+				hosts = append(hosts, m[1])
+			}
+		}
+	}
+	return hosts
+}
+
+// normalizeTargetHost strips the user and port from a target and lowercases
+// it. Anything that is not a plausible host yields "".
+func normalizeTargetHost(h string) string {
+	h = strings.TrimSpace(h)
+	if i := strings.LastIndex(h, "@"); i >= 0 {
+		h = h[i+1:]
+	}
+	if host, _, err := net.SplitHostPort(h); err == nil {
+		h = host
+	}
+	h = strings.Trim(strings.ToLower(h), "[]")
+	if h == "" || strings.ContainsAny(h, "$`{}()<>|;*? ") {
+		return ""
+	}
+	return h
+}
+
+func isLocalHost(h string) bool {
+	if h == "localhost" || strings.HasSuffix(h, ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(h)
+	return ip != nil && ip.IsLoopback()
+}
+
+// IsProductionHost reports whether a host matches one of the patterns.
+// Each pattern is a glob matched against the whole host and against each
+// of its dot- or slash-separated parts, so "prod" matches
+// db.prod.example.com and "*-prod" matches api-prod.example.com.
+func IsProductionHost(host string, patterns []string) bool {
+	host = strings.ToLower(host)
+	labels := strings.FieldsFunc(host, func(r rune) bool { return r == '.' || r == '/' })
+	for _, p := range patterns {
+		p = strings.ToLower(strings.TrimSpace(p))
+		if p == "" {
+			continue
+		}
+		if ok, _ := path.Match(p, host); ok {
+			return true
+		}
+		for _, l := range labels {
+			if ok, _ := path.Match(p, l); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MarkProductionTargets sets Production on each target whose host matches
+// a pattern and reports whether any did.
+func MarkProductionTargets(targets []Target, patterns []string) bool {
+	found := false
+	for i := range targets {
+		targets[i].Production = IsProductionHost(targets[i].Host, patterns)
+		found = found || targets[i].Production
+	}
+	return found
+}
+
+// ApplyTargets raises the tier of a command aimed at a production host one
+// step and notes which hosts, the way ApplyAnomaly does: a command that
+// needs approval moves up a tier and an unmatched one becomes caution.
+// Safe commands are left alone. res is not modified; a copy is returned
+// when anything changes.
+func ApplyTargets(res *MatchResult, targets []Target) *MatchResult {
+	if res == nil || res.IsSafe {
+		return res
+	}
+	var prod []string
+	for _, t := range targets {
+		if t.Production {
+			prod = append(prod, fmt.Sprintf("%s (%s)", t.Host, t.Via))
+		}
+	}
+	if len(prod) == 0 {
+		return res
+	}
+	out := *res
+	out.Explanation = append(append([]string(nil), res.Explanation...),
+		"targets production host: "+strings.Join(prod, ", "))
+	raised := RiskTierCaution
+	if res.NeedsApproval {
+		raised = upgradeTier(res.Tier)
+	}
+	if tierRank(raised) > tierRank(res.Tier) {
+		out.Explanation = append(out.Explanation, fmt.Sprintf("tier raised from %s to %s: production target", tierLabel(res.Tier), raised))
+		out.Tier = raised
+		out.MinApprovals = tierApprovals(raised)
+	}
+	out.NeedsApproval = true
+	return &out
+}
+
+// TargetsAttachment returns a command's targets as a context attachment for
+// reviewers, one "host (via)" line each, or nil when there are none.
+func TargetsAttachment(targets []Target) *db.Attachment {
+	if len(targets) == 0 {
+		return nil
+	}
+	lines := make([]string, 0, len(targets))
+	production := false
+	for _, t := range targets {
+		line := fmt.Sprintf("%s (%s)", t.Host, t.Via)
+		if t.Production {
+			line += " PRODUCTION"
+			production = true
+		}
+		lines = append(lines, line)
+	}
+	return &db.Attachment{
+		Type:    db.AttachmentTypeContext,
+		Content: strings.Join(lines, "\n"),
+		Metadata: map[string]any{
+			"type":       "targets",
+			"production": production,
+		},
+	}
+}
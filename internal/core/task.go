@@ -0,0 +1,127 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// ErrTaskNotFound is returned when no request carries a task's label.
+var ErrTaskNotFound = errors.New("no requests found for task")
+
+// Task roll-up statuses, in the order they take precedence: one failed
+// execution makes the whole task failed, one request awaiting review makes
+// it pending, and so on.
+const (
+	TaskFailed     = "failed"      // a request failed or timed out executing
+	TaskPending    = "pending"     // a request awaits review
+	TaskInProgress = "in_progress" // a request is approved or executing
+	TaskRejected   = "rejected"    // a request was rejected; the rest are done
+	TaskDone       = "done"        // every request that was not withdrawn executed
+	TaskCancelled  = "cancelled"   // every request was cancelled or expired
+)
+
+// TaskRollup sums up a task from how many of its requests are in each
+// status.
+func TaskRollup(counts map[db.RequestStatus]int) string {
+	has := func(statuses ...db.RequestStatus) bool {
+		for _, s := range statuses {
+			if counts[s] > 0 {
+				return true
+			}
+		}
+		return false
+	}
+	switch {
+	case has(db.StatusExecutionFailed, db.StatusTimedOut):
+		return TaskFailed
+	case has(db.StatusPending, db.StatusEscalated):
+		return TaskPending
+	case has(db.StatusApproved, db.StatusExecuting):
+		return TaskInProgress
+	case has(db.StatusRejected):
+		return TaskRejected
+	case has(db.StatusExecuted):
+		return TaskDone
+	default:
+		return TaskCancelled
+	}
+}
+
+// Task is a group of requests labeled with the same task ID, with their
+// combined status and timeline.
+type Task struct {
+	ID     string
+	Status string
+	Counts map[db.RequestStatus]int
+	// Requests are the task's requests, oldest first.
+	Requests []*db.Request
+	// Timeline is every member request's events, in the order they
+	// happened.
+	Timeline []*db.RequestEvent
+}
+
+// LoadTask gathers the requests labeled task=id, in projectPath or, when it
+// is empty, in every project.
+func LoadTask(database *db.DB, projectPath, id string) (*Task, error) {
+	if err := db.ValidateLabel(db.TaskLabel, id); err != nil || id == "" {
+		return nil, fmt.Errorf("invalid task id %q", id)
+	}
+	query := db.RequestPageQuery{
+		ProjectPath: projectPath,
+		Labels:      []db.LabelFilter{{Key: db.TaskLabel, Value: id}},
+	}
+	task := &Task{ID: id, Counts: make(map[db.RequestStatus]int)}
+	for {
+		page, next, err := database.ListRequestsPage(query)
+		if err != nil {
+			return nil, fmt.Errorf("listing task requests: %w", err)
+		}
+		task.Requests = append(task.Requests, page...)
+		if next == nil {
+			break
+		}
+		query.After = next
+	}
+	if len(task.Requests) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrTaskNotFound, id)
+	}
+	if err := database.LoadRequestLabels(task.Requests); err != nil {
+		return nil, err
+	}
+
+	// firstEvent orders requests created within the same second, since
+	// event IDs follow the order things happened.
+	firstEvent := make(map[string]int64, len(task.Requests))
+	for _, r := range task.Requests {
+		task.Counts[r.Status]++
+		events, err := database.ListRequestEvents(r.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(events) > 0 {
+			firstEvent[r.ID] = events[0].ID
+		}
+		task.Timeline = append(task.Timeline, events...)
+	}
+
+	// Pages come newest first; a task reads in the order it was worked.
+	sort.SliceStable(task.Requests, func(i, j int) bool {
+		a, b := task.Requests[i], task.Requests[j]
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return firstEvent[a.ID] < firstEvent[b.ID]
+	})
+	sort.SliceStable(task.Timeline, func(i, j int) bool {
+		a, b := task.Timeline[i], task.Timeline[j]
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID < b.ID
+	})
+	task.Status = TaskRollup(task.Counts)
+	return task, nil
+}
@@ -0,0 +1,95 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestTaskRollup(t *testing.T) {
+	tests := []struct {
+		counts map[db.RequestStatus]int
+		want   string
+	}{
+		{map[db.RequestStatus]int{db.StatusExecuted: 3, db.StatusExecutionFailed: 1, db.StatusPending: 1}, TaskFailed},
+		{map[db.RequestStatus]int{db.StatusExecuted: 2, db.StatusTimedOut: 1}, TaskFailed},
+		{map[db.RequestStatus]int{db.StatusExecuted: 2, db.StatusEscalated: 1, db.StatusRejected: 1}, TaskPending},
+		{map[db.RequestStatus]int{db.StatusExecuted: 1, db.StatusApproved: 1}, TaskInProgress},
+		{map[db.RequestStatus]int{db.StatusExecuted: 1, db.StatusRejected: 1}, TaskRejected},
+		{map[db.RequestStatus]int{db.StatusExecuted: 2, db.StatusCancelled: 1}, TaskDone},
+		{map[db.RequestStatus]int{db.StatusCancelled: 1, db.StatusTimeout: 1}, TaskCancelled},
+	}
+	for _, tc := range tests {
+		if got := TaskRollup(tc.counts); got != tc.want {
+			t.Errorf("TaskRollup(%v) = %q, want %q", tc.counts, got, tc.want)
+		}
+	}
+}
+
+func TestLoadTask(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	sess := testutil.MakeSession(t, database, testutil.WithProject("/proj"), testutil.WithAgent("Migrator"))
+
+	task := map[string]string{db.TaskLabel: "OPS-123"}
+	first := testutil.MakeRequest(t, database, sess,
+		testutil.WithCommand("psql -f 001_schema.sql", "/proj", false),
+		testutil.WithStatus(db.StatusExecuted),
+		testutil.WithLabels(task))
+	second := testutil.MakeRequest(t, database, sess,
+		testutil.WithCommand("psql -f 002_backfill.sql", "/proj", false),
+		testutil.WithLabels(task))
+	testutil.MakeRequest(t, database, sess,
+		testutil.WithCommand("rm -rf ./build", "/proj", false),
+		testutil.WithLabels(map[string]string{db.TaskLabel: "OPS-999"}))
+
+	// Creating a request records its created event; the first request's
+	// execution comes after both, so the timeline has to interleave them.
+	executed := &db.RequestEvent{RequestID: first.ID, Type: string(db.StatusExecuted), Actor: "Migrator"}
+	if err := database.RecordRequestEvent(executed); err != nil {
+		t.Fatalf("RecordRequestEvent: %v", err)
+	}
+
+	got, err := LoadTask(database, "/proj", "OPS-123")
+	if err != nil {
+		t.Fatalf("LoadTask: %v", err)
+	}
+	if got.Status != TaskPending {
+		t.Errorf("Status = %q, want %q", got.Status, TaskPending)
+	}
+	if len(got.Requests) != 2 || got.Requests[0].ID != first.ID || got.Requests[1].ID != second.ID {
+		t.Fatalf("Requests = %v, want [%s %s] oldest first", requestIDs(got.Requests), first.ID, second.ID)
+	}
+	if got.Counts[db.StatusExecuted] != 1 || got.Counts[db.StatusPending] != 1 {
+		t.Errorf("Counts = %v", got.Counts)
+	}
+	want := []struct{ request, typ string }{
+		{first.ID, db.RequestEventCreated},
+		{second.ID, db.RequestEventCreated},
+		{first.ID, string(db.StatusExecuted)},
+	}
+	if len(got.Timeline) != len(want) {
+		t.Fatalf("Timeline has %d events, want %d", len(got.Timeline), len(want))
+	}
+	for i, e := range got.Timeline {
+		if e.RequestID != want[i].request || e.Type != want[i].typ {
+			t.Errorf("Timeline[%d] = %s %s, want %s %s", i, e.RequestID, e.Type, want[i].request, want[i].typ)
+		}
+	}
+
+	if _, err := LoadTask(database, "/other", "OPS-123"); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("LoadTask in another project: err = %v, want ErrTaskNotFound", err)
+	}
+	if _, err := LoadTask(database, "", "bad\nid"); err == nil {
+		t.Error("expected an invalid task id to be refused")
+	}
+}
+
+func requestIDs(requests []*db.Request) []string {
+	ids := make([]string, len(requests))
+	for i, r := range requests {
+		ids[i] = r.ID
+	}
+	return ids
+}
@@ -0,0 +1,181 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ErrTemplateVarsUnacknowledged is returned when approving a command with
+// unexpanded template variables without acknowledging exactly that set.
+var ErrTemplateVarsUnacknowledged = errors.New("command has unexpanded template variables")
+
+var (
+	// shellVarRe matches $NAME and ${NAME...}.
+	shellVarRe = regexp.MustCompile(`\$(?:\{([A-Za-z_][A-Za-z0-9_]*)[^}]*\}|([A-Za-z_][A-Za-z0-9_]*))`)
+	// mustacheVarRe matches {{name}}. Go template actions such as
+	// {{.Names}} or {{json .}} (docker and kubectl --format) are concrete
+	// and do not match.
+	mustacheVarRe = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_-]*)\s*\}\}`)
+	// assignmentRe matches a NAME=value word; loopVarRe a for loop's
+	// variable.
+	assignmentRe = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)=`)
+	loopVarRe    = regexp.MustCompile(`\bfor\s+([A-Za-z_][A-Za-z0-9_]*)\s+in\b`)
+)
+
+// wellKnownShellVars are set in every shell session and are not
+// placeholders a reviewer needs to see filled in.
+var wellKnownShellVars = map[string]bool{
+	"HOME": true, "USER": true, "LOGNAME": true, "PWD": true, "OLDPWD": true,
+	"PATH": true, "SHELL": true, "TMPDIR": true, "HOSTNAME": true,
+	"UID": true, "EUID": true, "PPID": true, "RANDOM": true, "IFS": true,
+}
+
+// TemplateVars returns the placeholders in a command that will only be
+// filled in when it runs: shell variables ($TARGET, ${TARGET}) the command
+// does not set itself, and {{name}} template slots. Shell variables inside
+// single quotes are literal and are skipped, as are well-known session
+// variables such as $HOME. Names are returned sorted, as "$TARGET" or
+// "{{env}}".
+func TemplateVars(command string) []string {
+	seen := make(map[string]bool)
+	for _, m := range mustacheVarRe.FindAllStringSubmatch(command, -1) {
+		seen["{{"+m[1]+"}}"] = true
+	}
+
+	expandable := blankSingleQuoted(command)
+	defined := assignedVars(expandable)
+	for _, m := range loopVarRe.FindAllStringSubmatch(expandable, -1) {
+		defined[m[1]] = true
+	}
+	for _, m := range shellVarRe.FindAllStringSubmatch(expandable, -1) {
+		name := m[1] + m[2]
+		if !defined[name] && !wellKnownShellVars[name] {
+			seen["$"+name] = true
+		}
+	}
+
+	vars := make([]string, 0, len(seen))
+	for v := range seen {
+		vars = append(vars, v)
+	}
+	sort.Strings(vars)
+	return vars
+}
+
+// assignedVars returns the variables a command assigns: NAME=value words
+// at the start of each simple command, optionally after export, local or
+// readonly. An argument such as `env TARGET=x` is not an assignment.
+func assignedVars(command string) map[string]bool {
+	defined := make(map[string]bool)
+	segments := strings.FieldsFunc(command, func(r rune) bool {
+		return strings.ContainsRune(";&|()\n", r)
+	})
+	for _, seg := range segments {
+		words := strings.Fields(seg)
+		if len(words) > 0 && (words[0] == "export" || words[0] == "local" || words[0] == "readonly") {
+			words = words[1:]
+		}
+		for _, w := range words {
+			m := assignmentRe.FindStringSubmatch(w)
+			if m == nil {
+				break
+			}
+			defined[m[1]] = true
+		}
+	}
+	return defined
+}
+
+// blankSingleQuoted replaces single-quoted spans (outside double quotes)
+// and backslash-escaped characters with spaces, leaving only the text a
+// shell would expand variables in.
+func blankSingleQuoted(s string) string {
+	out := []byte(s)
+	inSingle, inDouble := false, false
+	for i := 0; i < len(out); i++ {
+		c := out[i]
+		switch {
+		case inSingle:
+			if c == '\'' {
+				inSingle = false
+			}
+			out[i] = ' '
+		case c == '\\' && i+1 < len(out):
+			out[i], out[i+1] = ' ', ' '
+			i++
+		case c == '"':
+			inDouble = !inDouble
+		case c == '\'' && !inDouble:
+			inSingle = true
+			out[i] = ' '
+		}
+	}
+	return string(out)
+}
+
+// CheckTemplateVarsAcknowledged verifies that acknowledged names exactly the
+// template variables in command. Names may be given bare (TARGET, env) or
+// as TemplateVars reports them ($TARGET, {{env}}).
+func CheckTemplateVarsAcknowledged(command string, acknowledged []string) error {
+	vars := TemplateVars(command)
+	if len(vars) == 0 {
+		return nil
+	}
+	acked := make(map[string]bool, len(acknowledged))
+	for _, a := range acknowledged {
+		if name := templateVarName(a); name != "" {
+			acked[name] = true
+		}
+	}
+	want := make(map[string]bool, len(vars))
+	var missing []string
+	for _, v := range vars {
+		name := templateVarName(v)
+		want[name] = true
+		if !acked[name] {
+			missing = append(missing, v)
+		}
+	}
+	var extra []string
+	for name := range acked {
+		if !want[name] {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+
+	switch {
+	case len(acknowledged) == 0:
+		names := make([]string, 0, len(want))
+		for name := range want {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("%w: %s; have the requestor resubmit it with concrete values, or acknowledge them with --ack-vars %s",
+			ErrTemplateVarsUnacknowledged, strings.Join(vars, ", "), strings.Join(names, ","))
+	case len(missing) > 0:
+		return fmt.Errorf("%w: not acknowledged: %s", ErrTemplateVarsUnacknowledged, strings.Join(missing, ", "))
+	case len(extra) > 0:
+		return fmt.Errorf("%w: acknowledged %s, which the command does not use (it uses %s)",
+			ErrTemplateVarsUnacknowledged, strings.Join(extra, ", "), strings.Join(vars, ", "))
+	}
+	return nil
+}
+
+// templateVarName strips the $, ${...} or {{...}} decoration from a
+// variable name.
+func templateVarName(v string) string {
+	v = strings.TrimSpace(v)
+	switch {
+	case strings.HasPrefix(v, "{{") && strings.HasSuffix(v, "}}"):
+		v = strings.TrimSpace(v[2 : len(v)-2])
+	case strings.HasPrefix(v, "${") && strings.HasSuffix(v, "}"):
+		v = v[2 : len(v)-1]
+	default:
+		v = strings.TrimPrefix(v, "$")
+	}
+	return v
+}
@@ -0,0 +1,129 @@
+package core
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestTemplateVars(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []string
+	}{
+		{"rm -rf ./build", nil},
+		{"rm -rf $TARGET", []string{"$TARGET"}},
+		{"kubectl delete ns ${NAMESPACE} --context {{env}}", []string{"$NAMESPACE", "{{env}}"}},
+		{"helm upgrade app ./chart -f values-{{ env }}.yaml", []string{"{{env}}"}},
+		{`psql "$DATABASE_URL" -c 'DROP TABLE users'`, []string{"$DATABASE_URL"}},
+		{"${TARGET:-/tmp} && rm -rf $TARGET", []string{"$TARGET"}},
+		// Single-quoted, escaped and special variables are not templates.
+		{`awk '{print $NF}' log.txt`, nil},
+		{`echo \$HOME_DIR $1 $? $$`, nil},
+		// Well-known session variables and variables the command sets.
+		{"rm -rf $HOME/.cache $TMPDIR/x", nil},
+		{"DIR=./build; rm -rf $DIR", nil},
+		{"export DIR=./out && rm -rf ${DIR}", nil},
+		{"for f in *.log; do rm $f; done", nil},
+		{"kubectl set env deploy/api TARGET=$TARGET", []string{"$TARGET"}},
+		// Go template actions in --format are concrete.
+		{"docker ps --format '{{.Names}}' | xargs docker rm -f", nil},
+		{"docker inspect -f '{{json .State}}' db", nil},
+	}
+	for _, tt := range tests {
+		got := TemplateVars(tt.command)
+		if len(got) == 0 && len(tt.want) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("TemplateVars(%q) = %v, want %v", tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestCheckTemplateVarsAcknowledged(t *testing.T) {
+	const command = "kubectl delete ns $NAMESPACE --context {{env}}"
+	tests := []struct {
+		name  string
+		acked []string
+		ok    bool
+	}{
+		{"none", nil, false},
+		{"partial", []string{"NAMESPACE"}, false},
+		{"extra", []string{"NAMESPACE", "env", "REGION"}, false},
+		{"bare names", []string{"NAMESPACE", "env"}, true},
+		{"decorated names", []string{"${NAMESPACE}", "{{ env }}"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckTemplateVarsAcknowledged(command, tt.acked)
+			if tt.ok && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !tt.ok && !errors.Is(err, ErrTemplateVarsUnacknowledged) {
+				t.Errorf("expected ErrTemplateVarsUnacknowledged, got %v", err)
+			}
+		})
+	}
+	if err := CheckTemplateVarsAcknowledged("rm -rf ./build", nil); err != nil {
+		t.Errorf("concrete command: %v", err)
+	}
+}
+
+func TestSubmitReview_TemplateVarsNeedAcknowledgment(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	requestor := testutil.MakeSession(t, database, testutil.WithAgent("Requestor"))
+	reviewer := testutil.MakeSession(t, database, testutil.WithAgent("Reviewer"), testutil.WithProject(requestor.ProjectPath))
+	rs := NewReviewService(database, DefaultReviewConfig())
+
+	templated := testutil.MakeRequest(t, database, requestor,
+		testutil.WithCommand("rm -rf $TARGET", requestor.ProjectPath, true))
+	review := func(req *db.Request, decision db.Decision, acked ...string) error {
+		_, err := rs.SubmitReview(ReviewOptions{
+			SessionID:        reviewer.ID,
+			SessionKey:       reviewer.SessionKey,
+			RequestID:        req.ID,
+			Decision:         decision,
+			AcknowledgedVars: acked,
+		})
+		return err
+	}
+
+	if err := review(templated, db.DecisionApprove); !errors.Is(err, ErrTemplateVarsUnacknowledged) {
+		t.Fatalf("approving without acknowledgment: %v", err)
+	}
+	if err := review(templated, db.DecisionApprove, "TARGET"); err != nil {
+		t.Fatalf("approving with acknowledgment: %v", err)
+	}
+
+	// Rejections never need an acknowledgment.
+	rejected := testutil.MakeRequest(t, database, requestor,
+		testutil.WithCommand("rm -rf {{dir}}", requestor.ProjectPath, true))
+	if err := review(rejected, db.DecisionReject); err != nil {
+		t.Fatalf("rejecting: %v", err)
+	}
+
+	// A proposed edit is the expanded command; only variables left in it
+	// need acknowledging.
+	edited := testutil.MakeRequest(t, database, requestor,
+		testutil.WithCommand("rm -rf $TARGET", requestor.ProjectPath, true))
+	propose := func(command string, acked ...string) error {
+		_, err := rs.ProposeEdit(ProposeEditOptions{
+			SessionID:        reviewer.ID,
+			SessionKey:       reviewer.SessionKey,
+			RequestID:        edited.ID,
+			Command:          command,
+			AcknowledgedVars: acked,
+		})
+		return err
+	}
+	if err := propose("rm -rf $TARGET/cache"); !errors.Is(err, ErrTemplateVarsUnacknowledged) {
+		t.Errorf("edit keeping a variable: %v", err)
+	}
+	if err := propose("rm -rf ./build"); err != nil {
+		t.Errorf("concrete edit: %v", err)
+	}
+}
@@ -0,0 +1,218 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// TierOverride adjusts the classification of commands from the agents it
+// selects, after the pattern engine has run.
+type TierOverride struct {
+	// Entry is the configured text, quoted in explanations.
+	Entry string
+	// Program and Model are case-insensitive globs (path.Match syntax)
+	// matched against the requesting session; empty matches anything.
+	Program string
+	Model   string
+	// Branch is a glob matched against the git branch checked out where
+	// the command runs; empty matches anything, and a command outside a
+	// branch never matches a non-empty glob.
+	Branch string
+	// Stage is a glob matched against the stage a deployment runner
+	// deploys to (see DeploymentStage); empty matches anything, and a
+	// command that deploys nowhere never matches a non-empty glob.
+	Stage string
+	// MinTier raises the tier of a command that needs approval to at
+	// least this tier.
+	MinTier RiskTier
+	// SkipCaution lets caution-tier commands through without a request.
+	SkipCaution bool
+}
+
+// skipCautionAction is the override action that skips caution tracking.
+const skipCautionAction = "skip_caution"
+
+// ParseTierOverride parses an agents.tier_overrides entry of the form
+// SELECTOR=ACTION. SELECTOR is a program glob, optionally written
+// "program:GLOB", "model:GLOB", "branch:GLOB" or "stage:GLOB"; ACTION is a
+// minimum tier (caution, dangerous, critical) or skip_caution. For example
+// "codex-cli=dangerous", "model:gpt-4o*=critical", "branch:main=critical"
+// or "stage:prod*=critical".
+func ParseTierOverride(entry string) (TierOverride, error) {
+	selector, action, ok := strings.Cut(strings.TrimSpace(entry), "=")
+	selector = strings.TrimSpace(selector)
+	action = strings.ToLower(strings.TrimSpace(action))
+	if !ok || selector == "" || action == "" {
+		return TierOverride{}, fmt.Errorf("tier override %q: want SELECTOR=ACTION", entry)
+	}
+
+	o := TierOverride{Entry: strings.TrimSpace(entry)}
+	switch kind, glob, hasKind := strings.Cut(selector, ":"); {
+	case hasKind && strings.EqualFold(kind, "model"):
+		o.Model = strings.ToLower(strings.TrimSpace(glob))
+	case hasKind && strings.EqualFold(kind, "program"):
+		o.Program = strings.ToLower(strings.TrimSpace(glob))
+	case hasKind && strings.EqualFold(kind, "branch"):
+		o.Branch = strings.ToLower(strings.TrimSpace(glob))
+	case hasKind && strings.EqualFold(kind, "stage"):
+		o.Stage = strings.ToLower(strings.TrimSpace(glob))
+	case hasKind:
+		return TierOverride{}, fmt.Errorf("tier override %q: unknown selector %q (want program:, model:, branch: or stage:)", entry, kind)
+	default:
+		o.Program = strings.ToLower(selector)
+	}
+	sel := o.Program + o.Model + o.Branch + o.Stage
+	if _, err := path.Match(sel, ""); err != nil || sel == "" {
+		return TierOverride{}, fmt.Errorf("tier override %q: invalid selector", entry)
+	}
+
+	switch action {
+	case skipCautionAction:
+		o.SkipCaution = true
+	case string(RiskTierCaution), string(RiskTierDangerous), string(RiskTierCritical):
+		o.MinTier = RiskTier(action)
+	default:
+		return TierOverride{}, fmt.Errorf("tier override %q: unknown action %q (want a tier or %s)", entry, action, skipCautionAction)
+	}
+	return o, nil
+}
+
+// ParseTierOverrides parses every entry, returning the valid ones and the
+// first error.
+func ParseTierOverrides(entries []string) ([]TierOverride, error) {
+	var overrides []TierOverride
+	var firstErr error
+	for _, entry := range entries {
+		o, err := ParseTierOverride(entry)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		overrides = append(overrides, o)
+	}
+	return overrides, firstErr
+}
+
+// OverrideSubject is what tier overrides select on: the requesting
+// session's program and model, the branch the command runs on and the
+// stage it deploys to.
+type OverrideSubject struct {
+	Program string
+	Model   string
+	Branch  string
+	Stage   string
+}
+
+// Matches reports whether the override selects a subject.
+func (o TierOverride) Matches(s OverrideSubject) bool {
+	return globMatch(o.Program, s.Program) && globMatch(o.Model, s.Model) &&
+		globMatch(o.Branch, s.Branch) && globMatch(o.Stage, s.Stage)
+}
+
+// OverrideSubjectFor builds the subject for a session's program and model
+// running cmd in cwd. The branch and stage are only looked up when an
+// override selects on them.
+func OverrideSubjectFor(overrides []TierOverride, program, model, cmd, cwd string) OverrideSubject {
+	s := OverrideSubject{Program: program, Model: model}
+	var branch, stage bool
+	for _, o := range overrides {
+		branch = branch || o.Branch != ""
+		stage = stage || o.Stage != ""
+	}
+	if branch {
+		s.Branch = CurrentBranch(cwd)
+	}
+	if stage {
+		s.Stage = DeploymentStage(cmd)
+	}
+	return s
+}
+
+// CurrentBranch returns the git branch checked out in dir, or "" when dir
+// is not in a repository or HEAD is detached.
+func CurrentBranch(dir string) string {
+	if dir == "" {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), gitRewriteTimeout)
+	defer cancel()
+	out, err := runCmdString(ctx, dir, "git", "symbolic-ref", "--short", "-q", "HEAD")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(out)
+}
+
+func globMatch(glob, value string) bool {
+	if glob == "" {
+		return true
+	}
+	ok, _ := path.Match(glob, strings.ToLower(strings.TrimSpace(value)))
+	return ok
+}
+
+// ApplyTierOverrides adjusts a classification for the overrides selecting
+// subject and notes each change in the explanation. Only commands that
+// need approval are adjusted: an override never turns an unmatched or safe
+// command into a request. res is not modified; a copy is returned when
+// anything changes.
+func ApplyTierOverrides(res *MatchResult, overrides []TierOverride, subject OverrideSubject) *MatchResult {
+	if res == nil || !res.NeedsApproval || len(overrides) == 0 {
+		return res
+	}
+	var matched []TierOverride
+	for _, o := range overrides {
+		if o.Matches(subject) {
+			matched = append(matched, o)
+		}
+	}
+	if len(matched) == 0 {
+		return res
+	}
+
+	out := *res
+	out.Explanation = append([]string(nil), res.Explanation...)
+	changed := false
+	for _, o := range matched {
+		if o.MinTier != "" && tierRank(out.Tier) < tierRank(o.MinTier) {
+			out.Explanation = append(out.Explanation,
+				fmt.Sprintf("tier raised from %s to %s by tier override %q", out.Tier, o.MinTier, o.Entry))
+			out.Tier = o.MinTier
+			out.MinApprovals = tierApprovals(o.MinTier)
+			changed = true
+		}
+	}
+	if out.Tier == RiskTierCaution {
+		for _, o := range matched {
+			if o.SkipCaution {
+				out.Explanation = append(out.Explanation,
+					fmt.Sprintf("caution tier not tracked by tier override %q", o.Entry))
+				out.NeedsApproval = false
+				changed = true
+				break
+			}
+		}
+	}
+	if !changed {
+		return res
+	}
+	return &out
+}
+
+// tierRank orders the approval tiers; anything else ranks lowest.
+func tierRank(t RiskTier) int {
+	switch t {
+	case RiskTierCritical:
+		return 3
+	case RiskTierDangerous:
+		return 2
+	case RiskTierCaution:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,162 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestParseTierOverride(t *testing.T) {
+	tests := []struct {
+		entry string
+		want  TierOverride
+	}{
+		{"codex-cli=dangerous", TierOverride{Program: "codex-cli", MinTier: RiskTierDangerous}},
+		{" Program:Codex* = Critical ", TierOverride{Program: "codex*", MinTier: RiskTierCritical}},
+		{"model:gpt-4o*=critical", TierOverride{Model: "gpt-4o*", MinTier: RiskTierCritical}},
+		{"shell=skip_caution", TierOverride{Program: "shell", SkipCaution: true}},
+		{"branch:release/*=critical", TierOverride{Branch: "release/*", MinTier: RiskTierCritical}},
+	}
+	for _, tt := range tests {
+		got, err := ParseTierOverride(tt.entry)
+		if err != nil {
+			t.Errorf("ParseTierOverride(%q): %v", tt.entry, err)
+			continue
+		}
+		got.Entry = ""
+		if got != tt.want {
+			t.Errorf("ParseTierOverride(%q) = %+v, want %+v", tt.entry, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"codex-cli", "=dangerous", "codex-cli=", "codex-cli=safe", "host:ci=dangerous", "model:=critical", "[=dangerous"} {
+		if _, err := ParseTierOverride(bad); err == nil {
+			t.Errorf("ParseTierOverride(%q) should fail", bad)
+		}
+	}
+
+	overrides, err := ParseTierOverrides([]string{"codex-cli=dangerous", "bogus", "shell=skip_caution"})
+	if err == nil || len(overrides) != 2 {
+		t.Errorf("ParseTierOverrides = %d overrides, %v; want 2 and an error", len(overrides), err)
+	}
+}
+
+func TestApplyTierOverrides(t *testing.T) {
+	overrides, err := ParseTierOverrides([]string{
+		"codex-cli=dangerous",
+		"model:gpt-4o*=critical",
+		"shell=skip_caution",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	caution := &MatchResult{Tier: RiskTierCaution, MatchedPattern: `^rm\s+[^-]`, NeedsApproval: true, Explanation: []string{"earlier note"}}
+
+	got := ApplyTierOverrides(caution, overrides, OverrideSubject{Program: "Codex-CLI", Model: "o3"})
+	if got.Tier != RiskTierDangerous || got.MinApprovals != 1 {
+		t.Errorf("codex-cli: tier %s, approvals %d", got.Tier, got.MinApprovals)
+	}
+	if len(got.Explanation) != 2 || !strings.Contains(got.Explanation[1], `"codex-cli=dangerous"`) {
+		t.Errorf("explanation = %q", got.Explanation)
+	}
+	if caution.Tier != RiskTierCaution || len(caution.Explanation) != 1 {
+		t.Error("the input classification was modified")
+	}
+
+	// The highest matching tier wins.
+	if got := ApplyTierOverrides(caution, overrides, OverrideSubject{Program: "codex-cli", Model: "gpt-4o-mini"}); got.Tier != RiskTierCritical || got.MinApprovals != 2 {
+		t.Errorf("codex-cli on gpt-4o: tier %s, approvals %d", got.Tier, got.MinApprovals)
+	}
+
+	got = ApplyTierOverrides(caution, overrides, OverrideSubject{Program: "shell"})
+	if got.NeedsApproval || got.Tier != RiskTierCaution {
+		t.Errorf("shell: %+v, want caution not tracked", got)
+	}
+
+	// Overrides never create requests or lower a tier.
+	unmatched := &MatchResult{}
+	if got := ApplyTierOverrides(unmatched, overrides, OverrideSubject{Program: "codex-cli"}); got != unmatched {
+		t.Errorf("unmatched command changed: %+v", got)
+	}
+	critical := &MatchResult{Tier: RiskTierCritical, NeedsApproval: true, MinApprovals: 2}
+	if got := ApplyTierOverrides(critical, overrides, OverrideSubject{Program: "codex-cli"}); got != critical {
+		t.Errorf("critical command changed: %+v", got)
+	}
+	dangerous := &MatchResult{Tier: RiskTierDangerous, NeedsApproval: true, MinApprovals: 1}
+	if got := ApplyTierOverrides(dangerous, overrides, OverrideSubject{Program: "shell"}); got != dangerous {
+		t.Errorf("skip_caution changed a dangerous command: %+v", got)
+	}
+	if got := ApplyTierOverrides(caution, overrides, OverrideSubject{Program: "claude-code", Model: "opus"}); got != caution {
+		t.Errorf("unselected agent changed: %+v", got)
+	}
+}
+
+func TestApplyTierOverrides_Branch(t *testing.T) {
+	overrides, err := ParseTierOverrides([]string{"branch:main=critical", "branch:release/*=dangerous"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	caution := &MatchResult{Tier: RiskTierCaution, NeedsApproval: true}
+
+	if got := ApplyTierOverrides(caution, overrides, OverrideSubject{Program: "codex-cli", Branch: "main"}); got.Tier != RiskTierCritical {
+		t.Errorf("main: tier %s", got.Tier)
+	}
+	if got := ApplyTierOverrides(caution, overrides, OverrideSubject{Branch: "release/1.2"}); got.Tier != RiskTierDangerous {
+		t.Errorf("release/1.2: tier %s", got.Tier)
+	}
+	// Outside a branch (no repository, detached HEAD) nothing matches.
+	if got := ApplyTierOverrides(caution, overrides, OverrideSubject{}); got != caution {
+		t.Errorf("no branch changed: %+v", got)
+	}
+
+	if s := OverrideSubjectFor(nil, "codex-cli", "o3", "ls", t.TempDir()); s != (OverrideSubject{Program: "codex-cli", Model: "o3"}) {
+		t.Errorf("OverrideSubjectFor without branch overrides = %+v", s)
+	}
+	if s := OverrideSubjectFor(overrides, "codex-cli", "o3", "ls", t.TempDir()); s.Branch != "" {
+		t.Errorf("branch outside a repository = %q", s.Branch)
+	}
+	if s := OverrideSubjectFor(overrides, "codex-cli", "o3", "ls", setupRewriteRepo(t)); s.Branch != "main" {
+		t.Errorf("branch in a repository on main = %q", s.Branch)
+	}
+}
+
+func TestCreateRequest_TierOverrides(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	codex := testutil.MakeSession(t, database, testutil.SessionWithAgentName("codex-agent"), testutil.WithProgram("codex-cli"))
+	human := testutil.MakeSession(t, database, testutil.SessionWithAgentName("human"), testutil.WithProgram("shell"))
+	config := DefaultRequestCreatorConfig()
+	config.AgentMailEnabled = false
+	config.TierOverrides, _ = ParseTierOverrides([]string{"codex-cli=dangerous", "shell=skip_caution"})
+	creator := NewRequestCreator(database, nil, nil, config)
+
+	result, err := creator.CreateRequest(context.Background(), CreateRequestOptions{
+		SessionID:     codex.ID,
+		Command:       "rm notes.txt",
+		Cwd:           "/project",
+		Justification: Justification{Reason: "cleanup"},
+	})
+	if err != nil {
+		t.Fatalf("CreateRequest: %v", err)
+	}
+	if result.Skipped || result.Request.RiskTier != RiskTierDangerous || result.Request.MinApprovals != 1 {
+		t.Fatalf("codex request = %+v", result.Request)
+	}
+	if n := len(result.Classification.Explanation); n == 0 || !strings.Contains(result.Classification.Explanation[n-1], "codex-cli=dangerous") {
+		t.Errorf("explanation = %q", result.Classification.Explanation)
+	}
+
+	result, err = creator.CreateRequest(context.Background(), CreateRequestOptions{
+		SessionID:     human.ID,
+		Command:       "rm notes.txt",
+		Cwd:           "/project",
+		Justification: Justification{Reason: "cleanup"},
+	})
+	if err != nil {
+		t.Fatalf("CreateRequest: %v", err)
+	}
+	if !result.Skipped || !strings.Contains(result.SkipReason, "tier_overrides") {
+		t.Errorf("human caution command: skipped %v (%s)", result.Skipped, result.SkipReason)
+	}
+}
@@ -0,0 +1,193 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// transcriptReadWindow bounds how much of a (possibly huge) transcript file is read.
+const transcriptReadWindow = 256 * 1024
+
+// LoadTranscriptSnippet reads the tail of an agent transcript and returns it
+// as a compressed, redacted transcript attachment.
+//
+// Plain text files are used line by line. JSONL transcripts (one message per
+// line, as written by agent CLIs) are reduced to "role: text" lines, with tool
+// calls rendered as "role: [tool Name] input". The snippet keeps only the most
+// recent MaxTranscriptLines lines and MaxTranscriptBytes bytes.
+func LoadTranscriptSnippet(path string, config *AttachmentConfig) (*db.Attachment, error) {
+	if config == nil {
+		cfg := DefaultAttachmentConfig()
+		config = &cfg
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, &AttachmentError{
+			Type:    db.AttachmentTypeTranscript,
+			Path:    path,
+			Message: fmt.Sprintf("resolving path: %v", err),
+		}
+	}
+
+	raw, partial, err := readTail(absPath, transcriptReadWindow)
+	if err != nil {
+		return nil, &AttachmentError{
+			Type:    db.AttachmentTypeTranscript,
+			Path:    path,
+			Message: fmt.Sprintf("reading file: %v", err),
+		}
+	}
+
+	lines := strings.Split(strings.TrimRight(raw, "\n"), "\n")
+	if partial && len(lines) > 1 {
+		// The first line was cut by the read window.
+		lines = lines[1:]
+	}
+	if strings.EqualFold(filepath.Ext(absPath), ".jsonl") {
+		lines = transcriptMessages(lines)
+	}
+
+	truncated := partial
+	if limit := config.MaxTranscriptLines; limit > 0 && len(lines) > limit {
+		lines = lines[len(lines)-limit:]
+		truncated = true
+	}
+	for i, line := range lines {
+		lines[i] = ApplyRedaction(line, nil)
+	}
+	snippet := strings.Join(lines, "\n")
+	if limit := config.MaxTranscriptBytes; limit > 0 && len(snippet) > limit {
+		cut := len(snippet) - limit
+		for cut < len(snippet) && !utf8.RuneStart(snippet[cut]) {
+			cut++
+		}
+		snippet = snippet[cut:]
+		truncated = true
+	}
+
+	encoded, err := db.CompressAttachmentContent(snippet)
+	if err != nil {
+		return nil, &AttachmentError{
+			Type:    db.AttachmentTypeTranscript,
+			Path:    path,
+			Message: err.Error(),
+		}
+	}
+
+	meta := map[string]any{
+		"source":   absPath,
+		"filename": filepath.Base(absPath),
+		"encoding": db.AttachmentEncodingGzip,
+		"lines":    len(lines),
+		"size":     len(snippet),
+	}
+	if truncated {
+		meta["truncated"] = true
+	}
+
+	return &db.Attachment{
+		Type:     db.AttachmentTypeTranscript,
+		Content:  encoded,
+		Metadata: meta,
+	}, nil
+}
+
+// readTail reads at most window bytes from the end of a file.
+// partial reports whether earlier content was skipped.
+func readTail(path string, window int64) (string, bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", false, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", false, err
+	}
+	offset := int64(0)
+	if info.Size() > window {
+		offset = info.Size() - window
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return "", false, err
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return "", false, err
+	}
+	return string(data), offset > 0, nil
+}
+
+// transcriptMessages converts JSONL transcript entries into readable lines.
+// Entries that are not messages (or not JSON) are skipped.
+func transcriptMessages(entries []string) []string {
+	var out []string
+	for _, entry := range entries {
+		var rec struct {
+			Type    string `json:"type"`
+			Role    string `json:"role"`
+			Content any    `json:"content"`
+			Message *struct {
+				Role    string `json:"role"`
+				Content any    `json:"content"`
+			} `json:"message"`
+		}
+		if err := json.Unmarshal([]byte(entry), &rec); err != nil {
+			continue
+		}
+		role, content := rec.Role, rec.Content
+		if rec.Message != nil {
+			role, content = rec.Message.Role, rec.Message.Content
+		}
+		if role == "" {
+			role = rec.Type
+		}
+		for _, text := range transcriptContentText(content) {
+			text = strings.Join(strings.Fields(text), " ")
+			if text == "" {
+				continue
+			}
+			out = append(out, role+": "+text)
+		}
+	}
+	return out
+}
+
+// transcriptContentText extracts displayable text from a message content
+// value, which is either a string or a list of typed content blocks.
+func transcriptContentText(content any) []string {
+	switch c := content.(type) {
+	case string:
+		return []string{c}
+	case []any:
+		var texts []string
+		for _, block := range c {
+			b, ok := block.(map[string]any)
+			if !ok {
+				continue
+			}
+			switch b["type"] {
+			case "text":
+				if s, ok := b["text"].(string); ok {
+					texts = append(texts, s)
+				}
+			case "tool_use":
+				name, _ := b["name"].(string)
+				input, _ := json.Marshal(b["input"])
+				texts = append(texts, fmt.Sprintf("[tool %s] %s", name, input))
+			}
+		}
+		return texts
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,108 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+func TestLoadTranscriptSnippet_PlainTextCappedAndRedacted(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "transcript.txt")
+
+	var b strings.Builder
+	for i := 0; i < 100; i++ {
+		b.WriteString("assistant: step\n")
+	}
+	b.WriteString("user: deploy with --password=hunter2\n")
+	if err := os.WriteFile(path, []byte(b.String()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := DefaultAttachmentConfig()
+	cfg.MaxTranscriptLines = 5
+	att, err := LoadTranscriptSnippet(path, &cfg)
+	if err != nil {
+		t.Fatalf("LoadTranscriptSnippet: %v", err)
+	}
+	if att.Type != db.AttachmentTypeTranscript || att.Metadata["encoding"] != db.AttachmentEncodingGzip {
+		t.Fatalf("unexpected attachment: %+v", att.Metadata)
+	}
+	if att.Metadata["truncated"] != true {
+		t.Error("expected truncated metadata")
+	}
+
+	text, err := att.Text()
+	if err != nil {
+		t.Fatalf("Text: %v", err)
+	}
+	if n := len(strings.Split(text, "\n")); n != 5 {
+		t.Errorf("expected 5 lines, got %d:\n%s", n, text)
+	}
+	if strings.Contains(text, "hunter2") {
+		t.Errorf("expected secret to be redacted:\n%s", text)
+	}
+}
+
+func TestLoadTranscriptSnippet_JSONL(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "session.jsonl")
+	content := strings.Join([]string{
+		`{"type":"user","message":{"role":"user","content":"clean up the build output"}}`,
+		`{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"Removing build dir."},{"type":"tool_use","name":"Bash","input":{"command":"rm -rf ./build"}}]}}`,
+		`{"type":"summary","summary":"ignored"}`,
+		`not json`,
+	}, "\n")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	att, err := LoadTranscriptSnippet(path, nil)
+	if err != nil {
+		t.Fatalf("LoadTranscriptSnippet: %v", err)
+	}
+	text, err := att.Text()
+	if err != nil {
+		t.Fatalf("Text: %v", err)
+	}
+	for _, want := range []string{
+		"user: clean up the build output",
+		"assistant: Removing build dir.",
+		`assistant: [tool Bash] {"command":"rm -rf ./build"}`,
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("snippet missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "ignored") || strings.Contains(text, "not json") {
+		t.Errorf("expected non-message entries to be skipped:\n%s", text)
+	}
+}
+
+func TestLoadTranscriptSnippet_ByteCap(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "long.txt")
+	if err := os.WriteFile(path, []byte(strings.Repeat("é", 500)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := DefaultAttachmentConfig()
+	cfg.MaxTranscriptBytes = 101
+	att, err := LoadTranscriptSnippet(path, &cfg)
+	if err != nil {
+		t.Fatalf("LoadTranscriptSnippet: %v", err)
+	}
+	text, _ := att.Text()
+	if len(text) > 101 || !strings.HasPrefix(text, "é") {
+		t.Errorf("expected rune-aligned snippet of at most 101 bytes, got %d bytes", len(text))
+	}
+}
+
+func TestLoadTranscriptSnippet_MissingFile(t *testing.T) {
+	if _, err := LoadTranscriptSnippet(filepath.Join(t.TempDir(), "missing.txt"), nil); err == nil {
+		t.Fatal("expected error for missing file")
+	}
+}
@@ -0,0 +1,139 @@
+// Package core implements execution windows (quiet hours) for risky commands.
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// ErrExecutionWindowClosed is returned when a request is executed during a
+// restricted window without a human override.
+var ErrExecutionWindowClosed = errors.New("execution window closed")
+
+// ExecutionWindowPolicy restricts when approved requests of certain tiers may
+// run, e.g. no critical executions 22:00-06:00 or on weekends.
+type ExecutionWindowPolicy struct {
+	// QuietStart and QuietEnd are minutes after midnight. When equal, no
+	// quiet hours apply. A window may wrap past midnight (22:00-06:00).
+	QuietStart int
+	QuietEnd   int
+	// BlockWeekends restricts execution all day Saturday and Sunday.
+	BlockWeekends bool
+	// Tiers lists the risk tiers the restrictions apply to.
+	Tiers map[db.RiskTier]bool
+	// Location is the timezone used to evaluate windows (nil = local).
+	Location *time.Location
+}
+
+// NewExecutionWindowPolicy builds a policy from configuration values.
+// quietHours uses "HH:MM-HH:MM" and may be empty; timezone may be empty for local time.
+func NewExecutionWindowPolicy(quietHours string, blockWeekends bool, tiers []string, timezone string) (*ExecutionWindowPolicy, error) {
+	p := &ExecutionWindowPolicy{
+		BlockWeekends: blockWeekends,
+		Tiers:         make(map[db.RiskTier]bool),
+		Location:      time.Local,
+	}
+
+	if strings.TrimSpace(quietHours) != "" {
+		start, end, err := ParseQuietHours(quietHours)
+		if err != nil {
+			return nil, err
+		}
+		p.QuietStart, p.QuietEnd = start, end
+	}
+
+	for _, t := range tiers {
+		tier := db.RiskTier(strings.ToLower(strings.TrimSpace(t)))
+		switch tier {
+		case db.RiskTierCritical, db.RiskTierDangerous, db.RiskTierCaution:
+			p.Tiers[tier] = true
+		default:
+			return nil, fmt.Errorf("invalid tier %q for execution window", t)
+		}
+	}
+
+	if tz := strings.TrimSpace(timezone); tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone %q: %w", tz, err)
+		}
+		p.Location = loc
+	}
+
+	return p, nil
+}
+
+// ParseQuietHours parses "HH:MM-HH:MM" into minutes after midnight.
+func ParseQuietHours(s string) (start, end int, err error) {
+	parts := strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid quiet hours %q (use HH:MM-HH:MM)", s)
+	}
+	if start, err = parseClock(parts[0]); err != nil {
+		return 0, 0, fmt.Errorf("invalid quiet hours %q: %w", s, err)
+	}
+	if end, err = parseClock(parts[1]); err != nil {
+		return 0, 0, fmt.Errorf("invalid quiet hours %q: %w", s, err)
+	}
+	return start, end, nil
+}
+
+func parseClock(s string) (int, error) {
+	hm := strings.Split(strings.TrimSpace(s), ":")
+	if len(hm) != 2 {
+		return 0, fmt.Errorf("expected HH:MM, got %q", s)
+	}
+	h, err := strconv.Atoi(hm[0])
+	if err != nil || h < 0 || h > 23 {
+		return 0, fmt.Errorf("invalid hour in %q", s)
+	}
+	m, err := strconv.Atoi(hm[1])
+	if err != nil || m < 0 || m > 59 {
+		return 0, fmt.Errorf("invalid minute in %q", s)
+	}
+	return h*60 + m, nil
+}
+
+// Restricted reports whether executing a request of the given tier at t falls
+// inside a restricted window, and if so describes the window.
+func (p *ExecutionWindowPolicy) Restricted(tier db.RiskTier, t time.Time) (bool, string) {
+	if p == nil || !p.Tiers[tier] {
+		return false, ""
+	}
+	loc := p.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	local := t.In(loc)
+
+	if p.BlockWeekends {
+		if wd := local.Weekday(); wd == time.Saturday || wd == time.Sunday {
+			return true, fmt.Sprintf("%s executions are not allowed on weekends", tier)
+		}
+	}
+
+	if p.QuietStart != p.QuietEnd {
+		minute := local.Hour()*60 + local.Minute()
+		var inside bool
+		if p.QuietStart < p.QuietEnd {
+			inside = minute >= p.QuietStart && minute < p.QuietEnd
+		} else {
+			inside = minute >= p.QuietStart || minute < p.QuietEnd
+		}
+		if inside {
+			return true, fmt.Sprintf("%s executions are not allowed during quiet hours %s-%s (%s)",
+				tier, formatClock(p.QuietStart), formatClock(p.QuietEnd), loc)
+		}
+	}
+
+	return false, ""
+}
+
+func formatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
@@ -0,0 +1,139 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/clock"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestParseQuietHours(t *testing.T) {
+	start, end, err := ParseQuietHours("22:00-06:30")
+	if err != nil {
+		t.Fatalf("ParseQuietHours error: %v", err)
+	}
+	if start != 22*60 || end != 6*60+30 {
+		t.Fatalf("got %d-%d, want %d-%d", start, end, 22*60, 6*60+30)
+	}
+
+	for _, bad := range []string{"22:00", "10pm-6am", "24:00-06:00", "22:60-06:00", "22:00-06:00-07:00"} {
+		if _, _, err := ParseQuietHours(bad); err == nil {
+			t.Errorf("ParseQuietHours(%q) expected error", bad)
+		}
+	}
+}
+
+func TestExecutionWindowPolicy_Restricted(t *testing.T) {
+	p, err := NewExecutionWindowPolicy("22:00-06:00", true, []string{"critical"}, "UTC")
+	if err != nil {
+		t.Fatalf("NewExecutionWindowPolicy error: %v", err)
+	}
+
+	// 2025-01-15 is a Wednesday.
+	cases := []struct {
+		name string
+		tier db.RiskTier
+		at   time.Time
+		want bool
+	}{
+		{"weekday afternoon", db.RiskTierCritical, time.Date(2025, 1, 15, 14, 0, 0, 0, time.UTC), false},
+		{"late night", db.RiskTierCritical, time.Date(2025, 1, 15, 23, 30, 0, 0, time.UTC), true},
+		{"early morning", db.RiskTierCritical, time.Date(2025, 1, 15, 5, 59, 0, 0, time.UTC), true},
+		{"window end is exclusive", db.RiskTierCritical, time.Date(2025, 1, 15, 6, 0, 0, 0, time.UTC), false},
+		{"saturday", db.RiskTierCritical, time.Date(2025, 1, 18, 12, 0, 0, 0, time.UTC), true},
+		{"other tier unaffected", db.RiskTierDangerous, time.Date(2025, 1, 15, 23, 30, 0, 0, time.UTC), false},
+	}
+	for _, tc := range cases {
+		got, reason := p.Restricted(tc.tier, tc.at)
+		if got != tc.want {
+			t.Errorf("%s: Restricted = %v (%s), want %v", tc.name, got, reason, tc.want)
+		}
+		if got && reason == "" {
+			t.Errorf("%s: expected a reason", tc.name)
+		}
+	}
+
+	var nilPolicy *ExecutionWindowPolicy
+	if got, _ := nilPolicy.Restricted(db.RiskTierCritical, time.Now()); got {
+		t.Error("nil policy should never restrict")
+	}
+
+	if _, err := NewExecutionWindowPolicy("", false, []string{"safe"}, ""); err == nil {
+		t.Error("expected error for safe tier")
+	}
+	if _, err := NewExecutionWindowPolicy("", false, nil, "Mars/Olympus"); err == nil {
+		t.Error("expected error for unknown timezone")
+	}
+}
+
+func TestExecutor_ExecutionWindowRequiresOverride(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	sess := testutil.MakeSession(t, database)
+	req := testutil.MakeRequest(t, database, sess,
+		testutil.WithCommand("echo quiet-hours", t.TempDir(), true),
+		testutil.WithRisk(db.RiskTierCritical),
+		testutil.WithStatus(db.StatusApproved),
+	)
+
+	policy, err := NewExecutionWindowPolicy("22:00-06:00", false, []string{"critical"}, "UTC")
+	if err != nil {
+		t.Fatalf("NewExecutionWindowPolicy error: %v", err)
+	}
+	exec := NewExecutor(database, nil).WithExecutionWindows(policy)
+	exec.clock = clock.Func(func() time.Time { return time.Date(2025, 1, 15, 23, 0, 0, 0, time.UTC) })
+
+	ok, reason := exec.CanExecute(req.ID)
+	if ok || !strings.Contains(reason, "quiet hours") {
+		t.Fatalf("expected CanExecute to be blocked by quiet hours, got ok=%v reason=%q", ok, reason)
+	}
+
+	_, err = exec.ExecuteApprovedRequest(context.Background(), ExecuteOptions{
+		RequestID:      req.ID,
+		SessionID:      sess.ID,
+		LogDir:         t.TempDir(),
+		SuppressOutput: true,
+	})
+	if !errors.Is(err, ErrExecutionWindowClosed) {
+		t.Fatalf("expected ErrExecutionWindowClosed, got %v", err)
+	}
+
+	// An override bound to a different command hash is not honored.
+	if err := database.CreateExecutionOverride(&db.ExecutionOverride{
+		RequestID:   req.ID,
+		CommandHash: "stale-hash",
+		GrantedBy:   "oncall",
+		Reason:      "old approval",
+	}); err != nil {
+		t.Fatalf("CreateExecutionOverride: %v", err)
+	}
+	if ok, _ := exec.CanExecute(req.ID); ok {
+		t.Fatal("override with mismatched hash should not unblock execution")
+	}
+
+	if err := database.CreateExecutionOverride(&db.ExecutionOverride{
+		RequestID:   req.ID,
+		CommandHash: req.Command.Hash,
+		GrantedBy:   "oncall",
+		Reason:      "hotfix",
+	}); err != nil {
+		t.Fatalf("CreateExecutionOverride: %v", err)
+	}
+
+	result, err := exec.ExecuteApprovedRequest(context.Background(), ExecuteOptions{
+		RequestID:      req.ID,
+		SessionID:      sess.ID,
+		LogDir:         t.TempDir(),
+		SuppressOutput: true,
+	})
+	if err != nil {
+		t.Fatalf("ExecuteApprovedRequest after override: %v", err)
+	}
+	if result.ExitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d", result.ExitCode)
+	}
+}
@@ -0,0 +1,515 @@
+// Package core normalizes PowerShell and cmd.exe commands for pattern matching.
+//
+// Windows deletions (Remove-Item and its aliases, del/erase, rd/rmdir) are
+// rewritten to the equivalent rm invocation so the same risk patterns apply
+// to Windows-based agents. Paths are rewritten to forward slashes: a drive
+// root (C:\, C:\*, \) becomes the filesystem root (/, /*), other drive paths
+// become /<drive>/<path> (C:\Windows -> /c/Windows), and the user profile
+// becomes ~.
+package core
+
+import (
+	"encoding/base64"
+	"regexp"
+	"strings"
+	"unicode/utf16"
+)
+
+// winToken is a token from a Windows command line with its byte offset.
+type winToken struct {
+	text  string
+	start int
+}
+
+// splitWindowsTokens splits a Windows command line on whitespace, honoring
+// double quotes (and PowerShell single quotes). Unlike POSIX shells,
+// backslashes are path separators, not escapes.
+func splitWindowsTokens(s string) []winToken {
+	var tokens []winToken
+	var cur strings.Builder
+	start := -1
+	var quote rune
+
+	for i, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+				continue
+			}
+			cur.WriteRune(r)
+		case r == '"' || r == '\'':
+			if start < 0 {
+				start = i
+			}
+			quote = r
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if start >= 0 {
+				tokens = append(tokens, winToken{text: cur.String(), start: start})
+				cur.Reset()
+				start = -1
+			}
+		default:
+			if start < 0 {
+				start = i
+			}
+			cur.WriteRune(r)
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, winToken{text: cur.String(), start: start})
+	}
+	return tokens
+}
+
+func windowsArgs(s string) []string {
+	tokens := splitWindowsTokens(s)
+	args := make([]string, len(tokens))
+	for i, t := range tokens {
+		args[i] = t.text
+	}
+	return args
+}
+
+// windowsCommandName returns the lowercased base name of a Windows command
+// without a .exe suffix (C:\Windows\System32\cmd.exe -> cmd).
+func windowsCommandName(tok string) string {
+	if i := strings.LastIndexAny(tok, `\/`); i >= 0 {
+		tok = tok[i+1:]
+	}
+	return strings.TrimSuffix(strings.ToLower(tok), ".exe")
+}
+
+// powershellValueOptions are powershell.exe/pwsh options that take a value.
+var powershellValueOptions = map[string]bool{
+	"executionpolicy": true, "ep": true, "ex": true,
+	"windowstyle": true, "w": true,
+	"workingdirectory": true, "wd": true,
+	"outputformat": true, "of": true,
+	"inputformat": true, "if": true,
+	"version": true, "v": true,
+	"configurationname": true,
+	"settingsfile":      true,
+	"psconsolefile":     true,
+}
+
+// unwrapWindowsShell extracts the inner command from `powershell -Command
+// ...`, `pwsh -EncodedCommand ...` or `cmd /c ...`. ok is false when seg is
+// not such a wrapper. decodeErr reports an -EncodedCommand payload that could
+// not be decoded; the caller treats it as a parse error.
+func unwrapWindowsShell(seg string) (inner, wrapper string, ok, decodeErr bool) {
+	tokens := splitWindowsTokens(seg)
+	if len(tokens) < 2 {
+		return "", "", false, false
+	}
+
+	rest := func(i int) string {
+		return unquoteWindows(strings.TrimSpace(seg[tokens[i].start:]))
+	}
+
+	switch name := windowsCommandName(tokens[0].text); name {
+	case "powershell", "pwsh":
+		for i := 1; i < len(tokens); i++ {
+			tok := tokens[i].text
+			if !strings.HasPrefix(tok, "-") && !strings.HasPrefix(tok, "/") {
+				// The first non-option argument is the command.
+				return rest(i), name + " -Command", true, false
+			}
+			opt := strings.ToLower(strings.TrimLeft(tok, "-/"))
+			switch {
+			case opt != "" && strings.HasPrefix("command", opt):
+				if i+1 >= len(tokens) {
+					return "", "", false, false
+				}
+				return rest(i + 1), name + " -Command", true, false
+			case opt == "e" || opt == "ec" || (len(opt) >= 2 && strings.HasPrefix("encodedcommand", opt)):
+				if i+1 >= len(tokens) {
+					return "", "", false, false
+				}
+				decoded, err := decodePowerShellCommand(tokens[i+1].text)
+				if err != nil {
+					return "", name + " -EncodedCommand", true, true
+				}
+				return decoded, name + " -EncodedCommand", true, false
+			case opt == "f" || strings.HasPrefix("file", opt):
+				// Script files cannot be inspected.
+				return "", "", false, false
+			case powershellValueOptions[opt]:
+				i++
+			}
+		}
+	case "cmd":
+		for i := 1; i < len(tokens); i++ {
+			opt := strings.ToLower(tokens[i].text)
+			if !strings.HasPrefix(opt, "/") {
+				return "", "", false, false
+			}
+			if opt == "/c" || opt == "/k" || opt == "/r" {
+				if i+1 >= len(tokens) {
+					return "", "", false, false
+				}
+				return rest(i + 1), "cmd " + opt, true, false
+			}
+		}
+	}
+	return "", "", false, false
+}
+
+// decodePowerShellCommand decodes an -EncodedCommand payload (base64 of
+// UTF-16LE text).
+func decodePowerShellCommand(payload string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return "", err
+	}
+	if len(raw)%2 != 0 {
+		return "", base64.CorruptInputError(len(raw))
+	}
+	units := make([]uint16, len(raw)/2)
+	for i := range units {
+		units[i] = uint16(raw[2*i]) | uint16(raw[2*i+1])<<8
+	}
+	return string(utf16.Decode(units)), nil
+}
+
+func unquoteWindows(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// windowsDeletion is a parsed Windows delete command.
+type windowsDeletion struct {
+	recurse bool
+	force   bool
+	paths   []string
+	// fromPipeline is set when paths come from PowerShell pipeline input
+	// ($_ / $PSItem) rather than the command line.
+	fromPipeline bool
+}
+
+// powershellValueParams are Remove-Item / Get-ChildItem parameters that take
+// a value (besides -Path and -LiteralPath).
+var powershellValueParams = []string{"filter", "include", "exclude", "stream", "credential", "depth", "attributes"}
+
+var posixShortFlags = regexp.MustCompile(`^-[rRfidvI]+$`)
+
+var windowsSwitchPattern = regexp.MustCompile(`^(/[A-Za-z](:[A-Za-z-]*)?)+$`)
+
+// parseWindowsDeletion parses Remove-Item (ri, del, erase, rd, rmdir, rm)
+// and cmd.exe del/erase/rd/rmdir. ok is false for anything else, including
+// POSIX rm/rmdir invocations.
+func parseWindowsDeletion(args []string) (*windowsDeletion, bool) {
+	if len(args) == 0 {
+		return nil, false
+	}
+	switch name := windowsCommandName(args[0]); name {
+	case "remove-item", "ri", "del", "erase", "rd":
+	case "rmdir", "rm":
+		if !looksLikeWindowsArgs(args[1:], name == "rmdir") {
+			return nil, false
+		}
+	default:
+		return nil, false
+	}
+
+	d := &windowsDeletion{}
+	params := args[1:]
+	for i := 0; i < len(params); i++ {
+		arg := params[i]
+		switch {
+		case windowsSwitchPattern.MatchString(arg):
+			for _, sw := range strings.Split(strings.ToLower(arg), "/")[1:] {
+				switch sw[0] {
+				case 's':
+					d.recurse = true
+				case 'q', 'f':
+					d.force = true
+				}
+			}
+		case len(arg) > 1 && arg[0] == '-':
+			name, value, hasValue := strings.Cut(strings.ToLower(arg[1:]), ":")
+			switch {
+			case strings.HasPrefix("recurse", name):
+				d.recurse = !hasValue || value != "$false"
+			case name == "f" || (len(name) >= 2 && strings.HasPrefix("force", name)):
+				d.force = !hasValue || value != "$false"
+			case isPathParam(name):
+				if !hasValue && i+1 < len(params) {
+					i++
+					value = params[i]
+				} else if hasValue {
+					value = arg[len(name)+2:]
+				}
+				d.addPaths(value)
+			case isValueParam(name):
+				if !hasValue {
+					i++
+				}
+			case posixShortFlags.MatchString(arg):
+				// rm -rf C:\x (e.g. from Git Bash).
+				d.recurse = d.recurse || strings.ContainsAny(arg, "rR")
+				d.force = d.force || strings.Contains(arg, "f")
+			}
+		default:
+			d.addPaths(arg)
+		}
+	}
+	return d, true
+}
+
+func isPathParam(name string) bool {
+	return name == "lp" || name == "pspath" ||
+		(name != "" && strings.HasPrefix("path", name)) ||
+		(len(name) >= 2 && strings.HasPrefix("literalpath", name))
+}
+
+func isValueParam(name string) bool {
+	for _, p := range powershellValueParams {
+		if len(name) >= 2 && strings.HasPrefix(p, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// looksLikeWindowsArgs reports whether the arguments of an ambiguous command
+// name (rm, rmdir) are PowerShell parameters, drive-qualified or
+// environment-variable paths, or (when allowSwitches is set) cmd.exe
+// switches. A bare backslash is not enough: POSIX shells use it to escape.
+func looksLikeWindowsArgs(args []string, allowSwitches bool) bool {
+	for _, arg := range args {
+		lower := strings.ToLower(arg)
+		if strings.HasPrefix(lower, "-") && !strings.HasPrefix(lower, "--") {
+			name, _, _ := strings.Cut(lower[1:], ":")
+			if len(name) >= 3 && (strings.HasPrefix("recurse", name) || strings.HasPrefix("force", name) || isPathParam(name)) {
+				return true
+			}
+			continue
+		}
+		if allowSwitches && windowsSwitchPattern.MatchString(arg) {
+			return true
+		}
+		if windowsDrivePattern.MatchString(arg) || windowsVarPattern.MatchString(arg) {
+			return true
+		}
+	}
+	return false
+}
+
+var windowsDrivePattern = regexp.MustCompile(`^[A-Za-z]:([\\/]|$)`)
+
+// addPaths records a (possibly comma-separated) PowerShell path list.
+func (d *windowsDeletion) addPaths(value string) {
+	for _, p := range strings.Split(value, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		lower := strings.ToLower(p)
+		if lower == "$_" || strings.HasPrefix(lower, "$_.") || strings.HasPrefix(lower, "$psitem") {
+			d.fromPipeline = true
+			continue
+		}
+		d.paths = append(d.paths, p)
+	}
+}
+
+// canonical renders the deletion as the equivalent rm command.
+func (d *windowsDeletion) canonical() string {
+	if len(d.paths) == 0 && !d.recurse {
+		// Bare rm, like the xargs form of a pipeline deletion.
+		return "rm"
+	}
+	parts := []string{"rm"}
+	flags := ""
+	if d.recurse {
+		flags += "r"
+	}
+	if d.force {
+		flags += "f"
+	}
+	if flags != "" {
+		parts = append(parts, "-"+flags)
+	}
+	for _, p := range d.paths {
+		parts = append(parts, canonicalWindowsPath(p))
+	}
+	return strings.Join(parts, " ")
+}
+
+// windowsPathVars maps well-known environment variables to their usual values.
+var windowsPathVars = map[string]string{
+	"systemdrive":       `C:`,
+	"systemroot":        `C:\Windows`,
+	"windir":            `C:\Windows`,
+	"programfiles":      `C:\Program Files`,
+	"programfiles(x86)": `C:\Program Files (x86)`,
+	"programdata":       `C:\ProgramData`,
+	"userprofile":       `~`,
+	"home":              `~`,
+}
+
+var windowsVarPattern = regexp.MustCompile(`(?i)^(?:\$env:([a-z0-9_]+)|\$\{env:([^}]+)\}|%([^%]+)%|\$(home)\b)`)
+
+// canonicalWindowsPath rewrites a Windows path for pattern matching.
+func canonicalWindowsPath(p string) string {
+	if m := windowsVarPattern.FindStringSubmatch(p); m != nil {
+		name := strings.ToLower(m[1] + m[2] + m[3] + m[4])
+		if value, ok := windowsPathVars[name]; ok {
+			p = value + p[len(m[0]):]
+		}
+	}
+
+	p = strings.ReplaceAll(p, `\`, "/")
+	if strings.HasPrefix(p, "//") {
+		// UNC path (\\server\share).
+		return p
+	}
+
+	if windowsDrivePattern.MatchString(p) {
+		rest := strings.TrimPrefix(p[2:], "/")
+		if isRootRest(rest) {
+			return rootForm(rest)
+		}
+		return "/" + strings.ToLower(p[:1]) + "/" + rest
+	}
+	if strings.HasPrefix(p, "/") && isRootRest(p[1:]) {
+		return rootForm(p[1:])
+	}
+	return p
+}
+
+func isRootRest(rest string) bool {
+	switch rest {
+	case "", ".", "*", "*.*":
+		return true
+	}
+	return false
+}
+
+func rootForm(rest string) string {
+	if strings.HasPrefix(rest, "*") {
+		return "/*"
+	}
+	return "/"
+}
+
+// windowsOnlyCommands are command names that identify a PowerShell or
+// cmd.exe command line.
+var windowsOnlyCommands = map[string]bool{
+	"remove-item": true, "ri": true, "del": true, "erase": true, "rd": true,
+	"get-childitem": true, "gci": true, "foreach-object": true,
+	"powershell": true, "pwsh": true, "cmd": true,
+}
+
+// powershellCmdletPattern matches common Verb-Noun cmdlet names.
+var powershellCmdletPattern = regexp.MustCompile(`(?i)^(get|set|new|remove|copy|move|rename|clear|stop|start|restart|invoke|write|format|out|select|where|sort)-[a-z]+$`)
+
+// isWindowsCommandLine reports whether cmd starts with a PowerShell cmdlet or
+// a Windows-only command, so backslashes must not be treated as escapes.
+func isWindowsCommandLine(cmd string) bool {
+	tokens := splitWindowsTokens(cmd)
+	if len(tokens) == 0 {
+		return false
+	}
+	name := windowsCommandName(tokens[0].text)
+	return windowsOnlyCommands[name] || powershellCmdletPattern.MatchString(name)
+}
+
+// normalizeWindowsSegment rewrites a Windows deletion to its rm form. Other
+// PowerShell and cmd.exe commands are returned as-is. ok is false when seg is
+// not a Windows command.
+func normalizeWindowsSegment(seg string) (string, bool) {
+	if d, ok := parseWindowsDeletion(windowsArgs(seg)); ok {
+		return d.canonical(), true
+	}
+	if isWindowsCommandLine(seg) {
+		return strings.TrimSpace(seg), true
+	}
+	return "", false
+}
+
+var forEachBlockPattern = regexp.MustCompile(`(?is)^(?:foreach-object|foreach|%)\s+(?:-process\s+)?\{(.*)\}\s*$`)
+
+// rewritePowerShellPipeline resolves deletions that take their paths from
+// the pipeline, e.g. `Get-ChildItem C:\logs -Recurse | Remove-Item -Force`
+// or `gci C:\x | % { Remove-Item $_ -Recurse }`. The deletion inherits the
+// source's paths, and a recursive listing makes it a recursive delete.
+// Other parts are returned unchanged.
+func rewritePowerShellPipeline(parts []string) []string {
+	out := make([]string, len(parts))
+	copy(out, parts)
+
+	for i := 1; i < len(parts); i++ {
+		body := parts[i]
+		if m := forEachBlockPattern.FindStringSubmatch(strings.TrimSpace(body)); m != nil {
+			body = m[1]
+		}
+		d, ok := parseWindowsDeletion(windowsArgs(body))
+		if !ok {
+			continue
+		}
+		if len(d.paths) == 0 || d.fromPipeline {
+			if src, ok := parseChildItemSource(windowsArgs(parts[i-1])); ok {
+				d.paths = append(d.paths, src.paths...)
+				d.recurse = d.recurse || src.recurse
+			}
+		}
+		out[i] = d.canonical()
+	}
+	return out
+}
+
+// childItemSource is a parsed Get-ChildItem (gci, ls, dir) listing.
+type childItemSource struct {
+	paths   []string
+	recurse bool
+}
+
+func parseChildItemSource(args []string) (*childItemSource, bool) {
+	if len(args) == 0 {
+		return nil, false
+	}
+	switch windowsCommandName(args[0]) {
+	case "get-childitem", "gci", "ls", "dir":
+	default:
+		return nil, false
+	}
+
+	src := &childItemSource{}
+	positional := 0
+	params := args[1:]
+	for i := 0; i < len(params); i++ {
+		arg := params[i]
+		if len(arg) > 1 && arg[0] == '-' {
+			name, value, hasValue := strings.Cut(strings.ToLower(arg[1:]), ":")
+			switch {
+			case name == "r" || strings.HasPrefix("recurse", name) && len(name) >= 2:
+				src.recurse = !hasValue || value != "$false"
+			case isPathParam(name):
+				if !hasValue && i+1 < len(params) {
+					i++
+					src.paths = append(src.paths, strings.Split(params[i], ",")...)
+				} else if hasValue {
+					src.paths = append(src.paths, strings.Split(arg[len(name)+2:], ",")...)
+				}
+			case isValueParam(name):
+				if !hasValue {
+					i++
+				}
+			}
+			continue
+		}
+		// The first positional argument is the path; the second is a filter.
+		if positional == 0 {
+			src.paths = append(src.paths, strings.Split(arg, ",")...)
+		}
+		positional++
+	}
+	if len(src.paths) == 0 {
+		src.paths = []string{"."}
+	}
+	return src, true
+}
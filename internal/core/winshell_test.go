@@ -0,0 +1,143 @@
+package core
+
+import (
+	"encoding/base64"
+	"testing"
+	"unicode/utf16"
+)
+
+func TestClassifyWindowsCommands(t *testing.T) {
+	engine := NewPatternEngine()
+
+	tests := []struct {
+		cmd  string
+		want RiskTier
+	}{
+		// PowerShell Remove-Item and aliases
+		{`Remove-Item -Recurse -Force C:\`, RiskTierCritical},
+		{`Remove-Item -Recurse -Force C:\*`, RiskTierCritical},
+		{`Remove-Item 'C:\Windows' -r -fo`, RiskTierCritical},
+		{`Remove-Item "C:\Program Files (x86)" -Recurse -Force`, RiskTierCritical},
+		{`rm -Recurse -Force $env:USERPROFILE`, RiskTierCritical},
+		{`Remove-Item C:\Users\bob\build -Recurse -Force`, RiskTierDangerous},
+		{`ri -Path D:\data -Recurse`, RiskTierDangerous},
+		{`Remove-Item -LiteralPath:D:\data -Recurse`, RiskTierDangerous},
+		{`Remove-Item foo.txt`, RiskTierCaution},
+		{`Remove-Item -Recurse:$false foo`, RiskTierCaution},
+		// cmd.exe
+		{`del /s /q C:\`, RiskTierCritical},
+		{`rd /s /q D:\`, RiskTierCritical},
+		{`cmd.exe /c rd /s /q C:\Windows`, RiskTierCritical},
+		{`cmd /c "del /s /q C:\ & echo done"`, RiskTierCritical},
+		{`del /s/q C:\temp\build`, RiskTierDangerous},
+		{`rmdir /s /q .\build`, RiskTierDangerous},
+		// Pipelines
+		{`Get-ChildItem C:\logs -Recurse | Remove-Item -Force`, RiskTierDangerous},
+		{`gci C:\ | % { Remove-Item $_.FullName -Recurse -Force }`, RiskTierCritical},
+		{`Get-Content list.txt | Remove-Item`, RiskTierCaution},
+		// Wrappers and compound commands
+		{`powershell -NoProfile -ExecutionPolicy Bypass -Command "Remove-Item -Recurse -Force C:\"`, RiskTierCritical},
+		{`pwsh -EncodedCommand ` + encodePowerShell(`Remove-Item -Recurse -Force C:\`), RiskTierCritical},
+		{`Remove-Item -Recurse -Force C:\; Write-Host done`, RiskTierCritical},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cmd, func(t *testing.T) {
+			result := engine.ClassifyCommand(tt.cmd, "/work")
+			if result.Tier != tt.want {
+				t.Errorf("ClassifyCommand(%q) = %s (pattern %q), want %s", tt.cmd, result.Tier, result.MatchedPattern, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifyWindowsCommands_NoFalsePositives(t *testing.T) {
+	engine := NewPatternEngine()
+
+	for _, cmd := range []string{
+		`Get-ChildItem C:\ | Select-Object Name`,
+		`Write-Host "C:\"`,
+		`rmdir emptydir`,
+	} {
+		result := engine.ClassifyCommand(cmd, "/work")
+		if result.NeedsApproval || result.ParseError {
+			t.Errorf("ClassifyCommand(%q) = %s (pattern %q, parse error %v), want no review", cmd, result.Tier, result.MatchedPattern, result.ParseError)
+		}
+	}
+
+	// POSIX rm keeps its backslash escapes.
+	if got := NormalizeCommand(`rm -rf foo\ bar`).Primary; got != "rm -rf foo bar" {
+		t.Errorf("Primary = %q, want POSIX normalization", got)
+	}
+}
+
+func TestNormalizeWindowsSegment(t *testing.T) {
+	tests := []struct {
+		seg  string
+		want string
+	}{
+		{`Remove-Item -Recurse -Force C:\`, "rm -rf /"},
+		{`Remove-Item C:\*.*`, "rm /*"},
+		{`Remove-Item \ -Recurse`, "rm -r /"},
+		{`Remove-Item -Path C:\a,D:\b -Force`, "rm -f /c/a /d/b"},
+		{`del /q %SystemRoot%\Temp\x.log`, "rm -f /c/Windows/Temp/x.log"},
+		{`Remove-Item ${env:ProgramFiles(x86)}\App -Recurse`, "rm -r /c/Program Files (x86)/App"},
+		{`Remove-Item \\server\share\dir -Recurse`, "rm -r //server/share/dir"},
+		{`Remove-Item -Recurse -Filter *.tmp -Path .\out`, "rm -r ./out"},
+		{`erase -r -f C:\Users\me\x`, "rm -rf /c/Users/me/x"},
+	}
+	for _, tt := range tests {
+		got, ok := normalizeWindowsSegment(tt.seg)
+		if !ok || got != tt.want {
+			t.Errorf("normalizeWindowsSegment(%q) = %q, %v; want %q", tt.seg, got, ok, tt.want)
+		}
+	}
+
+	for _, seg := range []string{`rm -rf ./build`, `rmdir emptydir`, `ls -la`} {
+		if got, ok := normalizeWindowsSegment(seg); ok {
+			t.Errorf("normalizeWindowsSegment(%q) = %q, want not a Windows command", seg, got)
+		}
+	}
+}
+
+func TestUnwrapWindowsShell(t *testing.T) {
+	tests := []struct {
+		seg     string
+		inner   string
+		wrapper string
+	}{
+		{`powershell -Command "Remove-Item x"`, "Remove-Item x", "powershell -Command"},
+		{`powershell.exe -NoProfile -c Remove-Item x`, "Remove-Item x", "powershell -Command"},
+		{`pwsh -NoLogo -ExecutionPolicy Bypass 'Get-Date'`, "Get-Date", "pwsh -Command"},
+		{`C:\Windows\System32\cmd.exe /d /c "rd /s /q C:\x"`, `rd /s /q C:\x`, "cmd /c"},
+		{`pwsh -enc ` + encodePowerShell("Get-Date"), "Get-Date", "pwsh -EncodedCommand"},
+	}
+	for _, tt := range tests {
+		inner, wrapper, ok, decodeErr := unwrapWindowsShell(tt.seg)
+		if !ok || decodeErr || inner != tt.inner || wrapper != tt.wrapper {
+			t.Errorf("unwrapWindowsShell(%q) = %q, %q, %v, %v; want %q, %q", tt.seg, inner, wrapper, ok, decodeErr, tt.inner, tt.wrapper)
+		}
+	}
+
+	if _, _, ok, _ := unwrapWindowsShell(`powershell -File deploy.ps1`); ok {
+		t.Error("expected -File scripts to be left alone")
+	}
+	if _, _, ok, decodeErr := unwrapWindowsShell(`pwsh -EncodedCommand not-base64!`); !ok || !decodeErr {
+		t.Error("expected undecodable -EncodedCommand to report a decode error")
+	}
+
+	res := NormalizeCommand(`pwsh -EncodedCommand not-base64!`)
+	if !res.ParseError {
+		t.Error("expected undecodable -EncodedCommand to set ParseError")
+	}
+}
+
+// encodePowerShell encodes a command the way -EncodedCommand expects.
+func encodePowerShell(cmd string) string {
+	units := utf16.Encode([]rune(cmd))
+	raw := make([]byte, 0, 2*len(units))
+	for _, u := range units {
+		raw = append(raw, byte(u), byte(u>>8))
+	}
+	return base64.StdEncoding.EncodeToString(raw)
+}
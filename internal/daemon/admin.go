@@ -0,0 +1,316 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// Connection and subscription IDs are unique across the daemon's listeners
+// so the admin REPL can address them without naming the listener.
+var (
+	nextClientID       atomic.Int64
+	nextSubscriptionID atomic.Int64
+)
+
+// clientConn tracks a connected client.
+type clientConn struct {
+	id          int64
+	remote      string
+	peer        *PeerCred
+	connectedAt time.Time
+	requests    atomic.Int64
+	lastMethod  atomic.Value // string
+	// limited counts the client's requests refused by quotas; bucket is
+	// its rate limit.
+	limited atomic.Int64
+	bucket  tokenBucket
+}
+
+// ClientInfo describes a connected client.
+type ClientInfo struct {
+	ID int64 `json:"id"`
+	// Listener is the socket path or TCP address the client connected to.
+	Listener    string    `json:"listener"`
+	Remote      string    `json:"remote,omitempty"`
+	Peer        *PeerCred `json:"peer,omitempty"`
+	ConnectedAt time.Time `json:"connected_at"`
+	Requests    int64     `json:"requests"`
+	LastMethod  string    `json:"last_method,omitempty"`
+	// Limited counts the client's requests refused by rate limits and
+	// quotas.
+	Limited int64 `json:"limited,omitempty"`
+	// Subscriptions are the client's event subscription IDs.
+	Subscriptions []int64 `json:"subscriptions,omitempty"`
+}
+
+func (s *IPCServer) trackClient(conn net.Conn, peer *PeerCred) *clientConn {
+	c := &clientConn{
+		id:          nextClientID.Add(1),
+		peer:        peer,
+		connectedAt: time.Now(),
+	}
+	if addr := conn.RemoteAddr(); addr != nil && addr.String() != "" {
+		c.remote = addr.String()
+	}
+	s.clientsMu.Lock()
+	if s.clients == nil {
+		s.clients = make(map[int64]*clientConn)
+	}
+	s.clients[c.id] = c
+	s.clientsMu.Unlock()
+	return c
+}
+
+func (s *IPCServer) untrackClient(id int64) {
+	s.clientsMu.Lock()
+	delete(s.clients, id)
+	s.clientsMu.Unlock()
+}
+
+// Clients lists the connected clients, oldest first.
+func (s *IPCServer) Clients() []ClientInfo {
+	subs := make(map[int64][]int64)
+	s.subscribersMu.RLock()
+	for _, sub := range s.subscribers {
+		subs[sub.clientID] = append(subs[sub.clientID], sub.id)
+	}
+	s.subscribersMu.RUnlock()
+
+	s.clientsMu.Lock()
+	clients := make([]ClientInfo, 0, len(s.clients))
+	for _, c := range s.clients {
+		info := ClientInfo{
+			ID:            c.id,
+			Listener:      s.socketPath,
+			Remote:        c.remote,
+			Peer:          c.peer,
+			ConnectedAt:   c.connectedAt,
+			Requests:      c.requests.Load(),
+			Limited:       c.limited.Load(),
+			Subscriptions: subs[c.id],
+		}
+		info.LastMethod, _ = c.lastMethod.Load().(string)
+		sort.Slice(info.Subscriptions, func(i, j int) bool { return info.Subscriptions[i] < info.Subscriptions[j] })
+		clients = append(clients, info)
+	}
+	s.clientsMu.Unlock()
+
+	sort.Slice(clients, func(i, j int) bool { return clients[i].ID < clients[j].ID })
+	return clients
+}
+
+// DropSubscription ends an event subscription and closes its connection so
+// the client notices and resubscribes. It reports whether the subscription
+// existed.
+func (s *IPCServer) DropSubscription(id int64) bool {
+	sub := s.closeSubscriber(id)
+	if sub == nil {
+		return false
+	}
+	s.logger.Info("subscription dropped by admin", "subscription", id, "client", sub.clientID)
+	return true
+}
+
+// Admin is the operator's handle on a running daemon, driven by the
+// foreground admin REPL.
+type Admin struct {
+	servers     []*IPCServer
+	readModel   *ReadModel
+	projectPath string
+	logger      *log.Logger
+}
+
+// NewAdmin creates an admin handle over the daemon's listeners and read
+// model.
+func NewAdmin(projectPath string, servers []*IPCServer, readModel *ReadModel, logger *log.Logger) *Admin {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &Admin{servers: servers, readModel: readModel, projectPath: projectPath, logger: logger}
+}
+
+// Clients lists the clients of every listener.
+func (a *Admin) Clients() []ClientInfo {
+	var clients []ClientInfo
+	for _, srv := range a.servers {
+		clients = append(clients, srv.Clients()...)
+	}
+	sort.Slice(clients, func(i, j int) bool { return clients[i].ID < clients[j].ID })
+	return clients
+}
+
+// DropSubscription ends the subscription on whichever listener holds it.
+func (a *Admin) DropSubscription(id int64) bool {
+	for _, srv := range a.servers {
+		if srv.DropSubscription(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// ReloadPatterns merges custom patterns added since startup into the
+// engine and drops the cached snapshot. Removed patterns stay loaded until
+// the daemon restarts.
+func (a *Admin) ReloadPatterns() (loaded, skipped int) {
+	loaded, skipped = loadDaemonCustomPatterns(a.projectPath, a.logger)
+	if a.readModel != nil {
+		a.readModel.Invalidate()
+	}
+	return loaded, skipped
+}
+
+// adminHelp lists the REPL commands.
+const adminHelp = `Commands:
+  clients              list connected clients and their subscriptions
+  drop <subscription>  end an event subscription (the client reconnects)
+  reload               merge newly added custom patterns, drop the cache
+  cache                show read model stats, pending requests and sessions
+  help                 show this help
+  quit                 stop the daemon`
+
+// RunREPL reads admin commands from in, one per line, and writes results
+// to out until in is exhausted or ctx is done. quit calls stop.
+func (a *Admin) RunREPL(ctx context.Context, in io.Reader, out io.Writer, stop func()) {
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(in)
+		for scanner.Scan() {
+			select {
+			case lines <- scanner.Text():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	fmt.Fprint(out, "slb> ")
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case line, ok := <-lines:
+			if !ok {
+				return
+			}
+			if !a.exec(strings.Fields(line), out) {
+				stop()
+				return
+			}
+			fmt.Fprint(out, "slb> ")
+		}
+	}
+}
+
+// exec runs one command and reports whether the REPL should continue.
+func (a *Admin) exec(args []string, out io.Writer) bool {
+	if len(args) == 0 {
+		return true
+	}
+	switch strings.ToLower(args[0]) {
+	case "help", "?":
+		fmt.Fprintln(out, adminHelp)
+	case "clients":
+		a.printClients(out)
+	case "drop":
+		if len(args) != 2 {
+			fmt.Fprintln(out, "usage: drop <subscription>")
+			break
+		}
+		id, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			fmt.Fprintf(out, "invalid subscription id %q\n", args[1])
+			break
+		}
+		if a.DropSubscription(id) {
+			fmt.Fprintf(out, "dropped subscription %d\n", id)
+		} else {
+			fmt.Fprintf(out, "no subscription %d\n", id)
+		}
+	case "reload":
+		loaded, skipped := a.ReloadPatterns()
+		fmt.Fprintf(out, "merged %d new custom pattern(s), skipped %d; cache dropped\n", loaded, skipped)
+	case "cache":
+		a.printCache(out)
+	case "quit", "exit":
+		fmt.Fprintln(out, "stopping daemon")
+		return false
+	default:
+		fmt.Fprintf(out, "unknown command %q (try help)\n", args[0])
+	}
+	return true
+}
+
+func (a *Admin) printClients(out io.Writer) {
+	clients := a.Clients()
+	if len(clients) == 0 {
+		fmt.Fprintln(out, "no clients connected")
+		return
+	}
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tPEER\tCONNECTED\tREQUESTS\tLIMITED\tLAST METHOD\tSUBSCRIPTIONS\tLISTENER")
+	for _, c := range clients {
+		peer := c.Remote
+		if c.Peer != nil {
+			peer = c.Peer.String()
+		}
+		subs := make([]string, len(c.Subscriptions))
+		for i, id := range c.Subscriptions {
+			subs[i] = strconv.FormatInt(id, 10)
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s ago\t%d\t%d\t%s\t%s\t%s\n",
+			c.ID, dashIfEmpty(peer), time.Since(c.ConnectedAt).Round(time.Second),
+			c.Requests, c.Limited, dashIfEmpty(c.LastMethod), dashIfEmpty(strings.Join(subs, ",")), c.Listener)
+	}
+	_ = tw.Flush()
+}
+
+func (a *Admin) printCache(out io.Writer) {
+	if a.readModel == nil {
+		fmt.Fprintln(out, "read model not configured")
+		return
+	}
+	snap, err := a.readModel.Snapshot()
+	if err != nil {
+		fmt.Fprintf(out, "loading snapshot: %v\n", err)
+		return
+	}
+	stats := a.readModel.Stats()
+	fmt.Fprintf(out, "project %s, loaded %s ago\n", snap.ProjectPath, time.Since(snap.LoadedAt).Round(time.Second))
+	fmt.Fprintf(out, "hits %d, misses %d (%.0f%%), invalidations %d, errors %d\n",
+		stats.Hits, stats.Misses, stats.HitRate*100, stats.Invalidations, stats.Errors)
+	fmt.Fprintf(out, "approvals indexed %d, executions indexed %d\n", len(snap.approvals), len(snap.executions))
+
+	fmt.Fprintf(out, "pending requests (%d):\n", len(snap.Pending))
+	for _, p := range snap.Pending {
+		human := ""
+		if p.AwaitingHuman {
+			human = " awaiting human"
+		}
+		fmt.Fprintf(out, "  %s %-9s %d/%d%s  %s\n", shortID(p.Request.ID), p.Request.RiskTier,
+			p.Approvals, p.Request.MinApprovals, human, displayCommand(p.Request))
+	}
+	fmt.Fprintf(out, "active sessions (%d):\n", len(snap.Sessions))
+	for _, sess := range snap.Sessions {
+		fmt.Fprintf(out, "  %s %s (%s, %s)\n", shortID(sess.ID), sess.AgentName, dashIfEmpty(sess.Program), dashIfEmpty(sess.Model))
+	}
+}
+
+func dashIfEmpty(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
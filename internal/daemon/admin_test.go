@@ -0,0 +1,119 @@
+package daemon
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestAdmin_ClientsAndDropSubscription(t *testing.T) {
+	socketPath := filepath.Join(shortSocketDir(t), "admin.sock")
+	srv, err := NewIPCServer(socketPath, newTestLogger())
+	if err != nil {
+		t.Fatalf("NewIPCServer failed: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = srv.Start(ctx) }()
+	defer func() { _ = srv.Stop() }()
+	time.Sleep(50 * time.Millisecond)
+
+	conn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer conn.Close()
+	data, _ := json.Marshal(RPCRequest{Method: "subscribe", ID: 1})
+	if _, err := conn.Write(append(data, '\n')); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	scanner := bufio.NewScanner(conn)
+	if !scanner.Scan() {
+		t.Fatal("no subscribe response")
+	}
+	var resp struct {
+		Result SubscriptionInfo `json:"result"`
+	}
+	if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+
+	admin := NewAdmin(t.TempDir(), []*IPCServer{srv}, nil, newTestLogger())
+	clients := admin.Clients()
+	if len(clients) != 1 {
+		t.Fatalf("expected one client, got %+v", clients)
+	}
+	c := clients[0]
+	if c.Requests != 1 || c.LastMethod != "subscribe" || len(c.Subscriptions) != 1 || c.Subscriptions[0] != resp.Result.SubscriptionID {
+		t.Errorf("unexpected client info: %+v", c)
+	}
+	if c.Listener != socketPath {
+		t.Errorf("listener = %q, want %q", c.Listener, socketPath)
+	}
+
+	if admin.DropSubscription(resp.Result.SubscriptionID + 1000) {
+		t.Error("expected an unknown subscription not to be dropped")
+	}
+	if !admin.DropSubscription(resp.Result.SubscriptionID) {
+		t.Fatal("expected the subscription to be dropped")
+	}
+	// The client sees its connection close.
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	if scanner.Scan() {
+		t.Errorf("expected the connection to close, read %q", scanner.Text())
+	}
+	ok := testutil.WaitForCondition(func() bool { return len(admin.Clients()) == 0 }, 10*time.Millisecond, 2*time.Second)
+	if !ok {
+		t.Errorf("expected the client to be gone, got %+v", admin.Clients())
+	}
+}
+
+func TestAdmin_RunREPL(t *testing.T) {
+	h := testutil.NewHarness(t)
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	req := testutil.MakeRequest(t, h.DB, sess, testutil.WithCommand("rm -rf ./build", h.ProjectDir, true))
+	// The engine is shared by the package's tests; a fresh pattern is new to it.
+	_, err := h.DB.InsertCustomPattern("critical", fmt.Sprintf(`^deploy\s+--everywhere-%d`, time.Now().UnixNano()), "fleet deploys", "human")
+	testutil.RequireNoError(t, err, "insert custom pattern")
+
+	rm := NewReadModel(h.ProjectDir, newTestLogger())
+	admin := NewAdmin(h.ProjectDir, nil, rm, newTestLogger())
+	in := strings.NewReader("help\nclients\ncache\ndrop x\ndrop 99\nbogus\nreload\nreload\nquit\ncache\n")
+	var out bytes.Buffer
+	stopped := false
+	admin.RunREPL(context.Background(), in, &out, func() { stopped = true })
+
+	got := out.String()
+	for _, want := range []string{
+		"drop <subscription>",
+		"no clients connected",
+		"pending requests (1):",
+		req.ID[:8],
+		"active sessions (1):",
+		`invalid subscription id "x"`,
+		"no subscription 99",
+		`unknown command "bogus"`,
+		"merged 1 new custom pattern(s)",
+		"merged 0 new custom pattern(s)",
+		"stopping daemon",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected REPL output to contain %q:\n%s", want, got)
+		}
+	}
+	if !stopped {
+		t.Error("expected quit to stop the daemon")
+	}
+	if strings.Count(got, "pending requests (") != 1 {
+		t.Error("expected commands after quit to be ignored")
+	}
+}
@@ -0,0 +1,222 @@
+// Package daemon provides per-method authorization for IPC connections.
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+)
+
+// Capability is a class of IPC methods a connection may call.
+type Capability string
+
+const (
+	// CapRead covers methods that only report state: status, subscribe,
+	// classification and the read model.
+	CapRead Capability = "read"
+	// CapWrite covers methods that change state: notify, request
+	// submission, execution checks and heartbeats.
+	CapWrite Capability = "write"
+)
+
+// knownCapabilities lists every capability a client may ask for.
+var knownCapabilities = map[Capability]struct{}{CapRead: {}, CapWrite: {}}
+
+// ErrCodeForbidden is returned for a method the connection was not granted
+// the capability to call.
+const ErrCodeForbidden = -32003
+
+// methodCapabilities maps each IPC method to the capability it requires.
+// Methods not listed (ping, hello) are open to every connection.
+var methodCapabilities = map[string]Capability{
+	"status":         CapRead,
+	"subscribe":      CapRead,
+	"watch_requests": CapRead,
+	"hook_query":     CapRead,
+	"hook_health":    CapRead,
+	"read_model":     CapRead,
+	"db_probe":       CapRead,
+	"notify":         CapWrite,
+	"create_request": CapWrite,
+	"verify_execute": CapWrite,
+	"heartbeat":      CapWrite,
+}
+
+// MethodCapability returns the capability an IPC method requires, or ""
+// when it requires none.
+func MethodCapability(method string) Capability {
+	return methodCapabilities[method]
+}
+
+// capSet is the set of capabilities granted to a connection.
+type capSet map[Capability]bool
+
+// capsForAccess returns the capabilities of an access level: "read" grants
+// read only, "write" (or "") grants both.
+func capsForAccess(access string) (capSet, error) {
+	switch Capability(access) {
+	case CapRead:
+		return capSet{CapRead: true}, nil
+	case CapWrite, "":
+		return capSet{CapRead: true, CapWrite: true}, nil
+	default:
+		return nil, fmt.Errorf("unknown access level %q (want read or write)", access)
+	}
+}
+
+// allCaps grants every capability.
+func allCaps() capSet {
+	return capSet{CapRead: true, CapWrite: true}
+}
+
+// narrow returns the capabilities of s that were requested. Unknown names
+// are an error, so a client cannot believe it holds a capability that does
+// not exist.
+func (s capSet) narrow(requested []Capability) (capSet, error) {
+	out := capSet{}
+	for _, c := range requested {
+		if _, ok := knownCapabilities[c]; !ok {
+			return nil, fmt.Errorf("unknown capability %q", c)
+		}
+		if s[c] {
+			out[c] = true
+		}
+	}
+	return out, nil
+}
+
+// list returns the capabilities in s, sorted.
+func (s capSet) list() []Capability {
+	out := make([]Capability, 0, len(s))
+	for c, ok := range s {
+		if ok {
+			out = append(out, c)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+// HelloParams are parameters for the hello method.
+type HelloParams struct {
+	// Capabilities the client needs. The grant is narrowed to those the
+	// connection holds; an empty list leaves the grant unchanged.
+	Capabilities []Capability `json:"capabilities,omitempty"`
+}
+
+// HelloResult is the result of the hello method.
+type HelloResult struct {
+	// Capabilities granted to the connection from now on.
+	Capabilities []Capability `json:"capabilities"`
+	// Denied lists requested capabilities the connection does not hold.
+	Denied []Capability `json:"denied,omitempty"`
+}
+
+// capsOf returns the capabilities granted to a connection. Connections not
+// accepted by the server (as in unit tests of handlers) hold all of them.
+func capsOf(conn net.Conn) capSet {
+	if lc, ok := conn.(*lockedConn); ok && lc.caps != nil {
+		return lc.caps
+	}
+	return allCaps()
+}
+
+// setCaps records the capabilities granted to a connection.
+func setCaps(conn net.Conn, caps capSet) {
+	if lc, ok := conn.(*lockedConn); ok {
+		lc.caps = caps
+	}
+}
+
+// connCaps returns the most a new Unix socket connection may be granted:
+// everything for the daemon's own user or when credentials are unknown
+// (admitPeer only lets those through while the socket is owner-only), and
+// the policy's access level for other allowed users.
+func (s *IPCServer) connCaps(peer *PeerCred) capSet {
+	if peer == nil || s.peerPolicy == nil || peer.UID == s.peerPolicy.ownUID {
+		return allCaps()
+	}
+	return s.peerPolicy.othersCaps
+}
+
+// authorize checks that conn may call method.
+func (s *IPCServer) authorize(conn net.Conn, req RPCRequest) *RPCResponse {
+	need := methodCapabilities[req.Method]
+	if need == "" || capsOf(conn)[need] {
+		return nil
+	}
+	s.logger.Warn("method refused", "method", req.Method, "requires", need, "peer", peerOf(conn).String())
+	return &RPCResponse{
+		Error: &Error{Code: ErrCodeForbidden, Message: fmt.Sprintf("method %s requires the %s capability", req.Method, need)},
+		ID:    req.ID,
+	}
+}
+
+// handleHello negotiates the connection's capabilities. A client asks for
+// the capabilities it needs and is granted those it holds; later calls
+// needing anything else are refused. Grants only ever shrink, so a
+// connection handed to less trusted code stays limited.
+func (s *IPCServer) handleHello(req RPCRequest, conn net.Conn) *RPCResponse {
+	var params HelloParams
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return &RPCResponse{
+				Error: &Error{Code: ErrCodeInvalidParams, Message: "invalid params: " + err.Error()},
+				ID:    req.ID,
+			}
+		}
+	}
+
+	held := capsOf(conn)
+	granted := held
+	if len(params.Capabilities) > 0 {
+		var err error
+		granted, err = held.narrow(params.Capabilities)
+		if err != nil {
+			return &RPCResponse{
+				Error: &Error{Code: ErrCodeInvalidParams, Message: err.Error()},
+				ID:    req.ID,
+			}
+		}
+		setCaps(conn, granted)
+	}
+
+	result := HelloResult{Capabilities: granted.list()}
+	for _, c := range params.Capabilities {
+		if !granted[c] {
+			result.Denied = append(result.Denied, c)
+		}
+	}
+	return &RPCResponse{Result: result, ID: req.ID}
+}
+
+// Hello negotiates capabilities for this client's connection. With no
+// capabilities it reports the current grant without changing it.
+func (c *IPCClient) Hello(ctx context.Context, caps ...Capability) (*HelloResult, error) {
+	if err := c.Connect(ctx); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.call("hello", HelloParams{Capabilities: caps})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Error != nil {
+		return nil, fmt.Errorf("hello error: %s", resp.Error.Message)
+	}
+
+	data, err := json.Marshal(resp.Result)
+	if err != nil {
+		return nil, fmt.Errorf("marshal result: %w", err)
+	}
+
+	var result HelloResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("unmarshal hello: %w", err)
+	}
+
+	return &result, nil
+}
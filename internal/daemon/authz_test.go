@@ -0,0 +1,217 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+func TestMethodCapability(t *testing.T) {
+	tests := []struct {
+		method string
+		want   Capability
+	}{
+		{"ping", ""},
+		{"hello", ""},
+		{"status", CapRead},
+		{"subscribe", CapRead},
+		{"hook_query", CapRead},
+		{"read_model", CapRead},
+		{"notify", CapWrite},
+		{"create_request", CapWrite},
+		{"verify_execute", CapWrite},
+		{"heartbeat", CapWrite},
+	}
+	for _, tt := range tests {
+		if got := MethodCapability(tt.method); got != tt.want {
+			t.Errorf("MethodCapability(%q) = %q, want %q", tt.method, got, tt.want)
+		}
+	}
+}
+
+// startAuthzServer starts a Unix socket server with policy and returns its
+// socket path.
+func startAuthzServer(t *testing.T, policy *PeerPolicy) string {
+	t.Helper()
+	socketPath := filepath.Join(shortSocketDir(t), "test.sock")
+	srv, err := NewIPCServer(socketPath, log.New(io.Discard))
+	if err != nil {
+		t.Fatalf("NewIPCServer: %v", err)
+	}
+	srv.SetPeerPolicy(policy)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() { _ = srv.Start(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		_ = srv.Stop()
+	})
+	return socketPath
+}
+
+func TestIPCServer_HelloNarrowsCapabilities(t *testing.T) {
+	socketPath := startAuthzServer(t, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	c := NewIPCClient(socketPath)
+	defer c.Close()
+
+	held, err := c.Hello(ctx)
+	if err != nil {
+		t.Fatalf("Hello: %v", err)
+	}
+	if len(held.Capabilities) != 2 {
+		t.Fatalf("expected the daemon's own user to hold read and write, got %v", held.Capabilities)
+	}
+	if err := c.Notify(ctx, "test", nil); err != nil {
+		t.Fatalf("Notify before narrowing: %v", err)
+	}
+
+	got, err := c.Hello(ctx, CapRead)
+	if err != nil {
+		t.Fatalf("Hello(read): %v", err)
+	}
+	if len(got.Capabilities) != 1 || got.Capabilities[0] != CapRead || len(got.Denied) != 0 {
+		t.Fatalf("unexpected grant %+v", got)
+	}
+
+	if _, err := c.Status(ctx); err != nil {
+		t.Fatalf("Status with read: %v", err)
+	}
+	if err := c.Ping(ctx); err != nil {
+		t.Fatalf("Ping with read: %v", err)
+	}
+	err = c.Notify(ctx, "test", nil)
+	if err == nil || !strings.Contains(err.Error(), "requires the write capability") {
+		t.Fatalf("expected notify to be refused, got %v", err)
+	}
+	if _, err := c.CreateRequest(ctx, CreateRequestParams{SessionID: "s", Command: "ls"}); err == nil {
+		t.Fatal("expected create_request to be refused")
+	}
+
+	// Grants never widen again.
+	got, err = c.Hello(ctx, CapRead, CapWrite)
+	if err != nil {
+		t.Fatalf("Hello(read, write): %v", err)
+	}
+	if len(got.Capabilities) != 1 || len(got.Denied) != 1 || got.Denied[0] != CapWrite {
+		t.Fatalf("expected write to stay denied, got %+v", got)
+	}
+
+	// Other connections are unaffected.
+	other := NewIPCClient(socketPath)
+	defer other.Close()
+	if err := other.Notify(ctx, "test", nil); err != nil {
+		t.Fatalf("Notify on a fresh connection: %v", err)
+	}
+
+	if _, err := c.Hello(ctx, "admin"); err == nil || !strings.Contains(err.Error(), "unknown capability") {
+		t.Fatalf("expected an unknown capability to be an error, got %v", err)
+	}
+}
+
+func TestIPCServer_PeerAccessReadOnly(t *testing.T) {
+	if !PeerCredSupported() {
+		t.Skip("peer credentials not supported on this platform")
+	}
+
+	policy, err := NewPeerPolicy([]string{strconv.Itoa(os.Getuid())}, nil, "read")
+	if err != nil {
+		t.Fatalf("NewPeerPolicy: %v", err)
+	}
+	// Pretend the daemon runs as someone else, so this user is a listed peer.
+	policy.ownUID = -1
+	socketPath := startAuthzServer(t, policy)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	c := NewIPCClient(socketPath)
+	defer c.Close()
+
+	got, err := c.Hello(ctx, CapRead, CapWrite)
+	if err != nil {
+		t.Fatalf("Hello: %v", err)
+	}
+	if len(got.Capabilities) != 1 || got.Capabilities[0] != CapRead {
+		t.Fatalf("expected a read-only peer, got %+v", got)
+	}
+	if _, err := c.Status(ctx); err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if err := c.Notify(ctx, "test", nil); err == nil {
+		t.Fatal("expected notify from a read-only peer to be refused")
+	}
+}
+
+func TestTCPServer_Capabilities(t *testing.T) {
+	srv, err := NewTCPServer(TCPServerOptions{
+		Addr:        "127.0.0.1:0",
+		RequireAuth: false,
+		ValidateAuth: func(_ context.Context, sessionKey string) (bool, error) {
+			return sessionKey == "good", nil
+		},
+	}, log.New(io.Discard))
+	if err != nil {
+		t.Fatalf("NewTCPServer: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = srv.Start(ctx) }()
+	t.Cleanup(func() { _ = srv.Stop() })
+	addr := srv.listener.Addr().String()
+
+	// notify sends a handshake and a notify call and returns the error
+	// code, 0 on success, or -1 if the connection was dropped.
+	notify := func(handshake string) int {
+		conn, err := net.DialTimeout("tcp", addr, 500*time.Millisecond)
+		if err != nil {
+			t.Fatalf("dial: %v", err)
+		}
+		defer conn.Close()
+		_ = conn.SetDeadline(time.Now().Add(time.Second))
+		_, _ = conn.Write([]byte(handshake + "\n"))
+		_, _ = conn.Write([]byte(`{"method":"notify","params":{"type":"test"},"id":1}` + "\n"))
+
+		line, err := bufio.NewReader(conn).ReadBytes('\n')
+		if err != nil {
+			return -1
+		}
+		var resp RPCResponse
+		if err := json.Unmarshal(line, &resp); err != nil {
+			t.Fatalf("unmarshal response: %v", err)
+		}
+		if resp.Error != nil {
+			return resp.Error.Code
+		}
+		return 0
+	}
+
+	tests := []struct {
+		name      string
+		handshake string
+		want      int
+	}{
+		{"unauthenticated is read-only", `{"auth":""}`, ErrCodeForbidden},
+		{"authenticated may write", `{"auth":"good"}`, 0},
+		{"handshake narrows to read", `{"auth":"good","capabilities":["read"]}`, ErrCodeForbidden},
+		{"handshake cannot widen", `{"auth":"","capabilities":["read","write"]}`, ErrCodeForbidden},
+		{"unknown capability drops the connection", `{"auth":"good","capabilities":["root"]}`, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := notify(tt.handshake); got != tt.want {
+				t.Fatalf("notify result = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+// Package daemon provides the per-tier auto-approval timer.
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/clock"
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/core/statemachine"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/charmbracelet/log"
+)
+
+// AutoApproveAuthor is recorded as the author of automatic approval decisions.
+const AutoApproveAuthor = "slb-daemon"
+
+// TierAutoApprovePolicy describes automatic approval for a single risk tier.
+type TierAutoApprovePolicy struct {
+	// Enabled turns on automatic approval for the tier.
+	Enabled bool
+	// Delay is how long a request must stay pending before it is approved.
+	Delay time.Duration
+	// Notify sends a desktop notification when a request is auto-approved.
+	Notify bool
+	// MaxScore, when positive, leaves requests with a higher risk score
+	// for a reviewer.
+	MaxScore int
+}
+
+// AutoApprovePoliciesFromConfig builds per-tier policies from the app config.
+// CRITICAL and DANGEROUS tiers are never included.
+func AutoApprovePoliciesFromConfig(cfg config.Config) map[db.RiskTier]TierAutoApprovePolicy {
+	policy := func(t config.PatternTierConfig) TierAutoApprovePolicy {
+		delay := t.AutoApproveDelaySeconds
+		if delay < 0 {
+			delay = 0
+		}
+		return TierAutoApprovePolicy{
+			Enabled:  t.AutoApprove,
+			Delay:    time.Duration(delay) * time.Second,
+			Notify:   t.AutoApproveNotify,
+			MaxScore: t.AutoApproveMaxScore,
+		}
+	}
+	return map[db.RiskTier]TierAutoApprovePolicy{
+		db.RiskTierCaution: policy(cfg.Patterns.Caution),
+	}
+}
+
+// shouldAutoApprove is a SAFETY-CRITICAL pure function deciding whether the
+// daemon may approve a pending request on its own.
+func shouldAutoApprove(req *db.Request, policy TierAutoApprovePolicy, now time.Time) (bool, string) {
+	if req == nil {
+		return false, "no request"
+	}
+	if req.Status != db.StatusPending {
+		return false, "request not pending (status: " + string(req.Status) + ")"
+	}
+	// CRITICAL and DANGEROUS tiers MUST require explicit approval.
+	if req.RiskTier == db.RiskTierCritical || req.RiskTier == db.RiskTierDangerous {
+		return false, "tier " + string(req.RiskTier) + " never auto-approves"
+	}
+	if !policy.Enabled {
+		return false, "auto-approve disabled for tier " + string(req.RiskTier)
+	}
+	if policy.MaxScore > 0 && req.RiskScore > policy.MaxScore {
+		return false, fmt.Sprintf("risk score %d above auto-approve limit %d", req.RiskScore, policy.MaxScore)
+	}
+	if now.Sub(req.CreatedAt) < policy.Delay {
+		return false, "delay not elapsed"
+	}
+	return true, fmt.Sprintf("pending %s with no objection (%s tier auto-approve)",
+		policy.Delay, req.RiskTier)
+}
+
+// AutoApprover periodically approves pending requests whose tier allows it.
+type AutoApprover struct {
+	projectPath string
+	policies    map[db.RiskTier]TierAutoApprovePolicy
+	logger      *log.Logger
+	notifier    DesktopNotifier
+	clock       clock.Clock
+	listener    statemachine.Listener
+}
+
+// NewAutoApprover creates an auto-approval timer for a project.
+func NewAutoApprover(projectPath string, policies map[db.RiskTier]TierAutoApprovePolicy, logger *log.Logger, notifier DesktopNotifier) *AutoApprover {
+	if logger == nil {
+		logger = log.Default()
+	}
+	if notifier == nil {
+		notifier = DesktopNotifierFunc(SendDesktopNotification)
+	}
+	return &AutoApprover{
+		projectPath: projectPath,
+		policies:    policies,
+		logger:      logger,
+		notifier:    notifier,
+		clock:       clock.Real,
+	}
+}
+
+// SetClock sets the clock used for auto-approval delays.
+func (a *AutoApprover) SetClock(c clock.Clock) {
+	a.clock = clock.OrReal(c)
+}
+
+// OnTransition sets a listener for the approvals this auto-approver stores.
+func (a *AutoApprover) OnTransition(l statemachine.Listener) {
+	a.listener = l
+}
+
+// Run checks for eligible requests every interval until ctx is cancelled.
+func (a *AutoApprover) Run(ctx context.Context, interval time.Duration) {
+	if a == nil {
+		return
+	}
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := a.Check(ctx); err != nil {
+				a.logger.Warn("auto-approve check failed", "error", err)
+			}
+		}
+	}
+}
+
+// Check approves all eligible pending requests once and returns how many were approved.
+func (a *AutoApprover) Check(ctx context.Context) (int, error) {
+	if a == nil || strings.TrimSpace(a.projectPath) == "" || !a.anyEnabled() {
+		return 0, nil
+	}
+
+	dbPath := filepath.Join(a.projectPath, ".slb", "state.db")
+	dbConn, err := db.OpenWithOptions(dbPath, db.OpenOptions{
+		CreateIfNotExists: false,
+		InitSchema:        false,
+	})
+	if err != nil {
+		// Treat missing DB as no-op (daemon should not crash).
+		return 0, nil
+	}
+	defer dbConn.Close()
+
+	pending, err := dbConn.ListPendingRequests(a.projectPath)
+	if err != nil {
+		return 0, fmt.Errorf("listing pending requests: %w", err)
+	}
+
+	machine := statemachine.New(dbConn).WithClock(a.clock).OnTransition(a.listener)
+	now := a.clock.Now().UTC()
+	approved := 0
+	for _, req := range pending {
+		if ctx.Err() != nil {
+			return approved, ctx.Err()
+		}
+		ok, reason := shouldAutoApprove(req, a.policies[req.RiskTier], now)
+		if !ok {
+			continue
+		}
+		if err := a.approve(dbConn, machine, req, reason); err != nil {
+			a.logger.Warn("auto-approve failed", "request_id", req.ID, "error", err)
+			continue
+		}
+		approved++
+	}
+	return approved, nil
+}
+
+func (a *AutoApprover) anyEnabled() bool {
+	for _, p := range a.policies {
+		if p.Enabled {
+			return true
+		}
+	}
+	return false
+}
+
+// approve transitions the request and records the automatic decision.
+func (a *AutoApprover) approve(dbConn *db.DB, machine *statemachine.Machine, req *db.Request, reason string) error {
+	// The status update is optimistic: it fails if a reviewer acted first.
+	in := statemachine.Input{Authority: statemachine.AuthorityPolicy, Actor: AutoApproveAuthor, Reason: reason}
+	if _, err := machine.Commit(req.ID, db.StatusApproved, in); err != nil {
+		return fmt.Errorf("transition to approved: %w", err)
+	}
+
+	if err := dbConn.CreateAnnotation(&db.RequestAnnotation{
+		RequestID:      req.ID,
+		Source:         db.AnnotationSourceAuto,
+		Author:         AutoApproveAuthor,
+		RiskSummary:    "Auto-approved: " + reason,
+		Recommendation: db.RecommendationApprove,
+	}); err != nil {
+		a.logger.Warn("failed to record auto-approve decision", "request_id", req.ID, "error", err)
+	}
+
+	a.logger.Warn("request auto-approved",
+		"request_id", req.ID,
+		"tier", req.RiskTier,
+		"command", truncateString(displayCommand(req), 80),
+		"agent", req.RequestorAgent,
+		"reason", reason)
+
+	if a.policies[req.RiskTier].Notify {
+		title := fmt.Sprintf("SLB: Request Auto-Approved (%s)", req.RiskTier)
+		body := fmt.Sprintf("Request %s was auto-approved.\nCommand: %s",
+			truncateID(req.ID, 8), truncateString(displayCommand(req), 50))
+		if err := a.notifier.Notify(title, body); err != nil {
+			a.logger.Debug("desktop notification failed", "error", err)
+		}
+	}
+	return nil
+}
+
+func displayCommand(req *db.Request) string {
+	if req.Command.DisplayRedacted != "" {
+		return req.Command.DisplayRedacted
+	}
+	return req.Command.Raw
+}
@@ -0,0 +1,147 @@
+package daemon
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestShouldAutoApprove(t *testing.T) {
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	enabled := TierAutoApprovePolicy{Enabled: true, Delay: 30 * time.Second}
+	capped := TierAutoApprovePolicy{Enabled: true, Delay: 30 * time.Second, MaxScore: 40}
+
+	cases := []struct {
+		name   string
+		req    *db.Request
+		policy TierAutoApprovePolicy
+		want   bool
+	}{
+		{"nil request", nil, enabled, false},
+		{"eligible caution", &db.Request{Status: db.StatusPending, RiskTier: db.RiskTierCaution, CreatedAt: now.Add(-time.Minute)}, enabled, true},
+		{"delay not elapsed", &db.Request{Status: db.StatusPending, RiskTier: db.RiskTierCaution, CreatedAt: now.Add(-10 * time.Second)}, enabled, false},
+		{"disabled", &db.Request{Status: db.StatusPending, RiskTier: db.RiskTierCaution, CreatedAt: now.Add(-time.Minute)}, TierAutoApprovePolicy{}, false},
+		{"not pending", &db.Request{Status: db.StatusRejected, RiskTier: db.RiskTierCaution, CreatedAt: now.Add(-time.Minute)}, enabled, false},
+		{"critical never", &db.Request{Status: db.StatusPending, RiskTier: db.RiskTierCritical, CreatedAt: now.Add(-time.Hour)}, enabled, false},
+		{"dangerous never", &db.Request{Status: db.StatusPending, RiskTier: db.RiskTierDangerous, CreatedAt: now.Add(-time.Hour)}, enabled, false},
+		{"score within limit", &db.Request{Status: db.StatusPending, RiskTier: db.RiskTierCaution, RiskScore: 40, CreatedAt: now.Add(-time.Minute)}, capped, true},
+		{"score above limit", &db.Request{Status: db.StatusPending, RiskTier: db.RiskTierCaution, RiskScore: 41, CreatedAt: now.Add(-time.Minute)}, capped, false},
+		{"no limit", &db.Request{Status: db.StatusPending, RiskTier: db.RiskTierCaution, RiskScore: 100, CreatedAt: now.Add(-time.Minute)}, enabled, true},
+	}
+	for _, tc := range cases {
+		if got, reason := shouldAutoApprove(tc.req, tc.policy, now); got != tc.want {
+			t.Errorf("%s: shouldAutoApprove = %v (%s), want %v", tc.name, got, reason, tc.want)
+		}
+	}
+}
+
+func TestAutoApprovePoliciesFromConfig(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Patterns.Caution.AutoApproveDelaySeconds = 45
+	cfg.Patterns.Caution.AutoApproveNotify = false
+	cfg.Patterns.Caution.AutoApproveMaxScore = 35
+
+	policies := AutoApprovePoliciesFromConfig(cfg)
+	caution := policies[db.RiskTierCaution]
+	if !caution.Enabled || caution.Delay != 45*time.Second || caution.Notify || caution.MaxScore != 35 {
+		t.Fatalf("unexpected caution policy: %+v", caution)
+	}
+	if _, ok := policies[db.RiskTierCritical]; ok {
+		t.Fatal("critical tier must never have an auto-approve policy")
+	}
+	if _, ok := policies[db.RiskTierDangerous]; ok {
+		t.Fatal("dangerous tier must never have an auto-approve policy")
+	}
+}
+
+func TestAutoApproverCheck(t *testing.T) {
+	project := t.TempDir()
+
+	dbConn, err := db.OpenProjectDB(project)
+	if err != nil {
+		t.Fatalf("open project db: %v", err)
+	}
+	t.Cleanup(func() { _ = dbConn.Close() })
+
+	if err := dbConn.CreateSession(&db.Session{
+		ID:          "s1",
+		AgentName:   "AgentA",
+		Program:     "test",
+		Model:       "model",
+		ProjectPath: project,
+	}); err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+
+	makeReq := func(raw string, tier db.RiskTier) *db.Request {
+		req := &db.Request{
+			ProjectPath:        project,
+			Command:            db.CommandSpec{Raw: raw, Cwd: project},
+			RiskTier:           tier,
+			RequestorSessionID: "s1",
+			RequestorAgent:     "AgentA",
+			RequestorModel:     "model",
+			Justification:      db.Justification{Reason: "test"},
+			MinApprovals:       1,
+		}
+		if err := dbConn.CreateRequest(req); err != nil {
+			t.Fatalf("create request: %v", err)
+		}
+		return req
+	}
+	caution := makeReq("rm ./tmp.txt", db.RiskTierCaution)
+	critical := makeReq("rm -rf /var/lib/app", db.RiskTierCritical)
+
+	notified := 0
+	approver := NewAutoApprover(project, map[db.RiskTier]TierAutoApprovePolicy{
+		db.RiskTierCaution: {Enabled: true, Delay: 30 * time.Second, Notify: true},
+	}, nil, DesktopNotifierFunc(func(title, message string) error {
+		notified++
+		return nil
+	}))
+
+	// Before the delay elapses nothing happens.
+	clk := testutil.NewFakeClock(caution.CreatedAt.Add(10 * time.Second))
+	approver.SetClock(clk)
+	if n, err := approver.Check(context.Background()); err != nil || n != 0 {
+		t.Fatalf("expected no approvals before delay, got %d (err %v)", n, err)
+	}
+
+	clk.Advance(50 * time.Second)
+	n, err := approver.Check(context.Background())
+	if err != nil || n != 1 {
+		t.Fatalf("expected 1 approval, got %d (err %v)", n, err)
+	}
+	if notified != 1 {
+		t.Fatalf("expected 1 notification, got %d", notified)
+	}
+
+	got, err := dbConn.GetRequest(caution.ID)
+	if err != nil {
+		t.Fatalf("get request: %v", err)
+	}
+	if got.Status != db.StatusApproved {
+		t.Fatalf("expected caution request approved, got %s", got.Status)
+	}
+	annotations, err := dbConn.ListAnnotationsForRequest(caution.ID)
+	if err != nil || len(annotations) != 1 || annotations[0].Source != db.AnnotationSourceAuto {
+		t.Fatalf("expected auto decision annotation, got %v (err %v)", annotations, err)
+	}
+
+	got, err = dbConn.GetRequest(critical.ID)
+	if err != nil {
+		t.Fatalf("get request: %v", err)
+	}
+	if got.Status != db.StatusPending {
+		t.Fatalf("critical request must stay pending, got %s", got.Status)
+	}
+
+	// A second pass is a no-op.
+	if n, err := approver.Check(context.Background()); err != nil || n != 0 {
+		t.Fatalf("expected idempotent check, got %d (err %v)", n, err)
+	}
+}
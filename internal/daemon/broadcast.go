@@ -0,0 +1,192 @@
+package daemon
+
+import (
+	"net"
+	"sort"
+	"sync/atomic"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+)
+
+// What happens to a subscriber whose event queue is full.
+const (
+	// OverflowDropOldest discards the subscriber's oldest queued event to
+	// make room, leaving a gap in the sequence numbers it sees.
+	OverflowDropOldest = "drop-oldest"
+	// OverflowDisconnect ends the subscription and closes its connection,
+	// so the client reconnects and resyncs.
+	OverflowDisconnect = "disconnect"
+)
+
+// BroadcastPolicy bounds how far behind a subscriber may fall. Each
+// subscriber has its own queue, so a slow one never holds up delivery to
+// the others.
+type BroadcastPolicy struct {
+	QueueSize int    `json:"queue_size"`
+	Overflow  string `json:"overflow"`
+}
+
+// DefaultBroadcastPolicy queues 100 events per subscriber and drops the
+// oldest past that.
+func DefaultBroadcastPolicy() BroadcastPolicy {
+	return BroadcastPolicy{QueueSize: 100, Overflow: OverflowDropOldest}
+}
+
+// BroadcastPolicyFromConfig returns the policy in the daemon section of cfg.
+func BroadcastPolicyFromConfig(cfg config.Config) BroadcastPolicy {
+	return BroadcastPolicy{
+		QueueSize: cfg.Daemon.SubscriberQueueSize,
+		Overflow:  cfg.Daemon.SubscriberOverflow,
+	}
+}
+
+// SetBroadcastPolicy sets the queue size and overflow policy of
+// subscriptions started from now on.
+func (s *IPCServer) SetBroadcastPolicy(p BroadcastPolicy) {
+	if p.QueueSize < 1 {
+		p.QueueSize = DefaultBroadcastPolicy().QueueSize
+	}
+	if p.Overflow != OverflowDisconnect {
+		p.Overflow = OverflowDropOldest
+	}
+	s.broadcastPolicy = p
+}
+
+// BroadcastStats reports event delivery to subscribers.
+type BroadcastStats struct {
+	Policy BroadcastPolicy `json:"policy"`
+	// Queued and Dropped count events across all subscriptions, ended
+	// ones included.
+	Queued  int64 `json:"queued"`
+	Dropped int64 `json:"dropped"`
+	// Disconnected counts subscriptions ended for falling behind.
+	Disconnected int64             `json:"disconnected"`
+	Subscribers  []SubscriberStats `json:"subscribers,omitempty"`
+}
+
+// SubscriberStats reports one subscription's queue.
+type SubscriberStats struct {
+	ID     int64 `json:"id"`
+	Client int64 `json:"client,omitempty"`
+	// Backlog is how many events are queued and not yet sent.
+	Backlog int   `json:"backlog"`
+	Queued  int64 `json:"queued"`
+	Dropped int64 `json:"dropped"`
+}
+
+// broadcastCounters are the server's totals behind BroadcastStats.
+type broadcastCounters struct {
+	queued       atomic.Int64
+	dropped      atomic.Int64
+	disconnected atomic.Int64
+}
+
+// newSubscriber creates a subscription with a queue sized by the broadcast
+// policy. conn is nil for in-process subscriptions.
+func (s *IPCServer) newSubscriber(conn net.Conn, watch *requestWatch) *subscriber {
+	sub := &subscriber{
+		id:     nextSubscriptionID.Add(1),
+		conn:   conn,
+		events: make(chan Event, s.broadcastPolicy.QueueSize),
+		done:   make(chan struct{}),
+		watch:  watch,
+	}
+	if lc, ok := conn.(*lockedConn); ok && lc.client != nil {
+		sub.clientID = lc.client.id
+	}
+	return sub
+}
+
+// offer queues event for sub without blocking. When the queue is full the
+// oldest event is dropped, or, under OverflowDisconnect, offer returns
+// false and the caller must disconnect sub once it no longer holds
+// subscribersMu.
+func (s *IPCServer) offer(sub *subscriber, event Event) bool {
+	select {
+	case sub.events <- event:
+		s.countQueued(sub)
+		return true
+	default:
+	}
+	if s.broadcastPolicy.Overflow == OverflowDisconnect {
+		s.countDropped(sub)
+		return false
+	}
+	select {
+	case <-sub.events:
+		s.countDropped(sub)
+	default:
+		// The stream took one meanwhile.
+	}
+	select {
+	case sub.events <- event:
+		s.countQueued(sub)
+	default:
+		s.countDropped(sub)
+	}
+	return true
+}
+
+func (s *IPCServer) countQueued(sub *subscriber) {
+	sub.queued.Add(1)
+	s.broadcastCounters.queued.Add(1)
+}
+
+func (s *IPCServer) countDropped(sub *subscriber) {
+	sub.dropped.Add(1)
+	s.broadcastCounters.dropped.Add(1)
+}
+
+// disconnectSlow ends a subscription whose queue overflowed under
+// OverflowDisconnect.
+func (s *IPCServer) disconnectSlow(sub *subscriber) {
+	if s.closeSubscriber(sub.id) == nil {
+		return
+	}
+	s.broadcastCounters.disconnected.Add(1)
+	s.logger.Warn("subscriber disconnected: event queue full",
+		"subscription", sub.id, "client", sub.clientID, "queue_size", cap(sub.events))
+}
+
+// closeSubscriber removes a subscription, ends its stream and closes its
+// connection. It returns nil if the subscription is already gone.
+func (s *IPCServer) closeSubscriber(id int64) *subscriber {
+	s.subscribersMu.Lock()
+	sub, ok := s.subscribers[id]
+	if ok {
+		delete(s.subscribers, id)
+		close(sub.done)
+	}
+	s.subscribersMu.Unlock()
+	if !ok {
+		return nil
+	}
+	if sub.conn != nil {
+		_ = sub.conn.Close()
+	}
+	return sub
+}
+
+// broadcastStats reports the policy, the totals and each subscription's
+// queue, in subscription order.
+func (s *IPCServer) broadcastStats() *BroadcastStats {
+	st := &BroadcastStats{
+		Policy:       s.broadcastPolicy,
+		Queued:       s.broadcastCounters.queued.Load(),
+		Dropped:      s.broadcastCounters.dropped.Load(),
+		Disconnected: s.broadcastCounters.disconnected.Load(),
+	}
+	s.subscribersMu.RLock()
+	for _, sub := range s.subscribers {
+		st.Subscribers = append(st.Subscribers, SubscriberStats{
+			ID:      sub.id,
+			Client:  sub.clientID,
+			Backlog: len(sub.events),
+			Queued:  sub.queued.Load(),
+			Dropped: sub.dropped.Load(),
+		})
+	}
+	s.subscribersMu.RUnlock()
+	sort.Slice(st.Subscribers, func(i, j int) bool { return st.Subscribers[i].ID < st.Subscribers[j].ID })
+	return st
+}
@@ -0,0 +1,107 @@
+package daemon
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/charmbracelet/log"
+)
+
+func TestBroadcastPolicyFromConfig(t *testing.T) {
+	if got, want := BroadcastPolicyFromConfig(config.DefaultConfig()), DefaultBroadcastPolicy(); got != want {
+		t.Fatalf("BroadcastPolicyFromConfig(defaults) = %+v, want %+v", got, want)
+	}
+}
+
+func TestIPCServer_BroadcastDropOldest(t *testing.T) {
+	srv, err := NewIPCServer(filepath.Join(shortSocketDir(t), "test.sock"), log.New(io.Discard))
+	if err != nil {
+		t.Fatalf("NewIPCServer: %v", err)
+	}
+	t.Cleanup(func() { _ = srv.Stop() })
+	srv.SetBroadcastPolicy(BroadcastPolicy{QueueSize: 2, Overflow: OverflowDropOldest})
+
+	slow := srv.SubscribeEvents(-1)
+	defer slow.Close()
+	fast := srv.SubscribeEvents(-1)
+	defer fast.Close()
+
+	var seqs []int64
+	for i := 0; i < 4; i++ {
+		srv.BroadcastEvent("test_event", i)
+		seqs = append(seqs, (<-fast.Events()).Seq)
+	}
+	for _, want := range seqs[2:] {
+		if got := (<-slow.Events()).Seq; got != want {
+			t.Fatalf("slow subscriber got seq %d, want %d (the newest)", got, want)
+		}
+	}
+
+	st := srv.broadcastStats()
+	if st.Queued != 8 || st.Dropped != 2 || st.Disconnected != 0 || len(st.Subscribers) != 2 {
+		t.Fatalf("stats = %+v", st)
+	}
+	if s := st.Subscribers[0]; s.ID != slow.sub.id || s.Dropped != 2 || s.Queued != 4 {
+		t.Errorf("slow subscriber stats = %+v", s)
+	}
+	if s := st.Subscribers[1]; s.Dropped != 0 || s.Backlog != 0 {
+		t.Errorf("fast subscriber stats = %+v", s)
+	}
+}
+
+func TestIPCServer_BroadcastDisconnect(t *testing.T) {
+	socketPath := filepath.Join(shortSocketDir(t), "test.sock")
+	srv, err := NewIPCServer(socketPath, log.New(io.Discard))
+	if err != nil {
+		t.Fatalf("NewIPCServer: %v", err)
+	}
+	srv.SetBroadcastPolicy(BroadcastPolicy{QueueSize: 1, Overflow: OverflowDisconnect})
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+		_ = srv.Stop()
+	})
+	go func() { _ = srv.Start(ctx) }()
+
+	slow := srv.SubscribeEvents(-1)
+	defer slow.Close()
+	fast := srv.SubscribeEvents(-1)
+	defer fast.Close()
+
+	for i := 0; i < 3; i++ {
+		srv.BroadcastEvent("test_event", i)
+		select {
+		case <-fast.Events():
+		case <-time.After(2 * time.Second):
+			t.Fatalf("fast subscriber missed event %d", i)
+		}
+	}
+	select {
+	case <-slow.Done():
+	default:
+		t.Fatal("slow subscriber should be disconnected")
+	}
+	select {
+	case <-fast.Done():
+		t.Fatal("fast subscriber should stay connected")
+	default:
+	}
+
+	client := NewIPCClient(socketPath)
+	defer client.Close()
+	info, err := client.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	st := info.Broadcast
+	if st == nil || st.Policy.Overflow != OverflowDisconnect || st.Disconnected != 1 || st.Dropped != 1 {
+		t.Fatalf("status broadcast = %+v", st)
+	}
+	if len(st.Subscribers) != 1 || st.Subscribers[0].ID != fast.sub.id {
+		t.Errorf("subscribers = %+v, want only the fast one", st.Subscribers)
+	}
+}
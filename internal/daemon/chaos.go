@@ -0,0 +1,185 @@
+package daemon
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ChaosEnv must be set to 1 for the daemon to run with chaos injected, so
+// a stray --chaos cannot degrade a daemon agents rely on.
+const ChaosEnv = "SLB_ALLOW_CHAOS"
+
+// DefaultChaosSpec is what --chaos injects when given no spec.
+const DefaultChaosSpec = "drop=0.1,reset=0.05,slow-db=200ms"
+
+// Chaos is the failures the daemon injects in chaos mode, for integrators
+// checking that their agents and hooks cope when SLB is unavailable.
+type Chaos struct {
+	// DropRate is the fraction of RPC requests read and never answered.
+	DropRate float64
+	// ResetRate is the fraction of RPC requests answered by resetting the
+	// connection.
+	ResetRate float64
+	// SlowDB is added to every statement on the daemon's state database.
+	SlowDB time.Duration
+	// Seed makes the drops and resets repeatable; 0 picks one at random.
+	Seed uint64
+}
+
+// ChaosAllowed reports whether SLB_ALLOW_CHAOS=1 is set.
+func ChaosAllowed() bool {
+	return os.Getenv(ChaosEnv) == "1"
+}
+
+// ParseChaos parses a chaos spec: comma-separated drop=<rate>,
+// reset=<rate>, slow-db=<duration> and seed=<n>, with rates from 0 to 1.
+func ParseChaos(spec string) (*Chaos, error) {
+	c := &Chaos{}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("chaos %q: want key=value", part)
+		}
+		var err error
+		switch strings.TrimSpace(key) {
+		case "drop":
+			c.DropRate, err = parseChaosRate(value)
+		case "reset":
+			c.ResetRate, err = parseChaosRate(value)
+		case "slow-db":
+			c.SlowDB, err = time.ParseDuration(strings.TrimSpace(value))
+			if err == nil && c.SlowDB < 0 {
+				err = fmt.Errorf("must not be negative")
+			}
+		case "seed":
+			c.Seed, err = strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+		default:
+			return nil, fmt.Errorf("chaos %q: unknown key (want drop, reset, slow-db or seed)", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("chaos %s: %w", key, err)
+		}
+	}
+	if c.DropRate+c.ResetRate > 1 {
+		return nil, fmt.Errorf("chaos drop and reset rates add up to more than 1")
+	}
+	return c, nil
+}
+
+func parseChaosRate(value string) (float64, error) {
+	rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, err
+	}
+	if rate < 0 || rate > 1 {
+		return 0, fmt.Errorf("rate %v out of range 0-1", rate)
+	}
+	return rate, nil
+}
+
+// String is the chaos spec c parses from.
+func (c *Chaos) String() string {
+	s := fmt.Sprintf("drop=%g,reset=%g,slow-db=%s", c.DropRate, c.ResetRate, c.SlowDB)
+	if c.Seed != 0 {
+		s += fmt.Sprintf(",seed=%d", c.Seed)
+	}
+	return s
+}
+
+// ChaosStats reports what chaos mode has injected.
+type ChaosStats struct {
+	Spec     string `json:"spec"`
+	Dropped  int64  `json:"dropped"`
+	Resets   int64  `json:"resets"`
+	SlowDBMS int64  `json:"slow_db_ms"`
+}
+
+type chaosAction int
+
+const (
+	chaosNone chaosAction = iota
+	chaosDrop
+	chaosReset
+)
+
+// chaosInjector decides, request by request, which to drop or reset. It
+// is shared by the daemon's Unix socket and TCP servers.
+type chaosInjector struct {
+	cfg Chaos
+
+	mu  sync.Mutex
+	rng *rand.Rand
+
+	dropped atomic.Int64
+	resets  atomic.Int64
+}
+
+func newChaosInjector(cfg Chaos) *chaosInjector {
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+	return &chaosInjector{cfg: cfg, rng: rand.New(rand.NewPCG(seed, seed))}
+}
+
+// roll picks what happens to the next request. A nil injector never
+// injects anything.
+func (ci *chaosInjector) roll() chaosAction {
+	if ci == nil {
+		return chaosNone
+	}
+	ci.mu.Lock()
+	p := ci.rng.Float64()
+	ci.mu.Unlock()
+	switch {
+	case p < ci.cfg.DropRate:
+		ci.dropped.Add(1)
+		return chaosDrop
+	case p < ci.cfg.DropRate+ci.cfg.ResetRate:
+		ci.resets.Add(1)
+		return chaosReset
+	}
+	return chaosNone
+}
+
+func (ci *chaosInjector) stats() *ChaosStats {
+	return &ChaosStats{
+		Spec:     ci.cfg.String(),
+		Dropped:  ci.dropped.Load(),
+		Resets:   ci.resets.Load(),
+		SlowDBMS: ci.cfg.SlowDB.Milliseconds(),
+	}
+}
+
+// SetChaos makes the server drop and reset requests as c says; nil turns
+// chaos off.
+func (s *IPCServer) SetChaos(c *Chaos) {
+	if c == nil {
+		s.chaos = nil
+		return
+	}
+	s.chaos = newChaosInjector(*c)
+}
+
+// resetConn closes conn abruptly: a TCP connection with a reset rather
+// than a clean shutdown.
+func resetConn(conn net.Conn) {
+	if lc, ok := conn.(*lockedConn); ok {
+		conn = lc.Conn
+	}
+	if tc, ok := conn.(*net.TCPConn); ok {
+		_ = tc.SetLinger(0)
+	}
+	_ = conn.Close()
+}
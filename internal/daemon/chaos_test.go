@@ -0,0 +1,121 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+func TestParseChaos(t *testing.T) {
+	c, err := ParseChaos("drop=0.2, reset=0.1,slow-db=50ms,seed=7")
+	if err != nil {
+		t.Fatalf("ParseChaos: %v", err)
+	}
+	if c.DropRate != 0.2 || c.ResetRate != 0.1 || c.SlowDB != 50*time.Millisecond || c.Seed != 7 {
+		t.Errorf("parsed %+v", c)
+	}
+	if got := c.String(); got != "drop=0.2,reset=0.1,slow-db=50ms,seed=7" {
+		t.Errorf("String() = %q", got)
+	}
+	if _, err := ParseChaos(DefaultChaosSpec); err != nil {
+		t.Errorf("default spec: %v", err)
+	}
+
+	for _, spec := range []string{"drop", "drop=2", "reset=-0.1", "slow-db=fast", "slow-db=-1s", "flaky=1", "drop=0.6,reset=0.5"} {
+		if _, err := ParseChaos(spec); err == nil {
+			t.Errorf("ParseChaos(%q) should fail", spec)
+		}
+	}
+}
+
+func TestIPCServer_Chaos(t *testing.T) {
+	start := func(t *testing.T, spec string) string {
+		t.Helper()
+		socketPath := filepath.Join(shortSocketDir(t), "c.sock")
+		srv, err := NewIPCServer(socketPath, log.New(io.Discard))
+		if err != nil {
+			t.Fatalf("NewIPCServer: %v", err)
+		}
+		c, err := ParseChaos(spec)
+		if err != nil {
+			t.Fatalf("ParseChaos: %v", err)
+		}
+		srv.SetChaos(c)
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(func() {
+			cancel()
+			_ = srv.Stop()
+		})
+		go func() { _ = srv.Start(ctx) }()
+		return socketPath
+	}
+	call := func(t *testing.T, conn net.Conn, r *bufio.Reader, method string) (*RPCResponse, error) {
+		t.Helper()
+		data, _ := json.Marshal(RPCRequest{Method: method, ID: 1})
+		if _, err := conn.Write(append(data, '\n')); err != nil {
+			return nil, err
+		}
+		_ = conn.SetReadDeadline(time.Now().Add(300 * time.Millisecond))
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			return nil, err
+		}
+		var resp RPCResponse
+		if err := json.Unmarshal(line, &resp); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		return &resp, nil
+	}
+
+	t.Run("drop", func(t *testing.T) {
+		conn, err := net.Dial("unix", start(t, "drop=1,seed=1"))
+		if err != nil {
+			t.Fatalf("dial: %v", err)
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+
+		_, err = call(t, conn, r, "ping")
+		var netErr net.Error
+		if !errors.As(err, &netErr) || !netErr.Timeout() {
+			t.Fatalf("dropped ping: want a read timeout, got %v", err)
+		}
+
+		// status is never dropped, and reports what was.
+		resp, err := call(t, conn, r, "status")
+		if err != nil {
+			t.Fatalf("status: %v", err)
+		}
+		result, _ := resp.Result.(map[string]any)
+		chaos, _ := result["chaos"].(map[string]any)
+		if chaos["dropped"] != float64(1) || !strings.HasPrefix(chaos["spec"].(string), "drop=1,") {
+			t.Errorf("status chaos = %v", result["chaos"])
+		}
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		conn, err := net.Dial("unix", start(t, "reset=1"))
+		if err != nil {
+			t.Fatalf("dial: %v", err)
+		}
+		defer conn.Close()
+
+		_, err = call(t, conn, bufio.NewReader(conn), "ping")
+		if err == nil {
+			t.Fatal("reset ping: want the connection closed")
+		}
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			t.Fatalf("reset ping timed out instead of closing")
+		}
+	})
+}
@@ -18,6 +18,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/Dicklesworthstone/slb/internal/config"
 	"github.com/charmbracelet/log"
 )
 
@@ -91,6 +92,14 @@ func NewClient(opts ...ClientOption) *Client {
 	return c
 }
 
+// Environment overrides for the daemon socket and PID file. They are the
+// variables that set daemon.ipc_socket and daemon.pid_file in the config, so
+// the daemon, the CLI and the hook agree on either source.
+const (
+	SocketPathEnv = "SLB_DAEMON_IPC_SOCKET"
+	PIDFileEnv    = "SLB_DAEMON_PID_FILE"
+)
+
 // DefaultSocketPath returns the default Unix socket path for the current project.
 // Format: /tmp/slb-{project-hash}.sock
 //
@@ -103,17 +112,58 @@ func NewClient(opts ...ClientOption) *Client {
 //
 // Falls back to the raw CWD if no .slb/ ancestor exists, which
 // preserves the v0.3.x behavior for setups that don't use `slb init`.
+//
+// SLB_DAEMON_IPC_SOCKET or daemon.ipc_socket replaces the hashed path, so
+// several daemons (say staging and prod) can serve the same directory.
 func DefaultSocketPath() string {
 	cwd, err := os.Getwd()
 	if err != nil {
 		cwd = "."
 	}
-	hashBase := projectRootForSocket(cwd)
+	return SocketPathFor(cwd)
+}
+
+// SocketPathFor returns the daemon socket path for the project containing
+// dir, as DefaultSocketPath does for the current directory.
+func SocketPathFor(dir string) string {
+	hashBase := projectRootForSocket(dir)
+	if socket, _ := daemonPathOverrides(hashBase); socket != "" {
+		return socket
+	}
 	hash := sha256.Sum256([]byte(hashBase))
 	shortHash := hex.EncodeToString(hash[:])[:12]
 	return filepath.Join(os.TempDir(), fmt.Sprintf("slb-%s.sock", shortHash))
 }
 
+// daemonPathOverrides returns the socket and PID file configured for the
+// project rooted at root, from the environment or else the config files.
+// Relative paths are taken from the project root. Empty strings mean the
+// defaults apply.
+func daemonPathOverrides(root string) (socket, pidFile string) {
+	socket = strings.TrimSpace(os.Getenv(SocketPathEnv))
+	pidFile = strings.TrimSpace(os.Getenv(PIDFileEnv))
+	if socket == "" || pidFile == "" {
+		// An unreadable config leaves the defaults in place; commands that
+		// need the config report its errors themselves.
+		if cfg, err := config.Load(config.LoadOptions{ProjectDir: root}); err == nil {
+			if socket == "" {
+				socket = strings.TrimSpace(cfg.Daemon.IPCSocket)
+			}
+			if pidFile == "" {
+				pidFile = strings.TrimSpace(cfg.Daemon.PIDFile)
+			}
+		}
+	}
+	return underRoot(root, socket), underRoot(root, pidFile)
+}
+
+func underRoot(root, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(root, path)
+}
+
 // projectRootForSocket walks up from `start` looking for a `.slb/`
 // directory and returns the absolute path of the directory containing
 // it. Returns the input directory unchanged if no .slb/ ancestor is
@@ -142,7 +192,29 @@ func projectRootForSocket(start string) string {
 
 // DefaultPIDFile returns the default PID file path.
 // Format: /tmp/slb-daemon-{username}.pid
+//
+// SLB_DAEMON_PID_FILE or daemon.pid_file replaces it. Without one, a daemon
+// on an overridden socket keeps its PID next to the socket, so separate
+// instances never share a PID file.
 func DefaultPIDFile() string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		cwd = "."
+	}
+	return PIDFileFor(cwd)
+}
+
+// PIDFileFor returns the daemon PID file for the project containing dir,
+// as DefaultPIDFile does for the current directory.
+func PIDFileFor(dir string) string {
+	socket, pidFile := daemonPathOverrides(projectRootForSocket(dir))
+	if pidFile != "" {
+		return pidFile
+	}
+	if socket != "" {
+		return strings.TrimSuffix(socket, filepath.Ext(socket)) + ".pid"
+	}
+
 	username := "unknown"
 	if u, err := user.Current(); err == nil {
 		username = u.Username
@@ -302,7 +374,7 @@ func (c *Client) checkSocketConnectivity(ctx context.Context) socketConnectivity
 	}
 
 	if result.host != "" {
-		result.tcpErr = pingDaemonTCP(ctx, result.host, strings.TrimSpace(os.Getenv("SLB_SESSION_KEY")))
+		result.tcpErr = pingDaemonTCP(ctx, result.host, tcpAuthKey())
 		if result.tcpErr == nil {
 			result.transport = "tcp"
 			return result
@@ -455,7 +527,7 @@ func (c *Client) MustHaveDaemon() error {
 	if c.IsDaemonRunning() {
 		return nil
 	}
-	return fmt.Errorf("daemon not running - start with: slb daemon start")
+	return fmt.Errorf("%w: not running - start with: slb daemon start", ErrDaemonUnreachable)
 }
 
 // TryDaemon attempts to communicate with daemon, returning whether it succeeded.
@@ -51,6 +51,58 @@ func TestDefaultPIDFile(t *testing.T) {
 	}
 }
 
+func TestSocketPathFor_EnvOverride(t *testing.T) {
+	project := t.TempDir()
+	t.Setenv(SocketPathEnv, "/run/slb/staging.sock")
+	t.Setenv(PIDFileEnv, "")
+
+	if got := SocketPathFor(project); got != "/run/slb/staging.sock" {
+		t.Fatalf("SocketPathFor = %q, want the env override", got)
+	}
+	// The PID file follows the socket so instances don't share one.
+	if got := PIDFileFor(project); got != "/run/slb/staging.pid" {
+		t.Fatalf("PIDFileFor = %q, want /run/slb/staging.pid", got)
+	}
+
+	t.Setenv(PIDFileEnv, "/run/slb/custom.pid")
+	if got := PIDFileFor(project); got != "/run/slb/custom.pid" {
+		t.Fatalf("PIDFileFor = %q, want the env override", got)
+	}
+}
+
+func TestSocketPathFor_ConfigOverride(t *testing.T) {
+	t.Setenv(SocketPathEnv, "")
+	t.Setenv(PIDFileEnv, "")
+	project := t.TempDir()
+	hashed := SocketPathFor(project)
+
+	if err := os.MkdirAll(filepath.Join(project, ".slb", "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := "[daemon]\nipc_socket = \"run/prod.sock\"\n"
+	if err := os.WriteFile(filepath.Join(project, ".slb", "config.toml"), []byte(cfg), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	want := filepath.Join(project, "run", "prod.sock")
+	if got := SocketPathFor(project); got != want {
+		t.Fatalf("SocketPathFor = %q, want %q (hashed default was %q)", got, want, hashed)
+	}
+	// Sub-directories of the project resolve to the same socket.
+	if got := SocketPathFor(filepath.Join(project, ".slb", "sub")); got != want {
+		t.Fatalf("SocketPathFor(sub) = %q, want %q", got, want)
+	}
+	if got := PIDFileFor(project); got != filepath.Join(project, "run", "prod.pid") {
+		t.Fatalf("PIDFileFor = %q", got)
+	}
+
+	// The environment wins over the config file.
+	t.Setenv(SocketPathEnv, "/tmp/slb-env.sock")
+	if got := SocketPathFor(project); got != "/tmp/slb-env.sock" {
+		t.Fatalf("SocketPathFor = %q, want the env override", got)
+	}
+}
+
 func TestNewClient(t *testing.T) {
 	// Default client
 	c := NewClient()
@@ -0,0 +1,255 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"os"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/integrations"
+	"github.com/Dicklesworthstone/slb/internal/keyring"
+)
+
+// CreateRequestParams are parameters for the create_request method.
+type CreateRequestParams struct {
+	SessionID      string            `json:"session_id"`
+	Command        string            `json:"command"`
+	Cwd            string            `json:"cwd"`
+	Shell          bool              `json:"shell,omitempty"`
+	Reason         string            `json:"reason,omitempty"`
+	ExpectedEffect string            `json:"expected_effect,omitempty"`
+	Goal           string            `json:"goal,omitempty"`
+	SafetyArgument string            `json:"safety_argument,omitempty"`
+	Labels         map[string]string `json:"labels,omitempty"`
+	// Path is the client's PATH, used to record which executable the
+	// command would run. The daemon's own PATH is used when empty.
+	Path string `json:"path,omitempty"`
+	// Env is the client's environment (KEY=VALUE entries), from which the
+	// pinned variables are recorded. Without it no variables are pinned.
+	Env []string `json:"env,omitempty"`
+	// IdempotencyKey makes a resubmission after a lost response return the
+	// original request instead of creating a duplicate.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+
+	// Attachments give reviewers context, such as the transcript a hook
+	// attaches to the request it submits.
+	Attachments []db.Attachment `json:"attachments,omitempty"`
+
+	// Provenance (optional): which agent tool call attempted the command.
+	AgentProgram   string `json:"agent_program,omitempty"`
+	ConversationID string `json:"conversation_id,omitempty"`
+	ToolCallID     string `json:"tool_call_id,omitempty"`
+}
+
+// CreateRequestResponse is the result of the create_request method.
+type CreateRequestResponse struct {
+	RequestID    string `json:"request_id,omitempty"`
+	Status       string `json:"status"` // request status, or "skipped"
+	Tier         string `json:"tier,omitempty"`
+	MinApprovals int    `json:"min_approvals,omitempty"`
+	SkipReason   string `json:"skip_reason,omitempty"`
+	Replayed     bool   `json:"replayed,omitempty"`
+	// TemplateVars are the command's unexpanded placeholders, which a
+	// reviewer must acknowledge to approve it.
+	TemplateVars []string `json:"template_vars,omitempty"`
+}
+
+// RequestCreatorFromConfig creates a request creator using the rate limits
+// and request settings of the app config.
+func RequestCreatorFromConfig(database *db.DB, cfg config.Config) *core.RequestCreator {
+	action := core.RateLimitAction(cfg.RateLimits.RateLimitAction)
+	switch action {
+	case core.RateLimitActionReject, core.RateLimitActionQueue, core.RateLimitActionWarn:
+		// Valid
+	default:
+		action = core.RateLimitActionReject
+	}
+	limiter := core.NewRateLimiter(database, core.RateLimitConfig{
+		MaxPendingPerSession: cfg.RateLimits.MaxPendingPerSession,
+		MaxRequestsPerMinute: cfg.RateLimits.MaxRequestsPerMinute,
+		Action:               action,
+	})
+
+	timeoutMinutes := int(math.Ceil(float64(cfg.General.RequestTimeoutSecs) / 60.0))
+	if timeoutMinutes <= 0 {
+		timeoutMinutes = 30
+	}
+	// Entries were checked when the config loaded.
+	tierOverrides, _ := core.ParseTierOverrides(cfg.Agents.TierOverrides)
+	creator := core.NewRequestCreator(database, limiter, nil, &core.RequestCreatorConfig{
+		BlockedAgents:              cfg.Agents.Blocked,
+		DynamicQuorumFloor:         1,
+		RequestTimeoutMinutes:      timeoutMinutes,
+		ApprovalTTLMinutes:         cfg.General.ApprovalTTLMins,
+		ApprovalTTLCriticalMinutes: cfg.General.ApprovalTTLCriticalMins,
+		AgentMailEnabled:           cfg.Integrations.AgentMailEnabled,
+		AgentMailThread:            cfg.Integrations.AgentMailThread,
+		AdvisoryTimeout:            time.Duration(cfg.Integrations.LLMReviewTimeoutSecs) * time.Second,
+		IdempotencyTTLMinutes:      cfg.General.IdempotencyTTLMins,
+		TierOverrides:              tierOverrides,
+		PinnedEnv:                  cfg.General.PinnedEnv,
+		Anomaly: core.AnomalyConfig{
+			Enabled:        cfg.Anomaly.Enabled,
+			BurstWindow:    time.Duration(cfg.Anomaly.BurstWindowSecs) * time.Second,
+			BurstThreshold: cfg.Anomaly.BurstThreshold,
+			NovelFamily:    cfg.Anomaly.NovelFamily,
+			MinHistory:     cfg.Anomaly.MinHistory,
+			BaselineDays:   cfg.Anomaly.BaselineDays,
+		},
+	})
+	if advisor := LLMAdvisorFromConfig(cfg); advisor != nil {
+		creator.SetAdvisor(advisor)
+	}
+	return creator
+}
+
+// SetRequestCreator configures the creator used by create_request.
+func (s *IPCServer) SetRequestCreator(rc *core.RequestCreator) {
+	s.creator = rc
+}
+
+// SetHookAutoRequest controls whether hook_query returns create_request
+// params for the commands it blocks.
+func (s *IPCServer) SetHookAutoRequest(enabled bool) {
+	s.hookAutoRequest = enabled
+}
+
+// handleCreateRequest handles the create_request IPC method. The client's
+// provenance fields and, when known, its peer credentials are stored with
+// the request's provenance. ctx bounds the fetches of any scripts the
+// command downloads, so a shutdown does not wait on them.
+func (s *IPCServer) handleCreateRequest(ctx context.Context, req RPCRequest, peer *PeerCred) *RPCResponse {
+	if s.creator == nil {
+		return &RPCResponse{
+			Error: &Error{Code: ErrCodeInternal, Message: "request creation not configured"},
+			ID:    req.ID,
+		}
+	}
+
+	var params CreateRequestParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return &RPCResponse{
+			Error: &Error{Code: ErrCodeInvalidParams, Message: "invalid params: " + err.Error()},
+			ID:    req.ID,
+		}
+	}
+
+	provenance := &db.RequestProvenance{
+		AgentProgram:   params.AgentProgram,
+		ConversationID: params.ConversationID,
+		ToolCallID:     params.ToolCallID,
+	}
+	if peer != nil {
+		uid, gid, pid := peer.UID, peer.GID, peer.PID
+		provenance.PeerUID, provenance.PeerGID, provenance.PeerPID = &uid, &gid, &pid
+	}
+
+	result, err := s.creator.CreateRequest(ctx, core.CreateRequestOptions{
+		SessionID: params.SessionID,
+		Command:   params.Command,
+		Cwd:       params.Cwd,
+		Shell:     params.Shell,
+		PathEnv:   params.Path,
+		Environ:   params.Env,
+		Justification: core.Justification{
+			Reason:         params.Reason,
+			ExpectedEffect: params.ExpectedEffect,
+			Goal:           params.Goal,
+			SafetyArgument: params.SafetyArgument,
+		},
+		Attachments:    params.Attachments,
+		Labels:         params.Labels,
+		IdempotencyKey: params.IdempotencyKey,
+		Provenance:     provenance,
+	})
+	if err != nil {
+		code := ErrCodeInternal
+		if isCreateRequestParamsError(err) {
+			code = ErrCodeInvalidParams
+		}
+		return &RPCResponse{
+			Error: &Error{Code: code, Message: err.Error()},
+			ID:    req.ID,
+		}
+	}
+
+	resp := CreateRequestResponse{Status: "skipped", SkipReason: result.SkipReason}
+	if result.Classification != nil {
+		resp.Tier = string(result.Classification.Tier)
+	}
+	if r := result.Request; r != nil {
+		resp = CreateRequestResponse{
+			RequestID:    r.ID,
+			Status:       string(r.Status),
+			Tier:         string(r.RiskTier),
+			MinApprovals: r.MinApprovals,
+			Replayed:     result.Replayed,
+			TemplateVars: result.TemplateVars,
+		}
+		if !result.Replayed {
+			s.logger.Info("request created", "request_id", r.ID, "tier", r.RiskTier, "peer", peer.String())
+			if s.readModel != nil {
+				s.readModel.Invalidate()
+			}
+		}
+	}
+
+	return &RPCResponse{
+		Result: resp,
+		ID:     req.ID,
+	}
+}
+
+// isCreateRequestParamsError reports whether err is the caller's fault
+// rather than the daemon's.
+func isCreateRequestParamsError(err error) bool {
+	for _, target := range []error{
+		core.ErrSessionRequired, core.ErrCommandRequired, core.ErrSessionNotFound,
+		core.ErrSessionInactive, core.ErrAgentBlocked, core.ErrInvalidInput, db.ErrIdempotencyKeyReused,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// LLMReviewToken is the keyring name of the LLM reviewer's API key.
+const LLMReviewToken = "llm-review-api-key"
+
+// LLMAdvisorFromConfig returns the configured LLM second-opinion reviewer,
+// or nil when disabled. The API key is read from SLB_LLM_REVIEW_API_KEY, or
+// from the keyring (`slb keyring set llm-review-api-key`), so it never lands
+// in config files.
+func LLMAdvisorFromConfig(cfg config.Config) core.AdvisoryReviewer {
+	if !cfg.Integrations.LLMReviewEnabled || cfg.Integrations.LLMReviewEndpoint == "" {
+		return nil
+	}
+	return integrations.NewLLMReviewer(
+		cfg.Integrations.LLMReviewEndpoint,
+		cfg.Integrations.LLMReviewModel,
+		LLMReviewAPIKey(cfg),
+		time.Duration(cfg.Integrations.LLMReviewTimeoutSecs)*time.Second,
+	)
+}
+
+// LLMReviewAPIKey returns SLB_LLM_REVIEW_API_KEY, or the stored token.
+func LLMReviewAPIKey(cfg config.Config) string {
+	if key := os.Getenv("SLB_LLM_REVIEW_API_KEY"); key != "" {
+		return key
+	}
+	store, err := keyring.OpenDefault(cfg.General.SessionKeyStore)
+	if err != nil || store == nil {
+		return ""
+	}
+	key, err := store.Get(keyring.TokenAccount(LLMReviewToken))
+	if err != nil {
+		return ""
+	}
+	return key
+}
@@ -6,7 +6,9 @@ package daemon
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"os/signal"
@@ -16,8 +18,10 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/Dicklesworthstone/slb/internal/clock"
 	"github.com/Dicklesworthstone/slb/internal/config"
 	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/core/statemachine"
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/Dicklesworthstone/slb/internal/utils"
 	"github.com/charmbracelet/log"
@@ -30,6 +34,25 @@ type ServerOptions struct {
 	SocketPath string
 	PIDFile    string
 	Logger     *log.Logger
+
+	// ProjectPath is the project the daemon serves; the working directory
+	// when empty.
+	ProjectPath string
+	// Notifier delivers desktop notifications; platform tools when nil.
+	Notifier DesktopNotifier
+	// Clock drives expiry, auto-approval and inactivity checks; the real
+	// clock when nil.
+	Clock clock.Clock
+	// TimeoutCheckInterval, when set, replaces the schedule of the
+	// expiry_sweep job.
+	TimeoutCheckInterval time.Duration
+	// AdminIn, when set, is read for admin REPL commands whose output goes
+	// to AdminOut (see Admin.RunREPL); `quit` stops the daemon.
+	AdminIn  io.Reader
+	AdminOut io.Writer
+	// Chaos, when set, injects failures (see Chaos). RunDaemon refuses it
+	// unless SLB_ALLOW_CHAOS=1.
+	Chaos *Chaos
 }
 
 // DefaultServerOptions returns defaults aligned with the daemon client.
@@ -63,7 +86,13 @@ func StartDaemonWithOptions(ctx context.Context, opts ServerOptions) error {
 	}
 
 	// Fork this binary with the same args, but in daemon mode.
-	cmd := exec.Command(os.Args[0], os.Args[1:]...)
+	return forkDaemon(opts, os.Args[1:])
+}
+
+// forkDaemon runs this binary with args in daemon mode as a detached
+// subprocess and records its PID.
+func forkDaemon(opts ServerOptions, args []string) error {
+	cmd := exec.Command(os.Args[0], args...)
 	cmd.Env = append(os.Environ(), daemonModeEnv+"=1")
 
 	// Best-effort: detach. Parent writes PID immediately.
@@ -80,6 +109,34 @@ func StartDaemonWithOptions(ctx context.Context, opts ServerOptions) error {
 	return nil
 }
 
+// RestartDaemon replaces a running daemon: it stops the current one,
+// killing it if it does not exit within timeout, and starts a new one by
+// running this binary with args in daemon mode. args must name the command
+// that runs the daemon (e.g. "daemon", "start"), since the caller is
+// usually some other command.
+func RestartDaemon(opts ServerOptions, timeout time.Duration, args ...string) error {
+	opts = normalizeServerOptions(opts)
+
+	if running, pid := daemonRunning(opts); running {
+		if err := StopDaemonWithOptions(opts, timeout); err != nil {
+			// A wedged daemon ignores SIGTERM.
+			if proc, err := os.FindProcess(pid); err == nil {
+				_ = proc.Kill()
+			}
+			deadline := time.Now().Add(2 * time.Second)
+			for processAlive(pid) && time.Now().Before(deadline) {
+				time.Sleep(100 * time.Millisecond)
+			}
+			if processAlive(pid) {
+				return fmt.Errorf("daemon did not exit after kill (pid=%d)", pid)
+			}
+		}
+	}
+	_ = os.Remove(opts.PIDFile)
+
+	return forkDaemon(opts, args)
+}
+
 // StopDaemon attempts to stop the daemon gracefully.
 func StopDaemon(timeout time.Duration) error {
 	return StopDaemonWithOptions(DefaultServerOptions(), timeout)
@@ -120,6 +177,10 @@ func StopDaemonWithOptions(opts ServerOptions, timeout time.Duration) error {
 func RunDaemon(ctx context.Context, opts ServerOptions) error {
 	opts = normalizeServerOptions(opts)
 
+	if opts.Chaos != nil && !ChaosAllowed() {
+		return fmt.Errorf("chaos mode requires %s=1", ChaosEnv)
+	}
+
 	logger := opts.Logger
 	if logger == nil {
 		l, err := utils.InitDaemonLogger()
@@ -148,13 +209,22 @@ func RunDaemon(ctx context.Context, opts ServerOptions) error {
 		return fmt.Errorf("creating ipc server: %w", err)
 	}
 
-	// Stop on signal or context cancellation.
+	// Stop on signal, context cancellation or the admin REPL's quit.
+	ctx, quit := context.WithCancel(ctx)
+	defer quit()
 	signalCtx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
 	logger.Info("daemon started", "pid", os.Getpid(), "pid_file", opts.PIDFile, "socket", opts.SocketPath)
+	if opts.Chaos != nil {
+		ipcServer.SetChaos(opts.Chaos)
+		logger.Warn("chaos mode: injecting failures", "chaos", opts.Chaos.String())
+	}
 
-	projectPath, _ := os.Getwd()
+	projectPath := opts.ProjectPath
+	if projectPath == "" {
+		projectPath, _ = os.Getwd()
+	}
 	cfg := config.DefaultConfig()
 	if loaded, err := config.Load(config.LoadOptions{ProjectDir: projectPath}); err != nil {
 		logger.Warn("failed to load config; using defaults", "error", err)
@@ -162,6 +232,26 @@ func RunDaemon(ctx context.Context, opts ServerOptions) error {
 		cfg = loaded
 	}
 
+	// Only the daemon's user and the configured peers may use the socket.
+	peerPolicy, err := NewPeerPolicy(cfg.Daemon.AllowedPeerUsers, cfg.Daemon.AllowedPeerGroups, cfg.Daemon.AllowedPeerAccess)
+	if err != nil {
+		_ = ipcServer.Stop()
+		return err
+	}
+	ipcServer.SetPeerPolicy(peerPolicy)
+	ipcServer.SetLimits(IPCLimitsFromConfig(cfg))
+	ipcServer.SetBroadcastPolicy(BroadcastPolicyFromConfig(cfg))
+	if peerPolicy.AllowsOthers() {
+		if PeerCredSupported() {
+			// Other users need to reach the socket; peer checks gate them.
+			if err := os.Chmod(opts.SocketPath, 0o666); err != nil {
+				logger.Warn("cannot open socket to allowed peers", "error", err)
+			}
+		} else {
+			logger.Warn("allowed peers ignored: peer credentials are not supported on this platform; socket stays owner-only")
+		}
+	}
+
 	// Merge persisted custom_patterns from `.slb/state.db` into the
 	// shared engine so the daemon's classify path enforces the same
 	// rules `slb patterns add` persisted (issue #2 daemon-side gap).
@@ -172,9 +262,146 @@ func RunDaemon(ctx context.Context, opts ServerOptions) error {
 	// "interception works only when the daemon is down."
 	loadDaemonCustomPatterns(projectPath, logger)
 
-	notifications := NewNotificationManager(projectPath, cfg.Notifications, logger, nil)
+	// Serve pending requests and sessions from memory, reloading after
+	// state.db changes. Without a .slb directory there is nothing to
+	// watch and the read model falls back to its max age.
+	readModel := NewReadModel(projectPath, logger)
+	readModel.SetClock(opts.Clock)
+	readModel.SetApprovalReuse(ApprovalReuseFromConfig(cfg))
+	ipcServer.SetApprovalReuse(ApprovalReuseFromConfig(cfg))
+	ipcServer.SetReadModel(readModel)
+	readModel.OnInvalidate(ipcServer.PokeRequestWatches)
+	if info, err := os.Stat(filepath.Join(projectPath, ".slb")); err == nil && info.IsDir() {
+		if watcher, err := NewWatcher(projectPath); err != nil {
+			logger.Warn("state watcher disabled; read model relies on max age", "error", err)
+		} else if err := watcher.Start(signalCtx); err != nil {
+			logger.Warn("state watcher disabled; read model relies on max age", "error", err)
+		} else {
+			defer watcher.Stop()
+			go readModel.Run(signalCtx, watcher.Events())
+		}
+	}
+
+	notifications := NewNotificationManager(projectPath, cfg.Notifications, logger, opts.Notifier)
+	notifications.SetClock(opts.Clock)
+
+	if stateDB := openDaemonDB(signalCtx, projectPath, logger); stateDB != nil {
+		stateDB.SetClock(opts.Clock)
+		if opts.Chaos != nil {
+			stateDB.SetLatency(opts.Chaos.SlowDB)
+		}
+		defer stateDB.Close()
+
+		// Batch broadcast events and heartbeats into one transaction per
+		// interval so many active agents don't each force an fsync.
+		eventWriter := db.NewBufferedEventWriter(stateDB, db.BufferedWriterOptions{})
+		eventWriter.Start(signalCtx)
+		ipcServer.SetEventWriter(eventWriter)
+		defer func() {
+			if err := eventWriter.Stop(); err != nil {
+				logger.Warn("final event flush failed", "error", err)
+			}
+		}()
+
+		// Count the patterns hook queries match, written once a minute.
+		patternHits := NewPatternHitCounter(stateDB, 0)
+		patternHits.Start(signalCtx)
+		ipcServer.SetPatternHitCounter(patternHits)
+		defer func() {
+			if err := patternHits.Stop(); err != nil {
+				logger.Warn("final pattern hit flush failed", "error", err)
+			}
+		}()
+
+		// Every status change the daemon makes is broadcast to subscribers.
+		machine := statemachine.New(stateDB).OnTransition(statusBroadcaster(ipcServer, readModel))
+
+		// verify_execute is left unconfigured, refusing every request,
+		// when the executor's gates cannot be built.
+		if executor, err := ExecutorFromConfig(stateDB, cfg); err != nil {
+			logger.Error("execution verifier disabled", "error", err)
+		} else {
+			verifier := NewVerifier(stateDB)
+			verifier.SetStateMachine(machine)
+			verifier.SetExecutor(executor)
+			ipcServer.SetVerifier(verifier)
+		}
+		ipcServer.SetRequestCreator(RequestCreatorFromConfig(stateDB, cfg))
+		ipcServer.SetHookAutoRequest(cfg.Integrations.HookAutoRequest)
+		ipcServer.SetDatabase(stateDB)
+		// Push status changes to requestors waiting through watch_requests.
+		go ipcServer.RunRequestWatches(signalCtx, 0)
+
+		timeoutCfg := TimeoutConfigFromConfig(cfg)
+		timeoutCfg.Logger = logger
+		timeoutCfg.Notifier = opts.Notifier
+		sweeper := NewTimeoutHandler(stateDB, timeoutCfg)
+		sweeper.SetStateMachine(machine)
+
+		// Expiry sweeps, review reminders, retention pruning and the weekly
+		// digest run on the job scheduler.
+		jobCfg := cfg
+		if opts.TimeoutCheckInterval > 0 {
+			jobCfg.Jobs.ExpirySweep.Schedule = opts.TimeoutCheckInterval.String()
+		}
+		jobs := &daemonJobs{
+			db:            stateDB,
+			projectPath:   projectPath,
+			cfg:           jobCfg,
+			sweeper:       sweeper,
+			notifications: notifications,
+			broadcast:     ipcServer.BroadcastEvent,
+			logger:        logger,
+		}
+		scheduler := NewScheduler(stateDB, logger)
+		for _, job := range jobs.jobs() {
+			if err := scheduler.Add(job, time.Now()); err != nil {
+				logger.Warn("daemon job disabled", "job", job.Name, "error", err)
+			}
+		}
+		go scheduler.Run(signalCtx)
+
+		if strings.TrimSpace(cfg.Daemon.WebAddr) != "" {
+			web, err := NewWebServer(WebServerOptions{
+				Addr:         cfg.Daemon.WebAddr,
+				AllowedIPs:   cfg.Daemon.WebAllowedIPs,
+				ProjectPath:  projectPath,
+				DB:           stateDB,
+				ReviewConfig: core.ReviewConfigFromConfig(cfg),
+				Machine:      machine,
+				Events:       ipcServer,
+			}, logger)
+			if err != nil {
+				logger.Warn("web UI disabled", "error", err)
+			} else {
+				defer web.Stop()
+				go func() {
+					if err := web.Start(signalCtx); err != nil {
+						logger.Warn("web UI stopped", "error", err)
+					}
+				}()
+				logger.Info("web UI started", "addr", web.Addr())
+			}
+		}
+	}
+
 	go notifications.Run(signalCtx, 10*time.Second)
 
+	autoApprover := NewAutoApprover(projectPath, AutoApprovePoliciesFromConfig(cfg), logger, opts.Notifier)
+	autoApprover.SetClock(opts.Clock)
+	autoApprover.OnTransition(statusBroadcaster(ipcServer, readModel))
+	go autoApprover.Run(signalCtx, 5*time.Second)
+
+	inactivity := NewInactivityMonitor(projectPath, cfg.Notifications, logger, opts.Notifier)
+	inactivity.SetClock(opts.Clock)
+	go inactivity.Run(signalCtx, 30*time.Second)
+
+	telemetry := NewTelemetryReporter(projectPath, cfg.Telemetry, logger)
+	telemetry.SetClock(opts.Clock)
+	go telemetry.Run(signalCtx, 10*time.Minute)
+
+	go NewHistoryReconciler(projectPath, cfg.History, logger).Run(signalCtx)
+
 	servers := []*IPCServer{ipcServer}
 	if strings.TrimSpace(cfg.Daemon.TCPAddr) != "" {
 		tcpSrv, err := NewTCPServer(TCPServerOptions{
@@ -194,21 +421,46 @@ func RunDaemon(ctx context.Context, opts ServerOptions) error {
 				}
 				defer dbConn.Close()
 
-				var count int
-				if err := dbConn.QueryRow(`SELECT COUNT(*) FROM sessions WHERE session_key = ? AND ended_at IS NULL`, sessionKey).Scan(&count); err != nil {
+				if _, err := dbConn.FindActiveSessionByKey(sessionKey); err != nil {
+					if errors.Is(err, db.ErrSessionNotFound) {
+						return false, nil
+					}
 					return false, err
 				}
-				return count > 0, nil
+				return true, nil
 			},
 		}, logger)
 		if err != nil {
 			logger.Warn("tcp listener disabled", "error", err)
 		} else {
+			tcpSrv.SetReadModel(readModel)
+			tcpSrv.SetEventWriter(ipcServer.eventWriter)
+			tcpSrv.SetVerifier(ipcServer.verifier)
+			tcpSrv.SetRequestCreator(ipcServer.creator)
+			tcpSrv.SetHookAutoRequest(ipcServer.hookAutoRequest)
+			tcpSrv.SetApprovalReuse(ipcServer.reuse)
+			tcpSrv.SetDatabase(ipcServer.database)
+			tcpSrv.chaos = ipcServer.chaos
+			tcpSrv.quotas = ipcServer.quotas
+			tcpSrv.SetBroadcastPolicy(ipcServer.broadcastPolicy)
+			if tcpSrv.database != nil {
+				readModel.OnInvalidate(tcpSrv.PokeRequestWatches)
+				go tcpSrv.RunRequestWatches(signalCtx, 0)
+			}
 			servers = append(servers, tcpSrv)
 			logger.Info("tcp listener started", "addr", cfg.Daemon.TCPAddr, "require_auth", cfg.Daemon.TCPRequireAuth)
 		}
 	}
 
+	if opts.AdminIn != nil {
+		out := opts.AdminOut
+		if out == nil {
+			out = io.Discard
+		}
+		admin := NewAdmin(projectPath, servers, readModel, logger)
+		go admin.RunREPL(signalCtx, opts.AdminIn, out, quit)
+	}
+
 	errCh := make(chan error, len(servers))
 	for _, srv := range servers {
 		srv := srv
@@ -327,9 +579,10 @@ func readPIDFile(path string) (int, error) {
 // would be the wrong tradeoff for a safety rail.
 //
 // Idempotent across calls: existing engine entries are not
-// re-added, so this can run at startup AND on a future reload
-// signal without duplicating in-memory state.
-func loadDaemonCustomPatterns(projectPath string, logger *log.Logger) {
+// re-added, so this can run at startup AND on the admin REPL's
+// reload without duplicating in-memory state. It returns how many
+// rows were merged and skipped.
+func loadDaemonCustomPatterns(projectPath string, logger *log.Logger) (loaded, skipped int) {
 	dbPath := filepath.Join(projectPath, ".slb", "state.db")
 	dbConn, err := db.OpenWithOptions(dbPath, db.OpenOptions{
 		CreateIfNotExists: false,
@@ -342,14 +595,14 @@ func loadDaemonCustomPatterns(projectPath string, logger *log.Logger) {
 		// project doesn't pollute the daemon log on every startup.
 		logger.Debug("custom_patterns load skipped (no project DB)",
 			"path", dbPath, "error", err)
-		return
+		return 0, 0
 	}
 	defer dbConn.Close()
 
 	rows, err := dbConn.ListCustomPatterns()
 	if err != nil {
 		logger.Warn("custom_patterns query failed", "error", err)
-		return
+		return 0, 0
 	}
 
 	engine := core.GetDefaultEngine()
@@ -360,8 +613,6 @@ func loadDaemonCustomPatterns(projectPath string, logger *log.Logger) {
 		}
 	}
 
-	loaded := 0
-	skipped := 0
 	for _, row := range rows {
 		tier := parseDaemonTier(row.Tier)
 		if tier == "" {
@@ -387,6 +638,7 @@ func loadDaemonCustomPatterns(projectPath string, logger *log.Logger) {
 		logger.Info("custom_patterns merged into engine",
 			"loaded", loaded, "skipped", skipped)
 	}
+	return loaded, skipped
 }
 
 // parseDaemonTier mirrors internal/cli/patterns.go::parseTier so
@@ -406,3 +658,24 @@ func parseDaemonTier(s string) core.RiskTier {
 		return ""
 	}
 }
+
+// openDaemonDB opens the project database for the event writer, the
+// execution verifier and the timeout sweeper, or returns nil when the
+// project has none.
+func openDaemonDB(ctx context.Context, projectPath string, logger *log.Logger) *db.DB {
+	dbPath := filepath.Join(projectPath, ".slb", "state.db")
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil
+	}
+	dbConn, err := db.OpenWithOptions(dbPath, db.OpenOptions{})
+	if err != nil {
+		logger.Warn("state db unavailable; event writer, verifier and sweeper disabled", "error", err)
+		return nil
+	}
+	if err := dbConn.ApplyMigrations(ctx); err != nil {
+		logger.Warn("state db unavailable; event writer, verifier and sweeper disabled", "error", err)
+		_ = dbConn.Close()
+		return nil
+	}
+	return dbConn
+}
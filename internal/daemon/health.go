@@ -0,0 +1,259 @@
+// Package daemon provides end-to-end health checks of a running daemon.
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// EventHealthProbe is broadcast by health checks to test event delivery.
+// Probe events are neither stored nor treated as state changes.
+const EventHealthProbe = "health_probe"
+
+// Health probe names, in the order CheckHealth runs them.
+const (
+	ProbePing      = "ping"
+	ProbeDB        = "db"
+	ProbeClassify  = "classify"
+	ProbeBroadcast = "broadcast"
+)
+
+// healthProbeCommand is classified by the classify probe. It is never run.
+const healthProbeCommand = "rm -rf ./slb-health-probe"
+
+// DBProbeResult is the result of the db_probe method.
+type DBProbeResult struct {
+	// Configured is false when the daemon serves a project without a
+	// state database, so there is nothing to probe.
+	Configured bool    `json:"configured"`
+	LatencyMs  float64 `json:"latency_ms,omitempty"`
+}
+
+// HealthProbe is the outcome of one step of a health check.
+type HealthProbe struct {
+	Name      string  `json:"name"`
+	OK        bool    `json:"ok"`
+	Skipped   bool    `json:"skipped,omitempty"`
+	LatencyMs float64 `json:"latency_ms"`
+	Detail    string  `json:"detail,omitempty"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// HealthReport is the outcome of CheckHealth.
+type HealthReport struct {
+	Healthy    bool          `json:"healthy"`
+	SocketPath string        `json:"socket_path"`
+	CheckedAt  time.Time     `json:"checked_at"`
+	Probes     []HealthProbe `json:"probes"`
+}
+
+// Failed returns the names of the probes that failed.
+func (r *HealthReport) Failed() []string {
+	var failed []string
+	for _, p := range r.Probes {
+		if !p.OK {
+			failed = append(failed, p.Name)
+		}
+	}
+	return failed
+}
+
+// SetDatabase configures the database exercised by db_probe.
+func (s *IPCServer) SetDatabase(database *db.DB) {
+	s.database = database
+}
+
+// handleDBProbe writes and reads back a throwaway row in the state database.
+func (s *IPCServer) handleDBProbe(req RPCRequest) *RPCResponse {
+	if s.database == nil {
+		return &RPCResponse{
+			Result: DBProbeResult{Configured: false},
+			ID:     req.ID,
+		}
+	}
+
+	start := time.Now()
+	if err := s.database.ProbeWriteRead(); err != nil {
+		return &RPCResponse{
+			Error: &Error{Code: ErrCodeInternal, Message: err.Error()},
+			ID:    req.ID,
+		}
+	}
+	return &RPCResponse{
+		Result: DBProbeResult{Configured: true, LatencyMs: millis(time.Since(start))},
+		ID:     req.ID,
+	}
+}
+
+// DBProbe asks the daemon to write and read back its state database.
+func (c *IPCClient) DBProbe(ctx context.Context) (*DBProbeResult, error) {
+	if err := c.Connect(ctx); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.call("db_probe", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Error != nil {
+		return nil, fmt.Errorf("db_probe error: %s", resp.Error.Message)
+	}
+
+	data, err := json.Marshal(resp.Result)
+	if err != nil {
+		return nil, fmt.Errorf("marshal result: %w", err)
+	}
+
+	var result DBProbeResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("unmarshal db probe: %w", err)
+	}
+
+	return &result, nil
+}
+
+// CheckHealth probes the daemon at socketPath end to end: a ping, a state
+// database write and read, a command classification, and an event
+// broadcast looped back through a subscription. Each probe is bounded by
+// timeout. When the ping fails the remaining probes are skipped and marked
+// failed, since nothing else can succeed.
+func CheckHealth(ctx context.Context, socketPath string, timeout time.Duration) *HealthReport {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	report := &HealthReport{SocketPath: socketPath, CheckedAt: time.Now().UTC()}
+
+	client := NewIPCClient(socketPath)
+	defer client.Close()
+
+	ping := runProbe(ctx, ProbePing, timeout, func(ctx context.Context) (string, error) {
+		if err := client.Connect(ctx); err != nil {
+			return "", err
+		}
+		client.setDeadline(ctx)
+		return "", client.Ping(ctx)
+	})
+	report.Probes = append(report.Probes, ping)
+	if !ping.OK {
+		for _, name := range []string{ProbeDB, ProbeClassify, ProbeBroadcast} {
+			report.Probes = append(report.Probes, HealthProbe{Name: name, Error: "skipped: daemon did not answer ping"})
+		}
+		return report
+	}
+
+	report.Probes = append(report.Probes, runProbe(ctx, ProbeDB, timeout, func(ctx context.Context) (string, error) {
+		client.setDeadline(ctx)
+		result, err := client.DBProbe(ctx)
+		if err != nil {
+			return "", err
+		}
+		if !result.Configured {
+			return "", errProbeSkipped
+		}
+		return "", nil
+	}))
+
+	report.Probes = append(report.Probes, runProbe(ctx, ProbeClassify, timeout, func(ctx context.Context) (string, error) {
+		client.setDeadline(ctx)
+		result, err := client.HookQuery(ctx, HookQueryParams{Command: healthProbeCommand})
+		if err != nil {
+			return "", err
+		}
+		if result.Tier == "" || result.Action == "" {
+			return "", errors.New("classification returned no tier")
+		}
+		return result.Tier, nil
+	}))
+
+	report.Probes = append(report.Probes, runProbe(ctx, ProbeBroadcast, timeout, func(ctx context.Context) (string, error) {
+		return "", probeBroadcast(ctx, socketPath, client)
+	}))
+
+	report.Healthy = len(report.Failed()) == 0
+	return report
+}
+
+// errProbeSkipped marks a probe with nothing to check as passed but skipped.
+var errProbeSkipped = errors.New("skipped")
+
+// runProbe times fn under its own timeout.
+func runProbe(ctx context.Context, name string, timeout time.Duration, fn func(context.Context) (string, error)) HealthProbe {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	detail, err := fn(ctx)
+	probe := HealthProbe{Name: name, OK: err == nil, LatencyMs: millis(time.Since(start)), Detail: detail}
+	switch {
+	case errors.Is(err, errProbeSkipped):
+		probe.OK = true
+		probe.Skipped = true
+		probe.Detail = "no state database"
+	case err != nil:
+		probe.Error = err.Error()
+	}
+	return probe
+}
+
+// probeBroadcast subscribes on a second connection, publishes a probe event
+// with a unique nonce through notifier and waits for it to come back.
+func probeBroadcast(ctx context.Context, socketPath string, notifier *IPCClient) error {
+	sub := NewIPCClient(socketPath)
+	defer sub.Close()
+
+	subCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	events, err := sub.Subscribe(subCtx)
+	if err != nil {
+		return err
+	}
+	sub.setDeadline(ctx)
+
+	nonce := strconv.FormatInt(time.Now().UnixNano(), 36)
+	notifier.setDeadline(ctx)
+	if err := notifier.Notify(ctx, EventHealthProbe, map[string]string{"nonce": nonce}); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("probe event not delivered: %w", ctx.Err())
+		case ev, ok := <-events:
+			if !ok {
+				return errors.New("subscription closed before the probe event arrived")
+			}
+			if ev.Type != EventHealthProbe {
+				continue
+			}
+			if payload, ok := ev.Payload.(map[string]any); ok && payload["nonce"] == nonce {
+				return nil
+			}
+		}
+	}
+}
+
+// setDeadline bounds reads and writes on the connection by ctx's deadline,
+// so a wedged daemon fails a probe instead of hanging it.
+func (c *IPCClient) setDeadline(ctx context.Context) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn != nil {
+		_ = c.conn.SetDeadline(deadline)
+	}
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,47 @@
+package daemon
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCheckHealth_NoDaemon(t *testing.T) {
+	socketPath := filepath.Join(shortSocketDir(t), "missing.sock")
+
+	report := CheckHealth(context.Background(), socketPath, 200*time.Millisecond)
+	if report.Healthy {
+		t.Fatal("expected an unhealthy report without a daemon")
+	}
+	if len(report.Probes) != 4 {
+		t.Fatalf("expected 4 probes, got %+v", report.Probes)
+	}
+	if got := report.Failed(); len(got) != 4 {
+		t.Fatalf("expected every probe to fail, got %v", got)
+	}
+	if report.Probes[1].Error == "" {
+		t.Error("expected skipped probes to say why")
+	}
+}
+
+func TestCheckHealth_WithoutDatabase(t *testing.T) {
+	socketPath := filepath.Join(shortSocketDir(t), "test.sock")
+	srv, err := NewIPCServer(socketPath, nil)
+	if err != nil {
+		t.Fatalf("NewIPCServer: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go srv.Start(ctx)
+	defer srv.Stop()
+
+	report := CheckHealth(ctx, socketPath, 2*time.Second)
+	if !report.Healthy {
+		t.Fatalf("expected a healthy report, got %+v", report.Probes)
+	}
+	db := report.Probes[1]
+	if db.Name != ProbeDB || !db.Skipped {
+		t.Fatalf("expected the db probe to be skipped without a database, got %+v", db)
+	}
+}
@@ -0,0 +1,95 @@
+package daemon
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/git"
+	"github.com/charmbracelet/log"
+)
+
+// HistoryReconciler keeps the history repo in step with the database by
+// backfilling artifacts for finished requests. It does nothing unless a
+// history repo is configured with auto_git_commit on.
+type HistoryReconciler struct {
+	projectPath string
+	cfg         config.HistoryConfig
+	logger      *log.Logger
+}
+
+// NewHistoryReconciler creates a history reconciler for a project.
+func NewHistoryReconciler(projectPath string, cfg config.HistoryConfig, logger *log.Logger) *HistoryReconciler {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &HistoryReconciler{projectPath: projectPath, cfg: cfg, logger: logger}
+}
+
+func (r *HistoryReconciler) enabled() bool {
+	return r != nil && r.cfg.AutoGitCommit && r.cfg.ReconcileIntervalMinutes > 0 &&
+		strings.TrimSpace(r.cfg.GitRepoPath) != "" && strings.TrimSpace(r.projectPath) != ""
+}
+
+// Run reconciles every history.reconcile_interval_minutes until ctx is
+// cancelled.
+func (r *HistoryReconciler) Run(ctx context.Context) {
+	if !r.enabled() {
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(r.cfg.ReconcileIntervalMinutes) * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.Check(); err != nil {
+				r.logger.Warn("history reconcile failed", "error", err)
+			}
+		}
+	}
+}
+
+// Check runs one reconcile pass. Mismatched artifacts are logged, not
+// fixed: they need a human to decide which side is right.
+func (r *HistoryReconciler) Check() (*git.ReconcileReport, error) {
+	if !r.enabled() {
+		return nil, nil
+	}
+
+	dbPath := filepath.Join(r.projectPath, ".slb", "state.db")
+	dbConn, err := db.OpenWithOptions(dbPath, db.OpenOptions{
+		CreateIfNotExists: false,
+		InitSchema:        false,
+	})
+	if err != nil {
+		// Treat missing DB as no-op (daemon should not crash).
+		return nil, nil
+	}
+	defer dbConn.Close()
+
+	repo, err := git.NewHistoryRepo(r.cfg.GitRepoPath)
+	if err != nil {
+		return nil, err
+	}
+	report, err := repo.Reconcile(dbConn, git.ReconcileOptions{ProjectPath: r.projectPath})
+	if err != nil {
+		return nil, err
+	}
+	if report.Backfilled > 0 {
+		r.logger.Info("history repo backfilled", "artifacts", report.Backfilled, "repo", repo.Path)
+	}
+	for _, issue := range report.Issues {
+		if issue.Problem != git.ProblemMissing {
+			r.logger.Warn("history repo artifact does not match the database",
+				"problem", issue.Problem, "kind", issue.Kind, "id", issue.ID, "path", issue.Path)
+		}
+	}
+	return report, nil
+}
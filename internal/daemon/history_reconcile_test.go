@@ -0,0 +1,53 @@
+package daemon
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestHistoryReconcilerCheck(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	project := t.TempDir()
+	dbConn, err := db.OpenProjectDB(project)
+	if err != nil {
+		t.Fatalf("open project db: %v", err)
+	}
+	t.Cleanup(func() { _ = dbConn.Close() })
+
+	sess := testutil.MakeSession(t, dbConn, testutil.WithProject(project))
+	testutil.MakeRequest(t, dbConn, sess, testutil.WithStatus(db.StatusCancelled))
+
+	cfg := config.HistoryConfig{GitRepoPath: t.TempDir(), AutoGitCommit: true, ReconcileIntervalMinutes: 60}
+	for name, disabled := range map[string]config.HistoryConfig{
+		"no repo":        {AutoGitCommit: true, ReconcileIntervalMinutes: 60},
+		"no auto commit": {GitRepoPath: cfg.GitRepoPath, ReconcileIntervalMinutes: 60},
+		"no interval":    {GitRepoPath: cfg.GitRepoPath, AutoGitCommit: true},
+	} {
+		if report, err := NewHistoryReconciler(project, disabled, nil).Check(); err != nil || report != nil {
+			t.Errorf("%s: report=%+v err=%v", name, report, err)
+		}
+	}
+
+	reconciler := NewHistoryReconciler(project, cfg, nil)
+	report, err := reconciler.Check()
+	testutil.RequireNoError(t, err, "first check")
+	if report == nil || report.Requests != 1 || report.Backfilled != 1 {
+		t.Fatalf("first report = %+v", report)
+	}
+	report, err = reconciler.Check()
+	testutil.RequireNoError(t, err, "second check")
+	if report.Backfilled != 0 || len(report.Issues) != 0 {
+		t.Errorf("second report = %+v", report)
+	}
+
+	// A project without a database is skipped.
+	if report, err := NewHistoryReconciler(t.TempDir(), cfg, nil).Check(); err != nil || report != nil {
+		t.Errorf("missing db: report=%+v err=%v", report, err)
+	}
+}
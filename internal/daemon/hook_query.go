@@ -3,7 +3,9 @@ package daemon
 
 import (
 	"encoding/json"
+	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/Dicklesworthstone/slb/internal/core"
@@ -15,6 +17,21 @@ type HookQueryParams struct {
 	Command   string `json:"command"`
 	SessionID string `json:"session_id"`
 	CWD       string `json:"cwd"`
+
+	// Provenance (optional): which agent tool attempted the command.
+	AgentProgram string `json:"agent_program,omitempty"`
+	ToolCallID   string `json:"tool_call_id,omitempty"`
+	// TranscriptPath is the agent transcript file. Its tail is attached to
+	// an auto-created request, or suggested as --context-file.
+	TranscriptPath string `json:"transcript_path,omitempty"`
+
+	// SLBSessionID is the slb session auto-created requests are submitted
+	// under (the hook passes $SLB_SESSION_ID). When empty, the project's
+	// active session for AgentProgram is used if there is exactly one.
+	SLBSessionID string `json:"slb_session_id,omitempty"`
+	// Description is the agent's own description of the tool call. It
+	// becomes the justification of an auto-created request.
+	Description string `json:"description,omitempty"`
 }
 
 // HookQueryResult is the result of a hook query.
@@ -25,10 +42,17 @@ type HookQueryResult struct {
 	MatchedPattern string `json:"matched_pattern"`      // Pattern that matched
 	MinApprovals   int    `json:"min_approvals"`        // Required approvals
 	RequestID      string `json:"request_id,omitempty"` // If pending approval exists
+	// Explanation notes tier adjustments, e.g. a path that resolves to a
+	// system location through a symlink.
+	Explanation []string `json:"explanation,omitempty"`
+	// AutoRequest is set for a blocked command when
+	// integrations.hook_auto_request is on: the create_request params the
+	// hook should submit so the agent gets a request ID to wait on.
+	AutoRequest *CreateRequestParams `json:"auto_request,omitempty"`
 }
 
 // handleHookQuery processes a hook query request.
-func (s *IPCServer) handleHookQuery(req RPCRequest) *RPCResponse {
+func (s *IPCServer) handleHookQuery(req RPCRequest, peer *PeerCred) *RPCResponse {
 	var params HookQueryParams
 	if err := json.Unmarshal(req.Params, &params); err != nil {
 		return &RPCResponse{
@@ -45,6 +69,9 @@ func (s *IPCServer) handleHookQuery(req RPCRequest) *RPCResponse {
 	}
 
 	result := s.classifyCommand(params)
+	if result.AutoRequest != nil {
+		s.attachTranscript(result.AutoRequest, params.TranscriptPath, peer)
+	}
 
 	return &RPCResponse{
 		Result: result,
@@ -54,13 +81,21 @@ func (s *IPCServer) handleHookQuery(req RPCRequest) *RPCResponse {
 
 // classifyCommand classifies a command and checks for existing approvals.
 func (s *IPCServer) classifyCommand(params HookQueryParams) *HookQueryResult {
-	// Classify the command
+	// Classify the command, then apply the agent's tier overrides
 	classification := core.Classify(params.Command, params.CWD)
+	if s.patternHits != nil {
+		s.patternHits.Record(classification)
+	}
+	if s.creator != nil {
+		program, model := s.hookRequestor(params)
+		classification = s.creator.ApplyTierOverrides(classification, program, model, params.Command, params.CWD)
+	}
 
 	result := &HookQueryResult{
 		Tier:           string(classification.Tier),
 		MatchedPattern: classification.MatchedPattern,
 		MinApprovals:   classification.MinApprovals,
+		Explanation:    classification.Explanation,
 	}
 
 	// Determine action based on classification
@@ -78,6 +113,11 @@ func (s *IPCServer) classifyCommand(params HookQueryParams) *HookQueryResult {
 		result.Action = "block"
 		result.Message = "DANGEROUS: Requires approval"
 
+	case classification.Tier == core.RiskTierCaution && !classification.NeedsApproval:
+		result.Action = "allow"
+		result.Message = "CAUTION: not tracked for this agent"
+		return result
+
 	case classification.Tier == core.RiskTierCaution:
 		result.Action = "ask"
 		result.Message = "CAUTION: Proceed with care"
@@ -90,26 +130,190 @@ func (s *IPCServer) classifyCommand(params HookQueryParams) *HookQueryResult {
 	}
 
 	// Check for existing approval in database
-	if params.SessionID != "" && classification.NeedsApproval {
-		if approved, requestID := s.checkApproval(params.Command, params.SessionID, params.CWD); approved {
-			result.Action = "allow"
-			result.Message = "Pre-approved"
-			result.RequestID = requestID
+	if classification.NeedsApproval {
+		for _, sessionID := range hookSessionIDs(params) {
+			if approved, requestID := s.checkApproval(params.Command, sessionID, params.CWD); approved {
+				result.Action = "allow"
+				result.Message = "Pre-approved"
+				result.RequestID = requestID
+				return result
+			}
 		}
 	}
 
+	if result.Action == "block" {
+		if s.hookAutoRequest {
+			result.AutoRequest = s.autoRequestParams(params)
+		}
+		result.Message += provenanceHint(params)
+	}
+
 	return result
 }
 
-// checkApproval checks if a command has been pre-approved in the database.
+// hookAutoRequestReason is the justification of an auto-created request
+// whose tool call carried no description.
+const hookAutoRequestReason = "Blocked by the slb hook; submitted automatically"
+
+// autoRequestParams builds the request a blocked hook query should submit,
+// or returns nil when no slb session can be determined.
+func (s *IPCServer) autoRequestParams(params HookQueryParams) *CreateRequestParams {
+	if s.creator == nil {
+		return nil
+	}
+	sessionID := params.SLBSessionID
+	if sessionID == "" {
+		sessionID = s.hookSession(params)
+	}
+	if sessionID == "" {
+		return nil
+	}
+
+	reason := strings.TrimSpace(params.Description)
+	if reason == "" {
+		reason = hookAutoRequestReason
+	}
+	auto := &CreateRequestParams{
+		SessionID:      sessionID,
+		Command:        params.Command,
+		Cwd:            params.CWD,
+		Shell:          true,
+		Reason:         reason,
+		Labels:         map[string]string{"source": "hook"},
+		AgentProgram:   params.AgentProgram,
+		ConversationID: params.SessionID,
+		ToolCallID:     params.ToolCallID,
+	}
+	if params.ToolCallID != "" {
+		// A re-run of the same hook returns the request it created.
+		auto.IdempotencyKey = "hook:" + params.ToolCallID
+	}
+	return auto
+}
+
+// attachTranscript attaches the tail of the agent's transcript to an
+// auto-created request, capped and redacted as 'slb request --context-file'
+// does. The file is only read for a peer running as the daemon's own user,
+// so other allowed users cannot have the daemon read its files. A
+// transcript that cannot be read is left out.
+func (s *IPCServer) attachTranscript(auto *CreateRequestParams, path string, peer *PeerCred) {
+	if path == "" || (peer != nil && peer.UID != os.Getuid()) {
+		return
+	}
+	attachment, err := core.LoadTranscriptSnippet(path, nil)
+	if err != nil {
+		s.logger.Debug("hook transcript not attached", "path", path, "error", err)
+		return
+	}
+	auto.Attachments = append(auto.Attachments, *attachment)
+}
+
+// hookRequestor returns the program and model tier overrides are matched
+// against: those of the slb session the hook names, or else the hook's
+// agent program.
+func (s *IPCServer) hookRequestor(params HookQueryParams) (program, model string) {
+	if params.SLBSessionID != "" && s.database != nil {
+		if sess, err := s.database.GetSession(params.SLBSessionID); err == nil {
+			return sess.Program, sess.Model
+		}
+	}
+	return params.AgentProgram, ""
+}
+
+// hookSessionIDs returns the sessions whose approvals cover the hook's
+// command: the session it names and the slb session it was given. A
+// guessed session (see hookSession) is never used, so one agent cannot
+// run on another's approvals.
+func hookSessionIDs(params HookQueryParams) []string {
+	var ids []string
+	for _, id := range []string{params.SessionID, params.SLBSessionID} {
+		if id != "" && (len(ids) == 0 || ids[0] != id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// hookSession picks the active session in the command's project that the
+// hook's agent is running under: the only one for its program, or else the
+// only one at all.
+func (s *IPCServer) hookSession(params HookQueryParams) string {
+	if params.CWD == "" {
+		return ""
+	}
+	root := projectRootForSocket(params.CWD)
+	var sessions []*db.Session
+	if s.readModel != nil && root == s.readModel.ProjectPath() {
+		snap, err := s.readModel.Snapshot()
+		if err != nil {
+			return ""
+		}
+		sessions = snap.Sessions
+	} else if s.database != nil {
+		var err error
+		if sessions, err = s.database.ListActiveSessions(root); err != nil {
+			return ""
+		}
+	}
+	var matches []*db.Session
+	for _, sess := range sessions {
+		if params.AgentProgram != "" && strings.EqualFold(sess.Program, params.AgentProgram) {
+			matches = append(matches, sess)
+		}
+	}
+	switch {
+	case len(matches) == 1:
+		return matches[0].ID
+	case len(matches) == 0 && len(sessions) == 1:
+		return sessions[0].ID
+	}
+	return ""
+}
+
+// provenanceHint suggests the provenance and transcript flags to pass when
+// submitting the blocked command, so reviewers can see which tool call
+// attempted it and why.
+func provenanceHint(params HookQueryParams) string {
+	var flags []string
+	if params.AgentProgram != "" {
+		flags = append(flags, "--agent-program "+params.AgentProgram)
+	}
+	if params.SessionID != "" {
+		flags = append(flags, "--conversation-id "+params.SessionID)
+	}
+	if params.ToolCallID != "" {
+		flags = append(flags, "--tool-call-id "+params.ToolCallID)
+	}
+	if params.TranscriptPath != "" {
+		flags = append(flags, "--context-file "+params.TranscriptPath)
+	}
+	if len(flags) == 0 {
+		return ""
+	}
+	return ". When submitting, add: " + strings.Join(flags, " ")
+}
+
+// SetApprovalReuse sets when executed requests answer retries in projects
+// the read model does not serve.
+func (s *IPCServer) SetApprovalReuse(r ApprovalReuse) {
+	s.reuse = r
+}
+
+// checkApproval checks if a command was approved for the session within the
+// last hour, or executed recently enough for the reuse policy to cover a
+// retry. The read model answers for its own project; other projects are
+// read from their database.
 func (s *IPCServer) checkApproval(command, sessionID, cwd string) (bool, string) {
-	// Determine database path
-	dbPath := filepath.Join(cwd, ".slb", "state.db")
 	if cwd == "" {
 		return false, ""
 	}
+	if s.readModel != nil && filepath.Clean(cwd) == s.readModel.ProjectPath() {
+		requestID, ok := s.readModel.ApprovedRequest(sessionID, command)
+		return ok, requestID
+	}
 
 	// Open database read-only
+	dbPath := filepath.Join(cwd, ".slb", "state.db")
 	opts := db.OpenOptions{
 		CreateIfNotExists: false,
 		InitSchema:        false,
@@ -121,25 +325,14 @@ func (s *IPCServer) checkApproval(command, sessionID, cwd string) (bool, string)
 	}
 	defer dbConn.Close()
 
-	// Query for approved request matching this command by display_redacted field
-	// We search for commands that match the display text (what the user sees)
-	var requestID string
-	var status string
-	err = dbConn.QueryRow(`
-		SELECT id, status FROM requests
-		WHERE display_redacted = ?
-		  AND session_id = ?
-		  AND status IN ('approved', 'executed')
-		  AND created_at > datetime('now', '-1 hour')
-		ORDER BY created_at DESC
-		LIMIT 1
-	`, command, sessionID).Scan(&requestID, &status)
-
+	now := time.Now()
+	approvals, executions, err := recentApprovals(dbConn, cwd, now, s.reuse.Window)
 	if err != nil {
 		return false, ""
 	}
-
-	return true, requestID
+	snap := &ReadModelSnapshot{approvals: approvals, executions: executions}
+	requestID, ok := snap.approvedRequest(sessionID, command, now, s.reuse)
+	return ok, requestID
 }
 
 // HookHealthResult is the result of a hook health check.
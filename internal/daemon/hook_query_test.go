@@ -6,8 +6,12 @@ import (
 	"encoding/json"
 	"net"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
 )
 
 func TestIPCServer_HookQuery_RequiresCommand(t *testing.T) {
@@ -347,3 +351,91 @@ func TestItoa(t *testing.T) {
 		}
 	}
 }
+
+func TestProvenanceHint(t *testing.T) {
+	if got := provenanceHint(HookQueryParams{Command: "rm -rf /"}); got != "" {
+		t.Errorf("expected no hint without provenance, got %q", got)
+	}
+
+	got := provenanceHint(HookQueryParams{
+		Command:        "rm -rf ./build",
+		SessionID:      "conv-1",
+		AgentProgram:   "claude-code",
+		ToolCallID:     "toolu_1",
+		TranscriptPath: "/tmp/session.jsonl",
+	})
+	for _, want := range []string{"--agent-program claude-code", "--conversation-id conv-1", "--tool-call-id toolu_1", "--context-file /tmp/session.jsonl"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("hint %q missing %q", got, want)
+		}
+	}
+}
+
+func TestIPCServer_HookQuery_TierOverrides(t *testing.T) {
+	h := testutil.NewHarness(t)
+	human := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir), testutil.WithProgram("shell"))
+
+	srv, err := NewIPCServer(filepath.Join(shortSocketDir(t), "to.sock"), newTestLogger())
+	if err != nil {
+		t.Fatalf("NewIPCServer failed: %v", err)
+	}
+	cfg := config.DefaultConfig()
+	cfg.Agents.TierOverrides = []string{"codex-cli=dangerous", "shell=skip_caution"}
+	srv.SetDatabase(h.DB)
+	srv.SetRequestCreator(RequestCreatorFromConfig(h.DB, cfg))
+
+	params := HookQueryParams{Command: "rm notes.txt", CWD: h.ProjectDir}
+	if result := srv.classifyCommand(params); result.Action != "ask" || result.Tier != "caution" {
+		t.Fatalf("without a program: %+v, want caution/ask", result)
+	}
+
+	params.AgentProgram = "codex-cli"
+	result := srv.classifyCommand(params)
+	if result.Action != "block" || result.Tier != "dangerous" || result.MinApprovals != 1 {
+		t.Fatalf("codex-cli: %+v, want dangerous/block", result)
+	}
+	if len(result.Explanation) == 0 || !strings.Contains(result.Explanation[len(result.Explanation)-1], "codex-cli=dangerous") {
+		t.Errorf("explanation = %q", result.Explanation)
+	}
+
+	// The slb session's program takes precedence over the hook's.
+	params.SLBSessionID = human.ID
+	if result := srv.classifyCommand(params); result.Action != "allow" || result.Tier != "caution" {
+		t.Errorf("shell session: %+v, want caution/allow", result)
+	}
+}
+
+func TestIPCServer_HookQuery_RecordsPatternHits(t *testing.T) {
+	h := testutil.NewHarness(t)
+
+	srv, err := NewIPCServer(filepath.Join(shortSocketDir(t), "hits.sock"), newTestLogger())
+	if err != nil {
+		t.Fatalf("NewIPCServer failed: %v", err)
+	}
+	cfg := config.DefaultConfig()
+	cfg.Agents.TierOverrides = []string{"codex-cli=dangerous"}
+	srv.SetDatabase(h.DB)
+	srv.SetRequestCreator(RequestCreatorFromConfig(h.DB, cfg))
+	counter := NewPatternHitCounter(h.DB, time.Hour)
+	srv.SetPatternHitCounter(counter)
+
+	srv.classifyCommand(HookQueryParams{Command: "rm notes.txt", CWD: h.ProjectDir})
+	// Overrides change the tier the hook reports, not the pattern's tier.
+	srv.classifyCommand(HookQueryParams{Command: "rm notes.txt", CWD: h.ProjectDir, AgentProgram: "codex-cli"})
+	srv.classifyCommand(HookQueryParams{Command: "echo hello", CWD: h.ProjectDir})
+
+	// Nothing is written until the counter flushes.
+	if hits, _ := h.DB.ListPatternHits(); len(hits) != 0 {
+		t.Fatalf("expected hits to be buffered, got %+v", hits)
+	}
+	if err := counter.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	hits, err := h.DB.ListPatternHits()
+	if err != nil {
+		t.Fatalf("ListPatternHits: %v", err)
+	}
+	if len(hits) != 1 || hits[0].Tier != "caution" || hits[0].Hits != 2 {
+		t.Fatalf("unexpected hits: %+v", hits)
+	}
+}
@@ -0,0 +1,278 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/clock"
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/charmbracelet/log"
+)
+
+// needsHumanFallback reports whether a pending request has gone unreviewed by
+// agents for longer than window and should be paged to a human.
+func needsHumanFallback(req *db.Request, reviewCount int, window time.Duration, now time.Time) bool {
+	if req == nil || window <= 0 || req.Status != db.StatusPending {
+		return false
+	}
+	if req.RiskTier != db.RiskTierDangerous && req.RiskTier != db.RiskTierCritical {
+		return false
+	}
+	if reviewCount > 0 {
+		return false
+	}
+	return now.Sub(req.CreatedAt) >= window
+}
+
+// ReviewSLAFromConfig converts the configured per-tier review SLAs.
+func ReviewSLAFromConfig(n config.NotificationsConfig) core.ReviewSLA {
+	return core.ReviewSLA{
+		Critical:  time.Duration(n.ReviewSLACriticalMinutes) * time.Minute,
+		Dangerous: time.Duration(n.ReviewSLADangerousMinutes) * time.Minute,
+		Caution:   time.Duration(n.ReviewSLACautionMinutes) * time.Minute,
+	}
+}
+
+// breachedReviewSLA reports whether a pending request is still unreviewed
+// past its tier's review SLA, and returns the SLA.
+func breachedReviewSLA(req *db.Request, reviewCount int, sla core.ReviewSLA, now time.Time) (bool, time.Duration) {
+	if req == nil || req.Status != db.StatusPending || reviewCount > 0 {
+		return false, 0
+	}
+	state := sla.Evaluate(req.RiskTier, req.CreatedAt, nil, nil, now)
+	if state == nil || !state.Breached {
+		return false, 0
+	}
+	return true, sla.For(req.RiskTier)
+}
+
+// InactivityMonitor pages a human when no agent reviewer acts on a
+// DANGEROUS or CRITICAL request within the configured window, or, with
+// review_sla_escalate, on any request left unreviewed past its review SLA.
+type InactivityMonitor struct {
+	projectPath string
+	cfg         config.NotificationsConfig
+	logger      *log.Logger
+	notifier    DesktopNotifier
+	webhook     WebhookNotifier
+	clock       clock.Clock
+}
+
+// NewInactivityMonitor creates a reviewer inactivity monitor for a project.
+func NewInactivityMonitor(projectPath string, cfg config.NotificationsConfig, logger *log.Logger, notifier DesktopNotifier) *InactivityMonitor {
+	if logger == nil {
+		logger = log.Default()
+	}
+	if notifier == nil {
+		notifier = DesktopNotifierFunc(SendDesktopNotification)
+	}
+
+	var webhook WebhookNotifier
+	if cfg.WebhookURL != "" {
+		webhook = NewDefaultWebhookNotifier()
+	}
+
+	return &InactivityMonitor{
+		projectPath: projectPath,
+		cfg:         cfg,
+		logger:      logger,
+		notifier:    notifier,
+		webhook:     webhook,
+		clock:       clock.Real,
+	}
+}
+
+// SetClock sets the clock used for the human fallback window.
+func (m *InactivityMonitor) SetClock(c clock.Clock) {
+	m.clock = clock.OrReal(c)
+}
+
+// WithWebhook sets a custom webhook notifier (for testing).
+func (m *InactivityMonitor) WithWebhook(w WebhookNotifier) *InactivityMonitor {
+	m.webhook = w
+	return m
+}
+
+// Run checks for unreviewed requests every interval until ctx is cancelled.
+func (m *InactivityMonitor) Run(ctx context.Context, interval time.Duration) {
+	if m == nil {
+		return
+	}
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := m.Check(ctx); err != nil {
+				m.logger.Warn("reviewer inactivity check failed", "error", err)
+			}
+		}
+	}
+}
+
+// Check pages a human for every overdue unreviewed request once and returns
+// how many requests were newly flagged as awaiting a human.
+func (m *InactivityMonitor) Check(ctx context.Context) (int, error) {
+	if m == nil || strings.TrimSpace(m.projectPath) == "" {
+		return 0, nil
+	}
+	if m.cfg.ReviewerInactivityMinutes <= 0 && !m.cfg.ReviewSLAEscalate {
+		return 0, nil
+	}
+
+	dbPath := filepath.Join(m.projectPath, ".slb", "state.db")
+	dbConn, err := db.OpenWithOptions(dbPath, db.OpenOptions{
+		CreateIfNotExists: false,
+		InitSchema:        false,
+	})
+	if err != nil {
+		// Treat missing DB as no-op (daemon should not crash).
+		return 0, nil
+	}
+	defer dbConn.Close()
+
+	pending, err := dbConn.ListPendingRequests(m.projectPath)
+	if err != nil {
+		return 0, fmt.Errorf("listing pending requests: %w", err)
+	}
+
+	ids := make([]string, 0, len(pending))
+	for _, req := range pending {
+		ids = append(ids, req.ID)
+	}
+	flagged, err := dbConn.AwaitingHumanRequestIDs(ids)
+	if err != nil {
+		return 0, err
+	}
+	seenBy, err := dbConn.UndecidedViewers(ids)
+	if err != nil {
+		// Older databases lack viewed receipts; page as if nobody looked.
+		m.logger.Warn("listing viewed receipts failed", "error", err)
+		seenBy = map[string][]string{}
+	}
+
+	now := m.clock.Now().UTC()
+	window := time.Duration(m.cfg.ReviewerInactivityMinutes) * time.Minute
+	var sla core.ReviewSLA
+	if m.cfg.ReviewSLAEscalate {
+		sla = ReviewSLAFromConfig(m.cfg)
+	}
+	escalated := 0
+	for _, req := range pending {
+		if ctx.Err() != nil {
+			return escalated, ctx.Err()
+		}
+		if flagged[req.ID] {
+			continue
+		}
+		approvals, rejections, err := dbConn.CountReviewsByDecision(req.ID)
+		if err != nil {
+			m.logger.Warn("counting reviews failed", "request_id", req.ID, "error", err)
+			continue
+		}
+		seen := seenBy[req.ID]
+		var reason string
+		pageWindow := window
+		if needsHumanFallback(req, approvals+rejections, window, now) {
+			reason = fmt.Sprintf("no agent reviewer opened it within %s", window)
+			if len(seen) > 0 {
+				reason = fmt.Sprintf("seen by %s with no decision within %s", strings.Join(seen, ", "), window)
+			}
+		} else if breached, target := breachedReviewSLA(req, approvals+rejections, sla, now); breached {
+			reason = fmt.Sprintf("review SLA of %s breached with no review", target)
+			pageWindow = target
+		} else {
+			continue
+		}
+		channels := m.page(ctx, req, seen, pageWindow, now)
+		created, err := dbConn.MarkAwaitingHuman(&db.HumanEscalation{
+			RequestID: req.ID,
+			Reason:    reason,
+			Channels:  channels,
+			PagedAt:   now,
+		})
+		if err != nil {
+			m.logger.Warn("flagging request awaiting human failed", "request_id", req.ID, "error", err)
+			continue
+		}
+		if created {
+			escalated++
+			m.logger.Warn("request awaiting human",
+				"request_id", req.ID,
+				"tier", req.RiskTier,
+				"urgency", escalationUrgency(seen),
+				"channels", strings.Join(channels, ","))
+		}
+	}
+	return escalated, nil
+}
+
+// escalationUrgency rates an awaiting-human page. A request no reviewer has
+// opened is urgent: nobody is on it. One that reviewers have seen but not
+// decided is only stalled.
+func escalationUrgency(seenBy []string) string {
+	if len(seenBy) == 0 {
+		return UrgencyUrgent
+	}
+	return UrgencyNormal
+}
+
+// page notifies every configured human channel and returns the ones that
+// succeeded. seenBy lists reviewers who opened the request without deciding.
+func (m *InactivityMonitor) page(ctx context.Context, req *db.Request, seenBy []string, window time.Duration, now time.Time) []string {
+	cmd := truncateString(displayCommand(req), 140)
+	urgency := escalationUrgency(seenBy)
+	var channels []string
+
+	if m.cfg.DesktopEnabled {
+		title := fmt.Sprintf("SLB: %s request needs a human", strings.ToUpper(string(req.RiskTier)))
+		message := fmt.Sprintf("Seen by %s, no decision within %s.\n%s\nID: %s", strings.Join(seenBy, ", "), window, cmd, shortID(req.ID))
+		if urgency == UrgencyUrgent {
+			title = fmt.Sprintf("SLB URGENT: %s request unseen by reviewers", strings.ToUpper(string(req.RiskTier)))
+			message = fmt.Sprintf("No reviewer opened it within %s.\n%s\nID: %s", window, cmd, shortID(req.ID))
+		}
+		if err := m.notifier.Notify(title, message); err != nil {
+			m.logger.Warn("desktop notification failed", "error", err)
+		} else {
+			channels = append(channels, "desktop")
+		}
+	}
+
+	if m.webhook != nil && m.cfg.WebhookURL != "" {
+		payload := WebhookPayload{
+			Event:     WebhookEventAwaitingHuman,
+			RequestID: req.ID,
+			Command:   cmd,
+			Tier:      string(req.RiskTier),
+			Requestor: req.RequestorAgent,
+			Timestamp: now.Format(time.RFC3339),
+			Project:   m.projectPath,
+			Urgency:   urgency,
+			SeenBy:    seenBy,
+		}
+		webhookCtx, cancel := context.WithTimeout(ctx, WebhookTimeout)
+		if err := m.webhook.Send(webhookCtx, m.cfg.WebhookURL, payload); err != nil {
+			m.logger.Warn("webhook notification failed",
+				"error", err,
+				"request_id", req.ID,
+				"event", WebhookEventAwaitingHuman)
+		} else {
+			channels = append(channels, "webhook")
+		}
+		cancel()
+	}
+
+	return channels
+}
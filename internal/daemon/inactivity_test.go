@@ -0,0 +1,300 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestNeedsHumanFallback(t *testing.T) {
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	window := 15 * time.Minute
+	old := now.Add(-time.Hour)
+
+	cases := []struct {
+		name    string
+		req     *db.Request
+		reviews int
+		window  time.Duration
+		want    bool
+	}{
+		{"nil request", nil, 0, window, false},
+		{"dangerous unreviewed", &db.Request{Status: db.StatusPending, RiskTier: db.RiskTierDangerous, CreatedAt: old}, 0, window, true},
+		{"critical unreviewed", &db.Request{Status: db.StatusPending, RiskTier: db.RiskTierCritical, CreatedAt: old}, 0, window, true},
+		{"caution ignored", &db.Request{Status: db.StatusPending, RiskTier: db.RiskTierCaution, CreatedAt: old}, 0, window, false},
+		{"has review", &db.Request{Status: db.StatusPending, RiskTier: db.RiskTierDangerous, CreatedAt: old}, 1, window, false},
+		{"window not elapsed", &db.Request{Status: db.StatusPending, RiskTier: db.RiskTierDangerous, CreatedAt: now.Add(-time.Minute)}, 0, window, false},
+		{"disabled", &db.Request{Status: db.StatusPending, RiskTier: db.RiskTierDangerous, CreatedAt: old}, 0, 0, false},
+		{"not pending", &db.Request{Status: db.StatusApproved, RiskTier: db.RiskTierDangerous, CreatedAt: old}, 0, window, false},
+	}
+	for _, tc := range cases {
+		if got := needsHumanFallback(tc.req, tc.reviews, tc.window, now); got != tc.want {
+			t.Errorf("%s: needsHumanFallback = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestInactivityMonitorCheck(t *testing.T) {
+	project := t.TempDir()
+
+	dbConn, err := db.OpenProjectDB(project)
+	if err != nil {
+		t.Fatalf("open project db: %v", err)
+	}
+	t.Cleanup(func() { _ = dbConn.Close() })
+
+	if err := dbConn.CreateSession(&db.Session{
+		ID:          "s1",
+		AgentName:   "AgentA",
+		Program:     "test",
+		Model:       "model",
+		ProjectPath: project,
+	}); err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+
+	req := &db.Request{
+		ProjectPath:        project,
+		Command:            db.CommandSpec{Raw: "rm -rf ./build", Cwd: project},
+		RiskTier:           db.RiskTierDangerous,
+		RequestorSessionID: "s1",
+		RequestorAgent:     "AgentA",
+		RequestorModel:     "model",
+		Justification:      db.Justification{Reason: "cleanup"},
+		MinApprovals:       1,
+	}
+	if err := dbConn.CreateRequest(req); err != nil {
+		t.Fatalf("create request: %v", err)
+	}
+
+	var events []WebhookEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload WebhookPayload
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		events = append(events, payload.Event)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	desktopCalls := 0
+	monitor := NewInactivityMonitor(project, config.NotificationsConfig{
+		DesktopEnabled:            true,
+		WebhookURL:                server.URL,
+		ReviewerInactivityMinutes: 15,
+	}, nil, DesktopNotifierFunc(func(title, message string) error {
+		desktopCalls++
+		return nil
+	}))
+
+	clk := testutil.NewFakeClock(req.CreatedAt.Add(5 * time.Minute))
+	monitor.SetClock(clk)
+	if n, err := monitor.Check(context.Background()); err != nil || n != 0 {
+		t.Fatalf("expected no escalation inside window, got %d (err %v)", n, err)
+	}
+
+	clk.Advance(15 * time.Minute)
+	n, err := monitor.Check(context.Background())
+	if err != nil || n != 1 {
+		t.Fatalf("expected 1 escalation, got %d (err %v)", n, err)
+	}
+	if desktopCalls != 1 || len(events) != 1 || events[0] != WebhookEventAwaitingHuman {
+		t.Fatalf("expected desktop and webhook page, got desktop=%d webhook=%v", desktopCalls, events)
+	}
+
+	esc, err := dbConn.GetHumanEscalation(req.ID)
+	if err != nil {
+		t.Fatalf("GetHumanEscalation: %v", err)
+	}
+	if len(esc.Channels) != 2 {
+		t.Fatalf("expected desktop and webhook channels, got %v", esc.Channels)
+	}
+
+	// Already paged requests are not paged again.
+	if n, err := monitor.Check(context.Background()); err != nil || n != 0 {
+		t.Fatalf("expected idempotent check, got %d (err %v)", n, err)
+	}
+	if desktopCalls != 1 || len(events) != 1 {
+		t.Fatalf("expected no repeat page, got desktop=%d webhook=%v", desktopCalls, events)
+	}
+}
+
+func TestBreachedReviewSLA(t *testing.T) {
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	sla := ReviewSLAFromConfig(config.NotificationsConfig{ReviewSLADangerousMinutes: 10})
+	pending := func(tier db.RiskTier, age time.Duration) *db.Request {
+		return &db.Request{Status: db.StatusPending, RiskTier: tier, CreatedAt: now.Add(-age)}
+	}
+
+	cases := []struct {
+		name    string
+		req     *db.Request
+		reviews int
+		want    bool
+	}{
+		{"nil request", nil, 0, false},
+		{"breached", pending(db.RiskTierDangerous, 11*time.Minute), 0, true},
+		{"within SLA", pending(db.RiskTierDangerous, 9*time.Minute), 0, false},
+		{"reviewed", pending(db.RiskTierDangerous, time.Hour), 1, false},
+		{"tier without SLA", pending(db.RiskTierCritical, time.Hour), 0, false},
+		{"not pending", &db.Request{Status: db.StatusApproved, RiskTier: db.RiskTierDangerous, CreatedAt: now.Add(-time.Hour)}, 0, false},
+	}
+	for _, tc := range cases {
+		got, target := breachedReviewSLA(tc.req, tc.reviews, sla, now)
+		if got != tc.want || (got && target != 10*time.Minute) {
+			t.Errorf("%s: breachedReviewSLA = %v, %s; want %v", tc.name, got, target, tc.want)
+		}
+	}
+}
+
+func TestInactivityMonitorCheck_ReviewSLA(t *testing.T) {
+	project := t.TempDir()
+
+	dbConn, err := db.OpenProjectDB(project)
+	if err != nil {
+		t.Fatalf("open project db: %v", err)
+	}
+	t.Cleanup(func() { _ = dbConn.Close() })
+
+	if err := dbConn.CreateSession(&db.Session{
+		ID:          "s1",
+		AgentName:   "AgentA",
+		Program:     "test",
+		Model:       "model",
+		ProjectPath: project,
+	}); err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	// Caution requests are never paged for reviewer inactivity, only for a
+	// breached SLA.
+	req := &db.Request{
+		ProjectPath:        project,
+		Command:            db.CommandSpec{Raw: "git stash drop", Cwd: project},
+		RiskTier:           db.RiskTierCaution,
+		RequestorSessionID: "s1",
+		RequestorAgent:     "AgentA",
+		RequestorModel:     "model",
+		Justification:      db.Justification{Reason: "cleanup"},
+	}
+	if err := dbConn.CreateRequest(req); err != nil {
+		t.Fatalf("create request: %v", err)
+	}
+
+	var messages []string
+	monitor := NewInactivityMonitor(project, config.NotificationsConfig{
+		DesktopEnabled:            true,
+		ReviewerInactivityMinutes: 15,
+		ReviewSLACautionMinutes:   5,
+		ReviewSLAEscalate:         true,
+	}, nil, DesktopNotifierFunc(func(title, message string) error {
+		messages = append(messages, message)
+		return nil
+	}))
+
+	clk := testutil.NewFakeClock(req.CreatedAt.Add(4 * time.Minute))
+	monitor.SetClock(clk)
+	if n, err := monitor.Check(context.Background()); err != nil || n != 0 {
+		t.Fatalf("expected no escalation inside the SLA, got %d (err %v)", n, err)
+	}
+
+	clk.Advance(2 * time.Minute)
+	if n, err := monitor.Check(context.Background()); err != nil || n != 1 {
+		t.Fatalf("expected 1 escalation, got %d (err %v)", n, err)
+	}
+	esc, err := dbConn.GetHumanEscalation(req.ID)
+	if err != nil {
+		t.Fatalf("GetHumanEscalation: %v", err)
+	}
+	if !strings.Contains(esc.Reason, "review SLA of 5m0s breached") {
+		t.Errorf("unexpected reason: %q", esc.Reason)
+	}
+	if len(messages) != 1 || !strings.Contains(messages[0], "within 5m0s") {
+		t.Errorf("unexpected page: %v", messages)
+	}
+
+	// Without review_sla_escalate the breach is only reported.
+	monitor = NewInactivityMonitor(project, config.NotificationsConfig{ReviewSLACautionMinutes: 5}, nil, nil)
+	if n, err := monitor.Check(context.Background()); err != nil || n != 0 {
+		t.Fatalf("expected no escalation without review_sla_escalate, got %d (err %v)", n, err)
+	}
+}
+
+func TestInactivityMonitorDisabled(t *testing.T) {
+	monitor := NewInactivityMonitor(t.TempDir(), config.NotificationsConfig{}, nil, nil)
+	if n, err := monitor.Check(context.Background()); err != nil || n != 0 {
+		t.Fatalf("expected disabled monitor to be a no-op, got %d (err %v)", n, err)
+	}
+}
+
+func TestInactivityMonitorCheck_SeenVersusUnseen(t *testing.T) {
+	project := t.TempDir()
+
+	dbConn, err := db.OpenProjectDB(project)
+	if err != nil {
+		t.Fatalf("open project db: %v", err)
+	}
+	t.Cleanup(func() { _ = dbConn.Close() })
+
+	sess := testutil.MakeSession(t, dbConn, testutil.WithProject(project), testutil.WithAgent("AgentA"))
+	unseen := testutil.MakeRequest(t, dbConn, sess, testutil.WithRisk(db.RiskTierDangerous))
+	seen := testutil.MakeRequest(t, dbConn, sess, testutil.WithRisk(db.RiskTierDangerous))
+	if _, err := dbConn.RecordRequestViewed(seen.ID, "AgentB"); err != nil {
+		t.Fatalf("RecordRequestViewed: %v", err)
+	}
+
+	payloads := map[string]WebhookPayload{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload WebhookPayload
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		payloads[payload.RequestID] = payload
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var titles []string
+	monitor := NewInactivityMonitor(project, config.NotificationsConfig{
+		DesktopEnabled:            true,
+		WebhookURL:                server.URL,
+		ReviewerInactivityMinutes: 15,
+	}, nil, DesktopNotifierFunc(func(title, message string) error {
+		titles = append(titles, title)
+		return nil
+	}))
+	monitor.SetClock(testutil.NewFakeClock(seen.CreatedAt.Add(time.Hour)))
+
+	if n, err := monitor.Check(context.Background()); err != nil || n != 2 {
+		t.Fatalf("expected 2 escalations, got %d (err %v)", n, err)
+	}
+
+	if p := payloads[unseen.ID]; p.Urgency != UrgencyUrgent || len(p.SeenBy) != 0 {
+		t.Errorf("unseen request payload = %+v", p)
+	}
+	if p := payloads[seen.ID]; p.Urgency != UrgencyNormal || len(p.SeenBy) != 1 || p.SeenBy[0] != "AgentB" {
+		t.Errorf("seen request payload = %+v", p)
+	}
+	urgent := 0
+	for _, title := range titles {
+		if strings.Contains(title, "URGENT") {
+			urgent++
+		}
+	}
+	if len(titles) != 2 || urgent != 1 {
+		t.Errorf("desktop titles = %q", titles)
+	}
+
+	esc, err := dbConn.GetHumanEscalation(seen.ID)
+	if err != nil || !strings.Contains(esc.Reason, "seen by AgentB with no decision") {
+		t.Errorf("seen escalation = %+v, %v", esc, err)
+	}
+	esc, err = dbConn.GetHumanEscalation(unseen.ID)
+	if err != nil || !strings.Contains(esc.Reason, "no agent reviewer opened it") {
+		t.Errorf("unseen escalation = %+v, %v", esc, err)
+	}
+}
@@ -0,0 +1,71 @@
+// Package daemon provides input validation for IPC requests.
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxParamsBytes bounds the params of one IPC request. The largest
+// legitimate params, a create_request at the core input limits, fit with
+// room to spare.
+const MaxParamsBytes = 256 << 10
+
+// validateParams rejects params that are oversized, not valid UTF-8, or
+// carry a NUL byte in any string, before any handler sees them. Go's JSON
+// decoder would otherwise silently replace invalid UTF-8 and pass NULs
+// through to the database and to terminals. Field limits are enforced by
+// core when the input is used.
+func (s *IPCServer) validateParams(req RPCRequest) *RPCResponse {
+	invalid := func(msg string) *RPCResponse {
+		s.logger.Warn("request refused", "method", req.Method, "reason", msg)
+		return &RPCResponse{
+			Error: &Error{Code: ErrCodeInvalidParams, Message: "invalid params: " + msg},
+			ID:    req.ID,
+		}
+	}
+
+	if len(req.Params) == 0 {
+		return nil
+	}
+	if len(req.Params) > MaxParamsBytes {
+		return invalid(fmt.Sprintf("too large (%d bytes, max %d)", len(req.Params), MaxParamsBytes))
+	}
+	if !utf8.Valid(req.Params) {
+		return invalid("not valid UTF-8")
+	}
+
+	var v any
+	if err := json.Unmarshal(req.Params, &v); err != nil {
+		// Handlers report malformed params themselves.
+		return nil
+	}
+	if containsNUL(v) {
+		return invalid("contains a NUL byte")
+	}
+	return nil
+}
+
+// containsNUL reports whether any string in a decoded JSON value, keys
+// included, contains a NUL byte.
+func containsNUL(v any) bool {
+	switch v := v.(type) {
+	case string:
+		return strings.IndexByte(v, 0) >= 0
+	case []any:
+		for _, e := range v {
+			if containsNUL(e) {
+				return true
+			}
+		}
+	case map[string]any:
+		for k, e := range v {
+			if strings.IndexByte(k, 0) >= 0 || containsNUL(e) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package daemon
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIPCServer_ValidatesParams(t *testing.T) {
+	socketPath := startAuthzServer(t, nil)
+
+	// send writes one request line and returns the error response, if any.
+	send := func(t *testing.T, line string) *Error {
+		t.Helper()
+		conn, err := net.DialTimeout("unix", socketPath, time.Second)
+		if err != nil {
+			t.Fatalf("dial: %v", err)
+		}
+		defer conn.Close()
+		_ = conn.SetDeadline(time.Now().Add(2 * time.Second))
+		// The server may hang up before reading an oversize line to the end.
+		go func() { _, _ = conn.Write([]byte(line + "\n")) }()
+		r := bufio.NewReaderSize(conn, 64*1024)
+		data, err := r.ReadBytes('\n')
+		if err != nil {
+			t.Fatalf("read response: %v", err)
+		}
+		var resp RPCResponse
+		if err := json.Unmarshal(data, &resp); err != nil {
+			t.Fatalf("unmarshal response: %v", err)
+		}
+		return resp.Error
+	}
+
+	tests := []struct {
+		name    string
+		line    string
+		wantMsg string
+	}{
+		{"nul in a string", `{"method":"notify","params":{"type":"x","payload":{"note":"a\u0000b"}},"id":1}`, "NUL byte"},
+		{"nul in a key", `{"method":"notify","params":{"type":"x","payload":{"a\u0000":1}},"id":1}`, "NUL byte"},
+		{"nul in a hook query", `{"method":"hook_query","params":{"command":"rm -rf /\u0000"},"id":1}`, "NUL byte"},
+		{"invalid utf-8", "{\"method\":\"notify\",\"params\":{\"type\":\"\xff\"},\"id\":1}", "not valid UTF-8"},
+		{"oversize params", `{"method":"notify","params":{"type":"x","payload":"` + strings.Repeat("a", MaxParamsBytes) + `"},"id":1}`, "too large"},
+		{"oversize line", `{"method":"notify","params":{"type":"x","payload":"` + strings.Repeat("a", 1024*1024) + `"},"id":1}`, "request too large"},
+		{"escaped backslash is fine", `{"method":"notify","params":{"type":"x","payload":"C:\\u0000"},"id":1}`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rpcErr := send(t, tt.line)
+			if tt.wantMsg == "" {
+				if rpcErr != nil {
+					t.Fatalf("unexpected error: %s", rpcErr.Message)
+				}
+				return
+			}
+			if rpcErr == nil || !strings.Contains(rpcErr.Message, tt.wantMsg) {
+				t.Fatalf("error = %+v, want one containing %q", rpcErr, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestContainsNUL(t *testing.T) {
+	var v any
+	if err := json.Unmarshal([]byte(`{"a":[1,{"b":"ok"}],"c":null}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if containsNUL(v) {
+		t.Fatal("expected clean value")
+	}
+	if err := json.Unmarshal([]byte(`{"a":[1,{"b":"\u0000"}]}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if !containsNUL(v) {
+		t.Fatal("expected nested NUL to be found")
+	}
+}
@@ -13,6 +13,9 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/core/statemachine"
+	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/charmbracelet/log"
 )
 
@@ -51,6 +54,16 @@ const (
 type lockedConn struct {
 	net.Conn
 	mu sync.Mutex
+
+	// peer is the Unix socket client's credentials, when known.
+	peer *PeerCred
+
+	// caps are the capabilities granted to the connection. Only the
+	// connection's read loop touches them.
+	caps capSet
+
+	// client is the connection's entry in the admin client list.
+	client *clientConn
 }
 
 func (c *lockedConn) Write(p []byte) (int, error) {
@@ -67,16 +80,20 @@ func newIPCServer(listener net.Listener, addr string, logger *log.Logger, cleanu
 	startDone := make(chan struct{})
 	close(startDone)
 	return &IPCServer{
-		socketPath:  addr,
-		listener:    listener,
-		logger:      logger,
-		startTime:   time.Now(),
-		subscribers: make(map[int64]*subscriber),
-		startDone:   startDone,
-		ctx:         ctx,
-		cancel:      cancel,
-		cleanup:     cleanup,
-		connGuard:   connGuard,
+		socketPath:      addr,
+		listener:        listener,
+		logger:          logger,
+		startTime:       time.Now(),
+		lastSeq:         time.Now().UnixMilli(),
+		reuse:           DefaultApprovalReuse(),
+		subscribers:     make(map[int64]*subscriber),
+		broadcastPolicy: DefaultBroadcastPolicy(),
+		watchPoke:       make(chan struct{}, 1),
+		startDone:       startDone,
+		ctx:             ctx,
+		cancel:          cancel,
+		cleanup:         cleanup,
+		connGuard:       connGuard,
 	}
 }
 
@@ -96,7 +113,28 @@ type IPCServer struct {
 	// Subscriber management.
 	subscribers   map[int64]*subscriber
 	subscribersMu sync.RWMutex
-	nextSubID     atomic.Int64
+
+	// How subscriber queues are sized and overflow, and what they have
+	// queued and dropped.
+	broadcastPolicy   BroadcastPolicy
+	broadcastCounters broadcastCounters
+
+	// Recent broadcasts, numbered, for event streams that resume. lastSeq
+	// starts at the server's start time in milliseconds so sequence numbers
+	// keep increasing across daemon restarts.
+	history   []Event
+	lastSeq   int64
+	historyMu sync.Mutex
+
+	// Status of the requests watched through watch_requests, as last
+	// pushed; watchPoke asks for an immediate check.
+	watchKnown map[string]db.RequestStatus
+	watchMu    sync.Mutex
+	watchPoke  chan struct{}
+
+	// Connected clients, listed by the admin REPL.
+	clients   map[int64]*clientConn
+	clientsMu sync.Mutex
 
 	// Shutdown coordination.
 	ctx       context.Context
@@ -107,14 +145,52 @@ type IPCServer struct {
 
 	// Optional verifier for execution gate checks.
 	verifier *Verifier
+
+	// Optional creator serving create_request.
+	creator *core.RequestCreator
+
+	// Whether hook_query hands blocked commands back as create_request
+	// params (integrations.hook_auto_request).
+	hookAutoRequest bool
+
+	// Optional read model serving cached project state.
+	readModel *ReadModel
+
+	// When executed requests answer retries of their command in projects
+	// the read model does not serve.
+	reuse ApprovalReuse
+
+	// Optional batched writer persisting events and heartbeats.
+	eventWriter *db.BufferedEventWriter
+	// Optional counter of the patterns hook queries match.
+	patternHits *PatternHitCounter
+
+	// Optional state database exercised by db_probe.
+	database *db.DB
+
+	// Optional policy for Unix socket peers.
+	peerPolicy *PeerPolicy
+
+	// Optional failures injected in chaos mode.
+	chaos *chaosInjector
+
+	// Optional per-client rate limits and quotas.
+	quotas *quotaGuard
 }
 
 // subscriber tracks an event subscription.
 type subscriber struct {
-	id     int64
-	conn   net.Conn
-	events chan Event
-	done   chan struct{}
+	id       int64
+	clientID int64
+	conn     net.Conn
+	events   chan Event
+	done     chan struct{}
+	// queued and dropped count the events offered to the subscription.
+	queued  atomic.Int64
+	dropped atomic.Int64
+	// watch scopes a watch_requests subscription to one session's
+	// requests; nil for subscribe, which receives every broadcast.
+	watch *requestWatch
 }
 
 // Event represents a daemon event sent to subscribers.
@@ -122,8 +198,13 @@ type Event struct {
 	Type    string `json:"type"`
 	Payload any    `json:"payload"`
 	Time    int64  `json:"time"`
+	// Seq numbers events in the order the server broadcast them.
+	Seq int64 `json:"seq,omitempty"`
 }
 
+// eventHistorySize is how many recent events a resuming stream can replay.
+const eventHistorySize = 512
+
 // NewIPCServer creates a new IPC server listening on the given Unix socket.
 func NewIPCServer(socketPath string, logger *log.Logger) (*IPCServer, error) {
 	if socketPath == "" {
@@ -263,8 +344,17 @@ func (s *IPCServer) handleConnection(conn net.Conn) {
 	// Ensure responses and subscription events cannot interleave on the same connection.
 	locked := &lockedConn{Conn: conn}
 
+	peer, ok := s.admitPeer(conn)
+	if !ok {
+		return
+	}
+	locked.peer = peer
+	locked.caps = s.connCaps(peer)
+
 	s.activeConns.Add(1)
 	defer s.activeConns.Add(-1)
+	locked.client = s.trackClient(conn, peer)
+	defer s.untrackClient(locked.client.id)
 
 	scanner := bufio.NewScanner(locked)
 	// Increase buffer for larger requests.
@@ -299,6 +389,15 @@ func (s *IPCServer) handleConnection(conn net.Conn) {
 	}
 
 	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			// Tell the client why before hanging up; the rest of the line
+			// cannot be skipped reliably.
+			s.logger.Warn("request refused", "reason", "request line too large")
+			_ = s.writeResponse(locked, &RPCResponse{
+				Error: &Error{Code: ErrCodeInvalidReq, Message: "request too large"},
+			})
+			return
+		}
 		s.logger.Debug("connection read error", "error", err)
 	}
 }
@@ -313,21 +412,61 @@ func (s *IPCServer) handleRequest(conn net.Conn, data []byte) *RPCResponse {
 		}
 	}
 
+	if lc, ok := conn.(*lockedConn); ok && lc.client != nil {
+		lc.client.requests.Add(1)
+		lc.client.lastMethod.Store(req.Method)
+	}
+	if resp := s.checkQuota(conn, req); resp != nil {
+		return resp
+	}
+
+	// status stays reliable so chaos mode's counters can be read.
+	if req.Method != "status" {
+		switch s.chaos.roll() {
+		case chaosDrop:
+			s.logger.Debug("chaos: request dropped", "method", req.Method)
+			return nil
+		case chaosReset:
+			s.logger.Debug("chaos: connection reset", "method", req.Method)
+			resetConn(conn)
+			return nil
+		}
+	}
+
+	if resp := s.authorize(conn, req); resp != nil {
+		return resp
+	}
+	if resp := s.validateParams(req); resp != nil {
+		return resp
+	}
+
 	switch req.Method {
 	case "ping":
 		return s.handlePing(req)
+	case "hello":
+		return s.handleHello(req, conn)
 	case "status":
 		return s.handleStatus(req)
 	case "notify":
 		return s.handleNotify(req)
 	case "subscribe":
 		return s.handleSubscribe(req, conn)
+	case "watch_requests":
+		return s.handleWatchRequests(req, conn)
 	case "verify_execute":
 		return s.handleVerifyExecute(req)
+	case "create_request":
+		return s.handleCreateRequest(s.ctx, req, peerOf(conn))
 	case "hook_query":
-		return s.handleHookQuery(req)
+		return s.handleHookQuery(req, peerOf(conn))
 	case "hook_health":
 		return s.handleHookHealth(req)
+	case "read_model":
+		return s.handleReadModel(req)
+	case "heartbeat":
+		return s.handleHeartbeat(req)
+	case "db_probe":
+		return s.handleDBProbe(req)
 	default:
 		return &RPCResponse{
 			Error: &Error{Code: ErrCodeMethodNotFound, Message: "method not found: " + req.Method},
@@ -350,14 +489,53 @@ func (s *IPCServer) handleStatus(req RPCRequest) *RPCResponse {
 	subCount := len(s.subscribers)
 	s.subscribersMu.RUnlock()
 
+	result := map[string]any{
+		"uptime_seconds":  int64(time.Since(s.startTime).Seconds()),
+		"pending_count":   s.pendingCount.Load(),
+		"active_sessions": s.activeConns.Load(),
+		"subscribers":     subCount,
+	}
+	if s.readModel != nil {
+		if snap, err := s.readModel.Snapshot(); err == nil {
+			result["pending_count"] = int32(len(snap.Pending))
+		}
+		result["cache"] = s.readModel.Stats()
+	}
+	if s.eventWriter != nil {
+		result["writer"] = s.eventWriter.Stats()
+	}
+	if s.chaos != nil {
+		result["chaos"] = s.chaos.stats()
+	}
+	if s.quotas != nil {
+		result["quotas"] = s.quotas.stats()
+	}
+	result["broadcast"] = s.broadcastStats()
+
 	return &RPCResponse{
-		Result: map[string]any{
-			"uptime_seconds":  int64(time.Since(s.startTime).Seconds()),
-			"pending_count":   s.pendingCount.Load(),
-			"active_sessions": s.activeConns.Load(),
-			"subscribers":     subCount,
-		},
-		ID: req.ID,
+		Result: result,
+		ID:     req.ID,
+	}
+}
+
+// handleReadModel returns the cached pending requests and active sessions.
+func (s *IPCServer) handleReadModel(req RPCRequest) *RPCResponse {
+	if s.readModel == nil {
+		return &RPCResponse{
+			Error: &Error{Code: ErrCodeInternal, Message: "read model not configured"},
+			ID:    req.ID,
+		}
+	}
+	snap, err := s.readModel.Snapshot()
+	if err != nil {
+		return &RPCResponse{
+			Error: &Error{Code: ErrCodeInternal, Message: err.Error()},
+			ID:    req.ID,
+		}
+	}
+	return &RPCResponse{
+		Result: snap,
+		ID:     req.ID,
 	}
 }
 
@@ -390,6 +568,10 @@ func (s *IPCServer) handleNotify(req RPCRequest) *RPCResponse {
 		Time:    time.Now().Unix(),
 	}
 
+	// Clients notify after writing; drop cached state so readers see it.
+	if s.readModel != nil && params.Type != EventHealthProbe {
+		s.readModel.Invalidate()
+	}
 	s.broadcast(event)
 
 	return &RPCResponse{
@@ -398,20 +580,63 @@ func (s *IPCServer) handleNotify(req RPCRequest) *RPCResponse {
 	}
 }
 
+// HeartbeatParams are parameters for the heartbeat method.
+type HeartbeatParams struct {
+	SessionID string `json:"session_id"`
+}
+
+// handleHeartbeat queues a session heartbeat for the next batched write.
+func (s *IPCServer) handleHeartbeat(req RPCRequest) *RPCResponse {
+	if s.eventWriter == nil {
+		return &RPCResponse{
+			Error: &Error{Code: ErrCodeInternal, Message: "event writer not configured"},
+			ID:    req.ID,
+		}
+	}
+
+	var params HeartbeatParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return &RPCResponse{
+			Error: &Error{Code: ErrCodeInvalidParams, Message: "invalid params: " + err.Error()},
+			ID:    req.ID,
+		}
+	}
+	if params.SessionID == "" {
+		return &RPCResponse{
+			Error: &Error{Code: ErrCodeInvalidParams, Message: "session_id is required"},
+			ID:    req.ID,
+		}
+	}
+
+	// A zero time stamps the heartbeat with the database clock.
+	s.eventWriter.Heartbeat(params.SessionID, time.Time{})
+	return &RPCResponse{
+		Result: map[string]bool{"queued": true},
+		ID:     req.ID,
+	}
+}
+
 // handleSubscribe sets up event streaming for the connection.
 func (s *IPCServer) handleSubscribe(req RPCRequest, conn net.Conn) *RPCResponse {
-	id := s.nextSubID.Add(1)
+	return s.startSubscription(req, conn, nil, nil)
+}
 
-	sub := &subscriber{
-		id:     id,
-		conn:   conn,
-		events: make(chan Event, 100),
-		done:   make(chan struct{}),
+// startSubscription registers a subscriber, confirms it and streams its
+// events until the connection or server goes away. registered, if set, runs
+// once the subscriber is registered and before it is confirmed.
+func (s *IPCServer) startSubscription(req RPCRequest, conn net.Conn, watch *requestWatch, registered func()) *RPCResponse {
+	if resp := s.checkSubscriptionQuota(conn, req); resp != nil {
+		return resp
 	}
+	sub := s.newSubscriber(conn, watch)
+	id := sub.id
 
 	s.subscribersMu.Lock()
 	s.subscribers[id] = sub
 	s.subscribersMu.Unlock()
+	if registered != nil {
+		registered()
+	}
 
 	// Send initial response.
 	resp := &RPCResponse{
@@ -458,20 +683,119 @@ func (s *IPCServer) streamEvents(sub *subscriber) {
 	}
 }
 
-// broadcast sends an event to all subscribers.
+// broadcast sends an event to all subscribers and queues it for storage.
+// Health probe events are delivered but not stored.
 func (s *IPCServer) broadcast(event Event) {
+	// Subscribers that overflowed are disconnected once the locks below
+	// are released.
+	var slow []*subscriber
+	defer func() {
+		for _, sub := range slow {
+			s.disconnectSlow(sub)
+		}
+	}()
+
+	if s.eventWriter != nil && event.Type != EventHealthProbe {
+		s.recordEvent(event)
+	}
+
+	// Numbering and delivery happen under historyMu so SubscribeEvents sees
+	// every event either in its replay or on its channel, never both.
+	s.historyMu.Lock()
+	defer s.historyMu.Unlock()
+	s.lastSeq++
+	event.Seq = s.lastSeq
+	s.history = append(s.history, event)
+	if len(s.history) > eventHistorySize {
+		s.history = s.history[len(s.history)-eventHistorySize:]
+	}
+
 	s.subscribersMu.RLock()
 	defer s.subscribersMu.RUnlock()
 
 	for _, sub := range s.subscribers {
-		select {
-		case sub.events <- event:
-		default:
-			// Buffer full, skip this subscriber.
+		if sub.watch != nil {
+			continue
+		}
+		if !s.offer(sub, event) {
+			slow = append(slow, sub)
 		}
 	}
 }
 
+// recordEvent queues an event for the batched writer.
+func (s *IPCServer) recordEvent(event Event) {
+	e := &db.DaemonEvent{Type: event.Type, CreatedAt: time.Unix(event.Time, 0).UTC()}
+	if event.Payload != nil {
+		payload, err := json.Marshal(event.Payload)
+		if err != nil {
+			s.logger.Debug("marshal event payload failed", "error", err)
+		} else {
+			e.PayloadJSON = string(payload)
+		}
+	}
+	s.eventWriter.RecordEvent(e)
+}
+
+// EventSubscription is an in-process subscription to the events the
+// subscribe method streams.
+type EventSubscription struct {
+	// Replay holds the recent events after the sequence number asked for.
+	Replay []Event
+	// Gap is set when events after that sequence number are no longer held,
+	// or it is ahead of the server, which then restarted since.
+	Gap bool
+
+	s   *IPCServer
+	sub *subscriber
+}
+
+// SubscribeEvents subscribes to broadcast events. With since >= 0, the
+// held events numbered after since are replayed first; with since < 0 only
+// new events are delivered. Close the subscription when done.
+func (s *IPCServer) SubscribeEvents(since int64) *EventSubscription {
+	sub := s.newSubscriber(nil, nil)
+	es := &EventSubscription{s: s, sub: sub}
+
+	s.historyMu.Lock()
+	defer s.historyMu.Unlock()
+	if since >= 0 {
+		switch {
+		case since > s.lastSeq:
+			es.Gap = true
+		case since < s.lastSeq && (len(s.history) == 0 || s.history[0].Seq > since+1):
+			es.Gap = true
+		}
+		for _, event := range s.history {
+			if event.Seq > since {
+				es.Replay = append(es.Replay, event)
+			}
+		}
+	}
+	s.subscribersMu.Lock()
+	s.subscribers[sub.id] = sub
+	s.subscribersMu.Unlock()
+	return es
+}
+
+// Events delivers new events. When the subscriber falls a queue's worth of
+// events behind, the oldest are dropped, leaving a gap in their sequence
+// numbers, or under OverflowDisconnect the subscription ends.
+func (es *EventSubscription) Events() <-chan Event {
+	return es.sub.events
+}
+
+// Done is closed when the server stops or the subscription is
+// disconnected for falling behind.
+func (es *EventSubscription) Done() <-chan struct{} {
+	return es.sub.done
+}
+
+// Close ends the subscription.
+func (es *EventSubscription) Close() {
+	es.s.removeSubscriber(es.sub.id)
+}
+
 // removeSubscriber removes a subscriber from the map.
 func (s *IPCServer) removeSubscriber(id int64) {
 	s.subscribersMu.Lock()
@@ -504,6 +828,46 @@ func (s *IPCServer) BroadcastEvent(eventType string, payload any) {
 	})
 }
 
+// EventRequestStatusChanged is broadcast after the daemon changes a
+// request's status through the state machine.
+const EventRequestStatusChanged = "request_status_changed"
+
+// statusBroadcaster returns a state machine listener that drops the cached
+// read model and broadcasts each transition to subscribers.
+func statusBroadcaster(s *IPCServer, m *ReadModel) statemachine.Listener {
+	return func(ev statemachine.Event) {
+		if m != nil {
+			m.Invalidate()
+		}
+		s.BroadcastEvent(EventRequestStatusChanged, map[string]any{
+			"request_id": ev.RequestID,
+			"from":       string(ev.From),
+			"to":         string(ev.To),
+			"authority":  string(ev.Authority),
+			"reason":     ev.Reason,
+			"actor":      ev.Actor,
+		})
+	}
+}
+
+// SetReadModel configures the cache used for status, hook_query approval
+// lookups and read_model requests.
+func (s *IPCServer) SetReadModel(m *ReadModel) {
+	s.readModel = m
+}
+
+// SetEventWriter configures the batched writer used to persist broadcast
+// events and heartbeat requests.
+func (s *IPCServer) SetEventWriter(w *db.BufferedEventWriter) {
+	s.eventWriter = w
+}
+
+// SetPatternHitCounter configures where hook_query records which patterns
+// classified each command.
+func (s *IPCServer) SetPatternHitCounter(c *PatternHitCounter) {
+	s.patternHits = c
+}
+
 // SetVerifier configures the execution verifier for gate checks.
 func (s *IPCServer) SetVerifier(v *Verifier) {
 	s.verifier = v
@@ -539,7 +903,7 @@ func (s *IPCServer) handleVerifyExecute(req RPCRequest) *RPCResponse {
 		}
 	}
 
-	result, err := s.verifier.VerifyAndMarkExecuting(params.RequestID, params.SessionID)
+	result, err := s.verifier.VerifyAndMarkExecuting(params)
 	if err != nil {
 		return &RPCResponse{
 			Error: &Error{Code: ErrCodeInternal, Message: err.Error()},
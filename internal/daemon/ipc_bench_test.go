@@ -0,0 +1,100 @@
+package daemon
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+// hookQueryP99Budget is the latency budget for a local hook_query round
+// trip. The hook blocks every agent command on this call.
+const hookQueryP99Budget = 10 * time.Millisecond
+
+// startBenchIPC starts an IPC server and returns a connected client.
+func startBenchIPC(tb testing.TB) *IPCClient {
+	tb.Helper()
+
+	socketPath := filepath.Join(shortSocketDir(tb), "bench.sock")
+	srv, err := NewIPCServer(socketPath, newTestLogger())
+	if err != nil {
+		tb.Fatalf("NewIPCServer failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		_ = srv.Start(ctx)
+	}()
+
+	client := NewIPCClient(socketPath)
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if err := client.Connect(ctx); err == nil {
+			break
+		} else if time.Now().After(deadline) {
+			cancel()
+			tb.Fatalf("connect failed: %v", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	tb.Cleanup(func() {
+		_ = client.Close()
+		cancel()
+		_ = srv.Stop()
+	})
+	return client
+}
+
+func hookQueryRoundTrip(tb testing.TB, client *IPCClient, command string) {
+	tb.Helper()
+	resp, err := client.call("hook_query", HookQueryParams{Command: command, CWD: "/work"})
+	if err != nil {
+		tb.Fatalf("hook_query failed: %v", err)
+	}
+	if resp.Error != nil {
+		tb.Fatalf("hook_query error: %s", resp.Error.Message)
+	}
+}
+
+func BenchmarkIPC_Ping(b *testing.B) {
+	client := startBenchIPC(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.call("ping", nil); err != nil {
+			b.Fatalf("ping failed: %v", err)
+		}
+	}
+}
+
+func BenchmarkIPC_HookQuery(b *testing.B) {
+	client := startBenchIPC(b)
+
+	for _, bc := range []struct{ name, cmd string }{
+		{"safe", "git status"},
+		{"dangerous", "rm -rf ./build"},
+		{"critical", "rm -rf /"},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				hookQueryRoundTrip(b, client, bc.cmd)
+			}
+		})
+	}
+}
+
+func TestIPC_HookQuery_PerfBudget(t *testing.T) {
+	testutil.RequirePerfBudgets(t)
+
+	client := startBenchIPC(t)
+	hookQueryRoundTrip(t, client, "git status") // warm up
+
+	testutil.RequireP99(t, "hook_query round trip", 300, hookQueryP99Budget, func() {
+		hookQueryRoundTrip(t, client, "rm -rf ./build")
+	})
+}
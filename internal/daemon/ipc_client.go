@@ -5,6 +5,7 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"os"
@@ -12,8 +13,40 @@ import (
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/keyring"
 )
 
+// tcpAuthKey returns the session key for the TCP handshake: SLB_SESSION_KEY,
+// else the key `slb session start` stored in the keyring for SLB_SESSION_ID.
+func tcpAuthKey() string {
+	if key := strings.TrimSpace(os.Getenv("SLB_SESSION_KEY")); key != "" {
+		return key
+	}
+	sessionID := strings.TrimSpace(os.Getenv("SLB_SESSION_ID"))
+	if sessionID == "" {
+		return ""
+	}
+	mode := string(keyring.ModeAuto)
+	if cfg, err := config.Load(config.LoadOptions{}); err == nil {
+		mode = cfg.General.SessionKeyStore
+	}
+	store, err := keyring.OpenDefault(mode)
+	if err != nil || store == nil {
+		return ""
+	}
+	key, err := store.Get(keyring.SessionAccount(sessionID))
+	if err != nil {
+		return ""
+	}
+	return key
+}
+
+// ErrDaemonUnreachable is returned when no daemon answers on the socket.
+var ErrDaemonUnreachable = errors.New("daemon unreachable")
+
 // IPCClient provides methods to communicate with the daemon via IPC.
 type IPCClient struct {
 	socketPath string
@@ -47,7 +80,7 @@ func (c *IPCClient) Connect(ctx context.Context) error {
 		conn, err = d.DialContext(ctx, "tcp", host)
 		if err == nil {
 			hello, err := json.Marshal(map[string]string{
-				"auth": strings.TrimSpace(os.Getenv("SLB_SESSION_KEY")),
+				"auth": tcpAuthKey(),
 			})
 			if err != nil {
 				_ = conn.Close()
@@ -65,7 +98,7 @@ func (c *IPCClient) Connect(ctx context.Context) error {
 	if conn == nil {
 		conn, err = d.DialContext(ctx, "unix", c.socketPath)
 		if err != nil {
-			return fmt.Errorf("connecting to daemon: %w", err)
+			return fmt.Errorf("connecting to daemon: %w: %w", ErrDaemonUnreachable, err)
 		}
 	}
 
@@ -165,6 +198,16 @@ type DaemonStatusInfo struct {
 	PendingCount   int32 `json:"pending_count"`
 	ActiveSessions int32 `json:"active_sessions"`
 	Subscribers    int   `json:"subscribers"`
+	// Cache is set when the daemon serves a read model.
+	Cache *ReadModelStats `json:"cache,omitempty"`
+	// Writer is set when the daemon batches event and heartbeat writes.
+	Writer *db.BufferedWriterStats `json:"writer,omitempty"`
+	// Chaos is set when the daemon runs in chaos mode.
+	Chaos *ChaosStats `json:"chaos,omitempty"`
+	// Quotas is set when the daemon limits its clients.
+	Quotas *QuotaStats `json:"quotas,omitempty"`
+	// Broadcast reports event delivery to subscribers.
+	Broadcast *BroadcastStats `json:"broadcast,omitempty"`
 }
 
 // Status returns the daemon's status information.
@@ -196,6 +239,34 @@ func (c *IPCClient) Status(ctx context.Context) (*DaemonStatusInfo, error) {
 	return &info, nil
 }
 
+// ReadModel returns the daemon's cached pending requests and active sessions.
+func (c *IPCClient) ReadModel(ctx context.Context) (*ReadModelSnapshot, error) {
+	if err := c.Connect(ctx); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.call("read_model", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Error != nil {
+		return nil, fmt.Errorf("read_model error: %s", resp.Error.Message)
+	}
+
+	data, err := json.Marshal(resp.Result)
+	if err != nil {
+		return nil, fmt.Errorf("marshal result: %w", err)
+	}
+
+	var snap ReadModelSnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("unmarshal read model: %w", err)
+	}
+
+	return &snap, nil
+}
+
 // Notify sends a notification to the daemon for broadcasting.
 func (c *IPCClient) Notify(ctx context.Context, eventType string, payload any) error {
 	if err := c.Connect(ctx); err != nil {
@@ -217,6 +288,120 @@ func (c *IPCClient) Notify(ctx context.Context, eventType string, payload any) e
 	return nil
 }
 
+// Heartbeat asks the daemon to record a session heartbeat. The write is
+// batched with other heartbeats and events.
+func (c *IPCClient) Heartbeat(ctx context.Context, sessionID string) error {
+	if err := c.Connect(ctx); err != nil {
+		return err
+	}
+
+	resp, err := c.call("heartbeat", HeartbeatParams{SessionID: sessionID})
+	if err != nil {
+		return err
+	}
+
+	if resp.Error != nil {
+		return fmt.Errorf("heartbeat error: %s", resp.Error.Message)
+	}
+
+	return nil
+}
+
+// HookQuery classifies a command the way the generated hook does, including
+// whether an approved request already covers it.
+func (c *IPCClient) HookQuery(ctx context.Context, params HookQueryParams) (*HookQueryResult, error) {
+	if err := c.Connect(ctx); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.call("hook_query", params)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Error != nil {
+		return nil, fmt.Errorf("hook_query error: %s", resp.Error.Message)
+	}
+
+	data, err := json.Marshal(resp.Result)
+	if err != nil {
+		return nil, fmt.Errorf("marshal result: %w", err)
+	}
+
+	var result HookQueryResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("unmarshal hook query: %w", err)
+	}
+
+	return &result, nil
+}
+
+// VerifyExecute asks the daemon's execution gate whether sessionID may run
+// an approved request from this process's directory and environment,
+// marking it executing when allowed.
+func (c *IPCClient) VerifyExecute(ctx context.Context, requestID, sessionID string) (*VerifyExecuteResponse, error) {
+	if err := c.Connect(ctx); err != nil {
+		return nil, err
+	}
+
+	params := VerifyExecuteParams{RequestID: requestID, SessionID: sessionID, Environ: os.Environ()}
+	if wd, err := os.Getwd(); err == nil {
+		params.Cwd = wd
+	}
+	resp, err := c.call("verify_execute", params)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Error != nil {
+		return nil, fmt.Errorf("verify_execute error: %s", resp.Error.Message)
+	}
+
+	data, err := json.Marshal(resp.Result)
+	if err != nil {
+		return nil, fmt.Errorf("marshal result: %w", err)
+	}
+
+	var result VerifyExecuteResponse
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("unmarshal verify result: %w", err)
+	}
+
+	return &result, nil
+}
+
+// CreateRequest asks the daemon to create an approval request. Set
+// params.IdempotencyKey to make retrying after a lost response safe.
+func (c *IPCClient) CreateRequest(ctx context.Context, params CreateRequestParams) (*CreateRequestResponse, error) {
+	if err := c.Connect(ctx); err != nil {
+		return nil, err
+	}
+	if params.Path == "" {
+		params.Path = os.Getenv("PATH")
+	}
+
+	resp, err := c.call("create_request", params)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Error != nil {
+		return nil, fmt.Errorf("create_request error: %s", resp.Error.Message)
+	}
+
+	data, err := json.Marshal(resp.Result)
+	if err != nil {
+		return nil, fmt.Errorf("marshal result: %w", err)
+	}
+
+	var result CreateRequestResponse
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("unmarshal create result: %w", err)
+	}
+
+	return &result, nil
+}
+
 // SubscriptionInfo contains subscription information.
 type SubscriptionInfo struct {
 	Subscribed     bool  `json:"subscribed"`
@@ -226,18 +411,43 @@ type SubscriptionInfo struct {
 // Subscribe subscribes to daemon events. Returns a channel that receives events.
 // The caller should read from the channel and call Close when done.
 func (c *IPCClient) Subscribe(ctx context.Context) (<-chan Event, error) {
+	return c.subscribe(ctx, "subscribe", nil)
+}
+
+// WatchRequests subscribes to status changes of sessionID's requests, or
+// only of requestIDs when given. The daemon pushes a
+// request_status_changed event whenever one of them moves, whoever moved
+// it, and keeps the session active while the subscription is open. Like
+// Subscribe, the channel is closed when ctx is done or the daemon goes
+// away.
+func (c *IPCClient) WatchRequests(ctx context.Context, sessionID string, requestIDs []string) (<-chan Event, error) {
+	return c.subscribe(ctx, "watch_requests", WatchRequestsParams{SessionID: sessionID, RequestIDs: requestIDs})
+}
+
+// subscribe calls a streaming method and relays the events it sends.
+func (c *IPCClient) subscribe(ctx context.Context, method string, params any) (<-chan Event, error) {
 	if err := c.Connect(ctx); err != nil {
 		return nil, err
 	}
 
-	// Subscribe is designed for long-lived event streaming.
+	// Subscriptions are designed for long-lived event streaming.
 	// Avoid issuing other RPC calls on this client while subscribed.
 
+	var rawParams json.RawMessage
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("marshal params: %w", err)
+		}
+		rawParams = data
+	}
+
 	c.mu.Lock()
 	// Send subscribe request
 	id := c.nextID.Add(1)
 	req := RPCRequest{
-		Method: "subscribe",
+		Method: method,
+		Params: rawParams,
 		ID:     id,
 	}
 
@@ -270,7 +480,7 @@ func (c *IPCClient) Subscribe(ctx context.Context) (<-chan Event, error) {
 
 	if resp.Error != nil {
 		c.mu.Unlock()
-		return nil, fmt.Errorf("subscribe error: %s", resp.Error.Message)
+		return nil, fmt.Errorf("%s error: %s", method, resp.Error.Message)
 	}
 	c.mu.Unlock()
 
@@ -11,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Dicklesworthstone/slb/internal/keyring"
 	"github.com/charmbracelet/log"
 )
 
@@ -407,6 +408,35 @@ func TestIPCClient_Connect_TCPFallback(t *testing.T) {
 	_ = srv.Stop()
 }
 
+func TestTCPAuthKey(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("SLB_SESSION_KEY_STORE", "file")
+	t.Setenv("SLB_SESSION_KEY", "")
+	t.Setenv("SLB_SESSION_ID", "sess-tcp")
+
+	if got := tcpAuthKey(); got != "" {
+		t.Errorf("expected no key before one is stored, got %q", got)
+	}
+	store := keyring.NewFileStore(filepath.Join(home, ".slb", keyring.FileName))
+	if err := store.Set(keyring.SessionAccount("sess-tcp"), "stored-key"); err != nil {
+		t.Fatal(err)
+	}
+	if got := tcpAuthKey(); got != "stored-key" {
+		t.Errorf("tcpAuthKey = %q, want the stored key", got)
+	}
+	t.Setenv("SLB_SESSION_KEY", " env-key ")
+	if got := tcpAuthKey(); got != "env-key" {
+		t.Errorf("tcpAuthKey = %q, want SLB_SESSION_KEY to win", got)
+	}
+	t.Setenv("SLB_SESSION_KEY", "")
+	t.Setenv("SLB_SESSION_KEY_STORE", "off")
+	if got := tcpAuthKey(); got != "" {
+		t.Errorf("expected no lookup with storage off, got %q", got)
+	}
+}
+
 func TestIPCClient_ConnectTCP_HandshakeWriteError(t *testing.T) {
 	if runtime.GOOS == "windows" {
 		t.Skip("unix socket tests not supported on windows")
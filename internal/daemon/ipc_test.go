@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
 	"github.com/charmbracelet/log"
 )
 
@@ -1062,3 +1063,75 @@ func TestIPCServer_handleVerifyExecute_DeniedDoesNotMarkExecuting(t *testing.T)
 		t.Fatalf("status=%s want %s", got.Status, db.StatusApproved)
 	}
 }
+
+func TestIPCClient_HeartbeatAndEventsAreBatched_Unix(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix socket tests not supported on windows")
+	}
+
+	h := testutil.NewHarness(t)
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+
+	socketPath := filepath.Join(shortSocketDir(t), "hb.sock")
+	srv, err := NewIPCServer(socketPath, log.New(io.Discard))
+	if err != nil {
+		t.Fatalf("NewIPCServer: %v", err)
+	}
+	writer := db.NewBufferedEventWriter(h.DB, db.BufferedWriterOptions{FlushInterval: time.Hour})
+	srv.SetEventWriter(writer)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+		_ = srv.Stop()
+	})
+	go func() { _ = srv.Start(ctx) }()
+
+	callCtx, callCancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer callCancel()
+	client := NewIPCClient(socketPath)
+	t.Cleanup(func() { _ = client.Close() })
+
+	if err := client.Heartbeat(callCtx, ""); err == nil {
+		t.Fatal("expected heartbeat without a session to fail")
+	}
+	if err := client.Heartbeat(callCtx, sess.ID); err != nil {
+		t.Fatalf("Heartbeat: %v", err)
+	}
+	if err := client.Notify(callCtx, "request_pending", map[string]any{"request_id": "req-1"}); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	info, err := client.Status(callCtx)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if info.Writer == nil || info.Writer.Pending != 2 {
+		t.Fatalf("expected 2 queued writes in status, got %+v", info.Writer)
+	}
+
+	if err := writer.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	events, err := h.DB.ListDaemonEvents(0, 10)
+	if err != nil {
+		t.Fatalf("ListDaemonEvents: %v", err)
+	}
+	if len(events) != 1 || events[0].Type != "request_pending" || events[0].PayloadJSON != `{"request_id":"req-1"}` {
+		t.Fatalf("unexpected stored events: %+v", events)
+	}
+	if stats := writer.Stats(); stats.Heartbeats != 1 || stats.Flushes != 1 {
+		t.Errorf("unexpected writer stats: %+v", stats)
+	}
+}
+
+func TestIPCServer_HeartbeatWithoutWriter(t *testing.T) {
+	srv, err := NewIPCServer(filepath.Join(shortSocketDir(t), "nw.sock"), newTestLogger())
+	if err != nil {
+		t.Fatalf("NewIPCServer: %v", err)
+	}
+	resp := srv.handleHeartbeat(RPCRequest{Method: "heartbeat", Params: json.RawMessage(`{"session_id":"s"}`), ID: 1})
+	if resp.Error == nil || resp.Error.Code != ErrCodeInternal {
+		t.Fatalf("expected internal error without an event writer, got %+v", resp)
+	}
+}
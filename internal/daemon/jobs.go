@@ -0,0 +1,162 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/schedule"
+	"github.com/charmbracelet/log"
+)
+
+// Daemon job names, as configured under [jobs] and listed by `slb daemon
+// jobs list`.
+const (
+	JobExpirySweep     = "expiry_sweep"
+	JobReviewReminders = "review_reminders"
+	JobRetentionPrune  = "retention_prune"
+	JobWeeklyDigest    = "weekly_digest"
+)
+
+// Events broadcast by the daemon's jobs.
+const (
+	// EventReviewReminder is broadcast for a request still waiting for
+	// review, each time it is due a reminder.
+	EventReviewReminder = "review_reminder"
+	// EventDigestReady is broadcast when the weekly digest has been written.
+	EventDigestReady = "digest_ready"
+)
+
+// daemonJobs is what the daemon's jobs work with.
+type daemonJobs struct {
+	db            *db.DB
+	projectPath   string
+	cfg           config.Config
+	sweeper       *TimeoutHandler
+	notifications *NotificationManager
+	broadcast     func(eventType string, payload any)
+	logger        *log.Logger
+}
+
+// jobs returns the daemon's jobs as configured. A job whose schedule does
+// not parse is left out with a warning.
+func (d *daemonJobs) jobs() []Job {
+	all := []struct {
+		name string
+		cfg  config.JobConfig
+		run  func(context.Context) error
+	}{
+		{JobExpirySweep, d.cfg.Jobs.ExpirySweep, func(context.Context) error { return d.sweeper.Sweep() }},
+		{JobReviewReminders, d.cfg.Jobs.ReviewReminders, d.remindReviewers},
+		{JobRetentionPrune, d.cfg.Jobs.RetentionPrune, d.pruneRetention},
+		{JobWeeklyDigest, d.cfg.Jobs.WeeklyDigest, d.writeDigest},
+	}
+	var jobs []Job
+	for _, j := range all {
+		sched, err := schedule.Parse(j.cfg.Schedule)
+		if err != nil {
+			d.logger.Warn("daemon job disabled", "job", j.name, "error", err)
+			continue
+		}
+		jobs = append(jobs, Job{Name: j.name, Schedule: sched, Enabled: j.cfg.Enabled, Run: j.run})
+	}
+	return jobs
+}
+
+// remindReviewers sends a reminder for each pending request due one. The
+// first comes jobs.reminder_minutes after the request was made; each later
+// one waits twice as long as the one before, up to
+// jobs.reminder_max_minutes.
+func (d *daemonJobs) remindReviewers(ctx context.Context) error {
+	pending, err := d.db.ListPendingRequests(d.projectPath)
+	if err != nil {
+		return err
+	}
+	sent, err := d.db.ListReviewReminders()
+	if err != nil {
+		return err
+	}
+	first := time.Duration(d.cfg.Jobs.ReminderMinutes) * time.Minute
+	maxDelay := time.Duration(d.cfg.Jobs.ReminderMaxMinutes) * time.Minute
+
+	now := d.db.Now()
+	for _, req := range pending {
+		count, last := 0, req.CreatedAt
+		if r := sent[req.ID]; r != nil {
+			count, last = r.Reminders, r.LastSentAt
+		}
+		if now.Sub(last) < reminderDelay(count, first, maxDelay) {
+			continue
+		}
+		if err := d.db.RecordReviewReminder(req.ID, now); err != nil {
+			return err
+		}
+		d.broadcast(EventReviewReminder, map[string]any{
+			"request_id":      req.ID,
+			"risk_tier":       string(req.RiskTier),
+			"requestor":       req.RequestorAgent,
+			"reminder":        count + 1,
+			"pending_seconds": int(now.Sub(req.CreatedAt).Seconds()),
+		})
+		d.notifications.Remind(ctx, req, count+1)
+	}
+	_, err = d.db.PruneReviewReminders()
+	return err
+}
+
+// reminderDelay is how long after the last reminder (or the request, for
+// the first) the next one is due, given how many were sent.
+func reminderDelay(sent int, first, maxDelay time.Duration) time.Duration {
+	if sent >= 30 {
+		return maxDelay
+	}
+	return min(first<<sent, maxDelay)
+}
+
+// pruneRetention deletes stored daemon events older than
+// history.retention_days; 0 keeps them forever.
+func (d *daemonJobs) pruneRetention(context.Context) error {
+	days := d.cfg.History.RetentionDays
+	if days <= 0 {
+		return nil
+	}
+	n, err := d.db.PruneDaemonEvents(d.db.Now().AddDate(0, 0, -days))
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		d.logger.Info("pruned daemon events", "deleted", n, "retention_days", days)
+	}
+	return nil
+}
+
+// writeDigest writes the last 7 days' weekly report, as markdown, to
+// .slb/reports/weekly-<date>.md.
+func (d *daemonJobs) writeDigest(context.Context) error {
+	until := d.db.Now().UTC().Truncate(time.Second).Add(time.Second)
+	since := until.AddDate(0, 0, -7)
+	report, err := core.BuildWeeklyReport(d.db, d.projectPath, since, until)
+	if err != nil {
+		return fmt.Errorf("building weekly report: %w", err)
+	}
+
+	dir := filepath.Join(d.projectPath, ".slb", "reports")
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("creating reports directory: %w", err)
+	}
+	path := filepath.Join(dir, "weekly-"+until.In(time.Local).Format("2006-01-02")+".md")
+	if err := os.WriteFile(path, []byte(report.Markdown()), 0o600); err != nil {
+		return fmt.Errorf("writing weekly report: %w", err)
+	}
+	d.broadcast(EventDigestReady, map[string]any{
+		"path":  path,
+		"since": since.Format(time.RFC3339),
+		"until": until.Format(time.RFC3339),
+	})
+	return nil
+}
@@ -15,6 +15,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Dicklesworthstone/slb/internal/clock"
 	"github.com/Dicklesworthstone/slb/internal/config"
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/charmbracelet/log"
@@ -35,6 +36,10 @@ const (
 	WebhookEventRequestTimeout WebhookEvent = "request_timeout"
 	// WebhookEventRequestEscalated is sent when a request is escalated.
 	WebhookEventRequestEscalated WebhookEvent = "request_escalated"
+	// WebhookEventAwaitingHuman is sent when no agent reviewer acted on a request in time.
+	WebhookEventAwaitingHuman WebhookEvent = "request_awaiting_human"
+	// WebhookEventReviewReminder is sent when a request is still waiting for review.
+	WebhookEventReviewReminder WebhookEvent = "review_reminder"
 )
 
 // WebhookPayload is the JSON payload sent to webhook URLs.
@@ -46,8 +51,19 @@ type WebhookPayload struct {
 	Requestor string       `json:"requestor"`
 	Timestamp string       `json:"timestamp"`
 	Project   string       `json:"project,omitempty"`
+	// Urgency is set on awaiting-human pages: UrgencyUrgent when no
+	// reviewer has opened the request, UrgencyNormal when some have.
+	Urgency string `json:"urgency,omitempty"`
+	// SeenBy lists reviewers who opened the request without deciding.
+	SeenBy []string `json:"seen_by,omitempty"`
 }
 
+// Urgencies of an awaiting-human page.
+const (
+	UrgencyUrgent = "urgent"
+	UrgencyNormal = "normal"
+)
+
 // WebhookNotifier handles webhook notifications.
 type WebhookNotifier interface {
 	Send(ctx context.Context, url string, payload WebhookPayload) error
@@ -69,7 +85,7 @@ type NotificationManager struct {
 	logger      *log.Logger
 	notifier    DesktopNotifier
 	webhook     WebhookNotifier
-	now         func() time.Time
+	clock       clock.Clock
 
 	mu       sync.Mutex
 	notified map[string]time.Time
@@ -144,11 +160,16 @@ func NewNotificationManager(projectPath string, cfg config.NotificationsConfig,
 		logger:      logger,
 		notifier:    notifier,
 		webhook:     webhook,
-		now:         time.Now,
+		clock:       clock.Real,
 		notified:    make(map[string]time.Time),
 	}
 }
 
+// SetClock sets the clock used for notification delays.
+func (m *NotificationManager) SetClock(c clock.Clock) {
+	m.clock = clock.OrReal(c)
+}
+
 // WithWebhook sets a custom webhook notifier (for testing).
 func (m *NotificationManager) WithWebhook(w WebhookNotifier) *NotificationManager {
 	m.webhook = w
@@ -205,7 +226,7 @@ func (m *NotificationManager) Check(ctx context.Context) error {
 	}
 	defer dbConn.Close()
 
-	now := m.now().UTC()
+	now := m.clock.Now().UTC()
 	delay := time.Duration(m.cfg.DesktopDelaySecs) * time.Second
 
 	pending, err := dbConn.ListPendingRequests(m.projectPath)
@@ -247,14 +268,7 @@ func (m *NotificationManager) Check(ctx context.Context) error {
 			continue
 		}
 
-		cmd := req.Command.DisplayRedacted
-		if cmd == "" {
-			cmd = req.Command.Raw
-		}
-		cmd = strings.TrimSpace(cmd)
-		if len(cmd) > 140 {
-			cmd = cmd[:140] + "…"
-		}
+		cmd := notifyCommand(req)
 
 		// Send desktop notification (CRITICAL only)
 		if hasDesktop && req.RiskTier == db.RiskTierCritical {
@@ -303,19 +317,10 @@ func (m *NotificationManager) SendWebhook(ctx context.Context, event WebhookEven
 		return nil
 	}
 
-	cmd := req.Command.DisplayRedacted
-	if cmd == "" {
-		cmd = req.Command.Raw
-	}
-	cmd = strings.TrimSpace(cmd)
-	if len(cmd) > 140 {
-		cmd = cmd[:140] + "…"
-	}
-
 	payload := WebhookPayload{
 		Event:     event,
 		RequestID: req.ID,
-		Command:   cmd,
+		Command:   notifyCommand(req),
 		Tier:      string(req.RiskTier),
 		Requestor: req.RequestorAgent,
 		Timestamp: time.Now().UTC().Format(time.RFC3339),
@@ -339,6 +344,36 @@ func (m *NotificationManager) SendWebhook(ctx context.Context, event WebhookEven
 	return nil
 }
 
+// Remind sends the nth review reminder for a request still pending: a
+// desktop notification when those are enabled, and the webhook when one
+// is configured.
+func (m *NotificationManager) Remind(ctx context.Context, req *db.Request, n int) {
+	if m == nil {
+		return
+	}
+	if m.cfg.DesktopEnabled {
+		title := fmt.Sprintf("SLB: %s request still pending", strings.ToUpper(string(req.RiskTier)))
+		message := fmt.Sprintf("%s\nRequestor: %s\nID: %s (reminder %d)", notifyCommand(req), req.RequestorAgent, shortID(req.ID), n)
+		if err := m.notifier.Notify(title, message); err != nil {
+			m.logger.Warn("desktop notification failed", "error", err)
+		}
+	}
+	_ = m.SendWebhook(ctx, WebhookEventReviewReminder, req)
+}
+
+// notifyCommand is a request's command as notifications show it.
+func notifyCommand(req *db.Request) string {
+	cmd := req.Command.DisplayRedacted
+	if cmd == "" {
+		cmd = req.Command.Raw
+	}
+	cmd = strings.TrimSpace(cmd)
+	if len(cmd) > 140 {
+		cmd = cmd[:140] + "…"
+	}
+	return cmd
+}
+
 func (m *NotificationManager) markOnce(key string, at time.Time) bool {
 	m.mu.Lock()
 	defer m.mu.Unlock()
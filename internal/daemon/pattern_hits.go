@@ -0,0 +1,130 @@
+package daemon
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// DefaultPatternHitFlushInterval is how often hook-query pattern hits are
+// written. Hook queries are the hottest daemon path: writing every hit
+// would keep state.db changing constantly, and the state watcher's debounce
+// would never settle long enough to invalidate the read model.
+const DefaultPatternHitFlushInterval = time.Minute
+
+// PatternHitCounter aggregates pattern hits from hook queries in memory
+// and writes them in one transaction per interval.
+type PatternHitCounter struct {
+	db       *db.DB
+	interval time.Duration
+
+	mu      sync.Mutex
+	pending map[string]*db.PatternHit
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// NewPatternHitCounter creates a counter that flushes to database every
+// interval (DefaultPatternHitFlushInterval when zero). Call Start to flush
+// in the background and Stop to flush what remains.
+func NewPatternHitCounter(database *db.DB, interval time.Duration) *PatternHitCounter {
+	if interval <= 0 {
+		interval = DefaultPatternHitFlushInterval
+	}
+	return &PatternHitCounter{
+		db:       database,
+		interval: interval,
+		pending:  make(map[string]*db.PatternHit),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Record counts the patterns that classified a command. Pass the pattern
+// engine's result, before overrides change its tier.
+func (c *PatternHitCounter) Record(res *core.MatchResult) {
+	hits := core.PatternHits(res, c.db.Now())
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mergeLocked(hits)
+}
+
+func (c *PatternHitCounter) mergeLocked(hits []*db.PatternHit) {
+	for _, h := range hits {
+		key := string(h.Tier) + "\x00" + h.Pattern
+		prev, ok := c.pending[key]
+		if !ok {
+			c.pending[key] = h
+			continue
+		}
+		prev.Hits += h.Hits
+		if h.FirstMatchedAt.Before(prev.FirstMatchedAt) {
+			prev.FirstMatchedAt = h.FirstMatchedAt
+		}
+		if h.LastMatchedAt.After(prev.LastMatchedAt) {
+			prev.LastMatchedAt = h.LastMatchedAt
+		}
+	}
+}
+
+// Flush writes the aggregated hits. On failure they are kept for the next
+// flush.
+func (c *PatternHitCounter) Flush() error {
+	c.mu.Lock()
+	pending := c.pending
+	c.pending = make(map[string]*db.PatternHit)
+	c.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+	hits := make([]*db.PatternHit, 0, len(pending))
+	for _, h := range pending {
+		hits = append(hits, h)
+	}
+	if err := c.db.RecordPatternHits(hits); err != nil {
+		c.mu.Lock()
+		c.mergeLocked(hits)
+		c.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Start flushes every interval until ctx is done or Stop is called.
+func (c *PatternHitCounter) Start(ctx context.Context) {
+	c.startOnce.Do(func() {
+		go func() {
+			defer close(c.done)
+			ticker := time.NewTicker(c.interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-c.stop:
+					return
+				case <-ticker.C:
+					_ = c.Flush()
+				}
+			}
+		}()
+	})
+}
+
+// Stop ends the background loop and flushes what remains.
+func (c *PatternHitCounter) Stop() error {
+	c.stopOnce.Do(func() {
+		close(c.stop)
+	})
+	// A counter that was never started has no loop to wait for.
+	c.startOnce.Do(func() { close(c.done) })
+	<-c.done
+	return c.Flush()
+}
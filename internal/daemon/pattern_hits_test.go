@@ -0,0 +1,92 @@
+package daemon
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestPatternHitCounter(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	counter := NewPatternHitCounter(database, time.Hour)
+
+	dangerous := &core.MatchResult{Tier: db.RiskTierDangerous, MatchedPattern: `^rm\s+-rf`}
+	counter.Record(dangerous)
+	counter.Record(dangerous)
+	counter.Record(&core.MatchResult{Tier: db.RiskTierCritical, MatchedPattern: `^rm\s+-rf`})
+	counter.Record(&core.MatchResult{})
+	counter.Record(nil)
+
+	if err := counter.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	counter.Record(dangerous)
+	if err := counter.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if err := counter.Flush(); err != nil {
+		t.Fatalf("empty Flush: %v", err)
+	}
+
+	hits, err := database.ListPatternHits()
+	if err != nil {
+		t.Fatalf("ListPatternHits: %v", err)
+	}
+	if len(hits) != 2 || hits[0].Tier != db.RiskTierDangerous || hits[0].Hits != 3 || hits[1].Hits != 1 {
+		t.Fatalf("unexpected hits: %+v", hits)
+	}
+
+	// Stop without Start still flushes.
+	counter.Record(dangerous)
+	if err := counter.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if hits, _ := database.ListPatternHits(); hits[0].Hits != 4 {
+		t.Errorf("expected Stop to flush, got %+v", hits[0])
+	}
+}
+
+func TestPatternHitCounter_FlushesOnInterval(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	counter := NewPatternHitCounter(database, 10*time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	counter.Start(ctx)
+	defer counter.Stop()
+
+	counter.Record(&core.MatchResult{Tier: db.RiskTierCaution, MatchedPattern: `^rm\s`})
+	ok := testutil.WaitForCondition(func() bool {
+		hits, err := database.ListPatternHits()
+		return err == nil && len(hits) == 1
+	}, 5*time.Millisecond, 2*time.Second)
+	if !ok {
+		t.Fatal("pattern hits were not flushed on the interval")
+	}
+}
+
+func TestPatternHitCounter_KeepsHitsWhenFlushFails(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	counter := NewPatternHitCounter(database, time.Hour)
+	counter.Record(&core.MatchResult{Tier: db.RiskTierCaution, MatchedPattern: `^rm\s`})
+
+	if _, err := database.Exec(`ALTER TABLE pattern_hits RENAME TO pattern_hits_gone`); err != nil {
+		t.Fatal(err)
+	}
+	if err := counter.Flush(); err == nil {
+		t.Fatal("expected Flush to fail without the table")
+	}
+	if _, err := database.Exec(`ALTER TABLE pattern_hits_gone RENAME TO pattern_hits`); err != nil {
+		t.Fatal(err)
+	}
+	counter.Record(&core.MatchResult{Tier: db.RiskTierCaution, MatchedPattern: `^rm\s`})
+	if err := counter.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if hits, _ := database.ListPatternHits(); len(hits) != 1 || hits[0].Hits != 2 {
+		t.Errorf("expected the failed batch to be kept, got %+v", hits)
+	}
+}
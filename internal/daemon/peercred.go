@@ -0,0 +1,158 @@
+// Package daemon provides Unix socket peer-credential authorization.
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+)
+
+// errPeerCredUnsupported is returned where the platform or connection type
+// cannot report peer credentials.
+var errPeerCredUnsupported = errors.New("peer credentials not supported")
+
+// PeerCred identifies the process on the other end of a Unix socket.
+type PeerCred struct {
+	UID int `json:"uid"`
+	GID int `json:"gid"`
+	PID int `json:"pid"`
+}
+
+// String formats the credentials for logs.
+func (p *PeerCred) String() string {
+	if p == nil {
+		return "unknown"
+	}
+	return fmt.Sprintf("uid=%d gid=%d pid=%d", p.UID, p.GID, p.PID)
+}
+
+// PeerPolicy decides which local users may talk to the daemon socket. The
+// daemon's own user is always allowed; other users must be listed by user
+// or by primary group, and get only the policy's access level.
+type PeerPolicy struct {
+	ownUID     int
+	uids       map[int]bool
+	gids       map[int]bool
+	othersCaps capSet
+}
+
+// NewPeerPolicy builds a policy from user and group names or numeric ids,
+// as configured in daemon.allowed_peer_users and daemon.allowed_peer_groups.
+// Unknown names are an error, so a typo cannot silently lock users out.
+// othersAccess ("read" or "write") is daemon.allowed_peer_access, the most
+// the listed users may do.
+func NewPeerPolicy(users, groups []string, othersAccess string) (*PeerPolicy, error) {
+	othersCaps, err := capsForAccess(othersAccess)
+	if err != nil {
+		return nil, fmt.Errorf("daemon.allowed_peer_access: %w", err)
+	}
+	p := &PeerPolicy{ownUID: os.Getuid(), uids: map[int]bool{}, gids: map[int]bool{}, othersCaps: othersCaps}
+	for _, name := range users {
+		id, err := lookupID(strings.TrimSpace(name), func(n string) (string, error) {
+			u, err := user.Lookup(n)
+			if err != nil {
+				return "", err
+			}
+			return u.Uid, nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("daemon.allowed_peer_users: %w", err)
+		}
+		p.uids[id] = true
+	}
+	for _, name := range groups {
+		id, err := lookupID(strings.TrimSpace(name), func(n string) (string, error) {
+			g, err := user.LookupGroup(n)
+			if err != nil {
+				return "", err
+			}
+			return g.Gid, nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("daemon.allowed_peer_groups: %w", err)
+		}
+		p.gids[id] = true
+	}
+	return p, nil
+}
+
+// lookupID returns name as a number, or resolves it with lookup.
+func lookupID(name string, lookup func(string) (string, error)) (int, error) {
+	if id, err := strconv.Atoi(name); err == nil {
+		return id, nil
+	}
+	raw, err := lookup(name)
+	if err != nil {
+		return 0, err
+	}
+	id, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("%s has non-numeric id %q", name, raw)
+	}
+	return id, nil
+}
+
+// AllowsOthers reports whether users besides the daemon's own are allowed,
+// in which case the socket must be reachable by them.
+func (p *PeerPolicy) AllowsOthers() bool {
+	return p != nil && (len(p.uids) > 0 || len(p.gids) > 0)
+}
+
+// Allows reports whether a peer may use the daemon.
+func (p *PeerPolicy) Allows(peer *PeerCred) bool {
+	if p == nil {
+		return true
+	}
+	if peer == nil {
+		return false
+	}
+	return peer.UID == p.ownUID || p.uids[peer.UID] || p.gids[peer.GID]
+}
+
+// SetPeerPolicy restricts Unix socket connections to peers the policy
+// allows. Connections whose credentials cannot be read (platforms without
+// SO_PEERCRED) are left to the socket's file permissions while it is
+// owner-only, and rejected once the policy opens it to other users.
+func (s *IPCServer) SetPeerPolicy(p *PeerPolicy) {
+	s.peerPolicy = p
+}
+
+// PeerCredSupported reports whether this platform can read Unix socket
+// peer credentials.
+func PeerCredSupported() bool {
+	return peerCredSupported
+}
+
+// admitPeer reads the peer credentials of a new connection and checks them
+// against the policy. It returns the credentials (nil when unavailable) and
+// false if the connection must be dropped.
+func (s *IPCServer) admitPeer(conn net.Conn) (*PeerCred, bool) {
+	peer, err := peerCredentials(conn)
+	if err != nil {
+		if s.peerPolicy.AllowsOthers() {
+			// The socket is open to other users, so its permissions no
+			// longer tell who is calling.
+			s.logger.Warn("connection rejected: cannot read peer credentials", "error", err)
+			return nil, false
+		}
+		return nil, true
+	}
+	if !s.peerPolicy.Allows(peer) {
+		s.logger.Warn("connection rejected: peer not allowed", "peer", peer.String())
+		return peer, false
+	}
+	s.logger.Debug("connection accepted", "peer", peer.String())
+	return peer, true
+}
+
+// peerOf returns the credentials recorded for a connection, if any.
+func peerOf(conn net.Conn) *PeerCred {
+	if lc, ok := conn.(*lockedConn); ok {
+		return lc.peer
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+//go:build !linux
+
+package daemon
+
+import "net"
+
+const peerCredSupported = false
+
+// peerCredentials is only implemented on Linux (SO_PEERCRED).
+func peerCredentials(conn net.Conn) (*PeerCred, error) {
+	return nil, errPeerCredUnsupported
+}
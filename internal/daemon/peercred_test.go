@@ -0,0 +1,127 @@
+package daemon
+
+import (
+	"context"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+func TestNewPeerPolicy(t *testing.T) {
+	p, err := NewPeerPolicy([]string{"1234", "root"}, []string{"42"}, "")
+	if err != nil {
+		t.Fatalf("NewPeerPolicy: %v", err)
+	}
+	if !p.AllowsOthers() {
+		t.Fatal("expected a policy with extra users to allow others")
+	}
+
+	tests := []struct {
+		name string
+		peer *PeerCred
+		want bool
+	}{
+		{"own user", &PeerCred{UID: os.Getuid(), GID: -1}, true},
+		{"listed uid", &PeerCred{UID: 1234, GID: -1}, true},
+		{"listed user name", &PeerCred{UID: 0, GID: -1}, true},
+		{"listed group", &PeerCred{UID: 999999, GID: 42}, true},
+		{"stranger", &PeerCred{UID: 999999, GID: 999999}, os.Getuid() == 999999},
+		{"unknown peer", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.Allows(tt.peer); got != tt.want {
+				t.Fatalf("Allows(%s) = %v, want %v", tt.peer, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := NewPeerPolicy([]string{"no-such-user-slb"}, nil, ""); err == nil {
+		t.Fatal("expected an error for an unknown user")
+	}
+	if _, err := NewPeerPolicy(nil, []string{"no-such-group-slb"}, ""); err == nil {
+		t.Fatal("expected an error for an unknown group")
+	}
+	if _, err := NewPeerPolicy(nil, nil, "admin"); err == nil {
+		t.Fatal("expected an error for an unknown access level")
+	}
+
+	own, err := NewPeerPolicy(nil, nil, "")
+	if err != nil {
+		t.Fatalf("NewPeerPolicy: %v", err)
+	}
+	if own.AllowsOthers() {
+		t.Fatal("expected an empty policy to allow only the daemon's user")
+	}
+	var none *PeerPolicy
+	if !none.Allows(nil) {
+		t.Fatal("expected a nil policy to allow everyone")
+	}
+}
+
+func TestIPCServer_PeerPolicy(t *testing.T) {
+	if !PeerCredSupported() {
+		t.Skip("peer credentials not supported on this platform")
+	}
+
+	ping := func(policy *PeerPolicy) error {
+		socketPath := filepath.Join(shortSocketDir(t), "test.sock")
+		srv, err := NewIPCServer(socketPath, nil)
+		if err != nil {
+			t.Fatalf("NewIPCServer: %v", err)
+		}
+		srv.SetPeerPolicy(policy)
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		go srv.Start(ctx)
+		defer srv.Stop()
+
+		c := NewIPCClient(socketPath)
+		defer c.Close()
+		return c.Ping(ctx)
+	}
+
+	// A policy owned by another user that does not list this one.
+	if err := ping(&PeerPolicy{ownUID: -1, uids: map[int]bool{}, gids: map[int]bool{}}); err == nil {
+		t.Fatal("expected a peer outside the policy to be rejected")
+	}
+
+	listed, err := NewPeerPolicy([]string{strconv.Itoa(os.Getuid())}, nil, "")
+	if err != nil {
+		t.Fatalf("NewPeerPolicy: %v", err)
+	}
+	listed.ownUID = -1
+	if err := ping(listed); err != nil {
+		t.Fatalf("expected a listed peer to be accepted: %v", err)
+	}
+}
+
+func TestIPCServer_AdmitPeerWithoutCredentials(t *testing.T) {
+	srv, err := NewIPCServer(filepath.Join(shortSocketDir(t), "test.sock"), log.New(io.Discard))
+	if err != nil {
+		t.Fatalf("NewIPCServer: %v", err)
+	}
+	// A pipe has no peer credentials, like a socket on a platform without
+	// SO_PEERCRED.
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	if _, ok := srv.admitPeer(server); !ok {
+		t.Fatal("expected an owner-only socket to admit a peer without credentials")
+	}
+	srv.SetPeerPolicy(&PeerPolicy{ownUID: os.Getuid(), uids: map[int]bool{}, gids: map[int]bool{}})
+	if _, ok := srv.admitPeer(server); !ok {
+		t.Fatal("expected a policy that lists no one else to admit a peer without credentials")
+	}
+	srv.SetPeerPolicy(&PeerPolicy{ownUID: os.Getuid(), uids: map[int]bool{12345: true}, gids: map[int]bool{}})
+	if _, ok := srv.admitPeer(server); ok {
+		t.Fatal("expected a socket open to other users to reject a peer without credentials")
+	}
+}
@@ -0,0 +1,255 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/clock"
+	"github.com/Dicklesworthstone/slb/internal/config"
+)
+
+// ErrCodeRateLimited is returned for a request over its connection's rate
+// limit or its session's quota, and for a subscription over the
+// connection's cap.
+const ErrCodeRateLimited = -32029
+
+// IPCLimits protect the daemon from a misbehaving client. A zero field
+// turns its limit off, except Burst, which defaults to RequestsPerSecond.
+type IPCLimits struct {
+	// RequestsPerSecond is the rate each connection's requests refill at,
+	// and Burst how many it may send at once (0 means RequestsPerSecond).
+	RequestsPerSecond int `json:"requests_per_second"`
+	Burst             int `json:"burst"`
+	// MaxSubscriptions caps the subscribe and watch_requests streams one
+	// connection holds.
+	MaxSubscriptions int `json:"max_subscriptions"`
+	// SessionRequestsPerMinute caps the requests naming one session_id,
+	// across all connections, in each minute; a client cannot get around
+	// it by opening more connections.
+	SessionRequestsPerMinute int `json:"session_requests_per_minute"`
+}
+
+// IPCLimitsFromConfig returns the limits in the daemon section of cfg.
+func IPCLimitsFromConfig(cfg config.Config) IPCLimits {
+	return IPCLimits{
+		RequestsPerSecond:        cfg.Daemon.ClientRequestsPerSecond,
+		Burst:                    cfg.Daemon.ClientRequestBurst,
+		MaxSubscriptions:         cfg.Daemon.ClientMaxSubscriptions,
+		SessionRequestsPerMinute: cfg.Daemon.SessionRequestsPerMinute,
+	}
+}
+
+// Quota limit names, as reported in QuotaViolation.Limit.
+const (
+	QuotaRate          = "rate"
+	QuotaSubscriptions = "subscriptions"
+	QuotaSession       = "session"
+)
+
+// QuotaViolation describes a refused request.
+type QuotaViolation struct {
+	Limit   string    `json:"limit"`
+	Method  string    `json:"method"`
+	Client  int64     `json:"client,omitempty"`
+	Session string    `json:"session,omitempty"`
+	At      time.Time `json:"at"`
+}
+
+// QuotaStats reports the daemon's client limits and how often they were
+// hit.
+type QuotaStats struct {
+	Limits               IPCLimits       `json:"limits"`
+	RateLimited          int64           `json:"rate_limited"`
+	SubscriptionsRefused int64           `json:"subscriptions_refused"`
+	SessionQuotaExceeded int64           `json:"session_quota_exceeded"`
+	LastViolation        *QuotaViolation `json:"last_violation,omitempty"`
+}
+
+// quotaGuard enforces IPCLimits. Like chaosInjector, it is shared by the
+// daemon's Unix socket and TCP servers, so session quotas span both.
+type quotaGuard struct {
+	limits IPCLimits
+	clock  clock.Clock
+
+	mu       sync.Mutex
+	window   time.Time // start of the current session quota minute
+	sessions map[string]int
+	last     *QuotaViolation
+
+	rateLimited          atomic.Int64
+	subscriptionsRefused atomic.Int64
+	sessionQuotaExceeded atomic.Int64
+}
+
+// tokenBucket rate-limits one connection. Only the connection's read loop
+// touches it.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// allow takes a token if one is left, after refilling at rate per second
+// up to burst. A burst below 1 means one second's worth of requests.
+func (b *tokenBucket) allow(now time.Time, rate, burst int) bool {
+	if burst < 1 {
+		burst = max(rate, 1)
+	}
+	if b.last.IsZero() {
+		b.tokens = float64(burst)
+	} else if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = min(float64(burst), b.tokens+elapsed*float64(rate))
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// SetLimits applies per-connection and per-session limits to the server's
+// clients.
+func (s *IPCServer) SetLimits(l IPCLimits) {
+	s.quotas = &quotaGuard{limits: l, clock: clock.OrReal(nil)}
+}
+
+// checkQuota refuses req when its connection is over its rate limit or the
+// session it names is over its quota. status is never refused, so the
+// counters stay readable.
+func (s *IPCServer) checkQuota(conn net.Conn, req RPCRequest) *RPCResponse {
+	g := s.quotas
+	if g == nil || req.Method == "status" {
+		return nil
+	}
+	now := g.clock.Now()
+	lc, _ := conn.(*lockedConn)
+
+	if g.limits.RequestsPerSecond > 0 && lc != nil && lc.client != nil &&
+		!lc.client.bucket.allow(now, g.limits.RequestsPerSecond, g.limits.Burst) {
+		g.rateLimited.Add(1)
+		s.quotaViolation(lc.client, QuotaViolation{Limit: QuotaRate, Method: req.Method, At: now})
+		return &RPCResponse{
+			Error: &Error{Code: ErrCodeRateLimited, Message: fmt.Sprintf("rate limited: more than %d requests per second", g.limits.RequestsPerSecond)},
+			ID:    req.ID,
+		}
+	}
+
+	if g.limits.SessionRequestsPerMinute > 0 {
+		if session := requestSession(req); session != "" && !g.countSession(session, now) {
+			g.sessionQuotaExceeded.Add(1)
+			var client *clientConn
+			if lc != nil {
+				client = lc.client
+			}
+			s.quotaViolation(client, QuotaViolation{Limit: QuotaSession, Method: req.Method, Session: session, At: now})
+			return &RPCResponse{
+				Error: &Error{Code: ErrCodeRateLimited, Message: fmt.Sprintf("session %s is over its quota of %d requests per minute", session, g.limits.SessionRequestsPerMinute)},
+				ID:    req.ID,
+			}
+		}
+	}
+	return nil
+}
+
+// checkSubscriptionQuota refuses a new subscription on a connection that
+// already holds the maximum.
+func (s *IPCServer) checkSubscriptionQuota(conn net.Conn, req RPCRequest) *RPCResponse {
+	g := s.quotas
+	lc, ok := conn.(*lockedConn)
+	if g == nil || g.limits.MaxSubscriptions <= 0 || !ok || lc.client == nil {
+		return nil
+	}
+	held := 0
+	s.subscribersMu.RLock()
+	for _, sub := range s.subscribers {
+		if sub.clientID == lc.client.id {
+			held++
+		}
+	}
+	s.subscribersMu.RUnlock()
+	if held < g.limits.MaxSubscriptions {
+		return nil
+	}
+	g.subscriptionsRefused.Add(1)
+	s.quotaViolation(lc.client, QuotaViolation{Limit: QuotaSubscriptions, Method: req.Method, At: g.clock.Now()})
+	return &RPCResponse{
+		Error: &Error{Code: ErrCodeRateLimited, Message: fmt.Sprintf("too many subscriptions: a connection may hold %d", g.limits.MaxSubscriptions)},
+		ID:    req.ID,
+	}
+}
+
+// countSession counts a request for session in the current minute and
+// reports whether it is within the quota.
+func (g *quotaGuard) countSession(session string, now time.Time) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.sessions == nil || now.Sub(g.window) >= time.Minute || now.Before(g.window) {
+		g.window = now
+		g.sessions = make(map[string]int)
+	}
+	g.sessions[session]++
+	return g.sessions[session] <= g.limits.SessionRequestsPerMinute
+}
+
+// quotaViolation records v and logs it. A client's first violation is a
+// warning; repeats are debug output, so a flooding client cannot flood the
+// log too.
+func (s *IPCServer) quotaViolation(client *clientConn, v QuotaViolation) {
+	first := true
+	if client != nil {
+		v.Client = client.id
+		first = client.limited.Add(1) == 1
+	}
+	g := s.quotas
+	g.mu.Lock()
+	g.last = &v
+	g.mu.Unlock()
+
+	args := []any{"limit", v.Limit, "method", v.Method, "client", v.Client}
+	if v.Session != "" {
+		args = append(args, "session", v.Session)
+	}
+	if first {
+		s.logger.Warn("client over quota", args...)
+	} else {
+		s.logger.Debug("client over quota", args...)
+	}
+}
+
+// stats reports the limits and violation counters. A nil guard has none.
+func (g *quotaGuard) stats() *QuotaStats {
+	if g == nil {
+		return nil
+	}
+	st := &QuotaStats{
+		Limits:               g.limits,
+		RateLimited:          g.rateLimited.Load(),
+		SubscriptionsRefused: g.subscriptionsRefused.Load(),
+		SessionQuotaExceeded: g.sessionQuotaExceeded.Load(),
+	}
+	g.mu.Lock()
+	if g.last != nil {
+		last := *g.last
+		st.LastViolation = &last
+	}
+	g.mu.Unlock()
+	return st
+}
+
+// requestSession returns the session_id a request's params name, if any.
+func requestSession(req RPCRequest) string {
+	if len(req.Params) == 0 {
+		return ""
+	}
+	var params struct {
+		SessionID string `json:"session_id"`
+	}
+	if json.Unmarshal(req.Params, &params) != nil {
+		return ""
+	}
+	return params.SessionID
+}
@@ -0,0 +1,179 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/clock"
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/charmbracelet/log"
+)
+
+func TestIPCLimitsFromConfig(t *testing.T) {
+	got := IPCLimitsFromConfig(config.DefaultConfig())
+	want := IPCLimits{RequestsPerSecond: 50, Burst: 100, MaxSubscriptions: 16, SessionRequestsPerMinute: 600}
+	if got != want {
+		t.Fatalf("IPCLimitsFromConfig(defaults) = %+v, want %+v", got, want)
+	}
+}
+
+func TestTokenBucket_ZeroBurstUsesRate(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	var b tokenBucket
+	for i := 0; i < 5; i++ {
+		if !b.allow(now, 5, 0) {
+			t.Fatalf("request %d of the first second refused with burst 0 and rate 5", i+1)
+		}
+	}
+	if b.allow(now, 5, 0) {
+		t.Fatal("a sixth request in the same instant should be refused")
+	}
+	if !b.allow(now.Add(200*time.Millisecond), 5, 0) {
+		t.Fatal("a token should refill after 200ms at rate 5")
+	}
+}
+
+func TestIPCServer_Quotas(t *testing.T) {
+	var now atomic.Int64
+	now.Store(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC).UnixNano())
+	advance := func(d time.Duration) { now.Add(int64(d)) }
+
+	socketPath := filepath.Join(shortSocketDir(t), "q.sock")
+	srv, err := NewIPCServer(socketPath, log.New(io.Discard))
+	if err != nil {
+		t.Fatalf("NewIPCServer: %v", err)
+	}
+	srv.SetLimits(IPCLimits{RequestsPerSecond: 1, Burst: 2, MaxSubscriptions: 1, SessionRequestsPerMinute: 2})
+	srv.quotas.clock = clock.Func(func() time.Time { return time.Unix(0, now.Load()) })
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+		_ = srv.Stop()
+	})
+	go func() { _ = srv.Start(ctx) }()
+
+	type client struct {
+		conn net.Conn
+		r    *bufio.Reader
+	}
+	dial := func(t *testing.T) *client {
+		t.Helper()
+		conn, err := net.Dial("unix", socketPath)
+		if err != nil {
+			t.Fatalf("dial: %v", err)
+		}
+		t.Cleanup(func() { _ = conn.Close() })
+		return &client{conn: conn, r: bufio.NewReader(conn)}
+	}
+	call := func(t *testing.T, c *client, method string, params any) *RPCResponse {
+		t.Helper()
+		req := RPCRequest{Method: method, ID: 1}
+		if params != nil {
+			req.Params, _ = json.Marshal(params)
+		}
+		data, _ := json.Marshal(req)
+		if _, err := c.conn.Write(append(data, '\n')); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		_ = c.conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		line, err := c.r.ReadBytes('\n')
+		if err != nil {
+			t.Fatalf("read %s: %v", method, err)
+		}
+		var resp RPCResponse
+		if err := json.Unmarshal(line, &resp); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		return &resp
+	}
+	limited := func(resp *RPCResponse) bool {
+		return resp.Error != nil && resp.Error.Code == ErrCodeRateLimited
+	}
+
+	t.Run("rate", func(t *testing.T) {
+		c := dial(t)
+		for i := 0; i < 2; i++ {
+			if resp := call(t, c, "ping", nil); resp.Error != nil {
+				t.Fatalf("ping %d within burst: %+v", i, resp.Error)
+			}
+		}
+		if resp := call(t, c, "ping", nil); !limited(resp) {
+			t.Fatalf("third ping should be rate limited, got %+v", resp)
+		}
+		if resp := call(t, c, "status", nil); resp.Error != nil {
+			t.Fatalf("status must not be rate limited: %+v", resp.Error)
+		}
+		if clients := srv.Clients(); len(clients) != 1 || clients[0].Limited != 1 {
+			t.Fatalf("clients = %+v, want one with a refused request", clients)
+		}
+		advance(time.Second)
+		if resp := call(t, c, "ping", nil); resp.Error != nil {
+			t.Fatalf("ping after refill: %+v", resp.Error)
+		}
+		// Another connection has its own bucket.
+		if resp := call(t, dial(t), "ping", nil); resp.Error != nil {
+			t.Fatalf("ping on a new connection: %+v", resp.Error)
+		}
+	})
+
+	t.Run("session", func(t *testing.T) {
+		advance(time.Minute)
+		params := HeartbeatParams{SessionID: "sess-1"}
+		// Spread over connections so only the session quota applies.
+		for i := 0; i < 2; i++ {
+			if resp := call(t, dial(t), "heartbeat", params); limited(resp) {
+				t.Fatalf("heartbeat %d within quota was limited", i)
+			}
+		}
+		if resp := call(t, dial(t), "heartbeat", params); !limited(resp) {
+			t.Fatalf("third heartbeat for the session should be over quota, got %+v", resp)
+		}
+		if resp := call(t, dial(t), "heartbeat", HeartbeatParams{SessionID: "sess-2"}); limited(resp) {
+			t.Fatal("another session should have its own quota")
+		}
+		advance(time.Minute)
+		if resp := call(t, dial(t), "heartbeat", params); limited(resp) {
+			t.Fatal("quota should reset the next minute")
+		}
+	})
+
+	t.Run("subscriptions", func(t *testing.T) {
+		advance(time.Minute)
+		c := dial(t)
+		if resp := call(t, c, "subscribe", nil); resp.Error != nil {
+			t.Fatalf("first subscription: %+v", resp.Error)
+		}
+		if resp := call(t, c, "subscribe", nil); !limited(resp) {
+			t.Fatalf("second subscription on the connection should be refused, got %+v", resp)
+		}
+		if resp := call(t, dial(t), "subscribe", nil); resp.Error != nil {
+			t.Fatalf("subscription on another connection: %+v", resp.Error)
+		}
+	})
+
+	st := srv.quotas.stats()
+	if st.RateLimited != 1 || st.SessionQuotaExceeded != 1 || st.SubscriptionsRefused != 1 {
+		t.Errorf("stats = %+v", st)
+	}
+	if st.LastViolation == nil || st.LastViolation.Limit != QuotaSubscriptions || st.LastViolation.Method != "subscribe" {
+		t.Errorf("last violation = %+v", st.LastViolation)
+	}
+
+	// Status surfaces the counters to clients.
+	statusClient := NewIPCClient(socketPath)
+	defer statusClient.Close()
+	info, err := statusClient.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if info.Quotas == nil || info.Quotas.Limits.MaxSubscriptions != 1 || info.Quotas.RateLimited != 1 {
+		t.Errorf("status quotas = %+v", info.Quotas)
+	}
+}
@@ -0,0 +1,387 @@
+// Package daemon provides an in-memory read model of project state.
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/clock"
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/charmbracelet/log"
+)
+
+// readModelMaxAge bounds how long a snapshot is served without an
+// invalidation, covering writes the watcher cannot see (e.g. another host
+// on a shared filesystem).
+const readModelMaxAge = 30 * time.Second
+
+// recentApprovalWindow is how long an approved request lets the same
+// session run its command without a new request.
+const recentApprovalWindow = time.Hour
+
+// recentApprovalLimit caps the approvals loaded per status.
+const recentApprovalLimit = 500
+
+// PendingRequest is a pending request with its review progress.
+type PendingRequest struct {
+	Request       *db.Request `json:"request"`
+	Approvals     int         `json:"approvals"`
+	AwaitingHuman bool        `json:"awaiting_human,omitempty"`
+}
+
+// ReadModelSnapshot is the cached view of a project's pending requests and
+// active sessions.
+type ReadModelSnapshot struct {
+	ProjectPath string           `json:"project_path"`
+	Pending     []PendingRequest `json:"pending"`
+	Sessions    []*db.Session    `json:"sessions"`
+	LoadedAt    time.Time        `json:"loaded_at"`
+
+	approvals map[approvalKey]recentApproval
+	// executions indexes executed requests, which ApprovalReuse may let a
+	// retry of the same command reuse.
+	executions map[approvalKey]recentApproval
+}
+
+type approvalKey struct {
+	sessionID string
+	command   string
+}
+
+type recentApproval struct {
+	requestID string
+	tier      db.RiskTier
+	// at is when the request was created, or executed for executions.
+	at time.Time
+}
+
+// ApprovalReuse lets an executed request stand in for a new one when the
+// session that ran it retries the same command, so idempotent retries are
+// not blocked again.
+type ApprovalReuse struct {
+	// Window is how long after execution a retry is let through; zero
+	// disables reuse.
+	Window time.Duration
+	// Tiers are the risk tiers reuse applies to.
+	Tiers map[db.RiskTier]bool
+}
+
+// ApprovalReuseFromConfig maps the integrations.hook_reuse_* settings.
+func ApprovalReuseFromConfig(cfg config.Config) ApprovalReuse {
+	reuse := ApprovalReuse{
+		Window: time.Duration(cfg.Integrations.HookReuseWindowMins) * time.Minute,
+		Tiers:  make(map[db.RiskTier]bool),
+	}
+	for _, tier := range cfg.Integrations.HookReuseTiers {
+		reuse.Tiers[db.RiskTier(strings.ToLower(strings.TrimSpace(tier)))] = true
+	}
+	return reuse
+}
+
+// DefaultApprovalReuse is the reuse policy of the default config.
+func DefaultApprovalReuse() ApprovalReuse {
+	return ApprovalReuseFromConfig(config.DefaultConfig())
+}
+
+func (r ApprovalReuse) allows(e recentApproval, now time.Time) bool {
+	return r.Window > 0 && r.Tiers[e.tier] && now.Sub(e.at) <= r.Window
+}
+
+// ReadModelStats reports cache effectiveness for the status RPC.
+type ReadModelStats struct {
+	Hits          int64   `json:"hits"`
+	Misses        int64   `json:"misses"`
+	Invalidations int64   `json:"invalidations"`
+	Errors        int64   `json:"errors"`
+	HitRate       float64 `json:"hit_rate"`
+	Loaded        bool    `json:"loaded"`
+	AgeSeconds    float64 `json:"age_seconds,omitempty"`
+	PendingCount  int     `json:"pending_count"`
+	SessionCount  int     `json:"session_count"`
+}
+
+// ReadModel caches the project's pending requests, active sessions and
+// recent approvals so hook_query, status and TUI refreshes are answered from
+// memory instead of each opening SQLite. Writes invalidate it (state.db
+// changes seen by the Watcher, notify RPCs); the next read reloads.
+type ReadModel struct {
+	projectPath string
+	dbPath      string
+	logger      *log.Logger
+	maxAge      time.Duration
+	clock       clock.Clock
+	reuse       ApprovalReuse
+
+	// reloadMu serializes reloads so concurrent misses share one query.
+	reloadMu   sync.Mutex
+	snapshot   atomic.Pointer[ReadModelSnapshot]
+	generation atomic.Int64
+
+	hits          atomic.Int64
+	misses        atomic.Int64
+	invalidations atomic.Int64
+	errors        atomic.Int64
+
+	// onInvalidate are called after every invalidation.
+	onInvalidate []func()
+}
+
+// NewReadModel creates a read model for the project's .slb/state.db.
+func NewReadModel(projectPath string, logger *log.Logger) *ReadModel {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &ReadModel{
+		projectPath: filepath.Clean(projectPath),
+		dbPath:      filepath.Join(projectPath, ".slb", "state.db"),
+		logger:      logger,
+		maxAge:      readModelMaxAge,
+		clock:       clock.Real,
+		reuse:       DefaultApprovalReuse(),
+	}
+}
+
+// SetApprovalReuse sets when executed requests answer retries. It takes
+// effect from the next reload.
+func (m *ReadModel) SetApprovalReuse(r ApprovalReuse) {
+	m.reuse = r
+	m.Invalidate()
+}
+
+// SetClock sets the clock used for snapshot age and the approval window.
+func (m *ReadModel) SetClock(c clock.Clock) {
+	m.clock = clock.OrReal(c)
+}
+
+// ProjectPath returns the project the read model serves.
+func (m *ReadModel) ProjectPath() string {
+	return m.projectPath
+}
+
+// OnInvalidate registers fn to run after every invalidation, i.e. whenever
+// the project state may have changed. Register before the model is shared;
+// fn must not block.
+func (m *ReadModel) OnInvalidate(fn func()) {
+	m.onInvalidate = append(m.onInvalidate, fn)
+}
+
+// Invalidate drops the cached snapshot; the next read reloads it.
+func (m *ReadModel) Invalidate() {
+	m.generation.Add(1)
+	m.snapshot.Store(nil)
+	m.invalidations.Add(1)
+	for _, fn := range m.onInvalidate {
+		fn()
+	}
+}
+
+// Snapshot returns the cached snapshot, reloading it when it was
+// invalidated or is older than the max age.
+func (m *ReadModel) Snapshot() (*ReadModelSnapshot, error) {
+	if snap := m.fresh(); snap != nil {
+		m.hits.Add(1)
+		return snap, nil
+	}
+
+	m.reloadMu.Lock()
+	defer m.reloadMu.Unlock()
+
+	// Another caller may have reloaded while we waited.
+	if snap := m.fresh(); snap != nil {
+		m.hits.Add(1)
+		return snap, nil
+	}
+	m.misses.Add(1)
+
+	gen := m.generation.Load()
+	snap, err := loadReadModel(m.dbPath, m.projectPath, m.clock.Now(), m.reuse.Window)
+	if err != nil {
+		m.errors.Add(1)
+		return nil, err
+	}
+	// Only cache if no write happened during the load; otherwise the next
+	// read reloads again.
+	if m.generation.Load() == gen {
+		m.snapshot.Store(snap)
+	}
+	return snap, nil
+}
+
+func (m *ReadModel) fresh() *ReadModelSnapshot {
+	snap := m.snapshot.Load()
+	if snap == nil || m.clock.Now().Sub(snap.LoadedAt) > m.maxAge {
+		return nil
+	}
+	return snap
+}
+
+// ApprovedRequest returns the ID of a recent approved request by sessionID
+// for command, or of an executed one the reuse policy allows, if any.
+func (m *ReadModel) ApprovedRequest(sessionID, command string) (string, bool) {
+	snap, err := m.Snapshot()
+	if err != nil {
+		return "", false
+	}
+	return snap.approvedRequest(sessionID, command, m.clock.Now(), m.reuse)
+}
+
+func (s *ReadModelSnapshot) approvedRequest(sessionID, command string, now time.Time, reuse ApprovalReuse) (string, bool) {
+	key := approvalKey{sessionID: sessionID, command: command}
+	if a, ok := s.approvals[key]; ok && now.Sub(a.at) <= recentApprovalWindow {
+		return a.requestID, true
+	}
+	if e, ok := s.executions[key]; ok && reuse.allows(e, now) {
+		return e.requestID, true
+	}
+	return "", false
+}
+
+// Stats returns cache counters and the size of the current snapshot.
+func (m *ReadModel) Stats() ReadModelStats {
+	stats := ReadModelStats{
+		Hits:          m.hits.Load(),
+		Misses:        m.misses.Load(),
+		Invalidations: m.invalidations.Load(),
+		Errors:        m.errors.Load(),
+	}
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(total)
+	}
+	if snap := m.snapshot.Load(); snap != nil {
+		stats.Loaded = true
+		stats.AgeSeconds = m.clock.Now().Sub(snap.LoadedAt).Seconds()
+		stats.PendingCount = len(snap.Pending)
+		stats.SessionCount = len(snap.Sessions)
+	}
+	return stats
+}
+
+// Run invalidates the read model for every watcher event until ctx is done
+// or the channel closes.
+func (m *ReadModel) Run(ctx context.Context, events <-chan WatchEvent) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			m.logger.Debug("read model invalidated", "path", ev.Path)
+			m.Invalidate()
+		}
+	}
+}
+
+// loadReadModel reads a fresh snapshot from the project database.
+func loadReadModel(dbPath, projectPath string, now time.Time, reuseWindow time.Duration) (*ReadModelSnapshot, error) {
+	dbConn, err := db.OpenWithOptions(dbPath, db.OpenOptions{
+		CreateIfNotExists: false,
+		InitSchema:        false,
+		ReadOnly:          true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("opening state db: %w", err)
+	}
+	defer dbConn.Close()
+
+	sessions, err := dbConn.ListActiveSessions(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("listing sessions: %w", err)
+	}
+
+	reqs, err := dbConn.ListPendingRequests(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("listing pending requests: %w", err)
+	}
+	if err := dbConn.LoadRequestLabels(reqs); err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(reqs))
+	for i, r := range reqs {
+		ids[i] = r.ID
+	}
+	// Older databases may predate the escalation table; treat as none flagged.
+	awaitingHuman, _ := dbConn.AwaitingHumanRequestIDs(ids)
+
+	pending := make([]PendingRequest, 0, len(reqs))
+	for _, r := range reqs {
+		approvals, _, _ := dbConn.CountReviewsByDecision(r.ID)
+		pending = append(pending, PendingRequest{
+			Request:       r,
+			Approvals:     approvals,
+			AwaitingHuman: awaitingHuman[r.ID],
+		})
+	}
+
+	approvals, executions, err := recentApprovals(dbConn, projectPath, now, reuseWindow)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReadModelSnapshot{
+		ProjectPath: projectPath,
+		Pending:     pending,
+		Sessions:    sessions,
+		LoadedAt:    now,
+		approvals:   approvals,
+		executions:  executions,
+	}, nil
+}
+
+// recentApprovals indexes approved requests created in the last
+// recentApprovalWindow, and requests executed in the last reuseWindow, by
+// requesting session and command (raw and redacted forms).
+func recentApprovals(dbConn *db.DB, projectPath string, now time.Time, reuseWindow time.Duration) (approvals, executions map[approvalKey]recentApproval, err error) {
+	approvals, err = indexRequests(dbConn, projectPath, db.StatusApproved, now.Add(-recentApprovalWindow))
+	if err != nil {
+		return nil, nil, err
+	}
+	executions = make(map[approvalKey]recentApproval)
+	if reuseWindow > 0 {
+		// A request may wait for approval before it runs, so look back
+		// further by creation time and compare execution times later.
+		executions, err = indexRequests(dbConn, projectPath, db.StatusExecuted, now.Add(-reuseWindow-recentApprovalWindow))
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return approvals, executions, nil
+}
+
+// indexRequests indexes the project's requests in status created since the
+// given time, keeping the newest request for each session and command.
+func indexRequests(dbConn *db.DB, projectPath string, status db.RequestStatus, since time.Time) (map[approvalKey]recentApproval, error) {
+	index := make(map[approvalKey]recentApproval)
+	reqs, _, err := dbConn.ListRequestsPage(db.RequestPageQuery{
+		ProjectPath: projectPath,
+		Status:      status,
+		Since:       since,
+		Limit:       recentApprovalLimit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing recent approvals: %w", err)
+	}
+	for _, r := range reqs {
+		a := recentApproval{requestID: r.ID, tier: r.RiskTier, at: r.CreatedAt}
+		if status == db.StatusExecuted && r.Execution != nil && r.Execution.ExecutedAt != nil {
+			a.at = *r.Execution.ExecutedAt
+		}
+		for _, cmd := range []string{r.Command.Raw, r.Command.DisplayRedacted} {
+			if cmd == "" {
+				continue
+			}
+			key := approvalKey{sessionID: r.RequestorSessionID, command: cmd}
+			if prev, ok := index[key]; !ok || a.at.After(prev.at) {
+				index[key] = a
+			}
+		}
+	}
+	return index, nil
+}
@@ -0,0 +1,293 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestReadModel_CachesUntilInvalidated(t *testing.T) {
+	h := testutil.NewHarness(t)
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	testutil.MakeRequest(t, h.DB, sess, testutil.WithCommand("rm -rf ./build", h.ProjectDir, true))
+
+	rm := NewReadModel(h.ProjectDir, newTestLogger())
+	snap, err := rm.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	if len(snap.Pending) != 1 || len(snap.Sessions) != 1 {
+		t.Fatalf("expected 1 pending and 1 session, got %d and %d", len(snap.Pending), len(snap.Sessions))
+	}
+
+	// A write without an invalidation is not seen yet.
+	testutil.MakeRequest(t, h.DB, sess, testutil.WithCommand("rm -rf ./dist", h.ProjectDir, true))
+	if snap, _ := rm.Snapshot(); len(snap.Pending) != 1 {
+		t.Fatalf("expected cached snapshot, got %d pending", len(snap.Pending))
+	}
+
+	rm.Invalidate()
+	snap, err = rm.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot after invalidate failed: %v", err)
+	}
+	if len(snap.Pending) != 2 {
+		t.Fatalf("expected reload to see 2 pending, got %d", len(snap.Pending))
+	}
+
+	stats := rm.Stats()
+	if stats.Hits != 1 || stats.Misses != 2 || stats.Invalidations != 1 || stats.Errors != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if !stats.Loaded || stats.PendingCount != 2 || stats.SessionCount != 1 {
+		t.Errorf("unexpected snapshot stats: %+v", stats)
+	}
+	if stats.HitRate < 0.33 || stats.HitRate > 0.34 {
+		t.Errorf("hit rate = %v, want 1/3", stats.HitRate)
+	}
+}
+
+func TestReadModel_MaxAge(t *testing.T) {
+	h := testutil.NewHarness(t)
+	rm := NewReadModel(h.ProjectDir, newTestLogger())
+	clk := testutil.NewFakeClock(time.Now())
+	rm.SetClock(clk)
+
+	if _, err := rm.Snapshot(); err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	clk.Advance(readModelMaxAge + time.Second)
+	if _, err := rm.Snapshot(); err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	if stats := rm.Stats(); stats.Misses != 2 || stats.Hits != 0 {
+		t.Errorf("expected stale snapshot to reload, got %+v", stats)
+	}
+}
+
+func TestReadModel_MissingDatabase(t *testing.T) {
+	rm := NewReadModel(t.TempDir(), newTestLogger())
+	if _, err := rm.Snapshot(); err == nil {
+		t.Fatal("expected an error without a state database")
+	}
+	if stats := rm.Stats(); stats.Errors != 1 || stats.Loaded {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if _, ok := rm.ApprovedRequest("sess", "rm -rf ./build"); ok {
+		t.Error("expected no approval without a database")
+	}
+}
+
+func TestReadModel_RunInvalidatesOnEvents(t *testing.T) {
+	h := testutil.NewHarness(t)
+	rm := NewReadModel(h.ProjectDir, newTestLogger())
+	if _, err := rm.Snapshot(); err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+
+	events := make(chan WatchEvent, 1)
+	done := make(chan struct{})
+	go func() {
+		rm.Run(context.Background(), events)
+		close(done)
+	}()
+	events <- WatchEvent{Path: h.DBPath, At: time.Now()}
+	close(events)
+	<-done
+
+	if stats := rm.Stats(); stats.Invalidations != 1 || stats.Loaded {
+		t.Errorf("expected the event to invalidate the snapshot, got %+v", stats)
+	}
+}
+
+// makeApprovedRequest creates a request and approves it.
+func makeApprovedRequest(t *testing.T, database *db.DB, sess *db.Session, command, cwd string) *db.Request {
+	t.Helper()
+	req := testutil.MakeRequest(t, database, sess,
+		testutil.WithCommand(command, cwd, true),
+		testutil.WithRisk(db.RiskTierDangerous),
+	)
+	if err := database.UpdateRequestStatus(req.ID, db.StatusApproved); err != nil {
+		t.Fatalf("UpdateRequestStatus failed: %v", err)
+	}
+	return req
+}
+
+func TestReadModel_ApprovedRequest(t *testing.T) {
+	h := testutil.NewHarness(t)
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	approved := makeApprovedRequest(t, h.DB, sess, "rm -rf ./build", h.ProjectDir)
+	old := makeApprovedRequest(t, h.DB, sess, "rm -rf ./old", h.ProjectDir)
+	if _, err := h.DB.Exec(`UPDATE requests SET created_at = ? WHERE id = ?`,
+		time.Now().Add(-2*time.Hour).UTC().Format(time.RFC3339), old.ID); err != nil {
+		t.Fatalf("backdating request: %v", err)
+	}
+	testutil.MakeRequest(t, h.DB, sess, testutil.WithCommand("rm -rf ./pending", h.ProjectDir, true))
+
+	rm := NewReadModel(h.ProjectDir, newTestLogger())
+	if id, ok := rm.ApprovedRequest(sess.ID, "rm -rf ./build"); !ok || id != approved.ID {
+		t.Errorf("ApprovedRequest = %q, %v; want %q", id, ok, approved.ID)
+	}
+	for _, tt := range []struct{ session, command string }{
+		{"other-session", "rm -rf ./build"},
+		{sess.ID, "rm -rf ./old"},
+		{sess.ID, "rm -rf ./pending"},
+	} {
+		if id, ok := rm.ApprovedRequest(tt.session, tt.command); ok {
+			t.Errorf("ApprovedRequest(%q, %q) = %q, want no approval", tt.session, tt.command, id)
+		}
+	}
+}
+
+// makeExecutedRequest creates a request of the given tier and records it as
+// executed at executedAt.
+func makeExecutedRequest(t *testing.T, database *db.DB, sess *db.Session, command, cwd string, tier db.RiskTier, executedAt time.Time) *db.Request {
+	t.Helper()
+	req := testutil.MakeRequest(t, database, sess,
+		testutil.WithCommand(command, cwd, true),
+		testutil.WithRisk(tier),
+	)
+	for _, status := range []db.RequestStatus{db.StatusApproved, db.StatusExecuting, db.StatusExecuted} {
+		if err := database.UpdateRequestStatus(req.ID, status); err != nil {
+			t.Fatalf("UpdateRequestStatus(%s) failed: %v", status, err)
+		}
+	}
+	if err := database.UpdateRequestExecution(req.ID, &db.Execution{ExecutedAt: &executedAt}); err != nil {
+		t.Fatalf("UpdateRequestExecution failed: %v", err)
+	}
+	return req
+}
+
+func TestReadModel_ReusesExecutedRequests(t *testing.T) {
+	h := testutil.NewHarness(t)
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	now := time.Now()
+	retried := makeExecutedRequest(t, h.DB, sess, "rm -rf ./build", h.ProjectDir, db.RiskTierDangerous, now.Add(-10*time.Minute))
+	critical := makeExecutedRequest(t, h.DB, sess, "rm -rf /tmp/cache", h.ProjectDir, db.RiskTierCritical, now.Add(-10*time.Minute))
+	makeExecutedRequest(t, h.DB, sess, "rm -rf ./stale", h.ProjectDir, db.RiskTierDangerous, now.Add(-2*time.Hour))
+
+	rm := NewReadModel(h.ProjectDir, newTestLogger())
+	if id, ok := rm.ApprovedRequest(sess.ID, "rm -rf ./build"); !ok || id != retried.ID {
+		t.Errorf("ApprovedRequest = %q, %v; want %q", id, ok, retried.ID)
+	}
+	for _, tt := range []struct{ session, command string }{
+		{"other-session", "rm -rf ./build"},
+		{sess.ID, "rm -rf /tmp/cache"}, // critical is not reused by default
+		{sess.ID, "rm -rf ./stale"},    // executed outside the window
+	} {
+		if id, ok := rm.ApprovedRequest(tt.session, tt.command); ok {
+			t.Errorf("ApprovedRequest(%q, %q) = %q, want no reuse", tt.session, tt.command, id)
+		}
+	}
+
+	rm.SetApprovalReuse(ApprovalReuse{Window: time.Hour, Tiers: map[db.RiskTier]bool{db.RiskTierCritical: true}})
+	if id, ok := rm.ApprovedRequest(sess.ID, "rm -rf /tmp/cache"); !ok || id != critical.ID {
+		t.Errorf("ApprovedRequest = %q, %v; want %q with critical reuse", id, ok, critical.ID)
+	}
+	if _, ok := rm.ApprovedRequest(sess.ID, "rm -rf ./build"); ok {
+		t.Error("expected dangerous reuse to be off")
+	}
+
+	rm.SetApprovalReuse(ApprovalReuse{})
+	if _, ok := rm.ApprovedRequest(sess.ID, "rm -rf /tmp/cache"); ok {
+		t.Error("expected a zero window to disable reuse")
+	}
+}
+
+func TestApprovalReuseFromConfig(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Integrations.HookReuseWindowMins = 5
+	cfg.Integrations.HookReuseTiers = []string{" Critical "}
+	reuse := ApprovalReuseFromConfig(cfg)
+	if reuse.Window != 5*time.Minute || !reuse.Tiers[db.RiskTierCritical] || reuse.Tiers[db.RiskTierDangerous] {
+		t.Errorf("unexpected reuse policy: %+v", reuse)
+	}
+}
+
+func TestIPCServer_HookQuery_PreApproved(t *testing.T) {
+	h := testutil.NewHarness(t)
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	approved := makeApprovedRequest(t, h.DB, sess, "rm -rf ./build", h.ProjectDir)
+
+	srv, err := NewIPCServer(filepath.Join(shortSocketDir(t), "rm.sock"), newTestLogger())
+	if err != nil {
+		t.Fatalf("NewIPCServer failed: %v", err)
+	}
+	params := HookQueryParams{Command: "rm -rf ./build", SessionID: sess.ID, CWD: h.ProjectDir}
+
+	// Without a read model the database is read directly.
+	if result := srv.classifyCommand(params); result.Action != "allow" || result.RequestID != approved.ID {
+		t.Fatalf("expected pre-approval from the database, got %+v", result)
+	}
+
+	srv.SetReadModel(NewReadModel(h.ProjectDir, newTestLogger()))
+	if result := srv.classifyCommand(params); result.Action != "allow" || result.RequestID != approved.ID {
+		t.Fatalf("expected pre-approval from the read model, got %+v", result)
+	}
+	params.SessionID = "someone-else"
+	if result := srv.classifyCommand(params); result.Action != "block" {
+		t.Errorf("expected other sessions to be blocked, got %+v", result)
+	}
+	// The hook names the conversation; the slb session comes separately.
+	params.SLBSessionID = sess.ID
+	if result := srv.classifyCommand(params); result.Action != "allow" || result.RequestID != approved.ID {
+		t.Errorf("expected the slb session's approval to apply, got %+v", result)
+	}
+	if srv.readModel.Stats().Hits == 0 {
+		t.Error("expected the second lookup to hit the cache")
+	}
+}
+
+func TestIPCServer_StatusAndReadModel(t *testing.T) {
+	h := testutil.NewHarness(t)
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	testutil.MakeRequest(t, h.DB, sess, testutil.WithCommand("rm -rf ./build", h.ProjectDir, true))
+
+	client := startBenchIPC(t)
+	// startBenchIPC has no read model; the RPC reports that.
+	if _, err := client.ReadModel(context.Background()); err == nil {
+		t.Fatal("expected read_model to fail without a read model")
+	}
+
+	srv, err := NewIPCServer(filepath.Join(shortSocketDir(t), "st.sock"), newTestLogger())
+	if err != nil {
+		t.Fatalf("NewIPCServer failed: %v", err)
+	}
+	rm := NewReadModel(h.ProjectDir, newTestLogger())
+	srv.SetReadModel(rm)
+
+	resp := srv.handleReadModel(RPCRequest{ID: 1})
+	if resp.Error != nil {
+		t.Fatalf("read_model failed: %s", resp.Error.Message)
+	}
+	data, _ := json.Marshal(resp.Result)
+	var snap ReadModelSnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		t.Fatalf("unmarshal snapshot: %v", err)
+	}
+	if len(snap.Pending) != 1 || snap.Pending[0].Request.Command.Raw != "rm -rf ./build" || len(snap.Sessions) != 1 {
+		t.Fatalf("unexpected snapshot: %+v", snap)
+	}
+
+	resp = srv.handleStatus(RPCRequest{ID: 2})
+	data, _ = json.Marshal(resp.Result)
+	var info DaemonStatusInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		t.Fatalf("unmarshal status: %v", err)
+	}
+	if info.PendingCount != 1 || info.Cache == nil || info.Cache.Hits < 1 {
+		t.Fatalf("expected cached pending count and hit metrics, got %+v (cache %+v)", info, info.Cache)
+	}
+
+	params, _ := json.Marshal(NotifyParams{Type: "request_created"})
+	srv.handleNotify(RPCRequest{Method: "notify", Params: params, ID: 3})
+	if stats := rm.Stats(); stats.Invalidations != 1 {
+		t.Errorf("expected notify to invalidate the read model, got %+v", stats)
+	}
+}
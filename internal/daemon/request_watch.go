@@ -0,0 +1,248 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// requestWatchWindow is how long after resolution a request is still
+// checked for watchers, so late transitions (approved to executed) are
+// pushed too.
+const requestWatchWindow = time.Hour
+
+// requestWatchHeartbeat is how often a session with an open
+// watch_requests subscription is recorded as active.
+const requestWatchHeartbeat = time.Minute
+
+// WatchRequestsParams are parameters for the watch_requests method.
+type WatchRequestsParams struct {
+	// SessionID is the requestor session whose requests are watched.
+	SessionID string `json:"session_id"`
+	// RequestIDs narrows the watch to these requests; empty watches every
+	// request of the session.
+	RequestIDs []string `json:"request_ids,omitempty"`
+}
+
+// requestWatch scopes a subscription to one session's requests.
+type requestWatch struct {
+	sessionID  string
+	requestIDs map[string]bool
+	// heartbeat is set when the connection may write, so the open
+	// subscription keeps its session active.
+	heartbeat bool
+}
+
+func (w *requestWatch) matches(requestID string) bool {
+	return len(w.requestIDs) == 0 || w.requestIDs[requestID]
+}
+
+// handleWatchRequests subscribes the connection to status changes of a
+// session's requests, whoever makes them. Only sessions known to the
+// daemon's database can be watched, so a client talking to the daemon of
+// another project falls back to polling instead of waiting for pushes that
+// never come.
+func (s *IPCServer) handleWatchRequests(req RPCRequest, conn net.Conn) *RPCResponse {
+	if s.database == nil {
+		return &RPCResponse{
+			Error: &Error{Code: ErrCodeInternal, Message: "state database not configured"},
+			ID:    req.ID,
+		}
+	}
+
+	var params WatchRequestsParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return &RPCResponse{
+			Error: &Error{Code: ErrCodeInvalidParams, Message: "invalid params: " + err.Error()},
+			ID:    req.ID,
+		}
+	}
+	if params.SessionID == "" {
+		return &RPCResponse{
+			Error: &Error{Code: ErrCodeInvalidParams, Message: "session_id is required"},
+			ID:    req.ID,
+		}
+	}
+	if _, err := s.database.GetSession(params.SessionID); err != nil {
+		return &RPCResponse{
+			Error: &Error{Code: ErrCodeInvalidParams, Message: "unknown session: " + params.SessionID},
+			ID:    req.ID,
+		}
+	}
+
+	watch := &requestWatch{
+		sessionID:  params.SessionID,
+		requestIDs: make(map[string]bool, len(params.RequestIDs)),
+		heartbeat:  capsOf(conn)[CapWrite],
+	}
+	for _, id := range params.RequestIDs {
+		watch.requestIDs[id] = true
+	}
+
+	// Once registered, record the statuses the client is about to read
+	// itself, so the first change after subscribing is pushed. Seeding
+	// after registration means a concurrent check cannot drop the seed.
+	return s.startSubscription(req, conn, watch, func() {
+		s.watchMu.Lock()
+		s.seedWatchLocked(params.SessionID, time.Now())
+		s.watchMu.Unlock()
+	})
+}
+
+// RequestWatches returns the watch_requests subscription IDs of each
+// watched session.
+func (s *IPCServer) RequestWatches() map[string][]int64 {
+	s.subscribersMu.RLock()
+	defer s.subscribersMu.RUnlock()
+
+	watches := make(map[string][]int64)
+	for _, sub := range s.subscribers {
+		if sub.watch != nil {
+			watches[sub.watch.sessionID] = append(watches[sub.watch.sessionID], sub.id)
+		}
+	}
+	return watches
+}
+
+// PokeRequestWatches asks for an immediate check of watched requests,
+// e.g. after the daemon saw the state database change.
+func (s *IPCServer) PokeRequestWatches() {
+	select {
+	case s.watchPoke <- struct{}{}:
+	default:
+	}
+}
+
+// RunRequestWatches pushes status changes of watched requests to their
+// sessions' subscriptions until ctx is done. It checks when poked and at
+// least every interval, which covers decisions made by processes the
+// daemon does not hear from.
+func (s *IPCServer) RunRequestWatches(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastHeartbeat time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.watchPoke:
+		}
+		now := time.Now()
+		s.checkRequestWatches(now)
+		if now.Sub(lastHeartbeat) >= requestWatchHeartbeat {
+			s.heartbeatWatchers()
+			lastHeartbeat = now
+		}
+	}
+}
+
+// checkRequestWatches pushes a request_status_changed event to each
+// subscription watching a request whose status moved since the last check.
+func (s *IPCServer) checkRequestWatches(now time.Time) {
+	if s.database == nil {
+		return
+	}
+
+	// Subscribers are listed under watchMu: a watch registered after the
+	// listing seeds itself only once this check is done.
+	s.watchMu.Lock()
+	defer s.watchMu.Unlock()
+
+	s.subscribersMu.RLock()
+	bySession := make(map[string][]*subscriber)
+	for _, sub := range s.subscribers {
+		if sub.watch != nil {
+			bySession[sub.watch.sessionID] = append(bySession[sub.watch.sessionID], sub)
+		}
+	}
+	s.subscribersMu.RUnlock()
+
+	known := make(map[string]db.RequestStatus)
+	for sessionID, subs := range bySession {
+		statuses, err := s.database.RequestStatusesBySession(sessionID, now.Add(-requestWatchWindow))
+		if err != nil {
+			s.logger.Debug("checking watched requests failed", "session_id", sessionID, "error", err)
+			// Keep what we knew so the change is pushed on the next check.
+			for id, status := range s.watchKnown {
+				if _, ok := known[id]; !ok {
+					known[id] = status
+				}
+			}
+			continue
+		}
+		for id, status := range statuses {
+			known[id] = status
+			prev, seen := s.watchKnown[id]
+			if !seen || prev == status {
+				continue
+			}
+			event := Event{
+				Type: EventRequestStatusChanged,
+				Payload: map[string]any{
+					"request_id": id,
+					"session_id": sessionID,
+					"from":       string(prev),
+					"to":         string(status),
+				},
+				Time: now.Unix(),
+			}
+			for _, sub := range subs {
+				if !sub.watch.matches(id) {
+					continue
+				}
+				// A client that misses an event re-reads on its
+				// fallback poll.
+				if !s.offer(sub, event) {
+					s.disconnectSlow(sub)
+				}
+			}
+		}
+	}
+	s.watchKnown = known
+}
+
+// seedWatchLocked records the current statuses of a session's requests
+// without pushing them. The caller holds watchMu.
+func (s *IPCServer) seedWatchLocked(sessionID string, now time.Time) {
+	statuses, err := s.database.RequestStatusesBySession(sessionID, now.Add(-requestWatchWindow))
+	if err != nil {
+		s.logger.Debug("seeding watched requests failed", "session_id", sessionID, "error", err)
+		return
+	}
+	if s.watchKnown == nil {
+		s.watchKnown = make(map[string]db.RequestStatus)
+	}
+	for id, status := range statuses {
+		if _, ok := s.watchKnown[id]; !ok {
+			s.watchKnown[id] = status
+		}
+	}
+}
+
+// heartbeatWatchers queues a heartbeat for every session with an open
+// watch that may write, so an agent blocked waiting stays active.
+func (s *IPCServer) heartbeatWatchers() {
+	if s.eventWriter == nil {
+		return
+	}
+	s.subscribersMu.RLock()
+	sessions := make(map[string]bool)
+	for _, sub := range s.subscribers {
+		if sub.watch != nil && sub.watch.heartbeat {
+			sessions[sub.watch.sessionID] = true
+		}
+	}
+	s.subscribersMu.RUnlock()
+
+	for sessionID := range sessions {
+		s.eventWriter.Heartbeat(sessionID, time.Time{})
+	}
+}
@@ -0,0 +1,114 @@
+package daemon
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+	"github.com/charmbracelet/log"
+)
+
+func TestIPCClient_WatchRequestsPushesStatusChanges_Unix(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix socket tests not supported on windows")
+	}
+
+	h := testutil.NewHarness(t)
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	watched := testutil.MakeRequest(t, h.DB, sess)
+	other := testutil.MakeRequest(t, h.DB, sess)
+
+	socketPath := filepath.Join(shortSocketDir(t), "wr.sock")
+	srv, err := NewIPCServer(socketPath, log.New(io.Discard))
+	if err != nil {
+		t.Fatalf("NewIPCServer: %v", err)
+	}
+	srv.SetDatabase(h.DB)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+		_ = srv.Stop()
+	})
+	go func() { _ = srv.Start(ctx) }()
+	// Only pokes drive checks in this test.
+	go srv.RunRequestWatches(ctx, time.Hour)
+
+	watchCtx, watchCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer watchCancel()
+
+	unknown := NewIPCClient(socketPath)
+	t.Cleanup(func() { _ = unknown.Close() })
+	if _, err := unknown.WatchRequests(watchCtx, "no-such-session", nil); err == nil {
+		t.Fatal("expected watching an unknown session to fail")
+	}
+
+	client := NewIPCClient(socketPath)
+	t.Cleanup(func() { _ = client.Close() })
+	events, err := client.WatchRequests(watchCtx, sess.ID, []string{watched.ID})
+	if err != nil {
+		t.Fatalf("WatchRequests: %v", err)
+	}
+	if got := srv.RequestWatches()[sess.ID]; len(got) != 1 {
+		t.Fatalf("expected the session to map to one subscription, got %v", srv.RequestWatches())
+	}
+
+	// A broadcast is not for watchers, and a change to an unwatched
+	// request is not pushed.
+	srv.BroadcastEvent("request_pending", map[string]any{"request_id": "elsewhere"})
+	if err := h.DB.UpdateRequestStatus(other.ID, db.StatusCancelled); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.DB.UpdateRequestStatus(watched.ID, db.StatusApproved); err != nil {
+		t.Fatal(err)
+	}
+	srv.PokeRequestWatches()
+
+	select {
+	case ev := <-events:
+		payload, _ := ev.Payload.(map[string]any)
+		if ev.Type != EventRequestStatusChanged || payload["request_id"] != watched.ID ||
+			payload["from"] != string(db.StatusPending) || payload["to"] != string(db.StatusApproved) {
+			t.Fatalf("unexpected event: %+v", ev)
+		}
+	case <-watchCtx.Done():
+		t.Fatal("timed out waiting for the pushed status change")
+	}
+
+	select {
+	case ev := <-events:
+		t.Fatalf("unexpected extra event: %+v", ev)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestIPCServer_WatchRequestsHeartbeats(t *testing.T) {
+	h := testutil.NewHarness(t)
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+
+	srv, err := NewIPCServer(filepath.Join(shortSocketDir(t), "hw.sock"), newTestLogger())
+	if err != nil {
+		t.Fatalf("NewIPCServer: %v", err)
+	}
+	t.Cleanup(func() { _ = srv.Stop() })
+	writer := db.NewBufferedEventWriter(h.DB, db.BufferedWriterOptions{FlushInterval: time.Hour})
+	srv.SetEventWriter(writer)
+
+	for id, watch := range map[int64]*requestWatch{
+		1: {sessionID: sess.ID, heartbeat: true},
+		2: {sessionID: "read-only", heartbeat: false},
+		3: nil,
+	} {
+		srv.subscribers[id] = &subscriber{id: id, watch: watch, done: make(chan struct{})}
+	}
+	srv.heartbeatWatchers()
+
+	if stats := writer.Stats(); stats.Pending != 1 {
+		t.Errorf("expected one queued heartbeat, got %+v", stats)
+	}
+}
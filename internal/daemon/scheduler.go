@@ -0,0 +1,177 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/schedule"
+	"github.com/charmbracelet/log"
+)
+
+// A failed job is retried after jobRetryBase, twice as long after each
+// further failure, and never later than its next scheduled run.
+const (
+	jobRetryBase     = 30 * time.Second
+	jobRetryMaxShift = 10
+)
+
+// schedulerMaxWait bounds how long the scheduler sleeps, so a changed
+// system clock is noticed.
+const schedulerMaxWait = time.Minute
+
+// Job is a recurring daemon task.
+type Job struct {
+	Name     string
+	Schedule schedule.Schedule
+	// Enabled jobs run; disabled ones are only recorded, so `slb daemon
+	// jobs list` shows them.
+	Enabled bool
+	Run     func(ctx context.Context) error
+}
+
+// Scheduler runs the daemon's recurring jobs and keeps their state in the
+// daemon_jobs table: when each runs next, survives restarts, and how its
+// last run went.
+type Scheduler struct {
+	db     *db.DB
+	logger *log.Logger
+
+	mu   sync.Mutex
+	jobs []*scheduledJob
+}
+
+type scheduledJob struct {
+	job   Job
+	state *db.DaemonJob
+}
+
+// NewScheduler creates a scheduler storing job state in database.
+func NewScheduler(database *db.DB, logger *log.Logger) *Scheduler {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &Scheduler{db: database, logger: logger}
+}
+
+// Add registers a job. A job whose stored schedule is unchanged keeps its
+// stored next run; otherwise an interval job first runs now and a cron
+// job at its next time.
+func (s *Scheduler) Add(job Job, now time.Time) error {
+	if job.Name == "" || job.Schedule.IsZero() || job.Run == nil {
+		return fmt.Errorf("job requires name, schedule and run")
+	}
+	state, err := s.db.GetDaemonJob(job.Name)
+	if errors.Is(err, db.ErrDaemonJobNotFound) {
+		state = &db.DaemonJob{Name: job.Name}
+	} else if err != nil {
+		return err
+	}
+
+	resume := state.Enabled && state.Schedule == job.Schedule.String() && state.NextRunAt != nil
+	state.Schedule = job.Schedule.String()
+	state.Enabled = job.Enabled
+	switch {
+	case !job.Enabled:
+		state.NextRunAt = nil
+	case resume:
+	case job.Schedule.Interval() > 0:
+		state.NextRunAt = &now
+	default:
+		next := job.Schedule.Next(now.In(time.Local))
+		state.NextRunAt = &next
+	}
+	if err := s.db.SaveDaemonJob(state); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.jobs = append(s.jobs, &scheduledJob{job: job, state: state})
+	s.mu.Unlock()
+	return nil
+}
+
+// Run runs jobs as they come due until ctx is done.
+func (s *Scheduler) Run(ctx context.Context) {
+	for {
+		now := time.Now()
+		s.RunDue(ctx, now)
+
+		wait := schedulerMaxWait
+		if next, ok := s.nextRun(); ok && next.Sub(now) < wait {
+			wait = time.Until(next)
+		}
+		timer := time.NewTimer(max(wait, 0))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// RunDue runs, one after another, every enabled job due at now.
+func (s *Scheduler) RunDue(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	jobs := append([]*scheduledJob(nil), s.jobs...)
+	s.mu.Unlock()
+
+	for _, sj := range jobs {
+		if ctx.Err() != nil {
+			return
+		}
+		if !sj.job.Enabled || sj.state.NextRunAt == nil || sj.state.NextRunAt.After(now) {
+			continue
+		}
+		s.runJob(ctx, sj, now)
+	}
+}
+
+func (s *Scheduler) runJob(ctx context.Context, sj *scheduledJob, now time.Time) {
+	start := time.Now()
+	err := sj.job.Run(ctx)
+	elapsed := time.Since(start)
+
+	state := sj.state
+	state.LastRunAt = &now
+	state.LastDurationMS = elapsed.Milliseconds()
+	state.Runs++
+	next := sj.job.Schedule.Next(now.In(time.Local))
+	if err != nil {
+		state.Failures++
+		state.LastError = err.Error()
+		retry := jobRetryBase << min(state.Failures-1, jobRetryMaxShift)
+		if at := now.Add(retry); at.Before(next) {
+			next = at
+		}
+		s.logger.Warn("daemon job failed", "job", sj.job.Name, "failures", state.Failures, "retry_at", next, "error", err)
+	} else {
+		state.Failures = 0
+		state.LastError = ""
+	}
+	state.NextRunAt = &next
+	if err := s.db.SaveDaemonJob(state); err != nil {
+		s.logger.Warn("saving daemon job state failed", "job", sj.job.Name, "error", err)
+	}
+}
+
+// nextRun is the earliest next run of an enabled job.
+func (s *Scheduler) nextRun() (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var next time.Time
+	found := false
+	for _, sj := range s.jobs {
+		if !sj.job.Enabled || sj.state.NextRunAt == nil {
+			continue
+		}
+		if !found || sj.state.NextRunAt.Before(next) {
+			next, found = *sj.state.NextRunAt, true
+		}
+	}
+	return next, found
+}
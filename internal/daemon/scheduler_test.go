@@ -0,0 +1,222 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/schedule"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+	"github.com/charmbracelet/log"
+)
+
+func mustSchedule(t *testing.T, spec string) schedule.Schedule {
+	t.Helper()
+	s, err := schedule.Parse(spec)
+	if err != nil {
+		t.Fatalf("schedule.Parse(%q): %v", spec, err)
+	}
+	return s
+}
+
+func TestScheduler_RunsDueJobsWithRetryBackoff(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	s := NewScheduler(database, log.New(io.Discard))
+	start := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+
+	runs, fail := 0, true
+	err := s.Add(Job{Name: "flaky", Schedule: mustSchedule(t, "1h"), Enabled: true, Run: func(context.Context) error {
+		runs++
+		if fail {
+			return errors.New("boom")
+		}
+		return nil
+	}}, start)
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	off := 0
+	if err := s.Add(Job{Name: "off", Schedule: mustSchedule(t, "1s"), Run: func(context.Context) error { off++; return nil }}, start); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	// Interval jobs first run right away; failures retry after 30s, then 1m.
+	s.RunDue(context.Background(), start)
+	s.RunDue(context.Background(), start.Add(29*time.Second))
+	s.RunDue(context.Background(), start.Add(30*time.Second))
+	if runs != 2 {
+		t.Fatalf("runs = %d, want 2", runs)
+	}
+	job, err := database.GetDaemonJob("flaky")
+	if err != nil {
+		t.Fatalf("GetDaemonJob: %v", err)
+	}
+	if job.Failures != 2 || job.LastError != "boom" || !job.NextRunAt.Equal(start.Add(90*time.Second)) {
+		t.Fatalf("after two failures: %+v next=%v", job, job.NextRunAt)
+	}
+
+	fail = false
+	s.RunDue(context.Background(), start.Add(90*time.Second))
+	job, _ = database.GetDaemonJob("flaky")
+	if job.Runs != 3 || job.Failures != 0 || job.LastError != "" || !job.NextRunAt.Equal(start.Add(90*time.Second+time.Hour)) {
+		t.Fatalf("after success: %+v next=%v", job, job.NextRunAt)
+	}
+
+	if off != 0 {
+		t.Errorf("disabled job ran %d times", off)
+	}
+	if job, _ := database.GetDaemonJob("off"); job.Enabled || job.NextRunAt != nil {
+		t.Errorf("disabled job state: %+v", job)
+	}
+}
+
+func TestScheduler_ResumesStoredSchedule(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	start := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	noop := func(context.Context) error { return nil }
+
+	s := NewScheduler(database, log.New(io.Discard))
+	if err := s.Add(Job{Name: "digest", Schedule: mustSchedule(t, "0 9 * * 1"), Enabled: true, Run: noop}, start); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	first, _ := database.GetDaemonJob("digest")
+	if first.NextRunAt == nil || first.NextRunAt.In(time.Local).Weekday() != time.Monday {
+		t.Fatalf("cron job next run = %v, want a Monday", first.NextRunAt)
+	}
+
+	// A restart a day later keeps the stored next run.
+	s = NewScheduler(database, log.New(io.Discard))
+	if err := s.Add(Job{Name: "digest", Schedule: mustSchedule(t, "0 9 * * 1"), Enabled: true, Run: noop}, start.AddDate(0, 0, 1)); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if again, _ := database.GetDaemonJob("digest"); !again.NextRunAt.Equal(*first.NextRunAt) {
+		t.Errorf("next run after restart = %v, want %v", again.NextRunAt, first.NextRunAt)
+	}
+
+	// A new schedule starts over.
+	s = NewScheduler(database, log.New(io.Discard))
+	if err := s.Add(Job{Name: "digest", Schedule: mustSchedule(t, "5m"), Enabled: true, Run: noop}, start); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if changed, _ := database.GetDaemonJob("digest"); changed.Schedule != "5m" || !changed.NextRunAt.Equal(start) {
+		t.Errorf("changed schedule: %+v next=%v", changed, changed.NextRunAt)
+	}
+}
+
+func TestReminderDelay(t *testing.T) {
+	first, maxDelay := 15*time.Minute, 4*time.Hour
+	want := []time.Duration{15 * time.Minute, 30 * time.Minute, time.Hour, 2 * time.Hour, 4 * time.Hour, 4 * time.Hour}
+	for sent, w := range want {
+		if got := reminderDelay(sent, first, maxDelay); got != w {
+			t.Errorf("reminderDelay(%d) = %s, want %s", sent, got, w)
+		}
+	}
+	if got := reminderDelay(100, first, maxDelay); got != maxDelay {
+		t.Errorf("reminderDelay(100) = %s", got)
+	}
+}
+
+func TestDaemonJobs_ReviewReminders(t *testing.T) {
+	database := testutil.TempDB(t)
+	clk := testutil.NewFakeClock(time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC))
+	database.SetClock(clk)
+	sess := testutil.MakeSession(t, database)
+	req := testutil.MakeRequest(t, database, sess)
+
+	var events []map[string]any
+	var notes []string
+	notifier := DesktopNotifierFunc(func(title, message string) error {
+		notes = append(notes, title)
+		return nil
+	})
+	jobs := &daemonJobs{
+		db:            database,
+		projectPath:   sess.ProjectPath,
+		cfg:           config.DefaultConfig(),
+		notifications: NewNotificationManager(sess.ProjectPath, config.NotificationsConfig{DesktopEnabled: true}, log.New(io.Discard), notifier),
+		broadcast: func(eventType string, payload any) {
+			if eventType == EventReviewReminder {
+				events = append(events, payload.(map[string]any))
+			}
+		},
+		logger: log.New(io.Discard),
+	}
+
+	remindAt := func(d time.Duration) {
+		t.Helper()
+		clk.Advance(d)
+		if err := jobs.remindReviewers(context.Background()); err != nil {
+			t.Fatalf("remindReviewers: %v", err)
+		}
+	}
+	remindAt(14 * time.Minute) // too early
+	remindAt(time.Minute)      // first reminder at 15m
+	remindAt(29 * time.Minute) // the second waits 30m
+	remindAt(time.Minute)
+	if len(events) != 2 || events[0]["request_id"] != req.ID || events[1]["reminder"] != 2 {
+		t.Fatalf("reminder events = %+v", events)
+	}
+	if len(notes) != 2 || !strings.Contains(notes[0], "still pending") {
+		t.Errorf("desktop notifications = %q", notes)
+	}
+
+	// Decided requests are no longer reminded about, and forgotten.
+	if err := database.UpdateRequestStatus(req.ID, db.StatusCancelled); err != nil {
+		t.Fatalf("UpdateRequestStatus: %v", err)
+	}
+	remindAt(time.Hour)
+	if len(events) != 2 {
+		t.Errorf("reminded about a cancelled request: %+v", events)
+	}
+	if sent, _ := database.ListReviewReminders(); len(sent) != 0 {
+		t.Errorf("reminders kept for a cancelled request: %+v", sent)
+	}
+}
+
+func TestDaemonJobs_RetentionAndDigest(t *testing.T) {
+	h := testutil.NewHarness(t)
+	clk := testutil.NewFakeClock(time.Now().UTC())
+	h.DB.SetClock(clk)
+	old := &db.DaemonEvent{Type: "old", CreatedAt: clk.Now().AddDate(0, 0, -400)}
+	recent := &db.DaemonEvent{Type: "recent", CreatedAt: clk.Now().AddDate(0, 0, -1)}
+	if err := h.DB.InsertDaemonEvents([]*db.DaemonEvent{old, recent}); err != nil {
+		t.Fatalf("InsertDaemonEvents: %v", err)
+	}
+
+	var digest map[string]any
+	jobs := &daemonJobs{
+		db:          h.DB,
+		projectPath: h.ProjectDir,
+		cfg:         config.DefaultConfig(),
+		broadcast: func(eventType string, payload any) {
+			if eventType == EventDigestReady {
+				digest = payload.(map[string]any)
+			}
+		},
+		logger: log.New(io.Discard),
+	}
+	if err := jobs.pruneRetention(context.Background()); err != nil {
+		t.Fatalf("pruneRetention: %v", err)
+	}
+	if events, _ := h.DB.ListDaemonEvents(0, 10); len(events) != 1 || events[0].Type != "recent" {
+		t.Errorf("events after pruning: %+v", events)
+	}
+
+	if err := jobs.writeDigest(context.Background()); err != nil {
+		t.Fatalf("writeDigest: %v", err)
+	}
+	path, _ := digest["path"].(string)
+	if filepath.Dir(path) != filepath.Join(h.ProjectDir, ".slb", "reports") {
+		t.Fatalf("digest path = %q", path)
+	}
+	if data, err := os.ReadFile(path); err != nil || len(data) == 0 {
+		t.Errorf("digest file: %v (%d bytes)", err, len(data))
+	}
+}
@@ -28,6 +28,9 @@ type TCPServerOptions struct {
 //
 // Handshake: client must first send a single line JSON object: {"auth":"<session_key>"}.
 // If RequireAuth is true, the auth value must validate; otherwise it may be empty.
+// Clients with a valid session key may read and write; clients without one
+// get read-only access. An optional "capabilities" list in the handshake
+// narrows the grant further, as the hello method does.
 func NewTCPServer(opts TCPServerOptions, logger *log.Logger) (*IPCServer, error) {
 	addr := strings.TrimSpace(opts.Addr)
 	if addr == "" {
@@ -65,7 +68,8 @@ func NewTCPServer(opts TCPServerOptions, logger *log.Logger) (*IPCServer, error)
 		}
 
 		var hello struct {
-			Auth string `json:"auth"`
+			Auth         string       `json:"auth"`
+			Capabilities []Capability `json:"capabilities"`
 		}
 		if err := json.Unmarshal(scanner.Bytes(), &hello); err != nil {
 			return fmt.Errorf("invalid handshake: %w", err)
@@ -89,6 +93,17 @@ func NewTCPServer(opts TCPServerOptions, logger *log.Logger) (*IPCServer, error)
 			}
 		}
 
+		caps := capSet{CapRead: true}
+		if auth != "" {
+			caps = allCaps()
+		}
+		if len(hello.Capabilities) > 0 {
+			if caps, err = caps.narrow(hello.Capabilities); err != nil {
+				return fmt.Errorf("invalid handshake: %w", err)
+			}
+		}
+		setCaps(conn, caps)
+
 		return nil
 	}
 
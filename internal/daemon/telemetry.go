@@ -0,0 +1,104 @@
+package daemon
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/clock"
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/integrations"
+	"github.com/charmbracelet/log"
+)
+
+// TelemetryReporter sends the opt-in pattern effectiveness report once per
+// configured interval. It does nothing unless telemetry is enabled.
+type TelemetryReporter struct {
+	projectPath string
+	cfg         config.TelemetryConfig
+	logger      *log.Logger
+	client      *integrations.TelemetryClient
+	clock       clock.Clock
+}
+
+// NewTelemetryReporter creates a telemetry reporter for a project.
+func NewTelemetryReporter(projectPath string, cfg config.TelemetryConfig, logger *log.Logger) *TelemetryReporter {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &TelemetryReporter{
+		projectPath: projectPath,
+		cfg:         cfg,
+		logger:      logger,
+		client:      integrations.NewTelemetryClient(cfg.Endpoint, integrations.DefaultTelemetryTimeout),
+		clock:       clock.Real,
+	}
+}
+
+// SetClock sets the clock used to decide when a report is due.
+func (r *TelemetryReporter) SetClock(c clock.Clock) {
+	r.clock = clock.OrReal(c)
+}
+
+// Run checks whether a report is due every interval until ctx is cancelled.
+func (r *TelemetryReporter) Run(ctx context.Context, interval time.Duration) {
+	if r == nil || !r.cfg.Enabled {
+		return
+	}
+	if interval <= 0 {
+		interval = 10 * time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.Check(ctx); err != nil {
+				r.logger.Warn("telemetry report failed", "error", err)
+			}
+		}
+	}
+}
+
+// Check sends a report when the interval has passed since the last attempt
+// and reports whether one was attempted. Failed attempts also wait a full
+// interval, so an unreachable endpoint is not retried on every tick.
+func (r *TelemetryReporter) Check(ctx context.Context) (bool, error) {
+	if r == nil || !r.cfg.Enabled || strings.TrimSpace(r.projectPath) == "" {
+		return false, nil
+	}
+
+	dbPath := filepath.Join(r.projectPath, ".slb", "state.db")
+	dbConn, err := db.OpenWithOptions(dbPath, db.OpenOptions{
+		CreateIfNotExists: false,
+		InitSchema:        false,
+	})
+	if err != nil {
+		// Treat missing DB as no-op (daemon should not crash).
+		return false, nil
+	}
+	defer dbConn.Close()
+
+	now := r.clock.Now().UTC()
+	last, err := dbConn.LastTelemetryReport(false)
+	if err != nil {
+		return false, err
+	}
+	interval := time.Duration(r.cfg.IntervalHours) * time.Hour
+	if last != nil && now.Sub(last.SentAt) < interval {
+		return false, nil
+	}
+
+	report, err := integrations.SendTelemetryReport(ctx, dbConn, r.client, now)
+	if err != nil {
+		return true, err
+	}
+	r.logger.Debug("telemetry report sent", "patterns", report.Patterns, "since", report.PeriodStart)
+	return true, nil
+}
@@ -0,0 +1,63 @@
+package daemon
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestTelemetryReporterCheck(t *testing.T) {
+	project := t.TempDir()
+	dbConn, err := db.OpenProjectDB(project)
+	if err != nil {
+		t.Fatalf("open project db: %v", err)
+	}
+	t.Cleanup(func() { _ = dbConn.Close() })
+
+	sess := testutil.MakeSession(t, dbConn, testutil.WithProject(project))
+	req := testutil.MakeRequest(t, dbConn, sess)
+	testutil.RequireNoError(t, dbConn.SetRequestPattern(&db.RequestPattern{
+		RequestID: req.ID, Tier: db.RiskTierDangerous, Pattern: `^rm\s+-rf`,
+	}), "set pattern")
+	testutil.RequireNoError(t, dbConn.UpdateRequestStatus(req.ID, db.StatusRejected), "reject")
+
+	posts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts++
+	}))
+	defer server.Close()
+
+	clk := testutil.NewFakeClock(time.Now().UTC().Add(time.Minute))
+	cfg := config.TelemetryConfig{Enabled: true, Endpoint: server.URL, IntervalHours: 24}
+
+	disabled := NewTelemetryReporter(project, config.TelemetryConfig{Endpoint: server.URL, IntervalHours: 24}, nil)
+	if sent, err := disabled.Check(context.Background()); err != nil || sent {
+		t.Fatalf("disabled reporter sent=%v err=%v", sent, err)
+	}
+
+	reporter := NewTelemetryReporter(project, cfg, nil)
+	reporter.SetClock(clk)
+	sent, err := reporter.Check(context.Background())
+	testutil.RequireNoError(t, err, "first check")
+	if !sent || posts != 1 {
+		t.Fatalf("expected one report, sent=%v posts=%d", sent, posts)
+	}
+
+	clk.Advance(time.Hour)
+	if sent, err := reporter.Check(context.Background()); err != nil || sent {
+		t.Fatalf("expected no report within the interval, sent=%v err=%v", sent, err)
+	}
+
+	clk.Advance(24 * time.Hour)
+	if sent, err := reporter.Check(context.Background()); err != nil || !sent {
+		t.Fatalf("expected a report after the interval, sent=%v err=%v", sent, err)
+	}
+	// Nothing new was resolved, so the endpoint is not contacted again.
+	testutil.RequireEqual(t, 1, posts, "posts")
+}
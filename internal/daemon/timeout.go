@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/core/statemachine"
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/charmbracelet/log"
 )
@@ -38,6 +39,8 @@ type TimeoutHandlerConfig struct {
 	Action TimeoutAction
 	// DesktopNotify enables desktop notifications on escalation.
 	DesktopNotify bool
+	// Notifier delivers desktop notifications; platform tools when nil.
+	Notifier DesktopNotifier
 	// Logger for timeout events.
 	Logger *log.Logger
 }
@@ -72,9 +75,10 @@ func TimeoutConfigFromConfig(cfg config.Config) TimeoutHandlerConfig {
 
 // TimeoutHandler manages request timeout checking.
 type TimeoutHandler struct {
-	db     *db.DB
-	config TimeoutHandlerConfig
-	logger *log.Logger
+	db      *db.DB
+	config  TimeoutHandlerConfig
+	logger  *log.Logger
+	machine *statemachine.Machine
 
 	mu      sync.Mutex
 	running bool
@@ -89,12 +93,30 @@ func NewTimeoutHandler(database *db.DB, cfg TimeoutHandlerConfig) *TimeoutHandle
 	}
 
 	return &TimeoutHandler{
-		db:     database,
-		config: cfg,
-		logger: logger,
+		db:      database,
+		config:  cfg,
+		logger:  logger,
+		machine: statemachine.New(database),
 	}
 }
 
+// SetStateMachine sets the machine that applies timeout status changes.
+func (h *TimeoutHandler) SetStateMachine(m *statemachine.Machine) {
+	if m != nil {
+		h.machine = m
+	}
+}
+
+// sweep applies a status change decided by the sweeper.
+func (h *TimeoutHandler) sweep(req *db.Request, to db.RequestStatus, authority statemachine.Authority) error {
+	_, err := h.machine.Commit(req.ID, to, statemachine.Input{
+		Authority: authority,
+		Actor:     "timeout_sweeper",
+		Reason:    "timeout action " + string(h.config.Action),
+	})
+	return err
+}
+
 // Start begins the timeout checker goroutine.
 // It returns immediately and the checker runs in the background.
 func (h *TimeoutHandler) Start(ctx context.Context) error {
@@ -139,7 +161,7 @@ func (h *TimeoutHandler) run(ctx context.Context) {
 	defer ticker.Stop()
 
 	// Do an initial check immediately
-	h.checkAndHandleExpired()
+	_ = h.Sweep()
 
 	for {
 		select {
@@ -151,17 +173,18 @@ func (h *TimeoutHandler) run(ctx context.Context) {
 		case <-h.stopCh:
 			return
 		case <-ticker.C:
-			h.checkAndHandleExpired()
+			_ = h.Sweep()
 		}
 	}
 }
 
-// checkAndHandleExpired finds and processes all expired requests.
-func (h *TimeoutHandler) checkAndHandleExpired() {
+// Sweep finds and processes all expired requests once. Requests that
+// fail to move on are logged and retried next sweep.
+func (h *TimeoutHandler) Sweep() error {
 	expired, err := h.db.FindExpiredRequests()
 	if err != nil {
 		h.logger.Error("failed to find expired requests", "error", err)
-		return
+		return fmt.Errorf("finding expired requests: %w", err)
 	}
 
 	for _, req := range expired {
@@ -171,6 +194,7 @@ func (h *TimeoutHandler) checkAndHandleExpired() {
 				"error", err)
 		}
 	}
+	return nil
 }
 
 // HandleExpiredRequest processes a single expired request according to the configured action.
@@ -196,7 +220,7 @@ func (h *TimeoutHandler) HandleExpiredRequest(req *db.Request) error {
 // handleEscalate transitions to TIMEOUT, then ESCALATED with notification.
 func (h *TimeoutHandler) handleEscalate(req *db.Request) error {
 	// First transition to TIMEOUT
-	if err := h.db.UpdateRequestStatus(req.ID, db.StatusTimeout); err != nil {
+	if err := h.sweep(req, db.StatusTimeout, statemachine.AuthoritySystem); err != nil {
 		return fmt.Errorf("transition to timeout: %w", err)
 	}
 
@@ -210,7 +234,7 @@ func (h *TimeoutHandler) handleEscalate(req *db.Request) error {
 	}
 
 	// Transition to ESCALATED
-	if err := h.db.UpdateRequestStatus(req.ID, db.StatusEscalated); err != nil {
+	if err := h.sweep(req, db.StatusEscalated, statemachine.AuthoritySystem); err != nil {
 		return fmt.Errorf("transition to escalated: %w", err)
 	}
 
@@ -226,7 +250,7 @@ func (h *TimeoutHandler) handleEscalate(req *db.Request) error {
 // handleAutoReject transitions to REJECTED (via TIMEOUT first for state machine).
 func (h *TimeoutHandler) handleAutoReject(req *db.Request) error {
 	// Transition to TIMEOUT first
-	if err := h.db.UpdateRequestStatus(req.ID, db.StatusTimeout); err != nil {
+	if err := h.sweep(req, db.StatusTimeout, statemachine.AuthoritySystem); err != nil {
 		return fmt.Errorf("transition to timeout: %w", err)
 	}
 
@@ -251,7 +275,7 @@ func (h *TimeoutHandler) handleAutoApproveWarn(req *db.Request) error {
 	}
 
 	// For CAUTION tier, we can auto-approve with warning
-	if err := h.db.UpdateRequestStatus(req.ID, db.StatusApproved); err != nil {
+	if err := h.sweep(req, db.StatusApproved, statemachine.AuthorityPolicy); err != nil {
 		return fmt.Errorf("transition to approved: %w", err)
 	}
 
@@ -274,7 +298,7 @@ func (h *TimeoutHandler) sendDesktopNotification(req *db.Request) {
 	body := fmt.Sprintf("Request %s timed out.\nCommand: %s\nAgent: %s",
 		truncateID(req.ID, 8), truncateString(req.Command.Raw, 50), req.RequestorAgent)
 
-	if err := notify(title, body); err != nil {
+	if err := h.desktopNotify(title, body); err != nil {
 		h.logger.Debug("desktop notification failed", "error", err)
 	}
 }
@@ -285,11 +309,19 @@ func (h *TimeoutHandler) sendAutoApproveWarning(req *db.Request) {
 	body := fmt.Sprintf("Request %s was auto-approved after timeout.\nCommand: %s",
 		truncateID(req.ID, 8), truncateString(req.Command.Raw, 50))
 
-	if err := notify(title, body); err != nil {
+	if err := h.desktopNotify(title, body); err != nil {
 		h.logger.Debug("desktop notification failed", "error", err)
 	}
 }
 
+// desktopNotify sends through the configured notifier, or platform tools.
+func (h *TimeoutHandler) desktopNotify(title, body string) error {
+	if h.config.Notifier != nil {
+		return h.config.Notifier.Notify(title, body)
+	}
+	return notify(title, body)
+}
+
 // notify sends a desktop notification using platform-specific tools.
 func notify(title, body string) error {
 	switch runtime.GOOS {
@@ -496,3 +496,43 @@ func TestTimeoutHandler_WithNotifications(t *testing.T) {
 		t.Fatalf("HandleExpiredRequest failed: %v", err)
 	}
 }
+
+func TestTimeoutHandler_FakeClockEscalationLadder(t *testing.T) {
+	database := testutil.TempDB(t)
+	clk := testutil.NewFakeClock(time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC))
+	database.SetClock(clk)
+
+	sess := testutil.MakeSession(t, database)
+	// Requests default to a 30 minute expiry from the database clock.
+	req := &db.Request{
+		ProjectPath:        sess.ProjectPath,
+		Command:            db.CommandSpec{Raw: "rm -rf ./build", Cwd: sess.ProjectPath, Shell: true},
+		RiskTier:           db.RiskTierDangerous,
+		RequestorSessionID: sess.ID,
+		RequestorAgent:     sess.AgentName,
+		RequestorModel:     sess.Model,
+		Justification:      db.Justification{Reason: "test"},
+		MinApprovals:       1,
+	}
+	if err := database.CreateRequest(req); err != nil {
+		t.Fatalf("CreateRequest: %v", err)
+	}
+
+	handler := NewTimeoutHandler(database, TimeoutHandlerConfig{Action: TimeoutActionEscalate})
+
+	clk.Advance(db.DefaultRequestTimeout - time.Minute)
+	_ = handler.Sweep()
+	if got, _ := database.GetRequest(req.ID); got.Status != db.StatusPending {
+		t.Fatalf("expected pending before expiry, got %s", got.Status)
+	}
+
+	clk.Advance(2 * time.Minute)
+	_ = handler.Sweep()
+	got, err := database.GetRequest(req.ID)
+	if err != nil {
+		t.Fatalf("GetRequest: %v", err)
+	}
+	if got.Status != db.StatusEscalated {
+		t.Fatalf("expected escalation after expiry, got %s", got.Status)
+	}
+}
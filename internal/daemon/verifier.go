@@ -4,8 +4,10 @@ package daemon
 import (
 	"errors"
 	"fmt"
-	"time"
 
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/core/statemachine"
 	"github.com/Dicklesworthstone/slb/internal/db"
 )
 
@@ -23,17 +25,57 @@ type VerificationResult struct {
 
 // Verifier validates execution gate conditions.
 type Verifier struct {
-	db *db.DB
+	db       *db.DB
+	machine  *statemachine.Machine
+	executor *core.Executor
 }
 
 // NewVerifier creates a new execution verifier.
 func NewVerifier(database *db.DB) *Verifier {
-	return &Verifier{db: database}
+	return &Verifier{db: database, machine: statemachine.New(database), executor: core.NewExecutor(database, nil)}
+}
+
+// SetExecutor sets the executor whose gates (policy, execution windows,
+// drift) a request must also pass, so the daemon refuses what
+// 'slb execute' would refuse.
+func (v *Verifier) SetExecutor(e *core.Executor) {
+	if e != nil {
+		v.executor = e
+	}
+}
+
+// SetStateMachine sets the machine that applies execution status changes.
+func (v *Verifier) SetStateMachine(m *statemachine.Machine) {
+	if m != nil {
+		v.machine = m
+	}
+}
+
+// ExecutorFromConfig builds the executor whose gates verify_execute applies,
+// with the same execution windows and tier overrides as 'slb execute'.
+func ExecutorFromConfig(database *db.DB, cfg config.Config) (*core.Executor, error) {
+	// Entries were checked when the config loaded.
+	tierOverrides, _ := core.ParseTierOverrides(cfg.Agents.TierOverrides)
+	executor := core.NewExecutor(database, nil).WithTierOverrides(tierOverrides)
+	if w := cfg.ExecutionWindows; w.Enabled {
+		windows, err := core.NewExecutionWindowPolicy(w.QuietHours, w.BlockWeekends, w.Tiers, w.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("execution_windows: %w", err)
+		}
+		executor.WithExecutionWindows(windows)
+	}
+	return executor, nil
+}
+
+// systemInput is the statemachine input for the verifier's bookkeeping.
+func systemInput(reason string) statemachine.Input {
+	return statemachine.Input{Authority: statemachine.AuthoritySystem, Actor: "daemon", Reason: reason}
 }
 
 // VerifyExecutionAllowed checks all gate conditions for executing a request.
 // Does NOT mark the request as executing - use VerifyAndMarkExecuting for that.
-func (v *Verifier) VerifyExecutionAllowed(requestID, sessionID string) (*VerificationResult, error) {
+func (v *Verifier) VerifyExecutionAllowed(params VerifyExecuteParams) (*VerificationResult, error) {
+	requestID, sessionID := params.RequestID, params.SessionID
 	if requestID == "" {
 		return nil, errors.New("request_id is required")
 	}
@@ -63,7 +105,7 @@ func (v *Verifier) VerifyExecutionAllowed(requestID, sessionID string) (*Verific
 		}, nil
 	}
 
-	now := time.Now()
+	now := v.db.Now()
 	if now.After(*request.ApprovalExpiresAt) {
 		return &VerificationResult{
 			Allowed: false,
@@ -98,6 +140,25 @@ func (v *Verifier) VerifyExecutionAllowed(requestID, sessionID string) (*Verific
 		}, nil
 	}
 
+	// Gate 5: The executor's own gates - current policy, execution
+	// windows and drift from the caller's directory and environment.
+	if err := v.executor.CheckGates(request, core.ExecuteOptions{Cwd: params.Cwd, Environ: params.Environ}); err != nil {
+		return &VerificationResult{
+			Allowed: false,
+			Reason:  err.Error(),
+		}, nil
+	}
+
+	// Gate 6: Downloaded scripts are pinned at review time, but the caller
+	// runs the raw command, which would fetch them again. Only 'slb
+	// execute' runs the pinned copies.
+	if len(core.DetectRemoteScripts(request.Command.Raw)) > 0 {
+		return &VerificationResult{
+			Allowed: false,
+			Reason:  "command downloads scripts into a shell; run it with 'slb execute', which runs the pinned copies",
+		}, nil
+	}
+
 	// All gates passed.
 	return &VerificationResult{
 		Allowed:                  true,
@@ -108,9 +169,11 @@ func (v *Verifier) VerifyExecutionAllowed(requestID, sessionID string) (*Verific
 
 // VerifyAndMarkExecuting verifies gate conditions and atomically marks the
 // request as EXECUTING. This implements "first executor wins" semantics.
-func (v *Verifier) VerifyAndMarkExecuting(requestID, sessionID string) (*VerificationResult, error) {
+func (v *Verifier) VerifyAndMarkExecuting(params VerifyExecuteParams) (*VerificationResult, error) {
+	requestID, sessionID := params.RequestID, params.SessionID
+
 	// First verify all conditions.
-	result, err := v.VerifyExecutionAllowed(requestID, sessionID)
+	result, err := v.VerifyExecutionAllowed(params)
 	if err != nil {
 		return nil, err
 	}
@@ -121,7 +184,7 @@ func (v *Verifier) VerifyAndMarkExecuting(requestID, sessionID string) (*Verific
 
 	// Attempt to atomically update status to EXECUTING.
 	// This will fail if someone else already changed the status.
-	err = v.db.UpdateRequestStatus(requestID, db.StatusExecuting)
+	_, err = v.machine.Commit(requestID, db.StatusExecuting, systemInput("verify_execute by session "+sessionID))
 	if err != nil {
 		// Check if it's because status changed (race condition).
 		request, getErr := v.db.GetRequest(requestID)
@@ -163,16 +226,16 @@ func (v *Verifier) RevertExecutingOnFailure(requestID string) error {
 	}
 
 	// Check approval hasn't expired.
-	if request.ApprovalExpiresAt != nil && time.Now().After(*request.ApprovalExpiresAt) {
+	if request.ApprovalExpiresAt != nil && v.db.Now().After(*request.ApprovalExpiresAt) {
 		// Approval expired, transition to TIMED_OUT instead.
-		if err := v.db.UpdateRequestStatus(requestID, db.StatusTimedOut); err != nil {
+		if _, err := v.machine.Commit(requestID, db.StatusTimedOut, systemInput("approval expired before execution started")); err != nil {
 			return fmt.Errorf("updating status to timed_out: %w", err)
 		}
 		return nil
 	}
 
 	// Revert to APPROVED.
-	if err := v.db.UpdateRequestStatus(requestID, db.StatusApproved); err != nil {
+	if _, err := v.machine.Commit(requestID, db.StatusApproved, systemInput("execution failed before the command started")); err != nil {
 		return fmt.Errorf("reverting status to approved: %w", err)
 	}
 
@@ -195,7 +258,7 @@ func (v *Verifier) MarkExecutionComplete(requestID string, exitCode int, success
 	}
 
 	// Update execution info.
-	now := time.Now().UTC()
+	now := v.db.Now()
 	exec := &db.Execution{
 		ExitCode:   &exitCode,
 		ExecutedAt: &now,
@@ -212,7 +275,7 @@ func (v *Verifier) MarkExecutionComplete(requestID string, exitCode int, success
 		status = db.StatusExecutionFailed
 	}
 
-	if err := v.db.UpdateRequestStatus(requestID, status); err != nil {
+	if _, err := v.machine.Commit(requestID, status, systemInput(fmt.Sprintf("exit code %d", exitCode))); err != nil {
 		return fmt.Errorf("updating status: %w", err)
 	}
 
@@ -223,6 +286,10 @@ func (v *Verifier) MarkExecutionComplete(requestID string, exitCode int, success
 type VerifyExecuteParams struct {
 	RequestID string `json:"request_id"`
 	SessionID string `json:"session_id"`
+	// Cwd and Environ describe where the caller will run the command, for
+	// the drift gate. A nil Environ checks the daemon's own environment.
+	Cwd     string   `json:"cwd,omitempty"`
+	Environ []string `json:"environ,omitempty"`
 }
 
 // VerifyExecuteResponse is the response for the verify_execute IPC method.
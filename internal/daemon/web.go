@@ -0,0 +1,403 @@
+package daemon
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/core/statemachine"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/charmbracelet/log"
+)
+
+//go:embed web
+var webAssets embed.FS
+
+// maxWebBody bounds review submissions from the browser.
+const maxWebBody = 64 << 10
+
+// webKeepalive is how often an idle event stream sends a comment, so
+// proxies keep the connection open.
+const webKeepalive = 15 * time.Second
+
+// WebServerOptions configures the optional browser review UI.
+type WebServerOptions struct {
+	Addr        string
+	AllowedIPs  []string
+	ProjectPath string
+	DB          *db.DB
+	// ReviewConfig applies to reviews submitted from the browser. Approvals
+	// that need sudo-mode authentication are refused: there is no terminal
+	// to authenticate at.
+	ReviewConfig core.ReviewConfig
+	// Machine, when set, records and broadcasts the status changes reviews
+	// cause.
+	Machine *statemachine.Machine
+	// Events, when set, serves /api/events from the daemon's broadcasts.
+	Events *IPCServer
+}
+
+// WebServer serves a small single-page review UI and the JSON API it uses:
+// list pending requests, show one, approve or reject it. Every API call
+// authenticates with an active session's key as a bearer token, and reviews
+// are recorded as that session's, under the same rules as `slb approve`.
+type WebServer struct {
+	opts    WebServerOptions
+	allowed []*net.IPNet
+	ln      net.Listener
+	srv     *http.Server
+	logger  *log.Logger
+}
+
+// NewWebServer listens on opts.Addr; call Start to serve.
+func NewWebServer(opts WebServerOptions, logger *log.Logger) (*WebServer, error) {
+	addr := strings.TrimSpace(opts.Addr)
+	if addr == "" {
+		return nil, fmt.Errorf("web addr is required")
+	}
+	if opts.DB == nil {
+		return nil, fmt.Errorf("web UI needs the state database")
+	}
+	allowed, err := parseAllowedIPNets(opts.AllowedIPs)
+	if err != nil {
+		return nil, err
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("listen web %s: %w", addr, err)
+	}
+	if logger == nil {
+		logger = log.Default()
+	}
+
+	w := &WebServer{opts: opts, allowed: allowed, ln: ln, logger: logger}
+	w.srv = &http.Server{
+		Handler:           w.handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	return w, nil
+}
+
+// Addr is the address the server listens on.
+func (w *WebServer) Addr() string {
+	return w.ln.Addr().String()
+}
+
+// Start serves until Stop is called or ctx is done.
+func (w *WebServer) Start(ctx context.Context) error {
+	go func() {
+		<-ctx.Done()
+		_ = w.Stop()
+	}()
+	if err := w.srv.Serve(w.ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Stop closes the listener and any open connections.
+func (w *WebServer) Stop() error {
+	return w.srv.Close()
+}
+
+func (w *WebServer) handler() http.Handler {
+	static, _ := fs.Sub(webAssets, "web")
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /", http.FileServerFS(static))
+	// A request's own page is the same app opened on that request, so
+	// reviewers.qr_url can link straight to it.
+	mux.HandleFunc("GET /requests/{id}", func(rw http.ResponseWriter, r *http.Request) {
+		http.ServeFileFS(rw, r, static, "index.html")
+	})
+	mux.HandleFunc("GET /api/requests", w.authed(w.listRequests))
+	mux.HandleFunc("GET /api/requests/{id}", w.authed(w.showRequest))
+	mux.HandleFunc("POST /api/requests/{id}/approve", w.authed(w.review(db.DecisionApprove)))
+	mux.HandleFunc("POST /api/requests/{id}/reject", w.authed(w.review(db.DecisionReject)))
+	mux.HandleFunc("GET /api/events", w.authed(w.streamEvents))
+
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if len(w.allowed) > 0 {
+			host, _, _ := net.SplitHostPort(r.RemoteAddr)
+			if ip := net.ParseIP(host); ip == nil || !ipAllowed(ip, w.allowed) {
+				http.Error(rw, "forbidden", http.StatusForbidden)
+				return
+			}
+		}
+		h := rw.Header()
+		h.Set("Content-Security-Policy", "default-src 'self'; frame-ancestors 'none'")
+		h.Set("X-Content-Type-Options", "nosniff")
+		h.Set("Referrer-Policy", "no-referrer")
+		h.Set("Cache-Control", "no-store")
+		mux.ServeHTTP(rw, r)
+	})
+}
+
+// authed resolves the bearer token to an active session before calling h.
+func (w *WebServer) authed(h func(http.ResponseWriter, *http.Request, *db.Session)) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		token = strings.TrimSpace(token)
+		if !ok || token == "" {
+			writeWebError(rw, http.StatusUnauthorized, "session key required")
+			return
+		}
+		sess, err := w.opts.DB.FindActiveSessionByKey(token)
+		if errors.Is(err, db.ErrSessionNotFound) {
+			writeWebError(rw, http.StatusUnauthorized, "unknown or ended session")
+			return
+		}
+		if err != nil {
+			writeWebError(rw, http.StatusInternalServerError, err.Error())
+			return
+		}
+		h(rw, r, sess)
+	}
+}
+
+// webRequest is a request as the browser UI shows it.
+type webRequest struct {
+	ID             string           `json:"id"`
+	Status         string           `json:"status"`
+	RiskTier       string           `json:"risk_tier"`
+	Command        string           `json:"command"`
+	Cwd            string           `json:"cwd"`
+	RequestorAgent string           `json:"requestor_agent"`
+	RequestorModel string           `json:"requestor_model,omitempty"`
+	Justification  db.Justification `json:"justification"`
+	MinApprovals   int              `json:"min_approvals"`
+	Own            bool             `json:"own"`
+	CreatedAt      time.Time        `json:"created_at"`
+	ExpiresAt      *time.Time       `json:"expires_at,omitempty"`
+	Reviews        []webReview      `json:"reviews,omitempty"`
+}
+
+type webReview struct {
+	ReviewerAgent string    `json:"reviewer_agent"`
+	Decision      string    `json:"decision"`
+	Comments      string    `json:"comments,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func newWebRequest(req *db.Request, sess *db.Session) webRequest {
+	command := req.Command.Raw
+	if req.Command.ContainsSensitive && req.Command.DisplayRedacted != "" {
+		command = req.Command.DisplayRedacted
+	}
+	return webRequest{
+		ID:             req.ID,
+		Status:         string(req.Status),
+		RiskTier:       string(req.RiskTier),
+		Command:        command,
+		Cwd:            req.Command.Cwd,
+		RequestorAgent: req.RequestorAgent,
+		RequestorModel: req.RequestorModel,
+		Justification:  req.Justification,
+		MinApprovals:   req.MinApprovals,
+		Own:            req.RequestorSessionID == sess.ID,
+		CreatedAt:      req.CreatedAt,
+		ExpiresAt:      req.ExpiresAt,
+	}
+}
+
+func (w *WebServer) listRequests(rw http.ResponseWriter, r *http.Request, sess *db.Session) {
+	pending, err := w.opts.DB.ListPendingRequests(w.opts.ProjectPath)
+	if err != nil {
+		writeWebError(rw, http.StatusInternalServerError, err.Error())
+		return
+	}
+	out := make([]webRequest, 0, len(pending))
+	for _, req := range pending {
+		out = append(out, newWebRequest(req, sess))
+	}
+	writeWebJSON(rw, http.StatusOK, out)
+}
+
+func (w *WebServer) showRequest(rw http.ResponseWriter, r *http.Request, sess *db.Session) {
+	req, reviews, err := w.opts.DB.GetRequestWithReviews(r.PathValue("id"))
+	if err != nil || req.ProjectPath != w.opts.ProjectPath {
+		writeWebError(rw, http.StatusNotFound, "request not found")
+		return
+	}
+	out := newWebRequest(req, sess)
+	for _, rev := range reviews {
+		out.Reviews = append(out.Reviews, webReview{
+			ReviewerAgent: rev.ReviewerAgent,
+			Decision:      string(rev.Decision),
+			Comments:      rev.Comments,
+			CreatedAt:     rev.CreatedAt,
+		})
+	}
+	writeWebJSON(rw, http.StatusOK, out)
+}
+
+func (w *WebServer) review(decision db.Decision) func(http.ResponseWriter, *http.Request, *db.Session) {
+	return func(rw http.ResponseWriter, r *http.Request, sess *db.Session) {
+		var body struct {
+			Reason   string `json:"reason"`
+			Comments string `json:"comments"`
+		}
+		if err := json.NewDecoder(http.MaxBytesReader(rw, r.Body, maxWebBody)).Decode(&body); err != nil {
+			writeWebError(rw, http.StatusBadRequest, "invalid body: "+err.Error())
+			return
+		}
+		comments := strings.TrimSpace(body.Comments)
+		if decision == db.DecisionReject {
+			reason := strings.TrimSpace(body.Reason)
+			if reason == "" {
+				writeWebError(rw, http.StatusBadRequest, "a reason is required to reject")
+				return
+			}
+			if comments != "" {
+				reason += "\n\n" + comments
+			}
+			comments = reason
+		}
+
+		req, err := w.opts.DB.GetRequest(r.PathValue("id"))
+		if err != nil || req.ProjectPath != w.opts.ProjectPath {
+			writeWebError(rw, http.StatusNotFound, "request not found")
+			return
+		}
+
+		svc := core.NewReviewService(w.opts.DB, w.opts.ReviewConfig)
+		if w.opts.Machine != nil {
+			svc.SetStateMachine(w.opts.Machine)
+		}
+		result, err := svc.SubmitReview(core.ReviewOptions{
+			SessionID:  sess.ID,
+			SessionKey: sess.SessionKey,
+			RequestID:  req.ID,
+			Decision:   decision,
+			Comments:   comments,
+		})
+		if errors.Is(err, core.ErrFreshAuthRequired) {
+			writeWebError(rw, http.StatusForbidden, "sudo mode: approve this tier with 'slb approve' at a terminal")
+			return
+		}
+		if err != nil {
+			writeWebError(rw, http.StatusConflict, err.Error())
+			return
+		}
+
+		resp := map[string]any{
+			"review_id":  result.Review.ID,
+			"request_id": req.ID,
+			"decision":   string(decision),
+			"approvals":  result.Approvals,
+			"rejections": result.Rejections,
+		}
+		if result.RequestStatusChanged {
+			resp["new_request_status"] = string(result.NewRequestStatus)
+		}
+		w.logger.Info("web review", "request_id", req.ID, "decision", decision, "reviewer", sess.AgentName)
+		writeWebJSON(rw, http.StatusOK, resp)
+	}
+}
+
+// streamEvents sends the daemon's events as server-sent events, each with
+// its sequence number as the event ID. A client resumes after the event
+// named by its Last-Event-ID header or since parameter; a resync event
+// tells it that events were missed and it should reload what it shows.
+func (w *WebServer) streamEvents(rw http.ResponseWriter, r *http.Request, _ *db.Session) {
+	if w.opts.Events == nil {
+		writeWebError(rw, http.StatusServiceUnavailable, "event stream unavailable")
+		return
+	}
+	flusher, ok := rw.(http.Flusher)
+	if !ok {
+		writeWebError(rw, http.StatusInternalServerError, "streaming unsupported")
+		return
+	}
+	since := int64(-1)
+	resume := r.Header.Get("Last-Event-ID")
+	if resume == "" {
+		resume = r.URL.Query().Get("since")
+	}
+	if resume != "" {
+		n, err := strconv.ParseInt(resume, 10, 64)
+		if err != nil || n < 0 {
+			writeWebError(rw, http.StatusBadRequest, "invalid event id "+strconv.Quote(resume))
+			return
+		}
+		since = n
+	}
+
+	sub := w.opts.Events.SubscribeEvents(since)
+	defer sub.Close()
+
+	h := rw.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("X-Accel-Buffering", "no")
+	rw.WriteHeader(http.StatusOK)
+
+	last := since
+	if sub.Gap {
+		writeWebResync(rw, "events after "+resume+" are no longer held")
+		last = -1
+	}
+	send := func(event Event) error {
+		if last >= 0 && event.Seq > last+1 {
+			writeWebResync(rw, "events were dropped")
+		}
+		last = event.Seq
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(rw, "id: %d\nevent: %s\ndata: %s\n\n", event.Seq, event.Type, data)
+		return err
+	}
+	for _, event := range sub.Replay {
+		if err := send(event); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	keepalive := time.NewTicker(webKeepalive)
+	defer keepalive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-sub.Done():
+			return
+		case <-keepalive.C:
+			if _, err := io.WriteString(rw, ": keepalive\n\n"); err != nil {
+				return
+			}
+		case event := <-sub.Events():
+			if err := send(event); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// writeWebResync tells an event stream client it missed events.
+func writeWebResync(rw http.ResponseWriter, reason string) {
+	data, _ := json.Marshal(map[string]string{"reason": reason})
+	_, _ = fmt.Fprintf(rw, "event: resync\ndata: %s\n\n", data)
+}
+
+func writeWebJSON(rw http.ResponseWriter, status int, v any) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+	_ = json.NewEncoder(rw).Encode(v)
+}
+
+func writeWebError(rw http.ResponseWriter, status int, msg string) {
+	writeWebJSON(rw, status, map[string]string{"error": msg})
+}
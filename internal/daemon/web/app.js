@@ -0,0 +1,233 @@
+"use strict";
+
+// The session key is the bearer token for every API call. It is kept in
+// sessionStorage so it goes away with the tab.
+const keyName = "slb-session-key";
+const $ = (id) => document.getElementById(id);
+let current = null;
+let events = null;
+let refreshTimer = null;
+
+function status(msg, error) {
+  $("status").textContent = msg || "";
+  $("status").className = error ? "error" : "";
+}
+
+async function api(method, path, body) {
+  const res = await fetch(path, {
+    method,
+    headers: {
+      "Authorization": "Bearer " + sessionStorage.getItem(keyName),
+      "Content-Type": "application/json",
+    },
+    body: body ? JSON.stringify(body) : undefined,
+  });
+  const data = await res.json().catch(() => ({}));
+  if (res.status === 401) {
+    signOut();
+  }
+  if (!res.ok) {
+    throw new Error(data.error || res.statusText);
+  }
+  return data;
+}
+
+function tier(t) {
+  const span = document.createElement("span");
+  span.className = "tier tier-" + t;
+  span.textContent = t;
+  return span;
+}
+
+async function loadList() {
+  const requests = await api("GET", "/api/requests");
+  const list = $("requests");
+  list.replaceChildren();
+  $("empty").hidden = requests.length > 0;
+  for (const r of requests) {
+    const li = document.createElement("li");
+    li.dataset.id = r.id;
+    li.className = current && current.id === r.id ? "selected" : "";
+    const head = document.createElement("div");
+    head.append(tier(r.risk_tier), " " + r.requestor_agent + (r.own ? " (you)" : ""));
+    const cmd = document.createElement("div");
+    cmd.className = "cmd";
+    cmd.textContent = r.command;
+    li.append(head, cmd);
+    li.addEventListener("click", () => show(r.id).catch((e) => status(e.message, true)));
+    list.append(li);
+  }
+}
+
+async function show(id, keepStatus) {
+  const r = await api("GET", "/api/requests/" + encodeURIComponent(id));
+  current = r;
+  history.replaceState(null, "", "/requests/" + encodeURIComponent(id));
+  for (const li of $("requests").children) {
+    li.classList.toggle("selected", li.dataset.id === id);
+  }
+  $("detail").hidden = false;
+  $("d-title").replaceChildren(tier(r.risk_tier), " " + r.id);
+  $("d-command").textContent = r.command;
+
+  const fields = [
+    ["Status", r.status],
+    ["Directory", r.cwd],
+    ["Requestor", r.requestor_agent + (r.requestor_model ? " (" + r.requestor_model + ")" : "")],
+    ["Reason", r.justification.reason],
+    ["Effect", r.justification.expected_effect],
+    ["Goal", r.justification.goal],
+    ["Safety", r.justification.safety_argument],
+    ["Approvals needed", String(r.min_approvals)],
+    ["Created", new Date(r.created_at).toLocaleString()],
+    ["Expires", r.expires_at ? new Date(r.expires_at).toLocaleString() : ""],
+  ];
+  const dl = $("d-fields");
+  dl.replaceChildren();
+  for (const [name, value] of fields) {
+    if (!value) continue;
+    const dt = document.createElement("dt");
+    dt.textContent = name;
+    const dd = document.createElement("dd");
+    dd.textContent = value;
+    dl.append(dt, dd);
+  }
+
+  const reviews = $("d-reviews");
+  reviews.replaceChildren();
+  for (const rev of r.reviews || []) {
+    const li = document.createElement("li");
+    li.textContent = rev.decision + " by " + rev.reviewer_agent + (rev.comments ? ": " + rev.comments : "");
+    reviews.append(li);
+  }
+  if (!reviews.children.length) {
+    reviews.textContent = "None yet.";
+  }
+  $("decide").hidden = r.own || r.status !== "pending";
+  if (!keepStatus) {
+    status("");
+  }
+}
+
+// refresh reloads what is shown after an event, once a burst of events
+// has passed.
+function refresh() {
+  clearTimeout(refreshTimer);
+  refreshTimer = setTimeout(async () => {
+    try {
+      await loadList();
+      if (current) {
+        await show(current.id, true);
+      }
+    } catch (e) {
+      status(e.message, true);
+    }
+  }, 200);
+}
+
+// watchEvents keeps the page live from /api/events, resuming after the
+// last event seen when the stream drops. EventSource cannot send the
+// session key, so the stream is read with fetch.
+async function watchEvents() {
+  if (events) {
+    events.abort();
+  }
+  const ctl = new AbortController();
+  events = ctl;
+  let lastId = "";
+  while (!ctl.signal.aborted && sessionStorage.getItem(keyName)) {
+    try {
+      const headers = { "Authorization": "Bearer " + sessionStorage.getItem(keyName) };
+      if (lastId) {
+        headers["Last-Event-ID"] = lastId;
+      }
+      const res = await fetch("/api/events", { headers, signal: ctl.signal });
+      if (res.status === 401) {
+        signOut();
+        return;
+      }
+      if (!res.ok) {
+        return;
+      }
+      const reader = res.body.pipeThrough(new TextDecoderStream()).getReader();
+      let buf = "";
+      for (;;) {
+        const { value, done } = await reader.read();
+        if (done) break;
+        buf += value;
+        let end;
+        while ((end = buf.indexOf("\n\n")) >= 0) {
+          const block = buf.slice(0, end);
+          buf = buf.slice(end + 2);
+          if (block.startsWith(":")) continue;
+          for (const line of block.split("\n")) {
+            if (line.startsWith("id: ")) lastId = line.slice(4);
+          }
+          refresh();
+        }
+      }
+    } catch (e) {
+      if (ctl.signal.aborted) return;
+    }
+    await new Promise((resolve) => setTimeout(resolve, 3000));
+  }
+}
+
+async function decide(decision) {
+  if (!current) return;
+  const body = { comments: $("comments").value, reason: $("reason").value };
+  const res = await api("POST", "/api/requests/" + encodeURIComponent(current.id) + "/" + decision, body);
+  $("comments").value = "";
+  $("reason").value = "";
+  status((decision === "approve" ? "Approved." : "Rejected.") +
+    (res.new_request_status ? " Request is now " + res.new_request_status + "." : ""));
+  await show(current.id).catch(() => { $("detail").hidden = true; });
+  await loadList();
+}
+
+function signOut() {
+  sessionStorage.removeItem(keyName);
+  current = null;
+  if (events) {
+    events.abort();
+    events = null;
+  }
+  $("app").hidden = true;
+  $("detail").hidden = true;
+  $("logout").hidden = true;
+  $("login").hidden = false;
+}
+
+async function start() {
+  if (!sessionStorage.getItem(keyName)) {
+    signOut();
+    return;
+  }
+  $("login").hidden = true;
+  $("app").hidden = false;
+  $("logout").hidden = false;
+  try {
+    await loadList();
+    // /requests/{id} opens that request, as linked from a QR code.
+    const m = location.pathname.match(/^\/requests\/([^/]+)$/);
+    if (m && !current) {
+      await show(decodeURIComponent(m[1]));
+    }
+  } catch (e) {
+    status(e.message, true);
+  }
+  watchEvents();
+}
+
+$("login").addEventListener("submit", (e) => {
+  e.preventDefault();
+  sessionStorage.setItem(keyName, $("key").value.trim());
+  $("key").value = "";
+  start();
+});
+$("logout").addEventListener("click", signOut);
+$("refresh").addEventListener("click", () => loadList().catch((e) => status(e.message, true)));
+$("approve").addEventListener("click", () => decide("approve").catch((e) => status(e.message, true)));
+$("reject").addEventListener("click", () => decide("reject").catch((e) => status(e.message, true)));
+
+start();
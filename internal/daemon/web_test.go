@@ -0,0 +1,244 @@
+package daemon
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+	"github.com/charmbracelet/log"
+)
+
+func newTestWebServer(t *testing.T, h *testutil.Harness, cfg config.Config) *WebServer {
+	t.Helper()
+	w, err := NewWebServer(WebServerOptions{
+		Addr:         "127.0.0.1:0",
+		AllowedIPs:   cfg.Daemon.WebAllowedIPs,
+		ProjectPath:  h.ProjectDir,
+		DB:           h.DB,
+		ReviewConfig: core.ReviewConfigFromConfig(cfg),
+	}, log.New(io.Discard))
+	if err != nil {
+		t.Fatalf("NewWebServer: %v", err)
+	}
+	t.Cleanup(func() { _ = w.Stop() })
+	return w
+}
+
+func webCall(t *testing.T, w *WebServer, method, path, key, body string) (int, string) {
+	t.Helper()
+	r := httptest.NewRequest(method, path, strings.NewReader(body))
+	r.RemoteAddr = "127.0.0.1:50000"
+	if key != "" {
+		r.Header.Set("Authorization", "Bearer "+key)
+	}
+	rec := httptest.NewRecorder()
+	w.handler().ServeHTTP(rec, r)
+	return rec.Code, rec.Body.String()
+}
+
+func TestWebServer_ReviewFlow(t *testing.T) {
+	h := testutil.NewHarness(t)
+	requestor := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir), testutil.WithAgent("Requestor"))
+	reviewer := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir), testutil.WithAgent("Reviewer"))
+	req := testutil.MakeRequest(t, h.DB, requestor,
+		testutil.WithCommand("rm -rf ./build", h.ProjectDir, true),
+		testutil.WithRisk(db.RiskTierDangerous),
+		testutil.WithMinApprovals(1),
+		testutil.WithRequireDifferentModel(false),
+	)
+	w := newTestWebServer(t, h, config.DefaultConfig())
+
+	code, body := webCall(t, w, "GET", "/", "", "")
+	if code != http.StatusOK || !strings.Contains(body, "<title>slb review</title>") {
+		t.Fatalf("GET / = %d:\n%s", code, body)
+	}
+	// reviewers.qr_url can link to a request's own page.
+	code, body = webCall(t, w, "GET", "/requests/"+req.ID, "", "")
+	if code != http.StatusOK || !strings.Contains(body, `<script src="/app.js">`) {
+		t.Fatalf("GET /requests/{id} = %d:\n%s", code, body)
+	}
+
+	if code, _ := webCall(t, w, "GET", "/api/requests", "", ""); code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated list = %d, want 401", code)
+	}
+	if code, _ := webCall(t, w, "GET", "/api/requests", "00ff", ""); code != http.StatusUnauthorized {
+		t.Errorf("unknown key = %d, want 401", code)
+	}
+
+	code, body = webCall(t, w, "GET", "/api/requests", reviewer.SessionKey, "")
+	if code != http.StatusOK || !strings.Contains(body, `"id":"`+req.ID+`"`) || !strings.Contains(body, `"own":false`) {
+		t.Fatalf("list = %d:\n%s", code, body)
+	}
+	code, body = webCall(t, w, "GET", "/api/requests/"+req.ID, reviewer.SessionKey, "")
+	if code != http.StatusOK || !strings.Contains(body, `"command":"rm -rf ./build"`) {
+		t.Fatalf("detail = %d:\n%s", code, body)
+	}
+
+	if code, body := webCall(t, w, "POST", "/api/requests/"+req.ID+"/reject", reviewer.SessionKey, `{}`); code != http.StatusBadRequest {
+		t.Errorf("reject without reason = %d: %s", code, body)
+	}
+	if code, body := webCall(t, w, "POST", "/api/requests/"+req.ID+"/approve", requestor.SessionKey, `{}`); code != http.StatusConflict {
+		t.Errorf("self-approval = %d, want 409: %s", code, body)
+	}
+
+	code, body = webCall(t, w, "POST", "/api/requests/"+req.ID+"/approve", reviewer.SessionKey, `{"comments":"from the browser"}`)
+	if code != http.StatusOK || !strings.Contains(body, `"new_request_status":"approved"`) {
+		t.Fatalf("approve = %d:\n%s", code, body)
+	}
+	reviews, err := h.DB.ListReviewsForRequest(req.ID)
+	if err != nil || len(reviews) != 1 || reviews[0].ReviewerSessionID != reviewer.ID || reviews[0].Comments != "from the browser" {
+		t.Fatalf("expected the reviewer's approval, got %+v (%v)", reviews, err)
+	}
+}
+
+func TestWebServer_SudoTiersAndAllowedIPs(t *testing.T) {
+	h := testutil.NewHarness(t)
+	requestor := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	reviewer := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	req := testutil.MakeRequest(t, h.DB, requestor, testutil.WithRisk(db.RiskTierCritical))
+
+	cfg := config.DefaultConfig()
+	cfg.SudoMode.Enabled = true
+	cfg.SudoMode.Tiers = []string{"critical"}
+	w := newTestWebServer(t, h, cfg)
+	code, body := webCall(t, w, "POST", "/api/requests/"+req.ID+"/approve", reviewer.SessionKey, `{}`)
+	if code != http.StatusForbidden || !strings.Contains(body, "sudo mode") {
+		t.Errorf("approving a sudo tier = %d: %s", code, body)
+	}
+
+	cfg = config.DefaultConfig()
+	cfg.Daemon.WebAllowedIPs = []string{"10.0.0.0/8"}
+	w = newTestWebServer(t, h, cfg)
+	if code, _ := webCall(t, w, "GET", "/", "", ""); code != http.StatusForbidden {
+		t.Errorf("client outside web_allowed_ips = %d, want 403", code)
+	}
+}
+
+func TestIPCServer_SubscribeEventsResume(t *testing.T) {
+	srv, err := NewIPCServer(filepath.Join(shortSocketDir(t), "test.sock"), log.New(io.Discard))
+	if err != nil {
+		t.Fatalf("NewIPCServer: %v", err)
+	}
+	t.Cleanup(func() { _ = srv.Stop() })
+
+	live := srv.SubscribeEvents(-1)
+	defer live.Close()
+	for i := 0; i < 3; i++ {
+		srv.BroadcastEvent("test_event", i)
+	}
+	var seqs []int64
+	for i := 0; i < 3; i++ {
+		seqs = append(seqs, (<-live.Events()).Seq)
+	}
+	if seqs[1] != seqs[0]+1 || seqs[2] != seqs[1]+1 {
+		t.Fatalf("sequence numbers = %v, want consecutive", seqs)
+	}
+
+	resumed := srv.SubscribeEvents(seqs[0])
+	resumed.Close()
+	if resumed.Gap || len(resumed.Replay) != 2 || resumed.Replay[0].Seq != seqs[1] {
+		t.Errorf("resume after %d: gap=%v replay=%+v", seqs[0], resumed.Gap, resumed.Replay)
+	}
+	if ahead := srv.SubscribeEvents(seqs[2] + 10); !ahead.Gap || len(ahead.Replay) != 0 {
+		t.Errorf("resume ahead of the server: gap=%v replay=%d", ahead.Gap, len(ahead.Replay))
+	}
+
+	for i := 0; i < eventHistorySize; i++ {
+		srv.BroadcastEvent("test_event", i)
+	}
+	if old := srv.SubscribeEvents(seqs[0]); !old.Gap || len(old.Replay) != eventHistorySize {
+		t.Errorf("resume past the history: gap=%v replay=%d", old.Gap, len(old.Replay))
+	}
+}
+
+func TestWebServer_Events(t *testing.T) {
+	h := testutil.NewHarness(t)
+	sess := testutil.MakeSession(t, h.DB, testutil.WithProject(h.ProjectDir))
+	srv, err := NewIPCServer(filepath.Join(shortSocketDir(t), "test.sock"), log.New(io.Discard))
+	if err != nil {
+		t.Fatalf("NewIPCServer: %v", err)
+	}
+	t.Cleanup(func() { _ = srv.Stop() })
+
+	w := newTestWebServer(t, h, config.DefaultConfig())
+	if code, _ := webCall(t, w, "GET", "/api/events", sess.SessionKey, ""); code != http.StatusServiceUnavailable {
+		t.Errorf("events without an event source = %d, want 503", code)
+	}
+	w.opts.Events = srv
+	if code, _ := webCall(t, w, "GET", "/api/events", "", ""); code != http.StatusUnauthorized {
+		t.Errorf("events without a session key = %d, want 401", code)
+	}
+	if code, _ := webCall(t, w, "GET", "/api/events?since=soon", sess.SessionKey, ""); code != http.StatusBadRequest {
+		t.Errorf("events with a bad since = %d, want 400", code)
+	}
+
+	live := srv.SubscribeEvents(-1)
+	defer live.Close()
+	srv.BroadcastEvent("first", nil)
+	srv.BroadcastEvent("second", nil)
+	first := <-live.Events()
+
+	ts := httptest.NewServer(w.handler())
+	defer ts.Close()
+	r, _ := http.NewRequest("GET", ts.URL+"/api/events", nil)
+	r.Header.Set("Authorization", "Bearer "+sess.SessionKey)
+	r.Header.Set("Last-Event-ID", strconv.FormatInt(first.Seq, 10))
+	resp, err := http.DefaultClient.Do(r)
+	if err != nil {
+		t.Fatalf("GET /api/events: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+	next := func() string {
+		select {
+		case line := <-lines:
+			return line
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for an event")
+			return ""
+		}
+	}
+
+	want := []string{
+		"id: " + strconv.FormatInt(first.Seq+1, 10),
+		"event: second",
+	}
+	for _, line := range want {
+		if got := next(); got != line {
+			t.Fatalf("replayed line = %q, want %q", got, line)
+		}
+	}
+	if got := next(); !strings.HasPrefix(got, "data: ") || !strings.Contains(got, `"type":"second"`) {
+		t.Fatalf("replayed data = %q", got)
+	}
+	next()
+
+	srv.BroadcastEvent("third", map[string]string{"request_id": "r1"})
+	if got := next(); got != "id: "+strconv.FormatInt(first.Seq+2, 10) {
+		t.Fatalf("live line = %q", got)
+	}
+	if got := next(); got != "event: third" {
+		t.Fatalf("live event = %q", got)
+	}
+}
@@ -0,0 +1,115 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Annotation sources.
+const (
+	// AnnotationSourceLLM marks annotations produced by an LLM second-opinion reviewer.
+	AnnotationSourceLLM = "llm"
+	// AnnotationSourceAuto marks automatic decisions made by the daemon (e.g. tier auto-approval).
+	AnnotationSourceAuto = "auto"
+)
+
+// Annotation recommendations.
+const (
+	RecommendationApprove   = "approve"
+	RecommendationReject    = "reject"
+	RecommendationUncertain = "uncertain"
+)
+
+// RequestAnnotation is an advisory note attached to a request.
+// Annotations are visible to reviewers but never count as approvals.
+type RequestAnnotation struct {
+	// ID is the unique annotation identifier (auto-generated).
+	ID int64 `json:"id"`
+	// RequestID is the request this annotation belongs to.
+	RequestID string `json:"request_id"`
+	// Source identifies what produced the annotation (e.g. "llm").
+	Source string `json:"source"`
+	// Author is the model or tool name that produced the annotation.
+	Author string `json:"author,omitempty"`
+	// RiskSummary is a short description of the risk.
+	RiskSummary string `json:"risk_summary"`
+	// Recommendation is approve, reject, or uncertain.
+	Recommendation string `json:"recommendation"`
+	// CreatedAt is when the annotation was recorded.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NormalizeRecommendation maps a free-form recommendation onto a known value.
+// Unknown values become "uncertain".
+func NormalizeRecommendation(s string) string {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case RecommendationApprove, "approved", "allow":
+		return RecommendationApprove
+	case RecommendationReject, "rejected", "deny":
+		return RecommendationReject
+	default:
+		return RecommendationUncertain
+	}
+}
+
+// CreateAnnotation inserts an advisory annotation for a request.
+func (db *DB) CreateAnnotation(a *RequestAnnotation) error {
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = db.Now()
+	}
+	a.Recommendation = NormalizeRecommendation(a.Recommendation)
+
+	result, err := db.Exec(`
+		INSERT INTO request_annotations (
+			request_id, source, author, risk_summary, recommendation, created_at
+		) VALUES (?, ?, ?, ?, ?, ?)
+	`,
+		a.RequestID, a.Source, nullString(a.Author),
+		a.RiskSummary, a.Recommendation,
+		a.CreatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("creating annotation: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("getting annotation id: %w", err)
+	}
+	a.ID = id
+	return nil
+}
+
+// ListAnnotationsForRequest returns all annotations for a request, oldest first.
+func (db *DB) ListAnnotationsForRequest(requestID string) ([]*RequestAnnotation, error) {
+	rows, err := db.Query(`
+		SELECT id, request_id, source, author, risk_summary, recommendation, created_at
+		FROM request_annotations
+		WHERE request_id = ?
+		ORDER BY created_at ASC, id ASC
+	`, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("listing annotations: %w", err)
+	}
+	defer rows.Close()
+
+	var list []*RequestAnnotation
+	for rows.Next() {
+		a := &RequestAnnotation{}
+		var author sql.NullString
+		var created string
+		if err := rows.Scan(&a.ID, &a.RequestID, &a.Source, &author,
+			&a.RiskSummary, &a.Recommendation, &created); err != nil {
+			return nil, fmt.Errorf("scanning annotation: %w", err)
+		}
+		a.Author = author.String
+		a.CreatedAt, _ = time.Parse(time.RFC3339, created)
+		list = append(list, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
@@ -0,0 +1,93 @@
+// Package db tests for request annotation operations.
+package db
+
+import "testing"
+
+func TestCreateAndListAnnotations(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, req := createTestRequest(t, db)
+
+	first := &RequestAnnotation{
+		RequestID:      req.ID,
+		Source:         AnnotationSourceLLM,
+		Author:         "gpt-test",
+		RiskSummary:    "Deletes the build directory only",
+		Recommendation: "APPROVE",
+	}
+	if err := db.CreateAnnotation(first); err != nil {
+		t.Fatalf("CreateAnnotation failed: %v", err)
+	}
+	if first.ID == 0 {
+		t.Error("expected ID to be generated")
+	}
+	if first.CreatedAt.IsZero() {
+		t.Error("expected CreatedAt to be set")
+	}
+	if first.Recommendation != RecommendationApprove {
+		t.Errorf("expected normalized recommendation, got %q", first.Recommendation)
+	}
+
+	second := &RequestAnnotation{
+		RequestID:      req.ID,
+		Source:         AnnotationSourceLLM,
+		RiskSummary:    "Unclear",
+		Recommendation: "maybe",
+	}
+	if err := db.CreateAnnotation(second); err != nil {
+		t.Fatalf("CreateAnnotation failed: %v", err)
+	}
+
+	list, err := db.ListAnnotationsForRequest(req.ID)
+	if err != nil {
+		t.Fatalf("ListAnnotationsForRequest failed: %v", err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("expected 2 annotations, got %d", len(list))
+	}
+	if list[0].Author != "gpt-test" {
+		t.Errorf("expected author gpt-test, got %q", list[0].Author)
+	}
+	if list[1].Recommendation != RecommendationUncertain {
+		t.Errorf("expected uncertain, got %q", list[1].Recommendation)
+	}
+
+	// Annotations never affect review counts.
+	approvals, _, err := db.CountReviewsByDecision(req.ID)
+	if err != nil {
+		t.Fatalf("CountReviewsByDecision failed: %v", err)
+	}
+	if approvals != 0 {
+		t.Errorf("expected 0 approvals, got %d", approvals)
+	}
+}
+
+func TestListAnnotationsForRequest_Empty(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	list, err := db.ListAnnotationsForRequest("missing")
+	if err != nil {
+		t.Fatalf("ListAnnotationsForRequest failed: %v", err)
+	}
+	if len(list) != 0 {
+		t.Errorf("expected no annotations, got %d", len(list))
+	}
+}
+
+func TestNormalizeRecommendation(t *testing.T) {
+	cases := map[string]string{
+		"approve":  RecommendationApprove,
+		" Allow ":  RecommendationApprove,
+		"REJECT":   RecommendationReject,
+		"deny":     RecommendationReject,
+		"":         RecommendationUncertain,
+		"whatever": RecommendationUncertain,
+	}
+	for in, want := range cases {
+		if got := NormalizeRecommendation(in); got != want {
+			t.Errorf("NormalizeRecommendation(%q)=%q want %q", in, got, want)
+		}
+	}
+}
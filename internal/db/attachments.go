@@ -0,0 +1,48 @@
+package db
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"io"
+)
+
+// AttachmentEncodingGzip marks attachment content stored as base64-encoded gzip.
+// It is recorded in the attachment's "encoding" metadata.
+const AttachmentEncodingGzip = "gzip+base64"
+
+// CompressAttachmentContent gzips text and returns it base64-encoded.
+func CompressAttachmentContent(text string) (string, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(text)); err != nil {
+		return "", fmt.Errorf("compressing attachment: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return "", fmt.Errorf("compressing attachment: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// Text returns the attachment content, decompressing it if it was stored
+// with AttachmentEncodingGzip.
+func (a Attachment) Text() (string, error) {
+	if enc, _ := a.Metadata["encoding"].(string); enc != AttachmentEncodingGzip {
+		return a.Content, nil
+	}
+	raw, err := base64.StdEncoding.DecodeString(a.Content)
+	if err != nil {
+		return "", fmt.Errorf("decoding attachment: %w", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		return "", fmt.Errorf("decompressing attachment: %w", err)
+	}
+	defer zr.Close()
+	text, err := io.ReadAll(zr)
+	if err != nil {
+		return "", fmt.Errorf("decompressing attachment: %w", err)
+	}
+	return string(text), nil
+}
@@ -0,0 +1,38 @@
+// Package db tests for attachment content encoding.
+package db
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAttachmentTextRoundTrip(t *testing.T) {
+	text := strings.Repeat("user: please clean the build dir\n", 20)
+	encoded, err := CompressAttachmentContent(text)
+	if err != nil {
+		t.Fatalf("CompressAttachmentContent: %v", err)
+	}
+	if len(encoded) >= len(text) {
+		t.Errorf("expected compressed content to be smaller: %d >= %d", len(encoded), len(text))
+	}
+
+	att := Attachment{
+		Type:     AttachmentTypeTranscript,
+		Content:  encoded,
+		Metadata: map[string]any{"encoding": AttachmentEncodingGzip},
+	}
+	got, err := att.Text()
+	if err != nil || got != text {
+		t.Fatalf("Text() = %q, %v; want original text", got, err)
+	}
+
+	plain := Attachment{Type: AttachmentTypeFile, Content: "hello"}
+	if got, err := plain.Text(); err != nil || got != "hello" {
+		t.Fatalf("plain Text() = %q, %v", got, err)
+	}
+
+	bad := Attachment{Content: "not base64!", Metadata: map[string]any{"encoding": AttachmentEncodingGzip}}
+	if _, err := bad.Text(); err == nil {
+		t.Fatal("expected error for corrupt content")
+	}
+}
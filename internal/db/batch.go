@@ -0,0 +1,235 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultFlushInterval is how often a BufferedEventWriter flushes.
+const DefaultFlushInterval = 100 * time.Millisecond
+
+// DefaultMaxBufferedEvents is how many queued events trigger an early flush.
+const DefaultMaxBufferedEvents = 256
+
+// maxBufferFactor bounds the queue at this multiple of MaxBuffered while
+// flushes fail; the oldest events are dropped beyond it.
+const maxBufferFactor = 4
+
+// BufferedWriterOptions configures a BufferedEventWriter.
+type BufferedWriterOptions struct {
+	// FlushInterval is how often queued writes are committed.
+	// DefaultFlushInterval when zero.
+	FlushInterval time.Duration
+	// MaxBuffered queued events trigger a flush before the interval.
+	// DefaultMaxBufferedEvents when zero.
+	MaxBuffered int
+}
+
+// BufferedWriterStats reports what a BufferedEventWriter has written.
+type BufferedWriterStats struct {
+	Flushes    int64 `json:"flushes"`
+	Events     int64 `json:"events"`
+	Heartbeats int64 `json:"heartbeats"`
+	Dropped    int64 `json:"dropped"`
+	Errors     int64 `json:"errors"`
+	Pending    int   `json:"pending"`
+}
+
+// BufferedEventWriter queues high-frequency writes (daemon events and session
+// heartbeats) and commits them together in one transaction per interval, so
+// dozens of active agents cost one fsync every FlushInterval instead of one
+// per write. Heartbeats for the same session within an interval collapse to
+// the latest one.
+type BufferedEventWriter struct {
+	db          *DB
+	interval    time.Duration
+	maxBuffered int
+
+	mu         sync.Mutex
+	events     []*DaemonEvent
+	heartbeats map[string]time.Time
+
+	// flushMu serializes flushes so batches commit in queue order.
+	flushMu sync.Mutex
+	kick    chan struct{}
+	stop    chan struct{}
+	done    chan struct{}
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	started   atomic.Bool
+
+	flushes       atomic.Int64
+	eventsWritten atomic.Int64
+	beatsWritten  atomic.Int64
+	dropped       atomic.Int64
+	errors        atomic.Int64
+}
+
+// NewBufferedEventWriter creates a writer for db. Call Start to flush in the
+// background and Stop to flush what remains.
+func NewBufferedEventWriter(db *DB, opts BufferedWriterOptions) *BufferedEventWriter {
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = DefaultFlushInterval
+	}
+	if opts.MaxBuffered <= 0 {
+		opts.MaxBuffered = DefaultMaxBufferedEvents
+	}
+	return &BufferedEventWriter{
+		db:          db,
+		interval:    opts.FlushInterval,
+		maxBuffered: opts.MaxBuffered,
+		heartbeats:  make(map[string]time.Time),
+		kick:        make(chan struct{}, 1),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// RecordEvent queues an event. Its ID is set once it is flushed.
+func (w *BufferedEventWriter) RecordEvent(e *DaemonEvent) {
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = w.db.Now()
+	}
+
+	w.mu.Lock()
+	w.events = append(w.events, e)
+	if limit := w.maxBuffered * maxBufferFactor; len(w.events) > limit {
+		over := len(w.events) - limit
+		w.events = append(w.events[:0:0], w.events[over:]...)
+		w.dropped.Add(int64(over))
+	}
+	full := len(w.events) >= w.maxBuffered
+	w.mu.Unlock()
+
+	if full {
+		select {
+		case w.kick <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Heartbeat queues a last_active_at update for a session.
+func (w *BufferedEventWriter) Heartbeat(sessionID string, at time.Time) {
+	if at.IsZero() {
+		at = w.db.Now()
+	}
+	w.mu.Lock()
+	if prev, ok := w.heartbeats[sessionID]; !ok || at.After(prev) {
+		w.heartbeats[sessionID] = at
+	}
+	w.mu.Unlock()
+}
+
+// Flush commits everything queued in a single transaction. On failure the
+// batch is put back and retried on the next flush.
+func (w *BufferedEventWriter) Flush() error {
+	w.flushMu.Lock()
+	defer w.flushMu.Unlock()
+
+	w.mu.Lock()
+	events := w.events
+	heartbeats := w.heartbeats
+	w.events = nil
+	w.heartbeats = make(map[string]time.Time)
+	w.mu.Unlock()
+
+	if len(events) == 0 && len(heartbeats) == 0 {
+		return nil
+	}
+
+	err := w.db.Transaction(func(tx *sql.Tx) error {
+		if err := insertDaemonEvents(tx, events); err != nil {
+			return err
+		}
+		return updateSessionHeartbeats(tx, heartbeats)
+	})
+	if err != nil {
+		w.errors.Add(1)
+		w.requeue(events, heartbeats)
+		return err
+	}
+
+	w.flushes.Add(1)
+	w.eventsWritten.Add(int64(len(events)))
+	w.beatsWritten.Add(int64(len(heartbeats)))
+	return nil
+}
+
+// requeue puts a failed batch back ahead of anything queued since.
+func (w *BufferedEventWriter) requeue(events []*DaemonEvent, heartbeats map[string]time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.events = append(events, w.events...)
+	if limit := w.maxBuffered * maxBufferFactor; len(w.events) > limit {
+		over := len(w.events) - limit
+		w.events = w.events[over:]
+		w.dropped.Add(int64(over))
+	}
+	for id, at := range heartbeats {
+		if prev, ok := w.heartbeats[id]; !ok || at.After(prev) {
+			w.heartbeats[id] = at
+		}
+	}
+}
+
+// Start flushes every FlushInterval (or sooner when MaxBuffered events are
+// queued) until ctx is done or Stop is called.
+func (w *BufferedEventWriter) Start(ctx context.Context) {
+	w.startOnce.Do(func() {
+		w.started.Store(true)
+		go w.loop(ctx)
+	})
+}
+
+// Stop ends the background loop and flushes what remains.
+func (w *BufferedEventWriter) Stop() error {
+	w.stopOnce.Do(func() {
+		close(w.stop)
+	})
+	if w.started.Load() {
+		<-w.done
+	}
+	return w.Flush()
+}
+
+func (w *BufferedEventWriter) loop(ctx context.Context) {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			_ = w.Flush()
+			return
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			_ = w.Flush()
+		case <-w.kick:
+			_ = w.Flush()
+		}
+	}
+}
+
+// Stats returns write counters and the number of queued writes.
+func (w *BufferedEventWriter) Stats() BufferedWriterStats {
+	w.mu.Lock()
+	pending := len(w.events) + len(w.heartbeats)
+	w.mu.Unlock()
+	return BufferedWriterStats{
+		Flushes:    w.flushes.Load(),
+		Events:     w.eventsWritten.Load(),
+		Heartbeats: w.beatsWritten.Load(),
+		Dropped:    w.dropped.Load(),
+		Errors:     w.errors.Load(),
+		Pending:    pending,
+	}
+}
@@ -0,0 +1,244 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestInsertAndListDaemonEvents(t *testing.T) {
+	db := setupTestDB(t)
+
+	events := []*DaemonEvent{
+		{Type: "request_created", PayloadJSON: `{"id":"a"}`},
+		{Type: "request_approved"},
+	}
+	if err := db.InsertDaemonEvents(events); err != nil {
+		t.Fatalf("InsertDaemonEvents failed: %v", err)
+	}
+	if events[0].ID == 0 || events[1].ID <= events[0].ID {
+		t.Fatalf("expected increasing IDs, got %d and %d", events[0].ID, events[1].ID)
+	}
+
+	got, err := db.ListDaemonEvents(0, 10)
+	if err != nil {
+		t.Fatalf("ListDaemonEvents failed: %v", err)
+	}
+	if len(got) != 2 || got[0].Type != "request_created" || got[0].PayloadJSON != `{"id":"a"}` || got[1].PayloadJSON != "" {
+		t.Fatalf("unexpected events: %+v", got)
+	}
+
+	after, err := db.ListDaemonEvents(events[0].ID, 10)
+	if err != nil {
+		t.Fatalf("ListDaemonEvents failed: %v", err)
+	}
+	if len(after) != 1 || after[0].ID != events[1].ID {
+		t.Fatalf("expected only the second event, got %+v", after)
+	}
+
+	if err := db.InsertDaemonEvents([]*DaemonEvent{{Type: "ok"}, {}}); err == nil {
+		t.Fatal("expected an error for an event without a type")
+	}
+	if got, _ := db.ListDaemonEvents(0, 10); len(got) != 2 {
+		t.Fatalf("expected the failed batch to roll back, got %d events", len(got))
+	}
+}
+
+func TestProbeWriteRead(t *testing.T) {
+	db := setupTestDB(t)
+
+	if err := db.ProbeWriteRead(); err != nil {
+		t.Fatalf("ProbeWriteRead failed: %v", err)
+	}
+	if got, _ := db.ListDaemonEvents(0, 10); len(got) != 0 {
+		t.Fatalf("expected the probe to leave no events, got %+v", got)
+	}
+
+	db.Close()
+	if err := db.ProbeWriteRead(); err == nil {
+		t.Fatal("expected an error from a closed database")
+	}
+}
+
+func TestUpdateSessionHeartbeats(t *testing.T) {
+	db := setupTestDB(t)
+	active := benchSession(t, db, "Active")
+	ended := benchSession(t, db, "Ended")
+	if err := db.EndSession(ended.ID); err != nil {
+		t.Fatalf("EndSession failed: %v", err)
+	}
+
+	at := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	err := db.UpdateSessionHeartbeats(map[string]time.Time{
+		active.ID: at,
+		ended.ID:  at,
+		"missing": at,
+	})
+	if err != nil {
+		t.Fatalf("UpdateSessionHeartbeats failed: %v", err)
+	}
+
+	got, err := db.GetSession(active.ID)
+	if err != nil {
+		t.Fatalf("GetSession failed: %v", err)
+	}
+	if !got.LastActiveAt.Equal(at) {
+		t.Errorf("last_active_at = %v, want %v", got.LastActiveAt, at)
+	}
+	got, _ = db.GetSession(ended.ID)
+	if got.LastActiveAt.Equal(at) {
+		t.Error("expected ended session to be left alone")
+	}
+}
+
+func TestBufferedEventWriter_FlushBatches(t *testing.T) {
+	db := setupTestDB(t)
+	sess := benchSession(t, db, "Beat")
+	w := NewBufferedEventWriter(db, BufferedWriterOptions{FlushInterval: time.Hour})
+
+	for i := 0; i < 10; i++ {
+		w.RecordEvent(&DaemonEvent{Type: fmt.Sprintf("event_%d", i)})
+	}
+	early := time.Now().Add(time.Minute).UTC().Truncate(time.Second)
+	late := early.Add(time.Minute)
+	w.Heartbeat(sess.ID, late)
+	w.Heartbeat(sess.ID, early)
+
+	if stats := w.Stats(); stats.Pending != 11 || stats.Flushes != 0 {
+		t.Fatalf("expected 11 queued writes before flush, got %+v", stats)
+	}
+	if got, _ := db.ListDaemonEvents(0, 100); len(got) != 0 {
+		t.Fatalf("expected nothing written before flush, got %d events", len(got))
+	}
+
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	got, err := db.ListDaemonEvents(0, 100)
+	if err != nil {
+		t.Fatalf("ListDaemonEvents failed: %v", err)
+	}
+	if len(got) != 10 || got[0].Type != "event_0" || got[9].Type != "event_9" {
+		t.Fatalf("expected events in queue order, got %+v", got)
+	}
+	s, _ := db.GetSession(sess.ID)
+	if !s.LastActiveAt.Equal(late) {
+		t.Errorf("expected heartbeats to collapse to the latest, got %v want %v", s.LastActiveAt, late)
+	}
+
+	stats := w.Stats()
+	if stats.Flushes != 1 || stats.Events != 10 || stats.Heartbeats != 1 || stats.Pending != 0 || stats.Errors != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	// An empty flush does nothing.
+	if err := w.Flush(); err != nil || w.Stats().Flushes != 1 {
+		t.Errorf("expected empty flush to be a no-op, err=%v stats=%+v", err, w.Stats())
+	}
+}
+
+func TestBufferedEventWriter_RequeuesOnError(t *testing.T) {
+	db := setupTestDB(t)
+	w := NewBufferedEventWriter(db, BufferedWriterOptions{MaxBuffered: 2})
+
+	w.RecordEvent(&DaemonEvent{Type: "kept"})
+	w.RecordEvent(&DaemonEvent{})
+	if err := w.Flush(); err == nil {
+		t.Fatal("expected flush to fail for an event without a type")
+	}
+	stats := w.Stats()
+	if stats.Errors != 1 || stats.Pending != 2 {
+		t.Fatalf("expected failed batch to be requeued, got %+v", stats)
+	}
+
+	// The queue is bounded while flushes keep failing.
+	for i := 0; i < 20; i++ {
+		w.RecordEvent(&DaemonEvent{Type: "more"})
+	}
+	stats = w.Stats()
+	if stats.Pending != 2*maxBufferFactor || stats.Dropped != 22-2*maxBufferFactor {
+		t.Fatalf("expected queue capped at %d, got %+v", 2*maxBufferFactor, stats)
+	}
+}
+
+func TestBufferedEventWriter_StartStop(t *testing.T) {
+	db := setupTestDB(t)
+	w := NewBufferedEventWriter(db, BufferedWriterOptions{FlushInterval: 10 * time.Millisecond, MaxBuffered: 5})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w.Start(ctx)
+
+	for i := 0; i < 5; i++ {
+		w.RecordEvent(&DaemonEvent{Type: "burst"})
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for w.Stats().Events < 5 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected background flush, got %+v", w.Stats())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	w.RecordEvent(&DaemonEvent{Type: "last"})
+	if err := w.Stop(); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	if got, _ := db.ListDaemonEvents(0, 100); len(got) != 6 {
+		t.Fatalf("expected Stop to flush the remainder, got %d events", len(got))
+	}
+	// Stop is idempotent.
+	if err := w.Stop(); err != nil {
+		t.Fatalf("second Stop failed: %v", err)
+	}
+}
+
+func TestBufferedEventWriter_StopWithoutStart(t *testing.T) {
+	db := setupTestDB(t)
+	w := NewBufferedEventWriter(db, BufferedWriterOptions{})
+	w.RecordEvent(&DaemonEvent{Type: "only"})
+	if err := w.Stop(); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	if w.Stats().Events != 1 {
+		t.Fatalf("expected Stop to flush, got %+v", w.Stats())
+	}
+}
+
+func BenchmarkHeartbeat_Direct(b *testing.B) {
+	db := setupTestDB(b)
+	sessions := benchSessions(b, db, 24)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := db.UpdateSessionHeartbeat(sessions[i%len(sessions)].ID); err != nil {
+			b.Fatalf("UpdateSessionHeartbeat failed: %v", err)
+		}
+	}
+}
+
+func BenchmarkHeartbeat_Buffered(b *testing.B) {
+	db := setupTestDB(b)
+	sessions := benchSessions(b, db, 24)
+	w := NewBufferedEventWriter(db, BufferedWriterOptions{FlushInterval: time.Hour})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w.Heartbeat(sessions[i%len(sessions)].ID, time.Time{})
+		w.RecordEvent(&DaemonEvent{Type: "heartbeat"})
+		if i%DefaultMaxBufferedEvents == DefaultMaxBufferedEvents-1 {
+			if err := w.Flush(); err != nil {
+				b.Fatalf("Flush failed: %v", err)
+			}
+		}
+	}
+	if err := w.Flush(); err != nil {
+		b.Fatalf("Flush failed: %v", err)
+	}
+}
+
+func benchSessions(b testing.TB, db *DB, n int) []*Session {
+	sessions := make([]*Session, n)
+	for i := range sessions {
+		sessions[i] = benchSession(b, db, fmt.Sprintf("Agent%d", i))
+	}
+	return sessions
+}
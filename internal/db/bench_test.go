@@ -0,0 +1,129 @@
+package db
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func benchRequest(sess *Session) *Request {
+	return &Request{
+		ProjectPath:        sess.ProjectPath,
+		RequestorSessionID: sess.ID,
+		RequestorAgent:     sess.AgentName,
+		RequestorModel:     sess.Model,
+		RiskTier:           RiskTierDangerous,
+		MinApprovals:       1,
+		Command: CommandSpec{
+			Raw:  "rm -rf ./build",
+			Cwd:  sess.ProjectPath,
+			Argv: []string{"rm", "-rf", "./build"},
+		},
+		Justification: Justification{Reason: "Clean build directory"},
+	}
+}
+
+func benchSession(b testing.TB, db *DB, name string) *Session {
+	b.Helper()
+	sess := &Session{
+		AgentName:   name,
+		Program:     "claude-code",
+		Model:       "opus-4.5",
+		ProjectPath: "/test/project",
+	}
+	if err := db.CreateSession(sess); err != nil {
+		b.Fatalf("CreateSession failed: %v", err)
+	}
+	return sess
+}
+
+func BenchmarkCreateRequest(b *testing.B) {
+	db := setupTestDB(b)
+	defer db.Close()
+	sess := benchSession(b, db, "Requestor")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := db.CreateRequest(benchRequest(sess)); err != nil {
+			b.Fatalf("CreateRequest failed: %v", err)
+		}
+	}
+}
+
+func BenchmarkCreateReview(b *testing.B) {
+	db := setupTestDB(b)
+	defer db.Close()
+	requestor := benchSession(b, db, "Requestor")
+	reviewer := benchSession(b, db, "Reviewer")
+
+	reqs := make([]*Request, b.N)
+	for i := range reqs {
+		reqs[i] = benchRequest(requestor)
+		if err := db.CreateRequest(reqs[i]); err != nil {
+			b.Fatalf("CreateRequest failed: %v", err)
+		}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		now := time.Now().UTC()
+		review := &Review{
+			RequestID:          reqs[i].ID,
+			ReviewerSessionID:  reviewer.ID,
+			ReviewerAgent:      reviewer.AgentName,
+			ReviewerModel:      reviewer.Model,
+			Decision:           DecisionApprove,
+			Signature:          ComputeReviewSignature(reviewer.SessionKey, reqs[i].ID, DecisionApprove, now),
+			SignatureTimestamp: now,
+		}
+		if err := db.CreateReview(review); err != nil {
+			b.Fatalf("CreateReview failed: %v", err)
+		}
+	}
+}
+
+func BenchmarkUpdateRequestStatus(b *testing.B) {
+	db := setupTestDB(b)
+	defer db.Close()
+	sess := benchSession(b, db, "Requestor")
+
+	ids := make([]string, b.N)
+	for i := range ids {
+		r := benchRequest(sess)
+		if err := db.CreateRequest(r); err != nil {
+			b.Fatalf("CreateRequest failed: %v", err)
+		}
+		ids[i] = r.ID
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := db.UpdateRequestStatus(ids[i], StatusApproved); err != nil {
+			b.Fatalf("UpdateRequestStatus failed: %v", err)
+		}
+	}
+}
+
+func BenchmarkListPendingRequests(b *testing.B) {
+	db := setupTestDB(b)
+	defer db.Close()
+	sess := benchSession(b, db, "Requestor")
+	for i := 0; i < 500; i++ {
+		r := benchRequest(sess)
+		r.Command.Raw = fmt.Sprintf("rm -rf ./build-%d", i)
+		if err := db.CreateRequest(r); err != nil {
+			b.Fatalf("CreateRequest failed: %v", err)
+		}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := db.ListPendingRequests(sess.ProjectPath); err != nil {
+			b.Fatalf("ListPendingRequests failed: %v", err)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrBinaryNotFound indicates no executable was recorded for a request.
+var ErrBinaryNotFound = errors.New("request binary not found")
+
+// RequestBinary records which executable a request's command would run, so
+// reviewers can tell when e.g. `terraform` is a wrapper script in the repo
+// rather than the real tool.
+type RequestBinary struct {
+	// RequestID is the request this binary belongs to.
+	RequestID string `json:"request_id"`
+	// Name is the command's first token as written (e.g. terraform).
+	Name string `json:"name"`
+	// Path is the absolute path the name resolved to.
+	Path string `json:"path"`
+	// RealPath is Path with symlinks resolved, when it differs.
+	RealPath string `json:"real_path,omitempty"`
+	// SHA256 is the hex digest of the file, when it could be read.
+	SHA256 string `json:"sha256,omitempty"`
+	// InProject is set when the file lives inside the project, such as
+	// ./node_modules/.bin or ./bin.
+	InProject bool `json:"in_project"`
+	// Interpreter is the script's #! line, empty for native binaries.
+	Interpreter string `json:"interpreter,omitempty"`
+	// CreatedAt is when the binary was resolved.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// IsScript reports whether the executable is a script run by an interpreter.
+func (b *RequestBinary) IsScript() bool {
+	return b.Interpreter != ""
+}
+
+// SetRequestBinary records (or replaces) the executable for a request.
+func (db *DB) SetRequestBinary(b *RequestBinary) error {
+	if b.RequestID == "" {
+		return fmt.Errorf("request binary requires request id")
+	}
+	if b.CreatedAt.IsZero() {
+		b.CreatedAt = db.Now()
+	}
+	return insertRequestBinary(db, b)
+}
+
+func insertRequestBinary(x execer, b *RequestBinary) error {
+	_, err := x.Exec(`
+		INSERT OR REPLACE INTO request_binaries (
+			request_id, name, path, real_path, sha256, in_project, interpreter, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, b.RequestID, b.Name, b.Path, nullString(b.RealPath), nullString(b.SHA256),
+		boolToInt(b.InProject), nullString(b.Interpreter), b.CreatedAt.Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("recording request binary: %w", err)
+	}
+	return nil
+}
+
+// GetRequestBinary returns the executable recorded for a request.
+func (db *DB) GetRequestBinary(requestID string) (*RequestBinary, error) {
+	b := &RequestBinary{}
+	var realPath, sum, interpreter sql.NullString
+	var inProject int
+	var created string
+	err := db.QueryRow(`
+		SELECT request_id, name, path, real_path, sha256, in_project, interpreter, created_at
+		FROM request_binaries
+		WHERE request_id = ?
+	`, requestID).Scan(&b.RequestID, &b.Name, &b.Path, &realPath, &sum, &inProject, &interpreter, &created)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrBinaryNotFound
+		}
+		return nil, fmt.Errorf("getting request binary: %w", err)
+	}
+	b.RealPath = realPath.String
+	b.SHA256 = sum.String
+	b.InProject = inProject != 0
+	b.Interpreter = interpreter.String
+	b.CreatedAt, _ = time.Parse(time.RFC3339, created)
+	return b, nil
+}
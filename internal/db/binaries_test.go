@@ -0,0 +1,51 @@
+package db
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRequestBinary(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, req := createTestRequest(t, db)
+
+	if _, err := db.GetRequestBinary(req.ID); !errors.Is(err, ErrBinaryNotFound) {
+		t.Fatalf("expected ErrBinaryNotFound, got %v", err)
+	}
+	if err := db.SetRequestBinary(&RequestBinary{Name: "terraform", Path: "/usr/bin/terraform"}); err == nil {
+		t.Fatal("expected error without request id")
+	}
+
+	b := &RequestBinary{
+		RequestID:   req.ID,
+		Name:        "terraform",
+		Path:        "/repo/bin/terraform",
+		RealPath:    "/repo/tools/tf.sh",
+		SHA256:      "abc123",
+		InProject:   true,
+		Interpreter: "#!/bin/sh",
+	}
+	if err := db.SetRequestBinary(b); err != nil {
+		t.Fatalf("SetRequestBinary failed: %v", err)
+	}
+
+	got, err := db.GetRequestBinary(req.ID)
+	if err != nil {
+		t.Fatalf("GetRequestBinary failed: %v", err)
+	}
+	if got.Name != b.Name || got.Path != b.Path || got.RealPath != b.RealPath || got.SHA256 != b.SHA256 ||
+		!got.InProject || got.Interpreter != b.Interpreter || !got.IsScript() || got.CreatedAt.IsZero() {
+		t.Fatalf("unexpected binary: %+v", got)
+	}
+
+	// Recording again replaces the previous binary.
+	if err := db.SetRequestBinary(&RequestBinary{RequestID: req.ID, Name: "terraform", Path: "/usr/bin/terraform"}); err != nil {
+		t.Fatalf("SetRequestBinary (replace) failed: %v", err)
+	}
+	got, err = db.GetRequestBinary(req.ID)
+	if err != nil || got.Path != "/usr/bin/terraform" || got.InProject || got.IsScript() || got.RealPath != "" {
+		t.Fatalf("expected replaced binary, got %+v (err %v)", got, err)
+	}
+}
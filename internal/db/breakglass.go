@@ -0,0 +1,258 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrBreakglassNotFound indicates the break-glass incident does not exist.
+var ErrBreakglassNotFound = errors.New("break-glass incident not found")
+
+// ErrBreakglassSelfAck indicates an actor tried to record the postmortem for
+// their own break-glass incident.
+var ErrBreakglassSelfAck = errors.New("a break-glass incident must be acknowledged by someone other than its actor")
+
+// BreakglassIncident records a command executed immediately without approval.
+// Every incident must be acknowledged with a postmortem before AckDueAt.
+type BreakglassIncident struct {
+	// ID is the unique incident identifier (UUID).
+	ID string `json:"id"`
+	// ProjectPath is the project the command ran in.
+	ProjectPath string `json:"project_path"`
+	// Actor is who triggered the break-glass execution, as they named
+	// themselves at the terminal.
+	Actor string `json:"actor"`
+	// ActorOSUser is the operating-system account that ran the execution.
+	ActorOSUser string `json:"actor_os_user,omitempty"`
+	// Command is the raw command that was executed.
+	Command string `json:"command"`
+	// CommandHash is the SHA256 of the command.
+	CommandHash string `json:"command_hash"`
+	// Cwd is the working directory of the execution.
+	Cwd string `json:"cwd,omitempty"`
+	// Reason explains why approval was bypassed.
+	Reason string `json:"reason"`
+	// ExitCode is the command's exit code once it finished.
+	ExitCode *int `json:"exit_code,omitempty"`
+	// LogPath is the execution log.
+	LogPath string `json:"log_path,omitempty"`
+	// ExecutedAt is when the command was started.
+	ExecutedAt time.Time `json:"executed_at"`
+	// AckDueAt is the deadline for the postmortem acknowledgment.
+	AckDueAt time.Time `json:"ack_due_at"`
+	// AcknowledgedAt is when the postmortem was recorded.
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+	// AcknowledgedBy is who recorded the postmortem.
+	AcknowledgedBy string `json:"acknowledged_by,omitempty"`
+	// AcknowledgedByOSUser is the operating-system account that recorded
+	// the postmortem.
+	AcknowledgedByOSUser string `json:"acknowledged_by_os_user,omitempty"`
+	// Postmortem is the follow-up note explaining the incident.
+	Postmortem string `json:"postmortem,omitempty"`
+}
+
+// IsOverdue reports whether the acknowledgment deadline passed without a postmortem.
+func (b *BreakglassIncident) IsOverdue(now time.Time) bool {
+	return b.AcknowledgedAt == nil && now.After(b.AckDueAt)
+}
+
+// CreateBreakglassIncident records a new break-glass incident.
+func (db *DB) CreateBreakglassIncident(b *BreakglassIncident) error {
+	return db.CreateBreakglassIncidentIfAllowed(b, nil)
+}
+
+// CreateBreakglassIncidentIfAllowed records a new break-glass incident if
+// allow accepts the project's previous incidents, newest first. The check and
+// the insert run in one transaction, so concurrent break-glass executions
+// cannot both pass a cooldown.
+func (db *DB) CreateBreakglassIncidentIfAllowed(b *BreakglassIncident, allow func(previous []*BreakglassIncident) error) error {
+	if b.ProjectPath == "" || b.Actor == "" || b.Command == "" || b.Reason == "" {
+		return fmt.Errorf("break-glass incident requires project, actor, command and reason")
+	}
+	if b.ID == "" {
+		b.ID = uuid.New().String()
+	}
+	if b.ExecutedAt.IsZero() {
+		b.ExecutedAt = db.Now()
+	}
+	if b.AckDueAt.IsZero() {
+		b.AckDueAt = b.ExecutedAt.Add(24 * time.Hour)
+	}
+
+	return db.Transaction(func(tx *sql.Tx) error {
+		if allow != nil {
+			rows, err := tx.Query(breakglassSelect+` WHERE project_path = ? ORDER BY executed_at DESC`, b.ProjectPath)
+			if err != nil {
+				return fmt.Errorf("listing break-glass incidents: %w", err)
+			}
+			previous, err := scanBreakglassIncidents(rows)
+			rows.Close()
+			if err != nil {
+				return err
+			}
+			if err := allow(previous); err != nil {
+				return err
+			}
+		}
+
+		_, err := tx.Exec(`
+			INSERT INTO breakglass_incidents (
+				id, project_path, actor, actor_os_user, command, command_hash, cwd, reason,
+				exit_code, log_path, executed_at, ack_due_at,
+				acknowledged_at, acknowledged_by, acknowledged_by_os_user, postmortem
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`,
+			b.ID, b.ProjectPath, b.Actor, nullString(b.ActorOSUser), b.Command, b.CommandHash, nullString(b.Cwd), b.Reason,
+			nullInt(b.ExitCode), nullString(b.LogPath),
+			b.ExecutedAt.UTC().Format(time.RFC3339), b.AckDueAt.UTC().Format(time.RFC3339),
+			formatTimePtr(b.AcknowledgedAt), nullString(b.AcknowledgedBy), nullString(b.AcknowledgedByOSUser), nullString(b.Postmortem),
+		)
+		if err != nil {
+			return fmt.Errorf("creating break-glass incident: %w", err)
+		}
+		return nil
+	})
+}
+
+// UpdateBreakglassResult records the outcome of a break-glass execution.
+func (db *DB) UpdateBreakglassResult(id string, exitCode *int, logPath string) error {
+	result, err := db.Exec(`
+		UPDATE breakglass_incidents SET exit_code = ?, log_path = ? WHERE id = ?
+	`, nullInt(exitCode), nullString(logPath), id)
+	if err != nil {
+		return fmt.Errorf("updating break-glass incident: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrBreakglassNotFound
+	}
+	return nil
+}
+
+// AcknowledgeBreakglass records the postmortem for an incident, by the
+// human named by from the operating-system account osUser. It is refused
+// when by matches the incident's actor ignoring case and spacing, or when
+// both runs came from the same account: names are typed by the humans
+// themselves, the account is not.
+func (db *DB) AcknowledgeBreakglass(id, by, osUser, postmortem string) error {
+	if by == "" || postmortem == "" {
+		return fmt.Errorf("acknowledgment requires an actor and a postmortem note")
+	}
+	return db.Transaction(func(tx *sql.Tx) error {
+		rows, err := tx.Query(breakglassSelect+` WHERE id = ?`, id)
+		if err != nil {
+			return fmt.Errorf("getting break-glass incident: %w", err)
+		}
+		list, err := scanBreakglassIncidents(rows)
+		rows.Close()
+		if err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return ErrBreakglassNotFound
+		}
+		inc := list[0]
+		if inc.AcknowledgedAt != nil {
+			return fmt.Errorf("break-glass incident %s is already acknowledged", id)
+		}
+		if normalizeActorName(inc.Actor) == normalizeActorName(by) ||
+			(inc.ActorOSUser != "" && inc.ActorOSUser == osUser) {
+			return ErrBreakglassSelfAck
+		}
+		_, err = tx.Exec(`
+			UPDATE breakglass_incidents
+			SET acknowledged_at = ?, acknowledged_by = ?, acknowledged_by_os_user = ?, postmortem = ?
+			WHERE id = ? AND acknowledged_at IS NULL
+		`, db.Now().Format(time.RFC3339), by, nullString(osUser), postmortem, id)
+		if err != nil {
+			return fmt.Errorf("acknowledging break-glass incident: %w", err)
+		}
+		return nil
+	})
+}
+
+// normalizeActorName folds case and runs of whitespace so "Jane  Doe" and
+// "jane doe" compare as the same name.
+func normalizeActorName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// GetBreakglassIncident retrieves an incident by ID.
+func (db *DB) GetBreakglassIncident(id string) (*BreakglassIncident, error) {
+	rows, err := db.Query(breakglassSelect+` WHERE id = ?`, id)
+	if err != nil {
+		return nil, fmt.Errorf("getting break-glass incident: %w", err)
+	}
+	defer rows.Close()
+	list, err := scanBreakglassIncidents(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, ErrBreakglassNotFound
+	}
+	return list[0], nil
+}
+
+// ListBreakglassIncidents returns incidents for a project, newest first.
+// When unacknowledgedOnly is true only incidents still awaiting a postmortem are returned.
+func (db *DB) ListBreakglassIncidents(projectPath string, unacknowledgedOnly bool) ([]*BreakglassIncident, error) {
+	query := breakglassSelect + ` WHERE project_path = ?`
+	if unacknowledgedOnly {
+		query += ` AND acknowledged_at IS NULL`
+	}
+	query += ` ORDER BY executed_at DESC`
+
+	rows, err := db.Query(query, projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("listing break-glass incidents: %w", err)
+	}
+	defer rows.Close()
+	return scanBreakglassIncidents(rows)
+}
+
+const breakglassSelect = `
+	SELECT id, project_path, actor, actor_os_user, command, command_hash, cwd, reason,
+	       exit_code, log_path, executed_at, ack_due_at,
+	       acknowledged_at, acknowledged_by, acknowledged_by_os_user, postmortem
+	FROM breakglass_incidents`
+
+func scanBreakglassIncidents(rows *sql.Rows) ([]*BreakglassIncident, error) {
+	var list []*BreakglassIncident
+	for rows.Next() {
+		b := &BreakglassIncident{}
+		var actorOSUser, cwd, logPath, ackAt, ackBy, ackByOSUser, postmortem sql.NullString
+		var exitCode sql.NullInt64
+		var executedAt, ackDueAt string
+		if err := rows.Scan(&b.ID, &b.ProjectPath, &b.Actor, &actorOSUser, &b.Command, &b.CommandHash, &cwd, &b.Reason,
+			&exitCode, &logPath, &executedAt, &ackDueAt, &ackAt, &ackBy, &ackByOSUser, &postmortem); err != nil {
+			return nil, fmt.Errorf("scanning break-glass incident: %w", err)
+		}
+		b.ActorOSUser = actorOSUser.String
+		b.Cwd = cwd.String
+		b.LogPath = logPath.String
+		b.AcknowledgedBy = ackBy.String
+		b.AcknowledgedByOSUser = ackByOSUser.String
+		b.Postmortem = postmortem.String
+		if exitCode.Valid {
+			code := int(exitCode.Int64)
+			b.ExitCode = &code
+		}
+		b.ExecutedAt, _ = time.Parse(time.RFC3339, executedAt)
+		b.AckDueAt, _ = time.Parse(time.RFC3339, ackDueAt)
+		if ackAt.Valid {
+			if t, err := time.Parse(time.RFC3339, ackAt.String); err == nil {
+				b.AcknowledgedAt = &t
+			}
+		}
+		list = append(list, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
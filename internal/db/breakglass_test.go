@@ -0,0 +1,114 @@
+// Package db tests for break-glass incident operations.
+package db
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBreakglassIncidents(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	if err := db.CreateBreakglassIncident(&BreakglassIncident{ProjectPath: "/p", Actor: "oncall"}); err == nil {
+		t.Fatal("expected validation error without command and reason")
+	}
+
+	executed := time.Now().UTC().Add(-2 * time.Hour).Truncate(time.Second)
+	inc := &BreakglassIncident{
+		ProjectPath: "/p",
+		Actor:       "oncall",
+		ActorOSUser: "alice",
+		Command:     "systemctl restart api",
+		CommandHash: "abc",
+		Reason:      "outage",
+		ExecutedAt:  executed,
+	}
+	if err := db.CreateBreakglassIncident(inc); err != nil {
+		t.Fatalf("CreateBreakglassIncident failed: %v", err)
+	}
+	if inc.ID == "" || !inc.AckDueAt.Equal(executed.Add(24*time.Hour)) {
+		t.Fatalf("expected ID and 24h ack deadline, got %+v", inc)
+	}
+
+	code := 0
+	if err := db.UpdateBreakglassResult(inc.ID, &code, "/tmp/bg.log"); err != nil {
+		t.Fatalf("UpdateBreakglassResult failed: %v", err)
+	}
+
+	open, err := db.ListBreakglassIncidents("/p", true)
+	if err != nil {
+		t.Fatalf("ListBreakglassIncidents failed: %v", err)
+	}
+	if len(open) != 1 || open[0].ExitCode == nil || *open[0].ExitCode != 0 || open[0].LogPath != "/tmp/bg.log" {
+		t.Fatalf("unexpected open incidents: %+v", open)
+	}
+	if open[0].IsOverdue(time.Now()) {
+		t.Error("incident should not be overdue yet")
+	}
+	if !open[0].IsOverdue(executed.Add(25 * time.Hour)) {
+		t.Error("incident should be overdue after the deadline")
+	}
+
+	if err := db.AcknowledgeBreakglass(inc.ID, "oncall", "bob", ""); err == nil {
+		t.Fatal("expected error without postmortem")
+	}
+	for _, self := range []struct{ name, osUser string }{
+		{"oncall", "bob"},
+		{" OnCall ", "bob"},
+		{"lead", "alice"},
+	} {
+		if err := db.AcknowledgeBreakglass(inc.ID, self.name, self.osUser, "restarted after OOM"); !errors.Is(err, ErrBreakglassSelfAck) {
+			t.Fatalf("ack as %q from %q: expected ErrBreakglassSelfAck, got %v", self.name, self.osUser, err)
+		}
+	}
+	if err := db.AcknowledgeBreakglass(inc.ID, "lead", "bob", "restarted after OOM"); err != nil {
+		t.Fatalf("AcknowledgeBreakglass failed: %v", err)
+	}
+	if err := db.AcknowledgeBreakglass(inc.ID, "lead", "bob", "again"); err == nil {
+		t.Fatal("expected error acknowledging twice")
+	}
+	if err := db.AcknowledgeBreakglass("missing", "lead", "bob", "x"); !errors.Is(err, ErrBreakglassNotFound) {
+		t.Fatalf("expected ErrBreakglassNotFound, got %v", err)
+	}
+
+	open, err = db.ListBreakglassIncidents("/p", true)
+	if err != nil || len(open) != 0 {
+		t.Fatalf("expected no open incidents, got %v (err %v)", open, err)
+	}
+	all, err := db.ListBreakglassIncidents("/p", false)
+	if err != nil || len(all) != 1 {
+		t.Fatalf("expected 1 incident, got %v (err %v)", all, err)
+	}
+	if all[0].Postmortem != "restarted after OOM" || all[0].AcknowledgedAt == nil ||
+		all[0].ActorOSUser != "alice" || all[0].AcknowledgedByOSUser != "bob" || all[0].IsOverdue(time.Now().Add(48*time.Hour)) {
+		t.Errorf("unexpected acknowledged incident: %+v", all[0])
+	}
+}
+
+func TestCreateBreakglassIncidentIfAllowed(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	newIncident := func() *BreakglassIncident {
+		return &BreakglassIncident{ProjectPath: "/p", Actor: "oncall", Command: "true", Reason: "outage"}
+	}
+	onlyOnce := func(previous []*BreakglassIncident) error {
+		if len(previous) > 0 {
+			return errors.New("cooling down")
+		}
+		return nil
+	}
+
+	if err := db.CreateBreakglassIncidentIfAllowed(newIncident(), onlyOnce); err != nil {
+		t.Fatalf("first incident: %v", err)
+	}
+	if err := db.CreateBreakglassIncidentIfAllowed(newIncident(), onlyOnce); err == nil {
+		t.Fatal("expected the check to refuse a second incident")
+	}
+	all, err := db.ListBreakglassIncidents("/p", false)
+	if err != nil || len(all) != 1 {
+		t.Fatalf("expected 1 incident, got %v (err %v)", all, err)
+	}
+}
@@ -0,0 +1,258 @@
+package db
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrCommandEditNotFound indicates no such proposed edit exists.
+var ErrCommandEditNotFound = errors.New("command edit not found")
+
+// CommandEditStatus is the state of a proposed edit.
+type CommandEditStatus string
+
+const (
+	// CommandEditProposed is an edit awaiting the requestor.
+	CommandEditProposed CommandEditStatus = "proposed"
+	// CommandEditAccepted is an edit the requestor accepted.
+	CommandEditAccepted CommandEditStatus = "accepted"
+)
+
+// CommandEdit is a corrected command a reviewer would approve in place of
+// the one requested ("approve if you change --force to --force-with-lease").
+type CommandEdit struct {
+	// ID is the unique edit identifier (UUID).
+	ID string `json:"id"`
+	// RequestID is the request the edit corrects.
+	RequestID string `json:"request_id"`
+
+	// ReviewerSessionID is the session that proposed the edit.
+	ReviewerSessionID string `json:"reviewer_session_id"`
+	// ReviewerAgent is the agent that proposed the edit.
+	ReviewerAgent string `json:"reviewer_agent"`
+	// ReviewerModel is the model that proposed the edit.
+	ReviewerModel string `json:"reviewer_model"`
+
+	// Command is the corrected command.
+	Command string `json:"command"`
+	// Comments explains the correction.
+	Comments string `json:"comments,omitempty"`
+
+	// Signature is HMAC(session_key, request_id + command + timestamp).
+	Signature string `json:"signature"`
+	// SignatureTimestamp is included in the signature to prevent replay.
+	SignatureTimestamp time.Time `json:"signature_timestamp"`
+	// AuthMethod is how the reviewer re-authenticated when sudo mode
+	// required it.
+	AuthMethod string `json:"auth_method,omitempty"`
+	// AuthenticatedAt is when that re-authentication happened.
+	AuthenticatedAt *time.Time `json:"authenticated_at,omitempty"`
+
+	// Status is proposed or accepted.
+	Status CommandEditStatus `json:"status"`
+	// AcceptedRequestID is the request created for the corrected command;
+	// empty until accepted, and when the corrected command needed no
+	// approval.
+	AcceptedRequestID string `json:"accepted_request_id,omitempty"`
+	// AcceptedAt is when the requestor accepted the edit.
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+	// CreatedAt is when the edit was proposed.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ComputeEditSignature computes an HMAC signature for a proposed edit.
+// Signature = HMAC-SHA256(sessionKey, requestID + command + timestamp)
+func ComputeEditSignature(sessionKey, requestID, command string, timestamp time.Time) string {
+	data := requestID + command + timestamp.Format(time.RFC3339)
+	key, _ := hex.DecodeString(sessionKey)
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// CreateCommandEdit records a proposed edit, generating ID and timestamps
+// if missing, along with an edit_proposed event on the request.
+func (db *DB) CreateCommandEdit(e *CommandEdit) error {
+	if e.RequestID == "" || e.ReviewerSessionID == "" || e.Command == "" {
+		return fmt.Errorf("command edit requires request id, reviewer session and command")
+	}
+	if e.ID == "" {
+		e.ID = uuid.New().String()
+	}
+	now := db.Now()
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = now
+	}
+	if e.SignatureTimestamp.IsZero() {
+		e.SignatureTimestamp = now
+	}
+	e.Status = CommandEditProposed
+
+	return db.Transaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			INSERT INTO command_edits (
+				id, request_id, reviewer_session_id, reviewer_agent, reviewer_model,
+				command, comments, signature, signature_timestamp,
+				auth_method, authenticated_at, status, created_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`,
+			e.ID, e.RequestID, e.ReviewerSessionID, e.ReviewerAgent, e.ReviewerModel,
+			e.Command, nullString(e.Comments), e.Signature, e.SignatureTimestamp.Format(time.RFC3339),
+			nullString(e.AuthMethod), formatTimePtr(e.AuthenticatedAt), string(e.Status), e.CreatedAt.Format(time.RFC3339),
+		)
+		if err != nil {
+			return fmt.Errorf("creating command edit: %w", err)
+		}
+		details := "edit " + e.ID + ": " + e.Command
+		if e.Comments != "" {
+			details += " (" + e.Comments + ")"
+		}
+		return insertRequestEvent(tx, &RequestEvent{
+			RequestID: e.RequestID,
+			Type:      RequestEventEditProposed,
+			Actor:     e.ReviewerAgent,
+			Details:   details,
+			CreatedAt: e.CreatedAt,
+		})
+	})
+}
+
+// ClaimCommandEdit marks a proposed edit accepted so it cannot be accepted
+// twice. It returns false if the edit was no longer proposed.
+func (db *DB) ClaimCommandEdit(id string) (bool, error) {
+	result, err := db.Exec(`
+		UPDATE command_edits SET status = ?, accepted_at = ?
+		WHERE id = ? AND status = ?
+	`, string(CommandEditAccepted), db.Now().Format(time.RFC3339), id, string(CommandEditProposed))
+	if err != nil {
+		return false, fmt.Errorf("claiming command edit: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("checking command edit claim: %w", err)
+	}
+	return n > 0, nil
+}
+
+// LinkCommandEdit records the request created for an accepted edit and
+// adds an edit_accepted event to both requests, completing the audit chain
+// from the original request to its correction. newRequestID is empty when
+// the corrected command needed no approval.
+func (db *DB) LinkCommandEdit(e *CommandEdit, newRequestID, actor string) error {
+	return db.Transaction(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`
+			UPDATE command_edits SET accepted_request_id = ? WHERE id = ?
+		`, nullString(newRequestID), e.ID); err != nil {
+			return fmt.Errorf("linking command edit: %w", err)
+		}
+		at := db.Now()
+		details := "edit " + e.ID + " from " + e.ReviewerAgent + " accepted; no approval needed for " + e.Command
+		if newRequestID != "" {
+			details = "edit " + e.ID + " from " + e.ReviewerAgent + " accepted as request " + newRequestID
+			if err := insertRequestEvent(tx, &RequestEvent{
+				RequestID: newRequestID,
+				Type:      RequestEventEditAccepted,
+				Actor:     actor,
+				Details:   "corrects request " + e.RequestID + " with edit " + e.ID + " from " + e.ReviewerAgent,
+				CreatedAt: at,
+			}); err != nil {
+				return err
+			}
+		}
+		return insertRequestEvent(tx, &RequestEvent{
+			RequestID: e.RequestID,
+			Type:      RequestEventEditAccepted,
+			Actor:     actor,
+			Details:   details,
+			CreatedAt: at,
+		})
+	})
+}
+
+const commandEditColumns = `
+	id, request_id, reviewer_session_id, reviewer_agent, reviewer_model,
+	command, comments, signature, signature_timestamp,
+	auth_method, authenticated_at, status, accepted_request_id, accepted_at, created_at`
+
+// GetCommandEdit returns a proposed edit by ID.
+func (db *DB) GetCommandEdit(id string) (*CommandEdit, error) {
+	rows, err := db.Query(`SELECT `+commandEditColumns+` FROM command_edits WHERE id = ?`, id)
+	if err != nil {
+		return nil, fmt.Errorf("getting command edit: %w", err)
+	}
+	list, err := scanCommandEdits(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, ErrCommandEditNotFound
+	}
+	return list[0], nil
+}
+
+// ListCommandEdits returns the edits proposed for a request, oldest first.
+func (db *DB) ListCommandEdits(requestID string) ([]*CommandEdit, error) {
+	rows, err := db.Query(`
+		SELECT `+commandEditColumns+` FROM command_edits
+		WHERE request_id = ?
+		ORDER BY created_at, id
+	`, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("listing command edits: %w", err)
+	}
+	return scanCommandEdits(rows)
+}
+
+// GetCommandEditForRequest returns the accepted edit a request was created
+// from, or ErrCommandEditNotFound if it was not created from one.
+func (db *DB) GetCommandEditForRequest(requestID string) (*CommandEdit, error) {
+	rows, err := db.Query(`SELECT `+commandEditColumns+` FROM command_edits WHERE accepted_request_id = ?`, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("getting command edit: %w", err)
+	}
+	list, err := scanCommandEdits(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, ErrCommandEditNotFound
+	}
+	return list[0], nil
+}
+
+func scanCommandEdits(rows *sql.Rows) ([]*CommandEdit, error) {
+	defer rows.Close()
+
+	var list []*CommandEdit
+	for rows.Next() {
+		e := &CommandEdit{}
+		var status, sigTs, created string
+		var comments, authMethod, authenticatedAt, acceptedRequestID, acceptedAt sql.NullString
+		if err := rows.Scan(&e.ID, &e.RequestID, &e.ReviewerSessionID, &e.ReviewerAgent, &e.ReviewerModel,
+			&e.Command, &comments, &e.Signature, &sigTs,
+			&authMethod, &authenticatedAt, &status, &acceptedRequestID, &acceptedAt, &created); err != nil {
+			return nil, fmt.Errorf("scanning command edit: %w", err)
+		}
+		e.Status = CommandEditStatus(status)
+		e.Comments, e.AuthMethod, e.AcceptedRequestID = comments.String, authMethod.String, acceptedRequestID.String
+		e.SignatureTimestamp, _ = time.Parse(time.RFC3339, sigTs)
+		e.CreatedAt, _ = time.Parse(time.RFC3339, created)
+		if authenticatedAt.Valid {
+			t, _ := time.Parse(time.RFC3339, authenticatedAt.String) //nolint:errcheck
+			e.AuthenticatedAt = &t
+		}
+		if acceptedAt.Valid {
+			t, _ := time.Parse(time.RFC3339, acceptedAt.String) //nolint:errcheck
+			e.AcceptedAt = &t
+		}
+		list = append(list, e)
+	}
+	return list, rows.Err()
+}
@@ -0,0 +1,68 @@
+package db
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCommandEdits(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	sess, req := createTestRequest(t, db)
+	_, other := createTestRequest(t, db)
+
+	if err := db.CreateCommandEdit(&CommandEdit{RequestID: req.ID, ReviewerSessionID: sess.ID}); err == nil {
+		t.Fatal("expected error without command")
+	}
+	if _, err := db.GetCommandEdit("missing"); !errors.Is(err, ErrCommandEditNotFound) {
+		t.Fatalf("expected ErrCommandEditNotFound, got %v", err)
+	}
+
+	e := &CommandEdit{
+		RequestID:         req.ID,
+		ReviewerSessionID: sess.ID,
+		ReviewerAgent:     "Reviewer",
+		ReviewerModel:     "model-b",
+		Command:           "git push --force-with-lease",
+		Comments:          "keep others' commits",
+		Signature:         "sig",
+	}
+	if err := db.CreateCommandEdit(e); err != nil || e.ID == "" || e.Status != CommandEditProposed {
+		t.Fatalf("CreateCommandEdit: %+v, %v", e, err)
+	}
+	list, err := db.ListCommandEdits(req.ID)
+	if err != nil || len(list) != 1 || list[0].Command != e.Command || list[0].Comments != e.Comments || list[0].CreatedAt.IsZero() {
+		t.Fatalf("ListCommandEdits = %+v, %v", list, err)
+	}
+
+	if claimed, err := db.ClaimCommandEdit(e.ID); err != nil || !claimed {
+		t.Fatalf("ClaimCommandEdit: %v, %v", claimed, err)
+	}
+	if claimed, err := db.ClaimCommandEdit(e.ID); err != nil || claimed {
+		t.Fatalf("second ClaimCommandEdit: %v, %v", claimed, err)
+	}
+	if err := db.LinkCommandEdit(e, other.ID, "Requestor"); err != nil {
+		t.Fatalf("LinkCommandEdit: %v", err)
+	}
+
+	got, err := db.GetCommandEditForRequest(other.ID)
+	if err != nil || got.ID != e.ID || got.Status != CommandEditAccepted || got.AcceptedRequestID != other.ID || got.AcceptedAt == nil {
+		t.Fatalf("GetCommandEditForRequest = %+v, %v", got, err)
+	}
+	if _, err := db.GetCommandEditForRequest(req.ID); !errors.Is(err, ErrCommandEditNotFound) {
+		t.Fatalf("original request was not created from an edit, got %v", err)
+	}
+
+	events, err := db.ListRequestEvents(req.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(events); n < 2 || events[n-2].Type != RequestEventEditProposed || events[n-1].Type != RequestEventEditAccepted {
+		t.Fatalf("unexpected events on the original: %+v", events)
+	}
+	events, err = db.ListRequestEvents(other.ID)
+	if err != nil || events[len(events)-1].Type != RequestEventEditAccepted || events[len(events)-1].Actor != "Requestor" {
+		t.Fatalf("unexpected events on the new request: %+v, %v", events, err)
+	}
+}
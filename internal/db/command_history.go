@@ -0,0 +1,44 @@
+package db
+
+import "fmt"
+
+// CommandHistory summarises how earlier requests for the same command in a
+// project turned out.
+type CommandHistory struct {
+	// Rejected counts requests reviewers rejected.
+	Rejected int `json:"rejected"`
+	// Succeeded counts executions that exited 0 and were not reported as
+	// causing problems.
+	Succeeded int `json:"succeeded"`
+	// Failed counts executions that exited non-zero.
+	Failed int `json:"failed"`
+	// CausedProblems counts requests whose latest recorded outcome says
+	// they caused problems.
+	CausedProblems int `json:"caused_problems"`
+}
+
+// CommandHistoryFor returns the history of requests in projectPath with the
+// given command hash.
+func (db *DB) CommandHistoryFor(projectPath, commandHash string) (CommandHistory, error) {
+	var h CommandHistory
+	err := db.QueryRow(`
+		WITH prior AS (
+			SELECT r.status, r.execution_exit_code AS exit_code,
+				(SELECT o.caused_problems FROM execution_outcomes o
+					WHERE o.request_id = r.id ORDER BY o.created_at DESC, o.id DESC LIMIT 1) AS caused
+			FROM requests r
+			WHERE r.project_path = ? AND r.command_hash = ?
+		)
+		SELECT
+			COALESCE(SUM(status = ?), 0),
+			COALESCE(SUM(exit_code = 0 AND COALESCE(caused, 0) = 0), 0),
+			COALESCE(SUM(exit_code IS NOT NULL AND exit_code != 0), 0),
+			COALESCE(SUM(caused = 1), 0)
+		FROM prior
+	`, projectPath, commandHash, string(StatusRejected)).
+		Scan(&h.Rejected, &h.Succeeded, &h.Failed, &h.CausedProblems)
+	if err != nil {
+		return CommandHistory{}, fmt.Errorf("reading command history: %w", err)
+	}
+	return h, nil
+}
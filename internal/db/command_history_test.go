@@ -0,0 +1,80 @@
+package db
+
+import "testing"
+
+func TestCommandHistoryFor(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, clean := createTestRequest(t, db)
+	_, broke := createTestRequest(t, db)
+	_, failed := createTestRequest(t, db)
+	_, rejected := createTestRequest(t, db)
+	createTestRequest(t, db) // still pending
+
+	execute := func(r *Request, code int) {
+		t.Helper()
+		if err := db.UpdateRequestExecution(r.ID, &Execution{ExitCode: &code}); err != nil {
+			t.Fatalf("UpdateRequestExecution failed: %v", err)
+		}
+	}
+	execute(clean, 0)
+	execute(broke, 0)
+	execute(failed, 2)
+	if _, err := db.RecordOutcome(broke.ID, true, "wiped caches", nil, ""); err != nil {
+		t.Fatalf("RecordOutcome failed: %v", err)
+	}
+	if err := db.UpdateRequestStatus(rejected.ID, StatusRejected); err != nil {
+		t.Fatalf("UpdateRequestStatus failed: %v", err)
+	}
+
+	h, err := db.CommandHistoryFor("/test/project", clean.Command.Hash)
+	if err != nil {
+		t.Fatalf("CommandHistoryFor failed: %v", err)
+	}
+	want := CommandHistory{Rejected: 1, Succeeded: 1, Failed: 1, CausedProblems: 1}
+	if h != want {
+		t.Errorf("CommandHistoryFor = %+v, want %+v", h, want)
+	}
+
+	if h, err := db.CommandHistoryFor("/other/project", clean.Command.Hash); err != nil || h != (CommandHistory{}) {
+		t.Errorf("expected no history in another project, got %+v (err %v)", h, err)
+	}
+}
+
+func TestRequestRiskScoreRoundTrip(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	sess, _ := createTestRequest(t, db)
+	r := &Request{
+		ProjectPath:        "/test/project",
+		RequestorSessionID: sess.ID,
+		RequestorAgent:     sess.AgentName,
+		RiskTier:           RiskTierCritical,
+		RiskScore:          87,
+		MinApprovals:       2,
+		Command:            CommandSpec{Raw: "rm -rf /etc", Cwd: "/test/project"},
+		Justification:      Justification{Reason: "test"},
+	}
+	if err := db.CreateRequest(r); err != nil {
+		t.Fatalf("CreateRequest failed: %v", err)
+	}
+
+	got, err := db.GetRequest(r.ID)
+	if err != nil {
+		t.Fatalf("GetRequest failed: %v", err)
+	}
+	if got.RiskScore != 87 {
+		t.Errorf("GetRequest RiskScore = %d, want 87", got.RiskScore)
+	}
+	pending, err := db.ListPendingRequests("/test/project")
+	if err != nil {
+		t.Fatalf("ListPendingRequests failed: %v", err)
+	}
+	for _, p := range pending {
+		if p.ID == r.ID && p.RiskScore != 87 {
+			t.Errorf("ListPendingRequests RiskScore = %d, want 87", p.RiskScore)
+		}
+	}
+}
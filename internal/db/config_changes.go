@@ -0,0 +1,177 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrConfigChangeNotFound indicates no such staged config change exists.
+var ErrConfigChangeNotFound = errors.New("config change not found")
+
+// ConfigChangeStatus is the state of a staged config change.
+type ConfigChangeStatus string
+
+const (
+	// ConfigChangeStaged is a change waiting for its request to execute.
+	ConfigChangeStaged ConfigChangeStatus = "staged"
+	// ConfigChangeApplied is a change written to its config file.
+	ConfigChangeApplied ConfigChangeStatus = "applied"
+)
+
+// ConfigChange is a config file staged with `slb config apply`. It is
+// written only when its critical request is executed, so config changes
+// get the same quorum as the commands they govern.
+type ConfigChange struct {
+	// ID is the unique change identifier (UUID).
+	ID string `json:"id"`
+	// RequestID is the request whose execution applies the change.
+	RequestID string `json:"request_id"`
+	// ProjectPath is the project the config belongs to.
+	ProjectPath string `json:"project_path"`
+	// TargetPath is the config file the change replaces.
+	TargetPath string `json:"target_path"`
+
+	// BaseHash is the hash of the config file when the change was staged
+	// (empty if it did not exist); the change is refused if it moved on.
+	BaseHash string `json:"base_hash"`
+	// ConfigHash is the hash of Content.
+	ConfigHash string `json:"config_hash"`
+	// Content is the staged config file.
+	Content string `json:"-"`
+	// Diff lists the changed settings for reviewers.
+	Diff string `json:"diff"`
+
+	// Status is staged or applied.
+	Status ConfigChangeStatus `json:"status"`
+	// CreatedBy is the agent that staged the change.
+	CreatedBy string `json:"created_by"`
+	// CreatedAt is when the change was staged.
+	CreatedAt time.Time `json:"created_at"`
+	// AppliedBy is the agent that executed the request.
+	AppliedBy string `json:"applied_by,omitempty"`
+	// AppliedAt is when the file was written.
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// CreateConfigChange records a staged change, generating ID and timestamp
+// if missing.
+func (db *DB) CreateConfigChange(c *ConfigChange) error {
+	if c.RequestID == "" || c.TargetPath == "" || c.ConfigHash == "" {
+		return fmt.Errorf("config change requires request id, target path and config hash")
+	}
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+	if c.CreatedAt.IsZero() {
+		c.CreatedAt = db.Now()
+	}
+	c.Status = ConfigChangeStaged
+
+	_, err := db.Exec(`
+		INSERT INTO config_changes (
+			id, request_id, project_path, target_path, base_hash, config_hash,
+			content, diff, status, created_by, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		c.ID, c.RequestID, c.ProjectPath, c.TargetPath, c.BaseHash, c.ConfigHash,
+		c.Content, c.Diff, string(c.Status), c.CreatedBy, c.CreatedAt.Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("creating config change: %w", err)
+	}
+	return nil
+}
+
+// MarkConfigChangeApplied records that a staged change was written. It
+// returns false if the change was no longer staged.
+func (db *DB) MarkConfigChangeApplied(id, appliedBy string) (bool, error) {
+	result, err := db.Exec(`
+		UPDATE config_changes SET status = ?, applied_by = ?, applied_at = ?
+		WHERE id = ? AND status = ?
+	`, string(ConfigChangeApplied), nullString(appliedBy), db.Now().Format(time.RFC3339), id, string(ConfigChangeStaged))
+	if err != nil {
+		return false, fmt.Errorf("marking config change applied: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("checking config change update: %w", err)
+	}
+	return n > 0, nil
+}
+
+const configChangeColumns = `
+	id, request_id, project_path, target_path, base_hash, config_hash,
+	content, diff, status, created_by, created_at, applied_by, applied_at`
+
+// GetConfigChange returns a config change by ID.
+func (db *DB) GetConfigChange(id string) (*ConfigChange, error) {
+	return db.getConfigChange(`SELECT `+configChangeColumns+` FROM config_changes WHERE id = ?`, id)
+}
+
+// GetConfigChangeForRequest returns the config change a request applies,
+// or ErrConfigChangeNotFound if it is not a config change request.
+func (db *DB) GetConfigChangeForRequest(requestID string) (*ConfigChange, error) {
+	return db.getConfigChange(`SELECT `+configChangeColumns+` FROM config_changes WHERE request_id = ?`, requestID)
+}
+
+func (db *DB) getConfigChange(query string, arg string) (*ConfigChange, error) {
+	rows, err := db.Query(query, arg)
+	if err != nil {
+		return nil, fmt.Errorf("getting config change: %w", err)
+	}
+	list, err := scanConfigChanges(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, ErrConfigChangeNotFound
+	}
+	return list[0], nil
+}
+
+// ListConfigHistory returns the changes applied to a project's config,
+// newest first. A limit of 0 returns them all.
+func (db *DB) ListConfigHistory(projectPath string, limit int) ([]*ConfigChange, error) {
+	query := `
+		SELECT ` + configChangeColumns + ` FROM config_changes
+		WHERE project_path = ? AND status = ?
+		ORDER BY applied_at DESC, rowid DESC`
+	args := []any{projectPath, string(ConfigChangeApplied)}
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing config history: %w", err)
+	}
+	return scanConfigChanges(rows)
+}
+
+func scanConfigChanges(rows *sql.Rows) ([]*ConfigChange, error) {
+	defer rows.Close()
+
+	var list []*ConfigChange
+	for rows.Next() {
+		c := &ConfigChange{}
+		var status, created string
+		var appliedBy, appliedAt sql.NullString
+		if err := rows.Scan(&c.ID, &c.RequestID, &c.ProjectPath, &c.TargetPath, &c.BaseHash, &c.ConfigHash,
+			&c.Content, &c.Diff, &status, &c.CreatedBy, &created, &appliedBy, &appliedAt); err != nil {
+			return nil, fmt.Errorf("scanning config change: %w", err)
+		}
+		c.Status = ConfigChangeStatus(status)
+		c.AppliedBy = appliedBy.String
+		c.CreatedAt, _ = time.Parse(time.RFC3339, created)
+		if appliedAt.Valid {
+			t, _ := time.Parse(time.RFC3339, appliedAt.String) //nolint:errcheck
+			c.AppliedAt = &t
+		}
+		list = append(list, c)
+	}
+	return list, rows.Err()
+}
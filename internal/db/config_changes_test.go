@@ -0,0 +1,82 @@
+package db
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestConfigChanges(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, req := createTestRequest(t, db)
+	_, other := createTestRequest(t, db)
+
+	if err := db.CreateConfigChange(&ConfigChange{RequestID: req.ID}); err == nil {
+		t.Fatal("expected error without target and hash")
+	}
+	if _, err := db.GetConfigChange("missing"); !errors.Is(err, ErrConfigChangeNotFound) {
+		t.Fatalf("expected ErrConfigChangeNotFound, got %v", err)
+	}
+
+	first := &ConfigChange{
+		RequestID:   req.ID,
+		ProjectPath: "/work/app",
+		TargetPath:  "/work/app/.slb/config.toml",
+		ConfigHash:  "hash-1",
+		Content:     "[general]\nmin_approvals = 3\n",
+		Diff:        "~ general.min_approvals = 2 -> 3",
+		CreatedBy:   "Requestor",
+	}
+	if err := db.CreateConfigChange(first); err != nil || first.ID == "" || first.Status != ConfigChangeStaged {
+		t.Fatalf("CreateConfigChange: %+v, %v", first, err)
+	}
+	got, err := db.GetConfigChangeForRequest(req.ID)
+	if err != nil || got.ID != first.ID || got.Content != first.Content || got.Diff != first.Diff || got.AppliedAt != nil {
+		t.Fatalf("GetConfigChangeForRequest = %+v, %v", got, err)
+	}
+	if _, err := db.GetConfigChangeForRequest(other.ID); !errors.Is(err, ErrConfigChangeNotFound) {
+		t.Fatalf("expected ErrConfigChangeNotFound for a plain request, got %v", err)
+	}
+
+	// Staged changes are not history.
+	if history, err := db.ListConfigHistory("/work/app", 0); err != nil || len(history) != 0 {
+		t.Fatalf("history before applying = %+v, %v", history, err)
+	}
+
+	if ok, err := db.MarkConfigChangeApplied(first.ID, "Executor"); err != nil || !ok {
+		t.Fatalf("MarkConfigChangeApplied: %v, %v", ok, err)
+	}
+	if ok, err := db.MarkConfigChangeApplied(first.ID, "Executor"); err != nil || ok {
+		t.Fatalf("second MarkConfigChangeApplied: %v, %v", ok, err)
+	}
+
+	second := &ConfigChange{
+		RequestID:   other.ID,
+		ProjectPath: "/work/app",
+		TargetPath:  "/work/app/.slb/config.toml",
+		BaseHash:    "hash-1",
+		ConfigHash:  "hash-2",
+		CreatedBy:   "Requestor",
+	}
+	if err := db.CreateConfigChange(second); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.MarkConfigChangeApplied(second.ID, "Executor"); err != nil {
+		t.Fatal(err)
+	}
+
+	history, err := db.ListConfigHistory("/work/app", 0)
+	if err != nil || len(history) != 2 || history[0].ConfigHash != "hash-2" || history[1].ConfigHash != "hash-1" {
+		t.Fatalf("ListConfigHistory = %+v, %v", history, err)
+	}
+	if history[1].Status != ConfigChangeApplied || history[1].AppliedBy != "Executor" || history[1].AppliedAt == nil {
+		t.Errorf("applied change = %+v", history[1])
+	}
+	if history, _ := db.ListConfigHistory("/work/app", 1); len(history) != 1 || history[0].ID != second.ID {
+		t.Errorf("limited history = %+v", history)
+	}
+	if history, _ := db.ListConfigHistory("/elsewhere", 0); len(history) != 0 {
+		t.Errorf("history of another project = %+v", history)
+	}
+}
@@ -58,7 +58,7 @@ func (db *DB) InsertCustomPattern(tier, pattern, description, source string) (in
 	result, err := db.Exec(
 		`INSERT INTO custom_patterns (tier, pattern, description, source, created_at)
 		 VALUES (?, ?, ?, ?, ?)`,
-		tier, pattern, description, source, time.Now().UTC().Format(time.RFC3339),
+		tier, pattern, description, source, db.Now().Format(time.RFC3339),
 	)
 	if err != nil {
 		return 0, fmt.Errorf("inserting custom pattern: %w", err)
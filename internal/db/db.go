@@ -9,15 +9,21 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
+	"github.com/Dicklesworthstone/slb/internal/clock"
 	_ "modernc.org/sqlite" // Pure Go SQLite driver
 )
 
 // DB wraps the SQLite database connection.
 type DB struct {
-	conn *sql.DB
-	path string
-	mu   sync.RWMutex
+	conn  *sql.DB
+	path  string
+	mu    sync.RWMutex
+	clock clock.Clock
+
+	// latency delays every statement (chaos mode's slow disk).
+	latency time.Duration
 }
 
 // OpenOptions configures database opening behavior.
@@ -46,8 +52,9 @@ func Open(path string) (*DB, error) {
 
 // OpenWithOptions opens a database connection with the given options.
 func OpenWithOptions(path string, opts OpenOptions) (*DB, error) {
-	// Ensure parent directory exists if creating
-	if opts.CreateIfNotExists {
+	// Ensure parent directory exists if creating. An in-memory database
+	// has no file.
+	if opts.CreateIfNotExists && path != ":memory:" {
 		dir := filepath.Dir(path)
 		if err := os.MkdirAll(dir, 0700); err != nil {
 			return nil, fmt.Errorf("creating database directory: %w", err)
@@ -63,7 +70,11 @@ func OpenWithOptions(path string, opts OpenOptions) (*DB, error) {
 
 	// Build connection string with pragmas
 	// Note: modernc.org/sqlite uses different pragma syntax
-	mode := ""
+	// Writers take the write lock when a transaction begins. A deferred
+	// transaction that reads first and then writes cannot wait for the
+	// lock (busy_timeout does not apply to the upgrade) and fails with
+	// SQLITE_BUSY under concurrent writers.
+	mode := "&_txlock=immediate"
 	if opts.ReadOnly {
 		mode = "&mode=ro"
 	}
@@ -164,8 +175,32 @@ func (db *DB) ValidateSchema() error {
 	return nil
 }
 
+// SetClock sets the clock used for timestamps, expiry and staleness checks.
+// A nil clock restores the system clock.
+func (db *DB) SetClock(c clock.Clock) {
+	db.clock = c
+}
+
+// Now returns the database clock's current time in UTC.
+func (db *DB) Now() time.Time {
+	return clock.OrReal(db.clock).Now().UTC()
+}
+
+// SetLatency delays every statement by d, simulating a slow disk for the
+// daemon's chaos mode. Set it before the database is shared.
+func (db *DB) SetLatency(d time.Duration) {
+	db.latency = d
+}
+
+func (db *DB) stall() {
+	if db.latency > 0 {
+		time.Sleep(db.latency)
+	}
+}
+
 // Exec executes a SQL statement.
 func (db *DB) Exec(query string, args ...any) (sql.Result, error) {
+	db.stall()
 	db.mu.Lock()
 	defer db.mu.Unlock()
 	return db.conn.Exec(query, args...)
@@ -173,6 +208,7 @@ func (db *DB) Exec(query string, args ...any) (sql.Result, error) {
 
 // Query executes a query that returns rows.
 func (db *DB) Query(query string, args ...any) (*sql.Rows, error) {
+	db.stall()
 	db.mu.RLock()
 	defer db.mu.RUnlock()
 	return db.conn.Query(query, args...)
@@ -180,6 +216,7 @@ func (db *DB) Query(query string, args ...any) (*sql.Rows, error) {
 
 // QueryRow executes a query that returns a single row.
 func (db *DB) QueryRow(query string, args ...any) *sql.Row {
+	db.stall()
 	db.mu.RLock()
 	defer db.mu.RUnlock()
 	return db.conn.QueryRow(query, args...)
@@ -187,6 +224,7 @@ func (db *DB) QueryRow(query string, args ...any) *sql.Row {
 
 // Begin starts a transaction.
 func (db *DB) Begin() (*sql.Tx, error) {
+	db.stall()
 	db.mu.Lock()
 	defer db.mu.Unlock()
 	return db.conn.Begin()
@@ -69,6 +69,21 @@ func TestOpenAndMigrate_OpenError(t *testing.T) {
 	}
 }
 
+func TestOpen_InMemoryCreatesNoFile(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	db, err := Open(":memory:")
+	if err != nil {
+		t.Fatalf("Open(:memory:) failed: %v", err)
+	}
+	db.Close()
+
+	if _, err := os.Stat(filepath.Join(dir, ":memory:")); !os.IsNotExist(err) {
+		t.Errorf("Open(:memory:) left a file in the working directory (stat err: %v)", err)
+	}
+}
+
 func TestOpenProjectDB(t *testing.T) {
 	projectDir := t.TempDir()
 	db, err := OpenProjectDB(projectDir)
@@ -160,6 +175,23 @@ func TestAddColumnIfMissing_AlreadyExistsAndMissingTable(t *testing.T) {
 	}
 }
 
+func TestDB_SetLatency(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer db.Close()
+
+	db.SetLatency(30 * time.Millisecond)
+	start := time.Now()
+	if _, err := db.Exec(`SELECT 1`); err != nil {
+		t.Fatalf("Exec: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("query took %v, want at least the 30ms latency", elapsed)
+	}
+}
+
 func TestDB_ReturnsErrorsWhenClosed(t *testing.T) {
 	tmpDir := t.TempDir()
 	db, err := Open(filepath.Join(tmpDir, "test.db"))
@@ -0,0 +1,208 @@
+package db
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrDelegationNotFound indicates the requested delegation does not exist,
+// has already been revoked, or is not the caller's to revoke.
+var ErrDelegationNotFound = errors.New("delegation not found")
+
+// Delegation lends one reviewer's approval authority to another reviewer
+// for a bounded time window, so absences don't block quorum.
+type Delegation struct {
+	// ID is the unique delegation identifier (UUID).
+	ID string `json:"id"`
+	// ProjectPath scopes the delegation to a project.
+	ProjectPath string `json:"project_path"`
+	// FromAgent is the reviewer delegating their authority.
+	FromAgent string `json:"from_agent"`
+	// FromSessionID is the delegator's session, whose key signed the
+	// delegation.
+	FromSessionID string `json:"from_session_id,omitempty"`
+	// Signature is the delegator's HMAC over the delegation.
+	Signature string `json:"-"`
+	// ToAgent is the reviewer receiving the authority.
+	ToAgent string `json:"to_agent"`
+	// Reason optionally explains the delegation (e.g. "vacation").
+	Reason string `json:"reason,omitempty"`
+	// StartsAt is when the delegation takes effect.
+	StartsAt time.Time `json:"starts_at"`
+	// ExpiresAt is when the delegation lapses.
+	ExpiresAt time.Time `json:"expires_at"`
+	// CreatedAt is when the delegation was recorded.
+	CreatedAt time.Time `json:"created_at"`
+	// RevokedAt is set when the delegation was revoked early.
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// IsActiveAt reports whether the delegation is in effect at t.
+func (d *Delegation) IsActiveAt(t time.Time) bool {
+	if d.RevokedAt != nil {
+		return false
+	}
+	return !t.Before(d.StartsAt) && t.Before(d.ExpiresAt)
+}
+
+// ComputeDelegationSignature computes the delegator's HMAC over the
+// delegation's ID, project, parties and window.
+func ComputeDelegationSignature(sessionKey string, d *Delegation) string {
+	data := d.ID + d.ProjectPath + d.FromAgent + d.FromSessionID + d.ToAgent +
+		d.StartsAt.UTC().Format(time.RFC3339) + d.ExpiresAt.UTC().Format(time.RFC3339)
+	key, _ := hex.DecodeString(sessionKey)
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyDelegationSignature verifies a delegation's signature.
+func VerifyDelegationSignature(sessionKey string, d *Delegation) bool {
+	if d.Signature == "" {
+		return false
+	}
+	expected := ComputeDelegationSignature(sessionKey, d)
+	return hmac.Equal([]byte(expected), []byte(d.Signature))
+}
+
+// PrepareDelegation fills in the ID and timestamps of a new delegation, so
+// it can be signed before CreateDelegation records it.
+func (db *DB) PrepareDelegation(d *Delegation) {
+	now := db.Now()
+	if d.ID == "" {
+		d.ID = uuid.New().String()
+	}
+	if d.CreatedAt.IsZero() {
+		d.CreatedAt = now
+	}
+	if d.StartsAt.IsZero() {
+		d.StartsAt = now
+	}
+}
+
+// CreateDelegation records a new delegation. It must be signed by the
+// delegator's session.
+func (db *DB) CreateDelegation(d *Delegation) error {
+	if d.FromAgent == "" || d.ToAgent == "" {
+		return fmt.Errorf("delegation requires from and to agents")
+	}
+	if d.FromAgent == d.ToAgent {
+		return fmt.Errorf("cannot delegate to yourself")
+	}
+	if d.FromSessionID == "" || d.Signature == "" {
+		return fmt.Errorf("delegation must be signed by the delegator's session")
+	}
+	db.PrepareDelegation(d)
+	if !d.ExpiresAt.After(d.StartsAt) {
+		return fmt.Errorf("delegation must expire after it starts")
+	}
+
+	_, err := db.Exec(`
+		INSERT INTO delegations (
+			id, project_path, from_agent, from_session_id, to_agent, reason,
+			starts_at, expires_at, created_at, revoked_at, signature
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		d.ID, d.ProjectPath, d.FromAgent, d.FromSessionID, d.ToAgent, nullString(d.Reason),
+		d.StartsAt.UTC().Format(time.RFC3339), d.ExpiresAt.UTC().Format(time.RFC3339),
+		d.CreatedAt.UTC().Format(time.RFC3339), formatTimePtr(d.RevokedAt), d.Signature,
+	)
+	if err != nil {
+		return fmt.Errorf("creating delegation: %w", err)
+	}
+	return nil
+}
+
+// RevokeDelegation ends a delegation early. Only a delegation in
+// projectPath that agent gave or received can be revoked.
+func (db *DB) RevokeDelegation(id, projectPath, agent string) error {
+	result, err := db.Exec(`
+		UPDATE delegations SET revoked_at = ?
+		WHERE id = ? AND project_path = ? AND (from_agent = ? OR to_agent = ?) AND revoked_at IS NULL
+	`, db.Now().Format(time.RFC3339), id, projectPath, agent, agent)
+	if err != nil {
+		return fmt.Errorf("revoking delegation: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	if n == 0 {
+		return ErrDelegationNotFound
+	}
+	return nil
+}
+
+// ListDelegations returns delegations for a project, newest first.
+// When includeInactive is false only delegations active now are returned.
+func (db *DB) ListDelegations(projectPath string, includeInactive bool) ([]*Delegation, error) {
+	query := `
+		SELECT id, project_path, from_agent, from_session_id, to_agent, reason,
+		       starts_at, expires_at, created_at, revoked_at, signature
+		FROM delegations WHERE project_path = ?`
+	args := []any{projectPath}
+	if !includeInactive {
+		now := db.Now().Format(time.RFC3339)
+		query += ` AND revoked_at IS NULL AND starts_at <= ? AND expires_at > ?`
+		args = append(args, now, now)
+	}
+	query += ` ORDER BY created_at DESC`
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing delegations: %w", err)
+	}
+	defer rows.Close()
+	return scanDelegations(rows)
+}
+
+// ListActiveDelegationsTx returns delegations in effect at t for a project within a transaction.
+func (db *DB) ListActiveDelegationsTx(tx *sql.Tx, projectPath string, t time.Time) ([]*Delegation, error) {
+	ts := t.UTC().Format(time.RFC3339)
+	rows, err := tx.Query(`
+		SELECT id, project_path, from_agent, from_session_id, to_agent, reason,
+		       starts_at, expires_at, created_at, revoked_at, signature
+		FROM delegations
+		WHERE project_path = ? AND revoked_at IS NULL AND starts_at <= ? AND expires_at > ?
+		ORDER BY created_at ASC
+	`, projectPath, ts, ts)
+	if err != nil {
+		return nil, fmt.Errorf("listing active delegations: %w", err)
+	}
+	defer rows.Close()
+	return scanDelegations(rows)
+}
+
+func scanDelegations(rows *sql.Rows) ([]*Delegation, error) {
+	var list []*Delegation
+	for rows.Next() {
+		d := &Delegation{}
+		var fromSession, reason, revokedAt, signature sql.NullString
+		var startsAt, expiresAt, createdAt string
+		if err := rows.Scan(&d.ID, &d.ProjectPath, &d.FromAgent, &fromSession, &d.ToAgent, &reason,
+			&startsAt, &expiresAt, &createdAt, &revokedAt, &signature); err != nil {
+			return nil, fmt.Errorf("scanning delegation: %w", err)
+		}
+		d.FromSessionID = fromSession.String
+		d.Reason = reason.String
+		d.Signature = signature.String
+		d.StartsAt, _ = time.Parse(time.RFC3339, startsAt)
+		d.ExpiresAt, _ = time.Parse(time.RFC3339, expiresAt)
+		d.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		if revokedAt.Valid {
+			t, err := time.Parse(time.RFC3339, revokedAt.String)
+			if err == nil {
+				d.RevokedAt = &t
+			}
+		}
+		list = append(list, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
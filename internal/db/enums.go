@@ -123,4 +123,6 @@ const (
 	AttachmentTypeContext AttachmentType = "context"
 	// AttachmentTypeScreenshot is a screenshot.
 	AttachmentTypeScreenshot AttachmentType = "screenshot"
+	// AttachmentTypeTranscript is a short, redacted agent transcript snippet.
+	AttachmentTypeTranscript AttachmentType = "transcript"
 )
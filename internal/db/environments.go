@@ -0,0 +1,150 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrRequestEnvironmentNotFound indicates no environment was pinned for a
+// request.
+var ErrRequestEnvironmentNotFound = errors.New("request environment not found")
+
+// RequestEnvironment pins where and with what a request was created, so
+// execution in a swapped directory or environment can be caught.
+type RequestEnvironment struct {
+	// RequestID is the request this environment belongs to.
+	RequestID string `json:"request_id"`
+	// Cwd is the command's working directory as given.
+	Cwd string `json:"cwd"`
+	// CwdReal is Cwd with symlinks resolved, when it could be.
+	CwdReal string `json:"cwd_real,omitempty"`
+	// Env holds the pinned variables, "" for unset ones. It is nil when the
+	// requester's environment was not known.
+	Env map[string]string `json:"env,omitempty"`
+	// CreatedAt is when the environment was recorded.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SetRequestEnvironment records (or replaces) the environment for a request.
+func (db *DB) SetRequestEnvironment(e *RequestEnvironment) error {
+	if e.RequestID == "" {
+		return fmt.Errorf("request environment requires request id")
+	}
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = db.Now()
+	}
+	return insertRequestEnvironment(db, e)
+}
+
+func insertRequestEnvironment(x execer, e *RequestEnvironment) error {
+	var envJSON sql.NullString
+	if e.Env != nil {
+		data, err := json.Marshal(e.Env)
+		if err != nil {
+			return fmt.Errorf("encoding pinned env: %w", err)
+		}
+		envJSON = sql.NullString{String: string(data), Valid: true}
+	}
+
+	_, err := x.Exec(`
+		INSERT OR REPLACE INTO request_environments (request_id, cwd, cwd_real, env_json, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, e.RequestID, e.Cwd, nullString(e.CwdReal), envJSON, e.CreatedAt.Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("recording request environment: %w", err)
+	}
+	return nil
+}
+
+// GetRequestEnvironment returns the environment recorded for a request.
+func (db *DB) GetRequestEnvironment(requestID string) (*RequestEnvironment, error) {
+	e := &RequestEnvironment{}
+	var cwdReal, envJSON sql.NullString
+	var created string
+	err := db.QueryRow(`
+		SELECT request_id, cwd, cwd_real, env_json, created_at
+		FROM request_environments
+		WHERE request_id = ?
+	`, requestID).Scan(&e.RequestID, &e.Cwd, &cwdReal, &envJSON, &created)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRequestEnvironmentNotFound
+		}
+		return nil, fmt.Errorf("getting request environment: %w", err)
+	}
+	e.CwdReal = cwdReal.String
+	if envJSON.Valid {
+		if err := json.Unmarshal([]byte(envJSON.String), &e.Env); err != nil {
+			return nil, fmt.Errorf("decoding pinned env: %w", err)
+		}
+	}
+	e.CreatedAt, _ = time.Parse(time.RFC3339, created)
+	return e, nil
+}
+
+// ExecutionDrift records one difference between a request's pinned
+// environment and the one it was executed from.
+type ExecutionDrift struct {
+	ID        int64  `json:"id"`
+	RequestID string `json:"request_id"`
+	// Kind is what drifted: cwd, binary or env:NAME.
+	Kind     string `json:"kind"`
+	Recorded string `json:"recorded"`
+	Current  string `json:"current"`
+	// AllowedBy names the human who allowed execution despite the drift;
+	// empty when execution was blocked.
+	AllowedBy  string    `json:"allowed_by,omitempty"`
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// RecordExecutionDrift stores a drift.
+func (db *DB) RecordExecutionDrift(d *ExecutionDrift) error {
+	if d.RequestID == "" || d.Kind == "" {
+		return fmt.Errorf("execution drift requires request id and kind")
+	}
+	if d.DetectedAt.IsZero() {
+		d.DetectedAt = db.Now()
+	}
+	result, err := db.Exec(`
+		INSERT INTO execution_drifts (request_id, kind, recorded, current, allowed_by, detected_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, d.RequestID, d.Kind, d.Recorded, d.Current, nullString(d.AllowedBy), d.DetectedAt.Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("recording execution drift: %w", err)
+	}
+	if d.ID, err = result.LastInsertId(); err != nil {
+		return fmt.Errorf("getting last insert id: %w", err)
+	}
+	return nil
+}
+
+// ListExecutionDrifts returns a request's drifts, oldest first.
+func (db *DB) ListExecutionDrifts(requestID string) ([]*ExecutionDrift, error) {
+	rows, err := db.Query(`
+		SELECT id, request_id, kind, recorded, current, allowed_by, detected_at
+		FROM execution_drifts
+		WHERE request_id = ?
+		ORDER BY id
+	`, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("listing execution drifts: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*ExecutionDrift
+	for rows.Next() {
+		d := &ExecutionDrift{}
+		var recorded, current, allowedBy sql.NullString
+		var detected string
+		if err := rows.Scan(&d.ID, &d.RequestID, &d.Kind, &recorded, &current, &allowedBy, &detected); err != nil {
+			return nil, fmt.Errorf("scanning execution drift: %w", err)
+		}
+		d.Recorded, d.Current, d.AllowedBy = recorded.String, current.String, allowedBy.String
+		d.DetectedAt, _ = time.Parse(time.RFC3339, detected)
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,69 @@
+package db
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRequestEnvironment(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, req := createTestRequest(t, db)
+
+	if _, err := db.GetRequestEnvironment(req.ID); !errors.Is(err, ErrRequestEnvironmentNotFound) {
+		t.Fatalf("expected ErrRequestEnvironmentNotFound, got %v", err)
+	}
+	if err := db.SetRequestEnvironment(&RequestEnvironment{Cwd: "/repo"}); err == nil {
+		t.Fatal("expected error without request id")
+	}
+
+	e := &RequestEnvironment{
+		RequestID: req.ID,
+		Cwd:       "/repo",
+		CwdReal:   "/srv/repo",
+		Env:       map[string]string{"KUBECONFIG": "/home/me/.kube/prod", "AWS_PROFILE": ""},
+	}
+	if err := db.SetRequestEnvironment(e); err != nil {
+		t.Fatalf("SetRequestEnvironment failed: %v", err)
+	}
+	got, err := db.GetRequestEnvironment(req.ID)
+	if err != nil {
+		t.Fatalf("GetRequestEnvironment failed: %v", err)
+	}
+	if got.Cwd != "/repo" || got.CwdReal != "/srv/repo" || len(got.Env) != 2 || got.Env["KUBECONFIG"] != "/home/me/.kube/prod" || got.CreatedAt.IsZero() {
+		t.Fatalf("unexpected environment: %+v", got)
+	}
+
+	// An unknown environment stays nil rather than empty.
+	if err := db.SetRequestEnvironment(&RequestEnvironment{RequestID: req.ID, Cwd: "/repo"}); err != nil {
+		t.Fatalf("SetRequestEnvironment (replace) failed: %v", err)
+	}
+	if got, err := db.GetRequestEnvironment(req.ID); err != nil || got.Env != nil || got.CwdReal != "" {
+		t.Fatalf("expected replaced environment, got %+v (err %v)", got, err)
+	}
+}
+
+func TestExecutionDrifts(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, req := createTestRequest(t, db)
+
+	if err := db.RecordExecutionDrift(&ExecutionDrift{RequestID: req.ID}); err == nil {
+		t.Fatal("expected error without kind")
+	}
+	for _, allowedBy := range []string{"", "alice"} {
+		d := &ExecutionDrift{RequestID: req.ID, Kind: "cwd", Recorded: "/repo", Current: "/tmp", AllowedBy: allowedBy}
+		if err := db.RecordExecutionDrift(d); err != nil || d.ID == 0 {
+			t.Fatalf("RecordExecutionDrift: id %d, %v", d.ID, err)
+		}
+	}
+	list, err := db.ListExecutionDrifts(req.ID)
+	if err != nil {
+		t.Fatalf("ListExecutionDrifts: %v", err)
+	}
+	if len(list) != 2 || list[0].AllowedBy != "" || list[1].AllowedBy != "alice" || list[1].Current != "/tmp" || list[1].DetectedAt.IsZero() {
+		t.Fatalf("unexpected drifts: %+v", list)
+	}
+}
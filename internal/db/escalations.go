@@ -0,0 +1,120 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrHumanEscalationNotFound indicates a request has not been paged to a human.
+var ErrHumanEscalationNotFound = errors.New("human escalation not found")
+
+// HumanEscalation records that a request was paged to a human because no
+// agent reviewer acted on it within the inactivity window.
+type HumanEscalation struct {
+	// RequestID is the request awaiting a human.
+	RequestID string `json:"request_id"`
+	// Reason explains why the request was escalated.
+	Reason string `json:"reason"`
+	// Channels lists the notification channels that were paged (e.g. desktop, webhook).
+	Channels []string `json:"channels,omitempty"`
+	// PagedAt is when the human was paged.
+	PagedAt time.Time `json:"paged_at"`
+}
+
+// MarkAwaitingHuman flags a request as awaiting a human.
+// It returns false if the request was already flagged.
+func (db *DB) MarkAwaitingHuman(e *HumanEscalation) (bool, error) {
+	if e.RequestID == "" || e.Reason == "" {
+		return false, fmt.Errorf("human escalation requires request id and reason")
+	}
+	if e.PagedAt.IsZero() {
+		e.PagedAt = db.Now()
+	}
+
+	result, err := db.Exec(`
+		INSERT OR IGNORE INTO human_escalations (request_id, reason, channels, paged_at)
+		VALUES (?, ?, ?, ?)
+	`, e.RequestID, e.Reason, nullString(strings.Join(e.Channels, ",")), e.PagedAt.Format(time.RFC3339))
+	if err != nil {
+		return false, fmt.Errorf("marking request awaiting human: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("checking human escalation insert: %w", err)
+	}
+	if n == 0 {
+		return false, nil
+	}
+	details := "paged a human: " + e.Reason
+	if len(e.Channels) > 0 {
+		details += " (" + strings.Join(e.Channels, ", ") + ")"
+	}
+	if err := insertRequestEvent(db, &RequestEvent{
+		RequestID: e.RequestID,
+		Type:      RequestEventEscalated,
+		Details:   details,
+		CreatedAt: e.PagedAt,
+	}); err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
+// GetHumanEscalation returns the escalation record for a request.
+func (db *DB) GetHumanEscalation(requestID string) (*HumanEscalation, error) {
+	e := &HumanEscalation{}
+	var channels sql.NullString
+	var paged string
+	err := db.QueryRow(`
+		SELECT request_id, reason, channels, paged_at
+		FROM human_escalations
+		WHERE request_id = ?
+	`, requestID).Scan(&e.RequestID, &e.Reason, &channels, &paged)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrHumanEscalationNotFound
+		}
+		return nil, fmt.Errorf("getting human escalation: %w", err)
+	}
+	if channels.Valid && channels.String != "" {
+		e.Channels = strings.Split(channels.String, ",")
+	}
+	e.PagedAt, _ = time.Parse(time.RFC3339, paged)
+	return e, nil
+}
+
+// AwaitingHumanRequestIDs returns the subset of requestIDs that are flagged as
+// awaiting a human.
+func (db *DB) AwaitingHumanRequestIDs(requestIDs []string) (map[string]bool, error) {
+	flagged := make(map[string]bool)
+	if len(requestIDs) == 0 {
+		return flagged, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(requestIDs)), ",")
+	args := make([]any, len(requestIDs))
+	for i, id := range requestIDs {
+		args[i] = id
+	}
+
+	rows, err := db.Query(`
+		SELECT request_id FROM human_escalations
+		WHERE request_id IN (`+placeholders+`)
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing human escalations: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scanning human escalation: %w", err)
+		}
+		flagged[id] = true
+	}
+	return flagged, rows.Err()
+}
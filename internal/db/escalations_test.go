@@ -0,0 +1,57 @@
+// Package db tests for human escalation operations.
+package db
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHumanEscalations(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, req := createTestRequest(t, db)
+
+	if _, err := db.GetHumanEscalation(req.ID); !errors.Is(err, ErrHumanEscalationNotFound) {
+		t.Fatalf("expected ErrHumanEscalationNotFound, got %v", err)
+	}
+	if _, err := db.MarkAwaitingHuman(&HumanEscalation{RequestID: req.ID}); err == nil {
+		t.Fatal("expected error without reason")
+	}
+
+	created, err := db.MarkAwaitingHuman(&HumanEscalation{
+		RequestID: req.ID,
+		Reason:    "no reviewer activity",
+		Channels:  []string{"desktop", "webhook"},
+	})
+	if err != nil || !created {
+		t.Fatalf("MarkAwaitingHuman = %v, %v; want true, nil", created, err)
+	}
+
+	// Marking again is a no-op.
+	created, err = db.MarkAwaitingHuman(&HumanEscalation{RequestID: req.ID, Reason: "again"})
+	if err != nil || created {
+		t.Fatalf("second MarkAwaitingHuman = %v, %v; want false, nil", created, err)
+	}
+
+	got, err := db.GetHumanEscalation(req.ID)
+	if err != nil {
+		t.Fatalf("GetHumanEscalation failed: %v", err)
+	}
+	if got.Reason != "no reviewer activity" || len(got.Channels) != 2 || got.PagedAt.IsZero() {
+		t.Fatalf("unexpected escalation: %+v", got)
+	}
+
+	flagged, err := db.AwaitingHumanRequestIDs([]string{req.ID, "missing"})
+	if err != nil {
+		t.Fatalf("AwaitingHumanRequestIDs failed: %v", err)
+	}
+	if !flagged[req.ID] || flagged["missing"] || len(flagged) != 1 {
+		t.Fatalf("unexpected flagged set: %v", flagged)
+	}
+
+	empty, err := db.AwaitingHumanRequestIDs(nil)
+	if err != nil || len(empty) != 0 {
+		t.Fatalf("expected empty set for no ids, got %v (err %v)", empty, err)
+	}
+}
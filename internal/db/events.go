@@ -0,0 +1,144 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DaemonEvent is an event broadcast by the daemon to its subscribers.
+type DaemonEvent struct {
+	// ID increases with every stored event.
+	ID int64 `json:"id"`
+	// Type is the event type (e.g. request_created).
+	Type string `json:"type"`
+	// PayloadJSON is the JSON-encoded event payload, if any.
+	PayloadJSON string `json:"payload_json,omitempty"`
+	// CreatedAt is when the event was broadcast.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// InsertDaemonEvents stores events in a single transaction, setting their IDs.
+func (db *DB) InsertDaemonEvents(events []*DaemonEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return db.Transaction(func(tx *sql.Tx) error {
+		return insertDaemonEvents(tx, events)
+	})
+}
+
+func insertDaemonEvents(tx *sql.Tx, events []*DaemonEvent) error {
+	stmt, err := tx.Prepare(`INSERT INTO daemon_events (type, payload_json, created_at) VALUES (?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("preparing daemon event insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, e := range events {
+		if e.Type == "" {
+			return fmt.Errorf("daemon event requires a type")
+		}
+		if e.CreatedAt.IsZero() {
+			e.CreatedAt = time.Now().UTC()
+		}
+		result, err := stmt.Exec(e.Type, nullString(e.PayloadJSON), e.CreatedAt.UTC().Format(time.RFC3339))
+		if err != nil {
+			return fmt.Errorf("inserting daemon event: %w", err)
+		}
+		if e.ID, err = result.LastInsertId(); err != nil {
+			return fmt.Errorf("reading daemon event id: %w", err)
+		}
+	}
+	return nil
+}
+
+// errProbeRollback undoes the row written by ProbeWriteRead.
+var errProbeRollback = errors.New("probe rollback")
+
+// ProbeWriteRead checks that the database accepts writes and serves them
+// back: it stores a daemon event, reads it and rolls the transaction back,
+// so nothing is left behind.
+func (db *DB) ProbeWriteRead() error {
+	err := db.Transaction(func(tx *sql.Tx) error {
+		e := &DaemonEvent{Type: "health_probe", CreatedAt: db.Now()}
+		if err := insertDaemonEvents(tx, []*DaemonEvent{e}); err != nil {
+			return err
+		}
+		var got string
+		if err := tx.QueryRow(`SELECT type FROM daemon_events WHERE id = ?`, e.ID).Scan(&got); err != nil {
+			return fmt.Errorf("reading probe event: %w", err)
+		}
+		if got != e.Type {
+			return fmt.Errorf("probe event read back as %q", got)
+		}
+		return errProbeRollback
+	})
+	if errors.Is(err, errProbeRollback) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("probing database: %w", err)
+	}
+	return nil
+}
+
+// ListDaemonEvents returns up to limit events with IDs greater than afterID,
+// oldest first.
+func (db *DB) ListDaemonEvents(afterID int64, limit int) ([]*DaemonEvent, error) {
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	rows, err := db.Query(`
+		SELECT id, type, payload_json, created_at
+		FROM daemon_events
+		WHERE id > ?
+		ORDER BY id
+		LIMIT ?
+	`, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("listing daemon events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*DaemonEvent
+	for rows.Next() {
+		e := &DaemonEvent{}
+		var payload sql.NullString
+		var created string
+		if err := rows.Scan(&e.ID, &e.Type, &payload, &created); err != nil {
+			return nil, fmt.Errorf("scanning daemon event: %w", err)
+		}
+		e.PayloadJSON = payload.String
+		e.CreatedAt, _ = time.Parse(time.RFC3339, created)
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// UpdateSessionHeartbeats sets last_active_at for many sessions in a single
+// transaction. Sessions that ended or do not exist are skipped.
+func (db *DB) UpdateSessionHeartbeats(heartbeats map[string]time.Time) error {
+	if len(heartbeats) == 0 {
+		return nil
+	}
+	return db.Transaction(func(tx *sql.Tx) error {
+		return updateSessionHeartbeats(tx, heartbeats)
+	})
+}
+
+func updateSessionHeartbeats(tx *sql.Tx, heartbeats map[string]time.Time) error {
+	stmt, err := tx.Prepare(`UPDATE sessions SET last_active_at = ? WHERE id = ? AND ended_at IS NULL`)
+	if err != nil {
+		return fmt.Errorf("preparing session heartbeat update: %w", err)
+	}
+	defer stmt.Close()
+
+	for id, at := range heartbeats {
+		if _, err := stmt.Exec(at.UTC().Format(time.RFC3339), id); err != nil {
+			return fmt.Errorf("updating session heartbeat: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	"unicode"
+)
+
+// ErrIdempotencyKeyNotFound is returned when a session has no live request
+// for an idempotency key.
+var ErrIdempotencyKeyNotFound = errors.New("idempotency key not found")
+
+// ErrIdempotencyKeyReused is returned when an idempotency key is replayed
+// with a different command than the request it created.
+var ErrIdempotencyKeyReused = errors.New("idempotency key was already used for a different command")
+
+// maxIdempotencyKeyLen bounds client-supplied keys; UUIDs and request
+// hashes fit comfortably.
+const maxIdempotencyKeyLen = 255
+
+// ValidateIdempotencyKey checks a client-supplied idempotency key: non-empty
+// printable text without whitespace.
+func ValidateIdempotencyKey(key string) error {
+	if key == "" {
+		return errors.New("idempotency key cannot be empty")
+	}
+	if len(key) > maxIdempotencyKeyLen {
+		return fmt.Errorf("idempotency key too long (max %d chars)", maxIdempotencyKeyLen)
+	}
+	for _, r := range key {
+		if unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return fmt.Errorf("invalid idempotency key %q: whitespace and control characters are not allowed", key)
+		}
+	}
+	return nil
+}
+
+// LookupIdempotentRequest returns the request sessionID created with key, if
+// the key has not expired. It fails with ErrIdempotencyKeyReused when that
+// request was for a command with a different hash.
+func (db *DB) LookupIdempotentRequest(sessionID, key, commandHash string) (*Request, error) {
+	var req *Request
+	err := db.Transaction(func(tx *sql.Tx) error {
+		var err error
+		req, err = db.lookupIdempotentRequestTx(tx, sessionID, key, commandHash)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func (db *DB) lookupIdempotentRequestTx(tx *sql.Tx, sessionID, key, commandHash string) (*Request, error) {
+	var requestID, storedHash string
+	err := tx.QueryRow(`
+		SELECT request_id, command_hash FROM idempotency_keys
+		WHERE session_id = ? AND key = ? AND expires_at > ?
+	`, sessionID, key, db.Now().Format(time.RFC3339)).Scan(&requestID, &storedHash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrIdempotencyKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("looking up idempotency key: %w", err)
+	}
+	if storedHash != commandHash {
+		return nil, fmt.Errorf("%w (request %s)", ErrIdempotencyKeyReused, requestID)
+	}
+	return db.GetRequestTx(tx, requestID)
+}
+
+// CreateRequestIdempotent creates r unless its requestor already submitted
+// the same command under key within ttl, in which case it returns the
+// request created then and replayed=true. Expired keys are discarded first,
+// so a key can be reused once its TTL has passed. The lookup and insert
+// share one write transaction, so concurrent retries create one request.
+func (db *DB) CreateRequestIdempotent(r *Request, key string, ttl time.Duration) (*Request, bool, error) {
+	if err := ValidateIdempotencyKey(key); err != nil {
+		return nil, false, fmt.Errorf("creating request: %w", err)
+	}
+	if err := db.prepareNewRequest(r); err != nil {
+		return nil, false, fmt.Errorf("creating request: %w", err)
+	}
+
+	var existing *Request
+	err := db.Transaction(func(tx *sql.Tx) error {
+		// Writing first takes the write lock, so a concurrent retry waits
+		// here and then sees the key this transaction inserts.
+		if _, err := tx.Exec(`DELETE FROM idempotency_keys WHERE expires_at <= ?`,
+			r.CreatedAt.Format(time.RFC3339)); err != nil {
+			return fmt.Errorf("pruning idempotency keys: %w", err)
+		}
+
+		req, err := db.lookupIdempotentRequestTx(tx, r.RequestorSessionID, key, r.Command.Hash)
+		if err == nil {
+			existing = req
+			return nil
+		}
+		if !errors.Is(err, ErrIdempotencyKeyNotFound) {
+			return err
+		}
+
+		if err := insertRequestTx(tx, r); err != nil {
+			return err
+		}
+		_, err = tx.Exec(`
+			INSERT INTO idempotency_keys (session_id, key, request_id, command_hash, created_at, expires_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, r.RequestorSessionID, key, r.ID, r.Command.Hash,
+			r.CreatedAt.Format(time.RFC3339), r.CreatedAt.Add(ttl).Format(time.RFC3339))
+		return err
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("creating request: %w", err)
+	}
+	if existing != nil {
+		return existing, true, nil
+	}
+	return r, false, nil
+}
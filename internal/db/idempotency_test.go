@@ -0,0 +1,142 @@
+package db
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/clock"
+)
+
+func createIdempotencyTestSession(t *testing.T, db *DB, agent string) *Session {
+	t.Helper()
+	sess := &Session{AgentName: agent, Program: "claude-code", Model: "opus-4.5", ProjectPath: "/test/project"}
+	if err := db.CreateSession(sess); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	return sess
+}
+
+func newIdempotentRequest(sessionID, command string) *Request {
+	return &Request{
+		ProjectPath:        "/test/project",
+		Command:            CommandSpec{Raw: command, Cwd: "/test/project"},
+		RiskTier:           RiskTierDangerous,
+		RequestorSessionID: sessionID,
+		RequestorAgent:     "TestAgent",
+		RequestorModel:     "test-model",
+		MinApprovals:       1,
+	}
+}
+
+func TestCreateRequestIdempotent_Replays(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	sess := createIdempotencyTestSession(t, db, "GreenLake")
+
+	first, replayed, err := db.CreateRequestIdempotent(newIdempotentRequest(sess.ID, "rm -rf ./build"), "k1", time.Hour)
+	if err != nil || replayed {
+		t.Fatalf("first create: replayed=%v err=%v", replayed, err)
+	}
+
+	again, replayed, err := db.CreateRequestIdempotent(newIdempotentRequest(sess.ID, "rm -rf ./build"), "k1", time.Hour)
+	if err != nil || !replayed {
+		t.Fatalf("retry: replayed=%v err=%v", replayed, err)
+	}
+	if again.ID != first.ID {
+		t.Fatalf("retry returned %s, want original %s", again.ID, first.ID)
+	}
+
+	if _, _, err := db.CreateRequestIdempotent(newIdempotentRequest(sess.ID, "rm -rf ./dist"), "k1", time.Hour); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Fatalf("expected ErrIdempotencyKeyReused, got %v", err)
+	}
+
+	// Keys are scoped to the session that used them.
+	other := createIdempotencyTestSession(t, db, "BlueLake")
+	if _, replayed, err := db.CreateRequestIdempotent(newIdempotentRequest(other.ID, "rm -rf ./build"), "k1", time.Hour); err != nil || replayed {
+		t.Fatalf("other session: replayed=%v err=%v", replayed, err)
+	}
+}
+
+func TestCreateRequestIdempotent_ExpiredKeyCreatesAnew(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	now := time.Now().UTC().Truncate(time.Second)
+	db.SetClock(clock.Func(func() time.Time { return now }))
+	sess := createIdempotencyTestSession(t, db, "GreenLake")
+
+	first, _, err := db.CreateRequestIdempotent(newIdempotentRequest(sess.ID, "rm -rf ./build"), "k1", time.Minute)
+	if err != nil {
+		t.Fatalf("first create: %v", err)
+	}
+	if _, err := db.LookupIdempotentRequest(sess.ID, "k1", first.Command.Hash); err != nil {
+		t.Fatalf("lookup before expiry: %v", err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := db.LookupIdempotentRequest(sess.ID, "k1", first.Command.Hash); !errors.Is(err, ErrIdempotencyKeyNotFound) {
+		t.Fatalf("expected ErrIdempotencyKeyNotFound after expiry, got %v", err)
+	}
+	second, replayed, err := db.CreateRequestIdempotent(newIdempotentRequest(sess.ID, "rm -rf ./dist"), "k1", time.Minute)
+	if err != nil || replayed {
+		t.Fatalf("create after expiry: replayed=%v err=%v", replayed, err)
+	}
+	if second.ID == first.ID {
+		t.Fatal("expired key replayed the old request")
+	}
+}
+
+func TestCreateRequestIdempotent_ConcurrentRetries(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	sess := createIdempotencyTestSession(t, db, "GreenLake")
+
+	var wg sync.WaitGroup
+	ids := make([]string, 8)
+	errs := make([]error, len(ids))
+	for i := range ids {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req, _, err := db.CreateRequestIdempotent(newIdempotentRequest(sess.ID, "rm -rf ./build"), "k1", time.Hour)
+			errs[i] = err
+			if req != nil {
+				ids[i] = req.ID
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("retry %d: %v", i, err)
+		}
+		if ids[i] != ids[0] {
+			t.Fatalf("retry %d created %s, want %s", i, ids[i], ids[0])
+		}
+	}
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM requests WHERE requestor_session_id = ?`, sess.ID).Scan(&count); err != nil {
+		t.Fatalf("count requests: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("stored %d requests, want 1", count)
+	}
+}
+
+func TestValidateIdempotencyKey(t *testing.T) {
+	valid := []string{"k1", "0b6f3c1e-2f0a-4c55-9d0e-0e5f8b1c2d3a", strings.Repeat("a", maxIdempotencyKeyLen)}
+	for _, key := range valid {
+		if err := ValidateIdempotencyKey(key); err != nil {
+			t.Errorf("ValidateIdempotencyKey(%q) = %v", key, err)
+		}
+	}
+	invalid := []string{"", "has space", "tab\there", "nl\n", strings.Repeat("a", maxIdempotencyKeyLen+1)}
+	for _, key := range invalid {
+		if err := ValidateIdempotencyKey(key); err == nil {
+			t.Errorf("ValidateIdempotencyKey(%q) accepted", key)
+		}
+	}
+}
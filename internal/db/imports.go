@@ -0,0 +1,196 @@
+package db
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrRequestAlreadyImported is returned when a record from an import source
+// was imported before.
+var ErrRequestAlreadyImported = errors.New("request already imported")
+
+// RequestImport records where an imported request came from.
+type RequestImport struct {
+	// Source names the approval process the request was imported from,
+	// e.g. "change-board".
+	Source string `json:"source"`
+	// ExternalID is the request's ID in that process.
+	ExternalID string    `json:"external_id"`
+	ImportedAt time.Time `json:"imported_at"`
+}
+
+// ImportRequest stores a finished request, and its reviews, brought in from
+// another approval process. Unlike CreateRequest it keeps the timestamps it
+// is given, so history reads as it happened. Requestors, reviewers and
+// executors get ended sessions of their own, named after the source, since
+// they never had slb sessions. Imported reviews carry no signature.
+func (db *DB) ImportRequest(r *Request, reviews []*Review) error {
+	imp := r.Import
+	if imp == nil || imp.Source == "" || imp.ExternalID == "" {
+		return fmt.Errorf("importing request: source and external id are required")
+	}
+	if !r.Status.IsTerminal() {
+		return fmt.Errorf("importing request %s: status %q is not a finished status", imp.ExternalID, r.Status)
+	}
+	if r.CreatedAt.IsZero() {
+		return fmt.Errorf("importing request %s: creation time is required", imp.ExternalID)
+	}
+	for key, value := range r.Labels {
+		if err := ValidateLabel(key, value); err != nil {
+			return err
+		}
+	}
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	if r.Command.Hash == "" {
+		r.Command.Hash = ComputeCommandHash(r.Command)
+	}
+	if imp.ImportedAt.IsZero() {
+		imp.ImportedAt = db.Now()
+	}
+	r.Version = 1
+
+	err := db.Transaction(func(tx *sql.Tx) error {
+		var existing string
+		err := tx.QueryRow(`SELECT id FROM requests WHERE import_source = ? AND import_external_id = ?`,
+			imp.Source, imp.ExternalID).Scan(&existing)
+		if err == nil {
+			return fmt.Errorf("%w: %s %s is request %s", ErrRequestAlreadyImported, imp.Source, imp.ExternalID, existing)
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		if r.RequestorSessionID, err = importSessionTx(tx, imp.Source, r.ProjectPath, r.RequestorAgent, r.CreatedAt); err != nil {
+			return err
+		}
+		if err := insertRequestTx(tx, r); err != nil {
+			return err
+		}
+
+		for _, rev := range reviews {
+			if rev.ID == "" {
+				rev.ID = uuid.New().String()
+			}
+			if rev.CreatedAt.IsZero() {
+				rev.CreatedAt = r.CreatedAt
+			}
+			if rev.SignatureTimestamp.IsZero() {
+				rev.SignatureTimestamp = rev.CreatedAt
+			}
+			rev.RequestID = r.ID
+			if rev.ReviewerSessionID, err = importSessionTx(tx, imp.Source, r.ProjectPath, rev.ReviewerAgent, rev.CreatedAt); err != nil {
+				return err
+			}
+			if err := insertReview(tx, rev); err != nil {
+				if isUniqueConstraintError(err) {
+					return fmt.Errorf("importing request %s: %s reviewed it twice", imp.ExternalID, rev.ReviewerAgent)
+				}
+				return err
+			}
+			if err := insertRequestEvent(tx, reviewEvent(rev)); err != nil {
+				return err
+			}
+		}
+
+		exec := r.Execution
+		if exec == nil {
+			exec = &Execution{}
+		} else if exec.ExecutedByAgent != "" && exec.ExecutedAt != nil {
+			if exec.ExecutedBySessionID, err = importSessionTx(tx, imp.Source, r.ProjectPath, exec.ExecutedByAgent, *exec.ExecutedAt); err != nil {
+				return err
+			}
+		}
+		if _, err := tx.Exec(`
+			UPDATE requests SET
+				import_source = ?, import_external_id = ?, imported_at = ?, resolved_at = ?,
+				execution_log_path = ?, execution_exit_code = ?, execution_duration_ms = ?,
+				execution_executed_at = ?, execution_executed_by_session_id = ?,
+				execution_executed_by_agent = ?, execution_executed_by_model = ?
+			WHERE id = ?
+		`,
+			imp.Source, imp.ExternalID, imp.ImportedAt.Format(time.RFC3339), formatTimePtr(r.ResolvedAt),
+			nullString(exec.LogPath), exec.ExitCode, exec.DurationMs,
+			formatTimePtr(exec.ExecutedAt), nullString(exec.ExecutedBySessionID),
+			nullString(exec.ExecutedByAgent), nullString(exec.ExecutedByModel),
+			r.ID,
+		); err != nil {
+			return err
+		}
+
+		finishedAt := r.CreatedAt
+		if r.ResolvedAt != nil {
+			finishedAt = *r.ResolvedAt
+		}
+		return insertRequestEvent(tx, &RequestEvent{
+			RequestID: r.ID,
+			Type:      string(r.Status),
+			Details:   "imported from " + imp.Source,
+			CreatedAt: finishedAt,
+		})
+	})
+	if err != nil {
+		if errors.Is(err, ErrRequestAlreadyImported) {
+			return err
+		}
+		return fmt.Errorf("importing request: %w", err)
+	}
+	return nil
+}
+
+// importSessionTx returns the ended session standing in for agent in
+// imported requests from source, creating it the first time.
+func importSessionTx(tx *sql.Tx, source, projectPath, agent string, at time.Time) (string, error) {
+	if agent == "" {
+		agent = "unknown"
+	}
+	sum := sha256.Sum256([]byte(source + "\x00" + projectPath + "\x00" + agent))
+	id := "import-" + hex.EncodeToString(sum[:12])
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("generating session key: %w", err)
+	}
+	ts := at.Format(time.RFC3339)
+	if _, err := tx.Exec(`
+		INSERT OR IGNORE INTO sessions (id, agent_name, program, model, project_path, session_key, started_at, last_active_at, ended_at)
+		VALUES (?, ?, ?, '', ?, ?, ?, ?, ?)
+	`, id, agent, "import:"+source, projectPath, hex.EncodeToString(key), ts, ts, ts); err != nil {
+		return "", fmt.Errorf("creating import session: %w", err)
+	}
+	return id, nil
+}
+
+// parseRequestImport reads the import columns of a request row.
+func parseRequestImport(source, externalID, importedAt sql.NullString) *RequestImport {
+	if !source.Valid {
+		return nil
+	}
+	imp := &RequestImport{Source: source.String, ExternalID: externalID.String}
+	if importedAt.Valid {
+		imp.ImportedAt, _ = time.Parse(time.RFC3339, importedAt.String) //nolint:errcheck
+	}
+	return imp
+}
+
+// GetImportedRequest returns the request imported from source under
+// externalID.
+func (db *DB) GetImportedRequest(source, externalID string) (*Request, error) {
+	var id string
+	err := db.QueryRow(`SELECT id FROM requests WHERE import_source = ? AND import_external_id = ?`, source, externalID).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrRequestNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting imported request: %w", err)
+	}
+	return db.GetRequest(id)
+}
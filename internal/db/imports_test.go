@@ -0,0 +1,92 @@
+package db
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestImportRequest(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	requestedAt := time.Date(2024, 3, 1, 14, 5, 0, 0, time.UTC)
+	reviewedAt := requestedAt.Add(20 * time.Minute)
+	executedAt := reviewedAt.Add(time.Hour)
+	exitCode := 0
+	newImport := func() *Request {
+		return &Request{
+			ProjectPath:    "/proj",
+			Command:        CommandSpec{Raw: "kubectl delete ns staging", Cwd: "/proj", Shell: true},
+			RiskTier:       RiskTierCritical,
+			RequestorAgent: "alice",
+			Justification:  Justification{Reason: "retire staging"},
+			Status:         StatusExecuted,
+			MinApprovals:   1,
+			CreatedAt:      requestedAt,
+			ResolvedAt:     &executedAt,
+			Execution:      &Execution{ExecutedAt: &executedAt, ExecutedByAgent: "alice", ExitCode: &exitCode},
+			Import:         &RequestImport{Source: "change-board", ExternalID: "CHG-42"},
+		}
+	}
+
+	r := newImport()
+	reviews := []*Review{{ReviewerAgent: "bob", Decision: DecisionApprove, Comments: "ok", CreatedAt: reviewedAt}}
+	if err := db.ImportRequest(r, reviews); err != nil {
+		t.Fatalf("ImportRequest error = %v", err)
+	}
+
+	got, gotReviews, err := db.GetRequestWithReviews(r.ID)
+	if err != nil {
+		t.Fatalf("GetRequestWithReviews error = %v", err)
+	}
+	if !got.CreatedAt.Equal(requestedAt) || got.ResolvedAt == nil || !got.ResolvedAt.Equal(executedAt) {
+		t.Errorf("times not kept: created %v resolved %v", got.CreatedAt, got.ResolvedAt)
+	}
+	if got.Import == nil || got.Import.Source != "change-board" || got.Import.ExternalID != "CHG-42" || got.Import.ImportedAt.IsZero() {
+		t.Errorf("import tag = %+v", got.Import)
+	}
+	if got.Execution == nil || got.Execution.ExitCode == nil || *got.Execution.ExitCode != 0 || got.Execution.ExecutedBySessionID == "" {
+		t.Errorf("execution = %+v", got.Execution)
+	}
+	if len(gotReviews) != 1 || gotReviews[0].ReviewerAgent != "bob" || !gotReviews[0].CreatedAt.Equal(reviewedAt) || gotReviews[0].Signature != "" {
+		t.Errorf("reviews = %+v", gotReviews)
+	}
+
+	sess, err := db.GetSession(got.RequestorSessionID)
+	if err != nil {
+		t.Fatalf("GetSession error = %v", err)
+	}
+	if sess.AgentName != "alice" || sess.Program != "import:change-board" || sess.EndedAt == nil {
+		t.Errorf("import session = %+v", sess)
+	}
+
+	events, err := db.ListRequestEvents(r.ID)
+	if err != nil {
+		t.Fatalf("ListRequestEvents error = %v", err)
+	}
+	var types []string
+	for _, e := range events {
+		types = append(types, e.Type)
+	}
+	if len(types) != 3 || types[0] != RequestEventCreated || types[1] != RequestEventReviewed || types[2] != string(StatusExecuted) {
+		t.Errorf("events = %v", types)
+	}
+
+	if err := db.ImportRequest(newImport(), nil); !errors.Is(err, ErrRequestAlreadyImported) {
+		t.Errorf("re-import error = %v, want ErrRequestAlreadyImported", err)
+	}
+	if found, err := db.GetImportedRequest("change-board", "CHG-42"); err != nil || found.ID != r.ID {
+		t.Errorf("GetImportedRequest = %v, %v", found, err)
+	}
+	if _, err := db.GetImportedRequest("change-board", "CHG-43"); !errors.Is(err, ErrRequestNotFound) {
+		t.Errorf("GetImportedRequest(missing) error = %v", err)
+	}
+
+	pending := newImport()
+	pending.Import.ExternalID = "CHG-44"
+	pending.Status = StatusApproved
+	if err := db.ImportRequest(pending, nil); err == nil {
+		t.Error("expected an unfinished request to be refused")
+	}
+}
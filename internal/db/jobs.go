@@ -0,0 +1,180 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrDaemonJobNotFound is returned for a job the daemon never registered.
+var ErrDaemonJobNotFound = errors.New("daemon job not found")
+
+// DaemonJob is the stored state of one of the daemon's recurring jobs.
+type DaemonJob struct {
+	Name     string `json:"name"`
+	Schedule string `json:"schedule"`
+	Enabled  bool   `json:"enabled"`
+	// NextRunAt is when the job runs next; nil while it is disabled.
+	NextRunAt *time.Time `json:"next_run_at,omitempty"`
+	LastRunAt *time.Time `json:"last_run_at,omitempty"`
+	// LastDurationMS is how long the last run took.
+	LastDurationMS int64 `json:"last_duration_ms"`
+	// LastError is the last run's error, empty when it succeeded.
+	LastError string `json:"last_error,omitempty"`
+	Runs      int    `json:"runs"`
+	// Failures counts consecutive failed runs.
+	Failures  int       `json:"failures"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SaveDaemonJob stores a job's state, replacing what was stored.
+func (db *DB) SaveDaemonJob(j *DaemonJob) error {
+	if j.Name == "" || j.Schedule == "" {
+		return fmt.Errorf("daemon job requires name and schedule")
+	}
+	j.UpdatedAt = db.Now().UTC()
+	_, err := db.Exec(`
+		INSERT INTO daemon_jobs (name, schedule, enabled, next_run_at, last_run_at,
+		  last_duration_ms, last_error, runs, failures, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET
+		  schedule = excluded.schedule,
+		  enabled = excluded.enabled,
+		  next_run_at = excluded.next_run_at,
+		  last_run_at = excluded.last_run_at,
+		  last_duration_ms = excluded.last_duration_ms,
+		  last_error = excluded.last_error,
+		  runs = excluded.runs,
+		  failures = excluded.failures,
+		  updated_at = excluded.updated_at
+	`, j.Name, j.Schedule, j.Enabled, formatTimePtr(j.NextRunAt), formatTimePtr(j.LastRunAt),
+		j.LastDurationMS, nullString(j.LastError), j.Runs, j.Failures, j.UpdatedAt.Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("saving daemon job: %w", err)
+	}
+	return nil
+}
+
+// GetDaemonJob returns the stored state of a job.
+func (db *DB) GetDaemonJob(name string) (*DaemonJob, error) {
+	jobs, err := db.queryDaemonJobs(`WHERE name = ?`, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, ErrDaemonJobNotFound
+	}
+	return jobs[0], nil
+}
+
+// ListDaemonJobs returns every job the daemon has registered, by name.
+func (db *DB) ListDaemonJobs() ([]*DaemonJob, error) {
+	return db.queryDaemonJobs(`ORDER BY name`)
+}
+
+func (db *DB) queryDaemonJobs(where string, args ...any) ([]*DaemonJob, error) {
+	rows, err := db.Query(`
+		SELECT name, schedule, enabled, next_run_at, last_run_at, last_duration_ms,
+		  last_error, runs, failures, updated_at
+		FROM daemon_jobs `+where, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing daemon jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []*DaemonJob
+	for rows.Next() {
+		j := &DaemonJob{}
+		var next, last, lastErr sql.NullString
+		var updated string
+		if err := rows.Scan(&j.Name, &j.Schedule, &j.Enabled, &next, &last, &j.LastDurationMS,
+			&lastErr, &j.Runs, &j.Failures, &updated); err != nil {
+			return nil, fmt.Errorf("scanning daemon job: %w", err)
+		}
+		j.NextRunAt = parseNullTime(next)
+		j.LastRunAt = parseNullTime(last)
+		j.LastError = lastErr.String
+		j.UpdatedAt, _ = time.Parse(time.RFC3339, updated)
+		jobs = append(jobs, j)
+	}
+	return jobs, rows.Err()
+}
+
+func parseNullTime(s sql.NullString) *time.Time {
+	if !s.Valid {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, s.String)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// ReviewReminder is how many review reminders a pending request has had.
+type ReviewReminder struct {
+	RequestID  string    `json:"request_id"`
+	Reminders  int       `json:"reminders"`
+	LastSentAt time.Time `json:"last_sent_at"`
+}
+
+// ListReviewReminders returns the review reminders sent so far, keyed by
+// request ID.
+func (db *DB) ListReviewReminders() (map[string]*ReviewReminder, error) {
+	rows, err := db.Query(`SELECT request_id, reminders, last_sent_at FROM review_reminders`)
+	if err != nil {
+		return nil, fmt.Errorf("listing review reminders: %w", err)
+	}
+	defer rows.Close()
+
+	out := make(map[string]*ReviewReminder)
+	for rows.Next() {
+		r := &ReviewReminder{}
+		var sent string
+		if err := rows.Scan(&r.RequestID, &r.Reminders, &sent); err != nil {
+			return nil, fmt.Errorf("scanning review reminder: %w", err)
+		}
+		r.LastSentAt, _ = time.Parse(time.RFC3339, sent)
+		out[r.RequestID] = r
+	}
+	return out, rows.Err()
+}
+
+// RecordReviewReminder counts a reminder sent at at for a request.
+func (db *DB) RecordReviewReminder(requestID string, at time.Time) error {
+	_, err := db.Exec(`
+		INSERT INTO review_reminders (request_id, reminders, last_sent_at)
+		VALUES (?, 1, ?)
+		ON CONFLICT (request_id) DO UPDATE SET
+		  reminders = reminders + 1,
+		  last_sent_at = excluded.last_sent_at
+	`, requestID, at.UTC().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("recording review reminder: %w", err)
+	}
+	return nil
+}
+
+// PruneReviewReminders forgets the reminders of requests that are no
+// longer pending.
+func (db *DB) PruneReviewReminders() (int64, error) {
+	result, err := db.Exec(`
+		DELETE FROM review_reminders
+		WHERE request_id NOT IN (SELECT id FROM requests WHERE status = ?)
+	`, string(StatusPending))
+	if err != nil {
+		return 0, fmt.Errorf("pruning review reminders: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// PruneDaemonEvents deletes stored daemon events older than before and
+// returns how many were deleted.
+func (db *DB) PruneDaemonEvents(before time.Time) (int64, error) {
+	result, err := db.Exec(`DELETE FROM daemon_events WHERE created_at < ?`, before.UTC().Format(time.RFC3339))
+	if err != nil {
+		return 0, fmt.Errorf("pruning daemon events: %w", err)
+	}
+	return result.RowsAffected()
+}
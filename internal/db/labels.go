@@ -0,0 +1,187 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Label limits keep labels usable as filter keys and in one-line displays.
+const (
+	maxLabelKeyLen   = 63
+	maxLabelValueLen = 255
+)
+
+var labelKeyRe = regexp.MustCompile(`^[a-z0-9]([a-z0-9._/-]*[a-z0-9])?$`)
+
+// ValidateLabel checks a label key and value. Keys are lowercase
+// alphanumerics with '.', '_', '/' and '-' inside; values are free text
+// without newlines.
+func ValidateLabel(key, value string) error {
+	if len(key) > maxLabelKeyLen || !labelKeyRe.MatchString(key) {
+		return fmt.Errorf("invalid label key %q: use lowercase letters, digits, '.', '_', '/', '-' (max %d chars)", key, maxLabelKeyLen)
+	}
+	if len(value) > maxLabelValueLen {
+		return fmt.Errorf("label %q value too long (max %d chars)", key, maxLabelValueLen)
+	}
+	if strings.ContainsAny(value, "\r\n") {
+		return fmt.Errorf("label %q value cannot contain newlines", key)
+	}
+	return nil
+}
+
+// ParseLabels parses key=value pairs (e.g. from repeated --label flags).
+// A key given twice keeps the last value.
+func ParseLabels(pairs []string) (map[string]string, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+	labels := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok {
+			return nil, fmt.Errorf("invalid label %q: expected key=value", pair)
+		}
+		if err := ValidateLabel(key, value); err != nil {
+			return nil, err
+		}
+		labels[key] = value
+	}
+	return labels, nil
+}
+
+// LabelFilter selects requests by label. A filter without a value matches
+// any request carrying the key.
+type LabelFilter struct {
+	Key      string
+	Value    string
+	AnyValue bool
+}
+
+// ParseLabelFilters parses "key=value" and bare "key" filters.
+func ParseLabelFilters(specs []string) ([]LabelFilter, error) {
+	filters := make([]LabelFilter, 0, len(specs))
+	for _, spec := range specs {
+		key, value, hasValue := strings.Cut(spec, "=")
+		key = strings.TrimSpace(key)
+		if err := ValidateLabel(key, value); err != nil {
+			return nil, err
+		}
+		filters = append(filters, LabelFilter{Key: key, Value: value, AnyValue: !hasValue})
+	}
+	return filters, nil
+}
+
+// MatchLabels reports whether labels satisfy every filter.
+func MatchLabels(labels map[string]string, filters []LabelFilter) bool {
+	for _, f := range filters {
+		v, ok := labels[f.Key]
+		if !ok || (!f.AnyValue && v != f.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// FormatLabels renders labels as sorted "key=value" pairs joined by sep.
+func FormatLabels(labels map[string]string, sep string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + "=" + labels[k]
+	}
+	return strings.Join(parts, sep)
+}
+
+// insertLabelsTx stores a request's labels within a transaction. Labels
+// must already be validated.
+func insertLabelsTx(tx *sql.Tx, requestID string, labels map[string]string) error {
+	for key, value := range labels {
+		if _, err := tx.Exec(`
+			INSERT OR REPLACE INTO request_labels (request_id, key, value) VALUES (?, ?, ?)
+		`, requestID, key, value); err != nil {
+			return fmt.Errorf("storing label %q: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// GetRequestLabels returns the labels on a request (empty if none).
+func (db *DB) GetRequestLabels(requestID string) (map[string]string, error) {
+	byRequest, err := db.labelsForRequests([]string{requestID})
+	if err != nil {
+		return nil, err
+	}
+	return byRequest[requestID], nil
+}
+
+// LoadRequestLabels fills the Labels field of each request. Request reads do
+// not load labels by themselves; list views call this when they need them.
+func (db *DB) LoadRequestLabels(requests []*Request) error {
+	if len(requests) == 0 {
+		return nil
+	}
+	ids := make([]string, len(requests))
+	for i, r := range requests {
+		ids[i] = r.ID
+	}
+	byRequest, err := db.labelsForRequests(ids)
+	if err != nil {
+		return err
+	}
+	for _, r := range requests {
+		r.Labels = byRequest[r.ID]
+	}
+	return nil
+}
+
+// labelQueryChunk bounds the IN list size to stay under SQLite's host
+// parameter limit on large histories.
+const labelQueryChunk = 500
+
+func (db *DB) labelsForRequests(requestIDs []string) (map[string]map[string]string, error) {
+	byRequest := make(map[string]map[string]string)
+	for start := 0; start < len(requestIDs); start += labelQueryChunk {
+		end := min(start+labelQueryChunk, len(requestIDs))
+		if err := db.collectLabels(requestIDs[start:end], byRequest); err != nil {
+			return nil, err
+		}
+	}
+	return byRequest, nil
+}
+
+func (db *DB) collectLabels(requestIDs []string, byRequest map[string]map[string]string) error {
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(requestIDs)), ",")
+	args := make([]any, len(requestIDs))
+	for i, id := range requestIDs {
+		args[i] = id
+	}
+
+	rows, err := db.Query(`
+		SELECT request_id, key, value FROM request_labels
+		WHERE request_id IN (`+placeholders+`)
+	`, args...)
+	if err != nil {
+		return fmt.Errorf("listing request labels: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, key, value string
+		if err := rows.Scan(&id, &key, &value); err != nil {
+			return fmt.Errorf("scanning request label: %w", err)
+		}
+		if byRequest[id] == nil {
+			byRequest[id] = make(map[string]string)
+		}
+		byRequest[id][key] = value
+	}
+	return rows.Err()
+}
@@ -0,0 +1,116 @@
+// Package db tests for request labels.
+package db
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseLabels(t *testing.T) {
+	labels, err := ParseLabels([]string{"team=infra", "ticket=OPS-12", "team=data", "note=a=b"})
+	if err != nil {
+		t.Fatalf("ParseLabels failed: %v", err)
+	}
+	if labels["team"] != "data" || labels["ticket"] != "OPS-12" || labels["note"] != "a=b" {
+		t.Fatalf("unexpected labels: %v", labels)
+	}
+
+	for _, bad := range []string{"team", "Team=x", "=x", "-team=x", "team=a\nb", strings.Repeat("k", 64) + "=x"} {
+		if _, err := ParseLabels([]string{bad}); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+
+	if labels, err := ParseLabels(nil); err != nil || labels != nil {
+		t.Fatalf("expected nil labels for no input, got %v (err %v)", labels, err)
+	}
+}
+
+func TestMatchLabels(t *testing.T) {
+	labels := map[string]string{"team": "infra", "env": "prod"}
+
+	cases := []struct {
+		specs []string
+		want  bool
+	}{
+		{nil, true},
+		{[]string{"team=infra"}, true},
+		{[]string{"team=infra", "env=prod"}, true},
+		{[]string{"team=data"}, false},
+		{[]string{"env"}, true},
+		{[]string{"ticket"}, false},
+		{[]string{"team="}, false},
+	}
+	for _, tc := range cases {
+		filters, err := ParseLabelFilters(tc.specs)
+		if err != nil {
+			t.Fatalf("ParseLabelFilters(%v) failed: %v", tc.specs, err)
+		}
+		if got := MatchLabels(labels, filters); got != tc.want {
+			t.Errorf("MatchLabels(%v) = %v, want %v", tc.specs, got, tc.want)
+		}
+	}
+
+	if got := FormatLabels(labels, ", "); got != "env=prod, team=infra" {
+		t.Errorf("FormatLabels = %q", got)
+	}
+}
+
+func TestRequestLabels(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	sess, plain := createTestRequest(t, db)
+	labeled := &Request{
+		ProjectPath:        sess.ProjectPath,
+		RequestorSessionID: sess.ID,
+		RequestorAgent:     sess.AgentName,
+		RiskTier:           RiskTierDangerous,
+		MinApprovals:       1,
+		Command:            CommandSpec{Raw: "make deploy", Cwd: sess.ProjectPath},
+		Justification:      Justification{Reason: "ship"},
+		Labels:             map[string]string{"team": "infra", "ticket": "OPS-12"},
+	}
+	if err := db.CreateRequest(labeled); err != nil {
+		t.Fatalf("CreateRequest failed: %v", err)
+	}
+
+	got, err := db.GetRequestLabels(labeled.ID)
+	if err != nil {
+		t.Fatalf("GetRequestLabels failed: %v", err)
+	}
+	if len(got) != 2 || got["team"] != "infra" || got["ticket"] != "OPS-12" {
+		t.Fatalf("unexpected labels: %v", got)
+	}
+
+	reqs := []*Request{{ID: plain.ID}, {ID: labeled.ID}}
+	if err := db.LoadRequestLabels(reqs); err != nil {
+		t.Fatalf("LoadRequestLabels failed: %v", err)
+	}
+	if reqs[0].Labels != nil || reqs[1].Labels["team"] != "infra" {
+		t.Fatalf("unexpected loaded labels: %v / %v", reqs[0].Labels, reqs[1].Labels)
+	}
+}
+
+func TestCreateRequest_InvalidLabelRecordsNothing(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	sess, _ := createTestRequest(t, db)
+	r := &Request{
+		ProjectPath:        sess.ProjectPath,
+		RequestorSessionID: sess.ID,
+		RequestorAgent:     sess.AgentName,
+		RiskTier:           RiskTierDangerous,
+		MinApprovals:       1,
+		Command:            CommandSpec{Raw: "make deploy", Cwd: sess.ProjectPath},
+		Justification:      Justification{Reason: "ship"},
+		Labels:             map[string]string{"Bad Key": "x"},
+	}
+	if err := db.CreateRequest(r); err == nil {
+		t.Fatal("expected error for invalid label key")
+	}
+	if _, err := db.GetRequest(r.ID); err == nil {
+		t.Fatal("expected request not to be created")
+	}
+}
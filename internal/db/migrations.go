@@ -186,6 +186,558 @@ ALTER TABLE execution_outcomes ADD COLUMN caused_problems INTEGER NOT NULL DEFAU
 ALTER TABLE execution_outcomes ADD COLUMN problem_description TEXT;
 ALTER TABLE execution_outcomes ADD COLUMN human_rating INTEGER;
 ALTER TABLE execution_outcomes ADD COLUMN human_notes TEXT;
+`,
+	},
+	{
+		Version: 4,
+		Name:    "request_annotations",
+		Up: `
+-- Advisory annotations attached to requests (e.g. LLM second opinions).
+-- Annotations are informational only and never count toward approvals.
+CREATE TABLE IF NOT EXISTS request_annotations (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  request_id TEXT NOT NULL REFERENCES requests(id) ON DELETE CASCADE,
+  source TEXT NOT NULL,
+  author TEXT,
+  risk_summary TEXT NOT NULL,
+  recommendation TEXT NOT NULL,
+  created_at TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_request_annotations_request ON request_annotations(request_id);
+`,
+	},
+	{
+		Version: 5,
+		Name:    "delegations",
+		Up: `
+-- Approval delegations: a reviewer lends their approval authority to another
+-- reviewer for a bounded time window (out-of-office rules).
+CREATE TABLE IF NOT EXISTS delegations (
+  id TEXT PRIMARY KEY,
+  project_path TEXT NOT NULL,
+  from_agent TEXT NOT NULL,
+  to_agent TEXT NOT NULL,
+  reason TEXT,
+  starts_at TEXT NOT NULL,
+  expires_at TEXT NOT NULL,
+  created_at TEXT NOT NULL,
+  revoked_at TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_delegations_project ON delegations(project_path);
+CREATE INDEX IF NOT EXISTS idx_delegations_to ON delegations(to_agent);
+`,
+	},
+	{
+		Version: 6,
+		Name:    "execution_overrides",
+		Up: `
+-- Human overrides allowing an approved request to run inside a restricted
+-- execution window (quiet hours, weekends).
+CREATE TABLE IF NOT EXISTS execution_overrides (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  request_id TEXT NOT NULL REFERENCES requests(id) ON DELETE CASCADE,
+  command_hash TEXT NOT NULL,
+  granted_by TEXT NOT NULL,
+  reason TEXT NOT NULL,
+  created_at TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_execution_overrides_request ON execution_overrides(request_id);
+`,
+	},
+	{
+		Version: 7,
+		Name:    "breakglass_incidents",
+		Up: `
+-- Break-glass executions: commands run immediately without approval. Each one
+-- is an incident that must be acknowledged with a postmortem note.
+CREATE TABLE IF NOT EXISTS breakglass_incidents (
+  id TEXT PRIMARY KEY,
+  project_path TEXT NOT NULL,
+  actor TEXT NOT NULL,
+  command TEXT NOT NULL,
+  command_hash TEXT NOT NULL,
+  cwd TEXT,
+  reason TEXT NOT NULL,
+  exit_code INTEGER,
+  log_path TEXT,
+  executed_at TEXT NOT NULL,
+  ack_due_at TEXT NOT NULL,
+  acknowledged_at TEXT,
+  acknowledged_by TEXT,
+  postmortem TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_breakglass_project ON breakglass_incidents(project_path, executed_at);
+`,
+	},
+	{
+		Version: 8,
+		Name:    "human_escalations",
+		Up: `
+-- Requests that no agent reviewer acted on in time and were paged to a human.
+CREATE TABLE IF NOT EXISTS human_escalations (
+  request_id TEXT PRIMARY KEY REFERENCES requests(id) ON DELETE CASCADE,
+  reason TEXT NOT NULL,
+  channels TEXT,
+  paged_at TEXT NOT NULL
+);
+`,
+	},
+	{
+		Version: 9,
+		Name:    "request_provenance",
+		Up: `
+-- Where a request came from: the agent tool, conversation, and tool call that
+-- attempted the command.
+CREATE TABLE IF NOT EXISTS request_provenance (
+  request_id TEXT PRIMARY KEY REFERENCES requests(id) ON DELETE CASCADE,
+  agent_program TEXT,
+  conversation_id TEXT,
+  tool_call_id TEXT,
+  prompt_hash TEXT,
+  created_at TEXT NOT NULL
+);
+`,
+	},
+	{
+		Version: 10,
+		Name:    "request_labels",
+		Up: `
+-- Arbitrary key/value labels on requests for org-specific triage.
+CREATE TABLE IF NOT EXISTS request_labels (
+  request_id TEXT NOT NULL REFERENCES requests(id) ON DELETE CASCADE,
+  key TEXT NOT NULL,
+  value TEXT NOT NULL,
+  PRIMARY KEY (request_id, key)
+);
+CREATE INDEX IF NOT EXISTS idx_request_labels_key_value ON request_labels(key, value);
+`,
+	},
+	{
+		Version: 11,
+		Name:    "requests_history_keyset",
+		Up: `
+-- Keyset pagination walks history by (created_at, id) per project.
+CREATE INDEX IF NOT EXISTS idx_requests_project_created_id ON requests(project_path, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_requests_created_id ON requests(created_at DESC, id DESC);
+`,
+	},
+	{
+		Version: 12,
+		Name:    "daemon_events",
+		Up: `
+-- Events broadcast by the daemon, written in batches by BufferedEventWriter.
+CREATE TABLE IF NOT EXISTS daemon_events (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  type TEXT NOT NULL,
+  payload_json TEXT,
+  created_at TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_daemon_events_created ON daemon_events(created_at);
+`,
+	},
+	{
+		Version: 13,
+		Name:    "idempotency_keys",
+		Up: `
+-- Client-supplied keys that make retried request submissions replay the
+-- original request instead of creating a duplicate.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+  session_id TEXT NOT NULL,
+  key TEXT NOT NULL,
+  request_id TEXT NOT NULL REFERENCES requests(id) ON DELETE CASCADE,
+  command_hash TEXT NOT NULL,
+  created_at TEXT NOT NULL,
+  expires_at TEXT NOT NULL,
+  PRIMARY KEY (session_id, key)
+);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires ON idempotency_keys(expires_at);
+`,
+	},
+	{
+		Version: 14,
+		Name:    "request_version",
+		Up: `
+-- Optimistic concurrency: bumped on every review and status change.
+ALTER TABLE requests ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+`,
+	},
+	{
+		Version: 15,
+		Name:    "provenance_peer",
+		Up: `
+-- Unix socket peer credentials of the client that submitted a request
+-- through the daemon.
+ALTER TABLE request_provenance ADD COLUMN peer_uid INTEGER;
+ALTER TABLE request_provenance ADD COLUMN peer_gid INTEGER;
+ALTER TABLE request_provenance ADD COLUMN peer_pid INTEGER;
+`,
+	},
+	{
+		Version: 16,
+		Name:    "request_binaries",
+		Up: `
+-- The executable a request's command would run, resolved via PATH when the
+-- request was created.
+CREATE TABLE IF NOT EXISTS request_binaries (
+  request_id TEXT PRIMARY KEY REFERENCES requests(id) ON DELETE CASCADE,
+  name TEXT NOT NULL,
+  path TEXT NOT NULL,
+  real_path TEXT,
+  sha256 TEXT,
+  in_project INTEGER NOT NULL DEFAULT 0,
+  interpreter TEXT,
+  created_at TEXT NOT NULL
+);
+`,
+	},
+	{
+		Version: 17,
+		Name:    "pattern_telemetry",
+		Up: `
+-- The pattern that classified each request, for pattern effectiveness stats.
+CREATE TABLE IF NOT EXISTS request_patterns (
+  request_id TEXT PRIMARY KEY REFERENCES requests(id) ON DELETE CASCADE,
+  tier TEXT NOT NULL,
+  pattern TEXT NOT NULL,
+  created_at TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_request_patterns_pattern ON request_patterns(pattern);
+
+-- Opt-in telemetry reports sent (or attempted); the last successful report
+-- marks where the next one starts.
+CREATE TABLE IF NOT EXISTS telemetry_reports (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  sent_at TEXT NOT NULL,
+  period_start TEXT NOT NULL,
+  period_end TEXT NOT NULL,
+  endpoint TEXT NOT NULL,
+  patterns INTEGER NOT NULL DEFAULT 0,
+  error TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_telemetry_reports_sent ON telemetry_reports(sent_at);
+`,
+	},
+	{
+		Version: 18,
+		Name:    "review_fresh_auth",
+		Up: `
+-- How and when a reviewer re-authenticated before deciding, when sudo mode
+-- required it.
+ALTER TABLE reviews ADD COLUMN auth_method TEXT;
+ALTER TABLE reviews ADD COLUMN authenticated_at TEXT;
+`,
+	},
+	{
+		Version: 19,
+		Name:    "command_observations",
+		Up: `
+-- Every command submitted for classification, by command family, as the
+-- per-project baseline for anomaly detection.
+CREATE TABLE IF NOT EXISTS command_observations (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  project_path TEXT NOT NULL,
+  session_id TEXT NOT NULL,
+  family TEXT NOT NULL,
+  observed_at TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_command_observations_family ON command_observations(project_path, family, observed_at);
+CREATE INDEX IF NOT EXISTS idx_command_observations_session ON command_observations(session_id, family, observed_at);
+CREATE INDEX IF NOT EXISTS idx_command_observations_observed ON command_observations(observed_at);
+`,
+	},
+	{
+		Version: 20,
+		Name:    "request_policies",
+		Up: `
+-- The classification policy (pattern set and tier overrides) a request was
+-- classified under, so execution can tell when it changed since.
+CREATE TABLE IF NOT EXISTS request_policies (
+  request_id TEXT PRIMARY KEY REFERENCES requests(id) ON DELETE CASCADE,
+  policy_hash TEXT NOT NULL,
+  tier TEXT NOT NULL,
+  created_at TEXT NOT NULL
+);
+
+-- Requests whose policy changed before execution, and how re-validation
+-- under the current policy turned out.
+CREATE TABLE IF NOT EXISTS policy_discrepancies (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  request_id TEXT NOT NULL REFERENCES requests(id) ON DELETE CASCADE,
+  recorded_hash TEXT NOT NULL,
+  current_hash TEXT NOT NULL,
+  recorded_tier TEXT NOT NULL,
+  current_tier TEXT NOT NULL,
+  blocked INTEGER NOT NULL DEFAULT 0,
+  detected_at TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_policy_discrepancies_request ON policy_discrepancies(request_id);
+`,
+	},
+	{
+		Version: 21,
+		Name:    "request_environments",
+		Up: `
+-- The working directory and pinned environment variables a request was
+-- created with; execution elsewhere counts as drift.
+CREATE TABLE IF NOT EXISTS request_environments (
+  request_id TEXT PRIMARY KEY REFERENCES requests(id) ON DELETE CASCADE,
+  cwd TEXT NOT NULL,
+  cwd_real TEXT,
+  env_json TEXT,
+  created_at TEXT NOT NULL
+);
+
+-- Drift found at execution: blocked, or allowed by a human override.
+CREATE TABLE IF NOT EXISTS execution_drifts (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  request_id TEXT NOT NULL REFERENCES requests(id) ON DELETE CASCADE,
+  kind TEXT NOT NULL,
+  recorded TEXT,
+  current TEXT,
+  allowed_by TEXT,
+  detected_at TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_execution_drifts_request ON execution_drifts(request_id);
+`,
+	},
+	{
+		Version: 22,
+		Name:    "request_events",
+		Up: `
+-- Lifecycle events of a request (created, viewed, reviewed, escalated and
+-- each status change) for its timeline.
+CREATE TABLE IF NOT EXISTS request_events (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  request_id TEXT NOT NULL REFERENCES requests(id) ON DELETE CASCADE,
+  type TEXT NOT NULL,
+  actor TEXT,
+  details TEXT,
+  created_at TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_request_events_request ON request_events(request_id, id);
+`,
+	},
+	{
+		Version: 23,
+		Name:    "command_edits",
+		Up: `
+-- Corrected commands reviewers propose instead of approving a request as
+-- is. Accepting one links the request created for the corrected command.
+CREATE TABLE IF NOT EXISTS command_edits (
+  id TEXT PRIMARY KEY,
+  request_id TEXT NOT NULL REFERENCES requests(id) ON DELETE CASCADE,
+  reviewer_session_id TEXT NOT NULL,
+  reviewer_agent TEXT NOT NULL,
+  reviewer_model TEXT NOT NULL,
+  command TEXT NOT NULL,
+  comments TEXT,
+  signature TEXT NOT NULL,
+  signature_timestamp TEXT NOT NULL,
+  auth_method TEXT,
+  authenticated_at TEXT,
+  status TEXT NOT NULL DEFAULT 'proposed',
+  accepted_request_id TEXT,
+  accepted_at TEXT,
+  created_at TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_command_edits_request ON command_edits(request_id);
+CREATE INDEX IF NOT EXISTS idx_command_edits_accepted ON command_edits(accepted_request_id);
+`,
+	},
+	{
+		Version: 24,
+		Name:    "config_changes",
+		Up: `
+-- Config files staged with slb config apply. Each is written by its
+-- critical request's execution; applied rows are the config hash history.
+CREATE TABLE IF NOT EXISTS config_changes (
+  id TEXT PRIMARY KEY,
+  request_id TEXT NOT NULL REFERENCES requests(id) ON DELETE CASCADE,
+  project_path TEXT NOT NULL,
+  target_path TEXT NOT NULL,
+  base_hash TEXT NOT NULL,
+  config_hash TEXT NOT NULL,
+  content TEXT NOT NULL,
+  diff TEXT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'staged',
+  created_by TEXT NOT NULL,
+  created_at TEXT NOT NULL,
+  applied_by TEXT,
+  applied_at TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_config_changes_request ON config_changes(request_id);
+CREATE INDEX IF NOT EXISTS idx_config_changes_project ON config_changes(project_path, applied_at);
+`,
+	},
+	{
+		Version: 25,
+		Name:    "pattern_hits",
+		Up: `
+-- How often each pattern classified a command (request creation and hook
+-- queries, safe matches included), for patterns list --stats and the
+-- unused custom pattern report.
+CREATE TABLE IF NOT EXISTS pattern_hits (
+  tier TEXT NOT NULL,
+  pattern TEXT NOT NULL,
+  hits INTEGER NOT NULL DEFAULT 0,
+  first_matched_at TEXT NOT NULL,
+  last_matched_at TEXT NOT NULL,
+  PRIMARY KEY (tier, pattern)
+);
+`,
+	},
+	{
+		Version: 26,
+		Name:    "request_risk_score",
+		Up: `
+-- Numeric 0-100 risk score computed when a request is created, for sorting
+-- the review queue and for score thresholds in policies.
+ALTER TABLE requests ADD COLUMN risk_score INTEGER NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_requests_risk_score ON requests(risk_score);
+`,
+	},
+	{
+		Version: 27,
+		Name:    "requestor_waits",
+		Up: `
+-- Requests whose requestor is blocked waiting for a decision (slb run,
+-- request --wait, status --wait), for review queue priority.
+CREATE TABLE IF NOT EXISTS requestor_waits (
+  request_id TEXT PRIMARY KEY REFERENCES requests(id) ON DELETE CASCADE,
+  session_id TEXT,
+  since TEXT NOT NULL
+);
+`,
+	},
+	{
+		Version: 28,
+		Name:    "request_presence",
+		Up: `
+-- Reviewers with a request open in the TUI right now, one row per session,
+-- refreshed while the view stays open.
+CREATE TABLE IF NOT EXISTS request_presence (
+  session_id TEXT PRIMARY KEY,
+  request_id TEXT NOT NULL REFERENCES requests(id) ON DELETE CASCADE,
+  viewer TEXT NOT NULL,
+  composing INTEGER NOT NULL DEFAULT 0,
+  seen_at TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_request_presence_request ON request_presence(request_id);
+`,
+	},
+	{
+		Version: 29,
+		Name:    "request_scripts",
+		Up: `
+-- Requests submitted as multi-line scripts: the interpreter that runs the
+-- script from a temp file and the SHA-256 of its content, checked again
+-- before it runs. The content itself is the request's command.
+CREATE TABLE IF NOT EXISTS request_scripts (
+  request_id TEXT PRIMARY KEY REFERENCES requests(id) ON DELETE CASCADE,
+  interpreter TEXT NOT NULL,
+  source TEXT,
+  sha256 TEXT NOT NULL,
+  created_at TEXT NOT NULL
+);
+`,
+	},
+	{
+		Version: 30,
+		Name:    "request_remote_scripts",
+		Up: `
+-- Scripts a request downloads and pipes into a shell (curl ... | bash),
+-- fetched at request time: the hash execution checks again and a preview
+-- for reviewers.
+CREATE TABLE IF NOT EXISTS request_remote_scripts (
+  request_id TEXT NOT NULL REFERENCES requests(id) ON DELETE CASCADE,
+  url TEXT NOT NULL,
+  fetcher TEXT NOT NULL,
+  shell TEXT NOT NULL,
+  sha256 TEXT NOT NULL,
+  size INTEGER NOT NULL DEFAULT 0,
+  preview TEXT,
+  fetched_at TEXT NOT NULL,
+  PRIMARY KEY (request_id, url)
+);
+`,
+	},
+	{
+		Version: 31,
+		Name:    "daemon_jobs",
+		Up: `
+-- The daemon's recurring jobs: their schedule, when they run next, and how
+-- their last run went. failures counts consecutive failed runs.
+CREATE TABLE IF NOT EXISTS daemon_jobs (
+  name TEXT PRIMARY KEY,
+  schedule TEXT NOT NULL,
+  enabled INTEGER NOT NULL DEFAULT 1,
+  next_run_at TEXT,
+  last_run_at TEXT,
+  last_duration_ms INTEGER NOT NULL DEFAULT 0,
+  last_error TEXT,
+  runs INTEGER NOT NULL DEFAULT 0,
+  failures INTEGER NOT NULL DEFAULT 0,
+  updated_at TEXT NOT NULL
+);
+
+-- Review reminders sent for a pending request, for spacing the next one.
+CREATE TABLE IF NOT EXISTS review_reminders (
+  request_id TEXT PRIMARY KEY REFERENCES requests(id) ON DELETE CASCADE,
+  reminders INTEGER NOT NULL DEFAULT 0,
+  last_sent_at TEXT NOT NULL
+);
+`,
+	},
+	{
+		Version: 32,
+		Name:    "request_execution_secrets",
+		Up: `
+-- Kinds of secret found in a request's execution output (JSON array), NULL
+-- when the output was clean.
+ALTER TABLE requests ADD COLUMN execution_secrets_json TEXT;
+`,
+	},
+	{
+		Version: 33,
+		Name:    "request_imports",
+		Up: `
+-- Requests imported from another approval process: the source they came
+-- from and their ID there, which keeps re-imports from duplicating them.
+ALTER TABLE requests ADD COLUMN import_source TEXT;
+ALTER TABLE requests ADD COLUMN import_external_id TEXT;
+ALTER TABLE requests ADD COLUMN imported_at TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_requests_import
+  ON requests(import_source, import_external_id)
+  WHERE import_source IS NOT NULL;
+`,
+	},
+	{
+		Version: 34,
+		Name:    "delegation_signatures",
+		Up: `
+-- The delegator's session and its HMAC over the delegation. Delegations
+-- recorded before this carry neither and no longer count toward quorum.
+ALTER TABLE delegations ADD COLUMN from_session_id TEXT;
+ALTER TABLE delegations ADD COLUMN signature TEXT;
+`,
+	},
+	{
+		Version: 35,
+		Name:    "request_redact_patterns",
+		Up: `
+-- The request's own --redact patterns (JSON array), applied with the
+-- built-in ones to its execution output. NULL when it had none.
+ALTER TABLE requests ADD COLUMN redact_patterns_json TEXT;
+`,
+	},
+	{
+		Version: 36,
+		Name:    "breakglass_os_users",
+		Up: `
+-- The operating-system accounts that broke the glass and acknowledged it,
+-- so the postmortem cannot come from the same account under another name.
+ALTER TABLE breakglass_incidents ADD COLUMN actor_os_user TEXT;
+ALTER TABLE breakglass_incidents ADD COLUMN acknowledged_by_os_user TEXT;
 `,
 	},
 }
@@ -238,6 +790,25 @@ func (db *DB) ApplyMigrations(ctx context.Context) error {
 					return fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
 				}
 			}
+		case 14:
+			if err := addColumnIfMissing(ctx, tx, "requests", "version", "INTEGER NOT NULL DEFAULT 1"); err != nil {
+				_ = tx.Rollback()
+				return fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
+			}
+		case 15:
+			for _, col := range []string{"peer_uid", "peer_gid", "peer_pid"} {
+				if err := addColumnIfMissing(ctx, tx, "request_provenance", col, "INTEGER"); err != nil {
+					_ = tx.Rollback()
+					return fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
+				}
+			}
+		case 18:
+			for _, col := range []string{"auth_method", "authenticated_at"} {
+				if err := addColumnIfMissing(ctx, tx, "reviews", col, "TEXT"); err != nil {
+					_ = tx.Rollback()
+					return fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
+				}
+			}
 		default:
 			if _, err := tx.ExecContext(ctx, m.Up); err != nil {
 				_ = tx.Rollback()
@@ -0,0 +1,80 @@
+package db
+
+import (
+	"fmt"
+	"time"
+)
+
+// CommandObservation records one command submitted for classification, by
+// command family (e.g. "rm" or "aws iam"). Observations form the baseline
+// anomaly detection compares new commands against.
+type CommandObservation struct {
+	ID          int64     `json:"id"`
+	ProjectPath string    `json:"project_path"`
+	SessionID   string    `json:"session_id"`
+	Family      string    `json:"family"`
+	ObservedAt  time.Time `json:"observed_at"`
+}
+
+// FamilyBaseline summarizes the observations a new command is judged by.
+type FamilyBaseline struct {
+	// ProjectObservations counts every observation in the project.
+	ProjectObservations int `json:"project_observations"`
+	// FamilyObservations counts the project's observations of the family.
+	FamilyObservations int `json:"family_observations"`
+	// SessionRecent counts the session's observations of the family since
+	// the burst window began.
+	SessionRecent int `json:"session_recent"`
+}
+
+// RecordCommandObservation stores an observation.
+func (db *DB) RecordCommandObservation(o *CommandObservation) error {
+	if o.ProjectPath == "" || o.Family == "" {
+		return fmt.Errorf("command observation requires project path and family")
+	}
+	if o.ObservedAt.IsZero() {
+		o.ObservedAt = db.Now()
+	}
+	result, err := db.Exec(`
+		INSERT INTO command_observations (project_path, session_id, family, observed_at)
+		VALUES (?, ?, ?, ?)
+	`, o.ProjectPath, o.SessionID, o.Family, o.ObservedAt.UTC().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("recording command observation: %w", err)
+	}
+	if o.ID, err = result.LastInsertId(); err != nil {
+		return fmt.Errorf("getting last insert id: %w", err)
+	}
+	return nil
+}
+
+// CommandFamilyBaseline returns the baseline for a family in a project,
+// counting the session's observations at or after since as recent.
+func (db *DB) CommandFamilyBaseline(projectPath, family, sessionID string, since time.Time) (FamilyBaseline, error) {
+	var b FamilyBaseline
+	err := db.QueryRow(`
+		SELECT
+			(SELECT COUNT(*) FROM command_observations WHERE project_path = ?),
+			(SELECT COUNT(*) FROM command_observations WHERE project_path = ? AND family = ?),
+			(SELECT COUNT(*) FROM command_observations WHERE session_id = ? AND project_path = ? AND family = ? AND observed_at >= ?)
+	`, projectPath, projectPath, family, sessionID, projectPath, family, since.UTC().Format(time.RFC3339)).
+		Scan(&b.ProjectObservations, &b.FamilyObservations, &b.SessionRecent)
+	if err != nil {
+		return FamilyBaseline{}, fmt.Errorf("reading command family baseline: %w", err)
+	}
+	return b, nil
+}
+
+// PruneCommandObservations deletes observations older than before and
+// returns how many were removed.
+func (db *DB) PruneCommandObservations(before time.Time) (int64, error) {
+	result, err := db.Exec(`DELETE FROM command_observations WHERE observed_at < ?`, before.UTC().Format(time.RFC3339))
+	if err != nil {
+		return 0, fmt.Errorf("pruning command observations: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("getting rows affected: %w", err)
+	}
+	return n, nil
+}
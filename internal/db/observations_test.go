@@ -0,0 +1,48 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCommandObservations(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	if err := db.RecordCommandObservation(&CommandObservation{ProjectPath: "/p"}); err == nil {
+		t.Fatal("expected error without a family")
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	record := func(project, session, family string, at time.Time) {
+		t.Helper()
+		if err := db.RecordCommandObservation(&CommandObservation{ProjectPath: project, SessionID: session, Family: family, ObservedAt: at}); err != nil {
+			t.Fatalf("RecordCommandObservation: %v", err)
+		}
+	}
+	record("/p", "s1", "rm", now.Add(-2*time.Hour))
+	record("/p", "s1", "rm", now.Add(-30*time.Second))
+	record("/p", "s1", "rm", now)
+	record("/p", "s2", "rm", now)
+	record("/p", "s1", "git push", now)
+	record("/other", "s1", "rm", now)
+
+	b, err := db.CommandFamilyBaseline("/p", "rm", "s1", now.Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("CommandFamilyBaseline: %v", err)
+	}
+	if b.ProjectObservations != 5 || b.FamilyObservations != 4 || b.SessionRecent != 2 {
+		t.Errorf("baseline = %+v, want 5 project, 4 family, 2 recent", b)
+	}
+	if b, _ := db.CommandFamilyBaseline("/p", "aws iam", "s1", now.Add(-time.Minute)); b.FamilyObservations != 0 || b.ProjectObservations != 5 {
+		t.Errorf("unseen family baseline = %+v", b)
+	}
+
+	n, err := db.PruneCommandObservations(now.Add(-time.Hour))
+	if err != nil || n != 1 {
+		t.Fatalf("PruneCommandObservations = %d, %v; want 1", n, err)
+	}
+	if b, _ := db.CommandFamilyBaseline("/p", "rm", "s1", now.Add(-time.Minute)); b.FamilyObservations != 3 {
+		t.Errorf("after prune baseline = %+v", b)
+	}
+}
@@ -34,7 +34,7 @@ type ExecutionOutcome struct {
 
 // CreateOutcome inserts an execution outcome record.
 func (db *DB) CreateOutcome(o *ExecutionOutcome) error {
-	now := time.Now().UTC()
+	now := db.Now()
 	if o.CreatedAt.IsZero() {
 		o.CreatedAt = now
 	}
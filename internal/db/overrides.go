@@ -0,0 +1,78 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrExecutionOverrideNotFound indicates no override exists for a request.
+var ErrExecutionOverrideNotFound = errors.New("execution override not found")
+
+// ExecutionOverride records a human decision to let an approved request run
+// inside a restricted execution window (quiet hours, weekends).
+type ExecutionOverride struct {
+	// ID is the unique override identifier (auto-generated).
+	ID int64 `json:"id"`
+	// RequestID is the request the override applies to.
+	RequestID string `json:"request_id"`
+	// CommandHash binds the override to the exact command that was approved.
+	CommandHash string `json:"command_hash"`
+	// GrantedBy identifies the human who granted the override.
+	GrantedBy string `json:"granted_by"`
+	// Reason explains why the window was overridden.
+	Reason string `json:"reason"`
+	// CreatedAt is when the override was granted.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateExecutionOverride records a window override for a request.
+func (db *DB) CreateExecutionOverride(o *ExecutionOverride) error {
+	if o.RequestID == "" || o.CommandHash == "" {
+		return fmt.Errorf("execution override requires request id and command hash")
+	}
+	if o.GrantedBy == "" || o.Reason == "" {
+		return fmt.Errorf("execution override requires granted_by and reason")
+	}
+	if o.CreatedAt.IsZero() {
+		o.CreatedAt = db.Now()
+	}
+
+	result, err := db.Exec(`
+		INSERT INTO execution_overrides (
+			request_id, command_hash, granted_by, reason, created_at
+		) VALUES (?, ?, ?, ?, ?)
+	`, o.RequestID, o.CommandHash, o.GrantedBy, o.Reason, o.CreatedAt.Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("creating execution override: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("getting execution override id: %w", err)
+	}
+	o.ID = id
+	return nil
+}
+
+// GetExecutionOverride returns the most recent override for a request.
+func (db *DB) GetExecutionOverride(requestID string) (*ExecutionOverride, error) {
+	o := &ExecutionOverride{}
+	var created string
+	err := db.QueryRow(`
+		SELECT id, request_id, command_hash, granted_by, reason, created_at
+		FROM execution_overrides
+		WHERE request_id = ?
+		ORDER BY id DESC
+		LIMIT 1
+	`, requestID).Scan(&o.ID, &o.RequestID, &o.CommandHash, &o.GrantedBy, &o.Reason, &created)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrExecutionOverrideNotFound
+		}
+		return nil, fmt.Errorf("getting execution override: %w", err)
+	}
+	o.CreatedAt, _ = time.Parse(time.RFC3339, created)
+	return o, nil
+}
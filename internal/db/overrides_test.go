@@ -0,0 +1,43 @@
+// Package db tests for execution window override operations.
+package db
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExecutionOverrides(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, req := createTestRequest(t, db)
+
+	if _, err := db.GetExecutionOverride(req.ID); !errors.Is(err, ErrExecutionOverrideNotFound) {
+		t.Fatalf("expected ErrExecutionOverrideNotFound, got %v", err)
+	}
+
+	if err := db.CreateExecutionOverride(&ExecutionOverride{RequestID: req.ID, CommandHash: req.Command.Hash}); err == nil {
+		t.Fatal("expected error without granted_by and reason")
+	}
+
+	o := &ExecutionOverride{
+		RequestID:   req.ID,
+		CommandHash: req.Command.Hash,
+		GrantedBy:   "oncall",
+		Reason:      "hotfix must ship tonight",
+	}
+	if err := db.CreateExecutionOverride(o); err != nil {
+		t.Fatalf("CreateExecutionOverride failed: %v", err)
+	}
+	if o.ID == 0 || o.CreatedAt.IsZero() {
+		t.Fatalf("expected ID and CreatedAt to be set, got %+v", o)
+	}
+
+	got, err := db.GetExecutionOverride(req.ID)
+	if err != nil {
+		t.Fatalf("GetExecutionOverride failed: %v", err)
+	}
+	if got.GrantedBy != "oncall" || got.CommandHash != req.Command.Hash || got.Reason != o.Reason {
+		t.Errorf("unexpected override: %+v", got)
+	}
+}
@@ -0,0 +1,194 @@
+// Package db provides keyset pagination over request history.
+package db
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// DefaultPageSize is used when a page query does not set a limit.
+const DefaultPageSize = 50
+
+// RequestCursor marks a position in history order (created_at descending,
+// then id descending). A page starts strictly after its cursor, so pages stay
+// stable while new requests arrive and cost the same however deep they are.
+type RequestCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// CursorFor returns the cursor positioned at r.
+func CursorFor(r *Request) *RequestCursor {
+	return &RequestCursor{CreatedAt: r.CreatedAt, ID: r.ID}
+}
+
+// String encodes the cursor as an opaque token for CLI and API use.
+func (c RequestCursor) String() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339) + "|" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseRequestCursor decodes a token produced by RequestCursor.String.
+func ParseRequestCursor(token string) (*RequestCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(token))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	ts, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidCursor)
+	}
+	createdAt, err := time.Parse(time.RFC3339, ts)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	return &RequestCursor{CreatedAt: createdAt, ID: id}, nil
+}
+
+// RequestPageQuery selects one page of request history. Zero-valued fields
+// do not filter.
+type RequestPageQuery struct {
+	// ProjectPath limits results to one project; empty means all projects.
+	ProjectPath string
+	// Search is a full-text query over commands, justifications and agents.
+	Search string
+	Status RequestStatus
+	Tier   RiskTier
+	Agent  string
+	// Since keeps requests created at or after this time.
+	Since time.Time
+	// Until keeps requests created before this time.
+	Until  time.Time
+	Labels []LabelFilter
+
+	// After is the cursor of the last row of the previous page; nil starts
+	// from the newest request.
+	After *RequestCursor
+	// Limit is the page size; DefaultPageSize when zero.
+	Limit int
+}
+
+// where builds the shared WHERE clause (without the cursor condition).
+func (q RequestPageQuery) where() (string, []any) {
+	var conds []string
+	var args []any
+	if q.Search != "" {
+		conds = append(conds, "r.rowid IN (SELECT rowid FROM requests_fts WHERE requests_fts MATCH ?)")
+		args = append(args, q.Search)
+	}
+	if q.ProjectPath != "" {
+		conds = append(conds, "r.project_path = ?")
+		args = append(args, q.ProjectPath)
+	}
+	if q.Status != "" {
+		conds = append(conds, "r.status = ?")
+		args = append(args, string(q.Status))
+	}
+	if q.Tier != "" {
+		conds = append(conds, "r.risk_tier = ?")
+		args = append(args, string(q.Tier))
+	}
+	if q.Agent != "" {
+		conds = append(conds, "r.requestor_agent = ?")
+		args = append(args, q.Agent)
+	}
+	if !q.Since.IsZero() {
+		conds = append(conds, "r.created_at >= ?")
+		args = append(args, q.Since.UTC().Format(time.RFC3339))
+	}
+	if !q.Until.IsZero() {
+		conds = append(conds, "r.created_at < ?")
+		args = append(args, q.Until.UTC().Format(time.RFC3339))
+	}
+	for _, f := range q.Labels {
+		if f.AnyValue {
+			conds = append(conds, "EXISTS (SELECT 1 FROM request_labels l WHERE l.request_id = r.id AND l.key = ?)")
+			args = append(args, f.Key)
+		} else {
+			conds = append(conds, "EXISTS (SELECT 1 FROM request_labels l WHERE l.request_id = r.id AND l.key = ? AND l.value = ?)")
+			args = append(args, f.Key, f.Value)
+		}
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+// ListRequestsPage returns one page of history, newest first, and the cursor
+// for the next page (nil on the last page).
+func (db *DB) ListRequestsPage(q RequestPageQuery) ([]*Request, *RequestCursor, error) {
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+
+	where, args := q.where()
+	if q.After != nil {
+		cursorCond := "(r.created_at < ? OR (r.created_at = ? AND r.id < ?))"
+		ts := q.After.CreatedAt.UTC().Format(time.RFC3339)
+		args = append(args, ts, ts, q.After.ID)
+		if where == "" {
+			where = cursorCond
+		} else {
+			where += " AND " + cursorCond
+		}
+	}
+	if where != "" {
+		where = "WHERE " + where
+	}
+	// Fetch one extra row to learn whether another page follows.
+	args = append(args, limit+1)
+
+	rows, err := db.Query(`
+		SELECT r.id, r.project_path,
+			r.command_raw, r.command_argv_json, r.command_cwd, r.command_shell, r.command_hash,
+			r.command_display_redacted, r.command_contains_sensitive,
+			r.risk_tier, r.requestor_session_id, r.requestor_agent, r.requestor_model,
+			r.justification_reason, r.justification_expected_effect, r.justification_goal, r.justification_safety_argument,
+			r.dry_run_command, r.dry_run_output, r.attachments_json,
+			r.status, r.min_approvals, r.require_different_model,
+			r.execution_log_path, r.execution_exit_code, r.execution_duration_ms,
+			r.execution_executed_at, r.execution_executed_by_session_id, r.execution_executed_by_agent, r.execution_executed_by_model, r.execution_secrets_json,
+			r.rollback_path, r.rollback_rolled_back_at,
+			r.created_at, r.resolved_at, r.expires_at, r.approval_expires_at, r.version, r.risk_score,
+			r.import_source, r.import_external_id, r.imported_at
+		FROM requests r
+		`+where+`
+		ORDER BY r.created_at DESC, r.id DESC
+		LIMIT ?
+	`, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing request page: %w", err)
+	}
+	defer rows.Close()
+
+	requests, err := scanRequests(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var next *RequestCursor
+	if len(requests) > limit {
+		requests = requests[:limit]
+		next = CursorFor(requests[limit-1])
+	}
+	return requests, next, nil
+}
+
+// CountRequests returns how many requests match q, ignoring its cursor and
+// limit.
+func (db *DB) CountRequests(q RequestPageQuery) (int, error) {
+	where, args := q.where()
+	if where != "" {
+		where = "WHERE " + where
+	}
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM requests r `+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("counting requests: %w", err)
+	}
+	return count, nil
+}
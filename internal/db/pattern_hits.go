@@ -0,0 +1,90 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// PatternHit counts how often one pattern classified a command.
+type PatternHit struct {
+	Tier           RiskTier  `json:"tier"`
+	Pattern        string    `json:"pattern"`
+	Hits           int       `json:"hits"`
+	FirstMatchedAt time.Time `json:"first_matched_at"`
+	LastMatchedAt  time.Time `json:"last_matched_at"`
+}
+
+// RecordPatternHits adds matches to the per-pattern counts in one
+// transaction. A hit with zero Hits counts once, and a zero LastMatchedAt
+// is now; a zero FirstMatchedAt is LastMatchedAt.
+func (db *DB) RecordPatternHits(hits []*PatternHit) error {
+	for _, h := range hits {
+		if h.Tier == "" || h.Pattern == "" {
+			return fmt.Errorf("pattern hit requires tier and pattern")
+		}
+	}
+	if len(hits) == 0 {
+		return nil
+	}
+
+	now := db.Now()
+	err := db.Transaction(func(tx *sql.Tx) error {
+		for _, h := range hits {
+			count, last, first := h.Hits, h.LastMatchedAt, h.FirstMatchedAt
+			if count <= 0 {
+				count = 1
+			}
+			if last.IsZero() {
+				last = now
+			}
+			if first.IsZero() {
+				first = last
+			}
+			_, err := tx.Exec(`
+				INSERT INTO pattern_hits (tier, pattern, hits, first_matched_at, last_matched_at)
+				VALUES (?, ?, ?, ?, ?)
+				ON CONFLICT (tier, pattern) DO UPDATE SET
+				  hits = hits + excluded.hits,
+				  first_matched_at = MIN(first_matched_at, excluded.first_matched_at),
+				  last_matched_at = MAX(last_matched_at, excluded.last_matched_at)
+			`, string(h.Tier), h.Pattern, count, first.UTC().Format(time.RFC3339), last.UTC().Format(time.RFC3339))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("recording pattern hits: %w", err)
+	}
+	return nil
+}
+
+// ListPatternHits returns the hit count of every pattern that has matched,
+// most hits first.
+func (db *DB) ListPatternHits() ([]*PatternHit, error) {
+	rows, err := db.Query(`
+		SELECT tier, pattern, hits, first_matched_at, last_matched_at
+		FROM pattern_hits
+		ORDER BY hits DESC, tier, pattern
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("listing pattern hits: %w", err)
+	}
+	defer rows.Close()
+
+	var hits []*PatternHit
+	for rows.Next() {
+		h := &PatternHit{}
+		var tier, first, last string
+		if err := rows.Scan(&tier, &h.Pattern, &h.Hits, &first, &last); err != nil {
+			return nil, fmt.Errorf("scanning pattern hit: %w", err)
+		}
+		h.Tier = RiskTier(tier)
+		h.FirstMatchedAt, _ = time.Parse(time.RFC3339, first)
+		h.LastMatchedAt, _ = time.Parse(time.RFC3339, last)
+		hits = append(hits, h)
+	}
+	return hits, rows.Err()
+}
@@ -0,0 +1,80 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/clock"
+)
+
+func TestPatternHits(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	db.SetClock(clock.Func(func() time.Time { return now }))
+
+	if err := db.RecordPatternHits([]*PatternHit{{Tier: RiskTierDangerous}}); err == nil {
+		t.Fatal("expected error without pattern")
+	}
+	if err := db.RecordPatternHits(nil); err != nil {
+		t.Fatalf("RecordPatternHits(nil): %v", err)
+	}
+
+	hits, err := db.ListPatternHits()
+	if err != nil || len(hits) != 0 {
+		t.Fatalf("expected no hits, got %+v (err %v)", hits, err)
+	}
+
+	record := func(tier RiskTier, pattern string) {
+		t.Helper()
+		if err := db.RecordPatternHits([]*PatternHit{{Tier: tier, Pattern: pattern}}); err != nil {
+			t.Fatalf("RecordPatternHits failed: %v", err)
+		}
+	}
+	record(RiskTierDangerous, `^rm\s+-rf`)
+	now = now.Add(time.Hour)
+	record(RiskTierDangerous, `^rm\s+-rf`)
+	record(RiskTierCritical, `^rm\s+-rf`)
+	record(RiskTier("safe"), `^git\s+status`)
+	now = now.Add(time.Hour)
+	record(RiskTierDangerous, `^rm\s+-rf`)
+
+	hits, err = db.ListPatternHits()
+	if err != nil {
+		t.Fatalf("ListPatternHits failed: %v", err)
+	}
+	if len(hits) != 3 {
+		t.Fatalf("expected 3 patterns, got %+v", hits)
+	}
+	top := hits[0]
+	if top.Tier != RiskTierDangerous || top.Pattern != `^rm\s+-rf` || top.Hits != 3 {
+		t.Fatalf("unexpected top pattern: %+v", top)
+	}
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if !top.FirstMatchedAt.Equal(start) || !top.LastMatchedAt.Equal(start.Add(2*time.Hour)) {
+		t.Errorf("unexpected timestamps: first %v, last %v", top.FirstMatchedAt, top.LastMatchedAt)
+	}
+	// The same regex in another tier is counted separately.
+	if hits[1].Tier != RiskTierCritical || hits[1].Hits != 1 || hits[2].Tier != "safe" {
+		t.Errorf("unexpected remaining hits: %+v, %+v", hits[1], hits[2])
+	}
+
+	// A batched count keeps the earliest first match and the latest last
+	// match, whatever order batches arrive in.
+	err = db.RecordPatternHits([]*PatternHit{{
+		Tier:           RiskTierDangerous,
+		Pattern:        `^rm\s+-rf`,
+		Hits:           5,
+		FirstMatchedAt: start.Add(-time.Hour),
+		LastMatchedAt:  start.Add(time.Hour),
+	}})
+	if err != nil {
+		t.Fatalf("RecordPatternHits failed: %v", err)
+	}
+	hits, _ = db.ListPatternHits()
+	top = hits[0]
+	if top.Hits != 8 || !top.FirstMatchedAt.Equal(start.Add(-time.Hour)) || !top.LastMatchedAt.Equal(start.Add(2*time.Hour)) {
+		t.Errorf("unexpected batched hit: %+v", top)
+	}
+}
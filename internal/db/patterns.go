@@ -42,7 +42,7 @@ func (db *DB) CreatePatternChange(pc *PatternChange) error {
 		pc.Status = PatternChangeStatusPending
 	}
 	if pc.CreatedAt.IsZero() {
-		pc.CreatedAt = time.Now().UTC()
+		pc.CreatedAt = db.Now()
 	}
 
 	result, err := db.Exec(`
@@ -0,0 +1,131 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrRequestPolicyNotFound indicates no policy was recorded for a request.
+var ErrRequestPolicyNotFound = errors.New("request policy not found")
+
+// RequestPolicy records the classification policy a request was created
+// under, identified by a hash of its pattern set and tier overrides.
+type RequestPolicy struct {
+	// RequestID is the request classified under the policy.
+	RequestID string `json:"request_id"`
+	// PolicyHash identifies the policy.
+	PolicyHash string `json:"policy_hash"`
+	// Tier is the tier the policy assigned.
+	Tier RiskTier `json:"tier"`
+	// CreatedAt is when the request was classified.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SetRequestPolicy records (or replaces) the policy for a request.
+func (db *DB) SetRequestPolicy(p *RequestPolicy) error {
+	if p.RequestID == "" || p.PolicyHash == "" {
+		return fmt.Errorf("request policy requires request id and policy hash")
+	}
+	if p.CreatedAt.IsZero() {
+		p.CreatedAt = db.Now()
+	}
+
+	_, err := db.Exec(`
+		INSERT OR REPLACE INTO request_policies (request_id, policy_hash, tier, created_at)
+		VALUES (?, ?, ?, ?)
+	`, p.RequestID, p.PolicyHash, string(p.Tier), p.CreatedAt.Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("recording request policy: %w", err)
+	}
+	return nil
+}
+
+// GetRequestPolicy returns the policy recorded for a request.
+func (db *DB) GetRequestPolicy(requestID string) (*RequestPolicy, error) {
+	p := &RequestPolicy{}
+	var tier, created string
+	err := db.QueryRow(`
+		SELECT request_id, policy_hash, tier, created_at
+		FROM request_policies
+		WHERE request_id = ?
+	`, requestID).Scan(&p.RequestID, &p.PolicyHash, &tier, &created)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRequestPolicyNotFound
+		}
+		return nil, fmt.Errorf("getting request policy: %w", err)
+	}
+	p.Tier = RiskTier(tier)
+	p.CreatedAt, _ = time.Parse(time.RFC3339, created)
+	return p, nil
+}
+
+// PolicyDiscrepancy records a request whose policy changed between
+// classification and execution, and the tier the current policy assigns.
+type PolicyDiscrepancy struct {
+	ID           int64    `json:"id"`
+	RequestID    string   `json:"request_id"`
+	RecordedHash string   `json:"recorded_hash"`
+	CurrentHash  string   `json:"current_hash"`
+	RecordedTier RiskTier `json:"recorded_tier"`
+	CurrentTier  RiskTier `json:"current_tier"`
+	// Blocked is set when the current tier is higher and execution was refused.
+	Blocked    bool      `json:"blocked"`
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// RecordPolicyDiscrepancy stores a discrepancy.
+func (db *DB) RecordPolicyDiscrepancy(d *PolicyDiscrepancy) error {
+	if d.RequestID == "" {
+		return fmt.Errorf("policy discrepancy requires request id")
+	}
+	if d.DetectedAt.IsZero() {
+		d.DetectedAt = db.Now()
+	}
+	result, err := db.Exec(`
+		INSERT INTO policy_discrepancies (
+			request_id, recorded_hash, current_hash, recorded_tier, current_tier, blocked, detected_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, d.RequestID, d.RecordedHash, d.CurrentHash, string(d.RecordedTier), string(d.CurrentTier),
+		boolToInt(d.Blocked), d.DetectedAt.Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("recording policy discrepancy: %w", err)
+	}
+	if d.ID, err = result.LastInsertId(); err != nil {
+		return fmt.Errorf("getting last insert id: %w", err)
+	}
+	return nil
+}
+
+// ListPolicyDiscrepancies returns a request's discrepancies, oldest first.
+func (db *DB) ListPolicyDiscrepancies(requestID string) ([]*PolicyDiscrepancy, error) {
+	rows, err := db.Query(`
+		SELECT id, request_id, recorded_hash, current_hash, recorded_tier, current_tier, blocked, detected_at
+		FROM policy_discrepancies
+		WHERE request_id = ?
+		ORDER BY id
+	`, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("listing policy discrepancies: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*PolicyDiscrepancy
+	for rows.Next() {
+		d := &PolicyDiscrepancy{}
+		var recordedTier, currentTier, detected string
+		var blocked int
+		if err := rows.Scan(&d.ID, &d.RequestID, &d.RecordedHash, &d.CurrentHash,
+			&recordedTier, &currentTier, &blocked, &detected); err != nil {
+			return nil, fmt.Errorf("scanning policy discrepancy: %w", err)
+		}
+		d.RecordedTier = RiskTier(recordedTier)
+		d.CurrentTier = RiskTier(currentTier)
+		d.Blocked = blocked != 0
+		d.DetectedAt, _ = time.Parse(time.RFC3339, detected)
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,60 @@
+package db
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRequestPolicy(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, req := createTestRequest(t, db)
+
+	if _, err := db.GetRequestPolicy(req.ID); !errors.Is(err, ErrRequestPolicyNotFound) {
+		t.Fatalf("expected ErrRequestPolicyNotFound, got %v", err)
+	}
+	if err := db.SetRequestPolicy(&RequestPolicy{RequestID: req.ID}); err == nil {
+		t.Fatal("expected error without policy hash")
+	}
+
+	if err := db.SetRequestPolicy(&RequestPolicy{RequestID: req.ID, PolicyHash: "aaa", Tier: RiskTierDangerous}); err != nil {
+		t.Fatalf("SetRequestPolicy failed: %v", err)
+	}
+	got, err := db.GetRequestPolicy(req.ID)
+	if err != nil || got.PolicyHash != "aaa" || got.Tier != RiskTierDangerous || got.CreatedAt.IsZero() {
+		t.Fatalf("unexpected policy: %+v (err %v)", got, err)
+	}
+}
+
+func TestPolicyDiscrepancies(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, req := createTestRequest(t, db)
+
+	if err := db.RecordPolicyDiscrepancy(&PolicyDiscrepancy{}); err == nil {
+		t.Fatal("expected error without request id")
+	}
+	for _, blocked := range []bool{false, true} {
+		d := &PolicyDiscrepancy{
+			RequestID:    req.ID,
+			RecordedHash: "aaa",
+			CurrentHash:  "bbb",
+			RecordedTier: RiskTierCaution,
+			CurrentTier:  RiskTierDangerous,
+			Blocked:      blocked,
+		}
+		if err := db.RecordPolicyDiscrepancy(d); err != nil || d.ID == 0 {
+			t.Fatalf("RecordPolicyDiscrepancy: id %d, %v", d.ID, err)
+		}
+	}
+
+	list, err := db.ListPolicyDiscrepancies(req.ID)
+	if err != nil {
+		t.Fatalf("ListPolicyDiscrepancies: %v", err)
+	}
+	if len(list) != 2 || list[0].Blocked || !list[1].Blocked || list[1].CurrentTier != RiskTierDangerous || list[1].DetectedAt.IsZero() {
+		t.Fatalf("unexpected discrepancies: %+v", list)
+	}
+}
@@ -0,0 +1,102 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrProvenanceNotFound indicates no provenance was recorded for a request.
+var ErrProvenanceNotFound = errors.New("request provenance not found")
+
+// RequestProvenance records which agent tool attempted a command, giving
+// reviewers context on why it was requested. All fields are optional.
+type RequestProvenance struct {
+	// RequestID is the request this provenance belongs to.
+	RequestID string `json:"request_id"`
+	// AgentProgram is the agent tool that invoked the command (e.g. claude-code).
+	AgentProgram string `json:"agent_program,omitempty"`
+	// ConversationID identifies the agent conversation/session.
+	ConversationID string `json:"conversation_id,omitempty"`
+	// ToolCallID identifies the tool call that attempted the command.
+	ToolCallID string `json:"tool_call_id,omitempty"`
+	// PromptHash is a hash of the originating prompt, when available.
+	PromptHash string `json:"prompt_hash,omitempty"`
+	// PeerUID, PeerGID and PeerPID are the Unix socket credentials of the
+	// process that submitted the request through the daemon.
+	PeerUID *int `json:"peer_uid,omitempty"`
+	PeerGID *int `json:"peer_gid,omitempty"`
+	PeerPID *int `json:"peer_pid,omitempty"`
+	// CreatedAt is when the provenance was recorded.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// IsEmpty reports whether no provenance fields are set.
+func (p *RequestProvenance) IsEmpty() bool {
+	return p == nil || (p.AgentProgram == "" && p.ConversationID == "" && p.ToolCallID == "" && p.PromptHash == "" &&
+		p.PeerUID == nil && p.PeerGID == nil && p.PeerPID == nil)
+}
+
+// SetRequestProvenance records (or replaces) provenance for a request.
+func (db *DB) SetRequestProvenance(p *RequestProvenance) error {
+	if p.RequestID == "" {
+		return fmt.Errorf("request provenance requires request id")
+	}
+	if p.CreatedAt.IsZero() {
+		p.CreatedAt = db.Now()
+	}
+
+	_, err := db.Exec(`
+		INSERT OR REPLACE INTO request_provenance (
+			request_id, agent_program, conversation_id, tool_call_id, prompt_hash,
+			peer_uid, peer_gid, peer_pid, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, p.RequestID, nullString(p.AgentProgram), nullString(p.ConversationID),
+		nullString(p.ToolCallID), nullString(p.PromptHash),
+		nullInt(p.PeerUID), nullInt(p.PeerGID), nullInt(p.PeerPID),
+		p.CreatedAt.Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("recording request provenance: %w", err)
+	}
+	return nil
+}
+
+// GetRequestProvenance returns the provenance recorded for a request.
+func (db *DB) GetRequestProvenance(requestID string) (*RequestProvenance, error) {
+	p := &RequestProvenance{}
+	var program, conversation, toolCall, promptHash sql.NullString
+	var peerUID, peerGID, peerPID sql.NullInt64
+	var created string
+	err := db.QueryRow(`
+		SELECT request_id, agent_program, conversation_id, tool_call_id, prompt_hash,
+			peer_uid, peer_gid, peer_pid, created_at
+		FROM request_provenance
+		WHERE request_id = ?
+	`, requestID).Scan(&p.RequestID, &program, &conversation, &toolCall, &promptHash,
+		&peerUID, &peerGID, &peerPID, &created)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrProvenanceNotFound
+		}
+		return nil, fmt.Errorf("getting request provenance: %w", err)
+	}
+	p.AgentProgram = program.String
+	p.ConversationID = conversation.String
+	p.ToolCallID = toolCall.String
+	p.PromptHash = promptHash.String
+	p.PeerUID = intPtrFromNull(peerUID)
+	p.PeerGID = intPtrFromNull(peerGID)
+	p.PeerPID = intPtrFromNull(peerPID)
+	p.CreatedAt, _ = time.Parse(time.RFC3339, created)
+	return p, nil
+}
+
+// intPtrFromNull converts a nullable integer column to *int.
+func intPtrFromNull(n sql.NullInt64) *int {
+	if !n.Valid {
+		return nil
+	}
+	v := int(n.Int64)
+	return &v
+}
@@ -0,0 +1,79 @@
+// Package db tests for request provenance operations.
+package db
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRequestProvenance(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, req := createTestRequest(t, db)
+
+	if _, err := db.GetRequestProvenance(req.ID); !errors.Is(err, ErrProvenanceNotFound) {
+		t.Fatalf("expected ErrProvenanceNotFound, got %v", err)
+	}
+	if err := db.SetRequestProvenance(&RequestProvenance{}); err == nil {
+		t.Fatal("expected error without request id")
+	}
+
+	p := &RequestProvenance{
+		RequestID:      req.ID,
+		AgentProgram:   "claude-code",
+		ConversationID: "conv-123",
+		ToolCallID:     "toolu_01",
+	}
+	if err := db.SetRequestProvenance(p); err != nil {
+		t.Fatalf("SetRequestProvenance failed: %v", err)
+	}
+
+	got, err := db.GetRequestProvenance(req.ID)
+	if err != nil {
+		t.Fatalf("GetRequestProvenance failed: %v", err)
+	}
+	if got.AgentProgram != "claude-code" || got.ConversationID != "conv-123" || got.ToolCallID != "toolu_01" || got.PromptHash != "" {
+		t.Fatalf("unexpected provenance: %+v", got)
+	}
+
+	// Recording again replaces the previous provenance.
+	p.PromptHash = "abc123"
+	if err := db.SetRequestProvenance(p); err != nil {
+		t.Fatalf("SetRequestProvenance (replace) failed: %v", err)
+	}
+	got, err = db.GetRequestProvenance(req.ID)
+	if err != nil || got.PromptHash != "abc123" {
+		t.Fatalf("expected replaced provenance, got %+v (err %v)", got, err)
+	}
+	if got.PeerUID != nil || got.PeerGID != nil || got.PeerPID != nil {
+		t.Fatalf("expected no peer credentials, got %+v", got)
+	}
+
+	uid, gid, pid := 1000, 100, 4242
+	p.PeerUID, p.PeerGID, p.PeerPID = &uid, &gid, &pid
+	if err := db.SetRequestProvenance(p); err != nil {
+		t.Fatalf("SetRequestProvenance (peer) failed: %v", err)
+	}
+	got, err = db.GetRequestProvenance(req.ID)
+	if err != nil {
+		t.Fatalf("GetRequestProvenance failed: %v", err)
+	}
+	if got.PeerUID == nil || *got.PeerUID != uid || got.PeerGID == nil || *got.PeerGID != gid || got.PeerPID == nil || *got.PeerPID != pid {
+		t.Fatalf("unexpected peer credentials: %+v", got)
+	}
+}
+
+func TestRequestProvenanceIsEmpty(t *testing.T) {
+	var nilProv *RequestProvenance
+	if !nilProv.IsEmpty() || !(&RequestProvenance{RequestID: "r"}).IsEmpty() {
+		t.Fatal("expected nil and field-less provenance to be empty")
+	}
+	if (&RequestProvenance{ToolCallID: "t"}).IsEmpty() {
+		t.Fatal("expected provenance with a tool call id to be non-empty")
+	}
+	uid := 0
+	if (&RequestProvenance{PeerUID: &uid}).IsEmpty() {
+		t.Fatal("expected provenance with a peer uid to be non-empty")
+	}
+}
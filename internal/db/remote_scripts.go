@@ -0,0 +1,80 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// RemoteScript pins a script a request downloads and pipes into a shell
+// (curl ... | bash). It is fetched when the request is created; execution
+// fetches it again and refuses to run if the content changed.
+type RemoteScript struct {
+	// RequestID is the request that runs the script.
+	RequestID string `json:"request_id"`
+	// URL is where the script is downloaded from.
+	URL string `json:"url"`
+	// Fetcher is the download tool: curl or wget.
+	Fetcher string `json:"fetcher"`
+	// Shell is the shell the download is piped into.
+	Shell string `json:"shell"`
+	// SHA256 is the hex digest of the content.
+	SHA256 string `json:"sha256"`
+	// Size is the content length in bytes.
+	Size int64 `json:"size"`
+	// Preview is the start of the content, for reviewers.
+	Preview string `json:"preview,omitempty"`
+	// FetchedAt is when the script was fetched.
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// SetRequestRemoteScript records (or replaces) a pinned remote script.
+func (db *DB) SetRequestRemoteScript(s *RemoteScript) error {
+	if s.FetchedAt.IsZero() {
+		s.FetchedAt = db.Now()
+	}
+	return insertRequestRemoteScript(db, s)
+}
+
+func insertRequestRemoteScript(x execer, s *RemoteScript) error {
+	if s.RequestID == "" || s.URL == "" || s.SHA256 == "" {
+		return fmt.Errorf("remote script requires request id, url and sha256")
+	}
+	_, err := x.Exec(`
+		INSERT OR REPLACE INTO request_remote_scripts (
+			request_id, url, fetcher, shell, sha256, size, preview, fetched_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, s.RequestID, s.URL, s.Fetcher, s.Shell, s.SHA256, s.Size, nullString(s.Preview), s.FetchedAt.Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("recording remote script: %w", err)
+	}
+	return nil
+}
+
+// ListRequestRemoteScripts returns the remote scripts pinned for a request.
+func (db *DB) ListRequestRemoteScripts(requestID string) ([]*RemoteScript, error) {
+	rows, err := db.Query(`
+		SELECT request_id, url, fetcher, shell, sha256, size, preview, fetched_at
+		FROM request_remote_scripts
+		WHERE request_id = ?
+		ORDER BY fetched_at, url
+	`, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("listing remote scripts: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*RemoteScript
+	for rows.Next() {
+		s := &RemoteScript{}
+		var preview sql.NullString
+		var fetched string
+		if err := rows.Scan(&s.RequestID, &s.URL, &s.Fetcher, &s.Shell, &s.SHA256, &s.Size, &preview, &fetched); err != nil {
+			return nil, fmt.Errorf("scanning remote script: %w", err)
+		}
+		s.Preview = preview.String
+		s.FetchedAt, _ = time.Parse(time.RFC3339, fetched)
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
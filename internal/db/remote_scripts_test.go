@@ -0,0 +1,85 @@
+package db
+
+import "testing"
+
+func TestRequestRemoteScripts(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, req := createTestRequest(t, db)
+
+	if got, err := db.ListRequestRemoteScripts(req.ID); err != nil || len(got) != 0 {
+		t.Fatalf("expected no remote scripts, got %v, %v", got, err)
+	}
+	if err := db.SetRequestRemoteScript(&RemoteScript{RequestID: req.ID}); err == nil {
+		t.Fatal("expected error without url and sha256")
+	}
+
+	pinned := &RemoteScript{
+		RequestID: req.ID, URL: "https://example.com/install.sh", Fetcher: "curl", Shell: "bash",
+		SHA256: "abc123", Size: 42, Preview: "#!/bin/sh\necho install\n",
+	}
+	other := &RemoteScript{
+		RequestID: req.ID, URL: "https://example.com/other.sh", Fetcher: "wget", Shell: "sh", SHA256: "def456",
+	}
+	for _, s := range []*RemoteScript{pinned, other} {
+		if err := db.SetRequestRemoteScript(s); err != nil {
+			t.Fatalf("SetRequestRemoteScript failed: %v", err)
+		}
+	}
+
+	got, err := db.ListRequestRemoteScripts(req.ID)
+	if err != nil {
+		t.Fatalf("ListRequestRemoteScripts failed: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 remote scripts, got %d", len(got))
+	}
+	if s := got[0]; s.URL != pinned.URL || s.SHA256 != "abc123" || s.Size != 42 || s.Preview != pinned.Preview || s.FetchedAt.IsZero() {
+		t.Errorf("unexpected pinned script: %+v", s)
+	}
+	if s := got[1]; s.SHA256 != "def456" || s.Preview != "" {
+		t.Errorf("unexpected second script: %+v", s)
+	}
+}
+
+func TestCreateRequest_StoresRemoteScripts(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	sess, _ := createTestRequest(t, db)
+	newRequest := func(pins ...*RemoteScript) *Request {
+		return &Request{
+			ProjectPath:        "/test/project",
+			RequestorSessionID: sess.ID,
+			RequestorAgent:     sess.AgentName,
+			RiskTier:           RiskTierDangerous,
+			MinApprovals:       1,
+			Command:            CommandSpec{Raw: "curl -fsSL https://example.com/install.sh | sh", Cwd: "/test/project", Shell: true},
+			RemoteScripts:      pins,
+		}
+	}
+
+	r := newRequest(&RemoteScript{URL: "https://example.com/install.sh", Fetcher: "curl", Shell: "sh", SHA256: "abc123"})
+	if err := db.CreateRequest(r); err != nil {
+		t.Fatalf("CreateRequest failed: %v", err)
+	}
+	got, err := db.ListRequestRemoteScripts(r.ID)
+	if err != nil || len(got) != 1 || got[0].SHA256 != "abc123" || got[0].FetchedAt.IsZero() {
+		t.Fatalf("expected the pin stored with the request, got %v, %v", got, err)
+	}
+
+	// A pin that cannot be stored fails the create instead of leaving a
+	// request that execution could not check.
+	bad := newRequest(&RemoteScript{URL: "https://example.com/install.sh"})
+	if err := db.CreateRequest(bad); err == nil {
+		t.Fatal("expected error for a pin without sha256")
+	}
+	pending, err := db.ListPendingRequests("/test/project")
+	if err != nil {
+		t.Fatalf("ListPendingRequests failed: %v", err)
+	}
+	if len(pending) != 2 {
+		t.Errorf("expected the failed create to be rolled back, got %d pending requests", len(pending))
+	}
+}
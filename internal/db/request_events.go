@@ -0,0 +1,159 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Request event types other than status changes, which are recorded under
+// the status the request moved to (approved, executed, ...).
+const (
+	RequestEventCreated   = "created"
+	RequestEventViewed    = "viewed"
+	RequestEventReviewed  = "reviewed"
+	RequestEventEscalated = "escalated"
+	// RequestEventEditProposed is a reviewer proposing a corrected command.
+	RequestEventEditProposed = "edit_proposed"
+	// RequestEventEditAccepted is the requestor accepting one, recorded on
+	// both the original request and the one created for the correction.
+	RequestEventEditAccepted = "edit_accepted"
+)
+
+// RequestEvent is one entry in a request's lifecycle timeline.
+type RequestEvent struct {
+	ID        int64  `json:"id"`
+	RequestID string `json:"request_id"`
+	// Type is a RequestEvent* constant or the status the request moved to.
+	Type string `json:"type"`
+	// Actor is who caused the event, when known.
+	Actor     string    `json:"actor,omitempty"`
+	Details   string    `json:"details,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// execer is satisfied by both *DB and *sql.Tx.
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// RecordRequestEvent stores an event, setting its ID.
+func (db *DB) RecordRequestEvent(e *RequestEvent) error {
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = db.Now()
+	}
+	return insertRequestEvent(db, e)
+}
+
+func insertRequestEvent(x execer, e *RequestEvent) error {
+	if e.RequestID == "" || e.Type == "" {
+		return fmt.Errorf("request event requires request id and type")
+	}
+	result, err := x.Exec(`
+		INSERT INTO request_events (request_id, type, actor, details, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, e.RequestID, e.Type, nullString(e.Actor), nullString(e.Details), e.CreatedAt.UTC().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("recording request event: %w", err)
+	}
+	if e.ID, err = result.LastInsertId(); err != nil {
+		return fmt.Errorf("getting last insert id: %w", err)
+	}
+	return nil
+}
+
+// ListRequestEvents returns a request's events in the order they happened.
+func (db *DB) ListRequestEvents(requestID string) ([]*RequestEvent, error) {
+	rows, err := db.Query(`
+		SELECT id, request_id, type, actor, details, created_at
+		FROM request_events
+		WHERE request_id = ?
+		ORDER BY id
+	`, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("listing request events: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*RequestEvent
+	for rows.Next() {
+		e := &RequestEvent{}
+		var actor, details sql.NullString
+		var created string
+		if err := rows.Scan(&e.ID, &e.RequestID, &e.Type, &actor, &details, &created); err != nil {
+			return nil, fmt.Errorf("scanning request event: %w", err)
+		}
+		e.Actor, e.Details = actor.String, details.String
+		e.CreatedAt, _ = time.Parse(time.RFC3339, created)
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// RecordRequestViewed records that viewer opened a request for review. Only
+// a viewer's first look is recorded, and a requestor opening their own
+// request is not a receipt; it reports whether an event was recorded.
+func (db *DB) RecordRequestViewed(requestID, viewer string) (bool, error) {
+	if requestID == "" || strings.TrimSpace(viewer) == "" {
+		return false, fmt.Errorf("viewed receipt requires request id and viewer")
+	}
+	result, err := db.Exec(`
+		INSERT INTO request_events (request_id, type, actor, details, created_at)
+		SELECT ?, ?, ?, NULL, ?
+		WHERE EXISTS (SELECT 1 FROM requests WHERE id = ? AND requestor_agent != ?)
+		  AND NOT EXISTS (SELECT 1 FROM request_events WHERE request_id = ? AND type = ? AND actor = ?)
+	`, requestID, RequestEventViewed, viewer, db.Now().Format(time.RFC3339),
+		requestID, viewer,
+		requestID, RequestEventViewed, viewer)
+	if err != nil {
+		return false, fmt.Errorf("recording viewed receipt: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("checking viewed receipt insert: %w", err)
+	}
+	return n > 0, nil
+}
+
+// UndecidedViewers returns, for each of the given requests that has any,
+// the reviewers who opened it but have not approved or rejected it, in the
+// order they first looked.
+func (db *DB) UndecidedViewers(requestIDs []string) (map[string][]string, error) {
+	viewers := make(map[string][]string)
+	if len(requestIDs) == 0 {
+		return viewers, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(requestIDs)), ",")
+	args := make([]any, 0, len(requestIDs)+1)
+	args = append(args, RequestEventViewed)
+	for _, id := range requestIDs {
+		args = append(args, id)
+	}
+
+	rows, err := db.Query(`
+		SELECT e.request_id, e.actor
+		FROM request_events e
+		WHERE e.type = ? AND e.actor IS NOT NULL
+		  AND e.request_id IN (`+placeholders+`)
+		  AND NOT EXISTS (
+		    SELECT 1 FROM reviews r
+		    WHERE r.request_id = e.request_id AND r.reviewer_agent = e.actor
+		  )
+		ORDER BY e.id
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing undecided viewers: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, actor string
+		if err := rows.Scan(&id, &actor); err != nil {
+			return nil, fmt.Errorf("scanning undecided viewer: %w", err)
+		}
+		viewers[id] = append(viewers[id], actor)
+	}
+	return viewers, rows.Err()
+}
@@ -0,0 +1,113 @@
+package db
+
+import (
+	"testing"
+)
+
+func TestRequestEvents(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	sess, req := createTestRequest(t, db)
+
+	if err := db.RecordRequestEvent(&RequestEvent{RequestID: req.ID}); err == nil {
+		t.Fatal("expected error without type")
+	}
+	viewed := &RequestEvent{RequestID: req.ID, Type: RequestEventViewed, Actor: "alice"}
+	if err := db.RecordRequestEvent(viewed); err != nil || viewed.ID == 0 {
+		t.Fatalf("RecordRequestEvent: id %d, %v", viewed.ID, err)
+	}
+
+	review := &Review{
+		RequestID:         req.ID,
+		ReviewerSessionID: sess.ID,
+		ReviewerAgent:     "Reviewer",
+		Decision:          DecisionApprove,
+		Comments:          "LGTM",
+	}
+	if err := db.CreateReview(review); err != nil {
+		t.Fatalf("CreateReview failed: %v", err)
+	}
+	if err := db.ApplyStatusChange(StatusChange{ID: req.ID, From: StatusPending, To: StatusApproved, At: db.Now(), Actor: "Reviewer", Reason: "quorum met"}); err != nil {
+		t.Fatalf("ApplyStatusChange failed: %v", err)
+	}
+	if _, err := db.MarkAwaitingHuman(&HumanEscalation{RequestID: req.ID, Reason: "no reviewer", Channels: []string{"desktop"}}); err != nil {
+		t.Fatalf("MarkAwaitingHuman failed: %v", err)
+	}
+	// A rejected transition leaves no event behind.
+	if err := db.ApplyStatusChange(StatusChange{ID: req.ID, From: StatusPending, To: StatusRejected, At: db.Now()}); err == nil {
+		t.Fatal("expected a stale transition to fail")
+	}
+
+	events, err := db.ListRequestEvents(req.ID)
+	if err != nil {
+		t.Fatalf("ListRequestEvents failed: %v", err)
+	}
+	want := []struct{ typ, actor, details string }{
+		{RequestEventCreated, req.RequestorAgent, "dangerous"},
+		{RequestEventViewed, "alice", ""},
+		{RequestEventReviewed, "Reviewer", "approve: LGTM"},
+		{"approved", "Reviewer", "quorum met"},
+		{RequestEventEscalated, "", "paged a human: no reviewer (desktop)"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(events), len(want), events)
+	}
+	for i, w := range want {
+		e := events[i]
+		if e.Type != w.typ || e.Actor != w.actor || e.Details != w.details || e.CreatedAt.IsZero() {
+			t.Errorf("event %d = %+v, want %s by %q (%q)", i, e, w.typ, w.actor, w.details)
+		}
+	}
+}
+
+func TestRecordRequestViewed(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	sess, req := createTestRequest(t, db)
+
+	if _, err := db.RecordRequestViewed(req.ID, " "); err == nil {
+		t.Fatal("expected error without viewer")
+	}
+	for _, c := range []struct {
+		viewer string
+		want   bool
+	}{
+		{req.RequestorAgent, false}, // own request
+		{"alice", true},
+		{"alice", false}, // second look
+		{"bob", true},
+	} {
+		got, err := db.RecordRequestViewed(req.ID, c.viewer)
+		if err != nil || got != c.want {
+			t.Errorf("RecordRequestViewed(%q) = %v, %v; want %v", c.viewer, got, err, c.want)
+		}
+	}
+	if got, err := db.RecordRequestViewed("missing", "alice"); err != nil || got {
+		t.Errorf("unknown request: %v, %v", got, err)
+	}
+
+	viewers, err := db.UndecidedViewers([]string{req.ID, "missing"})
+	if err != nil {
+		t.Fatalf("UndecidedViewers: %v", err)
+	}
+	if got := viewers[req.ID]; len(got) != 2 || got[0] != "alice" || got[1] != "bob" || len(viewers) != 1 {
+		t.Fatalf("undecided viewers = %v", viewers)
+	}
+
+	if err := db.CreateReview(&Review{
+		RequestID:         req.ID,
+		ReviewerSessionID: sess.ID,
+		ReviewerAgent:     "alice",
+		Decision:          DecisionReject,
+	}); err != nil {
+		t.Fatalf("CreateReview failed: %v", err)
+	}
+	if viewers, err := db.UndecidedViewers([]string{req.ID}); err != nil || len(viewers[req.ID]) != 1 || viewers[req.ID][0] != "bob" {
+		t.Fatalf("undecided viewers after alice decided = %v, %v", viewers, err)
+	}
+	if viewers, err := db.UndecidedViewers(nil); err != nil || len(viewers) != 0 {
+		t.Fatalf("no requests: %v, %v", viewers, err)
+	}
+}
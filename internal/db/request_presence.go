@@ -0,0 +1,76 @@
+package db
+
+import (
+	"fmt"
+	"time"
+)
+
+// RequestViewer is a reviewer who has a request open right now.
+type RequestViewer struct {
+	SessionID string `json:"session_id"`
+	Viewer    string `json:"viewer"`
+	// Composing is set while the viewer has an approval or rejection form
+	// open.
+	Composing bool      `json:"composing,omitempty"`
+	SeenAt    time.Time `json:"seen_at"`
+}
+
+// TouchRequestPresence records that a session is looking at a request. A
+// session is present on one request at a time, so touching another request
+// moves it there.
+func (db *DB) TouchRequestPresence(requestID, sessionID, viewer string, composing bool) error {
+	if requestID == "" || sessionID == "" || viewer == "" {
+		return fmt.Errorf("request presence requires request id, session id and viewer")
+	}
+	_, err := db.Exec(`
+		INSERT INTO request_presence (session_id, request_id, viewer, composing, seen_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(session_id) DO UPDATE SET
+		  request_id = excluded.request_id,
+		  viewer = excluded.viewer,
+		  composing = excluded.composing,
+		  seen_at = excluded.seen_at
+	`, sessionID, requestID, viewer, boolToInt(composing), db.Now().UTC().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("recording request presence: %w", err)
+	}
+	return nil
+}
+
+// ClearRequestPresence records that a session closed whatever request it had
+// open.
+func (db *DB) ClearRequestPresence(sessionID string) error {
+	if _, err := db.Exec(`DELETE FROM request_presence WHERE session_id = ?`, sessionID); err != nil {
+		return fmt.Errorf("clearing request presence: %w", err)
+	}
+	return nil
+}
+
+// RequestViewers returns who has had a request open since the given time,
+// in the order they last checked in.
+func (db *DB) RequestViewers(requestID string, since time.Time) ([]RequestViewer, error) {
+	rows, err := db.Query(`
+		SELECT session_id, viewer, composing, seen_at
+		FROM request_presence
+		WHERE request_id = ? AND seen_at >= ?
+		ORDER BY seen_at, viewer
+	`, requestID, since.UTC().Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("listing request viewers: %w", err)
+	}
+	defer rows.Close()
+
+	var out []RequestViewer
+	for rows.Next() {
+		var v RequestViewer
+		var composing int
+		var seen string
+		if err := rows.Scan(&v.SessionID, &v.Viewer, &composing, &seen); err != nil {
+			return nil, fmt.Errorf("scanning request viewer: %w", err)
+		}
+		v.Composing = composing != 0
+		v.SeenAt, _ = time.Parse(time.RFC3339, seen)
+		out = append(out, v)
+	}
+	return out, rows.Err()
+}
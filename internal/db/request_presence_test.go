@@ -0,0 +1,59 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/clock"
+)
+
+func TestRequestPresence(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	db.SetClock(clock.Func(func() time.Time { return now }))
+
+	_, first := createTestRequest(t, db)
+	_, second := createTestRequest(t, db)
+
+	if err := db.TouchRequestPresence(first.ID, "", "Alice", false); err == nil {
+		t.Fatal("expected error without session id")
+	}
+	if err := db.TouchRequestPresence(first.ID, "sess-a", "Alice", false); err != nil {
+		t.Fatalf("TouchRequestPresence failed: %v", err)
+	}
+	now = now.Add(time.Second)
+	if err := db.TouchRequestPresence(first.ID, "sess-b", "Bob", true); err != nil {
+		t.Fatalf("TouchRequestPresence failed: %v", err)
+	}
+
+	viewers, err := db.RequestViewers(first.ID, now.Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("RequestViewers failed: %v", err)
+	}
+	if len(viewers) != 2 || viewers[0].Viewer != "Alice" || viewers[0].Composing ||
+		viewers[1].Viewer != "Bob" || !viewers[1].Composing {
+		t.Fatalf("unexpected viewers: %+v", viewers)
+	}
+
+	// Checking in past the window drops stale viewers.
+	if viewers, _ := db.RequestViewers(first.ID, now); len(viewers) != 1 || viewers[0].SessionID != "sess-b" {
+		t.Fatalf("expected only Bob within the window, got %+v", viewers)
+	}
+
+	// Opening another request moves the session there.
+	if err := db.TouchRequestPresence(second.ID, "sess-a", "Alice", false); err != nil {
+		t.Fatalf("TouchRequestPresence failed: %v", err)
+	}
+	if viewers, _ := db.RequestViewers(first.ID, time.Time{}); len(viewers) != 1 || viewers[0].Viewer != "Bob" {
+		t.Fatalf("expected Alice to have left the first request, got %+v", viewers)
+	}
+
+	if err := db.ClearRequestPresence("sess-b"); err != nil {
+		t.Fatalf("ClearRequestPresence failed: %v", err)
+	}
+	if viewers, _ := db.RequestViewers(first.ID, time.Time{}); len(viewers) != 0 {
+		t.Fatalf("expected no viewers after clearing, got %+v", viewers)
+	}
+}
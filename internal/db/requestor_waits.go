@@ -0,0 +1,65 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MarkRequestorWaiting records that the requestor is blocked waiting for a
+// decision on a request. Marking an already waiting request keeps the
+// original start time.
+func (db *DB) MarkRequestorWaiting(requestID, sessionID string) error {
+	if requestID == "" {
+		return fmt.Errorf("requestor wait requires request id")
+	}
+	_, err := db.Exec(`
+		INSERT OR IGNORE INTO requestor_waits (request_id, session_id, since)
+		VALUES (?, ?, ?)
+	`, requestID, nullString(sessionID), db.Now().UTC().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("marking requestor waiting: %w", err)
+	}
+	return nil
+}
+
+// ClearRequestorWaiting records that the requestor stopped waiting.
+func (db *DB) ClearRequestorWaiting(requestID string) error {
+	if _, err := db.Exec(`DELETE FROM requestor_waits WHERE request_id = ?`, requestID); err != nil {
+		return fmt.Errorf("clearing requestor wait: %w", err)
+	}
+	return nil
+}
+
+// WaitingRequestIDs returns which of the given requests have a requestor
+// blocked waiting on them.
+func (db *DB) WaitingRequestIDs(requestIDs []string) (map[string]bool, error) {
+	waiting := make(map[string]bool)
+	if len(requestIDs) == 0 {
+		return waiting, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(requestIDs)), ",")
+	args := make([]any, len(requestIDs))
+	for i, id := range requestIDs {
+		args[i] = id
+	}
+
+	rows, err := db.Query(`
+		SELECT request_id FROM requestor_waits
+		WHERE request_id IN (`+placeholders+`)
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing requestor waits: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scanning requestor wait: %w", err)
+		}
+		waiting[id] = true
+	}
+	return waiting, rows.Err()
+}
@@ -0,0 +1,40 @@
+package db
+
+import "testing"
+
+func TestRequestorWaits(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	sess, waiting := createTestRequest(t, db)
+	_, idle := createTestRequest(t, db)
+
+	if err := db.MarkRequestorWaiting("", sess.ID); err == nil {
+		t.Fatal("expected error without request id")
+	}
+	if err := db.MarkRequestorWaiting(waiting.ID, sess.ID); err != nil {
+		t.Fatalf("MarkRequestorWaiting failed: %v", err)
+	}
+	// A second waiter on the same request is a no-op.
+	if err := db.MarkRequestorWaiting(waiting.ID, ""); err != nil {
+		t.Fatalf("MarkRequestorWaiting (again) failed: %v", err)
+	}
+
+	got, err := db.WaitingRequestIDs([]string{waiting.ID, idle.ID})
+	if err != nil {
+		t.Fatalf("WaitingRequestIDs failed: %v", err)
+	}
+	if !got[waiting.ID] || got[idle.ID] {
+		t.Fatalf("unexpected waiting set: %v", got)
+	}
+
+	if err := db.ClearRequestorWaiting(waiting.ID); err != nil {
+		t.Fatalf("ClearRequestorWaiting failed: %v", err)
+	}
+	if got, _ := db.WaitingRequestIDs([]string{waiting.ID}); got[waiting.ID] {
+		t.Error("expected the wait to be cleared")
+	}
+	if got, err := db.WaitingRequestIDs(nil); err != nil || len(got) != 0 {
+		t.Errorf("WaitingRequestIDs(nil) = %v, %v", got, err)
+	}
+}
@@ -26,6 +26,18 @@ const DefaultRequestTimeout = 30 * time.Minute
 // CreateRequest creates a new request in the database.
 // Generates a UUID and computes the command hash.
 func (db *DB) CreateRequest(r *Request) error {
+	if err := db.prepareNewRequest(r); err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	if err := db.Transaction(func(tx *sql.Tx) error { return insertRequestTx(tx, r) }); err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	return nil
+}
+
+// prepareNewRequest fills in the ID, command hash, status and timestamps of
+// a request about to be inserted and validates its labels.
+func (db *DB) prepareNewRequest(r *Request) error {
 	// Generate UUID if not set
 	if r.ID == "" {
 		r.ID = uuid.New().String()
@@ -37,21 +49,32 @@ func (db *DB) CreateRequest(r *Request) error {
 	}
 
 	// Set timestamps
-	now := time.Now().UTC()
+	now := db.Now()
 	r.CreatedAt = now
 	if r.Status == "" {
 		r.Status = StatusPending
 	}
+	r.Version = 1
 	if r.ExpiresAt == nil {
 		expiresAt := now.Add(DefaultRequestTimeout)
 		r.ExpiresAt = &expiresAt
 	}
 
+	for key, value := range r.Labels {
+		if err := ValidateLabel(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// insertRequestTx inserts a prepared request and its labels.
+func insertRequestTx(tx *sql.Tx, r *Request) error {
 	// Serialize complex fields (errors intentionally ignored - empty JSON arrays are acceptable defaults)
 	argvJSON, _ := json.Marshal(r.Command.Argv)       //nolint:errcheck
 	attachmentsJSON, _ := json.Marshal(r.Attachments) //nolint:errcheck
 
-	_, err := db.Exec(`
+	_, err := tx.Exec(`
 		INSERT INTO requests (
 			id, project_path,
 			command_raw, command_argv_json, command_cwd, command_shell, command_hash,
@@ -60,9 +83,10 @@ func (db *DB) CreateRequest(r *Request) error {
 			justification_reason, justification_expected_effect, justification_goal, justification_safety_argument,
 			dry_run_command, dry_run_output, attachments_json,
 			status, min_approvals, require_different_model,
-			created_at, expires_at, approval_expires_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`,
+			created_at, expires_at, approval_expires_at, risk_score,
+			redact_patterns_json
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`,
 		r.ID, r.ProjectPath,
 		r.Command.Raw, string(argvJSON), r.Command.Cwd, boolToInt(r.Command.Shell), r.Command.Hash,
 		nullString(r.Command.DisplayRedacted), boolToInt(r.Command.ContainsSensitive),
@@ -70,13 +94,60 @@ func (db *DB) CreateRequest(r *Request) error {
 		r.Justification.Reason, nullString(r.Justification.ExpectedEffect), nullString(r.Justification.Goal), nullString(r.Justification.SafetyArgument),
 		nullDryRunCommand(r.DryRun), nullDryRunOutput(r.DryRun), string(attachmentsJSON),
 		string(r.Status), r.MinApprovals, boolToInt(r.RequireDifferentModel),
-		r.CreatedAt.Format(time.RFC3339), formatTimePtr(r.ExpiresAt), formatTimePtr(r.ApprovalExpiresAt),
+		r.CreatedAt.Format(time.RFC3339), formatTimePtr(r.ExpiresAt), formatTimePtr(r.ApprovalExpiresAt), r.RiskScore,
+		nullStringsJSON(r.RedactPatterns),
 	)
-
 	if err != nil {
-		return fmt.Errorf("creating request: %w", err)
+		return err
+	}
+	if err := insertRequestEvent(tx, &RequestEvent{
+		RequestID: r.ID,
+		Type:      RequestEventCreated,
+		Actor:     r.RequestorAgent,
+		Details:   string(r.RiskTier),
+		CreatedAt: r.CreatedAt,
+	}); err != nil {
+		return err
+	}
+	if err := insertLabelsTx(tx, r.ID, r.Labels); err != nil {
+		return err
+	}
+	if r.Script != nil {
+		r.Script.RequestID = r.ID
+		if r.Script.CreatedAt.IsZero() {
+			r.Script.CreatedAt = r.CreatedAt
+		}
+		if err := insertRequestScript(tx, r.Script); err != nil {
+			return err
+		}
+	}
+	for _, s := range r.RemoteScripts {
+		s.RequestID = r.ID
+		if s.FetchedAt.IsZero() {
+			s.FetchedAt = r.CreatedAt
+		}
+		if err := insertRequestRemoteScript(tx, s); err != nil {
+			return err
+		}
+	}
+	if r.Binary != nil {
+		r.Binary.RequestID = r.ID
+		if r.Binary.CreatedAt.IsZero() {
+			r.Binary.CreatedAt = r.CreatedAt
+		}
+		if err := insertRequestBinary(tx, r.Binary); err != nil {
+			return err
+		}
+	}
+	if r.Environment != nil {
+		r.Environment.RequestID = r.ID
+		if r.Environment.CreatedAt.IsZero() {
+			r.Environment.CreatedAt = r.CreatedAt
+		}
+		if err := insertRequestEnvironment(tx, r.Environment); err != nil {
+			return err
+		}
 	}
-
 	return nil
 }
 
@@ -91,9 +162,10 @@ func (db *DB) GetRequestTx(tx *sql.Tx, id string) (*Request, error) {
 			dry_run_command, dry_run_output, attachments_json,
 			status, min_approvals, require_different_model,
 			execution_log_path, execution_exit_code, execution_duration_ms,
-			execution_executed_at, execution_executed_by_session_id, execution_executed_by_agent, execution_executed_by_model,
+			execution_executed_at, execution_executed_by_session_id, execution_executed_by_agent, execution_executed_by_model, execution_secrets_json,
 			rollback_path, rollback_rolled_back_at,
-			created_at, resolved_at, expires_at, approval_expires_at
+			created_at, resolved_at, expires_at, approval_expires_at, version, risk_score,
+			import_source, import_external_id, imported_at
 		FROM requests WHERE id = ?
 	`, id)
 
@@ -111,9 +183,10 @@ func (db *DB) GetRequest(id string) (*Request, error) {
 			dry_run_command, dry_run_output, attachments_json,
 			status, min_approvals, require_different_model,
 			execution_log_path, execution_exit_code, execution_duration_ms,
-			execution_executed_at, execution_executed_by_session_id, execution_executed_by_agent, execution_executed_by_model,
+			execution_executed_at, execution_executed_by_session_id, execution_executed_by_agent, execution_executed_by_model, execution_secrets_json,
 			rollback_path, rollback_rolled_back_at,
-			created_at, resolved_at, expires_at, approval_expires_at
+			created_at, resolved_at, expires_at, approval_expires_at, version, risk_score,
+			import_source, import_external_id, imported_at
 		FROM requests WHERE id = ?
 	`, id)
 
@@ -129,7 +202,8 @@ func (db *DB) GetRequestWithReviews(id string) (*Request, []*Review, error) {
 
 	rows, err := db.Query(`
 		SELECT id, request_id, reviewer_session_id, reviewer_agent, reviewer_model,
-			decision, signature, signature_timestamp, responses_json, comments, created_at
+			decision, signature, signature_timestamp, responses_json, comments,
+			auth_method, authenticated_at, created_at
 		FROM reviews WHERE request_id = ?
 		ORDER BY created_at ASC
 	`, id)
@@ -173,9 +247,10 @@ func (db *DB) ListPendingRequestsByProjects(projectPaths []string) ([]*Request,
 			dry_run_command, dry_run_output, attachments_json,
 			status, min_approvals, require_different_model,
 			execution_log_path, execution_exit_code, execution_duration_ms,
-			execution_executed_at, execution_executed_by_session_id, execution_executed_by_agent, execution_executed_by_model,
+			execution_executed_at, execution_executed_by_session_id, execution_executed_by_agent, execution_executed_by_model, execution_secrets_json,
 			rollback_path, rollback_rolled_back_at,
-			created_at, resolved_at, expires_at, approval_expires_at
+			created_at, resolved_at, expires_at, approval_expires_at, version, risk_score,
+			import_source, import_external_id, imported_at
 		FROM requests
 		WHERE project_path IN (%s) AND status = ?
 		ORDER BY created_at DESC
@@ -201,9 +276,10 @@ func (db *DB) ListPendingRequestsAllProjects() ([]*Request, error) {
 			dry_run_command, dry_run_output, attachments_json,
 			status, min_approvals, require_different_model,
 			execution_log_path, execution_exit_code, execution_duration_ms,
-			execution_executed_at, execution_executed_by_session_id, execution_executed_by_agent, execution_executed_by_model,
+			execution_executed_at, execution_executed_by_session_id, execution_executed_by_agent, execution_executed_by_model, execution_secrets_json,
 			rollback_path, rollback_rolled_back_at,
-			created_at, resolved_at, expires_at, approval_expires_at
+			created_at, resolved_at, expires_at, approval_expires_at, version, risk_score,
+			import_source, import_external_id, imported_at
 		FROM requests WHERE status = ?
 		ORDER BY created_at DESC
 	`, string(StatusPending))
@@ -226,9 +302,10 @@ func (db *DB) ListRequestsByStatus(status RequestStatus, projectPath string) ([]
 			dry_run_command, dry_run_output, attachments_json,
 			status, min_approvals, require_different_model,
 			execution_log_path, execution_exit_code, execution_duration_ms,
-			execution_executed_at, execution_executed_by_session_id, execution_executed_by_agent, execution_executed_by_model,
+			execution_executed_at, execution_executed_by_session_id, execution_executed_by_agent, execution_executed_by_model, execution_secrets_json,
 			rollback_path, rollback_rolled_back_at,
-			created_at, resolved_at, expires_at, approval_expires_at
+			created_at, resolved_at, expires_at, approval_expires_at, version, risk_score,
+			import_source, import_external_id, imported_at
 		FROM requests WHERE status = ? AND project_path = ?
 		ORDER BY created_at DESC
 	`, string(status), projectPath)
@@ -251,9 +328,10 @@ func (db *DB) ListAllRequests(projectPath string) ([]*Request, error) {
 			dry_run_command, dry_run_output, attachments_json,
 			status, min_approvals, require_different_model,
 			execution_log_path, execution_exit_code, execution_duration_ms,
-			execution_executed_at, execution_executed_by_session_id, execution_executed_by_agent, execution_executed_by_model,
+			execution_executed_at, execution_executed_by_session_id, execution_executed_by_agent, execution_executed_by_model, execution_secrets_json,
 			rollback_path, rollback_rolled_back_at,
-			created_at, resolved_at, expires_at, approval_expires_at
+			created_at, resolved_at, expires_at, approval_expires_at, version, risk_score,
+			import_source, import_external_id, imported_at
 		FROM requests WHERE project_path = ?
 		ORDER BY created_at DESC
 	`, projectPath)
@@ -265,74 +343,107 @@ func (db *DB) ListAllRequests(projectPath string) ([]*Request, error) {
 	return scanRequests(rows)
 }
 
-// UpdateRequestStatusTx updates a request's status within a transaction.
-func (db *DB) UpdateRequestStatusTx(tx *sql.Tx, id string, status RequestStatus, currentStatus RequestStatus) error {
-	// Validate transition using state machine
-	if !canTransition(currentStatus, status) {
-		return fmt.Errorf("%w: from %s to %s", ErrInvalidTransition, currentStatus, status)
+// ListRequestsBySession returns the requests a session submitted, newest
+// first.
+func (db *DB) ListRequestsBySession(sessionID string) ([]*Request, error) {
+	rows, err := db.Query(`
+		SELECT id, project_path,
+			command_raw, command_argv_json, command_cwd, command_shell, command_hash,
+			command_display_redacted, command_contains_sensitive,
+			risk_tier, requestor_session_id, requestor_agent, requestor_model,
+			justification_reason, justification_expected_effect, justification_goal, justification_safety_argument,
+			dry_run_command, dry_run_output, attachments_json,
+			status, min_approvals, require_different_model,
+			execution_log_path, execution_exit_code, execution_duration_ms,
+			execution_executed_at, execution_executed_by_session_id, execution_executed_by_agent, execution_executed_by_model, execution_secrets_json,
+			rollback_path, rollback_rolled_back_at,
+			created_at, resolved_at, expires_at, approval_expires_at, version, risk_score,
+			import_source, import_external_id, imported_at
+		FROM requests WHERE requestor_session_id = ?
+		ORDER BY created_at DESC
+	`, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("querying requests by session: %w", err)
 	}
+	defer rows.Close()
 
-	// Build update query
-	now := time.Now().UTC().Format(time.RFC3339)
-	var resolvedAt sql.NullString
-	if status.IsTerminal() {
-		resolvedAt = sql.NullString{String: now, Valid: true}
-	}
+	return scanRequests(rows)
+}
 
-	// Optimistic locking: ensure status hasn't changed since we read it
-	result, err := tx.Exec(`
-		UPDATE requests SET status = ?, resolved_at = ? WHERE id = ? AND status = ?
-	`, string(status), resolvedAt, id, string(currentStatus))
+// RequestStatusesBySession returns the status of each request a session
+// submitted that is still open or resolved at or after since.
+func (db *DB) RequestStatusesBySession(sessionID string, since time.Time) (map[string]RequestStatus, error) {
+	rows, err := db.Query(`
+		SELECT id, status FROM requests
+		WHERE requestor_session_id = ? AND (resolved_at IS NULL OR resolved_at >= ?)
+	`, sessionID, since.UTC().Format(time.RFC3339))
 	if err != nil {
-		return fmt.Errorf("updating request status: %w", err)
+		return nil, fmt.Errorf("querying request statuses: %w", err)
 	}
+	defer rows.Close()
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("getting rows affected: %w", err)
-	}
-	if rowsAffected == 0 {
-		return fmt.Errorf("%w: concurrent update detected or request not found", ErrInvalidTransition)
+	statuses := make(map[string]RequestStatus)
+	for rows.Next() {
+		var id, status string
+		if err := rows.Scan(&id, &status); err != nil {
+			return nil, fmt.Errorf("scanning request status: %w", err)
+		}
+		statuses[id] = RequestStatus(status)
 	}
+	return statuses, rows.Err()
+}
 
-	return nil
+// StatusChange is one status transition to store. Whether the transition is
+// allowed is decided by core/statemachine; the database only refuses moves out
+// of terminal states and compares-and-swaps on From.
+type StatusChange struct {
+	ID   string
+	From RequestStatus
+	To   RequestStatus
+	// At is when the transition happened; it becomes resolved_at for
+	// terminal states.
+	At time.Time
+	// ApprovalExpiresAt is stored when set, normally on approval.
+	ApprovalExpiresAt *time.Time
+	// Actor and Reason, when known, go into the request's timeline.
+	Actor  string
+	Reason string
 }
 
-// UpdateRequestStatus updates a request's status using the state machine.
-func (db *DB) UpdateRequestStatus(id string, status RequestStatus) error {
-	// Get current request
-	r, err := db.GetRequest(id)
-	if err != nil {
+// ApplyStatusChangeTx stores a status change within a transaction. It
+// returns ErrInvalidTransition when the request is no longer in c.From.
+func (db *DB) ApplyStatusChangeTx(tx *sql.Tx, c StatusChange) error {
+	if err := c.check(); err != nil {
 		return err
 	}
-
-	// Validate transition using state machine
-	if !canTransition(r.Status, status) {
-		return fmt.Errorf("%w: from %s to %s", ErrInvalidTransition, r.Status, status)
+	applied, err := applyStatusChangeTx(tx, c)
+	if err != nil {
+		return err
 	}
-
-	// Build update query
-	now := time.Now().UTC().Format(time.RFC3339)
-	var resolvedAt sql.NullString
-	if status.IsTerminal() {
-		resolvedAt = sql.NullString{String: now, Valid: true}
+	if !applied {
+		return fmt.Errorf("%w: concurrent update detected or request not found", ErrInvalidTransition)
 	}
+	return nil
+}
 
-	// Optimistic locking: ensure status hasn't changed since we read it
-	result, err := db.Exec(`
-		UPDATE requests SET status = ?, resolved_at = ? WHERE id = ? AND status = ?
-	`, string(status), resolvedAt, id, string(r.Status))
-	if err != nil {
-		return fmt.Errorf("updating request status: %w", err)
+// ApplyStatusChange stores a status change. It returns ErrInvalidTransition
+// when the request is no longer in c.From.
+func (db *DB) ApplyStatusChange(c StatusChange) error {
+	if err := c.check(); err != nil {
+		return err
 	}
-
-	rowsAffected, err := result.RowsAffected()
+	var applied bool
+	err := db.Transaction(func(tx *sql.Tx) error {
+		var err error
+		applied, err = applyStatusChangeTx(tx, c)
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("getting rows affected: %w", err)
+		return err
 	}
-	if rowsAffected == 0 {
+	if !applied {
 		// Check if request disappeared or status changed
-		latest, err := db.GetRequest(id)
+		latest, err := db.GetRequest(c.ID)
 		if err != nil {
 			if errors.Is(err, ErrRequestNotFound) {
 				return ErrRequestNotFound
@@ -340,35 +451,124 @@ func (db *DB) UpdateRequestStatus(id string, status RequestStatus) error {
 			return fmt.Errorf("checking request status after failed update: %w", err)
 		}
 		// Status changed concurrently
-		return fmt.Errorf("%w: concurrent update detected (wanted %s, got %s)", ErrInvalidTransition, r.Status, latest.Status)
+		return fmt.Errorf("%w: concurrent update detected (wanted %s, got %s)", ErrInvalidTransition, c.From, latest.Status)
+	}
+	return nil
+}
+
+// Optimistic locking: ensure status hasn't changed since it was read.
+const statusChangeSQL = `
+	UPDATE requests SET status = ?, resolved_at = ?, approval_expires_at = COALESCE(?, approval_expires_at),
+		version = version + 1
+	WHERE id = ? AND status = ?
+`
+
+// applyStatusChangeTx stores c and its timeline event, reporting whether
+// the request was still in c.From.
+func applyStatusChangeTx(tx *sql.Tx, c StatusChange) (bool, error) {
+	result, err := tx.Exec(statusChangeSQL, c.args()...)
+	if err != nil {
+		return false, fmt.Errorf("updating request status: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("getting rows affected: %w", err)
 	}
+	if rowsAffected == 0 {
+		return false, nil
+	}
+	err = insertRequestEvent(tx, &RequestEvent{
+		RequestID: c.ID,
+		Type:      string(c.To),
+		Actor:     c.Actor,
+		Details:   c.Reason,
+		CreatedAt: c.At,
+	})
+	return err == nil, err
+}
 
+func (c StatusChange) check() error {
+	if c.From.IsTerminal() || c.From == c.To {
+		return fmt.Errorf("%w: from %s to %s", ErrInvalidTransition, c.From, c.To)
+	}
 	return nil
 }
 
-// canTransition checks if a state transition is valid.
-func canTransition(from, to RequestStatus) bool {
-	// Terminal states cannot transition
-	if from.IsTerminal() {
-		return false
+func (c StatusChange) args() []any {
+	var resolvedAt, approvalExpiresAt sql.NullString
+	if c.To.IsTerminal() {
+		resolvedAt = sql.NullString{String: c.At.UTC().Format(time.RFC3339), Valid: true}
+	}
+	if c.ApprovalExpiresAt != nil {
+		approvalExpiresAt = sql.NullString{String: c.ApprovalExpiresAt.UTC().Format(time.RFC3339), Valid: true}
+	}
+	return []any{string(c.To), resolvedAt, approvalExpiresAt, c.ID, string(c.From)}
+}
+
+// ErrVersionConflict is matched by every *VersionConflictError.
+var ErrVersionConflict = errors.New("request version conflict")
+
+// VersionConflictError is returned when a request changed after the caller
+// read it, so a decision based on that read may be stale.
+type VersionConflictError struct {
+	RequestID string
+	Expected  int
+	Current   int
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("request %s changed since it was read (version %d, now %d); refresh and decide again",
+		e.RequestID, e.Expected, e.Current)
+}
+
+// Is reports whether target is ErrVersionConflict.
+func (e *VersionConflictError) Is(target error) bool {
+	return target == ErrVersionConflict
+}
+
+// BumpRequestVersionTx advances a request's version from expected. It fails
+// with a *VersionConflictError if another writer advanced it first.
+func (db *DB) BumpRequestVersionTx(tx *sql.Tx, id string, expected int) error {
+	result, err := tx.Exec(`UPDATE requests SET version = version + 1 WHERE id = ? AND version = ?`, id, expected)
+	if err != nil {
+		return fmt.Errorf("bumping request version: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("getting rows affected: %w", err)
+	}
+	if rowsAffected > 0 {
+		return nil
+	}
+	current, err := db.RequestVersionTx(tx, id)
+	if err != nil {
+		return err
+	}
+	return &VersionConflictError{RequestID: id, Expected: expected, Current: current}
+}
+
+// RequestVersionTx returns a request's current version within a transaction.
+func (db *DB) RequestVersionTx(tx *sql.Tx, id string) (int, error) {
+	var version int
+	err := tx.QueryRow(`SELECT version FROM requests WHERE id = ?`, id).Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrRequestNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("reading request version: %w", err)
 	}
+	return version, nil
+}
 
-	switch from {
-	case StatusPending:
-		return to == StatusApproved || to == StatusRejected || to == StatusCancelled || to == StatusTimeout
-	case StatusApproved:
-		return to == StatusExecuting || to == StatusCancelled
-	case StatusExecuting:
-		// Note: StatusApproved allows reverting execution when setup fails before command starts
-		return to == StatusExecuted || to == StatusExecutionFailed || to == StatusTimedOut || to == StatusApproved
-	case StatusTimeout:
-		return to == StatusEscalated
-	case StatusEscalated:
-		// Human intervention: can approve or reject after escalation
-		return to == StatusApproved || to == StatusRejected
-	default:
-		return false
+// UpdateRequestStatus updates a request's status without the state machine's
+// transition table or guards. Production code changes status through
+// core/statemachine; this is for fixtures and tools.
+func (db *DB) UpdateRequestStatus(id string, status RequestStatus) error {
+	r, err := db.GetRequest(id)
+	if err != nil {
+		return err
 	}
+	return db.ApplyStatusChange(StatusChange{ID: id, From: r.Status, To: status, At: db.Now()})
 }
 
 // UpdateRequestExecution updates the execution details for a request.
@@ -381,7 +581,8 @@ func (db *DB) UpdateRequestExecution(id string, exec *Execution) error {
 			execution_executed_at = ?,
 			execution_executed_by_session_id = ?,
 			execution_executed_by_agent = ?,
-			execution_executed_by_model = ?
+			execution_executed_by_model = ?,
+			execution_secrets_json = ?
 		WHERE id = ?
 	`,
 		nullString(exec.LogPath),
@@ -391,6 +592,7 @@ func (db *DB) UpdateRequestExecution(id string, exec *Execution) error {
 		nullString(exec.ExecutedBySessionID),
 		nullString(exec.ExecutedByAgent),
 		nullString(exec.ExecutedByModel),
+		nullStringsJSON(exec.SecretsFound),
 		id,
 	)
 	if err != nil {
@@ -477,7 +679,7 @@ func (db *DB) OldestRequestCreatedAtSince(sessionID string, since time.Time) (*t
 // CountRecentRequestsBySession counts requests created in the last N seconds for a session.
 // Used for rate limiting (e.g., max requests per minute).
 func (db *DB) CountRecentRequestsBySession(sessionID string, windowSeconds int) (int, error) {
-	since := time.Now().UTC().Add(-time.Duration(windowSeconds) * time.Second)
+	since := db.Now().Add(-time.Duration(windowSeconds) * time.Second)
 	return db.CountRequestsSince(sessionID, since)
 }
 
@@ -492,9 +694,10 @@ func (db *DB) SearchRequests(query string) ([]*Request, error) {
 			r.dry_run_command, r.dry_run_output, r.attachments_json,
 			r.status, r.min_approvals, r.require_different_model,
 			r.execution_log_path, r.execution_exit_code, r.execution_duration_ms,
-			r.execution_executed_at, r.execution_executed_by_session_id, r.execution_executed_by_agent, r.execution_executed_by_model,
+			r.execution_executed_at, r.execution_executed_by_session_id, r.execution_executed_by_agent, r.execution_executed_by_model, r.execution_secrets_json,
 			r.rollback_path, r.rollback_rolled_back_at,
-			r.created_at, r.resolved_at, r.expires_at, r.approval_expires_at
+			r.created_at, r.resolved_at, r.expires_at, r.approval_expires_at, r.version, r.risk_score,
+			r.import_source, r.import_external_id, r.imported_at
 		FROM requests r
 		JOIN requests_fts fts ON r.rowid = fts.rowid
 		WHERE requests_fts MATCH ?
@@ -511,7 +714,7 @@ func (db *DB) SearchRequests(query string) ([]*Request, error) {
 
 // FindExpiredRequests finds pending requests that have expired.
 func (db *DB) FindExpiredRequests() ([]*Request, error) {
-	now := time.Now().UTC().Format(time.RFC3339)
+	now := db.Now().Format(time.RFC3339)
 	rows, err := db.Query(`
 		SELECT id, project_path,
 			command_raw, command_argv_json, command_cwd, command_shell, command_hash,
@@ -521,9 +724,10 @@ func (db *DB) FindExpiredRequests() ([]*Request, error) {
 			dry_run_command, dry_run_output, attachments_json,
 			status, min_approvals, require_different_model,
 			execution_log_path, execution_exit_code, execution_duration_ms,
-			execution_executed_at, execution_executed_by_session_id, execution_executed_by_agent, execution_executed_by_model,
+			execution_executed_at, execution_executed_by_session_id, execution_executed_by_agent, execution_executed_by_model, execution_secrets_json,
 			rollback_path, rollback_rolled_back_at,
-			created_at, resolved_at, expires_at, approval_expires_at
+			created_at, resolved_at, expires_at, approval_expires_at, version, risk_score,
+			import_source, import_external_id, imported_at
 		FROM requests
 		WHERE status = ? AND expires_at IS NOT NULL AND expires_at < ?
 		ORDER BY expires_at ASC
@@ -536,6 +740,30 @@ func (db *DB) FindExpiredRequests() ([]*Request, error) {
 	return scanRequests(rows)
 }
 
+// LastExecutedRequest returns the most recently executed request for a
+// project, or ErrRequestNotFound if none has run.
+func (db *DB) LastExecutedRequest(projectPath string) (*Request, error) {
+	row := db.QueryRow(`
+		SELECT id, project_path,
+			command_raw, command_argv_json, command_cwd, command_shell, command_hash,
+			command_display_redacted, command_contains_sensitive,
+			risk_tier, requestor_session_id, requestor_agent, requestor_model,
+			justification_reason, justification_expected_effect, justification_goal, justification_safety_argument,
+			dry_run_command, dry_run_output, attachments_json,
+			status, min_approvals, require_different_model,
+			execution_log_path, execution_exit_code, execution_duration_ms,
+			execution_executed_at, execution_executed_by_session_id, execution_executed_by_agent, execution_executed_by_model, execution_secrets_json,
+			rollback_path, rollback_rolled_back_at,
+			created_at, resolved_at, expires_at, approval_expires_at, version, risk_score,
+			import_source, import_external_id, imported_at
+		FROM requests
+		WHERE project_path = ? AND execution_executed_at IS NOT NULL
+		ORDER BY execution_executed_at DESC
+		LIMIT 1
+	`, projectPath)
+	return scanRequest(row)
+}
+
 // ComputeCommandHash computes the hash for a command spec.
 // Hash = sha256(raw + "\n" + cwd + "\n" + json(argv) + "\n" + shell_bool)
 func ComputeCommandHash(cmd CommandSpec) string {
@@ -559,6 +787,8 @@ func scanRequest(row *sql.Row) (*Request, error) {
 		dryRunCmd, dryRunOutput                             sql.NullString
 		execLogPath, execExitCode, execDurationMs           sql.NullString
 		execAt, execBySessionID, execByAgent, execByModel   sql.NullString
+		execSecretsJSON                                     sql.NullString
+		importSource, importExternalID, importedAt          sql.NullString
 		rollbackPath, rollbackAt                            sql.NullString
 		createdAt, resolvedAt, expiresAt, approvalExpiresAt sql.NullString
 		riskTier, status                                    string
@@ -575,9 +805,10 @@ func scanRequest(row *sql.Row) (*Request, error) {
 		&dryRunCmd, &dryRunOutput, &attachmentsJSON,
 		&status, &minApprovals, &requireDiffModel,
 		&execLogPath, &execExitCode, &execDurationMs,
-		&execAt, &execBySessionID, &execByAgent, &execByModel,
+		&execAt, &execBySessionID, &execByAgent, &execByModel, &execSecretsJSON,
 		&rollbackPath, &rollbackAt,
-		&createdAt, &resolvedAt, &expiresAt, &approvalExpiresAt,
+		&createdAt, &resolvedAt, &expiresAt, &approvalExpiresAt, &r.Version, &r.RiskScore,
+		&importSource, &importExternalID, &importedAt,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -647,6 +878,9 @@ func scanRequest(row *sql.Row) (*Request, error) {
 		if execByModel.Valid {
 			r.Execution.ExecutedByModel = execByModel.String
 		}
+		if execSecretsJSON.Valid {
+			_ = json.Unmarshal([]byte(execSecretsJSON.String), &r.Execution.SecretsFound)
+		}
 	}
 
 	// Rollback info
@@ -676,6 +910,7 @@ func scanRequest(row *sql.Row) (*Request, error) {
 		t, _ := time.Parse(time.RFC3339, approvalExpiresAt.String) //nolint:errcheck
 		r.ApprovalExpiresAt = &t
 	}
+	r.Import = parseRequestImport(importSource, importExternalID, importedAt)
 
 	return r, nil
 }
@@ -692,6 +927,8 @@ func scanRequests(rows *sql.Rows) ([]*Request, error) {
 			dryRunCmd, dryRunOutput                             sql.NullString
 			execLogPath, execExitCode, execDurationMs           sql.NullString
 			execAt, execBySessionID, execByAgent, execByModel   sql.NullString
+			execSecretsJSON                                     sql.NullString
+			importSource, importExternalID, importedAt          sql.NullString
 			rollbackPath, rollbackAt                            sql.NullString
 			createdAt, resolvedAt, expiresAt, approvalExpiresAt sql.NullString
 			riskTier, status                                    string
@@ -708,9 +945,10 @@ func scanRequests(rows *sql.Rows) ([]*Request, error) {
 			&dryRunCmd, &dryRunOutput, &attachmentsJSON,
 			&status, &minApprovals, &requireDiffModel,
 			&execLogPath, &execExitCode, &execDurationMs,
-			&execAt, &execBySessionID, &execByAgent, &execByModel,
+			&execAt, &execBySessionID, &execByAgent, &execByModel, &execSecretsJSON,
 			&rollbackPath, &rollbackAt,
-			&createdAt, &resolvedAt, &expiresAt, &approvalExpiresAt,
+			&createdAt, &resolvedAt, &expiresAt, &approvalExpiresAt, &r.Version, &r.RiskScore,
+			&importSource, &importExternalID, &importedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("scanning request row: %w", err)
@@ -777,6 +1015,9 @@ func scanRequests(rows *sql.Rows) ([]*Request, error) {
 			if execByModel.Valid {
 				r.Execution.ExecutedByModel = execByModel.String
 			}
+			if execSecretsJSON.Valid {
+				_ = json.Unmarshal([]byte(execSecretsJSON.String), &r.Execution.SecretsFound)
+			}
 		}
 
 		// Rollback info
@@ -806,6 +1047,7 @@ func scanRequests(rows *sql.Rows) ([]*Request, error) {
 			t, _ := time.Parse(time.RFC3339, approvalExpiresAt.String) //nolint:errcheck
 			r.ApprovalExpiresAt = &t
 		}
+		r.Import = parseRequestImport(importSource, importExternalID, importedAt)
 
 		requests = append(requests, r)
 	}
@@ -833,6 +1075,40 @@ func nullString(s string) sql.NullString {
 	return sql.NullString{String: s, Valid: true}
 }
 
+// GetRequestRedactPatterns returns the request's own redaction patterns,
+// or nil when it has none.
+func (db *DB) GetRequestRedactPatterns(requestID string) ([]string, error) {
+	var raw sql.NullString
+	err := db.QueryRow(`SELECT redact_patterns_json FROM requests WHERE id = ?`, requestID).Scan(&raw)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRequestNotFound
+		}
+		return nil, fmt.Errorf("getting request redact patterns: %w", err)
+	}
+	if !raw.Valid {
+		return nil, nil
+	}
+	var patterns []string
+	if err := json.Unmarshal([]byte(raw.String), &patterns); err != nil {
+		return nil, fmt.Errorf("decoding request redact patterns: %w", err)
+	}
+	return patterns, nil
+}
+
+// nullStringsJSON encodes a list such as the kinds of secret found in
+// execution output as a JSON array, or NULL when it is empty.
+func nullStringsJSON(list []string) sql.NullString {
+	if len(list) == 0 {
+		return sql.NullString{}
+	}
+	b, err := json.Marshal(list)
+	if err != nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: string(b), Valid: true}
+}
+
 func nullDryRunCommand(dr *DryRunResult) sql.NullString {
 	if dr == nil {
 		return sql.NullString{}
@@ -2,10 +2,15 @@
 package db
 
 import (
+	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/clock"
 )
 
 func TestCreateRequest(t *testing.T) {
@@ -172,10 +177,83 @@ func TestUpdateRequestStatusInvalid(t *testing.T) {
 
 	_, r := createTestRequest(t, db)
 
-	// Invalid transition: pending -> executed (skip approved)
-	err := db.UpdateRequestStatus(r.ID, StatusExecuted)
-	if err == nil {
-		t.Error("Expected error for invalid transition")
+	// The transition table lives in core/statemachine; the database only
+	// refuses no-op moves and moves out of terminal states.
+	err := db.UpdateRequestStatus(r.ID, StatusPending)
+	if !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Expected ErrInvalidTransition for pending -> pending, got %v", err)
+	}
+}
+
+func TestApplyStatusChange_StoresApprovalExpiry(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, r := createTestRequest(t, db)
+	at := time.Now().UTC().Truncate(time.Second)
+	expires := at.Add(10 * time.Minute)
+
+	if err := db.ApplyStatusChange(StatusChange{ID: r.ID, From: StatusPending, To: StatusApproved, At: at, ApprovalExpiresAt: &expires}); err != nil {
+		t.Fatalf("ApplyStatusChange(approved) failed: %v", err)
+	}
+	// A later change without an expiry keeps the stored one.
+	if err := db.ApplyStatusChange(StatusChange{ID: r.ID, From: StatusApproved, To: StatusCancelled, At: at}); err != nil {
+		t.Fatalf("ApplyStatusChange(cancelled) failed: %v", err)
+	}
+
+	got, err := db.GetRequest(r.ID)
+	if err != nil {
+		t.Fatalf("GetRequest failed: %v", err)
+	}
+	if got.ApprovalExpiresAt == nil || !got.ApprovalExpiresAt.Equal(expires) {
+		t.Errorf("ApprovalExpiresAt = %v, want %v", got.ApprovalExpiresAt, expires)
+	}
+	if got.ResolvedAt == nil || !got.ResolvedAt.Equal(at) {
+		t.Errorf("ResolvedAt = %v, want %v", got.ResolvedAt, at)
+	}
+
+	// From no longer matches the stored status.
+	err = db.ApplyStatusChange(StatusChange{ID: r.ID, From: StatusApproved, To: StatusExecuting, At: at})
+	if !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Expected ErrInvalidTransition for a stale From, got %v", err)
+	}
+}
+
+func TestRequestVersion(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, r := createTestRequest(t, db)
+	if r.Version != 1 {
+		t.Fatalf("new request version = %d, want 1", r.Version)
+	}
+
+	if err := db.ApplyStatusChange(StatusChange{ID: r.ID, From: StatusPending, To: StatusApproved, At: db.Now()}); err != nil {
+		t.Fatalf("ApplyStatusChange failed: %v", err)
+	}
+	got, err := db.GetRequest(r.ID)
+	if err != nil {
+		t.Fatalf("GetRequest failed: %v", err)
+	}
+	if got.Version != 2 {
+		t.Fatalf("version after status change = %d, want 2", got.Version)
+	}
+
+	err = db.Transaction(func(tx *sql.Tx) error { return db.BumpRequestVersionTx(tx, r.ID, 1) })
+	var conflict *VersionConflictError
+	if !errors.As(err, &conflict) || !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("expected a VersionConflictError for a stale version, got %v", err)
+	}
+	if conflict.Expected != 1 || conflict.Current != 2 {
+		t.Errorf("conflict = %+v, want expected 1, current 2", conflict)
+	}
+
+	if err := db.Transaction(func(tx *sql.Tx) error { return db.BumpRequestVersionTx(tx, r.ID, 2) }); err != nil {
+		t.Fatalf("BumpRequestVersionTx(current) failed: %v", err)
+	}
+	err = db.Transaction(func(tx *sql.Tx) error { return db.BumpRequestVersionTx(tx, "missing", 1) })
+	if !errors.Is(err, ErrRequestNotFound) {
+		t.Errorf("expected ErrRequestNotFound, got %v", err)
 	}
 }
 
@@ -220,6 +298,33 @@ func TestFindExpiredRequests(t *testing.T) {
 	}
 }
 
+func TestFindExpiredRequests_Clock(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	now := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	db.SetClock(clock.Func(func() time.Time { return now }))
+
+	_, r := createTestRequest(t, db)
+	if !r.CreatedAt.Equal(now) || !r.ExpiresAt.Equal(now.Add(DefaultRequestTimeout)) {
+		t.Fatalf("expected timestamps from the db clock, got created %v expires %v", r.CreatedAt, r.ExpiresAt)
+	}
+
+	now = now.Add(DefaultRequestTimeout - time.Second)
+	if expired, err := db.FindExpiredRequests(); err != nil || len(expired) != 0 {
+		t.Fatalf("expected nothing expired yet, got %d (err %v)", len(expired), err)
+	}
+	now = now.Add(2 * time.Second)
+	if expired, err := db.FindExpiredRequests(); err != nil || len(expired) != 1 {
+		t.Fatalf("expected the request to expire, got %d (err %v)", len(expired), err)
+	}
+
+	db.SetClock(nil)
+	if got := db.Now(); time.Since(got) > time.Minute {
+		t.Errorf("expected SetClock(nil) to restore the system clock, got %v", got)
+	}
+}
+
 func TestComputeCommandHash(t *testing.T) {
 	cmd := CommandSpec{
 		Raw:   "rm -rf /tmp/test",
@@ -402,6 +507,90 @@ func TestListAllRequests(t *testing.T) {
 	}
 }
 
+func TestListRequestsBySession(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	mine := &Session{AgentName: "BySession1", Program: "codex-cli", Model: "gpt-5", ProjectPath: "/test/project"}
+	other := &Session{AgentName: "BySession2", Program: "codex-cli", Model: "gpt-5", ProjectPath: "/test/project"}
+	for _, s := range []*Session{mine, other} {
+		if err := db.CreateSession(s); err != nil {
+			t.Fatalf("CreateSession failed: %v", err)
+		}
+	}
+	for i, sess := range []*Session{mine, other, mine} {
+		r := &Request{
+			ProjectPath:        sess.ProjectPath,
+			RequestorSessionID: sess.ID,
+			RequestorAgent:     sess.AgentName,
+			RequestorModel:     sess.Model,
+			RiskTier:           RiskTierDangerous,
+			MinApprovals:       1,
+			Command:            CommandSpec{Raw: fmt.Sprintf("rm -rf ./build%d", i), Cwd: sess.ProjectPath},
+			Justification:      Justification{Reason: "by session"},
+		}
+		if err := db.CreateRequest(r); err != nil {
+			t.Fatalf("CreateRequest failed: %v", err)
+		}
+	}
+
+	got, err := db.ListRequestsBySession(mine.ID)
+	if err != nil {
+		t.Fatalf("ListRequestsBySession failed: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(got))
+	}
+	for _, r := range got {
+		if r.RequestorSessionID != mine.ID {
+			t.Errorf("request %s belongs to %s", r.ID, r.RequestorSessionID)
+		}
+	}
+}
+
+func TestRequestStatusesBySession(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	sess := &Session{AgentName: "Statuses", Program: "codex-cli", Model: "gpt-5", ProjectPath: "/test/project"}
+	if err := db.CreateSession(sess); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	var reqs []*Request
+	for i := 0; i < 3; i++ {
+		r := &Request{
+			ProjectPath:        sess.ProjectPath,
+			RequestorSessionID: sess.ID,
+			RequestorAgent:     sess.AgentName,
+			RequestorModel:     sess.Model,
+			RiskTier:           RiskTierDangerous,
+			MinApprovals:       1,
+			Command:            CommandSpec{Raw: fmt.Sprintf("rm -rf ./build%d", i), Cwd: sess.ProjectPath},
+			Justification:      Justification{Reason: "statuses"},
+		}
+		if err := db.CreateRequest(r); err != nil {
+			t.Fatalf("CreateRequest failed: %v", err)
+		}
+		reqs = append(reqs, r)
+	}
+	open, old, recent := reqs[0], reqs[1], reqs[2]
+	now := time.Now().UTC()
+	for id, resolved := range map[string]time.Time{old.ID: now.Add(-2 * time.Hour), recent.ID: now.Add(-time.Minute)} {
+		if _, err := db.Exec(`UPDATE requests SET status = ?, resolved_at = ? WHERE id = ?`,
+			string(StatusRejected), resolved.Format(time.RFC3339), id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	statuses, err := db.RequestStatusesBySession(sess.ID, now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("RequestStatusesBySession failed: %v", err)
+	}
+	if len(statuses) != 2 || statuses[open.ID] != StatusPending || statuses[recent.ID] != StatusRejected {
+		t.Errorf("unexpected statuses: %v", statuses)
+	}
+}
+
 func TestListPendingRequestsAllProjects(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
@@ -534,6 +723,36 @@ func TestListPendingRequestsByProjects(t *testing.T) {
 	}
 }
 
+func TestLastExecutedRequest(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	if _, err := db.LastExecutedRequest("/test/project"); !errors.Is(err, ErrRequestNotFound) {
+		t.Fatalf("expected ErrRequestNotFound, got %v", err)
+	}
+
+	_, older := createTestRequest(t, db)
+	_, newer := createTestRequest(t, db)
+	createTestRequest(t, db) // never executed
+	for i, r := range []*Request{newer, older} {
+		at := time.Date(2024, 1, 2, 3-i, 0, 0, 0, time.UTC)
+		if err := db.UpdateRequestExecution(r.ID, &Execution{ExecutedAt: &at}); err != nil {
+			t.Fatalf("UpdateRequestExecution failed: %v", err)
+		}
+	}
+
+	last, err := db.LastExecutedRequest("/test/project")
+	if err != nil {
+		t.Fatalf("LastExecutedRequest failed: %v", err)
+	}
+	if last.ID != newer.ID {
+		t.Errorf("last executed = %s, want %s", last.ID, newer.ID)
+	}
+	if _, err := db.LastExecutedRequest("/other"); !errors.Is(err, ErrRequestNotFound) {
+		t.Errorf("other project: expected ErrRequestNotFound, got %v", err)
+	}
+}
+
 func TestUpdateRequestExecutionAndRollback(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
@@ -551,6 +770,7 @@ func TestUpdateRequestExecutionAndRollback(t *testing.T) {
 		LogPath:             "/tmp/slb.log",
 		ExitCode:            &exitCode,
 		DurationMs:          &durationMs,
+		SecretsFound:        []string{"aws_access_key_id", "github_token"},
 	}
 	if err := db.UpdateRequestExecution(r.ID, exec); err != nil {
 		t.Fatalf("UpdateRequestExecution failed: %v", err)
@@ -592,6 +812,9 @@ func TestUpdateRequestExecutionAndRollback(t *testing.T) {
 	if retrieved.Execution.ExecutedByModel != "gpt-5" {
 		t.Fatalf("ExecutedByModel=%q want %q", retrieved.Execution.ExecutedByModel, "gpt-5")
 	}
+	if got := retrieved.Execution.SecretsFound; len(got) != 2 || got[0] != "aws_access_key_id" || got[1] != "github_token" {
+		t.Fatalf("SecretsFound=%v want [aws_access_key_id github_token]", got)
+	}
 
 	if retrieved.Rollback == nil {
 		t.Fatalf("expected rollback info to be present")
@@ -873,7 +1096,7 @@ func TestUpdateRequestStatus_TimeoutEscalatedAndTerminalNoTransition(t *testing.
 }
 
 // createTestRequest creates a test session and request.
-func createTestRequest(t *testing.T, db *DB) (*Session, *Request) {
+func createTestRequest(t testing.TB, db *DB) (*Session, *Request) {
 	t.Helper()
 
 	// Each call creates a unique session
@@ -909,3 +1132,156 @@ func createTestRequest(t *testing.T, db *DB) (*Session, *Request) {
 
 	return sess, r
 }
+
+func TestListRequestsPage(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	sess := &Session{AgentName: "Pager", Program: "codex-cli", Model: "gpt-5", ProjectPath: "/test/project"}
+	if err := db.CreateSession(sess); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	other := &Session{AgentName: "Other", Program: "codex-cli", Model: "gpt-5", ProjectPath: "/test/other"}
+	if err := db.CreateSession(other); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	// Seven requests sharing a timestamp exercise the id tie-breaker.
+	base := time.Now().UTC().Truncate(time.Second)
+	for i := 0; i < 10; i++ {
+		r := &Request{
+			ProjectPath:        sess.ProjectPath,
+			RequestorSessionID: sess.ID,
+			RequestorAgent:     sess.AgentName,
+			RequestorModel:     sess.Model,
+			RiskTier:           RiskTierDangerous,
+			MinApprovals:       1,
+			Command:            CommandSpec{Raw: fmt.Sprintf("rm -rf ./build-%d", i), Cwd: sess.ProjectPath},
+			Justification:      Justification{Reason: "paging"},
+		}
+		if i%3 == 0 {
+			r.RiskTier = RiskTierCritical
+		}
+		if err := db.CreateRequest(r); err != nil {
+			t.Fatalf("CreateRequest failed: %v", err)
+		}
+		created := base
+		if i < 3 {
+			created = base.Add(-time.Duration(i+1) * time.Minute)
+		}
+		if _, err := db.Exec(`UPDATE requests SET created_at = ? WHERE id = ?`, created.Format(time.RFC3339), r.ID); err != nil {
+			t.Fatalf("update created_at failed: %v", err)
+		}
+	}
+	if err := db.CreateRequest(&Request{
+		ProjectPath: other.ProjectPath, RequestorSessionID: other.ID, RequestorAgent: other.AgentName,
+		RequestorModel: other.Model, RiskTier: RiskTierDangerous, MinApprovals: 1,
+		Command: CommandSpec{Raw: "rm -rf ./elsewhere", Cwd: other.ProjectPath},
+	}); err != nil {
+		t.Fatalf("CreateRequest failed: %v", err)
+	}
+
+	query := RequestPageQuery{ProjectPath: sess.ProjectPath, Limit: 3}
+	var all []*Request
+	for pages := 0; ; pages++ {
+		if pages > 10 {
+			t.Fatal("pagination did not terminate")
+		}
+		page, next, err := db.ListRequestsPage(query)
+		if err != nil {
+			t.Fatalf("ListRequestsPage failed: %v", err)
+		}
+		all = append(all, page...)
+		if next == nil {
+			break
+		}
+		// Round-trip the cursor through its token form.
+		query.After, err = ParseRequestCursor(next.String())
+		if err != nil {
+			t.Fatalf("ParseRequestCursor failed: %v", err)
+		}
+	}
+
+	if len(all) != 10 {
+		t.Fatalf("expected 10 requests across pages, got %d", len(all))
+	}
+	seen := make(map[string]bool)
+	for i, r := range all {
+		if seen[r.ID] {
+			t.Fatalf("request %s returned twice", r.ID)
+		}
+		seen[r.ID] = true
+		if i > 0 {
+			prev := all[i-1]
+			if r.CreatedAt.After(prev.CreatedAt) || (r.CreatedAt.Equal(prev.CreatedAt) && r.ID > prev.ID) {
+				t.Fatalf("requests out of order at %d", i)
+			}
+		}
+	}
+
+	count, err := db.CountRequests(RequestPageQuery{ProjectPath: sess.ProjectPath, Tier: RiskTierCritical})
+	if err != nil {
+		t.Fatalf("CountRequests failed: %v", err)
+	}
+	if count != 4 {
+		t.Errorf("expected 4 critical requests, got %d", count)
+	}
+	if count, _ := db.CountRequests(RequestPageQuery{}); count != 11 {
+		t.Errorf("expected 11 requests across projects, got %d", count)
+	}
+}
+
+func TestListRequestsPage_Filters(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, r1 := createTestRequest(t, db)
+	_, r2 := createTestRequest(t, db)
+	if err := db.UpdateRequestStatus(r2.ID, StatusApproved); err != nil {
+		t.Fatalf("UpdateRequestStatus failed: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO request_labels (request_id, key, value) VALUES (?, 'team', 'infra')`, r1.ID); err != nil {
+		t.Fatalf("insert label failed: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		query RequestPageQuery
+		want  []string
+	}{
+		{"status", RequestPageQuery{Status: StatusApproved}, []string{r2.ID}},
+		{"agent", RequestPageQuery{Agent: r1.RequestorAgent}, []string{r1.ID}},
+		{"label value", RequestPageQuery{Labels: []LabelFilter{{Key: "team", Value: "infra"}}}, []string{r1.ID}},
+		{"label any", RequestPageQuery{Labels: []LabelFilter{{Key: "team", AnyValue: true}}}, []string{r1.ID}},
+		{"label miss", RequestPageQuery{Labels: []LabelFilter{{Key: "team", Value: "web"}}}, nil},
+		{"search", RequestPageQuery{Search: "build", Status: StatusPending}, []string{r1.ID}},
+		{"since future", RequestPageQuery{Since: time.Now().Add(time.Hour)}, nil},
+		{"until past", RequestPageQuery{Until: time.Now().Add(-time.Hour)}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, next, err := db.ListRequestsPage(tt.query)
+			if err != nil {
+				t.Fatalf("ListRequestsPage failed: %v", err)
+			}
+			if next != nil {
+				t.Errorf("expected a single page, got cursor %+v", next)
+			}
+			var ids []string
+			for _, r := range got {
+				ids = append(ids, r.ID)
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRequestCursor_Invalid(t *testing.T) {
+	for _, token := range []string{"", "!!!", "bm8tc2VwYXJhdG9y", "eHx5"} {
+		if _, err := ParseRequestCursor(token); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("ParseRequestCursor(%q) error = %v, want ErrInvalidCursor", token, err)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ReviewTiming is when a request was created, first reviewed and resolved,
+// for review SLA tracking.
+type ReviewTiming struct {
+	RequestID string        `json:"request_id"`
+	RiskTier  RiskTier      `json:"risk_tier"`
+	Status    RequestStatus `json:"status"`
+	CreatedAt time.Time     `json:"created_at"`
+	// FirstReviewAt is the earliest approval or rejection, nil if none.
+	FirstReviewAt *time.Time `json:"first_review_at,omitempty"`
+	ResolvedAt    *time.Time `json:"resolved_at,omitempty"`
+}
+
+// FirstReviewTimes returns the earliest review time of each of the given
+// requests that has been reviewed.
+func (db *DB) FirstReviewTimes(requestIDs []string) (map[string]time.Time, error) {
+	first := make(map[string]time.Time)
+	if len(requestIDs) == 0 {
+		return first, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(requestIDs)), ",")
+	args := make([]any, len(requestIDs))
+	for i, id := range requestIDs {
+		args[i] = id
+	}
+
+	rows, err := db.Query(`
+		SELECT request_id, MIN(created_at) FROM reviews
+		WHERE request_id IN (`+placeholders+`)
+		GROUP BY request_id
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing first reviews: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, at string
+		if err := rows.Scan(&id, &at); err != nil {
+			return nil, fmt.Errorf("scanning first review: %w", err)
+		}
+		if t, err := time.Parse(time.RFC3339, at); err == nil {
+			first[id] = t
+		}
+	}
+	return first, rows.Err()
+}
+
+// ListReviewTimings returns the review timing of every request created at
+// or after since, oldest first.
+func (db *DB) ListReviewTimings(since time.Time) ([]*ReviewTiming, error) {
+	rows, err := db.Query(`
+		SELECT r.id, r.risk_tier, r.status, r.created_at,
+			(SELECT MIN(rv.created_at) FROM reviews rv WHERE rv.request_id = r.id),
+			r.resolved_at
+		FROM requests r
+		WHERE r.created_at >= ?
+		ORDER BY r.created_at, r.id
+	`, since.UTC().Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("listing review timings: %w", err)
+	}
+	defer rows.Close()
+
+	var timings []*ReviewTiming
+	for rows.Next() {
+		t := &ReviewTiming{}
+		var tier, status, created string
+		var firstReview, resolved sql.NullString
+		if err := rows.Scan(&t.RequestID, &tier, &status, &created, &firstReview, &resolved); err != nil {
+			return nil, fmt.Errorf("scanning review timing: %w", err)
+		}
+		t.RiskTier = RiskTier(tier)
+		t.Status = RequestStatus(status)
+		t.CreatedAt, _ = time.Parse(time.RFC3339, created)
+		t.FirstReviewAt = parseOptionalTime(firstReview)
+		t.ResolvedAt = parseOptionalTime(resolved)
+		timings = append(timings, t)
+	}
+	return timings, rows.Err()
+}
+
+func parseOptionalTime(s sql.NullString) *time.Time {
+	if !s.Valid {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, s.String)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
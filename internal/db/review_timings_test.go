@@ -0,0 +1,64 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/clock"
+)
+
+func TestReviewTimings(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	db.SetClock(clock.Func(func() time.Time { return now }))
+
+	_, reviewed := createTestRequest(t, db)
+	_, unreviewed := createTestRequest(t, db)
+	reviewer, _ := createTestRequest(t, db)
+
+	review := func(at time.Time) {
+		t.Helper()
+		if err := db.CreateReview(&Review{
+			ID:                "rv-" + at.Format("150405"),
+			RequestID:         reviewed.ID,
+			ReviewerSessionID: reviewer.ID,
+			ReviewerAgent:     reviewer.AgentName,
+			ReviewerModel:     reviewer.Model,
+			Decision:          DecisionApprove,
+			CreatedAt:         at,
+		}); err != nil {
+			t.Fatalf("CreateReview failed: %v", err)
+		}
+	}
+	review(now.Add(7 * time.Minute))
+
+	first, err := db.FirstReviewTimes([]string{reviewed.ID, unreviewed.ID})
+	if err != nil {
+		t.Fatalf("FirstReviewTimes failed: %v", err)
+	}
+	if len(first) != 1 || !first[reviewed.ID].Equal(now.Add(7*time.Minute)) {
+		t.Fatalf("unexpected first reviews: %v", first)
+	}
+
+	timings, err := db.ListReviewTimings(now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("ListReviewTimings failed: %v", err)
+	}
+	byID := make(map[string]*ReviewTiming)
+	for _, rt := range timings {
+		byID[rt.RequestID] = rt
+	}
+	got := byID[reviewed.ID]
+	if got == nil || got.RiskTier != RiskTierDangerous || got.FirstReviewAt == nil || !got.FirstReviewAt.Equal(now.Add(7*time.Minute)) {
+		t.Fatalf("unexpected timing for reviewed request: %+v", got)
+	}
+	if u := byID[unreviewed.ID]; u == nil || u.FirstReviewAt != nil || u.ResolvedAt != nil {
+		t.Fatalf("unexpected timing for unreviewed request: %+v", u)
+	}
+
+	if timings, _ := db.ListReviewTimings(now.Add(time.Hour)); len(timings) != 0 {
+		t.Errorf("expected no timings after since, got %d", len(timings))
+	}
+}
@@ -27,7 +27,7 @@ func (db *DB) CreateReviewTx(tx *sql.Tx, r *Review) error {
 	if r.ID == "" {
 		r.ID = uuid.New().String()
 	}
-	now := time.Now().UTC()
+	now := db.Now()
 	if r.CreatedAt.IsZero() {
 		r.CreatedAt = now
 	}
@@ -41,12 +41,13 @@ func (db *DB) CreateReviewTx(tx *sql.Tx, r *Review) error {
 		INSERT INTO reviews (
 			id, request_id, reviewer_session_id, reviewer_agent, reviewer_model,
 			decision, signature, signature_timestamp,
-			responses_json, comments, created_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			responses_json, comments, auth_method, authenticated_at, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`,
 		r.ID, r.RequestID, r.ReviewerSessionID, r.ReviewerAgent, r.ReviewerModel,
 		string(r.Decision), r.Signature, r.SignatureTimestamp.Format(time.RFC3339),
-		nullString(string(respJSON)), nullString(r.Comments), r.CreatedAt.Format(time.RFC3339),
+		nullString(string(respJSON)), nullString(r.Comments),
+		nullString(r.AuthMethod), formatTimePtr(r.AuthenticatedAt), r.CreatedAt.Format(time.RFC3339),
 	)
 	if err != nil {
 		if isUniqueConstraintError(err) {
@@ -54,7 +55,7 @@ func (db *DB) CreateReviewTx(tx *sql.Tx, r *Review) error {
 		}
 		return fmt.Errorf("creating review: %w", err)
 	}
-	return nil
+	return insertRequestEvent(tx, reviewEvent(r))
 }
 
 // CreateReview inserts a review, generating ID and timestamps if missing.
@@ -62,7 +63,7 @@ func (db *DB) CreateReview(r *Review) error {
 	if r.ID == "" {
 		r.ID = uuid.New().String()
 	}
-	now := time.Now().UTC()
+	now := db.Now()
 	if r.CreatedAt.IsZero() {
 		r.CreatedAt = now
 	}
@@ -77,33 +78,55 @@ func (db *DB) CreateReview(r *Review) error {
 		return ErrReviewExists
 	}
 
+	if err := insertReview(db, r); err != nil {
+		if isUniqueConstraintError(err) {
+			return ErrReviewExists
+		}
+		return fmt.Errorf("creating review: %w", err)
+	}
+	return insertRequestEvent(db, reviewEvent(r))
+}
+
+// insertReview inserts a review row.
+func insertReview(x execer, r *Review) error {
 	respJSON, _ := json.Marshal(r.Responses)
 
-	_, err := db.Exec(`
+	_, err := x.Exec(`
 		INSERT INTO reviews (
 			id, request_id, reviewer_session_id, reviewer_agent, reviewer_model,
 			decision, signature, signature_timestamp,
-			responses_json, comments, created_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			responses_json, comments, auth_method, authenticated_at, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`,
 		r.ID, r.RequestID, r.ReviewerSessionID, r.ReviewerAgent, r.ReviewerModel,
 		string(r.Decision), r.Signature, r.SignatureTimestamp.Format(time.RFC3339),
-		nullString(string(respJSON)), nullString(r.Comments), r.CreatedAt.Format(time.RFC3339),
+		nullString(string(respJSON)), nullString(r.Comments),
+		nullString(r.AuthMethod), formatTimePtr(r.AuthenticatedAt), r.CreatedAt.Format(time.RFC3339),
 	)
-	if err != nil {
-		if isUniqueConstraintError(err) {
-			return ErrReviewExists
-		}
-		return fmt.Errorf("creating review: %w", err)
+	return err
+}
+
+// reviewEvent is the timeline event for a review.
+func reviewEvent(r *Review) *RequestEvent {
+	details := string(r.Decision)
+	if r.Comments != "" {
+		details += ": " + r.Comments
+	}
+	return &RequestEvent{
+		RequestID: r.RequestID,
+		Type:      RequestEventReviewed,
+		Actor:     r.ReviewerAgent,
+		Details:   details,
+		CreatedAt: r.CreatedAt,
 	}
-	return nil
 }
 
 // GetReview retrieves a review by ID.
 func (db *DB) GetReview(id string) (*Review, error) {
 	row := db.QueryRow(`
 		SELECT id, request_id, reviewer_session_id, reviewer_agent, reviewer_model,
-		       decision, signature, signature_timestamp, responses_json, comments, created_at
+		       decision, signature, signature_timestamp, responses_json, comments,
+		       auth_method, authenticated_at, created_at
 		FROM reviews WHERE id = ?
 	`, id)
 	return scanReviewRow(row)
@@ -113,7 +136,24 @@ func (db *DB) GetReview(id string) (*Review, error) {
 func (db *DB) ListReviewsForRequest(requestID string) ([]*Review, error) {
 	rows, err := db.Query(`
 		SELECT id, request_id, reviewer_session_id, reviewer_agent, reviewer_model,
-		       decision, signature, signature_timestamp, responses_json, comments, created_at
+		       decision, signature, signature_timestamp, responses_json, comments,
+		       auth_method, authenticated_at, created_at
+		FROM reviews WHERE request_id = ?
+		ORDER BY created_at ASC
+	`, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("listing reviews: %w", err)
+	}
+	defer rows.Close()
+	return scanReviewList(rows)
+}
+
+// ListReviewsForRequestTx returns all reviews for a request within a transaction.
+func (db *DB) ListReviewsForRequestTx(tx *sql.Tx, requestID string) ([]*Review, error) {
+	rows, err := tx.Query(`
+		SELECT id, request_id, reviewer_session_id, reviewer_agent, reviewer_model,
+		       decision, signature, signature_timestamp, responses_json, comments,
+		       auth_method, authenticated_at, created_at
 		FROM reviews WHERE request_id = ?
 		ORDER BY created_at ASC
 	`, requestID)
@@ -196,10 +236,10 @@ func scanReviewRow(row *sql.Row) (*Review, error) {
 	var decision string
 	var sigTs, created string
 	var responsesJSON sql.NullString
-	var comments sql.NullString
+	var comments, authMethod, authenticatedAt sql.NullString
 
 	err := row.Scan(&r.ID, &r.RequestID, &r.ReviewerSessionID, &r.ReviewerAgent, &r.ReviewerModel,
-		&decision, &r.Signature, &sigTs, &responsesJSON, &comments, &created)
+		&decision, &r.Signature, &sigTs, &responsesJSON, &comments, &authMethod, &authenticatedAt, &created)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrReviewNotFound
@@ -217,6 +257,11 @@ func scanReviewRow(row *sql.Row) (*Review, error) {
 	if comments.Valid {
 		r.Comments = comments.String
 	}
+	r.AuthMethod = authMethod.String
+	if authenticatedAt.Valid {
+		t, _ := time.Parse(time.RFC3339, authenticatedAt.String) //nolint:errcheck
+		r.AuthenticatedAt = &t
+	}
 
 	return r, nil
 }
@@ -228,10 +273,10 @@ func scanReviewList(rows *sql.Rows) ([]*Review, error) {
 		var decision string
 		var sigTs, created string
 		var responsesJSON sql.NullString
-		var comments sql.NullString
+		var comments, authMethod, authenticatedAt sql.NullString
 
 		if err := rows.Scan(&r.ID, &r.RequestID, &r.ReviewerSessionID, &r.ReviewerAgent, &r.ReviewerModel,
-			&decision, &r.Signature, &sigTs, &responsesJSON, &comments, &created); err != nil {
+			&decision, &r.Signature, &sigTs, &responsesJSON, &comments, &authMethod, &authenticatedAt, &created); err != nil {
 			return nil, fmt.Errorf("scanning reviews: %w", err)
 		}
 
@@ -244,6 +289,11 @@ func scanReviewList(rows *sql.Rows) ([]*Review, error) {
 		if comments.Valid {
 			r.Comments = comments.String
 		}
+		r.AuthMethod = authMethod.String
+		if authenticatedAt.Valid {
+			t, _ := time.Parse(time.RFC3339, authenticatedAt.String) //nolint:errcheck
+			r.AuthenticatedAt = &t
+		}
 
 		list = append(list, r)
 	}
@@ -312,52 +362,3 @@ func (db *DB) CheckRequestApprovalStatus(requestID string) (approved bool, rejec
 
 	return false, false, nil
 }
-
-// CreateReviewWithValidation creates a review with full validation:
-// - Checks the request exists and is pending
-// - Verifies the signature
-// - Prevents self-review
-// - Updates request status if approval threshold met
-func (db *DB) CreateReviewWithValidation(r *Review, sessionKey string) error {
-	// Get the request
-	req, err := db.GetRequest(r.RequestID)
-	if err != nil {
-		return err
-	}
-
-	// Verify request is pending
-	if req.Status != StatusPending {
-		return fmt.Errorf("request is not pending (status: %s)", req.Status)
-	}
-
-	// Prevent self-review
-	if r.ReviewerSessionID == req.RequestorSessionID {
-		return ErrSelfReview
-	}
-
-	// Verify signature
-	if !VerifyReviewSignature(sessionKey, r.RequestID, r.Decision, r.SignatureTimestamp, r.Signature) {
-		return ErrInvalidSignature
-	}
-
-	// Create the review
-	if err := db.CreateReview(r); err != nil {
-		return err
-	}
-
-	// Check if request should be approved or rejected
-	approved, rejected, err := db.CheckRequestApprovalStatus(r.RequestID)
-	if err != nil {
-		return err
-	}
-
-	if rejected {
-		return db.UpdateRequestStatus(r.RequestID, StatusRejected)
-	}
-
-	if approved {
-		return db.UpdateRequestStatus(r.RequestID, StatusApproved)
-	}
-
-	return nil
-}
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrRequestScriptNotFound indicates a request was not submitted as a script.
+var ErrRequestScriptNotFound = errors.New("request script not found")
+
+// RequestScript marks a request whose command is a multi-line script, run
+// from a temp file by Interpreter rather than as a single command line.
+type RequestScript struct {
+	// RequestID is the request this script belongs to.
+	RequestID string `json:"request_id"`
+	// Interpreter runs the script, e.g. bash or /usr/bin/env bash.
+	Interpreter string `json:"interpreter"`
+	// Source is where the script was read from (a file path, or - for
+	// stdin), when known.
+	Source string `json:"source,omitempty"`
+	// SHA256 is the hex digest of the script content.
+	SHA256 string `json:"sha256"`
+	// CreatedAt is when the script was recorded.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SetRequestScript records (or replaces) the script for a request.
+func (db *DB) SetRequestScript(s *RequestScript) error {
+	if s.CreatedAt.IsZero() {
+		s.CreatedAt = db.Now()
+	}
+	return insertRequestScript(db, s)
+}
+
+func insertRequestScript(x execer, s *RequestScript) error {
+	if s.RequestID == "" || s.Interpreter == "" || s.SHA256 == "" {
+		return fmt.Errorf("request script requires request id, interpreter and sha256")
+	}
+	_, err := x.Exec(`
+		INSERT OR REPLACE INTO request_scripts (request_id, interpreter, source, sha256, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, s.RequestID, s.Interpreter, nullString(s.Source), s.SHA256, s.CreatedAt.Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("recording request script: %w", err)
+	}
+	return nil
+}
+
+// GetRequestScript returns the script recorded for a request.
+func (db *DB) GetRequestScript(requestID string) (*RequestScript, error) {
+	s := &RequestScript{}
+	var source sql.NullString
+	var created string
+	err := db.QueryRow(`
+		SELECT request_id, interpreter, source, sha256, created_at
+		FROM request_scripts
+		WHERE request_id = ?
+	`, requestID).Scan(&s.RequestID, &s.Interpreter, &source, &s.SHA256, &created)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRequestScriptNotFound
+		}
+		return nil, fmt.Errorf("getting request script: %w", err)
+	}
+	s.Source = source.String
+	s.CreatedAt, _ = time.Parse(time.RFC3339, created)
+	return s, nil
+}
@@ -0,0 +1,32 @@
+package db
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRequestScript(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, req := createTestRequest(t, db)
+
+	if _, err := db.GetRequestScript(req.ID); !errors.Is(err, ErrRequestScriptNotFound) {
+		t.Fatalf("expected ErrRequestScriptNotFound, got %v", err)
+	}
+	if err := db.SetRequestScript(&RequestScript{RequestID: req.ID, Interpreter: "bash"}); err == nil {
+		t.Fatal("expected error without sha256")
+	}
+
+	s := &RequestScript{RequestID: req.ID, Interpreter: "/usr/bin/env bash", Source: "deploy.sh", SHA256: "abc123"}
+	if err := db.SetRequestScript(s); err != nil {
+		t.Fatalf("SetRequestScript failed: %v", err)
+	}
+	got, err := db.GetRequestScript(req.ID)
+	if err != nil {
+		t.Fatalf("GetRequestScript failed: %v", err)
+	}
+	if got.Interpreter != "/usr/bin/env bash" || got.Source != "deploy.sh" || got.SHA256 != "abc123" || got.CreatedAt.IsZero() {
+		t.Fatalf("unexpected script: %+v", got)
+	}
+}
@@ -3,6 +3,7 @@ package db
 
 import (
 	"crypto/rand"
+	"crypto/subtle"
 	"database/sql"
 	"encoding/hex"
 	"errors"
@@ -45,7 +46,7 @@ func (db *DB) CreateSession(s *Session) error {
 	}
 
 	// Set timestamps
-	now := time.Now().UTC()
+	now := db.Now()
 	s.StartedAt = now
 	s.LastActiveAt = now
 	s.EndedAt = nil
@@ -97,6 +98,16 @@ func (db *DB) GetSession(id string) (*Session, error) {
 	return scanSession(row)
 }
 
+// GetSessionTx retrieves a session by ID within a transaction.
+func (db *DB) GetSessionTx(tx *sql.Tx, id string) (*Session, error) {
+	row := tx.QueryRow(`
+		SELECT id, agent_name, program, model, project_path, session_key, started_at, last_active_at, ended_at
+		FROM sessions WHERE id = ?
+	`, id)
+
+	return scanSession(row)
+}
+
 // GetActiveSession retrieves the active session for an agent and project.
 // Returns ErrSessionNotFound if no active session exists.
 func (db *DB) GetActiveSession(agentName, projectPath string) (*Session, error) {
@@ -141,9 +152,52 @@ func (db *DB) ListAllActiveSessions() ([]*Session, error) {
 	return scanSessions(rows)
 }
 
+// FindActiveSessionByKey returns the active session whose key is key, or
+// ErrSessionNotFound. Keys are compared in constant time against every
+// active session rather than matched in SQL, so the time taken does not
+// depend on how much of a guessed key is right.
+func (db *DB) FindActiveSessionByKey(key string) (*Session, error) {
+	sessions, err := db.ListAllActiveSessions()
+	if err != nil {
+		return nil, err
+	}
+	var found *Session
+	for _, s := range sessions {
+		if SessionKeyMatches(key, s.SessionKey) && found == nil {
+			found = s
+		}
+	}
+	if found == nil {
+		return nil, ErrSessionNotFound
+	}
+	return found, nil
+}
+
+// SessionKeyMatches compares a presented session key with the stored one
+// in constant time.
+func SessionKeyMatches(given, want string) bool {
+	return subtle.ConstantTimeCompare([]byte(given), []byte(want)) == 1
+}
+
+// CountActiveSessions returns how many sessions are active in a project and
+// how many are active for an agent name across all projects.
+func (db *DB) CountActiveSessions(projectPath, agentName string) (inProject, forAgent int, err error) {
+	err = db.QueryRow(`
+		SELECT
+			COALESCE(SUM(CASE WHEN project_path = ? THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN agent_name = ? THEN 1 ELSE 0 END), 0)
+		FROM sessions
+		WHERE ended_at IS NULL
+	`, projectPath, agentName).Scan(&inProject, &forAgent)
+	if err != nil {
+		return 0, 0, fmt.Errorf("counting active sessions: %w", err)
+	}
+	return inProject, forAgent, nil
+}
+
 // UpdateSessionHeartbeat updates the last_active_at timestamp for a session.
 func (db *DB) UpdateSessionHeartbeat(id string) error {
-	now := time.Now().UTC().Format(time.RFC3339)
+	now := db.Now().Format(time.RFC3339)
 	result, err := db.Exec(`
 		UPDATE sessions SET last_active_at = ? WHERE id = ? AND ended_at IS NULL
 	`, now, id)
@@ -164,7 +218,7 @@ func (db *DB) UpdateSessionHeartbeat(id string) error {
 
 // EndSession marks a session as ended by setting ended_at.
 func (db *DB) EndSession(id string) error {
-	now := time.Now().UTC().Format(time.RFC3339)
+	now := db.Now().Format(time.RFC3339)
 	result, err := db.Exec(`
 		UPDATE sessions SET ended_at = ? WHERE id = ? AND ended_at IS NULL
 	`, now, id)
@@ -236,7 +290,7 @@ func (db *DB) ResetSessionRateLimits(id string, now time.Time) (time.Time, error
 
 // FindStaleSessions returns active sessions that haven't been active within the threshold.
 func (db *DB) FindStaleSessions(threshold time.Duration) ([]*Session, error) {
-	cutoff := time.Now().UTC().Add(-threshold).Format(time.RFC3339)
+	cutoff := db.Now().Add(-threshold).Format(time.RFC3339)
 	rows, err := db.Query(`
 		SELECT id, agent_name, program, model, project_path, session_key, started_at, last_active_at, ended_at
 		FROM sessions
@@ -137,6 +137,35 @@ func TestGetSessionNotFound(t *testing.T) {
 	}
 }
 
+func TestFindActiveSessionByKey(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	a := &Session{AgentName: "GreenLake", ProjectPath: "/test/project"}
+	b := &Session{AgentName: "BlueRiver", ProjectPath: "/test/other"}
+	for _, s := range []*Session{a, b} {
+		if err := db.CreateSession(s); err != nil {
+			t.Fatalf("CreateSession failed: %v", err)
+		}
+	}
+
+	found, err := db.FindActiveSessionByKey(b.SessionKey)
+	if err != nil || found.ID != b.ID {
+		t.Fatalf("FindActiveSessionByKey = %+v, %v; want %s", found, err, b.ID)
+	}
+	for _, key := range []string{"", a.SessionKey[:10], a.SessionKey + "0"} {
+		if _, err := db.FindActiveSessionByKey(key); err != ErrSessionNotFound {
+			t.Errorf("FindActiveSessionByKey(%q) = %v, want ErrSessionNotFound", key, err)
+		}
+	}
+	if err := db.EndSession(a.ID); err != nil {
+		t.Fatalf("EndSession failed: %v", err)
+	}
+	if _, err := db.FindActiveSessionByKey(a.SessionKey); err != ErrSessionNotFound {
+		t.Errorf("ended session: got %v, want ErrSessionNotFound", err)
+	}
+}
+
 func TestGetActiveSession(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
@@ -253,6 +282,38 @@ func TestListAllActiveSessions(t *testing.T) {
 	}
 }
 
+func TestCountActiveSessions(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	for _, s := range []*Session{
+		{AgentName: "Agent1", ProjectPath: "/test/project1"},
+		{AgentName: "Agent1", ProjectPath: "/test/project2"},
+		{AgentName: "Agent2", ProjectPath: "/test/project1"},
+		{AgentName: "Agent3", ProjectPath: "/test/project1"},
+	} {
+		if err := db.CreateSession(s); err != nil {
+			t.Fatalf("CreateSession failed: %v", err)
+		}
+		if s.AgentName == "Agent3" {
+			if err := db.EndSession(s.ID); err != nil {
+				t.Fatalf("EndSession failed: %v", err)
+			}
+		}
+	}
+
+	inProject, forAgent, err := db.CountActiveSessions("/test/project1", "Agent1")
+	if err != nil {
+		t.Fatalf("CountActiveSessions failed: %v", err)
+	}
+	if inProject != 2 || forAgent != 2 {
+		t.Errorf("counts = %d in project, %d for agent; want 2, 2", inProject, forAgent)
+	}
+	if inProject, forAgent, _ := db.CountActiveSessions("/nowhere", "Nobody"); inProject != 0 || forAgent != 0 {
+		t.Errorf("counts = %d, %d; want 0, 0", inProject, forAgent)
+	}
+}
+
 func TestUpdateSessionHeartbeat(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
@@ -688,7 +749,7 @@ func TestScanSessions_BadRows(t *testing.T) {
 }
 
 // setupTestDB creates a temporary database for testing.
-func setupTestDB(t *testing.T) *DB {
+func setupTestDB(t testing.TB) *DB {
 	t.Helper()
 
 	tmpDir := t.TempDir()
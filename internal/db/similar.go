@@ -0,0 +1,85 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// SimilarRequest is an earlier request in the same project that ran the
+// same command or matched the same pattern as another request.
+type SimilarRequest struct {
+	ID string `json:"id"`
+	// Command is the command as reviewers saw it (redacted if sensitive).
+	Command string        `json:"command"`
+	Status  RequestStatus `json:"status"`
+	// SameCommand is set when the command hash matches; otherwise only the
+	// matched pattern does.
+	SameCommand bool       `json:"same_command"`
+	ExitCode    *int       `json:"exit_code,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ResolvedAt  *time.Time `json:"resolved_at,omitempty"`
+	// CausedProblems is the latest recorded outcome, nil when none was
+	// recorded.
+	CausedProblems *bool `json:"caused_problems,omitempty"`
+}
+
+// ListSimilarRequests returns up to limit other requests in the request's
+// project that share its command hash or matched pattern, exact command
+// matches first and newest first within each group.
+func (db *DB) ListSimilarRequests(requestID string, limit int) ([]*SimilarRequest, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	rows, err := db.Query(`
+		SELECT r.id,
+			CASE WHEN r.command_contains_sensitive = 1 AND COALESCE(r.command_display_redacted, '') != ''
+				THEN r.command_display_redacted ELSE r.command_raw END,
+			r.status, r.command_hash = t.command_hash AS same_command,
+			r.execution_exit_code, r.created_at, r.resolved_at,
+			(SELECT o.caused_problems FROM execution_outcomes o
+				WHERE o.request_id = r.id ORDER BY o.created_at DESC, o.id DESC LIMIT 1)
+		FROM requests t
+		JOIN requests r ON r.project_path = t.project_path AND r.id != t.id
+		LEFT JOIN request_patterns tp ON tp.request_id = t.id
+		LEFT JOIN request_patterns rp ON rp.request_id = r.id
+		WHERE t.id = ?
+			AND (r.command_hash = t.command_hash OR (tp.pattern IS NOT NULL AND rp.pattern = tp.pattern))
+		ORDER BY same_command DESC, r.created_at DESC, r.id
+		LIMIT ?
+	`, requestID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("listing similar requests: %w", err)
+	}
+	defer rows.Close()
+
+	var similar []*SimilarRequest
+	for rows.Next() {
+		s := &SimilarRequest{}
+		var (
+			status, created      string
+			resolved             sql.NullString
+			exitCode, causedProb sql.NullInt64
+		)
+		if err := rows.Scan(&s.ID, &s.Command, &status, &s.SameCommand, &exitCode, &created, &resolved, &causedProb); err != nil {
+			return nil, fmt.Errorf("scanning similar request: %w", err)
+		}
+		s.Status = RequestStatus(status)
+		s.CreatedAt, _ = time.Parse(time.RFC3339, created)
+		if resolved.Valid {
+			if t, err := time.Parse(time.RFC3339, resolved.String); err == nil {
+				s.ResolvedAt = &t
+			}
+		}
+		if exitCode.Valid {
+			code := int(exitCode.Int64)
+			s.ExitCode = &code
+		}
+		if causedProb.Valid {
+			caused := causedProb.Int64 != 0
+			s.CausedProblems = &caused
+		}
+		similar = append(similar, s)
+	}
+	return similar, rows.Err()
+}
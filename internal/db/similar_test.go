@@ -0,0 +1,91 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/clock"
+)
+
+func TestListSimilarRequests(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	db.SetClock(clock.Func(func() time.Time { return now }))
+	next := func() { now = now.Add(time.Minute) }
+
+	sess, target := createTestRequest(t, db)
+	next()
+	_, older := createTestRequest(t, db)
+	next()
+	_, newer := createTestRequest(t, db)
+	next()
+
+	samePattern := &Request{
+		ProjectPath:        "/test/project",
+		RequestorSessionID: sess.ID,
+		RequestorAgent:     sess.AgentName,
+		RiskTier:           RiskTierDangerous,
+		MinApprovals:       1,
+		Command:            CommandSpec{Raw: "rm -rf ./dist", Cwd: "/test/project"},
+		Justification:      Justification{Reason: "clean dist"},
+	}
+	if err := db.CreateRequest(samePattern); err != nil {
+		t.Fatalf("CreateRequest failed: %v", err)
+	}
+	for _, id := range []string{target.ID, samePattern.ID} {
+		if err := db.SetRequestPattern(&RequestPattern{RequestID: id, Tier: RiskTierDangerous, Pattern: `^rm\s+-rf`}); err != nil {
+			t.Fatalf("SetRequestPattern failed: %v", err)
+		}
+	}
+
+	exitCode := 0
+	if err := db.UpdateRequestExecution(older.ID, &Execution{ExitCode: &exitCode}); err != nil {
+		t.Fatalf("UpdateRequestExecution failed: %v", err)
+	}
+	if err := db.UpdateRequestStatus(older.ID, StatusExecuted); err != nil {
+		t.Fatalf("UpdateRequestStatus failed: %v", err)
+	}
+	if _, err := db.RecordOutcome(older.ID, true, "deleted cached assets", nil, ""); err != nil {
+		t.Fatalf("RecordOutcome failed: %v", err)
+	}
+
+	similar, err := db.ListSimilarRequests(target.ID, 10)
+	if err != nil {
+		t.Fatalf("ListSimilarRequests failed: %v", err)
+	}
+	var ids []string
+	for _, s := range similar {
+		if s.ID == target.ID {
+			t.Fatal("the request itself must not be listed")
+		}
+		ids = append(ids, s.ID)
+	}
+	if len(similar) != 3 {
+		t.Fatalf("expected 3 similar requests, got %d: %v", len(similar), ids)
+	}
+	if ids[0] != newer.ID || ids[1] != older.ID || ids[2] != samePattern.ID {
+		t.Fatalf("unexpected order: %v", ids)
+	}
+	if !similar[0].SameCommand || similar[2].SameCommand {
+		t.Fatalf("unexpected same_command flags: %+v %+v", similar[0], similar[2])
+	}
+
+	got := similar[1]
+	if got.Status != StatusExecuted || got.ExitCode == nil || *got.ExitCode != 0 ||
+		got.CausedProblems == nil || !*got.CausedProblems || got.ResolvedAt == nil {
+		t.Fatalf("unexpected executed request: %+v", got)
+	}
+	if similar[0].CausedProblems != nil || similar[0].ExitCode != nil {
+		t.Fatalf("expected no outcome for the newer request: %+v", similar[1])
+	}
+
+	limited, err := db.ListSimilarRequests(target.ID, 1)
+	if err != nil {
+		t.Fatalf("ListSimilarRequests failed: %v", err)
+	}
+	if len(limited) != 1 {
+		t.Fatalf("expected limit to apply, got %d", len(limited))
+	}
+}
@@ -0,0 +1,76 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// TaskLabel is the label that groups related requests under a task, such
+// as the ticket an agent's multi-command migration belongs to
+// (task=OPS-123).
+const TaskLabel = "task"
+
+// TaskStatusCounts is a task and how many of its requests are in each
+// status.
+type TaskStatusCounts struct {
+	Task   string                `json:"task"`
+	Counts map[RequestStatus]int `json:"counts"`
+	// LastAt is when the task's newest request was created.
+	LastAt time.Time `json:"last_at"`
+}
+
+// ListTasks returns the tasks requests are labeled with, most recently
+// active first. An empty projectPath lists tasks in all projects.
+func (db *DB) ListTasks(projectPath string) ([]*TaskStatusCounts, error) {
+	query := `
+		SELECT l.value, r.status, COUNT(*), MAX(r.created_at)
+		FROM request_labels l
+		JOIN requests r ON r.id = l.request_id
+		WHERE l.key = ?`
+	args := []any{TaskLabel}
+	if projectPath != "" {
+		query += ` AND r.project_path = ?`
+		args = append(args, projectPath)
+	}
+	query += ` GROUP BY l.value, r.status`
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing tasks: %w", err)
+	}
+	defer rows.Close()
+
+	byTask := make(map[string]*TaskStatusCounts)
+	for rows.Next() {
+		var task, status, lastAt string
+		var count int
+		if err := rows.Scan(&task, &status, &count, &lastAt); err != nil {
+			return nil, fmt.Errorf("scanning task: %w", err)
+		}
+		t := byTask[task]
+		if t == nil {
+			t = &TaskStatusCounts{Task: task, Counts: make(map[RequestStatus]int)}
+			byTask[task] = t
+		}
+		t.Counts[RequestStatus(status)] += count
+		if at, err := time.Parse(time.RFC3339, lastAt); err == nil && at.After(t.LastAt) {
+			t.LastAt = at
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating tasks: %w", err)
+	}
+
+	tasks := make([]*TaskStatusCounts, 0, len(byTask))
+	for _, t := range byTask {
+		tasks = append(tasks, t)
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		if !tasks[i].LastAt.Equal(tasks[j].LastAt) {
+			return tasks[i].LastAt.After(tasks[j].LastAt)
+		}
+		return tasks[i].Task < tasks[j].Task
+	})
+	return tasks, nil
+}
@@ -0,0 +1,55 @@
+package db
+
+import "testing"
+
+func TestListTasks(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	sess, _ := createTestRequest(t, db)
+	create := func(project, task string) *Request {
+		t.Helper()
+		r := &Request{
+			ProjectPath:        project,
+			RequestorSessionID: sess.ID,
+			RequestorAgent:     sess.AgentName,
+			RiskTier:           RiskTierDangerous,
+			MinApprovals:       1,
+			Command:            CommandSpec{Raw: "make migrate", Cwd: project},
+			Justification:      Justification{Reason: "migrate"},
+			Labels:             map[string]string{TaskLabel: task, "team": "infra"},
+		}
+		if err := db.CreateRequest(r); err != nil {
+			t.Fatalf("CreateRequest failed: %v", err)
+		}
+		return r
+	}
+	create(sess.ProjectPath, "OPS-1")
+	cancelled := create(sess.ProjectPath, "OPS-1")
+	if err := db.UpdateRequestStatus(cancelled.ID, StatusCancelled); err != nil {
+		t.Fatalf("UpdateRequestStatus failed: %v", err)
+	}
+	create("/elsewhere", "OPS-2")
+
+	tasks, err := db.ListTasks(sess.ProjectPath)
+	if err != nil {
+		t.Fatalf("ListTasks failed: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].Task != "OPS-1" {
+		t.Fatalf("ListTasks(project) = %+v, want only OPS-1", tasks)
+	}
+	if c := tasks[0].Counts; c[StatusPending] != 1 || c[StatusCancelled] != 1 || len(c) != 2 {
+		t.Fatalf("OPS-1 counts = %v, want 1 pending and 1 cancelled", c)
+	}
+	if tasks[0].LastAt.IsZero() {
+		t.Fatal("expected LastAt to be set")
+	}
+
+	all, err := db.ListTasks("")
+	if err != nil {
+		t.Fatalf("ListTasks failed: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("ListTasks(all) = %d tasks, want 2", len(all))
+	}
+}
@@ -0,0 +1,160 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrRequestPatternNotFound indicates no matched pattern was recorded for a request.
+var ErrRequestPatternNotFound = errors.New("request pattern not found")
+
+// RequestPattern records the pattern that classified a request.
+type RequestPattern struct {
+	// RequestID is the request this pattern classified.
+	RequestID string `json:"request_id"`
+	// Tier is the risk tier the pattern assigned.
+	Tier RiskTier `json:"tier"`
+	// Pattern is the matched regex (or fallback rule name).
+	Pattern string `json:"pattern"`
+	// CreatedAt is when the request was classified.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SetRequestPattern records (or replaces) the matched pattern for a request.
+func (db *DB) SetRequestPattern(p *RequestPattern) error {
+	if p.RequestID == "" || p.Pattern == "" {
+		return fmt.Errorf("request pattern requires request id and pattern")
+	}
+	if p.CreatedAt.IsZero() {
+		p.CreatedAt = db.Now()
+	}
+
+	_, err := db.Exec(`
+		INSERT OR REPLACE INTO request_patterns (request_id, tier, pattern, created_at)
+		VALUES (?, ?, ?, ?)
+	`, p.RequestID, string(p.Tier), p.Pattern, p.CreatedAt.Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("recording request pattern: %w", err)
+	}
+	return nil
+}
+
+// GetRequestPattern returns the matched pattern recorded for a request.
+func (db *DB) GetRequestPattern(requestID string) (*RequestPattern, error) {
+	p := &RequestPattern{}
+	var tier, created string
+	err := db.QueryRow(`
+		SELECT request_id, tier, pattern, created_at
+		FROM request_patterns
+		WHERE request_id = ?
+	`, requestID).Scan(&p.RequestID, &tier, &p.Pattern, &created)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRequestPatternNotFound
+		}
+		return nil, fmt.Errorf("getting request pattern: %w", err)
+	}
+	p.Tier = RiskTier(tier)
+	p.CreatedAt, _ = time.Parse(time.RFC3339, created)
+	return p, nil
+}
+
+// PatternOutcome counts requests classified by one pattern that reached
+// one final status.
+type PatternOutcome struct {
+	Pattern string        `json:"pattern"`
+	Tier    RiskTier      `json:"tier"`
+	Status  RequestStatus `json:"status"`
+	Count   int           `json:"count"`
+}
+
+// ListPatternOutcomes counts requests resolved in [since, until) by matched
+// pattern, tier and final status. Requests still in flight are not counted.
+func (db *DB) ListPatternOutcomes(since, until time.Time) ([]PatternOutcome, error) {
+	rows, err := db.Query(`
+		SELECT p.pattern, p.tier, r.status, COUNT(*)
+		FROM request_patterns p
+		JOIN requests r ON r.id = p.request_id
+		WHERE r.resolved_at IS NOT NULL AND r.resolved_at >= ? AND r.resolved_at < ?
+		GROUP BY p.pattern, p.tier, r.status
+		ORDER BY p.tier, p.pattern, r.status
+	`, since.UTC().Format(time.RFC3339), until.UTC().Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("listing pattern outcomes: %w", err)
+	}
+	defer rows.Close()
+
+	var outcomes []PatternOutcome
+	for rows.Next() {
+		var o PatternOutcome
+		var tier, status string
+		if err := rows.Scan(&o.Pattern, &tier, &status, &o.Count); err != nil {
+			return nil, fmt.Errorf("scanning pattern outcome: %w", err)
+		}
+		o.Tier = RiskTier(tier)
+		o.Status = RequestStatus(status)
+		outcomes = append(outcomes, o)
+	}
+	return outcomes, rows.Err()
+}
+
+// TelemetryReport logs one attempt to send a telemetry report.
+type TelemetryReport struct {
+	ID          int64     `json:"id"`
+	SentAt      time.Time `json:"sent_at"`
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	Endpoint    string    `json:"endpoint"`
+	// Patterns is how many pattern entries the report carried.
+	Patterns int `json:"patterns"`
+	// Error is set when the send failed.
+	Error string `json:"error,omitempty"`
+}
+
+// RecordTelemetryReport logs a telemetry send attempt.
+func (db *DB) RecordTelemetryReport(r *TelemetryReport) error {
+	if r.SentAt.IsZero() {
+		r.SentAt = db.Now()
+	}
+	res, err := db.Exec(`
+		INSERT INTO telemetry_reports (sent_at, period_start, period_end, endpoint, patterns, error)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, r.SentAt.UTC().Format(time.RFC3339), r.PeriodStart.UTC().Format(time.RFC3339),
+		r.PeriodEnd.UTC().Format(time.RFC3339), r.Endpoint, r.Patterns, nullString(r.Error))
+	if err != nil {
+		return fmt.Errorf("recording telemetry report: %w", err)
+	}
+	r.ID, _ = res.LastInsertId()
+	return nil
+}
+
+// LastTelemetryReport returns the most recent send attempt, or the most
+// recent successful one when successOnly is set. It returns nil when there
+// is none.
+func (db *DB) LastTelemetryReport(successOnly bool) (*TelemetryReport, error) {
+	query := `
+		SELECT id, sent_at, period_start, period_end, endpoint, patterns, error
+		FROM telemetry_reports`
+	if successOnly {
+		query += ` WHERE error IS NULL`
+	}
+	query += ` ORDER BY id DESC LIMIT 1`
+
+	r := &TelemetryReport{}
+	var sent, start, end string
+	var errText sql.NullString
+	err := db.QueryRow(query).Scan(&r.ID, &sent, &start, &end, &r.Endpoint, &r.Patterns, &errText)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("getting last telemetry report: %w", err)
+	}
+	r.SentAt, _ = time.Parse(time.RFC3339, sent)
+	r.PeriodStart, _ = time.Parse(time.RFC3339, start)
+	r.PeriodEnd, _ = time.Parse(time.RFC3339, end)
+	r.Error = errText.String
+	return r, nil
+}
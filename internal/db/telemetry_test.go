@@ -0,0 +1,111 @@
+package db
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRequestPattern(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, req := createTestRequest(t, db)
+
+	if _, err := db.GetRequestPattern(req.ID); !errors.Is(err, ErrRequestPatternNotFound) {
+		t.Fatalf("expected ErrRequestPatternNotFound, got %v", err)
+	}
+	if err := db.SetRequestPattern(&RequestPattern{RequestID: req.ID}); err == nil {
+		t.Fatal("expected error without pattern")
+	}
+
+	p := &RequestPattern{RequestID: req.ID, Tier: RiskTierDangerous, Pattern: `^rm\s+-rf`}
+	if err := db.SetRequestPattern(p); err != nil {
+		t.Fatalf("SetRequestPattern failed: %v", err)
+	}
+	got, err := db.GetRequestPattern(req.ID)
+	if err != nil {
+		t.Fatalf("GetRequestPattern failed: %v", err)
+	}
+	if got.Pattern != p.Pattern || got.Tier != RiskTierDangerous || got.CreatedAt.IsZero() {
+		t.Fatalf("unexpected pattern: %+v", got)
+	}
+}
+
+func TestListPatternOutcomes(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	start := time.Now().UTC().Add(-time.Hour)
+	record := func(pattern string, status RequestStatus) {
+		t.Helper()
+		_, req := createTestRequest(t, db)
+		if err := db.SetRequestPattern(&RequestPattern{RequestID: req.ID, Tier: RiskTierDangerous, Pattern: pattern}); err != nil {
+			t.Fatalf("SetRequestPattern failed: %v", err)
+		}
+		if status != StatusPending {
+			if err := db.UpdateRequestStatus(req.ID, status); err != nil {
+				t.Fatalf("UpdateRequestStatus failed: %v", err)
+			}
+		}
+	}
+	record(`^rm\s+-rf`, StatusRejected)
+	record(`^rm\s+-rf`, StatusRejected)
+	record(`^rm\s+-rf`, StatusCancelled)
+	record(`^git\s+reset`, StatusPending)
+	record(`^git\s+reset`, StatusApproved) // not resolved yet
+
+	outcomes, err := db.ListPatternOutcomes(start, time.Now().UTC().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("ListPatternOutcomes failed: %v", err)
+	}
+	if len(outcomes) != 2 {
+		t.Fatalf("expected 2 outcome rows, got %+v", outcomes)
+	}
+	counts := map[RequestStatus]int{}
+	for _, o := range outcomes {
+		if o.Pattern != `^rm\s+-rf` || o.Tier != RiskTierDangerous {
+			t.Fatalf("unexpected outcome: %+v", o)
+		}
+		counts[o.Status] = o.Count
+	}
+	if counts[StatusRejected] != 2 || counts[StatusCancelled] != 1 {
+		t.Fatalf("unexpected counts: %v", counts)
+	}
+
+	outcomes, err = db.ListPatternOutcomes(start.Add(-2*time.Hour), start)
+	if err != nil || len(outcomes) != 0 {
+		t.Fatalf("expected no outcomes before the window, got %+v (err %v)", outcomes, err)
+	}
+}
+
+func TestTelemetryReports(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	if last, err := db.LastTelemetryReport(false); err != nil || last != nil {
+		t.Fatalf("expected no report, got %+v (err %v)", last, err)
+	}
+
+	end := time.Now().UTC().Truncate(time.Second)
+	ok := &TelemetryReport{PeriodStart: end.Add(-24 * time.Hour), PeriodEnd: end, Endpoint: "https://stats.example.com", Patterns: 3}
+	if err := db.RecordTelemetryReport(ok); err != nil {
+		t.Fatalf("RecordTelemetryReport failed: %v", err)
+	}
+	failed := &TelemetryReport{PeriodStart: end, PeriodEnd: end.Add(time.Hour), Endpoint: "https://stats.example.com", Error: "503"}
+	if err := db.RecordTelemetryReport(failed); err != nil {
+		t.Fatalf("RecordTelemetryReport failed: %v", err)
+	}
+	if failed.ID <= ok.ID {
+		t.Fatalf("expected increasing ids, got %d then %d", ok.ID, failed.ID)
+	}
+
+	last, err := db.LastTelemetryReport(false)
+	if err != nil || last == nil || last.Error != "503" {
+		t.Fatalf("expected the failed report last, got %+v (err %v)", last, err)
+	}
+	last, err = db.LastTelemetryReport(true)
+	if err != nil || last == nil || last.ID != ok.ID || last.Patterns != 3 || !last.PeriodEnd.Equal(end) {
+		t.Fatalf("expected the successful report, got %+v (err %v)", last, err)
+	}
+}
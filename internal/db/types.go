@@ -65,7 +65,7 @@ type Justification struct {
 
 // Attachment represents additional context attached to a request.
 type Attachment struct {
-	// Type is the attachment type (file, git_diff, context, screenshot).
+	// Type is the attachment type (file, git_diff, context, screenshot, transcript).
 	Type AttachmentType `json:"type"`
 	// Content is the attachment content.
 	Content string `json:"content"`
@@ -97,6 +97,10 @@ type Execution struct {
 	ExitCode *int `json:"exit_code,omitempty"`
 	// DurationMs is the execution duration in milliseconds.
 	DurationMs *int64 `json:"duration_ms,omitempty"`
+	// SecretsFound lists the kinds of secret (aws_access_key_id,
+	// github_token, ...) found in the command's output. The stored log has
+	// them redacted.
+	SecretsFound []string `json:"secrets_found,omitempty"`
 }
 
 // Rollback contains information about rollback state.
@@ -117,6 +121,10 @@ type Request struct {
 	Command CommandSpec `json:"command"`
 	// RiskTier is the risk classification.
 	RiskTier RiskTier `json:"risk_tier"`
+	// RiskScore grades the request from 0 (benign) to 100 within and across
+	// tiers. It orders the review queue and feeds policy thresholds; the
+	// tier still decides how many approvals are needed.
+	RiskScore int `json:"risk_score"`
 
 	// Requestor is the session ID that submitted the request.
 	RequestorSessionID string `json:"requestor_session_id"`
@@ -141,6 +149,34 @@ type Request struct {
 	// RequireDifferentModel requires a different model for approval.
 	RequireDifferentModel bool `json:"require_different_model"`
 
+	// Labels are org-specific key/value metadata. They are stored by
+	// CreateRequest; reads leave them nil unless loaded with LoadRequestLabels.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Script and RemoteScripts record the hashes execution checks the
+	// command's script and downloaded scripts against. CreateRequest
+	// stores them in the same transaction as the request; reads leave them
+	// nil (see GetRequestScript and ListRequestRemoteScripts).
+	Script        *RequestScript  `json:"-"`
+	RemoteScripts []*RemoteScript `json:"-"`
+
+	// Binary and Environment pin the executable, working directory and
+	// environment execution is checked against for drift. CreateRequest
+	// stores them in the same transaction as the request, so a request
+	// never exists without the pins it was created with; reads leave them
+	// nil (see GetRequestBinary and GetRequestEnvironment).
+	Binary      *RequestBinary      `json:"-"`
+	Environment *RequestEnvironment `json:"-"`
+
+	// RedactPatterns are the request's own patterns for sensitive data,
+	// applied with the built-in ones to its execution output. CreateRequest
+	// stores them; reads leave them nil (see GetRequestRedactPatterns).
+	RedactPatterns []string `json:"-"`
+
+	// Import is set on requests brought in from another approval process
+	// with 'slb history import', and marks them apart from native ones.
+	Import *RequestImport `json:"import,omitempty"`
+
 	// Execution contains execution information.
 	Execution *Execution `json:"execution,omitempty"`
 	// Rollback contains rollback information.
@@ -154,6 +190,10 @@ type Request struct {
 	ExpiresAt *time.Time `json:"expires_at,omitempty"`
 	// ApprovalExpiresAt is when approval becomes stale.
 	ApprovalExpiresAt *time.Time `json:"approval_expires_at,omitempty"`
+
+	// Version increases with every review and status change. Reviewers pass
+	// the version they decided on so a concurrent decision is detected.
+	Version int `json:"version"`
 }
 
 // IsExpired returns true if the request has expired.
@@ -214,6 +254,12 @@ type Review struct {
 	// Comments contains additional comments.
 	Comments string `json:"comments,omitempty"`
 
+	// AuthMethod is how the reviewer re-authenticated just before deciding
+	// ("passphrase" or "fido2") when sudo mode required it.
+	AuthMethod string `json:"auth_method,omitempty"`
+	// AuthenticatedAt is when that re-authentication happened.
+	AuthenticatedAt *time.Time `json:"authenticated_at,omitempty"`
+
 	// CreatedAt is when the review was created.
 	CreatedAt time.Time `json:"created_at"`
 }
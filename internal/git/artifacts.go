@@ -0,0 +1,204 @@
+package git
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Artifact kinds, named after the history repo directories they live in.
+const (
+	ArtifactRequest   = "request"
+	ArtifactReview    = "review"
+	ArtifactExecution = "execution"
+)
+
+// CommitInfo describes the last commit that touched an artifact.
+type CommitInfo struct {
+	Hash    string    `json:"hash"`
+	Author  string    `json:"author"`
+	Date    time.Time `json:"date"`
+	Subject string    `json:"subject"`
+}
+
+// Artifact is a JSON snapshot of a request, review or execution in the
+// history repo.
+type Artifact struct {
+	// Kind is request, review or execution.
+	Kind string `json:"kind"`
+	// ID is the request ID, or the review ID for reviews.
+	ID string `json:"id"`
+	// Path is relative to the repo root, with forward slashes.
+	Path string `json:"path"`
+	// Commit is nil if the file was never committed.
+	Commit *CommitInfo `json:"commit,omitempty"`
+	// Modified reports uncommitted changes to the file.
+	Modified bool `json:"modified"`
+	// Content is the committed snapshot, or the working copy if the file
+	// was never committed.
+	Content []byte `json:"-"`
+}
+
+// RequestArtifacts finds the snapshots recorded for a request: the request
+// itself, its reviews and its execution, in that order.
+func (r *HistoryRepo) RequestArtifacts(requestID string) ([]Artifact, error) {
+	if r == nil || r.Path == "" {
+		return nil, fmt.Errorf("history repo path is required")
+	}
+	if requestID == "" || strings.ContainsAny(requestID, `/\*?[`) {
+		return nil, fmt.Errorf("invalid request id %q", requestID)
+	}
+
+	var artifacts []Artifact
+	add := func(kind, id, pattern string, keep func(content []byte) bool) error {
+		matches, err := filepath.Glob(filepath.Join(r.Path, pattern))
+		if err != nil {
+			return err
+		}
+		sort.Strings(matches)
+		for _, abs := range matches {
+			rel, err := filepath.Rel(r.Path, abs)
+			if err != nil {
+				return err
+			}
+			a, err := r.loadArtifact(kind, filepath.ToSlash(rel))
+			if err != nil {
+				return err
+			}
+			if keep != nil && !keep(a.Content) {
+				continue
+			}
+			a.ID = id
+			if a.ID == "" {
+				a.ID = strings.TrimSuffix(strings.TrimPrefix(filepath.Base(abs), "rev-"), ".json")
+			}
+			artifacts = append(artifacts, *a)
+		}
+		return nil
+	}
+
+	if err := add(ArtifactRequest, requestID, filepath.Join("requests", "*", "*", "req-"+requestID+".json"), nil); err != nil {
+		return nil, err
+	}
+	// Reviews are named by review ID, so match on the request they belong to.
+	if err := add(ArtifactReview, "", filepath.Join("reviews", "*", "*", "rev-*.json"), func(content []byte) bool {
+		var rev struct {
+			RequestID string `json:"request_id"`
+		}
+		return json.Unmarshal(content, &rev) == nil && rev.RequestID == requestID
+	}); err != nil {
+		return nil, err
+	}
+	if err := add(ArtifactExecution, requestID, filepath.Join("executions", "*", "*", "exec-"+requestID+".json"), nil); err != nil {
+		return nil, err
+	}
+	return artifacts, nil
+}
+
+func (r *HistoryRepo) loadArtifact(kind, rel string) (*Artifact, error) {
+	a := &Artifact{Kind: kind, Path: rel}
+
+	out, err := runGit(r.Path, "log", "-1", "--format=%H%x1f%an%x1f%aI%x1f%s", "--", rel)
+	if err != nil && !strings.Contains(err.Error(), "does not have any commits") {
+		return nil, err
+	}
+	if parts := strings.SplitN(out, "\x1f", 4); len(parts) == 4 {
+		date, _ := time.Parse(time.RFC3339, parts[2]) //nolint:errcheck
+		a.Commit = &CommitInfo{Hash: parts[0], Author: parts[1], Date: date, Subject: parts[3]}
+	}
+
+	if a.Commit != nil {
+		content, err := runGit(r.Path, "show", a.Commit.Hash+":"+rel)
+		if err != nil {
+			return nil, err
+		}
+		a.Content = []byte(content)
+		status, err := runGit(r.Path, "status", "--porcelain", "--", rel)
+		if err != nil {
+			return nil, err
+		}
+		a.Modified = status != ""
+	} else {
+		content, err := os.ReadFile(filepath.Join(r.Path, filepath.FromSlash(rel)))
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", rel, err)
+		}
+		a.Content = content
+		a.Modified = true
+	}
+	return a, nil
+}
+
+// FieldDiff is a field whose value differs between an artifact and the
+// record it snapshots. Values are JSON; an empty value means the field is
+// missing on that side.
+type FieldDiff struct {
+	Field     string `json:"field"`
+	Committed string `json:"committed,omitempty"`
+	Current   string `json:"current,omitempty"`
+}
+
+// CompareArtifact compares an artifact's content with the current record,
+// field by field (nested objects as dotted fields, arrays as a whole).
+func CompareArtifact(content []byte, current any) ([]FieldDiff, error) {
+	committed, err := flattenJSON(content)
+	if err != nil {
+		return nil, fmt.Errorf("parsing artifact: %w", err)
+	}
+	encoded, err := json.Marshal(current)
+	if err != nil {
+		return nil, fmt.Errorf("marshal json: %w", err)
+	}
+	now, err := flattenJSON(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]string, 0, len(committed)+len(now))
+	for k := range committed {
+		fields = append(fields, k)
+	}
+	for k := range now {
+		if _, ok := committed[k]; !ok {
+			fields = append(fields, k)
+		}
+	}
+	sort.Strings(fields)
+
+	var diffs []FieldDiff
+	for _, f := range fields {
+		if committed[f] != now[f] {
+			diffs = append(diffs, FieldDiff{Field: f, Committed: committed[f], Current: now[f]})
+		}
+	}
+	return diffs, nil
+}
+
+func flattenJSON(content []byte) (map[string]string, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(content, &doc); err != nil {
+		return nil, err
+	}
+	out := make(map[string]string)
+	var walk func(prefix string, m map[string]any)
+	walk = func(prefix string, m map[string]any) {
+		for k, v := range m {
+			key := k
+			if prefix != "" {
+				key = prefix + "." + k
+			}
+			if obj, ok := v.(map[string]any); ok {
+				walk(key, obj)
+				continue
+			}
+			encoded, _ := json.Marshal(v) //nolint:errcheck // decoded JSON always re-encodes
+			out[key] = string(encoded)
+		}
+	}
+	walk("", doc)
+	return out, nil
+}
@@ -533,3 +533,94 @@ func TestHistoryRepo_ConstructorsAndHelpers(t *testing.T) {
 		t.Fatalf("expected max<=3 to hard truncate, got %q", got)
 	}
 }
+
+func TestHistoryRepo_RequestArtifacts(t *testing.T) {
+	requireGit(t)
+	repo := &HistoryRepo{Path: t.TempDir()}
+	if err := repo.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if artifacts, err := repo.RequestArtifacts("req-1"); err != nil || len(artifacts) != 0 {
+		t.Fatalf("RequestArtifacts on an empty repo = %+v, %v", artifacts, err)
+	}
+
+	when := time.Date(2025, time.January, 2, 3, 4, 5, 0, time.UTC)
+	req := &db.Request{ID: "req-1", RiskTier: db.RiskTierDangerous, Status: db.StatusPending, Command: db.CommandSpec{Raw: "rm -rf build"}, CreatedAt: when}
+	if _, _, err := repo.CommitRequest(req); err != nil {
+		t.Fatal(err)
+	}
+	for _, rev := range []*db.Review{
+		{ID: "a", RequestID: req.ID, Decision: db.DecisionApprove, CreatedAt: when},
+		{ID: "b", RequestID: "req-2", Decision: db.DecisionReject, CreatedAt: when},
+	} {
+		if _, _, err := repo.CommitReview(rev); err != nil {
+			t.Fatal(err)
+		}
+	}
+	exit := 0
+	if _, _, err := repo.CommitExecution(req.ID, &db.Execution{ExecutedAt: &when, ExitCode: &exit}); err != nil {
+		t.Fatal(err)
+	}
+
+	artifacts, err := repo.RequestArtifacts(req.ID)
+	if err != nil {
+		t.Fatalf("RequestArtifacts: %v", err)
+	}
+	if len(artifacts) != 3 {
+		t.Fatalf("expected 3 artifacts, got %+v", artifacts)
+	}
+	wantPaths := []string{"requests/2025/01/req-req-1.json", "reviews/2025/01/rev-a.json", "executions/2025/01/exec-req-1.json"}
+	for i, a := range artifacts {
+		if a.Path != wantPaths[i] || a.Commit == nil || a.Modified || len(a.Content) == 0 {
+			t.Errorf("artifact %d = %+v", i, a)
+		}
+	}
+	if artifacts[1].Kind != ArtifactReview || artifacts[1].ID != "a" || artifacts[0].ID != req.ID {
+		t.Errorf("artifact ids = %s, %s", artifacts[0].ID, artifacts[1].ID)
+	}
+	if c := artifacts[0].Commit; c.Author != defaultHistoryAuthorName || !strings.HasPrefix(c.Subject, "Request: dangerous") || c.Date.IsZero() {
+		t.Errorf("request commit = %+v", c)
+	}
+
+	// The committed snapshot matches the record until the record changes.
+	if diffs, err := CompareArtifact(artifacts[0].Content, req); err != nil || len(diffs) != 0 {
+		t.Fatalf("CompareArtifact before change = %+v, %v", diffs, err)
+	}
+	req.Status = db.StatusExecuted
+	diffs, err := CompareArtifact(artifacts[0].Content, req)
+	if err != nil || len(diffs) != 1 || diffs[0].Field != "status" || diffs[0].Committed != `"pending"` || diffs[0].Current != `"executed"` {
+		t.Fatalf("CompareArtifact after change = %+v, %v", diffs, err)
+	}
+
+	// Uncommitted edits are flagged, but the committed snapshot is shown.
+	path := filepath.Join(repo.Path, "requests", "2025", "01", "req-req-1.json")
+	if err := os.WriteFile(path, []byte("{}\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	artifacts, err = repo.RequestArtifacts(req.ID)
+	if err != nil || !artifacts[0].Modified || !strings.Contains(string(artifacts[0].Content), `"pending"`) {
+		t.Errorf("modified artifact = %+v, %v", artifacts[0], err)
+	}
+
+	if _, err := repo.RequestArtifacts("../x"); err == nil {
+		t.Error("expected an error for a path-like request id")
+	}
+	if _, err := CompareArtifact([]byte("not json"), req); err == nil {
+		t.Error("expected an error for invalid artifact content")
+	}
+}
+
+func TestHistoryRepo_RequestArtifacts_Uncommitted(t *testing.T) {
+	requireGit(t)
+	repo := &HistoryRepo{Path: t.TempDir()}
+	if err := repo.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if _, err := repo.writeJSON(filepath.Join("requests", "2025", "01", "req-req-1.json"), &db.Request{ID: "req-1"}); err != nil {
+		t.Fatal(err)
+	}
+	artifacts, err := repo.RequestArtifacts("req-1")
+	if err != nil || len(artifacts) != 1 || artifacts[0].Commit != nil || !artifacts[0].Modified || len(artifacts[0].Content) == 0 {
+		t.Fatalf("uncommitted artifact = %+v, %v", artifacts, err)
+	}
+}
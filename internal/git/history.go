@@ -87,6 +87,9 @@ func (r *HistoryRepo) CommitRequest(req *db.Request) (bool, string, error) {
 	}
 
 	msg := fmt.Sprintf("Request: %s %s", req.RiskTier, truncateForCommit(requestCommandForDisplay(req), 72))
+	if req.Import != nil {
+		msg = fmt.Sprintf("Import (%s %s): %s %s", req.Import.Source, req.Import.ExternalID, req.RiskTier, truncateForCommit(requestCommandForDisplay(req), 72))
+	}
 	committed, err := gitCommitIfNeeded(r.Path, msg)
 	return committed, abs, err
 }
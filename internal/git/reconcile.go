@@ -0,0 +1,180 @@
+package git
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// Reconcile problems.
+const (
+	// ProblemMissing means the record has no committed artifact.
+	ProblemMissing = "missing"
+	// ProblemMismatch means the committed artifact differs from the record.
+	ProblemMismatch = "mismatch"
+	// ProblemModified means a committed artifact has uncommitted edits.
+	ProblemModified = "modified"
+)
+
+// ReconcileOptions configures a reconcile run.
+type ReconcileOptions struct {
+	// ProjectPath selects the project whose requests are checked.
+	ProjectPath string
+	// DryRun reports missing artifacts without committing them.
+	DryRun bool
+}
+
+// ReconcileIssue is an artifact that did not match the database.
+type ReconcileIssue struct {
+	RequestID string `json:"request_id"`
+	Kind      string `json:"kind"`
+	ID        string `json:"id"`
+	// Path is empty for a missing artifact that was not backfilled.
+	Path        string      `json:"path,omitempty"`
+	Problem     string      `json:"problem"`
+	Differences []FieldDiff `json:"differences,omitempty"`
+	// Backfilled is set when a missing artifact was committed.
+	Backfilled bool `json:"backfilled,omitempty"`
+}
+
+// ReconcileReport summarizes a reconcile run.
+type ReconcileReport struct {
+	// Requests is the number of terminal requests checked.
+	Requests int `json:"requests"`
+	// Artifacts is the number of artifacts expected for them.
+	Artifacts  int              `json:"artifacts"`
+	Backfilled int              `json:"backfilled"`
+	Mismatched int              `json:"mismatched"`
+	Issues     []ReconcileIssue `json:"issues"`
+}
+
+// Reconcile checks that every terminal request in the database has
+// committed request, review and execution artifacts. Missing artifacts are
+// committed from the database (unless DryRun); artifacts that differ from
+// the database are reported but left alone, since the audit trail is what
+// the database is checked against.
+func (r *HistoryRepo) Reconcile(database *db.DB, opts ReconcileOptions) (*ReconcileReport, error) {
+	if database == nil {
+		return nil, fmt.Errorf("database is required")
+	}
+	if err := r.Init(); err != nil {
+		return nil, err
+	}
+
+	requests, err := database.ListAllRequests(opts.ProjectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ReconcileReport{Issues: []ReconcileIssue{}}
+	// Oldest first, so backfilled commits follow the order requests finished.
+	for i := len(requests) - 1; i >= 0; i-- {
+		req := requests[i]
+		if !req.Status.IsTerminal() {
+			continue
+		}
+		report.Requests++
+
+		type expected struct {
+			kind, id string
+			record   any
+			commit   func() error
+		}
+		items := []expected{{ArtifactRequest, req.ID, req, func() error {
+			_, _, err := r.CommitRequest(req)
+			return err
+		}}}
+		reviews, err := database.ListReviewsForRequest(req.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, rev := range reviews {
+			items = append(items, expected{ArtifactReview, rev.ID, rev, func() error {
+				_, _, err := r.CommitReview(rev)
+				return err
+			}})
+		}
+		if req.Execution != nil {
+			items = append(items, expected{ArtifactExecution, req.ID, req.Execution, func() error {
+				_, _, err := r.CommitExecution(req.ID, req.Execution)
+				return err
+			}})
+		}
+
+		for _, item := range items {
+			report.Artifacts++
+			a, err := r.findArtifact(item.kind, item.id)
+			if err != nil {
+				return nil, err
+			}
+			issue := ReconcileIssue{RequestID: req.ID, Kind: item.kind, ID: item.id}
+
+			if a == nil || a.Commit == nil {
+				issue.Problem = ProblemMissing
+				if !opts.DryRun {
+					if err := item.commit(); err != nil {
+						return nil, fmt.Errorf("backfilling %s %s: %w", item.kind, item.id, err)
+					}
+					if a, err = r.findArtifact(item.kind, item.id); err != nil {
+						return nil, err
+					}
+					issue.Backfilled = true
+					report.Backfilled++
+				}
+				if a != nil {
+					issue.Path = a.Path
+				}
+				report.Issues = append(report.Issues, issue)
+				continue
+			}
+
+			issue.Path = a.Path
+			diffs, err := CompareArtifact(a.Content, item.record)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", a.Path, err)
+			}
+			switch {
+			case len(diffs) > 0:
+				issue.Problem = ProblemMismatch
+				issue.Differences = diffs
+			case a.Modified:
+				issue.Problem = ProblemModified
+			default:
+				continue
+			}
+			report.Mismatched++
+			report.Issues = append(report.Issues, issue)
+		}
+	}
+	return report, nil
+}
+
+// findArtifact returns the artifact for a record, or nil if there is none.
+func (r *HistoryRepo) findArtifact(kind, id string) (*Artifact, error) {
+	var name string
+	switch kind {
+	case ArtifactRequest:
+		name = filepath.Join("requests", "*", "*", "req-"+id+".json")
+	case ArtifactReview:
+		name = filepath.Join("reviews", "*", "*", "rev-"+id+".json")
+	case ArtifactExecution:
+		name = filepath.Join("executions", "*", "*", "exec-"+id+".json")
+	default:
+		return nil, fmt.Errorf("unknown artifact kind %q", kind)
+	}
+	matches, err := filepath.Glob(filepath.Join(r.Path, name))
+	if err != nil || len(matches) == 0 {
+		return nil, err
+	}
+	rel, err := filepath.Rel(r.Path, matches[0])
+	if err != nil {
+		return nil, err
+	}
+	a, err := r.loadArtifact(kind, filepath.ToSlash(rel))
+	if err != nil {
+		return nil, err
+	}
+	a.ID = id
+	return a, nil
+}
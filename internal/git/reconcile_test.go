@@ -0,0 +1,106 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestHistoryRepo_Reconcile(t *testing.T) {
+	requireGit(t)
+	database := testutil.NewTestDB(t)
+	project := "/work/app"
+	requestor := testutil.MakeSession(t, database, testutil.WithProject(project), testutil.WithAgent("Requestor"))
+	reviewer := testutil.MakeSession(t, database, testutil.WithProject(project), testutil.WithAgent("Reviewer"))
+
+	done := testutil.MakeRequest(t, database, requestor, testutil.WithStatus(db.StatusRejected))
+	rev := &db.Review{RequestID: done.ID, ReviewerSessionID: reviewer.ID, ReviewerAgent: "Reviewer", ReviewerModel: "model", Decision: db.DecisionReject, Signature: "sig"}
+	if err := database.CreateReview(rev); err != nil {
+		t.Fatal(err)
+	}
+	executed := testutil.MakeRequest(t, database, requestor, testutil.WithStatus(db.StatusExecuted))
+	exit := 0
+	when := time.Now().UTC().Truncate(time.Second)
+	if err := database.UpdateRequestExecution(executed.ID, &db.Execution{ExecutedAt: &when, ExitCode: &exit, ExecutedByAgent: "Requestor"}); err != nil {
+		t.Fatal(err)
+	}
+	testutil.MakeRequest(t, database, requestor) // pending: not checked
+
+	repo := &HistoryRepo{Path: t.TempDir()}
+
+	report, err := repo.Reconcile(database, ReconcileOptions{ProjectPath: project, DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if report.Requests != 2 || report.Artifacts != 4 || report.Backfilled != 0 || len(report.Issues) != 4 {
+		t.Fatalf("dry run report = %+v", report)
+	}
+	if artifacts, _ := repo.RequestArtifacts(done.ID); len(artifacts) != 0 {
+		t.Fatalf("dry run committed %+v", artifacts)
+	}
+
+	report, err = repo.Reconcile(database, ReconcileOptions{ProjectPath: project})
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if report.Backfilled != 4 || report.Mismatched != 0 {
+		t.Fatalf("report = %+v", report)
+	}
+	for _, issue := range report.Issues {
+		if issue.Problem != ProblemMissing || !issue.Backfilled || issue.Path == "" {
+			t.Errorf("issue = %+v", issue)
+		}
+	}
+	artifacts, err := repo.RequestArtifacts(executed.ID)
+	if err != nil || len(artifacts) != 2 || artifacts[1].Kind != ArtifactExecution || artifacts[1].Commit == nil {
+		t.Fatalf("executed request artifacts = %+v, %v", artifacts, err)
+	}
+
+	// A second run has nothing to do.
+	report, err = repo.Reconcile(database, ReconcileOptions{ProjectPath: project})
+	if err != nil || report.Artifacts != 4 || len(report.Issues) != 0 {
+		t.Fatalf("second report = %+v, %v", report, err)
+	}
+
+	// The database moving away from the audit trail is reported, not fixed.
+	if _, err := database.Exec(`UPDATE requests SET justification_reason = 'edited' WHERE id = ?`, done.ID); err != nil {
+		t.Fatal(err)
+	}
+	// So are uncommitted edits to a committed artifact.
+	execPath := filepath.Join(repo.Path, filepath.FromSlash(artifacts[1].Path))
+	if err := os.WriteFile(execPath, []byte("{}\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	report, err = repo.Reconcile(database, ReconcileOptions{ProjectPath: project})
+	if err != nil {
+		t.Fatalf("Reconcile after drift: %v", err)
+	}
+	if report.Mismatched != 2 || report.Backfilled != 0 || len(report.Issues) != 2 {
+		t.Fatalf("drift report = %+v", report)
+	}
+	for _, issue := range report.Issues {
+		switch issue.ID {
+		case done.ID:
+			if issue.Problem != ProblemMismatch || len(issue.Differences) != 1 || issue.Differences[0].Field != "justification.reason" {
+				t.Errorf("request issue = %+v", issue)
+			}
+		case executed.ID:
+			if issue.Problem != ProblemModified || issue.Kind != ArtifactExecution {
+				t.Errorf("execution issue = %+v", issue)
+			}
+		default:
+			t.Errorf("unexpected issue %+v", issue)
+		}
+	}
+	if content, _ := os.ReadFile(execPath); string(content) != "{}\n" {
+		t.Errorf("reconcile overwrote a modified artifact: %q", content)
+	}
+
+	if _, err := repo.Reconcile(nil, ReconcileOptions{}); err == nil {
+		t.Error("expected an error without a database")
+	}
+}
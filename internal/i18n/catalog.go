@@ -0,0 +1,120 @@
+package i18n
+
+// catalogs maps a locale to its messages. Every key must exist in the English
+// catalog; other locales may lag behind and fall back to English per key.
+// Format verbs must match across locales.
+var catalogs = map[string]map[string]string{
+	"en": en,
+	"es": es,
+}
+
+var en = map[string]string{
+	// Request statuses.
+	"status.pending":          "PENDING",
+	"status.approved":         "APPROVED",
+	"status.rejected":         "REJECTED",
+	"status.executing":        "EXECUTING",
+	"status.executed":         "EXECUTED",
+	"status.execution_failed": "EXECUTION_FAILED",
+	"status.failed":           "FAILED",
+	"status.cancelled":        "CANCELLED",
+	"status.timeout":          "TIMEOUT",
+	"status.timed_out":        "TIMED_OUT",
+	"status.escalated":        "ESCALATED",
+
+	// CLI validation errors.
+	"error.session_id_required":   "--session-id is required",
+	"error.session_key_required":  "--session-key is required",
+	"error.reason_required":       "--reason is required for rejections",
+	"error.no_reviewer_session":   "--session-id is required: no active session found for %s in this project",
+	"error.no_reviewable_request": "no pending request left for you to review",
+
+	// slb approve / slb reject.
+	"approve.done":          "Approved request %s",
+	"approve.delegated":     "Includes %d delegated approval(s) on behalf of: %s",
+	"approve.ready":         "Request is now approved and ready for execution!",
+	"approve.edit_proposed": "Proposed edit %s for request %s: %s",
+	"approve.edit_accept":   "%s can accept it with: slb accept-edit %s",
+	"reject.done":           "Rejected request %s",
+	"review.id":             "Review ID: %s",
+	"review.reason":         "Reason: %s",
+	"review.counts":         "Approvals: %d, Rejections: %d",
+	"review.status_changed": "Request status changed to: %s",
+
+	// TUI approve/reject forms.
+	"tui.approve.title":         "Approve Request",
+	"tui.approve.confirm":       "You are about to APPROVE this request.",
+	"tui.approve.critical":      "This is a CRITICAL tier request requiring 2+ approvals.",
+	"tui.approve.comments":      "Comments (optional):",
+	"tui.approve.placeholder":   "Optional: Add comments about your approval...",
+	"tui.reject.title":          "Reject Request",
+	"tui.reject.confirm":        "You are about to REJECT this request.\nThe command will NOT be executed.",
+	"tui.reject.reason":         "Reason ",
+	"tui.reject.required":       "(required)",
+	"tui.reject.placeholder":    "Explain why you are rejecting this request...",
+	"tui.reject.reason_missing": "A reason is required when rejecting a request",
+	"tui.form.request":          "Request: %s",
+	"tui.form.command":          "Command: %s",
+	"tui.form.tier":             "Tier: %s",
+
+	// TUI dashboard.
+	"tui.dashboard.agents":      "Agents (%d)",
+	"tui.dashboard.pending":     "Pending Requests (%d)",
+	"tui.dashboard.activity":    "Recent Activity",
+	"tui.dashboard.no_agents":   "No active sessions",
+	"tui.dashboard.no_pending":  "No pending requests",
+	"tui.dashboard.no_activity": "No recent activity",
+}
+
+var es = map[string]string{
+	"status.pending":          "PENDIENTE",
+	"status.approved":         "APROBADA",
+	"status.rejected":         "RECHAZADA",
+	"status.executing":        "EJECUTANDO",
+	"status.executed":         "EJECUTADA",
+	"status.execution_failed": "EJECUCIÓN_FALLIDA",
+	"status.failed":           "FALLIDA",
+	"status.cancelled":        "CANCELADA",
+	"status.timeout":          "EXPIRADA",
+	"status.timed_out":        "EXPIRADA",
+	"status.escalated":        "ESCALADA",
+
+	"error.session_id_required":   "se requiere --session-id",
+	"error.session_key_required":  "se requiere --session-key",
+	"error.reason_required":       "se requiere --reason para rechazar",
+	"error.no_reviewer_session":   "se requiere --session-id: no hay una sesión activa de %s en este proyecto",
+	"error.no_reviewable_request": "no quedan solicitudes pendientes para revisar",
+
+	"approve.done":          "Solicitud %s aprobada",
+	"approve.delegated":     "Incluye %d aprobación(es) delegada(s) en nombre de: %s",
+	"approve.ready":         "¡La solicitud está aprobada y lista para ejecutarse!",
+	"approve.edit_proposed": "Corrección %s propuesta para la solicitud %s: %s",
+	"approve.edit_accept":   "%s puede aceptarla con: slb accept-edit %s",
+	"reject.done":           "Solicitud %s rechazada",
+	"review.id":             "ID de revisión: %s",
+	"review.reason":         "Motivo: %s",
+	"review.counts":         "Aprobaciones: %d, Rechazos: %d",
+	"review.status_changed": "El estado de la solicitud cambió a: %s",
+
+	"tui.approve.title":         "Aprobar solicitud",
+	"tui.approve.confirm":       "Está a punto de APROBAR esta solicitud.",
+	"tui.approve.critical":      "Esta es una solicitud de nivel CRÍTICO que requiere 2+ aprobaciones.",
+	"tui.approve.comments":      "Comentarios (opcional):",
+	"tui.approve.placeholder":   "Opcional: añada comentarios sobre su aprobación...",
+	"tui.reject.title":          "Rechazar solicitud",
+	"tui.reject.confirm":        "Está a punto de RECHAZAR esta solicitud.\nEl comando NO se ejecutará.",
+	"tui.reject.reason":         "Motivo ",
+	"tui.reject.required":       "(obligatorio)",
+	"tui.reject.placeholder":    "Explique por qué rechaza esta solicitud...",
+	"tui.reject.reason_missing": "Se requiere un motivo para rechazar una solicitud",
+	"tui.form.request":          "Solicitud: %s",
+	"tui.form.command":          "Comando: %s",
+	"tui.form.tier":             "Nivel: %s",
+
+	"tui.dashboard.agents":      "Agentes (%d)",
+	"tui.dashboard.pending":     "Solicitudes pendientes (%d)",
+	"tui.dashboard.activity":    "Actividad reciente",
+	"tui.dashboard.no_agents":   "No hay sesiones activas",
+	"tui.dashboard.no_pending":  "No hay solicitudes pendientes",
+	"tui.dashboard.no_activity": "Sin actividad reciente",
+}
@@ -0,0 +1,125 @@
+// Package i18n provides the message catalog for user-facing CLI and TUI
+// strings.
+//
+// Messages are looked up by key in the active locale's catalog and fall back
+// to English, so a partially translated catalog never shows raw keys. The
+// locale comes from general.locale / SLB_LOCALE, then the standard LC_ALL,
+// LC_MESSAGES, and LANG variables.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultLocale is the locale used when none is configured or the requested
+// one has no catalog.
+const DefaultLocale = "en"
+
+var (
+	mu     sync.RWMutex
+	active = Detect()
+)
+
+// Supported returns the locales that have a message catalog, sorted.
+func Supported() []string {
+	locales := make([]string, 0, len(catalogs))
+	for l := range catalogs {
+		locales = append(locales, l)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// IsSupported reports whether locale (e.g. "es", "es_MX.UTF-8") resolves to
+// a catalog.
+func IsSupported(locale string) bool {
+	_, ok := catalogs[Normalize(locale)]
+	return ok
+}
+
+// Normalize reduces a locale identifier such as "es_MX.UTF-8" or "pt-BR" to
+// its lower-case language code ("es", "pt").
+func Normalize(locale string) string {
+	l := strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(l, ".@"); i >= 0 {
+		l = l[:i]
+	}
+	if i := strings.IndexAny(l, "_-"); i >= 0 {
+		l = l[:i]
+	}
+	return l
+}
+
+// Detect picks a locale from the environment: SLB_LOCALE first, then the
+// POSIX LC_ALL, LC_MESSAGES, and LANG variables. Unsupported or unset values
+// yield DefaultLocale.
+func Detect() string {
+	for _, env := range []string{"SLB_LOCALE", "LC_ALL", "LC_MESSAGES", "LANG"} {
+		v := os.Getenv(env)
+		if v == "" {
+			continue
+		}
+		if l := Normalize(v); IsSupported(l) {
+			return l
+		}
+		// An explicit but unsupported value (e.g. LANG=fr_FR) still wins
+		// over lower-priority variables, matching POSIX precedence.
+		return DefaultLocale
+	}
+	return DefaultLocale
+}
+
+// SetLocale activates locale and returns the locale actually in use. An empty
+// value re-runs environment detection; unsupported locales fall back to
+// DefaultLocale.
+func SetLocale(locale string) string {
+	l := Normalize(locale)
+	switch {
+	case l == "":
+		l = Detect()
+	case !IsSupported(l):
+		l = DefaultLocale
+	}
+	mu.Lock()
+	active = l
+	mu.Unlock()
+	return l
+}
+
+// Locale returns the active locale.
+func Locale() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return active
+}
+
+// T returns the message for key in the active locale, formatted with args
+// using fmt.Sprintf verbs. Missing translations fall back to English; unknown
+// keys are returned as-is.
+func T(key string, args ...any) string {
+	msg, ok := catalogs[Locale()][key]
+	if !ok {
+		msg, ok = catalogs[DefaultLocale][key]
+	}
+	if !ok {
+		msg = key
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// Status returns the upper-case display label for a request status (e.g.
+// "PENDING"). Unknown statuses are upper-cased unchanged.
+func Status(status string) string {
+	key := "status." + strings.ToLower(status)
+	if _, ok := catalogs[DefaultLocale][key]; !ok {
+		return strings.ToUpper(status)
+	}
+	return T(key)
+}
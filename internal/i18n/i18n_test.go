@@ -0,0 +1,133 @@
+package i18n
+
+import (
+	"strings"
+	"testing"
+)
+
+// useLocale activates locale for the duration of a test.
+func useLocale(t *testing.T, locale string) {
+	t.Helper()
+	prev := Locale()
+	SetLocale(locale)
+	t.Cleanup(func() { SetLocale(prev) })
+}
+
+func TestNormalize(t *testing.T) {
+	cases := map[string]string{
+		"es":          "es",
+		"es_MX.UTF-8": "es",
+		"pt-BR":       "pt",
+		" EN_us ":     "en",
+		"de@euro":     "de",
+		"":            "",
+	}
+	for in, want := range cases {
+		if got := Normalize(in); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	clearEnv := func() {
+		for _, env := range []string{"SLB_LOCALE", "LC_ALL", "LC_MESSAGES", "LANG"} {
+			t.Setenv(env, "")
+		}
+	}
+
+	clearEnv()
+	if got := Detect(); got != DefaultLocale {
+		t.Errorf("Detect() with no env = %q, want %q", got, DefaultLocale)
+	}
+
+	clearEnv()
+	t.Setenv("LANG", "es_ES.UTF-8")
+	if got := Detect(); got != "es" {
+		t.Errorf("Detect() with LANG=es_ES = %q, want es", got)
+	}
+
+	clearEnv()
+	t.Setenv("LANG", "es_ES.UTF-8")
+	t.Setenv("LC_ALL", "fr_FR.UTF-8")
+	if got := Detect(); got != DefaultLocale {
+		t.Errorf("LC_ALL should take precedence over LANG, got %q", got)
+	}
+
+	clearEnv()
+	t.Setenv("LC_ALL", "C")
+	t.Setenv("SLB_LOCALE", "es")
+	if got := Detect(); got != "es" {
+		t.Errorf("SLB_LOCALE should take precedence, got %q", got)
+	}
+}
+
+func TestSetLocaleFallsBack(t *testing.T) {
+	useLocale(t, "es")
+	if got := Locale(); got != "es" {
+		t.Fatalf("Locale() = %q, want es", got)
+	}
+	if got := SetLocale("xx"); got != DefaultLocale {
+		t.Errorf("SetLocale(xx) = %q, want %q", got, DefaultLocale)
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	useLocale(t, "en")
+	if got := T("approve.done", "req-1"); got != "Approved request req-1" {
+		t.Errorf("T(en) = %q", got)
+	}
+
+	SetLocale("es")
+	if got := T("approve.done", "req-1"); got != "Solicitud req-1 aprobada" {
+		t.Errorf("T(es) = %q", got)
+	}
+	if got := T("no.such.key"); got != "no.such.key" {
+		t.Errorf("unknown keys should be returned as-is, got %q", got)
+	}
+}
+
+func TestTranslateFallsBackToEnglish(t *testing.T) {
+	useLocale(t, "es")
+	catalogs["es"] = map[string]string{}
+	t.Cleanup(func() { catalogs["es"] = es })
+
+	if got := T("approve.ready"); got != en["approve.ready"] {
+		t.Errorf("missing translation should fall back to English, got %q", got)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	useLocale(t, "en")
+	if got := Status("pending"); got != "PENDING" {
+		t.Errorf("Status(pending) = %q", got)
+	}
+	if got := Status("weird"); got != "WEIRD" {
+		t.Errorf("Status(weird) = %q", got)
+	}
+
+	SetLocale("es")
+	if got := Status("APPROVED"); got != "APROBADA" {
+		t.Errorf("Status(APPROVED) in es = %q", got)
+	}
+}
+
+func TestCatalogsConsistent(t *testing.T) {
+	for locale, msgs := range catalogs {
+		for key, msg := range msgs {
+			base, ok := en[key]
+			if !ok {
+				t.Errorf("%s: key %q missing from English catalog", locale, key)
+				continue
+			}
+			if strings.Count(msg, "%") != strings.Count(base, "%") {
+				t.Errorf("%s: key %q has mismatched format verbs: %q vs %q", locale, key, msg, base)
+			}
+		}
+	}
+	for key := range en {
+		if _, ok := es[key]; !ok {
+			t.Errorf("es: missing translation for %q", key)
+		}
+	}
+}
@@ -88,9 +88,26 @@ func (c *AgentMailClient) NotifyRequestExecuted(req *db.Request, exec *db.Execut
 	}
 	body := fmt.Sprintf("Request %s executed by %s (%s) at %s\nExit code: %d\nLog: %s\nCommand: `%s`\n",
 		req.ID, byAgent, byModel, execTime, exitCode, logPath, safeDisplay(req))
+	if exec != nil && len(exec.SecretsFound) > 0 {
+		// A leak needs someone to rotate the credential, not just a record.
+		subject = fmt.Sprintf("[SLB] SECRETS IN OUTPUT: %s", truncate(req.Command.Raw, 60))
+		body += fmt.Sprintf("\n**The output contained secrets** (%s). They are redacted from the stored log, but were shown to the executing agent: rotate them.\n",
+			strings.Join(exec.SecretsFound, ", "))
+		return c.send(subject, body, ImportanceUrgent)
+	}
 	return c.send(subject, body, ImportanceLow)
 }
 
+// NotifyBreakglass broadcasts a break-glass execution to all reviewers.
+func (c *AgentMailClient) NotifyBreakglass(inc *db.BreakglassIncident) error {
+	subject := fmt.Sprintf("[SLB] BREAK-GLASS by %s: %s", inc.Actor, truncate(inc.Command, 60))
+	body := fmt.Sprintf("## Break-glass execution (no approval)\n\n**Incident**: %s\n**Actor**: %s\n**Command**: `%s`\n**CWD**: %s\n**Reason**: %s\n**Executed**: %s\n\nA postmortem is due by %s.\nTo acknowledge: `slb breakglass ack %s --postmortem \"...\"`\n",
+		inc.ID, inc.Actor, inc.Command, inc.Cwd, inc.Reason,
+		inc.ExecutedAt.Format(time.RFC3339), inc.AckDueAt.Format(time.RFC3339), inc.ID,
+	)
+	return c.send(subject, body, ImportanceUrgent)
+}
+
 // RequestNotifier defines notification hooks for request lifecycle.
 type RequestNotifier interface {
 	NotifyNewRequest(req *db.Request) error
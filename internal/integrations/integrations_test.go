@@ -353,3 +353,32 @@ func TestAgentMailClient_SendSucceedsWhenCommandSucceeds(t *testing.T) {
 		t.Fatalf("expected nil, got: %v", err)
 	}
 }
+
+func TestAgentMailClient_NotifyRequestExecuted_SecretsFound(t *testing.T) {
+	dir := t.TempDir()
+	bin := filepath.Join(dir, "mcp-agent-mail")
+	argsPath := filepath.Join(dir, "args")
+	script := "#!/bin/sh\nfor a in \"$@\"; do echo \"$a\"; done > " + argsPath + "\n"
+	if err := os.WriteFile(bin, []byte(script), 0755); err != nil {
+		t.Fatalf("write stub: %v", err)
+	}
+	t.Setenv("PATH", dir)
+
+	c := NewAgentMailClient("/proj", "thread", "sender")
+	req := &db.Request{ID: "req-1", Command: db.CommandSpec{Raw: "env"}}
+	exec := &db.Execution{SecretsFound: []string{"aws_access_key_id", "github_token"}}
+	if err := c.NotifyRequestExecuted(req, exec, 0); err != nil {
+		t.Fatalf("NotifyRequestExecuted: %v", err)
+	}
+
+	data, err := os.ReadFile(argsPath)
+	if err != nil {
+		t.Fatalf("read args: %v", err)
+	}
+	args := string(data)
+	for _, want := range []string{"[SLB] SECRETS IN OUTPUT: env", ImportanceUrgent, "aws_access_key_id, github_token"} {
+		if !strings.Contains(args, want) {
+			t.Errorf("send args missing %q:\n%s", want, args)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package integrations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// DefaultLLMReviewTimeout bounds how long request creation waits for a second opinion.
+const DefaultLLMReviewTimeout = 15 * time.Second
+
+// maxLLMReviewResponseBytes caps how much of the endpoint response we read.
+const maxLLMReviewResponseBytes = 64 * 1024
+
+// LLMReviewRequest is the JSON payload POSTed to the configured LLM endpoint.
+// It is built from the request as given: core.RequestCreator hands the
+// advisor a copy with every free-text field redacted, so raw secrets never
+// leave the machine.
+type LLMReviewRequest struct {
+	RequestID      string   `json:"request_id"`
+	Command        string   `json:"command"`
+	Cwd            string   `json:"cwd"`
+	RiskTier       string   `json:"risk_tier"`
+	Requestor      string   `json:"requestor"`
+	RequestorModel string   `json:"requestor_model,omitempty"`
+	Reason         string   `json:"reason"`
+	ExpectedEffect string   `json:"expected_effect,omitempty"`
+	Goal           string   `json:"goal,omitempty"`
+	SafetyArgument string   `json:"safety_argument,omitempty"`
+	DryRunOutput   string   `json:"dry_run_output,omitempty"`
+	Attachments    []string `json:"attachments,omitempty"`
+	Model          string   `json:"model,omitempty"`
+}
+
+// LLMReviewResponse is the JSON document the endpoint is expected to return.
+type LLMReviewResponse struct {
+	RiskSummary    string `json:"risk_summary"`
+	Recommendation string `json:"recommendation"`
+	Model          string `json:"model,omitempty"`
+}
+
+// LLMReviewer asks an external LLM endpoint for an advisory second opinion.
+// Its output is stored as an annotation and never counts as an approval.
+type LLMReviewer struct {
+	endpoint string
+	model    string
+	apiKey   string
+	client   *http.Client
+}
+
+// NewLLMReviewer constructs a reviewer for the given endpoint.
+func NewLLMReviewer(endpoint, model, apiKey string, timeout time.Duration) *LLMReviewer {
+	if timeout <= 0 {
+		timeout = DefaultLLMReviewTimeout
+	}
+	return &LLMReviewer{
+		endpoint: endpoint,
+		model:    model,
+		apiKey:   apiKey,
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+// Review sends the request to the endpoint and returns an advisory annotation.
+func (r *LLMReviewer) Review(ctx context.Context, req *db.Request) (*db.RequestAnnotation, error) {
+	if r == nil || r.endpoint == "" {
+		return nil, fmt.Errorf("llm review endpoint not configured")
+	}
+	if req == nil {
+		return nil, fmt.Errorf("request is required")
+	}
+
+	payload := LLMReviewRequest{
+		RequestID:      req.ID,
+		Command:        safeDisplay(req),
+		Cwd:            req.Command.Cwd,
+		RiskTier:       string(req.RiskTier),
+		Requestor:      req.RequestorAgent,
+		RequestorModel: req.RequestorModel,
+		Reason:         req.Justification.Reason,
+		ExpectedEffect: req.Justification.ExpectedEffect,
+		Goal:           req.Justification.Goal,
+		SafetyArgument: req.Justification.SafetyArgument,
+		Model:          r.model,
+	}
+	if req.DryRun != nil {
+		payload.DryRunOutput = truncate(req.DryRun.Output, 4000)
+	}
+	for _, a := range req.Attachments {
+		payload.Attachments = append(payload.Attachments, string(a.Type))
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling llm review payload: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("creating llm review request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", "SLB-LLMReview/1.0")
+	if r.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+r.apiKey)
+	}
+
+	resp, err := r.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("sending llm review request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("llm review endpoint returned status %d", resp.StatusCode)
+	}
+
+	var parsed LLMReviewResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxLLMReviewResponseBytes)).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("decoding llm review response: %w", err)
+	}
+	summary := strings.TrimSpace(parsed.RiskSummary)
+	if summary == "" {
+		return nil, fmt.Errorf("llm review response missing risk_summary")
+	}
+
+	author := strings.TrimSpace(parsed.Model)
+	if author == "" {
+		author = r.model
+	}
+
+	return &db.RequestAnnotation{
+		RequestID:      req.ID,
+		Source:         db.AnnotationSourceLLM,
+		Author:         author,
+		RiskSummary:    summary,
+		Recommendation: db.NormalizeRecommendation(parsed.Recommendation),
+	}, nil
+}
@@ -0,0 +1,91 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+func testLLMRequest() *db.Request {
+	return &db.Request{
+		ID:             "req-llm",
+		RiskTier:       db.RiskTierDangerous,
+		RequestorAgent: "BlueLake",
+		RequestorModel: "model-a",
+		Command: db.CommandSpec{
+			Raw:             "curl -H 'token=abc123' https://example.com",
+			DisplayRedacted: "curl -H '[REDACTED]' https://example.com",
+			Cwd:             "/tmp",
+		},
+		Justification: db.Justification{Reason: "fetch data"},
+	}
+}
+
+func TestLLMReviewer_Review(t *testing.T) {
+	var got LLMReviewRequest
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		_ = json.NewEncoder(w).Encode(LLMReviewResponse{
+			RiskSummary:    "Network call only",
+			Recommendation: "Approve",
+		})
+	}))
+	defer srv.Close()
+
+	r := NewLLMReviewer(srv.URL, "reviewer-model", "secret", time.Second)
+	ann, err := r.Review(context.Background(), testLLMRequest())
+	if err != nil {
+		t.Fatalf("Review: %v", err)
+	}
+
+	if strings.Contains(got.Command, "abc123") {
+		t.Fatalf("raw secret leaked to endpoint: %q", got.Command)
+	}
+	if got.Model != "reviewer-model" || got.RiskTier != "dangerous" {
+		t.Fatalf("unexpected payload: %+v", got)
+	}
+	if auth != "Bearer secret" {
+		t.Fatalf("expected bearer auth, got %q", auth)
+	}
+	if ann.RequestID != "req-llm" || ann.Source != db.AnnotationSourceLLM {
+		t.Fatalf("unexpected annotation: %+v", ann)
+	}
+	if ann.Recommendation != db.RecommendationApprove {
+		t.Fatalf("expected approve, got %q", ann.Recommendation)
+	}
+	if ann.Author != "reviewer-model" {
+		t.Fatalf("expected author fallback to configured model, got %q", ann.Author)
+	}
+}
+
+func TestLLMReviewer_Errors(t *testing.T) {
+	if _, err := (*LLMReviewer)(nil).Review(context.Background(), testLLMRequest()); err == nil {
+		t.Fatalf("expected error for nil reviewer")
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	if _, err := NewLLMReviewer(failing.URL, "", "", 0).Review(context.Background(), testLLMRequest()); err == nil {
+		t.Fatalf("expected error for non-2xx status")
+	}
+
+	empty := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"recommendation":"reject"}`))
+	}))
+	defer empty.Close()
+	if _, err := NewLLMReviewer(empty.URL, "", "", 0).Review(context.Background(), testLLMRequest()); err == nil {
+		t.Fatalf("expected error for missing risk_summary")
+	}
+}
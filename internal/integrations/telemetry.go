@@ -0,0 +1,230 @@
+package integrations
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// DefaultTelemetryTimeout bounds one telemetry POST.
+const DefaultTelemetryTimeout = 10 * time.Second
+
+// TelemetryFormatVersion is the version of the TelemetryPayload layout.
+const TelemetryFormatVersion = 1
+
+// telemetryFirstLookback is how far back the first report reaches.
+const telemetryFirstLookback = 7 * 24 * time.Hour
+
+// Telemetry outcome buckets. Statuses are folded so that reports do not
+// depend on the state machine's internal names.
+const (
+	TelemetryOutcomeApproved  = "approved"
+	TelemetryOutcomeRejected  = "rejected"
+	TelemetryOutcomeTimeout   = "timeout"
+	TelemetryOutcomeCancelled = "cancelled"
+)
+
+// TelemetryPayload is the JSON document POSTed to the telemetry endpoint.
+// It identifies patterns only by hash and carries no commands, paths,
+// projects, agent names or request IDs.
+type TelemetryPayload struct {
+	Format      int                `json:"format"`
+	PeriodStart time.Time          `json:"period_start"`
+	PeriodEnd   time.Time          `json:"period_end"`
+	Patterns    []TelemetryPattern `json:"patterns"`
+}
+
+// TelemetryPattern is the decision tally for one pattern.
+type TelemetryPattern struct {
+	// PatternHash is the hex SHA-256 of the pattern text. Built-in patterns
+	// hash the same everywhere, so maintainers can match them; custom
+	// patterns stay opaque.
+	PatternHash string         `json:"pattern_hash"`
+	Tier        string         `json:"tier"`
+	Outcomes    map[string]int `json:"outcomes"`
+}
+
+// HashPattern returns the identifier telemetry uses for a pattern.
+func HashPattern(pattern string) string {
+	sum := sha256.Sum256([]byte(pattern))
+	return hex.EncodeToString(sum[:])
+}
+
+// telemetryOutcome folds a final request status into an outcome bucket.
+func telemetryOutcome(status db.RequestStatus) (string, bool) {
+	switch status {
+	case db.StatusApproved, db.StatusExecuting, db.StatusExecuted, db.StatusExecutionFailed:
+		return TelemetryOutcomeApproved, true
+	case db.StatusRejected:
+		return TelemetryOutcomeRejected, true
+	case db.StatusTimeout, db.StatusTimedOut:
+		return TelemetryOutcomeTimeout, true
+	case db.StatusCancelled:
+		return TelemetryOutcomeCancelled, true
+	default:
+		return "", false
+	}
+}
+
+// BuildTelemetryPayload tallies decisions for requests resolved in [since, until).
+func BuildTelemetryPayload(database *db.DB, since, until time.Time) (*TelemetryPayload, error) {
+	outcomes, err := database.ListPatternOutcomes(since, until)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := &TelemetryPayload{
+		Format:      TelemetryFormatVersion,
+		PeriodStart: since.UTC(),
+		PeriodEnd:   until.UTC(),
+		Patterns:    []TelemetryPattern{},
+	}
+	index := map[string]int{}
+	for _, o := range outcomes {
+		bucket, ok := telemetryOutcome(o.Status)
+		if !ok {
+			continue
+		}
+		key := string(o.Tier) + "\x00" + o.Pattern
+		i, seen := index[key]
+		if !seen {
+			i = len(payload.Patterns)
+			index[key] = i
+			payload.Patterns = append(payload.Patterns, TelemetryPattern{
+				PatternHash: HashPattern(o.Pattern),
+				Tier:        string(o.Tier),
+				Outcomes:    map[string]int{},
+			})
+		}
+		payload.Patterns[i].Outcomes[bucket] += o.Count
+	}
+	sort.Slice(payload.Patterns, func(i, j int) bool {
+		a, b := payload.Patterns[i], payload.Patterns[j]
+		if a.Tier != b.Tier {
+			return a.Tier < b.Tier
+		}
+		return a.PatternHash < b.PatternHash
+	})
+	return payload, nil
+}
+
+// TelemetryClient posts telemetry payloads to the configured endpoint.
+type TelemetryClient struct {
+	endpoint string
+	client   *http.Client
+}
+
+// NewTelemetryClient constructs a client for the given endpoint.
+func NewTelemetryClient(endpoint string, timeout time.Duration) *TelemetryClient {
+	if timeout <= 0 {
+		timeout = DefaultTelemetryTimeout
+	}
+	return &TelemetryClient{endpoint: endpoint, client: &http.Client{Timeout: timeout}}
+}
+
+// Send posts the payload.
+func (c *TelemetryClient) Send(ctx context.Context, payload *TelemetryPayload) error {
+	if c == nil || c.endpoint == "" {
+		return fmt.Errorf("telemetry endpoint not configured")
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshaling telemetry payload: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating telemetry request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", "SLB-Telemetry/1.0")
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("sending telemetry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("telemetry endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// NextTelemetryPeriodStart returns where the next report starts: the end of
+// the last successful report, or one week before now for the first report.
+func NextTelemetryPeriodStart(database *db.DB, now time.Time) (time.Time, error) {
+	last, err := database.LastTelemetryReport(true)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if last == nil {
+		return now.UTC().Add(-telemetryFirstLookback).Truncate(time.Second), nil
+	}
+	return last.PeriodEnd, nil
+}
+
+// SendTelemetryReport reports everything resolved since the last successful
+// report up to now and logs the attempt. A period with no resolved requests
+// is logged without contacting the endpoint.
+func SendTelemetryReport(ctx context.Context, database *db.DB, client *TelemetryClient, now time.Time) (*db.TelemetryReport, error) {
+	until := now.UTC().Truncate(time.Second)
+	since, err := NextTelemetryPeriodStart(database, until)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := BuildTelemetryPayload(database, since, until)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &db.TelemetryReport{
+		SentAt:      until,
+		PeriodStart: since,
+		PeriodEnd:   until,
+		Endpoint:    client.endpoint,
+		Patterns:    len(payload.Patterns),
+	}
+	var sendErr error
+	if len(payload.Patterns) > 0 {
+		sendErr = client.Send(ctx, payload)
+	}
+	if sendErr != nil {
+		record.Error = sendErr.Error()
+	}
+	if err := database.RecordTelemetryReport(record); err != nil {
+		return nil, err
+	}
+	return record, sendErr
+}
+
+// TelemetryStatus summarizes telemetry for status displays.
+type TelemetryStatus struct {
+	Enabled  bool   `json:"enabled"`
+	Endpoint string `json:"endpoint,omitempty"`
+	// LastAttempt is the most recent send attempt, successful or not.
+	LastAttempt *db.TelemetryReport `json:"last_attempt,omitempty"`
+	// LastSuccess is the most recent successful send.
+	LastSuccess *db.TelemetryReport `json:"last_success,omitempty"`
+}
+
+// GetTelemetryStatus reads the send log for a status display.
+func GetTelemetryStatus(database *db.DB, enabled bool, endpoint string) (*TelemetryStatus, error) {
+	status := &TelemetryStatus{Enabled: enabled, Endpoint: endpoint}
+	var err error
+	if status.LastAttempt, err = database.LastTelemetryReport(false); err != nil {
+		return nil, err
+	}
+	if status.LastSuccess, err = database.LastTelemetryReport(true); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
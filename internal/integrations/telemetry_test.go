@@ -0,0 +1,117 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+// resolveWithPattern creates a request classified by pattern and moves it to status.
+func resolveWithPattern(t *testing.T, database *db.DB, sess *db.Session, pattern string, status db.RequestStatus) {
+	t.Helper()
+	req := testutil.MakeRequest(t, database, sess,
+		testutil.WithCommand("rm -rf /srv/customer-data", "/home/alice/project", true),
+		testutil.WithRisk(db.RiskTierCritical))
+	testutil.RequireNoError(t, database.SetRequestPattern(&db.RequestPattern{
+		RequestID: req.ID, Tier: db.RiskTierCritical, Pattern: pattern,
+	}), "set pattern")
+	if status != db.StatusPending {
+		testutil.RequireNoError(t, database.UpdateRequestStatus(req.ID, status), "update status")
+	}
+}
+
+func TestBuildTelemetryPayload(t *testing.T) {
+	database := testutil.NewTestDB(t)
+	clk := testutil.NewFakeClock(time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC))
+	database.SetClock(clk)
+	sess := testutil.MakeSession(t, database)
+
+	resolveWithPattern(t, database, sess, `^rm\s+-rf\s+/`, db.StatusRejected)
+	resolveWithPattern(t, database, sess, `^rm\s+-rf\s+/`, db.StatusExecuted)
+	resolveWithPattern(t, database, sess, `^rm\s+-rf\s+/`, db.StatusExecutionFailed)
+	resolveWithPattern(t, database, sess, `^rm\s+-rf\s+/`, db.StatusTimedOut)
+	resolveWithPattern(t, database, sess, `^rm\s+-rf\s+/`, db.StatusPending)
+
+	payload, err := BuildTelemetryPayload(database, clk.Now().Add(-time.Hour), clk.Now().Add(time.Hour))
+	testutil.RequireNoError(t, err, "build payload")
+	testutil.RequireLen(t, payload.Patterns, 1, "patterns")
+	p := payload.Patterns[0]
+	testutil.RequireEqual(t, HashPattern(`^rm\s+-rf\s+/`), p.PatternHash, "hash")
+	testutil.RequireEqual(t, "critical", p.Tier, "tier")
+	testutil.RequireEqual(t, 2, p.Outcomes[TelemetryOutcomeApproved], "approved")
+	testutil.RequireEqual(t, 1, p.Outcomes[TelemetryOutcomeRejected], "rejected")
+	testutil.RequireEqual(t, 1, p.Outcomes[TelemetryOutcomeTimeout], "timeout")
+
+	body, err := json.Marshal(payload)
+	testutil.RequireNoError(t, err, "marshal")
+	for _, leak := range []string{"rm -rf", "customer-data", "alice", `rm\\s`, sess.AgentName} {
+		if strings.Contains(string(body), leak) {
+			t.Fatalf("payload leaks %q: %s", leak, body)
+		}
+	}
+}
+
+func TestSendTelemetryReport(t *testing.T) {
+	var posts []TelemetryPayload
+	fail := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		var p TelemetryPayload
+		if err := json.Unmarshal(body, &p); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		posts = append(posts, p)
+	}))
+	defer srv.Close()
+
+	database := testutil.NewTestDB(t)
+	clk := testutil.NewFakeClock(time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC))
+	database.SetClock(clk)
+	sess := testutil.MakeSession(t, database)
+	client := NewTelemetryClient(srv.URL, time.Second)
+
+	// Nothing resolved: logged without contacting the endpoint.
+	report, err := SendTelemetryReport(context.Background(), database, client, clk.Now())
+	testutil.RequireNoError(t, err, "empty send")
+	testutil.RequireEqual(t, 0, report.Patterns, "patterns")
+	testutil.RequireLen(t, posts, 0, "posts")
+	testutil.RequireEqual(t, clk.Now().Add(-telemetryFirstLookback), report.PeriodStart, "first period start")
+
+	clk.Advance(time.Hour)
+	resolveWithPattern(t, database, sess, `^git\s+push\s+--force`, db.StatusRejected)
+	clk.Advance(time.Hour)
+
+	fail = true
+	_, err = SendTelemetryReport(context.Background(), database, client, clk.Now())
+	if err == nil || !strings.Contains(err.Error(), "503") {
+		t.Fatalf("expected a 503 error, got %v", err)
+	}
+
+	// The failed period is retried by the next send.
+	fail = false
+	clk.Advance(time.Hour)
+	report, err = SendTelemetryReport(context.Background(), database, client, clk.Now())
+	testutil.RequireNoError(t, err, "send")
+	testutil.RequireLen(t, posts, 1, "posts")
+	testutil.RequireLen(t, posts[0].Patterns, 1, "posted patterns")
+	testutil.RequireEqual(t, 1, posts[0].Patterns[0].Outcomes[TelemetryOutcomeRejected], "rejected")
+	testutil.RequireEqual(t, clk.Now().Add(-3*time.Hour), report.PeriodStart, "period start")
+
+	status, err := GetTelemetryStatus(database, true, srv.URL)
+	testutil.RequireNoError(t, err, "status")
+	if status.LastSuccess == nil || status.LastAttempt == nil || status.LastSuccess.ID != status.LastAttempt.ID {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+}
@@ -0,0 +1,145 @@
+package keyring
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// runner runs a credential tool with optional stdin and returns its stdout.
+// Tests swap it for a fake.
+type runner func(stdin string, name string, args ...string) (string, error)
+
+// errExit wraps a tool's non-zero exit with its stderr.
+type errExit struct {
+	tool   string
+	code   int
+	stderr string
+}
+
+func (e *errExit) Error() string {
+	if e.stderr != "" {
+		return fmt.Sprintf("%s exited %d: %s", e.tool, e.code, e.stderr)
+	}
+	return fmt.Sprintf("%s exited %d", e.tool, e.code)
+}
+
+func execRunner(stdin string, name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", &errExit{tool: name, code: exitErr.ExitCode(), stderr: strings.TrimSpace(stderr.String())}
+		}
+		return "", fmt.Errorf("running %s: %w", name, err)
+	}
+	return stdout.String(), nil
+}
+
+func hasCommand(name string) bool {
+	_, err := exec.LookPath(name)
+	return err == nil
+}
+
+// exitCode returns the tool's exit code, or -1 when err is not an exit.
+func exitCode(err error) int {
+	var e *errExit
+	if errors.As(err, &e) {
+		return e.code
+	}
+	return -1
+}
+
+// keychainStore uses the macOS `security` tool.
+type keychainStore struct {
+	run runner
+}
+
+func newKeychainStore(run runner) *keychainStore {
+	return &keychainStore{run: run}
+}
+
+// errSecItemNotFound is what `security` exits with for a missing item.
+const errSecItemNotFound = 44
+
+func (s *keychainStore) Name() string { return "macos-keychain" }
+
+func (s *keychainStore) Get(account string) (string, error) {
+	out, err := s.run("", "security", "find-generic-password", "-s", Service, "-a", account, "-w")
+	if exitCode(err) == errSecItemNotFound {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading keychain: %w", err)
+	}
+	return strings.TrimRight(out, "\n"), nil
+}
+
+func (s *keychainStore) Set(account, secret string) error {
+	// -U updates an existing item in place. The secret is an argument
+	// because `security` only reads one from a tty.
+	if _, err := s.run("", "security", "add-generic-password", "-U", "-s", Service, "-a", account, "-w", secret); err != nil {
+		return fmt.Errorf("writing keychain: %w", err)
+	}
+	return nil
+}
+
+func (s *keychainStore) Delete(account string) error {
+	_, err := s.run("", "security", "delete-generic-password", "-s", Service, "-a", account)
+	if err != nil && exitCode(err) != errSecItemNotFound {
+		return fmt.Errorf("deleting from keychain: %w", err)
+	}
+	return nil
+}
+
+// secretServiceStore uses libsecret's `secret-tool`.
+type secretServiceStore struct {
+	run runner
+}
+
+func newSecretServiceStore(run runner) *secretServiceStore {
+	return &secretServiceStore{run: run}
+}
+
+func (s *secretServiceStore) Name() string { return "secret-service" }
+
+func (s *secretServiceStore) Get(account string) (string, error) {
+	out, err := s.run("", "secret-tool", "lookup", "service", Service, "account", account)
+	// secret-tool exits 1 with no output when nothing matches.
+	if exitCode(err) == 1 && out == "" {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading secret service: %w", err)
+	}
+	if out == "" {
+		return "", ErrNotFound
+	}
+	return strings.TrimRight(out, "\n"), nil
+}
+
+func (s *secretServiceStore) Set(account, secret string) error {
+	// The secret goes on stdin so it never shows up in ps.
+	label := fmt.Sprintf("%s: %s", Service, account)
+	if _, err := s.run(secret, "secret-tool", "store", "--label", label, "service", Service, "account", account); err != nil {
+		return fmt.Errorf("writing secret service: %w", err)
+	}
+	return nil
+}
+
+func (s *secretServiceStore) Delete(account string) error {
+	_, err := s.run("", "secret-tool", "clear", "service", Service, "account", account)
+	// clear exits 1 when nothing matched.
+	if err != nil && exitCode(err) != 1 {
+		return fmt.Errorf("deleting from secret service: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,310 @@
+// Package keyring keeps secrets such as session keys and API tokens in the
+// OS credential store: the macOS Keychain, the Secret Service (libsecret)
+// on Linux and BSD, or the Windows Credential Manager. Where none is
+// available it falls back to a file readable only by the user.
+package keyring
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Service names slb's entries in the OS credential store.
+const Service = "slb"
+
+// FileName is the fallback store inside the slb home directory.
+const FileName = "keyring.json"
+
+// ErrNotFound is returned by Get when no secret is stored for an account.
+var ErrNotFound = errors.New("secret not found in keyring")
+
+// ErrUnavailable is returned by Open when ModeKeyring is requested but no
+// OS credential store can be used.
+var ErrUnavailable = errors.New("no OS credential store available")
+
+// Store holds secrets by account name.
+type Store interface {
+	// Name identifies the backend, e.g. "macos-keychain" or "file".
+	Name() string
+	Get(account string) (string, error)
+	Set(account, secret string) error
+	// Delete removes the secret; deleting a missing one is not an error.
+	Delete(account string) error
+}
+
+// Mode selects the backend.
+type Mode string
+
+const (
+	// ModeAuto uses the OS credential store when there is one and the file
+	// otherwise, or when the OS store fails.
+	ModeAuto Mode = "auto"
+	// ModeKeyring uses only the OS credential store.
+	ModeKeyring Mode = "keyring"
+	// ModeFile uses only the file.
+	ModeFile Mode = "file"
+	// ModeOff stores nothing.
+	ModeOff Mode = "off"
+)
+
+// Modes lists the valid modes.
+var Modes = []Mode{ModeAuto, ModeKeyring, ModeFile, ModeOff}
+
+// ParseMode parses a mode name; empty means ModeAuto.
+func ParseMode(s string) (Mode, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return ModeAuto, nil
+	}
+	for _, m := range Modes {
+		if string(m) == s {
+			return m, nil
+		}
+	}
+	return "", fmt.Errorf("invalid keyring mode %q (want auto, keyring, file or off)", s)
+}
+
+// Open returns the store for mode, keeping the file fallback in dir
+// (usually ~/.slb). It returns a nil Store for ModeOff.
+func Open(mode Mode, dir string) (Store, error) {
+	file := NewFileStore(filepath.Join(dir, FileName))
+	switch mode {
+	case ModeOff:
+		return nil, nil
+	case ModeFile:
+		return file, nil
+	case ModeKeyring:
+		if sys := systemStore(); sys != nil {
+			return sys, nil
+		}
+		return nil, ErrUnavailable
+	case ModeAuto, "":
+		if sys := systemStore(); sys != nil {
+			return &fallbackStore{primary: sys, fallback: file}, nil
+		}
+		return file, nil
+	}
+	return nil, fmt.Errorf("invalid keyring mode %q", mode)
+}
+
+// OpenDefault parses mode (general.session_key_store) and opens the store
+// with the file fallback under DefaultDir.
+func OpenDefault(mode string) (Store, error) {
+	m, err := ParseMode(mode)
+	if err != nil {
+		return nil, err
+	}
+	if m == ModeOff {
+		return nil, nil
+	}
+	dir, err := DefaultDir()
+	if err != nil {
+		return nil, err
+	}
+	return Open(m, dir)
+}
+
+// SessionAccount names a session's key in the store.
+func SessionAccount(sessionID string) string { return "session:" + sessionID }
+
+// TokenAccount names an API token in the store.
+func TokenAccount(name string) string { return "token:" + name }
+
+// DefaultDir returns ~/.slb, where the file fallback lives.
+func DefaultDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("locating home directory: %w", err)
+	}
+	return filepath.Join(home, ".slb"), nil
+}
+
+// systemStore returns the platform's credential store, or nil when its
+// tooling is missing.
+func systemStore() Store {
+	switch runtime.GOOS {
+	case "darwin":
+		if hasCommand("security") {
+			return newKeychainStore(execRunner)
+		}
+	case "windows":
+		return newWincredStore()
+	default:
+		// The Secret Service lives on the session bus.
+		if hasCommand("secret-tool") && os.Getenv("DBUS_SESSION_BUS_ADDRESS") != "" {
+			return newSecretServiceStore(execRunner)
+		}
+	}
+	return nil
+}
+
+// fallbackStore writes to the OS store and falls back to the file when it
+// fails, e.g. a locked keyring over SSH. Reads check both.
+type fallbackStore struct {
+	primary, fallback Store
+
+	mu   sync.Mutex
+	used Store
+}
+
+// Name reports the backend that served the last Set, or the OS store.
+func (s *fallbackStore) Name() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.used != nil {
+		return s.used.Name()
+	}
+	return s.primary.Name()
+}
+
+func (s *fallbackStore) Get(account string) (string, error) {
+	secret, err := s.primary.Get(account)
+	if err == nil {
+		return secret, nil
+	}
+	if secret, ferr := s.fallback.Get(account); ferr == nil {
+		return secret, nil
+	}
+	return "", err
+}
+
+func (s *fallbackStore) Set(account, secret string) error {
+	used := s.primary
+	if err := s.primary.Set(account, secret); err != nil {
+		if ferr := s.fallback.Set(account, secret); ferr != nil {
+			return fmt.Errorf("%w (file fallback: %v)", err, ferr)
+		}
+		used = s.fallback
+	} else {
+		// Don't leave an older copy behind in the file.
+		_ = s.fallback.Delete(account)
+	}
+	s.mu.Lock()
+	s.used = used
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *fallbackStore) Delete(account string) error {
+	perr := s.primary.Delete(account)
+	ferr := s.fallback.Delete(account)
+	if perr != nil {
+		return perr
+	}
+	return ferr
+}
+
+// FileStore keeps secrets in a JSON file with mode 0600.
+type FileStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileStore returns a store backed by path. The file is created on the
+// first Set.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Name implements Store.
+func (s *FileStore) Name() string { return "file" }
+
+// Path returns the file the secrets are kept in.
+func (s *FileStore) Path() string { return s.path }
+
+// Get implements Store.
+func (s *FileStore) Get(account string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := s.load()
+	if err != nil {
+		return "", err
+	}
+	secret, ok := entries[account]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+// Set implements Store.
+func (s *FileStore) Set(account, secret string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := s.load()
+	if err != nil {
+		return err
+	}
+	entries[account] = secret
+	return s.save(entries)
+}
+
+// Delete implements Store.
+func (s *FileStore) Delete(account string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := entries[account]; !ok {
+		return nil
+	}
+	delete(entries, account)
+	return s.save(entries)
+}
+
+func (s *FileStore) load() (map[string]string, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return make(map[string]string), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading keyring file: %w", err)
+	}
+	entries := make(map[string]string)
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return nil, fmt.Errorf("parsing keyring file %s: %w", s.path, err)
+		}
+	}
+	return entries, nil
+}
+
+func (s *FileStore) save(entries map[string]string) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("creating keyring directory: %w", err)
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding keyring file: %w", err)
+	}
+	// Write a private temp file and rename it so readers never see a
+	// partial file and the secrets are never world-readable.
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".keyring-*")
+	if err != nil {
+		return fmt.Errorf("writing keyring file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0o600); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("writing keyring file: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("writing keyring file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing keyring file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("writing keyring file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package keyring
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", FileName)
+	s := NewFileStore(path)
+
+	if _, err := s.Get("sess-1"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get on a missing file = %v, want ErrNotFound", err)
+	}
+	if err := s.Delete("sess-1"); err != nil {
+		t.Fatalf("Delete on a missing file: %v", err)
+	}
+	if err := s.Set("sess-1", "key-one"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := s.Set("sess-2", "key-two"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := s.Set("sess-1", "key-one-rotated"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	// A second handle sees the same file.
+	other := NewFileStore(path)
+	if got, err := other.Get("sess-1"); err != nil || got != "key-one-rotated" {
+		t.Errorf("Get(sess-1) = %q, %v", got, err)
+	}
+	if got, err := other.Get("sess-2"); err != nil || got != "key-two" {
+		t.Errorf("Get(sess-2) = %q, %v", got, err)
+	}
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("stat: %v", err)
+		}
+		if perm := info.Mode().Perm(); perm != 0o600 {
+			t.Errorf("file mode = %o, want 600", perm)
+		}
+	}
+
+	if err := s.Delete("sess-1"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.Get("sess-1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete = %v, want ErrNotFound", err)
+	}
+	if got, _ := s.Get("sess-2"); got != "key-two" {
+		t.Errorf("Delete removed the wrong entry, sess-2 = %q", got)
+	}
+}
+
+func TestFileStore_CorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	if err := os.WriteFile(path, []byte("{not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s := NewFileStore(path)
+	if _, err := s.Get("x"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("expected a parse error, got %v", err)
+	}
+	if err := s.Set("x", "y"); err == nil {
+		t.Error("expected Set not to overwrite a corrupt file")
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	for in, want := range map[string]Mode{"": ModeAuto, "auto": ModeAuto, " File ": ModeFile, "keyring": ModeKeyring, "off": ModeOff} {
+		got, err := ParseMode(in)
+		if err != nil || got != want {
+			t.Errorf("ParseMode(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseMode("vault"); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+}
+
+func TestOpen(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(ModeOff, dir)
+	if err != nil || s != nil {
+		t.Errorf("Open(off) = %v, %v; want nil store", s, err)
+	}
+	s, err = Open(ModeFile, dir)
+	if err != nil {
+		t.Fatalf("Open(file): %v", err)
+	}
+	fs, ok := s.(*FileStore)
+	if !ok || fs.Path() != filepath.Join(dir, FileName) {
+		t.Errorf("Open(file) = %#v", s)
+	}
+	if _, err := Open("vault", dir); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+}
+
+// fakeTool records calls and plays back canned results.
+type fakeTool struct {
+	calls  []string
+	stdins []string
+	out    string
+	err    error
+}
+
+func (f *fakeTool) run(stdin string, name string, args ...string) (string, error) {
+	f.calls = append(f.calls, name+" "+strings.Join(args, " "))
+	f.stdins = append(f.stdins, stdin)
+	return f.out, f.err
+}
+
+func TestKeychainStore(t *testing.T) {
+	tool := &fakeTool{out: "secret\n"}
+	s := newKeychainStore(tool.run)
+	got, err := s.Get("sess-1")
+	if err != nil || got != "secret" {
+		t.Errorf("Get = %q, %v", got, err)
+	}
+	if err := s.Set("sess-1", "new"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	want := []string{
+		"security find-generic-password -s slb -a sess-1 -w",
+		"security add-generic-password -U -s slb -a sess-1 -w new",
+	}
+	if strings.Join(tool.calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("calls = %q, want %q", tool.calls, want)
+	}
+
+	tool.err = &errExit{tool: "security", code: errSecItemNotFound}
+	if _, err := s.Get("sess-1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get of a missing item = %v, want ErrNotFound", err)
+	}
+	if err := s.Delete("sess-1"); err != nil {
+		t.Errorf("Delete of a missing item: %v", err)
+	}
+	tool.err = &errExit{tool: "security", code: 51, stderr: "user interaction is not allowed"}
+	if err := s.Set("sess-1", "x"); err == nil || !strings.Contains(err.Error(), "interaction") {
+		t.Errorf("Set error = %v", err)
+	}
+}
+
+func TestSecretServiceStore(t *testing.T) {
+	tool := &fakeTool{}
+	s := newSecretServiceStore(tool.run)
+	if err := s.Set("sess-1", "hunter2"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if strings.Contains(tool.calls[0], "hunter2") || tool.stdins[0] != "hunter2" {
+		t.Errorf("expected the secret on stdin only, call %q stdin %q", tool.calls[0], tool.stdins[0])
+	}
+	if tool.calls[0] != "secret-tool store --label slb: sess-1 service slb account sess-1" {
+		t.Errorf("store call = %q", tool.calls[0])
+	}
+
+	tool.out = "hunter2"
+	if got, err := s.Get("sess-1"); err != nil || got != "hunter2" {
+		t.Errorf("Get = %q, %v", got, err)
+	}
+	tool.out, tool.err = "", &errExit{tool: "secret-tool", code: 1}
+	if _, err := s.Get("sess-1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get of a missing item = %v, want ErrNotFound", err)
+	}
+	if err := s.Delete("sess-1"); err != nil {
+		t.Errorf("Delete of a missing item: %v", err)
+	}
+}
+
+func TestFallbackStore(t *testing.T) {
+	broken := newSecretServiceStore((&fakeTool{err: &errExit{tool: "secret-tool", code: 2, stderr: "cannot unlock"}}).run)
+	file := NewFileStore(filepath.Join(t.TempDir(), FileName))
+	s := &fallbackStore{primary: broken, fallback: file}
+
+	if s.Name() != "secret-service" {
+		t.Errorf("Name before any Set = %q", s.Name())
+	}
+	if err := s.Set("sess-1", "k"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if s.Name() != "file" {
+		t.Errorf("Name after falling back = %q, want file", s.Name())
+	}
+	if got, err := s.Get("sess-1"); err != nil || got != "k" {
+		t.Errorf("Get = %q, %v", got, err)
+	}
+
+	// A working OS store takes over and clears the file copy.
+	working := &fakeTool{}
+	s.primary = newSecretServiceStore(working.run)
+	if err := s.Set("sess-1", "k2"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if s.Name() != "secret-service" {
+		t.Errorf("Name = %q, want secret-service", s.Name())
+	}
+	if _, err := file.Get("sess-1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected the file copy to be removed, got %v", err)
+	}
+}
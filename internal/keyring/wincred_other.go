@@ -0,0 +1,6 @@
+//go:build !windows
+
+package keyring
+
+// newWincredStore is only reachable on Windows.
+func newWincredStore() Store { return nil }
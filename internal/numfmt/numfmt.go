@@ -0,0 +1,153 @@
+// Package numfmt renders counts, durations and sizes for people: counts
+// with the active locale's thousands separator ("12,345"), durations in
+// their largest units ("2h 03m") and sizes in binary units ("1.5 MiB").
+//
+// Raw mode, set by the CLI's --raw flag, turns the formatting off so text
+// output can be parsed: plain integers, Go durations ("2h3m4s") and byte
+// counts. Like the i18n locale and the timefmt style, it is process-wide.
+package numfmt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/i18n"
+)
+
+var raw atomic.Bool
+
+// SetRaw turns raw mode on or off.
+func SetRaw(on bool) {
+	raw.Store(on)
+}
+
+// Raw reports whether raw mode is on.
+func Raw() bool {
+	return raw.Load()
+}
+
+// separators are a locale's digit grouping and decimal marks. Spanish, like
+// CLDR, leaves four-digit numbers ungrouped.
+type separators struct {
+	group       string
+	decimal     string
+	minGrouping int
+}
+
+var localeSeparators = map[string]separators{
+	"en": {group: ",", decimal: ".", minGrouping: 1},
+	"es": {group: ".", decimal: ",", minGrouping: 2},
+}
+
+func current() separators {
+	if s, ok := localeSeparators[i18n.Locale()]; ok {
+		return s
+	}
+	return localeSeparators[i18n.DefaultLocale]
+}
+
+// Count renders n with thousands separators, e.g. "12,345" (or "12.345" in
+// Spanish).
+func Count[T ~int | ~int64](n T) string {
+	s := strconv.FormatInt(int64(n), 10)
+	if Raw() {
+		return s
+	}
+	return group(s, current())
+}
+
+// Decimal renders f with prec digits after the locale's decimal mark and
+// grouped integer digits, e.g. "1,234.5".
+func Decimal(f float64, prec int) string {
+	s := strconv.FormatFloat(f, 'f', prec, 64)
+	if Raw() {
+		return s
+	}
+	seps := current()
+	whole, frac, hasFrac := strings.Cut(s, ".")
+	whole = group(whole, seps)
+	if !hasFrac {
+		return whole
+	}
+	return whole + seps.decimal + frac
+}
+
+// Percent renders f, already a percentage, with one decimal, e.g. "12.5%".
+func Percent(f float64) string {
+	return Decimal(f, 1) + "%"
+}
+
+// group inserts sep.group every three digits of the integer in s.
+func group(s string, sep separators) string {
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	if len(s) < 3+sep.minGrouping {
+		return sign + s
+	}
+	var b strings.Builder
+	head := len(s) % 3
+	if head > 0 {
+		b.WriteString(s[:head])
+	}
+	for i := head; i < len(s); i += 3 {
+		if b.Len() > 0 {
+			b.WriteString(sep.group)
+		}
+		b.WriteString(s[i : i+3])
+	}
+	return sign + b.String()
+}
+
+// Duration renders d in its two largest units: "850ms", "12.3s", "4m 05s",
+// "2h 03m" or "3d 04h". In raw mode it is Go's exact form, which
+// time.ParseDuration reads back.
+func Duration(d time.Duration) string {
+	if Raw() {
+		return d.String()
+	}
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	var s string
+	switch {
+	case d < time.Second:
+		s = fmt.Sprintf("%dms", d.Milliseconds())
+	case d < time.Minute:
+		s = Decimal(d.Seconds(), 1) + "s"
+	case d < time.Hour:
+		s = fmt.Sprintf("%dm %02ds", int(d.Minutes()), int(d.Seconds())%60)
+	case d < 24*time.Hour:
+		s = fmt.Sprintf("%dh %02dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		s = fmt.Sprintf("%sd %02dh", Count(int64(d.Hours()/24)), int(d.Hours())%24)
+	}
+	return sign + s
+}
+
+// Millis renders a duration given in milliseconds, as stored for executions.
+func Millis(ms int64) string {
+	return Duration(time.Duration(ms) * time.Millisecond)
+}
+
+var byteUnits = []string{"KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+
+// Bytes renders a size in binary units with one decimal, e.g. "512 B" or
+// "1.5 MiB". In raw mode it is the exact byte count, e.g. "1572864 B".
+func Bytes(n int64) string {
+	if Raw() || n < 1024 && n > -1024 {
+		return strconv.FormatInt(n, 10) + " B"
+	}
+	f := float64(n)
+	unit := -1
+	for (f >= 1024 || f <= -1024) && unit < len(byteUnits)-1 {
+		f /= 1024
+		unit++
+	}
+	return Decimal(f, 1) + " " + byteUnits[unit]
+}
@@ -0,0 +1,100 @@
+package numfmt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/i18n"
+)
+
+func withLocale(t *testing.T, locale string) {
+	t.Helper()
+	prev := i18n.Locale()
+	i18n.SetLocale(locale)
+	t.Cleanup(func() { i18n.SetLocale(prev) })
+}
+
+func withRaw(t *testing.T) {
+	t.Helper()
+	SetRaw(true)
+	t.Cleanup(func() { SetRaw(false) })
+}
+
+func TestCount(t *testing.T) {
+	withLocale(t, "en")
+	for n, want := range map[int64]string{
+		0: "0", 999: "999", 1000: "1,000", 12345: "12,345",
+		1234567: "1,234,567", -9876543: "-9,876,543",
+	} {
+		if got := Count(n); got != want {
+			t.Errorf("Count(%d) = %q, want %q", n, got, want)
+		}
+	}
+
+	withLocale(t, "es")
+	for n, want := range map[int]string{1234: "1234", 12345: "12.345", 1234567: "1.234.567"} {
+		if got := Count(n); got != want {
+			t.Errorf("es Count(%d) = %q, want %q", n, got, want)
+		}
+	}
+	if got := Decimal(12345.678, 2); got != "12.345,68" {
+		t.Errorf("es Decimal = %q", got)
+	}
+
+	withRaw(t)
+	if got := Count(1234567); got != "1234567" {
+		t.Errorf("raw Count = %q", got)
+	}
+}
+
+func TestDuration(t *testing.T) {
+	withLocale(t, "en")
+	for d, want := range map[time.Duration]string{
+		850 * time.Millisecond:                    "850ms",
+		12300 * time.Millisecond:                  "12.3s",
+		4*time.Minute + 5*time.Second:             "4m 05s",
+		2*time.Hour + 3*time.Minute + time.Second: "2h 03m",
+		1200*time.Hour + 4*time.Hour:              "50d 04h",
+		-90 * time.Second:                         "-1m 30s",
+	} {
+		if got := Duration(d); got != want {
+			t.Errorf("Duration(%v) = %q, want %q", d, got, want)
+		}
+	}
+	if got := Millis(1500); got != "1.5s" {
+		t.Errorf("Millis(1500) = %q", got)
+	}
+
+	withRaw(t)
+	d := 2*time.Hour + 3*time.Minute + 4*time.Second
+	if got := Duration(d); got != "2h3m4s" {
+		t.Errorf("raw Duration = %q", got)
+	}
+	if back, err := time.ParseDuration(Duration(d)); err != nil || back != d {
+		t.Errorf("raw Duration does not round-trip: %v, %v", back, err)
+	}
+}
+
+func TestBytes(t *testing.T) {
+	withLocale(t, "en")
+	for n, want := range map[int64]string{
+		0: "0 B", 1023: "1023 B", 1536: "1.5 KiB", 3 << 20: "3.0 MiB",
+		5 << 40: "5.0 TiB",
+	} {
+		if got := Bytes(n); got != want {
+			t.Errorf("Bytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+
+	withRaw(t)
+	if got := Bytes(3 << 20); got != "3145728 B" {
+		t.Errorf("raw Bytes = %q", got)
+	}
+}
+
+func TestPercent(t *testing.T) {
+	withLocale(t, "es")
+	if got := Percent(12.345); got != "12,3%" {
+		t.Errorf("es Percent = %q", got)
+	}
+}
@@ -8,8 +8,12 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"reflect"
 
 	"go.yaml.in/yaml/v3"
+
+	"github.com/Dicklesworthstone/slb/internal/numfmt"
+	"github.com/Dicklesworthstone/slb/internal/utils"
 )
 
 // Format represents the output format.
@@ -20,6 +24,13 @@ const (
 	FormatJSON Format = "json"
 	FormatYAML Format = "yaml"
 	FormatTOON Format = "toon"
+	// FormatNDJSON writes one compact JSON value per line. Slices are split
+	// into one line per element so consumers can parse records as they
+	// arrive and tolerate truncated output.
+	FormatNDJSON Format = "ndjson"
+	// FormatPorcelain writes stable, tab-separated lines for scripts; see
+	// writePorcelain for the layout.
+	FormatPorcelain Format = "porcelain"
 )
 
 // Writer handles formatted output.
@@ -71,7 +82,7 @@ func New(format Format, opts ...Option) *Writer {
 func (w *Writer) Write(data any) error {
 	// Pre-compute JSON for stats if needed
 	var jsonBytes []byte
-	if w.showStats {
+	if w.showStats && !Quiet() {
 		var err error
 		jsonBytes, err = json.Marshal(data)
 		if err == nil {
@@ -99,16 +110,39 @@ func (w *Writer) Write(data any) error {
 		_, err = w.out.Write(b)
 		return err
 	case FormatText:
+		if Quiet() {
+			return nil
+		}
 		// Human-friendly output goes to stderr to keep stdout clean for piping.
 		_, err := fmt.Fprintf(w.errOut, "%v\n", data)
 		return err
 	case FormatTOON:
 		return w.writeTOON(data)
+	case FormatNDJSON:
+		return w.writeLines(data)
+	case FormatPorcelain:
+		return writePorcelain(w.out, data)
 	default:
 		return fmt.Errorf("unsupported format: %s", w.format)
 	}
 }
 
+// writeLines encodes data as NDJSON, one line per element for slices and
+// arrays and a single line otherwise.
+func (w *Writer) writeLines(data any) error {
+	enc := json.NewEncoder(w.out)
+	v := reflect.ValueOf(data)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array || v.Type().Elem().Kind() == reflect.Uint8 {
+		return enc.Encode(data)
+	}
+	for i := 0; i < v.Len(); i++ {
+		if err := enc.Encode(v.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // printStats outputs token savings comparison to stderr.
 func (w *Writer) printStats(jsonBytes []byte) {
 	jsonSize := len(jsonBytes)
@@ -117,7 +151,7 @@ func (w *Writer) printStats(jsonBytes []byte) {
 		// For TOON mode, show actual savings
 		toonStr, err := EncodeTOON(json.RawMessage(jsonBytes))
 		if err != nil {
-			fmt.Fprintf(w.errOut, "[slb-toon] JSON: %d bytes (TOON encoding failed)\n", jsonSize)
+			fmt.Fprintf(w.errOut, "[slb-toon] JSON: %s (TOON encoding failed)\n", numfmt.Bytes(int64(jsonSize)))
 			return
 		}
 		toonSize := len(toonStr)
@@ -125,16 +159,16 @@ func (w *Writer) printStats(jsonBytes []byte) {
 		if jsonSize > 0 {
 			savings = 100 - (toonSize * 100 / jsonSize)
 		}
-		fmt.Fprintf(w.errOut, "[slb-toon] JSON: %d bytes, TOON: %d bytes (%d%% savings)\n", jsonSize, toonSize, savings)
+		fmt.Fprintf(w.errOut, "[slb-toon] JSON: %s, TOON: %s (%d%% savings)\n", numfmt.Bytes(int64(jsonSize)), numfmt.Bytes(int64(toonSize)), savings)
 	} else {
 		// For JSON/YAML mode, show potential TOON savings
 		if !TOONAvailable() {
-			fmt.Fprintf(w.errOut, "[slb-toon] JSON: %d bytes (TOON unavailable for comparison)\n", jsonSize)
+			fmt.Fprintf(w.errOut, "[slb-toon] JSON: %s (TOON unavailable for comparison)\n", numfmt.Bytes(int64(jsonSize)))
 			return
 		}
 		toonStr, err := EncodeTOON(json.RawMessage(jsonBytes))
 		if err != nil {
-			fmt.Fprintf(w.errOut, "[slb-toon] JSON: %d bytes (TOON unavailable for comparison)\n", jsonSize)
+			fmt.Fprintf(w.errOut, "[slb-toon] JSON: %s (TOON unavailable for comparison)\n", numfmt.Bytes(int64(jsonSize)))
 			return
 		}
 		toonSize := len(toonStr)
@@ -142,17 +176,23 @@ func (w *Writer) printStats(jsonBytes []byte) {
 		if jsonSize > 0 {
 			savings = 100 - (toonSize * 100 / jsonSize)
 		}
-		fmt.Fprintf(w.errOut, "[slb-toon] JSON: %d bytes, TOON would be: %d bytes (%d%% potential savings)\n", jsonSize, toonSize, savings)
+		fmt.Fprintf(w.errOut, "[slb-toon] JSON: %s, TOON would be: %s (%d%% potential savings)\n", numfmt.Bytes(int64(jsonSize)), numfmt.Bytes(int64(toonSize)), savings)
 	}
 }
 
-// WriteNDJSON outputs data as NDJSON when in JSON mode (one JSON per line).
+// WriteNDJSON outputs data as a single NDJSON line when in JSON or NDJSON
+// mode. Unlike Write in NDJSON mode, slices are not split.
 func (w *Writer) WriteNDJSON(data any) error {
 	switch w.format {
-	case FormatJSON:
+	case FormatJSON, FormatNDJSON:
 		enc := json.NewEncoder(w.out)
 		return enc.Encode(data)
+	case FormatPorcelain:
+		return writePorcelain(w.out, data)
 	case FormatText:
+		if Quiet() {
+			return nil
+		}
 		_, err := fmt.Fprintf(w.errOut, "%v\n", data)
 		return err
 	default:
@@ -162,10 +202,10 @@ func (w *Writer) WriteNDJSON(data any) error {
 
 // Success outputs a success message.
 func (w *Writer) Success(msg string) {
-	if w.format == FormatJSON || w.format == FormatYAML || w.format == FormatTOON {
+	if w.format == FormatJSON || w.format == FormatNDJSON || w.format == FormatYAML || w.format == FormatTOON || w.format == FormatPorcelain {
 		_ = w.Write(map[string]any{"status": "success", "message": msg})
-	} else {
-		fmt.Fprintf(w.errOut, "✓ %s\n", msg)
+	} else if !Quiet() {
+		fmt.Fprintf(w.errOut, "%s %s\n", utils.Glyph("✓", "[OK]"), msg)
 	}
 }
 
@@ -178,13 +218,16 @@ func (w *Writer) Error(err error) {
 	}
 	if w.format == FormatJSON {
 		_ = OutputJSONError(err, 1)
-	} else if w.format == FormatTOON {
-		// Use TOON encoding for error output
+	} else if w.format == FormatTOON || w.format == FormatNDJSON {
+		// Use the configured encoding for error output
 		_ = w.Write(payload)
+	} else if w.format == FormatPorcelain {
+		// Keep stdout to records; the error goes to stderr, still parseable.
+		_ = writePorcelain(w.errOut, payload)
 	} else if w.format == FormatYAML {
 		_ = OutputYAML(payload)
 	} else {
-		fmt.Fprintf(w.errOut, "✗ %s\n", err.Error())
+		fmt.Fprintf(w.errOut, "%s %s\n", utils.Glyph("✗", "[ERROR]"), err.Error())
 	}
 }
 
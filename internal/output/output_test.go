@@ -11,6 +11,7 @@ import (
 	"sync/atomic"
 	"testing"
 
+	"github.com/Dicklesworthstone/slb/internal/utils"
 	"go.yaml.in/yaml/v3"
 )
 
@@ -136,6 +137,40 @@ func TestWriter_Write_UnsupportedFormat(t *testing.T) {
 	}
 }
 
+func TestWriter_Write_NDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	w := New(FormatNDJSON, WithOutput(&buf))
+
+	if err := w.Write(map[string]any{"a": 1}); err != nil {
+		t.Fatalf("Write map: %v", err)
+	}
+	if err := w.Write([]map[string]any{{"id": "r1"}, {"id": "r2"}}); err != nil {
+		t.Fatalf("Write slice: %v", err)
+	}
+	if err := w.Write([]string{}); err != nil {
+		t.Fatalf("Write empty slice: %v", err)
+	}
+	if err := w.Write([]byte("raw")); err != nil {
+		t.Fatalf("Write bytes: %v", err)
+	}
+
+	want := "{\"a\":1}\n{\"id\":\"r1\"}\n{\"id\":\"r2\"}\n\"cmF3\"\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("unexpected NDJSON output:\n got: %q\nwant: %q", got, want)
+	}
+}
+
+func TestWriter_WriteNDJSON_NDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	w := New(FormatNDJSON, WithOutput(&buf))
+	if err := w.WriteNDJSON([]int{1, 2}); err != nil {
+		t.Fatalf("WriteNDJSON: %v", err)
+	}
+	if got := buf.String(); got != "[1,2]\n" {
+		t.Fatalf("expected the slice on one line, got %q", got)
+	}
+}
+
 func TestWriter_WriteNDJSON_JSON(t *testing.T) {
 	out := captureStdout(t, func() {
 		w := New(FormatJSON)
@@ -308,3 +343,19 @@ func TestOutputTableAndList(t *testing.T) {
 		t.Fatalf("unexpected list output: %q", listOut)
 	}
 }
+
+func TestWriter_Success_TextPlain(t *testing.T) {
+	prev := utils.PlainOutput()
+	utils.SetPlainOutput(true)
+	t.Cleanup(func() { utils.SetPlainOutput(prev) })
+
+	w := New(FormatText)
+	var buf bytes.Buffer
+	w.errOut = &buf
+
+	w.Success("ok")
+
+	if got := buf.String(); got != "[OK] ok\n" {
+		t.Fatalf("unexpected output: %q", got)
+	}
+}
@@ -0,0 +1,346 @@
+package output
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// writePorcelain renders data as tab-separated text for scripts, as stable
+// across releases as the JSON field names it is derived from:
+//
+//   - A list prints one line per element. Elements that are objects print
+//     one column per field, in the order of the command's JSON output
+//     (struct declaration order, or sorted map keys); absent fields are
+//     empty columns, so positions never shift.
+//   - An object prints "key<TAB>value" lines, with nested objects as
+//     dotted keys ("outcomes.total"). A list inside it prints its rows
+//     with the key as the first column ("tasks<TAB>...").
+//   - Tabs, newlines, carriage returns and backslashes in values are
+//     escaped as \t, \n, \r and \\. Nested values inside a row are compact
+//     JSON. There is no header line and no decoration.
+func writePorcelain(out io.Writer, data any) error {
+	p := &porcelainPrinter{out: out}
+	v := indirect(reflect.ValueOf(data))
+	switch {
+	case !v.IsValid():
+		return nil
+	case isPorcelainList(v):
+		p.rows(nil, v)
+	case isPorcelainObject(v):
+		p.object("", v)
+	default:
+		p.line(p.scalar(v))
+	}
+	return p.err
+}
+
+type porcelainPrinter struct {
+	out io.Writer
+	err error
+}
+
+func (p *porcelainPrinter) line(fields ...string) {
+	if p.err != nil {
+		return
+	}
+	_, p.err = io.WriteString(p.out, strings.Join(fields, "\t")+"\n")
+}
+
+// object prints the fields of a struct or map as key/value lines. Like
+// JSON, it leaves out omitempty fields that are empty.
+func (p *porcelainPrinter) object(prefix string, v reflect.Value) {
+	for _, f := range porcelainFields(v, true) {
+		key := prefix + f.name
+		fv := indirect(f.value)
+		switch {
+		case !fv.IsValid():
+			p.line(key, "")
+		case isPorcelainList(fv):
+			p.rows([]string{key}, fv)
+		case isPorcelainObject(fv):
+			p.object(key+".", fv)
+		default:
+			p.line(key, p.scalar(fv))
+		}
+	}
+}
+
+// rows prints one line per element of a slice, each starting with lead.
+func (p *porcelainPrinter) rows(lead []string, v reflect.Value) {
+	columns := porcelainColumns(v)
+	for i := 0; i < v.Len(); i++ {
+		elem := indirect(v.Index(i))
+		fields := append([]string(nil), lead...)
+		if columns == nil || !isPorcelainObject(elem) {
+			p.line(append(fields, p.cell(elem))...)
+			continue
+		}
+		values := make(map[string]reflect.Value)
+		for _, f := range porcelainFields(elem, false) {
+			values[f.name] = f.value
+		}
+		for _, name := range columns {
+			fields = append(fields, p.cell(indirect(values[name])))
+		}
+		p.line(fields...)
+	}
+}
+
+// cell renders a value inside a row: scalars as text, anything nested as
+// compact JSON.
+func (p *porcelainPrinter) cell(v reflect.Value) string {
+	if !v.IsValid() {
+		return ""
+	}
+	if (v.Kind() == reflect.Map || v.Kind() == reflect.Slice) && v.IsNil() {
+		return ""
+	}
+	if isPorcelainList(v) || isPorcelainObject(v) {
+		b, err := json.Marshal(v.Interface())
+		if err != nil {
+			p.fail(err)
+			return ""
+		}
+		return escapePorcelain(string(b))
+	}
+	return p.scalar(v)
+}
+
+func (p *porcelainPrinter) scalar(v reflect.Value) string {
+	if !v.IsValid() {
+		return ""
+	}
+	if marshalsItself(v) {
+		b, err := json.Marshal(v.Interface())
+		if err != nil {
+			p.fail(err)
+			return ""
+		}
+		var s string
+		if json.Unmarshal(b, &s) == nil {
+			return escapePorcelain(s)
+		}
+		if string(b) == "null" {
+			return ""
+		}
+		return escapePorcelain(string(b))
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return escapePorcelain(v.String())
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	default:
+		return escapePorcelain(fmt.Sprint(v.Interface()))
+	}
+}
+
+func (p *porcelainPrinter) fail(err error) {
+	if p.err == nil {
+		p.err = err
+	}
+}
+
+var porcelainEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+func escapePorcelain(s string) string {
+	return porcelainEscaper.Replace(s)
+}
+
+// indirect follows pointers and interfaces; it returns the zero Value for
+// nil.
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		if v.Kind() == reflect.Pointer && marshalsItself(v) {
+			return v
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+var (
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// marshalsItself reports whether v controls its own JSON form, like
+// time.Time; porcelain prints such values as scalars.
+func marshalsItself(v reflect.Value) bool {
+	t := v.Type()
+	return t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType)
+}
+
+func isPorcelainList(v reflect.Value) bool {
+	if marshalsItself(v) {
+		return false
+	}
+	switch v.Kind() {
+	case reflect.Slice:
+		return v.Type().Elem().Kind() != reflect.Uint8
+	case reflect.Array:
+		return true
+	}
+	return false
+}
+
+func isPorcelainObject(v reflect.Value) bool {
+	if marshalsItself(v) {
+		return false
+	}
+	return v.Kind() == reflect.Struct || v.Kind() == reflect.Map
+}
+
+type porcelainField struct {
+	name  string
+	value reflect.Value
+}
+
+// porcelainFields lists the fields of a struct or map under their JSON
+// names, in JSON order. With omitEmpty, omitempty fields that are empty
+// are left out.
+func porcelainFields(v reflect.Value, omitEmpty bool) []porcelainField {
+	var fields []porcelainField
+	if v.Kind() == reflect.Map {
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+		for _, k := range keys {
+			fields = append(fields, porcelainField{name: fmt.Sprint(k), value: v.MapIndex(k)})
+		}
+		return fields
+	}
+	for _, sf := range structFields(v.Type()) {
+		fv, err := v.FieldByIndexErr(sf.index)
+		if err != nil {
+			continue // field of a nil embedded pointer
+		}
+		if omitEmpty && sf.omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+		fields = append(fields, porcelainField{name: sf.name, value: fv})
+	}
+	return fields
+}
+
+type structField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+// structFields returns the JSON-visible fields of t in declaration order,
+// flattening embedded structs without a JSON name as encoding/json does.
+func structFields(t reflect.Type) []structField {
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		ft := sf.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			for _, inner := range structFields(ft) {
+				inner.index = append([]int{i}, inner.index...)
+				fields = append(fields, inner)
+			}
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		fields = append(fields, structField{
+			name:      name,
+			index:     []int{i},
+			omitEmpty: strings.Contains(","+opts+",", ",omitempty,"),
+		})
+	}
+	return fields
+}
+
+// porcelainColumns returns the row columns for the elements of a list, or
+// nil when they are not objects: struct elements give their fields, maps
+// the sorted union of their keys.
+func porcelainColumns(v reflect.Value) []string {
+	et := v.Type().Elem()
+	for et.Kind() == reflect.Pointer {
+		et = et.Elem()
+	}
+	if et.Kind() == reflect.Interface && v.Len() > 0 {
+		// Lists of any take their shape from the first element.
+		if first := indirect(v.Index(0)); first.IsValid() {
+			et = first.Type()
+		}
+	}
+	if et.Kind() == reflect.Struct {
+		if marshalsItself(reflect.New(et).Elem()) {
+			return nil
+		}
+		var columns []string
+		for _, f := range structFields(et) {
+			columns = append(columns, f.name)
+		}
+		return columns
+	}
+	if et.Kind() != reflect.Map {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var columns []string
+	for i := 0; i < v.Len(); i++ {
+		elem := indirect(v.Index(i))
+		if !elem.IsValid() || elem.Kind() != reflect.Map {
+			continue
+		}
+		for _, k := range elem.MapKeys() {
+			if name := fmt.Sprint(k); !seen[name] {
+				seen[name] = true
+				columns = append(columns, name)
+			}
+		}
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// isEmptyValue mirrors encoding/json's omitempty test.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	}
+	return false
+}
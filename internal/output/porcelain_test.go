@@ -0,0 +1,138 @@
+package output
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type porcelainRow struct {
+	ID      string            `json:"id"`
+	Tier    string            `json:"tier"`
+	Note    string            `json:"note,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Created time.Time         `json:"created_at"`
+	secret  string
+	Skipped string `json:"-"`
+}
+
+func porcelain(t *testing.T, data any) string {
+	t.Helper()
+	var buf bytes.Buffer
+	w := New(FormatPorcelain, WithOutput(&buf))
+	if err := w.Write(data); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	return buf.String()
+}
+
+func TestWriter_Write_PorcelainList(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	rows := []*porcelainRow{
+		{ID: "a", Tier: "critical", Created: at, secret: "x", Skipped: "y"},
+		{ID: "b", Tier: "caution", Note: "two\tcols\nand lines", Labels: map[string]string{"team": "infra"}, Created: at},
+	}
+	want := "a\tcritical\t\t\t2026-03-01T12:00:00Z\n" +
+		"b\tcaution\ttwo\\tcols\\nand lines\t{\"team\":\"infra\"}\t2026-03-01T12:00:00Z\n"
+	if got := porcelain(t, rows); got != want {
+		t.Fatalf("unexpected porcelain:\n%q\nwant\n%q", got, want)
+	}
+
+	// Columns come from the type, so an empty omitempty field never
+	// shifts later columns.
+	if got := porcelain(t, rows[:1]); got != "a\tcritical\t\t\t2026-03-01T12:00:00Z\n" {
+		t.Fatalf("unexpected single row: %q", got)
+	}
+	if got := porcelain(t, []string{"x", `back\slash`}); got != "x\nback\\\\slash\n" {
+		t.Fatalf("unexpected scalar list: %q", got)
+	}
+}
+
+func TestWriter_Write_PorcelainObject(t *testing.T) {
+	data := map[string]any{
+		"count": 2,
+		"stats": map[string]any{"total": 12345, "ratio": 0.25, "ok": true, "none": nil},
+		"tasks": []map[string]any{
+			{"id": "t1", "requests": 3},
+			{"id": "t2", "status": "open"},
+		},
+		"tags": []string{"one", "two"},
+	}
+	want := strings.Join([]string{
+		"count\t2",
+		"stats.none\t",
+		"stats.ok\ttrue",
+		"stats.ratio\t0.25",
+		"stats.total\t12345",
+		"tags\tone",
+		"tags\ttwo",
+		"tasks\tt1\t3\t",
+		"tasks\tt2\t\topen",
+	}, "\n") + "\n"
+	if got := porcelain(t, data); got != want {
+		t.Fatalf("unexpected porcelain:\n%s\nwant\n%s", got, want)
+	}
+
+	// Objects drop empty omitempty fields, like JSON.
+	got := porcelain(t, porcelainRow{ID: "a", Tier: "safe"})
+	if strings.Contains(got, "note") || !strings.Contains(got, "id\ta\ntier\tsafe\n") {
+		t.Fatalf("unexpected struct porcelain: %q", got)
+	}
+}
+
+func TestWriter_Error_Porcelain(t *testing.T) {
+	var out, errOut bytes.Buffer
+	w := New(FormatPorcelain, WithOutput(&out), WithErrorOutput(&errOut))
+	w.Error(errors.New("boom"))
+	if out.Len() != 0 {
+		t.Fatalf("errors should not reach stdout, got %q", out.String())
+	}
+	if !strings.Contains(errOut.String(), "message\tboom\n") {
+		t.Fatalf("unexpected error output: %q", errOut.String())
+	}
+}
+
+func TestQuiet(t *testing.T) {
+	SetQuiet(true)
+	t.Cleanup(func() { SetQuiet(false) })
+
+	var out, errOut bytes.Buffer
+	w := New(FormatText, WithOutput(&out), WithErrorOutput(&errOut), WithStats(true))
+	w.Success("done")
+	if err := w.Write("hello"); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if out.Len() != 0 || errOut.Len() != 0 {
+		t.Fatalf("quiet text output should be empty, got %q / %q", out.String(), errOut.String())
+	}
+	w.Error(errors.New("boom"))
+	if !strings.Contains(errOut.String(), "boom") {
+		t.Fatalf("quiet mode should keep errors, got %q", errOut.String())
+	}
+
+	// Records in structured formats are not decoration.
+	out.Reset()
+	p := New(FormatPorcelain, WithOutput(&out))
+	if err := p.Write([]string{"a"}); err != nil || out.String() != "a\n" {
+		t.Fatalf("quiet porcelain = %q, %v", out.String(), err)
+	}
+}
+
+func TestNotef(t *testing.T) {
+	t.Cleanup(func() { SetQuiet(false) })
+
+	var buf bytes.Buffer
+	Notef(&buf, "Created %s\n", "req-1")
+	if buf.String() != "Created req-1\n" {
+		t.Fatalf("Notef = %q", buf.String())
+	}
+
+	buf.Reset()
+	SetQuiet(true)
+	Notef(&buf, "Created %s\n", "req-1")
+	if buf.Len() != 0 {
+		t.Fatalf("quiet Notef should print nothing, got %q", buf.String())
+	}
+}
@@ -0,0 +1,33 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+)
+
+var quiet atomic.Bool
+
+// SetQuiet turns quiet mode on or off. In quiet mode the writer drops
+// success messages, human-readable text and token statistics; records in
+// structured formats and errors are still written, and exit codes are
+// unchanged.
+func SetQuiet(on bool) {
+	quiet.Store(on)
+}
+
+// Quiet reports whether quiet mode is on.
+func Quiet() bool {
+	return quiet.Load()
+}
+
+// Notef writes a status or banner line to w: a confirmation, a hint about
+// what to run next, a "[slb]" progress note. Quiet mode drops it. Records
+// the user asked for, and the output of commands slb executes, are not
+// notes and are printed directly.
+func Notef(w io.Writer, format string, args ...any) {
+	if Quiet() {
+		return
+	}
+	_, _ = fmt.Fprintf(w, format, args...)
+}
@@ -0,0 +1,153 @@
+package qrcode
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestReedSolomon(t *testing.T) {
+	// "HELLO WORLD" at 1-M, the worked example of the standard's tutorials.
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+	if got := rsRemainder(data, rsDivisor(10)); !bytes.Equal(got, want) {
+		t.Errorf("ECC = %v, want %v", got, want)
+	}
+}
+
+func TestFormatAndVersionBits(t *testing.T) {
+	if got := formatBits(0); got != 0b101010000010010 {
+		t.Errorf("formatBits(M, mask 0) = %015b", got)
+	}
+	if got := formatBits(5); got != 0b100000011001110 {
+		t.Errorf("formatBits(M, mask 5) = %015b", got)
+	}
+	if got := versionBits(7); got != 0b000111110010010100 {
+		t.Errorf("versionBits(7) = %018b", got)
+	}
+}
+
+func TestAlignmentPositions(t *testing.T) {
+	for version, want := range map[int][]int{
+		1:  nil,
+		2:  {6, 18},
+		7:  {6, 22, 38},
+		32: {6, 34, 60, 86, 112, 138},
+		40: {6, 30, 58, 86, 114, 142, 170},
+	} {
+		if got := alignmentPositions(version); !slices.Equal(got, want) {
+			t.Errorf("alignmentPositions(%d) = %v, want %v", version, got, want)
+		}
+	}
+}
+
+func TestCapacity(t *testing.T) {
+	// Byte-mode capacities at level M.
+	for version, bytes := range map[int]int{1: 14, 10: 213, 40: 2331} {
+		if _, err := Encode(make([]byte, bytes)); err != nil {
+			t.Fatalf("%d bytes: %v", bytes, err)
+		}
+		c, _ := Encode(make([]byte, bytes))
+		if c.Version != version {
+			t.Errorf("%d bytes: version %d, want %d", bytes, c.Version, version)
+		}
+	}
+	if _, err := Encode(make([]byte, 2332)); err != ErrTooLong {
+		t.Errorf("err = %v, want ErrTooLong", err)
+	}
+}
+
+// TestRoundTrip reads the codewords back out of an encoded symbol the way a
+// scanner would once it has located the grid: format bits, unmask, zigzag.
+func TestRoundTrip(t *testing.T) {
+	payload := []byte("SLB REVIEW 3f2a\nCmd: rm -rf /var/lib/pg")
+	c, err := Encode(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var format int
+	for i := 14; i >= 9; i-- {
+		format = format<<1 | b2i(c.Dark(14-i, 8))
+	}
+	format = format<<1 | b2i(c.Dark(7, 8))
+	format = format<<1 | b2i(c.Dark(8, 8))
+	format = format<<1 | b2i(c.Dark(8, 7))
+	for i := 5; i >= 0; i-- {
+		format = format<<1 | b2i(c.Dark(8, i))
+	}
+	if format != formatBits(c.Mask) {
+		t.Fatalf("format bits %015b do not match mask %d", format, c.Mask)
+	}
+
+	plain := newCode(c.Version)
+	plain.drawFunctionPatterns()
+	for y := range c.Size {
+		for x := range c.Size {
+			plain.dark[y][x] = c.dark[y][x]
+		}
+	}
+	plain.applyMask(c.Mask)
+	var read []byte
+	var cur byte
+	n := 0
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := range c.Size {
+			y := vert
+			if (right+1)&2 == 0 {
+				y = c.Size - 1 - vert
+			}
+			for j := range 2 {
+				if x := right - j; !plain.function[y][x] {
+					cur = cur<<1 | byte(b2i(plain.dark[y][x]))
+					if n++; n%8 == 0 {
+						read = append(read, cur)
+					}
+				}
+			}
+		}
+	}
+	want := addECC(c.Version, encodeData(c.Version, payload))
+	if !bytes.Equal(read[:len(want)], want) {
+		t.Fatal("codewords read back differ from those encoded")
+	}
+	// Up to version 3 there is one block, so the data codewords come first:
+	// mode, count, payload.
+	if c.Version > 3 {
+		t.Fatalf("version %d, want a single-block version", c.Version)
+	}
+	if read[0]>>4 != 0b0100 || int(read[0]&0xF)<<4|int(read[1]>>4) != len(payload) {
+		t.Errorf("header = %08b %08b", read[0], read[1])
+	}
+}
+
+func TestTerminal(t *testing.T) {
+	c, err := Encode([]byte("slb"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(c.Terminal(), "\n"), "\n")
+	width := c.Size + 2*quietZone
+	if len(lines) != (width+1)/2 {
+		t.Errorf("%d lines, want %d", len(lines), (width+1)/2)
+	}
+	for _, l := range lines {
+		if n := len([]rune(l)); n != width {
+			t.Fatalf("line is %d wide, want %d", n, width)
+		}
+	}
+	if lines[0] != strings.Repeat("█", width) {
+		t.Error("top quiet zone should be all light")
+	}
+}
+
+func b2i(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,199 @@
+// Package schedule parses when the daemon's recurring jobs run: a fixed
+// interval such as "10s" or "1h", or a five-field cron expression such as
+// "0 9 * * 1" (minute, hour, day of month, month, day of week), evaluated
+// in the zone of the time it is asked about.
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed job schedule.
+type Schedule struct {
+	spec  string
+	every time.Duration
+
+	// Cron fields as bit sets of the values they match.
+	minute, hour, dom, month, dow uint64
+	// A day is matched by day of month or day of week when both are
+	// restricted, as in cron; a "*" in either leaves only the other.
+	domStar, dowStar bool
+}
+
+// aliases are the cron shorthands Parse accepts.
+var aliases = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+// Parse parses an interval ("10s", "@every 1h") or a cron expression
+// ("*/15 * * * *", "@daily"). Cron fields take "*", values, ranges "a-b",
+// steps "*/n" or "a-b/n", and comma-separated lists of those; day of week
+// runs 0-6 from Sunday, and 7 is Sunday too.
+func Parse(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return Schedule{}, fmt.Errorf("empty schedule")
+	}
+	text := spec
+	if every, ok := strings.CutPrefix(text, "@every "); ok {
+		text = strings.TrimSpace(every)
+	}
+	if d, err := time.ParseDuration(text); err == nil {
+		if d <= 0 {
+			return Schedule{}, fmt.Errorf("schedule %q: interval must be positive", spec)
+		}
+		return Schedule{spec: spec, every: d}, nil
+	}
+	if alias, ok := aliases[text]; ok {
+		text = alias
+	}
+
+	fields := strings.Fields(text)
+	if len(fields) != 5 {
+		return Schedule{}, fmt.Errorf("schedule %q: want an interval such as 10m or five cron fields", spec)
+	}
+	s := Schedule{spec: spec}
+	var err error
+	if s.minute, err = parseField(fields[0], 0, 59); err != nil {
+		return Schedule{}, fmt.Errorf("schedule %q: minute: %w", spec, err)
+	}
+	if s.hour, err = parseField(fields[1], 0, 23); err != nil {
+		return Schedule{}, fmt.Errorf("schedule %q: hour: %w", spec, err)
+	}
+	if s.dom, err = parseField(fields[2], 1, 31); err != nil {
+		return Schedule{}, fmt.Errorf("schedule %q: day of month: %w", spec, err)
+	}
+	if s.month, err = parseField(fields[3], 1, 12); err != nil {
+		return Schedule{}, fmt.Errorf("schedule %q: month: %w", spec, err)
+	}
+	if s.dow, err = parseField(fields[4], 0, 7); err != nil {
+		return Schedule{}, fmt.Errorf("schedule %q: day of week: %w", spec, err)
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	s.domStar = strings.HasPrefix(fields[2], "*")
+	s.dowStar = strings.HasPrefix(fields[4], "*")
+
+	if s.Next(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)).IsZero() {
+		return Schedule{}, fmt.Errorf("schedule %q never matches", spec)
+	}
+	return s, nil
+}
+
+// parseField parses one cron field into a bit set of values in [lo, hi].
+func parseField(field string, lo, hi int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, stepText, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepText)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepText)
+			}
+			step = n
+		}
+
+		start, end := lo, hi
+		switch {
+		case rng == "*":
+		case strings.Contains(rng, "-"):
+			a, b, _ := strings.Cut(rng, "-")
+			var err error
+			if start, err = fieldValue(a, lo, hi); err != nil {
+				return 0, err
+			}
+			if end, err = fieldValue(b, lo, hi); err != nil {
+				return 0, err
+			}
+			if start > end {
+				return 0, fmt.Errorf("range %q runs backwards", rng)
+			}
+		default:
+			v, err := fieldValue(rng, lo, hi)
+			if err != nil {
+				return 0, err
+			}
+			start = v
+			if !hasStep {
+				end = v
+			}
+		}
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func fieldValue(text string, lo, hi int) (int, error) {
+	v, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", text)
+	}
+	if v < lo || v > hi {
+		return 0, fmt.Errorf("value %d out of range %d-%d", v, lo, hi)
+	}
+	return v, nil
+}
+
+// String is the schedule as it was written.
+func (s Schedule) String() string {
+	return s.spec
+}
+
+// Interval is the interval of an interval schedule, 0 for cron.
+func (s Schedule) Interval() time.Duration {
+	return s.every
+}
+
+// IsZero reports whether s is the zero Schedule, which never runs.
+func (s Schedule) IsZero() bool {
+	return s.spec == ""
+}
+
+// Next is the first time after t the schedule runs, or the zero time if it
+// never does. Cron schedules run on the minute, in t's zone.
+func (s Schedule) Next(t time.Time) time.Time {
+	if s.every > 0 {
+		return t.Add(s.every)
+	}
+	if s.minute == 0 {
+		return time.Time{}
+	}
+
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, loc)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (s Schedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
@@ -0,0 +1,36 @@
+package testutil
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFakeClock(start)
+	RequireEqual(t, start, c.Now(), "start")
+
+	RequireEqual(t, start.Add(time.Hour), c.Advance(time.Hour), "advance")
+	RequireEqual(t, start.Add(time.Hour), c.Now(), "now after advance")
+
+	c.Set(start.Add(-time.Minute))
+	RequireEqual(t, start.Add(-time.Minute), c.Now(), "set into the past")
+}
+
+func TestFakeClock_Concurrent(t *testing.T) {
+	c := NewFakeClock(time.Time{})
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				c.Advance(time.Second)
+				_ = c.Now()
+			}
+		}()
+	}
+	wg.Wait()
+	RequireEqual(t, time.Time{}.Add(1000*time.Second), c.Now(), "after concurrent advances")
+}
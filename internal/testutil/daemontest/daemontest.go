@@ -0,0 +1,232 @@
+// Package daemontest runs a complete in-process daemon for integration tests.
+//
+// It lives outside testutil because the daemon package's own tests import
+// testutil; importing the daemon from there would be a cycle.
+//
+//	d := daemontest.Start(t)
+//	sess := testutil.MakeSession(t, d.DB, testutil.WithProject(d.ProjectDir))
+//	status, err := d.Client.Status(ctx)
+package daemontest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/clock"
+	"github.com/Dicklesworthstone/slb/internal/daemon"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+// readyTimeout bounds how long Start waits for the socket to answer.
+const readyTimeout = 5 * time.Second
+
+// Daemon is a running daemon serving a temp project.
+type Daemon struct {
+	*testutil.Harness
+
+	// SocketPath is the Unix socket the daemon listens on.
+	SocketPath string
+	// Client is connected to SocketPath and closed on cleanup.
+	Client *daemon.IPCClient
+	// Notifier records desktop notifications instead of showing them.
+	Notifier *RecordingNotifier
+
+	cancel   context.CancelFunc
+	done     chan error
+	stopOnce sync.Once
+	stopErr  error
+}
+
+// Option customizes Start.
+type Option func(*config)
+
+type config struct {
+	harness       *testutil.Harness
+	projectSocket bool
+	clock         clock.Clock
+	checkInterval time.Duration
+}
+
+// WithHarness serves an existing harness's project instead of a new one.
+func WithHarness(h *testutil.Harness) Option {
+	return func(c *config) { c.harness = h }
+}
+
+// WithProjectSocket listens on daemon.SocketPathFor(ProjectDir), where the
+// CLI and the generated hook look for the daemon, instead of a private
+// socket. Only one such daemon can run per project.
+func WithProjectSocket() Option {
+	return func(c *config) { c.projectSocket = true }
+}
+
+// WithClock drives the daemon's expiry, auto-approval and inactivity checks
+// from c, typically a testutil.FakeClock.
+func WithClock(c clock.Clock) Option {
+	return func(cfg *config) { cfg.clock = c }
+}
+
+// WithCheckInterval sweeps expired requests every d instead of every
+// daemon.DefaultCheckInterval.
+func WithCheckInterval(d time.Duration) Option {
+	return func(c *config) { c.checkInterval = d }
+}
+
+// Start runs the daemon (IPC server, read model, event writer, verifier,
+// timeout sweeper and notification monitors) against a temp project's
+// .slb/state.db and returns once a client is connected. The daemon is
+// stopped on cleanup.
+func Start(t *testing.T, opts ...Option) *Daemon {
+	t.Helper()
+
+	var cfg config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	h := cfg.harness
+	if h == nil {
+		h = testutil.NewHarness(t)
+	}
+
+	// Unix socket paths are limited to ~104 bytes, which t.TempDir can
+	// exceed for long test names.
+	runDir, err := os.MkdirTemp("", "slbd-")
+	testutil.RequireNoError(t, err, "create daemon run dir")
+	t.Cleanup(func() { _ = os.RemoveAll(runDir) })
+
+	socketPath := filepath.Join(runDir, "d.sock")
+	if cfg.projectSocket {
+		socketPath = daemon.SocketPathFor(h.ProjectDir)
+	}
+
+	d := &Daemon{
+		Harness:    h,
+		SocketPath: socketPath,
+		Notifier:   &RecordingNotifier{},
+		done:       make(chan error, 1),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+	go func() {
+		d.done <- daemon.RunDaemon(ctx, daemon.ServerOptions{
+			SocketPath:           socketPath,
+			PIDFile:              filepath.Join(runDir, "d.pid"),
+			Logger:               testutil.TestLogger(t),
+			ProjectPath:          h.ProjectDir,
+			Notifier:             d.Notifier,
+			Clock:                cfg.clock,
+			TimeoutCheckInterval: cfg.checkInterval,
+		})
+	}()
+	t.Cleanup(func() {
+		if err := d.Stop(); err != nil {
+			t.Errorf("daemon stop: %v", err)
+		}
+	})
+
+	d.Client = daemon.NewIPCClient(socketPath)
+	if err := d.waitReady(); err != nil {
+		t.Fatalf("daemontest.Start: %v", err)
+	}
+	return d
+}
+
+// waitReady polls until the daemon answers a ping or exits.
+func (d *Daemon) waitReady() error {
+	deadline := time.Now().Add(readyTimeout)
+	for time.Now().Before(deadline) {
+		select {
+		case err := <-d.done:
+			d.done <- err
+			return fmt.Errorf("daemon exited before becoming ready: %v", err)
+		default:
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		err := d.Client.Ping(ctx)
+		cancel()
+		if err == nil {
+			return nil
+		}
+		_ = d.Client.Close()
+		time.Sleep(10 * time.Millisecond)
+	}
+	return fmt.Errorf("daemon not ready after %s", readyTimeout)
+}
+
+// Stop shuts the daemon down and waits for it to exit, flushing buffered
+// writes. It is called on cleanup; calling it earlier lets a test inspect
+// state written during shutdown.
+func (d *Daemon) Stop() error {
+	d.stopOnce.Do(func() {
+		_ = d.Client.Close()
+		d.cancel()
+		select {
+		case d.stopErr = <-d.done:
+		case <-time.After(readyTimeout):
+			d.stopErr = fmt.Errorf("daemon did not exit within %s", readyTimeout)
+		}
+	})
+	return d.stopErr
+}
+
+// NewClient returns another client for the daemon, closed on cleanup.
+func (d *Daemon) NewClient() *daemon.IPCClient {
+	c := daemon.NewIPCClient(d.SocketPath)
+	d.T.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+// WaitForStatus polls the database until the request reaches status.
+func (d *Daemon) WaitForStatus(requestID string, status db.RequestStatus, timeout time.Duration) *db.Request {
+	d.T.Helper()
+	var last *db.Request
+	ok := testutil.WaitForCondition(func() bool {
+		r, err := d.DB.GetRequest(requestID)
+		if err != nil {
+			return false
+		}
+		last = r
+		return r.Status == status
+	}, 10*time.Millisecond, timeout)
+	if !ok {
+		got := db.RequestStatus("<missing>")
+		if last != nil {
+			got = last.Status
+		}
+		d.T.Fatalf("request %s: status %s after %s, want %s", requestID, got, timeout, status)
+	}
+	return last
+}
+
+// Notification is one desktop notification the daemon sent.
+type Notification struct {
+	Title   string
+	Message string
+}
+
+// RecordingNotifier is a daemon.DesktopNotifier that records notifications.
+type RecordingNotifier struct {
+	mu    sync.Mutex
+	notes []Notification
+}
+
+// Notify records the notification.
+func (n *RecordingNotifier) Notify(title, message string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.notes = append(n.notes, Notification{Title: title, Message: message})
+	return nil
+}
+
+// Notifications returns the notifications recorded so far.
+func (n *RecordingNotifier) Notifications() []Notification {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]Notification(nil), n.notes...)
+}
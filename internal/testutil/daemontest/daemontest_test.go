@@ -0,0 +1,391 @@
+package daemontest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/daemon"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func TestStart_ServesProjectState(t *testing.T) {
+	d := Start(t)
+	sess := testutil.MakeSession(t, d.DB, testutil.WithProject(d.ProjectDir))
+	req := testutil.MakeRequest(t, d.DB, sess, testutil.WithCommand("rm -rf ./build", d.ProjectDir, true))
+
+	ctx := context.Background()
+	snap, err := d.Client.ReadModel(ctx)
+	testutil.RequireNoError(t, err, "read_model")
+	testutil.RequireLen(t, snap.Pending, 1, "pending requests")
+	testutil.RequireEqual(t, req.ID, snap.Pending[0].Request.ID, "pending request")
+
+	status, err := d.Client.Status(ctx)
+	testutil.RequireNoError(t, err, "status")
+	if status.Cache == nil || status.Writer == nil {
+		t.Fatalf("expected cache and writer stats, got %+v", status)
+	}
+}
+
+func TestStart_HookQueryAndVerifyExecute(t *testing.T) {
+	d := Start(t)
+	sess := testutil.MakeSession(t, d.DB, testutil.WithProject(d.ProjectDir))
+	reviewer := testutil.MakeSession(t, d.DB, testutil.WithProject(d.ProjectDir))
+	ctx := context.Background()
+
+	res, err := d.Client.HookQuery(ctx, daemon.HookQueryParams{
+		Command: "rm -rf ./build", SessionID: sess.ID, CWD: d.ProjectDir,
+	})
+	testutil.RequireNoError(t, err, "hook_query")
+	testutil.RequireEqual(t, "block", res.Action, "action before approval")
+
+	req := testutil.MakeRequest(t, d.DB, sess, testutil.WithCommand("rm -rf ./build", d.ProjectDir, true))
+	denied, err := d.Client.VerifyExecute(ctx, req.ID, sess.ID)
+	testutil.RequireNoError(t, err, "verify_execute pending")
+	if denied.Allowed {
+		t.Fatal("expected pending request to be denied")
+	}
+
+	testutil.RequireNoError(t, d.DB.CreateReview(&db.Review{
+		RequestID:         req.ID,
+		ReviewerSessionID: reviewer.ID,
+		ReviewerAgent:     reviewer.AgentName,
+		ReviewerModel:     reviewer.Model,
+		Decision:          db.DecisionApprove,
+		Signature:         "sig",
+	}), "create review")
+	testutil.RequireNoError(t, d.DB.UpdateRequestStatus(req.ID, db.StatusApproved), "approve")
+	_, err = d.DB.Exec(`UPDATE requests SET approval_expires_at = ? WHERE id = ?`,
+		time.Now().Add(10*time.Minute).UTC().Format(time.RFC3339), req.ID)
+	testutil.RequireNoError(t, err, "set approval expiry")
+
+	// The watcher invalidates the read model once the approval lands.
+	ok := testutil.WaitForCondition(func() bool {
+		res, err := d.Client.HookQuery(ctx, daemon.HookQueryParams{
+			Command: "rm -rf ./build", SessionID: sess.ID, CWD: d.ProjectDir,
+		})
+		return err == nil && res.Action == "allow" && res.RequestID == req.ID
+	}, 20*time.Millisecond, 5*time.Second)
+	if !ok {
+		t.Fatal("hook_query did not report the approval")
+	}
+
+	allowed, err := d.Client.VerifyExecute(ctx, req.ID, sess.ID)
+	testutil.RequireNoError(t, err, "verify_execute approved")
+	if !allowed.Allowed {
+		t.Fatalf("expected approved request to be allowed: %s", allowed.Reason)
+	}
+	again, err := d.Client.VerifyExecute(ctx, req.ID, sess.ID)
+	testutil.RequireNoError(t, err, "verify_execute again")
+	if again.Allowed {
+		t.Fatal("expected the second executor to be denied")
+	}
+}
+
+func TestStart_SweeperEscalatesWithFakeClock(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	clk := testutil.NewFakeClock(now)
+	d := Start(t, WithClock(clk), WithCheckInterval(20*time.Millisecond))
+
+	sess := testutil.MakeSession(t, d.DB, testutil.WithProject(d.ProjectDir))
+	req := testutil.MakeRequest(t, d.DB, sess,
+		testutil.WithCommand("rm -rf ./build", d.ProjectDir, true),
+		testutil.WithExpiresAt(now.Add(30*time.Minute)),
+	)
+
+	// Give the sweeper a few passes; nothing has expired yet.
+	time.Sleep(100 * time.Millisecond)
+	got, err := d.DB.GetRequest(req.ID)
+	testutil.RequireNoError(t, err, "get request")
+	testutil.RequireEqual(t, db.StatusPending, got.Status, "status before expiry")
+
+	clk.Advance(time.Hour)
+	d.WaitForStatus(req.ID, db.StatusEscalated, 5*time.Second)
+
+	var escalated bool
+	for _, n := range d.Notifier.Notifications() {
+		if strings.Contains(n.Title, "Escalated") && strings.Contains(n.Message, req.ID[:8]) {
+			escalated = true
+		}
+	}
+	if !escalated {
+		t.Fatalf("expected an escalation notification, got %+v", d.Notifier.Notifications())
+	}
+}
+
+func TestStart_BroadcastsStatusChanges(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	clk := testutil.NewFakeClock(now)
+	d := Start(t, WithClock(clk), WithCheckInterval(20*time.Millisecond))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	events, err := d.NewClient().Subscribe(ctx)
+	testutil.RequireNoError(t, err, "subscribe")
+
+	sess := testutil.MakeSession(t, d.DB, testutil.WithProject(d.ProjectDir))
+	req := testutil.MakeRequest(t, d.DB, sess,
+		testutil.WithCommand("rm -rf ./build", d.ProjectDir, true),
+		testutil.WithExpiresAt(now.Add(30*time.Minute)),
+	)
+	clk.Advance(time.Hour)
+
+	var got []string
+	for len(got) < 2 {
+		select {
+		case ev := <-events:
+			if ev.Type != daemon.EventRequestStatusChanged {
+				continue
+			}
+			payload, _ := ev.Payload.(map[string]any)
+			if payload["request_id"] != req.ID {
+				continue
+			}
+			got = append(got, payload["from"].(string)+"->"+payload["to"].(string))
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for status events, got %v", got)
+		}
+	}
+	testutil.RequireEqual(t, "pending->timeout timeout->escalated", strings.Join(got, " "), "broadcast transitions")
+}
+
+func TestStart_HeartbeatFlushedOnStop(t *testing.T) {
+	// Heartbeats never move last_active_at backwards, so use a future time.
+	at := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+	d := Start(t, WithClock(testutil.NewFakeClock(at)))
+	sess := testutil.MakeSession(t, d.DB, testutil.WithProject(d.ProjectDir))
+
+	testutil.RequireNoError(t, d.Client.Heartbeat(context.Background(), sess.ID), "heartbeat")
+	testutil.RequireNoError(t, d.Stop(), "stop")
+
+	got, err := d.DB.GetSession(sess.ID)
+	testutil.RequireNoError(t, err, "get session")
+	if !got.LastActiveAt.Equal(at) {
+		t.Fatalf("last_active_at = %v, want %v", got.LastActiveAt, at)
+	}
+}
+
+func TestStart_ProjectSocket(t *testing.T) {
+	d := Start(t, WithProjectSocket())
+	testutil.RequireEqual(t, daemon.SocketPathFor(d.ProjectDir), d.SocketPath, "socket path")
+
+	c := d.NewClient()
+	testutil.RequireNoError(t, c.Ping(context.Background()), "ping second client")
+}
+
+func TestRecordingNotifier(t *testing.T) {
+	n := &RecordingNotifier{}
+	testutil.RequireNoError(t, n.Notify("title", "body"), "notify")
+	notes := n.Notifications()
+	testutil.RequireLen(t, notes, 1, "notifications")
+	testutil.RequireEqual(t, Notification{Title: "title", Message: "body"}, notes[0], "notification")
+}
+
+func TestStart_CreateRequestIdempotent(t *testing.T) {
+	d := Start(t)
+	sess := testutil.MakeSession(t, d.DB, testutil.WithProject(d.ProjectDir))
+	ctx := context.Background()
+
+	params := daemon.CreateRequestParams{
+		SessionID:      sess.ID,
+		Command:        "rm -rf ./build",
+		Cwd:            d.ProjectDir,
+		Reason:         "clean build output",
+		IdempotencyKey: "retry-1",
+	}
+	first, err := d.Client.CreateRequest(ctx, params)
+	testutil.RequireNoError(t, err, "create_request")
+	if first.RequestID == "" || first.Replayed {
+		t.Fatalf("expected a new request, got %+v", first)
+	}
+	testutil.RequireEqual(t, string(db.StatusPending), first.Status, "status")
+
+	// A retry on a fresh connection, as after a dropped socket.
+	again, err := d.NewClient().CreateRequest(ctx, params)
+	testutil.RequireNoError(t, err, "create_request retry")
+	if !again.Replayed || again.RequestID != first.RequestID {
+		t.Fatalf("expected replay of %s, got %+v", first.RequestID, again)
+	}
+
+	params.Command = "rm -rf ./dist"
+	if _, err := d.Client.CreateRequest(ctx, params); err == nil || !strings.Contains(err.Error(), "different command") {
+		t.Fatalf("expected a reused-key error, got %v", err)
+	}
+
+	pending, err := d.DB.ListPendingRequests(d.ProjectDir)
+	testutil.RequireNoError(t, err, "list pending")
+	testutil.RequireLen(t, pending, 1, "pending requests")
+}
+
+func TestStart_CreateRequestAsksAdvisor(t *testing.T) {
+	release := make(chan struct{})
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		_, _ = w.Write([]byte(`{"risk_summary":"Deletes build output","recommendation":"approve"}`))
+	}))
+	defer endpoint.Close()
+	defer close(release)
+
+	h := testutil.NewHarness(t)
+	testutil.RequireNoError(t, os.WriteFile(filepath.Join(h.ProjectDir, ".slb", "config.toml"),
+		[]byte("[general]\nsession_key_store = \"off\"\n\n[integrations]\nllm_review_enabled = true\nllm_review_endpoint = \""+endpoint.URL+"\"\n"), 0o600), "write config")
+	d := Start(t, WithHarness(h))
+	sess := testutil.MakeSession(t, d.DB, testutil.WithProject(d.ProjectDir))
+
+	// The request is created while the advisor is still thinking.
+	resp, err := d.Client.CreateRequest(context.Background(), daemon.CreateRequestParams{
+		SessionID: sess.ID,
+		Command:   "rm -rf ./build",
+		Cwd:       d.ProjectDir,
+		Reason:    "clean build output",
+	})
+	testutil.RequireNoError(t, err, "create_request")
+	annotations, err := d.DB.ListAnnotationsForRequest(resp.RequestID)
+	testutil.RequireNoError(t, err, "list annotations")
+	testutil.RequireLen(t, annotations, 0, "annotations before the advisor answers")
+
+	release <- struct{}{}
+	deadline := time.Now().Add(5 * time.Second)
+	for len(annotations) == 0 && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+		annotations, err = d.DB.ListAnnotationsForRequest(resp.RequestID)
+		testutil.RequireNoError(t, err, "list annotations")
+	}
+	testutil.RequireLen(t, annotations, 1, "annotations")
+	testutil.RequireEqual(t, "Deletes build output", annotations[0].RiskSummary, "risk summary")
+}
+
+func TestStart_HealthCheck(t *testing.T) {
+	d := Start(t)
+
+	report := daemon.CheckHealth(context.Background(), d.SocketPath, 2*time.Second)
+	if !report.Healthy {
+		t.Fatalf("expected a healthy daemon, got %+v", report.Probes)
+	}
+	var names []string
+	for _, p := range report.Probes {
+		names = append(names, p.Name)
+		if p.Skipped {
+			t.Errorf("probe %s skipped with a state database", p.Name)
+		}
+	}
+	testutil.RequireEqual(t, "ping db classify broadcast", strings.Join(names, " "), "probes")
+
+	// Probe events are delivered but never stored.
+	testutil.RequireNoError(t, d.Stop(), "stop")
+	events, err := d.DB.ListDaemonEvents(0, 100)
+	testutil.RequireNoError(t, err, "list events")
+	for _, e := range events {
+		if e.Type == daemon.EventHealthProbe {
+			t.Fatalf("health probe left an event behind: %+v", e)
+		}
+	}
+}
+
+func TestStart_CreateRequestRecordsPeer(t *testing.T) {
+	if !daemon.PeerCredSupported() {
+		t.Skip("peer credentials not supported on this platform")
+	}
+	d := Start(t)
+	sess := testutil.MakeSession(t, d.DB, testutil.WithProject(d.ProjectDir))
+
+	resp, err := d.Client.CreateRequest(context.Background(), daemon.CreateRequestParams{
+		SessionID: sess.ID,
+		Command:   "rm -rf ./build",
+		Cwd:       d.ProjectDir,
+		Reason:    "clean build output",
+	})
+	testutil.RequireNoError(t, err, "create_request")
+
+	prov, err := d.DB.GetRequestProvenance(resp.RequestID)
+	testutil.RequireNoError(t, err, "get provenance")
+	if prov.PeerUID == nil || *prov.PeerUID != os.Getuid() {
+		t.Fatalf("peer uid = %v, want %d", prov.PeerUID, os.Getuid())
+	}
+	if prov.PeerPID == nil || *prov.PeerPID != os.Getpid() {
+		t.Fatalf("peer pid = %v, want %d", prov.PeerPID, os.Getpid())
+	}
+}
+
+func TestStart_HookAutoRequest(t *testing.T) {
+	h := testutil.NewHarness(t)
+	testutil.RequireNoError(t, os.WriteFile(filepath.Join(h.ProjectDir, ".slb", "config.toml"),
+		[]byte("[integrations]\nhook_auto_request = true\n"), 0o600), "write config")
+	d := Start(t, WithHarness(h))
+	testutil.MakeSession(t, d.DB, testutil.WithProject(d.ProjectDir), testutil.WithAgent("Other"), testutil.WithProgram("codex"))
+	sess := testutil.MakeSession(t, d.DB, testutil.WithProject(d.ProjectDir), testutil.WithProgram("claude-code"))
+	ctx := context.Background()
+	transcript := filepath.Join(t.TempDir(), "conv-1.jsonl")
+	testutil.RequireNoError(t, os.WriteFile(transcript,
+		[]byte(`{"type":"user","message":{"role":"user","content":"the build is stale, token=hunter2"}}`+"\n"), 0o600), "write transcript")
+
+	res, err := d.Client.HookQuery(ctx, daemon.HookQueryParams{
+		Command:        "rm -rf ./build",
+		SessionID:      "conv-1",
+		CWD:            d.ProjectDir,
+		AgentProgram:   "claude-code",
+		ToolCallID:     "toolu_1",
+		TranscriptPath: transcript,
+		Description:    "Clear stale build output",
+	})
+	testutil.RequireNoError(t, err, "hook_query")
+	testutil.RequireEqual(t, "block", res.Action, "action")
+	if res.AutoRequest == nil {
+		t.Fatal("expected auto_request params")
+	}
+	testutil.RequireEqual(t, sess.ID, res.AutoRequest.SessionID, "session picked by program")
+	testutil.RequireEqual(t, "hook:toolu_1", res.AutoRequest.IdempotencyKey, "idempotency key")
+
+	created, err := d.Client.CreateRequest(ctx, *res.AutoRequest)
+	testutil.RequireNoError(t, err, "create_request")
+	testutil.RequireEqual(t, string(db.StatusPending), created.Status, "status")
+	again, err := d.Client.CreateRequest(ctx, *res.AutoRequest)
+	testutil.RequireNoError(t, err, "create_request again")
+	if !again.Replayed || again.RequestID != created.RequestID {
+		t.Fatalf("expected a replay of %s, got %+v", created.RequestID, again)
+	}
+
+	req, err := d.DB.GetRequest(created.RequestID)
+	testutil.RequireNoError(t, err, "get request")
+	testutil.RequireEqual(t, "Clear stale build output", req.Justification.Reason, "reason")
+	prov, err := d.DB.GetRequestProvenance(created.RequestID)
+	testutil.RequireNoError(t, err, "get provenance")
+	testutil.RequireEqual(t, "toolu_1", prov.ToolCallID, "tool call id")
+	testutil.RequireEqual(t, "conv-1", prov.ConversationID, "conversation id")
+	testutil.RequireEqual(t, "claude-code", prov.AgentProgram, "agent program")
+
+	var attached *db.Attachment
+	for i := range req.Attachments {
+		if req.Attachments[i].Type == db.AttachmentTypeTranscript {
+			attached = &req.Attachments[i]
+		}
+	}
+	if attached == nil {
+		t.Fatalf("expected the transcript to be attached, got %+v", req.Attachments)
+	}
+	text, err := attached.Text()
+	testutil.RequireNoError(t, err, "transcript text")
+	if !strings.Contains(text, "user: the build is stale") || strings.Contains(text, "hunter2") {
+		t.Fatalf("expected a redacted transcript, got %q", text)
+	}
+}
+
+func TestStart_HookAutoRequestOff(t *testing.T) {
+	d := Start(t)
+	sess := testutil.MakeSession(t, d.DB, testutil.WithProject(d.ProjectDir))
+
+	res, err := d.Client.HookQuery(context.Background(), daemon.HookQueryParams{
+		Command: "rm -rf ./build", SLBSessionID: sess.ID, CWD: d.ProjectDir,
+	})
+	testutil.RequireNoError(t, err, "hook_query")
+	testutil.RequireEqual(t, "block", res.Action, "action")
+	if res.AutoRequest != nil {
+		t.Fatalf("expected no auto_request by default, got %+v", res.AutoRequest)
+	}
+}
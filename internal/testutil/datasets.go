@@ -0,0 +1,319 @@
+package testutil
+
+import (
+	"database/sql"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// SeedEnv overrides the seed of every Rand so a failing run can be replayed.
+const SeedEnv = "SLB_TEST_SEED"
+
+// Rand is a seeded, deterministic randomizer for generating test data. The
+// same seed yields the same sequence on every run and platform. A Rand is not
+// safe for concurrent use; parallel tests should each create their own.
+type Rand struct {
+	r    *rand.Rand
+	seed uint64
+}
+
+// NewRand returns a Rand seeded with seed, or with $SLB_TEST_SEED when set.
+// The seed is logged if the test fails.
+func NewRand(t testing.TB, seed uint64) *Rand {
+	t.Helper()
+	if v := os.Getenv(SeedEnv); v != "" {
+		parsed, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			t.Fatalf("invalid %s %q: %v", SeedEnv, v, err)
+		}
+		seed = parsed
+	}
+	t.Cleanup(func() {
+		if t.Failed() {
+			t.Logf("test data seed: %d (rerun with %s=%d)", seed, SeedEnv, seed)
+		}
+	})
+	return &Rand{r: rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15)), seed: seed}
+}
+
+// Seed returns the seed in use.
+func (r *Rand) Seed() uint64 {
+	return r.seed
+}
+
+// IntN returns a number in [0, n).
+func (r *Rand) IntN(n int) int {
+	return r.r.IntN(n)
+}
+
+// Float64 returns a number in [0, 1).
+func (r *Rand) Float64() float64 {
+	return r.r.Float64()
+}
+
+// Hex returns n deterministic hex characters.
+func (r *Rand) Hex(n int) string {
+	const digits = "0123456789abcdef"
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = digits[r.r.IntN(len(digits))]
+	}
+	return string(b)
+}
+
+// Time returns a time in [start, end) truncated to seconds, matching the
+// precision timestamps are stored with.
+func (r *Rand) Time(start, end time.Time) time.Time {
+	span := end.Sub(start)
+	if span <= 0 {
+		return start.Truncate(time.Second)
+	}
+	return start.Add(time.Duration(r.r.Int64N(int64(span)))).UTC().Truncate(time.Second)
+}
+
+// Pick returns a random element of items.
+func Pick[T any](r *Rand, items []T) T {
+	return items[r.IntN(len(items))]
+}
+
+// Weighted pairs a value with its relative frequency.
+type Weighted[T any] struct {
+	Value  T
+	Weight int
+}
+
+// PickWeighted returns a value with probability proportional to its weight.
+func PickWeighted[T any](r *Rand, items []Weighted[T]) T {
+	total := 0
+	for _, it := range items {
+		total += it.Weight
+	}
+	n := r.IntN(total)
+	for _, it := range items {
+		if n < it.Weight {
+			return it.Value
+		}
+		n -= it.Weight
+	}
+	return items[len(items)-1].Value
+}
+
+// DefaultTierMix approximates the tiers seen in a busy project.
+var DefaultTierMix = []Weighted[db.RiskTier]{
+	{db.RiskTierCaution, 40},
+	{db.RiskTierDangerous, 45},
+	{db.RiskTierCritical, 15},
+}
+
+// DefaultStatusMix approximates request outcomes in a busy project.
+var DefaultStatusMix = []Weighted[db.RequestStatus]{
+	{db.StatusPending, 10},
+	{db.StatusApproved, 15},
+	{db.StatusExecuted, 35},
+	{db.StatusExecutionFailed, 5},
+	{db.StatusRejected, 15},
+	{db.StatusCancelled, 10},
+	{db.StatusTimeout, 10},
+}
+
+// datasetCommands are templates for generated commands; %d is replaced by a
+// small random number.
+var datasetCommands = []string{
+	"rm -rf ./build-%d",
+	"git reset --hard HEAD~%d",
+	"git push --force origin feature-%d",
+	"kubectl delete pod api-%d",
+	"terraform destroy -target=module.db_%d",
+	"docker rm -f worker-%d",
+	"psql -c 'DROP TABLE tmp_%d'",
+	"npm unpublish pkg@1.0.%d",
+}
+
+// DatasetOption customizes MakeRequests.
+type DatasetOption func(*datasetConfig)
+
+type datasetConfig struct {
+	rnd      *Rand
+	sessions []*db.Session
+	project  string
+	start    time.Time
+	end      time.Time
+	tiers    []Weighted[db.RiskTier]
+	statuses []Weighted[db.RequestStatus]
+}
+
+// DatasetRand draws from r instead of a Rand seeded with 1.
+func DatasetRand(r *Rand) DatasetOption {
+	return func(c *datasetConfig) { c.rnd = r }
+}
+
+// DatasetSessions makes requests from the given sessions instead of three
+// generated ones.
+func DatasetSessions(sessions ...*db.Session) DatasetOption {
+	return func(c *datasetConfig) { c.sessions = sessions }
+}
+
+// DatasetProject sets the project of generated sessions.
+func DatasetProject(path string) DatasetOption {
+	return func(c *datasetConfig) { c.project = path }
+}
+
+// DatasetTimeRange spreads created_at over [start, end) instead of the last
+// 30 days.
+func DatasetTimeRange(start, end time.Time) DatasetOption {
+	return func(c *datasetConfig) {
+		c.start = start
+		c.end = end
+	}
+}
+
+// DatasetTiers overrides DefaultTierMix.
+func DatasetTiers(mix ...Weighted[db.RiskTier]) DatasetOption {
+	return func(c *datasetConfig) { c.tiers = mix }
+}
+
+// DatasetStatuses overrides DefaultStatusMix.
+func DatasetStatuses(mix ...Weighted[db.RequestStatus]) DatasetOption {
+	return func(c *datasetConfig) { c.statuses = mix }
+}
+
+// MakeRequests inserts n requests with varied tiers, statuses, commands,
+// requestors and created_at timestamps. Everything but the time range's end
+// (now, by default) is derived from the Rand, so a seed reproduces the same
+// dataset. Terminal requests get a resolved_at after their created_at.
+func MakeRequests(t testing.TB, database *db.DB, n int, opts ...DatasetOption) []*db.Request {
+	t.Helper()
+
+	cfg := datasetConfig{
+		tiers:    DefaultTierMix,
+		statuses: DefaultStatusMix,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.rnd == nil {
+		cfg.rnd = NewRand(t, 1)
+	}
+	if cfg.end.IsZero() {
+		cfg.end = time.Now().UTC().Truncate(time.Second)
+	}
+	if cfg.start.IsZero() {
+		cfg.start = cfg.end.Add(-30 * 24 * time.Hour)
+	}
+	if len(cfg.sessions) == 0 {
+		project := cfg.project
+		if project == "" {
+			project = filepath.Join(t.TempDir(), "project")
+		}
+		for i := 0; i < 3; i++ {
+			cfg.sessions = append(cfg.sessions, MakeSession(t, database,
+				WithProject(project),
+				WithAgent(fmt.Sprintf("Agent-%d-%s", i, cfg.rnd.Hex(4))),
+				WithModel(Pick(cfg.rnd, []string{"opus", "sonnet", "gpt"})),
+			))
+		}
+	}
+
+	requests := make([]*db.Request, 0, n)
+	for i := 0; i < n; i++ {
+		sess := Pick(cfg.rnd, cfg.sessions)
+		raw := fmt.Sprintf(Pick(cfg.rnd, datasetCommands), cfg.rnd.IntN(100))
+		r := &db.Request{
+			ID:                 "req-" + cfg.rnd.Hex(12),
+			ProjectPath:        sess.ProjectPath,
+			Command:            db.CommandSpec{Raw: raw, Cwd: sess.ProjectPath, Shell: true},
+			RiskTier:           PickWeighted(cfg.rnd, cfg.tiers),
+			RequestorSessionID: sess.ID,
+			RequestorAgent:     sess.AgentName,
+			RequestorModel:     sess.Model,
+			Justification:      db.Justification{Reason: "generated"},
+			Status:             PickWeighted(cfg.rnd, cfg.statuses),
+			MinApprovals:       1,
+		}
+		if r.RiskTier == db.RiskTierCritical {
+			r.MinApprovals = 2
+		}
+		createdAt := cfg.rnd.Time(cfg.start, cfg.end)
+		var resolvedAt *time.Time
+		if r.Status.IsTerminal() {
+			at := createdAt.Add(time.Duration(1+cfg.rnd.IntN(3600)) * time.Second)
+			resolvedAt = &at
+		}
+
+		RequireNoError(t, database.CreateRequest(r), "create request")
+		r.CreatedAt = createdAt
+		r.ResolvedAt = resolvedAt
+		requests = append(requests, r)
+	}
+
+	// CreateRequest stamps created_at with the current time; backdate all
+	// rows in one transaction.
+	err := database.Transaction(func(tx *sql.Tx) error {
+		stmt, err := tx.Prepare(`UPDATE requests SET created_at = ?, resolved_at = ? WHERE id = ?`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+		for _, r := range requests {
+			var resolved any
+			if r.ResolvedAt != nil {
+				resolved = r.ResolvedAt.Format(time.RFC3339)
+			}
+			if _, err := stmt.Exec(r.CreatedAt.Format(time.RFC3339), resolved, r.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	RequireNoError(t, err, "backdate requests")
+	return requests
+}
+
+// MakeReviewMatrix has every reviewer review every request it did not make,
+// approving three times out of four, in a single transaction. It returns the
+// reviews created.
+func MakeReviewMatrix(t testing.TB, database *db.DB, requests []*db.Request, reviewers []*db.Session, rnd *Rand) []*db.Review {
+	t.Helper()
+	if rnd == nil {
+		rnd = NewRand(t, 1)
+	}
+
+	var reviews []*db.Review
+	err := database.Transaction(func(tx *sql.Tx) error {
+		for _, r := range requests {
+			for _, reviewer := range reviewers {
+				if reviewer.ID == r.RequestorSessionID {
+					continue
+				}
+				decision := db.DecisionApprove
+				if rnd.IntN(4) == 0 {
+					decision = db.DecisionReject
+				}
+				rev := &db.Review{
+					ID:                "rev-" + rnd.Hex(12),
+					RequestID:         r.ID,
+					ReviewerSessionID: reviewer.ID,
+					ReviewerAgent:     reviewer.AgentName,
+					ReviewerModel:     reviewer.Model,
+					Decision:          decision,
+					Signature:         "sig-" + rnd.Hex(16),
+					CreatedAt:         r.CreatedAt.Add(time.Duration(1+rnd.IntN(600)) * time.Second),
+				}
+				if err := database.CreateReviewTx(tx, rev); err != nil {
+					return err
+				}
+				reviews = append(reviews, rev)
+			}
+		}
+		return nil
+	})
+	RequireNoError(t, err, "create review matrix")
+	return reviews
+}
@@ -0,0 +1,191 @@
+package testutil
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// fixedRange keeps generated timestamps independent of the wall clock.
+var (
+	fixedEnd   = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	fixedStart = fixedEnd.Add(-7 * 24 * time.Hour)
+)
+
+func datasetFingerprint(reqs []*db.Request) []string {
+	out := make([]string, len(reqs))
+	for i, r := range reqs {
+		out[i] = fmt.Sprintf("%s|%s|%s|%s|%s|%s", r.ID, r.Command.Raw, r.RiskTier, r.Status, r.RequestorAgent, r.CreatedAt.Format(time.RFC3339))
+	}
+	return out
+}
+
+func TestMakeRequests_Deterministic(t *testing.T) {
+	gen := func(seed uint64) []string {
+		database := NewTestDB(t)
+		reqs := MakeRequests(t, database, 50,
+			DatasetRand(NewRand(t, seed)),
+			DatasetProject("/data/project"),
+			DatasetTimeRange(fixedStart, fixedEnd),
+		)
+		return datasetFingerprint(reqs)
+	}
+
+	a, b, c := gen(7), gen(7), gen(8)
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("same seed produced different request %d:\n%s\n%s", i, a[i], b[i])
+		}
+	}
+	same := 0
+	for i := range a {
+		if a[i] == c[i] {
+			same++
+		}
+	}
+	if same == len(a) {
+		t.Fatal("different seeds produced the same dataset")
+	}
+}
+
+func TestMakeRequests_Realistic(t *testing.T) {
+	database := NewTestDB(t)
+	reqs := MakeRequests(t, database, 400,
+		DatasetRand(NewRand(t, 42)),
+		DatasetTimeRange(fixedStart, fixedEnd),
+	)
+	RequireLen(t, reqs, 400, "requests")
+
+	tiers := make(map[db.RiskTier]int)
+	statuses := make(map[db.RequestStatus]int)
+	agents := make(map[string]bool)
+	for _, r := range reqs {
+		tiers[r.RiskTier]++
+		statuses[r.Status]++
+		agents[r.RequestorAgent] = true
+		if r.CreatedAt.Before(fixedStart) || !r.CreatedAt.Before(fixedEnd) {
+			t.Fatalf("created_at %v outside range", r.CreatedAt)
+		}
+		if r.Status.IsTerminal() != (r.ResolvedAt != nil) {
+			t.Fatalf("request %s (%s) resolved_at = %v", r.ID, r.Status, r.ResolvedAt)
+		}
+		if r.ResolvedAt != nil && !r.ResolvedAt.After(r.CreatedAt) {
+			t.Fatalf("resolved_at %v not after created_at %v", r.ResolvedAt, r.CreatedAt)
+		}
+	}
+	for _, w := range DefaultTierMix {
+		if tiers[w.Value] == 0 {
+			t.Errorf("no %s requests generated", w.Value)
+		}
+	}
+	for _, w := range DefaultStatusMix {
+		if statuses[w.Value] == 0 {
+			t.Errorf("no %s requests generated", w.Value)
+		}
+	}
+	if len(agents) != 3 {
+		t.Errorf("expected 3 requestors, got %d", len(agents))
+	}
+
+	// Stored rows carry the generated timestamps.
+	stored, err := database.GetRequest(reqs[0].ID)
+	RequireNoError(t, err, "get request")
+	if !stored.CreatedAt.Equal(reqs[0].CreatedAt) {
+		t.Errorf("stored created_at = %v, want %v", stored.CreatedAt, reqs[0].CreatedAt)
+	}
+}
+
+func TestMakeRequests_Options(t *testing.T) {
+	database := NewTestDB(t)
+	sess := MakeSession(t, database, WithAgent("Solo"))
+	reqs := MakeRequests(t, database, 20,
+		DatasetSessions(sess),
+		DatasetTiers(Weighted[db.RiskTier]{db.RiskTierCritical, 1}),
+		DatasetStatuses(Weighted[db.RequestStatus]{db.StatusPending, 1}),
+	)
+	for _, r := range reqs {
+		if r.RequestorSessionID != sess.ID || r.RiskTier != db.RiskTierCritical || r.Status != db.StatusPending || r.MinApprovals != 2 {
+			t.Fatalf("options not applied: %+v", r)
+		}
+	}
+	pending, err := database.ListPendingRequests(sess.ProjectPath)
+	RequireNoError(t, err, "list pending")
+	RequireLen(t, pending, 20, "pending requests")
+}
+
+func TestMakeRequests_Parallel(t *testing.T) {
+	for i := 0; i < 4; i++ {
+		t.Run(fmt.Sprintf("db%d", i), func(t *testing.T) {
+			t.Parallel()
+			database := NewTestDB(t)
+			reqs := MakeRequests(t, database, 100, DatasetRand(NewRand(t, 3)))
+			stats, err := database.GetStats()
+			RequireNoError(t, err, "stats")
+			RequireEqual(t, len(reqs), stats.RequestCount, "request count")
+		})
+	}
+}
+
+func TestNewRand_SeedEnv(t *testing.T) {
+	t.Setenv(SeedEnv, "99")
+	r := NewRand(t, 1)
+	RequireEqual(t, uint64(99), r.Seed(), "seed")
+
+	other := NewRand(t, 5)
+	if r.Hex(16) != other.Hex(16) {
+		t.Error("expected the env seed to override both randomizers")
+	}
+}
+
+func TestPickWeighted(t *testing.T) {
+	r := NewRand(t, 1)
+	items := []Weighted[string]{{"never", 0}, {"always", 3}}
+	for i := 0; i < 100; i++ {
+		if got := PickWeighted(r, items); got != "always" {
+			t.Fatalf("picked zero-weight item %q", got)
+		}
+	}
+}
+
+func TestMakeReviewMatrix(t *testing.T) {
+	database := NewTestDB(t)
+	rnd := NewRand(t, 11)
+	reqs := MakeRequests(t, database, 30, DatasetRand(rnd), DatasetProject("/matrix"))
+
+	reviewers := []*db.Session{
+		MakeSession(t, database, WithProject("/matrix"), WithAgent("ReviewerA")),
+		MakeSession(t, database, WithProject("/matrix"), WithAgent("ReviewerB")),
+	}
+	// A requestor in the reviewer list must not review its own requests.
+	self, err := database.GetSession(reqs[0].RequestorSessionID)
+	RequireNoError(t, err, "get requestor")
+	reviewers = append(reviewers, self)
+
+	own := 0
+	for _, r := range reqs {
+		if r.RequestorSessionID == self.ID {
+			own++
+		}
+	}
+
+	reviews := MakeReviewMatrix(t, database, reqs, reviewers, rnd)
+	RequireLen(t, reviews, len(reqs)*len(reviewers)-own, "reviews")
+
+	decisions := make(map[db.Decision]int)
+	for _, rev := range reviews {
+		decisions[rev.Decision]++
+	}
+	if decisions[db.DecisionApprove] == 0 || decisions[db.DecisionReject] == 0 {
+		t.Errorf("expected both decisions, got %v", decisions)
+	}
+
+	stored, err := database.ListReviewsForRequest(reqs[0].ID)
+	RequireNoError(t, err, "list reviews")
+	for _, rev := range stored {
+		if rev.ReviewerSessionID == self.ID {
+			t.Fatal("requestor reviewed its own request")
+		}
+	}
+}
@@ -9,4 +9,14 @@
 //
 //	database := testutil.NewTestDB(t)
 //	session := testutil.MakeSession(t, database, testutil.SessionWithAgentName("Agent1"))
+//
+// For performance and pagination tests, MakeRequests and MakeReviewMatrix
+// generate large realistic datasets from a seeded Rand; a failing test logs
+// its seed, and SLB_TEST_SEED replays it:
+//
+//	rnd := testutil.NewRand(t, 42)
+//	reqs := testutil.MakeRequests(t, database, 5000, testutil.DatasetRand(rnd))
+//
+// Integration tests that need a running daemon use the daemontest
+// subpackage, which serves a temp project over a real socket.
 package testutil
@@ -17,7 +17,7 @@ type SessionOption func(*db.Session)
 type RequestOption func(*db.Request)
 
 // MakeSession creates and inserts a session into the DB.
-func MakeSession(t *testing.T, database *db.DB, opts ...SessionOption) *db.Session {
+func MakeSession(t testing.TB, database *db.DB, opts ...SessionOption) *db.Session {
 	t.Helper()
 
 	s := &db.Session{
@@ -35,7 +35,7 @@ func MakeSession(t *testing.T, database *db.DB, opts ...SessionOption) *db.Sessi
 }
 
 // MakeRequest creates and inserts a request linked to a session.
-func MakeRequest(t *testing.T, database *db.DB, session *db.Session, opts ...RequestOption) *db.Request {
+func MakeRequest(t testing.TB, database *db.DB, session *db.Session, opts ...RequestOption) *db.Request {
 	t.Helper()
 
 	now := time.Now().UTC()
@@ -104,6 +104,11 @@ func WithRisk(tier db.RiskTier) RequestOption {
 	return func(r *db.Request) { r.RiskTier = tier }
 }
 
+// WithRiskScore sets the risk score.
+func WithRiskScore(score int) RequestOption {
+	return func(r *db.Request) { r.RiskScore = score }
+}
+
 // WithExpiresAt overrides expiry.
 func WithExpiresAt(t time.Time) RequestOption {
 	return func(r *db.Request) { r.ExpiresAt = &t }
@@ -146,6 +151,16 @@ func WithMinApprovals(n int) RequestOption {
 	return func(r *db.Request) { r.MinApprovals = n }
 }
 
+// WithLabels sets request labels.
+func WithLabels(labels map[string]string) RequestOption {
+	return func(r *db.Request) { r.Labels = labels }
+}
+
+// WithAttachments sets request attachments.
+func WithAttachments(atts ...db.Attachment) RequestOption {
+	return func(r *db.Request) { r.Attachments = atts }
+}
+
 // randHex returns a cryptographically random hex string for unique test IDs.
 func randHex(n int) string {
 	b := make([]byte, (n+1)/2) // Each byte produces 2 hex chars
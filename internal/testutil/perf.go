@@ -0,0 +1,63 @@
+package testutil
+
+import (
+	"os"
+	"sort"
+	"testing"
+	"time"
+)
+
+// SkipPerfBudgetsEnv disables performance budget tests when set, for slow or
+// heavily shared machines.
+const SkipPerfBudgetsEnv = "SLB_SKIP_PERF_BUDGETS"
+
+// RequirePerfBudgets skips the test when timing thresholds would not be
+// meaningful: in -short mode, under the race detector, or when
+// SLB_SKIP_PERF_BUDGETS is set.
+func RequirePerfBudgets(t *testing.T) {
+	t.Helper()
+	switch {
+	case testing.Short():
+		t.Skip("skipping performance budget in -short mode")
+	case raceEnabled:
+		t.Skip("skipping performance budget under the race detector")
+	case os.Getenv(SkipPerfBudgetsEnv) != "":
+		t.Skipf("skipping performance budget (%s set)", SkipPerfBudgetsEnv)
+	}
+}
+
+// Percentile returns the p-th percentile (0-100) of samples using the
+// nearest-rank method. It returns 0 for no samples.
+func Percentile(samples []time.Duration, p float64) time.Duration {
+	if len(samples) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	rank := int(p/100*float64(len(sorted)) + 0.5)
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
+
+// RequireP99 runs fn n times and fails the test when the 99th percentile
+// latency exceeds budget.
+func RequireP99(t *testing.T, name string, n int, budget time.Duration, fn func()) {
+	t.Helper()
+	samples := make([]time.Duration, n)
+	for i := range samples {
+		start := time.Now()
+		fn()
+		samples[i] = time.Since(start)
+	}
+	p99 := Percentile(samples, 99)
+	t.Logf("%s: p50=%v p99=%v over %d runs (budget %v)", name, Percentile(samples, 50), p99, n, budget)
+	if p99 > budget {
+		t.Errorf("%s: p99 latency %v exceeds budget %v", name, p99, budget)
+	}
+}
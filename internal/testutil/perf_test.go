@@ -0,0 +1,43 @@
+package testutil
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPercentile(t *testing.T) {
+	if got := Percentile(nil, 99); got != 0 {
+		t.Fatalf("Percentile(nil) = %v, want 0", got)
+	}
+
+	samples := make([]time.Duration, 100)
+	for i := range samples {
+		// Reverse order to check the input is sorted.
+		samples[i] = time.Duration(100-i) * time.Millisecond
+	}
+	tests := []struct {
+		p    float64
+		want time.Duration
+	}{
+		{0, 1 * time.Millisecond},
+		{50, 50 * time.Millisecond},
+		{99, 99 * time.Millisecond},
+		{100, 100 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		if got := Percentile(samples, tt.p); got != tt.want {
+			t.Errorf("Percentile(p=%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+	if samples[0] != 100*time.Millisecond {
+		t.Error("Percentile must not reorder its input")
+	}
+}
+
+func TestRequireP99_WithinBudget(t *testing.T) {
+	calls := 0
+	RequireP99(t, "noop", 10, time.Second, func() { calls++ })
+	if calls != 10 {
+		t.Fatalf("fn called %d times, want 10", calls)
+	}
+}
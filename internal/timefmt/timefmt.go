@@ -0,0 +1,163 @@
+// Package timefmt renders timestamps for people, either relative to now
+// ("3h ago") or as absolute RFC3339 times in the configured time zone.
+//
+// The style and zone come from general.timestamps and general.timezone (or
+// SLB_TIMESTAMPS and SLB_TIMEZONE), the CLI's --timestamps flag, and the
+// TUI's toggle key. They are process-wide, like the i18n locale.
+package timefmt
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Style is how timestamps are shown.
+type Style string
+
+const (
+	// Relative shows times as an age, such as "3h ago".
+	Relative Style = "relative"
+	// Absolute shows times as RFC3339 in the configured time zone.
+	Absolute Style = "absolute"
+)
+
+// ParseStyle parses a style name; an empty name is Relative.
+func ParseStyle(s string) (Style, error) {
+	switch Style(strings.ToLower(strings.TrimSpace(s))) {
+	case "", Relative:
+		return Relative, nil
+	case Absolute:
+		return Absolute, nil
+	default:
+		return "", fmt.Errorf("unknown timestamp style %q (want relative or absolute)", s)
+	}
+}
+
+// LoadLocation resolves a time zone name: an IANA name such as
+// "Europe/Berlin", "UTC", or empty for the system's local zone.
+func LoadLocation(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.EqualFold(name, "local") {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q", name)
+	}
+	return loc, nil
+}
+
+// Formatter renders timestamps in one style and zone.
+type Formatter struct {
+	Style    Style
+	Location *time.Location
+}
+
+// New builds a formatter from a style name and a time zone name.
+func New(style, timezone string) (Formatter, error) {
+	s, err := ParseStyle(style)
+	if err != nil {
+		return Formatter{}, err
+	}
+	loc, err := LoadLocation(timezone)
+	if err != nil {
+		return Formatter{}, err
+	}
+	return Formatter{Style: s, Location: loc}, nil
+}
+
+// Absolute renders t as RFC3339 in the formatter's zone, or "" for the zero
+// time.
+func (f Formatter) Absolute(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	loc := f.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	return t.In(loc).Format(time.RFC3339)
+}
+
+// IsAbsolute reports whether timestamps are shown as absolute times.
+func (f Formatter) IsAbsolute() bool {
+	return f.Style == Absolute
+}
+
+// Toggled returns the formatter with the other style.
+func (f Formatter) Toggled() Formatter {
+	if f.IsAbsolute() {
+		f.Style = Relative
+	} else {
+		f.Style = Absolute
+	}
+	return f
+}
+
+var (
+	mu      sync.RWMutex
+	current = Formatter{Style: Relative, Location: time.Local}
+)
+
+// Current returns the process-wide formatter.
+func Current() Formatter {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// Set replaces the process-wide formatter.
+func Set(f Formatter) {
+	if f.Location == nil {
+		f.Location = time.Local
+	}
+	if f.Style == "" {
+		f.Style = Relative
+	}
+	mu.Lock()
+	current = f
+	mu.Unlock()
+}
+
+// Format renders t as RFC3339 in the process-wide zone. Machine-readable
+// output uses it whatever the style, so every command agrees on the zone.
+func Format(t time.Time) string {
+	return Current().Absolute(t)
+}
+
+// Show renders t in the formatter's style, relative to now when the style
+// is Relative.
+func (f Formatter) Show(t, now time.Time) string {
+	if f.IsAbsolute() {
+		return f.Absolute(t)
+	}
+	return RelativeTo(t, now)
+}
+
+// RelativeTo renders t as an age or a countdown from now: "just now",
+// "5m ago", "3h ago", "2d ago" or "in 10m".
+func RelativeTo(t, now time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	d := now.Sub(t)
+	format := "%s ago"
+	if d < 0 {
+		d = -d
+		format = "in %s"
+	}
+	var span string
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		span = fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		span = fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		span = fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+	return fmt.Sprintf(format, span)
+}
@@ -0,0 +1,79 @@
+package timefmt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+	f, err := New("ABSOLUTE", "Asia/Tokyo")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if !f.IsAbsolute() || f.Location.String() != "Asia/Tokyo" {
+		t.Fatalf("unexpected formatter %+v", f)
+	}
+	if f, err := New("", ""); err != nil || f.IsAbsolute() || f.Location != time.Local {
+		t.Fatalf("defaults = %+v, %v", f, err)
+	}
+	if _, err := New("sometimes", ""); err == nil {
+		t.Error("expected an unknown style to fail")
+	}
+	if _, err := New("", "Mars/Olympus"); err == nil {
+		t.Error("expected an unknown timezone to fail")
+	}
+}
+
+func TestFormatterShow(t *testing.T) {
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	now := at.Add(3 * time.Hour)
+
+	abs := Formatter{Style: Absolute, Location: tokyo}
+	if got := abs.Show(at, now); got != "2026-03-01T21:00:00+09:00" {
+		t.Errorf("absolute = %q", got)
+	}
+	rel := abs.Toggled()
+	if got := rel.Show(at, now); got != "3h ago" {
+		t.Errorf("relative = %q", got)
+	}
+	if rel.Toggled().Style != Absolute {
+		t.Error("toggling twice should restore the style")
+	}
+	if got := abs.Absolute(time.Time{}); got != "" {
+		t.Errorf("zero time = %q", got)
+	}
+}
+
+func TestRelativeTo(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		at   time.Time
+		want string
+	}{
+		{now.Add(-10 * time.Second), "just now"},
+		{now.Add(-5 * time.Minute), "5m ago"},
+		{now.Add(-26 * time.Hour), "1d ago"},
+		{now.Add(10 * time.Minute), "in 10m"},
+		{time.Time{}, ""},
+	} {
+		if got := RelativeTo(tc.at, now); got != tc.want {
+			t.Errorf("RelativeTo(%v) = %q, want %q", tc.at, got, tc.want)
+		}
+	}
+}
+
+func TestSetAndFormat(t *testing.T) {
+	saved := Current()
+	t.Cleanup(func() { Set(saved) })
+
+	Set(Formatter{Style: Absolute, Location: time.UTC})
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.FixedZone("X", 3600))
+	if got := Format(at); got != "2026-03-01T11:00:00Z" {
+		t.Errorf("Format = %q", got)
+	}
+	Set(Formatter{})
+	if c := Current(); c.Style != Relative || c.Location != time.Local {
+		t.Errorf("Set should fill defaults, got %+v", c)
+	}
+}
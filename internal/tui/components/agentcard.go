@@ -6,8 +6,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
 	"github.com/Dicklesworthstone/slb/internal/tui/icons"
 	"github.com/Dicklesworthstone/slb/internal/tui/theme"
+	"github.com/Dicklesworthstone/slb/internal/utils"
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -101,7 +103,7 @@ func (a *AgentCard) Render() string {
 
 	// Header: icon + name + status dot
 	nameStyle := lipgloss.NewStyle().Foreground(t.Mauve).Bold(true)
-	statusDot := lipgloss.NewStyle().Foreground(statusColor).Render("●")
+	statusDot := lipgloss.NewStyle().Foreground(statusColor).Render(utils.Glyph("●", "["+statusText+"]"))
 	header := fmt.Sprintf("%s %s %s", ic.Agent, nameStyle.Render(a.Agent.Name), statusDot)
 	lines = append(lines, header)
 
@@ -114,8 +116,11 @@ func (a *AgentCard) Render() string {
 	statusBadge := lipgloss.NewStyle().
 		Foreground(statusColor).
 		Render(strings.ToUpper(statusText))
-	timeAgo := formatTimeAgo(a.Agent.LastActive)
-	statusLine := fmt.Sprintf("%s  •  %s", statusBadge, dimStyle.Render(timeAgo))
+	timeAgo := "never"
+	if !a.Agent.LastActive.IsZero() {
+		timeAgo = timefmt.Current().Show(a.Agent.LastActive, time.Now())
+	}
+	statusLine := fmt.Sprintf("%s%s%s", statusBadge, utils.Glyph("  •  ", "  -  "), dimStyle.Render(timeAgo))
 	lines = append(lines, statusLine)
 
 	content := strings.Join(lines, "\n")
@@ -158,7 +163,7 @@ func (a *AgentCard) renderCompact() string {
 
 	nameStyle := lipgloss.NewStyle().Foreground(t.Mauve)
 	dimStyle := lipgloss.NewStyle().Foreground(t.Subtext)
-	statusDot := lipgloss.NewStyle().Foreground(statusColor).Render("●")
+	statusDot := lipgloss.NewStyle().Foreground(statusColor).Render(utils.Glyph("●", "["+compactStatus(a.Agent.Status)+"]"))
 
 	compact := fmt.Sprintf("%s %s %s  %s",
 		ic.Agent,
@@ -173,37 +178,6 @@ func (a *AgentCard) renderCompact() string {
 	return compact
 }
 
-// formatTimeAgo formats a time as a human-readable "ago" string.
-func formatTimeAgo(t time.Time) string {
-	if t.IsZero() {
-		return "never"
-	}
-
-	d := time.Since(t)
-
-	if d < time.Minute {
-		return "just now"
-	} else if d < time.Hour {
-		mins := int(d.Minutes())
-		if mins == 1 {
-			return "1 min ago"
-		}
-		return fmt.Sprintf("%d mins ago", mins)
-	} else if d < 24*time.Hour {
-		hours := int(d.Hours())
-		if hours == 1 {
-			return "1 hour ago"
-		}
-		return fmt.Sprintf("%d hours ago", hours)
-	} else {
-		days := int(d.Hours() / 24)
-		if days == 1 {
-			return "1 day ago"
-		}
-		return fmt.Sprintf("%d days ago", days)
-	}
-}
-
 // RenderAgentCard is a convenience function to render an agent card.
 func RenderAgentCard(agent AgentInfo) string {
 	return NewAgentCard(agent).Render()
@@ -213,3 +187,14 @@ func RenderAgentCard(agent AgentInfo) string {
 func RenderAgentCardCompact(agent AgentInfo) string {
 	return NewAgentCard(agent).AsCompact().Render()
 }
+
+// compactStatus returns the status text shown in place of the colored dot in
+// accessible output mode.
+func compactStatus(s AgentStatus) string {
+	switch s {
+	case AgentStatusActive, AgentStatusIdle, AgentStatusStale, AgentStatusEnded:
+		return string(s)
+	default:
+		return "unknown"
+	}
+}
@@ -5,6 +5,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
+	"github.com/Dicklesworthstone/slb/internal/utils"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -162,27 +166,21 @@ func TestAgentStatusConstants(t *testing.T) {
 	}
 }
 
-func TestFormatTimeAgo(t *testing.T) {
+func TestAgentCardRenderLastActive(t *testing.T) {
 	tests := []struct {
-		name     string
-		time     time.Time
-		expected string
+		name       string
+		lastActive time.Time
+		expected   string
 	}{
-		{"zero time", time.Time{}, "never"},
-		{"just now", time.Now(), "just now"},
-		{"1 min ago", time.Now().Add(-1 * time.Minute), "1 min ago"},
-		{"5 mins ago", time.Now().Add(-5 * time.Minute), "5 mins ago"},
-		{"1 hour ago", time.Now().Add(-1 * time.Hour), "1 hour ago"},
-		{"3 hours ago", time.Now().Add(-3 * time.Hour), "3 hours ago"},
-		{"1 day ago", time.Now().Add(-24 * time.Hour), "1 day ago"},
-		{"3 days ago", time.Now().Add(-72 * time.Hour), "3 days ago"},
+		{"never active", time.Time{}, "never"},
+		{"5m ago", time.Now().Add(-5 * time.Minute), "5m ago"},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			got := formatTimeAgo(tc.time)
-			if got != tc.expected {
-				t.Errorf("formatTimeAgo: expected %q, got %q", tc.expected, got)
+			agent := AgentInfo{Name: "Agent1", Program: "claude", Model: "opus", Status: AgentStatusActive, LastActive: tc.lastActive}
+			if got := NewAgentCard(agent).Render(); !strings.Contains(got, tc.expected) {
+				t.Errorf("Render output should contain %q, got:\n%s", tc.expected, got)
 			}
 		})
 	}
@@ -440,6 +438,13 @@ func TestRenderStatusBadgeCompact(t *testing.T) {
 	}
 }
 
+func TestRenderReadOnlyBadge(t *testing.T) {
+	result := RenderReadOnlyBadge()
+	if !strings.Contains(result, ReadOnlyLabel) {
+		t.Errorf("RenderReadOnlyBadge() = %q, want it to contain %q", result, ReadOnlyLabel)
+	}
+}
+
 // ============== RiskIndicator Tests ==============
 
 func TestNewRiskIndicator(t *testing.T) {
@@ -593,6 +598,35 @@ func TestTimelineChaining(t *testing.T) {
 	}
 }
 
+func TestTimelineFromEvents(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	events := TimelineFromEvents([]*db.RequestEvent{
+		{Type: "created", Actor: "GreenLake", Details: "dangerous", CreatedAt: now},
+		{Type: "reviewed", Actor: "BlueSnow", Details: "approve: LGTM", CreatedAt: now.Add(time.Minute)},
+	})
+	if len(events) != 2 || events[1].State != "reviewed" || events[1].Actor != "BlueSnow" || !events[0].Timestamp.Equal(now) {
+		t.Fatalf("events = %+v", events)
+	}
+
+	saved := timefmt.Current()
+	t.Cleanup(func() { timefmt.Set(saved) })
+	timefmt.Set(timefmt.Formatter{Location: time.UTC})
+
+	out := RenderTimelineExpanded(events, "pending")
+	for _, want := range []string{"CREATED", "REVIEWED", "by BlueSnow", "approve: LGTM", "2026-01-02 03:05:05"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expanded timeline missing %q:\n%s", want, out)
+		}
+	}
+
+	// Absolute timestamps are RFC3339 in the configured zone.
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	timefmt.Set(timefmt.Formatter{Style: timefmt.Absolute, Location: tokyo})
+	if out := RenderTimelineExpanded(events, "pending"); !strings.Contains(out, "2026-01-02T12:05:05+09:00") {
+		t.Errorf("expected an RFC3339 Tokyo time:\n%s", out)
+	}
+}
+
 func TestTimelineRenderNormal(t *testing.T) {
 	now := time.Now()
 	tl := NewTimeline().
@@ -946,3 +980,86 @@ func TestSpinnerStyleConstants(t *testing.T) {
 		seen[s] = true
 	}
 }
+
+// ============== Mouse Tests ==============
+
+func TestClickTracker(t *testing.T) {
+	var c ClickTracker
+	now := time.Now()
+
+	if c.Click("row-1", now) {
+		t.Error("first click should not be a double-click")
+	}
+	if !c.Click("row-1", now.Add(100*time.Millisecond)) {
+		t.Error("second quick click on the same row should be a double-click")
+	}
+	if c.Click("row-1", now.Add(200*time.Millisecond)) {
+		t.Error("a third click should start over")
+	}
+	if c.Click("row-2", now.Add(250*time.Millisecond)) {
+		t.Error("clicking a different row should not be a double-click")
+	}
+	if c.Click("row-2", now.Add(250*time.Millisecond+DoubleClickInterval+time.Millisecond)) {
+		t.Error("slow clicks should not be a double-click")
+	}
+}
+
+func TestMouseHelpers(t *testing.T) {
+	press := func(b tea.MouseButton) tea.MouseMsg {
+		return tea.MouseMsg{Action: tea.MouseActionPress, Button: b}
+	}
+	if !IsLeftClick(press(tea.MouseButtonLeft)) || IsLeftClick(press(tea.MouseButtonRight)) {
+		t.Error("IsLeftClick should match only left presses")
+	}
+	if IsLeftClick(tea.MouseMsg{Action: tea.MouseActionRelease, Button: tea.MouseButtonLeft}) {
+		t.Error("IsLeftClick should ignore releases")
+	}
+	if WheelDelta(press(tea.MouseButtonWheelUp)) != -1 || WheelDelta(press(tea.MouseButtonWheelDown)) != 1 || WheelDelta(press(tea.MouseButtonLeft)) != 0 {
+		t.Error("unexpected WheelDelta results")
+	}
+}
+
+func TestTableRowAtLine(t *testing.T) {
+	table := NewTable([]Column{{Header: "A"}}).WithRows([][]string{{"x"}, {"y"}})
+	for line, want := range map[int]int{0: -1, 1: -1, 2: 0, 3: 1, 4: -1} {
+		if got := table.RowAtLine(line); got != want {
+			t.Errorf("RowAtLine(%d) = %d, want %d", line, got, want)
+		}
+	}
+	table.ShowHeader = false
+	if got := table.RowAtLine(0); got != 0 {
+		t.Errorf("without header, RowAtLine(0) = %d, want 0", got)
+	}
+}
+
+func TestTablePlainOutputMarksSelection(t *testing.T) {
+	prev := utils.PlainOutput()
+	utils.SetPlainOutput(true)
+	t.Cleanup(func() { utils.SetPlainOutput(prev) })
+
+	table := NewTable([]Column{{Header: "A"}}).
+		WithRows([][]string{{"x"}, {"y"}}).
+		WithSelection(1)
+	lines := strings.Split(table.Render(), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 lines, got %d", len(lines))
+	}
+	if !strings.HasPrefix(lines[2], "  x") || !strings.HasPrefix(lines[3], "> y") {
+		t.Errorf("expected selected row marked with '>', got %q", lines[2:])
+	}
+	if got := table.RowAtLine(3); got != 1 {
+		t.Errorf("RowAtLine(3) = %d, want 1", got)
+	}
+}
+
+func TestAgentCardPlainOutputShowsStatusLabel(t *testing.T) {
+	prev := utils.PlainOutput()
+	utils.SetPlainOutput(true)
+	t.Cleanup(func() { utils.SetPlainOutput(prev) })
+
+	card := NewAgentCard(AgentInfo{Name: "BlueLake", Program: "codex", Status: AgentStatusIdle}).AsCompact()
+	out := card.Render()
+	if !strings.Contains(out, "[idle]") || strings.Contains(out, "●") {
+		t.Errorf("expected ASCII status label, got %q", out)
+	}
+}
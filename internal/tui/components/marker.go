@@ -0,0 +1,16 @@
+package components
+
+import "github.com/Dicklesworthstone/slb/internal/utils"
+
+// SelectionMarker returns a textual prefix marking the selected row. Colors
+// and reverse video are stripped in accessible output mode, so the selection
+// needs to be visible in the text itself; otherwise it returns "".
+func SelectionMarker(selected bool) string {
+	if !utils.PlainOutput() {
+		return ""
+	}
+	if selected {
+		return "> "
+	}
+	return "  "
+}
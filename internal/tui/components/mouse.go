@@ -0,0 +1,52 @@
+// Package components provides mouse helpers shared by list views.
+package components
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// DoubleClickInterval is the maximum gap between two clicks on the same
+// target for them to count as a double-click.
+const DoubleClickInterval = 400 * time.Millisecond
+
+// ClickTracker detects double-clicks on list rows. Bubble Tea only reports
+// individual presses, so the tracker remembers the last clicked target.
+type ClickTracker struct {
+	target string
+	at     time.Time
+}
+
+// Click records a click on target and reports whether it completes a
+// double-click. A completed double-click resets the tracker so a third click
+// starts over.
+func (c *ClickTracker) Click(target string, now time.Time) bool {
+	if target != "" && target == c.target && now.Sub(c.at) <= DoubleClickInterval {
+		*c = ClickTracker{}
+		return true
+	}
+	c.target = target
+	c.at = now
+	return false
+}
+
+// IsLeftClick reports whether msg is a left-button press.
+func IsLeftClick(msg tea.MouseMsg) bool {
+	return msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft
+}
+
+// WheelDelta returns -1 for wheel up, 1 for wheel down, and 0 otherwise.
+func WheelDelta(msg tea.MouseMsg) int {
+	if msg.Action != tea.MouseActionPress {
+		return 0
+	}
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		return -1
+	case tea.MouseButtonWheelDown:
+		return 1
+	default:
+		return 0
+	}
+}
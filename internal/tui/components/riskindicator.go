@@ -50,25 +50,20 @@ func (r *RiskIndicator) Render() string {
 
 	// Get colors based on tier
 	var fg, bg lipgloss.Color
-	var emoji string
 
 	switch tier {
 	case "critical":
 		fg, bg = t.Base, t.Red
-		emoji = "🔴"
 	case "dangerous":
 		fg, bg = t.Base, t.Peach
-		emoji = "🟠"
 	case "caution":
 		fg, bg = t.Base, t.Yellow
-		emoji = "🟡"
 	case "safe":
 		fg, bg = t.Base, t.Green
-		emoji = "🟢"
 	default:
 		fg, bg = t.Text, t.Surface
-		emoji = "⚪"
 	}
+	emoji := theme.TierEmoji(tier)
 
 	style := lipgloss.NewStyle().
 		Foreground(fg).
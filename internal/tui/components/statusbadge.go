@@ -4,6 +4,7 @@ package components
 import (
 	"strings"
 
+	"github.com/Dicklesworthstone/slb/internal/i18n"
 	"github.com/Dicklesworthstone/slb/internal/tui/icons"
 	"github.com/Dicklesworthstone/slb/internal/tui/theme"
 	"github.com/charmbracelet/lipgloss"
@@ -79,13 +80,13 @@ func (s *StatusBadge) Render() string {
 		if s.Compact {
 			content = icon
 		} else {
-			content = icon + " " + strings.ToUpper(s.Status)
+			content = icon + " " + i18n.Status(s.Status)
 		}
 	} else {
 		if s.Compact {
 			content = strings.ToUpper(s.Status[:1])
 		} else {
-			content = strings.ToUpper(s.Status)
+			content = i18n.Status(s.Status)
 		}
 	}
 
@@ -101,3 +102,17 @@ func RenderStatusBadge(status string) string {
 func RenderStatusBadgeCompact(status string) string {
 	return NewStatusBadge(status).AsCompact().Render()
 }
+
+// ReadOnlyLabel is the header label shown in read-only (spectator) mode.
+const ReadOnlyLabel = "READ-ONLY"
+
+// RenderReadOnlyBadge renders the badge that marks a view as read-only.
+func RenderReadOnlyBadge() string {
+	t := theme.Current
+	return lipgloss.NewStyle().
+		Foreground(t.Base).
+		Background(t.Yellow).
+		Bold(true).
+		Padding(0, 1).
+		Render(ReadOnlyLabel)
+}
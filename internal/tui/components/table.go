@@ -89,6 +89,15 @@ func (t *Table) Render() string {
 	// Calculate column widths
 	widths := t.calculateWidths()
 
+	// In accessible mode the selected row is marked in text; keep every line
+	// indented by the same amount so columns stay aligned.
+	marker := func(selected bool) string {
+		if t.SelectedRow < 0 {
+			return ""
+		}
+		return SelectionMarker(selected)
+	}
+
 	var lines []string
 
 	// Header
@@ -103,12 +112,12 @@ func (t *Table) Render() string {
 			cell := t.padCell(col.Header, widths[i], col.Align)
 			headerCells = append(headerCells, headerStyle.Render(cell))
 		}
-		lines = append(lines, strings.Join(headerCells, " "))
+		lines = append(lines, marker(false)+strings.Join(headerCells, " "))
 
 		// Separator
 		sepStyle := lipgloss.NewStyle().Foreground(th.Overlay0)
 		sep := sepStyle.Render(strings.Repeat("─", t.totalWidth(widths)))
-		lines = append(lines, sep)
+		lines = append(lines, marker(false)+sep)
 	}
 
 	// Rows
@@ -132,12 +141,24 @@ func (t *Table) Render() string {
 			cell := t.padCell(cellContent, widths[i], col.Align)
 			cells = append(cells, baseStyle.Render(cell))
 		}
-		lines = append(lines, strings.Join(cells, " "))
+		lines = append(lines, marker(rowIdx == t.SelectedRow)+strings.Join(cells, " "))
 	}
 
 	return strings.Join(lines, "\n")
 }
 
+// RowAtLine maps a line offset within the rendered table to a row index.
+// Returns -1 for header lines or lines past the last row.
+func (t *Table) RowAtLine(line int) int {
+	if t.ShowHeader {
+		line -= 2 // header + separator
+	}
+	if line < 0 || line >= len(t.Rows) {
+		return -1
+	}
+	return line
+}
+
 // calculateWidths calculates column widths.
 func (t *Table) calculateWidths() []int {
 	widths := make([]int, len(t.Columns))
@@ -6,7 +6,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
 	"github.com/Dicklesworthstone/slb/internal/tui/theme"
+	"github.com/Dicklesworthstone/slb/internal/utils"
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -18,6 +21,20 @@ type TimelineEvent struct {
 	Details   string // Additional details
 }
 
+// TimelineFromEvents converts recorded request events to timeline events.
+func TimelineFromEvents(events []*db.RequestEvent) []TimelineEvent {
+	out := make([]TimelineEvent, 0, len(events))
+	for _, e := range events {
+		out = append(out, TimelineEvent{
+			State:     e.Type,
+			Timestamp: e.CreatedAt,
+			Actor:     e.Actor,
+			Details:   e.Details,
+		})
+	}
+	return out
+}
+
 // Timeline renders a request lifecycle timeline.
 type Timeline struct {
 	Events   []TimelineEvent
@@ -95,7 +112,7 @@ func (t *Timeline) renderCompact() string {
 			color = th.Overlay0
 		}
 
-		dot := lipgloss.NewStyle().Foreground(color).Render("●")
+		dot := lipgloss.NewStyle().Foreground(color).Render(utils.Glyph("●", "*"))
 		parts = append(parts, dot)
 	}
 
@@ -107,7 +124,7 @@ func (t *Timeline) renderCompact() string {
 			if activeIdx >= 0 && i <= activeIdx {
 				arrowColor = th.Green
 			}
-			arrow := lipgloss.NewStyle().Foreground(arrowColor).Render(" → ")
+			arrow := lipgloss.NewStyle().Foreground(arrowColor).Render(utils.Glyph(" → ", " -> "))
 			result += arrow
 		}
 		result += part
@@ -137,18 +154,20 @@ func (t *Timeline) renderNormal() string {
 			stateColor = th.Blue
 		case "timeout", "escalated":
 			stateColor = th.Yellow
+		case "reviewed", "viewed", "edit_proposed", "edit_accepted":
+			stateColor = th.Mauve
 		default:
 			stateColor = th.Subtext
 		}
 
 		// Build the line
-		connector := "│"
-		node := "●"
+		connector := utils.Glyph("│", "|")
+		node := utils.Glyph("●", "*")
 		if isLast {
 			connector = " "
 		}
 		if isCurrent {
-			node = "◉"
+			node = utils.Glyph("◉", "@")
 		}
 
 		nodeStyle := lipgloss.NewStyle().Foreground(stateColor).Bold(isCurrent)
@@ -163,7 +182,7 @@ func (t *Timeline) renderNormal() string {
 		if !event.Timestamp.IsZero() {
 			timeStr = lipgloss.NewStyle().
 				Foreground(th.Subtext).
-				Render("  " + event.Timestamp.Format("15:04:05"))
+				Render("  " + compactTime(event.Timestamp))
 		}
 
 		line := fmt.Sprintf("%s %s%s",
@@ -203,6 +222,8 @@ func (t *Timeline) renderExpanded() string {
 			stateColor = th.Blue
 		case "timeout", "escalated":
 			stateColor = th.Yellow
+		case "reviewed", "viewed", "edit_proposed", "edit_accepted":
+			stateColor = th.Mauve
 		default:
 			stateColor = th.Subtext
 		}
@@ -210,9 +231,9 @@ func (t *Timeline) renderExpanded() string {
 		nodeStyle := lipgloss.NewStyle().Foreground(stateColor).Bold(isCurrent)
 		connectorStyle := lipgloss.NewStyle().Foreground(th.Overlay0)
 
-		node := "●"
+		node := utils.Glyph("●", "*")
 		if isCurrent {
-			node = "◉"
+			node = utils.Glyph("◉", "@")
 		}
 
 		stateLabel := lipgloss.NewStyle().
@@ -226,24 +247,24 @@ func (t *Timeline) renderExpanded() string {
 
 		// Details (indented)
 		if !event.Timestamp.IsZero() {
-			timeStr := event.Timestamp.Format("2006-01-02 15:04:05")
-			lines = append(lines, connectorStyle.Render("│  ")+
+			timeStr := fullTime(event.Timestamp)
+			lines = append(lines, connectorStyle.Render(utils.Glyph("│", "|")+"  ")+
 				lipgloss.NewStyle().Foreground(th.Subtext).Render(timeStr))
 		}
 
 		if event.Actor != "" {
-			lines = append(lines, connectorStyle.Render("│  ")+
+			lines = append(lines, connectorStyle.Render(utils.Glyph("│", "|")+"  ")+
 				lipgloss.NewStyle().Foreground(th.Subtext).Render("by "+event.Actor))
 		}
 
 		if event.Details != "" {
-			lines = append(lines, connectorStyle.Render("│  ")+
+			lines = append(lines, connectorStyle.Render(utils.Glyph("│", "|")+"  ")+
 				lipgloss.NewStyle().Foreground(th.Text).Render(event.Details))
 		}
 
 		// Connector to next
 		if !isLast {
-			lines = append(lines, connectorStyle.Render("│"))
+			lines = append(lines, connectorStyle.Render(utils.Glyph("│", "|")))
 		}
 	}
 
@@ -269,6 +290,15 @@ func RenderTimeline(events []TimelineEvent, current string) string {
 	return tl.Render()
 }
 
+// RenderTimelineExpanded is a convenience function for the expanded timeline.
+func RenderTimelineExpanded(events []TimelineEvent, current string) string {
+	tl := NewTimeline().WithCurrent(current).AsExpanded()
+	for _, e := range events {
+		tl.AddEvent(e.State, e.Timestamp, e.Actor, e.Details)
+	}
+	return tl.Render()
+}
+
 // RenderTimelineCompact is a convenience function for compact timeline.
 func RenderTimelineCompact(events []TimelineEvent, current string) string {
 	tl := NewTimeline().WithCurrent(current).AsCompact()
@@ -277,3 +307,23 @@ func RenderTimelineCompact(events []TimelineEvent, current string) string {
 	}
 	return tl.Render()
 }
+
+// compactTime renders a timeline timestamp: the time of day in the
+// configured zone, or the full time when absolute timestamps are on.
+func compactTime(t time.Time) string {
+	f := timefmt.Current()
+	if f.IsAbsolute() {
+		return f.Absolute(t)
+	}
+	return t.In(f.Location).Format("15:04:05")
+}
+
+// fullTime renders a timestamp with its date in the configured zone, as
+// RFC3339 when absolute timestamps are on.
+func fullTime(t time.Time) string {
+	f := timefmt.Current()
+	if f.IsAbsolute() {
+		return f.Absolute(t)
+	}
+	return t.In(f.Location).Format("2006-01-02 15:04:05")
+}
@@ -1,6 +1,7 @@
 package dashboard
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -10,19 +11,28 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/Dicklesworthstone/slb/internal/clock"
+	"github.com/Dicklesworthstone/slb/internal/daemon"
 	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/i18n"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
 	"github.com/Dicklesworthstone/slb/internal/tui/components"
 	"github.com/Dicklesworthstone/slb/internal/tui/theme"
+	"github.com/Dicklesworthstone/slb/internal/utils"
 )
 
 const refreshInterval = 2 * time.Second
 
+// awaitingHumanBadge marks pending requests that no agent reviewer acted on in time.
+const awaitingHumanBadge = "HUMAN"
+
 type focusPanel int
 
 const (
 	focusAgents focusPanel = iota
 	focusPending
 	focusActivity
+	focusPreview
 )
 
 type requestRow struct {
@@ -31,6 +41,15 @@ type requestRow struct {
 	Command   string
 	Requestor string
 	CreatedAt time.Time
+	// AwaitingHuman is set once the daemon paged a human after reviewer inactivity.
+	AwaitingHuman bool
+
+	// Detail preview fields.
+	Labels       string
+	Reason       string
+	Approvals    int
+	MinApprovals int
+	ExpiresAt    *time.Time
 }
 
 type refreshMsg struct{}
@@ -69,6 +88,19 @@ type Model struct {
 	lastErr     error
 	lastRefresh time.Time
 
+	// ReadOnly marks the dashboard as a spectator view in the header.
+	ReadOnly bool
+
+	// Pane layout preferences, persisted to layoutPath when changed.
+	layout     Layout
+	layoutPath string
+
+	// Mouse double-click detection
+	clicks components.ClickTracker
+
+	// clock renders relative times ("5m ago"); the real clock when nil.
+	clock clock.Clock
+
 	// Callbacks
 	OnPatterns func() // Navigate to pattern management view
 	OnHistory  func() // Navigate to history view
@@ -81,12 +113,25 @@ func New(projectPath string) Model {
 			projectPath = pwd
 		}
 	}
+	layoutPath := DefaultLayoutPath()
 	return Model{
 		projectPath: projectPath,
 		focus:       focusPending,
+		layout:      LoadLayout(layoutPath),
+		layoutPath:  layoutPath,
+		clock:       clock.Real,
 	}
 }
 
+// SetClock sets the clock relative times are rendered against.
+func (m *Model) SetClock(c clock.Clock) {
+	m.clock = clock.OrReal(c)
+}
+
+func (m Model) now() time.Time {
+	return clock.OrReal(m.clock).Now()
+}
+
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(loadCmd(m.projectPath), tickCmd())
 }
@@ -98,6 +143,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		m.ready = true
 		return m, nil
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
 	case refreshMsg:
 		return m, tea.Batch(loadCmd(m.projectPath), tickCmd())
 	case dataMsg:
@@ -116,18 +163,31 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch msg.String() {
 		case "q", "ctrl+c":
 			return m, tea.Quit
-		case "tab":
-			m.focus = (m.focus + 1) % 3
+		case "tab", "right", "l":
+			m.cycleFocus(1)
 			return m, nil
-		case "shift+tab":
-			m.focus = (m.focus + 2) % 3
+		case "shift+tab", "left":
+			m.cycleFocus(-1)
 			return m, nil
-		case "left":
-			m.focus = (m.focus + 2) % 3
-			return m, nil
-		case "right", "l":
-			m.focus = (m.focus + 1) % 3
+		case "1", "2", "3", "4":
+			m.focusPane(focusPanel(msg.String()[0] - '1'))
 			return m, nil
+		case "+", "=":
+			return m, m.resizeFocused(resizeStep)
+		case "-":
+			return m, m.resizeFocused(-resizeStep)
+		case "A":
+			m.layout.ShowAgents = !m.layout.ShowAgents
+			return m, m.layoutChanged()
+		case "E":
+			m.layout.ShowActivity = !m.layout.ShowActivity
+			return m, m.layoutChanged()
+		case "P":
+			m.layout.ShowPreview = !m.layout.ShowPreview
+			return m, m.layoutChanged()
+		case "0":
+			m.layout = DefaultLayout()
+			return m, m.layoutChanged()
 		case "up", "k":
 			m.moveSelection(-1)
 			return m, nil
@@ -144,7 +204,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.OnHistory()
 			} else {
 				// Fallback to left focus if no handler
-				m.focus = (m.focus + 2) % 3
+				m.cycleFocus(-1)
 			}
 			return m, nil
 		}
@@ -162,31 +222,7 @@ func (m Model) View() string {
 
 	header := m.renderHeader()
 	footer := m.renderFooter()
-
-	bodyHeight := m.height - lipgloss.Height(header) - lipgloss.Height(footer)
-	if bodyHeight < 6 {
-		bodyHeight = 6
-	}
-
-	gap := 1
-	leftW := maxInt(28, m.width/4)
-	rightW := maxInt(28, m.width/4)
-	centerW := m.width - leftW - rightW - (2 * gap)
-	if centerW < 30 {
-		centerW = 30
-	}
-
-	agentsPanel := m.renderAgentsPanel(leftW, bodyHeight)
-	pendingPanel := m.renderPendingPanel(centerW, bodyHeight)
-	activityPanel := m.renderActivityPanel(rightW, bodyHeight)
-
-	body := lipgloss.JoinHorizontal(lipgloss.Top,
-		agentsPanel,
-		lipgloss.NewStyle().Width(gap).Render(""),
-		pendingPanel,
-		lipgloss.NewStyle().Width(gap).Render(""),
-		activityPanel,
-	)
+	body := m.renderBody(m.bodyHeight())
 
 	// Keep the whole view on a consistent background.
 	page := lipgloss.NewStyle().Background(th.Base).Render(
@@ -195,11 +231,19 @@ func (m Model) View() string {
 	return page
 }
 
+// bodyHeight is the height available to the panes between header and footer.
+func (m Model) bodyHeight() int {
+	return maxInt(6, m.height-lipgloss.Height(m.renderHeader())-lipgloss.Height(m.renderFooter()))
+}
+
 func (m Model) renderHeader() string {
 	th := theme.Current
 
 	title := lipgloss.NewStyle().Foreground(th.Mauve).Bold(true).Render("SLB Dashboard")
-	statusDot := lipgloss.NewStyle().Foreground(th.Yellow).Render("●")
+	if m.ReadOnly {
+		title += " " + components.RenderReadOnlyBadge()
+	}
+	statusDot := lipgloss.NewStyle().Foreground(th.Yellow).Render(utils.Glyph("●", "*"))
 	daemon := lipgloss.NewStyle().Foreground(th.Subtext).Render(fmt.Sprintf("%s Daemon: unknown", statusDot))
 
 	row := lipgloss.JoinHorizontal(lipgloss.Top,
@@ -219,11 +263,11 @@ func (m Model) renderHeader() string {
 func (m Model) renderFooter() string {
 	th := theme.Current
 
-	hint := lipgloss.NewStyle().Foreground(th.Subtext).Render("[tab] focus  [↑/↓] navigate  [m] patterns  [h] history  [q] quit")
+	hint := lipgloss.NewStyle().Foreground(th.Subtext).Render("[tab/1-4] focus  [↑/↓] navigate  [+/-] resize  [A/E/P] panes  [m] patterns  [h] history  [q] quit")
 
 	right := ""
 	if !m.lastRefresh.IsZero() {
-		right = "refreshed " + formatTimeAgo(m.lastRefresh)
+		right = "refreshed " + timefmt.Current().Show(m.lastRefresh, m.now())
 	}
 	if m.lastErr != nil {
 		right = "error: " + m.lastErr.Error()
@@ -246,21 +290,22 @@ func (m Model) renderFooter() string {
 func (m Model) renderAgentsPanel(width, height int) string {
 	th := theme.Current
 
-	title := lipgloss.NewStyle().Foreground(th.Blue).Bold(true).Render(fmt.Sprintf("Agents (%d)", len(m.agents)))
+	title := lipgloss.NewStyle().Foreground(th.Blue).Bold(true).Render(i18n.T("tui.dashboard.agents", len(m.agents)))
 
 	lines := []string{title}
 	visible := maxInt(1, height-4) // title + border padding
 	start, end := window(m.agentOff, len(m.agents), visible)
 
 	for i := start; i < end; i++ {
+		selected := i == m.agentSel && m.focus == focusAgents
 		card := components.NewAgentCard(m.agents[i]).
 			AsCompact().
-			AsSelected(i == m.agentSel && m.focus == focusAgents).
+			AsSelected(selected).
 			WithWidth(width - 4)
-		lines = append(lines, card.Render())
+		lines = append(lines, components.SelectionMarker(selected)+card.Render())
 	}
 	if len(m.agents) == 0 {
-		lines = append(lines, lipgloss.NewStyle().Foreground(th.Subtext).Render("No active sessions"))
+		lines = append(lines, lipgloss.NewStyle().Foreground(th.Subtext).Render(i18n.T("tui.dashboard.no_agents")))
 	}
 
 	borderColor := th.Overlay0
@@ -280,31 +325,42 @@ func (m Model) renderAgentsPanel(width, height int) string {
 func (m Model) renderPendingPanel(width, height int) string {
 	th := theme.Current
 
-	title := lipgloss.NewStyle().Foreground(th.Blue).Bold(true).Render(fmt.Sprintf("Pending Requests (%d)", len(m.pending)))
+	title := lipgloss.NewStyle().Foreground(th.Blue).Bold(true).Render(i18n.T("tui.dashboard.pending", len(m.pending)))
 	lines := []string{title}
 
 	visible := maxInt(1, height-4)
 	start, end := window(m.pendingOff, len(m.pending), visible)
+	now := m.now()
 
 	lineStyle := lipgloss.NewStyle().Foreground(th.Text)
 	selectedStyle := lipgloss.NewStyle().Foreground(th.Text).Background(th.Surface1).Bold(true)
+	humanBadgeStyle := lipgloss.NewStyle().Foreground(th.Base).Background(th.Peach).Bold(true)
 
 	for i := start; i < end; i++ {
 		r := m.pending[i]
 		emoji := theme.TierEmoji(r.Tier)
-		age := formatTimeAgo(r.CreatedAt)
-		label := fmt.Sprintf("%s %s  •  %s  •  %s", emoji, r.Command, r.Requestor, age)
-		label = truncateRunes(label, width-4)
+		age := timefmt.Current().Show(r.CreatedAt, now)
+		sep := utils.Glyph("  •  ", "  -  ")
+		label := fmt.Sprintf("%s %s%s%s%s%s", emoji, r.Command, sep, r.Requestor, sep, age)
+
+		badge := ""
+		if r.AwaitingHuman {
+			badge = humanBadgeStyle.Render(awaitingHumanBadge) + " "
+		}
+
+		selected := i == m.pendingSel && (m.focus == focusPending || m.focus == focusPreview)
+		marker := components.SelectionMarker(selected)
+		label = truncateRunes(label, width-4-lipgloss.Width(badge)-lipgloss.Width(marker))
 
 		style := lineStyle
-		if i == m.pendingSel && m.focus == focusPending {
+		if selected {
 			style = selectedStyle
 		}
-		lines = append(lines, style.Render(label))
+		lines = append(lines, marker+badge+style.Render(label))
 	}
 
 	if len(m.pending) == 0 {
-		lines = append(lines, lipgloss.NewStyle().Foreground(th.Subtext).Render("No pending requests"))
+		lines = append(lines, lipgloss.NewStyle().Foreground(th.Subtext).Render(i18n.T("tui.dashboard.no_pending")))
 	}
 
 	borderColor := th.Overlay0
@@ -321,10 +377,65 @@ func (m Model) renderPendingPanel(width, height int) string {
 		Render(strings.Join(lines, "\n"))
 }
 
+// renderPreviewPanel shows details of the selected pending request so large
+// terminals can review the queue without switching views.
+func (m Model) renderPreviewPanel(width, height int) string {
+	th := theme.Current
+
+	title := lipgloss.NewStyle().Foreground(th.Blue).Bold(true).Render("Preview")
+	lines := []string{title}
+
+	labelStyle := lipgloss.NewStyle().Foreground(th.Subtext)
+	valueStyle := lipgloss.NewStyle().Foreground(th.Text)
+	field := func(label, value string) string {
+		return labelStyle.Render(label+": ") + valueStyle.Render(truncateRunes(value, width-6-len(label)))
+	}
+
+	if m.pendingSel >= 0 && m.pendingSel < len(m.pending) {
+		r := m.pending[m.pendingSel]
+		lines = append(lines,
+			field("ID", r.ID),
+			field("Tier", theme.TierEmoji(r.Tier)+" "+strings.ToUpper(r.Tier)),
+			field("Command", r.Command),
+			field("Requestor", r.Requestor),
+			field("Created", timefmt.Current().Show(r.CreatedAt, m.now())),
+			field("Approvals", fmt.Sprintf("%d/%d", r.Approvals, r.MinApprovals)),
+		)
+		if r.ExpiresAt != nil {
+			lines = append(lines, field("Expires", r.ExpiresAt.Local().Format("15:04:05")))
+		}
+		if r.Labels != "" {
+			lines = append(lines, field("Labels", r.Labels))
+		}
+		if r.Reason != "" {
+			lines = append(lines, field("Reason", r.Reason))
+		}
+		if r.AwaitingHuman {
+			lines = append(lines, lipgloss.NewStyle().Foreground(th.Peach).Bold(true).Render("Awaiting human review"))
+		}
+		lines = append(lines, labelStyle.Render("[enter] open details"))
+	} else {
+		lines = append(lines, labelStyle.Render("No request selected"))
+	}
+
+	borderColor := th.Overlay0
+	if m.focus == focusPreview {
+		borderColor = th.Mauve
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(borderColor).
+		Padding(0, 1).
+		Width(width).
+		Height(height).
+		Render(strings.Join(lines, "\n"))
+}
+
 func (m Model) renderActivityPanel(width, height int) string {
 	th := theme.Current
 
-	title := lipgloss.NewStyle().Foreground(th.Blue).Bold(true).Render("Recent Activity")
+	title := lipgloss.NewStyle().Foreground(th.Blue).Bold(true).Render(i18n.T("tui.dashboard.activity"))
 	lines := []string{title}
 
 	visible := maxInt(1, height-4)
@@ -334,16 +445,18 @@ func (m Model) renderActivityPanel(width, height int) string {
 	selectedStyle := lipgloss.NewStyle().Foreground(th.Text).Background(th.Surface1).Bold(true)
 
 	for i := start; i < end; i++ {
-		line := truncateRunes(m.activity[i], width-4)
+		selected := i == m.activitySel && m.focus == focusActivity
+		marker := components.SelectionMarker(selected)
+		line := truncateRunes(m.activity[i], width-4-lipgloss.Width(marker))
 		style := lineStyle
-		if i == m.activitySel && m.focus == focusActivity {
+		if selected {
 			style = selectedStyle
 		}
-		lines = append(lines, style.Render(line))
+		lines = append(lines, marker+style.Render(line))
 	}
 
 	if len(m.activity) == 0 {
-		lines = append(lines, lipgloss.NewStyle().Foreground(th.Subtext).Render("No recent activity"))
+		lines = append(lines, lipgloss.NewStyle().Foreground(th.Subtext).Render(i18n.T("tui.dashboard.no_activity")))
 	}
 
 	borderColor := th.Overlay0
@@ -373,7 +486,7 @@ func (m *Model) moveSelection(delta int) {
 	case focusAgents:
 		m.agentSel += delta
 		m.agentSel, m.agentOff = clampSelection(m.agentSel, m.agentOff, len(m.agents), m.visibleRows())
-	case focusPending:
+	case focusPending, focusPreview:
 		m.pendingSel += delta
 		m.pendingSel, m.pendingOff = clampSelection(m.pendingSel, m.pendingOff, len(m.pending), m.visibleRows())
 	case focusActivity:
@@ -383,9 +496,10 @@ func (m *Model) moveSelection(delta int) {
 }
 
 // SelectedRequestID returns the ID of the currently selected pending request.
-// Returns empty string if no request is selected or if the pending requests panel is not focused.
+// Returns empty string if no request is selected or if neither the pending
+// requests panel nor the preview pane is focused.
 func (m *Model) SelectedRequestID() string {
-	if m.focus != focusPending {
+	if m.focus != focusPending && m.focus != focusPreview {
 		return ""
 	}
 	if m.pendingSel < 0 || m.pendingSel >= len(m.pending) {
@@ -416,7 +530,18 @@ func loadCmd(projectPath string) tea.Cmd {
 	}
 }
 
+// daemonReadTimeout bounds the attempt to read from a running daemon before
+// falling back to the database.
+const daemonReadTimeout = 250 * time.Millisecond
+
+// loadData reads sessions and pending requests from the project daemon's
+// cache when one is running, and from the database otherwise.
 func loadData(projectPath string) ([]components.AgentInfo, []requestRow, []string, error) {
+	if snap := daemonSnapshot(projectPath); snap != nil {
+		agents, pending, activity := buildDashboardData(snap.Sessions, snap.Pending, time.Now())
+		return agents, pending, activity, nil
+	}
+
 	dbPath := filepath.Join(projectPath, ".slb", "state.db")
 	dbConn, err := db.OpenWithOptions(dbPath, db.OpenOptions{
 		CreateIfNotExists: false,
@@ -433,35 +558,84 @@ func loadData(projectPath string) ([]components.AgentInfo, []requestRow, []strin
 	if err != nil {
 		return []components.AgentInfo{}, []requestRow{}, []string{}, err
 	}
+
+	reqs, err := dbConn.ListPendingRequests(projectPath)
+	if err != nil {
+		agents, _, _ := buildDashboardData(sessions, nil, time.Now())
+		return agents, []requestRow{}, []string{}, err
+	}
+	ids := make([]string, 0, len(reqs))
+	for _, r := range reqs {
+		ids = append(ids, r.ID)
+	}
+	// Older databases may predate the escalation table; treat as none flagged.
+	awaitingHuman, _ := dbConn.AwaitingHumanRequestIDs(ids)
+	_ = dbConn.LoadRequestLabels(reqs)
+
+	pendingReqs := make([]daemon.PendingRequest, 0, len(reqs))
+	for _, r := range reqs {
+		approvals, _, _ := dbConn.CountReviewsByDecision(r.ID)
+		pendingReqs = append(pendingReqs, daemon.PendingRequest{
+			Request:       r,
+			Approvals:     approvals,
+			AwaitingHuman: awaitingHuman[r.ID],
+		})
+	}
+
+	agents, pending, activity := buildDashboardData(sessions, pendingReqs, time.Now())
+	return agents, pending, activity, nil
+}
+
+// daemonSnapshot returns the read model of a daemon serving projectPath, or
+// nil when none is reachable.
+func daemonSnapshot(projectPath string) *daemon.ReadModelSnapshot {
+	ctx, cancel := context.WithTimeout(context.Background(), daemonReadTimeout)
+	defer cancel()
+
+	client := daemon.NewIPCClient(daemon.SocketPathFor(projectPath))
+	defer client.Close()
+	snap, err := client.ReadModel(ctx)
+	if err != nil || filepath.Clean(snap.ProjectPath) != filepath.Clean(projectPath) {
+		return nil
+	}
+	return snap
+}
+
+// buildDashboardData turns sessions and pending requests into dashboard rows,
+// classifying agents and aging requests relative to now.
+func buildDashboardData(sessions []*db.Session, reqs []daemon.PendingRequest, now time.Time) ([]components.AgentInfo, []requestRow, []string) {
 	agents := make([]components.AgentInfo, 0, len(sessions))
 	for _, s := range sessions {
 		agents = append(agents, components.AgentInfo{
 			Name:        s.AgentName,
 			Program:     s.Program,
 			Model:       s.Model,
-			Status:      classifyAgentStatus(s.LastActiveAt),
+			Status:      classifyAgentStatus(s.LastActiveAt, now),
 			LastActive:  s.LastActiveAt,
 			SessionID:   s.ID,
 			ProjectPath: s.ProjectPath,
 		})
 	}
 
-	reqs, err := dbConn.ListPendingRequests(projectPath)
-	if err != nil {
-		return agents, []requestRow{}, []string{}, err
-	}
 	pending := make([]requestRow, 0, len(reqs))
-	for _, r := range reqs {
+	for _, p := range reqs {
+		r := p.Request
 		cmd := r.Command.DisplayRedacted
 		if cmd == "" {
 			cmd = r.Command.Raw
 		}
 		pending = append(pending, requestRow{
-			ID:        r.ID,
-			Tier:      string(r.RiskTier),
-			Command:   cmd,
-			Requestor: r.RequestorAgent,
-			CreatedAt: r.CreatedAt,
+			ID:            r.ID,
+			Tier:          string(r.RiskTier),
+			Command:       cmd,
+			Requestor:     r.RequestorAgent,
+			CreatedAt:     r.CreatedAt,
+			AwaitingHuman: p.AwaitingHuman,
+			Labels:        db.FormatLabels(r.Labels, ", "),
+			Reason:        r.Justification.Reason,
+			Approvals:     p.Approvals,
+			MinApprovals:  r.MinApprovals,
+			ExpiresAt:     r.ExpiresAt,
 		})
 	}
 
@@ -469,17 +643,17 @@ func loadData(projectPath string) ([]components.AgentInfo, []requestRow, []strin
 	activity := make([]string, 0, minInt(10, len(pending)))
 	for i := 0; i < len(pending) && i < 10; i++ {
 		p := pending[i]
-		activity = append(activity, fmt.Sprintf("Pending %s by %s (%s)", shortID(p.ID), p.Requestor, formatTimeAgo(p.CreatedAt)))
+		activity = append(activity, fmt.Sprintf("Pending %s by %s (%s)", shortID(p.ID), p.Requestor, timefmt.Current().Show(p.CreatedAt, now)))
 	}
 
-	return agents, pending, activity, nil
+	return agents, pending, activity
 }
 
-func classifyAgentStatus(lastActive time.Time) components.AgentStatus {
+func classifyAgentStatus(lastActive, now time.Time) components.AgentStatus {
 	if lastActive.IsZero() {
 		return components.AgentStatusStale
 	}
-	d := time.Since(lastActive)
+	d := now.Sub(lastActive)
 	switch {
 	case d < 5*time.Minute:
 		return components.AgentStatusActive
@@ -558,36 +732,6 @@ func truncateRunes(s string, max int) string {
 	return string(rs[:max-3]) + "..."
 }
 
-func formatTimeAgo(t time.Time) string {
-	if t.IsZero() {
-		return "never"
-	}
-
-	d := time.Since(t)
-	switch {
-	case d < time.Minute:
-		return "just now"
-	case d < time.Hour:
-		mins := int(d.Minutes())
-		if mins == 1 {
-			return "1m ago"
-		}
-		return fmt.Sprintf("%dm ago", mins)
-	case d < 24*time.Hour:
-		hours := int(d.Hours())
-		if hours == 1 {
-			return "1h ago"
-		}
-		return fmt.Sprintf("%dh ago", hours)
-	default:
-		days := int(d.Hours() / 24)
-		if days == 1 {
-			return "1d ago"
-		}
-		return fmt.Sprintf("%dd ago", days)
-	}
-}
-
 func maxInt(a, b int) int {
 	if a > b {
 		return a
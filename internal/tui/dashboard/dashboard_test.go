@@ -117,6 +117,21 @@ func TestModelView(t *testing.T) {
 	}
 }
 
+func TestModelViewReadOnly(t *testing.T) {
+	m := New("")
+	m.ready = true
+	m.width = 120
+	m.height = 24
+
+	if strings.Contains(m.View(), "READ-ONLY") {
+		t.Error("READ-ONLY badge should not be shown by default")
+	}
+	m.ReadOnly = true
+	if !strings.Contains(m.View(), "READ-ONLY") {
+		t.Error("header should show READ-ONLY badge")
+	}
+}
+
 func TestModelRefresh(t *testing.T) {
 	m := New("")
 	m.ready = true
@@ -224,32 +239,6 @@ func TestTruncateRunes(t *testing.T) {
 	}
 }
 
-func TestFormatTimeAgo(t *testing.T) {
-	tests := []struct {
-		name     string
-		time     time.Time
-		expected string
-	}{
-		{"zero", time.Time{}, "never"},
-		{"just now", time.Now(), "just now"},
-		{"1m", time.Now().Add(-time.Minute), "1m ago"},
-		{"5m", time.Now().Add(-5 * time.Minute), "5m ago"},
-		{"1h", time.Now().Add(-time.Hour), "1h ago"},
-		{"3h", time.Now().Add(-3 * time.Hour), "3h ago"},
-		{"1d", time.Now().Add(-24 * time.Hour), "1d ago"},
-		{"3d", time.Now().Add(-72 * time.Hour), "3d ago"},
-	}
-
-	for _, tc := range tests {
-		t.Run(tc.name, func(t *testing.T) {
-			got := formatTimeAgo(tc.time)
-			if got != tc.expected {
-				t.Errorf("formatTimeAgo: expected %q, got %q", tc.expected, got)
-			}
-		})
-	}
-}
-
 func TestShortID(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -306,18 +295,19 @@ func TestMinInt(t *testing.T) {
 }
 
 func TestClassifyAgentStatus(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
 	tests := []struct {
 		lastActive time.Time
 		expected   components.AgentStatus
 	}{
 		{time.Time{}, components.AgentStatusStale},
-		{time.Now(), components.AgentStatusActive},
-		{time.Now().Add(-10 * time.Minute), components.AgentStatusIdle},
-		{time.Now().Add(-1 * time.Hour), components.AgentStatusStale},
+		{now, components.AgentStatusActive},
+		{now.Add(-10 * time.Minute), components.AgentStatusIdle},
+		{now.Add(-1 * time.Hour), components.AgentStatusStale},
 	}
 
 	for _, tc := range tests {
-		got := classifyAgentStatus(tc.lastActive)
+		got := classifyAgentStatus(tc.lastActive, now)
 		if got != tc.expected {
 			t.Errorf("classifyAgentStatus: expected %v, got %v", tc.expected, got)
 		}
@@ -1027,3 +1017,22 @@ func TestLoadDataWithDisplayRedacted(t *testing.T) {
 		t.Errorf("expected command to be 'redacted cmd', got %q", pending[0].Command)
 	}
 }
+
+func TestRenderPendingPanelAwaitingHumanBadge(t *testing.T) {
+	m := New("/tmp/test")
+	m.width = 80
+	m.height = 24
+	m.ready = true
+
+	m.pending = []requestRow{
+		{ID: "req-1", Tier: "dangerous", Command: "rm -rf ./build", Requestor: "Agent1", CreatedAt: time.Now()},
+	}
+	if strings.Contains(m.renderPendingPanel(60, 10), awaitingHumanBadge) {
+		t.Fatal("badge should not render for requests not awaiting a human")
+	}
+
+	m.pending[0].AwaitingHuman = true
+	if !strings.Contains(m.renderPendingPanel(60, 10), awaitingHumanBadge) {
+		t.Fatal("expected awaiting-human badge")
+	}
+}
@@ -17,6 +17,15 @@ type KeyMap struct {
 	FocusAgents   key.Binding
 	FocusRequests key.Binding
 	FocusActivity key.Binding
+	FocusPreview  key.Binding
+
+	// Layout
+	Grow           key.Binding
+	Shrink         key.Binding
+	ToggleAgents   key.Binding
+	ToggleActivity key.Binding
+	TogglePreview  key.Binding
+	ResetLayout    key.Binding
 
 	// Actions
 	Select  key.Binding
@@ -69,6 +78,34 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("3"),
 			key.WithHelp("3", "activity panel"),
 		),
+		FocusPreview: key.NewBinding(
+			key.WithKeys("4"),
+			key.WithHelp("4", "preview pane"),
+		),
+		Grow: key.NewBinding(
+			key.WithKeys("+", "="),
+			key.WithHelp("+", "grow pane"),
+		),
+		Shrink: key.NewBinding(
+			key.WithKeys("-"),
+			key.WithHelp("-", "shrink pane"),
+		),
+		ToggleAgents: key.NewBinding(
+			key.WithKeys("A"),
+			key.WithHelp("A", "toggle agents"),
+		),
+		ToggleActivity: key.NewBinding(
+			key.WithKeys("E"),
+			key.WithHelp("E", "toggle activity"),
+		),
+		TogglePreview: key.NewBinding(
+			key.WithKeys("P"),
+			key.WithHelp("P", "toggle preview"),
+		),
+		ResetLayout: key.NewBinding(
+			key.WithKeys("0"),
+			key.WithHelp("0", "reset layout"),
+		),
 		Select: key.NewBinding(
 			key.WithKeys("enter"),
 			key.WithHelp("enter", "select"),
@@ -117,7 +154,8 @@ func (k KeyMap) ShortHelp() []key.Binding {
 func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Down, k.Tab, k.ShiftTab},
-		{k.FocusAgents, k.FocusRequests, k.FocusActivity},
+		{k.FocusAgents, k.FocusRequests, k.FocusActivity, k.FocusPreview},
+		{k.Grow, k.Shrink, k.ToggleAgents, k.ToggleActivity, k.TogglePreview, k.ResetLayout},
 		{k.Select, k.Approve, k.Reject, k.Details},
 		{k.Refresh, k.Help, k.Quit},
 	}
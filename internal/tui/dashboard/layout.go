@@ -0,0 +1,297 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Large terminals get the detail preview pane stacked under the pending queue.
+const (
+	largeLayoutWidth  = 140
+	largeLayoutHeight = 30
+)
+
+// Split bounds (percent) and the step used by the resize keys.
+const (
+	minSidePercent    = 15
+	maxSidePercent    = 40
+	minPreviewPercent = 25
+	maxPreviewPercent = 75
+	resizeStep        = 5
+
+	// minPaneWidth keeps side panes readable when shrunk on narrow terminals.
+	minPaneWidth = 20
+)
+
+// layoutFileName is the per-user file that stores dashboard layout preferences.
+const layoutFileName = "tui-layout.json"
+
+// Layout holds the dashboard pane preferences. The pending queue is always
+// shown; the other panes can be toggled and the splits resized.
+type Layout struct {
+	ShowAgents   bool `json:"show_agents"`
+	ShowActivity bool `json:"show_activity"`
+	ShowPreview  bool `json:"show_preview"`
+
+	// AgentsPercent and ActivityPercent are the widths of the left and right
+	// columns as a percentage of the terminal width.
+	AgentsPercent   int `json:"agents_percent"`
+	ActivityPercent int `json:"activity_percent"`
+	// PreviewPercent is the share of the center column height given to the
+	// detail preview.
+	PreviewPercent int `json:"preview_percent"`
+}
+
+// DefaultLayout returns the default dashboard layout.
+func DefaultLayout() Layout {
+	return Layout{
+		ShowAgents:      true,
+		ShowActivity:    true,
+		ShowPreview:     true,
+		AgentsPercent:   25,
+		ActivityPercent: 25,
+		PreviewPercent:  40,
+	}
+}
+
+// normalize clamps splits into their allowed ranges.
+func (l Layout) normalize() Layout {
+	l.AgentsPercent = clampInt(l.AgentsPercent, minSidePercent, maxSidePercent)
+	l.ActivityPercent = clampInt(l.ActivityPercent, minSidePercent, maxSidePercent)
+	l.PreviewPercent = clampInt(l.PreviewPercent, minPreviewPercent, maxPreviewPercent)
+	return l
+}
+
+// DefaultLayoutPath returns the per-user layout preferences path (~/.slb/tui-layout.json).
+func DefaultLayoutPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return ""
+	}
+	return filepath.Join(home, ".slb", layoutFileName)
+}
+
+// LoadLayout reads layout preferences from path. A missing or unreadable
+// file yields the default layout.
+func LoadLayout(path string) Layout {
+	layout := DefaultLayout()
+	if path == "" {
+		return layout
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return layout
+	}
+	if err := json.Unmarshal(data, &layout); err != nil {
+		return DefaultLayout()
+	}
+	return layout.normalize()
+}
+
+// SaveLayout writes layout preferences to path.
+func SaveLayout(path string, layout Layout) error {
+	if path == "" {
+		return fmt.Errorf("no layout path")
+	}
+	data, err := json.MarshalIndent(layout.normalize(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding layout: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("creating layout dir: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("writing layout: %w", err)
+	}
+	return nil
+}
+
+// saveLayoutCmd persists the layout in the background; failures are ignored
+// because preferences are a convenience, not state.
+func saveLayoutCmd(path string, layout Layout) tea.Cmd {
+	if path == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		_ = SaveLayout(path, layout)
+		return nil
+	}
+}
+
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+// Layout returns the current pane layout.
+func (m Model) Layout() Layout {
+	return m.layout
+}
+
+// WithLayoutPath overrides where layout preferences are loaded from and saved
+// to. An empty path disables persistence.
+func (m Model) WithLayoutPath(path string) Model {
+	m.layoutPath = path
+	m.layout = LoadLayout(path)
+	return m
+}
+
+// isLarge reports whether the terminal is big enough for the preview pane.
+func (m Model) isLarge() bool {
+	return m.width >= largeLayoutWidth && m.height >= largeLayoutHeight
+}
+
+// previewVisible reports whether the detail preview pane is shown.
+func (m Model) previewVisible() bool {
+	return m.layout.ShowPreview && m.isLarge()
+}
+
+// visiblePanes returns the panes currently on screen in focus order.
+func (m Model) visiblePanes() []focusPanel {
+	var panes []focusPanel
+	if m.layout.ShowAgents {
+		panes = append(panes, focusAgents)
+	}
+	panes = append(panes, focusPending)
+	if m.layout.ShowActivity {
+		panes = append(panes, focusActivity)
+	}
+	if m.previewVisible() {
+		panes = append(panes, focusPreview)
+	}
+	return panes
+}
+
+// cycleFocus moves focus by delta through the visible panes, wrapping around.
+func (m *Model) cycleFocus(delta int) {
+	panes := m.visiblePanes()
+	idx := 0
+	for i, p := range panes {
+		if p == m.focus {
+			idx = i
+			break
+		}
+	}
+	idx = ((idx+delta)%len(panes) + len(panes)) % len(panes)
+	m.focus = panes[idx]
+}
+
+// focusPane focuses a pane directly if it is visible.
+func (m *Model) focusPane(p focusPanel) {
+	for _, v := range m.visiblePanes() {
+		if v == p {
+			m.focus = p
+			return
+		}
+	}
+}
+
+// resizeFocused grows (positive delta) or shrinks the focused pane.
+// Side panes change width; the pending queue and preview share the center
+// column height.
+func (m *Model) resizeFocused(delta int) tea.Cmd {
+	switch m.focus {
+	case focusAgents:
+		m.layout.AgentsPercent += delta
+	case focusActivity:
+		m.layout.ActivityPercent += delta
+	case focusPending:
+		if !m.previewVisible() {
+			return nil
+		}
+		m.layout.PreviewPercent -= delta
+	case focusPreview:
+		m.layout.PreviewPercent += delta
+	}
+	return m.layoutChanged()
+}
+
+// layoutChanged normalizes the layout, keeps focus on a visible pane, and
+// persists the new preferences.
+func (m *Model) layoutChanged() tea.Cmd {
+	m.layout = m.layout.normalize()
+	visible := false
+	for _, p := range m.visiblePanes() {
+		if p == m.focus {
+			visible = true
+			break
+		}
+	}
+	if !visible {
+		m.focus = focusPending
+	}
+	return saveLayoutCmd(m.layoutPath, m.layout)
+}
+
+// paneGap is the spacing between columns.
+const paneGap = 1
+
+// paneGeometry holds the content sizes of the visible panes; each pane adds
+// a one-cell border on every side.
+type paneGeometry struct {
+	leftW, centerW, rightW int
+	pendingH, previewH     int // previewH is 0 when the preview is hidden
+}
+
+// geometry computes pane sizes for the given body height.
+func (m Model) geometry(height int) paneGeometry {
+	var g paneGeometry
+	if m.layout.ShowAgents {
+		g.leftW = maxInt(minPaneWidth, m.width*m.layout.AgentsPercent/100)
+	}
+	if m.layout.ShowActivity {
+		g.rightW = maxInt(minPaneWidth, m.width*m.layout.ActivityPercent/100)
+	}
+	g.centerW = m.width - g.leftW - g.rightW
+	if g.leftW > 0 {
+		g.centerW -= paneGap
+	}
+	if g.rightW > 0 {
+		g.centerW -= paneGap
+	}
+	if g.centerW < 30 {
+		g.centerW = 30
+	}
+
+	g.pendingH = height
+	if m.previewVisible() {
+		// Both stacked panels carry their own border rows.
+		g.previewH = maxInt(4, (height-2)*m.layout.PreviewPercent/100)
+		g.pendingH = maxInt(4, height-2-g.previewH)
+	}
+	return g
+}
+
+// renderBody lays out the visible panes for the given body height.
+func (m Model) renderBody(height int) string {
+	g := m.geometry(height)
+	spacer := lipgloss.NewStyle().Width(paneGap).Render("")
+
+	center := m.renderPendingPanel(g.centerW, g.pendingH)
+	if g.previewH > 0 {
+		center = lipgloss.JoinVertical(lipgloss.Left,
+			center,
+			m.renderPreviewPanel(g.centerW, g.previewH),
+		)
+	}
+
+	var cols []string
+	if g.leftW > 0 {
+		cols = append(cols, m.renderAgentsPanel(g.leftW, height), spacer)
+	}
+	cols = append(cols, center)
+	if g.rightW > 0 {
+		cols = append(cols, spacer, m.renderActivityPanel(g.rightW, height))
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, cols...)
+}
@@ -0,0 +1,219 @@
+package dashboard
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func runeKey(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+// newLayoutTestModel returns a ready model with layout persistence pointed at a temp file.
+func newLayoutTestModel(t *testing.T, width, height int) (Model, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), layoutFileName)
+	m := New("").WithLayoutPath(path)
+	m.ready = true
+	m.width = width
+	m.height = height
+	return m, path
+}
+
+// runCmd executes a command synchronously (used to flush layout saves).
+func runCmd(cmd tea.Cmd) {
+	if cmd != nil {
+		cmd()
+	}
+}
+
+func TestLoadLayoutDefaultsAndClamping(t *testing.T) {
+	if got := LoadLayout(""); got != DefaultLayout() {
+		t.Errorf("LoadLayout(\"\") = %+v, want defaults", got)
+	}
+	if got := LoadLayout(filepath.Join(t.TempDir(), "missing.json")); got != DefaultLayout() {
+		t.Errorf("missing file should yield defaults, got %+v", got)
+	}
+
+	path := filepath.Join(t.TempDir(), layoutFileName)
+	if err := os.WriteFile(path, []byte("{not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got := LoadLayout(path); got != DefaultLayout() {
+		t.Errorf("corrupt file should yield defaults, got %+v", got)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"show_agents":false,"agents_percent":90,"preview_percent":1}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	got := LoadLayout(path)
+	if got.ShowAgents {
+		t.Error("expected agents pane hidden from saved layout")
+	}
+	if !got.ShowActivity {
+		t.Error("unset fields should keep their defaults")
+	}
+	if got.AgentsPercent != maxSidePercent || got.PreviewPercent != minPreviewPercent {
+		t.Errorf("expected splits to be clamped, got %+v", got)
+	}
+}
+
+func TestSaveLayoutRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", layoutFileName)
+	want := DefaultLayout()
+	want.ShowPreview = false
+	want.ActivityPercent = 30
+
+	if err := SaveLayout(path, want); err != nil {
+		t.Fatalf("SaveLayout: %v", err)
+	}
+	if got := LoadLayout(path); got != want {
+		t.Errorf("round trip = %+v, want %+v", got, want)
+	}
+	if err := SaveLayout("", want); err == nil {
+		t.Error("expected error for empty path")
+	}
+}
+
+func TestFocusCyclesVisiblePanes(t *testing.T) {
+	// Small terminal: no preview, three panes.
+	m, _ := newLayoutTestModel(t, 100, 24)
+	if got := len(m.visiblePanes()); got != 3 {
+		t.Fatalf("expected 3 panes on a small terminal, got %d", got)
+	}
+
+	// Large terminal: preview joins the cycle after activity.
+	m, _ = newLayoutTestModel(t, 180, 50)
+	m.focus = focusActivity
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = updated.(Model)
+	if m.focus != focusPreview {
+		t.Fatalf("expected tab to focus preview, got %d", m.focus)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = updated.(Model)
+	if m.focus != focusAgents {
+		t.Fatalf("expected tab to wrap to agents, got %d", m.focus)
+	}
+
+	// Direct focus keys only target visible panes.
+	updated, _ = m.Update(runeKey("4"))
+	m = updated.(Model)
+	if m.focus != focusPreview {
+		t.Errorf("expected 4 to focus preview, got %d", m.focus)
+	}
+	m.width = 100
+	updated, _ = m.Update(runeKey("2"))
+	m = updated.(Model)
+	updated, _ = m.Update(runeKey("4"))
+	m = updated.(Model)
+	if m.focus != focusPending {
+		t.Errorf("4 should be ignored when preview is hidden, got %d", m.focus)
+	}
+}
+
+func TestTogglePaneMovesFocusAndPersists(t *testing.T) {
+	m, path := newLayoutTestModel(t, 100, 24)
+	m.focus = focusAgents
+
+	updated, cmd := m.Update(runeKey("A"))
+	m = updated.(Model)
+	runCmd(cmd)
+
+	if m.layout.ShowAgents {
+		t.Fatal("expected agents pane hidden")
+	}
+	if m.focus != focusPending {
+		t.Errorf("focus should fall back to pending when its pane is hidden, got %d", m.focus)
+	}
+	if strings.Contains(m.View(), "Agents (") {
+		t.Error("hidden agents pane should not render")
+	}
+	if LoadLayout(path).ShowAgents {
+		t.Error("layout change should be persisted")
+	}
+
+	// Preferences survive a new model (e.g. returning from another view).
+	if New("").WithLayoutPath(path).Layout().ShowAgents {
+		t.Error("new model should load persisted layout")
+	}
+
+	updated, cmd = m.Update(runeKey("0"))
+	m = updated.(Model)
+	runCmd(cmd)
+	if m.layout != DefaultLayout() || LoadLayout(path) != DefaultLayout() {
+		t.Error("0 should reset and persist the default layout")
+	}
+}
+
+func TestResizeFocusedPane(t *testing.T) {
+	m, _ := newLayoutTestModel(t, 180, 50)
+
+	m.focus = focusAgents
+	updated, _ := m.Update(runeKey("+"))
+	m = updated.(Model)
+	if m.layout.AgentsPercent != DefaultLayout().AgentsPercent+resizeStep {
+		t.Errorf("expected agents pane to grow, got %d", m.layout.AgentsPercent)
+	}
+
+	m.focus = focusActivity
+	for i := 0; i < 10; i++ {
+		updated, _ = m.Update(runeKey("-"))
+		m = updated.(Model)
+	}
+	if m.layout.ActivityPercent != minSidePercent {
+		t.Errorf("expected activity pane clamped at %d, got %d", minSidePercent, m.layout.ActivityPercent)
+	}
+
+	m.focus = focusPreview
+	updated, _ = m.Update(runeKey("="))
+	m = updated.(Model)
+	if m.layout.PreviewPercent != DefaultLayout().PreviewPercent+resizeStep {
+		t.Errorf("expected preview to grow, got %d", m.layout.PreviewPercent)
+	}
+
+	m.focus = focusPending
+	updated, _ = m.Update(runeKey("+"))
+	m = updated.(Model)
+	if m.layout.PreviewPercent != DefaultLayout().PreviewPercent {
+		t.Errorf("growing pending should shrink preview, got %d", m.layout.PreviewPercent)
+	}
+}
+
+func TestPreviewPaneShowsSelectedRequest(t *testing.T) {
+	expires := time.Now().Add(30 * time.Minute)
+	m, _ := newLayoutTestModel(t, 180, 50)
+	m.pending = []requestRow{
+		{ID: "req-one", Tier: "critical", Command: "rm -rf /data", Requestor: "BlueLake", CreatedAt: time.Now()},
+		{ID: "req-two", Tier: "dangerous", Command: "git push --force", Requestor: "GreenCastle", CreatedAt: time.Now(),
+			Reason: "rewrite history", Approvals: 1, MinApprovals: 2, ExpiresAt: &expires},
+	}
+
+	view := m.View()
+	if !strings.Contains(view, "Preview") || !strings.Contains(view, "req-one") {
+		t.Fatal("large terminal should show preview of the selected request")
+	}
+
+	m.focus = focusPreview
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = updated.(Model)
+	if got := m.SelectedRequestID(); got != "req-two" {
+		t.Fatalf("navigating in preview should move the selection, got %q", got)
+	}
+	view = m.View()
+	for _, want := range []string{"rewrite history", "1/2"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("preview should contain %q", want)
+		}
+	}
+
+	m.width = 100
+	if strings.Contains(m.View(), "Preview") {
+		t.Error("preview should be hidden on small terminals")
+	}
+}
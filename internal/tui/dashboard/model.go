@@ -9,10 +9,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
 	"github.com/Dicklesworthstone/slb/internal/tui/components"
 	"github.com/Dicklesworthstone/slb/internal/tui/icons"
 	"github.com/Dicklesworthstone/slb/internal/tui/styles"
 	"github.com/Dicklesworthstone/slb/internal/tui/theme"
+	"github.com/Dicklesworthstone/slb/internal/utils"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -285,15 +287,15 @@ func (m Model) renderHeader(ic *icons.IconSet, t *theme.Theme) string {
 	var statusColor lipgloss.Color
 	switch m.daemonStatus {
 	case DaemonConnected:
-		statusIcon = "●"
+		statusIcon = utils.Glyph("●", "*")
 		statusText = "Daemon Running"
 		statusColor = t.Green
 	case DaemonDisconnected:
-		statusIcon = "●"
+		statusIcon = utils.Glyph("●", "*")
 		statusText = "Daemon Disconnected"
 		statusColor = t.Red
 	default:
-		statusIcon = "●"
+		statusIcon = utils.Glyph("●", "*")
 		statusText = "Checking..."
 		statusColor = t.Yellow
 	}
@@ -405,7 +407,7 @@ func (m Model) renderRequestItem(req RequestData, selected bool, width int, t *t
 	cmdStyle := lipgloss.NewStyle().Foreground(t.Green)
 
 	// Requestor and time
-	timeAgo := formatTimeAgo(req.CreatedAt)
+	timeAgo := timefmt.Current().Show(req.CreatedAt, time.Now())
 	meta := lipgloss.NewStyle().
 		Foreground(t.Subtext).
 		Render(fmt.Sprintf("by %s • %s", req.RequestorID, timeAgo))
@@ -485,7 +487,7 @@ func (m Model) renderActivityItem(activity ActivityItem, selected bool, width in
 	}
 
 	actionStyle := lipgloss.NewStyle().Foreground(actionColor)
-	timeAgo := formatTimeAgo(activity.Timestamp)
+	timeAgo := timefmt.Current().Show(activity.Timestamp, time.Now())
 
 	line := fmt.Sprintf("%s %s %s • %s",
 		actionStyle.Render(actionIcon),
@@ -553,36 +555,6 @@ func (m Model) renderFooter(t *theme.Theme, ic *icons.IconSet) string {
 	return footerStyle.Render(statsStyle.Render(stats) + middle + keysStyle.Render(keybindings))
 }
 
-// formatTimeAgo formats a time as "X ago" string.
-func formatTimeAgo(t time.Time) string {
-	if t.IsZero() {
-		return "never"
-	}
-
-	d := time.Since(t)
-
-	if d < time.Minute {
-		return "just now"
-	} else if d < time.Hour {
-		mins := int(d.Minutes())
-		if mins == 1 {
-			return "1m ago"
-		}
-		return fmt.Sprintf("%dm ago", mins)
-	} else if d < 24*time.Hour {
-		hours := int(d.Hours())
-		if hours == 1 {
-			return "1h ago"
-		}
-		return fmt.Sprintf("%dh ago", hours)
-	}
-	days := int(d.Hours() / 24)
-	if days == 1 {
-		return "1d ago"
-	}
-	return fmt.Sprintf("%dd ago", days)
-}
-
 // SetAgents sets the agent data.
 func (m *Model) SetAgents(agents []AgentData) {
 	m.agents = agents
@@ -0,0 +1,109 @@
+package dashboard
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Dicklesworthstone/slb/internal/tui/components"
+)
+
+// OpenRequestMsg asks the parent view to open a request's detail view.
+type OpenRequestMsg struct {
+	RequestID string
+}
+
+// listHeaderLines is the number of lines above the first row of a pane
+// (top border + title).
+const listHeaderLines = 2
+
+// handleMouse handles clicks and wheel scrolling on the dashboard panes.
+// Clicking focuses the pane under the cursor and selects the row; a
+// double-click on a pending request (or anywhere in the preview) opens it.
+func (m Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	pane, line, ok := m.paneAt(msg.X, msg.Y)
+	if !ok {
+		return m, nil
+	}
+
+	if delta := components.WheelDelta(msg); delta != 0 {
+		m.focus = pane
+		m.moveSelection(delta)
+		return m, nil
+	}
+	if !components.IsLeftClick(msg) {
+		return m, nil
+	}
+
+	m.focus = pane
+	target := ""
+	switch pane {
+	case focusAgents:
+		if idx := m.rowIndex(line, m.agentOff, len(m.agents), m.bodyHeight()); idx >= 0 {
+			m.agentSel = idx
+		}
+	case focusActivity:
+		if idx := m.rowIndex(line, m.activityOff, len(m.activity), m.bodyHeight()); idx >= 0 {
+			m.activitySel = idx
+		}
+	case focusPending:
+		if idx := m.rowIndex(line, m.pendingOff, len(m.pending), m.geometry(m.bodyHeight()).pendingH); idx >= 0 {
+			m.pendingSel = idx
+			target = m.pending[idx].ID
+		}
+	case focusPreview:
+		target = m.SelectedRequestID()
+	}
+
+	if target != "" && m.clicks.Click(target, time.Now()) {
+		return m, func() tea.Msg { return OpenRequestMsg{RequestID: target} }
+	}
+	if target == "" {
+		m.clicks = components.ClickTracker{}
+	}
+	return m, nil
+}
+
+// rowIndex maps a line within a pane's list area to an item index, given the
+// pane's scroll offset, item count, and content height. Returns -1 when the
+// line is not on an item.
+func (m Model) rowIndex(line, offset, total, height int) int {
+	start, end := window(offset, total, maxInt(1, height-4))
+	idx := start + line
+	if line < 0 || idx >= end {
+		return -1
+	}
+	return idx
+}
+
+// paneAt returns the pane under screen position (x, y) and the line within
+// its list area (0 is the first row; negative for the border and title).
+func (m Model) paneAt(x, y int) (focusPanel, int, bool) {
+	top := lipgloss.Height(m.renderHeader())
+	height := m.bodyHeight()
+	rel := y - top
+	if x < 0 || rel < 0 || rel >= height+2 {
+		return 0, 0, false
+	}
+
+	g := m.geometry(height)
+	left := 0
+	if g.leftW > 0 {
+		if x < g.leftW+2 {
+			return focusAgents, rel - listHeaderLines, true
+		}
+		left = g.leftW + 2 + paneGap
+	}
+	if x >= left && x < left+g.centerW+2 {
+		if g.previewH > 0 && rel >= g.pendingH+2 {
+			return focusPreview, rel - (g.pendingH + 2) - listHeaderLines, true
+		}
+		return focusPending, rel - listHeaderLines, true
+	}
+	left += g.centerW + 2 + paneGap
+	if g.rightW > 0 && x >= left && x < left+g.rightW+2 {
+		return focusActivity, rel - listHeaderLines, true
+	}
+	return 0, 0, false
+}
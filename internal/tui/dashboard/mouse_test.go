@@ -0,0 +1,126 @@
+package dashboard
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Dicklesworthstone/slb/internal/tui/components"
+)
+
+func leftClick(x, y int) tea.MouseMsg {
+	return tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft}
+}
+
+func mouseTestModel(t *testing.T, width, height int) Model {
+	t.Helper()
+	m, _ := newLayoutTestModel(t, width, height)
+	m.agents = []components.AgentInfo{{Name: "BlueLake"}, {Name: "GreenCastle"}}
+	m.pending = []requestRow{
+		{ID: "req-one", Tier: "critical", Command: "rm -rf /data", CreatedAt: time.Now()},
+		{ID: "req-two", Tier: "dangerous", Command: "git push --force", CreatedAt: time.Now()},
+	}
+	m.activity = []string{"first", "second", "third"}
+	return m
+}
+
+// firstRowLine returns the screen line of the first list row (below the
+// header, pane border, and title).
+func firstRowLine(m Model) int {
+	return lipgloss.Height(m.renderHeader()) + listHeaderLines
+}
+
+func TestPaneAt(t *testing.T) {
+	m := mouseTestModel(t, 100, 24)
+	firstRowY := firstRowLine(m)
+	g := m.geometry(m.bodyHeight())
+	centerX := g.leftW + 2 + paneGap + 1
+	rightX := centerX + g.centerW + 2
+
+	tests := []struct {
+		name string
+		x, y int
+		pane focusPanel
+		line int
+		ok   bool
+	}{
+		{"agents row", 1, firstRowY, focusAgents, 0, true},
+		{"pending title", centerX, firstRowY - 1, focusPending, -1, true},
+		{"pending second row", centerX, firstRowY + 1, focusPending, 1, true},
+		{"activity row", rightX + 1, firstRowY + 2, focusActivity, 2, true},
+		{"header", centerX, 0, 0, 0, false},
+		{"past right edge", m.width + 10, firstRowY, 0, 0, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pane, line, ok := m.paneAt(tc.x, tc.y)
+			if ok != tc.ok || (ok && (pane != tc.pane || line != tc.line)) {
+				t.Errorf("paneAt(%d,%d) = (%d,%d,%v), want (%d,%d,%v)", tc.x, tc.y, pane, line, ok, tc.pane, tc.line, tc.ok)
+			}
+		})
+	}
+}
+
+func TestMouseClickSelectsAndFocuses(t *testing.T) {
+	m := mouseTestModel(t, 100, 24)
+	firstRowY := firstRowLine(m)
+	m.focus = focusActivity
+	g := m.geometry(m.bodyHeight())
+	centerX := g.leftW + 2 + paneGap + 1
+
+	updated, cmd := m.Update(leftClick(centerX, firstRowY+1))
+	m = updated.(Model)
+	if cmd != nil {
+		t.Error("single click should not open the request")
+	}
+	if m.focus != focusPending || m.SelectedRequestID() != "req-two" {
+		t.Fatalf("expected pending focus on req-two, got focus %d id %q", m.focus, m.SelectedRequestID())
+	}
+
+	updated, _ = m.Update(leftClick(1, firstRowY+1))
+	m = updated.(Model)
+	if m.focus != focusAgents || m.agentSel != 1 {
+		t.Errorf("expected agents focus with second agent selected, got focus %d sel %d", m.focus, m.agentSel)
+	}
+}
+
+func TestMouseDoubleClickOpensRequest(t *testing.T) {
+	m := mouseTestModel(t, 100, 24)
+	firstRowY := firstRowLine(m)
+	g := m.geometry(m.bodyHeight())
+	centerX := g.leftW + 2 + paneGap + 1
+
+	updated, _ := m.Update(leftClick(centerX, firstRowY))
+	updated, cmd := updated.(Model).Update(leftClick(centerX, firstRowY))
+	if cmd == nil {
+		t.Fatal("double-click should return an open command")
+	}
+	msg, ok := cmd().(OpenRequestMsg)
+	if !ok || msg.RequestID != "req-one" {
+		t.Fatalf("expected OpenRequestMsg for req-one, got %#v", cmd())
+	}
+	_ = updated
+}
+
+func TestMouseWheelScrollsPaneUnderCursor(t *testing.T) {
+	m := mouseTestModel(t, 100, 24)
+	firstRowY := firstRowLine(m)
+	m.focus = focusAgents
+	g := m.geometry(m.bodyHeight())
+	centerX := g.leftW + 2 + paneGap + 1
+
+	wheel := tea.MouseMsg{X: centerX, Y: firstRowY, Action: tea.MouseActionPress, Button: tea.MouseButtonWheelDown}
+	updated, _ := m.Update(wheel)
+	m = updated.(Model)
+	if m.focus != focusPending || m.pendingSel != 1 {
+		t.Errorf("wheel should focus and scroll the pending pane, got focus %d sel %d", m.focus, m.pendingSel)
+	}
+
+	// Release events are ignored.
+	release := tea.MouseMsg{X: centerX, Y: firstRowY, Action: tea.MouseActionRelease, Button: tea.MouseButtonLeft}
+	if _, cmd := m.Update(release); cmd != nil {
+		t.Error("mouse release should be ignored")
+	}
+}
@@ -0,0 +1,93 @@
+package dashboard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/daemon"
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+	"github.com/Dicklesworthstone/slb/internal/tui/tuitest"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var snapshotNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func snapshotModel(t *testing.T, sessions []*db.Session, reqs []daemon.PendingRequest, size tuitest.Size) Model {
+	t.Helper()
+
+	m := New("/work/project")
+	m.layout = DefaultLayout()
+	m.layoutPath = ""
+	m.SetClock(testutil.NewFakeClock(snapshotNow))
+
+	agents, pending, activity := buildDashboardData(sessions, reqs, snapshotNow)
+	updated, _ := m.Update(dataMsg{
+		agents:      agents,
+		pending:     pending,
+		activity:    activity,
+		refreshedAt: snapshotNow.Add(-5 * time.Second),
+	})
+	updated, _ = updated.(Model).Update(tea.WindowSizeMsg{Width: size.Width, Height: size.Height})
+	return updated.(Model)
+}
+
+func snapshotData() ([]*db.Session, []daemon.PendingRequest) {
+	at := func(d time.Duration) *time.Time {
+		t := snapshotNow.Add(d)
+		return &t
+	}
+	sessions := []*db.Session{
+		{ID: "sess-blue", AgentName: "BlueLake", Program: "claude-code", Model: "opus", ProjectPath: "/work/project", LastActiveAt: snapshotNow.Add(-30 * time.Second)},
+		{ID: "sess-green", AgentName: "GreenCastle", Program: "codex-cli", Model: "gpt-5", ProjectPath: "/work/project", LastActiveAt: snapshotNow.Add(-10 * time.Minute)},
+		{ID: "sess-red", AgentName: "RedStone", Program: "cursor", Model: "sonnet", ProjectPath: "/work/project", LastActiveAt: snapshotNow.Add(-3 * time.Hour)},
+	}
+	reqs := []daemon.PendingRequest{
+		{
+			Request: &db.Request{
+				ID:             "req-0001-critical",
+				RiskTier:       db.RiskTierCritical,
+				Command:        db.CommandSpec{Raw: "kubectl delete namespace production"},
+				RequestorAgent: "BlueLake",
+				MinApprovals:   2,
+				CreatedAt:      snapshotNow.Add(-2 * time.Minute),
+				ExpiresAt:      at(28 * time.Minute),
+				Justification:  db.Justification{Reason: "Tear down the broken rollout"},
+			},
+			Approvals:     1,
+			AwaitingHuman: true,
+		},
+		{
+			Request: &db.Request{
+				ID:             "req-0002-dangerous",
+				RiskTier:       db.RiskTierDangerous,
+				Command:        db.CommandSpec{Raw: "git push --force origin main"},
+				RequestorAgent: "GreenCastle",
+				MinApprovals:   1,
+				CreatedAt:      snapshotNow.Add(-15 * time.Minute),
+				ExpiresAt:      at(15 * time.Minute),
+				Justification:  db.Justification{Reason: "Drop the leaked credentials commit"},
+			},
+		},
+	}
+	return sessions, reqs
+}
+
+func TestDashboardSnapshot(t *testing.T) {
+	tuitest.Pin(t)
+	sessions, reqs := snapshotData()
+
+	for _, size := range tuitest.Sizes {
+		t.Run(size.String(), func(t *testing.T) {
+			m := snapshotModel(t, sessions, reqs, size)
+			tuitest.RequireSnapshot(t, "dashboard_"+size.String(), m.View())
+		})
+	}
+}
+
+func TestDashboardSnapshot_Empty(t *testing.T) {
+	tuitest.Pin(t)
+
+	m := snapshotModel(t, nil, nil, tuitest.Sizes[0])
+	tuitest.RequireSnapshot(t, "dashboard_empty", m.View())
+}
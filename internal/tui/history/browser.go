@@ -13,9 +13,12 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/Dicklesworthstone/slb/internal/clock"
 	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
 	"github.com/Dicklesworthstone/slb/internal/tui/components"
 	"github.com/Dicklesworthstone/slb/internal/tui/theme"
+	"github.com/Dicklesworthstone/slb/internal/utils"
 )
 
 const (
@@ -118,9 +121,13 @@ type Model struct {
 	rows       []HistoryRow
 	totalCount int
 
-	// Pagination
+	// Pagination walks history by keyset cursor. cursors[i] is where page i
+	// starts (nil for the first page); next is where the following page
+	// starts, nil on the last page.
 	page      int
 	pageCount int
+	cursors   []*db.RequestCursor
+	next      *db.RequestCursor
 
 	// Selection
 	selectedIdx int
@@ -133,6 +140,12 @@ type Model struct {
 	// Filters
 	filters Filters
 
+	// Mouse double-click detection
+	clicks components.ClickTracker
+
+	// clock renders relative times ("5m ago"); the real clock when nil.
+	clock clock.Clock
+
 	// Callbacks
 	OnBack   func()
 	OnSelect func(requestID string)
@@ -145,10 +158,16 @@ type Model struct {
 // refreshMsg triggers a data refresh.
 type refreshMsg struct{}
 
+// OpenRequestMsg asks the parent view to open a request's detail view.
+type OpenRequestMsg struct {
+	RequestID string
+}
+
 // dataMsg contains loaded data.
 type dataMsg struct {
 	rows        []HistoryRow
 	totalCount  int
+	next        *db.RequestCursor
 	err         error
 	refreshedAt time.Time
 }
@@ -172,12 +191,18 @@ func New(projectPath string) Model {
 		searchInput: ti,
 		filters:     NewFilters(),
 		page:        0,
+		clock:       clock.Real,
 	}
 }
 
+// SetClock sets the clock relative times are rendered against.
+func (m *Model) SetClock(c clock.Clock) {
+	m.clock = clock.OrReal(c)
+}
+
 // Init initializes the model.
 func (m Model) Init() tea.Cmd {
-	return tea.Batch(loadDataCmd(m.projectPath, m.searchQuery, m.filters, m.page), tickCmd())
+	return tea.Batch(m.load(), tickCmd())
 }
 
 // Update handles messages.
@@ -192,12 +217,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.searchInput.Width = min(60, m.width-20)
 		return m, nil
 
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
+
 	case refreshMsg:
-		return m, tea.Batch(loadDataCmd(m.projectPath, m.searchQuery, m.filters, m.page), tickCmd())
+		return m, tea.Batch(m.load(), tickCmd())
 
 	case dataMsg:
 		m.rows = msg.rows
 		m.totalCount = msg.totalCount
+		m.next = msg.next
 		m.lastErr = msg.err
 		m.lastRefresh = msg.refreshedAt
 		m.pageCount = (m.totalCount + pageSize - 1) / pageSize
@@ -217,9 +246,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "enter":
 				m.searchQuery = m.searchInput.Value()
 				m.searching = false
-				m.page = 0
-				m.selectedIdx = 0
-				return m, loadDataCmd(m.projectPath, m.searchQuery, m.filters, m.page)
+				m.firstPage()
+				return m, m.load()
 			case "esc":
 				m.searching = false
 				m.searchInput.SetValue(m.searchQuery)
@@ -243,9 +271,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.searchQuery != "" {
 				m.searchQuery = ""
 				m.searchInput.SetValue("")
-				m.page = 0
-				m.selectedIdx = 0
-				return m, loadDataCmd(m.projectPath, m.searchQuery, m.filters, m.page)
+				m.firstPage()
+				return m, m.load()
 			}
 			if m.OnBack != nil {
 				m.OnBack()
@@ -268,46 +295,101 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case key.Matches(msg, m.keyMap.NextPage):
-			if m.page < m.pageCount-1 {
-				m.page++
+			if m.nextPage() {
 				m.selectedIdx = 0
-				return m, loadDataCmd(m.projectPath, m.searchQuery, m.filters, m.page)
+				return m, m.load()
 			}
 			return m, nil
 
 		case key.Matches(msg, m.keyMap.PrevPage):
-			if m.page > 0 {
-				m.page--
+			if m.prevPage() {
 				m.selectedIdx = 0
-				return m, loadDataCmd(m.projectPath, m.searchQuery, m.filters, m.page)
+				return m, m.load()
 			}
 			return m, nil
 
 		case key.Matches(msg, m.keyMap.Select):
-			if len(m.rows) > 0 && m.selectedIdx < len(m.rows) {
-				if m.OnSelect != nil {
-					m.OnSelect(m.rows[m.selectedIdx].ID)
-				}
-			}
-			return m, nil
+			return m, m.selectCurrent()
 
 		case key.Matches(msg, m.keyMap.FilterTier):
 			m.filters.CycleTier()
-			m.page = 0
-			m.selectedIdx = 0
-			return m, loadDataCmd(m.projectPath, m.searchQuery, m.filters, m.page)
+			m.firstPage()
+			return m, m.load()
 
 		case key.Matches(msg, m.keyMap.FilterStatus):
 			m.filters.CycleStatus()
-			m.page = 0
-			m.selectedIdx = 0
-			return m, loadDataCmd(m.projectPath, m.searchQuery, m.filters, m.page)
+			m.firstPage()
+			return m, m.load()
 		}
 	}
 
 	return m, tea.Batch(cmds...)
 }
 
+// handleMouse handles clicks and wheel scrolling on the history table.
+func (m Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if m.searching {
+		return m, nil
+	}
+	if delta := components.WheelDelta(msg); delta != 0 {
+		return m.scroll(delta)
+	}
+	if !components.IsLeftClick(msg) {
+		return m, nil
+	}
+	idx := m.rowAt(msg.Y)
+	if idx < 0 {
+		return m, nil
+	}
+	m.selectedIdx = idx
+	if m.clicks.Click(m.rows[idx].ID, time.Now()) {
+		return m, m.selectCurrent()
+	}
+	return m, nil
+}
+
+// scroll moves the selection by delta, turning the page at either end.
+func (m Model) scroll(delta int) (tea.Model, tea.Cmd) {
+	next := m.selectedIdx + delta
+	switch {
+	case next >= len(m.rows):
+		if m.nextPage() {
+			m.selectedIdx = 0
+			return m, m.load()
+		}
+	case next < 0:
+		if m.prevPage() {
+			// Clamped once the previous page has loaded.
+			m.selectedIdx = pageSize - 1
+			return m, m.load()
+		}
+	default:
+		m.selectedIdx = next
+	}
+	return m, nil
+}
+
+// selectCurrent opens the selected row, notifying OnSelect and the parent view.
+func (m Model) selectCurrent() tea.Cmd {
+	if len(m.rows) == 0 || m.selectedIdx >= len(m.rows) {
+		return nil
+	}
+	id := m.rows[m.selectedIdx].ID
+	if m.OnSelect != nil {
+		m.OnSelect(id)
+	}
+	return func() tea.Msg { return OpenRequestMsg{RequestID: id} }
+}
+
+// rowAt maps a screen line to a row index, or -1 when no row is there.
+func (m Model) rowAt(y int) int {
+	if len(m.rows) == 0 {
+		return -1
+	}
+	top := lipgloss.Height(m.renderHeader()) + lipgloss.Height(m.renderSearchBar())
+	return m.table().RowAtLine(y - top)
+}
+
 // View renders the model.
 func (m Model) View() string {
 	if !m.ready {
@@ -358,6 +440,11 @@ func (m Model) renderHeader() string {
 		Render(lipgloss.JoinHorizontal(lipgloss.Top, title, spacer, pageInfo))
 }
 
+// Searching reports whether the search box has focus and takes keystrokes.
+func (m Model) Searching() bool {
+	return m.searching
+}
+
 func (m Model) renderSearchBar() string {
 	th := theme.Current
 
@@ -389,20 +476,21 @@ func (m Model) renderSearchBar() string {
 		Render(lipgloss.JoinHorizontal(lipgloss.Center, searchBox, "  ", filterSection))
 }
 
-func (m Model) renderTable() string {
-	th := theme.Current
-
-	// Calculate available height for table
-	tableHeight := max(5, m.height-10)
-
+// table builds the table component for the current page.
+func (m Model) table() *components.Table {
+	whenWidth := 10
+	if timefmt.Current().IsAbsolute() {
+		whenWidth = len(time.RFC3339) // 2006-01-02T15:04:05+07:00
+	}
 	columns := []components.Column{
 		{Header: "ID", Width: 10},
 		{Header: "Command", MinWidth: 20, MaxWidth: 50},
 		{Header: "Agent", Width: 12},
 		{Header: "Status", Width: 10},
-		{Header: "When", Width: 10},
+		{Header: "When", Width: whenWidth},
 	}
 
+	now := clock.OrReal(m.clock).Now()
 	var rows [][]string
 	for _, row := range m.rows {
 		cmd := row.Command
@@ -411,7 +499,7 @@ func (m Model) renderTable() string {
 		}
 
 		statusIcon := statusIcon(row.Status)
-		when := formatTimeAgo(row.CreatedAt)
+		when := timefmt.Current().Show(row.CreatedAt, now)
 
 		rows = append(rows, []string{
 			shortID(row.ID),
@@ -422,12 +510,19 @@ func (m Model) renderTable() string {
 		})
 	}
 
-	table := components.NewTable(columns).
+	return components.NewTable(columns).
 		WithRows(rows).
 		WithSelection(m.selectedIdx).
 		WithMaxWidth(m.width - 4)
+}
+
+func (m Model) renderTable() string {
+	th := theme.Current
+
+	// Calculate available height for table
+	tableHeight := max(5, m.height-10)
 
-	tableView := table.Render()
+	tableView := m.table().Render()
 
 	// Add empty state if no results
 	if len(m.rows) == 0 {
@@ -495,19 +590,62 @@ func tickCmd() tea.Cmd {
 	})
 }
 
-func loadDataCmd(projectPath, query string, filters Filters, page int) tea.Cmd {
+// load reloads the current page from its cursor.
+func (m Model) load() tea.Cmd {
+	var after *db.RequestCursor
+	if m.page < len(m.cursors) {
+		after = m.cursors[m.page]
+	}
+	return loadDataCmd(m.projectPath, m.searchQuery, m.filters, after)
+}
+
+// firstPage resets pagination after the query or filters change.
+func (m *Model) firstPage() {
+	m.page = 0
+	m.cursors = nil
+	m.next = nil
+	m.selectedIdx = 0
+}
+
+// nextPage advances to the page starting at m.next, if there is one.
+func (m *Model) nextPage() bool {
+	if m.next == nil {
+		return false
+	}
+	cursors := make([]*db.RequestCursor, m.page+2)
+	copy(cursors, m.cursors)
+	cursors[m.page+1] = m.next
+	m.cursors = cursors
+	m.page++
+	m.next = nil
+	return true
+}
+
+// prevPage steps back to the previous page, whose cursor is remembered.
+func (m *Model) prevPage() bool {
+	if m.page == 0 {
+		return false
+	}
+	m.page--
+	return true
+}
+
+func loadDataCmd(projectPath, query string, filters Filters, after *db.RequestCursor) tea.Cmd {
 	return func() tea.Msg {
-		rows, total, err := loadHistoryData(projectPath, query, filters, page)
+		rows, total, next, err := loadHistoryData(projectPath, query, filters, after)
 		return dataMsg{
 			rows:        rows,
 			totalCount:  total,
+			next:        next,
 			err:         err,
 			refreshedAt: time.Now().UTC(),
 		}
 	}
 }
 
-func loadHistoryData(projectPath, query string, filters Filters, page int) ([]HistoryRow, int, error) {
+// loadHistoryData loads the page of history after the given cursor (nil for
+// the first page), the total number of matches, and the next page's cursor.
+func loadHistoryData(projectPath, query string, filters Filters, after *db.RequestCursor) ([]HistoryRow, int, *db.RequestCursor, error) {
 	dbPath := filepath.Join(projectPath, ".slb", "state.db")
 	dbConn, err := db.OpenWithOptions(dbPath, db.OpenOptions{
 		CreateIfNotExists: false,
@@ -515,48 +653,29 @@ func loadHistoryData(projectPath, query string, filters Filters, page int) ([]Hi
 		ReadOnly:          true,
 	})
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, nil, err
 	}
 	defer dbConn.Close()
 
-	// Build search query
-	var requests []*db.Request
-	if query != "" {
-		requests, err = dbConn.SearchRequests(query)
-	} else {
-		requests, err = dbConn.ListAllRequests(projectPath)
+	pageQuery := db.RequestPageQuery{
+		ProjectPath: projectPath,
+		Search:      query,
+		Tier:        db.RiskTier(filters.TierFilter),
+		Status:      db.RequestStatus(filters.StatusFilter),
+		After:       after,
+		Limit:       pageSize,
 	}
+	total, err := dbConn.CountRequests(pageQuery)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, nil, err
 	}
-
-	// Apply filters
-	filtered := make([]*db.Request, 0, len(requests))
-	for _, r := range requests {
-		if filters.TierFilter != "" && string(r.RiskTier) != filters.TierFilter {
-			continue
-		}
-		if filters.StatusFilter != "" && string(r.Status) != filters.StatusFilter {
-			continue
-		}
-		filtered = append(filtered, r)
-	}
-
-	// Paginate
-	total := len(filtered)
-	start := page * pageSize
-	end := start + pageSize
-	if start > total {
-		start = total
-	}
-	if end > total {
-		end = total
+	requests, next, err := dbConn.ListRequestsPage(pageQuery)
+	if err != nil {
+		return nil, 0, nil, err
 	}
 
-	page_requests := filtered[start:end]
-
-	rows := make([]HistoryRow, 0, len(page_requests))
-	for _, r := range page_requests {
+	rows := make([]HistoryRow, 0, len(requests))
+	for _, r := range requests {
 		cmd := r.Command.DisplayRedacted
 		if cmd == "" {
 			cmd = r.Command.Raw
@@ -572,7 +691,7 @@ func loadHistoryData(projectPath, query string, filters Filters, page int) ([]Hi
 		})
 	}
 
-	return rows, total, nil
+	return rows, total, next, nil
 }
 
 func shortID(id string) string {
@@ -582,48 +701,18 @@ func shortID(id string) string {
 	return id[:8]
 }
 
-func formatTimeAgo(t time.Time) string {
-	if t.IsZero() {
-		return "never"
-	}
-
-	d := time.Since(t)
-	switch {
-	case d < time.Minute:
-		return "just now"
-	case d < time.Hour:
-		mins := int(d.Minutes())
-		if mins == 1 {
-			return "1m ago"
-		}
-		return fmt.Sprintf("%dm ago", mins)
-	case d < 24*time.Hour:
-		hours := int(d.Hours())
-		if hours == 1 {
-			return "1h ago"
-		}
-		return fmt.Sprintf("%dh ago", hours)
-	default:
-		days := int(d.Hours() / 24)
-		if days == 1 {
-			return "1d ago"
-		}
-		return fmt.Sprintf("%dd ago", days)
-	}
-}
-
 func statusIcon(s db.RequestStatus) string {
 	switch s {
 	case db.StatusApproved, db.StatusExecuted:
-		return "✓"
+		return utils.Glyph("✓", "+")
 	case db.StatusRejected, db.StatusExecutionFailed:
-		return "✗"
+		return utils.Glyph("✗", "x")
 	case db.StatusPending:
-		return "⋯"
+		return utils.Glyph("⋯", ".")
 	case db.StatusTimeout, db.StatusEscalated:
-		return "⚠"
+		return utils.Glyph("⚠", "!")
 	case db.StatusCancelled:
-		return "○"
+		return utils.Glyph("○", "-")
 	default:
 		return "?"
 	}
@@ -0,0 +1,99 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+// seedHistory creates n requests with a realistic mix of tiers, statuses and
+// timestamps.
+func seedHistory(tb testing.TB, n int) *testHarness {
+	tb.Helper()
+	h := newTestHarness(tb)
+	sess := createTestSession(tb, h.db, h.projectPath)
+	testutil.MakeRequests(tb, h.db, n, testutil.DatasetSessions(sess), testutil.DatasetRand(testutil.NewRand(tb, 1)))
+	return h
+}
+
+// TestLoadHistoryData_WalksLargeDataset pages through a generated dataset
+// whose timestamps collide often and checks every request is seen once, in
+// history order.
+func TestLoadHistoryData_WalksLargeDataset(t *testing.T) {
+	h := newTestHarness(t)
+	sess := createTestSession(t, h.db, h.projectPath)
+	end := time.Now().UTC().Truncate(time.Second)
+	reqs := testutil.MakeRequests(t, h.db, 5*pageSize+7,
+		testutil.DatasetSessions(sess),
+		testutil.DatasetRand(testutil.NewRand(t, 5)),
+		testutil.DatasetTimeRange(end.Add(-time.Minute), end),
+	)
+
+	seen := make(map[string]bool)
+	var prev *HistoryRow
+	var after *db.RequestCursor
+	for pages := 0; ; pages++ {
+		if pages > len(reqs) {
+			t.Fatal("pagination did not terminate")
+		}
+		rows, total, next, err := loadHistoryData(h.projectPath, "", Filters{}, after)
+		if err != nil {
+			t.Fatalf("loadHistoryData failed: %v", err)
+		}
+		if total != len(reqs) {
+			t.Fatalf("total = %d, want %d", total, len(reqs))
+		}
+		for i := range rows {
+			row := rows[i]
+			if seen[row.ID] {
+				t.Fatalf("request %s returned twice", row.ID)
+			}
+			seen[row.ID] = true
+			if prev != nil && (row.CreatedAt.After(prev.CreatedAt) || (row.CreatedAt.Equal(prev.CreatedAt) && row.ID > prev.ID)) {
+				t.Fatalf("request %s out of order after %s", row.ID, prev.ID)
+			}
+			prev = &row
+		}
+		if next == nil {
+			break
+		}
+		after = next
+	}
+	if len(seen) != len(reqs) {
+		t.Fatalf("saw %d of %d requests", len(seen), len(reqs))
+	}
+}
+
+func BenchmarkLoadHistoryData(b *testing.B) {
+	h := seedHistory(b, 1000)
+
+	// Cursor for the last page, to check deep pages cost the same.
+	_, deep, err := h.db.ListRequestsPage(db.RequestPageQuery{ProjectPath: h.projectPath, Limit: 1000 - pageSize})
+	if err != nil || deep == nil {
+		b.Fatalf("locating last page: %v", err)
+	}
+
+	cases := []struct {
+		name    string
+		query   string
+		filters Filters
+		after   *db.RequestCursor
+	}{
+		{"first_page", "", Filters{}, nil},
+		{"last_page", "", Filters{}, deep},
+		{"tier_filter", "", Filters{TierFilter: string(db.RiskTierCritical)}, nil},
+		{"search", "build", Filters{}, nil},
+	}
+	for _, bc := range cases {
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, _, _, err := loadHistoryData(h.projectPath, bc.query, bc.filters, bc.after); err != nil {
+					b.Fatalf("loadHistoryData failed: %v", err)
+				}
+			}
+		})
+	}
+}
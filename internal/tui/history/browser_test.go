@@ -9,6 +9,7 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 
 	"github.com/Dicklesworthstone/slb/internal/db"
 )
@@ -324,6 +325,7 @@ func TestBrowserModelUpdateKeyNextPage(t *testing.T) {
 	m := New("")
 	m.page = 0
 	m.pageCount = 3
+	m.next = &db.RequestCursor{ID: "REQ-21"}
 
 	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRight})
 	model := updated.(Model)
@@ -381,6 +383,38 @@ func TestBrowserModelUpdateKeyPrevPageAtStart(t *testing.T) {
 	}
 }
 
+func TestBrowserModelPageCursors(t *testing.T) {
+	m := New("")
+	m.pageCount = 3
+	second := &db.RequestCursor{ID: "REQ-20"}
+
+	updated, _ := m.Update(dataMsg{rows: []HistoryRow{{ID: "REQ-1"}}, totalCount: 45, next: second})
+	m = updated.(Model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	m = updated.(Model)
+	if m.page != 1 || len(m.cursors) != 2 || m.cursors[1] != second || m.next != nil {
+		t.Fatalf("expected page 1 to start at the stored cursor, got page %d cursors %v", m.page, m.cursors)
+	}
+
+	// Without a next cursor the last page cannot be passed.
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if updated.(Model).page != 1 || cmd != nil {
+		t.Error("expected next page to be unavailable until data reports a cursor")
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	m = updated.(Model)
+	if m.page != 0 || m.cursors[1] != second {
+		t.Errorf("expected prev to keep remembered cursors, got page %d", m.page)
+	}
+
+	// Changing filters starts over.
+	m.firstPage()
+	if m.page != 0 || m.cursors != nil || m.next != nil {
+		t.Errorf("expected firstPage to reset pagination, got %+v", m.cursors)
+	}
+}
+
 func TestBrowserModelUpdateKeySelect(t *testing.T) {
 	m := New("")
 	m.rows = []HistoryRow{{ID: "REQ-123"}}
@@ -403,6 +437,90 @@ func TestBrowserModelUpdateKeySelect(t *testing.T) {
 	}
 }
 
+func TestBrowserModelUpdateKeySelectOpensRequest(t *testing.T) {
+	m := New("")
+	m.rows = []HistoryRow{{ID: "REQ-123"}}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("select should return an open command")
+	}
+	if msg, ok := cmd().(OpenRequestMsg); !ok || msg.RequestID != "REQ-123" {
+		t.Errorf("expected OpenRequestMsg for REQ-123, got %#v", cmd())
+	}
+}
+
+func TestBrowserModelMouseClickAndDoubleClick(t *testing.T) {
+	m := New("")
+	m.ready = true
+	m.width = 120
+	m.height = 40
+	m.rows = []HistoryRow{{ID: "REQ-1"}, {ID: "REQ-2"}, {ID: "REQ-3"}}
+
+	// Header line, search bar, then table header + separator.
+	firstRow := lipgloss.Height(m.renderHeader()) + lipgloss.Height(m.renderSearchBar()) + 2
+	click := tea.MouseMsg{X: 5, Y: firstRow + 2, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft}
+
+	updated, cmd := m.Update(click)
+	m = updated.(Model)
+	if cmd != nil {
+		t.Error("single click should only select")
+	}
+	if m.selectedIdx != 2 {
+		t.Fatalf("expected third row selected, got %d", m.selectedIdx)
+	}
+
+	_, cmd = m.Update(click)
+	if cmd == nil {
+		t.Fatal("double-click should open the request")
+	}
+	if msg, ok := cmd().(OpenRequestMsg); !ok || msg.RequestID != "REQ-3" {
+		t.Errorf("expected OpenRequestMsg for REQ-3, got %#v", cmd())
+	}
+
+	// Clicking the table header selects nothing.
+	header := tea.MouseMsg{X: 5, Y: firstRow - 2, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft}
+	updated, _ = m.Update(header)
+	if updated.(Model).selectedIdx != 2 {
+		t.Error("clicking the header should not change selection")
+	}
+}
+
+func TestBrowserModelMouseWheelPaginates(t *testing.T) {
+	m := New("")
+	m.rows = []HistoryRow{{ID: "REQ-1"}, {ID: "REQ-2"}}
+	m.pageCount = 2
+	m.next = &db.RequestCursor{ID: "REQ-2"}
+
+	down := tea.MouseMsg{Action: tea.MouseActionPress, Button: tea.MouseButtonWheelDown}
+	up := tea.MouseMsg{Action: tea.MouseActionPress, Button: tea.MouseButtonWheelUp}
+
+	updated, cmd := m.Update(down)
+	m = updated.(Model)
+	if m.selectedIdx != 1 || cmd != nil {
+		t.Fatalf("wheel down should move selection within the page, got %d", m.selectedIdx)
+	}
+
+	updated, cmd = m.Update(down)
+	m = updated.(Model)
+	if m.page != 1 || m.selectedIdx != 0 || cmd == nil {
+		t.Fatalf("wheel past the last row should load the next page, got page %d sel %d", m.page, m.selectedIdx)
+	}
+
+	updated, cmd = m.Update(up)
+	m = updated.(Model)
+	if m.page != 0 || cmd == nil {
+		t.Errorf("wheel above the first row should load the previous page, got page %d", m.page)
+	}
+
+	// Wheel is ignored while typing a search.
+	m.searching = true
+	updated, _ = m.Update(down)
+	if updated.(Model).page != 0 {
+		t.Error("wheel should be ignored while searching")
+	}
+}
+
 func TestBrowserModelUpdateKeySelectEmptyRows(t *testing.T) {
 	m := New("")
 	m.rows = nil
@@ -567,32 +685,6 @@ func TestShortID(t *testing.T) {
 	}
 }
 
-func TestBrowserFormatTimeAgo(t *testing.T) {
-	tests := []struct {
-		name     string
-		time     time.Time
-		expected string
-	}{
-		{"zero", time.Time{}, "never"},
-		{"just now", time.Now(), "just now"},
-		{"1m", time.Now().Add(-time.Minute), "1m ago"},
-		{"5m", time.Now().Add(-5 * time.Minute), "5m ago"},
-		{"1h", time.Now().Add(-time.Hour), "1h ago"},
-		{"3h", time.Now().Add(-3 * time.Hour), "3h ago"},
-		{"1d", time.Now().Add(-24 * time.Hour), "1d ago"},
-		{"3d", time.Now().Add(-72 * time.Hour), "3d ago"},
-	}
-
-	for _, tc := range tests {
-		t.Run(tc.name, func(t *testing.T) {
-			got := formatTimeAgo(tc.time)
-			if got != tc.expected {
-				t.Errorf("formatTimeAgo: expected %q, got %q", tc.expected, got)
-			}
-		})
-	}
-}
-
 func TestBrowserStatusIcon(t *testing.T) {
 	tests := []struct {
 		status   db.RequestStatus
@@ -815,6 +907,7 @@ func TestBrowserModelUpdateKeyNextPageVim(t *testing.T) {
 	m := New("")
 	m.page = 0
 	m.pageCount = 3
+	m.next = &db.RequestCursor{ID: "REQ-21"}
 
 	// Test 'l' for next page
 	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'l'}})
@@ -856,7 +949,7 @@ func TestLoadHistoryData(t *testing.T) {
 	createTestRequest(t, h.db, sess, "git push --force", db.RiskTierDangerous, db.StatusApproved)
 
 	// Load data
-	rows, total, err := loadHistoryData(h.projectPath, "", Filters{}, 0)
+	rows, total, _, err := loadHistoryData(h.projectPath, "", Filters{}, nil)
 	if err != nil {
 		t.Fatalf("loadHistoryData failed: %v", err)
 	}
@@ -877,7 +970,7 @@ func TestLoadHistoryDataWithSearch(t *testing.T) {
 	createTestRequest(t, h.db, sess, "npm install", db.RiskTierCaution, db.StatusApproved)
 
 	// Search for docker
-	rows, _, err := loadHistoryData(h.projectPath, "docker", Filters{}, 0)
+	rows, _, _, err := loadHistoryData(h.projectPath, "docker", Filters{}, nil)
 	if err != nil {
 		t.Fatalf("loadHistoryData with search failed: %v", err)
 	}
@@ -895,7 +988,7 @@ func TestLoadHistoryDataWithTierFilter(t *testing.T) {
 
 	// Filter by critical tier
 	filters := Filters{TierFilter: string(db.RiskTierCritical)}
-	rows, total, err := loadHistoryData(h.projectPath, "", filters, 0)
+	rows, total, _, err := loadHistoryData(h.projectPath, "", filters, nil)
 	if err != nil {
 		t.Fatalf("loadHistoryData with tier filter failed: %v", err)
 	}
@@ -920,7 +1013,7 @@ func TestLoadHistoryDataWithStatusFilter(t *testing.T) {
 
 	// Filter by approved status
 	filters := Filters{StatusFilter: string(db.StatusApproved)}
-	rows, total, err := loadHistoryData(h.projectPath, "", filters, 0)
+	rows, total, _, err := loadHistoryData(h.projectPath, "", filters, nil)
 	if err != nil {
 		t.Fatalf("loadHistoryData with status filter failed: %v", err)
 	}
@@ -943,7 +1036,7 @@ func TestLoadHistoryDataPagination(t *testing.T) {
 	}
 
 	// First page
-	rows, total, err := loadHistoryData(h.projectPath, "", Filters{}, 0)
+	rows, total, next, err := loadHistoryData(h.projectPath, "", Filters{}, nil)
 	if err != nil {
 		t.Fatalf("loadHistoryData page 0 failed: %v", err)
 	}
@@ -955,8 +1048,16 @@ func TestLoadHistoryDataPagination(t *testing.T) {
 		t.Errorf("expected %d rows on first page, got %d", pageSize, len(rows))
 	}
 
+	if next == nil {
+		t.Fatal("expected a cursor for the second page")
+	}
+	seen := make(map[string]bool)
+	for _, r := range rows {
+		seen[r.ID] = true
+	}
+
 	// Second page
-	rows, _, err = loadHistoryData(h.projectPath, "", Filters{}, 1)
+	rows, _, next, err = loadHistoryData(h.projectPath, "", Filters{}, next)
 	if err != nil {
 		t.Fatalf("loadHistoryData page 1 failed: %v", err)
 	}
@@ -964,10 +1065,18 @@ func TestLoadHistoryDataPagination(t *testing.T) {
 	if len(rows) != 5 {
 		t.Errorf("expected 5 rows on second page, got %d", len(rows))
 	}
+	if next != nil {
+		t.Errorf("expected no cursor after the last page, got %+v", next)
+	}
+	for _, r := range rows {
+		if seen[r.ID] {
+			t.Errorf("request %s appears on both pages", r.ID)
+		}
+	}
 }
 
 func TestLoadHistoryDataNonexistentDB(t *testing.T) {
-	_, _, err := loadHistoryData("/nonexistent/path", "", Filters{}, 0)
+	_, _, _, err := loadHistoryData("/nonexistent/path", "", Filters{}, nil)
 	if err == nil {
 		t.Error("expected error for nonexistent database")
 	}
@@ -976,7 +1085,7 @@ func TestLoadHistoryDataNonexistentDB(t *testing.T) {
 func TestLoadHistoryDataEmptyDB(t *testing.T) {
 	h := newTestHarness(t)
 
-	rows, total, err := loadHistoryData(h.projectPath, "", Filters{}, 0)
+	rows, total, _, err := loadHistoryData(h.projectPath, "", Filters{}, nil)
 	if err != nil {
 		t.Fatalf("loadHistoryData on empty DB failed: %v", err)
 	}
@@ -995,7 +1104,7 @@ func TestLoadDataCmd(t *testing.T) {
 	sess := createTestSession(t, h.db, h.projectPath)
 	createTestRequest(t, h.db, sess, "test cmd", db.RiskTierCaution, db.StatusPending)
 
-	cmd := loadDataCmd(h.projectPath, "", Filters{}, 0)
+	cmd := loadDataCmd(h.projectPath, "", Filters{}, nil)
 	if cmd == nil {
 		t.Fatal("loadDataCmd should return non-nil command")
 	}
@@ -1028,7 +1137,7 @@ type testHarness struct {
 	db          *db.DB
 }
 
-func newTestHarness(t *testing.T) *testHarness {
+func newTestHarness(t testing.TB) *testHarness {
 	t.Helper()
 
 	tmpDir := t.TempDir()
@@ -1059,7 +1168,7 @@ func mkdir(path string) error {
 	return os.MkdirAll(path, 0755)
 }
 
-func createTestSession(t *testing.T, database *db.DB, projectPath string) *db.Session {
+func createTestSession(t testing.TB, database *db.DB, projectPath string) *db.Session {
 	t.Helper()
 
 	sess := &db.Session{
@@ -1076,7 +1185,7 @@ func createTestSession(t *testing.T, database *db.DB, projectPath string) *db.Se
 	return sess
 }
 
-func createTestRequest(t *testing.T, database *db.DB, sess *db.Session, cmd string, tier db.RiskTier, status db.RequestStatus) *db.Request {
+func createTestRequest(t testing.TB, database *db.DB, sess *db.Session, cmd string, tier db.RiskTier, status db.RequestStatus) *db.Request {
 	t.Helper()
 
 	exp := time.Now().Add(30 * time.Minute)
@@ -0,0 +1,34 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+	"github.com/Dicklesworthstone/slb/internal/tui/tuitest"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestBrowserSnapshot(t *testing.T) {
+	tuitest.Pin(t)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	rows := []HistoryRow{
+		{ID: "req-0001-critical", Command: "kubectl delete namespace production", Agent: "BlueLake", Status: db.StatusApproved, Tier: db.RiskTierCritical, CreatedAt: now.Add(-5 * time.Minute)},
+		{ID: "req-0002-dangerous", Command: "git push --force origin main", Agent: "GreenCastle", Status: db.StatusRejected, Tier: db.RiskTierDangerous, CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: "req-0003-caution", Command: "rm -rf ./build", Agent: "RedStone", Status: db.StatusExecuted, Tier: db.RiskTierCaution, CreatedAt: now.Add(-26 * time.Hour)},
+		{ID: "req-0004-timeout", Command: "terraform destroy -auto-approve", Agent: "BlueLake", Status: db.StatusTimeout, Tier: db.RiskTierCritical, CreatedAt: now.Add(-72 * time.Hour)},
+	}
+
+	for _, size := range tuitest.Sizes {
+		t.Run(size.String(), func(t *testing.T) {
+			m := New("/work/project")
+			m.SetClock(testutil.NewFakeClock(now))
+
+			updated, _ := m.Update(dataMsg{rows: rows, totalCount: len(rows), refreshedAt: now})
+			updated, _ = updated.(Model).Update(tea.WindowSizeMsg{Width: size.Width, Height: size.Height})
+			tuitest.RequireSnapshot(t, "history_"+size.String(), updated.(Model).View())
+		})
+	}
+}
@@ -4,6 +4,8 @@ package icons
 import (
 	"os"
 	"strings"
+
+	"github.com/Dicklesworthstone/slb/internal/utils"
 )
 
 // IconSet defines a set of icons for the TUI.
@@ -101,6 +103,11 @@ func SetNerdFonts(enabled bool) {
 	useNerdFonts = enabled
 }
 
+// NerdFonts reports whether Nerd Font icons are enabled.
+func NerdFonts() bool {
+	return useNerdFonts
+}
+
 // nerd returns the Nerd Font icon set.
 func nerd() *IconSet {
 	return &IconSet{
@@ -233,7 +240,7 @@ func ascii() *IconSet {
 
 // Current returns the current icon set based on configuration.
 func Current() *IconSet {
-	if useNerdFonts {
+	if useNerdFonts && !utils.PlainOutput() {
 		return nerd()
 	}
 	return ascii()
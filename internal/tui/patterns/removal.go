@@ -15,6 +15,7 @@ import (
 	"github.com/Dicklesworthstone/slb/internal/db"
 	"github.com/Dicklesworthstone/slb/internal/tui/components"
 	"github.com/Dicklesworthstone/slb/internal/tui/theme"
+	"github.com/Dicklesworthstone/slb/internal/utils"
 )
 
 const refreshInterval = 5 * time.Second
@@ -100,6 +101,9 @@ type Model struct {
 	// Filters
 	filterType string // "", "remove", "suggest", "add"
 
+	// ReadOnly disables approve/reject for spectators.
+	ReadOnly bool
+
 	// Callbacks
 	OnBack    func()
 	OnApprove func(id int64)
@@ -216,6 +220,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case key.Matches(msg, m.keyMap.Approve):
+			if m.ReadOnly {
+				return m, nil
+			}
 			if len(m.rows) > 0 && m.selectedIdx < len(m.rows) {
 				row := m.rows[m.selectedIdx]
 				if row.Status == db.PatternChangeStatusPending {
@@ -225,6 +232,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case key.Matches(msg, m.keyMap.Reject):
+			if m.ReadOnly {
+				return m, nil
+			}
 			if len(m.rows) > 0 && m.selectedIdx < len(m.rows) {
 				row := m.rows[m.selectedIdx]
 				if row.Status == db.PatternChangeStatusPending {
@@ -280,6 +290,9 @@ func (m Model) renderHeader() string {
 		Foreground(th.Mauve).
 		Bold(true).
 		Render("Pattern Change Review")
+	if m.ReadOnly {
+		title += " " + components.RenderReadOnlyBadge()
+	}
 
 	count := lipgloss.NewStyle().
 		Foreground(th.Subtext).
@@ -417,13 +430,11 @@ func (m Model) renderFooter() string {
 	th := theme.Current
 
 	// Key hints
-	keys := []string{
-		"[a] approve",
-		"[r] reject",
-		"[f] filter",
-		"[↑/↓] navigate",
-		"[esc] back",
+	var keys []string
+	if !m.ReadOnly {
+		keys = append(keys, "[a] approve", "[r] reject")
 	}
+	keys = append(keys, "[f] filter", "[↑/↓] navigate", "[esc] back")
 	hint := lipgloss.NewStyle().
 		Foreground(th.Subtext).
 		Render(strings.Join(keys, "  "))
@@ -579,11 +590,11 @@ func performAction(projectPath string, id int64, action string) error {
 func statusIcon(s string) string {
 	switch s {
 	case db.PatternChangeStatusApproved:
-		return "✓"
+		return utils.Glyph("✓", "+")
 	case db.PatternChangeStatusRejected:
-		return "✗"
+		return utils.Glyph("✗", "x")
 	case db.PatternChangeStatusPending:
-		return "⋯"
+		return utils.Glyph("⋯", ".")
 	default:
 		return "?"
 	}
@@ -592,13 +603,13 @@ func statusIcon(s string) string {
 func typeIcon(t string) string {
 	switch t {
 	case db.PatternChangeTypeRemove:
-		return "−"
+		return utils.Glyph("−", "-")
 	case db.PatternChangeTypeSuggest:
 		return "?"
 	case db.PatternChangeTypeAdd:
 		return "+"
 	default:
-		return "•"
+		return utils.Glyph("•", "-")
 	}
 }
 
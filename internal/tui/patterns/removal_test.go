@@ -310,6 +310,34 @@ func TestModelUpdateKeyReject(t *testing.T) {
 	_ = cmd
 }
 
+func TestModelUpdateReadOnlyIgnoresActions(t *testing.T) {
+	m := New("")
+	m.ReadOnly = true
+	m.rows = []RemovalRow{
+		{ID: 42, Status: db.PatternChangeStatusPending},
+	}
+
+	for _, r := range []rune{'a', 'r'} {
+		if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}); cmd != nil {
+			t.Errorf("key %q should be ignored in read-only mode", r)
+		}
+	}
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil {
+		t.Error("enter should not approve in read-only mode")
+	}
+
+	m.ready = true
+	m.width = 120
+	m.height = 24
+	view := m.View()
+	if !strings.Contains(view, "READ-ONLY") {
+		t.Error("header should show READ-ONLY badge")
+	}
+	if strings.Contains(view, "[a] approve") {
+		t.Error("footer should not advertise approve in read-only mode")
+	}
+}
+
 func TestModelUpdateKeyFilter(t *testing.T) {
 	m := New("")
 	m.ready = true
@@ -10,6 +10,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/i18n"
 	"github.com/Dicklesworthstone/slb/internal/tui/components"
 	"github.com/Dicklesworthstone/slb/internal/tui/theme"
 )
@@ -60,7 +61,7 @@ type ApproveModel struct {
 func NewApproveModel(request *db.Request) *ApproveModel {
 	// Create textarea for comments
 	ti := textarea.New()
-	ti.Placeholder = "Optional: Add comments about your approval..."
+	ti.Placeholder = i18n.T("tui.approve.placeholder")
 	ti.ShowLineNumbers = false
 	ti.SetHeight(4)
 	ti.Focus()
@@ -120,7 +121,7 @@ func (m *ApproveModel) View() string {
 		Foreground(th.Green).
 		Bold(true).
 		Padding(1, 0)
-	b.WriteString(titleStyle.Render("Approve Request"))
+	b.WriteString(titleStyle.Render(i18n.T("tui.approve.title")))
 	b.WriteString("\n\n")
 
 	// Request summary
@@ -136,9 +137,9 @@ func (m *ApproveModel) View() string {
 	tierBadge := components.RenderRiskIndicator(string(m.Request.RiskTier))
 
 	summary := lipgloss.JoinVertical(lipgloss.Left,
-		"Request: "+m.Request.ID,
-		"Command: "+cmdPreview,
-		"Tier: "+tierBadge,
+		i18n.T("tui.form.request", m.Request.ID),
+		i18n.T("tui.form.command", cmdPreview),
+		i18n.T("tui.form.tier", tierBadge),
 	)
 	b.WriteString(summaryStyle.Render(summary))
 	b.WriteString("\n\n")
@@ -149,9 +150,9 @@ func (m *ApproveModel) View() string {
 		Bold(true).
 		Padding(0, 2)
 
-	confirmMsg := "You are about to APPROVE this request."
+	confirmMsg := i18n.T("tui.approve.confirm")
 	if m.Request.RiskTier == db.RiskTierCritical {
-		confirmMsg += "\nThis is a CRITICAL tier request requiring 2+ approvals."
+		confirmMsg += "\n" + i18n.T("tui.approve.critical")
 	}
 	b.WriteString(confirmStyle.Render(confirmMsg))
 	b.WriteString("\n\n")
@@ -161,7 +162,7 @@ func (m *ApproveModel) View() string {
 		Foreground(th.Blue).
 		Bold(true).
 		Padding(0, 2)
-	b.WriteString(labelStyle.Render("Comments (optional):"))
+	b.WriteString(labelStyle.Render(i18n.T("tui.approve.comments")))
 	b.WriteString("\n")
 
 	inputStyle := lipgloss.NewStyle().
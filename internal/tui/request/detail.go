@@ -11,7 +11,10 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/Dicklesworthstone/slb/internal/clock"
+	"github.com/Dicklesworthstone/slb/internal/core"
 	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
 	"github.com/Dicklesworthstone/slb/internal/tui/components"
 	"github.com/Dicklesworthstone/slb/internal/tui/icons"
 	"github.com/Dicklesworthstone/slb/internal/tui/theme"
@@ -98,7 +101,10 @@ const (
 type DetailModel struct {
 	Request  *db.Request
 	Reviews  []db.Review
-	Session  *db.Session // Current session for approval eligibility
+	Events   []*db.RequestEvent // Recorded lifecycle events; empty for older requests
+	Session  *db.Session        // Current session for approval eligibility
+	Viewers  []db.RequestViewer // Other reviewers with the request open
+	ReadOnly bool               // Spectator mode: approve/reject/execute are disabled
 	Width    int
 	Height   int
 	KeyMap   DetailKeyMap
@@ -119,6 +125,16 @@ type DetailModel struct {
 
 	// Copied flag for feedback
 	copied bool
+
+	// notice explains a change that landed while a form was open, or why a
+	// decision was not submitted.
+	notice string
+	// conflict holds the next submit back: another reviewer decided while
+	// the form was open and the reviewer has not seen it yet.
+	conflict bool
+
+	// clock renders relative times and checks expiry; the real clock when nil.
+	clock clock.Clock
 }
 
 // NewDetailModel creates a new request detail model.
@@ -128,6 +144,7 @@ func NewDetailModel(request *db.Request, reviews []db.Review) *DetailModel {
 		Reviews: reviews,
 		KeyMap:  DefaultDetailKeyMap(),
 		Mode:    DetailModeView,
+		clock:   clock.Real,
 	}
 }
 
@@ -137,11 +154,58 @@ func (m *DetailModel) WithSession(s *db.Session) *DetailModel {
 	return m
 }
 
+// WithEvents sets the recorded lifecycle events shown in the timeline.
+func (m *DetailModel) WithEvents(events []*db.RequestEvent) *DetailModel {
+	m.Events = events
+	return m
+}
+
+// WithClock sets the clock used for relative times and approval expiry.
+func (m *DetailModel) WithClock(c clock.Clock) *DetailModel {
+	m.clock = clock.OrReal(c)
+	return m
+}
+
+func (m *DetailModel) now() time.Time {
+	return clock.OrReal(m.clock).Now()
+}
+
+// WithReadOnly puts the view in spectator mode, disabling all actions.
+func (m *DetailModel) WithReadOnly(readOnly bool) *DetailModel {
+	m.ReadOnly = readOnly
+	return m
+}
+
+// WithViewers sets the other reviewers who have the request open.
+func (m *DetailModel) WithViewers(viewers []db.RequestViewer) *DetailModel {
+	m.Viewers = otherViewers(viewers, m.Session)
+	return m
+}
+
 // Init initializes the model.
 func (m *DetailModel) Init() tea.Cmd {
 	return nil
 }
 
+// Rerender redraws the scrollable content, for example after the timestamp
+// style changed.
+func (m *DetailModel) Rerender() {
+	if m.ready {
+		m.viewport.SetContent(m.renderContent())
+	}
+}
+
+// RefreshMsg carries a fresh copy of the request shown in the detail view,
+// so decisions made elsewhere show up while it is open.
+type RefreshMsg struct {
+	Request *db.Request
+	Reviews []db.Review
+	Events  []*db.RequestEvent
+	// Viewers are the reviewers who have the request open, possibly
+	// including the current session.
+	Viewers []db.RequestViewer
+}
+
 // Update handles messages.
 func (m *DetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
@@ -167,8 +231,14 @@ func (m *DetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			updated, cmd := m.approveForm.Update(msg)
 			m.approveForm = updated.(*ApproveModel)
 			if m.approveForm.Submitted {
-				if m.OnApprove != nil {
-					cmds = append(cmds, m.OnApprove(m.Request.ID, m.approveForm.Comments))
+				switch m.gateSubmit("approval") {
+				case submitHold:
+					m.approveForm.Submitted = false
+					return m, cmd
+				case submitSend:
+					if m.OnApprove != nil {
+						cmds = append(cmds, m.OnApprove(m.Request.ID, m.approveForm.Comments))
+					}
 				}
 				m.Mode = DetailModeView
 				m.approveForm = nil
@@ -176,15 +246,21 @@ func (m *DetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.Mode = DetailModeView
 				m.approveForm = nil
 			}
-			return m, cmd
+			return m, tea.Batch(append(cmds, cmd)...)
 		}
 
 		if m.Mode == DetailModeReject && m.rejectForm != nil {
 			updated, cmd := m.rejectForm.Update(msg)
 			m.rejectForm = updated.(*RejectModel)
 			if m.rejectForm.Submitted {
-				if m.OnReject != nil {
-					cmds = append(cmds, m.OnReject(m.Request.ID, m.rejectForm.Reason))
+				switch m.gateSubmit("rejection") {
+				case submitHold:
+					m.rejectForm.Submitted = false
+					return m, cmd
+				case submitSend:
+					if m.OnReject != nil {
+						cmds = append(cmds, m.OnReject(m.Request.ID, m.rejectForm.Reason))
+					}
 				}
 				m.Mode = DetailModeView
 				m.rejectForm = nil
@@ -192,13 +268,14 @@ func (m *DetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.Mode = DetailModeView
 				m.rejectForm = nil
 			}
-			return m, cmd
+			return m, tea.Batch(append(cmds, cmd)...)
 		}
 
 		// Handle main view keybindings
 		switch {
 		case key.Matches(msg, m.KeyMap.Approve):
 			if m.canApprove() {
+				m.notice, m.conflict = "", false
 				m.Mode = DetailModeApprove
 				m.approveForm = NewApproveModel(m.Request)
 				m.approveForm.Width = m.Width
@@ -207,6 +284,7 @@ func (m *DetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case key.Matches(msg, m.KeyMap.Reject):
 			if m.canReject() {
+				m.notice, m.conflict = "", false
 				m.Mode = DetailModeReject
 				m.rejectForm = NewRejectModel(m.Request)
 				m.rejectForm.Width = m.Width
@@ -252,6 +330,10 @@ func (m *DetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case clearCopiedMsg:
 		m.copied = false
+
+	case RefreshMsg:
+		m.applyRefresh(msg)
+		return m, nil
 	}
 
 	// Update viewport
@@ -266,6 +348,111 @@ func (m *DetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 type clearCopiedMsg struct{}
 
+// applyRefresh shows a fresh copy of the request. A decision that landed
+// while a form is open is called out, and holds the form's next submit.
+func (m *DetailModel) applyRefresh(msg RefreshMsg) {
+	if msg.Request == nil || msg.Request.ID != m.Request.ID {
+		return
+	}
+	m.Viewers = otherViewers(msg.Viewers, m.Session)
+
+	if m.Mode != DetailModeView {
+		if change := m.describeChange(msg); change != "" {
+			m.notice = change
+			m.conflict = true
+		}
+	}
+	m.Request = msg.Request
+	m.Reviews = msg.Reviews
+	if msg.Events != nil {
+		m.Events = msg.Events
+	}
+	if m.ready {
+		m.viewport.SetContent(m.renderContent())
+	}
+}
+
+// describeChange summarises decisions in a refresh that the view has not
+// shown yet: other reviewers' reviews and a status change.
+func (m *DetailModel) describeChange(msg RefreshMsg) string {
+	seen := make(map[string]bool, len(m.Reviews))
+	for _, rev := range m.Reviews {
+		seen[rev.ID] = true
+	}
+	var parts []string
+	for _, rev := range msg.Reviews {
+		if seen[rev.ID] || (m.Session != nil && rev.ReviewerSessionID == m.Session.ID) {
+			continue
+		}
+		verb := "approved"
+		if rev.Decision == db.DecisionReject {
+			verb = "rejected"
+		}
+		parts = append(parts, fmt.Sprintf("%s %s this request", rev.ReviewerAgent, verb))
+	}
+	if msg.Request.Status != m.Request.Status {
+		parts = append(parts, "it is now "+strings.ToUpper(string(msg.Request.Status)))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "While you were writing, " + strings.Join(parts, "; ") + "."
+}
+
+// submitGate is what happens to a submitted approval or rejection.
+type submitGate int
+
+const (
+	submitSend submitGate = iota
+	// submitHold keeps the form open so the reviewer sees what changed
+	// before submitting again.
+	submitHold
+	// submitRefuse drops the decision; the request can no longer take it.
+	submitRefuse
+)
+
+// gateSubmit decides whether a submitted decision goes through. One the
+// request can no longer take is refused with a notice, and one submitted
+// after another reviewer's decision landed is held until submitted again.
+func (m *DetailModel) gateSubmit(decision string) submitGate {
+	if m.ReadOnly {
+		return submitRefuse
+	}
+	if !m.canApprove() {
+		m.notice = fmt.Sprintf("Your %s was not submitted: the request is %s.",
+			decision, strings.ToUpper(string(m.Request.Status)))
+		m.conflict = false
+		if m.Request.Status == db.StatusPending {
+			m.notice = fmt.Sprintf("Your %s was not submitted: you can no longer review this request.", decision)
+		}
+		if m.ready {
+			m.viewport.SetContent(m.renderContent())
+		}
+		return submitRefuse
+	}
+	if m.conflict {
+		m.conflict = false
+		m.notice += " Submit again to record your " + decision + " anyway."
+		return submitHold
+	}
+	m.notice = ""
+	return submitSend
+}
+
+// otherViewers drops the current session from a list of viewers.
+func otherViewers(viewers []db.RequestViewer, session *db.Session) []db.RequestViewer {
+	if session == nil {
+		return viewers
+	}
+	out := make([]db.RequestViewer, 0, len(viewers))
+	for _, v := range viewers {
+		if v.SessionID != session.ID {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
 // View renders the model.
 func (m *DetailModel) View() string {
 	if !m.ready {
@@ -277,10 +464,10 @@ func (m *DetailModel) View() string {
 
 	// Handle form modes
 	if m.Mode == DetailModeApprove && m.approveForm != nil {
-		return m.approveForm.View()
+		return m.renderFormBanner() + m.approveForm.View()
 	}
 	if m.Mode == DetailModeReject && m.rejectForm != nil {
-		return m.rejectForm.View()
+		return m.renderFormBanner() + m.rejectForm.View()
 	}
 
 	// Header
@@ -324,6 +511,12 @@ func (m *DetailModel) renderHeader() string {
 		statusBadge,
 		tierIndicator,
 	)
+	if m.ReadOnly {
+		header += "  " + components.RenderReadOnlyBadge()
+	}
+	if presence := m.presenceText(); presence != "" {
+		header += "  " + lipgloss.NewStyle().Foreground(th.Yellow).Render(presence)
+	}
 
 	headerStyle := lipgloss.NewStyle().
 		Background(th.Surface).
@@ -338,6 +531,10 @@ func (m *DetailModel) renderContent() string {
 	th := theme.Current
 	var sections []string
 
+	if m.notice != "" {
+		sections = append(sections, lipgloss.NewStyle().Foreground(th.Peach).Bold(true).Render(m.notice))
+	}
+
 	// Command box
 	cmdBox := components.NewCommandBox(m.Request.Command.Raw).
 		WithHint(true)
@@ -348,6 +545,9 @@ func (m *DetailModel) renderContent() string {
 		cmdBox = cmdBox.WithMaxWidth(m.Width - 4)
 	}
 	sections = append(sections, cmdBox.Render())
+	if warning := m.templateVarsText(); warning != "" {
+		sections = append(sections, lipgloss.NewStyle().Foreground(th.Peach).Bold(true).Render(warning))
+	}
 
 	// Requestor info
 	requestorInfo := m.renderRequestorInfo()
@@ -389,6 +589,60 @@ func (m *DetailModel) renderContent() string {
 	return strings.Join(sections, "\n"+divider+"\n\n")
 }
 
+// presenceText names the other reviewers who have the request open, for
+// example "Reviewer2 is viewing".
+func (m *DetailModel) presenceText() string {
+	var composing, viewing []string
+	for _, v := range m.Viewers {
+		if v.Composing {
+			composing = append(composing, v.Viewer)
+		} else {
+			viewing = append(viewing, v.Viewer)
+		}
+	}
+	var parts []string
+	if len(composing) > 0 {
+		parts = append(parts, joinNames(composing)+pluralVerb(len(composing), " is", " are")+" writing a review")
+	}
+	if len(viewing) > 0 {
+		parts = append(parts, joinNames(viewing)+pluralVerb(len(viewing), " is", " are")+" viewing")
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return icons.Current().Users + " " + strings.Join(parts, ", ")
+}
+
+// renderFormBanner renders presence and any notice above an open form, so
+// a reviewer writing a decision sees others at work and decisions landing.
+func (m *DetailModel) renderFormBanner() string {
+	th := theme.Current
+	var lines []string
+	if presence := m.presenceText(); presence != "" {
+		lines = append(lines, lipgloss.NewStyle().Foreground(th.Yellow).Render(presence))
+	}
+	if m.notice != "" {
+		lines = append(lines, lipgloss.NewStyle().Foreground(th.Peach).Bold(true).Render(m.notice))
+	}
+	if warning := m.templateVarsText(); warning != "" && m.Mode == DetailModeApprove {
+		lines = append(lines, lipgloss.NewStyle().Foreground(th.Peach).Bold(true).Render(warning))
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return lipgloss.NewStyle().Padding(0, 1).Width(m.Width).Render(strings.Join(lines, "\n")) + "\n"
+}
+
+// templateVarsText warns about the command's unexpanded template
+// variables. Approving from this view acknowledges them.
+func (m *DetailModel) templateVarsText() string {
+	vars := core.TemplateVars(m.Request.Command.Raw)
+	if len(vars) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Unexpanded template variables: %s. Approving acknowledges them as they are.", strings.Join(vars, ", "))
+}
+
 // renderRequestorInfo renders requestor information.
 func (m *DetailModel) renderRequestorInfo() string {
 	th := theme.Current
@@ -402,7 +656,7 @@ func (m *DetailModel) renderRequestorInfo() string {
 	metaStyle := lipgloss.NewStyle().Foreground(th.Subtext)
 
 	agentIcon := icons.Current().Agent
-	timeAgo := formatTimeAgo(m.Request.CreatedAt)
+	timeAgo := timefmt.Current().Show(m.Request.CreatedAt, m.now())
 
 	info := fmt.Sprintf("%s %s (%s)\n%s",
 		agentIcon,
@@ -413,7 +667,7 @@ func (m *DetailModel) renderRequestorInfo() string {
 
 	// Add expiry info if pending
 	if m.Request.Status == db.StatusPending && m.Request.ExpiresAt != nil {
-		expiresIn := time.Until(*m.Request.ExpiresAt)
+		expiresIn := m.Request.ExpiresAt.Sub(m.now())
 		if expiresIn > 0 {
 			info += metaStyle.Render(fmt.Sprintf(" (expires in %s)", formatDuration(expiresIn)))
 		} else {
@@ -421,6 +675,10 @@ func (m *DetailModel) renderRequestorInfo() string {
 		}
 	}
 
+	if len(m.Request.Labels) > 0 {
+		info += "\n" + metaStyle.Render("Labels: "+db.FormatLabels(m.Request.Labels, ", "))
+	}
+
 	return sectionTitle + "\n" + info
 }
 
@@ -504,6 +762,17 @@ func (m *DetailModel) renderAttachments() string {
 			Foreground(th.Peach).
 			Render(string(att.Type))
 
+		// Transcript snippets are short and already redacted: show them in full.
+		if att.Type == db.AttachmentTypeTranscript {
+			if text, err := att.Text(); err == nil {
+				lines = append(lines, fmt.Sprintf("%d. %s %s:", i+1, typeIcon, typeBadge))
+				for _, tl := range strings.Split(text, "\n") {
+					lines = append(lines, "   "+lipgloss.NewStyle().Foreground(th.Subtext).Render(tl))
+				}
+				continue
+			}
+		}
+
 		preview := att.Content
 		if len(preview) > 100 {
 			preview = preview[:100] + "..."
@@ -530,7 +799,13 @@ func (m *DetailModel) renderTimeline() string {
 		Render("Timeline")
 
 	tl := components.NewTimeline().WithCurrent(string(m.Request.Status))
+	if len(m.Events) > 0 {
+		tl.Events = components.TimelineFromEvents(m.Events)
+		return sectionTitle + "\n" + tl.Render()
+	}
 
+	// Requests that predate recorded events get a timeline rebuilt from
+	// their reviews and execution.
 	// Add created event
 	tl.AddEvent("created", m.Request.CreatedAt, m.Request.RequestorAgent, "Request submitted")
 
@@ -591,7 +866,7 @@ func (m *DetailModel) renderReviews() string {
 
 		reviewer := lipgloss.NewStyle().Foreground(th.Text).Bold(true).Render(rev.ReviewerAgent)
 		decision := lipgloss.NewStyle().Foreground(decisionColor).Render(strings.ToUpper(string(rev.Decision)))
-		timeStr := lipgloss.NewStyle().Foreground(th.Subtext).Render(formatTimeAgo(rev.CreatedAt))
+		timeStr := lipgloss.NewStyle().Foreground(th.Subtext).Render(timefmt.Current().Show(rev.CreatedAt, m.now()))
 
 		line := fmt.Sprintf("%s %s %s  %s", icon, reviewer, decision, timeStr)
 		if rev.Comments != "" {
@@ -640,6 +915,10 @@ func (m *DetailModel) renderFooter() string {
 
 // canApprove returns true if the current session can approve.
 func (m *DetailModel) canApprove() bool {
+	// Spectators never act
+	if m.ReadOnly {
+		return false
+	}
 	// Must be pending
 	if m.Request.Status != db.StatusPending {
 		return false
@@ -669,12 +948,16 @@ func (m *DetailModel) canReject() bool {
 
 // canExecute returns true if the request can be executed.
 func (m *DetailModel) canExecute() bool {
+	// Spectators never act
+	if m.ReadOnly {
+		return false
+	}
 	// Must be approved
 	if m.Request.Status != db.StatusApproved {
 		return false
 	}
 	// Check if approval expired
-	if m.Request.ApprovalExpiresAt != nil && time.Now().After(*m.Request.ApprovalExpiresAt) {
+	if m.Request.ApprovalExpiresAt != nil && m.now().After(*m.Request.ApprovalExpiresAt) {
 		return false
 	}
 	return true
@@ -690,7 +973,7 @@ func attachmentIcon(attType string) string {
 		return ic.File
 	case "git_diff":
 		return ic.Git
-	case "context":
+	case "context", "transcript":
 		return ic.Terminal
 	case "screenshot":
 		return ic.File
@@ -699,30 +982,18 @@ func attachmentIcon(attType string) string {
 	}
 }
 
-func formatTimeAgo(t time.Time) string {
-	d := time.Since(t)
-	switch {
-	case d < time.Minute:
-		return "just now"
-	case d < time.Hour:
-		mins := int(d.Minutes())
-		if mins == 1 {
-			return "1 minute ago"
-		}
-		return fmt.Sprintf("%d minutes ago", mins)
-	case d < 24*time.Hour:
-		hours := int(d.Hours())
-		if hours == 1 {
-			return "1 hour ago"
-		}
-		return fmt.Sprintf("%d hours ago", hours)
-	default:
-		days := int(d.Hours() / 24)
-		if days == 1 {
-			return "1 day ago"
-		}
-		return fmt.Sprintf("%d days ago", days)
+func joinNames(names []string) string {
+	if len(names) <= 1 {
+		return strings.Join(names, "")
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
+
+func pluralVerb(n int, one, many string) string {
+	if n == 1 {
+		return one
 	}
+	return many
 }
 
 func formatDuration(d time.Duration) string {
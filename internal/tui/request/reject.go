@@ -10,6 +10,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/i18n"
 	"github.com/Dicklesworthstone/slb/internal/tui/components"
 	"github.com/Dicklesworthstone/slb/internal/tui/theme"
 )
@@ -56,7 +57,7 @@ type RejectModel struct {
 func NewRejectModel(request *db.Request) *RejectModel {
 	// Create textarea for reason
 	ti := textarea.New()
-	ti.Placeholder = "Explain why you are rejecting this request..."
+	ti.Placeholder = i18n.T("tui.reject.placeholder")
 	ti.ShowLineNumbers = false
 	ti.SetHeight(4)
 	ti.Focus()
@@ -90,7 +91,7 @@ func (m *RejectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			reason := strings.TrimSpace(m.reasonInput.Value())
 			if reason == "" {
 				m.showError = true
-				m.errorMsg = "A reason is required when rejecting a request"
+				m.errorMsg = i18n.T("tui.reject.reason_missing")
 				return m, nil
 			}
 			m.Reason = reason
@@ -126,7 +127,7 @@ func (m *RejectModel) View() string {
 		Foreground(th.Red).
 		Bold(true).
 		Padding(1, 0)
-	b.WriteString(titleStyle.Render("Reject Request"))
+	b.WriteString(titleStyle.Render(i18n.T("tui.reject.title")))
 	b.WriteString("\n\n")
 
 	// Request summary
@@ -142,9 +143,9 @@ func (m *RejectModel) View() string {
 	tierBadge := components.RenderRiskIndicator(string(m.Request.RiskTier))
 
 	summary := lipgloss.JoinVertical(lipgloss.Left,
-		"Request: "+m.Request.ID,
-		"Command: "+cmdPreview,
-		"Tier: "+tierBadge,
+		i18n.T("tui.form.request", m.Request.ID),
+		i18n.T("tui.form.command", cmdPreview),
+		i18n.T("tui.form.tier", tierBadge),
 	)
 	b.WriteString(summaryStyle.Render(summary))
 	b.WriteString("\n\n")
@@ -155,7 +156,7 @@ func (m *RejectModel) View() string {
 		Bold(true).
 		Padding(0, 2)
 
-	warnMsg := "You are about to REJECT this request.\nThe command will NOT be executed."
+	warnMsg := i18n.T("tui.reject.confirm")
 	b.WriteString(warnStyle.Render(warnMsg))
 	b.WriteString("\n\n")
 
@@ -169,7 +170,7 @@ func (m *RejectModel) View() string {
 		Foreground(th.Red).
 		Bold(true)
 
-	b.WriteString(labelStyle.Render("Reason ") + requiredStyle.Render("(required)") + labelStyle.Render(":"))
+	b.WriteString(labelStyle.Render(i18n.T("tui.reject.reason")) + requiredStyle.Render(i18n.T("tui.reject.required")) + labelStyle.Render(":"))
 	b.WriteString("\n")
 
 	// Input border color changes on error
@@ -209,6 +209,24 @@ func TestDetailModelUpdateKeyApprove(t *testing.T) {
 	}
 }
 
+func TestDetailModelWarnsAboutTemplateVars(t *testing.T) {
+	req := testRequest()
+	req.Command.Raw = "rm -rf $TARGET"
+	session := &db.Session{ID: "session-2", AgentName: "Reviewer"}
+
+	m := NewDetailModel(req, nil).WithSession(session)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 50})
+	model := updated.(*DetailModel)
+	if !strings.Contains(model.renderContent(), "Unexpanded template variables: $TARGET") {
+		t.Error("detail view should list the unexpanded variables")
+	}
+
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	if view := updated.(*DetailModel).View(); !strings.Contains(view, "Approving acknowledges them") {
+		t.Errorf("approve form should repeat the warning, got:\n%s", view)
+	}
+}
+
 func TestDetailModelUpdateKeyApproveCannotApproveOwn(t *testing.T) {
 	req := testRequest()
 	session := &db.Session{ID: "session-1"} // Same as requestor
@@ -262,6 +280,67 @@ func TestDetailModelUpdateKeyExecute(t *testing.T) {
 	}
 }
 
+func TestDetailModelReadOnlyDisablesActions(t *testing.T) {
+	req := testRequest()
+	session := &db.Session{ID: "session-2"}
+
+	m := NewDetailModel(req, nil).WithSession(session).WithReadOnly(true)
+	m.ready = true
+
+	for _, r := range []rune{'a', 'r'} {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		if model := updated.(*DetailModel); model.Mode != DetailModeView {
+			t.Errorf("key %q should not open a form in read-only mode", r)
+		}
+	}
+
+	req.Status = db.StatusApproved
+	executeCalled := false
+	m.OnExecute = func(id string) tea.Cmd {
+		executeCalled = true
+		return nil
+	}
+	_, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if executeCalled {
+		t.Error("OnExecute should not be called in read-only mode")
+	}
+}
+
+func TestDetailModelReadOnlyBlocksSubmittedForm(t *testing.T) {
+	req := testRequest()
+	m := NewDetailModel(req, nil).WithSession(&db.Session{ID: "session-2"})
+	m.ready = true
+
+	// Open the form, then switch to read-only before submitting.
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	m = updated.(*DetailModel)
+	m.WithReadOnly(true)
+	m.approveForm.Submitted = true
+
+	approveCalled := false
+	m.OnApprove = func(id, comments string) tea.Cmd {
+		approveCalled = true
+		return nil
+	}
+	_, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if approveCalled {
+		t.Error("OnApprove should not be called in read-only mode")
+	}
+}
+
+func TestDetailModelReadOnlyHeader(t *testing.T) {
+	m := NewDetailModel(testRequest(), nil).WithReadOnly(true)
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	view := m.View()
+	if !strings.Contains(view, "READ-ONLY") {
+		t.Error("header should show READ-ONLY badge")
+	}
+	if strings.Contains(view, "pprove") {
+		t.Error("footer should not advertise approve in read-only mode")
+	}
+}
+
 func TestDetailModelUpdateKeyScroll(t *testing.T) {
 	m := NewDetailModel(testRequest(), nil)
 
@@ -366,6 +445,27 @@ func TestDetailModelViewWithReviews(t *testing.T) {
 	}
 }
 
+func TestDetailModelTimelineFromEvents(t *testing.T) {
+	req := testRequest()
+	now := time.Now()
+	m := NewDetailModel(req, nil).WithEvents([]*db.RequestEvent{
+		{RequestID: req.ID, Type: db.RequestEventCreated, Actor: "Requestor", CreatedAt: now.Add(-time.Hour)},
+		{RequestID: req.ID, Type: db.RequestEventEscalated, Actor: "slb", Details: "paged a human", CreatedAt: now},
+	})
+
+	timeline := m.renderTimeline()
+	if !strings.Contains(timeline, "ESCALATED") {
+		t.Errorf("timeline should show recorded events:\n%s", timeline)
+	}
+	if strings.Contains(timeline, "PENDING") {
+		t.Errorf("timeline should not synthesize states when events exist:\n%s", timeline)
+	}
+
+	if timeline := NewDetailModel(req, nil).renderTimeline(); !strings.Contains(timeline, "PENDING") {
+		t.Errorf("timeline without events should be rebuilt from the request:\n%s", timeline)
+	}
+}
+
 func TestDetailModelViewWithRejectionReview(t *testing.T) {
 	req := testRequest()
 	reviews := []db.Review{
@@ -464,6 +564,25 @@ func TestDetailModelViewWithLongAttachment(t *testing.T) {
 	}
 }
 
+func TestDetailModelRenderTranscriptAttachment(t *testing.T) {
+	encoded, err := db.CompressAttachmentContent("user: clean up\nassistant: removing ./build")
+	if err != nil {
+		t.Fatalf("CompressAttachmentContent: %v", err)
+	}
+	req := testRequest()
+	req.Attachments = []db.Attachment{{
+		Type:     db.AttachmentTypeTranscript,
+		Content:  encoded,
+		Metadata: map[string]any{"encoding": db.AttachmentEncodingGzip},
+	}}
+
+	m := NewDetailModel(req, nil)
+	out := m.renderAttachments()
+	if !strings.Contains(out, "user: clean up") || !strings.Contains(out, "assistant: removing ./build") {
+		t.Errorf("expected decoded transcript lines, got:\n%s", out)
+	}
+}
+
 func TestDetailModelViewWithExecution(t *testing.T) {
 	req := testRequest()
 	req.Status = db.StatusExecuted
@@ -608,31 +727,6 @@ func TestAttachmentIcon(t *testing.T) {
 	}
 }
 
-func TestFormatTimeAgo(t *testing.T) {
-	tests := []struct {
-		name     string
-		time     time.Time
-		expected string
-	}{
-		{"just now", time.Now(), "just now"},
-		{"1 minute", time.Now().Add(-time.Minute), "1 minute ago"},
-		{"5 minutes", time.Now().Add(-5 * time.Minute), "5 minutes ago"},
-		{"1 hour", time.Now().Add(-time.Hour), "1 hour ago"},
-		{"3 hours", time.Now().Add(-3 * time.Hour), "3 hours ago"},
-		{"1 day", time.Now().Add(-24 * time.Hour), "1 day ago"},
-		{"3 days", time.Now().Add(-72 * time.Hour), "3 days ago"},
-	}
-
-	for _, tc := range tests {
-		t.Run(tc.name, func(t *testing.T) {
-			got := formatTimeAgo(tc.time)
-			if got != tc.expected {
-				t.Errorf("formatTimeAgo: expected %q, got %q", tc.expected, got)
-			}
-		})
-	}
-}
-
 func TestFormatDuration(t *testing.T) {
 	tests := []struct {
 		duration time.Duration
@@ -1156,3 +1250,120 @@ func TestRenderFunctionsEdgeCases(t *testing.T) {
 		t.Error("Long dry run output should be truncated")
 	}
 }
+
+func TestDetailModelShowsPresence(t *testing.T) {
+	m := NewDetailModel(testRequest(), nil).WithSession(&db.Session{ID: "session-2"})
+	m.WithViewers([]db.RequestViewer{
+		{SessionID: "session-2", Viewer: "Me"},
+		{SessionID: "session-3", Viewer: "Reviewer2"},
+	})
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	view := m.View()
+	if !strings.Contains(view, "Reviewer2 is viewing") {
+		t.Errorf("expected presence in the header, got:\n%s", view)
+	}
+	if strings.Contains(view, "Me is") {
+		t.Error("the current session should not be listed as a viewer")
+	}
+
+	m.Update(RefreshMsg{Request: testRequest(), Viewers: []db.RequestViewer{
+		{SessionID: "session-3", Viewer: "Reviewer2", Composing: true},
+		{SessionID: "session-4", Viewer: "Reviewer3"},
+	}})
+	if got := m.presenceText(); !strings.Contains(got, "Reviewer2 is writing a review, Reviewer3 is viewing") {
+		t.Errorf("presenceText = %q", got)
+	}
+}
+
+func TestDetailModelRefreshUpdatesLive(t *testing.T) {
+	m := NewDetailModel(testRequest(), nil).WithSession(&db.Session{ID: "session-2"})
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	approved := testRequest()
+	approved.Status = db.StatusApproved
+	m.Update(RefreshMsg{Request: approved, Reviews: []db.Review{
+		{ID: "rev-1", ReviewerSessionID: "session-3", ReviewerAgent: "Reviewer2", Decision: db.DecisionApprove},
+	}})
+
+	if m.Request.Status != db.StatusApproved || len(m.Reviews) != 1 {
+		t.Fatalf("expected the refresh to apply, got status %s and %d reviews", m.Request.Status, len(m.Reviews))
+	}
+	if m.notice != "" {
+		t.Errorf("no notice is needed outside a form, got %q", m.notice)
+	}
+	if m.canApprove() {
+		t.Error("an approved request should no longer offer approval")
+	}
+
+	other := testRequest()
+	other.ID = "REQ-OTHER"
+	m.Update(RefreshMsg{Request: other})
+	if m.Request.ID != "REQ-001" {
+		t.Error("a refresh for another request should be ignored")
+	}
+}
+
+func TestDetailModelHoldsSubmitAfterConflictingDecision(t *testing.T) {
+	m := NewDetailModel(testRequest(), nil).WithSession(&db.Session{ID: "session-2"})
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+
+	rejects := 0
+	m.OnReject = func(id, reason string) tea.Cmd {
+		rejects++
+		return nil
+	}
+
+	// Another reviewer approves while the rejection is being written; the
+	// request still needs a second approval.
+	m.Update(RefreshMsg{Request: testRequest(), Reviews: []db.Review{
+		{ID: "rev-1", ReviewerSessionID: "session-3", ReviewerAgent: "Reviewer2", Decision: db.DecisionApprove},
+	}})
+	if !strings.Contains(m.View(), "Reviewer2 approved this request") {
+		t.Errorf("expected the form to call out the new decision, got:\n%s", m.View())
+	}
+
+	m.rejectForm.reasonInput.SetValue("not safe")
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if rejects != 0 || m.Mode != DetailModeReject {
+		t.Fatalf("the first submit should be held, rejects=%d mode=%v", rejects, m.Mode)
+	}
+	if !strings.Contains(m.View(), "Submit again") {
+		t.Error("expected the notice to ask for a second submit")
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if rejects != 1 || m.Mode != DetailModeView {
+		t.Fatalf("the second submit should go through, rejects=%d mode=%v", rejects, m.Mode)
+	}
+}
+
+func TestDetailModelRefusesSubmitOnceResolved(t *testing.T) {
+	m := NewDetailModel(testRequest(), nil).WithSession(&db.Session{ID: "session-2"})
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+
+	approvals := 0
+	m.OnApprove = func(id, comments string) tea.Cmd {
+		approvals++
+		return nil
+	}
+
+	rejected := testRequest()
+	rejected.Status = db.StatusRejected
+	m.Update(RefreshMsg{Request: rejected, Reviews: []db.Review{
+		{ID: "rev-1", ReviewerSessionID: "session-3", ReviewerAgent: "Reviewer2", Decision: db.DecisionReject},
+	}})
+
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if approvals != 0 {
+		t.Fatal("an approval for a rejected request must not be submitted")
+	}
+	if m.Mode != DetailModeView {
+		t.Error("expected the form to close")
+	}
+	if !strings.Contains(m.View(), "was not submitted: the request is REJECTED") {
+		t.Errorf("expected a refusal notice, got:\n%s", m.View())
+	}
+}
@@ -0,0 +1,57 @@
+package request
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+	"github.com/Dicklesworthstone/slb/internal/tui/tuitest"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestDetailSnapshot(t *testing.T) {
+	tuitest.Pin(t)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	expires := now.Add(26 * time.Minute)
+
+	req := &db.Request{
+		ID:                 "req-0001-critical",
+		ProjectPath:        "/work/project",
+		Command:            db.CommandSpec{Raw: "kubectl delete namespace production", Cwd: "/work/project"},
+		RiskTier:           db.RiskTierCritical,
+		RequestorSessionID: "sess-blue",
+		RequestorAgent:     "BlueLake",
+		RequestorModel:     "opus",
+		Justification: db.Justification{
+			Reason:         "Tear down the broken rollout",
+			ExpectedEffect: "Removes every workload in the namespace",
+			Goal:           "Redeploy from a clean slate",
+			SafetyArgument: "Manifests are in git; the namespace is recreated by CI",
+		},
+		Status:       db.StatusPending,
+		MinApprovals: 2,
+		CreatedAt:    now.Add(-4 * time.Minute),
+		ExpiresAt:    &expires,
+	}
+	reviews := []db.Review{
+		{
+			ID:                "rev-1",
+			RequestID:         req.ID,
+			ReviewerSessionID: "sess-green",
+			ReviewerAgent:     "GreenCastle",
+			ReviewerModel:     "gpt-5",
+			Decision:          db.DecisionApprove,
+			Comments:          "Confirmed the rollout is unrecoverable",
+			CreatedAt:         now.Add(-1 * time.Minute),
+		},
+	}
+
+	for _, size := range tuitest.Sizes {
+		t.Run(size.String(), func(t *testing.T) {
+			m := NewDetailModel(req, reviews).WithClock(testutil.NewFakeClock(now))
+			m.Update(tea.WindowSizeMsg{Width: size.Width, Height: size.Height})
+			tuitest.RequireSnapshot(t, "detail_"+size.String(), m.View())
+		})
+	}
+}
@@ -146,3 +146,41 @@ func Latte() *Theme {
 		Overlay2: lipgloss.Color("#7c7f93"),
 	}
 }
+
+// HighContrast returns an accessible theme that uses only the 16 basic ANSI
+// colors, so terminals and screen magnifiers render it with their own
+// high-contrast palette.
+func HighContrast() *Theme {
+	return &Theme{
+		Name:   "High Contrast",
+		IsDark: true,
+
+		// Primary colors
+		Mauve:    lipgloss.Color("13"),
+		Blue:     lipgloss.Color("12"),
+		Green:    lipgloss.Color("10"),
+		Yellow:   lipgloss.Color("11"),
+		Red:      lipgloss.Color("9"),
+		Peach:    lipgloss.Color("11"),
+		Teal:     lipgloss.Color("14"),
+		Pink:     lipgloss.Color("13"),
+		Flamingo: lipgloss.Color("13"),
+
+		// Text colors
+		Text:    lipgloss.Color("15"),
+		Subtext: lipgloss.Color("7"),
+
+		// Surface colors
+		Surface:  lipgloss.Color("0"),
+		Surface0: lipgloss.Color("0"),
+		Surface1: lipgloss.Color("8"),
+		Base:     lipgloss.Color("0"),
+		Mantle:   lipgloss.Color("0"),
+		Crust:    lipgloss.Color("0"),
+
+		// Overlay colors
+		Overlay0: lipgloss.Color("7"),
+		Overlay1: lipgloss.Color("7"),
+		Overlay2: lipgloss.Color("15"),
+	}
+}
@@ -2,6 +2,7 @@
 package theme
 
 import (
+	"github.com/Dicklesworthstone/slb/internal/utils"
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -48,6 +49,10 @@ const (
 	FlavorMacchiato FlavorName = "macchiato"
 	FlavorFrappe    FlavorName = "frappe"
 	FlavorLatte     FlavorName = "latte"
+
+	// FlavorHighContrast is the accessible theme built from the basic ANSI
+	// palette; it is used automatically in no-color mode.
+	FlavorHighContrast FlavorName = "high-contrast"
 )
 
 // Current holds the active theme.
@@ -64,6 +69,8 @@ func SetTheme(flavor FlavorName) {
 		Current = Frappe()
 	case FlavorLatte:
 		Current = Latte()
+	case FlavorHighContrast:
+		Current = HighContrast()
 	default:
 		Current = Mocha()
 	}
@@ -109,8 +116,12 @@ func (t *Theme) StatusColor(status string) lipgloss.Color {
 	}
 }
 
-// TierEmoji returns the emoji for a risk tier.
+// TierEmoji returns the emoji for a risk tier, or an ASCII label in
+// accessible output mode.
 func TierEmoji(tier string) string {
+	if utils.PlainOutput() {
+		return tierLabel(tier)
+	}
 	switch tier {
 	case "critical", "CRITICAL":
 		return "🔴"
@@ -125,8 +136,12 @@ func TierEmoji(tier string) string {
 	}
 }
 
-// StatusIcon returns the icon for a request status.
+// StatusIcon returns the icon for a request status, or an ASCII label in
+// accessible output mode.
 func StatusIcon(status string) string {
+	if utils.PlainOutput() {
+		return statusLabel(status)
+	}
 	switch status {
 	case "pending", "PENDING":
 		return "⏳"
@@ -148,3 +163,43 @@ func StatusIcon(status string) string {
 		return "?"
 	}
 }
+
+// tierLabel returns the ASCII label for a risk tier.
+func tierLabel(tier string) string {
+	switch tier {
+	case "critical", "CRITICAL":
+		return "[CRIT]"
+	case "dangerous", "DANGEROUS":
+		return "[DANG]"
+	case "caution", "CAUTION":
+		return "[CAUT]"
+	case "safe", "SAFE":
+		return "[SAFE]"
+	default:
+		return "[----]"
+	}
+}
+
+// statusLabel returns the ASCII label for a request status.
+func statusLabel(status string) string {
+	switch status {
+	case "pending", "PENDING":
+		return "[PEND]"
+	case "approved", "APPROVED":
+		return "[OK]"
+	case "rejected", "REJECTED":
+		return "[REJ]"
+	case "executed", "EXECUTED":
+		return "[DONE]"
+	case "failed", "FAILED":
+		return "[FAIL]"
+	case "timeout", "TIMEOUT":
+		return "[TIME]"
+	case "cancelled", "CANCELLED":
+		return "[CANC]"
+	case "escalated", "ESCALATED":
+		return "[ESC]"
+	default:
+		return "[?]"
+	}
+}
@@ -3,6 +3,7 @@ package theme
 import (
 	"testing"
 
+	"github.com/Dicklesworthstone/slb/internal/utils"
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -326,3 +327,34 @@ func TestThemeColorConsistency(t *testing.T) {
 		})
 	}
 }
+
+func TestPlainOutputLabels(t *testing.T) {
+	prev := utils.PlainOutput()
+	utils.SetPlainOutput(true)
+	t.Cleanup(func() { utils.SetPlainOutput(prev) })
+
+	if got := TierEmoji("critical"); got != "[CRIT]" {
+		t.Errorf("TierEmoji(critical) = %q, want [CRIT]", got)
+	}
+	if got := TierEmoji("bogus"); got != "[----]" {
+		t.Errorf("TierEmoji(bogus) = %q, want [----]", got)
+	}
+	if got := StatusIcon("pending"); got != "[PEND]" {
+		t.Errorf("StatusIcon(pending) = %q, want [PEND]", got)
+	}
+	if got := StatusIcon("executed"); got != "[DONE]" {
+		t.Errorf("StatusIcon(executed) = %q, want [DONE]", got)
+	}
+}
+
+func TestSetThemeHighContrast(t *testing.T) {
+	t.Cleanup(func() { SetTheme(FlavorMocha) })
+
+	SetTheme(FlavorHighContrast)
+	if Current.Name != "High Contrast" {
+		t.Errorf("expected high contrast theme, got %q", Current.Name)
+	}
+	if Current.Red != lipgloss.Color("9") {
+		t.Errorf("expected ANSI red, got %q", Current.Red)
+	}
+}
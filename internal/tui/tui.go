@@ -3,13 +3,16 @@
 package tui
 
 import (
+	"io"
 	"os"
 	"path/filepath"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/Dicklesworthstone/slb/internal/core"
 	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/timefmt"
 	"github.com/Dicklesworthstone/slb/internal/tui/dashboard"
 	"github.com/Dicklesworthstone/slb/internal/tui/history"
 	"github.com/Dicklesworthstone/slb/internal/tui/patterns"
@@ -17,6 +20,15 @@ import (
 	"github.com/Dicklesworthstone/slb/internal/tui/theme"
 )
 
+const (
+	// detailRefreshInterval is how often an open request is reloaded and
+	// its viewer's presence renewed.
+	detailRefreshInterval = 2 * time.Second
+	// presenceWindow is how long a viewer counts as present after their
+	// last check-in, covering TUIs that exit without clearing it.
+	presenceWindow = 10 * time.Second
+)
+
 // View represents the current view in the TUI.
 type View int
 
@@ -35,6 +47,16 @@ type Options struct {
 	RefreshInterval int
 	SessionID       string
 	SessionKey      string
+	// ReadOnly runs the TUI as a spectator: approve/reject actions are
+	// disabled in every view, regardless of session credentials.
+	ReadOnly bool
+	// ReviewConfig is passed to the review service; its FreshAuthTiers
+	// (sudo mode) decide which approvals call Authenticate first. Left
+	// zero, core.DefaultReviewConfig() is used.
+	ReviewConfig core.ReviewConfig
+	// Authenticate prompts for fresh authentication with the terminal
+	// released from the TUI.
+	Authenticate func() (*core.FreshAuth, error)
 }
 
 // DefaultOptions returns the default TUI options.
@@ -78,16 +100,23 @@ func NewWithOptions(opts Options) Model {
 	if opts.Theme != "" {
 		theme.SetTheme(theme.FlavorName(opts.Theme))
 	}
+	if opts.ReviewConfig.ConflictResolution == "" {
+		opts.ReviewConfig = core.DefaultReviewConfig()
+	}
 
 	// Create dashboard model
 	dash := dashboard.New(opts.ProjectPath)
+	dash.ReadOnly = opts.ReadOnly
+
+	pats := patterns.New(opts.ProjectPath)
+	pats.ReadOnly = opts.ReadOnly
 
 	return Model{
 		options:   opts,
 		view:      ViewDashboard,
 		dashboard: &dash,
 		history:   history.New(opts.ProjectPath),
-		patterns:  patterns.New(opts.ProjectPath),
+		patterns:  pats,
 	}
 }
 
@@ -111,6 +140,20 @@ func (m Model) Init() tea.Cmd {
 	return nil
 }
 
+// freshAuthMsg carries a sudo mode authentication back to the event loop
+// so the approval it unlocks can be submitted.
+type freshAuthMsg struct {
+	requestID string
+	comments  string
+	version   int
+	auth      *core.FreshAuth
+}
+
+// detailTickMsg reloads the request open in the detail view.
+type detailTickMsg struct {
+	requestID string
+}
+
 // navigateMsg is sent when navigating to a different view.
 type navigateMsg struct {
 	view      View
@@ -129,7 +172,33 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case navigateMsg:
 		return m.handleNavigation(msg)
 
+	case freshAuthMsg:
+		return m, m.submitApproval(msg.requestID, msg.comments, msg.version, msg.auth)
+
+	case detailTickMsg:
+		if m.view != ViewRequestDetail || m.detail == nil || m.detail.Request == nil ||
+			m.detail.Request.ID != msg.requestID {
+			return m, nil
+		}
+		return m, tea.Batch(m.refreshDetail(msg.requestID), detailTickCmd(msg.requestID))
+
+	case dashboard.OpenRequestMsg:
+		return m.handleNavigation(navigateMsg{view: ViewRequestDetail, requestID: msg.RequestID})
+
+	case history.OpenRequestMsg:
+		return m.handleNavigation(navigateMsg{view: ViewRequestDetail, requestID: msg.RequestID})
+
 	case tea.KeyMsg:
+		// T switches every view between relative and absolute timestamps,
+		// unless a text field has the keystroke.
+		if msg.String() == "T" && !m.typing() {
+			timefmt.Set(timefmt.Current().Toggled())
+			if m.detail != nil {
+				m.detail.Rerender()
+			}
+			return m, nil
+		}
+
 		// Handle global navigation keys based on current view
 		if m.view == ViewDashboard {
 			switch msg.String() {
@@ -179,6 +248,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 }
 
+// typing reports whether the current view has a text field taking keys.
+func (m Model) typing() bool {
+	switch m.view {
+	case ViewHistory:
+		return m.history.Searching()
+	case ViewRequestDetail:
+		return m.detail != nil && m.detail.Mode != request.DetailModeView
+	}
+	return false
+}
+
 // forwardUpdate forwards messages to the current view.
 func (m Model) forwardUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
@@ -225,11 +305,15 @@ func (m Model) forwardUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // handleNavigation handles view navigation.
 func (m Model) handleNavigation(nav navigateMsg) (tea.Model, tea.Cmd) {
+	if m.view == ViewRequestDetail {
+		m.clearPresence()
+	}
 	m.view = nav.view
 
 	switch nav.view {
 	case ViewDashboard:
 		dash := dashboard.New(m.options.ProjectPath)
+		dash.ReadOnly = m.options.ReadOnly
 		m.dashboard = &dash
 		m.setupDashboardCallbacks()
 		return m, m.dashboard.Init()
@@ -242,7 +326,7 @@ func (m Model) handleNavigation(nav navigateMsg) (tea.Model, tea.Cmd) {
 			if detail != nil {
 				m.detail = detail
 				m.setupDetailCallbacks()
-				return m, m.detail.Init()
+				return m, tea.Batch(m.detail.Init(), detailTickCmd(nav.requestID))
 			}
 		}
 		// Fall back to dashboard if request not found
@@ -256,6 +340,7 @@ func (m Model) handleNavigation(nav navigateMsg) (tea.Model, tea.Cmd) {
 
 	case ViewPatterns:
 		m.patterns = patterns.New(m.options.ProjectPath)
+		m.patterns.ReadOnly = m.options.ReadOnly
 		m.setupPatternsCallbacks()
 		return m, m.patterns.Init()
 	}
@@ -286,6 +371,11 @@ func (m *Model) setupDetailCallbacks() {
 			return navigateMsg{view: ViewDashboard}
 		}
 	}
+	if m.options.ReadOnly {
+		m.detail.OnApprove = nil
+		m.detail.OnReject = nil
+		return
+	}
 	m.detail.OnApprove = func(requestID string, comments string) tea.Cmd {
 		return m.approveRequest(requestID, comments)
 	}
@@ -336,6 +426,12 @@ func (m *Model) loadRequestDetail(requestID string) *request.DetailModel {
 	if err != nil {
 		return nil
 	}
+	if currentSession != nil && !m.options.ReadOnly && req.Status == db.StatusPending &&
+		currentSession.ID != req.RequestorSessionID {
+		m.recordViewed(requestID, currentSession)
+	}
+	// Labels are display-only here; older databases may lack the table.
+	_ = dbConn.LoadRequestLabels([]*db.Request{req})
 
 	reviewPtrs, _ := dbConn.ListReviewsForRequest(requestID)
 
@@ -348,63 +444,207 @@ func (m *Model) loadRequestDetail(requestID string) *request.DetailModel {
 	}
 
 	detail := request.NewDetailModel(req, reviews)
-	if currentSession != nil {
+	// Events are display-only here; older databases may lack the table.
+	if events, err := dbConn.ListRequestEvents(requestID); err == nil {
+		detail.WithEvents(events)
+	}
+	if m.options.ReadOnly {
+		detail.WithReadOnly(true)
+	} else if currentSession != nil {
 		detail.WithSession(currentSession)
 	}
+	// Presence is display-only too.
+	if viewers, err := dbConn.RequestViewers(requestID, dbConn.Now().Add(-presenceWindow)); err == nil {
+		detail.WithViewers(viewers)
+	}
 	return detail
 }
 
-// approveRequest creates a command to approve a request.
-func (m *Model) approveRequest(requestID string, comments string) tea.Cmd {
-	return func() tea.Msg {
-		if m.options.SessionID == "" || m.options.SessionKey == "" {
-			return nil // Cannot approve without session
-		}
+func detailTickCmd(requestID string) tea.Cmd {
+	return tea.Tick(detailRefreshInterval, func(time.Time) tea.Msg {
+		return detailTickMsg{requestID: requestID}
+	})
+}
 
-		dbPath := filepath.Join(m.options.ProjectPath, ".slb", "state.db")
+// refreshDetail reloads the open request so decisions made elsewhere show
+// up, and renews the reviewer's presence on it. Spectators are never shown
+// as present.
+func (m *Model) refreshDetail(requestID string) tea.Cmd {
+	var present *db.Session
+	if !m.options.ReadOnly && m.detail != nil {
+		present = m.detail.Session
+	}
+	composing := m.detail != nil && m.detail.Mode != request.DetailModeView
+	dbPath := filepath.Join(m.options.ProjectPath, ".slb", "state.db")
+
+	return func() tea.Msg {
 		dbConn, err := db.OpenWithOptions(dbPath, db.OpenOptions{
 			CreateIfNotExists: false,
-			InitSchema:        false, // Schema should exist
-			ReadOnly:          false,
+			InitSchema:        false,
+			ReadOnly:          present == nil,
 		})
 		if err != nil {
 			return nil
 		}
 		defer dbConn.Close()
 
-		// Get session to populate reviewer info
-		session, err := dbConn.GetSession(m.options.SessionID)
+		req, err := dbConn.GetRequest(requestID)
 		if err != nil {
 			return nil
 		}
+		if present != nil && req.Status == db.StatusPending && present.ID != req.RequestorSessionID {
+			_ = dbConn.TouchRequestPresence(requestID, present.ID, present.AgentName, composing)
+		}
+		_ = dbConn.LoadRequestLabels([]*db.Request{req})
 
-		now := time.Now().UTC()
-		review := &db.Review{
-			RequestID:          requestID,
-			ReviewerSessionID:  session.ID,
-			ReviewerAgent:      session.AgentName,
-			ReviewerModel:      session.Model,
-			Decision:           db.DecisionApprove,
-			Comments:           comments,
-			SignatureTimestamp: now,
+		msg := request.RefreshMsg{Request: req}
+		reviewPtrs, _ := dbConn.ListReviewsForRequest(requestID)
+		for _, r := range reviewPtrs {
+			if r != nil {
+				msg.Reviews = append(msg.Reviews, *r)
+			}
 		}
+		msg.Events, _ = dbConn.ListRequestEvents(requestID)
+		msg.Viewers, _ = dbConn.RequestViewers(requestID, dbConn.Now().Add(-presenceWindow))
+		return msg
+	}
+}
+
+// clearPresence removes the reviewer's presence from the request they are
+// leaving. It is best effort, like recordViewed.
+func (m *Model) clearPresence() {
+	if m.options.ReadOnly || m.detail == nil || m.detail.Session == nil {
+		return
+	}
+	dbPath := filepath.Join(m.options.ProjectPath, ".slb", "state.db")
+	dbConn, err := db.OpenWithOptions(dbPath, db.OpenOptions{
+		CreateIfNotExists: false,
+		InitSchema:        false,
+	})
+	if err != nil {
+		return
+	}
+	defer dbConn.Close()
+	_ = dbConn.ClearRequestPresence(m.detail.Session.ID)
+}
 
-		// Compute signature
-		review.Signature = db.ComputeReviewSignature(m.options.SessionKey, requestID, db.DecisionApprove, now)
+// recordViewed leaves a viewed receipt for the reviewer opening a request
+// and marks them present on it. It is best effort: the detail view opens
+// the database read-only and a missed receipt never blocks it.
+func (m *Model) recordViewed(requestID string, viewer *db.Session) {
+	dbPath := filepath.Join(m.options.ProjectPath, ".slb", "state.db")
+	dbConn, err := db.OpenWithOptions(dbPath, db.OpenOptions{
+		CreateIfNotExists: false,
+		InitSchema:        false,
+	})
+	if err != nil {
+		return
+	}
+	defer dbConn.Close()
+	_, _ = dbConn.RecordRequestViewed(requestID, viewer.AgentName)
+	_ = dbConn.TouchRequestPresence(requestID, viewer.ID, viewer.AgentName, false)
+}
 
-		if err := dbConn.CreateReviewWithValidation(review, m.options.SessionKey); err != nil {
-			// In a real app we'd send an error msg, but for now just log/ignore or return to dash
-			// Ideally we return an error message tea.Msg
+// detailVersion returns the version of the request shown in the detail view,
+// so a decision made on a stale screen is refused rather than applied to a
+// request that changed underneath it. It returns 0 when the version is
+// unknown.
+func (m *Model) detailVersion(requestID string) int {
+	if m.detail == nil || m.detail.Request == nil || m.detail.Request.ID != requestID {
+		return 0
+	}
+	return m.detail.Request.Version
+}
+
+// detailTemplateVars returns the unexpanded template variables of the
+// request shown in the detail view. The view lists them above the approve
+// form, so approving there acknowledges them.
+func (m *Model) detailTemplateVars(requestID string) []string {
+	if m.detail == nil || m.detail.Request == nil || m.detail.Request.ID != requestID {
+		return nil
+	}
+	return core.TemplateVars(m.detail.Request.Command.Raw)
+}
+
+// approveRequest creates a command to approve a request. When sudo mode
+// covers the request's tier, the TUI first hands the terminal to
+// Authenticate and approves only once that succeeds.
+func (m *Model) approveRequest(requestID string, comments string) tea.Cmd {
+	version := m.detailVersion(requestID)
+	if m.options.Authenticate != nil && m.detail != nil && m.detail.Request != nil &&
+		m.detail.Request.ID == requestID && m.options.ReviewConfig.RequiresFreshAuth(m.detail.Request.RiskTier) {
+		prompt := &freshAuthPrompt{authenticate: m.options.Authenticate}
+		return tea.Exec(prompt, func(err error) tea.Msg {
+			if err != nil {
+				return nil
+			}
+			return freshAuthMsg{requestID: requestID, comments: comments, version: version, auth: prompt.auth}
+		})
+	}
+	return m.submitApproval(requestID, comments, version, nil)
+}
+
+// submitApproval creates a command that records an approval.
+func (m *Model) submitApproval(requestID, comments string, version int, auth *core.FreshAuth) tea.Cmd {
+	ackVars := m.detailTemplateVars(requestID)
+	return func() tea.Msg {
+		if m.options.ReadOnly || m.options.SessionID == "" || m.options.SessionKey == "" {
+			return nil // Cannot approve without session (or as a spectator)
 		}
 
+		dbPath := filepath.Join(m.options.ProjectPath, ".slb", "state.db")
+		dbConn, err := db.OpenWithOptions(dbPath, db.OpenOptions{
+			CreateIfNotExists: false,
+			InitSchema:        false, // Schema should exist
+			ReadOnly:          false,
+		})
+		if err != nil {
+			return nil
+		}
+		defer dbConn.Close()
+
+		// The review service applies quorum and conflict rules and moves the
+		// request through the state machine, as `slb approve` does.
+		_, _ = core.NewReviewService(dbConn, m.options.ReviewConfig).SubmitReview(core.ReviewOptions{
+			SessionID:        m.options.SessionID,
+			SessionKey:       m.options.SessionKey,
+			RequestID:        requestID,
+			Decision:         db.DecisionApprove,
+			Comments:         comments,
+			ExpectedVersion:  version,
+			FreshAuth:        auth,
+			AcknowledgedVars: ackVars,
+		})
+
 		return navigateMsg{view: ViewDashboard}
 	}
 }
 
+// freshAuthPrompt runs Authenticate as a tea.ExecCommand, so the prompt
+// gets the terminal while the TUI is suspended.
+type freshAuthPrompt struct {
+	authenticate func() (*core.FreshAuth, error)
+	auth         *core.FreshAuth
+}
+
+func (p *freshAuthPrompt) Run() error {
+	auth, err := p.authenticate()
+	if err != nil {
+		return err
+	}
+	p.auth = auth
+	return nil
+}
+
+func (p *freshAuthPrompt) SetStdin(io.Reader)  {}
+func (p *freshAuthPrompt) SetStdout(io.Writer) {}
+func (p *freshAuthPrompt) SetStderr(io.Writer) {}
+
 // rejectRequest creates a command to reject a request.
 func (m *Model) rejectRequest(requestID string, reason string) tea.Cmd {
+	version := m.detailVersion(requestID)
 	return func() tea.Msg {
-		if m.options.SessionID == "" || m.options.SessionKey == "" {
+		if m.options.ReadOnly || m.options.SessionID == "" || m.options.SessionKey == "" {
 			return nil
 		}
 
@@ -419,25 +659,14 @@ func (m *Model) rejectRequest(requestID string, reason string) tea.Cmd {
 		}
 		defer dbConn.Close()
 
-		session, err := dbConn.GetSession(m.options.SessionID)
-		if err != nil {
-			return nil
-		}
-
-		now := time.Now().UTC()
-		review := &db.Review{
-			RequestID:          requestID,
-			ReviewerSessionID:  session.ID,
-			ReviewerAgent:      session.AgentName,
-			ReviewerModel:      session.Model,
-			Decision:           db.DecisionReject,
-			Comments:           reason,
-			SignatureTimestamp: now,
-		}
-
-		review.Signature = db.ComputeReviewSignature(m.options.SessionKey, requestID, db.DecisionReject, now)
-
-		_ = dbConn.CreateReviewWithValidation(review, m.options.SessionKey)
+		_, _ = core.NewReviewService(dbConn, m.options.ReviewConfig).SubmitReview(core.ReviewOptions{
+			SessionID:       m.options.SessionID,
+			SessionKey:      m.options.SessionKey,
+			RequestID:       requestID,
+			Decision:        db.DecisionReject,
+			Comments:        reason,
+			ExpectedVersion: version,
+		})
 
 		return navigateMsg{view: ViewDashboard}
 	}
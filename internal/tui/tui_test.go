@@ -1,12 +1,18 @@
 package tui
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/Dicklesworthstone/slb/internal/core"
 	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+	"github.com/Dicklesworthstone/slb/internal/tui/dashboard"
+	"github.com/Dicklesworthstone/slb/internal/tui/history"
 	"github.com/Dicklesworthstone/slb/internal/tui/request"
 )
 
@@ -36,6 +42,57 @@ func TestNewWithOptions(t *testing.T) {
 	}
 }
 
+func TestNewWithOptionsReadOnly(t *testing.T) {
+	m := NewWithOptions(Options{ProjectPath: "/tmp/test", ReadOnly: true})
+	if !m.dashboard.ReadOnly || !m.patterns.ReadOnly {
+		t.Error("expected dashboard and patterns views to be read-only")
+	}
+
+	updated, _ := m.handleNavigation(navigateMsg{view: ViewPatterns})
+	if !updated.(Model).patterns.ReadOnly {
+		t.Error("expected patterns view to stay read-only after navigation")
+	}
+}
+
+func TestReadOnlyDetailCallbacks(t *testing.T) {
+	m := NewWithOptions(Options{
+		ProjectPath: "/tmp/test",
+		SessionID:   "sess",
+		SessionKey:  "key",
+		ReadOnly:    true,
+	})
+	m.detail = request.NewDetailModel(&db.Request{ID: "req-1", Status: db.StatusPending}, nil)
+	m.setupDetailCallbacks()
+
+	if m.detail.OnApprove != nil || m.detail.OnReject != nil {
+		t.Error("approve/reject callbacks should not be wired in read-only mode")
+	}
+	if m.detail.OnBack == nil {
+		t.Error("back callback should still be wired in read-only mode")
+	}
+	if msg := m.approveRequest("req-1", "")(); msg != nil {
+		t.Errorf("approveRequest should be a no-op in read-only mode, got %#v", msg)
+	}
+	if msg := m.rejectRequest("req-1", "")(); msg != nil {
+		t.Errorf("rejectRequest should be a no-op in read-only mode, got %#v", msg)
+	}
+}
+
+func TestOpenRequestMsgNavigatesToDetail(t *testing.T) {
+	for _, msg := range []tea.Msg{
+		dashboard.OpenRequestMsg{RequestID: "missing"},
+		history.OpenRequestMsg{RequestID: "missing"},
+	} {
+		m := NewWithOptions(Options{ProjectPath: t.TempDir()})
+		m.view = ViewHistory
+		updated, _ := m.Update(msg)
+		// The request does not exist, so navigation falls back to the dashboard.
+		if um := updated.(Model); um.view != ViewDashboard || um.selectedRequestID != "missing" {
+			t.Errorf("%T: expected detail navigation attempt, got view %d selected %q", msg, um.view, um.selectedRequestID)
+		}
+	}
+}
+
 func TestModelInit(t *testing.T) {
 	m := New()
 	cmd := m.Init()
@@ -609,3 +666,143 @@ func TestUpdateKeyPressOnDashboardNonNavigation(t *testing.T) {
 		t.Errorf("non-navigation key should keep dashboard view, got %d", um.view)
 	}
 }
+
+func TestApproveRequest_SudoModePromptsFirst(t *testing.T) {
+	calls := 0
+	m := NewWithOptions(Options{
+		ProjectPath:  t.TempDir(),
+		ReviewConfig: core.ReviewConfig{ConflictResolution: core.ConflictAnyRejectionBlocks, FreshAuthTiers: []db.RiskTier{db.RiskTierCritical}},
+		Authenticate: func() (*core.FreshAuth, error) {
+			calls++
+			return &core.FreshAuth{Method: "passphrase", At: time.Now()}, nil
+		},
+	})
+	req := &db.Request{ID: "req-1", RiskTier: db.RiskTierCritical, Status: db.StatusPending, Version: 3, CreatedAt: time.Now()}
+	m.detail = request.NewDetailModel(req, nil)
+
+	if cmd := m.approveRequest("req-1", "ok"); cmd == nil {
+		t.Fatal("expected a command")
+	}
+	if calls != 0 {
+		t.Error("authentication should wait for the terminal to be released")
+	}
+
+	prompt := &freshAuthPrompt{authenticate: m.options.Authenticate}
+	if err := prompt.Run(); err != nil || prompt.auth == nil || calls != 1 {
+		t.Fatalf("Run = %v, auth %v, calls %d", err, prompt.auth, calls)
+	}
+	failing := &freshAuthPrompt{authenticate: func() (*core.FreshAuth, error) { return nil, core.ErrFreshAuthRequired }}
+	if err := failing.Run(); err == nil || failing.auth != nil {
+		t.Errorf("failing Run = %v, auth %v", err, failing.auth)
+	}
+
+	// The authentication comes back as a message that submits the approval.
+	_, cmd := m.Update(freshAuthMsg{requestID: "req-1", comments: "ok", version: 3, auth: prompt.auth})
+	if cmd == nil {
+		t.Error("expected freshAuthMsg to submit the approval")
+	}
+}
+
+func TestLoadRequestDetail_RecordsViewedReceipt(t *testing.T) {
+	project := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(project, ".slb"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	database := testutil.NewTestDBAtPath(t, filepath.Join(project, ".slb", "state.db"))
+	requestor := testutil.MakeSession(t, database, testutil.WithProject(project), testutil.WithAgent("Requestor"))
+	reviewer := testutil.MakeSession(t, database, testutil.WithProject(project), testutil.WithAgent("Reviewer"))
+	req := testutil.MakeRequest(t, database, requestor)
+
+	viewers := func() []string {
+		t.Helper()
+		seen, err := database.UndecidedViewers([]string{req.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return seen[req.ID]
+	}
+
+	spectator := NewWithOptions(Options{ProjectPath: project, SessionID: reviewer.ID, ReadOnly: true})
+	if spectator.loadRequestDetail(req.ID) == nil {
+		t.Fatal("expected a detail model")
+	}
+	if got := viewers(); len(got) != 0 {
+		t.Fatalf("spectators should not leave receipts, got %v", got)
+	}
+
+	m := NewWithOptions(Options{ProjectPath: project, SessionID: reviewer.ID})
+	detail := m.loadRequestDetail(req.ID)
+	if detail == nil {
+		t.Fatal("expected a detail model")
+	}
+	if got := viewers(); len(got) != 1 || got[0] != "Reviewer" {
+		t.Fatalf("viewers = %v", got)
+	}
+	if len(detail.Events) == 0 || detail.Events[0].Type != db.RequestEventCreated {
+		t.Errorf("expected recorded events on the detail model, got %+v", detail.Events)
+	}
+}
+
+func TestRequestDetail_PresenceAndLiveRefresh(t *testing.T) {
+	project := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(project, ".slb"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	database := testutil.NewTestDBAtPath(t, filepath.Join(project, ".slb", "state.db"))
+	requestor := testutil.MakeSession(t, database, testutil.WithProject(project), testutil.WithAgent("Requestor"))
+	first := testutil.MakeSession(t, database, testutil.WithProject(project), testutil.WithAgent("Reviewer1"))
+	second := testutil.MakeSession(t, database, testutil.WithProject(project), testutil.WithAgent("Reviewer2"))
+	req := testutil.MakeRequest(t, database, requestor)
+
+	viewers := func() []db.RequestViewer {
+		t.Helper()
+		got, err := database.RequestViewers(req.ID, time.Time{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+
+	// The requestor and spectators are never shown as present.
+	own := NewWithOptions(Options{ProjectPath: project, SessionID: requestor.ID})
+	own.loadRequestDetail(req.ID)
+	spectator := NewWithOptions(Options{ProjectPath: project, SessionID: first.ID, ReadOnly: true})
+	spectator.loadRequestDetail(req.ID)
+	if got := viewers(); len(got) != 0 {
+		t.Fatalf("expected no presence yet, got %+v", got)
+	}
+
+	m1 := NewWithOptions(Options{ProjectPath: project, SessionID: first.ID})
+	next, _ := m1.Update(navigateMsg{view: ViewRequestDetail, requestID: req.ID})
+	m1 = next.(Model)
+	if got := viewers(); len(got) != 1 || got[0].Viewer != "Reviewer1" {
+		t.Fatalf("expected Reviewer1 to be present, got %+v", got)
+	}
+
+	m2 := NewWithOptions(Options{ProjectPath: project, SessionID: second.ID})
+	detail := m2.loadRequestDetail(req.ID)
+	if len(detail.Viewers) != 1 || detail.Viewers[0].Viewer != "Reviewer1" {
+		t.Fatalf("expected Reviewer2 to see Reviewer1, got %+v", detail.Viewers)
+	}
+
+	// A decision made elsewhere reaches the open view on the next refresh.
+	if err := database.UpdateRequestStatus(req.ID, db.StatusRejected); err != nil {
+		t.Fatal(err)
+	}
+	msg, ok := m1.refreshDetail(req.ID)().(request.RefreshMsg)
+	if !ok {
+		t.Fatal("expected a refresh message")
+	}
+	if msg.Request.Status != db.StatusRejected {
+		t.Errorf("refresh status = %s", msg.Request.Status)
+	}
+	if len(msg.Viewers) != 2 {
+		t.Errorf("expected both reviewers present, got %+v", msg.Viewers)
+	}
+
+	// Leaving the request clears the reviewer's presence.
+	_, _ = m1.Update(navigateMsg{view: ViewDashboard})
+	if got := viewers(); len(got) != 1 || got[0].Viewer != "Reviewer2" {
+		t.Fatalf("expected only Reviewer2 left, got %+v", got)
+	}
+}
@@ -0,0 +1,128 @@
+// Package tuitest provides golden-file snapshot testing for TUI views.
+//
+// Views are rendered at fixed terminal sizes, stripped of ANSI styling and
+// compared with testdata/<name>.golden in the calling package. Run the tests
+// with -update to rewrite the golden files after an intended change. The flag
+// is only defined in packages that import tuitest, so name them:
+//
+//	go test ./internal/tui/dashboard ./internal/tui/history ./internal/tui/request -run Snapshot -update
+package tuitest
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/i18n"
+	"github.com/Dicklesworthstone/slb/internal/tui/icons"
+	"github.com/Dicklesworthstone/slb/internal/utils"
+)
+
+var update = flag.Bool("update", false, "rewrite TUI golden files under testdata")
+
+// Size is a terminal size.
+type Size struct {
+	Width  int
+	Height int
+}
+
+func (s Size) String() string {
+	return fmt.Sprintf("%dx%d", s.Width, s.Height)
+}
+
+// Sizes covers a small terminal, the common default and a wide one, so
+// layouts that reflow or hide panes are all exercised.
+var Sizes = []Size{
+	{Width: 80, Height: 24},
+	{Width: 120, Height: 40},
+	{Width: 200, Height: 50},
+}
+
+// Pin fixes everything besides the model that changes how a view renders:
+// English messages, Unicode glyphs, ASCII icons and UTC timestamps. The
+// previous settings are restored on cleanup. Tests that call Pin must not run
+// in parallel.
+func Pin(t testing.TB) {
+	t.Helper()
+
+	prevLocale := i18n.Locale()
+	prevPlain := utils.PlainOutput()
+	prevNerd := icons.NerdFonts()
+	prevLocal := time.Local
+	t.Cleanup(func() {
+		i18n.SetLocale(prevLocale)
+		utils.SetPlainOutput(prevPlain)
+		icons.SetNerdFonts(prevNerd)
+		time.Local = prevLocal
+	})
+
+	i18n.SetLocale(i18n.DefaultLocale)
+	utils.SetPlainOutput(false)
+	icons.SetNerdFonts(false)
+	time.Local = time.UTC
+}
+
+// Normalize strips ANSI escape sequences and trailing whitespace from each
+// line, leaving the layout a golden file records.
+func Normalize(view string) string {
+	lines := strings.Split(utils.StripANSI(view), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n") + "\n"
+}
+
+// RequireSnapshot compares the normalized view with testdata/<name>.golden,
+// or writes it there when -update is set.
+func RequireSnapshot(t testing.TB, name, view string) {
+	t.Helper()
+
+	got := Normalize(view)
+	path := filepath.Join("testdata", name+".golden")
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("creating testdata: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatalf("writing golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file (run with -update to create it): %v", err)
+	}
+	if got == string(want) {
+		return
+	}
+	t.Errorf("%s does not match the rendered view (run with -update if the change is intended)\n%s",
+		path, diff(string(want), got))
+}
+
+// diff reports the first differing line and both full renderings.
+func diff(want, got string) string {
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+
+	line := 0
+	for line < len(wantLines) && line < len(gotLines) && wantLines[line] == gotLines[line] {
+		line++
+	}
+	at := func(lines []string) string {
+		if line < len(lines) {
+			return fmt.Sprintf("%q", lines[line])
+		}
+		return "<end of view>"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "first difference at line %d:\n  want: %s\n  got:  %s\n", line+1, at(wantLines), at(gotLines))
+	fmt.Fprintf(&b, "--- want\n%s--- got\n%s", want, got)
+	return b.String()
+}
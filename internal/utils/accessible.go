@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// plainOutput reports whether accessible (no-color, ASCII) output is active.
+var plainOutput = detectPlainOutput()
+
+// detectPlainOutput checks SLB_NO_COLOR, the NO_COLOR convention
+// (https://no-color.org), and dumb terminals.
+func detectPlainOutput() bool {
+	if v, ok := os.LookupEnv("SLB_NO_COLOR"); ok {
+		v = strings.ToLower(strings.TrimSpace(v))
+		return v != "" && v != "0" && v != "false" && v != "no"
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return true
+	}
+	return strings.EqualFold(os.Getenv("TERM"), "dumb")
+}
+
+// savedProfile is the color profile to restore when accessible output is
+// turned off again.
+var savedProfile termenv.Profile
+
+func init() {
+	if plainOutput {
+		savedProfile = lipgloss.ColorProfile()
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+}
+
+// SetPlainOutput enables or disables accessible output: no ANSI colors or
+// styling, and ASCII labels instead of emoji and other glyphs. Intended for
+// screen readers and dumb terminals.
+func SetPlainOutput(enabled bool) {
+	switch {
+	case enabled && !plainOutput:
+		savedProfile = lipgloss.ColorProfile()
+		lipgloss.SetColorProfile(termenv.Ascii)
+	case !enabled && plainOutput:
+		lipgloss.SetColorProfile(savedProfile)
+	}
+	plainOutput = enabled
+}
+
+// PlainOutput reports whether accessible output is enabled.
+func PlainOutput() bool {
+	return plainOutput
+}
+
+// Glyph returns fancy normally and ascii in accessible output mode.
+func Glyph(fancy, ascii string) string {
+	if plainOutput {
+		return ascii
+	}
+	return fancy
+}
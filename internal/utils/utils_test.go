@@ -121,3 +121,54 @@ func TestDefaultLoggerWrappers(t *testing.T) {
 		}
 	}
 }
+
+func TestGlyphPlainOutput(t *testing.T) {
+	prev := PlainOutput()
+	t.Cleanup(func() { SetPlainOutput(prev) })
+
+	SetPlainOutput(false)
+	if got := Glyph("✓", "[OK]"); got != "✓" {
+		t.Errorf("Glyph = %q, want fancy glyph", got)
+	}
+
+	SetPlainOutput(true)
+	if !PlainOutput() {
+		t.Fatal("expected plain output enabled")
+	}
+	if got := Glyph("✓", "[OK]"); got != "[OK]" {
+		t.Errorf("Glyph = %q, want ASCII label", got)
+	}
+}
+
+func TestDetectPlainOutput(t *testing.T) {
+	cases := []struct {
+		name       string
+		slbNoColor *string
+		noColor    string
+		term       string
+		want       bool
+	}{
+		{name: "default", term: "xterm-256color", want: false},
+		{name: "slb no color", slbNoColor: strPtr("1"), term: "xterm", want: true},
+		{name: "slb no color disabled wins", slbNoColor: strPtr("false"), noColor: "1", term: "dumb", want: false},
+		{name: "no color convention", noColor: "1", term: "xterm", want: true},
+		{name: "dumb terminal", term: "dumb", want: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.slbNoColor != nil {
+				t.Setenv("SLB_NO_COLOR", *tc.slbNoColor)
+			} else {
+				t.Setenv("SLB_NO_COLOR", "")
+				os.Unsetenv("SLB_NO_COLOR")
+			}
+			t.Setenv("NO_COLOR", tc.noColor)
+			t.Setenv("TERM", tc.term)
+			if got := detectPlainOutput(); got != tc.want {
+				t.Errorf("detectPlainOutput() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func strPtr(s string) *string { return &s }
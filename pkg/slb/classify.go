@@ -0,0 +1,117 @@
+package slb
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/Dicklesworthstone/slb/internal/core"
+)
+
+// Tier is a command's risk tier.
+type Tier string
+
+// Risk tiers, most to least severe.
+const (
+	TierCritical  Tier = "critical"
+	TierDangerous Tier = "dangerous"
+	TierCaution   Tier = "caution"
+	TierSafe      Tier = "safe"
+)
+
+// Classification is the result of classifying a command.
+type Classification struct {
+	// Tier is the command's risk tier; empty when no pattern matched.
+	Tier Tier `json:"tier,omitempty"`
+	// MatchedPattern is the regex (or fallback rule) that decided the tier.
+	MatchedPattern string `json:"matched_pattern,omitempty"`
+	// MinApprovals is how many approvals the tier requires.
+	MinApprovals int `json:"min_approvals"`
+	// NeedsApproval reports whether submitting the command creates a request.
+	NeedsApproval bool `json:"needs_approval"`
+	// IsSafe reports whether the command matched a safe pattern.
+	IsSafe bool `json:"is_safe"`
+	// ParseError reports that the command could not be fully parsed and
+	// its tier was raised as a precaution.
+	ParseError bool `json:"parse_error,omitempty"`
+	// Segments lists the matches within a compound command.
+	Segments []Segment `json:"segments,omitempty"`
+	// Notes explains adjustments made to the matched tier.
+	Notes []string `json:"notes,omitempty"`
+}
+
+// Segment is one matched part of a compound command such as `a && b`.
+type Segment struct {
+	Command        string `json:"command"`
+	Tier           Tier   `json:"tier"`
+	MatchedPattern string `json:"matched_pattern,omitempty"`
+}
+
+// Classifier classifies commands against the built-in patterns plus any
+// enabled pattern packs and added patterns. It is safe for concurrent use.
+type Classifier struct {
+	engine *core.PatternEngine
+}
+
+// NewClassifier returns a classifier with the built-in patterns and the
+// named pattern packs (e.g. "kubernetes", "terraform") enabled.
+func NewClassifier(packs ...string) (*Classifier, error) {
+	engine := core.NewPatternEngine()
+	for _, name := range packs {
+		if err := engine.EnablePack(strings.TrimSpace(name)); err != nil {
+			return nil, err
+		}
+	}
+	return &Classifier{engine: engine}, nil
+}
+
+// AddPattern adds a regex pattern to a tier.
+func (c *Classifier) AddPattern(tier Tier, pattern, description string) error {
+	t := core.ParseRiskTier(string(tier))
+	if t == "" {
+		return fmt.Errorf("unknown tier %q", tier)
+	}
+	return c.engine.AddPattern(t, pattern, description, "embedded")
+}
+
+// Classify classifies a command run from cwd. Cwd resolves relative paths
+// and may be empty.
+func (c *Classifier) Classify(command, cwd string) Classification {
+	return classificationFrom(c.engine.ClassifyCommand(command, cwd))
+}
+
+var (
+	builtinOnce       sync.Once
+	builtinClassifier *Classifier
+)
+
+// Classify classifies a command against the built-in patterns only.
+func Classify(command, cwd string) Classification {
+	builtinOnce.Do(func() {
+		builtinClassifier = &Classifier{engine: core.NewPatternEngine()}
+	})
+	return builtinClassifier.Classify(command, cwd)
+}
+
+func classificationFrom(r *core.MatchResult) Classification {
+	if r == nil {
+		return Classification{}
+	}
+	c := Classification{
+		Tier:           Tier(r.Tier),
+		MatchedPattern: r.MatchedPattern,
+		MinApprovals:   r.MinApprovals,
+		NeedsApproval:  r.NeedsApproval,
+		IsSafe:         r.IsSafe,
+		ParseError:     r.ParseError,
+		Notes:          append([]string(nil), r.Explanation...),
+	}
+	for _, s := range r.MatchedSegments {
+		c.Segments = append(c.Segments, Segment{
+			Command:        s.Segment,
+			Tier:           Tier(s.Tier),
+			MatchedPattern: s.MatchedPattern,
+		})
+	}
+	return c
+}
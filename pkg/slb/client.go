@@ -0,0 +1,317 @@
+package slb
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/config"
+	"github.com/Dicklesworthstone/slb/internal/core"
+	"github.com/Dicklesworthstone/slb/internal/daemon"
+	"github.com/Dicklesworthstone/slb/internal/db"
+)
+
+// Errors returned by Client methods. Test with errors.Is.
+var (
+	ErrSessionNotFound = core.ErrSessionNotFound
+	ErrSessionInactive = core.ErrSessionInactive
+	ErrAgentBlocked    = core.ErrAgentBlocked
+	ErrRequestNotFound = db.ErrRequestNotFound
+	// ErrActiveSession is returned by StartSession when the agent already
+	// has an active session in the project.
+	ErrActiveSession = db.ErrActiveSessionExists
+)
+
+// DefaultPollInterval is how often Wait re-reads a request.
+const DefaultPollInterval = 500 * time.Millisecond
+
+// Status is a request's lifecycle state.
+type Status string
+
+// Request statuses.
+const (
+	StatusPending         Status = "pending"
+	StatusApproved        Status = "approved"
+	StatusRejected        Status = "rejected"
+	StatusExecuting       Status = "executing"
+	StatusExecuted        Status = "executed"
+	StatusExecutionFailed Status = "execution_failed"
+	StatusCancelled       Status = "cancelled"
+	StatusTimeout         Status = "timeout"
+	StatusTimedOut        Status = "timed_out"
+	StatusEscalated       Status = "escalated"
+)
+
+// Decided reports whether reviewers have settled the request: it is
+// approved or has reached a final state.
+func (s Status) Decided() bool {
+	return s == StatusApproved || db.RequestStatus(s).IsTerminal()
+}
+
+// Options configure Open.
+type Options struct {
+	// ProjectDir is the project root (required). Config is read from its
+	// .slb/config.toml and the user config, as for the CLI.
+	ProjectDir string
+	// ConfigPath overrides the config file location.
+	ConfigPath string
+	// DBPath overrides the database (default <ProjectDir>/.slb/state.db).
+	DBPath string
+}
+
+// Client submits and tracks requests in one project. It is safe for
+// concurrent use.
+type Client struct {
+	project    string
+	db         *db.DB
+	classifier *Classifier
+	creator    *core.RequestCreator
+}
+
+// Open loads the project's config and opens (creating if needed) its
+// database. The classifier includes the project's pattern packs and custom
+// patterns, so it agrees with `slb classify` in that project.
+func Open(opts Options) (*Client, error) {
+	if strings.TrimSpace(opts.ProjectDir) == "" {
+		return nil, fmt.Errorf("project dir is required")
+	}
+	project, err := filepath.Abs(opts.ProjectDir)
+	if err != nil {
+		return nil, fmt.Errorf("resolving project dir: %w", err)
+	}
+	cfg, err := config.Load(config.LoadOptions{ProjectDir: project, ConfigPath: opts.ConfigPath})
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+
+	dbPath := opts.DBPath
+	if dbPath == "" {
+		dbPath = filepath.Join(project, ".slb", "state.db")
+	}
+	database, err := db.OpenAndMigrate(dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+
+	classifier, err := NewClassifier(cfg.Patterns.Packs...)
+	if err != nil {
+		database.Close()
+		return nil, err
+	}
+	rows, err := database.ListCustomPatterns()
+	if err != nil {
+		database.Close()
+		return nil, fmt.Errorf("loading custom patterns: %w", err)
+	}
+	// Unloadable rows are skipped, as in the CLI.
+	classifier.engine.MergeCustomPatterns(rows)
+
+	creator := daemon.RequestCreatorFromConfig(database, cfg)
+	creator.SetPatternEngine(classifier.engine)
+
+	return &Client{project: project, db: database, classifier: classifier, creator: creator}, nil
+}
+
+// Close closes the database.
+func (c *Client) Close() error {
+	return c.db.Close()
+}
+
+// Classify classifies a command with the project's patterns.
+func (c *Client) Classify(command, cwd string) Classification {
+	return c.classifier.Classify(command, cwd)
+}
+
+// SessionOptions describe the agent starting a session.
+type SessionOptions struct {
+	// Agent names the agent (required); one active session per agent and project.
+	Agent   string
+	Program string
+	Model   string
+}
+
+// Session is an agent session. Key signs reviews and must be kept secret.
+type Session struct {
+	ID          string    `json:"id"`
+	Key         string    `json:"key"`
+	Agent       string    `json:"agent"`
+	Program     string    `json:"program,omitempty"`
+	Model       string    `json:"model,omitempty"`
+	ProjectPath string    `json:"project_path"`
+	StartedAt   time.Time `json:"started_at"`
+}
+
+// StartSession starts a session in the project.
+func (c *Client) StartSession(opts SessionOptions) (*Session, error) {
+	s := &db.Session{
+		AgentName:   opts.Agent,
+		Program:     opts.Program,
+		Model:       opts.Model,
+		ProjectPath: c.project,
+	}
+	if err := c.db.CreateSession(s); err != nil {
+		return nil, err
+	}
+	return &Session{
+		ID:          s.ID,
+		Key:         s.SessionKey,
+		Agent:       s.AgentName,
+		Program:     s.Program,
+		Model:       s.Model,
+		ProjectPath: s.ProjectPath,
+		StartedAt:   s.StartedAt,
+	}, nil
+}
+
+// EndSession ends a session.
+func (c *Client) EndSession(id string) error {
+	return c.db.EndSession(id)
+}
+
+// SubmitOptions describe a command to submit for review.
+type SubmitOptions struct {
+	// SessionID is the submitting session (required).
+	SessionID string
+	// Command is the command line (required).
+	Command string
+	// Cwd is where the command would run (default: the project dir).
+	Cwd string
+	// Reason is why the command is needed; reviewers see it first.
+	Reason         string
+	ExpectedEffect string
+	Goal           string
+	SafetyArgument string
+	// Labels are key/value metadata for triage.
+	Labels map[string]string
+	// IdempotencyKey makes retries safe: resubmitting the same command
+	// with the same key returns the original request.
+	IdempotencyKey string
+	// RedactPatterns are regexes whose matches are hidden from reviewers.
+	RedactPatterns []string
+}
+
+// Submission is the result of Submit.
+type Submission struct {
+	// Request is the created request, nil when Skipped.
+	Request *Request `json:"request,omitempty"`
+	// Skipped is set when the command needs no approval.
+	Skipped    bool   `json:"skipped"`
+	SkipReason string `json:"skip_reason,omitempty"`
+	// Replayed is set when IdempotencyKey matched an earlier submission.
+	Replayed       bool           `json:"replayed,omitempty"`
+	Classification Classification `json:"classification"`
+}
+
+// Request is a submitted request.
+type Request struct {
+	ID   string `json:"id"`
+	Tier Tier   `json:"tier"`
+	// Command is the command as reviewers see it, with redactions applied.
+	Command      string     `json:"command"`
+	CommandHash  string     `json:"command_hash"`
+	Status       Status     `json:"status"`
+	MinApprovals int        `json:"min_approvals"`
+	Approvals    int        `json:"approvals"`
+	Rejections   int        `json:"rejections"`
+	CreatedAt    time.Time  `json:"created_at"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	ResolvedAt   *time.Time `json:"resolved_at,omitempty"`
+}
+
+// Submit classifies a command and, unless it needs no approval, creates a
+// pending request for reviewers. ctx bounds the fetches of any scripts the
+// command downloads.
+func (c *Client) Submit(ctx context.Context, opts SubmitOptions) (*Submission, error) {
+	cwd := opts.Cwd
+	if cwd == "" {
+		cwd = c.project
+	}
+	result, err := c.creator.CreateRequest(ctx, core.CreateRequestOptions{
+		SessionID: opts.SessionID,
+		Command:   opts.Command,
+		Cwd:       cwd,
+		Shell:     true,
+		Justification: core.Justification{
+			Reason:         opts.Reason,
+			ExpectedEffect: opts.ExpectedEffect,
+			Goal:           opts.Goal,
+			SafetyArgument: opts.SafetyArgument,
+		},
+		RedactPatterns: opts.RedactPatterns,
+		ProjectPath:    c.project,
+		Labels:         opts.Labels,
+		IdempotencyKey: opts.IdempotencyKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sub := &Submission{
+		Skipped:        result.Skipped,
+		SkipReason:     result.SkipReason,
+		Replayed:       result.Replayed,
+		Classification: classificationFrom(result.Classification),
+	}
+	if result.Request != nil {
+		if sub.Request, err = c.requestFrom(result.Request); err != nil {
+			return nil, err
+		}
+	}
+	return sub, nil
+}
+
+// Get returns a request's current state.
+func (c *Client) Get(id string) (*Request, error) {
+	r, err := c.db.GetRequest(id)
+	if err != nil {
+		return nil, err
+	}
+	return c.requestFrom(r)
+}
+
+// Wait polls a request until it is decided (see Status.Decided) or ctx is
+// done, returning its latest state.
+func (c *Client) Wait(ctx context.Context, id string) (*Request, error) {
+	ticker := time.NewTicker(DefaultPollInterval)
+	defer ticker.Stop()
+	for {
+		req, err := c.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		if req.Status.Decided() {
+			return req, nil
+		}
+		select {
+		case <-ctx.Done():
+			return req, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Client) requestFrom(r *db.Request) (*Request, error) {
+	approvals, rejections, err := c.db.CountReviewsByDecision(r.ID)
+	if err != nil {
+		return nil, err
+	}
+	command := r.Command.DisplayRedacted
+	if command == "" {
+		command = r.Command.Raw
+	}
+	return &Request{
+		ID:           r.ID,
+		Tier:         Tier(r.RiskTier),
+		Command:      command,
+		CommandHash:  r.Command.Hash,
+		Status:       Status(r.Status),
+		MinApprovals: r.MinApprovals,
+		Approvals:    approvals,
+		Rejections:   rejections,
+		CreatedAt:    r.CreatedAt,
+		ExpiresAt:    r.ExpiresAt,
+		ResolvedAt:   r.ResolvedAt,
+	}, nil
+}
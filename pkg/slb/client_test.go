@@ -0,0 +1,136 @@
+package slb
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/internal/db"
+	"github.com/Dicklesworthstone/slb/internal/testutil"
+)
+
+func openTestClient(t *testing.T, configTOML string) (*Client, string) {
+	t.Helper()
+	project := t.TempDir()
+	if configTOML != "" {
+		testutil.RequireNoError(t, os.MkdirAll(filepath.Join(project, ".slb"), 0o700), "mkdir .slb")
+		testutil.RequireNoError(t, os.WriteFile(filepath.Join(project, ".slb", "config.toml"), []byte(configTOML), 0o600), "write config")
+	}
+	client, err := Open(Options{ProjectDir: project})
+	testutil.RequireNoError(t, err, "open")
+	t.Cleanup(func() { _ = client.Close() })
+	return client, project
+}
+
+func TestOpen_RequiresProjectDir(t *testing.T) {
+	if _, err := Open(Options{}); err == nil {
+		t.Fatal("expected an error without a project dir")
+	}
+}
+
+func TestClient_SubmitAndWait(t *testing.T) {
+	client, project := openTestClient(t, "")
+
+	session, err := client.StartSession(SessionOptions{Agent: "Orchestrator", Program: "embedder", Model: "m1"})
+	testutil.RequireNoError(t, err, "start session")
+	testutil.RequireEqual(t, project, session.ProjectPath, "project path")
+	if _, err := client.StartSession(SessionOptions{Agent: "Orchestrator"}); !errors.Is(err, ErrActiveSession) {
+		t.Fatalf("expected ErrActiveSession, got %v", err)
+	}
+
+	skipped, err := client.Submit(context.Background(), SubmitOptions{SessionID: session.ID, Command: "ls -la", Reason: "look"})
+	testutil.RequireNoError(t, err, "submit safe")
+	if !skipped.Skipped || skipped.Request != nil {
+		t.Fatalf("expected a skipped submission, got %+v", skipped)
+	}
+
+	sub, err := client.Submit(context.Background(), SubmitOptions{
+		SessionID:      session.ID,
+		Command:        "rm -rf ./build --token=hunter2",
+		Reason:         "clean build",
+		Labels:         map[string]string{"team": "infra"},
+		IdempotencyKey: "build-clean-1",
+		RedactPatterns: []string{`hunter2`},
+	})
+	testutil.RequireNoError(t, err, "submit")
+	if sub.Skipped || sub.Request == nil || sub.Request.Status != StatusPending || sub.Request.Tier != TierDangerous {
+		t.Fatalf("unexpected submission: %+v", sub)
+	}
+	if sub.Classification.MatchedPattern == "" {
+		t.Fatal("expected the matched pattern")
+	}
+	if sub.Request.Command == "rm -rf ./build --token=hunter2" {
+		t.Fatalf("expected a redacted command, got %q", sub.Request.Command)
+	}
+
+	replay, err := client.Submit(context.Background(), SubmitOptions{
+		SessionID: session.ID, Command: "rm -rf ./build --token=hunter2", Reason: "retry", IdempotencyKey: "build-clean-1",
+	})
+	testutil.RequireNoError(t, err, "replay")
+	if !replay.Replayed || replay.Request.ID != sub.Request.ID {
+		t.Fatalf("expected a replay of %s, got %+v", sub.Request.ID, replay)
+	}
+
+	// Approve out of band, as a reviewer's CLI would.
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = client.db.UpdateRequestStatus(sub.Request.ID, db.StatusApproved)
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := client.Wait(ctx, sub.Request.ID)
+	testutil.RequireNoError(t, err, "wait")
+	testutil.RequireEqual(t, StatusApproved, req.Status, "status")
+
+	if _, err := client.Get("missing"); !errors.Is(err, ErrRequestNotFound) {
+		t.Fatalf("expected ErrRequestNotFound, got %v", err)
+	}
+
+	testutil.RequireNoError(t, client.EndSession(session.ID), "end session")
+	if _, err := client.Submit(context.Background(), SubmitOptions{SessionID: session.ID, Command: "rm -rf ./dist", Reason: "x"}); !errors.Is(err, ErrSessionInactive) {
+		t.Fatalf("expected ErrSessionInactive, got %v", err)
+	}
+}
+
+func TestClient_UsesProjectConfigAndPatterns(t *testing.T) {
+	client, _ := openTestClient(t, "[agents]\nblocked = [\"Rogue\"]\n")
+
+	// Custom patterns persisted by `slb patterns add` apply to the client
+	// opened afterwards.
+	_, err := client.db.InsertCustomPattern("critical", `^deploy\s+--prod`, "production deploys", "human")
+	testutil.RequireNoError(t, err, "insert custom pattern")
+	testutil.RequireNoError(t, client.Close(), "close")
+	client, err = Open(Options{ProjectDir: client.project})
+	testutil.RequireNoError(t, err, "reopen")
+	t.Cleanup(func() { _ = client.Close() })
+
+	testutil.RequireEqual(t, TierCritical, client.Classify("deploy --prod", "").Tier, "custom pattern tier")
+	if got := Classify("deploy --prod", ""); got.NeedsApproval {
+		t.Fatalf("package Classify should use built-ins only, got %+v", got)
+	}
+
+	rogue, err := client.StartSession(SessionOptions{Agent: "Rogue"})
+	testutil.RequireNoError(t, err, "start session")
+	if _, err := client.Submit(context.Background(), SubmitOptions{SessionID: rogue.ID, Command: "rm -rf ./build", Reason: "x"}); !errors.Is(err, ErrAgentBlocked) {
+		t.Fatalf("expected ErrAgentBlocked, got %v", err)
+	}
+}
+
+func TestNewClassifier(t *testing.T) {
+	if _, err := NewClassifier("no-such-pack"); err == nil {
+		t.Fatal("expected an error for an unknown pack")
+	}
+	c, err := NewClassifier()
+	testutil.RequireNoError(t, err, "new classifier")
+	if err := c.AddPattern("extreme", `^x`, ""); err == nil {
+		t.Fatal("expected an error for an unknown tier")
+	}
+
+	got := c.Classify("echo hi && rm -rf /etc", "")
+	if got.Tier != TierCritical || len(got.Segments) == 0 {
+		t.Fatalf("expected compound segments, got %+v", got)
+	}
+}
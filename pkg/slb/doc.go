@@ -0,0 +1,27 @@
+// Package slb lets Go programs embed SLB's command classification and
+// request submission without running the slb CLI.
+//
+// Classification needs no state:
+//
+//	c := slb.Classify("git push --force origin main", "")
+//	if c.NeedsApproval {
+//		// route through review
+//	}
+//
+// Submitting requests works against a project's .slb/state.db, the same
+// database the CLI, daemon and TUI use, so reviewers see embedded requests
+// like any other:
+//
+//	client, err := slb.Open(slb.Options{ProjectDir: "/path/to/repo"})
+//	...
+//	session, err := client.StartSession(slb.SessionOptions{Agent: "Orchestrator"})
+//	sub, err := client.Submit(ctx, slb.SubmitOptions{
+//		SessionID: session.ID,
+//		Command:   "kubectl delete namespace staging",
+//		Reason:    "tear down the preview environment",
+//	})
+//	req, err := client.Wait(ctx, sub.Request.ID)
+//
+// The types in this package are its own and are converted from SLB's
+// internal packages, so the API stays stable as those change.
+package slb
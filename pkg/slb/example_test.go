@@ -0,0 +1,72 @@
+package slb_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/Dicklesworthstone/slb/pkg/slb"
+)
+
+func ExampleClassify() {
+	for _, cmd := range []string{"ls -la", "git reset --hard HEAD~1", "rm -rf /etc"} {
+		c := slb.Classify(cmd, "")
+		fmt.Printf("%-24s tier=%s needs_approval=%v\n", cmd, c.Tier, c.NeedsApproval)
+	}
+	// Output:
+	// ls -la                   tier= needs_approval=false
+	// git reset --hard HEAD~1  tier=dangerous needs_approval=true
+	// rm -rf /etc              tier=critical needs_approval=true
+}
+
+func ExampleClassifier_AddPattern() {
+	classifier, err := slb.NewClassifier()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := classifier.AddPattern(slb.TierCritical, `^deploy\s+--prod`, "production deploys"); err != nil {
+		log.Fatal(err)
+	}
+	c := classifier.Classify("deploy --prod", "")
+	fmt.Println(c.Tier, c.MinApprovals)
+	// Output: critical 2
+}
+
+func ExampleClient_Submit() {
+	project, err := os.MkdirTemp("", "slb-example")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(project)
+
+	client, err := slb.Open(slb.Options{ProjectDir: project})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer client.Close()
+
+	session, err := client.StartSession(slb.SessionOptions{Agent: "Orchestrator", Model: "example-model"})
+	if err != nil {
+		log.Fatal(err)
+	}
+	sub, err := client.Submit(context.Background(), slb.SubmitOptions{
+		SessionID: session.ID,
+		Command:   "git reset --hard HEAD~1",
+		Reason:    "drop the broken merge commit",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(sub.Request.Tier, sub.Request.Status, sub.Request.MinApprovals)
+
+	// A reviewer approves with `slb approve`; here nobody does in time.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req, err := client.Wait(ctx, sub.Request.ID)
+	fmt.Println(req.Status, err)
+	// Output:
+	// dangerous pending 1
+	// pending context deadline exceeded
+}
@@ -37,7 +37,7 @@ func TestHappyPath_RequestApproveWorkflow(t *testing.T) {
 	env.Step("Verifying request is now approved")
 	// After sufficient approvals, status should be APPROVED
 	// Note: The harness's ApproveRequest creates a review but doesn't update status
-	// In the real flow, CreateReviewWithValidation would update it
+	// In the real flow, the review service would update it
 	// For this test, we manually verify the approval was recorded
 	env.AssertApprovalCount(req, 1)
 